@@ -0,0 +1,105 @@
+// Command admin manages back-office admin accounts.
+//
+// Usage:
+//
+//	admin create -email ops@pay2go.example -name "Ops" < password.txt
+//
+// The password is read from the first line of stdin so it does not end up in
+// shell history. The TOTP secret and an otpauth:// URI for enrolling an
+// authenticator app are printed once and cannot be retrieved later.
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"Pay2Go/internal/adapters/persistence/mysql"
+	"Pay2Go/internal/adapters/persistence/postgres"
+	"Pay2Go/internal/adapters/persistence/sqlite"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/encryption"
+	"Pay2Go/internal/usecases/admin"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// totpIssuer names the account in authenticator apps
+const totpIssuer = "Pay2Go"
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "create" {
+		fmt.Fprintln(os.Stderr, "usage: admin create -email <email> -name <name> < password")
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	email := flags.String("email", "", "admin email address")
+	name := flags.String("name", "", "admin display name")
+	_ = flags.Parse(os.Args[2:])
+
+	if err := create(*email, *name); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create admin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// create adds an admin and prints their TOTP enrollment details
+func create(email, name string) error {
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		return fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	// Connect to database
+	db, err := sql.Open(cfg.Database.DriverName(), cfg.Database.GetDSN())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// TOTP secrets are encrypted at rest like every other secret
+	secretCipher, err := encryption.NewEnvelopeCipher(cfg.Encryption.Keys, cfg.Encryption.PrimaryKeyID)
+	if err != nil {
+		return err
+	}
+	secretCipher.AllowLegacy = cfg.Encryption.AllowLegacy
+
+	var adminUserRepo ports.AdminUserRepository = postgres.NewAdminUserRepository(db, secretCipher)
+	switch cfg.Database.Driver {
+	case config.DriverMySQL:
+		adminUserRepo = mysql.NewAdminUserRepository(db, secretCipher)
+	case config.DriverSQLite:
+		adminUserRepo = sqlite.NewAdminUserRepository(db, secretCipher)
+	}
+	createAdminUC := admin.NewCreateAdminUseCase(adminUserRepo)
+
+	adminUser, err := createAdminUC.Execute(context.Background(), admin.CreateAdminInput{
+		Email:    email,
+		Name:     name,
+		Password: password,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created admin %s (%s)\n", adminUser.Email, adminUser.ID)
+	fmt.Printf("TOTP secret: %s\n", adminUser.TOTPSecret)
+	fmt.Printf("Enrollment URI: %s\n", adminUser.TOTPProvisioningURI(totpIssuer))
+
+	return nil
+}
@@ -0,0 +1,133 @@
+// Command migrate applies the embedded database schema migrations.
+//
+// Usage:
+//
+//	migrate up              apply all pending migrations
+//	migrate down [-steps N] roll back the last N migrations (default 1)
+//	migrate version         print the applied and latest versions
+//	migrate force VERSION   record VERSION as applied without running anything
+//
+// Database settings come from the same DB_* environment variables as the API;
+// DB_DRIVER=mysql applies the MySQL schema in migrations/mysql.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/migrate"
+	"Pay2Go/migrations"
+)
+
+const usage = "usage: migrate up | down [-steps N] | version | force VERSION"
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := run(os.Args[1], os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "migrate %s failed: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// run executes one migrate subcommand
+func run(command string, args []string) error {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	// Connect to database
+	db, err := sql.Open(cfg.Database.DriverName(), cfg.Database.GetDSN())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	dialect, files := migrate.Postgres, fs.FS(migrations.FS)
+	switch cfg.Database.Driver {
+	case config.DriverMySQL:
+		dialect, files = migrate.MySQL, migrations.MySQLFS
+	case config.DriverSQLite:
+		dialect, files = migrate.SQLite, migrations.SQLiteFS
+	}
+	migrator, err := migrate.New(db, dialect, files)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, version := range applied {
+			fmt.Printf("Applied %06d\n", version)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+
+	case "down":
+		flags := flag.NewFlagSet("down", flag.ExitOnError)
+		steps := flags.Int("steps", 1, "number of migrations to roll back")
+		_ = flags.Parse(args)
+		if *steps < 1 {
+			return fmt.Errorf("-steps must be at least 1")
+		}
+
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, version := range reverted {
+			fmt.Printf("Rolled back %06d\n", version)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "version":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		state := ""
+		if dirty {
+			state = " (dirty)"
+		}
+		fmt.Printf("Applied: %06d%s\nLatest:  %06d\n", version, state, migrator.Latest())
+
+	case "force":
+		if len(args) != 1 {
+			return fmt.Errorf(usage)
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			return err
+		}
+		fmt.Printf("Forced version %06d\n", version)
+
+	default:
+		return fmt.Errorf(usage)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+// Command openapi writes the OpenAPI document of the partner API, the one
+// served at /api/v1/openapi.json.
+//
+// Usage:
+//
+//	openapi                  print the document
+//	openapi -o FILE          write the document to FILE
+//	openapi -check FILE      exit 1 if FILE is not the current document
+//
+// CI runs the check against docs/openapi.json so DTO and route changes
+// regenerate the committed document (make openapi).
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"Pay2Go/internal/adapters/http/routes"
+)
+
+func main() {
+	output := flag.String("o", "", "write the document to this file instead of stdout")
+	check := flag.String("check", "", "compare the document with this file and exit 1 if it differs")
+	flag.Parse()
+
+	spec, err := json.MarshalIndent(routes.OpenAPI(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+	spec = append(spec, '\n')
+
+	switch {
+	case *check != "":
+		committed, err := os.ReadFile(*check)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+			os.Exit(1)
+		}
+		if !bytes.Equal(committed, spec) {
+			fmt.Fprintf(os.Stderr, "openapi: %s is out of date; run make openapi\n", *check)
+			os.Exit(1)
+		}
+	case *output != "":
+		if err := os.WriteFile(*output, spec, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		os.Stdout.Write(spec)
+	}
+}
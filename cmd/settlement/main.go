@@ -0,0 +1,214 @@
+// Command settlement reconciles payment providers' settlement and chargeback
+// feeds against the stored transactions.
+//
+// Entries are read from a Kafka topic through the REST Proxy
+// (SETTLEMENT_SOURCE=kafka) or from the CSV files providers upload to a drop
+// directory, usually the home of an SFTP account
+// (SETTLEMENT_SOURCE=directory). Chargebacks open disputes and their
+// reversals close them, with dispute.created and dispute.reversed events for
+// the partner; entries that disagree with the transactions are listed at
+// GET /api/v1/admin/settlements/discrepancies, and alerted to Slack or Teams
+// once ALERT_DISCREPANCY_THRESHOLD of them are found within
+// ALERT_DISCREPANCY_WINDOW_MINUTES.
+//
+// Usage:
+//
+//	settlement
+//
+// The command reads the API's configuration. It stops on SIGINT or SIGTERM
+// once the entries in flight are reconciled. Run one per feed: entries
+// delivered twice are reconciled once, but files in a drop directory are
+// not claimed.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"Pay2Go/internal/adapters/persistence/mysql"
+	"Pay2Go/internal/adapters/persistence/postgres"
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/adapters/persistence/sqlite"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/httpclient"
+	"Pay2Go/internal/infrastructure/logger"
+	"Pay2Go/internal/infrastructure/notify"
+	"Pay2Go/internal/infrastructure/settlementfeed"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/settlement"
+)
+
+// repositories are the persistence adapters reconciliation uses
+type repositories struct {
+	transactions      ports.TransactionRepository
+	settlementEntries ports.SettlementEntryRepository
+	disputes          ports.DisputeRepository
+	outbox            ports.OutboxRepository
+}
+
+// kafkaFetchTimeout bounds a fetch of records from the REST Proxy, which
+// holds it open until records arrive or its own timeout passes
+const kafkaFetchTimeout = 30 * time.Second
+
+func main() {
+	appLogger := logger.New()
+	appLogger.Info("Starting Pay2Go settlement reconciliation...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		appLogger.Error("Failed to load configuration: %v", err)
+		os.Exit(1)
+	}
+	logLevel, _ := logger.ParseLevel(cfg.Server.LogLevel)
+	appLogger.SetLevel(logLevel)
+
+	// Connect to database
+	db, err := sql.Open(cfg.Database.DriverName(), cfg.Database.GetDSN())
+	if err != nil {
+		appLogger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+	cfg.Database.ConfigurePool(db)
+	if err := db.Ping(); err != nil {
+		appLogger.Error("Failed to ping database: %v", err)
+		os.Exit(1)
+	}
+	repos := newRepositories(cfg.Database.Driver, db)
+
+	// Entries that are not valid are skipped rather than retried forever;
+	// the provider has to send them again corrected
+	invalid := func(source string, err error) {
+		appLogger.Warn("Skipped invalid settlement entry from %s: %v", source, err)
+	}
+
+	transport, err := httpclient.NewTransport(httpclient.Config{
+		MaxIdleConns:        cfg.HTTPClient.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.HTTPClient.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.HTTPClient.MaxConnsPerHost,
+		IdleConnTimeout:     time.Duration(cfg.HTTPClient.IdleConnTimeoutSeconds) * time.Second,
+		DialTimeout:         time.Duration(cfg.HTTPClient.DialTimeoutSeconds) * time.Second,
+		TLSHandshakeTimeout: time.Duration(cfg.HTTPClient.TLSHandshakeTimeoutSeconds) * time.Second,
+		ProxyURL:            cfg.HTTPClient.ProxyURL,
+		HTTP2:               cfg.HTTPClient.HTTP2,
+	})
+	if err != nil {
+		appLogger.Error("Invalid HTTP client configuration: %v", err)
+		os.Exit(1)
+	}
+	defer transport.CloseIdleConnections()
+
+	var feed ports.SettlementFeed
+	switch cfg.Settlement.Source {
+	case config.SettlementSourceKafka:
+		kafkaFeed, err := settlementfeed.NewKafkaFeed(settlementfeed.KafkaConfig{
+			RESTURL:  cfg.Events.KafkaRESTURL,
+			Topic:    cfg.Settlement.KafkaTopic,
+			Group:    cfg.Settlement.KafkaGroup,
+			Username: cfg.Events.KafkaUsername,
+			Password: cfg.Events.KafkaPassword,
+		}, transport.Client(kafkaFetchTimeout), invalid)
+		if err != nil {
+			appLogger.Error("Invalid settlement feed configuration: %v", err)
+			os.Exit(1)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := kafkaFeed.Close(ctx); err != nil {
+				appLogger.Warn("Failed to close settlement feed: %v", err)
+			}
+		}()
+		feed = kafkaFeed
+		appLogger.Info("Reconciling Kafka topic %s as %s", cfg.Settlement.KafkaTopic, cfg.Settlement.KafkaGroup)
+	default:
+		feed, err = settlementfeed.NewDirectoryFeed(cfg.Settlement.DropDirectory, invalid)
+		if err != nil {
+			appLogger.Error("Invalid settlement feed configuration: %v", err)
+			os.Exit(1)
+		}
+		appLogger.Info("Reconciling settlement files dropped in %s", cfg.Settlement.DropDirectory)
+	}
+
+	reconcileUC := settlement.NewReconcileUseCase(
+		repos.settlementEntries,
+		repos.disputes,
+		repos.transactions,
+		repos.outbox,
+		sqldb.NewUnitOfWork(db),
+	)
+	// Alert operations when many entries disagree with the transactions
+	consumer := settlement.NewConsumer(feed, reconcileUC, settlement.DiscrepancyAlerts{
+		Sink:      notify.NewSink(cfg.Alerts.SlackWebhookURL, cfg.Alerts.TeamsWebhookURL, transport.Client(10*time.Second)),
+		Threshold: cfg.Alerts.DiscrepancyThreshold,
+		Window:    time.Duration(cfg.Alerts.DiscrepancyWindowMinutes) * time.Minute,
+		OnError: func(err error) {
+			appLogger.Warn("%v", err)
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	run(ctx, consumer, time.Duration(cfg.Settlement.PollIntervalSeconds)*time.Second, appLogger)
+
+	stats := consumer.Stats()
+	appLogger.Info("Settlement reconciliation stopped: %d entries reconciled, %d discrepancies, %d duplicates skipped",
+		stats.Reconciled, stats.Discrepancies, stats.Duplicates)
+}
+
+// run reconciles the feed until ctx is done. A pass that had entries is
+// followed by the next right away, since more may be waiting; an empty or
+// failed one waits for pollInterval. Entries in flight when ctx is done are
+// reconciled on a context that is not canceled.
+func run(ctx context.Context, consumer *settlement.Consumer, pollInterval time.Duration, appLogger *logger.Logger) {
+	for ctx.Err() == nil {
+		received, err := consumer.RunOnce(context.WithoutCancel(ctx))
+		if err != nil {
+			appLogger.Error("Settlement reconciliation pass failed: %v", err)
+		}
+		if received > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// newRepositories creates the repositories for driver
+func newRepositories(driver string, db *sql.DB) repositories {
+	switch driver {
+	case config.DriverMySQL:
+		return repositories{
+			transactions:      mysql.NewTransactionRepository(db, nil),
+			settlementEntries: mysql.NewSettlementEntryRepository(db),
+			disputes:          mysql.NewDisputeRepository(db),
+			outbox:            mysql.NewOutboxRepository(db),
+		}
+	case config.DriverSQLite:
+		return repositories{
+			transactions:      sqlite.NewTransactionRepository(db),
+			settlementEntries: sqlite.NewSettlementEntryRepository(db),
+			disputes:          sqlite.NewDisputeRepository(db),
+			outbox:            sqlite.NewOutboxRepository(db),
+		}
+	}
+
+	return repositories{
+		transactions:      postgres.NewTransactionRepository(db, nil),
+		settlementEntries: postgres.NewSettlementEntryRepository(db),
+		disputes:          postgres.NewDisputeRepository(db),
+		outbox:            postgres.NewOutboxRepository(db),
+	}
+}
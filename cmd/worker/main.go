@@ -0,0 +1,221 @@
+// Command worker processes the payments the API queued.
+//
+// With PAYMENT_ASYNC_PROCESSING=true, POST /transactions/:id/process only
+// queues the payment and answers 202; workers claim the queued jobs and run
+// them against the payment providers, so slow providers hold up workers
+// rather than API requests. Any number of workers can share the queue.
+//
+// Usage:
+//
+//	worker
+//
+// The worker reads the API's configuration. It stops on SIGINT or SIGTERM
+// once the payments in flight are done.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/adapters/persistence/cached"
+	"Pay2Go/internal/adapters/persistence/mysql"
+	"Pay2Go/internal/adapters/persistence/postgres"
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/adapters/persistence/sqlite"
+	"Pay2Go/internal/infrastructure/cache"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/encryption"
+	"Pay2Go/internal/infrastructure/lock"
+	"Pay2Go/internal/infrastructure/logger"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+	"Pay2Go/internal/usecases/usage"
+)
+
+// repositories are the persistence adapters the worker uses
+type repositories struct {
+	transactions        ports.TransactionRepository
+	providerCredentials ports.ProviderCredentialRepository
+	paymentJobs         ports.PaymentJobRepository
+	outbox              ports.OutboxRepository
+	auditLogs           ports.AuditLogRepository
+	usage               ports.UsageRepository
+	locker              ports.Locker
+}
+
+func main() {
+	appLogger := logger.New()
+	appLogger.Info("Starting Pay2Go payment worker...")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		appLogger.Error("Failed to load configuration: %v", err)
+		os.Exit(1)
+	}
+	logLevel, _ := logger.ParseLevel(cfg.Server.LogLevel)
+	appLogger.SetLevel(logLevel)
+
+	// Connect to database
+	db, err := sql.Open(cfg.Database.DriverName(), cfg.Database.GetDSN())
+	if err != nil {
+		appLogger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+	cfg.Database.ConfigurePool(db)
+	if err := db.Ping(); err != nil {
+		appLogger.Error("Failed to ping database: %v", err)
+		os.Exit(1)
+	}
+
+	// Provider credentials are encrypted at rest
+	secretCipher, err := encryption.NewEnvelopeCipher(cfg.Encryption.Keys, cfg.Encryption.PrimaryKeyID)
+	if err != nil {
+		appLogger.Error("Invalid encryption configuration: %v", err)
+		os.Exit(1)
+	}
+	secretCipher.AllowLegacy = cfg.Encryption.AllowLegacy
+	repos := newRepositories(cfg.Database.Driver, db, secretCipher)
+
+	// The API's locks and transaction cache live in Redis when it is
+	// configured, and the worker has to share them: the lock keeps a payment
+	// from being processed here and by a synchronous request at once, and
+	// saves must invalidate what partners polling the transaction read
+	transactionRepo := repos.transactions
+	paymentLocker := repos.locker
+	if cfg.Redis.URL != "" {
+		redisOptions, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			appLogger.Error("Invalid REDIS_URL: %v", err)
+			os.Exit(1)
+		}
+		redisClient := redis.NewClient(redisOptions)
+		defer redisClient.Close()
+
+		paymentLocker = lock.NewRedisLocker(redisClient)
+		if cfg.Cache.TransactionTTLSeconds > 0 {
+			transactionRepo = cached.NewTransactionRepository(transactionRepo, cache.NewRedisTransactionCache(redisClient))
+		}
+	}
+
+	// The same gateways as the API: test-mode transactions go to the
+	// sandbox, live ones to the partner's own provider account if they
+	// connected one
+	var paymentGateway ports.PaymentGateway = payment.NewModeRouter(
+		payment.NewCredentialRouter(
+			repos.providerCredentials,
+			payment.NewMockPaymentGateway("mock"),
+			payment.NewPartnerAccountGateway,
+		),
+		payment.NewSandboxPaymentGateway(),
+	)
+	usageMeter := usage.NewMeter(repos.usage, time.Minute, func(err error) {
+		appLogger.Error("Failed to write usage, retrying with the next flush: %v", err)
+	})
+	paymentGateway = usageMeter.Gateway(paymentGateway)
+
+	processPaymentUC := transaction.NewProcessPaymentUseCase(
+		transactionRepo,
+		repos.outbox,
+		sqldb.NewUnitOfWork(db),
+		paymentGateway,
+		repos.auditLogs,
+		paymentLocker,
+		time.Duration(cfg.Lock.PaymentTTLSeconds)*time.Second,
+	)
+	worker := transaction.NewPaymentWorker(
+		repos.paymentJobs,
+		transactionRepo,
+		processPaymentUC,
+		cfg.Worker.BatchSize,
+		cfg.Worker.Concurrency,
+		time.Duration(cfg.Worker.ClaimTimeoutSeconds)*time.Second,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The meter stops after the last pass, so its final flush includes the
+	// payments in flight at shutdown
+	meterCtx, stopMeter := context.WithCancel(context.Background())
+	meterDone := make(chan struct{})
+	go func() {
+		defer close(meterDone)
+		usageMeter.Run(meterCtx)
+	}()
+
+	appLogger.Info("Processing queued payments, %d at once", cfg.Worker.Concurrency)
+	run(ctx, worker, time.Duration(cfg.Worker.PollIntervalMs)*time.Millisecond, appLogger)
+
+	stopMeter()
+	<-meterDone
+	stats := worker.Stats()
+	appLogger.Info("Worker stopped: %d payments completed, %d declined, %d attempts retried", stats.Completed, stats.Declined, stats.Retried)
+}
+
+// run runs passes until ctx is done. A pass that found jobs is followed by
+// the next right away, since more may be waiting; an empty one waits for
+// pollInterval. Jobs in flight when ctx is done run to their end, on a
+// context that is not canceled, so no payment is cut off at the provider.
+func run(ctx context.Context, worker *transaction.PaymentWorker, pollInterval time.Duration, appLogger *logger.Logger) {
+	for ctx.Err() == nil {
+		ran, err := worker.RunDue(context.WithoutCancel(ctx))
+		if err != nil {
+			appLogger.Error("Payment worker pass failed: %v", err)
+		}
+		if ran > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// newRepositories creates the repositories for driver
+func newRepositories(driver string, db *sql.DB, cipher ports.SecretCipher) repositories {
+	switch driver {
+	case config.DriverMySQL:
+		return repositories{
+			transactions:        mysql.NewTransactionRepository(db, nil),
+			providerCredentials: mysql.NewProviderCredentialRepository(db, cipher),
+			paymentJobs:         mysql.NewPaymentJobRepository(db),
+			outbox:              mysql.NewOutboxRepository(db),
+			auditLogs:           mysql.NewAuditLogRepository(db, nil),
+			usage:               mysql.NewUsageRepository(db, nil),
+			locker:              mysql.NewNamedLocker(db),
+		}
+	case config.DriverSQLite:
+		return repositories{
+			transactions:        sqlite.NewTransactionRepository(db),
+			providerCredentials: sqlite.NewProviderCredentialRepository(db, cipher),
+			paymentJobs:         sqlite.NewPaymentJobRepository(db),
+			outbox:              sqlite.NewOutboxRepository(db),
+			auditLogs:           sqlite.NewAuditLogRepository(db),
+			usage:               sqlite.NewUsageRepository(db),
+			locker:              lock.NewMemoryLocker(),
+		}
+	}
+
+	return repositories{
+		transactions:        postgres.NewTransactionRepository(db, nil),
+		providerCredentials: postgres.NewProviderCredentialRepository(db, cipher),
+		paymentJobs:         postgres.NewPaymentJobRepository(db),
+		outbox:              postgres.NewOutboxRepository(db),
+		auditLogs:           postgres.NewAuditLogRepository(db, nil),
+		usage:               postgres.NewUsageRepository(db, nil),
+		locker:              postgres.NewAdvisoryLocker(db),
+	}
+}
@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckInterval is how often readiness is checked again for the
+// health service
+const healthCheckInterval = 5 * time.Second
+
+// watchReadiness checks readiness until the server stops, so health checks
+// and watches answer as GET /health/ready does
+func (s *Server) watchReadiness() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.checkReadiness()
+		}
+	}
+}
+
+// checkReadiness sets the status of the server, and of the Payments
+// service, to SERVING when the readiness check passes and NOT_SERVING
+// otherwise. A server without the check is serving until closed.
+func (s *Server) checkReadiness() {
+	status := healthpb.HealthCheckResponse_SERVING
+	if s.readinessUseCase != nil {
+		// The use case bounds its checks with a timeout of its own
+		if !s.readinessUseCase.Execute(context.Background()).Ready() {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	// Once closed, the health server keeps NOT_SERVING whatever is set
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(ServiceName, status)
+}
+
+// healthServer is the standard health service, whose watches end once the
+// server stops, so they do not hold up its graceful stop
+type healthServer struct {
+	*health.Server
+	done <-chan struct{}
+}
+
+// Watch implements Health.Watch: it sends the status, then again whenever
+// it changes, until the client cancels or the server stops. A stopping
+// server sends NOT_SERVING last, so clients move elsewhere.
+func (h healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	watch := &watchStream{Health_WatchServer: stream, ctx: ctx}
+	watched := make(chan error, 1)
+	go func() { watched <- h.Server.Watch(req, watch) }()
+
+	select {
+	case err := <-watched:
+		return err
+	case <-h.done:
+	}
+	// The watch has ended once it returns, so the stream is free to send on
+	cancel()
+	<-watched
+	if watch.last != healthpb.HealthCheckResponse_NOT_SERVING {
+		if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}); err != nil {
+			return err
+		}
+	}
+	return statusf(codes.Unavailable, "server_shutting_down", "the server is shutting down")
+}
+
+// watchStream is the stream of a watch, remembering the status it sent
+type watchStream struct {
+	healthpb.Health_WatchServer
+	ctx  context.Context
+	last healthpb.HealthCheckResponse_ServingStatus
+}
+
+// Context returns the context of the watch
+func (w *watchStream) Context() context.Context {
+	return w.ctx
+}
+
+// Send sends a status
+func (w *watchStream) Send(response *healthpb.HealthCheckResponse) error {
+	w.last = response.Status
+	return w.Health_WatchServer.Send(response)
+}
@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// The interceptors mirror the HTTP middleware of the same names, so calls
+// are traced, logged, measured and recovered from as requests are
+
+// metadataRequestID carries the ID of a call, in both directions
+const metadataRequestID = "x-request-id"
+
+// RequestID gives every call an ID, returned in the x-request-id header
+// metadata and carried to audit entries, payment providers and error
+// reports. A caller can send the ID itself, so a request keeps its ID from
+// the service that received it; IDs that are not UUIDs are replaced.
+func RequestID(ctx context.Context, _ string, next func(ctx context.Context) error) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id, err := uuid.Parse(firstValue(md, metadataRequestID))
+	if err != nil || id == uuid.Nil {
+		id = uuid.New()
+	}
+	callOf(ctx).requestID = id
+	_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, id.String()))
+	return next(ports.WithRequestID(ctx, id))
+}
+
+// CallLog is where calls are logged, such as the application logger, whose
+// level decides whether they are written
+type CallLog interface {
+	Info(message string, args ...interface{})
+}
+
+// Logger logs every call once it ends
+func Logger(log CallLog) Interceptor {
+	return func(ctx context.Context, method string, next func(ctx context.Context) error) error {
+		start := time.Now()
+		err := next(ctx)
+
+		c := callOf(ctx)
+		requestID, partnerID := "", ""
+		if c.requestID != uuid.Nil {
+			requestID = c.requestID.String()
+		}
+		if c.caller.partnerID != uuid.Nil {
+			partnerID = c.caller.partnerID.String()
+		}
+		log.Info("gRPC Call: request_id=%s partner_id=%s method=%s code=%s duration=%s peer=%s",
+			requestID,
+			partnerID,
+			method,
+			codeName(codeOfError(err)),
+			time.Since(start),
+			peerAddress(ctx),
+		)
+		return err
+	}
+}
+
+// CallObserver records served calls, such as the Prometheus metrics
+type CallObserver interface {
+	ObserveCall(method, code string, duration time.Duration)
+}
+
+// Metrics records the latency of every call by method and status code.
+// Calls to methods the server does not have are recorded as "unknown", so
+// what clients send does not each become a series.
+func Metrics(observer CallObserver) Interceptor {
+	return func(ctx context.Context, method string, next func(ctx context.Context) error) error {
+		start := time.Now()
+		err := next(ctx)
+
+		if !callOf(ctx).known {
+			method = "unknown"
+		}
+		observer.ObserveCall(method, codeName(codeOfError(err)), time.Since(start))
+		return err
+	}
+}
+
+// Recovery recovers from panics, which end the call with INTERNAL, and
+// reports them, and internal errors, to reporter, which may be nil
+func Recovery(reporter ports.ErrorReporter) Interceptor {
+	return func(ctx context.Context, method string, next func(ctx context.Context) error) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				if reporter != nil {
+					reporter.Report(ctx, ports.ErrorReport{Panic: r, Stack: debug.Stack(), Tags: callTags(ctx, method)})
+				}
+				err = statusf(codes.Internal, "internal_server_error", "An unexpected error occurred")
+			}
+		}()
+
+		err = next(ctx)
+		if reporter != nil && err != nil && codeOfError(err) == codes.Internal {
+			reporter.Report(ctx, ports.ErrorReport{Err: err, Tags: callTags(ctx, method)})
+		}
+		return err
+	}
+}
+
+// callTags describes the call an error happened in
+func callTags(ctx context.Context, method string) map[string]string {
+	tags := map[string]string{
+		"method": method,
+	}
+	if id := callOf(ctx).caller.partnerID; id != uuid.Nil {
+		tags["partner_id"] = id.String()
+	}
+	return tags
+}
+
+// codeOfError returns the code a call that returned err ends with
+func codeOfError(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	return statusOf(err, "").Code
+}
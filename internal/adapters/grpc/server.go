@@ -0,0 +1,508 @@
+// Package grpc serves the Payments service of proto/pay2go/v1/payments.proto
+// to internal services, with the standard health and reflection services.
+// It registers the code protoc-gen-go and protoc-gen-go-grpc generate from
+// that file (internal/gen/pay2go/v1, see `make proto`) on a grpc-go server,
+// backed by the same use cases as the HTTP API.
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	pay2gov1 "Pay2Go/internal/gen/pay2go/v1"
+	"Pay2Go/internal/usecases/apikey"
+	healthuc "Pay2Go/internal/usecases/health"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+const (
+	// ServiceName is the full name of the Payments service
+	ServiceName = "pay2go.v1.Payments"
+
+	// HealthServiceName is the full name of the standard health service
+	// (grpc.health.v1), which Kubernetes gRPC probes and load balancers call
+	HealthServiceName = "grpc.health.v1.Health"
+
+	// ReflectionServiceName is the full name of the server reflection
+	// service, which tools such as grpcurl list and describe services with
+	ReflectionServiceName = "grpc.reflection.v1.ServerReflection"
+
+	// maxMessageBytes bounds the size of a request message
+	maxMessageBytes = 4 << 20
+
+	// trailerErrorCode carries the error code of a failed call
+	trailerErrorCode = "pay2go-error-code"
+)
+
+// caller is who made a call, as its API key tells
+type caller struct {
+	partnerID uuid.UUID
+	livemode  bool
+	ipAddress string
+	userAgent string
+}
+
+// method is a method of a service and the scope calling it takes
+type method struct {
+	scope    valueobjects.APIKeyScope // Empty for methods open to any client
+	fallback string                   // Code of errors that are no domain error
+}
+
+// call is the state of a call being served, which the server keeps in its
+// context for the interceptors
+type call struct {
+	method    string
+	known     bool // Whether the server has the method
+	requestID uuid.UUID
+	caller    caller // Set by authentication
+}
+
+type callKey struct{}
+
+// callOf returns the call ctx belongs to
+func callOf(ctx context.Context) *call {
+	if c, ok := ctx.Value(callKey{}).(*call); ok {
+		return c
+	}
+	return &call{}
+}
+
+// Interceptor wraps every call the server serves, unary or streaming, as
+// middleware wraps HTTP requests. method is the full method name, such as
+// /pay2go.v1.Payments/GetTransaction; next serves the call with the context
+// given, and an interceptor may end the call with an error instead.
+type Interceptor func(ctx context.Context, method string, next func(ctx context.Context) error) error
+
+// Server serves the Payments service, with the standard health
+// (grpc.health.v1) and reflection (grpc.reflection.v1 and v1alpha)
+// services, in clear text for internal networks.
+type Server struct {
+	pay2gov1.UnimplementedPaymentsServer
+
+	authenticator *apikey.Authenticator
+	partnerRepo   ports.PartnerRepository
+	usageTracker  *apikey.UsageTracker
+
+	createTxnUseCase  *transaction.CreateTransactionUseCase
+	getTxnUseCase     *transaction.GetTransactionUseCase
+	processTxnUseCase *transaction.ProcessPaymentUseCase
+	enqueueUseCase    *transaction.EnqueuePaymentUseCase
+	refundUseCase     *transaction.RefundTransactionUseCase
+	readinessUseCase  *healthuc.CheckReadinessUseCase
+
+	methods      map[string]method
+	interceptors []Interceptor
+
+	server *grpc.Server
+	health *health.Server
+
+	// done is closed when the server stops, which ends the readiness checks
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer creates a new Payments server. enqueueUseCase is nil when
+// payments are processed synchronously, and usageTracker may be nil.
+// readinessUseCase answers health checks; without it the server reports
+// itself serving until closed.
+func NewServer(
+	authenticator *apikey.Authenticator,
+	partnerRepo ports.PartnerRepository,
+	usageTracker *apikey.UsageTracker,
+	createTxnUseCase *transaction.CreateTransactionUseCase,
+	getTxnUseCase *transaction.GetTransactionUseCase,
+	processTxnUseCase *transaction.ProcessPaymentUseCase,
+	enqueueUseCase *transaction.EnqueuePaymentUseCase,
+	refundUseCase *transaction.RefundTransactionUseCase,
+	readinessUseCase *healthuc.CheckReadinessUseCase,
+) *Server {
+	s := &Server{
+		authenticator:     authenticator,
+		partnerRepo:       partnerRepo,
+		usageTracker:      usageTracker,
+		createTxnUseCase:  createTxnUseCase,
+		getTxnUseCase:     getTxnUseCase,
+		processTxnUseCase: processTxnUseCase,
+		enqueueUseCase:    enqueueUseCase,
+		refundUseCase:     refundUseCase,
+		readinessUseCase:  readinessUseCase,
+		health:            health.NewServer(),
+		done:              make(chan struct{}),
+	}
+	methodName := func(service, name string) string { return "/" + service + "/" + name }
+	s.methods = map[string]method{
+		methodName(ServiceName, "CreateTransaction"): {scope: valueobjects.ScopePayments, fallback: "transaction_creation_failed"},
+		methodName(ServiceName, "ProcessPayment"):    {scope: valueobjects.ScopePayments, fallback: "payment_processing_failed"},
+		methodName(ServiceName, "Refund"):            {scope: valueobjects.ScopeRefunds, fallback: "refund_failed"},
+		methodName(ServiceName, "GetTransaction"):    {scope: valueobjects.ScopeReadOnly, fallback: "transaction_not_found"},
+
+		// Probes and tools such as grpcurl call these without an API key
+		methodName(HealthServiceName, "Check"):                                         {fallback: "health_check_failed"},
+		methodName(HealthServiceName, "Watch"):                                         {fallback: "health_check_failed"},
+		methodName(HealthServiceName, "List"):                                          {fallback: "health_check_failed"},
+		methodName(ReflectionServiceName, "ServerReflectionInfo"):                      {fallback: "reflection_failed"},
+		methodName("grpc.reflection.v1alpha.ServerReflection", "ServerReflectionInfo"): {fallback: "reflection_failed"},
+	}
+
+	s.server = grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessageBytes),
+		grpc.UnaryInterceptor(s.interceptUnary),
+		grpc.StreamInterceptor(s.interceptStream),
+		grpc.UnknownServiceHandler(s.unknownMethod),
+	)
+	pay2gov1.RegisterPaymentsServer(s.server, s)
+	healthpb.RegisterHealthServer(s.server, healthServer{Server: s.health, done: s.done})
+	reflection.Register(s.server)
+	return s
+}
+
+// Use appends interceptors to the chain every call runs through, outermost
+// first. Authentication always runs last, next to the method. Call it
+// before Serve.
+func (s *Server) Use(interceptors ...Interceptor) {
+	s.interceptors = append(s.interceptors, interceptors...)
+}
+
+// Serve accepts connections on listener until the server stops
+func (s *Server) Serve(listener net.Listener) error {
+	s.checkReadiness()
+	go s.watchReadiness()
+	return s.server.Serve(listener)
+}
+
+// Close reports the server as not serving to health checks, which ends
+// their watches with NOT_SERVING, so clients move elsewhere while it
+// drains. Call it before Stop.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		s.health.Shutdown()
+		close(s.done)
+	})
+}
+
+// Stop stops the server once the calls in flight end, or cuts them off
+// when ctx is done first
+func (s *Server) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// interceptUnary runs a unary call through the interceptors
+func (s *Server) interceptUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var response interface{}
+	err := s.intercept(ctx, info.FullMethod, func(ctx context.Context) (err error) {
+		response, err = handler(ctx, req)
+		return err
+	})
+	return response, err
+}
+
+// interceptStream runs a streaming call through the interceptors
+func (s *Server) interceptStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.intercept(stream.Context(), info.FullMethod, func(ctx context.Context) error {
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	})
+}
+
+// serverStream is a stream whose context the interceptors set
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the call
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// intercept serves a call through the interceptors, then authentication,
+// and answers failures with their status and error code
+func (s *Server) intercept(ctx context.Context, fullMethod string, serve func(ctx context.Context) error) error {
+	m, known := s.methods[fullMethod]
+	c := &call{method: fullMethod, known: known}
+	ctx = context.WithValue(ctx, callKey{}, c)
+
+	// The interceptors wrap authentication, which wraps the method
+	handler := func(ctx context.Context) error {
+		if err := s.authenticate(ctx, c, m.scope); err != nil {
+			return err
+		}
+		if err := serve(ctx); err != nil {
+			return statusOf(err, m.fallback)
+		}
+		return nil
+	}
+	for i := len(s.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := s.interceptors[i], handler
+		handler = func(ctx context.Context) error {
+			return interceptor(ctx, fullMethod, next)
+		}
+	}
+
+	err := handler(ctx)
+	if err == nil {
+		return nil
+	}
+	status := statusOf(err, "internal_error")
+	if status.ErrorCode != "" {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(trailerErrorCode, status.ErrorCode))
+	}
+	return status
+}
+
+// unknownMethod answers calls to methods the server does not have
+func (s *Server) unknownMethod(_ interface{}, stream grpc.ServerStream) error {
+	name, _ := grpc.MethodFromServerStream(stream)
+	return statusf(codes.Unimplemented, "unknown_method", "unknown method %s", name)
+}
+
+// authenticate resolves the bearer API key of the call's metadata to its
+// active partner, requiring scope, and records it as the caller. Methods
+// without a scope, and unknown ones, are called without an API key.
+func (s *Server) authenticate(ctx context.Context, c *call, scope valueobjects.APIKeyScope) error {
+	if scope == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	token, ok := strings.CutPrefix(firstValue(md, "authorization"), "Bearer ")
+	if !ok || token == "" {
+		return statusf(codes.Unauthenticated, "unauthorized", "missing bearer API key")
+	}
+	key, err := s.authenticator.Authenticate(ctx, token)
+	if err != nil {
+		message := "invalid API key"
+		if err == errors.ErrAPIKeyRevoked || err == errors.ErrAuthMethod {
+			message = err.Error()
+		}
+		return statusf(codes.Unauthenticated, "unauthorized", "%s", message)
+	}
+
+	partner, err := s.partnerRepo.GetByID(ports.ReadOnly(ctx), key.PartnerID)
+	if err != nil || partner == nil {
+		return statusf(codes.Unauthenticated, "unauthorized", "invalid API key")
+	}
+	if !partner.IsActive {
+		return statusf(codes.PermissionDenied, "forbidden", "partner account is inactive")
+	}
+	if !valueobjects.HasScope(key.Scopes, scope) {
+		return statusf(codes.PermissionDenied, "insufficient_scope", "API key requires the %s scope", scope)
+	}
+
+	ipAddress := peerAddress(ctx)
+	if host, _, err := net.SplitHostPort(ipAddress); err == nil {
+		ipAddress = host
+	}
+	if s.usageTracker != nil {
+		s.usageTracker.Record(key.ID, ipAddress)
+	}
+	c.caller = caller{
+		partnerID: partner.ID,
+		livemode:  key.Livemode,
+		ipAddress: ipAddress,
+		userAgent: firstValue(md, "user-agent"),
+	}
+	return nil
+}
+
+// CreateTransaction implements Payments.CreateTransaction
+func (s *Server) CreateTransaction(ctx context.Context, req *pay2gov1.CreateTransactionRequest) (*pay2gov1.CreateTransactionResponse, error) {
+	caller := callOf(ctx).caller
+	if req.IdempotencyKey == "" || req.Amount == "" || req.Currency == "" {
+		return nil, statusf(codes.InvalidArgument, "validation_error", "missing required fields")
+	}
+
+	money, err := valueobjects.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make(map[string]interface{}, len(req.Metadata))
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+
+	output, err := s.createTxnUseCase.Execute(ctx, transaction.CreateTransactionInput{
+		PartnerID:      caller.partnerID,
+		IdempotencyKey: req.IdempotencyKey,
+		Amount:         money.Amount,
+		Currency:       money.Currency.String(),
+		PaymentMethod:  req.PaymentMethod,
+		Provider:       req.Provider,
+		CustomerEmail:  req.CustomerEmail,
+		CustomerName:   req.CustomerName,
+		CustomerPhone:  req.CustomerPhone,
+		BillingCountry: req.BillingCountry,
+		Description:    req.Description,
+		Metadata:       metadata,
+		Livemode:       caller.livemode,
+		IPAddress:      caller.ipAddress,
+		UserAgent:      caller.userAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pay2gov1.CreateTransactionResponse{
+		TransactionId: output.TransactionID.String(),
+		Status:        output.Status,
+		Livemode:      output.Livemode,
+		CreatedAt:     timestamppb.New(output.CreatedAt),
+	}, nil
+}
+
+// ProcessPayment implements Payments.ProcessPayment
+func (s *Server) ProcessPayment(ctx context.Context, req *pay2gov1.ProcessPaymentRequest) (*pay2gov1.ProcessPaymentResponse, error) {
+	caller := callOf(ctx).caller
+	txnID, err := parseTransactionID(req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.enqueueUseCase != nil {
+		job, err := s.enqueueUseCase.Execute(ctx, caller.partnerID, txnID)
+		if err != nil {
+			return nil, err
+		}
+		return &pay2gov1.ProcessPaymentResponse{Queued: true, JobId: job.ID.String()}, nil
+	}
+
+	// Processing takes the transaction ID alone, so the caller's access to
+	// it is checked first
+	if _, err := s.getTxnUseCase.Execute(ctx, txnID, caller.partnerID, caller.livemode); err != nil {
+		return nil, err
+	}
+	if err := s.processTxnUseCase.Execute(ctx, txnID); err != nil {
+		return nil, err
+	}
+	return &pay2gov1.ProcessPaymentResponse{}, nil
+}
+
+// Refund implements Payments.Refund
+func (s *Server) Refund(ctx context.Context, req *pay2gov1.RefundRequest) (*pay2gov1.Refund, error) {
+	caller := callOf(ctx).caller
+	txnID, err := parseTransactionID(req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	money, err := valueobjects.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := s.refundUseCase.Execute(ctx, transaction.RefundTransactionInput{
+		TransactionID: txnID,
+		PartnerID:     caller.partnerID,
+		Amount:        money.Amount,
+		Currency:      money.Currency.String(),
+		ReasonCode:    req.Reason,
+		ReasonNote:    req.ReasonNote,
+		Livemode:      caller.livemode,
+		IPAddress:     caller.ipAddress,
+		UserAgent:     caller.userAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pay2gov1.Refund{
+		Id:            refund.ID.String(),
+		TransactionId: refund.TransactionID.String(),
+		Amount:        refund.Amount.Decimal(),
+		Currency:      refund.Amount.Currency.String(),
+		Status:        string(refund.Status),
+		Reason:        string(refund.Reason.Code),
+		ReasonNote:    refund.Reason.Note,
+		CreatedAt:     timestamppb.New(refund.CreatedAt),
+	}, nil
+}
+
+// GetTransaction implements Payments.GetTransaction
+func (s *Server) GetTransaction(ctx context.Context, req *pay2gov1.GetTransactionRequest) (*pay2gov1.Transaction, error) {
+	caller := callOf(ctx).caller
+	txnID, err := parseTransactionID(req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := s.getTxnUseCase.Execute(ctx, txnID, caller.partnerID, caller.livemode)
+	if err != nil {
+		return nil, err
+	}
+	return mapTransaction(txn), nil
+}
+
+// mapTransaction maps a transaction to its message
+func mapTransaction(txn *entities.Transaction) *pay2gov1.Transaction {
+	response := &pay2gov1.Transaction{
+		Id:                    txn.ID.String(),
+		IdempotencyKey:        txn.IdempotencyKey,
+		Amount:                txn.Amount.Decimal(),
+		Currency:              txn.Amount.Currency.String(),
+		PaymentMethod:         txn.PaymentMethod.String(),
+		Provider:              txn.Provider.String(),
+		ProviderTransactionId: txn.ProviderTransactionID,
+		Status:                string(txn.Status),
+		Livemode:              txn.Livemode,
+		CustomerEmail:         txn.CustomerEmail,
+		CustomerName:          txn.CustomerName,
+		Description:           txn.Description,
+		ErrorCode:             txn.ErrorCode,
+		ErrorMessage:          txn.ErrorMessage,
+		CreatedAt:             timestamppb.New(txn.CreatedAt),
+		UpdatedAt:             timestamppb.New(txn.UpdatedAt),
+	}
+	if txn.ProcessedAt != nil {
+		response.ProcessedAt = timestamppb.New(*txn.ProcessedAt)
+	}
+	return response
+}
+
+// parseTransactionID parses the transaction ID of a request
+func parseTransactionID(id string) (uuid.UUID, error) {
+	txnID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, statusf(codes.InvalidArgument, "invalid_transaction_id", "invalid transaction ID format")
+	}
+	return txnID, nil
+}
+
+// firstValue returns the first value of key in md, or ""
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// peerAddress returns the address of the client of a call
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
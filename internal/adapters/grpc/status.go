@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"Pay2Go/internal/adapters/http/handlers"
+)
+
+// codeNames are the names of the codes, as gRPC tools print them and the
+// metrics label calls with
+var codeNames = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// codeName returns the name of code, such as NOT_FOUND
+func codeName(code codes.Code) string {
+	if name, ok := codeNames[code]; ok {
+		return name
+	}
+	return "CODE_" + strconv.Itoa(int(code))
+}
+
+// Status is the outcome of a call that failed. ErrorCode is the code of
+// docs/ERRORS.md the HTTP API answers the same failure with, sent as the
+// pay2go-error-code trailer.
+type Status struct {
+	Code      codes.Code
+	Message   string
+	ErrorCode string
+}
+
+// Error implements error
+func (s *Status) Error() string {
+	return fmt.Sprintf("grpc status %s: %s", codeName(s.Code), s.Message)
+}
+
+// GRPCStatus returns the status grpc-go sends for s
+func (s *Status) GRPCStatus() *status.Status {
+	return status.New(s.Code, s.Message)
+}
+
+// statusf returns a Status with a formatted message
+func statusf(code codes.Code, errorCode, format string, args ...interface{}) *Status {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...), ErrorCode: errorCode}
+}
+
+// statusOf returns the status answering err. Domain errors are answered as
+// the HTTP API answers them, and failures of grpc-go itself, such as a
+// request that does not decode, keep their status; other errors are
+// internal, with fallback as their code.
+func statusOf(err error, fallback string) *Status {
+	var s *Status
+	if stderrors.As(err, &s) {
+		return s
+	}
+	if classified, ok := handlers.ClassifyError(err); ok {
+		return &Status{Code: codeOf(classified.Status), Message: classified.Message, ErrorCode: classified.Code}
+	}
+	if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return &Status{Code: status.FromContextError(err).Code(), Message: err.Error()}
+	}
+	if grpcStatus, ok := status.FromError(err); ok {
+		return &Status{Code: grpcStatus.Code(), Message: grpcStatus.Message()}
+	}
+	return &Status{Code: codes.Internal, Message: err.Error(), ErrorCode: fallback}
+}
+
+// codeOf maps the HTTP status of an error to the closest gRPC code
+func codeOf(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if status >= 400 && status < 500 {
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminLoginRequest represents a back-office admin's sign-in
+type AdminLoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,len=6"`
+}
+
+// AdminResponse represents a back-office admin in API responses
+type AdminResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// AdminLoginResponse carries the admin session token, shown only once
+type AdminLoginResponse struct {
+	Token     string        `json:"token"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	Admin     AdminResponse `json:"admin"`
+}
+
+// RuntimeSettingsRequest changes runtime settings of the API process; omitted
+// fields stay as they are
+type RuntimeSettingsRequest struct {
+	LogLevel             *string `json:"log_level"`
+	GCPercent            *int    `json:"gc_percent"`
+	BlockProfileRate     *int    `json:"block_profile_rate"`
+	MutexProfileFraction *int    `json:"mutex_profile_fraction"`
+}
+
+// RuntimeSettingsResponse represents the runtime settings of the API process
+// that answered; every instance has its own
+type RuntimeSettingsResponse struct {
+	LogLevel             string `json:"log_level"`
+	GCPercent            int    `json:"gc_percent"`
+	BlockProfileRate     int    `json:"block_profile_rate"`
+	MutexProfileFraction int    `json:"mutex_profile_fraction"`
+}
@@ -0,0 +1,95 @@
+package dto
+
+// AnalyticsRequest is the range of days analytics are read for, both
+// inclusive, as YYYY-MM-DD in UTC, and the currency they are narrowed to.
+// Without a range the last 30 days are read.
+type AnalyticsRequest struct {
+	DateFrom string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo   string `query:"date_to" validate:"omitempty,datetime=2006-01-02"`
+	Currency string `query:"currency" validate:"omitempty,len=3"`
+}
+
+// VolumeRequest reads payment volume over time
+type VolumeRequest struct {
+	AnalyticsRequest
+	Interval string `query:"interval" validate:"omitempty,oneof=day week month"`
+}
+
+// SuccessRatesRequest reads payment success rates
+type SuccessRatesRequest struct {
+	AnalyticsRequest
+	GroupBy string `query:"group_by" validate:"omitempty,oneof=provider payment_method"`
+}
+
+// VolumePointResponse is the succeeded payments of one currency in a bucket
+type VolumePointResponse struct {
+	// Start is the first day of the bucket; weeks start on Monday
+	Start    string `json:"start"`
+	Currency string `json:"currency"`
+	Payments int64  `json:"payments"`
+	Volume   string `json:"volume"`
+}
+
+// VolumeResponse is payment volume over time, ordered by bucket and
+// currency; buckets without payments are left out
+type VolumeResponse struct {
+	DateFrom string                `json:"date_from"`
+	DateTo   string                `json:"date_to"`
+	Interval string                `json:"interval"`
+	Series   []VolumePointResponse `json:"series"`
+}
+
+// SuccessRateResponse is the outcome of one provider's or payment
+// method's payments
+type SuccessRateResponse struct {
+	Key       string `json:"key"`
+	Payments  int64  `json:"payments"`
+	Succeeded int64  `json:"succeeded"`
+	Failed    int64  `json:"failed"`
+	// SuccessRate is the share of payments that succeeded; null without
+	// payments
+	SuccessRate *float64 `json:"success_rate"`
+}
+
+// SuccessRatesResponse is the success rates, most payments first
+type SuccessRatesResponse struct {
+	DateFrom string                `json:"date_from"`
+	DateTo   string                `json:"date_to"`
+	GroupBy  string                `json:"group_by"`
+	Rates    []SuccessRateResponse `json:"rates"`
+}
+
+// AverageAmountResponse is the average succeeded payment of one currency
+type AverageAmountResponse struct {
+	Currency      string `json:"currency"`
+	Payments      int64  `json:"payments"`
+	Volume        string `json:"volume"`
+	AverageAmount string `json:"average_amount"`
+}
+
+// AverageAmountsResponse is the average amounts of the currencies with
+// succeeded payments
+type AverageAmountsResponse struct {
+	DateFrom   string                  `json:"date_from"`
+	DateTo     string                  `json:"date_to"`
+	Currencies []AverageAmountResponse `json:"currencies"`
+}
+
+// RefundRateResponse is the refunds of one currency against its volume
+type RefundRateResponse struct {
+	Currency       string `json:"currency"`
+	Payments       int64  `json:"payments"`
+	Volume         string `json:"volume"`
+	Refunds        int64  `json:"refunds"`
+	RefundedVolume string `json:"refunded_volume"`
+	// RefundRate is the share of the volume refunded; null without volume
+	RefundRate *float64 `json:"refund_rate"`
+}
+
+// RefundRatesResponse is the refund rates by currency. Refunds are counted
+// on the day they are made, so they can be of payments before the range.
+type RefundRatesResponse struct {
+	DateFrom   string               `json:"date_from"`
+	DateTo     string               `json:"date_to"`
+	Currencies []RefundRateResponse `json:"currencies"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+)
+
+// CreateAPIKeyRequest represents a request to issue a new API key
+type CreateAPIKeyRequest struct {
+	Label      string   `json:"label" validate:"required,min=1,max=100"`
+	Scopes     []string `json:"scopes" validate:"required,min=1,dive,oneof=read_only payments refunds admin"`
+	AuthMethod string   `json:"auth_method" validate:"omitempty,oneof=bearer hmac"`
+	Livemode   *bool    `json:"livemode"` // Defaults to true; false issues a sandbox key
+}
+
+// APIKeyResponse represents an API key (the secret is never returned after creation)
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Label      string     `json:"label"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AuthMethod string     `json:"auth_method"`
+	Livemode   bool       `json:"livemode"`
+	LastUsedAt *time.Time `json:"last_used_at"` // Null if the key was never used
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyResponse includes the plaintext key, shown only once
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ListAPIKeysResponse represents a partner's API keys
+type ListAPIKeysResponse struct {
+	APIKeys []APIKeyResponse `json:"api_keys"`
+}
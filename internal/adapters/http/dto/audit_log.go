@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+)
+
+// ListAuditLogsRequest represents query parameters for listing audit logs.
+// PartnerID is only read by the back-office listing.
+type ListAuditLogsRequest struct {
+	Action       string `query:"action"`
+	ResourceType string `query:"resource_type"`
+	ResourceID   string `query:"resource_id" validate:"omitempty,uuid"`
+	PartnerID    string `query:"partner_id" validate:"omitempty,uuid"`
+	DateFrom     string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo       string `query:"date_to" validate:"omitempty,datetime=2006-01-02"` // Inclusive
+	Limit        int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset       int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+// AuditLogResponse represents an audit log entry. IDs the action was logged
+// without are left out.
+type AuditLogResponse struct {
+	ID           int64                  `json:"id"`
+	PartnerID    string                 `json:"partner_id,omitempty"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id,omitempty"`
+	IPAddress    string                 `json:"ip_address"`
+	UserAgent    string                 `json:"user_agent,omitempty"`
+	RequestID    string                 `json:"request_id,omitempty"`
+	Changes      map[string]interface{} `json:"changes,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	PrevHash     string                 `json:"prev_hash,omitempty"` // Empty for entries logged before chaining
+	Hash         string                 `json:"hash,omitempty"`
+}
+
+// ListAuditLogsResponse represents a paginated audit log list, newest first
+type ListAuditLogsResponse struct {
+	AuditLogs []AuditLogResponse `json:"audit_logs"`
+	Total     int64              `json:"total"`
+	Limit     int                `json:"limit"`
+	Offset    int                `json:"offset"`
+}
+
+// AuditChainResponse represents the verification of one audit chain
+type AuditChainResponse struct {
+	PartnerID string `json:"partner_id,omitempty"` // Empty for actions logged without a partner
+	Intact    bool   `json:"intact"`
+	Verified  int64  `json:"verified"`
+	Unchained int64  `json:"unchained"`
+	HeadHash  string `json:"head_hash,omitempty"`
+
+	// BrokenEntryID and Reason describe the first entry that does not fit
+	// the chain
+	BrokenEntryID int64  `json:"broken_entry_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// VerifyAuditChainsResponse represents the verification of several chains
+type VerifyAuditChainsResponse struct {
+	Intact bool                 `json:"intact"`
+	Chains []AuditChainResponse `json:"chains"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventResponse represents an event as partners receive it, the same in
+// webhooks and the event stream
+type EventResponse struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+)
+
+// CreateExportRequest represents a request for an export file
+type CreateExportRequest struct {
+	Resource string               `json:"resource" validate:"required,oneof=transactions refunds"`
+	Format   string               `json:"format" validate:"omitempty,oneof=csv ndjson quickbooks_iif xero_csv"` // Defaults to csv
+	Filters  ExportFiltersRequest `json:"filters"`
+	Columns  []string             `json:"columns"`  // CSV columns, in order; defaults to all
+	Timezone string               `json:"timezone"` // IANA zone name of the file's times; defaults to UTC
+}
+
+// ExportFiltersRequest narrows an export; the filters of the resource's list
+// endpoint, with date ranges as long as needed
+type ExportFiltersRequest struct {
+	Status        string            `json:"status"` // One status or, for transactions, a comma-separated list
+	Currency      string            `json:"currency" validate:"omitempty,len=3"`
+	AmountMin     string            `json:"amount_min"` // Decimal string in major units; needs currency. Transactions only.
+	AmountMax     string            `json:"amount_max"` // Decimal string in major units; needs currency. Transactions only.
+	DateFrom      string            `json:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo        string            `json:"date_to" validate:"omitempty,datetime=2006-01-02"` // Inclusive
+	Metadata      map[string]string `json:"metadata"`                                         // Transactions only
+	TransactionID string            `json:"transaction_id" validate:"omitempty,uuid"`         // Refunds only
+}
+
+// ExportResponse represents an export and, once completed, where to
+// download it
+type ExportResponse struct {
+	ID          string     `json:"id"`
+	Resource    string     `json:"resource"`
+	Format      string     `json:"format"`
+	Columns     []string   `json:"columns,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"`
+	Status      string     `json:"status"`
+	RowCount    int64      `json:"row_count"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// DownloadURL is signed, needs no API key and stops working at
+	// DownloadExpiresAt; fetch the export again for a fresh one
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
+
+// DownloadExportRequest represents the signature of an export download URL
+type DownloadExportRequest struct {
+	Expires   int64  `query:"expires" validate:"required"`
+	Signature string `query:"signature" validate:"required"`
+}
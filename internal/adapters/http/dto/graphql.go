@@ -0,0 +1,8 @@
+package dto
+
+// GraphQLRequest represents a GraphQL query posted as JSON
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+// MetricsResponse represents runtime metrics of the API process
+type MetricsResponse struct {
+	// Database holds connection pool statistics per pool ("primary", "replica")
+	Database map[string]PoolStats `json:"database"`
+	// Webhooks holds the load of the outbox relay delivering webhooks
+	Webhooks WebhookRelayStats `json:"webhooks"`
+	// HTTPClient holds the load of outbound HTTP requests
+	HTTPClient HTTPClientStats `json:"http_client"`
+}
+
+// PoolStats represents database connection pool statistics
+type PoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// WebhookRelayStats represents the load of the webhook relay. Queued events
+// and full batches that keep growing mean webhooks are delivered slower than
+// they arrive. The relay publishes the outbox shards whose number modulo
+// ShardCount is ShardIndex.
+type WebhookRelayStats struct {
+	Workers     int   `json:"workers"`
+	PerPartner  int   `json:"per_partner"`
+	ShardIndex  int   `json:"shard_index"`
+	ShardCount  int   `json:"shard_count"`
+	Queued      int64 `json:"queued"`
+	InFlight    int64 `json:"in_flight"`
+	Published   int64 `json:"published"`
+	Failed      int64 `json:"failed"`
+	Throttled   int64 `json:"throttled"`
+	FullBatches int64 `json:"full_batches"`
+}
+
+// HTTPClientStats represents the load of the connection pool outbound HTTP
+// requests share. Opened connections growing with requests instead of
+// reused ones mean the idle pool is too small.
+type HTTPClientStats struct {
+	Requests          int64 `json:"requests"`
+	Failed            int64 `json:"failed"`
+	InFlight          int64 `json:"in_flight"`
+	ConnectionsOpened int64 `json:"connections_opened"`
+	ConnectionsReused int64 `json:"connections_reused"`
+}
@@ -0,0 +1,215 @@
+package dto
+
+import (
+	"time"
+)
+
+// ListPartnersRequest represents query parameters for listing partners
+type ListPartnersRequest struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+}
+
+// PartnerSummary represents a partner in the back-office partner list
+type PartnerSummary struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	IsActive  bool      `json:"is_active"`
+	Locale    string    `json:"locale"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListPartnersResponse represents a paginated partner list
+type ListPartnersResponse struct {
+	Partners []PartnerSummary `json:"partners"`
+	Total    int64            `json:"total"`
+	Limit    int              `json:"limit"`
+	Offset   int              `json:"offset"`
+}
+
+// PutPartnerRequest represents the desired state of a partner; fields left
+// out take their defaults rather than keeping their current value
+type PutPartnerRequest struct {
+	Name               string `json:"name" validate:"required"`
+	Email              string `json:"email" validate:"required,email"`
+	IsActive           *bool  `json:"is_active"`                                                  // Defaults to true
+	RateLimitPerMinute int    `json:"rate_limit_per_minute" validate:"omitempty,min=1,max=10000"` // Defaults to 100
+	// RefundApprovalThresholds maps ISO 4217 codes to decimal amounts above
+	// which refunds need approval, e.g. {"USD": "1000.00"}; empty disables approval
+	RefundApprovalThresholds map[string]string `json:"refund_approval_thresholds"`
+	RefundWindowDays         int               `json:"refund_window_days" validate:"omitempty,min=0,max=3650"` // 0 uses the platform default
+}
+
+// PartnerResponse represents a partner's account settings. Version is also
+// the ETag of the partner's admin resources.
+type PartnerResponse struct {
+	ID                       string            `json:"id"`
+	Name                     string            `json:"name"`
+	Email                    string            `json:"email"`
+	IsActive                 bool              `json:"is_active"`
+	RateLimitPerMinute       int               `json:"rate_limit_per_minute"`
+	RefundApprovalThresholds map[string]string `json:"refund_approval_thresholds"`
+	RefundWindowDays         int               `json:"refund_window_days"`
+	Version                  int               `json:"version"`
+	CreatedAt                time.Time         `json:"created_at"`
+	UpdatedAt                time.Time         `json:"updated_at"`
+}
+
+// UpdatePartnerWebhookRequest represents a request to deliver a partner's webhooks to a URL
+type UpdatePartnerWebhookRequest struct {
+	URL string `json:"url" validate:"required,url"`
+}
+
+// PartnerWebhookResponse represents where a partner's webhooks are delivered
+type PartnerWebhookResponse struct {
+	PartnerID string `json:"partner_id"`
+	// URL is empty when no webhook is configured
+	URL string `json:"url"`
+	// Secret signs the webhooks; it is only returned by the request that
+	// issued it
+	Secret string `json:"secret,omitempty"`
+}
+
+// UpdatePartnerFeaturesRequest represents a request to change a partner's feature flags
+type UpdatePartnerFeaturesRequest struct {
+	Features map[string]bool `json:"features" validate:"required,min=1"`
+}
+
+// PartnerFeaturesResponse represents whether each feature is enabled for a partner
+type PartnerFeaturesResponse struct {
+	PartnerID string          `json:"partner_id"`
+	Features  map[string]bool `json:"features"`
+}
+
+// UpdatePartnerCurrenciesRequest represents a request to restrict a partner's currencies
+type UpdatePartnerCurrenciesRequest struct {
+	Currencies []string `json:"currencies"` // Empty allows every currency
+}
+
+// PartnerCurrenciesResponse represents the currencies a partner may charge in
+type PartnerCurrenciesResponse struct {
+	PartnerID  string   `json:"partner_id"`
+	Currencies []string `json:"currencies"` // Empty means every currency is allowed
+}
+
+// UpdatePartnerAmountLimitsRequest represents a request to set a partner's maximum amounts
+type UpdatePartnerAmountLimitsRequest struct {
+	AmountLimits map[string]string `json:"amount_limits"` // Currency -> decimal amount; empty uses platform limits
+}
+
+// PartnerAmountLimitsResponse represents a partner's own maximum transaction amounts
+type PartnerAmountLimitsResponse struct {
+	PartnerID    string            `json:"partner_id"`
+	AmountLimits map[string]string `json:"amount_limits"` // Currencies not listed use the platform limit
+}
+
+// UpdatePartnerLocaleRequest represents a request to change a partner's locale
+type UpdatePartnerLocaleRequest struct {
+	Locale string `json:"locale" validate:"required,bcp47_language_tag"`
+}
+
+// PartnerLocaleResponse represents a partner's locale
+type PartnerLocaleResponse struct {
+	PartnerID string `json:"partner_id"`
+	Locale    string `json:"locale"`
+	Country   string `json:"country,omitempty"` // Region of the locale, if it is a country
+}
+
+// UpdatePartnerSMSSenderRequest represents a request to change who a partner's text messages come from
+type UpdatePartnerSMSSenderRequest struct {
+	SMSSender string `json:"sms_sender"` // Empty for the platform's sender
+}
+
+// PartnerSMSSenderResponse represents who a partner's text messages come from
+type PartnerSMSSenderResponse struct {
+	PartnerID string `json:"partner_id"`
+	SMSSender string `json:"sms_sender"` // Empty when the platform's sender is used
+}
+
+// UpdateFieldFilterRequest represents a request to choose the fields hidden from a partner's integrations
+type UpdateFieldFilterRequest struct {
+	Responses map[string][]string `json:"responses"` // Fields hidden from API responses, by scope of the calling key
+	Webhooks  []string            `json:"webhooks"`  // Fields hidden from the data of webhooks and streamed events
+}
+
+// FieldFilterResponse represents the fields hidden from a partner's integrations
+type FieldFilterResponse struct {
+	Responses map[string][]string `json:"responses"`
+	Webhooks  []string            `json:"webhooks"`
+}
+
+// UpdateAccountMappingRequest represents a request to name the accounts a
+// partner's accounting exports post to; empty fields take the defaults
+type UpdateAccountMappingRequest struct {
+	ClearingAccount string `json:"clearing_account" validate:"max=100"`                          // Bank account payouts come from; QuickBooks only
+	SalesAccount    string `json:"sales_account" validate:"max=100"`                             // Income account of payments
+	RefundsAccount  string `json:"refunds_account" validate:"max=100"`                           // Account refunds are charged to
+	FeesAccount     string `json:"fees_account" validate:"max=100"`                              // Expense account of Pay2Go's fees
+	DateFormat      string `json:"date_format" validate:"omitempty,oneof=dd/mm/yyyy mm/dd/yyyy"` // Of Xero bank statements; defaults to dd/mm/yyyy
+}
+
+// AccountMappingResponse represents the accounts a partner's accounting
+// exports post to. Default is true while the partner has not named its own.
+type AccountMappingResponse struct {
+	Default         bool   `json:"default"`
+	ClearingAccount string `json:"clearing_account"`
+	SalesAccount    string `json:"sales_account"`
+	RefundsAccount  string `json:"refunds_account"`
+	FeesAccount     string `json:"fees_account"`
+	DateFormat      string `json:"date_format"`
+}
+
+// UpdatePartnerRoundingPolicyRequest represents a request to change how a partner's amounts are rounded
+type UpdatePartnerRoundingPolicyRequest struct {
+	RoundingPolicy string `json:"rounding_policy" validate:"required,oneof=half_up half_even truncate"`
+}
+
+// PartnerRoundingPolicyResponse represents a partner's rounding policy
+type PartnerRoundingPolicyResponse struct {
+	PartnerID      string `json:"partner_id"`
+	RoundingPolicy string `json:"rounding_policy"`
+}
+
+// UpdatePartnerEventDestinationRequest represents a request to deliver a partner's events to its own AWS account
+type UpdatePartnerEventDestinationRequest struct {
+	Type    string `json:"type" validate:"required,oneof=sns sqs"`
+	Target  string `json:"target" validate:"required"` // SNS topic ARN or SQS queue URL
+	RoleARN string `json:"role_arn" validate:"required"`
+}
+
+// EventDestinationDTO represents an SNS topic or SQS queue receiving a partner's events
+type EventDestinationDTO struct {
+	Type    string `json:"type"`
+	Target  string `json:"target"`
+	RoleARN string `json:"role_arn"`
+	Region  string `json:"region"`
+}
+
+// PartnerEventDestinationResponse represents where a partner's events are delivered
+type PartnerEventDestinationResponse struct {
+	PartnerID string `json:"partner_id"`
+	// EventDestination is null when events are delivered to the webhook URL
+	EventDestination *EventDestinationDTO `json:"event_destination"`
+	// ExternalID is the sts:ExternalId Pay2Go assumes the role with, for the
+	// role's trust policy
+	ExternalID string `json:"external_id"`
+}
+
+// RotateSecretsResponse reports how many stored secrets were re-encrypted
+type RotateSecretsResponse struct {
+	Rotated int `json:"rotated"`
+}
+
+// OffboardPartnerResponse summarizes a partner's off-boarding
+type OffboardPartnerResponse struct {
+	PartnerID      string     `json:"partner_id"`
+	RevokedAPIKeys int        `json:"revoked_api_keys"`
+	DeletedAt      *time.Time `json:"deleted_at"`
+	AnonymizeAfter *time.Time `json:"anonymize_after"`
+}
+
+// UpdateReceiptLogoRequest represents a request to set the logo printed on a partner's receipts
+type UpdateReceiptLogoRequest struct {
+	Image []byte `json:"image"` // Base64 of a PNG or JPEG image, at most 512 KB and 1000x1000 pixels
+}
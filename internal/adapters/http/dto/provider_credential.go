@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+)
+
+// SaveProviderCredentialRequest represents a request to connect a partner's provider account
+type SaveProviderCredentialRequest struct {
+	AccountID string `json:"account_id"` // PayPal client ID; optional for Stripe
+	SecretKey string `json:"secret_key" validate:"required"`
+}
+
+// ProviderCredentialResponse represents a provider credential (the secret is masked)
+type ProviderCredentialResponse struct {
+	Provider  string    `json:"provider"`
+	AccountID string    `json:"account_id,omitempty"`
+	SecretKey string    `json:"secret_key"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ListProviderCredentialsResponse represents a partner's provider credentials
+type ListProviderCredentialsResponse struct {
+	Credentials []ProviderCredentialResponse `json:"credentials"`
+}
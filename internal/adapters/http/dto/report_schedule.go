@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"time"
+)
+
+// ReportScheduleRequest represents a request to create or reconfigure a
+// report schedule
+type ReportScheduleRequest struct {
+	Name      string               `json:"name" validate:"required,max=100"`
+	Resource  string               `json:"resource" validate:"required,oneof=transactions refunds statements"`
+	Format    string               `json:"format" validate:"omitempty,oneof=csv ndjson quickbooks_iif xero_csv"` // Defaults to csv
+	Filters   ExportFiltersRequest `json:"filters"`                                                              // As an export's, without dates; not for statements
+	Columns   []string             `json:"columns"`                                                              // CSV columns, in order; defaults to all
+	Timezone  string               `json:"timezone"`                                                             // IANA zone name the schedule runs in; defaults to UTC
+	Frequency string               `json:"frequency" validate:"required,oneof=daily weekly monthly"`
+	Hour      int                  `json:"hour" validate:"min=0,max=23"` // Hour of the day a run is delivered
+	// Destination is where reports are delivered
+	Destination ReportDestinationRequest `json:"destination"`
+}
+
+// ReportDestinationRequest represents where a schedule delivers reports.
+// SFTP schedules take a password or a private key; reconfiguring one
+// without either keeps the credentials it has.
+type ReportDestinationRequest struct {
+	Type string `json:"type" validate:"required,oneof=email sftp s3"`
+
+	// Email
+	Recipients []string `json:"recipients"`
+
+	// SFTP
+	Host       string `json:"host"`
+	Port       int    `json:"port"` // Defaults to 22
+	Username   string `json:"username"`
+	HostKey    string `json:"host_key"` // Server public key, such as "ssh-ed25519 AAAA..."
+	Directory  string `json:"directory"`
+	Password   string `json:"password"`
+	PrivateKey string `json:"private_key"` // Unencrypted, in PEM or OpenSSH form
+
+	// S3
+	Bucket  string `json:"bucket"`
+	Region  string `json:"region"`
+	Prefix  string `json:"prefix"`
+	RoleARN string `json:"role_arn"` // Role Pay2Go assumes, with the partner ID as external ID
+}
+
+// ReportScheduleResponse represents a report schedule
+type ReportScheduleResponse struct {
+	ID          string                    `json:"id"`
+	Name        string                    `json:"name"`
+	Resource    string                    `json:"resource"`
+	Format      string                    `json:"format"`
+	Filters     ReportFiltersResponse     `json:"filters"`
+	Columns     []string                  `json:"columns,omitempty"`
+	Timezone    string                    `json:"timezone,omitempty"`
+	Frequency   string                    `json:"frequency"`
+	Hour        int                       `json:"hour"`
+	Destination ReportDestinationResponse `json:"destination"`
+	NextRunAt   time.Time                 `json:"next_run_at"`
+	LastRunAt   *time.Time                `json:"last_run_at,omitempty"`
+	LastStatus  string                    `json:"last_status,omitempty"`
+	Error       string                    `json:"error,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// ReportFiltersResponse represents the filters of a report schedule
+type ReportFiltersResponse struct {
+	Status        string            `json:"status,omitempty"`
+	Currency      string            `json:"currency,omitempty"`
+	AmountMin     string            `json:"amount_min,omitempty"`
+	AmountMax     string            `json:"amount_max,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	TransactionID string            `json:"transaction_id,omitempty"`
+}
+
+// ReportDestinationResponse represents where a schedule delivers reports.
+// SFTP credentials are never returned; auth tells which one is set.
+type ReportDestinationResponse struct {
+	Type       string   `json:"type"`
+	Recipients []string `json:"recipients,omitempty"`
+	Host       string   `json:"host,omitempty"`
+	Port       int      `json:"port,omitempty"`
+	Username   string   `json:"username,omitempty"`
+	HostKey    string   `json:"host_key,omitempty"`
+	Directory  string   `json:"directory,omitempty"`
+	Auth       string   `json:"auth,omitempty"` // password or private_key
+	Bucket     string   `json:"bucket,omitempty"`
+	Region     string   `json:"region,omitempty"`
+	Prefix     string   `json:"prefix,omitempty"`
+	RoleARN    string   `json:"role_arn,omitempty"`
+}
+
+// ListReportSchedulesResponse represents a partner's report schedules
+type ListReportSchedulesResponse struct {
+	Schedules []ReportScheduleResponse `json:"schedules"`
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+)
+
+// ListDisputesRequest represents query parameters for listing disputes
+type ListDisputesRequest struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+}
+
+// DisputeResponse represents a dispute of a payment, opened by a chargeback
+type DisputeResponse struct {
+	ID                string     `json:"id"`
+	TransactionID     string     `json:"transaction_id"`
+	Provider          string     `json:"provider"`
+	ProviderReference string     `json:"provider_reference"`
+	Amount            string     `json:"amount"`
+	Currency          string     `json:"currency"`
+	Reason            string     `json:"reason,omitempty"`
+	Status            string     `json:"status"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	ReversedAt        *time.Time `json:"reversed_at,omitempty"`
+}
+
+// ListDisputesResponse represents a paginated dispute list, newest first
+type ListDisputesResponse struct {
+	Disputes []DisputeResponse `json:"disputes"`
+	Total    int64             `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+// ListSettlementDiscrepanciesRequest represents query parameters for
+// listing settlement discrepancies
+type ListSettlementDiscrepanciesRequest struct {
+	Provider  string `query:"provider"`
+	PartnerID string `query:"partner_id" validate:"omitempty,uuid"`
+	DateFrom  string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo    string `query:"date_to" validate:"omitempty,datetime=2006-01-02"` // Inclusive
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+// SettlementDiscrepancyResponse represents a settlement or chargeback entry
+// that disagrees with the stored transactions. IDs of a transaction that
+// was not found are left out.
+type SettlementDiscrepancyResponse struct {
+	ID                    string    `json:"id"`
+	Provider              string    `json:"provider"`
+	Reference             string    `json:"reference"`
+	Type                  string    `json:"type"`
+	ProviderTransactionID string    `json:"provider_transaction_id"`
+	Amount                string    `json:"amount"`
+	Fee                   string    `json:"fee"`
+	Currency              string    `json:"currency"`
+	Reason                string    `json:"reason,omitempty"`
+	OccurredAt            time.Time `json:"occurred_at"`
+	Source                string    `json:"source,omitempty"`
+	TransactionID         string    `json:"transaction_id,omitempty"`
+	PartnerID             string    `json:"partner_id,omitempty"`
+	Discrepancy           string    `json:"discrepancy"`
+	Detail                string    `json:"detail"`
+	ReconciledAt          time.Time `json:"reconciled_at"`
+}
+
+// ListSettlementDiscrepanciesResponse represents a paginated discrepancy
+// list, newest first
+type ListSettlementDiscrepanciesResponse struct {
+	Discrepancies []SettlementDiscrepancyResponse `json:"discrepancies"`
+	Total         int64                           `json:"total"`
+	Limit         int                             `json:"limit"`
+	Offset        int                             `json:"offset"`
+}
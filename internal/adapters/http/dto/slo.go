@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// SLOResponse represents the service levels of the rolling windows, for the
+// status page
+type SLOResponse struct {
+	Livemode    bool        `json:"livemode"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Windows     []SLOWindow `json:"windows"`
+}
+
+// SLOWindow represents the service levels of one window, such as 1h
+type SLOWindow struct {
+	Window    string           `json:"window"`
+	Since     time.Time        `json:"since"`
+	Payments  PaymentLevels    `json:"payments"`
+	Webhooks  WebhookLevels    `json:"webhooks"`
+	Providers []ProviderLevels `json:"providers"`
+	Partners  []PartnerLevels  `json:"partners"`
+}
+
+// PaymentLevels represents payment success and latency. Rates and latency
+// are null without payments.
+type PaymentLevels struct {
+	Succeeded    int64    `json:"succeeded"`
+	Failed       int64    `json:"failed"`
+	SuccessRate  *float64 `json:"success_rate"`
+	P95LatencyMs *int64   `json:"p95_latency_ms"`
+}
+
+// WebhookLevels represents webhook delivery success; the rate is null
+// without attempts
+type WebhookLevels struct {
+	Delivered   int64    `json:"delivered"`
+	Failing     int64    `json:"failing"`
+	SuccessRate *float64 `json:"success_rate"`
+}
+
+// ProviderLevels represents the payment service levels of one provider
+type ProviderLevels struct {
+	Provider string        `json:"provider"`
+	Payments PaymentLevels `json:"payments"`
+}
+
+// PartnerLevels represents the service levels one partner got
+type PartnerLevels struct {
+	PartnerID string        `json:"partner_id"`
+	Payments  PaymentLevels `json:"payments"`
+	Webhooks  WebhookLevels `json:"webhooks"`
+}
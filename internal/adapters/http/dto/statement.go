@@ -0,0 +1,111 @@
+package dto
+
+import "time"
+
+// ListStatementsRequest represents query parameters for listing statements
+type ListStatementsRequest struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int `query:"offset" validate:"omitempty,min=0"`
+}
+
+// DownloadStatementRequest represents query parameters for downloading a
+// statement
+type DownloadStatementRequest struct {
+	// Format is csv for the statement document, or quickbooks_iif or
+	// xero_csv for the fees it charges; defaults to csv
+	Format string `query:"format" validate:"omitempty,oneof=csv quickbooks_iif xero_csv"`
+}
+
+// ListPeriodStatementsRequest represents query parameters for listing every
+// partner's statement of a month
+type ListPeriodStatementsRequest struct {
+	Period string `query:"period" validate:"required"` // YYYY-MM
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+// GenerateStatementRequest represents a request to issue a partner's
+// statement of a month that is over
+type GenerateStatementRequest struct {
+	Period string `json:"period" validate:"required"` // YYYY-MM
+}
+
+// StatementTotalResponse represents a count of items and their amount
+type StatementTotalResponse struct {
+	Count  int64  `json:"count"`
+	Amount string `json:"amount"`
+}
+
+// StatementTierFeeResponse represents the fee the payments of one pricing
+// tier were charged
+type StatementTierFeeResponse struct {
+	Tier     string                 `json:"tier"`
+	Percent  float64                `json:"percent"`
+	Fixed    string                 `json:"fixed"`
+	Payments StatementTotalResponse `json:"payments"`
+	Fee      string                 `json:"fee"`
+}
+
+// StatementCurrencyResponse represents the activity and fees of one
+// currency in a statement
+type StatementCurrencyResponse struct {
+	Currency            string                     `json:"currency"`
+	Payments            StatementTotalResponse     `json:"payments"`
+	Refunds             StatementTotalResponse     `json:"refunds"`
+	Chargebacks         StatementTotalResponse     `json:"chargebacks"`
+	ChargebackReversals StatementTotalResponse     `json:"chargeback_reversals"`
+	Fees                []StatementTierFeeResponse `json:"fees"`
+	ChargebackFees      StatementTotalResponse     `json:"chargeback_fees"`
+	TotalFees           string                     `json:"total_fees"`
+	Net                 string                     `json:"net"`
+}
+
+// StatementResponse represents a partner's monthly statement
+type StatementResponse struct {
+	ID             string                      `json:"id"`
+	PartnerID      string                      `json:"partner_id"`
+	Period         string                      `json:"period"`
+	Pricing        PricingResponse             `json:"pricing"`
+	RoundingPolicy string                      `json:"rounding_policy"`
+	Currencies     []StatementCurrencyResponse `json:"currencies"`
+	CreatedAt      time.Time                   `json:"created_at"`
+}
+
+// ListStatementsResponse represents a paginated statement list
+type ListStatementsResponse struct {
+	Statements []StatementResponse `json:"statements"`
+	Total      int64               `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+// PricingTierRequest represents a fee tier of a partner's pricing. Fixed is
+// in minor units of the payment's currency.
+type PricingTierRequest struct {
+	Name    string  `json:"name"`
+	UpTo    int     `json:"up_to"` // 0 on the last tier, which covers the rest
+	Percent float64 `json:"percent"`
+	Fixed   int64   `json:"fixed"`
+}
+
+// UpdatePartnerPricingRequest represents a request to set what a partner's
+// statements charge; no tiers charges the platform's standard pricing
+type UpdatePartnerPricingRequest struct {
+	Tiers         []PricingTierRequest `json:"tiers"`
+	ChargebackFee int64                `json:"chargeback_fee"` // Minor units of the chargeback's currency
+}
+
+// PricingResponse represents the fee tiers and chargeback fee statements
+// charge, in minor units
+type PricingResponse struct {
+	Tiers         []PricingTierRequest `json:"tiers"`
+	ChargebackFee int64                `json:"chargeback_fee"`
+}
+
+// PartnerPricingResponse represents a partner's pricing. Standard is true
+// when the partner has none of its own and is charged the platform's.
+type PartnerPricingResponse struct {
+	PartnerID string `json:"partner_id"`
+	Standard  bool   `json:"standard"`
+	PricingResponse
+}
@@ -1,107 +1,301 @@
-package dto
 // Package dto contains Data Transfer Objects for HTTP API
 // DTOs are the contract between external world and our application
 package dto
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	Version   string    `json:"version"`	Timestamp time.Time `json:"timestamp"`	Status    string    `json:"status"`type HealthCheckResponse struct {// HealthCheckResponse represents health check response}	Details map[string]interface{} `json:"details,omitempty"`	Code    string                 `json:"code,omitempty"`	Message string                 `json:"message"`	Error   string                 `json:"error"`type ErrorResponse struct {// ErrorResponse represents error response}	CreatedAt     time.Time `json:"created_at"`	Reason        string    `json:"reason"`	Status        string    `json:"status"`	Currency      string    `json:"currency"`	Amount        float64   `json:"amount"`	TransactionID string    `json:"transaction_id"`	RefundID      string    `json:"refund_id"`type RefundTransactionResponse struct {// RefundTransactionResponse represents refund response}	Reason   string  `json:"reason" validate:"required,min=1,max=255"`	Currency string  `json:"currency" validate:"required,len=3"`	Amount   float64 `json:"amount" validate:"required,gt=0"`type RefundTransactionRequest struct {// RefundTransactionRequest represents refund request}	Offset       int                      `json:"offset"`	Limit        int                      `json:"limit"`	Total        int64                    `json:"total"`	Transactions []GetTransactionResponse `json:"transactions"`type ListTransactionsResponse struct {// ListTransactionsResponse represents paginated transaction list}	Offset   int    `query:"offset" validate:"omitempty,min=0"`	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`	DateTo   string `query:"date_to" validate:"omitempty,datetime=2006-01-02"`	DateFrom string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`	Status   string `query:"status" validate:"omitempty,oneof=pending processing completed failed cancelled refunded partially_refunded"`type ListTransactionsRequest struct {// ListTransactionsRequest represents query parameters for listing transactions}	ProcessedAt           *time.Time             `json:"processed_at,omitempty"`	UpdatedAt             time.Time              `json:"updated_at"`	CreatedAt             time.Time              `json:"created_at"`	ErrorMessage          string                 `json:"error_message,omitempty"`	ErrorCode             string                 `json:"error_code,omitempty"`	Metadata              map[string]interface{} `json:"metadata,omitempty"`	Description           string                 `json:"description,omitempty"`	CustomerPhone         string                 `json:"customer_phone,omitempty"`	CustomerName          string                 `json:"customer_name,omitempty"`	CustomerEmail         string                 `json:"customer_email"`	Status                string                 `json:"status"`	ProviderTransactionID string                 `json:"provider_transaction_id,omitempty"`	Provider              string                 `json:"provider"`	PaymentMethod         string                 `json:"payment_method"`	Currency              string                 `json:"currency"`	Amount                float64                `json:"amount"`	IdempotencyKey        string                 `json:"idempotency_key"`	PartnerID             string                 `json:"partner_id"`	ID                    string                 `json:"id"`type GetTransactionResponse struct {// GetTransactionResponse represents a transaction details response}	CreatedAt     time.Time `json:"created_at"`	Currency      string    `json:"currency"`	Amount        float64   `json:"amount"`	Status        string    `json:"status"`	TransactionID string    `json:"transaction_id"`type CreateTransactionResponse struct {// CreateTransactionResponse represents the HTTP response}	Metadata       map[string]interface{} `json:"metadata" validate:"omitempty"`	Description    string                 `json:"description" validate:"omitempty,max=500"`	CustomerPhone  string                 `json:"customer_phone" validate:"omitempty,e164"`	CustomerName   string                 `json:"customer_name" validate:"omitempty,min=1,max=255"`	CustomerEmail  string                 `json:"customer_email" validate:"required,email"`	Provider       string                 `json:"provider" validate:"required,oneof=stripe paypal adyen manual"`	PaymentMethod  string                 `json:"payment_method" validate:"required,oneof=card bank_transfer e_wallet crypto"`	Currency       string                 `json:"currency" validate:"required,len=3"`	Amount         float64                `json:"amount" validate:"required,gt=0"`	IdempotencyKey string                 `json:"idempotency_key" validate:"required,min=1,max=255"`type CreateTransactionRequest struct {// CreateTransactionRequest represents the HTTP request for creating a transaction)	"github.com/google/uuid"	"time"import (
\ No newline at end of file
+import (
+	"time"
+)
+
+// CreateTransactionRequest represents the HTTP request for creating a transaction
+type CreateTransactionRequest struct {
+	IdempotencyKey       string                 `json:"idempotency_key" validate:"required,min=1,max=255"`
+	Amount               string                 `json:"amount" validate:"required"` // Decimal string in major units, e.g. "100.00"
+	Currency             string                 `json:"currency" validate:"required,len=3"`
+	PaymentMethod        string                 `json:"payment_method" validate:"required,oneof=card bank_transfer e_wallet crypto"`
+	Provider             string                 `json:"provider" validate:"required,oneof=stripe paypal adyen manual"`
+	PaymentMethodDetails *PaymentMethodDetails  `json:"payment_method_details,omitempty" validate:"omitempty"` // Optional card, bank account or wallet used
+	CustomerEmail        string                 `json:"customer_email" validate:"required,email"`
+	CustomerName         string                 `json:"customer_name" validate:"omitempty,min=1,max=255"`
+	CustomerPhone        string                 `json:"customer_phone" validate:"omitempty,e164"`
+	BillingCountry       string                 `json:"billing_country" validate:"omitempty,iso3166_1_alpha2"`
+	Description          string                 `json:"description" validate:"omitempty,max=500"`
+	Metadata             map[string]interface{} `json:"metadata" validate:"omitempty"`
+}
+
+// PaymentMethodDetails describes how the payer paid; exactly one field is set,
+// matching payment_method (none for crypto)
+type PaymentMethodDetails struct {
+	Card        *CardDetails        `json:"card,omitempty"`
+	BankAccount *BankAccountDetails `json:"bank_account,omitempty"`
+	Wallet      *WalletDetails      `json:"wallet,omitempty"`
+}
+
+// CardDetails represents the non-sensitive details of a card; never send the full card number.
+// Issuer country and funding are derived from the BIN and only appear in responses.
+type CardDetails struct {
+	Brand         string `json:"brand,omitempty" validate:"required_without=BIN"`
+	Last4         string `json:"last4" validate:"required,len=4,numeric"`
+	ExpMonth      int    `json:"exp_month" validate:"required,min=1,max=12"`
+	ExpYear       int    `json:"exp_year" validate:"required"`
+	Fingerprint   string `json:"fingerprint,omitempty" validate:"omitempty,max=64"`
+	BIN           string `json:"bin,omitempty" validate:"omitempty,min=6,max=8,numeric"`
+	IssuerCountry string `json:"issuer_country,omitempty"`
+	Funding       string `json:"funding,omitempty"`
+}
+
+// BankAccountDetails represents the bank account a transfer came from
+type BankAccountDetails struct {
+	BankName   string `json:"bank_name,omitempty" validate:"omitempty,max=255"`
+	BankCode   string `json:"bank_code,omitempty" validate:"omitempty,max=34"`
+	Last4      string `json:"last4" validate:"required,len=4,numeric"`
+	HolderType string `json:"holder_type,omitempty" validate:"omitempty,oneof=individual company"`
+}
+
+// WalletDetails represents the e-wallet a payment was made with
+type WalletDetails struct {
+	Type      string `json:"type" validate:"required"`
+	AccountID string `json:"account_id,omitempty" validate:"omitempty,max=128"`
+}
+
+// CreateTransactionResponse represents the HTTP response
+type CreateTransactionResponse struct {
+	TransactionID string    `json:"transaction_id"`
+	Status        string    `json:"status"`
+	Amount        string    `json:"amount"`
+	Currency      string    `json:"currency"`
+	Livemode      bool      `json:"livemode"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// GetTransactionResponse represents a transaction details response
+type GetTransactionResponse struct {
+	ID                    string                 `json:"id"`
+	PartnerID             string                 `json:"partner_id"`
+	IdempotencyKey        string                 `json:"idempotency_key"`
+	Amount                string                 `json:"amount"`
+	Currency              string                 `json:"currency"`
+	PaymentMethod         string                 `json:"payment_method"`
+	PaymentMethodDetails  *PaymentMethodDetails  `json:"payment_method_details,omitempty"`
+	Provider              string                 `json:"provider"`
+	ProviderTransactionID string                 `json:"provider_transaction_id,omitempty"`
+	Status                string                 `json:"status"`
+	Livemode              bool                   `json:"livemode"`
+	CustomerEmail         string                 `json:"customer_email"`
+	CustomerName          string                 `json:"customer_name,omitempty"`
+	CustomerPhone         string                 `json:"customer_phone,omitempty"`
+	BillingCountry        string                 `json:"billing_country,omitempty"`
+	Description           string                 `json:"description,omitempty"`
+	Metadata              map[string]interface{} `json:"metadata,omitempty"`
+	ErrorCode             string                 `json:"error_code,omitempty"`
+	ErrorMessage          string                 `json:"error_message,omitempty"`
+	CreatedAt             time.Time              `json:"created_at"`
+	UpdatedAt             time.Time              `json:"updated_at"`
+	ProcessedAt           *time.Time             `json:"processed_at,omitempty"`
+	// Refunds and Customer are only set when asked for with expand
+	Refunds  *TransactionRefundsResponse `json:"refunds,omitempty"`
+	Customer *CustomerResponse           `json:"customer,omitempty"`
+	Links    map[string]Link             `json:"links,omitempty"`
+}
+
+// ExpandRequest is the expand query parameter of transaction responses: a
+// comma-separated list of the related resources to embed, refunds and
+// customer
+type ExpandRequest struct {
+	Expand string `query:"expand"`
+}
+
+// Link is a related resource, or an action on a resource, and the method
+// to request it with
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method"`
+}
+
+// CustomerResponse is the customer who paid a transaction
+type CustomerResponse struct {
+	Email          string `json:"email"`
+	Name           string `json:"name,omitempty"`
+	Phone          string `json:"phone,omitempty"`
+	BillingCountry string `json:"billing_country,omitempty"`
+}
+
+// TransactionRefundsResponse is the refunds of a transaction, newest first.
+// HasMore is set when the transaction has more refunds than embedded.
+type TransactionRefundsResponse struct {
+	Data    []RefundTransactionResponse `json:"data"`
+	HasMore bool                        `json:"has_more"`
+}
+
+// ListTransactionsRequest represents query parameters for listing transactions.
+// Metadata filters are given as metadata[key]=value and read separately.
+type ListTransactionsRequest struct {
+	Status        string `query:"status"` // One status or a comma-separated list
+	Currency      string `query:"currency" validate:"omitempty,len=3"`
+	AmountMin     string `query:"amount_min"` // Decimal string in major units; needs currency
+	AmountMax     string `query:"amount_max"` // Decimal string in major units; needs currency
+	DateFrom      string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo        string `query:"date_to" validate:"omitempty,datetime=2006-01-02"` // Inclusive
+	Sort          string `query:"sort" validate:"omitempty,oneof=created_at -created_at amount -amount"`
+	StartingAfter string `query:"starting_after" validate:"omitempty,uuid"`
+	Limit         int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset        int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+// ListTransactionsResponse represents paginated transaction list
+type ListTransactionsResponse struct {
+	Transactions []GetTransactionResponse `json:"transactions"`
+	Total        int64                    `json:"total"`
+	Limit        int                      `json:"limit"`
+	Offset       int                      `json:"offset"`
+	// NextCursor is the starting_after value for the next page, when the
+	// page is full
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Links      map[string]Link `json:"links"`
+}
+
+// RefundTransactionRequest represents refund request
+type RefundTransactionRequest struct {
+	Amount     string `json:"amount" validate:"required"` // Decimal string in major units, e.g. "25.50"
+	Currency   string `json:"currency" validate:"required,len=3"`
+	Reason     string `json:"reason" validate:"required,oneof=requested_by_customer duplicate fraudulent other"`
+	ReasonNote string `json:"reason_note" validate:"required_if=Reason other,max=255"`
+}
+
+// RefundTransactionResponse represents refund response
+type RefundTransactionResponse struct {
+	RefundID      string     `json:"refund_id"`
+	TransactionID string     `json:"transaction_id"`
+	Amount        string     `json:"amount"`
+	Currency      string     `json:"currency"`
+	Status        string     `json:"status"`
+	Reason        string     `json:"reason"`
+	ReasonNote    string     `json:"reason_note,omitempty"`
+	ApprovedBy    string     `json:"approved_by,omitempty"`
+	ApprovedAt    *time.Time `json:"approved_at,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// RefundReasonSummaryRequest represents query parameters for the refund reason report
+type RefundReasonSummaryRequest struct {
+	DateFrom string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo   string `query:"date_to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// RefundReasonSummaryItem represents refund totals for one reason and currency
+type RefundReasonSummaryItem struct {
+	Reason      string `json:"reason"`
+	Currency    string `json:"currency"`
+	Count       int64  `json:"count"`
+	TotalAmount string `json:"total_amount"`
+}
+
+// RefundReasonSummaryResponse represents the refund reason report
+type RefundReasonSummaryResponse struct {
+	Reasons []RefundReasonSummaryItem `json:"reasons"`
+}
+
+// BulkRefundRequest represents a bulk full-refund request
+type BulkRefundRequest struct {
+	TransactionIDs []string `json:"transaction_ids" validate:"required,min=1,max=1000,dive,uuid"`
+	Reason         string   `json:"reason" validate:"required,oneof=requested_by_customer duplicate fraudulent other"`
+	ReasonNote     string   `json:"reason_note" validate:"required_if=Reason other,max=255"`
+}
+
+// BulkRefundItemResponse represents the outcome for one transaction in a bulk refund
+type BulkRefundItemResponse struct {
+	TransactionID string `json:"transaction_id"`
+	RefundID      string `json:"refund_id,omitempty"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+}
+
+// BulkRefundJobResponse represents bulk refund job progress and report
+type BulkRefundJobResponse struct {
+	JobID       string                   `json:"job_id"`
+	Status      string                   `json:"status"`
+	Total       int                      `json:"total"`
+	Processed   int                      `json:"processed"`
+	Succeeded   int                      `json:"succeeded"`
+	Failed      int                      `json:"failed"`
+	Results     []BulkRefundItemResponse `json:"results,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+	CompletedAt *time.Time               `json:"completed_at,omitempty"`
+}
+
+// ProcessPaymentResponse acknowledges that processing a payment has started,
+// or that it was queued for a worker
+type ProcessPaymentResponse struct {
+	Message string `json:"message"`
+	JobID   string `json:"job_id,omitempty"`
+}
+
+// VerificationCodeResponse acknowledges that a verification code was texted to the customer
+type VerificationCodeResponse struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// VerifyCodeRequest represents the code a customer entered
+type VerifyCodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// VerifyCodeResponse confirms that the customer entered the code texted to them
+type VerifyCodeResponse struct {
+	TransactionID string `json:"transaction_id"`
+	Verified      bool   `json:"verified"`
+}
+
+// ProviderEventsResponse acknowledges a payment provider's notification
+type ProviderEventsResponse struct {
+	Handled    int `json:"handled"`
+	Duplicates int `json:"duplicates"` // Events already handled from an earlier delivery
+}
+
+// DeletedResponse confirms that an admin soft-deleted a transaction or refund
+type DeletedResponse struct {
+	ID        string     `json:"id"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// ErrorResponse represents error response. Code is stable for clients to
+// switch on; Message is for people and may change.
+type ErrorResponse struct {
+	// Type is the class of the error: invalid_request_error,
+	// authentication_error, permission_error, conflict_error,
+	// rate_limit_error or api_error
+	Type string `json:"type"`
+	Code string `json:"code"`
+	// Error repeats Code for clients written before codes were added
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	// Param is the request field the error is about, if any
+	Param   string                 `json:"param,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	// RequestID identifies the request in logs and audit entries
+	RequestID string `json:"request_id,omitempty"`
+	// DocURL documents the code
+	DocURL string `json:"doc_url"`
+}
+
+// HealthCheckResponse represents health check response
+type HealthCheckResponse struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   string    `json:"version"`
+}
+
+// ReadinessResponse represents the readiness check response: status is
+// ready, degraded or not_ready
+type ReadinessResponse struct {
+	Status     string                     `json:"status"`
+	Timestamp  time.Time                  `json:"timestamp"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// ComponentStatus represents the health of one dependency: up, degraded or down
+type ComponentStatus struct {
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+)
+
+// TransactionSocketRequest is a message clients send on the transaction
+// WebSocket
+type TransactionSocketRequest struct {
+	// Type is subscribe or unsubscribe
+	Type          string `json:"type"`
+	TransactionID string `json:"transaction_id"`
+}
+
+// TransactionStatusMessage is the status of a subscribed transaction, sent
+// on subscribing and whenever the transaction changes
+type TransactionStatusMessage struct {
+	// Type is always transaction.status
+	Type          string     `json:"type"`
+	TransactionID string     `json:"transaction_id"`
+	Status        string     `json:"status"`
+	ErrorCode     string     `json:"error_code,omitempty"`
+	ErrorMessage  string     `json:"error_message,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+}
+
+// TransactionSocketError answers a message the server could not act on; the
+// connection stays open
+type TransactionSocketError struct {
+	// Type is always error
+	Type          string `json:"type"`
+	Error         string `json:"error"`
+	Message       string `json:"message"`
+	TransactionID string `json:"transaction_id,omitempty"`
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"time"
+)
+
+// Money is an amount in API v2: an integer count of the currency's minor
+// units, such as cents, so clients never parse decimals
+type Money struct {
+	Value    int64  `json:"value" validate:"min=0"`
+	Currency string `json:"currency" validate:"required,len=3"`
+}
+
+// CreateTransactionV2Request represents the v2 request for creating a
+// transaction. The transaction waits for confirmation unless Confirm is set.
+type CreateTransactionV2Request struct {
+	IdempotencyKey       string                `json:"idempotency_key" validate:"required,min=1,max=255"`
+	Amount               Money                 `json:"amount" validate:"required"`
+	PaymentMethod        string                `json:"payment_method" validate:"required,oneof=card bank_transfer e_wallet crypto"`
+	Provider             string                `json:"provider" validate:"required,oneof=stripe paypal adyen manual"`
+	PaymentMethodDetails *PaymentMethodDetails `json:"payment_method_details,omitempty" validate:"omitempty"`
+	CustomerEmail        string                `json:"customer_email" validate:"required,email"`
+	CustomerName         string                `json:"customer_name" validate:"omitempty,min=1,max=255"`
+	CustomerPhone        string                `json:"customer_phone" validate:"omitempty,e164"`
+	BillingCountry       string                `json:"billing_country" validate:"omitempty,iso3166_1_alpha2"`
+	Description          string                `json:"description" validate:"omitempty,max=500"`
+	Metadata             map[string]string     `json:"metadata" validate:"omitempty,max=50,dive,keys,min=1,max=40,endkeys,max=500"`
+	Confirm              bool                  `json:"confirm"`
+}
+
+// TransactionErrorV2 is why a v2 transaction failed
+type TransactionErrorV2 struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// TransactionV2Response represents a transaction in API v2, with the status
+// of its payment intent: requires_confirmation, processing, succeeded,
+// failed or canceled. Refunds leave a succeeded payment succeeded and show
+// in amount_refunded.
+type TransactionV2Response struct {
+	ID                    string                `json:"id"`
+	Object                string                `json:"object"`
+	IdempotencyKey        string                `json:"idempotency_key"`
+	Amount                Money                 `json:"amount"`
+	AmountRefunded        Money                 `json:"amount_refunded"`
+	Status                string                `json:"status"`
+	PaymentMethod         string                `json:"payment_method"`
+	PaymentMethodDetails  *PaymentMethodDetails `json:"payment_method_details,omitempty"`
+	Provider              string                `json:"provider"`
+	ProviderTransactionID string                `json:"provider_transaction_id,omitempty"`
+	Livemode              bool                  `json:"livemode"`
+	CustomerEmail         string                `json:"customer_email"`
+	CustomerName          string                `json:"customer_name,omitempty"`
+	CustomerPhone         string                `json:"customer_phone,omitempty"`
+	BillingCountry        string                `json:"billing_country,omitempty"`
+	Description           string                `json:"description,omitempty"`
+	Metadata              map[string]string     `json:"metadata"`
+	LastError             *TransactionErrorV2   `json:"last_error"`
+	CreatedAt             time.Time             `json:"created_at"`
+	UpdatedAt             time.Time             `json:"updated_at"`
+	ProcessedAt           *time.Time            `json:"processed_at"`
+	// Refunds and Customer are only set when asked for with expand
+	Refunds  *TransactionRefundsV2Response `json:"refunds,omitempty"`
+	Customer *CustomerResponse             `json:"customer,omitempty"`
+	Links    map[string]Link               `json:"links"`
+}
+
+// RefundV2Response represents a refund in API v2
+type RefundV2Response struct {
+	ID            string     `json:"id"`
+	Object        string     `json:"object"`
+	TransactionID string     `json:"transaction_id"`
+	Amount        Money      `json:"amount"`
+	Status        string     `json:"status"`
+	Reason        string     `json:"reason"`
+	ReasonNote    string     `json:"reason_note,omitempty"`
+	ApprovedAt    *time.Time `json:"approved_at"`
+	CancelledAt   *time.Time `json:"cancelled_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TransactionRefundsV2Response is the refunds of a v2 transaction, newest
+// first. HasMore is set when the transaction has more refunds than
+// embedded.
+type TransactionRefundsV2Response struct {
+	Data    []RefundV2Response `json:"data"`
+	HasMore bool               `json:"has_more"`
+}
+
+// ListTransactionsV2Request represents query parameters for listing v2
+// transactions. Pages follow a cursor; metadata filters are given as
+// metadata[key]=value as in v1.
+type ListTransactionsV2Request struct {
+	Status        string `query:"status" validate:"omitempty,oneof=requires_confirmation processing succeeded failed canceled"`
+	Currency      string `query:"currency" validate:"omitempty,len=3"`
+	DateFrom      string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo        string `query:"date_to" validate:"omitempty,datetime=2006-01-02"` // Inclusive
+	StartingAfter string `query:"starting_after" validate:"omitempty,uuid"`
+	Limit         int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// ListTransactionsV2Response represents a page of v2 transactions
+type ListTransactionsV2Response struct {
+	Data []TransactionV2Response `json:"data"`
+	// HasMore reports whether a next page may exist; NextCursor is its
+	// starting_after value
+	HasMore    bool            `json:"has_more"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Links      map[string]Link `json:"links"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+// UsageRequest is the range of days usage is read for, both inclusive, as
+// YYYY-MM-DD in UTC. Without them the last 30 days are read.
+type UsageRequest struct {
+	DateFrom string `query:"date_from"`
+	DateTo   string `query:"date_to"`
+}
+
+// PaymentVolumeResponse is the live payments of one currency
+type PaymentVolumeResponse struct {
+	Currency       string `json:"currency"`
+	Payments       int64  `json:"payments"`
+	FailedPayments int64  `json:"failed_payments"`
+	// Volume is the decimal amount of the successful payments
+	Volume string `json:"volume"`
+}
+
+// UsageResponse is API and payment usage over some time
+type UsageResponse struct {
+	APICalls     int64 `json:"api_calls"`
+	ClientErrors int64 `json:"client_errors"`
+	ServerErrors int64 `json:"server_errors"`
+	// ErrorRate is the share of calls answered with an error; null without
+	// calls
+	ErrorRate *float64                `json:"error_rate"`
+	Payments  []PaymentVolumeResponse `json:"payments"`
+}
+
+// DailyUsageResponse is a partner's usage on one day
+type DailyUsageResponse struct {
+	Date string `json:"date"`
+	UsageResponse
+}
+
+// ListUsageResponse is a partner's usage per day
+type ListUsageResponse struct {
+	DateFrom string               `json:"date_from"`
+	DateTo   string               `json:"date_to"`
+	Days     []DailyUsageResponse `json:"days"`
+}
+
+// PartnerUsageResponse is one partner's usage over the range
+type PartnerUsageResponse struct {
+	PartnerID string `json:"partner_id"`
+	UsageResponse
+}
+
+// UsageRollupResponse is every partner's usage over the range, busiest
+// partners first
+type UsageRollupResponse struct {
+	DateFrom string                 `json:"date_from"`
+	DateTo   string                 `json:"date_to"`
+	Partners []PartnerUsageResponse `json:"partners"`
+}
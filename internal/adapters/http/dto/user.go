@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+)
+
+// CreateUserRequest represents a request to add a team member
+type CreateUserRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Name     string `json:"name" validate:"required,min=1,max=255"`
+	Role     string `json:"role" validate:"required,oneof=owner developer finance read_only"`
+	Password string `json:"password" validate:"required,min=12"`
+}
+
+// UpdateUserRoleRequest represents a request to change a team member's role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner developer finance read_only"`
+}
+
+// UserResponse represents a team member (the password hash is never returned)
+type UserResponse struct {
+	ID          string     `json:"id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	Role        string     `json:"role"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// ListUsersResponse represents a partner's team
+type ListUsersResponse struct {
+	Users []UserResponse `json:"users"`
+}
+
+// LoginRequest represents a team member's sign-in
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// LoginResponse carries the session token, shown only once
+type LoginResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	User      UserResponse `json:"user"`
+}
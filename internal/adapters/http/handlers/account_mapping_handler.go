@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/partner"
+)
+
+// AccountMappingHandler handles requests for the accounts a partner's
+// QuickBooks and Xero exports post to
+type AccountMappingHandler struct {
+	getUseCase    *partner.GetPartnerUseCase
+	updateUseCase *partner.UpdatePartnerAccountMappingUseCase
+}
+
+// NewAccountMappingHandler creates a new account mapping handler
+func NewAccountMappingHandler(
+	getUseCase *partner.GetPartnerUseCase,
+	updateUseCase *partner.UpdatePartnerAccountMappingUseCase,
+) *AccountMappingHandler {
+	return &AccountMappingHandler{
+		getUseCase:    getUseCase,
+		updateUseCase: updateUseCase,
+	}
+}
+
+// GetAccountMapping handles GET /api/v1/accounting/accounts
+func (h *AccountMappingHandler) GetAccountMapping(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return c.JSON(mapAccountMappingToDTO(p))
+}
+
+// UpdateAccountMapping handles PUT /api/v1/accounting/accounts, replacing
+// the whole mapping
+func (h *AccountMappingHandler) UpdateAccountMapping(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdateAccountMappingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	mapping := valueobjects.AccountMapping(req)
+	p, err := h.updateUseCase.Execute(c.Context(), partner.UpdatePartnerAccountMappingInput{
+		PartnerID: partnerID,
+		Mapping:   &mapping,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return c.JSON(mapAccountMappingToDTO(p))
+}
+
+// ResetAccountMapping handles DELETE /api/v1/accounting/accounts, returning
+// the partner to the default accounts
+func (h *AccountMappingHandler) ResetAccountMapping(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateUseCase.Execute(c.Context(), partner.UpdatePartnerAccountMappingInput{
+		PartnerID: partnerID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "partner_update_failed")
+	}
+
+	return c.JSON(mapAccountMappingToDTO(p))
+}
+
+// mapAccountMappingToDTO maps the accounts a partner's accounting exports
+// post to to their response DTO
+func mapAccountMappingToDTO(p *entities.Partner) dto.AccountMappingResponse {
+	accounts := p.Accounts()
+	return dto.AccountMappingResponse{
+		Default:         p.AccountMapping == nil,
+		ClearingAccount: accounts.ClearingAccount,
+		SalesAccount:    accounts.SalesAccount,
+		RefundsAccount:  accounts.RefundsAccount,
+		FeesAccount:     accounts.FeesAccount,
+		DateFormat:      accounts.DateFormat,
+	}
+}
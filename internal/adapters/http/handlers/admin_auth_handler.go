@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/admin"
+)
+
+// AdminAuthHandler handles back-office admin sign-in and sign-out
+type AdminAuthHandler struct {
+	loginUseCase  *admin.LoginUseCase
+	logoutUseCase *admin.LogoutUseCase
+}
+
+// NewAdminAuthHandler creates a new admin auth handler
+func NewAdminAuthHandler(loginUseCase *admin.LoginUseCase, logoutUseCase *admin.LogoutUseCase) *AdminAuthHandler {
+	return &AdminAuthHandler{
+		loginUseCase:  loginUseCase,
+		logoutUseCase: logoutUseCase,
+	}
+}
+
+// Login handles POST /api/v1/admin/auth/login
+func (h *AdminAuthHandler) Login(c *fiber.Ctx) error {
+	// Parse request body
+	var req dto.AdminLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	output, err := h.loginUseCase.Execute(c.Context(), admin.LoginInput{
+		Email:     req.Email,
+		Password:  req.Password,
+		Code:      req.Code,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusUnauthorized, "invalid_credentials")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.AdminLoginResponse{
+		Token:     output.Token,
+		ExpiresAt: output.Session.ExpiresAt,
+		Admin: dto.AdminResponse{
+			ID:          output.Admin.ID,
+			Email:       output.Admin.Email,
+			Name:        output.Admin.Name,
+			LastLoginAt: output.Admin.LastLoginAt,
+		},
+	})
+}
+
+// Logout handles POST /api/v1/admin/auth/logout
+func (h *AdminAuthHandler) Logout(c *fiber.Ctx) error {
+	session, ok := middleware.GetAdminSession(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "logout requires an admin session",
+		})
+	}
+
+	// Execute use case
+	if err := h.logoutUseCase.Execute(c.Context(), session); err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "logout_failed")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "logged out",
+	})
+}
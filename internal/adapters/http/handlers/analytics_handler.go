@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/analytics"
+)
+
+// AnalyticsHandler serves a partner's payment analytics, read from the
+// daily rollups
+type AnalyticsHandler struct {
+	getAnalyticsUC *analytics.GetAnalyticsUseCase
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(getAnalyticsUC *analytics.GetAnalyticsUseCase) *AnalyticsHandler {
+	return &AnalyticsHandler{getAnalyticsUC: getAnalyticsUC}
+}
+
+// GetVolume handles GET /api/v1/analytics/volume: succeeded payments over
+// time, by day, week or month
+func (h *AnalyticsHandler) GetVolume(c *fiber.Ctx) error {
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	var req dto.VolumeRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query, err := analyticsQuery(c, req.AnalyticsRequest)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query.PartnerID = partnerID
+	interval, err := analytics.NewInterval(req.Interval)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	series, err := h.getAnalyticsUC.Volume(c.Context(), query, interval)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_analytics")
+	}
+
+	response := dto.VolumeResponse{
+		DateFrom: query.From.Format("2006-01-02"),
+		DateTo:   query.To.Format("2006-01-02"),
+		Interval: string(interval),
+		Series:   make([]dto.VolumePointResponse, len(series)),
+	}
+	for i, point := range series {
+		response.Series[i] = dto.VolumePointResponse{
+			Start:    point.Start.Format("2006-01-02"),
+			Currency: point.Currency,
+			Payments: point.Payments,
+			Volume:   valueobjects.FormatMinorUnits(point.Volume, valueobjects.Currency(point.Currency)),
+		}
+	}
+	return c.JSON(response)
+}
+
+// GetSuccessRates handles GET /api/v1/analytics/success-rates: the share of
+// payments that succeeded by provider or payment method
+func (h *AnalyticsHandler) GetSuccessRates(c *fiber.Ctx) error {
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	var req dto.SuccessRatesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query, err := analyticsQuery(c, req.AnalyticsRequest)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query.PartnerID = partnerID
+	dimension, err := analytics.NewDimension(req.GroupBy)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	rates, err := h.getAnalyticsUC.SuccessRates(c.Context(), query, dimension)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_analytics")
+	}
+
+	response := dto.SuccessRatesResponse{
+		DateFrom: query.From.Format("2006-01-02"),
+		DateTo:   query.To.Format("2006-01-02"),
+		GroupBy:  string(dimension),
+		Rates:    make([]dto.SuccessRateResponse, len(rates)),
+	}
+	for i, rate := range rates {
+		response.Rates[i] = dto.SuccessRateResponse{
+			Key:         rate.Key,
+			Payments:    rate.Succeeded + rate.Failed,
+			Succeeded:   rate.Succeeded,
+			Failed:      rate.Failed,
+			SuccessRate: rate.Rate(),
+		}
+	}
+	return c.JSON(response)
+}
+
+// GetAverageAmounts handles GET /api/v1/analytics/average-amounts: the
+// average succeeded payment by currency
+func (h *AnalyticsHandler) GetAverageAmounts(c *fiber.Ctx) error {
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	var req dto.AnalyticsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query, err := analyticsQuery(c, req)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query.PartnerID = partnerID
+
+	totals, err := h.getAnalyticsUC.Totals(c.Context(), query)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_analytics")
+	}
+
+	response := dto.AverageAmountsResponse{
+		DateFrom:   query.From.Format("2006-01-02"),
+		DateTo:     query.To.Format("2006-01-02"),
+		Currencies: make([]dto.AverageAmountResponse, 0, len(totals)),
+	}
+	for _, total := range totals {
+		if total.Payments == 0 {
+			continue
+		}
+		currency := valueobjects.Currency(total.Currency)
+		response.Currencies = append(response.Currencies, dto.AverageAmountResponse{
+			Currency:      total.Currency,
+			Payments:      total.Payments,
+			Volume:        valueobjects.FormatMinorUnits(total.Volume, currency),
+			AverageAmount: valueobjects.FormatMinorUnits(*total.AverageAmount(), currency),
+		})
+	}
+	return c.JSON(response)
+}
+
+// GetRefundRates handles GET /api/v1/analytics/refund-rates: the share of
+// the volume refunded by currency
+func (h *AnalyticsHandler) GetRefundRates(c *fiber.Ctx) error {
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	var req dto.AnalyticsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query, err := analyticsQuery(c, req)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query.PartnerID = partnerID
+
+	totals, err := h.getAnalyticsUC.Totals(c.Context(), query)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_analytics")
+	}
+
+	response := dto.RefundRatesResponse{
+		DateFrom:   query.From.Format("2006-01-02"),
+		DateTo:     query.To.Format("2006-01-02"),
+		Currencies: make([]dto.RefundRateResponse, len(totals)),
+	}
+	for i, total := range totals {
+		currency := valueobjects.Currency(total.Currency)
+		response.Currencies[i] = dto.RefundRateResponse{
+			Currency:       total.Currency,
+			Payments:       total.Payments,
+			Volume:         valueobjects.FormatMinorUnits(total.Volume, currency),
+			Refunds:        total.Refunds,
+			RefundedVolume: valueobjects.FormatMinorUnits(total.RefundedVolume, currency),
+			RefundRate:     total.RefundRate(),
+		}
+	}
+	return c.JSON(response)
+}
+
+// analyticsQuery reads the range and currency of req, in the mode of the
+// request's key
+func analyticsQuery(c *fiber.Ctx, req dto.AnalyticsRequest) (analytics.Query, error) {
+	from, to, err := parseDateRange(req.DateFrom, req.DateTo)
+	if err != nil {
+		return analytics.Query{}, err
+	}
+	query := analytics.Query{
+		Livemode: middleware.GetLivemode(c),
+		From:     from,
+		To:       to,
+	}
+	if req.Currency != "" {
+		currency, err := valueobjects.NewCurrency(req.Currency)
+		if err != nil {
+			return analytics.Query{}, err
+		}
+		query.Currency = string(currency)
+	}
+	return query, nil
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/apikey"
+)
+
+// APIKeyHandler handles API key management requests
+type APIKeyHandler struct {
+	createUseCase *apikey.CreateAPIKeyUseCase
+	listUseCase   *apikey.ListAPIKeysUseCase
+	revokeUseCase *apikey.RevokeAPIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(
+	createUseCase *apikey.CreateAPIKeyUseCase,
+	listUseCase *apikey.ListAPIKeysUseCase,
+	revokeUseCase *apikey.RevokeAPIKeyUseCase,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		createUseCase: createUseCase,
+		listUseCase:   listUseCase,
+		revokeUseCase: revokeUseCase,
+	}
+}
+
+// CreateAPIKey handles POST /api/v1/api-keys
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	output, err := h.createUseCase.Execute(c.Context(), apikey.CreateAPIKeyInput{
+		PartnerID:  partnerID,
+		Label:      req.Label,
+		Scopes:     req.Scopes,
+		AuthMethod: req.AuthMethod,
+		Livemode:   req.Livemode == nil || *req.Livemode,
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "api_key_creation_failed")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CreateAPIKeyResponse{
+		APIKeyResponse: mapAPIKeyToDTO(output.Key),
+		Key:            output.Plaintext,
+	})
+}
+
+// ListAPIKeys handles GET /api/v1/api-keys
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Optionally narrow to keys unused for the given number of days
+	unusedDays := c.QueryInt("unused_days", 0)
+	if unusedDays < 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "unused_days must not be negative",
+		})
+	}
+
+	// Execute use case
+	keys, err := h.listUseCase.Execute(c.Context(), partnerID, time.Duration(unusedDays)*24*time.Hour)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_api_keys")
+	}
+
+	response := dto.ListAPIKeysResponse{
+		APIKeys: make([]dto.APIKeyResponse, len(keys)),
+	}
+	for i, key := range keys {
+		response.APIKeys[i] = mapAPIKeyToDTO(key)
+	}
+	return c.JSON(response)
+}
+
+// RevokeAPIKey handles DELETE /api/v1/api-keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse key ID
+	keyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_api_key_id",
+			Message: "invalid API key ID format",
+		})
+	}
+
+	// Execute use case
+	key, err := h.revokeUseCase.Execute(c.Context(), apikey.RevokeAPIKeyInput{
+		KeyID:     keyID,
+		PartnerID: partnerID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrAPIKeyNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "api_key_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusConflict, "api_key_revocation_failed")
+	}
+
+	return c.JSON(mapAPIKeyToDTO(key))
+}
+
+// mapAPIKeyToDTO maps an API key entity to its response DTO
+func mapAPIKeyToDTO(key *entities.APIKey) dto.APIKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = scope.String()
+	}
+
+	return dto.APIKeyResponse{
+		ID:         key.ID.String(),
+		Label:      key.Label,
+		Prefix:     key.KeyPrefix,
+		Scopes:     scopes,
+		AuthMethod: string(key.AuthMethod),
+		Livemode:   key.Livemode,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		CreatedAt:  key.CreatedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
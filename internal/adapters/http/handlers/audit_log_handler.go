@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/audit"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// AuditLogHandler handles audit log HTTP requests
+type AuditLogHandler struct {
+	listUseCase      *audit.ListAuditLogsUseCase
+	listAllUseCase   *audit.ListAllAuditLogsUseCase
+	verifyUseCase    *audit.VerifyAuditChainUseCase
+	verifyAllUseCase *audit.VerifyAllAuditChainsUseCase
+}
+
+// NewAuditLogHandler creates a new audit log handler
+func NewAuditLogHandler(
+	listUseCase *audit.ListAuditLogsUseCase,
+	listAllUseCase *audit.ListAllAuditLogsUseCase,
+	verifyUseCase *audit.VerifyAuditChainUseCase,
+	verifyAllUseCase *audit.VerifyAllAuditChainsUseCase,
+) *AuditLogHandler {
+	return &AuditLogHandler{
+		listUseCase:      listUseCase,
+		listAllUseCase:   listAllUseCase,
+		verifyUseCase:    verifyUseCase,
+		verifyAllUseCase: verifyAllUseCase,
+	}
+}
+
+// ListAuditLogs handles GET /api/v1/audit-logs
+func (h *AuditLogHandler) ListAuditLogs(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	req, query, err := parseAuditLogQuery(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Execute use case
+	entries, total, err := h.listUseCase.Execute(c.Context(), partnerID, query)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_audit_logs")
+	}
+
+	return c.JSON(mapAuditLogsToDTO(entries, total, req))
+}
+
+// ListAllAuditLogs handles GET /api/v1/admin/audit-logs
+func (h *AuditLogHandler) ListAllAuditLogs(c *fiber.Ctx) error {
+	req, query, err := parseAuditLogQuery(c)
+	if err == nil && req.PartnerID != "" {
+		var partnerID uuid.UUID
+		if partnerID, err = uuid.Parse(req.PartnerID); err != nil {
+			err = errors.NewValidationError("partner_id", "must be a partner ID")
+		}
+		query.PartnerID = &partnerID
+	}
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Execute use case
+	entries, total, err := h.listAllUseCase.Execute(c.Context(), query)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_audit_logs")
+	}
+
+	return c.JSON(mapAuditLogsToDTO(entries, total, req))
+}
+
+// VerifyAuditLogs handles GET /api/v1/audit-logs/verify
+func (h *AuditLogHandler) VerifyAuditLogs(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	report, err := h.verifyUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_verify_audit_logs")
+	}
+
+	return c.JSON(mapAuditChainToDTO(report))
+}
+
+// VerifyAllAuditLogs handles GET /api/v1/admin/audit-logs/verify, checking
+// every chain or, with partner_id, one partner's
+func (h *AuditLogHandler) VerifyAllAuditLogs(c *fiber.Ctx) error {
+	var reports []*audit.ChainReport
+	var err error
+	if c.Query("partner_id") != "" {
+		partnerID, parseErr := uuid.Parse(c.Query("partner_id"))
+		if parseErr != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_query_parameters",
+				Message: errors.NewValidationError("partner_id", "must be a partner ID").Error(),
+			})
+		}
+		var report *audit.ChainReport
+		if report, err = h.verifyUseCase.Execute(c.Context(), partnerID); err == nil {
+			reports = append(reports, report)
+		}
+	} else {
+		reports, err = h.verifyAllUseCase.Execute(c.Context())
+	}
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_verify_audit_logs")
+	}
+
+	response := dto.VerifyAuditChainsResponse{
+		Intact: true,
+		Chains: make([]dto.AuditChainResponse, len(reports)),
+	}
+	for i, report := range reports {
+		response.Chains[i] = mapAuditChainToDTO(report)
+		response.Intact = response.Intact && report.Intact()
+	}
+	return c.JSON(response)
+}
+
+// parseAuditLogQuery reads the list query parameters shared by the partner
+// and back-office listings; partner_id is left to the caller
+func parseAuditLogQuery(c *fiber.Ctx) (dto.ListAuditLogsRequest, ports.AuditLogQuery, error) {
+	var req dto.ListAuditLogsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return req, ports.AuditLogQuery{}, err
+	}
+
+	// Set defaults
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	query := ports.AuditLogQuery{
+		Action:       req.Action,
+		ResourceType: req.ResourceType,
+		Limit:        req.Limit,
+		Offset:       req.Offset,
+	}
+
+	if req.ResourceID != "" {
+		resourceID, err := uuid.Parse(req.ResourceID)
+		if err != nil {
+			return req, query, errors.NewValidationError("resource_id", "must be a UUID")
+		}
+		query.ResourceID = &resourceID
+	}
+
+	if req.DateFrom != "" {
+		from, err := time.Parse("2006-01-02", req.DateFrom)
+		if err != nil {
+			return req, query, errors.NewValidationError("date_from", "must be YYYY-MM-DD")
+		}
+		query.CreatedFrom = &from
+	}
+	if req.DateTo != "" {
+		to, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return req, query, errors.NewValidationError("date_to", "must be YYYY-MM-DD")
+		}
+		// date_to includes the whole day
+		to = to.AddDate(0, 0, 1)
+		query.CreatedTo = &to
+	}
+
+	return req, query, nil
+}
+
+// mapAuditLogsToDTO maps a page of audit entries to the list response
+func mapAuditLogsToDTO(entries []*ports.AuditLogEntry, total int64, req dto.ListAuditLogsRequest) dto.ListAuditLogsResponse {
+	logs := make([]dto.AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		logs[i] = dto.AuditLogResponse{
+			ID:           entry.ID,
+			PartnerID:    optionalUUID(entry.PartnerID),
+			Action:       entry.Action,
+			ResourceType: entry.ResourceType,
+			ResourceID:   optionalUUID(entry.ResourceID),
+			IPAddress:    entry.IPAddress,
+			UserAgent:    entry.UserAgent,
+			RequestID:    optionalUUID(entry.RequestID),
+			Changes:      entry.Changes,
+			CreatedAt:    entry.CreatedAt,
+			PrevHash:     entry.PrevHash,
+			Hash:         entry.Hash,
+		}
+	}
+
+	return dto.ListAuditLogsResponse{
+		AuditLogs: logs,
+		Total:     total,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+}
+
+// mapAuditChainToDTO maps a chain verification report
+func mapAuditChainToDTO(report *audit.ChainReport) dto.AuditChainResponse {
+	response := dto.AuditChainResponse{
+		PartnerID: optionalUUID(report.PartnerID),
+		Intact:    report.Intact(),
+		Verified:  report.Verified,
+		Unchained: report.Unchained,
+		HeadHash:  report.HeadHash,
+	}
+	if report.Break != nil {
+		response.BrokenEntryID = report.Break.EntryID
+		response.Reason = report.Break.Reason
+	}
+	return response
+}
+
+// optionalUUID returns id as a string, or "" for uuid.Nil
+func optionalUUID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
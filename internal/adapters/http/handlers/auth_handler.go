@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/user"
+)
+
+// AuthHandler handles team member sign-in and sign-out
+type AuthHandler struct {
+	loginUseCase  *user.LoginUseCase
+	logoutUseCase *user.LogoutUseCase
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(loginUseCase *user.LoginUseCase, logoutUseCase *user.LogoutUseCase) *AuthHandler {
+	return &AuthHandler{
+		loginUseCase:  loginUseCase,
+		logoutUseCase: logoutUseCase,
+	}
+}
+
+// Login handles POST /api/v1/auth/login
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	// Parse request body
+	var req dto.LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	output, err := h.loginUseCase.Execute(c.Context(), user.LoginInput{
+		Email:     req.Email,
+		Password:  req.Password,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusUnauthorized, "invalid_credentials")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.LoginResponse{
+		Token:     output.Token,
+		ExpiresAt: output.Session.ExpiresAt,
+		User:      mapUserToDTO(output.User),
+	})
+}
+
+// Logout handles POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	// Only session tokens can be signed out; API keys are revoked instead
+	session, ok := middleware.GetSession(c)
+	if !ok {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "not_a_session",
+			Message: "logout requires a session token",
+		})
+	}
+
+	// Execute use case
+	if err := h.logoutUseCase.Execute(c.Context(), session); err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "logout_failed")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "logged out",
+	})
+}
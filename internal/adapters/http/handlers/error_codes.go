@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	stderrors "errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/domain/errors"
+)
+
+// domainErrorCode is the status and stable code a domain error is answered
+// with
+type domainErrorCode struct {
+	err    error
+	status int
+	code   string
+}
+
+// domainErrorCodes map the domain errors to their answer. Codes are part of
+// the API: never change one, add a new error instead, and document it in
+// docs/ERRORS.md.
+var domainErrorCodes = []domainErrorCode{
+	{errors.ErrInvalidAmount, fiber.StatusBadRequest, "invalid_amount"},
+	{errors.ErrInvalidCurrency, fiber.StatusBadRequest, "invalid_currency"},
+	{errors.ErrInvalidCountry, fiber.StatusBadRequest, "invalid_country"},
+	{errors.ErrInvalidLocale, fiber.StatusBadRequest, "invalid_locale"},
+	{errors.ErrInvalidPaymentMethod, fiber.StatusBadRequest, "invalid_payment_method"},
+	{errors.ErrCardBINNotFound, fiber.StatusNotFound, "card_bin_not_found"},
+	{errors.ErrInvalidStatus, fiber.StatusUnprocessableEntity, "invalid_status"},
+	{errors.ErrTransactionNotFound, fiber.StatusNotFound, "transaction_not_found"},
+	{errors.ErrDuplicateTransaction, fiber.StatusConflict, "duplicate_transaction"},
+	{errors.ErrRetryNotAllowed, fiber.StatusUnprocessableEntity, "retry_not_allowed"},
+	{errors.ErrReceiptNotAvailable, fiber.StatusConflict, "receipt_not_available"},
+
+	{errors.ErrPartnerNotFound, fiber.StatusNotFound, "partner_not_found"},
+	{errors.ErrPartnerExists, fiber.StatusConflict, "partner_email_taken"},
+	{errors.ErrPartnerInactive, fiber.StatusForbidden, "partner_inactive"},
+	{errors.ErrFeatureDisabled, fiber.StatusForbidden, "feature_disabled"},
+	{errors.ErrCurrencyNotAllowed, fiber.StatusUnprocessableEntity, "currency_not_allowed"},
+	{errors.ErrInvalidAPIKey, fiber.StatusUnauthorized, "invalid_api_key"},
+	{errors.ErrAPIKeyNotFound, fiber.StatusNotFound, "api_key_not_found"},
+	{errors.ErrAPIKeyRevoked, fiber.StatusUnauthorized, "api_key_revoked"},
+	{errors.ErrAPIKeyIDTaken, fiber.StatusConflict, "api_key_id_taken"},
+	{errors.ErrMissingScope, fiber.StatusForbidden, "insufficient_scope"},
+	{errors.ErrAuthMethod, fiber.StatusUnauthorized, "auth_method_not_allowed"},
+	{errors.ErrInvalidSignature, fiber.StatusUnauthorized, "invalid_signature"},
+	{errors.ErrSignatureExpired, fiber.StatusUnauthorized, "signature_expired"},
+	{errors.ErrSignatureReplayed, fiber.StatusUnauthorized, "signature_replayed"},
+	{errors.ErrTooManyFailures, fiber.StatusTooManyRequests, "too_many_failed_attempts"},
+
+	{errors.ErrInvalidPhoneNumber, fiber.StatusUnprocessableEntity, "invalid_phone_number"},
+	{errors.ErrInvalidVerificationCode, fiber.StatusUnprocessableEntity, "invalid_verification_code"},
+	{errors.ErrSMSRateLimited, fiber.StatusTooManyRequests, "sms_rate_limited"},
+	{errors.ErrSMSUnavailable, fiber.StatusServiceUnavailable, "sms_unavailable"},
+
+	{errors.ErrProviderCredentialNotFound, fiber.StatusNotFound, "provider_credential_not_found"},
+	{errors.ErrProviderCredentialChanged, fiber.StatusConflict, "provider_credential_changed"},
+	{errors.ErrExchangeRateUnavailable, fiber.StatusUnprocessableEntity, "exchange_rate_unavailable"},
+	{errors.ErrProviderNotConfigured, fiber.StatusNotFound, "provider_not_configured"},
+
+	{errors.ErrUserNotFound, fiber.StatusNotFound, "user_not_found"},
+	{errors.ErrUserAlreadyExists, fiber.StatusConflict, "user_already_exists"},
+	{errors.ErrInvalidCredentials, fiber.StatusUnauthorized, "invalid_credentials"},
+	{errors.ErrSessionNotFound, fiber.StatusUnauthorized, "session_not_found"},
+	{errors.ErrSessionExpired, fiber.StatusUnauthorized, "session_expired"},
+	{errors.ErrLastOwner, fiber.StatusConflict, "last_owner"},
+	{errors.ErrAdminNotFound, fiber.StatusNotFound, "admin_not_found"},
+	{errors.ErrAdminAlreadyExists, fiber.StatusConflict, "admin_already_exists"},
+	{errors.ErrInvalidAdminCredentials, fiber.StatusUnauthorized, "invalid_credentials"},
+
+	{errors.ErrRefundNotFound, fiber.StatusNotFound, "refund_not_found"},
+	{errors.ErrRefundAmountExceeded, fiber.StatusUnprocessableEntity, "refund_amount_exceeded"},
+	{errors.ErrRefundNotAllowed, fiber.StatusUnprocessableEntity, "refund_not_allowed"},
+	{errors.ErrRefundWindowExpired, fiber.StatusUnprocessableEntity, "refund_window_expired"},
+	{errors.ErrBulkRefundJobNotFound, fiber.StatusNotFound, "job_not_found"},
+	{errors.ErrExportNotFound, fiber.StatusNotFound, "export_not_found"},
+	{errors.ErrExportNotReady, fiber.StatusConflict, "export_not_ready"},
+	{errors.ErrExportLinkExpired, fiber.StatusForbidden, "export_link_expired"},
+	{errors.ErrStatementNotFound, fiber.StatusNotFound, "statement_not_found"},
+	{errors.ErrReportScheduleNotFound, fiber.StatusNotFound, "report_schedule_not_found"},
+
+	{errors.ErrAmountBelowMinimum, fiber.StatusUnprocessableEntity, "amount_below_minimum"},
+	{errors.ErrAmountAboveMaximum, fiber.StatusUnprocessableEntity, "amount_limit_exceeded"},
+	{errors.ErrUnauthorizedOperation, fiber.StatusForbidden, "unauthorized_operation"},
+
+	{errors.ErrResourceLocked, fiber.StatusConflict, "resource_locked"},
+	{errors.ErrPreconditionFailed, fiber.StatusPreconditionFailed, "precondition_failed"},
+}
+
+// ClassifiedError is the status, code and message a domain error is
+// answered with
+type ClassifiedError struct {
+	Status  int
+	Code    string
+	Message string
+	Param   string
+}
+
+// ClassifyError returns the answer to err when it is a domain error; APIs
+// besides this one, such as the gRPC server, report the same codes
+func ClassifyError(err error) (ClassifiedError, bool) {
+	if conflict := asVersionConflict(err); conflict != nil {
+		response := versionConflictResponse(conflict)
+		return ClassifiedError{Status: fiber.StatusConflict, Code: response.Error, Message: response.Message}, true
+	}
+	var limitErr *errors.AmountLimitError
+	if stderrors.As(err, &limitErr) {
+		return ClassifiedError{Status: fiber.StatusUnprocessableEntity, Code: "amount_limit_exceeded", Message: limitErr.Error()}, true
+	}
+	var domainErr *errors.DomainError
+	if stderrors.As(err, &domainErr) {
+		switch domainErr.Code {
+		case errors.CodeValidation:
+			return ClassifiedError{
+				Status:  fiber.StatusBadRequest,
+				Code:    "validation_error",
+				Message: domainErr.Message,
+				Param:   domainErr.Param,
+			}, true
+		case errors.CodeBusinessRule:
+			return ClassifiedError{Status: fiber.StatusUnprocessableEntity, Code: "business_rule_violation", Message: domainErr.Message}, true
+		}
+	}
+	for _, known := range domainErrorCodes {
+		if stderrors.Is(err, known.err) {
+			return ClassifiedError{Status: known.status, Code: known.code, Message: err.Error()}, true
+		}
+	}
+	return ClassifiedError{}, false
+}
+
+// respondDomainError answers err with the code of the domain error it is,
+// or, for any other error, with status and code
+func respondDomainError(c *fiber.Ctx, err error, status int, code string) error {
+	if conflict := asVersionConflict(err); conflict != nil {
+		return respondError(c, fiber.StatusConflict, versionConflictResponse(conflict))
+	}
+	var limitErr *errors.AmountLimitError
+	if stderrors.As(err, &limitErr) {
+		return respondError(c, fiber.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "amount_limit_exceeded",
+			Message: limitErr.Error(),
+			Details: map[string]interface{}{
+				"currency": limitErr.Currency,
+				"limit":    limitErr.Limit,
+				"scope":    limitErr.Scope,
+			},
+		})
+	}
+	if classified, ok := ClassifyError(err); ok {
+		return respondError(c, classified.Status, dto.ErrorResponse{
+			Error:   classified.Code,
+			Message: classified.Message,
+			Param:   classified.Param,
+		})
+	}
+	return respondError(c, status, dto.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+	})
+}
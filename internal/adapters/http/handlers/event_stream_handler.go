@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/ports"
+)
+
+const (
+	// streamBatchSize bounds the events read from the outbox at a time
+	streamBatchSize = 100
+	// streamHeartbeat is how often an idle stream sends a comment, so
+	// proxies do not close it
+	streamHeartbeat = 15 * time.Second
+	// streamRetry is how long clients wait before reconnecting
+	streamRetry = 5 * time.Second
+)
+
+// EventStreamHandler streams a partner's transaction and refund events as
+// server-sent events
+type EventStreamHandler struct {
+	streamUseCase *outbox.StreamEventsUseCase
+	pollInterval  time.Duration
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+// NewEventStreamHandler creates a new event stream handler reading new
+// events every pollInterval
+func NewEventStreamHandler(streamUseCase *outbox.StreamEventsUseCase, pollInterval time.Duration) *EventStreamHandler {
+	return &EventStreamHandler{
+		streamUseCase: streamUseCase,
+		pollInterval:  pollInterval,
+		done:          make(chan struct{}),
+	}
+}
+
+// Close ends the open streams, so a stopping server is not held up by them.
+// Their clients reconnect to another instance and resume where they were.
+func (h *EventStreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Stream handles GET /api/v1/events/stream. A reconnecting client resumes
+// after the Last-Event-ID header, or the last_event_id query parameter of
+// clients that cannot set headers; otherwise the stream starts with the
+// events recorded from now on.
+func (h *EventStreamHandler) Stream(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Resume after the last event the client received
+	cursor := h.streamUseCase.Start()
+	lastEventID := c.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		cursor, err = parseEventCursor(lastEventID)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_last_event_id",
+				Message: "Last-Event-ID is not the ID of a streamed event",
+			})
+		}
+	}
+	livemode := middleware.GetLivemode(c)
+	// Streamed events hide what the partner's webhooks hide
+	var hidden []string
+	if partner, ok := middleware.GetPartner(c); ok {
+		hidden = partner.FieldFilter.Webhooks
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	// Proxies such as nginx must pass events on as they come
+	c.Set("X-Accel-Buffering", "no")
+
+	// The stream runs after the handler returns and must not touch c; it
+	// stops once a write fails because the client went away
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+		if w.Flush() != nil {
+			return
+		}
+
+		poll := time.NewTicker(h.pollInterval)
+		defer poll.Stop()
+		idleSince := time.Now()
+		for {
+			events, next, more, err := h.streamUseCase.Execute(context.Background(), partnerID, livemode, cursor, streamBatchSize)
+			if err != nil {
+				// The client reconnects and resumes after its last event
+				return
+			}
+			for _, event := range events {
+				payload, err := valueobjects.HideFields(event.Payload, hidden)
+				if err != nil {
+					return
+				}
+				data, err := json.Marshal(dto.EventResponse{
+					ID:        event.ID.String(),
+					Type:      event.EventType,
+					CreatedAt: event.CreatedAt.UTC(),
+					Data:      payload,
+				})
+				if err != nil {
+					return
+				}
+				id := formatEventCursor(ports.OutboxCursor{CreatedAt: event.CreatedAt, ID: event.ID})
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event.EventType, data)
+			}
+			cursor = next
+			if len(events) > 0 {
+				if w.Flush() != nil {
+					return
+				}
+				idleSince = time.Now()
+			} else if time.Since(idleSince) >= streamHeartbeat {
+				w.WriteString(": heartbeat\n\n")
+				if w.Flush() != nil {
+					return
+				}
+				idleSince = time.Now()
+			}
+			if more {
+				continue
+			}
+
+			select {
+			case <-h.done:
+				return
+			case <-poll.C:
+			}
+		}
+	})
+	return nil
+}
+
+// formatEventCursor returns the ID a streamed event is sent with: its
+// creation time in nanoseconds and its ID, which is where a reconnecting
+// client resumes
+func formatEventCursor(cursor ports.OutboxCursor) string {
+	return strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + "_" + cursor.ID.String()
+}
+
+// parseEventCursor parses the ID of a streamed event
+func parseEventCursor(id string) (ports.OutboxCursor, error) {
+	nanos, eventID, ok := strings.Cut(id, "_")
+	if !ok {
+		return ports.OutboxCursor{}, fmt.Errorf("invalid event ID %q", id)
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return ports.OutboxCursor{}, fmt.Errorf("invalid event ID %q: %w", id, err)
+	}
+	parsedID, err := uuid.Parse(eventID)
+	if err != nil {
+		return ports.OutboxCursor{}, fmt.Errorf("invalid event ID %q: %w", id, err)
+	}
+	return ports.OutboxCursor{CreatedAt: time.Unix(0, unixNano).UTC(), ID: parsedID}, nil
+}
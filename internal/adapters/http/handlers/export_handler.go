@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/codec"
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/export"
+)
+
+// exportableRefundStatuses are the statuses the refund status filter accepts
+var exportableRefundStatuses = map[entities.RefundStatus]bool{
+	entities.RefundStatusPending:          true,
+	entities.RefundStatusRequiresApproval: true,
+	entities.RefundStatusProcessing:       true,
+	entities.RefundStatusCompleted:        true,
+	entities.RefundStatusFailed:           true,
+	entities.RefundStatusCancelled:        true,
+}
+
+// ExportHandler handles export HTTP requests
+type ExportHandler struct {
+	createExportUseCase   *export.CreateExportUseCase
+	getExportUseCase      *export.GetExportUseCase
+	downloadExportUseCase *export.DownloadExportUseCase
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(
+	createExportUseCase *export.CreateExportUseCase,
+	getExportUseCase *export.GetExportUseCase,
+	downloadExportUseCase *export.DownloadExportUseCase,
+) *ExportHandler {
+	return &ExportHandler{
+		createExportUseCase:   createExportUseCase,
+		getExportUseCase:      getExportUseCase,
+		downloadExportUseCase: downloadExportUseCase,
+	}
+}
+
+// CreateExport handles POST /api/v1/exports, queuing an export of the
+// transactions or refunds matching the filters. It answers 202 with the
+// export, to be polled until it completed.
+func (h *ExportHandler) CreateExport(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.CreateExportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+	if req.Format == "" {
+		req.Format = exportFormatCSV
+	}
+
+	resource := entities.ExportResource(req.Resource)
+	filters, err := exportFilters(resource, req.Filters)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_request")
+	}
+	layout := entities.ExportLayout{Columns: req.Columns, Timezone: req.Timezone}
+	if _, err := newExportLayout(exportColumns(resource), layout); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_request")
+	}
+
+	// Execute use case
+	job, err := h.createExportUseCase.Execute(c.Context(), export.CreateExportInput{
+		PartnerID: partnerID,
+		Livemode:  middleware.GetLivemode(c),
+		Resource:  resource,
+		Format:    entities.ExportFormat(req.Format),
+		Filters:   filters,
+		Layout:    layout,
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_create_export")
+	}
+
+	return codec.Respond(c, fiber.StatusAccepted, mapExportToDTO(c, &export.ExportStatus{Job: job}))
+}
+
+// GetExport handles GET /api/v1/exports/:id. A completed export comes with
+// a freshly signed download URL.
+func (h *ExportHandler) GetExport(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse export ID
+	exportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_export_id",
+			Message: "invalid export ID format",
+		})
+	}
+
+	// Execute use case
+	status, err := h.getExportUseCase.Execute(c.Context(), partnerID, middleware.GetLivemode(c), exportID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_export")
+	}
+
+	return codec.Respond(c, fiber.StatusOK, mapExportToDTO(c, status))
+}
+
+// DownloadExport handles GET /api/v1/exports/:id/download, the signed URL
+// of a completed export. The signature authenticates the request.
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	// Parse export ID
+	exportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_export_id",
+			Message: "invalid export ID format",
+		})
+	}
+
+	var req dto.DownloadExportRequest
+	if err := c.QueryParser(&req); err != nil || req.Signature == "" {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "invalid_signature",
+			Message: "expires and signature are required",
+		})
+	}
+
+	// Execute use case
+	job, data, err := h.downloadExportUseCase.Execute(c.Context(), exportID, req.Expires, req.Signature)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_download_export")
+	}
+
+	c.Set(fiber.HeaderContentType, fileContentType(job.Format))
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+string(job.Resource)+"-"+job.ID.String()+"."+job.Format.Extension()+`"`)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Send(data)
+}
+
+// exportFilters validates the filters of an export of resource. Filters
+// of the other resource are refused rather than ignored.
+func exportFilters(resource entities.ExportResource, req dto.ExportFiltersRequest) (entities.ExportFilters, error) {
+	var filters entities.ExportFilters
+
+	// Dates, amounts and statuses are parsed as the transaction list does
+	list := dto.ListTransactionsRequest{DateFrom: req.DateFrom, DateTo: req.DateTo}
+	if resource != entities.ExportResourceRefunds {
+		list.Status, list.Currency, list.AmountMin, list.AmountMax = req.Status, req.Currency, req.AmountMin, req.AmountMax
+	}
+	query, err := transactionQuery(list)
+	if err != nil {
+		return filters, err
+	}
+	filters.CreatedFrom, filters.CreatedTo = query.CreatedFrom, query.CreatedTo
+
+	if resource == entities.ExportResourceRefunds {
+		switch {
+		case req.Currency != "" || req.AmountMin != "" || req.AmountMax != "":
+			return filters, errors.NewValidationError("currency", "refund exports are not filtered by currency or amount")
+		case len(req.Metadata) > 0:
+			return filters, errors.NewValidationError("metadata", "refund exports are not filtered by metadata")
+		}
+		if req.Status != "" {
+			if !exportableRefundStatuses[entities.RefundStatus(req.Status)] {
+				return filters, errors.NewValidationError("status", "unknown refund status "+req.Status)
+			}
+			filters.Statuses = []string{req.Status}
+		}
+		if req.TransactionID != "" {
+			transactionID, err := uuid.Parse(req.TransactionID)
+			if err != nil {
+				return filters, errors.NewValidationError("transaction_id", "must be a transaction ID")
+			}
+			filters.TransactionID = &transactionID
+		}
+		return filters, nil
+	}
+
+	if req.TransactionID != "" {
+		return filters, errors.NewValidationError("transaction_id", "only refund exports are filtered by transaction")
+	}
+	for _, status := range query.Statuses {
+		filters.Statuses = append(filters.Statuses, string(status))
+	}
+	if query.Currency != nil {
+		filters.Currency = query.Currency.String()
+	}
+	filters.MinAmount, filters.MaxAmount = query.MinAmount, query.MaxAmount
+	filters.Metadata = req.Metadata
+	return filters, nil
+}
+
+// mapExportToDTO maps an export to its response. Downloads are signed when
+// the export itself is fetched, so their URL is below the request's path,
+// in the API version used.
+func mapExportToDTO(c *fiber.Ctx, status *export.ExportStatus) dto.ExportResponse {
+	job := status.Job
+	response := dto.ExportResponse{
+		ID:          job.ID.String(),
+		Resource:    string(job.Resource),
+		Format:      string(job.Format),
+		Columns:     job.Layout.Columns,
+		Timezone:    job.Layout.Timezone,
+		Status:      string(job.Status),
+		RowCount:    job.RowCount,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+	// Why attempts failed stays with the job: it may be a database error
+	if job.Status == entities.ExportJobStatusFailed {
+		response.Error = "the export could not be produced; request it again"
+	}
+	if status.DownloadExpiresAt != nil {
+		response.DownloadURL = c.BaseURL() + c.Path() + "/download?expires=" +
+			strconv.FormatInt(status.DownloadExpiresAt.Unix(), 10) + "&signature=" + status.DownloadSignature
+		response.DownloadExpiresAt = status.DownloadExpiresAt
+	}
+	return response
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/partner"
+)
+
+// FieldFilterHandler handles requests for the fields hidden from a
+// partner's API responses and webhooks
+type FieldFilterHandler struct {
+	getUseCase    *partner.GetPartnerUseCase
+	updateUseCase *partner.UpdatePartnerFieldFilterUseCase
+}
+
+// NewFieldFilterHandler creates a new field filter handler
+func NewFieldFilterHandler(
+	getUseCase *partner.GetPartnerUseCase,
+	updateUseCase *partner.UpdatePartnerFieldFilterUseCase,
+) *FieldFilterHandler {
+	return &FieldFilterHandler{
+		getUseCase:    getUseCase,
+		updateUseCase: updateUseCase,
+	}
+}
+
+// GetFieldFilter handles GET /api/v1/field-filter
+func (h *FieldFilterHandler) GetFieldFilter(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return c.JSON(mapFieldFilterToDTO(p))
+}
+
+// UpdateFieldFilter handles PUT /api/v1/field-filter, replacing the whole
+// filter; empty lists hide nothing
+func (h *FieldFilterHandler) UpdateFieldFilter(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdateFieldFilterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateUseCase.Execute(c.Context(), partner.UpdatePartnerFieldFilterInput{
+		PartnerID: partnerID,
+		Responses: req.Responses,
+		Webhooks:  req.Webhooks,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return c.JSON(mapFieldFilterToDTO(p))
+}
+
+// mapFieldFilterToDTO maps a partner's field filter to its response DTO,
+// with empty lists rather than nulls
+func mapFieldFilterToDTO(p *entities.Partner) dto.FieldFilterResponse {
+	response := dto.FieldFilterResponse{
+		Responses: make(map[string][]string, len(p.FieldFilter.Responses)),
+		Webhooks:  append([]string{}, p.FieldFilter.Webhooks...),
+	}
+	for scope, paths := range p.FieldFilter.Responses {
+		response.Responses[scope.String()] = paths
+	}
+	return response
+}
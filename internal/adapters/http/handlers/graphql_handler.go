@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"Pay2Go/internal/adapters/http/codec"
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// GraphQLHandler answers read-only GraphQL queries about a partner's
+// transactions, refunds and webhook events, so dashboards can fetch what
+// they show in one round trip
+type GraphQLHandler struct {
+	schema *graphql.Schema
+	// introspection answers queries about the schema only, whose type
+	// references nest deeper than dashboard queries may
+	introspection *graphql.Schema
+	limits        *graphqlLimits
+}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(
+	getTxnUseCase *transaction.GetTransactionUseCase,
+	listTxnUseCase *transaction.ListTransactionsUseCase,
+	getRefundUseCase *transaction.GetRefundUseCase,
+	listRefundsUseCase *transaction.ListRefundsUseCase,
+	listEventsUseCase *outbox.ListEventsUseCase,
+) *GraphQLHandler {
+	useCases := &graphqlUseCases{
+		getTxn:      getTxnUseCase,
+		listTxn:     listTxnUseCase,
+		getRefund:   getRefundUseCase,
+		listRefunds: listRefundsUseCase,
+		listEvents:  listEventsUseCase,
+	}
+	return &GraphQLHandler{
+		schema:        newGraphQLSchema(useCases, graphql.MaxDepth(graphqlMaxDepth)),
+		introspection: newGraphQLSchema(useCases),
+		limits:        newGraphQLLimits(graphqlSchema),
+	}
+}
+
+// Query handles POST /api/v1/graphql
+func (h *GraphQLHandler) Query(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.GraphQLRequest
+	if err := codec.Decode(c, &req); err != nil || req.Query == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "body must be JSON with a query",
+		})
+	}
+
+	// Queries too large to run are refused before the engine expands them
+	schema := h.schema
+	introspection, err := h.limits.check(req.Query, req.Variables)
+	if err != nil {
+		return codec.Respond(c, fiber.StatusBadRequest, &graphql.Response{
+			Errors: []*gqlerrors.QueryError{{
+				Message:    err.Error(),
+				Extensions: map[string]interface{}{"code": "graphql_validation_failed"},
+			}},
+		})
+	}
+	if introspection {
+		schema = h.introspection
+	}
+
+	// Execute the query as the caller
+	ctx := context.WithValue(c.Context(), graphqlCallerKey{}, graphqlCaller{
+		partnerID: partnerID,
+		livemode:  middleware.GetLivemode(c),
+	})
+	response := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	// Errors carry the same codes as the REST API
+	for _, gqlErr := range response.Errors {
+		code := "graphql_validation_failed"
+		if gqlErr.ResolverError != nil {
+			code = "internal_error"
+			if classified, ok := ClassifyError(gqlErr.ResolverError); ok {
+				code = classified.Code
+				gqlErr.Message = classified.Message
+			}
+		}
+		gqlErr.Extensions = map[string]interface{}{"code": code}
+	}
+
+	// A query that did not validate has no data
+	status := fiber.StatusOK
+	if response.Data == nil {
+		status = fiber.StatusBadRequest
+	}
+	return codec.Respond(c, status, response)
+}
+
+// Schema handles GET /api/v1/graphql/schema, publishing the schema in SDL
+func (h *GraphQLHandler) Schema(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.SendString(graphqlSchema)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// graphqlMaxNodes and graphqlMaxCost bound the size of a query once its
+// fragments are expanded; a full page of transactions, with four fields of
+// up to 100 refunds each, costs about 40000
+const (
+	graphqlMaxNodes = 500
+	graphqlMaxCost  = 50000
+)
+
+// graphqlLimits bounds the size of queries before they run. The engine
+// bounds their depth, but expands fragments wherever they are spread, so a
+// few fragments spreading each other twice select millions of fields.
+type graphqlLimits struct {
+	schema *ast.Schema
+}
+
+// newGraphQLLimits creates limits for queries of the schema in sdl
+func newGraphQLLimits(sdl string) *graphqlLimits {
+	return &graphqlLimits{schema: gqlparser.MustLoadSchema(&ast.Source{Name: "schema", Input: sdl})}
+}
+
+// graphqlSize is the size of a selection set: the fields it selects and
+// those it may resolve
+type graphqlSize struct {
+	nodes, cost int
+}
+
+// check refuses a query selecting more than graphqlMaxNodes fields, or
+// resolving more than graphqlMaxCost, each field taking a first argument
+// counting its selections that many times over. introspection reports
+// whether the query only asks about the schema.
+func (l *graphqlLimits) check(query string, variables map[string]interface{}) (introspection bool, err error) {
+	doc, err := parser.ParseQuery(&ast.Source{Name: "query", Input: query})
+	if err != nil {
+		return false, err
+	}
+
+	w := &graphqlSizeWalker{
+		schema:    l.schema,
+		doc:       doc,
+		variables: variables,
+		fragments: make(map[string]graphqlSize),
+		expanding: make(map[string]bool),
+	}
+	introspection = len(doc.Operations) > 0
+	for _, op := range doc.Operations {
+		for _, selection := range op.SelectionSet {
+			if field, ok := selection.(*ast.Field); !ok || !strings.HasPrefix(field.Name, "__") {
+				introspection = false
+			}
+		}
+		size := w.selectionSet(op.SelectionSet, l.schema.Query)
+		if size.nodes > graphqlMaxNodes {
+			return false, fmt.Errorf("the query selects more than the maximum of %d fields", graphqlMaxNodes)
+		}
+		if size.cost > graphqlMaxCost {
+			return false, fmt.Errorf("the query may resolve more than the maximum of %d fields; ask for fewer with first", graphqlMaxCost)
+		}
+	}
+	return introspection, nil
+}
+
+// graphqlSizeWalker sizes the selection sets of a query, each fragment once
+type graphqlSizeWalker struct {
+	schema    *ast.Schema
+	doc       *ast.QueryDocument
+	variables map[string]interface{}
+	fragments map[string]graphqlSize
+	expanding map[string]bool
+}
+
+// selectionSet sizes the selections of a value of parent. Fields the
+// schema does not have are left to the engine to refuse.
+func (w *graphqlSizeWalker) selectionSet(selections ast.SelectionSet, parent *ast.Definition) graphqlSize {
+	var size graphqlSize
+	for _, selection := range selections {
+		var selected graphqlSize
+		switch selection := selection.(type) {
+		case *ast.Field:
+			selected = w.field(selection, parent)
+		case *ast.InlineFragment:
+			on := parent
+			if selection.TypeCondition != "" {
+				on = w.schema.Types[selection.TypeCondition]
+			}
+			selected = w.selectionSet(selection.SelectionSet, on)
+		case *ast.FragmentSpread:
+			selected = w.fragment(selection.Name)
+		}
+		size = size.add(selected)
+	}
+	return size
+}
+
+// add sums two sizes, stopping just past the maximums so that fragments
+// doubling at every spread cannot overflow them
+func (s graphqlSize) add(other graphqlSize) graphqlSize {
+	return graphqlSize{
+		nodes: min(s.nodes+other.nodes, graphqlMaxNodes+1),
+		cost:  min(s.cost+other.cost, graphqlMaxCost+1),
+	}
+}
+
+// field sizes a field and what it selects
+func (w *graphqlSizeWalker) field(field *ast.Field, parent *ast.Definition) graphqlSize {
+	if parent == nil {
+		return graphqlSize{nodes: 1, cost: 1}
+	}
+	definition := parent.Fields.ForName(field.Name)
+	if definition == nil {
+		return graphqlSize{nodes: 1, cost: 1}
+	}
+	selected := w.selectionSet(field.SelectionSet, w.schema.Types[definition.Type.Name()])
+
+	// Lists cost what their items do, as many times as first asks for
+	times := 1
+	if argument := definition.Arguments.ForName("first"); argument != nil {
+		value := argument.DefaultValue
+		if given := field.Arguments.ForName("first"); given != nil {
+			value = given.Value
+		}
+		times = min(w.intValue(value), graphqlMaxCost+1)
+	}
+	return graphqlSize{nodes: 1, cost: 1}.add(graphqlSize{nodes: selected.nodes, cost: times * selected.cost})
+}
+
+// fragment sizes a named fragment; a fragment spreading itself is left to
+// the engine to refuse
+func (w *graphqlSizeWalker) fragment(name string) graphqlSize {
+	if size, ok := w.fragments[name]; ok {
+		return size
+	}
+	definition := w.doc.Fragments.ForName(name)
+	if definition == nil || w.expanding[name] {
+		return graphqlSize{}
+	}
+	w.expanding[name] = true
+	size := w.selectionSet(definition.SelectionSet, w.schema.Types[definition.TypeCondition])
+	w.expanding[name] = false
+	w.fragments[name] = size
+	return size
+}
+
+// intValue reads an Int argument, given literally or as a variable, from 1
+// to just past the maximum cost; the engine refuses values of other types
+func (w *graphqlSizeWalker) intValue(value *ast.Value) int {
+	if value == nil {
+		return 1
+	}
+	v, err := value.Value(w.variables)
+	if err != nil {
+		return 1
+	}
+	var n float64
+	switch v := v.(type) {
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	default:
+		return 1
+	}
+	return int(min(max(n, 1), graphqlMaxCost+1))
+}
@@ -0,0 +1,560 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// graphqlMaxDepth bounds nesting; transaction { refunds { transaction {
+// events } } } is as deep as a dashboard needs to go
+const graphqlMaxDepth = 6
+
+// graphqlSchema is the dashboard schema. Resolvers are matched to its
+// fields by name.
+const graphqlSchema = `schema {
+  query: Query
+}
+
+type Query {
+  transaction(id: ID!): Transaction
+  "Transactions, newest first"
+  transactions(
+    status: [String!]
+    currency: String
+    provider: String
+    "Inclusive"
+    createdFrom: DateTime
+    "Exclusive"
+    createdTo: DateTime
+    "Metadata keys and the string values they must have"
+    metadata: JSON
+    first: Int = 20
+    "The endCursor of the previous page"
+    after: ID
+  ): TransactionPage!
+  refund(id: ID!): Refund
+  "Refunds, newest first"
+  refunds(
+    status: String
+    transactionId: ID
+    "Inclusive"
+    createdFrom: DateTime
+    "Exclusive"
+    createdTo: DateTime
+    first: Int = 20
+    offset: Int = 0
+  ): [Refund!]!
+}
+
+"A payment"
+type Transaction {
+  id: ID!
+  idempotencyKey: String!
+  amount: String!
+  currency: String!
+  refundedAmount: String!
+  paymentMethod: String!
+  provider: String!
+  providerTransactionId: String
+  status: String!
+  livemode: Boolean!
+  customerEmail: String!
+  customerName: String
+  customerPhone: String
+  billingCountry: String
+  description: String
+  metadata: JSON
+  errorCode: String
+  errorMessage: String
+  createdAt: DateTime!
+  updatedAt: DateTime!
+  processedAt: DateTime
+  "Webhook events, oldest first"
+  events(first: Int = 20): [WebhookEvent!]!
+  "Refunds of the transaction, newest first"
+  refunds(status: String, first: Int = 100): [Refund!]!
+}
+
+"A refund of a transaction"
+type Refund {
+  id: ID!
+  transactionId: ID!
+  amount: String!
+  currency: String!
+  status: String!
+  reason: String!
+  reasonNote: String
+  providerRefundId: String
+  errorCode: String
+  errorMessage: String
+  approvedBy: String
+  approvedAt: DateTime
+  createdAt: DateTime!
+  updatedAt: DateTime!
+  processedAt: DateTime
+  "The refunded transaction"
+  transaction: Transaction!
+  "Webhook events, oldest first"
+  events(first: Int = 20): [WebhookEvent!]!
+}
+
+"A page of transactions"
+type TransactionPage {
+  nodes: [Transaction!]!
+  "Transactions matching the filters, on all pages"
+  totalCount: Int!
+  "The after argument for the next page, when the page is full"
+  endCursor: ID
+}
+
+"A webhook event recorded about a transaction or refund"
+type WebhookEvent {
+  id: ID!
+  type: String!
+  payload: JSON!
+  attempts: Int!
+  delivered: Boolean!
+  deliveredAt: DateTime
+  lastError: String
+  createdAt: DateTime!
+}
+
+"An RFC 3339 timestamp, such as 2024-05-01T12:00:00Z"
+scalar DateTime
+
+"Any JSON value"
+scalar JSON
+`
+
+// graphqlCaller is the partner and mode a query runs for
+type graphqlCaller struct {
+	partnerID uuid.UUID
+	livemode  bool
+}
+
+type graphqlCallerKey struct{}
+
+// callerOf returns the caller the handler put in ctx
+func callerOf(ctx context.Context) graphqlCaller {
+	caller, _ := ctx.Value(graphqlCallerKey{}).(graphqlCaller)
+	return caller
+}
+
+// graphqlUseCases are the read use cases the resolvers call, the same as
+// the REST API's, so a query sees exactly what the partner's API key does
+type graphqlUseCases struct {
+	getTxn      *transaction.GetTransactionUseCase
+	listTxn     *transaction.ListTransactionsUseCase
+	getRefund   *transaction.GetRefundUseCase
+	listRefunds *transaction.ListRefundsUseCase
+	listEvents  *outbox.ListEventsUseCase
+}
+
+// newGraphQLSchema parses the dashboard schema and binds its resolvers
+func newGraphQLSchema(useCases *graphqlUseCases, opts ...graphql.SchemaOpt) *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchema, &queryResolver{useCases}, opts...)
+}
+
+// queryResolver resolves the root fields
+type queryResolver struct {
+	useCases *graphqlUseCases
+}
+
+func (q *queryResolver) Transaction(ctx context.Context, args struct{ ID graphql.ID }) (*transactionResolver, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, errors.NewValidationError("id", "must be a transaction ID")
+	}
+	caller := callerOf(ctx)
+	txn, err := q.useCases.getTxn.Execute(ctx, id, caller.partnerID, caller.livemode)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionResolver{txn, q.useCases}, nil
+}
+
+// transactionsArgs are the arguments of the transactions field
+type transactionsArgs struct {
+	Status      *[]string
+	Currency    *string
+	Provider    *string
+	CreatedFrom *graphqlDateTime
+	CreatedTo   *graphqlDateTime
+	Metadata    *graphqlJSON
+	First       int32
+	After       *graphql.ID
+}
+
+func (q *queryResolver) Transactions(ctx context.Context, args transactionsArgs) (*transactionPageResolver, error) {
+	query, err := graphqlTransactionQuery(args)
+	if err != nil {
+		return nil, err
+	}
+	caller := callerOf(ctx)
+	transactions, total, err := q.useCases.listTxn.Execute(ctx, caller.partnerID, caller.livemode, query)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionPageResolver{transactions: transactions, total: total, limit: query.Limit, useCases: q.useCases}, nil
+}
+
+func (q *queryResolver) Refund(ctx context.Context, args struct{ ID graphql.ID }) (*refundResolver, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, errors.NewValidationError("id", "must be a refund ID")
+	}
+	caller := callerOf(ctx)
+	refund, err := q.useCases.getRefund.Execute(ctx, id, caller.partnerID, caller.livemode)
+	if err != nil {
+		return nil, err
+	}
+	return &refundResolver{refund, q.useCases}, nil
+}
+
+// refundsArgs are the arguments of the refunds fields; those of a
+// transaction take only status and first
+type refundsArgs struct {
+	Status        *string
+	TransactionID *graphql.ID
+	CreatedFrom   *graphqlDateTime
+	CreatedTo     *graphqlDateTime
+	First         int32
+	Offset        int32
+}
+
+func (q *queryResolver) Refunds(ctx context.Context, args refundsArgs) ([]*refundResolver, error) {
+	return listRefunds(ctx, q.useCases, args, nil)
+}
+
+// listRefunds lists refunds, of one transaction if transactionID gives one
+func listRefunds(ctx context.Context, useCases *graphqlUseCases, args refundsArgs, transactionID *uuid.UUID) ([]*refundResolver, error) {
+	query := ports.RefundQuery{TransactionID: transactionID, Limit: int(args.First), Offset: int(args.Offset)}
+	if args.TransactionID != nil {
+		parsed, err := uuid.Parse(string(*args.TransactionID))
+		if err != nil {
+			return nil, errors.NewValidationError("transactionId", "must be a transaction ID")
+		}
+		query.TransactionID = &parsed
+	}
+	if args.Status != nil {
+		refundStatus := entities.RefundStatus(*args.Status)
+		if !listableRefundStatuses[refundStatus] {
+			return nil, errors.NewValidationError("status", "unknown status "+*args.Status)
+		}
+		query.Status = &refundStatus
+	}
+	if args.CreatedFrom != nil {
+		query.CreatedFrom = &args.CreatedFrom.Time
+	}
+	if args.CreatedTo != nil {
+		query.CreatedTo = &args.CreatedTo.Time
+	}
+	caller := callerOf(ctx)
+	refunds, err := useCases.listRefunds.Execute(ctx, caller.partnerID, caller.livemode, query)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*refundResolver, len(refunds))
+	for i, refund := range refunds {
+		resolvers[i] = &refundResolver{refund, useCases}
+	}
+	return resolvers, nil
+}
+
+// listEvents lists the events about one aggregate
+func listEvents(ctx context.Context, useCases *graphqlUseCases, aggregateType string, id uuid.UUID, first int32) ([]*eventResolver, error) {
+	events, err := useCases.listEvents.Execute(ctx, callerOf(ctx).partnerID, aggregateType, id, int(first))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*eventResolver, len(events))
+	for i, event := range events {
+		resolvers[i] = &eventResolver{event}
+	}
+	return resolvers, nil
+}
+
+// transactionPageResolver resolves a page of the transactions field
+type transactionPageResolver struct {
+	transactions []*entities.Transaction
+	total        int64
+	limit        int
+	useCases     *graphqlUseCases
+}
+
+func (p *transactionPageResolver) Nodes() []*transactionResolver {
+	resolvers := make([]*transactionResolver, len(p.transactions))
+	for i, txn := range p.transactions {
+		resolvers[i] = &transactionResolver{txn, p.useCases}
+	}
+	return resolvers
+}
+
+func (p *transactionPageResolver) TotalCount() int32 {
+	return int32(p.total)
+}
+
+func (p *transactionPageResolver) EndCursor() *graphql.ID {
+	if len(p.transactions) == 0 || len(p.transactions) < p.limit {
+		return nil
+	}
+	return graphqlID(p.transactions[len(p.transactions)-1].ID)
+}
+
+// transactionResolver resolves the fields of a transaction
+type transactionResolver struct {
+	t        *entities.Transaction
+	useCases *graphqlUseCases
+}
+
+func (r *transactionResolver) ID() graphql.ID         { return *graphqlID(r.t.ID) }
+func (r *transactionResolver) IdempotencyKey() string { return r.t.IdempotencyKey }
+func (r *transactionResolver) Amount() string         { return r.t.Amount.Decimal() }
+func (r *transactionResolver) Currency() string       { return r.t.Amount.Currency.String() }
+func (r *transactionResolver) RefundedAmount() string { return refundedDecimal(r.t) }
+func (r *transactionResolver) PaymentMethod() string  { return string(r.t.PaymentMethod) }
+func (r *transactionResolver) Provider() string       { return string(r.t.Provider) }
+func (r *transactionResolver) ProviderTransactionID() *string {
+	return optional(r.t.ProviderTransactionID)
+}
+func (r *transactionResolver) Status() string                { return string(r.t.Status) }
+func (r *transactionResolver) Livemode() bool                { return r.t.Livemode }
+func (r *transactionResolver) CustomerEmail() string         { return r.t.CustomerEmail }
+func (r *transactionResolver) CustomerName() *string         { return optional(r.t.CustomerName) }
+func (r *transactionResolver) CustomerPhone() *string        { return optional(r.t.CustomerPhone) }
+func (r *transactionResolver) BillingCountry() *string       { return optional(r.t.BillingCountry.String()) }
+func (r *transactionResolver) Description() *string          { return optional(r.t.Description) }
+func (r *transactionResolver) ErrorCode() *string            { return optional(r.t.ErrorCode) }
+func (r *transactionResolver) ErrorMessage() *string         { return optional(r.t.ErrorMessage) }
+func (r *transactionResolver) CreatedAt() graphqlDateTime    { return graphqlDateTime{r.t.CreatedAt} }
+func (r *transactionResolver) UpdatedAt() graphqlDateTime    { return graphqlDateTime{r.t.UpdatedAt} }
+func (r *transactionResolver) ProcessedAt() *graphqlDateTime { return optionalTime(r.t.ProcessedAt) }
+
+func (r *transactionResolver) Metadata() *graphqlJSON {
+	if r.t.Metadata == nil {
+		return nil
+	}
+	return &graphqlJSON{r.t.Metadata}
+}
+
+func (r *transactionResolver) Events(ctx context.Context, args struct{ First int32 }) ([]*eventResolver, error) {
+	return listEvents(ctx, r.useCases, "transaction", r.t.ID, args.First)
+}
+
+func (r *transactionResolver) Refunds(ctx context.Context, args struct {
+	Status *string
+	First  int32
+}) ([]*refundResolver, error) {
+	return listRefunds(ctx, r.useCases, refundsArgs{Status: args.Status, First: args.First}, &r.t.ID)
+}
+
+// refundResolver resolves the fields of a refund
+type refundResolver struct {
+	r        *entities.Refund
+	useCases *graphqlUseCases
+}
+
+func (r *refundResolver) ID() graphql.ID                { return *graphqlID(r.r.ID) }
+func (r *refundResolver) TransactionID() graphql.ID     { return *graphqlID(r.r.TransactionID) }
+func (r *refundResolver) Amount() string                { return r.r.Amount.Decimal() }
+func (r *refundResolver) Currency() string              { return r.r.Amount.Currency.String() }
+func (r *refundResolver) Status() string                { return string(r.r.Status) }
+func (r *refundResolver) Reason() string                { return string(r.r.Reason.Code) }
+func (r *refundResolver) ReasonNote() *string           { return optional(r.r.Reason.Note) }
+func (r *refundResolver) ProviderRefundID() *string     { return optional(r.r.ProviderRefundID) }
+func (r *refundResolver) ErrorCode() *string            { return optional(r.r.ErrorCode) }
+func (r *refundResolver) ErrorMessage() *string         { return optional(r.r.ErrorMessage) }
+func (r *refundResolver) ApprovedBy() *string           { return optional(r.r.ApprovedBy) }
+func (r *refundResolver) ApprovedAt() *graphqlDateTime  { return optionalTime(r.r.ApprovedAt) }
+func (r *refundResolver) CreatedAt() graphqlDateTime    { return graphqlDateTime{r.r.CreatedAt} }
+func (r *refundResolver) UpdatedAt() graphqlDateTime    { return graphqlDateTime{r.r.UpdatedAt} }
+func (r *refundResolver) ProcessedAt() *graphqlDateTime { return optionalTime(r.r.ProcessedAt) }
+
+func (r *refundResolver) Transaction(ctx context.Context) (*transactionResolver, error) {
+	caller := callerOf(ctx)
+	txn, err := r.useCases.getTxn.Execute(ctx, r.r.TransactionID, caller.partnerID, caller.livemode)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionResolver{txn, r.useCases}, nil
+}
+
+func (r *refundResolver) Events(ctx context.Context, args struct{ First int32 }) ([]*eventResolver, error) {
+	return listEvents(ctx, r.useCases, "refund", r.r.ID, args.First)
+}
+
+// eventResolver resolves the fields of an outbox event
+type eventResolver struct {
+	e *entities.OutboxEvent
+}
+
+func (r *eventResolver) ID() graphql.ID                { return *graphqlID(r.e.ID) }
+func (r *eventResolver) Type() string                  { return r.e.EventType }
+func (r *eventResolver) Payload() graphqlJSON          { return graphqlJSON{r.e.Payload} }
+func (r *eventResolver) Attempts() int32               { return int32(r.e.Attempts) }
+func (r *eventResolver) Delivered() bool               { return r.e.PublishedAt != nil }
+func (r *eventResolver) DeliveredAt() *graphqlDateTime { return optionalTime(r.e.PublishedAt) }
+func (r *eventResolver) LastError() *string            { return optional(r.e.LastError) }
+func (r *eventResolver) CreatedAt() graphqlDateTime    { return graphqlDateTime{r.e.CreatedAt} }
+
+// graphqlDateTime is the DateTime scalar: an RFC 3339 timestamp
+type graphqlDateTime struct {
+	time.Time
+}
+
+// ImplementsGraphQLType binds the type to the DateTime scalar
+func (graphqlDateTime) ImplementsGraphQLType(name string) bool {
+	return name == "DateTime"
+}
+
+// UnmarshalGraphQL parses an argument or variable
+func (d *graphqlDateTime) UnmarshalGraphQL(input interface{}) error {
+	if s, ok := input.(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			d.Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("DateTime cannot represent %v; use RFC 3339, such as \"2024-05-01T12:00:00Z\"", input)
+}
+
+// MarshalJSON writes the timestamp in UTC
+func (d graphqlDateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.UTC().Format(time.RFC3339Nano))
+}
+
+// graphqlJSON is the JSON scalar: any JSON value
+type graphqlJSON struct {
+	value interface{}
+}
+
+// ImplementsGraphQLType binds the type to the JSON scalar
+func (graphqlJSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL takes an argument or variable as it is
+func (j *graphqlJSON) UnmarshalGraphQL(input interface{}) error {
+	j.value = input
+	return nil
+}
+
+// MarshalJSON writes the value
+func (j graphqlJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.value)
+}
+
+// graphqlTransactionQuery translates the arguments of the transactions
+// field, as buildTransactionQuery does list query parameters
+func graphqlTransactionQuery(args transactionsArgs) (ports.TransactionQuery, error) {
+	var query ports.TransactionQuery
+	query.Limit = int(args.First)
+	if query.Limit <= 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+
+	if args.Status != nil {
+		for _, value := range *args.Status {
+			status := entities.TransactionStatus(value)
+			if !listableStatuses[status] {
+				return query, errors.NewValidationError("status", "unknown status "+value)
+			}
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+	if args.Currency != nil {
+		currency, err := valueobjects.NewCurrency(*args.Currency)
+		if err != nil {
+			return query, err
+		}
+		query.Currency = &currency
+	}
+	if args.Provider != nil {
+		provider, err := valueobjects.NewPaymentProvider(*args.Provider)
+		if err != nil {
+			return query, err
+		}
+		query.Provider = &provider
+	}
+	if args.CreatedFrom != nil {
+		query.CreatedFrom = &args.CreatedFrom.Time
+	}
+	if args.CreatedTo != nil {
+		query.CreatedTo = &args.CreatedTo.Time
+	}
+	if args.Metadata != nil && args.Metadata.value != nil {
+		fields, ok := args.Metadata.value.(map[string]interface{})
+		if !ok {
+			return query, errors.NewValidationError("metadata", "must be an object of string values")
+		}
+		query.Metadata = make(map[string]string, len(fields))
+		for key, value := range fields {
+			s, ok := value.(string)
+			if !ok {
+				return query, errors.NewValidationError("metadata", "must be an object of string values")
+			}
+			query.Metadata[key] = s
+		}
+	}
+	if args.After != nil {
+		id, err := uuid.Parse(string(*args.After))
+		if err != nil {
+			return query, errors.NewValidationError("after", "must be a transaction ID")
+		}
+		query.After = &id
+	}
+	return query, nil
+}
+
+// listableRefundStatuses are the statuses the refund status filter accepts
+var listableRefundStatuses = map[entities.RefundStatus]bool{
+	entities.RefundStatusPending:          true,
+	entities.RefundStatusRequiresApproval: true,
+	entities.RefundStatusProcessing:       true,
+	entities.RefundStatusCompleted:        true,
+	entities.RefundStatusFailed:           true,
+	entities.RefundStatusCancelled:        true,
+}
+
+// refundedDecimal is the refunded amount of t in its currency, which the
+// refunded amount leaves empty until the first refund
+func refundedDecimal(t *entities.Transaction) string {
+	return valueobjects.FormatMinorUnits(t.RefundedAmount.Amount, t.Amount.Currency)
+}
+
+// optional is s, or null if it is empty
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalTime is t, or null if it is not set
+func optionalTime(t *time.Time) *graphqlDateTime {
+	if t == nil {
+		return nil
+	}
+	return &graphqlDateTime{*t}
+}
+
+// graphqlID is the ID of an entity
+func graphqlID(id uuid.UUID) *graphql.ID {
+	gqlID := graphql.ID(id.String())
+	return &gqlID
+}
@@ -1,45 +1,66 @@
 package handlers
-package handlers
 
 import (
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	})		"status": "alive",	return c.JSON(fiber.Map{func (h *HealthHandler) Live(c *fiber.Ctx) error {// Live handles GET /health/live}	})		"status": "ready",	return c.JSON(fiber.Map{	// In production, check database connectivity, external services, etc.func (h *HealthHandler) Ready(c *fiber.Ctx) error {// Ready handles GET /health/ready}	return c.JSON(response)	}		Version:   "1.0.0",		Timestamp: time.Now(),		Status:    "healthy",	response := dto.HealthCheckResponse{func (h *HealthHandler) Check(c *fiber.Ctx) error {// Check handles GET /health}	return &HealthHandler{}func NewHealthHandler() *HealthHandler {// NewHealthHandler creates a new health handlertype HealthHandler struct{}// HealthHandler handles health check requests)	"Pay2Go/internal/adapters/http/dto"	"github.com/gofiber/fiber/v2"	"time"
\ No newline at end of file
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/usecases/health"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// HealthHandler handles health check requests
+type HealthHandler struct {
+	checkReadinessUC *health.CheckReadinessUseCase
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(checkReadinessUC *health.CheckReadinessUseCase) *HealthHandler {
+	return &HealthHandler{checkReadinessUC: checkReadinessUC}
+}
+
+// Check handles GET /health
+func (h *HealthHandler) Check(c *fiber.Ctx) error {
+	response := dto.HealthCheckResponse{
+		Status:    "healthy",
+		Timestamp: time.Now(),
+		Version:   "1.0.0",
+	}
+	return c.JSON(response)
+}
+
+// Ready handles GET /health/ready. It answers 503 when a dependency is
+// down, and 200 when all are up or some only degraded.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	readiness := h.checkReadinessUC.Execute(c.Context())
+
+	response := dto.ReadinessResponse{
+		Status:     "ready",
+		Timestamp:  time.Now(),
+		Components: make(map[string]dto.ComponentStatus, len(readiness.Components)),
+	}
+	for name, component := range readiness.Components {
+		response.Components[name] = dto.ComponentStatus{
+			Status:  string(component.Status),
+			Error:   component.Error,
+			Details: component.Details,
+		}
+	}
+
+	if !readiness.Ready() {
+		response.Status = "not_ready"
+		return c.Status(fiber.StatusServiceUnavailable).JSON(response)
+	}
+	if readiness.Status == ports.HealthDegraded {
+		response.Status = "degraded"
+	}
+	return c.JSON(response)
+}
+
+// Live handles GET /health/live
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status": "alive",
+	})
+}
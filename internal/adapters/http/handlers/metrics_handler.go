@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"database/sql"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// DBStatsSource is a connection pool that reports statistics, such as *sql.DB
+type DBStatsSource interface {
+	Stats() sql.DBStats
+}
+
+// HTTPTransportStatsSource is the transport of outbound HTTP requests
+type HTTPTransportStatsSource interface {
+	Stats() ports.HTTPTransportStats
+}
+
+// PrometheusSource writes metrics in the Prometheus text exposition format
+type PrometheusSource interface {
+	WritePrometheus(w io.Writer) error
+}
+
+// prometheusContentType is the content type of the text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsHandler handles runtime metrics requests
+type MetricsHandler struct {
+	pools      map[string]DBStatsSource
+	relay      *outbox.Relay
+	transport  HTTPTransportStatsSource
+	prometheus PrometheusSource
+}
+
+// NewMetricsHandler creates a new metrics handler for the named database
+// pools, the webhook relay and the outbound HTTP transport, which serves
+// Prometheus scrapes from prometheus
+func NewMetricsHandler(
+	pools map[string]DBStatsSource,
+	relay *outbox.Relay,
+	transport HTTPTransportStatsSource,
+	prometheus PrometheusSource,
+) *MetricsHandler {
+	return &MetricsHandler{pools: pools, relay: relay, transport: transport, prometheus: prometheus}
+}
+
+// Metrics handles GET /metrics. Prometheus scrapers, which ask for plain
+// text or OpenMetrics, and ?format=prometheus get the text exposition
+// format; everyone else gets JSON.
+func (h *MetricsHandler) Metrics(c *fiber.Ctx) error {
+	if wantsPrometheus(c) {
+		c.Set(fiber.HeaderContentType, prometheusContentType)
+		return h.prometheus.WritePrometheus(c.Response().BodyWriter())
+	}
+
+	response := dto.MetricsResponse{
+		Database: make(map[string]dto.PoolStats, len(h.pools)),
+	}
+	for name, pool := range h.pools {
+		stats := pool.Stats()
+		response.Database[name] = dto.PoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		}
+	}
+
+	relay := h.relay.Stats()
+	response.Webhooks = dto.WebhookRelayStats{
+		Workers:     relay.Workers,
+		PerPartner:  relay.PerPartner,
+		ShardIndex:  relay.Shards.Index,
+		ShardCount:  relay.Shards.Count,
+		Queued:      relay.Queued,
+		InFlight:    relay.InFlight,
+		Published:   relay.Published,
+		Failed:      relay.Failed,
+		Throttled:   relay.Throttled,
+		FullBatches: relay.FullBatches,
+	}
+
+	transport := h.transport.Stats()
+	response.HTTPClient = dto.HTTPClientStats{
+		Requests:          transport.Requests,
+		Failed:            transport.Failed,
+		InFlight:          transport.InFlight,
+		ConnectionsOpened: transport.ConnectionsOpened,
+		ConnectionsReused: transport.ConnectionsReused,
+	}
+	return c.JSON(response)
+}
+
+// wantsPrometheus reports whether the request asks for the text format
+func wantsPrometheus(c *fiber.Ctx) bool {
+	if c.Query("format") == "prometheus" {
+		return true
+	}
+	accept := c.Get(fiber.HeaderAccept)
+	return strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text")
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/apikey"
+	"Pay2Go/internal/usecases/partner"
+)
+
+// PartnerAPIKeyHandler handles back-office requests for a partner's API
+// keys. Keys are created at IDs chosen by the admin, so that
+// infrastructure-as-code tools can apply the same configuration again and
+// again.
+type PartnerAPIKeyHandler struct {
+	getPartnerUseCase *partner.GetPartnerUseCase
+	createUseCase     *apikey.CreateAPIKeyUseCase
+	getUseCase        *apikey.GetAPIKeyUseCase
+	listUseCase       *apikey.ListAPIKeysUseCase
+	revokeUseCase     *apikey.RevokeAPIKeyUseCase
+}
+
+// NewPartnerAPIKeyHandler creates a new partner API key handler
+func NewPartnerAPIKeyHandler(
+	getPartnerUseCase *partner.GetPartnerUseCase,
+	createUseCase *apikey.CreateAPIKeyUseCase,
+	getUseCase *apikey.GetAPIKeyUseCase,
+	listUseCase *apikey.ListAPIKeysUseCase,
+	revokeUseCase *apikey.RevokeAPIKeyUseCase,
+) *PartnerAPIKeyHandler {
+	return &PartnerAPIKeyHandler{
+		getPartnerUseCase: getPartnerUseCase,
+		createUseCase:     createUseCase,
+		getUseCase:        getUseCase,
+		listUseCase:       listUseCase,
+		revokeUseCase:     revokeUseCase,
+	}
+}
+
+// ListAPIKeys handles GET /api/v1/admin/partners/:id/api-keys
+func (h *PartnerAPIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Keys only exist for partners that do
+	if _, err := h.getPartnerUseCase.Execute(c.Context(), partnerID); err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	// Execute use case
+	keys, err := h.listUseCase.Execute(c.Context(), partnerID, 0)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_api_keys")
+	}
+
+	response := dto.ListAPIKeysResponse{
+		APIKeys: make([]dto.APIKeyResponse, len(keys)),
+	}
+	for i, key := range keys {
+		response.APIKeys[i] = mapAPIKeyToDTO(key)
+	}
+	return c.JSON(response)
+}
+
+// GetAPIKey handles GET /api/v1/admin/partners/:id/api-keys/:key_id
+func (h *PartnerAPIKeyHandler) GetAPIKey(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse key ID
+	keyID, err := uuid.Parse(c.Params("key_id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_api_key_id",
+			Message: "invalid API key ID format",
+		})
+	}
+
+	// Keys only exist for partners that do
+	if _, err := h.getPartnerUseCase.Execute(c.Context(), partnerID); err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	// Execute use case
+	key, err := h.getUseCase.Execute(c.Context(), partnerID, keyID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_api_key")
+	}
+
+	return c.JSON(mapAPIKeyToDTO(key))
+}
+
+// PutAPIKey handles PUT /api/v1/admin/partners/:id/api-keys/:key_id. The
+// first request creates the key and answers 201 with its secret; repeating
+// it answers 200 with the same key, whose secret is not shown again.
+func (h *PartnerAPIKeyHandler) PutAPIKey(c *fiber.Ctx) error {
+	// Get admin identity
+	if _, err := middleware.GetAdminID(c); err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse key ID
+	keyID, err := uuid.Parse(c.Params("key_id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_api_key_id",
+			Message: "invalid API key ID format",
+		})
+	}
+
+	// Keys only exist for partners that do
+	if _, err := h.getPartnerUseCase.Execute(c.Context(), partnerID); err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	// Parse request body
+	var req dto.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	output, err := h.createUseCase.Execute(c.Context(), apikey.CreateAPIKeyInput{
+		PartnerID:  partnerID,
+		KeyID:      keyID,
+		Label:      req.Label,
+		Scopes:     req.Scopes,
+		AuthMethod: req.AuthMethod,
+		Livemode:   req.Livemode == nil || *req.Livemode,
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "api_key_creation_failed")
+	}
+
+	if !output.Created {
+		return c.JSON(mapAPIKeyToDTO(output.Key))
+	}
+	return c.Status(fiber.StatusCreated).JSON(dto.CreateAPIKeyResponse{
+		APIKeyResponse: mapAPIKeyToDTO(output.Key),
+		Key:            output.Plaintext,
+	})
+}
+
+// RevokeAPIKey handles DELETE /api/v1/admin/partners/:id/api-keys/:key_id;
+// revoking a revoked key succeeds too
+func (h *PartnerAPIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	// Get admin identity
+	if _, err := middleware.GetAdminID(c); err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse key ID
+	keyID, err := uuid.Parse(c.Params("key_id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_api_key_id",
+			Message: "invalid API key ID format",
+		})
+	}
+
+	// Keys only exist for partners that do
+	if _, err := h.getPartnerUseCase.Execute(c.Context(), partnerID); err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	// Execute use case
+	_, err = h.revokeUseCase.Execute(c.Context(), apikey.RevokeAPIKeyInput{
+		KeyID:     keyID,
+		PartnerID: partnerID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil && err != errors.ErrAPIKeyRevoked {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "api_key_revocation_failed")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
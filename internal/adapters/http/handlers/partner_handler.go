@@ -0,0 +1,1022 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/partner"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// PartnerHandler handles back-office partner management requests
+type PartnerHandler struct {
+	getUseCase              *partner.GetPartnerUseCase
+	listUseCase             *partner.ListPartnersUseCase
+	putUseCase              *partner.PutPartnerUseCase
+	updateWebhookUseCase    *partner.UpdatePartnerWebhookUseCase
+	updateFeaturesUseCase   *partner.UpdatePartnerFeaturesUseCase
+	updateCurrenciesUseCase *partner.UpdatePartnerCurrenciesUseCase
+	updateLimitsUseCase     *partner.UpdatePartnerAmountLimitsUseCase
+	updateLocaleUseCase     *partner.UpdatePartnerLocaleUseCase
+	updateSMSSenderUseCase  *partner.UpdatePartnerSMSSenderUseCase
+	updateRoundingUseCase   *partner.UpdatePartnerRoundingPolicyUseCase
+	updateEventsUseCase     *partner.UpdatePartnerEventDestinationUseCase
+	rotateSecretsUseCase    *partner.RotateSecretsUseCase
+	offboardUseCase         *partner.OffboardPartnerUseCase
+}
+
+// NewPartnerHandler creates a new partner handler
+func NewPartnerHandler(
+	getUseCase *partner.GetPartnerUseCase,
+	listUseCase *partner.ListPartnersUseCase,
+	putUseCase *partner.PutPartnerUseCase,
+	updateWebhookUseCase *partner.UpdatePartnerWebhookUseCase,
+	updateFeaturesUseCase *partner.UpdatePartnerFeaturesUseCase,
+	updateCurrenciesUseCase *partner.UpdatePartnerCurrenciesUseCase,
+	updateLimitsUseCase *partner.UpdatePartnerAmountLimitsUseCase,
+	updateLocaleUseCase *partner.UpdatePartnerLocaleUseCase,
+	updateSMSSenderUseCase *partner.UpdatePartnerSMSSenderUseCase,
+	updateRoundingUseCase *partner.UpdatePartnerRoundingPolicyUseCase,
+	updateEventsUseCase *partner.UpdatePartnerEventDestinationUseCase,
+	rotateSecretsUseCase *partner.RotateSecretsUseCase,
+	offboardUseCase *partner.OffboardPartnerUseCase,
+) *PartnerHandler {
+	return &PartnerHandler{
+		getUseCase:              getUseCase,
+		listUseCase:             listUseCase,
+		putUseCase:              putUseCase,
+		updateWebhookUseCase:    updateWebhookUseCase,
+		updateFeaturesUseCase:   updateFeaturesUseCase,
+		updateCurrenciesUseCase: updateCurrenciesUseCase,
+		updateLimitsUseCase:     updateLimitsUseCase,
+		updateLocaleUseCase:     updateLocaleUseCase,
+		updateSMSSenderUseCase:  updateSMSSenderUseCase,
+		updateRoundingUseCase:   updateRoundingUseCase,
+		updateEventsUseCase:     updateEventsUseCase,
+		rotateSecretsUseCase:    rotateSecretsUseCase,
+		offboardUseCase:         offboardUseCase,
+	}
+}
+
+// ListPartners handles GET /api/v1/admin/partners
+func (h *PartnerHandler) ListPartners(c *fiber.Ctx) error {
+	// Parse query parameters
+	var req dto.ListPartnersRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Set defaults
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	// Execute use case
+	partners, total, err := h.listUseCase.Execute(c.Context(), req.Limit, req.Offset)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_partners")
+	}
+
+	// Map to response DTOs
+	summaries := make([]dto.PartnerSummary, len(partners))
+	for i, p := range partners {
+		summaries[i] = dto.PartnerSummary{
+			ID:        p.ID.String(),
+			Name:      p.Name,
+			Email:     p.Email,
+			IsActive:  p.IsActive,
+			Locale:    p.Locale.String(),
+			CreatedAt: p.CreatedAt,
+		}
+	}
+
+	return c.JSON(dto.ListPartnersResponse{
+		Partners: summaries,
+		Total:    total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	})
+}
+
+// GetPartner handles GET /api/v1/admin/partners/:id
+func (h *PartnerHandler) GetPartner(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerToDTO(p))
+}
+
+// PutPartner handles PUT /api/v1/admin/partners/:id, creating the partner
+// at the ID in the path or bringing the existing one to the request's state
+func (h *PartnerHandler) PutPartner(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.PutPartnerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	output, err := h.putUseCase.Execute(expectVersion(c), partner.PutPartnerInput{
+		PartnerID:                partnerID,
+		Name:                     req.Name,
+		Email:                    req.Email,
+		Active:                   req.IsActive == nil || *req.IsActive,
+		RateLimitPerMinute:       req.RateLimitPerMinute,
+		RefundApprovalThresholds: req.RefundApprovalThresholds,
+		RefundWindowDays:         req.RefundWindowDays,
+		CreateOnly:               c.Get(fiber.HeaderIfNoneMatch) == "*",
+		UpdateOnly:               c.Get(fiber.HeaderIfMatch) == "*",
+		AdminID:                  adminID,
+		IPAddress:                c.IP(),
+		UserAgent:                c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	if output.Created {
+		c.Status(fiber.StatusCreated)
+		c.Location("/api/v1/admin/partners/" + output.Partner.ID.String())
+	}
+	return partnerJSON(c, output.Partner, mapPartnerToDTO(output.Partner))
+}
+
+// GetWebhook handles GET /api/v1/admin/partners/:id/webhook
+func (h *PartnerHandler) GetWebhook(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, dto.PartnerWebhookResponse{PartnerID: p.ID.String(), URL: p.WebhookURL})
+}
+
+// UpdateWebhook handles PUT /api/v1/admin/partners/:id/webhook
+func (h *PartnerHandler) UpdateWebhook(c *fiber.Ctx) error {
+	// Parse request body
+	var req dto.UpdatePartnerWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+	if req.URL == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "url is required",
+		})
+	}
+
+	return h.setWebhook(c, req.URL)
+}
+
+// DeleteWebhook handles DELETE /api/v1/admin/partners/:id/webhook
+func (h *PartnerHandler) DeleteWebhook(c *fiber.Ctx) error {
+	return h.setWebhook(c, "")
+}
+
+// setWebhook changes the webhook URL of the partner in the path, or removes
+// the webhook when url is empty
+func (h *PartnerHandler) setWebhook(c *fiber.Ctx, url string) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	output, err := h.updateWebhookUseCase.Execute(expectVersion(c), partner.UpdatePartnerWebhookInput{
+		PartnerID: partnerID,
+		URL:       url,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, output.Partner, dto.PartnerWebhookResponse{
+		PartnerID: output.Partner.ID.String(),
+		URL:       output.Partner.WebhookURL,
+		Secret:    output.Secret,
+	})
+}
+
+// GetFeatures handles GET /api/v1/admin/partners/:id/features
+func (h *PartnerHandler) GetFeatures(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerFeaturesToDTO(p))
+}
+
+// UpdateFeatures handles PATCH /api/v1/admin/partners/:id/features
+func (h *PartnerHandler) UpdateFeatures(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdatePartnerFeaturesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	if len(req.Features) == 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "features must not be empty",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateFeaturesUseCase.Execute(expectVersion(c), partner.UpdatePartnerFeaturesInput{
+		PartnerID: partnerID,
+		Features:  req.Features,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, mapPartnerFeaturesToDTO(p))
+}
+
+// GetCurrencies handles GET /api/v1/admin/partners/:id/currencies
+func (h *PartnerHandler) GetCurrencies(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerCurrenciesToDTO(p))
+}
+
+// UpdateCurrencies handles PUT /api/v1/admin/partners/:id/currencies
+func (h *PartnerHandler) UpdateCurrencies(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdatePartnerCurrenciesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateCurrenciesUseCase.Execute(expectVersion(c), partner.UpdatePartnerCurrenciesInput{
+		PartnerID:  partnerID,
+		Currencies: req.Currencies,
+		AdminID:    adminID,
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, mapPartnerCurrenciesToDTO(p))
+}
+
+// GetAmountLimits handles GET /api/v1/admin/partners/:id/amount-limits
+func (h *PartnerHandler) GetAmountLimits(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerAmountLimitsToDTO(p))
+}
+
+// UpdateAmountLimits handles PUT /api/v1/admin/partners/:id/amount-limits
+func (h *PartnerHandler) UpdateAmountLimits(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdatePartnerAmountLimitsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateLimitsUseCase.Execute(expectVersion(c), partner.UpdatePartnerAmountLimitsInput{
+		PartnerID: partnerID,
+		Limits:    req.AmountLimits,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, mapPartnerAmountLimitsToDTO(p))
+}
+
+// GetLocale handles GET /api/v1/admin/partners/:id/locale
+func (h *PartnerHandler) GetLocale(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerLocaleToDTO(p))
+}
+
+// UpdateLocale handles PUT /api/v1/admin/partners/:id/locale
+func (h *PartnerHandler) UpdateLocale(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdatePartnerLocaleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateLocaleUseCase.Execute(expectVersion(c), partner.UpdatePartnerLocaleInput{
+		PartnerID: partnerID,
+		Locale:    req.Locale,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, mapPartnerLocaleToDTO(p))
+}
+
+// GetSMSSender handles GET /api/v1/admin/partners/:id/sms-sender
+func (h *PartnerHandler) GetSMSSender(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerSMSSenderToDTO(p))
+}
+
+// UpdateSMSSender handles PUT /api/v1/admin/partners/:id/sms-sender
+func (h *PartnerHandler) UpdateSMSSender(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdatePartnerSMSSenderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateSMSSenderUseCase.Execute(expectVersion(c), partner.UpdatePartnerSMSSenderInput{
+		PartnerID: partnerID,
+		SMSSender: req.SMSSender,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, mapPartnerSMSSenderToDTO(p))
+}
+
+// GetRoundingPolicy handles GET /api/v1/admin/partners/:id/rounding-policy
+func (h *PartnerHandler) GetRoundingPolicy(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerRoundingPolicyToDTO(p))
+}
+
+// UpdateRoundingPolicy handles PUT /api/v1/admin/partners/:id/rounding-policy
+func (h *PartnerHandler) UpdateRoundingPolicy(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdatePartnerRoundingPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateRoundingUseCase.Execute(expectVersion(c), partner.UpdatePartnerRoundingPolicyInput{
+		PartnerID: partnerID,
+		Policy:    req.RoundingPolicy,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, mapPartnerRoundingPolicyToDTO(p))
+}
+
+// GetEventDestination handles GET /api/v1/admin/partners/:id/event-destination
+func (h *PartnerHandler) GetEventDestination(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, mapPartnerEventDestinationToDTO(p))
+}
+
+// UpdateEventDestination handles PUT /api/v1/admin/partners/:id/event-destination
+func (h *PartnerHandler) UpdateEventDestination(c *fiber.Ctx) error {
+	// Parse request body
+	var req dto.UpdatePartnerEventDestinationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+	if req.Type == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "type is required",
+		})
+	}
+
+	return h.setEventDestination(c, req)
+}
+
+// DeleteEventDestination handles DELETE /api/v1/admin/partners/:id/event-destination
+func (h *PartnerHandler) DeleteEventDestination(c *fiber.Ctx) error {
+	return h.setEventDestination(c, dto.UpdatePartnerEventDestinationRequest{})
+}
+
+// setEventDestination changes the event destination of the partner in the
+// path to req's, or removes it when req is empty
+func (h *PartnerHandler) setEventDestination(c *fiber.Ctx, req dto.UpdatePartnerEventDestinationRequest) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.updateEventsUseCase.Execute(expectVersion(c), partner.UpdatePartnerEventDestinationInput{
+		PartnerID: partnerID,
+		Type:      req.Type,
+		Target:    req.Target,
+		RoleARN:   req.RoleARN,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, mapPartnerEventDestinationToDTO(p))
+}
+
+// Offboard handles DELETE /api/v1/admin/partners/:id
+func (h *PartnerHandler) Offboard(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	output, err := h.offboardUseCase.Execute(expectVersion(c), partner.OffboardPartnerInput{
+		PartnerID: partnerID,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrPartnerNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "partner_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "partner_offboarding_failed")
+	}
+
+	return c.JSON(dto.OffboardPartnerResponse{
+		PartnerID:      output.Partner.ID.String(),
+		RevokedAPIKeys: output.RevokedAPIKeys,
+		DeletedAt:      output.Partner.DeletedAt,
+		AnonymizeAfter: output.Partner.AnonymizeAfter,
+	})
+}
+
+// RotateSecrets handles POST /api/v1/admin/secrets/rotate
+func (h *PartnerHandler) RotateSecrets(c *fiber.Ctx) error {
+	rotated, err := h.rotateSecretsUseCase.Execute(c.Context())
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "secret_rotation_failed")
+	}
+
+	return c.JSON(dto.RotateSecretsResponse{Rotated: rotated})
+}
+
+// partnerJSON answers body, a representation of p's settings, tagged with
+// p's version. Every admin resource of a partner shares the tag, so any
+// change to the partner invalidates all of them.
+func partnerJSON(c *fiber.Ctx, p *entities.Partner, body interface{}) error {
+	c.Set(fiber.HeaderETag, `"`+strconv.Itoa(p.Version)+`"`)
+	return c.JSON(body)
+}
+
+// expectVersion returns the request's context, expecting the partner
+// version its If-Match header names (see ports.ExpectVersion). A tag this
+// API did not issue, including a weak one, never matches; * matches any
+// version.
+func expectVersion(c *fiber.Ctx) context.Context {
+	ifMatch := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return c.Context()
+	}
+
+	version := 0
+	if unquoted, ok := strings.CutPrefix(ifMatch, `"`); ok && strings.HasSuffix(unquoted, `"`) {
+		version, _ = strconv.Atoi(strings.TrimSuffix(unquoted, `"`))
+	}
+	return ports.ExpectVersion(c.Context(), version)
+}
+
+// mapPartnerToDTO maps a partner's account settings to their response DTO
+func mapPartnerToDTO(p *entities.Partner) dto.PartnerResponse {
+	return dto.PartnerResponse{
+		ID:                       p.ID.String(),
+		Name:                     p.Name,
+		Email:                    p.Email,
+		IsActive:                 p.IsActive,
+		RateLimitPerMinute:       p.RateLimitPerMinute,
+		RefundApprovalThresholds: p.RefundApprovalThresholds.Decimals(),
+		RefundWindowDays:         p.RefundWindowDays,
+		Version:                  p.Version,
+		CreatedAt:                p.CreatedAt,
+		UpdatedAt:                p.UpdatedAt,
+	}
+}
+
+// mapPartnerCurrenciesToDTO maps a partner's allowed currencies to their response DTO
+func mapPartnerCurrenciesToDTO(p *entities.Partner) dto.PartnerCurrenciesResponse {
+	currencies := make([]string, len(p.AllowedCurrencies))
+	for i, currency := range p.AllowedCurrencies {
+		currencies[i] = currency.String()
+	}
+
+	return dto.PartnerCurrenciesResponse{
+		PartnerID:  p.ID.String(),
+		Currencies: currencies,
+	}
+}
+
+// mapPartnerAmountLimitsToDTO maps a partner's amount limits to their response DTO
+func mapPartnerAmountLimitsToDTO(p *entities.Partner) dto.PartnerAmountLimitsResponse {
+	return dto.PartnerAmountLimitsResponse{
+		PartnerID:    p.ID.String(),
+		AmountLimits: p.AmountLimits.Decimals(),
+	}
+}
+
+// mapPartnerLocaleToDTO maps a partner's locale to its response DTO
+func mapPartnerLocaleToDTO(p *entities.Partner) dto.PartnerLocaleResponse {
+	return dto.PartnerLocaleResponse{
+		PartnerID: p.ID.String(),
+		Locale:    p.Locale.String(),
+		Country:   p.Locale.Country().String(),
+	}
+}
+
+// mapPartnerSMSSenderToDTO maps a partner's SMS sender to its response DTO
+func mapPartnerSMSSenderToDTO(p *entities.Partner) dto.PartnerSMSSenderResponse {
+	return dto.PartnerSMSSenderResponse{
+		PartnerID: p.ID.String(),
+		SMSSender: p.SMSSenderID.String(),
+	}
+}
+
+// mapPartnerRoundingPolicyToDTO maps a partner's rounding policy to its response DTO
+func mapPartnerRoundingPolicyToDTO(p *entities.Partner) dto.PartnerRoundingPolicyResponse {
+	return dto.PartnerRoundingPolicyResponse{
+		PartnerID:      p.ID.String(),
+		RoundingPolicy: p.RoundingPolicy.String(),
+	}
+}
+
+// mapPartnerEventDestinationToDTO maps a partner's event destination to its response DTO
+func mapPartnerEventDestinationToDTO(p *entities.Partner) dto.PartnerEventDestinationResponse {
+	response := dto.PartnerEventDestinationResponse{
+		PartnerID:  p.ID.String(),
+		ExternalID: p.ID.String(),
+	}
+	if p.EventDestination != nil {
+		response.EventDestination = &dto.EventDestinationDTO{
+			Type:    string(p.EventDestination.Type),
+			Target:  p.EventDestination.Target,
+			RoleARN: p.EventDestination.RoleARN,
+			Region:  p.EventDestination.Region(),
+		}
+	}
+	return response
+}
+
+// mapPartnerFeaturesToDTO maps a partner's effective feature flags to their response DTO
+func mapPartnerFeaturesToDTO(p *entities.Partner) dto.PartnerFeaturesResponse {
+	features := make(map[string]bool)
+	for feature, enabled := range p.EffectiveFeatures() {
+		features[feature.String()] = enabled
+	}
+
+	return dto.PartnerFeaturesResponse{
+		PartnerID: p.ID.String(),
+		Features:  features,
+	}
+}
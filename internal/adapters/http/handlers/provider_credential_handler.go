@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/credential"
+)
+
+// ProviderCredentialHandler handles requests for partners' own provider accounts
+type ProviderCredentialHandler struct {
+	saveUseCase   *credential.SaveProviderCredentialUseCase
+	listUseCase   *credential.ListProviderCredentialsUseCase
+	deleteUseCase *credential.DeleteProviderCredentialUseCase
+}
+
+// NewProviderCredentialHandler creates a new provider credential handler
+func NewProviderCredentialHandler(
+	saveUseCase *credential.SaveProviderCredentialUseCase,
+	listUseCase *credential.ListProviderCredentialsUseCase,
+	deleteUseCase *credential.DeleteProviderCredentialUseCase,
+) *ProviderCredentialHandler {
+	return &ProviderCredentialHandler{
+		saveUseCase:   saveUseCase,
+		listUseCase:   listUseCase,
+		deleteUseCase: deleteUseCase,
+	}
+}
+
+// SaveCredential handles PUT /api/v1/provider-credentials/:provider
+func (h *ProviderCredentialHandler) SaveCredential(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.SaveProviderCredentialRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	saved, err := h.saveUseCase.Execute(c.Context(), credential.SaveProviderCredentialInput{
+		PartnerID: partnerID,
+		Provider:  c.Params("provider"),
+		AccountID: req.AccountID,
+		SecretKey: req.SecretKey,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "provider_credential_save_failed")
+	}
+
+	return c.JSON(mapProviderCredentialToDTO(saved))
+}
+
+// ListCredentials handles GET /api/v1/provider-credentials
+func (h *ProviderCredentialHandler) ListCredentials(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	credentials, err := h.listUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_provider_credentials")
+	}
+
+	response := dto.ListProviderCredentialsResponse{
+		Credentials: make([]dto.ProviderCredentialResponse, len(credentials)),
+	}
+	for i, item := range credentials {
+		response.Credentials[i] = mapProviderCredentialToDTO(item)
+	}
+	return c.JSON(response)
+}
+
+// DeleteCredential handles DELETE /api/v1/provider-credentials/:provider
+func (h *ProviderCredentialHandler) DeleteCredential(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	err = h.deleteUseCase.Execute(c.Context(), credential.DeleteProviderCredentialInput{
+		PartnerID: partnerID,
+		Provider:  c.Params("provider"),
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrProviderCredentialNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "provider_credential_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "provider_credential_delete_failed")
+	}
+
+	return c.JSON(fiber.Map{"message": "provider credential deleted"})
+}
+
+// mapProviderCredentialToDTO maps a provider credential to its response DTO
+func mapProviderCredentialToDTO(item *entities.ProviderCredential) dto.ProviderCredentialResponse {
+	return dto.ProviderCredentialResponse{
+		Provider:  item.Provider.String(),
+		AccountID: item.AccountID,
+		SecretKey: item.MaskedSecret(),
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
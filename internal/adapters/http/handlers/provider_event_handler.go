@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// ProviderEventHandler receives the notifications payment providers send
+// about payments and refunds
+type ProviderEventHandler struct {
+	handleUseCase *transaction.HandleProviderEventsUseCase
+}
+
+// NewProviderEventHandler creates a new provider event handler
+func NewProviderEventHandler(handleUseCase *transaction.HandleProviderEventsUseCase) *ProviderEventHandler {
+	return &ProviderEventHandler{handleUseCase: handleUseCase}
+}
+
+// ReceiveEvents handles POST /api/v1/providers/:provider/events
+func (h *ProviderEventHandler) ReceiveEvents(c *fiber.Ctx) error {
+	// Execute use case; the provider's signature authenticates the request
+	result, err := h.handleUseCase.Execute(c.Context(), c.Params("provider"), c.Body(), func(name string) string {
+		return c.Get(name)
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "provider_event_failed")
+	}
+
+	return c.JSON(dto.ProviderEventsResponse{
+		Handled:    result.Handled,
+		Duplicates: result.Duplicates,
+	})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/partner"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// ReceiptHandler handles requests for transaction receipts and the logo
+// printed on them
+type ReceiptHandler struct {
+	getReceiptUseCase *transaction.GetReceiptUseCase
+	updateLogoUseCase *partner.UpdatePartnerReceiptLogoUseCase
+}
+
+// NewReceiptHandler creates a new receipt handler
+func NewReceiptHandler(
+	getReceiptUseCase *transaction.GetReceiptUseCase,
+	updateLogoUseCase *partner.UpdatePartnerReceiptLogoUseCase,
+) *ReceiptHandler {
+	return &ReceiptHandler{
+		getReceiptUseCase: getReceiptUseCase,
+		updateLogoUseCase: updateLogoUseCase,
+	}
+}
+
+// GetReceipt handles GET /api/v1/transactions/:id/receipt. The receipt is
+// tagged with the version of what it shows, so a client that has it gets
+// 304 Not Modified until a refund or a change of branding reissues it.
+func (h *ReceiptHandler) GetReceipt(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID from URL
+	txnID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// Execute use case
+	output, err := h.getReceiptUseCase.Execute(c.Context(), txnID, partnerID, middleware.GetLivemode(c), receiptTag(c.Get(fiber.HeaderIfNoneMatch)))
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusNotFound, "transaction_not_found")
+	}
+
+	// Receipts belong to one partner, and clients must check back every time
+	c.Set(fiber.HeaderETag, `"`+output.Version+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if output.NotModified {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="receipt-`+txnID.String()+`.pdf"`)
+	return c.Send(output.Document)
+}
+
+// receiptTag is the version an If-None-Match header names, weak or not;
+// empty when it names none
+func receiptTag(ifNoneMatch string) string {
+	tag := strings.TrimPrefix(strings.TrimSpace(ifNoneMatch), "W/")
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return ""
+	}
+	return tag[1 : len(tag)-1]
+}
+
+// UpdateReceiptLogo handles PUT /api/v1/branding/logo
+func (h *ReceiptHandler) UpdateReceiptLogo(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdateReceiptLogoRequest
+	if err := c.BodyParser(&req); err != nil || len(req.Image) == 0 {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "image must be a base64 encoded PNG or JPEG image",
+		})
+	}
+
+	return h.updateLogo(c, partner.UpdatePartnerReceiptLogoInput{PartnerID: partnerID, Image: req.Image})
+}
+
+// DeleteReceiptLogo handles DELETE /api/v1/branding/logo; receipts then
+// show the partner's name alone
+func (h *ReceiptHandler) DeleteReceiptLogo(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	return h.updateLogo(c, partner.UpdatePartnerReceiptLogoInput{PartnerID: partnerID})
+}
+
+// updateLogo sets or removes the partner's logo
+func (h *ReceiptHandler) updateLogo(c *fiber.Ctx, input partner.UpdatePartnerReceiptLogoInput) error {
+	input.IPAddress = c.IP()
+	input.UserAgent = c.Get("User-Agent")
+
+	// Execute use case
+	if _, err := h.updateLogoUseCase.Execute(c.Context(), input); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
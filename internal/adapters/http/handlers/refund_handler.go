@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// RefundHandler handles refund-related HTTP requests
+type RefundHandler struct {
+	approveRefundUseCase *transaction.ApproveRefundUseCase
+	cancelRefundUseCase  *transaction.CancelRefundUseCase
+	bulkRefundUseCase    *transaction.BulkRefundUseCase
+	getBulkJobUseCase    *transaction.GetBulkRefundJobUseCase
+	reasonSummaryUseCase *transaction.GetRefundReasonSummaryUseCase
+	deleteRefundUseCase  *transaction.DeleteRefundUseCase
+	restoreRefundUseCase *transaction.RestoreRefundUseCase
+}
+
+// NewRefundHandler creates a new refund handler
+func NewRefundHandler(
+	approveRefundUseCase *transaction.ApproveRefundUseCase,
+	cancelRefundUseCase *transaction.CancelRefundUseCase,
+	bulkRefundUseCase *transaction.BulkRefundUseCase,
+	getBulkJobUseCase *transaction.GetBulkRefundJobUseCase,
+	reasonSummaryUseCase *transaction.GetRefundReasonSummaryUseCase,
+	deleteRefundUseCase *transaction.DeleteRefundUseCase,
+	restoreRefundUseCase *transaction.RestoreRefundUseCase,
+) *RefundHandler {
+	return &RefundHandler{
+		approveRefundUseCase: approveRefundUseCase,
+		cancelRefundUseCase:  cancelRefundUseCase,
+		bulkRefundUseCase:    bulkRefundUseCase,
+		getBulkJobUseCase:    getBulkJobUseCase,
+		reasonSummaryUseCase: reasonSummaryUseCase,
+		deleteRefundUseCase:  deleteRefundUseCase,
+		restoreRefundUseCase: restoreRefundUseCase,
+	}
+}
+
+// ApproveRefund handles POST /api/v1/refunds/:id/approve
+func (h *RefundHandler) ApproveRefund(c *fiber.Ctx) error {
+	// Approvers are back-office admins, or team members of the owning partner
+	input := transaction.ApproveRefundInput{
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	}
+	if adminID, err := middleware.GetAdminID(c); err == nil {
+		input.ApprovedBy = adminID
+	} else if user, ok := middleware.GetUser(c); ok {
+		input.ApprovedBy = user.Email
+		input.PartnerID = &user.PartnerID
+	} else {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin or team member authentication required",
+		})
+	}
+
+	// Parse refund ID
+	refundID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_refund_id",
+			Message: "invalid refund ID format",
+		})
+	}
+
+	input.RefundID = refundID
+
+	// Execute use case
+	refund, err := h.approveRefundUseCase.Execute(c.Context(), input)
+	if err != nil {
+		if err == errors.ErrRefundNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "refund_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusUnprocessableEntity, "refund_approval_failed")
+	}
+
+	return c.JSON(mapRefundToDTO(refund))
+}
+
+// CancelRefund handles POST /api/v1/refunds/:id/cancel
+func (h *RefundHandler) CancelRefund(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse refund ID
+	refundID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_refund_id",
+			Message: "invalid refund ID format",
+		})
+	}
+
+	// Execute use case
+	refund, err := h.cancelRefundUseCase.Execute(c.Context(), transaction.CancelRefundInput{
+		RefundID:  refundID,
+		PartnerID: partnerID,
+		Livemode:  middleware.GetLivemode(c),
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrRefundNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "refund_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusConflict, "refund_cancellation_failed")
+	}
+
+	return c.JSON(mapRefundToDTO(refund))
+}
+
+// BulkRefund handles POST /api/v1/refunds/bulk
+func (h *RefundHandler) BulkRefund(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.BulkRefundRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	transactionIDs := make([]uuid.UUID, len(req.TransactionIDs))
+	for i, rawID := range req.TransactionIDs {
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_transaction_id",
+				Message: "invalid transaction ID format: " + rawID,
+			})
+		}
+		transactionIDs[i] = id
+	}
+
+	// Execute use case
+	job, err := h.bulkRefundUseCase.Execute(c.Context(), transaction.BulkRefundInput{
+		PartnerID:      partnerID,
+		TransactionIDs: transactionIDs,
+		ReasonCode:     req.Reason,
+		ReasonNote:     req.ReasonNote,
+		Livemode:       middleware.GetLivemode(c),
+		IPAddress:      c.IP(),
+		UserAgent:      c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrFeatureDisabled {
+			return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+				Error:   "feature_disabled",
+				Message: "bulk refunds are not enabled for this account",
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "bulk_refund_failed")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.BulkRefundJobResponse{
+		JobID:     job.ID.String(),
+		Status:    string(job.Status),
+		Total:     len(job.TransactionIDs),
+		CreatedAt: job.CreatedAt,
+	})
+}
+
+// GetBulkRefundJob handles GET /api/v1/refunds/bulk/:id
+func (h *RefundHandler) GetBulkRefundJob(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse job ID
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_job_id",
+			Message: "invalid job ID format",
+		})
+	}
+
+	// Execute use case
+	job, err := h.getBulkJobUseCase.Execute(c.Context(), jobID, partnerID, middleware.GetLivemode(c))
+	if err != nil {
+		if err == errors.ErrBulkRefundJobNotFound {
+			return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+				Error:   "job_not_found",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_job")
+	}
+
+	return c.JSON(mapBulkRefundJobToDTO(job))
+}
+
+// GetReasonSummary handles GET /api/v1/refunds/reasons
+func (h *RefundHandler) GetReasonSummary(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse query parameters
+	var req dto.RefundReasonSummaryRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	filter := ports.RefundReportFilter{
+		PartnerID: partnerID,
+		Livemode:  middleware.GetLivemode(c),
+	}
+	if req.DateFrom != "" {
+		filter.DateFrom = &req.DateFrom
+	}
+	if req.DateTo != "" {
+		filter.DateTo = &req.DateTo
+	}
+
+	// Execute use case
+	summaries, err := h.reasonSummaryUseCase.Execute(c.Context(), filter)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_refund_summary")
+	}
+
+	response := dto.RefundReasonSummaryResponse{
+		Reasons: make([]dto.RefundReasonSummaryItem, len(summaries)),
+	}
+	for i, summary := range summaries {
+		response.Reasons[i] = dto.RefundReasonSummaryItem{
+			Reason:      string(summary.ReasonCode),
+			Currency:    summary.Total.Currency.String(),
+			Count:       summary.Count,
+			TotalAmount: summary.Total.Decimal(),
+		}
+	}
+	return c.JSON(response)
+}
+
+// DeleteRefund handles DELETE /api/v1/admin/refunds/:id
+func (h *RefundHandler) DeleteRefund(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse refund ID
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_refund_id",
+			Message: "invalid refund ID format",
+		})
+	}
+
+	// Execute use case
+	refund, err := h.deleteRefundUseCase.Execute(c.Context(), transaction.SoftDeleteInput{
+		ID:        id,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return softDeleteError(c, err, errors.ErrRefundNotFound, "refund", "refund_deletion_failed")
+	}
+
+	return c.JSON(dto.DeletedResponse{
+		ID:        refund.ID.String(),
+		DeletedAt: refund.DeletedAt,
+	})
+}
+
+// RestoreRefund handles POST /api/v1/admin/refunds/:id/restore
+func (h *RefundHandler) RestoreRefund(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse refund ID
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_refund_id",
+			Message: "invalid refund ID format",
+		})
+	}
+
+	// Execute use case
+	refund, err := h.restoreRefundUseCase.Execute(c.Context(), transaction.SoftDeleteInput{
+		ID:        id,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return softDeleteError(c, err, errors.ErrRefundNotFound, "refund", "refund_restore_failed")
+	}
+
+	return c.JSON(mapRefundToDTO(refund))
+}
+
+// mapRefundToDTO maps a refund entity to its response DTO
+func mapRefundToDTO(refund *entities.Refund) dto.RefundTransactionResponse {
+	return dto.RefundTransactionResponse{
+		RefundID:      refund.ID.String(),
+		TransactionID: refund.TransactionID.String(),
+		Amount:        refund.Amount.Decimal(),
+		Currency:      refund.Amount.Currency.String(),
+		Status:        string(refund.Status),
+		Reason:        string(refund.Reason.Code),
+		ReasonNote:    refund.Reason.Note,
+		ApprovedBy:    refund.ApprovedBy,
+		ApprovedAt:    refund.ApprovedAt,
+		CancelledAt:   refund.CancelledAt,
+		CreatedAt:     refund.CreatedAt,
+	}
+}
+
+// mapBulkRefundJobToDTO maps a bulk refund job to its progress report
+func mapBulkRefundJobToDTO(job *entities.BulkRefundJob) dto.BulkRefundJobResponse {
+	results := make([]dto.BulkRefundItemResponse, len(job.Results))
+	for i, item := range job.Results {
+		results[i] = dto.BulkRefundItemResponse{
+			TransactionID: item.TransactionID.String(),
+			Status:        item.Status,
+			Error:         item.Error,
+		}
+		if item.RefundID != nil {
+			results[i].RefundID = item.RefundID.String()
+		}
+	}
+
+	return dto.BulkRefundJobResponse{
+		JobID:       job.ID.String(),
+		Status:      string(job.Status),
+		Total:       len(job.TransactionIDs),
+		Processed:   job.Processed(),
+		Succeeded:   job.Succeeded,
+		Failed:      job.Failed,
+		Results:     results,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+}
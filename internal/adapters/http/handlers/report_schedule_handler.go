@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/report"
+)
+
+// ReportScheduleHandler handles requests for partners' scheduled reports
+type ReportScheduleHandler struct {
+	createUseCase  *report.CreateScheduleUseCase
+	updateUseCase  *report.UpdateScheduleUseCase
+	listUseCase    *report.ListSchedulesUseCase
+	getUseCase     *report.GetScheduleUseCase
+	deleteUseCase  *report.DeleteScheduleUseCase
+	deliverUseCase *report.DeliverReportsUseCase
+}
+
+// NewReportScheduleHandler creates a new report schedule handler
+func NewReportScheduleHandler(
+	createUseCase *report.CreateScheduleUseCase,
+	updateUseCase *report.UpdateScheduleUseCase,
+	listUseCase *report.ListSchedulesUseCase,
+	getUseCase *report.GetScheduleUseCase,
+	deleteUseCase *report.DeleteScheduleUseCase,
+	deliverUseCase *report.DeliverReportsUseCase,
+) *ReportScheduleHandler {
+	return &ReportScheduleHandler{
+		createUseCase:  createUseCase,
+		updateUseCase:  updateUseCase,
+		listUseCase:    listUseCase,
+		getUseCase:     getUseCase,
+		deleteUseCase:  deleteUseCase,
+		deliverUseCase: deliverUseCase,
+	}
+}
+
+// CreateSchedule handles POST /api/v1/report-schedules
+func (h *ReportScheduleHandler) CreateSchedule(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.ReportScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+	settings, err := reportSettings(req)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_request")
+	}
+
+	// Execute use case
+	schedule, err := h.createUseCase.Execute(c.Context(), report.ScheduleInput{
+		PartnerID: partnerID,
+		Livemode:  middleware.GetLivemode(c),
+		Settings:  settings,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_create_report_schedule")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(mapReportScheduleToDTO(schedule))
+}
+
+// UpdateSchedule handles PUT /api/v1/report-schedules/:id, replacing the
+// schedule's settings
+func (h *ReportScheduleHandler) UpdateSchedule(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondInvalidReportScheduleID(c)
+	}
+
+	// Parse request body
+	var req dto.ReportScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+	settings, err := reportSettings(req)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_request")
+	}
+
+	// Execute use case
+	schedule, err := h.updateUseCase.Execute(c.Context(), report.UpdateScheduleInput{
+		ScheduleInput: report.ScheduleInput{
+			PartnerID: partnerID,
+			Livemode:  middleware.GetLivemode(c),
+			Settings:  settings,
+			IPAddress: c.IP(),
+			UserAgent: c.Get("User-Agent"),
+		},
+		ScheduleID: scheduleID,
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_update_report_schedule")
+	}
+
+	return c.JSON(mapReportScheduleToDTO(schedule))
+}
+
+// ListSchedules handles GET /api/v1/report-schedules
+func (h *ReportScheduleHandler) ListSchedules(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	schedules, err := h.listUseCase.Execute(c.Context(), partnerID, middleware.GetLivemode(c))
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_report_schedules")
+	}
+
+	response := dto.ListReportSchedulesResponse{
+		Schedules: make([]dto.ReportScheduleResponse, len(schedules)),
+	}
+	for i, schedule := range schedules {
+		response.Schedules[i] = mapReportScheduleToDTO(schedule)
+	}
+	return c.JSON(response)
+}
+
+// GetSchedule handles GET /api/v1/report-schedules/:id
+func (h *ReportScheduleHandler) GetSchedule(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondInvalidReportScheduleID(c)
+	}
+
+	// Execute use case
+	schedule, err := h.getUseCase.Execute(c.Context(), partnerID, middleware.GetLivemode(c), scheduleID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_report_schedule")
+	}
+
+	return c.JSON(mapReportScheduleToDTO(schedule))
+}
+
+// DeleteSchedule handles DELETE /api/v1/report-schedules/:id
+func (h *ReportScheduleHandler) DeleteSchedule(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondInvalidReportScheduleID(c)
+	}
+
+	// Execute use case
+	err = h.deleteUseCase.Execute(c.Context(), report.DeleteScheduleInput{
+		PartnerID:  partnerID,
+		Livemode:   middleware.GetLivemode(c),
+		ScheduleID: scheduleID,
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_delete_report_schedule")
+	}
+
+	return c.JSON(fiber.Map{"message": "report schedule deleted"})
+}
+
+// RunSchedule handles POST /api/v1/report-schedules/:id/run, delivering
+// the report of the last period now. A delivery failing is answered with
+// why, so partners can check a destination before its first run.
+func (h *ReportScheduleHandler) RunSchedule(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondInvalidReportScheduleID(c)
+	}
+
+	// Execute use case
+	schedule, err := h.deliverUseCase.RunNow(c.Context(), report.RunNowInput{
+		PartnerID:  partnerID,
+		Livemode:   middleware.GetLivemode(c),
+		ScheduleID: scheduleID,
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_run_report_schedule")
+	}
+
+	return c.JSON(mapReportScheduleToDTO(schedule))
+}
+
+// reportSettings returns the settings of a schedule request. Filters and
+// columns are checked as an export's are.
+func reportSettings(req dto.ReportScheduleRequest) (entities.ReportSettings, error) {
+	if req.Format == "" {
+		req.Format = exportFormatCSV
+	}
+
+	settings := entities.ReportSettings{
+		Name:      req.Name,
+		Resource:  entities.ReportResource(req.Resource),
+		Format:    entities.ExportFormat(req.Format),
+		Layout:    entities.ExportLayout{Columns: req.Columns, Timezone: req.Timezone},
+		Frequency: entities.ReportFrequency(req.Frequency),
+		Hour:      req.Hour,
+		Destination: valueobjects.ReportDestination{
+			Type:       valueobjects.ReportDestinationType(req.Destination.Type),
+			Recipients: req.Destination.Recipients,
+			Host:       req.Destination.Host,
+			Port:       req.Destination.Port,
+			Username:   req.Destination.Username,
+			HostKey:    req.Destination.HostKey,
+			Directory:  req.Destination.Directory,
+			Password:   req.Destination.Password,
+			PrivateKey: req.Destination.PrivateKey,
+			Bucket:     req.Destination.Bucket,
+			Region:     req.Destination.Region,
+			Prefix:     req.Destination.Prefix,
+			RoleARN:    req.Destination.RoleARN,
+		},
+	}
+
+	resource := entities.ExportResource(req.Resource)
+	if resource.IsValid() {
+		filters, err := exportFilters(resource, req.Filters)
+		if err != nil {
+			return settings, err
+		}
+		settings.Filters = filters
+		if _, err := newExportLayout(exportColumns(resource), settings.Layout); err != nil {
+			return settings, err
+		}
+	}
+	return settings, nil
+}
+
+// respondInvalidReportScheduleID answers a malformed schedule ID
+func respondInvalidReportScheduleID(c *fiber.Ctx) error {
+	return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+		Error:   "invalid_report_schedule_id",
+		Message: "invalid report schedule ID format",
+	})
+}
+
+// mapReportScheduleToDTO maps a report schedule to its response, without
+// SFTP credentials
+func mapReportScheduleToDTO(schedule *entities.ReportSchedule) dto.ReportScheduleResponse {
+	filters := schedule.Filters
+	currency := valueobjects.Currency(filters.Currency)
+	destination := schedule.Destination
+	response := dto.ReportScheduleResponse{
+		ID:       schedule.ID.String(),
+		Name:     schedule.Name,
+		Resource: string(schedule.Resource),
+		Format:   string(schedule.Format),
+		Filters: dto.ReportFiltersResponse{
+			Status:   strings.Join(filters.Statuses, ","),
+			Currency: filters.Currency,
+			Metadata: filters.Metadata,
+		},
+		Columns:   schedule.Layout.Columns,
+		Timezone:  schedule.Layout.Timezone,
+		Frequency: string(schedule.Frequency),
+		Hour:      schedule.Hour,
+		Destination: dto.ReportDestinationResponse{
+			Type:       string(destination.Type),
+			Recipients: destination.Recipients,
+			Host:       destination.Host,
+			Port:       destination.Port,
+			Username:   destination.Username,
+			HostKey:    destination.HostKey,
+			Directory:  destination.Directory,
+			Auth:       destination.Auth,
+			Bucket:     destination.Bucket,
+			Region:     destination.Region,
+			Prefix:     destination.Prefix,
+			RoleARN:    destination.RoleARN,
+		},
+		NextRunAt:  schedule.NextRunAt,
+		LastRunAt:  schedule.LastRunAt,
+		LastStatus: string(schedule.LastStatus),
+		CreatedAt:  schedule.CreatedAt,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+	if filters.MinAmount != nil {
+		response.Filters.AmountMin = valueobjects.FormatMinorUnits(*filters.MinAmount, currency)
+	}
+	if filters.MaxAmount != nil {
+		response.Filters.AmountMax = valueobjects.FormatMinorUnits(*filters.MaxAmount, currency)
+	}
+	if filters.TransactionID != nil {
+		response.Filters.TransactionID = filters.TransactionID.String()
+	}
+	// Why a run failed stays with the schedule, as it may be a database
+	// error; running the schedule answers a delivery's failure
+	if schedule.LastStatus == entities.ReportRunFailed {
+		response.Error = "the last report could not be delivered; run the schedule to see why"
+	}
+	return response
+}
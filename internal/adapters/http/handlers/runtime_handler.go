@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RuntimeTuner reads and changes the runtime settings of the process
+type RuntimeTuner interface {
+	Settings() ports.RuntimeSettings
+	Update(update ports.RuntimeSettingsUpdate, changedBy string) (ports.RuntimeSettings, error)
+}
+
+// RuntimeHandler handles back-office requests tuning the running process
+type RuntimeHandler struct {
+	tuner RuntimeTuner
+}
+
+// NewRuntimeHandler creates a new runtime handler
+func NewRuntimeHandler(tuner RuntimeTuner) *RuntimeHandler {
+	return &RuntimeHandler{tuner: tuner}
+}
+
+// GetSettings handles GET /api/v1/admin/runtime
+func (h *RuntimeHandler) GetSettings(c *fiber.Ctx) error {
+	return c.JSON(runtimeSettingsResponse(h.tuner.Settings()))
+}
+
+// UpdateSettings handles PATCH /api/v1/admin/runtime. Only the instance that
+// receives the request changes.
+func (h *RuntimeHandler) UpdateSettings(c *fiber.Ctx) error {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	var req dto.RuntimeSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	settings, err := h.tuner.Update(ports.RuntimeSettingsUpdate{
+		LogLevel:             req.LogLevel,
+		GCPercent:            req.GCPercent,
+		BlockProfileRate:     req.BlockProfileRate,
+		MutexProfileFraction: req.MutexProfileFraction,
+	}, adminID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_runtime_settings")
+	}
+	return c.JSON(runtimeSettingsResponse(settings))
+}
+
+func runtimeSettingsResponse(settings ports.RuntimeSettings) dto.RuntimeSettingsResponse {
+	return dto.RuntimeSettingsResponse{
+		LogLevel:             settings.LogLevel,
+		GCPercent:            settings.GCPercent,
+		BlockProfileRate:     settings.BlockProfileRate,
+		MutexProfileFraction: settings.MutexProfileFraction,
+	}
+}
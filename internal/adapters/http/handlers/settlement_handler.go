@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/settlement"
+)
+
+// SettlementHandler handles dispute and settlement reconciliation HTTP requests
+type SettlementHandler struct {
+	listDisputesUseCase      *settlement.ListDisputesUseCase
+	listDiscrepanciesUseCase *settlement.ListDiscrepanciesUseCase
+}
+
+// NewSettlementHandler creates a new settlement handler
+func NewSettlementHandler(
+	listDisputesUseCase *settlement.ListDisputesUseCase,
+	listDiscrepanciesUseCase *settlement.ListDiscrepanciesUseCase,
+) *SettlementHandler {
+	return &SettlementHandler{
+		listDisputesUseCase:      listDisputesUseCase,
+		listDiscrepanciesUseCase: listDiscrepanciesUseCase,
+	}
+}
+
+// ListDisputes handles GET /api/v1/disputes
+func (h *SettlementHandler) ListDisputes(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	var req dto.ListDisputesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	req.Limit, req.Offset = pageBounds(req.Limit, req.Offset)
+
+	// Execute use case
+	disputes, total, err := h.listDisputesUseCase.Execute(c.Context(), partnerID, req.Limit, req.Offset)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_disputes")
+	}
+
+	response := dto.ListDisputesResponse{
+		Disputes: make([]dto.DisputeResponse, len(disputes)),
+		Total:    total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	for i, dispute := range disputes {
+		response.Disputes[i] = mapDisputeToDTO(dispute)
+	}
+	return c.JSON(response)
+}
+
+// ListDiscrepancies handles GET /api/v1/admin/settlements/discrepancies
+func (h *SettlementHandler) ListDiscrepancies(c *fiber.Ctx) error {
+	req, query, err := parseDiscrepancyQuery(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Execute use case
+	entries, total, err := h.listDiscrepanciesUseCase.Execute(c.Context(), query)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_settlement_discrepancies")
+	}
+
+	response := dto.ListSettlementDiscrepanciesResponse{
+		Discrepancies: make([]dto.SettlementDiscrepancyResponse, len(entries)),
+		Total:         total,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	}
+	for i, entry := range entries {
+		response.Discrepancies[i] = mapDiscrepancyToDTO(entry)
+	}
+	return c.JSON(response)
+}
+
+// parseDiscrepancyQuery reads the discrepancy list query parameters
+func parseDiscrepancyQuery(c *fiber.Ctx) (dto.ListSettlementDiscrepanciesRequest, ports.SettlementDiscrepancyQuery, error) {
+	var req dto.ListSettlementDiscrepanciesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return req, ports.SettlementDiscrepancyQuery{}, err
+	}
+	req.Limit, req.Offset = pageBounds(req.Limit, req.Offset)
+
+	query := ports.SettlementDiscrepancyQuery{
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	if req.Provider != "" {
+		provider, err := valueobjects.NewPaymentProvider(req.Provider)
+		if err != nil {
+			return req, query, err
+		}
+		query.Provider = &provider
+	}
+
+	if req.PartnerID != "" {
+		partnerID, err := uuid.Parse(req.PartnerID)
+		if err != nil {
+			return req, query, errors.NewValidationError("partner_id", "must be a partner ID")
+		}
+		query.PartnerID = &partnerID
+	}
+
+	if req.DateFrom != "" {
+		from, err := time.Parse("2006-01-02", req.DateFrom)
+		if err != nil {
+			return req, query, errors.NewValidationError("date_from", "must be YYYY-MM-DD")
+		}
+		query.ReconciledFrom = &from
+	}
+	if req.DateTo != "" {
+		to, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return req, query, errors.NewValidationError("date_to", "must be YYYY-MM-DD")
+		}
+		// date_to includes the whole day
+		to = to.AddDate(0, 0, 1)
+		query.ReconciledTo = &to
+	}
+
+	return req, query, nil
+}
+
+// pageBounds applies the default and maximum page size of listings
+func pageBounds(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// mapDisputeToDTO maps a dispute
+func mapDisputeToDTO(dispute *entities.Dispute) dto.DisputeResponse {
+	return dto.DisputeResponse{
+		ID:                dispute.ID.String(),
+		TransactionID:     dispute.TransactionID.String(),
+		Provider:          dispute.Provider.String(),
+		ProviderReference: dispute.ProviderReference,
+		Amount:            dispute.Amount.Decimal(),
+		Currency:          dispute.Amount.Currency.String(),
+		Reason:            dispute.Reason,
+		Status:            string(dispute.Status),
+		CreatedAt:         dispute.CreatedAt,
+		UpdatedAt:         dispute.UpdatedAt,
+		ReversedAt:        dispute.ReversedAt,
+	}
+}
+
+// mapDiscrepancyToDTO maps a settlement entry with a discrepancy
+func mapDiscrepancyToDTO(entry *entities.SettlementEntry) dto.SettlementDiscrepancyResponse {
+	response := dto.SettlementDiscrepancyResponse{
+		ID:                    entry.ID.String(),
+		Provider:              entry.Provider.String(),
+		Reference:             entry.Reference,
+		Type:                  string(entry.Type),
+		ProviderTransactionID: entry.ProviderTransactionID,
+		Amount:                entry.Amount.Decimal(),
+		Fee:                   entry.Fee.Decimal(),
+		Currency:              entry.Amount.Currency.String(),
+		Reason:                entry.Reason,
+		OccurredAt:            entry.OccurredAt,
+		Source:                entry.Source,
+		Discrepancy:           string(entry.Discrepancy),
+		Detail:                entry.DiscrepancyDetail,
+		ReconciledAt:          entry.ReconciledAt,
+	}
+	if entry.TransactionID != nil {
+		response.TransactionID = entry.TransactionID.String()
+		response.PartnerID = entry.PartnerID.String()
+	}
+	return response
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/usecases/health"
+)
+
+// SLOHandler serves the service levels the status page shows
+type SLOHandler struct {
+	getSLOSummaryUC *health.GetSLOSummaryUseCase
+}
+
+// NewSLOHandler creates a new SLO handler
+func NewSLOHandler(getSLOSummaryUC *health.GetSLOSummaryUseCase) *SLOHandler {
+	return &SLOHandler{getSLOSummaryUC: getSLOSummaryUC}
+}
+
+// Summary handles GET /slo: payment success rate, P95 processing latency and
+// webhook delivery success, overall, per provider and per partner, over the
+// last hour and day. ?livemode=false summarizes sandbox payments instead.
+func (h *SLOHandler) Summary(c *fiber.Ctx) error {
+	livemode := true
+	if raw := c.Query("livemode"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "livemode must be true or false",
+			})
+		}
+		livemode = parsed
+	}
+
+	windows, err := h.getSLOSummaryUC.Execute(c.Context(), livemode)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "failed_to_summarize_slo",
+			Message: "Failed to summarize service levels",
+		})
+	}
+
+	response := dto.SLOResponse{
+		Livemode:    livemode,
+		GeneratedAt: time.Now(),
+		Windows:     make([]dto.SLOWindow, len(windows)),
+	}
+	for i, window := range windows {
+		out := dto.SLOWindow{
+			Window:    windowName(window.Window),
+			Since:     window.Since,
+			Payments:  paymentLevels(window.Payments),
+			Webhooks:  webhookLevels(window.Webhooks),
+			Providers: make([]dto.ProviderLevels, len(window.Providers)),
+			Partners:  make([]dto.PartnerLevels, len(window.Partners)),
+		}
+		for j, provider := range window.Providers {
+			out.Providers[j] = dto.ProviderLevels{
+				Provider: string(provider.Provider),
+				Payments: paymentLevels(provider.Payments),
+			}
+		}
+		for j, partner := range window.Partners {
+			out.Partners[j] = dto.PartnerLevels{
+				PartnerID: partner.PartnerID.String(),
+				Payments:  paymentLevels(partner.Payments),
+				Webhooks:  webhookLevels(partner.Webhooks),
+			}
+		}
+		response.Windows[i] = out
+	}
+	return c.JSON(response)
+}
+
+// windowName names a window like 1h or 24h
+func windowName(window time.Duration) string {
+	return strings.TrimSuffix(strings.TrimSuffix(window.String(), "0s"), "0m")
+}
+
+func paymentLevels(levels health.PaymentLevels) dto.PaymentLevels {
+	out := dto.PaymentLevels{
+		Succeeded:   levels.Succeeded,
+		Failed:      levels.Failed,
+		SuccessRate: levels.SuccessRate,
+	}
+	if levels.P95Latency != nil {
+		ms := levels.P95Latency.Milliseconds()
+		out.P95LatencyMs = &ms
+	}
+	return out
+}
+
+func webhookLevels(levels health.WebhookLevels) dto.WebhookLevels {
+	return dto.WebhookLevels{
+		Delivered:   levels.Delivered,
+		Failing:     levels.Failing,
+		SuccessRate: levels.SuccessRate,
+	}
+}
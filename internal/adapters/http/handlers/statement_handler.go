@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/export"
+	"Pay2Go/internal/usecases/partner"
+	"Pay2Go/internal/usecases/statement"
+)
+
+// StatementHandler handles requests for partners' monthly statements and
+// the pricing they charge
+type StatementHandler struct {
+	listUseCase          *statement.ListStatementsUseCase
+	getUseCase           *statement.GetStatementUseCase
+	generateUseCase      *statement.GenerateStatementUseCase
+	getPartnerUseCase    *partner.GetPartnerUseCase
+	updatePricingUseCase *partner.UpdatePartnerPricingUseCase
+	writer               *export.Writer
+}
+
+// NewStatementHandler creates a new statement handler
+func NewStatementHandler(
+	listUseCase *statement.ListStatementsUseCase,
+	getUseCase *statement.GetStatementUseCase,
+	generateUseCase *statement.GenerateStatementUseCase,
+	getPartnerUseCase *partner.GetPartnerUseCase,
+	updatePricingUseCase *partner.UpdatePartnerPricingUseCase,
+	writer *export.Writer,
+) *StatementHandler {
+	return &StatementHandler{
+		listUseCase:          listUseCase,
+		getUseCase:           getUseCase,
+		generateUseCase:      generateUseCase,
+		getPartnerUseCase:    getPartnerUseCase,
+		updatePricingUseCase: updatePricingUseCase,
+		writer:               writer,
+	}
+}
+
+// ListStatements handles GET /api/v1/statements
+func (h *StatementHandler) ListStatements(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	var req dto.ListStatementsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	req.Limit, req.Offset = pageBounds(req.Limit, req.Offset)
+
+	// Execute use case
+	statements, total, err := h.listUseCase.Execute(c.Context(), partnerID, req.Limit, req.Offset)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_statements")
+	}
+
+	return c.JSON(mapStatementsToDTO(statements, total, req.Limit, req.Offset))
+}
+
+// GetStatement handles GET /api/v1/statements/:id
+func (h *StatementHandler) GetStatement(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse statement ID from URL
+	statementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_statement_id",
+			Message: "invalid statement ID format",
+		})
+	}
+
+	// Execute use case
+	s, err := h.getUseCase.Execute(c.Context(), partnerID, statementID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_statement")
+	}
+
+	return c.JSON(mapStatementToDTO(s))
+}
+
+// DownloadStatement handles GET /api/v1/statements/:id/download
+func (h *StatementHandler) DownloadStatement(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	return h.download(c, partnerID)
+}
+
+// AdminDownloadStatement handles GET /api/v1/admin/statements/:id/download
+func (h *StatementHandler) AdminDownloadStatement(c *fiber.Ctx) error {
+	return h.download(c, uuid.Nil)
+}
+
+// download sends the CSV document of the statement in the path, which
+// partnerID must own unless it is uuid.Nil, or its fees in the accounting
+// format asked for
+func (h *StatementHandler) download(c *fiber.Ctx, partnerID uuid.UUID) error {
+	// Parse statement ID from URL
+	statementID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_statement_id",
+			Message: "invalid statement ID format",
+		})
+	}
+
+	var req dto.DownloadStatementRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	format := entities.ExportFormat(req.Format)
+	if format != "" && format != entities.ExportFormatCSV {
+		if !format.IsAccounting() {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_query_parameters",
+				Message: "format must be csv, quickbooks_iif or xero_csv",
+			})
+		}
+		return h.downloadFees(c, partnerID, statementID, format)
+	}
+
+	// Execute use case
+	s, document, err := h.getUseCase.Document(c.Context(), partnerID, statementID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_download_statement")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="statement-`+s.Period.String()+"-"+s.PartnerID.String()+`.csv"`)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=3600")
+	return c.Send(document)
+}
+
+// downloadFees sends the fees of statement statementID in format, posted to
+// the accounts of the statement's partner
+func (h *StatementHandler) downloadFees(c *fiber.Ctx, partnerID, statementID uuid.UUID, format entities.ExportFormat) error {
+	s, err := h.getUseCase.Execute(c.Context(), partnerID, statementID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_download_statement")
+	}
+
+	var buf bytes.Buffer
+	if err := h.writer.WriteStatement(c.Context(), &buf, s, format); err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_download_statement")
+	}
+
+	c.Set(fiber.HeaderContentType, fileContentType(format))
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="statement-`+s.Period.String()+"-"+s.PartnerID.String()+"-fees."+format.Extension()+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=3600")
+	return c.Send(buf.Bytes())
+}
+
+// ListPeriodStatements handles GET /api/v1/admin/statements, every
+// partner's statement of a month for invoicing
+func (h *StatementHandler) ListPeriodStatements(c *fiber.Ctx) error {
+	var req dto.ListPeriodStatementsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	period, err := entities.NewStatementPeriod(req.Period)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	req.Limit, req.Offset = pageBounds(req.Limit, req.Offset)
+
+	// Execute use case
+	statements, total, err := h.listUseCase.ByPeriod(c.Context(), period, req.Limit, req.Offset)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_statements")
+	}
+
+	return c.JSON(mapStatementsToDTO(statements, total, req.Limit, req.Offset))
+}
+
+// GenerateStatement handles POST /api/v1/admin/partners/:id/statements. A
+// statement already issued is returned as it is.
+func (h *StatementHandler) GenerateStatement(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.GenerateStatementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	s, err := h.generateUseCase.Execute(c.Context(), partnerID, entities.StatementPeriod(req.Period))
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_generate_statement")
+	}
+
+	return c.JSON(mapStatementToDTO(s))
+}
+
+// GetPricing handles GET /api/v1/admin/partners/:id/pricing
+func (h *StatementHandler) GetPricing(c *fiber.Ctx) error {
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Execute use case
+	p, err := h.getPartnerUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_partner")
+	}
+
+	return partnerJSON(c, p, h.mapPartnerPricingToDTO(p))
+}
+
+// UpdatePricing handles PUT /api/v1/admin/partners/:id/pricing
+func (h *StatementHandler) UpdatePricing(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse partner ID
+	partnerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_partner_id",
+			Message: "invalid partner ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdatePartnerPricingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	tiers := make([]valueobjects.PricingTier, len(req.Tiers))
+	for i, tier := range req.Tiers {
+		tiers[i] = valueobjects.PricingTier(tier)
+	}
+
+	// Execute use case
+	p, err := h.updatePricingUseCase.Execute(expectVersion(c), partner.UpdatePartnerPricingInput{
+		PartnerID:     partnerID,
+		Tiers:         tiers,
+		ChargebackFee: req.ChargebackFee,
+		AdminID:       adminID,
+		IPAddress:     c.IP(),
+		UserAgent:     c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "partner_update_failed")
+	}
+
+	return partnerJSON(c, p, h.mapPartnerPricingToDTO(p))
+}
+
+// mapPartnerPricingToDTO maps the pricing a partner's statements charge to
+// its response DTO
+func (h *StatementHandler) mapPartnerPricingToDTO(p *entities.Partner) dto.PartnerPricingResponse {
+	pricing, standard := h.generateUseCase.PricingOf(p)
+	return dto.PartnerPricingResponse{
+		PartnerID:       p.ID.String(),
+		Standard:        standard,
+		PricingResponse: mapPricingToDTO(pricing),
+	}
+}
+
+// mapPricingToDTO maps a pricing to its response DTO
+func mapPricingToDTO(pricing valueobjects.Pricing) dto.PricingResponse {
+	response := dto.PricingResponse{
+		Tiers:         make([]dto.PricingTierRequest, len(pricing.Tiers)),
+		ChargebackFee: pricing.ChargebackFee,
+	}
+	for i, tier := range pricing.Tiers {
+		response.Tiers[i] = dto.PricingTierRequest(tier)
+	}
+	return response
+}
+
+// mapStatementsToDTO maps a page of statements
+func mapStatementsToDTO(statements []*entities.Statement, total int64, limit, offset int) dto.ListStatementsResponse {
+	response := dto.ListStatementsResponse{
+		Statements: make([]dto.StatementResponse, len(statements)),
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}
+	for i, s := range statements {
+		response.Statements[i] = mapStatementToDTO(s)
+	}
+	return response
+}
+
+// mapStatementToDTO maps a statement; amounts are in major units of their
+// currency
+func mapStatementToDTO(s *entities.Statement) dto.StatementResponse {
+	response := dto.StatementResponse{
+		ID:             s.ID.String(),
+		PartnerID:      s.PartnerID.String(),
+		Period:         s.Period.String(),
+		Pricing:        mapPricingToDTO(s.Pricing),
+		RoundingPolicy: s.RoundingPolicy.String(),
+		Currencies:     make([]dto.StatementCurrencyResponse, len(s.Currencies)),
+		CreatedAt:      s.CreatedAt,
+	}
+	for i, line := range s.Currencies {
+		total := func(t entities.StatementTotal) dto.StatementTotalResponse {
+			return dto.StatementTotalResponse{Count: t.Count, Amount: valueobjects.FormatMinorUnits(t.Amount, line.Currency)}
+		}
+		fees := make([]dto.StatementTierFeeResponse, len(line.TierFees))
+		for j, tierFee := range line.TierFees {
+			fees[j] = dto.StatementTierFeeResponse{
+				Tier:     tierFee.Tier,
+				Percent:  tierFee.Percent,
+				Fixed:    valueobjects.FormatMinorUnits(tierFee.Fixed, line.Currency),
+				Payments: total(tierFee.Payments),
+				Fee:      valueobjects.FormatMinorUnits(tierFee.Fee, line.Currency),
+			}
+		}
+		response.Currencies[i] = dto.StatementCurrencyResponse{
+			Currency:            line.Currency.String(),
+			Payments:            total(line.Payments),
+			Refunds:             total(line.Refunds),
+			Chargebacks:         total(line.Chargebacks),
+			ChargebackReversals: total(line.ChargebackReversals),
+			Fees:                fees,
+			ChargebackFees:      total(line.ChargebackFees),
+			TotalFees:           valueobjects.FormatMinorUnits(line.TotalFees, line.Currency),
+			Net:                 valueobjects.FormatMinorUnits(line.Net, line.Currency),
+		}
+	}
+	return response
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// maxExpandedRefunds bounds the refunds embedded in a transaction
+const maxExpandedRefunds = 100
+
+// transactionExpansions are the related resources a request asks to embed
+// in transaction responses, with ?expand=refunds,customer
+type transactionExpansions struct {
+	refunds  bool
+	customer bool
+}
+
+// parseExpand reads the expand query parameter
+func parseExpand(c *fiber.Ctx) (transactionExpansions, error) {
+	var expand transactionExpansions
+	for _, name := range strings.Split(c.Query("expand"), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "refunds":
+			expand.refunds = true
+		case "customer":
+			expand.customer = true
+		default:
+			return expand, errors.NewValidationError("expand", "cannot expand "+name+", only refunds and customer")
+		}
+	}
+	return expand, nil
+}
+
+// transactionRefunds reads the refunds of a transaction to embed, newest
+// first, and whether it has more
+func transactionRefunds(ctx context.Context, listRefundsUseCase *transaction.ListRefundsUseCase, txn *entities.Transaction) ([]*entities.Refund, bool, error) {
+	refunds, err := listRefundsUseCase.Execute(ctx, txn.PartnerID, txn.Livemode, ports.RefundQuery{
+		TransactionID: &txn.ID,
+		Limit:         maxExpandedRefunds,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return refunds, len(refunds) == maxExpandedRefunds, nil
+}
+
+// mapCustomerToDTO maps the customer of a transaction to its response DTO
+func mapCustomerToDTO(txn *entities.Transaction) *dto.CustomerResponse {
+	return &dto.CustomerResponse{
+		Email:          txn.CustomerEmail,
+		Name:           txn.CustomerName,
+		Phone:          txn.CustomerPhone,
+		BillingCountry: txn.BillingCountry.String(),
+	}
+}
+
+// transactionLinks are the links of a transaction under base, such as
+// /api/v1/transactions: itself, and the actions its status allows.
+// processAction is the action that processes a pending transaction.
+func transactionLinks(base string, txn *entities.Transaction, processAction string) map[string]dto.Link {
+	self := base + "/" + txn.ID.String()
+	links := map[string]dto.Link{
+		"self": {Href: self, Method: fiber.MethodGet},
+	}
+	if txn.IsPending() {
+		links[processAction] = dto.Link{Href: self + "/" + processAction, Method: fiber.MethodPost}
+	}
+	if txn.IsRefundable() {
+		links["refund"] = dto.Link{Href: self + "/refund", Method: fiber.MethodPost}
+	}
+	return links
+}
+
+// pageLinks are the links of a page of a list at path: itself with the
+// query the client sent and, unless nextCursor is empty, the next page,
+// which starts after it
+func pageLinks(c *fiber.Ctx, path, nextCursor string) map[string]dto.Link {
+	query := url.Values{}
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		query.Add(string(key), string(value))
+	})
+	links := map[string]dto.Link{
+		"self": {Href: withQuery(path, query), Method: fiber.MethodGet},
+	}
+	if nextCursor != "" {
+		query.Set("starting_after", nextCursor)
+		query.Del("offset")
+		links["next"] = dto.Link{Href: withQuery(path, query), Method: fiber.MethodGet}
+	}
+	return links
+}
+
+// withQuery appends query to path, if there is any
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Export formats
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+// exportContentTypes are the content types of the export formats
+var exportContentTypes = map[string]string{
+	exportFormatCSV:    "text/csv; charset=utf-8",
+	exportFormatNDJSON: "application/x-ndjson",
+}
+
+// accountingContentTypes are the content types of the accounting formats,
+// which export jobs and statements are written in but not
+// GET /transactions/export
+var accountingContentTypes = map[entities.ExportFormat]string{
+	// QuickBooks reads IIF files as tab-separated text
+	entities.ExportFormatQuickBooksIIF: "text/plain; charset=utf-8",
+	entities.ExportFormatXeroCSV:       "text/csv; charset=utf-8",
+}
+
+// fileContentType is the content type of a file in format
+func fileContentType(format entities.ExportFormat) string {
+	if contentType, ok := accountingContentTypes[format]; ok {
+		return contentType
+	}
+	return exportContentTypes[string(format)]
+}
+
+// exportRow is a transaction or refund of an export: NDJSON encodes it as
+// the API returns it, CSV writes its record. Its times are in the zone of
+// the export.
+type exportRow interface {
+	csvRecord() []string
+}
+
+// exportLayout is an entities.ExportLayout checked against the columns of
+// the resource exported
+type exportLayout struct {
+	// columns are the indexes of the CSV columns written, in order
+	columns  []int
+	location *time.Location
+}
+
+// newExportLayout checks layout against all, the CSV columns of the
+// resource exported
+func newExportLayout(all []string, layout entities.ExportLayout) (exportLayout, error) {
+	location, err := layout.Location()
+	if err != nil {
+		return exportLayout{}, err
+	}
+	if len(layout.Columns) == 0 {
+		columns := make([]int, len(all))
+		for i := range all {
+			columns[i] = i
+		}
+		return exportLayout{columns: columns, location: location}, nil
+	}
+
+	index := make(map[string]int, len(all))
+	for i, column := range all {
+		index[column] = i
+	}
+	columns := make([]int, len(layout.Columns))
+	seen := make(map[string]bool, len(layout.Columns))
+	for i, column := range layout.Columns {
+		j, ok := index[column]
+		if !ok {
+			return exportLayout{}, errors.NewValidationError("columns", "unknown column "+column)
+		}
+		if seen[column] {
+			return exportLayout{}, errors.NewValidationError("columns", "column "+column+" is selected twice")
+		}
+		seen[column] = true
+		columns[i] = j
+	}
+	return exportLayout{columns: columns, location: location}, nil
+}
+
+// transactionExportLayout checks the columns and timezone query parameters
+// of GET /transactions/export, a comma-separated list and an IANA zone name
+func transactionExportLayout(format, columns, timezone string) (exportLayout, error) {
+	layout := entities.ExportLayout{Timezone: timezone}
+	if columns != "" {
+		if format != exportFormatCSV {
+			return exportLayout{}, errors.NewValidationError("columns", "only CSV exports select columns")
+		}
+		layout.Columns = strings.Split(columns, ",")
+	}
+	return newExportLayout(transactionCSVColumns, layout)
+}
+
+// exportColumns are the CSV columns of exports of resource
+func exportColumns(resource entities.ExportResource) []string {
+	if resource == entities.ExportResourceRefunds {
+		return refundCSVColumns
+	}
+	return transactionCSVColumns
+}
+
+// rowExport writes exported rows in one format
+type rowExport interface {
+	// write adds a row, failing once the client has gone away
+	write(row exportRow) error
+	// fail ends an export that could not be completed with an error line,
+	// since the response has already started
+	fail(err error)
+	// flush writes out what is buffered
+	flush()
+}
+
+// newRowExport returns the export of format writing to w; all are the CSV
+// columns of the resource exported, of which layout selects the ones
+// written
+func newRowExport(format string, w *bufio.Writer, all []string, layout exportLayout) rowExport {
+	if format == exportFormatNDJSON {
+		return &ndjsonExport{w: w, encoder: json.NewEncoder(w)}
+	}
+	export := &csvExport{w: csv.NewWriter(w), out: w, columns: layout.columns}
+	header := make([]string, len(layout.columns))
+	for i, j := range layout.columns {
+		header[i] = all[j]
+	}
+	_ = export.w.Write(header)
+	return export
+}
+
+// ndjsonExport writes a row as JSON per line
+type ndjsonExport struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExport) write(row exportRow) error {
+	return e.encoder.Encode(row)
+}
+
+func (e *ndjsonExport) fail(err error) {
+	_ = e.encoder.Encode(dto.ErrorResponse{Error: "export_failed", Message: err.Error()})
+}
+
+func (e *ndjsonExport) flush() {
+	_ = e.w.Flush()
+}
+
+// csvExport writes a record per row, quoting fields as RFC 4180 requires
+type csvExport struct {
+	w       *csv.Writer
+	out     *bufio.Writer
+	columns []int
+}
+
+func (e *csvExport) write(row exportRow) error {
+	record := row.csvRecord()
+	fields := make([]string, len(e.columns))
+	for i, j := range e.columns {
+		fields[i] = valueobjects.SpreadsheetCell(record[j])
+	}
+	return e.w.Write(fields)
+}
+
+// fail adds a row whose first field is "#error", which no ID can be
+func (e *csvExport) fail(err error) {
+	_ = e.w.Write([]string{"#error", "export_failed", err.Error()})
+}
+
+func (e *csvExport) flush() {
+	e.w.Flush()
+	_ = e.out.Flush()
+}
+
+// transactionCSVColumns is the header row of CSV exports of transactions
+var transactionCSVColumns = []string{
+	"id", "created_at", "status", "amount", "currency", "payment_method",
+	"provider", "provider_transaction_id", "customer_email", "customer_name",
+	"billing_country", "description", "idempotency_key", "error_code",
+	"livemode", "processed_at",
+}
+
+// transactionRow is an exported transaction
+type transactionRow struct {
+	dto.GetTransactionResponse
+}
+
+// newTransactionRow is txn as exported with times in location
+func newTransactionRow(txn *entities.Transaction, location *time.Location) transactionRow {
+	r := transactionRow{mapTransactionToDTO(txn)}
+	r.CreatedAt = r.CreatedAt.In(location)
+	r.UpdatedAt = r.UpdatedAt.In(location)
+	r.ProcessedAt = localExportTime(r.ProcessedAt, location)
+	return r
+}
+
+func (r transactionRow) csvRecord() []string {
+	return []string{
+		r.ID,
+		r.CreatedAt.Format(time.RFC3339),
+		r.Status,
+		r.Amount,
+		r.Currency,
+		r.PaymentMethod,
+		r.Provider,
+		r.ProviderTransactionID,
+		r.CustomerEmail,
+		r.CustomerName,
+		r.BillingCountry,
+		r.Description,
+		r.IdempotencyKey,
+		r.ErrorCode,
+		strconv.FormatBool(r.Livemode),
+		formatExportTime(r.ProcessedAt),
+	}
+}
+
+// refundCSVColumns is the header row of CSV exports of refunds
+var refundCSVColumns = []string{
+	"refund_id", "created_at", "transaction_id", "status", "amount", "currency",
+	"reason", "reason_note", "approved_by", "approved_at", "cancelled_at",
+}
+
+// refundRow is an exported refund
+type refundRow struct {
+	dto.RefundTransactionResponse
+}
+
+// newRefundRow is refund as exported with times in location
+func newRefundRow(refund *entities.Refund, location *time.Location) refundRow {
+	r := refundRow{mapRefundToDTO(refund)}
+	r.CreatedAt = r.CreatedAt.In(location)
+	r.ApprovedAt = localExportTime(r.ApprovedAt, location)
+	r.CancelledAt = localExportTime(r.CancelledAt, location)
+	return r
+}
+
+func (r refundRow) csvRecord() []string {
+	return []string{
+		r.RefundID,
+		r.CreatedAt.Format(time.RFC3339),
+		r.TransactionID,
+		r.Status,
+		r.Amount,
+		r.Currency,
+		r.Reason,
+		r.ReasonNote,
+		r.ApprovedBy,
+		formatExportTime(r.ApprovedAt),
+		formatExportTime(r.CancelledAt),
+	}
+}
+
+// formatExportTime formats an optional time for CSV, empty when unset
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// localExportTime is an optional time in location
+func localExportTime(t *time.Time, location *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(location)
+	return &local
+}
+
+// ExportEncoder writes the files of export jobs in the formats of
+// GET /transactions/export
+type ExportEncoder struct{}
+
+// NewExportEncoder creates an encoder of export files
+func NewExportEncoder() *ExportEncoder {
+	return &ExportEncoder{}
+}
+
+// NewFile starts a file of resource in format and layout, written to w
+func (ExportEncoder) NewFile(w io.Writer, resource entities.ExportResource, format entities.ExportFormat, layout entities.ExportLayout) (ports.ExportFile, error) {
+	columns := exportColumns(resource)
+	checked, err := newExportLayout(columns, layout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export layout: %w", err)
+	}
+	out := bufio.NewWriter(w)
+	return &exportFile{export: newRowExport(string(format), out, columns, checked), out: out, location: checked.location}, nil
+}
+
+// exportFile is the file of an export job
+type exportFile struct {
+	export   rowExport
+	out      *bufio.Writer
+	location *time.Location
+}
+
+func (f *exportFile) WriteTransaction(txn *entities.Transaction) error {
+	return f.export.write(newTransactionRow(txn, f.location))
+}
+
+func (f *exportFile) WriteRefund(refund *entities.Refund) error {
+	return f.export.write(newRefundRow(refund, f.location))
+}
+
+func (f *exportFile) Close() error {
+	f.export.flush()
+	return f.out.Flush()
+}
+
+// detachTransactionQuery copies the strings of query that may point into
+// the request's buffers, which are reused once the handler returns while
+// an export is still streaming
+func detachTransactionQuery(query ports.TransactionQuery) ports.TransactionQuery {
+	statuses := make([]entities.TransactionStatus, len(query.Statuses))
+	for i, status := range query.Statuses {
+		statuses[i] = entities.TransactionStatus(strings.Clone(string(status)))
+	}
+	query.Statuses = statuses
+	if query.Currency != nil {
+		currency := valueobjects.Currency(strings.Clone(query.Currency.String()))
+		query.Currency = &currency
+	}
+	return query
+}
@@ -1,344 +1,807 @@
-package handlers
 // Package handlers contains HTTP request handlers
 package handlers
 
 import (
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return filter	}		filter.DateTo = &req.DateTo	if req.DateTo != "" {	}		filter.DateFrom = &req.DateFrom	if req.DateFrom != "" {	}		filter.Status = &status		status := entities.TransactionStatus(req.Status)	if req.Status != "" {	}		Offset: req.Offset,		Limit:  req.Limit,	filter := transaction.TransactionFilter{func buildTransactionFilter(req dto.ListTransactionsRequest) transaction.TransactionFilter {}	}		ProcessedAt:           txn.ProcessedAt,		UpdatedAt:             txn.UpdatedAt,		CreatedAt:             txn.CreatedAt,		ErrorMessage:          txn.ErrorMessage,		ErrorCode:             txn.ErrorCode,		Metadata:              txn.Metadata,		Description:           txn.Description,		CustomerPhone:         txn.CustomerPhone,		CustomerName:          txn.CustomerName,		CustomerEmail:         txn.CustomerEmail,		Status:                string(txn.Status),		ProviderTransactionID: txn.ProviderTransactionID,		Provider:              txn.Provider.String(),		PaymentMethod:         txn.PaymentMethod.String(),		Currency:              txn.Amount.Currency.String(),		Amount:                txn.Amount.Amount,		IdempotencyKey:        txn.IdempotencyKey,		PartnerID:             txn.PartnerID.String(),		ID:                    txn.ID.String(),	return dto.GetTransactionResponse{func (h *TransactionHandler) mapTransactionToDTO(txn *entities.Transaction) dto.GetTransactionResponse {// Helper functions}	return c.Status(fiber.StatusCreated).JSON(response)	}		CreatedAt:     refund.CreatedAt,		Reason:        refund.Reason,		Status:        string(refund.Status),		Currency:      refund.Amount.Currency.String(),		Amount:        refund.Amount.Amount,		TransactionID: refund.TransactionID.String(),		RefundID:      refund.ID.String(),	response := dto.RefundTransactionResponse{	// Return response	}		})			Message: err.Error(),			Error:   "refund_failed",		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{	if err != nil {	refund, err := h.refundUseCase.Execute(c.Context(), input)	// Execute use case	}		UserAgent:     c.Get("User-Agent"),		IPAddress:     c.IP(),		Reason:        req.Reason,		Currency:      req.Currency,		Amount:        req.Amount,		PartnerID:     partnerID,		TransactionID: txnID,	input := transaction.RefundTransactionInput{	// Create use case input	}		})			Message: "invalid request body",			Error:   "invalid_request",		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{	if err := c.BodyParser(&req); err != nil {	var req dto.RefundTransactionRequest	// Parse request body	}		})			Message: "invalid transaction ID format",			Error:   "invalid_transaction_id",		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{	if err != nil {	txnID, err := uuid.Parse(txnIDStr)	txnIDStr := c.Params("id")	// Parse transaction ID	}		})			Message: "authentication required",			Error:   "unauthorized",		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{	if err != nil {	partnerID, err := middleware.GetPartnerID(c)	// Get partner IDfunc (h *TransactionHandler) RefundTransaction(c *fiber.Ctx) error {// RefundTransaction handles POST /api/v1/transactions/:id/refund}	})		"message": "payment processing initiated",	return c.JSON(fiber.Map{	}		})			Message: err.Error(),			Error:   "payment_processing_failed",		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{	if err := h.processTxnUseCase.Execute(c.Context(), txnID); err != nil {	// Execute use case	}		})			Message: "invalid transaction ID format",			Error:   "invalid_transaction_id",		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{	if err != nil {	txnID, err := uuid.Parse(txnIDStr)	txnIDStr := c.Params("id")	// Parse transaction ID	}		})			Message: "authentication required",			Error:   "unauthorized",		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{	if err != nil {	_, err := middleware.GetPartnerID(c)	// Get partner IDfunc (h *TransactionHandler) ProcessPayment(c *fiber.Ctx) error {// ProcessPayment handles POST /api/v1/transactions/:id/process}	return c.JSON(response)	}		Offset:       req.Offset,		Limit:        req.Limit,		Total:        total,		Transactions: txnDTOs,	response := dto.ListTransactionsResponse{	}		txnDTOs[i] = h.mapTransactionToDTO(txn)	for i, txn := range transactions {	txnDTOs := make([]dto.GetTransactionResponse, len(transactions))	// Map to response DTOs	}		})			Message: err.Error(),			Error:   "failed_to_list_transactions",		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{	if err != nil {	transactions, total, err := h.listTxnUseCase.Execute(c.Context(), partnerID, filter)	// Execute use case	filter := buildTransactionFilter(req)	// Build filter	}		req.Limit = 100	if req.Limit > 100 {	}		req.Limit = 20	if req.Limit == 0 {	// Set defaults	}		})			Message: err.Error(),			Error:   "invalid_query_parameters",		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{	if err := c.QueryParser(&req); err != nil {	var req dto.ListTransactionsRequest	// Parse query parameters	}		})			Message: "authentication required",			Error:   "unauthorized",		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{	if err != nil {	partnerID, err := middleware.GetPartnerID(c)	// Get partner IDfunc (h *TransactionHandler) ListTransactions(c *fiber.Ctx) error {// ListTransactions handles GET /api/v1/transactions}	return c.JSON(response)	response := h.mapTransactionToDTO(txn)	// Map to response DTO	}		})			Message: err.Error(),			Error:   "transaction_not_found",		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{	if err != nil {	txn, err := h.getTxnUseCase.Execute(c.Context(), txnID, partnerID)	// Execute use case	}		})			Message: "invalid transaction ID format",			Error:   "invalid_transaction_id",		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{	if err != nil {	txnID, err := uuid.Parse(txnIDStr)	txnIDStr := c.Params("id")	// Parse transaction ID from URL	}		})			Message: "authentication required",			Error:   "unauthorized",		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{	if err != nil {	partnerID, err := middleware.GetPartnerID(c)	// Get partner IDfunc (h *TransactionHandler) GetTransaction(c *fiber.Ctx) error {// GetTransaction handles GET /api/v1/transactions/:id}	return c.Status(fiber.StatusCreated).JSON(response)	}		CreatedAt:     output.CreatedAt,		Currency:      req.Currency,		Amount:        req.Amount,		Status:        output.Status,		TransactionID: output.TransactionID.String(),	response := dto.CreateTransactionResponse{	// Return response	}		})			Message: err.Error(),			Error:   "transaction_creation_failed",		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{	if err != nil {	output, err := h.createTxnUseCase.Execute(c.Context(), input)	// Execute use case	}		UserAgent:      c.Get("User-Agent"),		IPAddress:      c.IP(),		Metadata:       req.Metadata,		Description:    req.Description,		CustomerPhone:  req.CustomerPhone,		CustomerName:   req.CustomerName,		CustomerEmail:  req.CustomerEmail,		Provider:       req.Provider,		PaymentMethod:  req.PaymentMethod,		Currency:       req.Currency,		Amount:         req.Amount,		IdempotencyKey: req.IdempotencyKey,		PartnerID:      partnerID,	input := transaction.CreateTransactionInput{	// Create use case input	}		})			Message: "missing required fields",			Error:   "validation_error",		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{	if req.IdempotencyKey == "" || req.Amount <= 0 || req.Currency == "" {	// Validate request (in production, use proper validator)	}		})			Message: "invalid request body",			Error:   "invalid_request",		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{	if err := c.BodyParser(&req); err != nil {	var req dto.CreateTransactionRequest	// Parse request body	}		})			Message: "authentication required",			Error:   "unauthorized",		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{	if err != nil {	partnerID, err := middleware.GetPartnerID(c)	// Get partner ID from context (set by auth middleware)func (h *TransactionHandler) CreateTransaction(c *fiber.Ctx) error {// CreateTransaction handles POST /api/v1/transactions}	}		refundUseCase:     refundUseCase,		processTxnUseCase: processTxnUseCase,		listTxnUseCase:    listTxnUseCase,		getTxnUseCase:     getTxnUseCase,		createTxnUseCase:  createTxnUseCase,	return &TransactionHandler{) *TransactionHandler {	refundUseCase *transaction.RefundTransactionUseCase,	processTxnUseCase *transaction.ProcessPaymentUseCase,	listTxnUseCase *transaction.ListTransactionsUseCase,	getTxnUseCase *transaction.GetTransactionUseCase,	createTxnUseCase *transaction.CreateTransactionUseCase,func NewTransactionHandler(// NewTransactionHandler creates a new transaction handler}	refundUseCase     *transaction.RefundTransactionUseCase	processTxnUseCase *transaction.ProcessPaymentUseCase	listTxnUseCase    *transaction.ListTransactionsUseCase	getTxnUseCase     *transaction.GetTransactionUseCase	createTxnUseCase  *transaction.CreateTransactionUseCasetype TransactionHandler struct {// TransactionHandler handles transaction-related HTTP requests)	"Pay2Go/internal/usecases/transaction"	"Pay2Go/internal/domain/entities"	"Pay2Go/internal/adapters/http/middleware"	"Pay2Go/internal/adapters/http/dto"	"github.com/google/uuid"	"github.com/gofiber/fiber/v2"
\ No newline at end of file
+	"bufio"
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/codec"
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// TransactionHandler handles transaction-related HTTP requests
+type TransactionHandler struct {
+	createTxnUseCase  *transaction.CreateTransactionUseCase
+	getTxnUseCase     *transaction.GetTransactionUseCase
+	listTxnUseCase    *transaction.ListTransactionsUseCase
+	exportTxnUseCase  *transaction.ExportTransactionsUseCase
+	processTxnUseCase *transaction.ProcessPaymentUseCase
+	// enqueueUseCase queues payments for cmd/worker instead of processing
+	// them in the request; nil processes them in the request
+	enqueueUseCase    *transaction.EnqueuePaymentUseCase
+	refundUseCase     *transaction.RefundTransactionUseCase
+	deleteTxnUseCase  *transaction.DeleteTransactionUseCase
+	restoreTxnUseCase *transaction.RestoreTransactionUseCase
+	// listRefundsUseCase reads the refunds embedded with expand=refunds
+	listRefundsUseCase *transaction.ListRefundsUseCase
+}
+
+// NewTransactionHandler creates a new transaction handler
+func NewTransactionHandler(
+	createTxnUseCase *transaction.CreateTransactionUseCase,
+	getTxnUseCase *transaction.GetTransactionUseCase,
+	listTxnUseCase *transaction.ListTransactionsUseCase,
+	exportTxnUseCase *transaction.ExportTransactionsUseCase,
+	processTxnUseCase *transaction.ProcessPaymentUseCase,
+	enqueueUseCase *transaction.EnqueuePaymentUseCase,
+	refundUseCase *transaction.RefundTransactionUseCase,
+	deleteTxnUseCase *transaction.DeleteTransactionUseCase,
+	restoreTxnUseCase *transaction.RestoreTransactionUseCase,
+	listRefundsUseCase *transaction.ListRefundsUseCase,
+) *TransactionHandler {
+	return &TransactionHandler{
+		createTxnUseCase:   createTxnUseCase,
+		getTxnUseCase:      getTxnUseCase,
+		listTxnUseCase:     listTxnUseCase,
+		exportTxnUseCase:   exportTxnUseCase,
+		processTxnUseCase:  processTxnUseCase,
+		enqueueUseCase:     enqueueUseCase,
+		refundUseCase:      refundUseCase,
+		deleteTxnUseCase:   deleteTxnUseCase,
+		restoreTxnUseCase:  restoreTxnUseCase,
+		listRefundsUseCase: listRefundsUseCase,
+	}
+}
+
+// CreateTransaction handles POST /api/v1/transactions
+func (h *TransactionHandler) CreateTransaction(c *fiber.Ctx) error {
+	// Get partner ID from context (set by auth middleware)
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.CreateTransactionRequest
+	if err := codec.Decode(c, &req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Validate request (in production, use proper validator)
+	if req.IdempotencyKey == "" || req.Amount == "" || req.Currency == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "missing required fields",
+		})
+	}
+
+	// Amounts arrive as decimal strings and are handled in minor units from here on
+	money, err := valueobjects.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "validation_error")
+	}
+
+	// Card, bank account or wallet details are optional but must match the payment method
+	var details *valueobjects.PaymentMethodDetails
+	if req.PaymentMethodDetails != nil {
+		details, err = parsePaymentMethodDetails(req.PaymentMethod, *req.PaymentMethodDetails)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	// Create use case input
+	input := transaction.CreateTransactionInput{
+		PartnerID:            partnerID,
+		IdempotencyKey:       req.IdempotencyKey,
+		Amount:               money.Amount,
+		Currency:             money.Currency.String(),
+		PaymentMethod:        req.PaymentMethod,
+		Provider:             req.Provider,
+		PaymentMethodDetails: details,
+		CustomerEmail:        req.CustomerEmail,
+		CustomerName:         req.CustomerName,
+		CustomerPhone:        req.CustomerPhone,
+		BillingCountry:       req.BillingCountry,
+		Description:          req.Description,
+		Metadata:             req.Metadata,
+		Livemode:             middleware.GetLivemode(c),
+		IPAddress:            c.IP(),
+		UserAgent:            c.Get("User-Agent"),
+	}
+
+	// Execute use case
+	output, err := h.createTxnUseCase.Execute(c.Context(), input)
+	if err != nil {
+		if err == errors.ErrFeatureDisabled {
+			return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+				Error:   "feature_disabled",
+				Message: "crypto payments are not enabled for this account",
+			})
+		}
+		if err == errors.ErrCurrencyNotAllowed {
+			return respondError(c, fiber.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "currency_not_allowed",
+				Message: "currency " + req.Currency + " is not enabled for this account",
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "transaction_creation_failed")
+	}
+
+	// Return response
+	response := dto.CreateTransactionResponse{
+		TransactionID: output.TransactionID.String(),
+		Status:        output.Status,
+		Amount:        money.Decimal(),
+		Currency:      money.Currency.String(),
+		Livemode:      output.Livemode,
+		CreatedAt:     output.CreatedAt,
+	}
+	return codec.Respond(c, fiber.StatusCreated, response)
+}
+
+// GetTransaction handles GET /api/v1/transactions/:id. Refunds and the
+// customer are embedded when asked for with ?expand=refunds,customer.
+func (h *TransactionHandler) GetTransaction(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID from URL
+	txnIDStr := c.Params("id")
+	txnID, err := uuid.Parse(txnIDStr)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	expand, err := parseExpand(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Execute use case
+	txn, err := h.getTxnUseCase.Execute(c.Context(), txnID, partnerID, middleware.GetLivemode(c))
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusNotFound, "transaction_not_found")
+	}
+
+	// Map to response DTO
+	response, err := h.transactionResponse(c.Context(), txn, expand)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_refunds")
+	}
+	return codec.Respond(c, fiber.StatusOK, response)
+}
+
+// ListTransactions handles GET /api/v1/transactions. expand applies to
+// every transaction of the page.
+func (h *TransactionHandler) ListTransactions(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse query parameters
+	var req dto.ListTransactionsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Set defaults
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	// Build query
+	query, err := buildTransactionQuery(c, req)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	expand, err := parseExpand(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Execute use case
+	transactions, total, err := h.listTxnUseCase.Execute(c.Context(), partnerID, middleware.GetLivemode(c), query)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_transactions")
+	}
+
+	// Map to response DTOs
+	txnDTOs := make([]dto.GetTransactionResponse, len(transactions))
+	for i, txn := range transactions {
+		txnDTOs[i], err = h.transactionResponse(c.Context(), txn, expand)
+		if err != nil {
+			return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_refunds")
+		}
+	}
+	response := dto.ListTransactionsResponse{
+		Transactions: txnDTOs,
+		Total:        total,
+		Limit:        req.Limit,
+		Offset:       req.Offset,
+	}
+	if len(transactions) == req.Limit {
+		response.NextCursor = transactions[len(transactions)-1].ID.String()
+	}
+	response.Links = pageLinks(c, "/api/v1/transactions", response.NextCursor)
+	return codec.Respond(c, fiber.StatusOK, response)
+}
+
+// ExportTransactions handles GET /api/v1/transactions/export, streaming
+// every transaction matching the list filters as CSV or NDJSON. Rows are
+// written as they are read from the database, so memory stays flat however
+// large the export.
+func (h *TransactionHandler) ExportTransactions(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse query parameters
+	var req dto.ListTransactionsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	format := c.Query("format", exportFormatCSV)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_query_parameters",
+			Message: "format must be csv or ndjson",
+		})
+	}
+	layout, err := transactionExportLayout(format, c.Query("columns"), c.Query("timezone"))
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Build query; the export has no pages
+	req.Limit, req.Offset = 0, 0
+	query, err := buildTransactionQuery(c, req)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	query = detachTransactionQuery(query)
+	livemode := middleware.GetLivemode(c)
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="transactions.`+format+`"`)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// The stream runs after the handler returns and must not touch c; it
+	// stops once a write fails because the client went away
+	exportFormat := strings.Clone(format)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		export := newRowExport(exportFormat, w, transactionCSVColumns, layout)
+		err := h.exportTxnUseCase.Execute(context.Background(), partnerID, livemode, query, func(txn *entities.Transaction) error {
+			return export.write(newTransactionRow(txn, layout.location))
+		})
+		if err != nil {
+			export.fail(err)
+		}
+		export.flush()
+	})
+	return nil
+}
+
+// ProcessPayment handles POST /api/v1/transactions/:id/process
+func (h *TransactionHandler) ProcessPayment(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID
+	txnIDStr := c.Params("id")
+	txnID, err := uuid.Parse(txnIDStr)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// In async mode a worker processes the payment; the partner learns the
+	// outcome from the webhook or by polling the transaction
+	if h.enqueueUseCase != nil {
+		job, err := h.enqueueUseCase.Execute(c.Context(), partnerID, txnID)
+		if err != nil {
+			return respondDomainError(c, err, fiber.StatusInternalServerError, "payment_processing_failed")
+		}
+		return codec.Respond(c, fiber.StatusAccepted, dto.ProcessPaymentResponse{
+			Message: "payment queued for processing",
+			JobID:   job.ID.String(),
+		})
+	}
+
+	// Execute use case
+	if err := h.processTxnUseCase.Execute(c.Context(), txnID); err != nil {
+		if conflict := asVersionConflict(err); conflict != nil {
+			return respondError(c, fiber.StatusConflict, versionConflictResponse(conflict))
+		}
+		if err == errors.ErrResourceLocked {
+			return respondError(c, fiber.StatusConflict, dto.ErrorResponse{
+				Error:   "processing_in_progress",
+				Message: "the transaction is being processed by another request, retry later",
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "payment_processing_failed")
+	}
+	return codec.Respond(c, fiber.StatusOK, dto.ProcessPaymentResponse{
+		Message: "payment processing initiated",
+	})
+}
+
+// RefundTransaction handles POST /api/v1/transactions/:id/refund
+func (h *TransactionHandler) RefundTransaction(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID
+	txnIDStr := c.Params("id")
+	txnID, err := uuid.Parse(txnIDStr)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.RefundTransactionRequest
+	if err := codec.Decode(c, &req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	money, err := valueobjects.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "validation_error")
+	}
+
+	// Create use case input
+	input := transaction.RefundTransactionInput{
+		TransactionID: txnID,
+		PartnerID:     partnerID,
+		Amount:        money.Amount,
+		Currency:      money.Currency.String(),
+		ReasonCode:    req.Reason,
+		ReasonNote:    req.ReasonNote,
+		Livemode:      middleware.GetLivemode(c),
+		IPAddress:     c.IP(),
+		UserAgent:     c.Get("User-Agent"),
+	}
+
+	// Execute use case
+	refund, err := h.refundUseCase.Execute(c.Context(), input)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "refund_failed")
+	}
+
+	// Refunds held for approval are accepted but not yet processed
+	status := fiber.StatusCreated
+	if refund.RequiresApproval() {
+		status = fiber.StatusAccepted
+	}
+	return codec.Respond(c, status, mapRefundToDTO(refund))
+}
+
+// DeleteTransaction handles DELETE /api/v1/admin/transactions/:id
+func (h *TransactionHandler) DeleteTransaction(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse transaction ID
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// Execute use case
+	txn, err := h.deleteTxnUseCase.Execute(c.Context(), transaction.SoftDeleteInput{
+		ID:        id,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return softDeleteError(c, err, errors.ErrTransactionNotFound, "transaction", "transaction_deletion_failed")
+	}
+
+	return c.JSON(dto.DeletedResponse{
+		ID:        txn.ID.String(),
+		DeletedAt: txn.DeletedAt,
+	})
+}
+
+// RestoreTransaction handles POST /api/v1/admin/transactions/:id/restore
+func (h *TransactionHandler) RestoreTransaction(c *fiber.Ctx) error {
+	// Get admin identity
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin authentication required",
+		})
+	}
+
+	// Parse transaction ID
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// Execute use case
+	txn, err := h.restoreTxnUseCase.Execute(c.Context(), transaction.SoftDeleteInput{
+		ID:        id,
+		AdminID:   adminID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return softDeleteError(c, err, errors.ErrTransactionNotFound, "transaction", "transaction_restore_failed")
+	}
+
+	return c.JSON(mapTransactionToDTO(txn))
+}
+
+// transactionResponse maps a transaction to its response DTO, with its
+// links and the related resources expand asks for
+func (h *TransactionHandler) transactionResponse(ctx context.Context, txn *entities.Transaction, expand transactionExpansions) (dto.GetTransactionResponse, error) {
+	response := mapTransactionToDTO(txn)
+	response.Links = transactionLinks("/api/v1/transactions", txn, "process")
+	if expand.customer {
+		response.Customer = mapCustomerToDTO(txn)
+	}
+	if expand.refunds {
+		refunds, hasMore, err := transactionRefunds(ctx, h.listRefundsUseCase, txn)
+		if err != nil {
+			return response, err
+		}
+		response.Refunds = &dto.TransactionRefundsResponse{
+			Data:    make([]dto.RefundTransactionResponse, len(refunds)),
+			HasMore: hasMore,
+		}
+		for i, refund := range refunds {
+			response.Refunds.Data[i] = mapRefundToDTO(refund)
+		}
+	}
+	return response, nil
+}
+
+// Helper functions
+func mapTransactionToDTO(txn *entities.Transaction) dto.GetTransactionResponse {
+	return dto.GetTransactionResponse{
+		ID:                    txn.ID.String(),
+		PartnerID:             txn.PartnerID.String(),
+		IdempotencyKey:        txn.IdempotencyKey,
+		Amount:                txn.Amount.Decimal(),
+		Currency:              txn.Amount.Currency.String(),
+		PaymentMethod:         txn.PaymentMethod.String(),
+		PaymentMethodDetails:  mapPaymentMethodDetailsToDTO(txn.PaymentMethodDetails),
+		Provider:              txn.Provider.String(),
+		ProviderTransactionID: txn.ProviderTransactionID,
+		Status:                string(txn.Status),
+		Livemode:              txn.Livemode,
+		CustomerEmail:         txn.CustomerEmail,
+		CustomerName:          txn.CustomerName,
+		CustomerPhone:         txn.CustomerPhone,
+		BillingCountry:        txn.BillingCountry.String(),
+		Description:           txn.Description,
+		Metadata:              txn.Metadata,
+		ErrorCode:             txn.ErrorCode,
+		ErrorMessage:          txn.ErrorMessage,
+		CreatedAt:             txn.CreatedAt,
+		UpdatedAt:             txn.UpdatedAt,
+		ProcessedAt:           txn.ProcessedAt,
+	}
+}
+
+// parsePaymentMethodDetails validates request details against the payment method
+func parsePaymentMethodDetails(paymentMethod string, req dto.PaymentMethodDetails) (*valueobjects.PaymentMethodDetails, error) {
+	method, err := valueobjects.NewPaymentMethod(paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	var details valueobjects.PaymentMethodDetails
+	if req.Card != nil {
+		// The brand may be left to BIN detection
+		brand := req.Card.Brand
+		if brand == "" {
+			brand = valueobjects.CardBrandUnknown.String()
+		}
+		details.Card, err = valueobjects.NewCardDetails(brand, req.Card.Last4,
+			req.Card.ExpMonth, req.Card.ExpYear, req.Card.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if req.Card.BIN != "" {
+			if details.Card.BIN, err = valueobjects.NewBIN(req.Card.BIN); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.BankAccount != nil {
+		details.BankAccount, err = valueobjects.NewBankAccountDetails(req.BankAccount.BankName,
+			req.BankAccount.BankCode, req.BankAccount.Last4, req.BankAccount.HolderType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Wallet != nil {
+		details.Wallet, err = valueobjects.NewWalletDetails(req.Wallet.Type, req.Wallet.AccountID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := details.ValidateFor(method); err != nil {
+		return nil, err
+	}
+
+	return &details, nil
+}
+
+// mapPaymentMethodDetailsToDTO maps payment method details to their response DTO
+func mapPaymentMethodDetailsToDTO(details *valueobjects.PaymentMethodDetails) *dto.PaymentMethodDetails {
+	if details == nil {
+		return nil
+	}
+
+	response := &dto.PaymentMethodDetails{}
+	if card := details.Card; card != nil {
+		response.Card = &dto.CardDetails{
+			Brand:         card.Brand.String(),
+			Last4:         card.Last4,
+			ExpMonth:      card.ExpMonth,
+			ExpYear:       card.ExpYear,
+			Fingerprint:   card.Fingerprint,
+			BIN:           card.BIN.String(),
+			IssuerCountry: card.IssuerCountry.String(),
+			Funding:       card.Funding.String(),
+		}
+	}
+	if bank := details.BankAccount; bank != nil {
+		response.BankAccount = &dto.BankAccountDetails{
+			BankName:   bank.BankName,
+			BankCode:   bank.BankCode,
+			Last4:      bank.Last4,
+			HolderType: string(bank.HolderType),
+		}
+	}
+	if wallet := details.Wallet; wallet != nil {
+		response.Wallet = &dto.WalletDetails{
+			Type:      wallet.Type.String(),
+			AccountID: wallet.AccountID,
+		}
+	}
+
+	return response
+}
+
+// asVersionConflict returns the version conflict behind err, if any; use
+// cases wrap repository errors, so a plain type assertion would miss it
+func asVersionConflict(err error) *errors.VersionConflictError {
+	var conflict *errors.VersionConflictError
+	if stderrors.As(err, &conflict) {
+		return conflict
+	}
+	return nil
+}
+
+// respondError writes the error envelope, see middleware.RespondError
+func respondError(c *fiber.Ctx, status int, response dto.ErrorResponse) error {
+	return middleware.RespondError(c, status, response)
+}
+
+// versionConflictResponse tells the client its request lost a race with a
+// concurrent change and can be retried
+func versionConflictResponse(conflict *errors.VersionConflictError) dto.ErrorResponse {
+	return dto.ErrorResponse{
+		Error:   "concurrent_modification",
+		Message: conflict.Resource + " was modified by another request, retry",
+		Details: map[string]interface{}{
+			"resource": conflict.Resource,
+			"id":       conflict.ID,
+		},
+	}
+}
+
+// softDeleteError responds to a delete or restore of resource that failed
+// with err; notFound is the resource's not found error
+func softDeleteError(c *fiber.Ctx, err, notFound error, resource, failure string) error {
+	if err == notFound {
+		return respondError(c, fiber.StatusNotFound, dto.ErrorResponse{
+			Error:   resource + "_not_found",
+			Message: err.Error(),
+		})
+	}
+	if conflict := asVersionConflict(err); conflict != nil {
+		return respondError(c, fiber.StatusConflict, versionConflictResponse(conflict))
+	}
+	if _, ok := err.(*errors.DomainError); ok {
+		return respondError(c, fiber.StatusConflict, dto.ErrorResponse{
+			Error:   resource + "_not_deletable",
+			Message: err.Error(),
+		})
+	}
+	return respondDomainError(c, err, fiber.StatusInternalServerError, failure)
+}
+
+// listableStatuses are the statuses the status filter accepts
+var listableStatuses = map[entities.TransactionStatus]bool{
+	entities.StatusPending:           true,
+	entities.StatusProcessing:        true,
+	entities.StatusCompleted:         true,
+	entities.StatusFailed:            true,
+	entities.StatusCancelled:         true,
+	entities.StatusRefunded:          true,
+	entities.StatusPartiallyRefunded: true,
+}
+
+// buildTransactionQuery translates list query parameters, including
+// metadata[key]=value pairs, into a transaction query
+func buildTransactionQuery(c *fiber.Ctx, req dto.ListTransactionsRequest) (ports.TransactionQuery, error) {
+	query, err := transactionQuery(req)
+	if err != nil {
+		return query, err
+	}
+
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		name := string(key)
+		if strings.HasPrefix(name, "metadata[") && strings.HasSuffix(name, "]") {
+			if query.Metadata == nil {
+				query.Metadata = make(map[string]string)
+			}
+			query.Metadata[name[len("metadata["):len(name)-1]] = string(value)
+		}
+	})
+
+	return query, nil
+}
+
+// transactionQuery translates the list filters of req into a transaction
+// query
+func transactionQuery(req dto.ListTransactionsRequest) (ports.TransactionQuery, error) {
+	query := ports.TransactionQuery{
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	if req.Status != "" {
+		for _, value := range strings.Split(req.Status, ",") {
+			status := entities.TransactionStatus(strings.TrimSpace(value))
+			if !listableStatuses[status] {
+				return query, errors.NewValidationError("status", "unknown status "+string(status))
+			}
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+
+	if req.Currency != "" {
+		currency, err := valueobjects.NewCurrency(req.Currency)
+		if err != nil {
+			return query, err
+		}
+		query.Currency = &currency
+	}
+	if (req.AmountMin != "" || req.AmountMax != "") && query.Currency == nil {
+		return query, errors.NewValidationError("currency", "required with amount_min or amount_max")
+	}
+	if req.AmountMin != "" {
+		minAmount, err := valueobjects.ParseMoney(req.AmountMin, req.Currency)
+		if err != nil {
+			return query, err
+		}
+		query.MinAmount = &minAmount.Amount
+	}
+	if req.AmountMax != "" {
+		maxAmount, err := valueobjects.ParseMoney(req.AmountMax, req.Currency)
+		if err != nil {
+			return query, err
+		}
+		query.MaxAmount = &maxAmount.Amount
+	}
+
+	if req.DateFrom != "" {
+		from, err := time.Parse("2006-01-02", req.DateFrom)
+		if err != nil {
+			return query, errors.NewValidationError("date_from", "must be YYYY-MM-DD")
+		}
+		query.CreatedFrom = &from
+	}
+	if req.DateTo != "" {
+		to, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return query, errors.NewValidationError("date_to", "must be YYYY-MM-DD")
+		}
+		// date_to includes the whole day
+		to = to.AddDate(0, 0, 1)
+		query.CreatedTo = &to
+	}
+
+	if req.Sort != "" {
+		query.Ascending = !strings.HasPrefix(req.Sort, "-")
+		query.OrderBy = ports.TransactionOrder(strings.TrimPrefix(req.Sort, "-"))
+		if !query.OrderBy.IsValid() {
+			return query, errors.NewValidationError("sort", "must be created_at, -created_at, amount or -amount")
+		}
+	}
+
+	if req.StartingAfter != "" {
+		after, err := uuid.Parse(req.StartingAfter)
+		if err != nil {
+			return query, errors.NewValidationError("starting_after", "must be a transaction ID")
+		}
+		query.After = &after
+	}
+
+	return query, nil
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+const (
+	// maxSocketSubscriptions bounds the transactions one socket follows
+	maxSocketSubscriptions = 20
+	// maxSocketMessageSize bounds the messages clients send
+	maxSocketMessageSize = 4 << 10
+	// socketPingInterval is how often the server pings; clients that
+	// answer nothing for two intervals are disconnected
+	socketPingInterval = 30 * time.Second
+	// socketWriteTimeout bounds writing a message to a client
+	socketWriteTimeout = 10 * time.Second
+)
+
+// TransactionSocketHandler pushes the status of transactions to clients
+// over a WebSocket, such as a point-of-sale terminal showing a QR code while
+// the customer pays out-of-band. Each socket follows its partner's events in
+// the outbox, as event streams do, and reads a subscribed transaction again
+// only when an event names it.
+type TransactionSocketHandler struct {
+	getTxnUseCase *transaction.GetTransactionUseCase
+	streamUseCase *outbox.StreamEventsUseCase
+	pollInterval  time.Duration
+
+	mu      sync.Mutex
+	sockets map[*websocket.Conn]struct{}
+	closed  bool
+}
+
+// NewTransactionSocketHandler creates a new transaction socket handler
+// reading new events every pollInterval
+func NewTransactionSocketHandler(
+	getTxnUseCase *transaction.GetTransactionUseCase,
+	streamUseCase *outbox.StreamEventsUseCase,
+	pollInterval time.Duration,
+) *TransactionSocketHandler {
+	return &TransactionSocketHandler{
+		getTxnUseCase: getTxnUseCase,
+		streamUseCase: streamUseCase,
+		pollInterval:  pollInterval,
+		sockets:       make(map[*websocket.Conn]struct{}),
+	}
+}
+
+// Close closes the open sockets and refuses new ones, so a stopping server
+// is not held up by them. Clients reconnect to another instance and
+// subscribe again.
+func (h *TransactionSocketHandler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for conn := range h.sockets {
+		closeSocket(conn, websocket.CloseGoingAway, "server restarting")
+	}
+}
+
+// Connect handles GET /api/v1/ws/transactions. Clients subscribe with
+// {"type": "subscribe", "transaction_id": ...} messages, or the
+// transaction_id query parameter, and receive the transaction's status then
+// and whenever it changes.
+func (h *TransactionSocketHandler) Connect(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// The first transaction may be given when connecting
+	var initial uuid.UUID
+	if id := c.Query("transaction_id"); id != "" {
+		initial, err = uuid.Parse(id)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_transaction_id",
+				Message: "invalid transaction ID format",
+			})
+		}
+	}
+
+	if !websocket.IsWebSocketUpgrade(c) {
+		c.Set(fiber.HeaderUpgrade, "websocket")
+		return respondError(c, fiber.StatusUpgradeRequired, dto.ErrorResponse{
+			Error:   "websocket_required",
+			Message: "this endpoint is a WebSocket; connect with a WebSocket client",
+		})
+	}
+
+	// The socket outlives the request and must not touch c. Events are
+	// followed from now on; subscribing reads the current status.
+	socket := &transactionSocket{
+		handler:   h,
+		partnerID: partnerID,
+		livemode:  middleware.GetLivemode(c),
+		v2:        middleware.GetAPIVersion(c) == middleware.APIVersion2,
+		cursor:    h.streamUseCase.Start(),
+		versions:  make(map[uuid.UUID]string),
+	}
+	upgrade := websocket.New(func(conn *websocket.Conn) {
+		socket.conn = conn
+		if !h.track(conn) {
+			closeSocket(conn, websocket.CloseGoingAway, "server restarting")
+			return
+		}
+		defer h.untrack(conn)
+		socket.serve(initial)
+	}, websocket.Config{RecoverHandler: recoverSocket})
+	if err := upgrade(c); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_websocket_handshake",
+			Message: "the WebSocket handshake is invalid",
+		})
+	}
+	return nil
+}
+
+// track registers an open socket; false once the handler is closed
+func (h *TransactionSocketHandler) track(conn *websocket.Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.sockets[conn] = struct{}{}
+	return true
+}
+
+// untrack forgets a socket that is done
+func (h *TransactionSocketHandler) untrack(conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sockets, conn)
+}
+
+// transactionSocket is one client's connection and subscriptions
+type transactionSocket struct {
+	handler   *TransactionSocketHandler
+	conn      *websocket.Conn
+	partnerID uuid.UUID
+	livemode  bool
+	v2        bool
+	// cursor is where the partner's events are read on from
+	cursor ports.OutboxCursor
+	// versions are the subscribed transactions, with the version of each
+	// last sent
+	versions map[uuid.UUID]string
+}
+
+// serve answers the client's messages and pushes status changes until the
+// connection closes
+func (s *transactionSocket) serve(initial uuid.UUID) {
+	// Any message or pong keeps the connection open
+	s.conn.SetReadLimit(maxSocketMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(2 * socketPingInterval))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(2 * socketPingInterval))
+	})
+
+	// Messages are read apart, so a client that sends nothing still gets
+	// updates
+	messages := make(chan []byte)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(messages)
+		for {
+			_, message, err := s.conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = s.conn.SetReadDeadline(time.Now().Add(2 * socketPingInterval))
+			select {
+			case messages <- message:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	if initial != uuid.Nil {
+		s.subscribe(initial)
+	}
+	poll := time.NewTicker(s.handler.pollInterval)
+	defer poll.Stop()
+	ping := time.NewTicker(socketPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			s.handle(message)
+		case <-poll.C:
+			s.follow()
+		case <-ping.C:
+			if s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteTimeout)) != nil {
+				return
+			}
+		}
+	}
+}
+
+// handle acts on a client's message
+func (s *transactionSocket) handle(message []byte) {
+	var req dto.TransactionSocketRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		s.fail("invalid_request", "messages must be JSON objects", "")
+		return
+	}
+	txnID, err := uuid.Parse(req.TransactionID)
+	if err != nil {
+		s.fail("invalid_transaction_id", "invalid transaction ID format", req.TransactionID)
+		return
+	}
+
+	switch req.Type {
+	case "subscribe":
+		s.subscribe(txnID)
+	case "unsubscribe":
+		delete(s.versions, txnID)
+	default:
+		s.fail("invalid_request", "type must be subscribe or unsubscribe", req.TransactionID)
+	}
+}
+
+// subscribe follows a transaction of the partner and sends its status
+func (s *transactionSocket) subscribe(txnID uuid.UUID) {
+	if _, ok := s.versions[txnID]; !ok && len(s.versions) >= maxSocketSubscriptions {
+		s.fail("too_many_subscriptions", "unsubscribe from a transaction first", txnID.String())
+		return
+	}
+	txn, err := s.handler.getTxnUseCase.Execute(context.Background(), txnID, s.partnerID, s.livemode)
+	if err != nil {
+		if isTransactionMissing(err) {
+			s.fail("transaction_not_found", "transaction not found", txnID.String())
+		} else {
+			s.fail("internal_server_error", "the transaction could not be read, subscribe again", txnID.String())
+		}
+		return
+	}
+	s.versions[txnID] = ""
+	s.push(txn)
+}
+
+// follow reads the partner's new events and sends the status of the
+// subscribed transactions they name
+func (s *transactionSocket) follow() {
+	changed := make(map[uuid.UUID]bool)
+	for {
+		events, next, more, err := s.handler.streamUseCase.Execute(context.Background(), s.partnerID, s.livemode, s.cursor, streamBatchSize)
+		if err != nil {
+			// Read again from the same cursor at the next poll
+			break
+		}
+		s.cursor = next
+		for _, event := range events {
+			// Transaction and refund events both name the transaction
+			var named struct {
+				TransactionID uuid.UUID `json:"transaction_id"`
+			}
+			if json.Unmarshal(event.Payload, &named) != nil {
+				continue
+			}
+			if _, ok := s.versions[named.TransactionID]; ok {
+				changed[named.TransactionID] = true
+			}
+		}
+		if !more {
+			break
+		}
+	}
+
+	for txnID := range changed {
+		s.refresh(txnID)
+	}
+}
+
+// refresh sends the status of a subscribed transaction if it changed
+func (s *transactionSocket) refresh(txnID uuid.UUID) {
+	txn, err := s.handler.getTxnUseCase.Execute(context.Background(), txnID, s.partnerID, s.livemode)
+	if isTransactionMissing(err) {
+		// Deleted since; there is nothing left to follow
+		delete(s.versions, txnID)
+		s.fail("transaction_not_found", "transaction not found", txnID.String())
+		return
+	}
+	if err != nil {
+		// The client subscribes again to read the status
+		s.fail("internal_server_error", "the transaction could not be read, subscribe again", txnID.String())
+		return
+	}
+	s.push(txn)
+}
+
+// push sends a transaction's status unless it was sent already
+func (s *transactionSocket) push(txn *entities.Transaction) {
+	version := string(txn.Status) + "@" + txn.UpdatedAt.String()
+	if s.versions[txn.ID] == version {
+		return
+	}
+	s.versions[txn.ID] = version
+
+	status := string(txn.Status)
+	if s.v2 {
+		status = intentStatus(txn.Status)
+	}
+	s.send(dto.TransactionStatusMessage{
+		Type:          "transaction.status",
+		TransactionID: txn.ID.String(),
+		Status:        status,
+		ErrorCode:     txn.ErrorCode,
+		ErrorMessage:  txn.ErrorMessage,
+		UpdatedAt:     txn.UpdatedAt,
+		ProcessedAt:   txn.ProcessedAt,
+	})
+}
+
+// fail tells the client a message could not be acted on
+func (s *transactionSocket) fail(code, message, txnID string) {
+	s.send(dto.TransactionSocketError{
+		Type:          "error",
+		Error:         code,
+		Message:       message,
+		TransactionID: txnID,
+	})
+}
+
+// send writes a message; a failed write ends the connection, which the
+// reader then notices
+func (s *transactionSocket) send(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	if err := s.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		_ = s.conn.Close()
+	}
+}
+
+// closeSocket tells the client why the connection ends and closes it
+func closeSocket(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	_ = conn.Close()
+}
+
+// recoverSocket closes a socket whose handler panicked, without telling the
+// client why
+func recoverSocket(conn *websocket.Conn) {
+	if recover() != nil {
+		closeSocket(conn, websocket.CloseInternalServerErr, "")
+	}
+}
+
+// isTransactionMissing reports whether err means the partner has no such
+// transaction, including transactions of other partners
+func isTransactionMissing(err error) bool {
+	return stderrors.Is(err, errors.ErrTransactionNotFound) || stderrors.Is(err, errors.ErrUnauthorizedOperation)
+}
@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/codec"
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// Metadata limits of API v2
+const (
+	maxMetadataKeys        = 50
+	maxMetadataKeyLength   = 40
+	maxMetadataValueLength = 500
+)
+
+// Payment intent statuses of API v2
+const (
+	intentRequiresConfirmation = "requires_confirmation"
+	intentProcessing           = "processing"
+	intentSucceeded            = "succeeded"
+	intentFailed               = "failed"
+	intentCanceled             = "canceled"
+)
+
+// intentStatuses maps the statuses of API v2 to those of transactions.
+// Refunds do not undo a payment, so refunded transactions succeeded.
+var intentStatuses = map[string][]entities.TransactionStatus{
+	intentRequiresConfirmation: {entities.StatusPending},
+	intentProcessing:           {entities.StatusProcessing},
+	intentSucceeded:            {entities.StatusCompleted, entities.StatusPartiallyRefunded, entities.StatusRefunded},
+	intentFailed:               {entities.StatusFailed},
+	intentCanceled:             {entities.StatusCancelled},
+}
+
+// TransactionV2Handler handles the transactions of API v2: payment intents
+// created, then confirmed, with amounts as money objects in minor units
+type TransactionV2Handler struct {
+	createTxnUseCase  *transaction.CreateTransactionUseCase
+	getTxnUseCase     *transaction.GetTransactionUseCase
+	listTxnUseCase    *transaction.ListTransactionsUseCase
+	processTxnUseCase *transaction.ProcessPaymentUseCase
+	// enqueueUseCase queues confirmed payments for cmd/worker; nil
+	// processes them in the request
+	enqueueUseCase *transaction.EnqueuePaymentUseCase
+	// listRefundsUseCase reads the refunds embedded with expand=refunds
+	listRefundsUseCase *transaction.ListRefundsUseCase
+}
+
+// NewTransactionV2Handler creates a new API v2 transaction handler
+func NewTransactionV2Handler(
+	createTxnUseCase *transaction.CreateTransactionUseCase,
+	getTxnUseCase *transaction.GetTransactionUseCase,
+	listTxnUseCase *transaction.ListTransactionsUseCase,
+	processTxnUseCase *transaction.ProcessPaymentUseCase,
+	enqueueUseCase *transaction.EnqueuePaymentUseCase,
+	listRefundsUseCase *transaction.ListRefundsUseCase,
+) *TransactionV2Handler {
+	return &TransactionV2Handler{
+		createTxnUseCase:   createTxnUseCase,
+		getTxnUseCase:      getTxnUseCase,
+		listTxnUseCase:     listTxnUseCase,
+		processTxnUseCase:  processTxnUseCase,
+		enqueueUseCase:     enqueueUseCase,
+		listRefundsUseCase: listRefundsUseCase,
+	}
+}
+
+// CreateTransaction handles POST /api/v2/transactions
+func (h *TransactionV2Handler) CreateTransaction(c *fiber.Ctx) error {
+	// Get partner ID from context (set by auth middleware)
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.CreateTransactionV2Request
+	if err := codec.Decode(c, &req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+	if req.IdempotencyKey == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "idempotency_key is required",
+			Param:   "idempotency_key",
+		})
+	}
+	money, err := valueobjects.NewMoney(req.Amount.Value, req.Amount.Currency)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "validation_error")
+	}
+	metadata, err := parseMetadataV2(req.Metadata)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "validation_error")
+	}
+
+	// Card, bank account or wallet details are optional but must match the payment method
+	var details *valueobjects.PaymentMethodDetails
+	if req.PaymentMethodDetails != nil {
+		details, err = parsePaymentMethodDetails(req.PaymentMethod, *req.PaymentMethodDetails)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	// Execute use case
+	livemode := middleware.GetLivemode(c)
+	output, err := h.createTxnUseCase.Execute(c.Context(), transaction.CreateTransactionInput{
+		PartnerID:            partnerID,
+		IdempotencyKey:       req.IdempotencyKey,
+		Amount:               money.Amount,
+		Currency:             money.Currency.String(),
+		PaymentMethod:        req.PaymentMethod,
+		Provider:             req.Provider,
+		PaymentMethodDetails: details,
+		CustomerEmail:        req.CustomerEmail,
+		CustomerName:         req.CustomerName,
+		CustomerPhone:        req.CustomerPhone,
+		BillingCountry:       req.BillingCountry,
+		Description:          req.Description,
+		Metadata:             metadata,
+		Livemode:             livemode,
+		IPAddress:            c.IP(),
+		UserAgent:            c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "transaction_creation_failed")
+	}
+
+	// Confirm in the same call when asked; a retried request only confirms
+	// a transaction that still waits for it
+	if req.Confirm && output.Status == string(entities.StatusPending) {
+		return h.confirm(c, partnerID, output.TransactionID, livemode)
+	}
+
+	txn, err := h.getTxnUseCase.Execute(c.Context(), output.TransactionID, partnerID, livemode)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "transaction_creation_failed")
+	}
+	return codec.Respond(c, fiber.StatusCreated, mapTransactionToV2DTO(txn))
+}
+
+// GetTransaction handles GET /api/v2/transactions/:id. Refunds and the
+// customer are embedded when asked for with ?expand=refunds,customer.
+func (h *TransactionV2Handler) GetTransaction(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID from URL
+	txnID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	expand, err := parseExpand(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Execute use case
+	txn, err := h.getTxnUseCase.Execute(c.Context(), txnID, partnerID, middleware.GetLivemode(c))
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusNotFound, "transaction_not_found")
+	}
+	response, err := h.transactionResponse(c.Context(), txn, expand)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_refunds")
+	}
+	return codec.Respond(c, fiber.StatusOK, response)
+}
+
+// ListTransactions handles GET /api/v2/transactions. expand applies to
+// every transaction of the page.
+func (h *TransactionV2Handler) ListTransactions(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse query parameters
+	var req dto.ListTransactionsV2Request
+	if err := c.QueryParser(&req); err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	// Set defaults
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	// Build query with the v1 filters, statuses translated
+	v1 := dto.ListTransactionsRequest{
+		Currency:      req.Currency,
+		DateFrom:      req.DateFrom,
+		DateTo:        req.DateTo,
+		StartingAfter: req.StartingAfter,
+		Limit:         req.Limit,
+	}
+	if req.Status != "" {
+		statuses, ok := intentStatuses[req.Status]
+		if !ok {
+			return respondDomainError(c, errors.NewValidationError("status", "unknown status "+req.Status), fiber.StatusBadRequest, "invalid_query_parameters")
+		}
+		names := make([]string, len(statuses))
+		for i, status := range statuses {
+			names[i] = string(status)
+		}
+		v1.Status = strings.Join(names, ",")
+	}
+	query, err := buildTransactionQuery(c, v1)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+	expand, err := parseExpand(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	// Execute use case
+	transactions, _, err := h.listTxnUseCase.Execute(c.Context(), partnerID, middleware.GetLivemode(c), query)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_transactions")
+	}
+
+	response := dto.ListTransactionsV2Response{
+		Data: make([]dto.TransactionV2Response, len(transactions)),
+	}
+	for i, txn := range transactions {
+		response.Data[i], err = h.transactionResponse(c.Context(), txn, expand)
+		if err != nil {
+			return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_refunds")
+		}
+	}
+	if len(transactions) == req.Limit {
+		response.HasMore = true
+		response.NextCursor = transactions[len(transactions)-1].ID.String()
+	}
+	response.Links = pageLinks(c, "/api/v2/transactions", response.NextCursor)
+	return codec.Respond(c, fiber.StatusOK, response)
+}
+
+// ConfirmTransaction handles POST /api/v2/transactions/:id/confirm
+func (h *TransactionV2Handler) ConfirmTransaction(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID
+	txnID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// Only the partner's own transactions, in the key's mode, are confirmed
+	livemode := middleware.GetLivemode(c)
+	if _, err := h.getTxnUseCase.Execute(c.Context(), txnID, partnerID, livemode); err != nil {
+		return respondDomainError(c, err, fiber.StatusNotFound, "transaction_not_found")
+	}
+	return h.confirm(c, partnerID, txnID, livemode)
+}
+
+// confirm processes a payment, or queues it in async mode, and answers with
+// the transaction: 200 once processed, whether it succeeded or failed, and
+// 202 while queued
+func (h *TransactionV2Handler) confirm(c *fiber.Ctx, partnerID, txnID uuid.UUID, livemode bool) error {
+	status := fiber.StatusOK
+	if h.enqueueUseCase != nil {
+		if _, err := h.enqueueUseCase.Execute(c.Context(), partnerID, txnID); err != nil {
+			return respondDomainError(c, err, fiber.StatusInternalServerError, "payment_processing_failed")
+		}
+		status = fiber.StatusAccepted
+	} else if err := h.processTxnUseCase.Execute(c.Context(), txnID); err != nil {
+		if conflict := asVersionConflict(err); conflict != nil {
+			return respondError(c, fiber.StatusConflict, versionConflictResponse(conflict))
+		}
+		if err == errors.ErrResourceLocked {
+			return respondError(c, fiber.StatusConflict, dto.ErrorResponse{
+				Error:   "processing_in_progress",
+				Message: "the transaction is being processed by another request, retry later",
+			})
+		}
+		// A declined payment is a failed transaction, not a failed call
+		txn, getErr := h.getTxnUseCase.Execute(c.Context(), txnID, partnerID, livemode)
+		if getErr != nil || txn.Status != entities.StatusFailed {
+			return respondDomainError(c, err, fiber.StatusInternalServerError, "payment_processing_failed")
+		}
+		return codec.Respond(c, status, mapTransactionToV2DTO(txn))
+	}
+
+	txn, err := h.getTxnUseCase.Execute(c.Context(), txnID, partnerID, livemode)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "payment_processing_failed")
+	}
+	return codec.Respond(c, status, mapTransactionToV2DTO(txn))
+}
+
+// parseMetadataV2 checks v2 metadata against its limits
+func parseMetadataV2(metadata map[string]string) (map[string]interface{}, error) {
+	if len(metadata) > maxMetadataKeys {
+		return nil, errors.NewValidationError("metadata", fmt.Sprintf("must have at most %d keys", maxMetadataKeys))
+	}
+	parsed := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		if key == "" || len(key) > maxMetadataKeyLength {
+			return nil, errors.NewValidationError("metadata", fmt.Sprintf("keys must have 1 to %d characters", maxMetadataKeyLength))
+		}
+		if len(value) > maxMetadataValueLength {
+			return nil, errors.NewValidationError("metadata", fmt.Sprintf("values must have at most %d characters", maxMetadataValueLength))
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
+
+// intentStatus returns the v2 status of a transaction
+func intentStatus(status entities.TransactionStatus) string {
+	for intent, statuses := range intentStatuses {
+		for _, s := range statuses {
+			if s == status {
+				return intent
+			}
+		}
+	}
+	return string(status)
+}
+
+// transactionResponse maps a transaction to its v2 response DTO, with the
+// related resources expand asks for
+func (h *TransactionV2Handler) transactionResponse(ctx context.Context, txn *entities.Transaction, expand transactionExpansions) (dto.TransactionV2Response, error) {
+	response := mapTransactionToV2DTO(txn)
+	if expand.customer {
+		response.Customer = mapCustomerToDTO(txn)
+	}
+	if expand.refunds {
+		refunds, hasMore, err := transactionRefunds(ctx, h.listRefundsUseCase, txn)
+		if err != nil {
+			return response, err
+		}
+		response.Refunds = &dto.TransactionRefundsV2Response{
+			Data:    make([]dto.RefundV2Response, len(refunds)),
+			HasMore: hasMore,
+		}
+		for i, refund := range refunds {
+			response.Refunds.Data[i] = mapRefundToV2DTO(refund)
+		}
+	}
+	return response, nil
+}
+
+// mapRefundToV2DTO maps a refund to its v2 response DTO
+func mapRefundToV2DTO(refund *entities.Refund) dto.RefundV2Response {
+	return dto.RefundV2Response{
+		ID:            refund.ID.String(),
+		Object:        "refund",
+		TransactionID: refund.TransactionID.String(),
+		Amount:        dto.Money{Value: refund.Amount.Amount, Currency: refund.Amount.Currency.String()},
+		Status:        string(refund.Status),
+		Reason:        string(refund.Reason.Code),
+		ReasonNote:    refund.Reason.Note,
+		ApprovedAt:    refund.ApprovedAt,
+		CancelledAt:   refund.CancelledAt,
+		CreatedAt:     refund.CreatedAt,
+	}
+}
+
+// mapTransactionToV2DTO maps a transaction to its v2 response DTO
+func mapTransactionToV2DTO(txn *entities.Transaction) dto.TransactionV2Response {
+	// Metadata of v1 transactions may hold other JSON values; v2 shows
+	// them as strings
+	metadata := make(map[string]string, len(txn.Metadata))
+	for key, value := range txn.Metadata {
+		if s, ok := value.(string); ok {
+			metadata[key] = s
+		} else {
+			metadata[key] = fmt.Sprint(value)
+		}
+	}
+
+	response := dto.TransactionV2Response{
+		ID:             txn.ID.String(),
+		Object:         "transaction",
+		IdempotencyKey: txn.IdempotencyKey,
+		Amount:         dto.Money{Value: txn.Amount.Amount, Currency: txn.Amount.Currency.String()},
+		AmountRefunded: dto.Money{
+			Value:    txn.RefundedAmount.Amount,
+			Currency: txn.Amount.Currency.String(),
+		},
+		Status:                intentStatus(txn.Status),
+		PaymentMethod:         txn.PaymentMethod.String(),
+		PaymentMethodDetails:  mapPaymentMethodDetailsToDTO(txn.PaymentMethodDetails),
+		Provider:              txn.Provider.String(),
+		ProviderTransactionID: txn.ProviderTransactionID,
+		Livemode:              txn.Livemode,
+		CustomerEmail:         txn.CustomerEmail,
+		CustomerName:          txn.CustomerName,
+		CustomerPhone:         txn.CustomerPhone,
+		BillingCountry:        txn.BillingCountry.String(),
+		Description:           txn.Description,
+		Metadata:              metadata,
+		CreatedAt:             txn.CreatedAt,
+		UpdatedAt:             txn.UpdatedAt,
+		ProcessedAt:           txn.ProcessedAt,
+	}
+	if txn.ErrorCode != "" || txn.ErrorMessage != "" {
+		response.LastError = &dto.TransactionErrorV2{Code: txn.ErrorCode, Message: txn.ErrorMessage}
+	}
+	response.Links = transactionLinks("/api/v2/transactions", txn, "confirm")
+	return response
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/usage"
+)
+
+// defaultUsageDays is how many days usage is read for without a range
+const defaultUsageDays = 30
+
+// UsageHandler serves metered API and payment usage
+type UsageHandler struct {
+	getUsageUC       *usage.GetUsageUseCase
+	getUsageRollupUC *usage.GetUsageRollupUseCase
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(getUsageUC *usage.GetUsageUseCase, getUsageRollupUC *usage.GetUsageRollupUseCase) *UsageHandler {
+	return &UsageHandler{
+		getUsageUC:       getUsageUC,
+		getUsageRollupUC: getUsageRollupUC,
+	}
+}
+
+// GetUsage handles GET /api/v1/usage: the partner's own usage per day
+func (h *UsageHandler) GetUsage(c *fiber.Ctx) error {
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	from, to, err := parseUsageRange(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	days, err := h.getUsageUC.Execute(c.Context(), partnerID, from, to)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_usage")
+	}
+
+	response := dto.ListUsageResponse{
+		DateFrom: from.Format("2006-01-02"),
+		DateTo:   to.Format("2006-01-02"),
+		Days:     make([]dto.DailyUsageResponse, len(days)),
+	}
+	for i, day := range days {
+		response.Days[i] = dto.DailyUsageResponse{
+			Date:          day.Day.Format("2006-01-02"),
+			UsageResponse: mapUsageToDTO(day.Usage),
+		}
+	}
+	return c.JSON(response)
+}
+
+// GetUsageRollup handles GET /api/v1/admin/usage: every partner's usage
+// over the range, for billing and capacity planning
+func (h *UsageHandler) GetUsageRollup(c *fiber.Ctx) error {
+	from, to, err := parseUsageRange(c)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusBadRequest, "invalid_query_parameters")
+	}
+
+	partners, err := h.getUsageRollupUC.Execute(c.Context(), from, to)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_get_usage")
+	}
+
+	response := dto.UsageRollupResponse{
+		DateFrom: from.Format("2006-01-02"),
+		DateTo:   to.Format("2006-01-02"),
+		Partners: make([]dto.PartnerUsageResponse, len(partners)),
+	}
+	for i, partner := range partners {
+		response.Partners[i] = dto.PartnerUsageResponse{
+			PartnerID:     partner.PartnerID.String(),
+			UsageResponse: mapUsageToDTO(partner.Usage),
+		}
+	}
+	return c.JSON(response)
+}
+
+// parseUsageRange reads date_from and date_to, defaulting to the last
+// defaultUsageDays days up to today
+func parseUsageRange(c *fiber.Ctx) (from, to time.Time, err error) {
+	var req dto.UsageRequest
+	if err := c.QueryParser(&req); err != nil {
+		return from, to, err
+	}
+	return parseDateRange(req.DateFrom, req.DateTo)
+}
+
+// parseDateRange parses a range of days given as YYYY-MM-DD, defaulting to
+// the last defaultUsageDays days up to today
+func parseDateRange(dateFrom, dateTo string) (from, to time.Time, err error) {
+	to = time.Now().UTC().Truncate(24 * time.Hour)
+	if dateTo != "" {
+		if to, err = time.Parse("2006-01-02", dateTo); err != nil {
+			return from, to, errors.NewValidationError("date_to", "must be YYYY-MM-DD")
+		}
+	}
+	from = to.AddDate(0, 0, 1-defaultUsageDays)
+	if dateFrom != "" {
+		if from, err = time.Parse("2006-01-02", dateFrom); err != nil {
+			return from, to, errors.NewValidationError("date_from", "must be YYYY-MM-DD")
+		}
+	}
+	return from, to, nil
+}
+
+// mapUsageToDTO maps usage to its response DTO
+func mapUsageToDTO(u usage.Usage) dto.UsageResponse {
+	response := dto.UsageResponse{
+		APICalls:     u.APICalls,
+		ClientErrors: u.ClientErrors,
+		ServerErrors: u.ServerErrors,
+		ErrorRate:    u.ErrorRate(),
+		Payments:     make([]dto.PaymentVolumeResponse, len(u.Payments)),
+	}
+	for i, p := range u.Payments {
+		response.Payments[i] = dto.PaymentVolumeResponse{
+			Currency:       p.Currency,
+			Payments:       p.Payments,
+			FailedPayments: p.FailedPayments,
+			Volume:         valueobjects.FormatMinorUnits(p.Volume, valueobjects.Currency(p.Currency)),
+		}
+	}
+	return response
+}
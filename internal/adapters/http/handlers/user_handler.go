@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/user"
+)
+
+// UserHandler handles partner team management requests
+type UserHandler struct {
+	createUseCase     *user.CreateUserUseCase
+	listUseCase       *user.ListUsersUseCase
+	updateRoleUseCase *user.UpdateUserRoleUseCase
+	removeUseCase     *user.RemoveUserUseCase
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(
+	createUseCase *user.CreateUserUseCase,
+	listUseCase *user.ListUsersUseCase,
+	updateRoleUseCase *user.UpdateUserRoleUseCase,
+	removeUseCase *user.RemoveUserUseCase,
+) *UserHandler {
+	return &UserHandler{
+		createUseCase:     createUseCase,
+		listUseCase:       listUseCase,
+		updateRoleUseCase: updateRoleUseCase,
+		removeUseCase:     removeUseCase,
+	}
+}
+
+// CreateUser handles POST /api/v1/users
+func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse request body
+	var req dto.CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	created, err := h.createUseCase.Execute(c.Context(), user.CreateUserInput{
+		PartnerID: partnerID,
+		Email:     req.Email,
+		Name:      req.Name,
+		Role:      req.Role,
+		Password:  req.Password,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		if err == errors.ErrUserAlreadyExists {
+			return respondError(c, fiber.StatusConflict, dto.ErrorResponse{
+				Error:   "user_already_exists",
+				Message: err.Error(),
+			})
+		}
+		return respondDomainError(c, err, fiber.StatusBadRequest, "user_creation_failed")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(mapUserToDTO(created))
+}
+
+// ListUsers handles GET /api/v1/users
+func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Execute use case
+	users, err := h.listUseCase.Execute(c.Context(), partnerID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "failed_to_list_users")
+	}
+
+	response := dto.ListUsersResponse{
+		Users: make([]dto.UserResponse, len(users)),
+	}
+	for i, u := range users {
+		response.Users[i] = mapUserToDTO(u)
+	}
+	return c.JSON(response)
+}
+
+// UpdateUserRole handles PATCH /api/v1/users/:id
+func (h *UserHandler) UpdateUserRole(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse user ID
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "invalid user ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.UpdateUserRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+
+	// Execute use case
+	updated, err := h.updateRoleUseCase.Execute(c.Context(), user.UpdateUserRoleInput{
+		UserID:    userID,
+		PartnerID: partnerID,
+		Role:      req.Role,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return h.handleUserError(c, err, "user_update_failed")
+	}
+
+	return c.JSON(mapUserToDTO(updated))
+}
+
+// RemoveUser handles DELETE /api/v1/users/:id
+func (h *UserHandler) RemoveUser(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse user ID
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "invalid user ID format",
+		})
+	}
+
+	// Execute use case
+	err = h.removeUseCase.Execute(c.Context(), user.RemoveUserInput{
+		UserID:    userID,
+		PartnerID: partnerID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return h.handleUserError(c, err, "user_removal_failed")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "user removed",
+	})
+}
+
+// handleUserError maps team management errors to HTTP responses
+func (h *UserHandler) handleUserError(c *fiber.Ctx, err error, code string) error {
+	switch err {
+	case errors.ErrUserNotFound:
+		return respondDomainError(c, err, fiber.StatusNotFound, "user_not_found")
+	case errors.ErrLastOwner:
+		return respondDomainError(c, err, fiber.StatusConflict, "last_owner")
+	}
+	return respondDomainError(c, err, fiber.StatusBadRequest, code)
+}
+
+// mapUserToDTO maps a user entity to its response DTO
+func mapUserToDTO(u *entities.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:          u.ID.String(),
+		Email:       u.Email,
+		Name:        u.Name,
+		Role:        u.Role.String(),
+		CreatedAt:   u.CreatedAt,
+		LastLoginAt: u.LastLoginAt,
+	}
+}
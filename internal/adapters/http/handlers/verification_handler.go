@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/notification"
+)
+
+// VerificationHandler handles the SMS verification codes customers confirm
+// payments with
+type VerificationHandler struct {
+	sendCodeUseCase   *notification.SendVerificationCodeUseCase
+	verifyCodeUseCase *notification.VerifyCodeUseCase
+}
+
+// NewVerificationHandler creates a new verification handler
+func NewVerificationHandler(
+	sendCodeUseCase *notification.SendVerificationCodeUseCase,
+	verifyCodeUseCase *notification.VerifyCodeUseCase,
+) *VerificationHandler {
+	return &VerificationHandler{
+		sendCodeUseCase:   sendCodeUseCase,
+		verifyCodeUseCase: verifyCodeUseCase,
+	}
+}
+
+// SendCode handles POST /api/v1/transactions/:id/verification-code
+func (h *VerificationHandler) SendCode(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID
+	txnID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// Execute use case
+	expiresAt, err := h.sendCodeUseCase.Execute(c.Context(), partnerID, txnID)
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "verification_code_failed")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.VerificationCodeResponse{
+		ExpiresAt: expiresAt.UTC(),
+	})
+}
+
+// VerifyCode handles POST /api/v1/transactions/:id/verification-code/verify
+func (h *VerificationHandler) VerifyCode(c *fiber.Ctx) error {
+	// Get partner ID
+	partnerID, err := middleware.GetPartnerID(c)
+	if err != nil {
+		return respondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "authentication required",
+		})
+	}
+
+	// Parse transaction ID
+	txnID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "invalid transaction ID format",
+		})
+	}
+
+	// Parse request body
+	var req dto.VerifyCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "invalid request body",
+		})
+	}
+	if req.Code == "" {
+		return respondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "code is required",
+			Param:   "code",
+		})
+	}
+
+	// Execute use case
+	err = h.verifyCodeUseCase.Execute(c.Context(), notification.VerifyCodeInput{
+		PartnerID:     partnerID,
+		TransactionID: txnID,
+		Code:          req.Code,
+		IPAddress:     c.IP(),
+		UserAgent:     c.Get("User-Agent"),
+	})
+	if err != nil {
+		return respondDomainError(c, err, fiber.StatusInternalServerError, "verification_code_failed")
+	}
+
+	return c.JSON(dto.VerifyCodeResponse{
+		TransactionID: txnID.String(),
+		Verified:      true,
+	})
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AccessLog middleware writes one JSON line per request for compliance
+// review: who called what, the outcome and what was sent, with card
+// numbers, secrets and emails redacted
+type AccessLog struct {
+	maxBodyBytes int
+	onError      func(error)
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// accessLogEntry is one line of the access log
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id"`
+	PartnerID string    `json:"partner_id,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Query     string    `json:"query,omitempty"`
+	Status    int       `json:"status"`
+	LatencyMs float64   `json:"latency_ms"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Body      string    `json:"body,omitempty"`
+	// BodyTruncated is set when the redacted body was cut at the limit
+	BodyTruncated bool `json:"body_truncated,omitempty"`
+}
+
+// NewAccessLog creates an access log writing to w. Redacted request bodies
+// are kept up to maxBodyBytes; 0 leaves them out. onError is told about
+// lines that could not be written.
+func NewAccessLog(w io.Writer, maxBodyBytes int, onError func(error)) *AccessLog {
+	return &AccessLog{w: w, maxBodyBytes: maxBodyBytes, onError: onError}
+}
+
+// Handle logs the request once the rest of the chain has answered it
+func (m *AccessLog) Handle(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	entry := accessLogEntry{
+		Time:      start.UTC(),
+		RequestID: GetRequestID(c),
+		Method:    c.Method(),
+		Path:      redactString(c.Path()),
+		Status:    responseStatus(c, err),
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+	if id, ok := c.Locals("partner_id").(uuid.UUID); ok {
+		entry.PartnerID = id.String()
+	}
+	if query := c.Request().URI().QueryString(); len(query) > 0 {
+		values, parseErr := url.ParseQuery(string(query))
+		if parseErr != nil {
+			entry.Query = "[invalid query]"
+		} else {
+			entry.Query = RedactQuery(values)
+		}
+	}
+	if m.maxBodyBytes > 0 {
+		entry.Body = RedactBody(c.Get(fiber.HeaderContentType), c.Body())
+		if len(entry.Body) > m.maxBodyBytes {
+			entry.Body = entry.Body[:m.maxBodyBytes]
+			entry.BodyTruncated = true
+		}
+	}
+
+	m.write(entry)
+	return err
+}
+
+// write appends entry as one line; lines of concurrent requests never
+// interleave
+func (m *AccessLog) write(entry accessLogEntry) {
+	line, err := json.Marshal(entry)
+	if err == nil {
+		line = append(line, '\n')
+		m.mu.Lock()
+		_, err = m.w.Write(line)
+		m.mu.Unlock()
+	}
+	if err != nil && m.onError != nil {
+		m.onError(err)
+	}
+}
+
+// responseStatus is the status the client gets. Errors returned down the
+// chain become responses in the app's error handler later on, so their
+// status is worked out the same way.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if stderrors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// AdminAuthMiddleware validates admin session tokens for back-office operations.
+// Admins sign in at /admin/auth/login with password and TOTP; partner
+// credentials are never accepted here.
+type AdminAuthMiddleware struct {
+	adminRepo   ports.AdminUserRepository
+	sessionRepo ports.AdminSessionRepository
+}
+
+// NewAdminAuthMiddleware creates a new admin auth middleware
+func NewAdminAuthMiddleware(adminRepo ports.AdminUserRepository, sessionRepo ports.AdminSessionRepository) *AdminAuthMiddleware {
+	return &AdminAuthMiddleware{
+		adminRepo:   adminRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Handle validates the admin session and sets the admin identity in context
+func (m *AdminAuthMiddleware) Handle(c *fiber.Ctx) error {
+	// Expected format: "Bearer <admin_session_token>"
+	token, found := strings.CutPrefix(c.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return RespondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "missing or invalid authorization header",
+		})
+	}
+
+	if !entities.IsAdminSessionToken(token) {
+		return RespondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "admin session required",
+		})
+	}
+
+	admin, session, err := m.verifySession(c, token)
+	if err != nil {
+		return adminSessionExpired(c)
+	}
+
+	return m.authenticate(c, admin, session)
+}
+
+// authenticate stores the admin identity in context and continues the chain
+func (m *AdminAuthMiddleware) authenticate(c *fiber.Ctx, admin *entities.AdminUser, session *entities.AdminSession) error {
+	c.Locals("admin_id", admin.Email)
+	c.Locals("admin", admin)
+	c.Locals("admin_session", session)
+
+	return c.Next()
+}
+
+// adminSessionExpired rejects a request with an unusable admin session
+func adminSessionExpired(c *fiber.Ctx) error {
+	return RespondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+		Error:   "unauthorized",
+		Message: errors.ErrSessionExpired.Error(),
+	})
+}
+
+// verifySession resolves a session token to an active admin
+func (m *AdminAuthMiddleware) verifySession(c *fiber.Ctx, token string) (*entities.AdminUser, *entities.AdminSession, error) {
+	session, err := m.sessionRepo.GetByTokenHash(c.Context(), entities.HashSessionToken(token))
+	if err != nil || session == nil {
+		return nil, nil, errors.ErrSessionNotFound
+	}
+
+	if err := session.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	// Disabled admins lose access immediately
+	admin, err := m.adminRepo.GetByID(c.Context(), session.AdminID)
+	if err != nil || admin == nil || admin.IsDisabled() {
+		return nil, nil, errors.ErrSessionExpired
+	}
+
+	return admin, session, nil
+}
+
+// AdminOrPartner authenticates admin session tokens as back-office admins and
+// everything else as partner credentials, so one route can serve both
+func AdminOrPartner(adminAuth *AdminAuthMiddleware, auth *AuthMiddleware) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, found := strings.CutPrefix(c.Get("Authorization"), "Bearer ")
+		if found && entities.IsAdminSessionToken(token) {
+			admin, session, err := adminAuth.verifySession(c, token)
+			if err == nil {
+				return adminAuth.authenticate(c, admin, session)
+			}
+			if err != errors.ErrSessionNotFound {
+				return adminSessionExpired(c)
+			}
+			// Not an admin session after all; an API key may share the prefix by chance
+		}
+
+		return auth.Handle(c)
+	}
+}
+
+// GetAdminID retrieves the authenticated admin identity (their email) from context
+func GetAdminID(c *fiber.Ctx) (string, error) {
+	if id, ok := c.Locals("admin_id").(string); ok && id != "" {
+		return id, nil
+	}
+
+	return "", errors.ErrUnauthorizedOperation
+}
+
+// GetAdminSession retrieves the admin session, if the request used one
+func GetAdminSession(c *fiber.Ctx) (*entities.AdminSession, bool) {
+	session, ok := c.Locals("admin_session").(*entities.AdminSession)
+	return session, ok
+}
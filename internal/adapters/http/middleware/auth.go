@@ -2,88 +2,375 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/domain/entities"
 	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/apikey"
 	"Pay2Go/internal/usecases/ports"
 )
 
-// AuthMiddleware validates API key and sets partner context
+const (
+	// hmacScheme is the Authorization scheme for signed requests:
+	// "HMAC-SHA256 <key_prefix>:<hex signature>"
+	hmacScheme = "HMAC-SHA256"
+
+	// timestampHeader carries the Unix time (seconds) the request was signed at
+	timestampHeader = "X-Pay2Go-Timestamp"
+
+	// signatureTolerance bounds clock skew and how long a signature stays usable
+	signatureTolerance = 5 * time.Minute
+)
+
+// errReplayCheckUnavailable is returned when the replay store cannot say
+// whether a signature was used before
+var errReplayCheckUnavailable = stderrors.New("signature replay check unavailable, retry the request")
+
+// AuthMiddleware validates API keys or team member sessions and sets partner context
 type AuthMiddleware struct {
-	partnerRepo ports.PartnerRepository
+	partnerRepo   ports.PartnerRepository
+	authenticator *apikey.Authenticator
+	usageTracker  *apikey.UsageTracker
+	guard         *apikey.FailedAuthGuard
+	userRepo      ports.UserRepository
+	sessionRepo   ports.UserSessionRepository
+
+	// replayStore remembers signatures seen within the tolerance window
+	replayStore ports.RateLimitStore
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(partnerRepo ports.PartnerRepository) *AuthMiddleware {
+func NewAuthMiddleware(
+	partnerRepo ports.PartnerRepository,
+	authenticator *apikey.Authenticator,
+	usageTracker *apikey.UsageTracker,
+	guard *apikey.FailedAuthGuard,
+	userRepo ports.UserRepository,
+	sessionRepo ports.UserSessionRepository,
+	replayStore ports.RateLimitStore,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		partnerRepo: partnerRepo,
+		partnerRepo:   partnerRepo,
+		authenticator: authenticator,
+		usageTracker:  usageTracker,
+		guard:         guard,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		replayStore:   replayStore,
 	}
 }
 
-// Handle validates API key and authenticates partner
+// Handle validates API key, request signature or session token and authenticates partner
 func (m *AuthMiddleware) Handle(c *fiber.Ctx) error {
 	// Get API key from Authorization header
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "unauthorized",
-			"message": "missing authorization header",
+		return RespondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "missing authorization header",
 		})
 	}
 
-	// Expected format: "Bearer <api_key>"
+	// Expected format: "Bearer <api_key|session_token>" or "HMAC-SHA256 <key_prefix>:<signature>"
 	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "unauthorized",
-			"message": "invalid authorization header format",
+	if len(parts) != 2 || (parts[0] != "Bearer" && parts[0] != hmacScheme) {
+		return RespondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "invalid authorization header format",
 		})
 	}
 
-	apiKey := parts[1]
-	if len(apiKey) < 8 {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "unauthorized",
-			"message": "invalid API key",
+	// Refuse locked-out callers before any repository lookup
+	prefix := credentialPrefix(parts[0], parts[1])
+	if m.guard != nil {
+		if retryAfter, err := m.guard.Check(c.Context(), prefix, c.IP()); err != nil {
+			return respondLockedOut(c, retryAfter, err)
+		}
+	}
+
+	// Team member sessions carry the scopes of the member's role
+	if parts[0] == "Bearer" && entities.IsSessionToken(parts[1]) {
+		user, session, err := m.verifySession(c, parts[1])
+		if err == nil {
+			return m.authenticate(c, user.PartnerID, user.Role.Scopes(), true, func() {
+				c.Locals("user", user)
+				c.Locals("session", session)
+			})
+		}
+		if err != errors.ErrSessionNotFound {
+			m.recordFailure(c, prefix)
+			return RespondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "unauthorized",
+				Message: errors.ErrSessionExpired.Error(),
+			})
+		}
+		// Not a session after all; an API key may share the prefix by chance
+	}
+
+	var key *entities.APIKey
+	var err error
+	if parts[0] == hmacScheme {
+		key, err = m.verifySignedRequest(c, parts[1])
+	} else {
+		key, err = m.authenticator.Authenticate(c.Context(), parts[1])
+	}
+	if err == errReplayCheckUnavailable {
+		// Without the replay store a signature could be reused; refuse it
+		return RespondError(c, fiber.StatusServiceUnavailable, dto.ErrorResponse{
+			Error:   "service_unavailable",
+			Message: err.Error(),
 		})
 	}
+	if err != nil {
+		m.recordFailure(c, prefix)
 
-	// Get API key prefix (first 8 characters)
-	prefix := apiKey[:8]
+		message := "invalid API key"
+		switch err {
+		case errors.ErrAPIKeyRevoked, errors.ErrAuthMethod, errors.ErrInvalidSignature,
+			errors.ErrSignatureExpired, errors.ErrSignatureReplayed:
+			message = err.Error()
+		}
+		return RespondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: message,
+		})
+	}
 
-	// Find partner by prefix
-	partner, err := m.partnerRepo.GetByAPIKeyPrefix(c.Context(), prefix)
+	return m.authenticate(c, key.PartnerID, key.Scopes, key.Livemode, func() {
+		c.Locals("api_key", key)
+		if m.usageTracker != nil {
+			m.usageTracker.Record(key.ID, c.IP())
+		}
+	})
+}
+
+// respondLockedOut refuses a caller locked out by the failed authentication
+// guard, telling them when they may retry
+func respondLockedOut(c *fiber.Ctx, retryAfter time.Duration, err error) error {
+	retrySeconds := int(retryAfter.Seconds() + 0.999)
+	if retrySeconds < 1 {
+		retrySeconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retrySeconds))
+
+	return RespondError(c, fiber.StatusTooManyRequests, dto.ErrorResponse{
+		Error:   "too_many_failed_attempts",
+		Message: err.Error(),
+	})
+}
+
+// recordFailure counts a failed attempt towards the caller's lockout
+func (m *AuthMiddleware) recordFailure(c *fiber.Ctx, prefix string) {
+	if m.guard != nil {
+		m.guard.RecordFailure(c.Context(), prefix, c.IP(), c.Get("User-Agent"))
+	}
+}
+
+// credentialPrefix returns the identifying prefix of the presented credential
+func credentialPrefix(scheme, credentials string) string {
+	if scheme == hmacScheme {
+		prefix, _, _ := strings.Cut(credentials, ":")
+		return prefix
+	}
+
+	if len(credentials) > 8 {
+		return credentials[:8]
+	}
+
+	return credentials
+}
+
+// authenticate loads the credential's partner and sets the request context
+func (m *AuthMiddleware) authenticate(
+	c *fiber.Ctx,
+	partnerID uuid.UUID,
+	scopes []valueobjects.APIKeyScope,
+	livemode bool,
+	setCredential func(),
+) error {
+	// Load the owning partner; a cached copy will do
+	partner, err := m.partnerRepo.GetByID(ports.ReadOnly(c.Context()), partnerID)
 	if err != nil || partner == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "unauthorized",
-			"message": "invalid API key",
+		return RespondError(c, fiber.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "invalid API key",
 		})
 	}
 
-	// Validate API key
-	if err := partner.ValidateAPIKey(apiKey); err != nil {
-		if err == errors.ErrPartnerInactive {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error":   "forbidden",
-				"message": "partner account is inactive",
-			})
-		}
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error":   "unauthorized",
-			"message": "invalid API key",
+	if !partner.IsActive {
+		return RespondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+			Error:   "forbidden",
+			Message: "partner account is inactive",
 		})
 	}
 
-	// Set partner ID in context
+	// Set partner and credential in context
 	c.Locals("partner_id", partner.ID)
 	c.Locals("partner", partner)
+	c.Locals("scopes", scopes)
+	c.Locals("livemode", livemode)
+	setCredential()
 
 	return c.Next()
 }
 
+// verifySession authenticates a team member's session token
+func (m *AuthMiddleware) verifySession(c *fiber.Ctx, token string) (*entities.User, *entities.UserSession, error) {
+	session, err := m.sessionRepo.GetByTokenHash(c.Context(), entities.HashSessionToken(token))
+	if err != nil || session == nil {
+		return nil, nil, errors.ErrSessionNotFound
+	}
+
+	if err := session.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	// Removed members lose access immediately
+	user, err := m.userRepo.GetByID(c.Context(), session.UserID)
+	if err != nil || user == nil || user.IsDeleted() {
+		return nil, nil, errors.ErrSessionExpired
+	}
+
+	return user, session, nil
+}
+
+// verifySignedRequest authenticates a request signed with an HMAC key.
+// The signature covers the timestamp, method, path with query and body digest,
+// so a leaked signature cannot be reused for another request or after the tolerance.
+func (m *AuthMiddleware) verifySignedRequest(c *fiber.Ctx, credentials string) (*entities.APIKey, error) {
+	prefix, signature, ok := strings.Cut(credentials, ":")
+	if !ok || len(prefix) != 8 || signature == "" {
+		return nil, errors.ErrInvalidAPIKey
+	}
+	// Signatures are remembered as sent, so they must have one spelling:
+	// the lowercase hex of an HMAC-SHA256
+	if !isSignatureHex(signature) {
+		return nil, errors.ErrInvalidSignature
+	}
+
+	// Reject requests signed outside the tolerance window
+	timestamp := c.Get(timestampHeader)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.ErrSignatureExpired
+	}
+	if skew := time.Since(time.Unix(signedAt, 0)); skew > signatureTolerance || skew < -signatureTolerance {
+		return nil, errors.ErrSignatureExpired
+	}
+
+	key, err := m.authenticator.AuthenticateSigned(c.Context(), prefix, signingString(c, timestamp), signature)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each signature is accepted once; it expires with the tolerance window on both sides
+	if m.replayStore != nil {
+		result, err := m.replayStore.Allow(c.Context(), "replay:"+prefix+":"+signature, 1, 2*signatureTolerance)
+		if err != nil {
+			return nil, errReplayCheckUnavailable
+		}
+		if !result.Allowed {
+			return nil, errors.ErrSignatureReplayed
+		}
+	}
+
+	return key, nil
+}
+
+// isSignatureHex reports whether signature is the lowercase hex of an
+// HMAC-SHA256
+func isSignatureHex(signature string) bool {
+	if len(signature) != 2*sha256.Size {
+		return false
+	}
+	for _, r := range signature {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// signingString builds the canonical message partners sign:
+// timestamp, method, path with query and hex SHA-256 of the body, newline-separated
+func signingString(c *fiber.Ctx, timestamp string) string {
+	bodyDigest := sha256.Sum256(c.Body())
+
+	return strings.Join([]string{
+		timestamp,
+		c.Method(),
+		c.OriginalURL(),
+		hex.EncodeToString(bodyDigest[:]),
+	}, "\n")
+}
+
+// RequireScope rejects requests whose API key or session lacks the given scope
+func RequireScope(scope valueobjects.APIKeyScope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scopes, _ := c.Locals("scopes").([]valueobjects.APIKeyScope)
+		if !valueobjects.HasScope(scopes, scope) {
+			return RespondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+				Error:   "insufficient_scope",
+				Message: "API key requires the " + scope.String() + " scope",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireRole rejects team member sessions whose role is not one of roles.
+// API key requests are governed by scopes alone and pass through.
+func RequireRole(roles ...valueobjects.UserRole) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if user, ok := GetUser(c); ok && !user.HasRole(roles...) {
+			return RespondError(c, fiber.StatusForbidden, dto.ErrorResponse{
+				Error:   "insufficient_role",
+				Message: "your role does not allow this action",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// GetUser retrieves the signed-in team member, if the request used a session
+func GetUser(c *fiber.Ctx) (*entities.User, bool) {
+	user, ok := c.Locals("user").(*entities.User)
+	return user, ok
+}
+
+// GetSession retrieves the team member session, if the request used one
+func GetSession(c *fiber.Ctx) (*entities.UserSession, bool) {
+	session, ok := c.Locals("session").(*entities.UserSession)
+	return session, ok
+}
+
+// GetPartner retrieves the authenticated partner, which may be a cached copy
+func GetPartner(c *fiber.Ctx) (*entities.Partner, bool) {
+	partner, ok := c.Locals("partner").(*entities.Partner)
+	return partner, ok
+}
+
+// GetLivemode reports whether the request was made with a live API key
+func GetLivemode(c *fiber.Ctx) bool {
+	if livemode, ok := c.Locals("livemode").(bool); ok {
+		return livemode
+	}
+
+	return true
+}
+
 // GetPartnerID retrieves partner ID from context
 func GetPartnerID(c *fiber.Ctx) (uuid.UUID, error) {
 	partnerID := c.Locals("partner_id")
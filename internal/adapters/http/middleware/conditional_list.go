@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// ConditionalList lets partners polling a list skip pages that did not
+// change. Each page gets an ETag of its body, which includes the page's
+// next_cursor, so a page is only reported unchanged when the next one starts
+// at the same place too; a matching If-None-Match gets 304 Not Modified.
+type ConditionalList struct {
+	etag fiber.Handler
+}
+
+// NewConditionalList creates a new conditional list middleware
+func NewConditionalList() *ConditionalList {
+	return &ConditionalList{
+		// Weak, since compression changes the bytes but not the page
+		etag: etag.New(etag.Config{Weak: true}),
+	}
+}
+
+// Handle tags the page and answers If-None-Match
+func (m *ConditionalList) Handle(c *fiber.Ctx) error {
+	// Pages belong to one partner, and clients must check back every time
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+
+	// The etag middleware only tags the page; it compares a weak tag with
+	// the exact bytes of If-None-Match, which proxies may have stripped of
+	// W/ or sent in a list
+	ifNoneMatch := strings.Clone(c.Get(fiber.HeaderIfNoneMatch))
+	c.Request().Header.Del(fiber.HeaderIfNoneMatch)
+	if err := m.etag(c); err != nil {
+		return err
+	}
+
+	tag := string(c.Response().Header.Peek(fiber.HeaderETag))
+	if ifNoneMatch == "" || tag == "" || !etagListMatches(ifNoneMatch, tag) {
+		return nil
+	}
+	c.Context().ResetBody()
+	return c.SendStatus(fiber.StatusNotModified)
+}
+
+// etagListMatches reports whether the If-None-Match list matches tag by weak
+// comparison (RFC 9110, section 13.1.2), which ignores the W/ of either
+func etagListMatches(list, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+)
+
+// ErrorDocsURL is where error codes are documented; the doc_url of an error
+// is this followed by its code
+const ErrorDocsURL = "https://github.com/RookieJoel/Pay2Go/blob/main/docs/ERRORS.md#"
+
+// Error types, the class of an error by its status
+const (
+	ErrorTypeInvalidRequest = "invalid_request_error"
+	ErrorTypeAuthentication = "authentication_error"
+	ErrorTypePermission     = "permission_error"
+	ErrorTypeConflict       = "conflict_error"
+	ErrorTypeRateLimit      = "rate_limit_error"
+	ErrorTypeAPI            = "api_error"
+)
+
+// ErrorType returns the type of an error answered with status
+func ErrorType(status int) string {
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		return ErrorTypeAPI
+	case status == fiber.StatusUnauthorized:
+		return ErrorTypeAuthentication
+	case status == fiber.StatusForbidden:
+		return ErrorTypePermission
+	case status == fiber.StatusConflict, status == fiber.StatusPreconditionFailed:
+		return ErrorTypeConflict
+	case status == fiber.StatusTooManyRequests:
+		return ErrorTypeRateLimit
+	default:
+		return ErrorTypeInvalidRequest
+	}
+}
+
+// StatusErrorCode returns the code of an error known only by its status,
+// e.g. not_found for 404
+func StatusErrorCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "unknown_error"
+	}
+	return strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(text, "-", " ")), " ", "_")
+}
+
+// RespondError writes response as the error envelope: its code, set in
+// Error or Code, is completed with the type, the documentation URL and the
+// ID of the request, which partners quote to support. Server errors are
+// passed on to the error tracker.
+func RespondError(c *fiber.Ctx, status int, response dto.ErrorResponse) error {
+	if response.Code == "" {
+		response.Code = response.Error
+	}
+	response.Error = response.Code
+	response.Type = ErrorType(status)
+	response.DocURL = ErrorDocsURL + response.Code
+	response.RequestID = GetRequestID(c)
+	if status >= fiber.StatusInternalServerError {
+		SetServerError(c, stderrors.New(response.Message))
+	}
+	return c.Status(status).JSON(response)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// FilterFields removes from JSON responses the fields the partner's field
+// filter hides from the scopes of the calling key or team member. It runs
+// after authentication, and shapes whatever the handlers answered, errors
+// included; streams, sockets and file downloads are left alone.
+func FilterFields(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		return err
+	}
+
+	partner, ok := GetPartner(c)
+	if !ok || len(partner.FieldFilter.Responses) == 0 {
+		return nil
+	}
+	scopes, _ := c.Locals("scopes").([]valueobjects.APIKeyScope)
+	hidden := partner.FieldFilter.HiddenFor(scopes)
+	if len(hidden) == 0 {
+		return nil
+	}
+
+	response := c.Response()
+	if response.IsBodyStream() || !isJSONResponse(string(response.Header.ContentType())) {
+		return nil
+	}
+	body, err := valueobjects.HideFields(response.Body(), hidden)
+	if err != nil {
+		// Not JSON after all, so there are no fields to hide
+		return nil
+	}
+	response.SetBody(body)
+	return nil
+}
+
+// isJSONResponse reports whether contentType is JSON, such as
+// application/json; charset=utf-8 or application/problem+json
+func isJSONResponse(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
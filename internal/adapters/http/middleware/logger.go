@@ -1,55 +1,54 @@
 package middleware
-package middleware
 
 import (
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return err	)		"ip="+c.IP(),		"duration="+duration.String(),		"status="+string(rune(c.Response().StatusCode())),		"path="+c.Path(),		"method="+c.Method(),		"partner_id="+partnerID,		"request_id="+requestID,	println("HTTP Request:",	// Structured log (in production, use proper logger like zerolog)	}		}			partnerID = id.String()		if id, ok := pid.(uuid.UUID); ok {	if pid := c.Locals("partner_id"); pid != nil {	partnerID := ""	// Get partner ID if authenticated	duration := time.Since(start)	// Log after request completes	err := c.Next()	// Continue to next middleware/handler	c.Set("X-Request-ID", requestID)	c.Locals("request_id", requestID)	requestID := uuid.New().String()	// Generate request ID	start := time.Now()func (m *Logger) Handle(c *fiber.Ctx) error {// Handle logs request details}	return &Logger{}func NewLogger() *Logger {// NewLogger creates a new logger middlewaretype Logger struct{}// Logger middleware logs HTTP requests with structured format)	"github.com/google/uuid"	"github.com/gofiber/fiber/v2"	"time"
\ No newline at end of file
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RequestLog is where requests are logged, such as the application logger,
+// whose level decides whether they are written
+type RequestLog interface {
+	Info(message string, args ...interface{})
+}
+
+// Logger middleware logs HTTP requests with structured format
+type Logger struct {
+	log RequestLog
+}
+
+// NewLogger creates a new logger middleware writing to log
+func NewLogger(log RequestLog) *Logger {
+	return &Logger{log: log}
+}
+
+// Handle logs request details
+func (m *Logger) Handle(c *fiber.Ctx) error {
+	start := time.Now()
+
+	// Continue to next middleware/handler
+	err := c.Next()
+
+	// Log after request completes
+	duration := time.Since(start)
+
+	// Get partner ID if authenticated
+	partnerID := ""
+	if pid := c.Locals("partner_id"); pid != nil {
+		if id, ok := pid.(uuid.UUID); ok {
+			partnerID = id.String()
+		}
+	}
+
+	m.log.Info("HTTP Request: request_id=%s partner_id=%s method=%s path=%s status=%d duration=%s ip=%s",
+		GetRequestID(c),
+		partnerID,
+		c.Method(),
+		c.Path(),
+		c.Response().StatusCode(),
+		duration,
+		c.IP(),
+	)
+	return err
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestObserver records served requests, such as the Prometheus metrics
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// RequestMetrics middleware records the latency of every request by the
+// route it matched
+type RequestMetrics struct {
+	observer RequestObserver
+}
+
+// NewRequestMetrics creates a new request metrics middleware reporting to
+// observer
+func NewRequestMetrics(observer RequestObserver) *RequestMetrics {
+	return &RequestMetrics{observer: observer}
+}
+
+// Handle times the rest of the chain
+func (m *RequestMetrics) Handle(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := responseStatus(c, err)
+
+	// The route is the pattern registered, never the path the client sent.
+	// Requests answered by a middleware, such as ones rejected by auth or
+	// matching no route, get the middleware's prefix.
+	m.observer.ObserveRequest(c.Method(), c.Route().Path, status, time.Since(start))
+	return err
+}
@@ -1,128 +1,73 @@
 package middleware
-package middleware
 
 import (
-	"sync"
+	"strconv"
 	"time"
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return b	}		return a	if a < b {func min(a, b int) int {}	}		rl.mu.Unlock()		}			b.mu.Unlock()			}				delete(rl.buckets, key)			if now.Sub(b.lastRefill) > 30*time.Minute {			b.mu.Lock()		for key, b := range rl.buckets {		now := time.Now()		rl.mu.Lock()	for range ticker.C {	defer ticker.Stop()	ticker := time.NewTicker(10 * time.Minute)func (rl *RateLimiter) cleanup() {}	return nil	b.tokens--	// Consume token	}		return fiber.ErrTooManyRequests	if b.tokens <= 0 {	// Check if token available	}		b.lastRefill = now		b.tokens = min(b.capacity, b.tokens+tokensToAdd)	if tokensToAdd > 0 {	tokensToAdd := int(elapsed.Minutes() * float64(b.refillRate))	elapsed := now.Sub(b.lastRefill)	now := time.Now()	// Refill tokens based on time elapsed	defer b.mu.Unlock()	b.mu.Lock()	rl.mu.Unlock()	}		rl.buckets[key] = b		}			lastRefill: time.Now(),			refillRate: capacity,			capacity:   capacity,			tokens:     capacity,		b = &bucket{	if !exists {	b, exists := rl.buckets[key]	rl.mu.Lock()func (rl *RateLimiter) checkLimit(c *fiber.Ctx, key string, capacity int) error {}	return c.Next()	}		})			"message": "Too many requests. Please try again later.",			"error":   "rate_limit_exceeded",		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{	if rl.checkLimit(c, key, rateLimit) != nil {	key := partnerID.(string)	rateLimit := 100 // Default: 100 requests per minute	// In production, get this from partner config	}		return c.Next()	if partner == nil {	partner := c.Locals("partner")	// Get partner-specific rate limit	}		return rl.checkLimit(c, c.IP(), 60) // 60 requests per minute for unauthenticated		// If not authenticated, use IP-based rate limiting	if partnerID == nil {	partnerID := c.Locals("partner_id")	// Get partner ID from context (set by auth middleware)func (rl *RateLimiter) Handle(c *fiber.Ctx) error {// Handle checks rate limit and rejects if exceeded}	return rl	go rl.cleanup()	// Cleanup goroutine to remove stale buckets	}		buckets: make(map[string]*bucket),	rl := &RateLimiter{func NewRateLimiter() *RateLimiter {// NewRateLimiter creates a new rate limiter}	mu           sync.Mutex	lastRefill   time.Time	refillRate   int       // tokens per minute	capacity     int	tokens       inttype bucket struct {}	mu      sync.RWMutex	buckets map[string]*buckettype RateLimiter struct {// RateLimiter implements token bucket rate limiting per partner)	"github.com/gofiber/fiber/v2"
\ No newline at end of file
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+const (
+	// rateLimitWindow is the sliding window partner limits are expressed in
+	rateLimitWindow = time.Minute
+
+	// anonymousRateLimit applies to requests without an authenticated partner
+	anonymousRateLimit = 60
+)
+
+// RateLimiter enforces per-partner sliding-window rate limits
+type RateLimiter struct {
+	store        ports.RateLimitStore
+	defaultLimit int
+}
+
+// NewRateLimiter creates a new rate limiter
+func NewRateLimiter(store ports.RateLimitStore, defaultLimit int) *RateLimiter {
+	return &RateLimiter{
+		store:        store,
+		defaultLimit: defaultLimit,
+	}
+}
+
+// Handle checks rate limit and rejects if exceeded
+func (rl *RateLimiter) Handle(c *fiber.Ctx) error {
+	// Partners are limited by their configured rate; anonymous callers by IP
+	key := "ratelimit:ip:" + c.IP()
+	limit := anonymousRateLimit
+	if partner, ok := c.Locals("partner").(*entities.Partner); ok {
+		key = "ratelimit:partner:" + partner.ID.String()
+		limit = partner.RateLimitPerMinute
+		if limit <= 0 {
+			limit = rl.defaultLimit
+		}
+	}
+
+	result, err := rl.store.Allow(c.Context(), key, limit, rateLimitWindow)
+	if err != nil {
+		// Fail open: an unavailable limiter store must not take the API down
+		return c.Next()
+	}
+
+	c.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+	if !result.Allowed {
+		retryAfter := int(result.RetryAfter.Seconds() + 0.999)
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+		return RespondError(c, fiber.StatusTooManyRequests, dto.ErrorResponse{
+			Error:   "rate_limit_exceeded",
+			Message: "Too many requests. Please try again later.",
+		})
+	}
+
+	return c.Next()
+}
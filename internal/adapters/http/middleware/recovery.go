@@ -1,33 +1,91 @@
 package middleware
-package middleware
 
 import (
-	"github.com/gofiber/fiber/v2"
-)
-
-// Recovery middleware recovers from panics
-type Recovery struct{}
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
+	"runtime/debug"
 
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/usecases/ports"
+)
 
-}	return c.Next()	}()		}			})				"message": "An unexpected error occurred",				"error":   "internal_server_error",			_ = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{			// Return 500 error			println("PANIC:", r)			// Log panic (in production, use proper logger)		if r := recover(); r != nil {	defer func() {func (m *Recovery) Handle(c *fiber.Ctx) error {// Handle recovers from panics and returns 500 error}	return &Recovery{}func NewRecovery() *Recovery {// NewRecovery creates a new recovery middleware
\ No newline at end of file
+// PanicLog is where recovered panics are logged, such as the application
+// logger
+type PanicLog interface {
+	Error(message string, args ...interface{})
+}
+
+// Recovery middleware recovers from panics, which it logs, and reports
+// them, and server errors, to the error tracker
+type Recovery struct {
+	log PanicLog
+	// reporter is nil without an error tracker
+	reporter ports.ErrorReporter
+}
+
+// NewRecovery creates a new recovery middleware logging panics to log and
+// reporting to reporter, which may be nil
+func NewRecovery(log PanicLog, reporter ports.ErrorReporter) *Recovery {
+	return &Recovery{log: log, reporter: reporter}
+}
+
+// serverErrorKey holds the error behind a 5xx response a handler wrote
+type serverErrorKey struct{}
+
+// SetServerError records err as the cause of the 5xx response a handler is
+// sending, so Recovery can report it
+func SetServerError(c *fiber.Ctx, err error) {
+	c.Locals(serverErrorKey{}, err)
+}
+
+// Handle recovers from panics and returns 500 error
+func (m *Recovery) Handle(c *fiber.Ctx) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			m.log.Error("HTTP Panic: request_id=%s method=%s path=%s panic=%v\n%s",
+				GetRequestID(c),
+				c.Method(),
+				c.Path(),
+				r,
+				stack,
+			)
+			if m.reporter != nil {
+				m.reporter.Report(c.Context(), ports.ErrorReport{Panic: r, Stack: stack, Tags: requestTags(c)})
+			}
+
+			// Return 500 error
+			err = RespondError(c, fiber.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_server_error",
+				Message: "An unexpected error occurred",
+			})
+		}
+	}()
+
+	err = c.Next()
+	if m.reporter == nil {
+		return err
+	}
+	if serverErr, ok := c.Locals(serverErrorKey{}).(error); ok && c.Response().StatusCode() >= fiber.StatusInternalServerError {
+		m.reporter.Report(c.Context(), ports.ErrorReport{Err: serverErr, Tags: requestTags(c)})
+	} else if e, ok := err.(*fiber.Error); err != nil && (!ok || e.Code >= fiber.StatusInternalServerError) {
+		m.reporter.Report(c.Context(), ports.ErrorReport{Err: err, Tags: requestTags(c)})
+	}
+	return err
+}
+
+// requestTags describes the request an error happened in
+func requestTags(c *fiber.Ctx) map[string]string {
+	tags := map[string]string{
+		"method": c.Method(),
+		"route":  c.Route().Path,
+	}
+	if partnerID, ok := c.Locals("partner_id").(uuid.UUID); ok {
+		tags["partner_id"] = partnerID.String()
+	}
+	if id := c.Params("id"); id != "" {
+		tags["resource_id"] = id
+	}
+	return tags
+}
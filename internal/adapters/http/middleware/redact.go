@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the values of secret fields
+const redacted = "[REDACTED]"
+
+// secretFieldParts mark a field as secret when its name, lowercased and
+// without '_' or '-', contains one of them
+var secretFieldParts = []string{
+	"password", "secret", "token", "apikey", "authorization", "signature", "cvv", "cvc",
+}
+
+// secretFields are secret field names too short to match by part
+var secretFields = map[string]bool{"key": true, "pin": true}
+
+var (
+	// cardPattern matches 13 to 19 digits, optionally grouped by spaces or
+	// dashes; matches failing the Luhn check are left alone
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactBody returns body as safe to keep in an access log: secret fields,
+// such as passwords and API keys, are replaced, card numbers keep only
+// their last 4 digits and emails only the first letter of their user.
+// JSON and form bodies are redacted field by field; any other body is
+// described by its content type and size alone, as it cannot be checked.
+func RedactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Sprintf("[invalid JSON, %d bytes]", len(body))
+		}
+		var out bytes.Buffer
+		enc := json.NewEncoder(&out)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(redactValue(value)); err != nil {
+			return fmt.Sprintf("[invalid JSON, %d bytes]", len(body))
+		}
+		return strings.TrimSuffix(out.String(), "\n")
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("[invalid form, %d bytes]", len(body))
+		}
+		return RedactQuery(values)
+	}
+	if mediaType == "" {
+		mediaType = "unknown content type"
+	}
+	return fmt.Sprintf("[%s, %d bytes]", mediaType, len(body))
+}
+
+// RedactQuery encodes query parameters with their secrets redacted
+func RedactQuery(values url.Values) string {
+	for name, list := range values {
+		for i, value := range list {
+			if isSecretField(name) {
+				list[i] = redacted
+			} else {
+				list[i] = redactString(value)
+			}
+		}
+	}
+	// Encoding escapes the brackets of redacted values; they read better raw
+	return strings.ReplaceAll(values.Encode(), url.QueryEscape(redacted), redacted)
+}
+
+// redactValue redacts a decoded JSON value in place
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if isSecretField(name) && field != nil {
+				v[name] = redacted
+			} else {
+				v[name] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		return redactString(v)
+	case json.Number:
+		// Card numbers sent as numbers
+		if masked := redactString(v.String()); masked != v.String() {
+			return masked
+		}
+	}
+	return value
+}
+
+// isSecretField reports whether the field called name holds a secret
+func isSecretField(name string) bool {
+	name = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	if secretFields[name] {
+		return true
+	}
+	for _, part := range secretFieldParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString masks the card numbers and emails in s
+func redactString(s string) string {
+	s = cardPattern.ReplaceAllStringFunc(s, func(match string) string {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(match)
+		if !luhnValid(digits) {
+			return match
+		}
+		return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+	})
+	return emailPattern.ReplaceAllStringFunc(s, func(match string) string {
+		user, domain, _ := strings.Cut(match, "@")
+		return user[:1] + "***@" + domain
+	})
+}
+
+// luhnValid reports whether digits pass the Luhn check of card numbers
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
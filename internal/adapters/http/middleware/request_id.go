@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// HeaderRequestID carries the ID of a request, in both directions
+const HeaderRequestID = "X-Request-ID"
+
+// RequestID gives every request an ID, returned in the X-Request-ID header
+// and carried to audit entries, payment providers and error responses, so
+// support can follow a partner's complaint across systems. A partner or
+// proxy can send the ID itself; IDs that are not UUIDs are replaced, since
+// audit entries store them as UUIDs. Requests routed again keep their ID.
+func RequestID(c *fiber.Ctx) error {
+	if _, ok := c.Locals(ports.RequestIDKey{}).(uuid.UUID); ok {
+		return c.Next()
+	}
+
+	id, err := uuid.Parse(c.Get(HeaderRequestID))
+	if err != nil || id == uuid.Nil {
+		id = uuid.New()
+	}
+
+	c.Locals(ports.RequestIDKey{}, id)
+	c.Set(HeaderRequestID, id.String())
+	return c.Next()
+}
+
+// GetRequestID returns the ID RequestID gave the request, or an empty string
+// before it ran
+func GetRequestID(c *fiber.Ctx) string {
+	id, ok := c.Locals(ports.RequestIDKey{}).(uuid.UUID)
+	if !ok {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// UsageRecorder counts the API calls of partners
+type UsageRecorder interface {
+	RecordCall(partnerID uuid.UUID, status int)
+}
+
+// MeterUsage counts every call authenticated as a partner, with the status
+// it was answered with. Calls rejected before the partner is known, such as
+// those with an invalid API key, are not counted.
+func MeterUsage(recorder UsageRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if partnerID, ok := c.Locals("partner_id").(uuid.UUID); ok {
+			recorder.RecordCall(partnerID, responseStatus(c, err))
+		}
+		return err
+	}
+}
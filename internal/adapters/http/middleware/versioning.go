@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+)
+
+// API versions
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2"
+)
+
+// HeaderAPIVersion names the API version a request asks for, and the one a
+// response was answered in
+const HeaderAPIVersion = "Pay2Go-Version"
+
+// versionMediaType matches media types such as application/vnd.pay2go.v2+json
+var versionMediaType = regexp.MustCompile(`^application/vnd\.pay2go\.(v[0-9]+)\+json$`)
+
+// versionSegment matches the version segment of /api/v1/...
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+// Deprecation announces that an API version is going away, in the
+// Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers of its responses
+type Deprecation struct {
+	// Since is when the version was deprecated
+	Since time.Time
+	// Sunset is when it stops answering; zero if not decided
+	Sunset time.Time
+	// Successor is the path prefix of the version that replaces it, such as
+	// /api/v2; each response links to its path under it
+	Successor string
+}
+
+// Handle marks the response as deprecated
+func (d Deprecation) Handle(c *fiber.Ctx) error {
+	c.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	if !d.Sunset.IsZero() {
+		c.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		// The same path under the successor, without this version's prefix
+		path := c.Path()
+		if rest, ok := strings.CutPrefix(path, "/api/"); ok {
+			_, path, _ = strings.Cut(rest, "/")
+			path = "/" + path
+		}
+		c.Set(fiber.HeaderLink, "<"+strings.TrimSuffix(d.Successor+path, "/")+`>; rel="successor-version"`)
+	}
+	return c.Next()
+}
+
+// Versioning routes requests to an API version. Paths under /api/<version>
+// are answered in that version. Unversioned paths, under /api/ or the legacy
+// root-level /transactions, are answered in the version the Pay2Go-Version
+// header or an application/vnd.pay2go.<version>+json Accept type asks for,
+// or in the default version when neither does.
+type Versioning struct {
+	defaultVersion string
+	versions       map[string]bool
+}
+
+// NewVersioning creates a new versioning middleware for the given versions
+func NewVersioning(defaultVersion string, versions ...string) *Versioning {
+	v := &Versioning{
+		defaultVersion: defaultVersion,
+		versions:       make(map[string]bool, len(versions)),
+	}
+	for _, version := range versions {
+		v.versions[version] = true
+	}
+	return v
+}
+
+// Handle labels responses with their version. Requests
+// to unversioned paths are moved under the version they ask for and routed
+// again, so Handle must only follow middleware that is safe to run twice,
+// such as RequestID.
+func (v *Versioning) Handle(c *fiber.Ctx) error {
+	path := c.Path()
+	rest, underAPI := strings.CutPrefix(path, "/api/")
+	segment, _, _ := strings.Cut(rest, "/")
+	switch {
+	case underAPI && versionSegment.MatchString(segment):
+		if !v.versions[segment] {
+			return unsupportedVersion(c, segment)
+		}
+		c.Locals("api_version", segment)
+		c.Set(HeaderAPIVersion, segment)
+		return c.Next()
+	case underAPI:
+		rest = "/" + rest
+	case path == "/transactions" || strings.HasPrefix(path, "/transactions/"):
+		rest = path
+	default:
+		return c.Next()
+	}
+
+	version := requestedVersion(c)
+	if version == "" {
+		version = v.defaultVersion
+	}
+	if !v.versions[version] {
+		return unsupportedVersion(c, version)
+	}
+	c.Path("/api/" + version + rest)
+	return c.RestartRouting()
+}
+
+// unsupportedVersion answers a request for a version that does not exist
+func unsupportedVersion(c *fiber.Ctx, version string) error {
+	return RespondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+		Error:   "unsupported_api_version",
+		Message: "API version " + version + " does not exist",
+	})
+}
+
+// GetAPIVersion returns the API version a request is answered in, or an
+// empty string outside the versioned API
+func GetAPIVersion(c *fiber.Ctx) string {
+	version, _ := c.Locals("api_version").(string)
+	return version
+}
+
+// requestedVersion returns the version a request asks for in its headers, or
+// an empty string
+func requestedVersion(c *fiber.Ctx) string {
+	if version := strings.TrimSpace(c.Get(HeaderAPIVersion)); version != "" {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		return version
+	}
+	for _, mediaType := range strings.Split(c.Get(fiber.HeaderAccept), ",") {
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+		if match := versionMediaType.FindStringSubmatch(strings.TrimSpace(mediaType)); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
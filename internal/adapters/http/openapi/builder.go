@@ -0,0 +1,413 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Endpoint describes an operation to add to a document
+type Endpoint struct {
+	Method string
+	Path   string // Fiber syntax, such as /api/v1/transactions/:id
+
+	ID          string
+	Tag         string
+	Summary     string
+	Description string
+
+	// Public operations take no credentials
+	Public bool
+
+	// Query is a struct whose query-tagged fields are the query parameters
+	Query interface{}
+
+	// Body is the request body DTO
+	Body interface{}
+
+	// Status is the status of success, 200 if not set; Alternatives are
+	// other statuses of success answered with the same body. Response is
+	// the DTO answered with, or nil for no body; ContentTypes, if any,
+	// replace JSON with bodies of other types.
+	Status       int
+	Alternatives []int
+	Response     interface{}
+	ContentTypes []string
+
+	// Errors are the statuses of failures besides those of authentication
+	Errors []int
+}
+
+// Builder builds a document one endpoint at a time
+type Builder struct {
+	doc         *Document
+	errorSchema *Schema
+	names       map[string]reflect.Type
+}
+
+// NewBuilder starts a document. Failures are answered with errorResponse.
+func NewBuilder(info Info, errorResponse interface{}) *Builder {
+	b := &Builder{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas:         make(map[string]*Schema),
+				SecuritySchemes: make(map[string]*SecurityScheme),
+			},
+		},
+		names: make(map[string]reflect.Type),
+	}
+	b.errorSchema = b.Schema(errorResponse)
+	return b
+}
+
+// Tag adds a tag, listing tags in the order they are added
+func (b *Builder) Tag(name, description string) {
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name, Description: description})
+}
+
+// SecurityScheme adds a way of authenticating that every operation but the
+// public ones accepts
+func (b *Builder) SecurityScheme(name string, scheme *SecurityScheme) {
+	b.doc.Components.SecuritySchemes[name] = scheme
+	b.doc.Security = append(b.doc.Security, SecurityRequirement{name: {}})
+}
+
+// Add adds an endpoint
+func (b *Builder) Add(e Endpoint) {
+	path, params := pathTemplate(e.Path)
+	op := &Operation{
+		Summary:     e.Summary,
+		Description: e.Description,
+		OperationID: e.ID,
+		Parameters:  params,
+		Responses:   make(map[string]*Response),
+	}
+	if e.Tag != "" {
+		op.Tags = []string{e.Tag}
+	}
+	for _, item := range b.doc.Paths {
+		for _, existing := range item {
+			if existing.OperationID == e.ID {
+				panic(fmt.Sprintf("openapi: operation %s added twice", e.ID))
+			}
+		}
+	}
+
+	if e.Query != nil {
+		op.Parameters = append(op.Parameters, b.queryParameters(reflect.TypeOf(e.Query))...)
+	}
+	if e.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: b.Schema(e.Body)}},
+		}
+	}
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	var content map[string]*MediaType
+	switch {
+	case len(e.ContentTypes) > 0:
+		content = make(map[string]*MediaType)
+		for _, contentType := range e.ContentTypes {
+			content[contentType] = &MediaType{Schema: &Schema{Type: "string"}}
+		}
+	case e.Response != nil:
+		content = map[string]*MediaType{"application/json": {Schema: b.Schema(e.Response)}}
+	}
+	for _, code := range append([]int{status}, e.Alternatives...) {
+		op.Responses[strconv.Itoa(code)] = &Response{Description: http.StatusText(code), Content: content}
+	}
+
+	errors := e.Errors
+	if e.Public {
+		op.Security = &[]SecurityRequirement{}
+	} else {
+		errors = append([]int{http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests}, errors...)
+	}
+	for _, code := range errors {
+		op.Responses[strconv.Itoa(code)] = &Response{
+			Description: http.StatusText(code),
+			Content:     map[string]*MediaType{"application/json": {Schema: b.errorSchema}},
+		}
+	}
+
+	item := b.doc.Paths[path]
+	if item == nil {
+		item = make(PathItem)
+		b.doc.Paths[path] = item
+	}
+	item[strings.ToLower(e.Method)] = op
+}
+
+// Document returns the document built so far
+func (b *Builder) Document() *Document {
+	return b.doc
+}
+
+// pathTemplate turns a Fiber path into an OpenAPI path template and its
+// parameters
+func pathTemplate(path string) (string, []*Parameter) {
+	segments := strings.Split(path, "/")
+	var params []*Parameter
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		schema := &Schema{Type: "string"}
+		if name == "id" {
+			schema.Format = "uuid"
+		}
+		params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// queryParameters returns the parameters of the query-tagged fields of t,
+// those of embedded structs included
+func (b *Builder) queryParameters(t reflect.Type) []*Parameter {
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			params = append(params, b.queryParameters(field.Type)...)
+			continue
+		}
+		name := field.Tag.Get("query")
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := b.schemaOf(field.Type)
+		rules, _ := validateRules(field.Tag.Get("validate"))
+		applyRules(schema, rules)
+		params = append(params, &Parameter{Name: name, In: "query", Required: has(rules, "required"), Schema: schema})
+	}
+	return params
+}
+
+// Schema returns the schema of v's type. Named structs are added to the
+// components and referred to.
+func (b *Builder) Schema(v interface{}) *Schema {
+	return b.schemaOf(reflect.TypeOf(v))
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf returns the schema of t
+func (b *Builder) schemaOf(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		return b.schemaOf(t.Elem())
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if existing, ok := b.names[t.Name()]; ok {
+			if existing != t {
+				panic(fmt.Sprintf("openapi: types %s and %s share a name", existing, t))
+			}
+		} else {
+			// Registered before its fields, so types can refer to themselves
+			b.names[t.Name()] = t
+			b.doc.Components.Schemas[t.Name()] = b.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	panic(fmt.Sprintf("openapi: no schema for %s", t))
+}
+
+// structSchema returns the object schema of a struct. Fields are required
+// when validation requires them, or, for fields without validation, when
+// they are never left out of the JSON.
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the JSON fields of t, those of embedded structs included
+func (b *Builder) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(schema, embedded)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.schemaOf(field.Type)
+		omitempty := strings.Contains(options, "omitempty")
+		validate, validated := field.Tag.Lookup("validate")
+		rules, elements := validateRules(validate)
+		// Siblings of $ref are ignored in 3.0, so referenced types keep
+		// their own constraints only
+		if property.Ref == "" {
+			applyRules(property, rules)
+			if field.Type.Kind() == reflect.Ptr && !omitempty {
+				property.Nullable = true
+			}
+		}
+		for _, element := range []*Schema{property.Items, property.AdditionalProperties} {
+			if element != nil && element.Ref == "" {
+				applyRules(element, elements)
+			}
+		}
+		schema.Properties[name] = property
+
+		required := !omitempty && field.Type.Kind() != reflect.Ptr
+		if validated {
+			required = has(rules, "required")
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// validateRules parses a validate tag into the rules of a field and, after
+// dive, those of its elements, with their parameters. Rules of map keys are
+// left out.
+func validateRules(tag string) (rules, elements map[string][]string) {
+	if tag == "" {
+		return nil, nil
+	}
+	rules = make(map[string][]string)
+	current, keys := rules, false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch {
+		case name == "dive" && elements == nil:
+			elements = make(map[string][]string)
+			current = elements
+			continue
+		case name == "keys":
+			keys = true
+		case name == "endkeys":
+			keys = false
+		}
+		if keys || name == "endkeys" {
+			continue
+		}
+		current[name] = strings.Fields(param)
+	}
+	return rules, elements
+}
+
+// applyRules constrains a schema as validation rules do
+func applyRules(schema *Schema, rules map[string][]string) {
+	number := schema.Type == "integer" || schema.Type == "number"
+	bound := func(rule string) (*int, *float64) {
+		params, ok := rules[rule]
+		if !ok || len(params) != 1 {
+			return nil, nil
+		}
+		n, err := strconv.Atoi(params[0])
+		if err != nil {
+			return nil, nil
+		}
+		f := float64(n)
+		return &n, &f
+	}
+
+	if n, f := bound("min"); n != nil {
+		switch {
+		case number:
+			schema.Minimum = f
+		case schema.Type == "array":
+			schema.MinItems = n
+		case schema.Type == "string":
+			schema.MinLength = n
+		}
+	}
+	if n, f := bound("max"); n != nil {
+		switch {
+		case number:
+			schema.Maximum = f
+		case schema.Type == "array":
+			schema.MaxItems = n
+		case schema.Type == "object":
+			schema.MaxProperties = n
+		case schema.Type == "string":
+			schema.MaxLength = n
+		}
+	}
+	if n, _ := bound("len"); n != nil && schema.Type == "string" {
+		schema.MinLength, schema.MaxLength = n, n
+	}
+	if values, ok := rules["oneof"]; ok {
+		schema.Enum = values
+	}
+
+	switch {
+	case has(rules, "email"):
+		schema.Format = "email"
+	case has(rules, "uuid"):
+		schema.Format = "uuid"
+	case has(rules, "url"):
+		schema.Format = "uri"
+	case has(rules, "e164"):
+		schema.Pattern = `^\+[1-9][0-9]{1,14}$`
+	case has(rules, "iso3166_1_alpha2"):
+		schema.Pattern = "^[A-Z]{2}$"
+	case has(rules, "numeric"):
+		schema.Pattern = "^[0-9]+$"
+	}
+	if layout, ok := rules["datetime"]; ok && len(layout) == 1 && layout[0] == "2006-01-02" {
+		schema.Format = "date"
+	}
+}
+
+// has reports whether rules include rule
+func has(rules map[string][]string, rule string) bool {
+	_, ok := rules[rule]
+	return ok
+}
@@ -0,0 +1,638 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/openapi"
+)
+
+// exportQuery is the query of GET /transactions/export: the list filters,
+// the format and the layout
+type exportQuery struct {
+	dto.ListTransactionsRequest
+	Format   string `query:"format" validate:"omitempty,oneof=csv ndjson"`
+	Columns  string `query:"columns"`  // Comma-separated CSV columns, in order
+	Timezone string `query:"timezone"` // IANA zone name of the times
+}
+
+// listTransactionsQuery is the query of GET /transactions: the list
+// filters and the expansions of each transaction
+type listTransactionsQuery struct {
+	dto.ListTransactionsRequest
+	dto.ExpandRequest
+}
+
+// listTransactionsV2Query is the query of GET /v2/transactions
+type listTransactionsV2Query struct {
+	dto.ListTransactionsV2Request
+	dto.ExpandRequest
+}
+
+// eventStreamQuery is the query of GET /events/stream, for clients that
+// cannot send the Last-Event-ID header
+type eventStreamQuery struct {
+	LastEventID string `query:"last_event_id"`
+}
+
+// transactionSocketQuery is the query of GET /ws/transactions
+type transactionSocketQuery struct {
+	TransactionID string `query:"transaction_id" validate:"omitempty,uuid"`
+}
+
+// OpenAPI describes the partner API registered by SetupRoutes. Back-office
+// routes under /admin, /metrics and /slo are operated by Pay2Go and left out.
+// Keep it in step with SetupRoutes; make openapi-check fails CI when the
+// committed docs/openapi.json no longer matches.
+func OpenAPI() *openapi.Document {
+	b := openapi.NewBuilder(openapi.Info{
+		Title: "Pay2Go Payment Orchestration API",
+		Description: "Create, process and refund payments across Stripe, Adyen and other providers through one API. " +
+			"Every authenticated /api/v1 path is also served under /api/v2, where transactions are payment intents; " +
+			"the /api/v1 ones are deprecated.",
+		Version: "1.0.0",
+	}, dto.ErrorResponse{})
+
+	// The body of calls that answer with a message alone
+	message := struct {
+		Message string `json:"message"`
+	}{}
+
+	b.SecurityScheme("bearerAuth", &openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "A bearer API key, or a team member's session token from POST /api/v1/auth/login. Each route also needs an API key scope or team member role.",
+	})
+	b.SecurityScheme("hmacAuth", &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        "Authorization",
+		Description: "HMAC-SHA256 <key-prefix>:<signature>, with the X-Pay2Go-Timestamp header, for API keys issued with auth_method hmac.",
+	})
+
+	b.Tag("Health", "Liveness and readiness probes")
+	b.Tag("Transactions", "Payments and their processing")
+	b.Tag("Transactions v2", "Payments as payment intents, with amounts in minor units")
+	b.Tag("Refunds", "Refunds, their approval and bulk refunds")
+	b.Tag("Disputes", "Chargebacks reported in provider settlement feeds")
+	b.Tag("Statements", "Monthly statements of volume and fees")
+	b.Tag("Analytics", "Payment volume, success and refund rates from daily rollups")
+	b.Tag("Report Schedules", "Reports delivered on a schedule by email, SFTP or S3")
+	b.Tag("Accounting", "Accounts of the partner's accounting system that QuickBooks and Xero exports post to")
+	b.Tag("GraphQL", "Read-only GraphQL over transactions, refunds and webhook events")
+	b.Tag("Events", "Transaction and refund events and statuses pushed live")
+	b.Tag("API Keys", "The partner's API keys")
+	b.Tag("Team", "Team members and their sessions")
+	b.Tag("Provider Credentials", "The partner's own provider accounts")
+	b.Tag("Field Filter", "Fields hidden from the partner's API responses and webhooks")
+	b.Tag("Audit Logs", "The partner's audit trail")
+	b.Tag("Usage", "The partner's metered usage")
+	b.Tag("Exports", "Transaction and refund files produced in the background")
+	b.Tag("Provider Notifications", "Events pushed by payment providers")
+	b.Tag("Documentation", "This document")
+
+	// Health checks
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/health", ID: "getHealth", Tag: "Health",
+		Summary: "Check service health", Public: true,
+		Response: dto.HealthCheckResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/health/ready", ID: "getReadiness", Tag: "Health",
+		Summary: "Check that dependencies are reachable", Public: true,
+		Alternatives: []int{http.StatusServiceUnavailable},
+		Response:     dto.ReadinessResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/health/live", ID: "getLiveness", Tag: "Health",
+		Summary: "Check that the process is alive", Public: true,
+		Response: map[string]string{},
+	})
+
+	// Transactions
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/transactions", ID: "createTransaction", Tag: "Transactions",
+		Summary:     "Create a transaction",
+		Description: "Scope: payments. Retries with the same idempotency_key return the original transaction.",
+		Body:        dto.CreateTransactionRequest{},
+		Status:      http.StatusCreated, Response: dto.CreateTransactionResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/transactions", ID: "listTransactions", Tag: "Transactions",
+		Summary:     "List transactions",
+		Description: "Scope: read_only. Pages by starting_after, or by offset; links.next is the next page. expand=refunds,customer embeds them in each transaction.",
+		Query:       listTransactionsQuery{},
+		Response:    dto.ListTransactionsResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/transactions/export", ID: "exportTransactions", Tag: "Transactions",
+		Summary:      "Export transactions as CSV or NDJSON",
+		Description:  "Scope: read_only. Streams every transaction matching the list filters.",
+		Query:        exportQuery{},
+		ContentTypes: []string{"text/csv", "application/x-ndjson"},
+		Errors:       []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/transactions/:id", ID: "getTransaction", Tag: "Transactions",
+		Summary:     "Get a transaction",
+		Description: "Scope: read_only. expand=refunds,customer embeds them; links are the actions the status allows.",
+		Query:       dto.ExpandRequest{},
+		Response:    dto.GetTransactionResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/transactions/:id/process", ID: "processPayment", Tag: "Transactions",
+		Summary:      "Process a pending payment",
+		Description:  "Scope: payments. Answers 202 with a job ID when payments are processed asynchronously.",
+		Alternatives: []int{http.StatusAccepted},
+		Response:     dto.ProcessPaymentResponse{},
+		Errors:       []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/transactions/:id/refund", ID: "refundTransaction", Tag: "Transactions",
+		Summary:      "Refund a transaction",
+		Description:  "Scope: refunds. Refunds held for approval are answered with 202.",
+		Body:         dto.RefundTransactionRequest{},
+		Status:       http.StatusCreated,
+		Alternatives: []int{http.StatusAccepted},
+		Response:     dto.RefundTransactionResponse{},
+		Errors:       []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/transactions/:id/receipt", ID: "getReceipt", Tag: "Transactions",
+		Summary:      "Get the PDF receipt of a paid transaction",
+		Description:  "Scope: read_only. Shows completed refunds, so it changes after each; its ETag answers If-None-Match with 304 until then.",
+		ContentTypes: []string{"application/pdf"},
+		Errors:       []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/transactions/:id/verification-code", ID: "sendVerificationCode", Tag: "Transactions",
+		Summary:     "Text the customer a verification code",
+		Description: "Scope: payments.",
+		Status:      http.StatusAccepted, Response: dto.VerificationCodeResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusServiceUnavailable},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/transactions/:id/verification-code/verify", ID: "verifyCode", Tag: "Transactions",
+		Summary:     "Check a verification code",
+		Description: "Scope: payments.",
+		Body:        dto.VerifyCodeRequest{},
+		Response:    dto.VerifyCodeResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+
+	// Transactions, v2
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v2/transactions", ID: "createTransactionV2", Tag: "Transactions v2",
+		Summary:     "Create a payment intent",
+		Description: "Scope: payments. The transaction waits for confirmation unless confirm is set. Retries with the same idempotency_key return the original transaction.",
+		Body:        dto.CreateTransactionV2Request{},
+		Status:      http.StatusCreated,
+		// Answered as the confirmation would be, with confirm set
+		Alternatives: []int{http.StatusOK, http.StatusAccepted},
+		Response:     dto.TransactionV2Response{},
+		Errors:       []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v2/transactions", ID: "listTransactionsV2", Tag: "Transactions v2",
+		Summary:     "List payment intents",
+		Description: "Scope: read_only. Pages by starting_after; links.next is the next page. expand=refunds,customer embeds them in each payment intent.",
+		Query:       listTransactionsV2Query{},
+		Response:    dto.ListTransactionsV2Response{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v2/transactions/:id", ID: "getTransactionV2", Tag: "Transactions v2",
+		Summary:     "Get a payment intent",
+		Description: "Scope: read_only. expand=refunds,customer embeds them; links are the actions the status allows.",
+		Query:       dto.ExpandRequest{},
+		Response:    dto.TransactionV2Response{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v2/transactions/:id/confirm", ID: "confirmTransactionV2", Tag: "Transactions v2",
+		Summary:      "Confirm a payment intent",
+		Description:  "Scope: payments. Answers 200 once processed, whether the payment succeeded or failed, and 202 when payments are processed asynchronously.",
+		Alternatives: []int{http.StatusAccepted},
+		Response:     dto.TransactionV2Response{},
+		Errors:       []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	})
+
+	// Refunds
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/refunds/:id/approve", ID: "approveRefund", Tag: "Refunds",
+		Summary:     "Approve a refund held for approval",
+		Description: "Team members: owner or finance. Also open to back-office admin sessions.",
+		Response:    dto.RefundTransactionResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/refunds/:id/cancel", ID: "cancelRefund", Tag: "Refunds",
+		Summary:     "Cancel a refund held for approval",
+		Description: "Scope: refunds.",
+		Response:    dto.RefundTransactionResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/refunds/reasons", ID: "getRefundReasons", Tag: "Refunds",
+		Summary:     "Summarize refunds by reason",
+		Description: "Scope: read_only. Team members: owner, finance or read_only.",
+		Query:       dto.RefundReasonSummaryRequest{},
+		Response:    dto.RefundReasonSummaryResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/refunds/bulk", ID: "bulkRefund", Tag: "Refunds",
+		Summary:     "Refund many transactions in the background",
+		Description: "Scope: refunds.",
+		Body:        dto.BulkRefundRequest{},
+		Status:      http.StatusAccepted, Response: dto.BulkRefundJobResponse{},
+		Errors: []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/refunds/bulk/:id", ID: "getBulkRefundJob", Tag: "Refunds",
+		Summary:     "Get the progress of a bulk refund",
+		Description: "Scope: read_only.",
+		Response:    dto.BulkRefundJobResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+
+	// Disputes
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/disputes", ID: "listDisputes", Tag: "Disputes",
+		Summary:     "List chargebacks",
+		Description: "Scope: read_only. Team members: owner, finance or read_only.",
+		Query:       dto.ListDisputesRequest{},
+		Response:    dto.ListDisputesResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+
+	// Statements
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/statements", ID: "listStatements", Tag: "Statements",
+		Summary:     "List monthly statements, latest first",
+		Description: "Scope: read_only. Team members: owner, finance or read_only. A month's statement of live payments, refunds, chargebacks and fees is issued once the month is over.",
+		Query:       dto.ListStatementsRequest{},
+		Response:    dto.ListStatementsResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/statements/:id", ID: "getStatement", Tag: "Statements",
+		Summary:     "Get a monthly statement",
+		Description: "Scope: read_only. Team members: owner, finance or read_only.",
+		Response:    dto.StatementResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/statements/:id/download", ID: "downloadStatement", Tag: "Statements",
+		Summary:      "Download a monthly statement as CSV, or its fees for QuickBooks or Xero",
+		Description:  "Scope: read_only. Team members: owner, finance or read_only. As csv, one row per item of each currency, amounts signed by their effect on what is owed to you. As quickbooks_iif or xero_csv, an entry per fee tier and chargeback fees of each currency, on the last day of the month, posted to your accounting accounts.",
+		Query:        dto.DownloadStatementRequest{},
+		ContentTypes: []string{"text/csv", "text/plain"},
+		Errors:       []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+
+	// Analytics
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/analytics/volume", ID: "getAnalyticsVolume", Tag: "Analytics",
+		Summary:     "Get succeeded payment volume over time",
+		Description: "Scope: read_only. Team members: owner, finance or read_only. Buckets the days of the range, the last 30 by default, by day, week or month. Analytics are rolled up in the background, so today's are a few minutes behind.",
+		Query:       dto.VolumeRequest{},
+		Response:    dto.VolumeResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/analytics/success-rates", ID: "getAnalyticsSuccessRates", Tag: "Analytics",
+		Summary:     "Get payment success rates by provider or payment method",
+		Description: "Scope: read_only. Team members: owner, finance or read_only.",
+		Query:       dto.SuccessRatesRequest{},
+		Response:    dto.SuccessRatesResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/analytics/average-amounts", ID: "getAnalyticsAverageAmounts", Tag: "Analytics",
+		Summary:     "Get the average succeeded payment by currency",
+		Description: "Scope: read_only. Team members: owner, finance or read_only.",
+		Query:       dto.AnalyticsRequest{},
+		Response:    dto.AverageAmountsResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/analytics/refund-rates", ID: "getAnalyticsRefundRates", Tag: "Analytics",
+		Summary:     "Get the share of volume refunded by currency",
+		Description: "Scope: read_only. Team members: owner, finance or read_only. Refunds count on the day they are made, so they can be of payments before the range.",
+		Query:       dto.AnalyticsRequest{},
+		Response:    dto.RefundRatesResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+
+	// Report schedules
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/report-schedules", ID: "createReportSchedule", Tag: "Report Schedules",
+		Summary:     "Schedule a report",
+		Description: "Scope: admin. Team members: owner or finance. Delivers transactions or refunds, filtered as exports are, or monthly statements, every day, Monday or first of the month at hour in the schedule's time zone, covering the period before. Up to 20 schedules per mode.",
+		Body:        dto.ReportScheduleRequest{},
+		Status:      http.StatusCreated, Response: dto.ReportScheduleResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/report-schedules", ID: "listReportSchedules", Tag: "Report Schedules",
+		Summary:     "List the partner's report schedules",
+		Description: "Scope: admin. Team members: owner or finance. SFTP credentials are never returned.",
+		Response:    dto.ListReportSchedulesResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/report-schedules/:id", ID: "getReportSchedule", Tag: "Report Schedules",
+		Summary:     "Get a report schedule",
+		Description: "Scope: admin. Team members: owner or finance.",
+		Response:    dto.ReportScheduleResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPut, Path: "/api/v1/report-schedules/:id", ID: "updateReportSchedule", Tag: "Report Schedules",
+		Summary:     "Reconfigure a report schedule",
+		Description: "Scope: admin. Team members: owner or finance. Replaces every setting; an SFTP destination without password or private_key keeps its credentials.",
+		Body:        dto.ReportScheduleRequest{},
+		Response:    dto.ReportScheduleResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodDelete, Path: "/api/v1/report-schedules/:id", ID: "deleteReportSchedule", Tag: "Report Schedules",
+		Summary:     "Delete a report schedule",
+		Description: "Scope: admin. Team members: owner or finance.",
+		Response:    message,
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/report-schedules/:id/run", ID: "runReportSchedule", Tag: "Report Schedules",
+		Summary:     "Deliver a schedule's report now",
+		Description: "Scope: admin. Team members: owner or finance. Delivers the report of the last period, such as to try a destination; a failure is answered with 422 and why.",
+		Response:    dto.ReportScheduleResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	})
+
+	// Accounting
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/accounting/accounts", ID: "getAccountMapping", Tag: "Accounting",
+		Summary:     "Get the accounts QuickBooks and Xero exports post to",
+		Description: "Scope: read_only. Team members: owner, finance or read_only. Default is true until accounts are named.",
+		Response:    dto.AccountMappingResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPut, Path: "/api/v1/accounting/accounts", ID: "updateAccountMapping", Tag: "Accounting",
+		Summary:     "Name the accounts QuickBooks and Xero exports post to",
+		Description: "Scope: admin. Team members: owner or finance. Replaces the whole mapping; empty fields take the defaults. QuickBooks takes account names, Xero account codes.",
+		Body:        dto.UpdateAccountMappingRequest{},
+		Response:    dto.AccountMappingResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodDelete, Path: "/api/v1/accounting/accounts", ID: "resetAccountMapping", Tag: "Accounting",
+		Summary:     "Post QuickBooks and Xero exports to the default accounts",
+		Description: "Scope: admin. Team members: owner or finance.",
+		Response:    dto.AccountMappingResponse{},
+	})
+
+	// GraphQL
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/graphql", ID: "queryGraphQL", Tag: "GraphQL",
+		Summary:     "Run a GraphQL query",
+		Description: "Scope: read_only. Answers {data, errors} as in the GraphQL specification; the schema is at GET /api/v1/graphql/schema.",
+		Body:        dto.GraphQLRequest{},
+		Response:    map[string]interface{}{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/graphql/schema", ID: "getGraphQLSchema", Tag: "GraphQL",
+		Summary:      "Get the GraphQL schema in SDL",
+		Description:  "Scope: read_only.",
+		ContentTypes: []string{"text/plain"},
+	})
+
+	// Events
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/events/stream", ID: "streamEvents", Tag: "Events",
+		Summary:      "Stream transaction and refund events",
+		Description:  "Scope: read_only. Server-sent events with the webhook body as data, resumed after the Last-Event-ID header.",
+		Query:        eventStreamQuery{},
+		ContentTypes: []string{"text/event-stream"},
+		Errors:       []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/ws/transactions", ID: "connectTransactionSocket", Tag: "Events",
+		Summary:     "Follow transactions over a WebSocket",
+		Description: "Scope: read_only. Upgrades to a WebSocket pushing transaction.status messages for the transactions subscribed to; see the API guide for the messages.",
+		Query:       transactionSocketQuery{},
+		Status:      http.StatusSwitchingProtocols,
+		Errors:      []int{http.StatusBadRequest, http.StatusUpgradeRequired},
+	})
+
+	// API keys
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/api-keys", ID: "createAPIKey", Tag: "API Keys",
+		Summary:     "Issue an API key",
+		Description: "Scope: admin. Team members: owner or developer. The key is only shown in this response.",
+		Body:        dto.CreateAPIKeyRequest{},
+		Status:      http.StatusCreated, Response: dto.CreateAPIKeyResponse{},
+		Errors: []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/api-keys", ID: "listAPIKeys", Tag: "API Keys",
+		Summary:     "List API keys",
+		Description: "Scope: admin. Team members: owner or developer.",
+		Response:    dto.ListAPIKeysResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodDelete, Path: "/api/v1/api-keys/:id", ID: "revokeAPIKey", Tag: "API Keys",
+		Summary:     "Revoke an API key",
+		Description: "Scope: admin. Team members: owner or developer.",
+		Response:    dto.APIKeyResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+
+	// Team members
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/auth/login", ID: "login", Tag: "Team",
+		Summary: "Sign a team member in", Public: true,
+		Body:   dto.LoginRequest{},
+		Status: http.StatusCreated, Response: dto.LoginResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/auth/logout", ID: "logout", Tag: "Team",
+		Summary:  "End the team member's session",
+		Response: message,
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/users", ID: "createUser", Tag: "Team",
+		Summary:     "Add a team member",
+		Description: "Scope: admin. Team members: owner.",
+		Body:        dto.CreateUserRequest{},
+		Status:      http.StatusCreated, Response: dto.UserResponse{},
+		Errors: []int{http.StatusBadRequest, http.StatusConflict},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/users", ID: "listUsers", Tag: "Team",
+		Summary:     "List team members",
+		Description: "Scope: admin. Team members: owner.",
+		Response:    dto.ListUsersResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPatch, Path: "/api/v1/users/:id", ID: "updateUserRole", Tag: "Team",
+		Summary:     "Change a team member's role",
+		Description: "Scope: admin. Team members: owner.",
+		Body:        dto.UpdateUserRoleRequest{},
+		Response:    dto.UserResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodDelete, Path: "/api/v1/users/:id", ID: "removeUser", Tag: "Team",
+		Summary:     "Remove a team member",
+		Description: "Scope: admin. Team members: owner.",
+		Response:    message,
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	})
+
+	// Provider credentials
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPut, Path: "/api/v1/provider-credentials/:provider", ID: "saveProviderCredential", Tag: "Provider Credentials",
+		Summary:     "Save the partner's credentials for a provider",
+		Description: "Scope: admin. Team members: owner.",
+		Body:        dto.SaveProviderCredentialRequest{},
+		Response:    dto.ProviderCredentialResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusConflict},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/provider-credentials", ID: "listProviderCredentials", Tag: "Provider Credentials",
+		Summary:     "List the partner's provider credentials",
+		Description: "Scope: admin. Team members: owner. Secrets are never returned.",
+		Response:    dto.ListProviderCredentialsResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodDelete, Path: "/api/v1/provider-credentials/:provider", ID: "deleteProviderCredential", Tag: "Provider Credentials",
+		Summary:     "Delete the partner's credentials for a provider",
+		Description: "Scope: admin. Team members: owner.",
+		Response:    message,
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+
+	// Field filter
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/field-filter", ID: "getFieldFilter", Tag: "Field Filter",
+		Summary:     "Get the fields hidden from the partner's API responses and webhooks",
+		Description: "Scope: admin. Team members: owner.",
+		Response:    dto.FieldFilterResponse{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPut, Path: "/api/v1/field-filter", ID: "updateFieldFilter", Tag: "Field Filter",
+		Summary:     "Replace the fields hidden from the partner's API responses and webhooks",
+		Description: "Scope: admin. Team members: owner. Responses lists fields by scope; a key sees a field if any of its scopes does.",
+		Body:        dto.UpdateFieldFilterRequest{},
+		Response:    dto.FieldFilterResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+
+	// Branding
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPut, Path: "/api/v1/branding/logo", ID: "updateReceiptLogo", Tag: "Branding",
+		Summary:     "Set the logo printed on the partner's receipts",
+		Description: "Scope: admin. Team members: owner. A PNG or JPEG image of at most 512 KB and 1000x1000 pixels, base64 encoded.",
+		Body:        dto.UpdateReceiptLogoRequest{},
+		Status:      http.StatusNoContent,
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodDelete, Path: "/api/v1/branding/logo", ID: "deleteReceiptLogo", Tag: "Branding",
+		Summary:     "Remove the logo printed on the partner's receipts",
+		Description: "Scope: admin. Team members: owner. Receipts then show the partner's name alone.",
+		Status:      http.StatusNoContent,
+	})
+
+	// Audit logs
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/audit-logs", ID: "listAuditLogs", Tag: "Audit Logs",
+		Summary:     "List the partner's audit log",
+		Description: "Scope: admin. Team members: owner. partner_id is ignored; entries are always the caller's own.",
+		Query:       dto.ListAuditLogsRequest{},
+		Response:    dto.ListAuditLogsResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/audit-logs/verify", ID: "verifyAuditLogs", Tag: "Audit Logs",
+		Summary:     "Verify the partner's audit log hash chain",
+		Description: "Scope: admin. Team members: owner.",
+		Response:    dto.AuditChainResponse{},
+	})
+
+	// Usage
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/usage", ID: "getUsage", Tag: "Usage",
+		Summary:     "Get the partner's metered usage by day",
+		Description: "Scope: read_only. Team members: owner, finance or read_only.",
+		Query:       dto.UsageRequest{},
+		Response:    dto.ListUsageResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	})
+
+	// Exports
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/exports", ID: "createExport", Tag: "Exports",
+		Summary:     "Export transactions or refunds in the background",
+		Description: "Scope: read_only. Poll the export until it completed for its download URL. The quickbooks_iif and xero_csv formats hold paid payments or completed refunds, posted to the accounts of GET /api/v1/accounting/accounts.",
+		Body:        dto.CreateExportRequest{},
+		Status:      http.StatusAccepted, Response: dto.ExportResponse{},
+		Errors: []int{http.StatusBadRequest},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/exports/:id", ID: "getExport", Tag: "Exports",
+		Summary:     "Get an export",
+		Description: "Scope: read_only. A completed export comes with a freshly signed download URL.",
+		Response:    dto.ExportResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/exports/:id/download", ID: "downloadExport", Tag: "Exports",
+		Summary:      "Download a completed export",
+		Description:  "Authenticated by the signature of the URL returned with the export, until it expires.",
+		Public:       true,
+		Query:        dto.DownloadExportRequest{},
+		ContentTypes: []string{"text/csv", "application/x-ndjson"},
+		Errors:       []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+	})
+
+	// Provider notifications
+	b.Add(openapi.Endpoint{
+		Method: http.MethodPost, Path: "/api/v1/providers/:provider/events", ID: "receiveProviderEvents", Tag: "Provider Notifications",
+		Summary:     "Receive a provider's notification",
+		Description: "Called by Stripe or Adyen with their own payload, authenticated by the provider's signature header.",
+		Public:      true,
+		Response:    dto.ProviderEventsResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+	})
+
+	// Documentation
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/openapi.json", ID: "getOpenAPI", Tag: "Documentation",
+		Summary: "Get this document", Public: true,
+		Response: map[string]interface{}{},
+	})
+	b.Add(openapi.Endpoint{
+		Method: http.MethodGet, Path: "/api/v1/docs", ID: "getDocs", Tag: "Documentation",
+		Summary: "Browse this document in Swagger UI", Public: true,
+		ContentTypes: []string{"text/html"},
+	})
+
+	// Authenticated v1 routes are deprecated in favor of v2
+	doc := b.Document()
+	for path, item := range doc.Paths {
+		for _, op := range item {
+			if op.Security == nil && strings.HasPrefix(path, "/api/v1/") {
+				op.Deprecated = true
+			}
+		}
+	}
+	return doc
+}
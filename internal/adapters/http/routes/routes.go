@@ -1,46 +1,326 @@
-package routes
 // Package routes configures HTTP routes
 package routes
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	transactions.Post("/:id/refund", transactionHandler.RefundTransaction)	transactions.Post("/:id/process", transactionHandler.ProcessPayment)	transactions.Get("/", transactionHandler.ListTransactions)	transactions.Get("/:id", transactionHandler.GetTransaction)	transactions.Post("/", transactionHandler.CreateTransaction)	transactions := protected.Group("/transactions")	// Transaction routes	protected.Use(middleware.RateLimiter())	protected.Use(middleware.Auth(partnerRepo))	protected := api.Group("")	// Protected routes (require authentication)	health.Get("/live", healthHandler.Live)	health.Get("/ready", healthHandler.Ready)	health.Get("/", healthHandler.Health)	health := api.Group("/health")	// Health check routes (no auth required)	api := app.Group("/api/v1")	// Public routes	app.Use(middleware.Recovery())	app.Use(middleware.Logger())	// Setup middleware) {	partnerRepo ports.PartnerRepository,	healthHandler *handlers.HealthHandler,	transactionHandler *handlers.TransactionHandler,	app *fiber.App,func SetupRoutes(// SetupRoutes configures all application routes)	"github.com/gofiber/fiber/v2"	"Pay2Go/internal/usecases/ports"	"Pay2Go/internal/adapters/http/middleware"	"Pay2Go/internal/adapters/http/handlers"import (
\ No newline at end of file
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// SetupRoutes configures all application routes
+func SetupRoutes(
+	app *fiber.App,
+	transactionHandler *handlers.TransactionHandler,
+	transactionV2Handler *handlers.TransactionV2Handler,
+	refundHandler *handlers.RefundHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	credentialHandler *handlers.ProviderCredentialHandler,
+	userHandler *handlers.UserHandler,
+	partnerHandler *handlers.PartnerHandler,
+	partnerAPIKeyHandler *handlers.PartnerAPIKeyHandler,
+	auditLogHandler *handlers.AuditLogHandler,
+	settlementHandler *handlers.SettlementHandler,
+	verificationHandler *handlers.VerificationHandler,
+	providerEventHandler *handlers.ProviderEventHandler,
+	graphqlHandler *handlers.GraphQLHandler,
+	eventStreamHandler *handlers.EventStreamHandler,
+	transactionSocketHandler *handlers.TransactionSocketHandler,
+	exportHandler *handlers.ExportHandler,
+	fieldFilterHandler *handlers.FieldFilterHandler,
+	receiptHandler *handlers.ReceiptHandler,
+	statementHandler *handlers.StatementHandler,
+	analyticsHandler *handlers.AnalyticsHandler,
+	reportScheduleHandler *handlers.ReportScheduleHandler,
+	accountMappingHandler *handlers.AccountMappingHandler,
+	openAPIHandler *handlers.OpenAPIHandler,
+	authHandler *handlers.AuthHandler,
+	adminAuthHandler *handlers.AdminAuthHandler,
+	healthHandler *handlers.HealthHandler,
+	metricsHandler *handlers.MetricsHandler,
+	sloHandler *handlers.SLOHandler,
+	usageHandler *handlers.UsageHandler,
+	runtimeHandler *handlers.RuntimeHandler,
+	versioning *middleware.Versioning,
+	v1Deprecation middleware.Deprecation,
+	requestLogger *middleware.Logger,
+	accessLog *middleware.AccessLog,
+	requestMetrics *middleware.RequestMetrics,
+	usageMeter middleware.UsageRecorder,
+	recovery *middleware.Recovery,
+	auth *middleware.AuthMiddleware,
+	adminAuth *middleware.AdminAuthMiddleware,
+	rateLimiter *middleware.RateLimiter,
+	loginGuard *middleware.LoginGuard,
+) {
+	// Setup middleware
+	app.Use(middleware.RequestID)
+	// Routes unversioned requests to the version they ask for; what runs
+	// before it runs twice for them
+	app.Use(versioning.Handle)
+	app.Use(requestLogger.Handle)
+	// Redacted access log, when one is kept
+	if accessLog != nil {
+		app.Use(accessLog.Handle)
+	}
+	app.Use(requestMetrics.Handle)
+	// Calls per partner, for billing and capacity planning
+	app.Use(middleware.MeterUsage(usageMeter))
+	app.Use(recovery.Handle)
+	// gzip or brotli, whichever the client accepts; event streams are
+	// flushed event by event and WebSockets take over the connection, so
+	// both are left as they are
+	app.Use(compress.New(compress.Config{
+		Next: func(c *fiber.Ctx) bool {
+			return strings.HasSuffix(c.Path(), "/events/stream") || strings.Contains(c.Path(), "/ws/")
+		},
+	}))
+
+	// List pages are tagged so polling clients only download changed pages
+	conditionalList := middleware.NewConditionalList().Handle
+
+	// Public routes
+	api := app.Group("/api/v1")
+
+	// Health check routes (no auth required)
+	health := api.Group("/health")
+	health.Get("/", healthHandler.Check)
+	health.Get("/ready", healthHandler.Ready)
+	health.Get("/live", healthHandler.Live)
+
+	// Runtime metrics for scrapers (no auth required; keep it off the public
+	// load balancer)
+	app.Get("/metrics", metricsHandler.Metrics)
+
+	// Service levels for the status page (no auth required; keep it off the
+	// public load balancer)
+	app.Get("/slo", sloHandler.Summary)
+
+	// OpenAPI document of the partner API, and Swagger UI to browse it (no
+	// auth required)
+	api.Get("/openapi.json", openAPIHandler.Spec)
+	api.Get("/docs", openAPIHandler.Docs)
+
+	// Payment provider notifications (authenticated by the provider's
+	// signature)
+	api.Post("/providers/:provider/events", providerEventHandler.ReceiveEvents)
+
+	// Export downloads (authenticated by the URL's signature)
+	api.Get("/exports/:id/download", exportHandler.DownloadExport)
+
+	// Team member sign-in (anonymous, so rate limited per IP, and locked out
+	// after repeated failures like API keys)
+	api.Post("/auth/login", rateLimiter.Handle, loginGuard.Handle("user"), authHandler.Login)
+
+	// Team member roles (API keys are governed by scopes alone)
+	approvers := middleware.RequireRole(valueobjects.RoleOwner, valueobjects.RoleFinance)
+	keyManagers := middleware.RequireRole(valueobjects.RoleOwner, valueobjects.RoleDeveloper)
+	reportViewers := middleware.RequireRole(valueobjects.RoleOwner, valueobjects.RoleFinance, valueobjects.RoleReadOnly)
+	owners := middleware.RequireRole(valueobjects.RoleOwner)
+
+	// Refund approval: back-office admin session, or the partner's owners and finance members
+	api.Post("/refunds/:id/approve", v1Deprecation.Handle, middleware.AdminOrPartner(adminAuth, auth), approvers, refundHandler.ApproveRefund)
+
+	// Back-office sign-in (anonymous, so rate limited per IP and locked out
+	// after repeated failures; registered before the admin group so its
+	// session check does not apply)
+	api.Post("/admin/auth/login", rateLimiter.Handle, loginGuard.Handle("admin"), adminAuthHandler.Login)
+
+	// Back-office routes (admin sessions only)
+	adminRoutes := api.Group("/admin", adminAuth.Handle)
+	adminRoutes.Post("/auth/logout", adminAuthHandler.Logout)
+	adminRoutes.Get("/partners", conditionalList, partnerHandler.ListPartners)
+	adminRoutes.Get("/partners/:id", partnerHandler.GetPartner)
+	adminRoutes.Put("/partners/:id", partnerHandler.PutPartner)
+	adminRoutes.Delete("/partners/:id", partnerHandler.Offboard)
+	adminRoutes.Get("/partners/:id/webhook", partnerHandler.GetWebhook)
+	adminRoutes.Put("/partners/:id/webhook", partnerHandler.UpdateWebhook)
+	adminRoutes.Delete("/partners/:id/webhook", partnerHandler.DeleteWebhook)
+	adminRoutes.Get("/partners/:id/api-keys", partnerAPIKeyHandler.ListAPIKeys)
+	adminRoutes.Get("/partners/:id/api-keys/:key_id", partnerAPIKeyHandler.GetAPIKey)
+	adminRoutes.Put("/partners/:id/api-keys/:key_id", partnerAPIKeyHandler.PutAPIKey)
+	adminRoutes.Delete("/partners/:id/api-keys/:key_id", partnerAPIKeyHandler.RevokeAPIKey)
+	adminRoutes.Get("/partners/:id/features", partnerHandler.GetFeatures)
+	adminRoutes.Patch("/partners/:id/features", partnerHandler.UpdateFeatures)
+	adminRoutes.Get("/partners/:id/currencies", partnerHandler.GetCurrencies)
+	adminRoutes.Put("/partners/:id/currencies", partnerHandler.UpdateCurrencies)
+	adminRoutes.Get("/partners/:id/amount-limits", partnerHandler.GetAmountLimits)
+	adminRoutes.Put("/partners/:id/amount-limits", partnerHandler.UpdateAmountLimits)
+	adminRoutes.Get("/partners/:id/locale", partnerHandler.GetLocale)
+	adminRoutes.Put("/partners/:id/locale", partnerHandler.UpdateLocale)
+	adminRoutes.Get("/partners/:id/sms-sender", partnerHandler.GetSMSSender)
+	adminRoutes.Put("/partners/:id/sms-sender", partnerHandler.UpdateSMSSender)
+	adminRoutes.Get("/partners/:id/rounding-policy", partnerHandler.GetRoundingPolicy)
+	adminRoutes.Put("/partners/:id/rounding-policy", partnerHandler.UpdateRoundingPolicy)
+	adminRoutes.Get("/partners/:id/pricing", statementHandler.GetPricing)
+	adminRoutes.Put("/partners/:id/pricing", statementHandler.UpdatePricing)
+	adminRoutes.Post("/partners/:id/statements", statementHandler.GenerateStatement)
+	adminRoutes.Get("/partners/:id/event-destination", partnerHandler.GetEventDestination)
+	adminRoutes.Put("/partners/:id/event-destination", partnerHandler.UpdateEventDestination)
+	adminRoutes.Delete("/partners/:id/event-destination", partnerHandler.DeleteEventDestination)
+	adminRoutes.Post("/secrets/rotate", partnerHandler.RotateSecrets)
+	adminRoutes.Delete("/transactions/:id", transactionHandler.DeleteTransaction)
+	adminRoutes.Post("/transactions/:id/restore", transactionHandler.RestoreTransaction)
+	adminRoutes.Delete("/refunds/:id", refundHandler.DeleteRefund)
+	adminRoutes.Post("/refunds/:id/restore", refundHandler.RestoreRefund)
+	adminRoutes.Get("/audit-logs", conditionalList, auditLogHandler.ListAllAuditLogs)
+	adminRoutes.Get("/audit-logs/verify", auditLogHandler.VerifyAllAuditLogs)
+	adminRoutes.Get("/usage", usageHandler.GetUsageRollup)
+	adminRoutes.Get("/settlements/discrepancies", conditionalList, settlementHandler.ListDiscrepancies)
+	adminRoutes.Get("/statements", conditionalList, statementHandler.ListPeriodStatements)
+	adminRoutes.Get("/statements/:id/download", statementHandler.AdminDownloadStatement)
+
+	// Diagnostics of the instance that answers: profiles under
+	// /admin/debug/pprof/ and runtime settings such as the log level
+	adminRoutes.Get("/runtime", runtimeHandler.GetSettings)
+	adminRoutes.Patch("/runtime", runtimeHandler.UpdateSettings)
+	adminRoutes.Use(pprof.New(pprof.Config{Prefix: "/api/v1/admin"}))
+
+	// API key scopes
+	readOnly := middleware.RequireScope(valueobjects.ScopeReadOnly)
+	payments := middleware.RequireScope(valueobjects.ScopePayments)
+	refundsScope := middleware.RequireScope(valueobjects.ScopeRefunds)
+	admin := middleware.RequireScope(valueobjects.ScopeAdmin)
+
+	// Partner routes, the same in every version unless a version overrides
+	// them first
+	partnerRoutes := func(protected fiber.Router) {
+		// Transaction routes
+		transactions := protected.Group("/transactions")
+		transactions.Post("/", payments, transactionHandler.CreateTransaction)
+		transactions.Get("/export", readOnly, transactionHandler.ExportTransactions)
+		transactions.Get("/:id", readOnly, transactionHandler.GetTransaction)
+		transactions.Get("/", readOnly, conditionalList, transactionHandler.ListTransactions)
+		transactions.Post("/:id/process", payments, transactionHandler.ProcessPayment)
+		transactions.Post("/:id/refund", refundsScope, transactionHandler.RefundTransaction)
+		// PDF receipts of paid transactions, reissued after refunds
+		transactions.Get("/:id/receipt", readOnly, receiptHandler.GetReceipt)
+		// One-time codes texted to customers to confirm pending payments
+		transactions.Post("/:id/verification-code", payments, verificationHandler.SendCode)
+		transactions.Post("/:id/verification-code/verify", payments, verificationHandler.VerifyCode)
+
+		// Refund routes
+		refunds := protected.Group("/refunds")
+		refunds.Get("/reasons", readOnly, reportViewers, refundHandler.GetReasonSummary)
+		refunds.Post("/bulk", refundsScope, refundHandler.BulkRefund)
+		refunds.Get("/bulk/:id", readOnly, refundHandler.GetBulkRefundJob)
+		refunds.Post("/:id/cancel", refundsScope, refundHandler.CancelRefund)
+
+		// Exports too large to page through, produced in the background
+		exports := protected.Group("/exports")
+		exports.Post("/", readOnly, exportHandler.CreateExport)
+		exports.Get("/:id", readOnly, exportHandler.GetExport)
+
+		// Chargebacks reported in providers' settlement feeds
+		protected.Get("/disputes", readOnly, reportViewers, conditionalList, settlementHandler.ListDisputes)
+
+		// Monthly statements of volume and fees, issued once the month is over
+		statements := protected.Group("/statements")
+		statements.Get("/", readOnly, reportViewers, conditionalList, statementHandler.ListStatements)
+		statements.Get("/:id", readOnly, reportViewers, statementHandler.GetStatement)
+		statements.Get("/:id/download", readOnly, reportViewers, statementHandler.DownloadStatement)
+
+		// Payment analytics, read from daily rollups
+		analytics := protected.Group("/analytics")
+		analytics.Get("/volume", readOnly, reportViewers, analyticsHandler.GetVolume)
+		analytics.Get("/success-rates", readOnly, reportViewers, analyticsHandler.GetSuccessRates)
+		analytics.Get("/average-amounts", readOnly, reportViewers, analyticsHandler.GetAverageAmounts)
+		analytics.Get("/refund-rates", readOnly, reportViewers, analyticsHandler.GetRefundRates)
+
+		// Reports delivered on a schedule by email, SFTP or S3
+		reportSchedules := protected.Group("/report-schedules")
+		reportSchedules.Post("/", admin, approvers, reportScheduleHandler.CreateSchedule)
+		reportSchedules.Get("/", admin, approvers, reportScheduleHandler.ListSchedules)
+		reportSchedules.Get("/:id", admin, approvers, reportScheduleHandler.GetSchedule)
+		reportSchedules.Put("/:id", admin, approvers, reportScheduleHandler.UpdateSchedule)
+		reportSchedules.Delete("/:id", admin, approvers, reportScheduleHandler.DeleteSchedule)
+		reportSchedules.Post("/:id/run", admin, approvers, reportScheduleHandler.RunSchedule)
+
+		// Accounts the partner's QuickBooks and Xero exports post to
+		accounting := protected.Group("/accounting")
+		accounting.Get("/accounts", readOnly, reportViewers, accountMappingHandler.GetAccountMapping)
+		accounting.Put("/accounts", admin, approvers, accountMappingHandler.UpdateAccountMapping)
+		accounting.Delete("/accounts", admin, approvers, accountMappingHandler.ResetAccountMapping)
+
+		// Read-only GraphQL over transactions, refunds and their webhook events
+		protected.Post("/graphql", readOnly, graphqlHandler.Query)
+		protected.Get("/graphql/schema", readOnly, graphqlHandler.Schema)
+
+		// Transaction and refund events as server-sent events, for
+		// dashboards following them live instead of receiving webhooks
+		protected.Get("/events/stream", readOnly, eventStreamHandler.Stream)
+
+		// Status of chosen transactions pushed over a WebSocket, for
+		// terminals waiting on payments made out-of-band
+		protected.Get("/ws/transactions", readOnly, transactionSocketHandler.Connect)
+
+		// API key management routes
+		apiKeys := protected.Group("/api-keys")
+		apiKeys.Post("/", admin, keyManagers, apiKeyHandler.CreateAPIKey)
+		apiKeys.Get("/", admin, keyManagers, conditionalList, apiKeyHandler.ListAPIKeys)
+		apiKeys.Delete("/:id", admin, keyManagers, apiKeyHandler.RevokeAPIKey)
+
+		// Partner's own provider account routes
+		credentials := protected.Group("/provider-credentials")
+		credentials.Put("/:provider", admin, owners, credentialHandler.SaveCredential)
+		credentials.Get("/", admin, owners, conditionalList, credentialHandler.ListCredentials)
+		credentials.Delete("/:provider", admin, owners, credentialHandler.DeleteCredential)
+
+		// Team management routes
+		users := protected.Group("/users")
+		users.Post("/", admin, owners, userHandler.CreateUser)
+		users.Get("/", admin, owners, conditionalList, userHandler.ListUsers)
+		users.Patch("/:id", admin, owners, userHandler.UpdateUserRole)
+		users.Delete("/:id", admin, owners, userHandler.RemoveUser)
+
+		// Fields hidden from the partner's API responses and webhooks
+		protected.Get("/field-filter", admin, owners, fieldFilterHandler.GetFieldFilter)
+		protected.Put("/field-filter", admin, owners, fieldFilterHandler.UpdateFieldFilter)
+
+		// Logo printed on the partner's receipts
+		protected.Put("/branding/logo", admin, owners, receiptHandler.UpdateReceiptLogo)
+		protected.Delete("/branding/logo", admin, owners, receiptHandler.DeleteReceiptLogo)
+
+		// Partner's own audit trail
+		protected.Get("/audit-logs", admin, owners, conditionalList, auditLogHandler.ListAuditLogs)
+		protected.Get("/audit-logs/verify", admin, owners, auditLogHandler.VerifyAuditLogs)
+
+		// Partner's own metered usage
+		protected.Get("/usage", readOnly, reportViewers, usageHandler.GetUsage)
+
+		// Session routes
+		protected.Post("/auth/logout", authHandler.Logout)
+	}
+
+	// Protected routes (require authentication); deprecated in favor of v2
+	protected := api.Group("")
+	protected.Use(v1Deprecation.Handle)
+	protected.Use(auth.Handle)
+	protected.Use(rateLimiter.Handle)
+	protected.Use(middleware.FilterFields)
+	partnerRoutes(protected)
+
+	// API v2: transactions as payment intents with money amounts; the rest
+	// as in v1
+	v2 := app.Group("/api/v2")
+	v2.Post("/refunds/:id/approve", middleware.AdminOrPartner(adminAuth, auth), approvers, refundHandler.ApproveRefund)
+	v2.Get("/exports/:id/download", exportHandler.DownloadExport)
+
+	v2Protected := v2.Group("")
+	v2Protected.Use(auth.Handle)
+	v2Protected.Use(rateLimiter.Handle)
+	v2Protected.Use(middleware.FilterFields)
+
+	// IDs are constrained so /transactions/export stays with v1's handler
+	v2Transactions := v2Protected.Group("/transactions")
+	v2Transactions.Post("/", payments, transactionV2Handler.CreateTransaction)
+	v2Transactions.Get("/", readOnly, conditionalList, transactionV2Handler.ListTransactions)
+	v2Transactions.Get("/:id<guid>", readOnly, transactionV2Handler.GetTransaction)
+	v2Transactions.Post("/:id<guid>/confirm", payments, transactionV2Handler.ConfirmTransaction)
+	partnerRoutes(v2Protected)
+}
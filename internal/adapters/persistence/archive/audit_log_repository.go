@@ -0,0 +1,124 @@
+package archive
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// AuditLogRepository implements ports.AuditLogRepository over a database
+// repository and the archive its old entries are moved to, so readers see
+// one audit trail wherever an entry is kept
+type AuditLogRepository struct {
+	db      ports.AuditLogRepository
+	archive ports.EventArchive
+	// retention is how long entries stay in the database; queries only for
+	// newer entries skip the archive
+	retention time.Duration
+}
+
+// NewAuditLogRepository creates a repository reading from db and archive
+func NewAuditLogRepository(db ports.AuditLogRepository, archive ports.EventArchive, retention time.Duration) *AuditLogRepository {
+	return &AuditLogRepository{db: db, archive: archive, retention: retention}
+}
+
+// LogAction logs to the database
+func (r *AuditLogRepository) LogAction(ctx context.Context, action ports.AuditAction) error {
+	return r.db.LogAction(ctx, action)
+}
+
+// List merges the matching entries of the database and the archive. Both
+// are asked for everything up to the end of the page, since the latest entry
+// of a chain stays in the database however old it is.
+func (r *AuditLogRepository) List(ctx context.Context, q ports.AuditLogQuery) ([]*ports.AuditLogEntry, int64, error) {
+	if q.CreatedFrom != nil && q.CreatedFrom.After(time.Now().Add(-r.retention)) {
+		return r.db.List(ctx, q)
+	}
+
+	upToPage := q
+	upToPage.Offset = 0
+	upToPage.Limit = q.Offset + q.Limit
+
+	current, currentTotal, err := r.db.List(ctx, upToPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	archived, archivedTotal, err := r.archive.ListAuditLogs(ctx, upToPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := append(current, archived...)
+	sort.Slice(entries, func(i, j int) bool {
+		return newerAuditLogEntry(entries[i], entries[j])
+	})
+
+	start := q.Offset
+	if start > len(entries) {
+		start = len(entries)
+	}
+	end := len(entries)
+	if start+q.Limit < end {
+		end = start + q.Limit
+	}
+	return entries[start:end], currentTotal + archivedTotal, nil
+}
+
+// ListChain merges the chain's archived and current entries in ID order
+func (r *AuditLogRepository) ListChain(ctx context.Context, partnerID uuid.UUID, afterID int64, limit int) ([]*ports.AuditLogEntry, error) {
+	archived, err := r.archive.ListAuditChain(ctx, partnerID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	current, err := r.db.ListChain(ctx, partnerID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := append(archived, current...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// ListChainPartnerIDs returns the partners with entries in the database or
+// the archive
+func (r *AuditLogRepository) ListChainPartnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	partnerIDs, err := r.db.ListChainPartnerIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := r.archive.ListAuditChainPartnerIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(partnerIDs))
+	for _, partnerID := range partnerIDs {
+		seen[partnerID] = true
+	}
+	for _, partnerID := range archived {
+		if !seen[partnerID] {
+			seen[partnerID] = true
+			partnerIDs = append(partnerIDs, partnerID)
+		}
+	}
+	sortUUIDs(partnerIDs)
+	return partnerIDs, nil
+}
+
+// ListArchivable lists the database entries due for archiving
+func (r *AuditLogRepository) ListArchivable(ctx context.Context, before time.Time, limit int) ([]*ports.AuditLogEntry, error) {
+	return r.db.ListArchivable(ctx, before, limit)
+}
+
+// Delete removes entries from the database
+func (r *AuditLogRepository) Delete(ctx context.Context, ids []int64) error {
+	return r.db.Delete(ctx, ids)
+}
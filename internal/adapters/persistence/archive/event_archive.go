@@ -0,0 +1,452 @@
+// Package archive keeps old audit entries and published outbox events in an
+// object store, such as an S3 bucket, once they are moved out of the
+// database.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Objects are gzipped JSON lines, one entry or event per line, under
+//
+//	audit-logs/<partner ID or "none">/<YYYY-MM>/<first ID>-<last ID>.jsonl.gz
+//	outbox-events/<YYYY-MM>/<ID of the first event>.jsonl.gz
+//
+// Audit entries are grouped by chain and by the month they were logged, so
+// reading a chain or a range of months lists a single prefix. IDs are
+// zero-padded to sort in lexical order. Outbox events are grouped by the
+// month they were published.
+const (
+	auditLogPrefix    = "audit-logs/"
+	outboxEventPrefix = "outbox-events/"
+	objectSuffix      = ".jsonl.gz"
+
+	// noPartnerChain names the chain of actions logged without a partner
+	noPartnerChain = "none"
+)
+
+// EventArchive implements ports.EventArchive on top of a ports.ObjectStore
+type EventArchive struct {
+	store ports.ObjectStore
+}
+
+// NewEventArchive creates an archive storing its objects in store
+func NewEventArchive(store ports.ObjectStore) *EventArchive {
+	return &EventArchive{store: store}
+}
+
+// auditRecord is how an audit entry is archived
+type auditRecord struct {
+	ID           int64                  `json:"id"`
+	PartnerID    uuid.UUID              `json:"partner_id"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   uuid.UUID              `json:"resource_id"`
+	IPAddress    string                 `json:"ip_address"`
+	UserAgent    string                 `json:"user_agent"`
+	RequestID    uuid.UUID              `json:"request_id"`
+	Changes      map[string]interface{} `json:"changes,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	PrevHash     string                 `json:"prev_hash,omitempty"`
+	Hash         string                 `json:"hash,omitempty"`
+}
+
+// outboxRecord is how a published outbox event is archived
+type outboxRecord struct {
+	ID            uuid.UUID       `json:"id"`
+	PartnerID     uuid.UUID       `json:"partner_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at"`
+}
+
+// ArchiveAuditLogs stores entries in one object per chain and month
+func (a *EventArchive) ArchiveAuditLogs(ctx context.Context, entries []*ports.AuditLogEntry) error {
+	groups := make(map[string][]*ports.AuditLogEntry)
+	for _, entry := range entries {
+		prefix := auditLogPrefix + chainName(entry.PartnerID) + "/" + entry.CreatedAt.UTC().Format("2006-01") + "/"
+		groups[prefix] = append(groups[prefix], entry)
+	}
+
+	for prefix, group := range groups {
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+
+		records := make([]interface{}, len(group))
+		for i, entry := range group {
+			records[i] = auditRecord{
+				ID:           entry.ID,
+				PartnerID:    entry.PartnerID,
+				Action:       entry.Action,
+				ResourceType: entry.ResourceType,
+				ResourceID:   entry.ResourceID,
+				IPAddress:    entry.IPAddress,
+				UserAgent:    entry.UserAgent,
+				RequestID:    entry.RequestID,
+				Changes:      entry.Changes,
+				CreatedAt:    entry.CreatedAt.UTC(),
+				PrevHash:     entry.PrevHash,
+				Hash:         entry.Hash,
+			}
+		}
+
+		key := fmt.Sprintf("%s%020d-%020d%s", prefix, group[0].ID, group[len(group)-1].ID, objectSuffix)
+		if err := a.put(ctx, key, records); err != nil {
+			return fmt.Errorf("failed to archive audit logs: %w", err)
+		}
+	}
+	return nil
+}
+
+// ArchiveOutboxEvents stores events in one object per month of publishing
+func (a *EventArchive) ArchiveOutboxEvents(ctx context.Context, events []*entities.OutboxEvent) error {
+	groups := make(map[string][]*entities.OutboxEvent)
+	var months []string
+	for _, event := range events {
+		at := event.CreatedAt
+		if event.PublishedAt != nil {
+			at = *event.PublishedAt
+		}
+		month := outboxEventPrefix + at.UTC().Format("2006-01") + "/"
+		if _, ok := groups[month]; !ok {
+			months = append(months, month)
+		}
+		groups[month] = append(groups[month], event)
+	}
+
+	for _, month := range months {
+		group := groups[month]
+		records := make([]interface{}, len(group))
+		for i, event := range group {
+			records[i] = outboxRecord{
+				ID:            event.ID,
+				PartnerID:     event.PartnerID,
+				AggregateType: event.AggregateType,
+				AggregateID:   event.AggregateID,
+				EventType:     event.EventType,
+				Payload:       event.Payload,
+				Attempts:      event.Attempts,
+				CreatedAt:     event.CreatedAt.UTC(),
+				PublishedAt:   event.PublishedAt,
+			}
+		}
+
+		if err := a.put(ctx, month+group[0].ID.String()+objectSuffix, records); err != nil {
+			return fmt.Errorf("failed to archive outbox events: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListAuditLogs reads every archived object of the chains and months q can
+// match, newest entries first. Archived entries are read rarely, so nothing
+// is indexed beyond the object keys.
+func (a *EventArchive) ListAuditLogs(ctx context.Context, q ports.AuditLogQuery) ([]*ports.AuditLogEntry, int64, error) {
+	prefix := auditLogPrefix
+	if q.PartnerID != nil {
+		prefix += chainName(*q.PartnerID) + "/"
+	}
+	keys, err := a.store.List(ctx, prefix)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list archived audit logs: %w", err)
+	}
+
+	var objects []auditObject
+	for _, key := range keys {
+		object, ok := parseAuditObjectKey(key)
+		if ok && object.overlaps(q.CreatedFrom, q.CreatedTo) {
+			objects = append(objects, object)
+		}
+	}
+
+	entries, err := a.readAuditObjects(ctx, objects)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matching []*ports.AuditLogEntry
+	for _, entry := range entries {
+		if matchesAuditLogQuery(entry, q) {
+			matching = append(matching, entry)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return newerAuditLogEntry(matching[i], matching[j])
+	})
+
+	total := int64(len(matching))
+	start := q.Offset
+	if start > len(matching) {
+		start = len(matching)
+	}
+	end := len(matching)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+	return matching[start:end], total, nil
+}
+
+// ListAuditChain reads the archived objects of a chain holding entries with
+// IDs above afterID, in ID order, until limit entries are certain
+func (a *EventArchive) ListAuditChain(ctx context.Context, partnerID uuid.UUID, afterID int64, limit int) ([]*ports.AuditLogEntry, error) {
+	keys, err := a.store.List(ctx, auditLogPrefix+chainName(partnerID)+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived audit logs: %w", err)
+	}
+
+	var objects []auditObject
+	for _, key := range keys {
+		if object, ok := parseAuditObjectKey(key); ok && object.lastID > afterID {
+			objects = append(objects, object)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].firstID < objects[j].firstID })
+
+	var entries []*ports.AuditLogEntry
+	seen := make(map[int64]bool)
+	for _, object := range objects {
+		// Objects starting after the limit-th entry found cannot change the result
+		if limit > 0 && len(entries) >= limit && object.firstID > entries[limit-1].ID {
+			break
+		}
+
+		read, err := a.readAuditObjects(ctx, []auditObject{object})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range read {
+			if entry.ID > afterID && !seen[entry.ID] {
+				seen[entry.ID] = true
+				entries = append(entries, entry)
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// ListAuditChainPartnerIDs returns the chains with archived entries, uuid.Nil
+// standing for the actions logged without a partner
+func (a *EventArchive) ListAuditChainPartnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	keys, err := a.store.List(ctx, auditLogPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived audit logs: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var partnerIDs []uuid.UUID
+	for _, key := range keys {
+		object, ok := parseAuditObjectKey(key)
+		if ok && !seen[object.partnerID] {
+			seen[object.partnerID] = true
+			partnerIDs = append(partnerIDs, object.partnerID)
+		}
+	}
+	sortUUIDs(partnerIDs)
+	return partnerIDs, nil
+}
+
+// auditObject is what the key of an archived audit object tells
+type auditObject struct {
+	key       string
+	partnerID uuid.UUID
+	month     time.Time
+	firstID   int64
+	lastID    int64
+}
+
+// parseAuditObjectKey reads the key of an audit object, ignoring other keys
+func parseAuditObjectKey(key string) (auditObject, bool) {
+	parts := strings.Split(strings.TrimPrefix(key, auditLogPrefix), "/")
+	if len(parts) != 3 || !strings.HasSuffix(parts[2], objectSuffix) {
+		return auditObject{}, false
+	}
+
+	object := auditObject{key: key}
+	if parts[0] != noPartnerChain {
+		partnerID, err := uuid.Parse(parts[0])
+		if err != nil {
+			return auditObject{}, false
+		}
+		object.partnerID = partnerID
+	}
+
+	month, err := time.Parse("2006-01", parts[1])
+	if err != nil {
+		return auditObject{}, false
+	}
+	object.month = month
+
+	ids := strings.SplitN(strings.TrimSuffix(parts[2], objectSuffix), "-", 2)
+	if len(ids) != 2 {
+		return auditObject{}, false
+	}
+	if object.firstID, err = strconv.ParseInt(ids[0], 10, 64); err != nil {
+		return auditObject{}, false
+	}
+	if object.lastID, err = strconv.ParseInt(ids[1], 10, 64); err != nil {
+		return auditObject{}, false
+	}
+	return object, true
+}
+
+// overlaps reports whether the month of the object intersects [from, to)
+func (o auditObject) overlaps(from, to *time.Time) bool {
+	if from != nil && !o.month.AddDate(0, 1, 0).After(*from) {
+		return false
+	}
+	if to != nil && !o.month.Before(*to) {
+		return false
+	}
+	return true
+}
+
+// readAuditObjects reads the entries of objects. An entry archived twice, by
+// a move that was interrupted and repeated, is returned once.
+func (a *EventArchive) readAuditObjects(ctx context.Context, objects []auditObject) ([]*ports.AuditLogEntry, error) {
+	var entries []*ports.AuditLogEntry
+	seen := make(map[int64]bool)
+	for _, object := range objects {
+		data, err := a.store.Get(ctx, object.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archived audit logs: %w", err)
+		}
+
+		err = decodeLines(data, func(line []byte) error {
+			var record auditRecord
+			if err := json.Unmarshal(line, &record); err != nil {
+				return err
+			}
+			if seen[record.ID] {
+				return nil
+			}
+			seen[record.ID] = true
+
+			entries = append(entries, &ports.AuditLogEntry{
+				ID: record.ID,
+				AuditAction: ports.AuditAction{
+					PartnerID:    record.PartnerID,
+					Action:       record.Action,
+					ResourceType: record.ResourceType,
+					ResourceID:   record.ResourceID,
+					IPAddress:    record.IPAddress,
+					UserAgent:    record.UserAgent,
+					RequestID:    record.RequestID,
+					Changes:      record.Changes,
+				},
+				CreatedAt: record.CreatedAt,
+				PrevHash:  record.PrevHash,
+				Hash:      record.Hash,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", object.key, err)
+		}
+	}
+	return entries, nil
+}
+
+// put stores records as gzipped JSON lines
+func (a *EventArchive) put(ctx context.Context, key string, records []interface{}) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return a.store.Put(ctx, key, buf.Bytes())
+}
+
+// decodeLines calls fn with every line of gzipped JSON lines
+func decodeLines(data []byte, fn func(line []byte) error) error {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// chainName names the directory of a partner's chain
+func chainName(partnerID uuid.UUID) string {
+	if partnerID == uuid.Nil {
+		return noPartnerChain
+	}
+	return partnerID.String()
+}
+
+// matchesAuditLogQuery applies the filters of q, without paging
+func matchesAuditLogQuery(entry *ports.AuditLogEntry, q ports.AuditLogQuery) bool {
+	if q.PartnerID != nil && entry.PartnerID != *q.PartnerID {
+		return false
+	}
+	if q.Action != "" && entry.Action != q.Action {
+		return false
+	}
+	if q.ResourceType != "" && entry.ResourceType != q.ResourceType {
+		return false
+	}
+	if q.ResourceID != nil && entry.ResourceID != *q.ResourceID {
+		return false
+	}
+	if q.CreatedFrom != nil && entry.CreatedAt.Before(*q.CreatedFrom) {
+		return false
+	}
+	if q.CreatedTo != nil && !entry.CreatedAt.Before(*q.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// newerAuditLogEntry orders entries as AuditLogRepository.List does
+func newerAuditLogEntry(a, b *ports.AuditLogEntry) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID > b.ID
+}
+
+// sortUUIDs sorts IDs by their bytes, the order the databases use
+func sortUUIDs(ids []uuid.UUID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+}
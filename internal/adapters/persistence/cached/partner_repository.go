@@ -0,0 +1,162 @@
+// Package cached puts caches in front of repositories on the hot path.
+package cached
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// PartnerRepository implements ports.PartnerRepository with partners cached
+// in process and, optionally, in a cache shared by every instance.
+//
+// Only read-only contexts (see ports.ReadOnly) are answered from the cache,
+// since they accept slightly stale data anyway; reads that precede a write go
+// to the database. Changes made through the repository invalidate the
+// partner everywhere. A read racing with a change can still cache the old
+// partner, for at most the TTL.
+type PartnerRepository struct {
+	ports.PartnerRepository
+	local ports.CacheService
+	// shared is nil without a shared cache
+	shared ports.PartnerCache
+	ttl    time.Duration
+}
+
+// NewPartnerRepository caches the partners of next for ttl
+func NewPartnerRepository(next ports.PartnerRepository, local ports.CacheService, shared ports.PartnerCache, ttl time.Duration) *PartnerRepository {
+	return &PartnerRepository{
+		PartnerRepository: next,
+		local:             local,
+		shared:            shared,
+		ttl:               ttl,
+	}
+}
+
+// GetByID returns a cached copy of the partner in read-only contexts
+func (r *PartnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Partner, error) {
+	if !ports.IsReadOnly(ctx) {
+		return r.PartnerRepository.GetByID(ctx, id)
+	}
+
+	key := partnerKey(id)
+	if cached, _ := r.local.Get(ctx, key); cached != nil {
+		if partner, ok := cached.(*entities.Partner); ok {
+			return clonePartner(partner), nil
+		}
+	}
+
+	if r.shared != nil {
+		if partner, err := r.shared.Get(ctx, id); err == nil && partner != nil {
+			_ = r.local.Set(ctx, key, clonePartner(partner), r.ttlSeconds())
+			return partner, nil
+		}
+	}
+
+	partner, err := r.PartnerRepository.GetByID(ctx, id)
+	if err != nil || partner == nil {
+		return partner, err
+	}
+	_ = r.local.Set(ctx, key, clonePartner(partner), r.ttlSeconds())
+	if r.shared != nil {
+		_ = r.shared.Set(ctx, partner, r.ttl)
+	}
+	return partner, nil
+}
+
+// Update saves the partner and invalidates it
+func (r *PartnerRepository) Update(ctx context.Context, partner *entities.Partner) error {
+	if err := r.PartnerRepository.Update(ctx, partner); err != nil {
+		return err
+	}
+	r.invalidate(ctx, partner.ID)
+	return nil
+}
+
+// MarkAnonymized records the anonymization and invalidates the partner
+func (r *PartnerRepository) MarkAnonymized(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if err := r.PartnerRepository.MarkAnonymized(ctx, id, at); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// Run drops the partners other instances invalidate from the in-process
+// cache until ctx is done. Without a shared cache there is nothing to listen
+// to, and other instances' changes show once the TTL expires.
+func (r *PartnerRepository) Run(ctx context.Context) error {
+	if r.shared == nil {
+		return nil
+	}
+	return r.shared.Subscribe(ctx, func(id uuid.UUID) {
+		_ = r.local.Delete(ctx, partnerKey(id))
+	})
+}
+
+// invalidate drops the partner from both caches. A failure only delays the
+// change until the TTL expires, so it does not fail the write.
+func (r *PartnerRepository) invalidate(ctx context.Context, id uuid.UUID) {
+	_ = r.local.Delete(ctx, partnerKey(id))
+	if r.shared != nil {
+		_ = r.shared.Invalidate(ctx, id)
+	}
+}
+
+// ttlSeconds is the TTL for ports.CacheService, at least a second
+func (r *PartnerRepository) ttlSeconds() int {
+	if seconds := int(r.ttl / time.Second); seconds > 0 {
+		return seconds
+	}
+	return 1
+}
+
+func partnerKey(id uuid.UUID) string {
+	return "partner:" + id.String()
+}
+
+// clonePartner copies p deeply enough that callers changing the copy leave
+// the cached partner alone
+func clonePartner(p *entities.Partner) *entities.Partner {
+	c := *p
+	if p.Features != nil {
+		c.Features = make(map[valueobjects.PartnerFeature]bool, len(p.Features))
+		for k, v := range p.Features {
+			c.Features[k] = v
+		}
+	}
+	if p.AllowedCurrencies != nil {
+		c.AllowedCurrencies = append([]valueobjects.Currency{}, p.AllowedCurrencies...)
+	}
+	if p.AmountLimits != nil {
+		c.AmountLimits = make(valueobjects.AmountLimits, len(p.AmountLimits))
+		for k, v := range p.AmountLimits {
+			c.AmountLimits[k] = v
+		}
+	}
+	if p.EventDestination != nil {
+		destination := *p.EventDestination
+		c.EventDestination = &destination
+	}
+	c.FieldFilter = p.FieldFilter.Clone()
+	if p.Pricing != nil {
+		pricing := p.Pricing.Clone()
+		c.Pricing = &pricing
+	}
+	if p.AccountMapping != nil {
+		mapping := *p.AccountMapping
+		c.AccountMapping = &mapping
+	}
+	if p.Metadata != nil {
+		c.Metadata = make(map[string]interface{}, len(p.Metadata))
+		for k, v := range p.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return &c
+}
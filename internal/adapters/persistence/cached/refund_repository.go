@@ -0,0 +1,53 @@
+package cached
+
+import (
+	"context"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RefundRepository implements ports.RefundRepository, removing a transaction
+// from the cache TransactionRepository invalidates whenever a refund
+// reservation or release moves its refunded amount
+type RefundRepository struct {
+	ports.RefundRepository
+	transactions ports.TransactionRepository
+	cache        ports.TransactionCache
+}
+
+// NewRefundRepository invalidates the transactions whose refunded amount next
+// changes in cache, looking up their partner in transactions
+func NewRefundRepository(next ports.RefundRepository, transactions ports.TransactionRepository, cache ports.TransactionCache) *RefundRepository {
+	return &RefundRepository{RefundRepository: next, transactions: transactions, cache: cache}
+}
+
+// Reserve creates the refund, reserves its amount and invalidates the transaction
+func (r *RefundRepository) Reserve(ctx context.Context, refund *entities.Refund) error {
+	if err := r.RefundRepository.Reserve(ctx, refund); err != nil {
+		return err
+	}
+	r.invalidate(ctx, refund)
+	return nil
+}
+
+// Release saves the refund, returns its amount and invalidates the transaction
+func (r *RefundRepository) Release(ctx context.Context, refund *entities.Refund) error {
+	if err := r.RefundRepository.Release(ctx, refund); err != nil {
+		return err
+	}
+	r.invalidate(ctx, refund)
+	return nil
+}
+
+// invalidate drops the refund's transaction from the cache once the write
+// commits. The transaction is read in the write's own unit of work, as it
+// is gone from ctx afterwards; if it cannot be, its cached copy lags by up
+// to the TTL.
+func (r *RefundRepository) invalidate(ctx context.Context, refund *entities.Refund) {
+	transaction, err := r.transactions.GetByID(ctx, refund.TransactionID)
+	if err != nil {
+		return
+	}
+	invalidateTransaction(ctx, r.cache, transaction.PartnerID, transaction.ID)
+}
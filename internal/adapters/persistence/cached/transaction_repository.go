@@ -0,0 +1,71 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// TransactionRepository implements ports.TransactionRepository, removing a
+// transaction from the cache GET /transactions/:id reads through whenever
+// it is saved or retried, so no status transition is served stale.
+//
+// Inside a unit of work the cache is invalidated once it commits, so a read
+// racing with the save cannot cache the state it replaces. The refunded
+// amount that refund reservations move is invalidated by RefundRepository.
+// AnonymizeCustomerData, which changes a partner's transactions in bulk,
+// still lags by up to the cache's TTL; it only runs on offboarded partners,
+// whose keys no longer read anything.
+type TransactionRepository struct {
+	ports.TransactionRepository
+	cache ports.TransactionCache
+}
+
+// NewTransactionRepository invalidates the transactions next saves in cache
+func NewTransactionRepository(next ports.TransactionRepository, cache ports.TransactionCache) *TransactionRepository {
+	return &TransactionRepository{TransactionRepository: next, cache: cache}
+}
+
+// Update saves the transaction and invalidates it
+func (r *TransactionRepository) Update(ctx context.Context, transaction *entities.Transaction) error {
+	if err := r.TransactionRepository.Update(ctx, transaction); err != nil {
+		return err
+	}
+	r.invalidate(ctx, transaction)
+	return nil
+}
+
+// IncrementRetryCount moves the transaction back to pending and invalidates it
+func (r *TransactionRepository) IncrementRetryCount(ctx context.Context, transaction *entities.Transaction, maxRetries int) error {
+	if err := r.TransactionRepository.IncrementRetryCount(ctx, transaction, maxRetries); err != nil {
+		return err
+	}
+	r.invalidate(ctx, transaction)
+	return nil
+}
+
+// Delete soft-deletes the transaction and invalidates it
+func (r *TransactionRepository) Delete(ctx context.Context, transaction *entities.Transaction) error {
+	if err := r.TransactionRepository.Delete(ctx, transaction); err != nil {
+		return err
+	}
+	r.invalidate(ctx, transaction)
+	return nil
+}
+
+// invalidate drops the transaction from the cache once the write commits
+func (r *TransactionRepository) invalidate(ctx context.Context, transaction *entities.Transaction) {
+	invalidateTransaction(ctx, r.cache, transaction.PartnerID, transaction.ID)
+}
+
+// invalidateTransaction drops a transaction from cache once the unit of
+// work in ctx commits, or right away outside one. A failure only delays the
+// change until the TTL expires, so it does not fail the write.
+func invalidateTransaction(ctx context.Context, cache ports.TransactionCache, partnerID, id uuid.UUID) {
+	ports.AfterCommit(ctx, func() {
+		_ = cache.Invalidate(context.WithoutCancel(ctx), partnerID, id)
+	})
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+// AdminUserRepository implements ports.AdminUserRepository in memory. TOTP
+// secrets are kept as given.
+type AdminUserRepository struct {
+	store *Store
+}
+
+// NewAdminUserRepository creates a new in-memory admin repository
+func NewAdminUserRepository(store *Store) *AdminUserRepository {
+	return &AdminUserRepository{store: store}
+}
+
+// Create creates a new admin
+func (r *AdminUserRepository) Create(ctx context.Context, admin *entities.AdminUser) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.adminUsers[admin.ID]; ok {
+		return fmt.Errorf("failed to create admin: admin %s already exists", admin.ID)
+	}
+	for _, existing := range r.store.data.adminUsers {
+		if existing.Email == admin.Email {
+			return errors.ErrAdminAlreadyExists
+		}
+	}
+
+	r.store.data.adminUsers[admin.ID] = cloneAdminUser(admin)
+	return nil
+}
+
+// GetByID retrieves an admin by ID
+func (r *AdminUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.AdminUser, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	admin, ok := r.store.data.adminUsers[id]
+	if !ok {
+		return nil, errors.ErrAdminNotFound
+	}
+	return cloneAdminUser(admin), nil
+}
+
+// GetByEmail retrieves an admin by email, which is stored lowercased
+func (r *AdminUserRepository) GetByEmail(ctx context.Context, email string) (*entities.AdminUser, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	email = strings.ToLower(email)
+	for _, admin := range r.store.data.adminUsers {
+		if admin.Email == email {
+			return cloneAdminUser(admin), nil
+		}
+	}
+	return nil, errors.ErrAdminNotFound
+}
+
+// RecordLogin stores the accepted TOTP step and login time, unless a
+// concurrent login already used that step or a later one
+func (r *AdminUserRepository) RecordLogin(ctx context.Context, admin *entities.AdminUser) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.adminUsers[admin.ID]
+	if !ok || current.TOTPLastStep >= admin.TOTPLastStep {
+		return errors.ErrInvalidAdminCredentials
+	}
+
+	updated := cloneAdminUser(current)
+	updated.TOTPLastStep = admin.TOTPLastStep
+	updated.LastLoginAt = cloneTime(admin.LastLoginAt)
+	r.store.data.adminUsers[admin.ID] = updated
+	return nil
+}
+
+// AdminSessionRepository implements ports.AdminSessionRepository in memory
+type AdminSessionRepository struct {
+	store *Store
+}
+
+// NewAdminSessionRepository creates a new in-memory admin session repository
+func NewAdminSessionRepository(store *Store) *AdminSessionRepository {
+	return &AdminSessionRepository{store: store}
+}
+
+// Create creates a new session
+func (r *AdminSessionRepository) Create(ctx context.Context, session *entities.AdminSession) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.data.adminSessions {
+		if existing.ID == session.ID || existing.TokenHash == session.TokenHash {
+			return fmt.Errorf("failed to create admin session: session already exists")
+		}
+	}
+
+	r.store.data.adminSessions[session.ID] = cloneAdminSession(session)
+	return nil
+}
+
+// GetByTokenHash retrieves a session by the hash of its token
+func (r *AdminSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.AdminSession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, session := range r.store.data.adminSessions {
+		if session.TokenHash == tokenHash {
+			return cloneAdminSession(session), nil
+		}
+	}
+	return nil, errors.ErrSessionNotFound
+}
+
+// Update records that a session was revoked
+func (r *AdminSessionRepository) Update(ctx context.Context, session *entities.AdminSession) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if current, ok := r.store.data.adminSessions[session.ID]; ok {
+		updated := cloneAdminSession(current)
+		updated.RevokedAt = cloneTime(session.RevokedAt)
+		r.store.data.adminSessions[session.ID] = updated
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// analyticsKey identifies an analytics rollup
+type analyticsKey struct {
+	partnerID     uuid.UUID
+	day           time.Time
+	livemode      bool
+	currency      string
+	provider      string
+	paymentMethod string
+}
+
+// AnalyticsRepository implements ports.AnalyticsRepository in memory
+type AnalyticsRepository struct {
+	store *Store
+}
+
+// NewAnalyticsRepository creates a new in-memory analytics repository
+func NewAnalyticsRepository(store *Store) *AnalyticsRepository {
+	return &AnalyticsRepository{store: store}
+}
+
+// ReplaceRollups replaces partnerID's rollups of the days with rollups
+func (r *AnalyticsRepository) ReplaceRollups(ctx context.Context, partnerID uuid.UUID, from, to time.Time, rollups []ports.AnalyticsRollup) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	from, to = ports.UsageDay(from), ports.UsageDay(to)
+	for key := range r.store.data.analytics {
+		if key.partnerID == partnerID && !key.day.Before(from) && !key.day.After(to) {
+			delete(r.store.data.analytics, key)
+		}
+	}
+	for _, rollup := range rollups {
+		rollup.PartnerID = partnerID
+		rollup.Day = ports.UsageDay(rollup.Day)
+		key := analyticsKey{
+			partnerID:     partnerID,
+			day:           rollup.Day,
+			livemode:      rollup.Livemode,
+			currency:      rollup.Currency,
+			provider:      rollup.Provider,
+			paymentMethod: rollup.PaymentMethod,
+		}
+		r.store.data.analytics[key] = rollup
+	}
+	return nil
+}
+
+// ListRollups returns the rollups matching q
+func (r *AnalyticsRepository) ListRollups(ctx context.Context, q ports.AnalyticsQuery) ([]ports.AnalyticsRollup, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	from, to := ports.UsageDay(q.From), ports.UsageDay(q.To)
+	var rollups []ports.AnalyticsRollup
+	for _, rollup := range r.store.data.analytics {
+		if rollup.PartnerID != q.PartnerID || rollup.Livemode != q.Livemode {
+			continue
+		}
+		if rollup.Day.Before(from) || rollup.Day.After(to) {
+			continue
+		}
+		if q.Currency != "" && rollup.Currency != q.Currency {
+			continue
+		}
+		rollups = append(rollups, rollup)
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		a, b := rollups[i], rollups[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		return a.PaymentMethod < b.PaymentMethod
+	})
+	return rollups, nil
+}
+
+// LatestDay returns the latest day rolled up
+func (r *AnalyticsRepository) LatestDay(ctx context.Context) (time.Time, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var latest time.Time
+	for key := range r.store.data.analytics {
+		if key.day.After(latest) {
+			latest = key.day
+		}
+	}
+	return latest, !latest.IsZero(), nil
+}
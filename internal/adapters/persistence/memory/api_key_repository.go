@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// APIKeyRepository implements ports.APIKeyRepository in memory
+type APIKeyRepository struct {
+	store *Store
+}
+
+// NewAPIKeyRepository creates a new in-memory API key repository
+func NewAPIKeyRepository(store *Store) *APIKeyRepository {
+	return &APIKeyRepository{store: store}
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.data.apiKeys {
+		if existing.ID == key.ID || existing.KeyHash == key.KeyHash || existing.KeyPrefix == key.KeyPrefix {
+			return fmt.Errorf("failed to create API key: key %s already exists", key.KeyPrefix)
+		}
+	}
+
+	r.store.data.apiKeys[key.ID] = cloneAPIKey(key)
+	return nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error) {
+	return r.getOne(func(k *entities.APIKey) bool { return k.ID == id })
+}
+
+// GetByPrefix retrieves an API key by its prefix
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	return r.getOne(func(k *entities.APIKey) bool { return k.KeyPrefix == prefix })
+}
+
+// ListByPartnerID retrieves all API keys of a partner, newest first
+func (r *APIKeyRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.APIKey, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var keys []*entities.APIKey
+	for _, key := range r.store.data.apiKeys {
+		if key.PartnerID == partnerID {
+			keys = append(keys, cloneAPIKey(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// Update updates an existing API key
+func (r *APIKeyRepository) Update(ctx context.Context, key *entities.APIKey) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.apiKeys[key.ID]
+	if !ok {
+		return nil
+	}
+
+	updated := cloneAPIKey(current)
+	updated.Label = key.Label
+	updated.Scopes = append([]valueobjects.APIKeyScope{}, key.Scopes...)
+	updated.KeyHash = key.KeyHash
+	updated.UpdatedAt = key.UpdatedAt
+	updated.RevokedAt = cloneTime(key.RevokedAt)
+	r.store.data.apiKeys[key.ID] = updated
+	return nil
+}
+
+// RecordUsage stores when and from where a key was last used, ignoring
+// usage older than what is already recorded
+func (r *APIKeyRepository) RecordUsage(ctx context.Context, id uuid.UUID, usedAt time.Time, ipAddress string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key, ok := r.store.data.apiKeys[id]
+	if !ok || (key.LastUsedAt != nil && !key.LastUsedAt.Before(usedAt)) {
+		return nil
+	}
+
+	used := cloneAPIKey(key)
+	used.LastUsedAt = &usedAt
+	used.LastUsedIP = ipAddress
+	r.store.data.apiKeys[id] = used
+	return nil
+}
+
+// getOne returns the key match accepts, revoked or not
+func (r *APIKeyRepository) getOne(match func(k *entities.APIKey) bool) (*entities.APIKey, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, key := range r.store.data.apiKeys {
+		if match(key) {
+			return cloneAPIKey(key), nil
+		}
+	}
+	return nil, errors.ErrAPIKeyNotFound
+}
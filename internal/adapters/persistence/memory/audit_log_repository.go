@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// AuditLogRepository implements ports.AuditLogRepository in memory
+type AuditLogRepository struct {
+	store *Store
+}
+
+// NewAuditLogRepository creates a new in-memory audit log repository
+func NewAuditLogRepository(store *Store) *AuditLogRepository {
+	return &AuditLogRepository{store: store}
+}
+
+// LogAction appends an audit event to its partner's hash chain, numbering
+// entries from 1. A missing IP address is stored as 0.0.0.0, as in the SQL
+// repositories.
+func (r *AuditLogRepository) LogAction(ctx context.Context, action ports.AuditAction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if action.IPAddress == "" {
+		action.IPAddress = "0.0.0.0"
+	}
+	entry := &ports.AuditLogEntry{
+		ID:          1,
+		AuditAction: action,
+		CreatedAt:   ports.AuditChainTime(action.Time()),
+		PrevHash:    ports.AuditChainGenesis(action.PartnerID),
+	}
+	entry.Changes = cloneJSONMap(action.Changes)
+
+	logs := r.store.data.auditLogs
+	if n := len(logs); n > 0 {
+		entry.ID = logs[n-1].ID + 1
+	}
+	for i := len(logs) - 1; i >= 0; i-- {
+		if logs[i].PartnerID == entry.PartnerID {
+			entry.PrevHash = logs[i].Hash
+			break
+		}
+	}
+
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return fmt.Errorf("failed to log audit action: %w", err)
+	}
+	entry.Hash = hash
+
+	r.store.data.auditLogs = append(logs, entry)
+	return nil
+}
+
+// List retrieves the entries matching q, newest first
+func (r *AuditLogRepository) List(ctx context.Context, q ports.AuditLogQuery) ([]*ports.AuditLogEntry, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Entries are stored oldest first
+	var matching []*ports.AuditLogEntry
+	for i := len(r.store.data.auditLogs) - 1; i >= 0; i-- {
+		if entry := r.store.data.auditLogs[i]; matchesAuditLogQuery(entry, q) {
+			matching = append(matching, entry)
+		}
+	}
+
+	start, end := page(len(matching), q.Limit, q.Offset)
+	var entries []*ports.AuditLogEntry
+	for _, entry := range matching[start:end] {
+		entries = append(entries, cloneAuditLogEntry(entry))
+	}
+	return entries, int64(len(matching)), nil
+}
+
+// ListChain retrieves entries of a partner's chain, oldest first
+func (r *AuditLogRepository) ListChain(ctx context.Context, partnerID uuid.UUID, afterID int64, limit int) ([]*ports.AuditLogEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var entries []*ports.AuditLogEntry
+	for _, entry := range r.store.data.auditLogs {
+		if len(entries) == limit {
+			break
+		}
+		if entry.PartnerID == partnerID && entry.ID > afterID {
+			entries = append(entries, cloneAuditLogEntry(entry))
+		}
+	}
+	return entries, nil
+}
+
+// ListChainPartnerIDs returns the partners that have audit entries
+func (r *AuditLogRepository) ListChainPartnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seen := make(map[uuid.UUID]bool)
+	var partnerIDs []uuid.UUID
+	for _, entry := range r.store.data.auditLogs {
+		if !seen[entry.PartnerID] {
+			seen[entry.PartnerID] = true
+			partnerIDs = append(partnerIDs, entry.PartnerID)
+		}
+	}
+	sort.Slice(partnerIDs, func(i, j int) bool {
+		return compareUUID(partnerIDs[i], partnerIDs[j]) < 0
+	})
+	return partnerIDs, nil
+}
+
+// ListArchivable retrieves entries logged before before, lowest ID first,
+// leaving out the latest entry of every chain
+func (r *AuditLogRepository) ListArchivable(ctx context.Context, before time.Time, limit int) ([]*ports.AuditLogEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	latest := make(map[uuid.UUID]int64)
+	for _, entry := range r.store.data.auditLogs {
+		latest[entry.PartnerID] = entry.ID
+	}
+
+	var entries []*ports.AuditLogEntry
+	for _, entry := range r.store.data.auditLogs {
+		if len(entries) == limit {
+			break
+		}
+		if entry.CreatedAt.Before(before) && entry.ID != latest[entry.PartnerID] {
+			entries = append(entries, cloneAuditLogEntry(entry))
+		}
+	}
+	return entries, nil
+}
+
+// Delete removes entries by ID
+func (r *AuditLogRepository) Delete(ctx context.Context, ids []int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deleted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	var kept []*ports.AuditLogEntry
+	for _, entry := range r.store.data.auditLogs {
+		if !deleted[entry.ID] {
+			kept = append(kept, entry)
+		}
+	}
+	r.store.data.auditLogs = kept
+	return nil
+}
+
+// matchesAuditLogQuery applies the filters of q, without paging
+func matchesAuditLogQuery(entry *ports.AuditLogEntry, q ports.AuditLogQuery) bool {
+	if q.PartnerID != nil && entry.PartnerID != *q.PartnerID {
+		return false
+	}
+	if q.Action != "" && entry.Action != q.Action {
+		return false
+	}
+	if q.ResourceType != "" && entry.ResourceType != q.ResourceType {
+		return false
+	}
+	if q.ResourceID != nil && entry.ResourceID != *q.ResourceID {
+		return false
+	}
+	if q.CreatedFrom != nil && entry.CreatedAt.Before(*q.CreatedFrom) {
+		return false
+	}
+	if q.CreatedTo != nil && !entry.CreatedAt.Before(*q.CreatedTo) {
+		return false
+	}
+	return true
+}
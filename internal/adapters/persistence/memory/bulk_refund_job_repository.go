@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+// BulkRefundJobRepository implements ports.BulkRefundJobRepository in memory
+type BulkRefundJobRepository struct {
+	store *Store
+}
+
+// NewBulkRefundJobRepository creates a new in-memory bulk refund job repository
+func NewBulkRefundJobRepository(store *Store) *BulkRefundJobRepository {
+	return &BulkRefundJobRepository{store: store}
+}
+
+// Create creates a new bulk refund job
+func (r *BulkRefundJobRepository) Create(ctx context.Context, job *entities.BulkRefundJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.bulkRefundJobs[job.ID]; ok {
+		return fmt.Errorf("failed to create bulk refund job: job %s already exists", job.ID)
+	}
+
+	r.store.data.bulkRefundJobs[job.ID] = cloneBulkRefundJob(job)
+	return nil
+}
+
+// GetByID retrieves a bulk refund job by ID
+func (r *BulkRefundJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.BulkRefundJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.data.bulkRefundJobs[id]
+	if !ok {
+		return nil, errors.ErrBulkRefundJobNotFound
+	}
+	return cloneBulkRefundJob(job), nil
+}
+
+// ClaimDue claims up to limit unfinished jobs that are due, oldest first
+func (r *BulkRefundJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.BulkRefundJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var due []*entities.BulkRefundJob
+	for _, job := range r.store.data.bulkRefundJobs {
+		if !job.IsCompleted() && !job.NextAttemptAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	start, end := page(len(due), limit, 0)
+	var jobs []*entities.BulkRefundJob
+	for _, job := range due[start:end] {
+		claimed := cloneBulkRefundJob(job)
+		claimed.NextAttemptAt = claimedUntil
+		r.store.data.bulkRefundJobs[job.ID] = claimed
+		jobs = append(jobs, cloneBulkRefundJob(claimed))
+	}
+	return jobs, nil
+}
+
+// Update updates job progress and results
+func (r *BulkRefundJobRepository) Update(ctx context.Context, job *entities.BulkRefundJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.bulkRefundJobs[job.ID]
+	if !ok {
+		return nil
+	}
+
+	progress := cloneBulkRefundJob(job)
+	updated := cloneBulkRefundJob(current)
+	updated.Status = job.Status
+	updated.Results = progress.Results
+	updated.Succeeded = job.Succeeded
+	updated.Failed = job.Failed
+	updated.UpdatedAt = job.UpdatedAt
+	updated.NextAttemptAt = job.NextAttemptAt
+	updated.CompletedAt = progress.CompletedAt
+	r.store.data.bulkRefundJobs[job.ID] = updated
+	return nil
+}
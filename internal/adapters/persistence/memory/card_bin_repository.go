@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// CardBINRepository implements ports.CardBINRepository in memory
+type CardBINRepository struct {
+	store *Store
+}
+
+// NewCardBINRepository creates a new in-memory card BIN repository and adds
+// bins to the store's BIN table. The SQL tables are seeded by the migrations;
+// here the caller supplies the ranges it needs.
+func NewCardBINRepository(store *Store, bins ...valueobjects.BINInfo) *CardBINRepository {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, info := range bins {
+		store.data.cardBINs[info.Prefix] = info
+	}
+	return &CardBINRepository{store: store}
+}
+
+// Lookup returns the longest BIN range matching bin
+func (r *CardBINRepository) Lookup(ctx context.Context, bin valueobjects.BIN) (*valueobjects.BINInfo, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Ranges are 6 to 8 digit prefixes, so at most three candidates match
+	code := bin.String()
+	for length := 8; length >= 6; length-- {
+		if length > len(code) {
+			continue
+		}
+		if info, ok := r.store.data.cardBINs[valueobjects.BIN(code[:length])]; ok {
+			return &info, nil
+		}
+	}
+	return nil, errors.ErrCardBINNotFound
+}
@@ -0,0 +1,267 @@
+package memory
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// The clone functions copy entities deeply enough that the copy and the
+// original share nothing a caller could change.
+
+func cloneTransaction(t *entities.Transaction) *entities.Transaction {
+	c := *t
+	c.Metadata = cloneJSONMap(t.Metadata)
+	c.ProviderCredentialID = cloneUUID(t.ProviderCredentialID)
+	c.ProcessedAt = cloneTime(t.ProcessedAt)
+	c.FailedAt = cloneTime(t.FailedAt)
+	c.DeletedAt = cloneTime(t.DeletedAt)
+	c.PaymentMethodDetails = clonePaymentMethodDetails(t.PaymentMethodDetails)
+	return &c
+}
+
+func cloneRefund(r *entities.Refund) *entities.Refund {
+	c := *r
+	c.ApprovedAt = cloneTime(r.ApprovedAt)
+	c.ProcessedAt = cloneTime(r.ProcessedAt)
+	c.CancelledAt = cloneTime(r.CancelledAt)
+	c.DeletedAt = cloneTime(r.DeletedAt)
+	return &c
+}
+
+func clonePartner(p *entities.Partner) *entities.Partner {
+	c := *p
+	if p.Features != nil {
+		c.Features = make(map[valueobjects.PartnerFeature]bool, len(p.Features))
+		for k, v := range p.Features {
+			c.Features[k] = v
+		}
+	}
+	if p.AllowedCurrencies != nil {
+		c.AllowedCurrencies = append([]valueobjects.Currency{}, p.AllowedCurrencies...)
+	}
+	if p.AmountLimits != nil {
+		c.AmountLimits = make(valueobjects.AmountLimits, len(p.AmountLimits))
+		for k, v := range p.AmountLimits {
+			c.AmountLimits[k] = v
+		}
+	}
+	if p.RefundApprovalThresholds != nil {
+		c.RefundApprovalThresholds = make(valueobjects.AmountLimits, len(p.RefundApprovalThresholds))
+		for k, v := range p.RefundApprovalThresholds {
+			c.RefundApprovalThresholds[k] = v
+		}
+	}
+	if p.EventDestination != nil {
+		destination := *p.EventDestination
+		c.EventDestination = &destination
+	}
+	c.FieldFilter = p.FieldFilter.Clone()
+	if p.Pricing != nil {
+		pricing := p.Pricing.Clone()
+		c.Pricing = &pricing
+	}
+	if p.AccountMapping != nil {
+		mapping := *p.AccountMapping
+		c.AccountMapping = &mapping
+	}
+	c.Metadata = cloneJSONMap(p.Metadata)
+	c.AnonymizeAfter = cloneTime(p.AnonymizeAfter)
+	c.AnonymizedAt = cloneTime(p.AnonymizedAt)
+	c.DeletedAt = cloneTime(p.DeletedAt)
+	return &c
+}
+
+func cloneAPIKey(k *entities.APIKey) *entities.APIKey {
+	c := *k
+	if k.Scopes != nil {
+		c.Scopes = append([]valueobjects.APIKeyScope{}, k.Scopes...)
+	}
+	c.LastUsedAt = cloneTime(k.LastUsedAt)
+	c.RevokedAt = cloneTime(k.RevokedAt)
+	return &c
+}
+
+func cloneProviderCredential(p *entities.ProviderCredential) *entities.ProviderCredential {
+	c := *p
+	return &c
+}
+
+func cloneUser(u *entities.User) *entities.User {
+	c := *u
+	c.LastLoginAt = cloneTime(u.LastLoginAt)
+	c.DeletedAt = cloneTime(u.DeletedAt)
+	return &c
+}
+
+func cloneUserSession(s *entities.UserSession) *entities.UserSession {
+	c := *s
+	c.RevokedAt = cloneTime(s.RevokedAt)
+	return &c
+}
+
+func cloneAdminUser(a *entities.AdminUser) *entities.AdminUser {
+	c := *a
+	c.LastLoginAt = cloneTime(a.LastLoginAt)
+	c.DisabledAt = cloneTime(a.DisabledAt)
+	return &c
+}
+
+func cloneAdminSession(s *entities.AdminSession) *entities.AdminSession {
+	c := *s
+	c.RevokedAt = cloneTime(s.RevokedAt)
+	return &c
+}
+
+func cloneBulkRefundJob(j *entities.BulkRefundJob) *entities.BulkRefundJob {
+	c := *j
+	if j.TransactionIDs != nil {
+		c.TransactionIDs = append([]uuid.UUID{}, j.TransactionIDs...)
+	}
+	if j.Results != nil {
+		c.Results = make([]entities.BulkRefundItem, len(j.Results))
+		for i, item := range j.Results {
+			item.RefundID = cloneUUID(item.RefundID)
+			item.StartedAt = cloneTime(item.StartedAt)
+			c.Results[i] = item
+		}
+	}
+	c.CompletedAt = cloneTime(j.CompletedAt)
+	return &c
+}
+
+func cloneOutboxEvent(e *entities.OutboxEvent) *entities.OutboxEvent {
+	c := *e
+	c.Payload = append(json.RawMessage(nil), e.Payload...)
+	c.PublishedAt = cloneTime(e.PublishedAt)
+	return &c
+}
+
+func clonePaymentJob(j *entities.PaymentJob) *entities.PaymentJob {
+	c := *j
+	c.CompletedAt = cloneTime(j.CompletedAt)
+	return &c
+}
+
+func cloneExportJob(j *entities.ExportJob) *entities.ExportJob {
+	c := *j
+	c.Filters = cloneExportFilters(j.Filters)
+	if j.Layout.Columns != nil {
+		c.Layout.Columns = append([]string{}, j.Layout.Columns...)
+	}
+	c.CompletedAt = cloneTime(j.CompletedAt)
+	return &c
+}
+
+func cloneReportSchedule(s *entities.ReportSchedule) *entities.ReportSchedule {
+	c := *s
+	c.Filters = cloneExportFilters(s.Filters)
+	if s.Layout.Columns != nil {
+		c.Layout.Columns = append([]string{}, s.Layout.Columns...)
+	}
+	if s.Destination.Recipients != nil {
+		c.Destination.Recipients = append([]string{}, s.Destination.Recipients...)
+	}
+	c.LastRunAt = cloneTime(s.LastRunAt)
+	return &c
+}
+
+func cloneSettlementEntry(e *entities.SettlementEntry) *entities.SettlementEntry {
+	c := *e
+	c.TransactionID = cloneUUID(e.TransactionID)
+	c.PartnerID = cloneUUID(e.PartnerID)
+	return &c
+}
+
+func cloneDispute(d *entities.Dispute) *entities.Dispute {
+	c := *d
+	c.ReversedAt = cloneTime(d.ReversedAt)
+	return &c
+}
+
+func cloneStatement(s *entities.Statement) *entities.Statement {
+	c := *s
+	c.Pricing = s.Pricing.Clone()
+	if s.Currencies != nil {
+		c.Currencies = make([]entities.StatementCurrency, len(s.Currencies))
+		for i, line := range s.Currencies {
+			line.TierFees = append([]entities.StatementTierFee(nil), line.TierFees...)
+			c.Currencies[i] = line
+		}
+	}
+	return &c
+}
+
+func cloneAuditLogEntry(e *ports.AuditLogEntry) *ports.AuditLogEntry {
+	c := *e
+	c.Changes = cloneJSONMap(e.Changes)
+	return &c
+}
+
+func clonePaymentMethodDetails(d *valueobjects.PaymentMethodDetails) *valueobjects.PaymentMethodDetails {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	if d.Card != nil {
+		card := *d.Card
+		c.Card = &card
+	}
+	if d.BankAccount != nil {
+		account := *d.BankAccount
+		c.BankAccount = &account
+	}
+	if d.Wallet != nil {
+		wallet := *d.Wallet
+		c.Wallet = &wallet
+	}
+	return &c
+}
+
+// cloneJSONMap copies a JSON column's value through JSON, so numbers come
+// back as float64 and unknown types are dropped, just as from the database
+func cloneJSONMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	var c map[string]interface{}
+	json.Unmarshal(data, &c)
+	return c
+}
+
+// cloneExportFilters copies filters through JSON, as the SQL repositories
+// store them
+func cloneExportFilters(f entities.ExportFilters) entities.ExportFilters {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return entities.ExportFilters{}
+	}
+	var c entities.ExportFilters
+	json.Unmarshal(data, &c)
+	return c
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func cloneUUID(id *uuid.UUID) *uuid.UUID {
+	if id == nil {
+		return nil
+	}
+	c := *id
+	return &c
+}
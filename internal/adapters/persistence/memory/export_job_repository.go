@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+// ExportJobRepository implements ports.ExportJobRepository in memory
+type ExportJobRepository struct {
+	store *Store
+}
+
+// NewExportJobRepository creates a new in-memory export job repository
+func NewExportJobRepository(store *Store) *ExportJobRepository {
+	return &ExportJobRepository{store: store}
+}
+
+// Add queues a job
+func (r *ExportJobRepository) Add(ctx context.Context, job *entities.ExportJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.exportJobs[job.ID]; ok {
+		return fmt.Errorf("failed to add export job: job %s already exists", job.ID)
+	}
+
+	r.store.data.exportJobs[job.ID] = cloneExportJob(job)
+	return nil
+}
+
+// GetByID retrieves a job
+func (r *ExportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ExportJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.data.exportJobs[id]
+	if !ok {
+		return nil, errors.ErrExportNotFound
+	}
+	return cloneExportJob(job), nil
+}
+
+// ClaimDue claims up to limit pending jobs that are due, oldest first
+func (r *ExportJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.ExportJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var due []*entities.ExportJob
+	for _, job := range r.store.data.exportJobs {
+		if job.Status == entities.ExportJobStatusPending && !job.NextAttemptAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	start, end := page(len(due), limit, 0)
+	var jobs []*entities.ExportJob
+	for _, job := range due[start:end] {
+		claimed := cloneExportJob(job)
+		claimed.NextAttemptAt = claimedUntil
+		r.store.data.exportJobs[job.ID] = claimed
+		jobs = append(jobs, cloneExportJob(claimed))
+	}
+	return jobs, nil
+}
+
+// Update saves the outcome of an attempt
+func (r *ExportJobRepository) Update(ctx context.Context, job *entities.ExportJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.exportJobs[job.ID]
+	if !ok {
+		return nil
+	}
+
+	updated := cloneExportJob(current)
+	updated.Status = job.Status
+	updated.Attempts = job.Attempts
+	updated.NextAttemptAt = job.NextAttemptAt
+	updated.LastError = job.LastError
+	updated.ObjectKey = job.ObjectKey
+	updated.RowCount = job.RowCount
+	updated.CompletedAt = cloneTime(job.CompletedAt)
+	r.store.data.exportJobs[job.ID] = updated
+	return nil
+}
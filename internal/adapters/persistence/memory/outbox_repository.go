@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// OutboxRepository implements ports.OutboxRepository in memory. Events added
+// inside a unit of work are undone with it, as in the SQL outbox.
+type OutboxRepository struct {
+	store *Store
+}
+
+// NewOutboxRepository creates a new in-memory outbox repository
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+// Add records an event
+func (r *OutboxRepository) Add(ctx context.Context, event *entities.OutboxEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.outboxEvents[event.ID]; ok {
+		return fmt.Errorf("failed to add outbox event: event %s already exists", event.ID)
+	}
+
+	r.store.data.outboxEvents[event.ID] = cloneOutboxEvent(event)
+	return nil
+}
+
+// ClaimDue claims up to limit unpublished events of shards that are due,
+// oldest first
+func (r *OutboxRepository) ClaimDue(ctx context.Context, shards ports.OutboxShards, limit int, claimedUntil time.Time) ([]*entities.OutboxEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var due []*entities.OutboxEvent
+	for _, event := range r.store.data.outboxEvents {
+		if event.PublishedAt == nil && !event.NextAttemptAt.After(now) && event.Attempts < entities.MaxOutboxAttempts &&
+			shards.Includes(event.Shard) {
+			due = append(due, event)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	start, end := page(len(due), limit, 0)
+	var events []*entities.OutboxEvent
+	for _, event := range due[start:end] {
+		claimed := cloneOutboxEvent(event)
+		claimed.NextAttemptAt = claimedUntil
+		r.store.data.outboxEvents[event.ID] = claimed
+		events = append(events, cloneOutboxEvent(claimed))
+	}
+	return events, nil
+}
+
+// Update saves the outcome of a publish attempt
+func (r *OutboxRepository) Update(ctx context.Context, event *entities.OutboxEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.outboxEvents[event.ID]
+	if !ok {
+		return nil
+	}
+
+	updated := cloneOutboxEvent(current)
+	updated.Attempts = event.Attempts
+	updated.NextAttemptAt = event.NextAttemptAt
+	updated.LastError = event.LastError
+	updated.PublishedAt = cloneTime(event.PublishedAt)
+	r.store.data.outboxEvents[event.ID] = updated
+	return nil
+}
+
+// Backlog counts the unpublished events that have attempts left
+func (r *OutboxRepository) Backlog(ctx context.Context) (ports.OutboxBacklog, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var backlog ports.OutboxBacklog
+	for _, event := range r.store.data.outboxEvents {
+		if event.PublishedAt != nil || event.Attempts >= entities.MaxOutboxAttempts {
+			continue
+		}
+		backlog.Pending++
+		if backlog.OldestCreatedAt == nil || event.CreatedAt.Before(*backlog.OldestCreatedAt) {
+			backlog.OldestCreatedAt = cloneTime(&event.CreatedAt)
+		}
+	}
+	return backlog, nil
+}
+
+// ListPublishedBefore returns events published before before, oldest first
+func (r *OutboxRepository) ListPublishedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var published []*entities.OutboxEvent
+	for _, event := range r.store.data.outboxEvents {
+		if event.PublishedAt != nil && event.PublishedAt.Before(before) {
+			published = append(published, event)
+		}
+	}
+	sort.Slice(published, func(i, j int) bool {
+		return published[i].PublishedAt.Before(*published[j].PublishedAt)
+	})
+
+	start, end := page(len(published), limit, 0)
+	var events []*entities.OutboxEvent
+	for _, event := range published[start:end] {
+		events = append(events, cloneOutboxEvent(event))
+	}
+	return events, nil
+}
+
+// ListByAggregate returns up to limit events about one aggregate, oldest first
+func (r *OutboxRepository) ListByAggregate(ctx context.Context, aggregateType string, aggregateID uuid.UUID, limit int) ([]*entities.OutboxEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var matching []*entities.OutboxEvent
+	for _, event := range r.store.data.outboxEvents {
+		if event.AggregateType == aggregateType && event.AggregateID == aggregateID {
+			matching = append(matching, event)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].CreatedAt.Before(matching[j].CreatedAt)
+	})
+
+	start, end := page(len(matching), limit, 0)
+	var events []*entities.OutboxEvent
+	for _, event := range matching[start:end] {
+		events = append(events, cloneOutboxEvent(event))
+	}
+	return events, nil
+}
+
+// ListByPartner returns up to limit of a partner's events recorded after
+// the cursor and no later than until, oldest first
+func (r *OutboxRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, after ports.OutboxCursor, until time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var matching []*entities.OutboxEvent
+	for _, event := range r.store.data.outboxEvents {
+		if event.PartnerID != partnerID || event.CreatedAt.After(until) {
+			continue
+		}
+		if event.CreatedAt.After(after.CreatedAt) ||
+			(event.CreatedAt.Equal(after.CreatedAt) && event.ID.String() > after.ID.String()) {
+			matching = append(matching, event)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		if !matching[i].CreatedAt.Equal(matching[j].CreatedAt) {
+			return matching[i].CreatedAt.Before(matching[j].CreatedAt)
+		}
+		return matching[i].ID.String() < matching[j].ID.String()
+	})
+
+	start, end := page(len(matching), limit, 0)
+	var events []*entities.OutboxEvent
+	for _, event := range matching[start:end] {
+		events = append(events, cloneOutboxEvent(event))
+	}
+	return events, nil
+}
+
+// Delete removes events by ID
+func (r *OutboxRepository) Delete(ctx context.Context, ids []uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, id := range ids {
+		delete(r.store.data.outboxEvents, id)
+	}
+	return nil
+}
+
+// GetDeliverySummary counts, per partner, the webhook events recorded since since
+// that were published and those whose every attempt so far failed
+func (r *OutboxRepository) GetDeliverySummary(ctx context.Context, since time.Time) ([]ports.DeliverySummary, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	byPartner := make(map[uuid.UUID]*ports.DeliverySummary)
+	for _, event := range r.store.data.outboxEvents {
+		if event.CreatedAt.Before(since) || !entities.IsWebhookEvent(event.EventType) {
+			continue
+		}
+		summary, ok := byPartner[event.PartnerID]
+		if !ok {
+			summary = &ports.DeliverySummary{PartnerID: event.PartnerID}
+			byPartner[event.PartnerID] = summary
+		}
+		if event.PublishedAt != nil {
+			summary.Delivered++
+		} else if event.Attempts > 0 {
+			summary.Failing++
+		}
+	}
+
+	summaries := make([]ports.DeliverySummary, 0, len(byPartner))
+	for _, summary := range byPartner {
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+// DeleteByPartner removes all of a partner's events
+func (r *OutboxRepository) DeleteByPartner(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for id, event := range r.store.data.outboxEvents {
+		if event.PartnerID == partnerID {
+			delete(r.store.data.outboxEvents, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
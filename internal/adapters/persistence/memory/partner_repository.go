@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// PartnerRepository implements ports.PartnerRepository in memory. Webhook
+// secrets are kept as given; there is no storage to encrypt them for.
+type PartnerRepository struct {
+	store *Store
+}
+
+// NewPartnerRepository creates a new in-memory partner repository
+func NewPartnerRepository(store *Store) *PartnerRepository {
+	return &PartnerRepository{store: store}
+}
+
+// Create creates a new partner
+func (r *PartnerRepository) Create(ctx context.Context, partner *entities.Partner) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.data.partners {
+		switch {
+		case existing.ID == partner.ID:
+			return fmt.Errorf("failed to create partner: partner %s already exists", partner.ID)
+		case existing.Email == partner.Email:
+			return fmt.Errorf("failed to create partner: email %s is taken", partner.Email)
+		case existing.APIKeyHash == partner.APIKeyHash:
+			return fmt.Errorf("failed to create partner: API key hash is taken")
+		}
+	}
+
+	r.store.data.partners[partner.ID] = clonePartner(partner)
+	return nil
+}
+
+// GetByID retrieves a partner by ID
+func (r *PartnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Partner, error) {
+	return r.getOne(func(p *entities.Partner) bool { return p.ID == id })
+}
+
+// GetByEmail retrieves a partner by email
+func (r *PartnerRepository) GetByEmail(ctx context.Context, email string) (*entities.Partner, error) {
+	return r.getOne(func(p *entities.Partner) bool { return p.Email == email })
+}
+
+// GetByAPIKeyPrefix retrieves a partner by API key prefix
+func (r *PartnerRepository) GetByAPIKeyPrefix(ctx context.Context, prefix string) (*entities.Partner, error) {
+	return r.getOne(func(p *entities.Partner) bool { return p.APIKeyPrefix == prefix })
+}
+
+// getOne returns the partner, not off-boarded, that match accepts
+func (r *PartnerRepository) getOne(match func(p *entities.Partner) bool) (*entities.Partner, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, partner := range r.store.data.partners {
+		if partner.DeletedAt == nil && match(partner) {
+			return clonePartner(partner), nil
+		}
+	}
+	return nil, errors.ErrPartnerNotFound
+}
+
+// Update updates an existing partner if it is still at partner.Version, or
+// at the version ctx expects (see ports.ExpectVersion), and moves partner to
+// the next version. Metadata is set on creation only, as in the SQL
+// repositories.
+func (r *PartnerRepository) Update(ctx context.Context, partner *entities.Partner) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	version, expected := ports.ExpectedVersion(ctx)
+	if !expected {
+		version = partner.Version
+	}
+
+	current, ok := r.store.data.partners[partner.ID]
+	if !ok || current.Version != version || current.DeletedAt != nil {
+		if expected {
+			return errors.ErrPreconditionFailed
+		}
+		return &errors.VersionConflictError{Resource: "partner", ID: partner.ID.String(), Version: version}
+	}
+
+	updated := clonePartner(partner)
+	updated.APIKeyHash = current.APIKeyHash
+	updated.APIKeyPrefix = current.APIKeyPrefix
+	updated.Metadata = cloneJSONMap(current.Metadata)
+	updated.AnonymizedAt = cloneTime(current.AnonymizedAt)
+	updated.CreatedAt = current.CreatedAt
+	updated.Version = version + 1
+	r.store.data.partners[partner.ID] = updated
+	partner.Version = updated.Version
+	return nil
+}
+
+// List retrieves all partners with pagination
+func (r *PartnerRepository) List(ctx context.Context, limit, offset int) ([]*entities.Partner, error) {
+	partners, _ := r.list(limit, offset)
+	return partners, nil
+}
+
+// ListWithTotal retrieves a page of partners and the number of partners on
+// all pages
+func (r *PartnerRepository) ListWithTotal(ctx context.Context, limit, offset int) ([]*entities.Partner, int64, error) {
+	partners, total := r.list(limit, offset)
+	return partners, total, nil
+}
+
+// list returns a page of the partners not off-boarded, newest first, and
+// how many there are
+func (r *PartnerRepository) list(limit, offset int) ([]*entities.Partner, int64) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var active []*entities.Partner
+	for _, partner := range r.store.data.partners {
+		if partner.DeletedAt == nil {
+			active = append(active, partner)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if !active[i].CreatedAt.Equal(active[j].CreatedAt) {
+			return active[i].CreatedAt.After(active[j].CreatedAt)
+		}
+		return compareUUID(active[i].ID, active[j].ID) < 0
+	})
+
+	start, end := page(len(active), limit, offset)
+	var partners []*entities.Partner
+	for _, partner := range active[start:end] {
+		partners = append(partners, clonePartner(partner))
+	}
+	return partners, int64(len(active))
+}
+
+// ListDueForAnonymization returns off-boarded partners whose customer data is due for anonymization
+func (r *PartnerRepository) ListDueForAnonymization(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var due []*entities.Partner
+	for _, partner := range r.store.data.partners {
+		if partner.DeletedAt != nil && partner.AnonymizeAfter != nil &&
+			!partner.AnonymizeAfter.After(now) && partner.AnonymizedAt == nil {
+			due = append(due, partner)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].AnonymizeAfter.Before(*due[j].AnonymizeAfter)
+	})
+
+	var ids []uuid.UUID
+	for _, partner := range due {
+		ids = append(ids, partner.ID)
+	}
+	return ids, nil
+}
+
+// MarkAnonymized records that a partner's customer data was anonymized
+func (r *PartnerRepository) MarkAnonymized(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if partner, ok := r.store.data.partners[id]; ok {
+		marked := clonePartner(partner)
+		marked.AnonymizedAt = &at
+		r.store.data.partners[id] = marked
+	}
+	return nil
+}
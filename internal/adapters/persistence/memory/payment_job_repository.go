@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// PaymentJobRepository implements ports.PaymentJobRepository in memory
+type PaymentJobRepository struct {
+	store *Store
+}
+
+// NewPaymentJobRepository creates a new in-memory payment job repository
+func NewPaymentJobRepository(store *Store) *PaymentJobRepository {
+	return &PaymentJobRepository{store: store}
+}
+
+// Add queues a job
+func (r *PaymentJobRepository) Add(ctx context.Context, job *entities.PaymentJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.paymentJobs[job.ID]; ok {
+		return fmt.Errorf("failed to add payment job: job %s already exists", job.ID)
+	}
+
+	r.store.data.paymentJobs[job.ID] = clonePaymentJob(job)
+	return nil
+}
+
+// ClaimDue claims up to limit uncompleted jobs that are due, oldest first
+func (r *PaymentJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.PaymentJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var due []*entities.PaymentJob
+	for _, job := range r.store.data.paymentJobs {
+		if job.CompletedAt == nil && !job.NextAttemptAt.After(now) && job.Attempts < entities.MaxPaymentJobAttempts {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	start, end := page(len(due), limit, 0)
+	var jobs []*entities.PaymentJob
+	for _, job := range due[start:end] {
+		claimed := clonePaymentJob(job)
+		claimed.NextAttemptAt = claimedUntil
+		r.store.data.paymentJobs[job.ID] = claimed
+		jobs = append(jobs, clonePaymentJob(claimed))
+	}
+	return jobs, nil
+}
+
+// Update saves the outcome of an attempt
+func (r *PaymentJobRepository) Update(ctx context.Context, job *entities.PaymentJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.paymentJobs[job.ID]
+	if !ok {
+		return nil
+	}
+
+	updated := clonePaymentJob(current)
+	updated.Attempts = job.Attempts
+	updated.NextAttemptAt = job.NextAttemptAt
+	updated.LastError = job.LastError
+	updated.CompletedAt = cloneTime(job.CompletedAt)
+	r.store.data.paymentJobs[job.ID] = updated
+	return nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// ProviderCredentialRepository implements ports.ProviderCredentialRepository
+// in memory
+type ProviderCredentialRepository struct {
+	store *Store
+}
+
+// NewProviderCredentialRepository creates a new in-memory provider credential repository
+func NewProviderCredentialRepository(store *Store) *ProviderCredentialRepository {
+	return &ProviderCredentialRepository{store: store}
+}
+
+// Save creates the partner's credential for its provider, or replaces the
+// account and secret of the existing one
+func (r *ProviderCredentialRepository) Save(ctx context.Context, credential *entities.ProviderCredential) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if existing := r.find(credential.PartnerID, credential.Provider); existing != nil {
+		replaced := cloneProviderCredential(existing)
+		replaced.AccountID = credential.AccountID
+		replaced.SecretKey = credential.SecretKey
+		replaced.UpdatedAt = credential.UpdatedAt
+		r.store.data.providerCredentials[existing.ID] = replaced
+		return nil
+	}
+
+	r.store.data.providerCredentials[credential.ID] = cloneProviderCredential(credential)
+	return nil
+}
+
+// GetByPartnerAndProvider retrieves a partner's credential for a provider
+func (r *ProviderCredentialRepository) GetByPartnerAndProvider(
+	ctx context.Context,
+	partnerID uuid.UUID,
+	provider valueobjects.PaymentProvider,
+) (*entities.ProviderCredential, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	credential := r.find(partnerID, provider)
+	if credential == nil {
+		return nil, errors.ErrProviderCredentialNotFound
+	}
+	return cloneProviderCredential(credential), nil
+}
+
+// ListByPartnerID retrieves all credentials of a partner, by provider
+func (r *ProviderCredentialRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.ProviderCredential, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var credentials []*entities.ProviderCredential
+	for _, credential := range r.store.data.providerCredentials {
+		if credential.PartnerID == partnerID {
+			credentials = append(credentials, cloneProviderCredential(credential))
+		}
+	}
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].Provider.String() < credentials[j].Provider.String()
+	})
+	return credentials, nil
+}
+
+// Delete removes a partner's credential for a provider
+func (r *ProviderCredentialRepository) Delete(ctx context.Context, partnerID uuid.UUID, provider valueobjects.PaymentProvider) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	credential := r.find(partnerID, provider)
+	if credential == nil {
+		return errors.ErrProviderCredentialNotFound
+	}
+	delete(r.store.data.providerCredentials, credential.ID)
+	return nil
+}
+
+// find returns the stored credential of a partner for a provider, or nil;
+// the caller holds the store's lock
+func (r *ProviderCredentialRepository) find(partnerID uuid.UUID, provider valueobjects.PaymentProvider) *entities.ProviderCredential {
+	for _, credential := range r.store.data.providerCredentials {
+		if credential.PartnerID == partnerID && credential.Provider == provider {
+			return credential
+		}
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RefundRepository implements ports.RefundRepository in memory
+type RefundRepository struct {
+	store *Store
+}
+
+// NewRefundRepository creates a new in-memory refund repository
+func NewRefundRepository(store *Store) *RefundRepository {
+	return &RefundRepository{store: store}
+}
+
+// Create creates a new refund
+func (r *RefundRepository) Create(ctx context.Context, refund *entities.Refund) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.insert(refund)
+}
+
+// CreateBatch creates refunds, none of them if one already exists
+func (r *RefundRepository) CreateBatch(ctx context.Context, refunds []*entities.Refund) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, refund := range refunds {
+		if _, ok := r.store.data.refunds[refund.ID]; ok {
+			return fmt.Errorf("failed to create refunds: refund %s already exists", refund.ID)
+		}
+		for _, earlier := range refunds[:i] {
+			if earlier.ID == refund.ID {
+				return fmt.Errorf("failed to create refunds: refund %s is repeated", refund.ID)
+			}
+		}
+	}
+
+	for _, refund := range refunds {
+		if err := r.insert(refund); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reserve creates a refund and adds its amount to the transaction's refunded
+// total. Both happen under the store's lock, so concurrent refunds can never
+// exceed the original amount.
+func (r *RefundRepository) Reserve(ctx context.Context, refund *entities.Refund) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	txn, ok := r.store.data.transactions[refund.TransactionID]
+	if !ok || txn.DeletedAt != nil || txn.RefundedAmount.Amount+refund.Amount.Amount > txn.Amount.Amount {
+		return errors.ErrRefundAmountExceeded
+	}
+	if err := r.insert(refund); err != nil {
+		return err
+	}
+
+	reserved := cloneTransaction(txn)
+	reserved.RefundedAmount.Amount += refund.Amount.Amount
+	r.store.data.transactions[txn.ID] = reserved
+	return nil
+}
+
+// Release saves a refund that will never complete and returns its amount to
+// the transaction's refundable balance
+func (r *RefundRepository) Release(ctx context.Context, refund *entities.Refund) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.update(refund); err != nil {
+		return err
+	}
+
+	if txn, ok := r.store.data.transactions[refund.TransactionID]; ok {
+		released := cloneTransaction(txn)
+		released.RefundedAmount.Amount -= refund.Amount.Amount
+		if released.RefundedAmount.Amount < 0 {
+			released.RefundedAmount.Amount = 0
+		}
+		r.store.data.transactions[txn.ID] = released
+	}
+	return nil
+}
+
+// insert stores a new refund; the caller holds the store's lock
+func (r *RefundRepository) insert(refund *entities.Refund) error {
+	if _, ok := r.store.data.refunds[refund.ID]; ok {
+		return fmt.Errorf("failed to create refund: refund %s already exists", refund.ID)
+	}
+
+	stored := cloneRefund(refund)
+	stored.Version = 1
+	r.store.data.refunds[refund.ID] = stored
+	return nil
+}
+
+// GetByID retrieves a refund by ID
+func (r *RefundRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Refund, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	refund, ok := r.store.data.refunds[id]
+	if !ok || refund.DeletedAt != nil {
+		return nil, errors.ErrRefundNotFound
+	}
+	return cloneRefund(refund), nil
+}
+
+// GetByTransactionID retrieves all refunds for a transaction, newest first
+func (r *RefundRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var refunds []*entities.Refund
+	for _, refund := range r.store.data.refunds {
+		if refund.TransactionID == transactionID && refund.DeletedAt == nil {
+			refunds = append(refunds, cloneRefund(refund))
+		}
+	}
+	sort.Slice(refunds, func(i, j int) bool {
+		return refunds[i].CreatedAt.After(refunds[j].CreatedAt)
+	})
+	return refunds, nil
+}
+
+// Update updates an existing refund if it is still at refund.Version, and
+// moves refund to the next version. A refund changed since it was loaded is
+// left alone and a *errors.VersionConflictError returned.
+func (r *RefundRepository) Update(ctx context.Context, refund *entities.Refund) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.update(refund)
+}
+
+// update saves refund state; the caller holds the store's lock
+func (r *RefundRepository) update(refund *entities.Refund) error {
+	current, ok := r.store.data.refunds[refund.ID]
+	if !ok || current.Version != refund.Version || current.DeletedAt != nil {
+		return &errors.VersionConflictError{Resource: "refund", ID: refund.ID.String(), Version: refund.Version}
+	}
+
+	updated := cloneRefund(current)
+	updated.Status = refund.Status
+	updated.ProviderRefundID = refund.ProviderRefundID
+	updated.ErrorCode = refund.ErrorCode
+	updated.ErrorMessage = refund.ErrorMessage
+	updated.ApprovedBy = refund.ApprovedBy
+	updated.ApprovedAt = cloneTime(refund.ApprovedAt)
+	updated.UpdatedAt = refund.UpdatedAt
+	updated.ProcessedAt = cloneTime(refund.ProcessedAt)
+	updated.CancelledAt = cloneTime(refund.CancelledAt)
+	updated.Version++
+	r.store.data.refunds[refund.ID] = updated
+
+	refund.Version++
+	return nil
+}
+
+// Delete saves refund's DeletedAt if it is still at refund.Version, and moves
+// refund to the next version
+func (r *RefundRepository) Delete(ctx context.Context, refund *entities.Refund) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.refunds[refund.ID]
+	if !ok || current.Version != refund.Version || current.DeletedAt != nil {
+		return &errors.VersionConflictError{Resource: "refund", ID: refund.ID.String(), Version: refund.Version}
+	}
+
+	deleted := cloneRefund(current)
+	deleted.DeletedAt = cloneTime(refund.DeletedAt)
+	deleted.UpdatedAt = refund.UpdatedAt
+	deleted.Version++
+	r.store.data.refunds[refund.ID] = deleted
+
+	refund.Version++
+	return nil
+}
+
+// Restore clears the DeletedAt of a deleted refund
+func (r *RefundRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.refunds[id]
+	if !ok || current.DeletedAt == nil {
+		return errors.ErrRefundNotFound
+	}
+
+	restored := cloneRefund(current)
+	restored.DeletedAt = nil
+	restored.UpdatedAt = time.Now()
+	restored.Version++
+	r.store.data.refunds[id] = restored
+	return nil
+}
+
+// GetTotalRefundedAmount calculates total refunded amount for a transaction
+func (r *RefundRepository) GetTotalRefundedAmount(ctx context.Context, transactionID uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var total int64
+	for _, refund := range r.store.data.refunds {
+		if refund.TransactionID == transactionID && refund.Status == entities.RefundStatusCompleted && refund.DeletedAt == nil {
+			total += refund.Amount.Amount
+		}
+	}
+	return total, nil
+}
+
+// GetReasonSummary aggregates a partner's completed refunds by reason code
+func (r *RefundRepository) GetReasonSummary(ctx context.Context, filter ports.RefundReportFilter) ([]ports.RefundReasonSummary, error) {
+	// The dates are days in UTC; DateTo includes its whole day
+	var from, to *time.Time
+	if filter.DateFrom != nil {
+		day, err := time.Parse("2006-01-02", *filter.DateFrom)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize refunds: invalid date_from: %w", err)
+		}
+		from = &day
+	}
+	if filter.DateTo != nil {
+		day, err := time.Parse("2006-01-02", *filter.DateTo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize refunds: invalid date_to: %w", err)
+		}
+		day = day.AddDate(0, 0, 1)
+		to = &day
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	type group struct {
+		code     valueobjects.RefundReasonCode
+		currency valueobjects.Currency
+	}
+	groups := make(map[group]*ports.RefundReasonSummary)
+	for _, refund := range r.store.data.refunds {
+		if refund.Status != entities.RefundStatusCompleted || refund.DeletedAt != nil {
+			continue
+		}
+		txn, ok := r.store.data.transactions[refund.TransactionID]
+		if !ok || txn.DeletedAt != nil || txn.PartnerID != filter.PartnerID || txn.Livemode != filter.Livemode {
+			continue
+		}
+		if from != nil && refund.CreatedAt.Before(*from) {
+			continue
+		}
+		if to != nil && !refund.CreatedAt.Before(*to) {
+			continue
+		}
+
+		key := group{code: refund.Reason.Code, currency: refund.Amount.Currency}
+		summary, ok := groups[key]
+		if !ok {
+			summary = &ports.RefundReasonSummary{
+				ReasonCode: key.code,
+				Total:      valueobjects.Money{Currency: key.currency},
+			}
+			groups[key] = summary
+		}
+		summary.Count++
+		summary.Total.Amount += refund.Amount.Amount
+	}
+
+	var summaries []ports.RefundReasonSummary
+	for _, summary := range groups {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].ReasonCode != summaries[j].ReasonCode {
+			return summaries[i].ReasonCode < summaries[j].ReasonCode
+		}
+		return summaries[i].Total.Currency < summaries[j].Total.Currency
+	})
+	return summaries, nil
+}
+
+// List returns a partner's refunds matching query, newest first
+func (r *RefundRepository) List(ctx context.Context, query ports.RefundQuery) ([]*entities.Refund, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var matching []*entities.Refund
+	for _, refund := range r.store.data.refunds {
+		transaction := r.store.data.transactions[refund.TransactionID]
+		if refund.DeletedAt != nil || transaction == nil || transaction.DeletedAt != nil ||
+			transaction.PartnerID != query.PartnerID || transaction.Livemode != query.Livemode {
+			continue
+		}
+		if query.TransactionID != nil && refund.TransactionID != *query.TransactionID {
+			continue
+		}
+		if query.Status != nil && refund.Status != *query.Status {
+			continue
+		}
+		if query.CreatedFrom != nil && refund.CreatedAt.Before(*query.CreatedFrom) {
+			continue
+		}
+		if query.CreatedTo != nil && !refund.CreatedAt.Before(*query.CreatedTo) {
+			continue
+		}
+		matching = append(matching, refund)
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].CreatedAt.After(matching[j].CreatedAt)
+	})
+
+	start, end := page(len(matching), query.Limit, query.Offset)
+	refunds := make([]*entities.Refund, 0, end-start)
+	for _, refund := range matching[start:end] {
+		refunds = append(refunds, cloneRefund(refund))
+	}
+	return refunds, nil
+}
+
+// AnonymizeCustomerData clears the reason notes of all of a partner's refunds
+func (r *RefundRepository) AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var changed int64
+	for id, refund := range r.store.data.refunds {
+		transaction := r.store.data.transactions[refund.TransactionID]
+		if transaction == nil || transaction.PartnerID != partnerID || refund.Reason.Note == "" {
+			continue
+		}
+
+		anonymized := cloneRefund(refund)
+		anonymized.Reason.Note = ""
+		anonymized.Version++
+		anonymized.UpdatedAt = now
+		r.store.data.refunds[id] = anonymized
+		changed++
+	}
+	return changed, nil
+}
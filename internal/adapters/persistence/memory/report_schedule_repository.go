@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+// ReportScheduleRepository implements ports.ReportScheduleRepository in
+// memory. SFTP credentials are kept as they are; nothing is written to disk.
+type ReportScheduleRepository struct {
+	store *Store
+}
+
+// NewReportScheduleRepository creates a new in-memory report schedule repository
+func NewReportScheduleRepository(store *Store) *ReportScheduleRepository {
+	return &ReportScheduleRepository{store: store}
+}
+
+// Create stores a new schedule
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *entities.ReportSchedule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.reportSchedules[schedule.ID]; ok {
+		return fmt.Errorf("failed to create report schedule: schedule %s already exists", schedule.ID)
+	}
+
+	r.store.data.reportSchedules[schedule.ID] = cloneReportSchedule(schedule)
+	return nil
+}
+
+// GetByID retrieves a schedule
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ReportSchedule, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	schedule, ok := r.store.data.reportSchedules[id]
+	if !ok {
+		return nil, errors.ErrReportScheduleNotFound
+	}
+	return cloneReportSchedule(schedule), nil
+}
+
+// ListByPartner retrieves a partner's schedules in livemode, oldest first
+func (r *ReportScheduleRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, livemode bool) ([]*entities.ReportSchedule, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var schedules []*entities.ReportSchedule
+	for _, schedule := range r.store.data.reportSchedules {
+		if schedule.PartnerID == partnerID && schedule.Livemode == livemode {
+			schedules = append(schedules, cloneReportSchedule(schedule))
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		if !schedules[i].CreatedAt.Equal(schedules[j].CreatedAt) {
+			return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+		}
+		return schedules[i].ID.String() < schedules[j].ID.String()
+	})
+	return schedules, nil
+}
+
+// Update saves a schedule at its version
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *entities.ReportSchedule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.reportSchedules[schedule.ID]
+	if !ok || current.Version != schedule.Version {
+		return &errors.VersionConflictError{Resource: "report_schedule", ID: schedule.ID.String(), Version: schedule.Version}
+	}
+
+	updated := cloneReportSchedule(schedule)
+	updated.PartnerID = current.PartnerID
+	updated.Livemode = current.Livemode
+	updated.CreatedAt = current.CreatedAt
+	updated.Version = schedule.Version + 1
+	r.store.data.reportSchedules[schedule.ID] = updated
+	schedule.Version = updated.Version
+	return nil
+}
+
+// Delete removes a schedule
+func (r *ReportScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.reportSchedules[id]; !ok {
+		return errors.ErrReportScheduleNotFound
+	}
+	delete(r.store.data.reportSchedules, id)
+	return nil
+}
+
+// ClaimDue claims up to limit schedules whose next run is due, longest due
+// first
+func (r *ReportScheduleRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.ReportSchedule, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var due []*entities.ReportSchedule
+	for _, schedule := range r.store.data.reportSchedules {
+		if !schedule.NextRunAt.After(now) {
+			due = append(due, schedule)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextRunAt.Before(due[j].NextRunAt)
+	})
+
+	start, end := page(len(due), limit, 0)
+	var schedules []*entities.ReportSchedule
+	for _, schedule := range due[start:end] {
+		claimed := cloneReportSchedule(schedule)
+		claimed.NextRunAt = claimedUntil
+		claimed.Version++
+		r.store.data.reportSchedules[schedule.ID] = claimed
+		schedules = append(schedules, cloneReportSchedule(claimed))
+	}
+	return schedules, nil
+}
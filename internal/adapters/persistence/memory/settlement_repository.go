@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SettlementEntryRepository implements ports.SettlementEntryRepository in memory
+type SettlementEntryRepository struct {
+	store *Store
+}
+
+// NewSettlementEntryRepository creates a new in-memory settlement entry repository
+func NewSettlementEntryRepository(store *Store) *SettlementEntryRepository {
+	return &SettlementEntryRepository{store: store}
+}
+
+// Create stores a reconciled entry
+func (r *SettlementEntryRepository) Create(ctx context.Context, entry *entities.SettlementEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.data.settlementEntries {
+		if existing.ID == entry.ID || (existing.Provider == entry.Provider && existing.Reference == entry.Reference) {
+			return fmt.Errorf("failed to create settlement entry: %s entry %s already exists", entry.Provider, entry.Reference)
+		}
+	}
+
+	r.store.data.settlementEntries[entry.ID] = cloneSettlementEntry(entry)
+	return nil
+}
+
+// GetByReference retrieves the entry provider reported under reference
+func (r *SettlementEntryRepository) GetByReference(ctx context.Context, provider valueobjects.PaymentProvider, reference string) (*entities.SettlementEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, entry := range r.store.data.settlementEntries {
+		if entry.Provider == provider && entry.Reference == reference {
+			return cloneSettlementEntry(entry), nil
+		}
+	}
+	return nil, nil
+}
+
+// ListDiscrepancies retrieves the entries with a discrepancy matching query, newest first
+func (r *SettlementEntryRepository) ListDiscrepancies(ctx context.Context, query ports.SettlementDiscrepancyQuery) ([]*entities.SettlementEntry, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var matched []*entities.SettlementEntry
+	for _, entry := range r.store.data.settlementEntries {
+		if !entry.HasDiscrepancy() {
+			continue
+		}
+		if query.Provider != nil && entry.Provider != *query.Provider {
+			continue
+		}
+		if query.PartnerID != nil && (entry.PartnerID == nil || *entry.PartnerID != *query.PartnerID) {
+			continue
+		}
+		if query.ReconciledFrom != nil && entry.ReconciledAt.Before(*query.ReconciledFrom) {
+			continue
+		}
+		if query.ReconciledTo != nil && !entry.ReconciledAt.Before(*query.ReconciledTo) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].ReconciledAt.Equal(matched[j].ReconciledAt) {
+			return matched[i].ReconciledAt.After(matched[j].ReconciledAt)
+		}
+		return matched[i].ID.String() < matched[j].ID.String()
+	})
+
+	start, end := page(len(matched), query.Limit, query.Offset)
+	entries := make([]*entities.SettlementEntry, 0, end-start)
+	for _, entry := range matched[start:end] {
+		entries = append(entries, cloneSettlementEntry(entry))
+	}
+	return entries, int64(len(matched)), nil
+}
+
+// DisputeRepository implements ports.DisputeRepository in memory
+type DisputeRepository struct {
+	store *Store
+}
+
+// NewDisputeRepository creates a new in-memory dispute repository
+func NewDisputeRepository(store *Store) *DisputeRepository {
+	return &DisputeRepository{store: store}
+}
+
+// Create stores a new dispute
+func (r *DisputeRepository) Create(ctx context.Context, dispute *entities.Dispute) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.disputes[dispute.ID]; ok {
+		return fmt.Errorf("failed to create dispute: dispute %s already exists", dispute.ID)
+	}
+
+	r.store.data.disputes[dispute.ID] = cloneDispute(dispute)
+	return nil
+}
+
+// Update saves a dispute's status
+func (r *DisputeRepository) Update(ctx context.Context, dispute *entities.Dispute) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.disputes[dispute.ID]
+	if !ok {
+		return fmt.Errorf("failed to update dispute: dispute %s not found", dispute.ID)
+	}
+
+	updated := cloneDispute(current)
+	updated.Status = dispute.Status
+	updated.UpdatedAt = dispute.UpdatedAt
+	updated.ReversedAt = cloneTime(dispute.ReversedAt)
+	r.store.data.disputes[dispute.ID] = updated
+	return nil
+}
+
+// GetOpenByTransaction retrieves the oldest open dispute of a transaction
+func (r *DisputeRepository) GetOpenByTransaction(ctx context.Context, transactionID uuid.UUID) (*entities.Dispute, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var oldest *entities.Dispute
+	for _, dispute := range r.store.data.disputes {
+		if dispute.TransactionID != transactionID || dispute.Status != entities.DisputeStatusOpen {
+			continue
+		}
+		if oldest == nil || dispute.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = dispute
+		}
+	}
+	if oldest == nil {
+		return nil, nil
+	}
+	return cloneDispute(oldest), nil
+}
+
+// ListByPartner retrieves a partner's disputes, newest first
+func (r *DisputeRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Dispute, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var matched []*entities.Dispute
+	for _, dispute := range r.store.data.disputes {
+		if dispute.PartnerID == partnerID {
+			matched = append(matched, dispute)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].ID.String() < matched[j].ID.String()
+	})
+
+	start, end := page(len(matched), limit, offset)
+	disputes := make([]*entities.Dispute, 0, end-start)
+	for _, dispute := range matched[start:end] {
+		disputes = append(disputes, cloneDispute(dispute))
+	}
+	return disputes, int64(len(matched)), nil
+}
+
+// ListOpenedOrReversed retrieves a partner's disputes opened or reversed
+// from from (inclusive) to to (exclusive), oldest first
+func (r *DisputeRepository) ListOpenedOrReversed(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]*entities.Dispute, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	within := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+	var disputes []*entities.Dispute
+	for _, dispute := range r.store.data.disputes {
+		if dispute.PartnerID == partnerID && (within(dispute.CreatedAt) || (dispute.ReversedAt != nil && within(*dispute.ReversedAt))) {
+			disputes = append(disputes, cloneDispute(dispute))
+		}
+	}
+	sort.Slice(disputes, func(i, j int) bool {
+		if !disputes[i].CreatedAt.Equal(disputes[j].CreatedAt) {
+			return disputes[i].CreatedAt.Before(disputes[j].CreatedAt)
+		}
+		return disputes[i].ID.String() < disputes[j].ID.String()
+	})
+	return disputes, nil
+}
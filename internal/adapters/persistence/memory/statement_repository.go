@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+// StatementRepository implements ports.StatementRepository in memory
+type StatementRepository struct {
+	store *Store
+}
+
+// NewStatementRepository creates a new in-memory statement repository
+func NewStatementRepository(store *Store) *StatementRepository {
+	return &StatementRepository{store: store}
+}
+
+// Create saves a statement, failing with errors.ErrStatementExists when
+// the partner already has one for the period
+func (r *StatementRepository) Create(ctx context.Context, statement *entities.Statement) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.data.statements {
+		if existing.ID == statement.ID || (existing.PartnerID == statement.PartnerID && existing.Period == statement.Period) {
+			return errors.ErrStatementExists
+		}
+	}
+
+	r.store.data.statements[statement.ID] = cloneStatement(statement)
+	return nil
+}
+
+// GetByID retrieves a statement
+func (r *StatementRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Statement, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	statement, ok := r.store.data.statements[id]
+	if !ok {
+		return nil, errors.ErrStatementNotFound
+	}
+	return cloneStatement(statement), nil
+}
+
+// GetByPeriod retrieves a partner's statement of period
+func (r *StatementRepository) GetByPeriod(ctx context.Context, partnerID uuid.UUID, period entities.StatementPeriod) (*entities.Statement, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, statement := range r.store.data.statements {
+		if statement.PartnerID == partnerID && statement.Period == period {
+			return cloneStatement(statement), nil
+		}
+	}
+	return nil, errors.ErrStatementNotFound
+}
+
+// ListByPartner retrieves a partner's statements, latest period first
+func (r *StatementRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Statement, int64, error) {
+	return r.list(func(statement *entities.Statement) bool {
+		return statement.PartnerID == partnerID
+	}, func(a, b *entities.Statement) bool {
+		return a.Period > b.Period
+	}, limit, offset)
+}
+
+// ListByPeriod retrieves every partner's statement of period, by partner
+func (r *StatementRepository) ListByPeriod(ctx context.Context, period entities.StatementPeriod, limit, offset int) ([]*entities.Statement, int64, error) {
+	return r.list(func(statement *entities.Statement) bool {
+		return statement.Period == period
+	}, func(a, b *entities.Statement) bool {
+		return a.PartnerID.String() < b.PartnerID.String()
+	}, limit, offset)
+}
+
+// list returns a page of the statements matching match, ordered by less
+func (r *StatementRepository) list(match func(*entities.Statement) bool, less func(a, b *entities.Statement) bool, limit, offset int) ([]*entities.Statement, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var matched []*entities.Statement
+	for _, statement := range r.store.data.statements {
+		if match(statement) {
+			matched = append(matched, statement)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return less(matched[i], matched[j])
+	})
+
+	start, end := page(len(matched), limit, offset)
+	statements := make([]*entities.Statement, 0, end-start)
+	for _, statement := range matched[start:end] {
+		statements = append(statements, cloneStatement(statement))
+	}
+	return statements, int64(len(matched)), nil
+}
@@ -0,0 +1,241 @@
+// Package memory implements the repository interfaces in process memory, for
+// unit tests and for running the API without a database. It behaves like the
+// SQL repositories: entities are copied in and out, so callers never share
+// state with the store, and the same not-found, duplicate and version conflict
+// errors are returned. Nothing survives a restart.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Store holds the data of the memory repositories. Repositories created on
+// the same store see each other's writes, like repositories sharing a
+// database.
+type Store struct {
+	mu   sync.Mutex
+	data tables
+
+	// units serializes units of work, so a rollback only has to restore the
+	// snapshot taken when the unit of work began
+	units sync.Mutex
+}
+
+// tables are the store's data. Stored entities are never changed in place,
+// only replaced, so copying the maps is enough to snapshot them.
+type tables struct {
+	transactions        map[uuid.UUID]*entities.Transaction
+	refunds             map[uuid.UUID]*entities.Refund
+	partners            map[uuid.UUID]*entities.Partner
+	apiKeys             map[uuid.UUID]*entities.APIKey
+	providerCredentials map[uuid.UUID]*entities.ProviderCredential
+	users               map[uuid.UUID]*entities.User
+	userSessions        map[uuid.UUID]*entities.UserSession
+	adminUsers          map[uuid.UUID]*entities.AdminUser
+	adminSessions       map[uuid.UUID]*entities.AdminSession
+	bulkRefundJobs      map[uuid.UUID]*entities.BulkRefundJob
+	outboxEvents        map[uuid.UUID]*entities.OutboxEvent
+	paymentJobs         map[uuid.UUID]*entities.PaymentJob
+	exportJobs          map[uuid.UUID]*entities.ExportJob
+	settlementEntries   map[uuid.UUID]*entities.SettlementEntry
+	disputes            map[uuid.UUID]*entities.Dispute
+	statements          map[uuid.UUID]*entities.Statement
+	reportSchedules     map[uuid.UUID]*entities.ReportSchedule
+	cardBINs            map[valueobjects.BIN]valueobjects.BINInfo
+	auditLogs           []*ports.AuditLogEntry
+	usage               map[usageKey]ports.UsageRecord
+	analytics           map[analyticsKey]ports.AnalyticsRollup
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{data: tables{
+		transactions:        make(map[uuid.UUID]*entities.Transaction),
+		refunds:             make(map[uuid.UUID]*entities.Refund),
+		partners:            make(map[uuid.UUID]*entities.Partner),
+		apiKeys:             make(map[uuid.UUID]*entities.APIKey),
+		providerCredentials: make(map[uuid.UUID]*entities.ProviderCredential),
+		users:               make(map[uuid.UUID]*entities.User),
+		userSessions:        make(map[uuid.UUID]*entities.UserSession),
+		adminUsers:          make(map[uuid.UUID]*entities.AdminUser),
+		adminSessions:       make(map[uuid.UUID]*entities.AdminSession),
+		bulkRefundJobs:      make(map[uuid.UUID]*entities.BulkRefundJob),
+		outboxEvents:        make(map[uuid.UUID]*entities.OutboxEvent),
+		paymentJobs:         make(map[uuid.UUID]*entities.PaymentJob),
+		exportJobs:          make(map[uuid.UUID]*entities.ExportJob),
+		settlementEntries:   make(map[uuid.UUID]*entities.SettlementEntry),
+		disputes:            make(map[uuid.UUID]*entities.Dispute),
+		statements:          make(map[uuid.UUID]*entities.Statement),
+		reportSchedules:     make(map[uuid.UUID]*entities.ReportSchedule),
+		cardBINs:            make(map[valueobjects.BIN]valueobjects.BINInfo),
+		usage:               make(map[usageKey]ports.UsageRecord),
+		analytics:           make(map[analyticsKey]ports.AnalyticsRollup),
+	}}
+}
+
+// snapshot copies every table
+func (t tables) snapshot() tables {
+	s := tables{
+		transactions:        make(map[uuid.UUID]*entities.Transaction, len(t.transactions)),
+		refunds:             make(map[uuid.UUID]*entities.Refund, len(t.refunds)),
+		partners:            make(map[uuid.UUID]*entities.Partner, len(t.partners)),
+		apiKeys:             make(map[uuid.UUID]*entities.APIKey, len(t.apiKeys)),
+		providerCredentials: make(map[uuid.UUID]*entities.ProviderCredential, len(t.providerCredentials)),
+		users:               make(map[uuid.UUID]*entities.User, len(t.users)),
+		userSessions:        make(map[uuid.UUID]*entities.UserSession, len(t.userSessions)),
+		adminUsers:          make(map[uuid.UUID]*entities.AdminUser, len(t.adminUsers)),
+		adminSessions:       make(map[uuid.UUID]*entities.AdminSession, len(t.adminSessions)),
+		bulkRefundJobs:      make(map[uuid.UUID]*entities.BulkRefundJob, len(t.bulkRefundJobs)),
+		outboxEvents:        make(map[uuid.UUID]*entities.OutboxEvent, len(t.outboxEvents)),
+		paymentJobs:         make(map[uuid.UUID]*entities.PaymentJob, len(t.paymentJobs)),
+		exportJobs:          make(map[uuid.UUID]*entities.ExportJob, len(t.exportJobs)),
+		settlementEntries:   make(map[uuid.UUID]*entities.SettlementEntry, len(t.settlementEntries)),
+		disputes:            make(map[uuid.UUID]*entities.Dispute, len(t.disputes)),
+		statements:          make(map[uuid.UUID]*entities.Statement, len(t.statements)),
+		reportSchedules:     make(map[uuid.UUID]*entities.ReportSchedule, len(t.reportSchedules)),
+		cardBINs:            make(map[valueobjects.BIN]valueobjects.BINInfo, len(t.cardBINs)),
+		auditLogs:           append([]*ports.AuditLogEntry(nil), t.auditLogs...),
+		usage:               make(map[usageKey]ports.UsageRecord, len(t.usage)),
+		analytics:           make(map[analyticsKey]ports.AnalyticsRollup, len(t.analytics)),
+	}
+	for k, v := range t.transactions {
+		s.transactions[k] = v
+	}
+	for k, v := range t.refunds {
+		s.refunds[k] = v
+	}
+	for k, v := range t.partners {
+		s.partners[k] = v
+	}
+	for k, v := range t.apiKeys {
+		s.apiKeys[k] = v
+	}
+	for k, v := range t.providerCredentials {
+		s.providerCredentials[k] = v
+	}
+	for k, v := range t.users {
+		s.users[k] = v
+	}
+	for k, v := range t.userSessions {
+		s.userSessions[k] = v
+	}
+	for k, v := range t.adminUsers {
+		s.adminUsers[k] = v
+	}
+	for k, v := range t.adminSessions {
+		s.adminSessions[k] = v
+	}
+	for k, v := range t.bulkRefundJobs {
+		s.bulkRefundJobs[k] = v
+	}
+	for k, v := range t.outboxEvents {
+		s.outboxEvents[k] = v
+	}
+	for k, v := range t.paymentJobs {
+		s.paymentJobs[k] = v
+	}
+	for k, v := range t.exportJobs {
+		s.exportJobs[k] = v
+	}
+	for k, v := range t.settlementEntries {
+		s.settlementEntries[k] = v
+	}
+	for k, v := range t.disputes {
+		s.disputes[k] = v
+	}
+	for k, v := range t.statements {
+		s.statements[k] = v
+	}
+	for k, v := range t.reportSchedules {
+		s.reportSchedules[k] = v
+	}
+	for k, v := range t.cardBINs {
+		s.cardBINs[k] = v
+	}
+	for k, v := range t.usage {
+		s.usage[k] = v
+	}
+	for k, v := range t.analytics {
+		s.analytics[k] = v
+	}
+	return s
+}
+
+// unitKey is the context key marking a running unit of work
+type unitKey struct{}
+
+// UnitOfWork implements ports.UnitOfWork for a store
+type UnitOfWork struct {
+	store *Store
+}
+
+// NewUnitOfWork creates a new unit of work on store
+func NewUnitOfWork(store *Store) *UnitOfWork {
+	return &UnitOfWork{store: store}
+}
+
+// Do runs fn and undoes its writes if it returns an error or panics, or runs
+// the hooks it added with ports.AfterCommit if it does not. Units of
+// work run one at a time. Writes are visible to other callers before the unit
+// of work ends, and a rollback also undoes writes made meanwhile outside it,
+// which is fine for tests and a single-user demo but not for production.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if ctx.Value(unitKey{}) != nil {
+		return fn(ctx)
+	}
+
+	unitCtx, hooks := ports.WithAfterCommit(context.WithValue(ctx, unitKey{}, true))
+	committed := false
+	// Hooks run once the next unit of work may start, as they would after a
+	// database commit
+	defer func() {
+		if committed {
+			hooks.Run()
+		}
+	}()
+
+	s := u.store
+	s.units.Lock()
+	defer s.units.Unlock()
+
+	s.mu.Lock()
+	before := s.data.snapshot()
+	s.mu.Unlock()
+
+	defer func() {
+		if !committed {
+			s.mu.Lock()
+			s.data = before
+			s.mu.Unlock()
+		}
+	}()
+
+	if err := fn(unitCtx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// page returns the bounds of one page of n items, clamped to the items. A
+// limit of 0 selects nothing, as LIMIT 0 does.
+func page(n, limit, offset int) (start, end int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > n {
+		offset = n
+	}
+	end = n
+	if limit >= 0 && offset+limit < n {
+		end = offset + limit
+	}
+	return offset, end
+}
@@ -0,0 +1,464 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// TransactionRepository implements ports.TransactionRepository in memory
+type TransactionRepository struct {
+	store *Store
+}
+
+// NewTransactionRepository creates a new in-memory transaction repository
+func NewTransactionRepository(store *Store) *TransactionRepository {
+	return &TransactionRepository{store: store}
+}
+
+// Create creates a new transaction
+func (r *TransactionRepository) Create(ctx context.Context, txn *entities.Transaction) error {
+	return r.CreateBatch(ctx, []*entities.Transaction{txn})
+}
+
+// CreateBatch creates transactions, none of them if one is a duplicate
+func (r *TransactionRepository) CreateBatch(ctx context.Context, txns []*entities.Transaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, txn := range txns {
+		if _, ok := r.store.data.transactions[txn.ID]; ok {
+			return errors.ErrDuplicateTransaction
+		}
+		for _, existing := range r.store.data.transactions {
+			if existing.PartnerID == txn.PartnerID && existing.IdempotencyKey == txn.IdempotencyKey {
+				return errors.ErrDuplicateTransaction
+			}
+		}
+		for _, earlier := range txns[:i] {
+			if earlier.ID == txn.ID || (earlier.PartnerID == txn.PartnerID && earlier.IdempotencyKey == txn.IdempotencyKey) {
+				return errors.ErrDuplicateTransaction
+			}
+		}
+	}
+
+	for _, txn := range txns {
+		// The version and refunded total start from the column defaults
+		stored := cloneTransaction(txn)
+		stored.Version = 1
+		stored.RefundedAmount = valueobjects.Money{Currency: txn.Amount.Currency}
+		r.store.data.transactions[txn.ID] = stored
+	}
+	return nil
+}
+
+// GetByID retrieves a transaction by ID
+func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	txn, ok := r.store.data.transactions[id]
+	if !ok || txn.DeletedAt != nil {
+		return nil, errors.ErrTransactionNotFound
+	}
+	return cloneTransaction(txn), nil
+}
+
+// GetByIdempotencyKey retrieves a transaction by partner and idempotency key
+func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, partnerID uuid.UUID, idempotencyKey string) (*entities.Transaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, txn := range r.store.data.transactions {
+		if txn.PartnerID == partnerID && txn.IdempotencyKey == idempotencyKey && txn.DeletedAt == nil {
+			return cloneTransaction(txn), nil
+		}
+	}
+	return nil, nil // Not found, not an error
+}
+
+// Update updates an existing transaction if it is still at txn.Version, and
+// moves txn to the next version. A transaction changed since it was loaded is
+// left alone and a *errors.VersionConflictError returned.
+func (r *TransactionRepository) Update(ctx context.Context, txn *entities.Transaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.transactions[txn.ID]
+	if !ok || current.Version != txn.Version || current.DeletedAt != nil {
+		return &errors.VersionConflictError{Resource: "transaction", ID: txn.ID.String(), Version: txn.Version}
+	}
+
+	// Only the fields the SQL repositories update are saved
+	updated := cloneTransaction(current)
+	updated.Status = txn.Status
+	updated.ProviderTransactionID = txn.ProviderTransactionID
+	updated.ErrorCode = txn.ErrorCode
+	updated.ErrorMessage = txn.ErrorMessage
+	updated.RetryCount = txn.RetryCount
+	updated.UpdatedAt = txn.UpdatedAt
+	updated.ProcessedAt = cloneTime(txn.ProcessedAt)
+	updated.FailedAt = cloneTime(txn.FailedAt)
+	updated.ProviderCredentialID = cloneUUID(txn.ProviderCredentialID)
+	updated.PaymentMethodDetails = clonePaymentMethodDetails(txn.PaymentMethodDetails)
+	updated.Version++
+	r.store.data.transactions[txn.ID] = updated
+
+	txn.Version++
+	return nil
+}
+
+// Delete saves txn's DeletedAt if it is still at txn.Version, and moves txn
+// to the next version
+func (r *TransactionRepository) Delete(ctx context.Context, txn *entities.Transaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.transactions[txn.ID]
+	if !ok || current.Version != txn.Version || current.DeletedAt != nil {
+		return &errors.VersionConflictError{Resource: "transaction", ID: txn.ID.String(), Version: txn.Version}
+	}
+
+	deleted := cloneTransaction(current)
+	deleted.DeletedAt = cloneTime(txn.DeletedAt)
+	deleted.UpdatedAt = txn.UpdatedAt
+	deleted.Version++
+	r.store.data.transactions[txn.ID] = deleted
+
+	txn.Version++
+	return nil
+}
+
+// Restore clears the DeletedAt of a deleted transaction
+func (r *TransactionRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.transactions[id]
+	if !ok || current.DeletedAt == nil {
+		return errors.ErrTransactionNotFound
+	}
+
+	restored := cloneTransaction(current)
+	restored.DeletedAt = nil
+	restored.UpdatedAt = time.Now()
+	restored.Version++
+	r.store.data.transactions[id] = restored
+	return nil
+}
+
+// IncrementRetryCount moves a failed transaction with retries left back to
+// pending, counting the retry under the store's lock
+func (r *TransactionRepository) IncrementRetryCount(ctx context.Context, txn *entities.Transaction, maxRetries int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.transactions[txn.ID]
+	if !ok || current.DeletedAt != nil || current.Status != entities.StatusFailed || current.RetryCount >= maxRetries {
+		return errors.ErrRetryNotAllowed
+	}
+
+	updated := cloneTransaction(current)
+	updated.Status = entities.StatusPending
+	updated.RetryCount++
+	updated.UpdatedAt = time.Now()
+	updated.Version++
+	r.store.data.transactions[txn.ID] = updated
+
+	txn.Status = updated.Status
+	txn.RetryCount = updated.RetryCount
+	txn.UpdatedAt = updated.UpdatedAt
+	txn.Version = updated.Version
+	return nil
+}
+
+// AnonymizeCustomerData erases customer PII from all of a partner's transactions
+func (r *TransactionRepository) AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var changed int64
+	for id, txn := range r.store.data.transactions {
+		if txn.PartnerID != partnerID || txn.CustomerEmail == "anonymized@invalid" {
+			continue
+		}
+
+		anonymized := cloneTransaction(txn)
+		anonymized.CustomerEmail = "anonymized@invalid"
+		anonymized.CustomerName = ""
+		anonymized.CustomerPhone = ""
+		anonymized.ProviderCustomerID = ""
+		anonymized.Metadata = map[string]interface{}{}
+		anonymized.IPAddress = "0.0.0.0"
+		anonymized.UserAgent = ""
+		if details := anonymized.PaymentMethodDetails; details != nil {
+			anonymized.PaymentMethodDetails = details.Anonymized()
+		}
+		anonymized.Version++
+		anonymized.UpdatedAt = now
+		r.store.data.transactions[id] = anonymized
+		changed++
+	}
+	return changed, nil
+}
+
+// GetProcessingSummary counts the processed transactions of a mode created
+// since since by provider, partner, outcome and latency
+func (r *TransactionRepository) GetProcessingSummary(ctx context.Context, since time.Time, livemode bool) ([]ports.ProcessingSummary, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	type group struct {
+		provider  valueobjects.PaymentProvider
+		partnerID uuid.UUID
+		succeeded bool
+		bucket    int
+	}
+	counts := make(map[group]int64)
+	for _, txn := range r.store.data.transactions {
+		if txn.DeletedAt != nil || txn.Livemode != livemode || txn.CreatedAt.Before(since) {
+			continue
+		}
+		finishedAt := txn.ProcessedAt
+		if finishedAt == nil {
+			finishedAt = txn.FailedAt
+		}
+		if finishedAt == nil {
+			continue
+		}
+		counts[group{
+			provider:  txn.Provider,
+			partnerID: txn.PartnerID,
+			succeeded: txn.ProcessedAt != nil,
+			bucket:    ports.ProcessingLatencyBucket(finishedAt.Sub(txn.CreatedAt)),
+		}]++
+	}
+
+	summaries := make([]ports.ProcessingSummary, 0, len(counts))
+	for g, count := range counts {
+		summaries = append(summaries, ports.ProcessingSummary{
+			Provider:      g.provider,
+			PartnerID:     g.partnerID,
+			Succeeded:     g.succeeded,
+			LatencyBucket: g.bucket,
+			Count:         count,
+		})
+	}
+	return summaries, nil
+}
+
+// List retrieves the transactions matching q, one page at a time
+func (r *TransactionRepository) List(ctx context.Context, q ports.TransactionQuery) ([]*entities.Transaction, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	matching, total, err := r.query(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	start, end := page(len(matching), q.Limit, q.Offset)
+	var transactions []*entities.Transaction
+	for _, txn := range matching[start:end] {
+		transactions = append(transactions, cloneTransaction(txn))
+	}
+	return transactions, total, nil
+}
+
+// Stream calls fn with a copy of each transaction matching q, in its order.
+// The matching transactions are copied up front, so fn may use the store.
+func (r *TransactionRepository) Stream(ctx context.Context, q ports.TransactionQuery, fn func(*entities.Transaction) error) error {
+	r.store.mu.Lock()
+	matching, _, err := r.query(q)
+	transactions := make([]*entities.Transaction, len(matching))
+	for i, txn := range matching {
+		transactions[i] = cloneTransaction(txn)
+	}
+	r.store.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, txn := range transactions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// query returns the transactions matching q in its order, after its cursor
+// but not paged, and the total matching regardless of the cursor. The
+// caller holds the store's lock.
+func (r *TransactionRepository) query(q ports.TransactionQuery) ([]*entities.Transaction, int64, error) {
+	if (q.MinAmount != nil || q.MaxAmount != nil) && q.Currency == nil {
+		return nil, 0, fmt.Errorf("amount range needs a currency")
+	}
+	orderBy := q.OrderBy
+	if orderBy == "" {
+		orderBy = ports.TransactionOrderCreatedAt
+	}
+	if !orderBy.IsValid() {
+		return nil, 0, fmt.Errorf("cannot order transactions by %q", orderBy)
+	}
+
+	var matching []*entities.Transaction
+	for _, txn := range r.store.data.transactions {
+		if matchesTransactionQuery(txn, q) {
+			matching = append(matching, txn)
+		}
+	}
+	total := int64(len(matching))
+
+	// before reports whether a comes before b in the chosen order. The ID
+	// breaks ties, as in the SQL repositories.
+	before := func(a, b *entities.Transaction) bool {
+		var cmp int
+		if orderBy == ports.TransactionOrderAmount {
+			cmp = compareInt64(a.Amount.Amount, b.Amount.Amount)
+		} else {
+			cmp = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if cmp == 0 {
+			cmp = compareUUID(a.ID, b.ID)
+		}
+		if q.Ascending {
+			return cmp < 0
+		}
+		return cmp > 0
+	}
+
+	// The cursor must be one of the listed partner's transactions; an
+	// unknown cursor lists nothing
+	if q.After != nil {
+		cursor, ok := r.store.data.transactions[*q.After]
+		if !ok || (q.PartnerID != nil && cursor.PartnerID != *q.PartnerID) {
+			return nil, total, nil
+		}
+		after := matching[:0:0]
+		for _, txn := range matching {
+			if before(cursor, txn) {
+				after = append(after, txn)
+			}
+		}
+		matching = after
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return before(matching[i], matching[j])
+	})
+	return matching, total, nil
+}
+
+// matchesTransactionQuery applies the filters of q, without paging, ordering
+// or cursor
+func matchesTransactionQuery(txn *entities.Transaction, q ports.TransactionQuery) bool {
+	if txn.DeletedAt != nil {
+		return false
+	}
+	if q.PartnerID != nil && txn.PartnerID != *q.PartnerID {
+		return false
+	}
+	if q.Livemode != nil && txn.Livemode != *q.Livemode {
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, status := range q.Statuses {
+			if txn.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if q.Currency != nil && txn.Amount.Currency != *q.Currency {
+		return false
+	}
+	if q.MinAmount != nil && txn.Amount.Amount < *q.MinAmount {
+		return false
+	}
+	if q.MaxAmount != nil && txn.Amount.Amount > *q.MaxAmount {
+		return false
+	}
+
+	if q.CreatedFrom != nil && txn.CreatedAt.Before(*q.CreatedFrom) {
+		return false
+	}
+	if q.CreatedTo != nil && !txn.CreatedAt.Before(*q.CreatedTo) {
+		return false
+	}
+
+	if q.Provider != nil && txn.Provider != *q.Provider {
+		return false
+	}
+	if q.ProviderTransactionID != "" && txn.ProviderTransactionID != q.ProviderTransactionID {
+		return false
+	}
+
+	// Each pair must be a string member of metadata, as with containment on
+	// PostgreSQL
+	for key, value := range q.Metadata {
+		if s, ok := txn.Metadata[key].(string); !ok || s != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetByPartnerID retrieves transactions for a specific partner
+func (r *TransactionRepository) GetByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Transaction, error) {
+	q := ports.TransactionQuery{
+		PartnerID: &partnerID,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	txns, _, err := r.List(ctx, q)
+	return txns, err
+}
+
+// GetByStatus retrieves transactions by status
+func (r *TransactionRepository) GetByStatus(ctx context.Context, status entities.TransactionStatus, limit, offset int) ([]*entities.Transaction, error) {
+	q := ports.TransactionQuery{
+		Statuses: []entities.TransactionStatus{status},
+		Limit:    limit,
+		Offset:   offset,
+	}
+	txns, _, err := r.List(ctx, q)
+	return txns, err
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareUUID orders IDs as the databases do, byte by byte
+func compareUUID(a, b uuid.UUID) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return compareInt64(int64(a[i]), int64(b[i]))
+		}
+	}
+	return 0
+}
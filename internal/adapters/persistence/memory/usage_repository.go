@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// usageKey identifies a usage record
+type usageKey struct {
+	partnerID uuid.UUID
+	day       time.Time
+	currency  string
+}
+
+// UsageRepository implements ports.UsageRepository in memory
+type UsageRepository struct {
+	store *Store
+}
+
+// NewUsageRepository creates a new in-memory usage repository
+func NewUsageRepository(store *Store) *UsageRepository {
+	return &UsageRepository{store: store}
+}
+
+// AddUsage adds records to the stored counters
+func (r *UsageRepository) AddUsage(ctx context.Context, records []ports.UsageRecord) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, record := range records {
+		record.Day = ports.UsageDay(record.Day)
+		key := usageKey{partnerID: record.PartnerID, day: record.Day, currency: record.Currency}
+		stored, ok := r.store.data.usage[key]
+		if !ok {
+			r.store.data.usage[key] = record
+			continue
+		}
+		stored.APICalls += record.APICalls
+		stored.ClientErrors += record.ClientErrors
+		stored.ServerErrors += record.ServerErrors
+		stored.Payments += record.Payments
+		stored.FailedPayments += record.FailedPayments
+		stored.Volume += record.Volume
+		r.store.data.usage[key] = stored
+	}
+	return nil
+}
+
+// ListUsage returns the usage matching q
+func (r *UsageRepository) ListUsage(ctx context.Context, q ports.UsageQuery) ([]ports.UsageRecord, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	from, to := ports.UsageDay(q.From), ports.UsageDay(q.To)
+	var records []ports.UsageRecord
+	for _, record := range r.store.data.usage {
+		if record.Day.Before(from) || record.Day.After(to) {
+			continue
+		}
+		if q.PartnerID != nil && record.PartnerID != *q.PartnerID {
+			continue
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.PartnerID != b.PartnerID {
+			return a.PartnerID.String() < b.PartnerID.String()
+		}
+		return a.Currency < b.Currency
+	})
+	return records, nil
+}
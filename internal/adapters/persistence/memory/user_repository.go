@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// UserRepository implements ports.UserRepository in memory
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository creates a new in-memory user repository
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// Create creates a new user. Emails are unique among the users not deleted,
+// ignoring case.
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.data.users[user.ID]; ok {
+		return fmt.Errorf("failed to create user: user %s already exists", user.ID)
+	}
+	for _, existing := range r.store.data.users {
+		if existing.DeletedAt == nil && strings.EqualFold(existing.Email, user.Email) {
+			return errors.ErrUserAlreadyExists
+		}
+	}
+
+	r.store.data.users[user.ID] = cloneUser(user)
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	return r.getOne(func(u *entities.User) bool { return u.ID == id })
+}
+
+// GetByEmail retrieves a user by email, which is stored lowercased
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	email = strings.ToLower(email)
+	return r.getOne(func(u *entities.User) bool { return u.Email == email })
+}
+
+// ListByPartnerID retrieves a partner's team members, oldest first
+func (r *UserRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var users []*entities.User
+	for _, user := range r.store.data.users {
+		if user.PartnerID == partnerID && user.DeletedAt == nil {
+			users = append(users, cloneUser(user))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+	return users, nil
+}
+
+// CountByRole counts a partner's team members holding a role
+func (r *UserRepository) CountByRole(ctx context.Context, partnerID uuid.UUID, role valueobjects.UserRole) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	count := 0
+	for _, user := range r.store.data.users {
+		if user.PartnerID == partnerID && user.Role == role && user.DeletedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Update updates an existing user
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.data.users[user.ID]
+	if !ok || current.DeletedAt != nil {
+		return nil
+	}
+
+	updated := cloneUser(current)
+	updated.Name = user.Name
+	updated.Role = user.Role
+	updated.PasswordHash = user.PasswordHash
+	updated.UpdatedAt = user.UpdatedAt
+	updated.LastLoginAt = cloneTime(user.LastLoginAt)
+	updated.DeletedAt = cloneTime(user.DeletedAt)
+	r.store.data.users[user.ID] = updated
+	return nil
+}
+
+// getOne returns the user, not deleted, that match accepts
+func (r *UserRepository) getOne(match func(u *entities.User) bool) (*entities.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.data.users {
+		if user.DeletedAt == nil && match(user) {
+			return cloneUser(user), nil
+		}
+	}
+	return nil, errors.ErrUserNotFound
+}
+
+// UserSessionRepository implements ports.UserSessionRepository in memory
+type UserSessionRepository struct {
+	store *Store
+}
+
+// NewUserSessionRepository creates a new in-memory session repository
+func NewUserSessionRepository(store *Store) *UserSessionRepository {
+	return &UserSessionRepository{store: store}
+}
+
+// Create creates a new session
+func (r *UserSessionRepository) Create(ctx context.Context, session *entities.UserSession) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.data.userSessions {
+		if existing.ID == session.ID || existing.TokenHash == session.TokenHash {
+			return fmt.Errorf("failed to create session: session already exists")
+		}
+	}
+
+	r.store.data.userSessions[session.ID] = cloneUserSession(session)
+	return nil
+}
+
+// GetByTokenHash retrieves a session by the hash of its token
+func (r *UserSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.UserSession, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, session := range r.store.data.userSessions {
+		if session.TokenHash == tokenHash {
+			return cloneUserSession(session), nil
+		}
+	}
+	return nil, errors.ErrSessionNotFound
+}
+
+// Update records that a session was revoked
+func (r *UserSessionRepository) Update(ctx context.Context, session *entities.UserSession) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if current, ok := r.store.data.userSessions[session.ID]; ok {
+		updated := cloneUserSession(current)
+		updated.RevokedAt = cloneTime(session.RevokedAt)
+		r.store.data.userSessions[session.ID] = updated
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// AdminUserRepository implements ports.AdminUserRepository for MySQL.
+// TOTP secrets are encrypted with cipher before they are written.
+type AdminUserRepository struct {
+	db     *sql.DB
+	cipher ports.SecretCipher
+}
+
+// NewAdminUserRepository creates a new MySQL admin user repository
+func NewAdminUserRepository(db *sql.DB, cipher ports.SecretCipher) *AdminUserRepository {
+	return &AdminUserRepository{db: db, cipher: cipher}
+}
+
+// Create creates a new admin
+func (r *AdminUserRepository) Create(ctx context.Context, admin *entities.AdminUser) error {
+	query := `
+		INSERT INTO admin_users (
+			id, email, name, password_hash, totp_secret, totp_last_step,
+			created_at, updated_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+
+	totpSecret, err := r.cipher.Encrypt(admin.TOTPSecret, adminTOTPSecret(admin.ID))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		admin.ID,
+		admin.Email,
+		admin.Name,
+		admin.PasswordHash,
+		totpSecret,
+		admin.TOTPLastStep,
+		admin.CreatedAt,
+		admin.UpdatedAt,
+	)
+
+	if err != nil {
+		if isDuplicateKey(err) {
+			return errors.ErrAdminAlreadyExists
+		}
+		return fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an admin by ID
+func (r *AdminUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.AdminUser, error) {
+	return r.getOne(ctx, "id = ?", id)
+}
+
+// GetByEmail retrieves an admin by email
+func (r *AdminUserRepository) GetByEmail(ctx context.Context, email string) (*entities.AdminUser, error) {
+	return r.getOne(ctx, "email = LOWER(?)", email)
+}
+
+// RecordLogin stores the accepted TOTP step and login time. The step only moves
+// forward, so two logins racing with the same code cannot both succeed.
+func (r *AdminUserRepository) RecordLogin(ctx context.Context, admin *entities.AdminUser) error {
+	query := `
+		UPDATE admin_users SET
+			totp_last_step = ?,
+			last_login_at = ?
+		WHERE id = ? AND totp_last_step < ?
+	`
+
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		admin.TOTPLastStep, admin.LastLoginAt, admin.ID, admin.TOTPLastStep)
+	if err != nil {
+		return fmt.Errorf("failed to record admin login: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.ErrInvalidAdminCredentials
+	}
+
+	return nil
+}
+
+// RotateSecrets re-encrypts TOTP secrets with the current encryption key
+func (r *AdminUserRepository) RotateSecrets(ctx context.Context) (int, error) {
+	return rotateSecretColumn(ctx, r.db, r.cipher, "admin_users", "totp_secret", "id", "")
+}
+
+// getOne retrieves a single admin matching the condition
+func (r *AdminUserRepository) getOne(ctx context.Context, condition string, arg interface{}) (*entities.AdminUser, error) {
+	query := `
+		SELECT id, email, name, password_hash, totp_secret, totp_last_step,
+			   created_at, updated_at, last_login_at, disabled_at
+		FROM admin_users
+		WHERE ` + condition
+
+	admin, err := r.scanAdmin(sqldb.Conn(ctx, r.db).QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrAdminNotFound
+		}
+		return nil, fmt.Errorf("failed to get admin: %w", err)
+	}
+
+	return admin, nil
+}
+
+// scanAdmin reads an admin from a row, decrypting its TOTP secret
+func (r *AdminUserRepository) scanAdmin(row sqldb.RowScanner) (*entities.AdminUser, error) {
+	var admin entities.AdminUser
+
+	err := row.Scan(
+		&admin.ID,
+		&admin.Email,
+		&admin.Name,
+		&admin.PasswordHash,
+		&admin.TOTPSecret,
+		&admin.TOTPLastStep,
+		&admin.CreatedAt,
+		&admin.UpdatedAt,
+		&admin.LastLoginAt,
+		&admin.DisabledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	admin.TOTPSecret, err = r.cipher.Decrypt(admin.TOTPSecret, adminTOTPSecret(admin.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+
+	return &admin, nil
+}
+
+// AdminSessionRepository implements ports.AdminSessionRepository for MySQL
+type AdminSessionRepository struct {
+	db *sql.DB
+}
+
+// NewAdminSessionRepository creates a new MySQL admin session repository
+func NewAdminSessionRepository(db *sql.DB) *AdminSessionRepository {
+	return &AdminSessionRepository{db: db}
+}
+
+// Create creates a new session
+func (r *AdminSessionRepository) Create(ctx context.Context, session *entities.AdminSession) error {
+	query := `
+		INSERT INTO admin_sessions (id, admin_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		session.ID,
+		session.AdminID,
+		session.TokenHash,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create admin session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTokenHash retrieves a session by the hash of its token
+func (r *AdminSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.AdminSession, error) {
+	query := `
+		SELECT id, admin_id, token_hash, expires_at, created_at, revoked_at
+		FROM admin_sessions
+		WHERE token_hash = ?
+	`
+
+	var session entities.AdminSession
+	err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash).Scan(
+		&session.ID,
+		&session.AdminID,
+		&session.TokenHash,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get admin session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// Update updates an existing session
+func (r *AdminSessionRepository) Update(ctx context.Context, session *entities.AdminSession) error {
+	query := `UPDATE admin_sessions SET revoked_at = ? WHERE id = ?`
+
+	if _, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, session.RevokedAt, session.ID); err != nil {
+		return fmt.Errorf("failed to update admin session: %w", err)
+	}
+
+	return nil
+}
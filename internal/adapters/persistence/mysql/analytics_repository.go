@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// AnalyticsRepository implements ports.AnalyticsRepository for MySQL
+type AnalyticsRepository struct {
+	db *sql.DB
+	// replica serves reads in read-only contexts; nil reads from db
+	replica *sqldb.Replica
+}
+
+// NewAnalyticsRepository creates a new MySQL analytics repository
+func NewAnalyticsRepository(db *sql.DB, replica *sqldb.Replica) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db, replica: replica}
+}
+
+// ReplaceRollups deletes partnerID's rollups of the days and inserts rollups
+// in one database transaction
+func (r *AnalyticsRepository) ReplaceRollups(ctx context.Context, partnerID uuid.UUID, from, to time.Time, rollups []ports.AnalyticsRollup) error {
+	query := `
+		INSERT INTO payment_analytics_daily (
+			partner_id, day, livemode, currency, provider, payment_method,
+			succeeded_payments, failed_payments, volume, refunds, refunded_volume
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	return sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM payment_analytics_daily WHERE partner_id = ? AND day >= ? AND day <= ?`,
+			partnerID, ports.UsageDay(from), ports.UsageDay(to),
+		); err != nil {
+			return fmt.Errorf("failed to delete analytics rollups: %w", err)
+		}
+		for _, rollup := range rollups {
+			if _, err := tx.ExecContext(ctx, query,
+				partnerID,
+				ports.UsageDay(rollup.Day),
+				rollup.Livemode,
+				rollup.Currency,
+				rollup.Provider,
+				rollup.PaymentMethod,
+				rollup.SucceededPayments,
+				rollup.FailedPayments,
+				rollup.Volume,
+				rollup.Refunds,
+				rollup.RefundedVolume,
+			); err != nil {
+				return fmt.Errorf("failed to insert analytics rollup: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListRollups returns the rollups matching q
+func (r *AnalyticsRepository) ListRollups(ctx context.Context, q ports.AnalyticsQuery) ([]ports.AnalyticsRollup, error) {
+	query := `
+		SELECT partner_id, day, livemode, currency, provider, payment_method,
+			   succeeded_payments, failed_payments, volume, refunds, refunded_volume
+		FROM payment_analytics_daily
+		WHERE partner_id = ? AND livemode = ? AND day >= ? AND day <= ?`
+	args := []interface{}{q.PartnerID, q.Livemode, ports.UsageDay(q.From), ports.UsageDay(q.To)}
+	if q.Currency != "" {
+		query += ` AND currency = ?`
+		args = append(args, q.Currency)
+	}
+	query += ` ORDER BY day, currency, provider, payment_method`
+
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics rollups: %w", err)
+	}
+	defer rows.Close()
+
+	var rollups []ports.AnalyticsRollup
+	for rows.Next() {
+		var rollup ports.AnalyticsRollup
+		if err := rows.Scan(
+			&rollup.PartnerID,
+			&rollup.Day,
+			&rollup.Livemode,
+			&rollup.Currency,
+			&rollup.Provider,
+			&rollup.PaymentMethod,
+			&rollup.SucceededPayments,
+			&rollup.FailedPayments,
+			&rollup.Volume,
+			&rollup.Refunds,
+			&rollup.RefundedVolume,
+		); err != nil {
+			return nil, err
+		}
+		rollup.Day = rollup.Day.UTC()
+		rollups = append(rollups, rollup)
+	}
+	return rollups, rows.Err()
+}
+
+// LatestDay returns the latest day rolled up
+func (r *AnalyticsRepository) LatestDay(ctx context.Context) (time.Time, bool, error) {
+	var day time.Time
+	err := sqldb.ReadConn(ctx, r.db, r.replica).QueryRowContext(ctx,
+		`SELECT day FROM payment_analytics_daily ORDER BY day DESC LIMIT 1`,
+	).Scan(&day)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get latest analytics day: %w", err)
+	}
+	return day.UTC(), true, nil
+}
@@ -0,0 +1,243 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// APIKeyRepository implements ports.APIKeyRepository for MySQL.
+// HMAC signing secrets are encrypted with cipher before they are written.
+type APIKeyRepository struct {
+	db     *sql.DB
+	cipher ports.SecretCipher
+	stmts  *sqldb.Statements
+}
+
+// NewAPIKeyRepository creates a new MySQL API key repository
+func NewAPIKeyRepository(db *sql.DB, cipher ports.SecretCipher) *APIKeyRepository {
+	return &APIKeyRepository{db: db, cipher: cipher, stmts: sqldb.NewStatements(db)}
+}
+
+// PrepareStatements prepares the query of GetByPrefix, run to authenticate
+// every request
+func (r *APIKeyRepository) PrepareStatements(ctx context.Context) error {
+	return r.stmts.Prepare(ctx, apiKeyQuery("key_prefix = ?"))
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	query := `
+		INSERT INTO api_keys (
+			id, partner_id, label, key_hash, key_prefix, scopes,
+			auth_method, signing_secret, livemode, created_at, updated_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+
+	signingSecret, err := r.cipher.Encrypt(key.SigningSecret, apiKeySigningSecret(key.PartnerID, key.ID))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt signing secret: %w", err)
+	}
+
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		key.ID,
+		key.PartnerID,
+		key.Label,
+		key.KeyHash,
+		key.KeyPrefix,
+		scopesJSON(key.Scopes),
+		string(key.AuthMethod),
+		sql.NullString{String: signingSecret, Valid: signingSecret != ""},
+		key.Livemode,
+		key.CreatedAt,
+		key.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error) {
+	return r.getOne(ctx, "id = ?", id)
+}
+
+// GetByPrefix retrieves an API key by its 8-character prefix
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	return r.getOne(ctx, "key_prefix = ?", prefix)
+}
+
+// ListByPartnerID retrieves all API keys of a partner, including revoked ones
+func (r *APIKeyRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.APIKey, error) {
+	query := `
+		SELECT id, partner_id, label, key_hash, key_prefix, scopes,
+			   auth_method, signing_secret, livemode,
+			   last_used_at, last_used_ip,
+			   created_at, updated_at, revoked_at
+		FROM api_keys
+		WHERE partner_id = ?
+		ORDER BY created_at DESC
+	`
+
+	rows, err := sqldb.Conn(ctx, r.db).QueryContext(ctx, query, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*entities.APIKey
+	for rows.Next() {
+		key, err := r.scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Update updates an existing API key
+func (r *APIKeyRepository) Update(ctx context.Context, key *entities.APIKey) error {
+	query := `
+		UPDATE api_keys SET
+			label = ?,
+			scopes = ?,
+			key_hash = ?,
+			updated_at = ?,
+			revoked_at = ?
+		WHERE id = ?
+	`
+
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		key.Label,
+		scopesJSON(key.Scopes),
+		key.KeyHash,
+		key.UpdatedAt,
+		key.RevokedAt,
+		key.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return nil
+}
+
+// RecordUsage stores when and from where a key was last used
+func (r *APIKeyRepository) RecordUsage(ctx context.Context, id uuid.UUID, usedAt time.Time, ipAddress string) error {
+	query := `
+		UPDATE api_keys SET
+			last_used_at = ?,
+			last_used_ip = ?
+		WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?)
+	`
+
+	if _, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, usedAt, ipAddress, id, usedAt); err != nil {
+		return fmt.Errorf("failed to record API key usage: %w", err)
+	}
+
+	return nil
+}
+
+// RotateSecrets re-encrypts HMAC signing secrets with the current encryption key
+func (r *APIKeyRepository) RotateSecrets(ctx context.Context) (int, error) {
+	return rotateSecretColumn(ctx, r.db, r.cipher, "api_keys", "signing_secret", "partner_id", "id")
+}
+
+// apiKeyQuery selects the API key matching condition
+func apiKeyQuery(condition string) string {
+	return `
+		SELECT id, partner_id, label, key_hash, key_prefix, scopes,
+			   auth_method, signing_secret, livemode,
+			   last_used_at, last_used_ip,
+			   created_at, updated_at, revoked_at
+		FROM api_keys
+		WHERE ` + condition
+}
+
+// getOne retrieves a single API key matching the condition
+func (r *APIKeyRepository) getOne(ctx context.Context, condition string, arg interface{}) (*entities.APIKey, error) {
+
+	key, err := r.scanAPIKey(r.stmts.Conn(ctx).QueryRowContext(ctx, apiKeyQuery(condition), arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// scanAPIKey reads an API key from a row, decrypting its signing secret
+func (r *APIKeyRepository) scanAPIKey(row sqldb.RowScanner) (*entities.APIKey, error) {
+	var key entities.APIKey
+	var scopesJSON []byte
+	var scopes []string
+	var authMethod string
+	var signingSecret sql.NullString
+	var lastUsedIP sql.NullString
+
+	err := row.Scan(
+		&key.ID,
+		&key.PartnerID,
+		&key.Label,
+		&key.KeyHash,
+		&key.KeyPrefix,
+		&scopesJSON,
+		&authMethod,
+		&signingSecret,
+		&key.Livemode,
+		&key.LastUsedAt,
+		&lastUsedIP,
+		&key.CreatedAt,
+		&key.UpdatedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(scopesJSON, &scopes); err != nil {
+		return nil, fmt.Errorf("failed to decode API key scopes: %w", err)
+	}
+	key.AuthMethod = entities.APIKeyAuthMethod(authMethod)
+	key.SigningSecret, err = r.cipher.Decrypt(signingSecret.String, apiKeySigningSecret(key.PartnerID, key.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signing secret: %w", err)
+	}
+	key.LastUsedIP = lastUsedIP.String
+	key.Scopes = make([]valueobjects.APIKeyScope, len(scopes))
+	for i, scope := range scopes {
+		key.Scopes[i] = valueobjects.APIKeyScope(scope)
+	}
+
+	return &key, nil
+}
+
+// scopesJSON encodes scopes for the JSON array column
+func scopesJSON(scopes []valueobjects.APIKeyScope) string {
+	result := make([]string, len(scopes))
+	for i, scope := range scopes {
+		result[i] = scope.String()
+	}
+	encoded, _ := json.Marshal(result)
+	return string(encoded)
+}
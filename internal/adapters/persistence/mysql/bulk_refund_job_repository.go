@@ -0,0 +1,226 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// BulkRefundJobRepository implements ports.BulkRefundJobRepository for MySQL
+type BulkRefundJobRepository struct {
+	db *sql.DB
+}
+
+// NewBulkRefundJobRepository creates a new MySQL bulk refund job repository
+func NewBulkRefundJobRepository(db *sql.DB) *BulkRefundJobRepository {
+	return &BulkRefundJobRepository{db: db}
+}
+
+// bulkRefundJobColumns are the columns scanned by query, in order
+const bulkRefundJobColumns = `id, partner_id, transaction_ids, reason_code, reason, livemode,
+			   ip_address, user_agent, status, results, succeeded, failed,
+			   next_attempt_at, created_at, updated_at, completed_at`
+
+// Create creates a new bulk refund job
+func (r *BulkRefundJobRepository) Create(ctx context.Context, job *entities.BulkRefundJob) error {
+	query := `
+		INSERT INTO bulk_refund_jobs (
+			id, partner_id, transaction_ids, reason_code, reason, livemode,
+			ip_address, user_agent, status, results, succeeded, failed,
+			next_attempt_at, created_at, updated_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	transactionIDsJSON, err := json.Marshal(job.TransactionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction ids: %w", err)
+	}
+
+	resultsJSON, err := json.Marshal(job.Results)
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		job.ID,
+		job.PartnerID,
+		string(transactionIDsJSON),
+		string(job.Reason.Code),
+		job.Reason.Note,
+		job.Livemode,
+		job.IPAddress,
+		job.UserAgent,
+		string(job.Status),
+		string(resultsJSON),
+		job.Succeeded,
+		job.Failed,
+		job.NextAttemptAt,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk refund job: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a bulk refund job by ID
+func (r *BulkRefundJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.BulkRefundJob, error) {
+	jobs, err := r.query(ctx, sqldb.Conn(ctx, r.db), "SELECT "+bulkRefundJobColumns+" FROM bulk_refund_jobs WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, errors.ErrBulkRefundJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// ClaimDue claims due jobs, oldest first. Rows another worker is claiming
+// are skipped rather than waited for.
+func (r *BulkRefundJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.BulkRefundJob, error) {
+	b := &sqlBuilder{}
+	b.where("status <> %s", entities.BulkRefundJobStatusCompleted)
+	b.where("next_attempt_at <= UTC_TIMESTAMP(6)")
+	query := `
+		SELECT ` + bulkRefundJobColumns + `
+		FROM bulk_refund_jobs
+		` + b.clause() + `
+		ORDER BY created_at ASC
+		LIMIT ` + b.arg(limit) + `
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var jobs []*entities.BulkRefundJob
+	err := sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		jobs, err = r.query(ctx, tx, query, b.args...)
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		claim := &sqlBuilder{}
+		until := claim.arg(claimedUntil)
+		placeholders := make([]string, len(jobs))
+		for i, job := range jobs {
+			placeholders[i] = claim.arg(job.ID)
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE bulk_refund_jobs SET next_attempt_at = "+until+" WHERE id IN ("+strings.Join(placeholders, ", ")+")", claim.args...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to claim bulk refund jobs: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		job.NextAttemptAt = claimedUntil
+	}
+	return jobs, nil
+}
+
+// Update updates job progress and results, and when it is next due
+func (r *BulkRefundJobRepository) Update(ctx context.Context, job *entities.BulkRefundJob) error {
+	query := `
+		UPDATE bulk_refund_jobs SET
+			status = ?,
+			results = ?,
+			succeeded = ?,
+			failed = ?,
+			next_attempt_at = ?,
+			updated_at = ?,
+			completed_at = ?
+		WHERE id = ?
+	`
+	resultsJSON, err := json.Marshal(job.Results)
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		string(job.Status),
+		string(resultsJSON),
+		job.Succeeded,
+		job.Failed,
+		job.NextAttemptAt,
+		job.UpdatedAt,
+		job.CompletedAt,
+		job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update bulk refund job: %w", err)
+	}
+	return nil
+}
+
+// query runs a SELECT of bulkRefundJobColumns on db
+func (r *BulkRefundJobRepository) query(ctx context.Context, db sqldb.Querier, query string, args ...interface{}) ([]*entities.BulkRefundJob, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk refund jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entities.BulkRefundJob
+	for rows.Next() {
+		var job entities.BulkRefundJob
+		var status string
+		var reasonCode string
+		var reasonNote, ipAddress, userAgent sql.NullString
+		var transactionIDsJSON, resultsJSON []byte
+		if err := rows.Scan(
+			&job.ID,
+			&job.PartnerID,
+			&transactionIDsJSON,
+			&reasonCode,
+			&reasonNote,
+			&job.Livemode,
+			&ipAddress,
+			&userAgent,
+			&status,
+			&resultsJSON,
+			&job.Succeeded,
+			&job.Failed,
+			&job.NextAttemptAt,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+			&job.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bulk refund job: %w", err)
+		}
+
+		job.Reason = valueobjects.RefundReason{
+			Code: valueobjects.RefundReasonCode(reasonCode),
+			Note: reasonNote.String,
+		}
+		job.IPAddress = ipAddress.String
+		job.UserAgent = userAgent.String
+		job.Status = entities.BulkRefundJobStatus(status)
+		if err := json.Unmarshal(transactionIDsJSON, &job.TransactionIDs); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction ids: %w", err)
+		}
+		if err := json.Unmarshal(resultsJSON, &job.Results); err != nil {
+			return nil, fmt.Errorf("failed to decode results: %w", err)
+		}
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get bulk refund jobs: %w", err)
+	}
+	return jobs, nil
+}
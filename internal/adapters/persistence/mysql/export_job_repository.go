@@ -0,0 +1,205 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+// ExportJobRepository implements ports.ExportJobRepository for MySQL
+type ExportJobRepository struct {
+	db *sql.DB
+}
+
+// NewExportJobRepository creates a new MySQL export job repository
+func NewExportJobRepository(db *sql.DB) *ExportJobRepository {
+	return &ExportJobRepository{db: db}
+}
+
+// exportJobColumns are the columns scanned by query, in order
+const exportJobColumns = `id, partner_id, livemode, resource, format, filters, csv_columns, timezone, status,
+			   attempts, next_attempt_at, last_error, object_key, row_count, created_at, completed_at`
+
+// Add queues a job
+func (r *ExportJobRepository) Add(ctx context.Context, job *entities.ExportJob) error {
+	filtersJSON, err := json.Marshal(job.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode export filters: %w", err)
+	}
+
+	query := `
+		INSERT INTO export_jobs (
+			id, partner_id, livemode, resource, format, filters, csv_columns,
+			timezone, status, attempts, next_attempt_at, created_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		job.ID,
+		job.PartnerID,
+		job.Livemode,
+		job.Resource,
+		job.Format,
+		filtersJSON,
+		strings.Join(job.Layout.Columns, ","),
+		job.Layout.Timezone,
+		job.Status,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add export job: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a job
+func (r *ExportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ExportJob, error) {
+	jobs, err := r.query(ctx, sqldb.Conn(ctx, r.db), "SELECT "+exportJobColumns+" FROM export_jobs WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, errors.ErrExportNotFound
+	}
+	return jobs[0], nil
+}
+
+// ClaimDue claims due jobs, oldest first. Rows another worker is claiming
+// are skipped rather than waited for.
+func (r *ExportJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.ExportJob, error) {
+	b := &sqlBuilder{}
+	b.where("status = %s", entities.ExportJobStatusPending)
+	b.where("next_attempt_at <= UTC_TIMESTAMP(6)")
+	query := `
+		SELECT ` + exportJobColumns + `
+		FROM export_jobs
+		` + b.clause() + `
+		ORDER BY created_at ASC
+		LIMIT ` + b.arg(limit) + `
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var jobs []*entities.ExportJob
+	err := sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		jobs, err = r.query(ctx, tx, query, b.args...)
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		claim := &sqlBuilder{}
+		until := claim.arg(claimedUntil)
+		placeholders := make([]string, len(jobs))
+		for i, job := range jobs {
+			placeholders[i] = claim.arg(job.ID)
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE export_jobs SET next_attempt_at = "+until+" WHERE id IN ("+strings.Join(placeholders, ", ")+")", claim.args...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to claim export jobs: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		job.NextAttemptAt = claimedUntil
+	}
+	return jobs, nil
+}
+
+// Update saves the outcome of an attempt
+func (r *ExportJobRepository) Update(ctx context.Context, job *entities.ExportJob) error {
+	query := `
+		UPDATE export_jobs SET
+			status = ?,
+			attempts = ?,
+			next_attempt_at = ?,
+			last_error = NULLIF(?, ''),
+			object_key = NULLIF(?, ''),
+			row_count = ?,
+			completed_at = ?
+		WHERE id = ?
+	`
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		job.Status,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.LastError,
+		job.ObjectKey,
+		job.RowCount,
+		job.CompletedAt,
+		job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update export job: %w", err)
+	}
+	return nil
+}
+
+// query runs a SELECT of exportJobColumns on db
+func (r *ExportJobRepository) query(ctx context.Context, db sqldb.Querier, query string, args ...interface{}) ([]*entities.ExportJob, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entities.ExportJob
+	for rows.Next() {
+		var job entities.ExportJob
+		var filtersJSON []byte
+		var csvColumns string
+		var lastError, objectKey sql.NullString
+		if err := rows.Scan(
+			&job.ID,
+			&job.PartnerID,
+			&job.Livemode,
+			&job.Resource,
+			&job.Format,
+			&filtersJSON,
+			&csvColumns,
+			&job.Layout.Timezone,
+			&job.Status,
+			&job.Attempts,
+			&job.NextAttemptAt,
+			&lastError,
+			&objectKey,
+			&job.RowCount,
+			&job.CreatedAt,
+			&job.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan export job: %w", err)
+		}
+
+		if err := json.Unmarshal(filtersJSON, &job.Filters); err != nil {
+			return nil, fmt.Errorf("failed to decode export filters: %w", err)
+		}
+		if csvColumns != "" {
+			job.Layout.Columns = strings.Split(csvColumns, ",")
+		}
+		job.LastError = lastError.String
+		job.ObjectKey = objectKey.String
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// erDupEntry is MySQL's error number for a duplicate unique key
+const erDupEntry = 1062
+
+// isDuplicateKey reports whether err is a unique key violation
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erDupEntry
+}
+
+// ReplicaLagQuery returns how many seconds a replica is behind: the age of
+// the oldest transaction its applier workers are applying, 0 when idle
+const ReplicaLagQuery = `
+	SELECT COALESCE(MAX(TIMESTAMPDIFF(MICROSECOND, APPLYING_TRANSACTION_ORIGINAL_COMMIT_TIMESTAMP, NOW(6))), 0) / 1000000
+	FROM performance_schema.replication_applier_status_by_worker
+	WHERE APPLYING_TRANSACTION <> ''
+`
@@ -0,0 +1,16 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+)
+
+// NewNamedLocker creates a ports.Locker on MySQL named locks (GET_LOCK),
+// which are held by the session. Names are limited to 64 characters.
+func NewNamedLocker(db *sql.DB) *sqldb.SessionLocker {
+	return sqldb.NewSessionLocker(db,
+		`SELECT GET_LOCK(?, 0) = 1`,
+		`SELECT RELEASE_LOCK(?)`,
+	)
+}
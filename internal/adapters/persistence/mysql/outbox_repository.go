@@ -0,0 +1,286 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// OutboxRepository implements ports.OutboxRepository for MySQL
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new MySQL outbox repository
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Add records an event in the caller's unit of work, if there is one
+func (r *OutboxRepository) Add(ctx context.Context, event *entities.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_events (
+			id, partner_id, aggregate_type, aggregate_id, event_type, payload, shard,
+			attempts, next_attempt_at, created_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		event.ID,
+		event.PartnerID,
+		event.AggregateType,
+		event.AggregateID,
+		event.EventType,
+		string(event.Payload),
+		event.Shard,
+		event.Attempts,
+		event.NextAttemptAt,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue claims due events of shards, oldest first. Rows another relay
+// is claiming are skipped rather than waited for.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, shards ports.OutboxShards, limit int, claimedUntil time.Time) ([]*entities.OutboxEvent, error) {
+	b := &sqlBuilder{}
+	b.where("published_at IS NULL")
+	b.where("next_attempt_at <= UTC_TIMESTAMP(6)")
+	b.where("attempts < %s", entities.MaxOutboxAttempts)
+	if shards.Count > 1 {
+		b.where("MOD(shard, %s) = %s", shards.Count, shards.Index)
+	}
+	query := `
+		SELECT id, partner_id, aggregate_type, aggregate_id, event_type, payload, shard,
+			   attempts, next_attempt_at, last_error, created_at, published_at
+		FROM outbox_events
+		` + b.clause() + `
+		ORDER BY created_at ASC
+		LIMIT ` + b.arg(limit) + `
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var events []*entities.OutboxEvent
+	err := sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		events, err = r.query(ctx, tx, query, b.args...)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		claim := &sqlBuilder{}
+		until := claim.arg(claimedUntil)
+		placeholders := make([]string, len(events))
+		for i, event := range events {
+			placeholders[i] = claim.arg(event.ID)
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE outbox_events SET next_attempt_at = "+until+" WHERE id IN ("+strings.Join(placeholders, ", ")+")", claim.args...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to claim outbox events: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		event.NextAttemptAt = claimedUntil
+	}
+	return events, nil
+}
+
+// Update saves the outcome of a publish attempt
+func (r *OutboxRepository) Update(ctx context.Context, event *entities.OutboxEvent) error {
+	query := `
+		UPDATE outbox_events SET
+			attempts = ?,
+			next_attempt_at = ?,
+			last_error = NULLIF(?, ''),
+			published_at = ?
+		WHERE id = ?
+	`
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		event.Attempts,
+		event.NextAttemptAt,
+		event.LastError,
+		event.PublishedAt,
+		event.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	return nil
+}
+
+// Backlog counts the unpublished events that have attempts left
+func (r *OutboxRepository) Backlog(ctx context.Context) (ports.OutboxBacklog, error) {
+	var backlog ports.OutboxBacklog
+	err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT COUNT(*), MIN(created_at)
+		FROM outbox_events
+		WHERE published_at IS NULL AND attempts < ?
+	`, entities.MaxOutboxAttempts).Scan(&backlog.Pending, &backlog.OldestCreatedAt)
+	if err != nil {
+		return ports.OutboxBacklog{}, fmt.Errorf("failed to count outbox events: %w", err)
+	}
+	return backlog, nil
+}
+
+// ListPublishedBefore returns events published before before, oldest first
+func (r *OutboxRepository) ListPublishedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	query := `
+		SELECT id, partner_id, aggregate_type, aggregate_id, event_type, payload, shard,
+			   attempts, next_attempt_at, last_error, created_at, published_at
+		FROM outbox_events
+		WHERE published_at < ?
+		ORDER BY published_at ASC
+		LIMIT ?
+	`
+	return r.query(ctx, sqldb.Conn(ctx, r.db), query, before, limit)
+}
+
+// ListByAggregate returns up to limit events about one aggregate, oldest first
+func (r *OutboxRepository) ListByAggregate(ctx context.Context, aggregateType string, aggregateID uuid.UUID, limit int) ([]*entities.OutboxEvent, error) {
+	query := `
+		SELECT id, partner_id, aggregate_type, aggregate_id, event_type, payload, shard,
+			   attempts, next_attempt_at, last_error, created_at, published_at
+		FROM outbox_events
+		WHERE aggregate_type = ? AND aggregate_id = ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ?
+	`
+	return r.query(ctx, sqldb.Conn(ctx, r.db), query, aggregateType, aggregateID, limit)
+}
+
+// ListByPartner returns up to limit of a partner's events recorded after
+// the cursor and no later than until, oldest first
+func (r *OutboxRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, after ports.OutboxCursor, until time.Time, limit int) ([]*entities.OutboxEvent, error) {
+	query := `
+		SELECT id, partner_id, aggregate_type, aggregate_id, event_type, payload, shard,
+			   attempts, next_attempt_at, last_error, created_at, published_at
+		FROM outbox_events
+		WHERE partner_id = ?
+		  AND (created_at > ? OR (created_at = ? AND id > ?))
+		  AND created_at <= ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ?
+	`
+	return r.query(ctx, sqldb.Conn(ctx, r.db), query, partnerID, after.CreatedAt, after.CreatedAt, after.ID, until, limit)
+}
+
+// Delete removes events by ID
+func (r *OutboxRepository) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	b := &sqlBuilder{}
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		placeholders[i] = b.arg(id)
+	}
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx,
+		"DELETE FROM outbox_events WHERE id IN ("+strings.Join(placeholders, ", ")+")", b.args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete outbox events: %w", err)
+	}
+	return nil
+}
+
+// GetDeliverySummary counts, per partner, the webhook events recorded since
+// since that were published and those whose every attempt so far failed
+func (r *OutboxRepository) GetDeliverySummary(ctx context.Context, since time.Time) ([]ports.DeliverySummary, error) {
+	args := []interface{}{since}
+	for _, eventType := range entities.WebhookEvents {
+		args = append(args, eventType)
+	}
+	eventTypes := strings.TrimSuffix(strings.Repeat("?, ", len(entities.WebhookEvents)), ", ")
+	rows, err := sqldb.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT partner_id,
+			   SUM(CASE WHEN published_at IS NOT NULL THEN 1 ELSE 0 END),
+			   SUM(CASE WHEN published_at IS NULL AND attempts > 0 THEN 1 ELSE 0 END)
+		FROM outbox_events
+		WHERE created_at >= ? AND event_type IN (`+eventTypes+`)
+		GROUP BY partner_id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ports.DeliverySummary
+	for rows.Next() {
+		var summary ports.DeliverySummary
+		if err := rows.Scan(&summary.PartnerID, &summary.Delivered, &summary.Failing); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// query runs a SELECT of outbox events on db
+func (r *OutboxRepository) query(ctx context.Context, db sqldb.Querier, query string, args ...interface{}) ([]*entities.OutboxEvent, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entities.OutboxEvent
+	for rows.Next() {
+		var event entities.OutboxEvent
+		var payloadJSON []byte
+		var lastError sql.NullString
+		if err := rows.Scan(
+			&event.ID,
+			&event.PartnerID,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.EventType,
+			&payloadJSON,
+			&event.Shard,
+			&event.Attempts,
+			&event.NextAttemptAt,
+			&lastError,
+			&event.CreatedAt,
+			&event.PublishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+
+		event.LastError = lastError.String
+		event.Payload = payloadJSON
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteByPartner removes all of a partner's events
+func (r *OutboxRepository) DeleteByPartner(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM outbox_events WHERE partner_id = ?", partnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete outbox events: %w", err)
+	}
+	return result.RowsAffected()
+}
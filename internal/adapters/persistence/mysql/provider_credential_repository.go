@@ -0,0 +1,164 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ProviderCredentialRepository implements ports.ProviderCredentialRepository for MySQL.
+// Secret keys are encrypted with cipher before they are written.
+type ProviderCredentialRepository struct {
+	db     *sql.DB
+	cipher ports.SecretCipher
+}
+
+// NewProviderCredentialRepository creates a new MySQL provider credential repository
+func NewProviderCredentialRepository(db *sql.DB, cipher ports.SecretCipher) *ProviderCredentialRepository {
+	return &ProviderCredentialRepository{db: db, cipher: cipher}
+}
+
+// Save creates or replaces the partner's credential for its provider
+func (r *ProviderCredentialRepository) Save(ctx context.Context, credential *entities.ProviderCredential) error {
+	query := `
+		INSERT INTO provider_credentials (
+			id, partner_id, provider, account_id, secret_key,
+			created_at, updated_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?
+		)
+		ON DUPLICATE KEY UPDATE
+			account_id = VALUES(account_id),
+			secret_key = VALUES(secret_key),
+			updated_at = VALUES(updated_at)
+	`
+
+	secretKey, err := r.cipher.Encrypt(credential.SecretKey, providerCredentialSecret(credential.PartnerID, credential.Provider))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt provider secret: %w", err)
+	}
+
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		credential.ID,
+		credential.PartnerID,
+		credential.Provider.String(),
+		credential.AccountID,
+		secretKey,
+		credential.CreatedAt,
+		credential.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save provider credential: %w", err)
+	}
+
+	return nil
+}
+
+// GetByPartnerAndProvider retrieves a partner's credential for a provider
+func (r *ProviderCredentialRepository) GetByPartnerAndProvider(
+	ctx context.Context,
+	partnerID uuid.UUID,
+	provider valueobjects.PaymentProvider,
+) (*entities.ProviderCredential, error) {
+	query := `
+		SELECT id, partner_id, provider, account_id, secret_key,
+			   created_at, updated_at
+		FROM provider_credentials
+		WHERE partner_id = ? AND provider = ?
+	`
+
+	credential, err := r.scanCredential(sqldb.Conn(ctx, r.db).QueryRowContext(ctx, query, partnerID, provider.String()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrProviderCredentialNotFound
+		}
+		return nil, fmt.Errorf("failed to get provider credential: %w", err)
+	}
+
+	return credential, nil
+}
+
+// ListByPartnerID retrieves all credentials of a partner
+func (r *ProviderCredentialRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.ProviderCredential, error) {
+	query := `
+		SELECT id, partner_id, provider, account_id, secret_key,
+			   created_at, updated_at
+		FROM provider_credentials
+		WHERE partner_id = ?
+		ORDER BY provider
+	`
+
+	rows, err := sqldb.Conn(ctx, r.db).QueryContext(ctx, query, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider credentials: %w", err)
+	}
+	defer rows.Close()
+
+	var credentials []*entities.ProviderCredential
+	for rows.Next() {
+		credential, err := r.scanCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, rows.Err()
+}
+
+// Delete removes a partner's credential for a provider
+func (r *ProviderCredentialRepository) Delete(ctx context.Context, partnerID uuid.UUID, provider valueobjects.PaymentProvider) error {
+	query := `DELETE FROM provider_credentials WHERE partner_id = ? AND provider = ?`
+
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, partnerID, provider.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete provider credential: %w", err)
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.ErrProviderCredentialNotFound
+	}
+
+	return nil
+}
+
+// RotateSecrets re-encrypts provider secret keys with the current encryption key
+func (r *ProviderCredentialRepository) RotateSecrets(ctx context.Context) (int, error) {
+	return rotateSecretColumn(ctx, r.db, r.cipher, "provider_credentials", "secret_key", "partner_id", "provider")
+}
+
+// scanCredential reads a credential from a row, decrypting its secret key
+func (r *ProviderCredentialRepository) scanCredential(row sqldb.RowScanner) (*entities.ProviderCredential, error) {
+	var credential entities.ProviderCredential
+	var provider string
+
+	err := row.Scan(
+		&credential.ID,
+		&credential.PartnerID,
+		&provider,
+		&credential.AccountID,
+		&credential.SecretKey,
+		&credential.CreatedAt,
+		&credential.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.Provider = valueobjects.PaymentProvider(provider)
+	credential.SecretKey, err = r.cipher.Decrypt(credential.SecretKey, providerCredentialSecret(credential.PartnerID, credential.Provider))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt provider secret: %w", err)
+	}
+
+	return &credential, nil
+}
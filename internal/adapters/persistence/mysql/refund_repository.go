@@ -0,0 +1,429 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RefundRepository implements ports.RefundRepository for MySQL
+type RefundRepository struct {
+	db *sql.DB
+	// replica serves reads in read-only contexts; nil reads from db
+	replica *sqldb.Replica
+}
+
+// NewRefundRepository creates a new MySQL refund repository
+func NewRefundRepository(db *sql.DB, replica *sqldb.Replica) *RefundRepository {
+	return &RefundRepository{db: db, replica: replica}
+}
+
+// Create creates a new refund
+func (r *RefundRepository) Create(ctx context.Context, refund *entities.Refund) error {
+	return r.insert(ctx, sqldb.Conn(ctx, r.db), refund)
+}
+
+// Reserve creates a refund and adds its amount to the transaction's refunded
+// total in one database transaction. The conditional UPDATE takes a row lock,
+// so concurrent refunds are serialized and can never exceed the original amount.
+func (r *RefundRepository) Reserve(ctx context.Context, refund *entities.Refund) error {
+	return sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE transactions
+			SET refunded_amount = refunded_amount + ?
+			WHERE id = ? AND refunded_amount + ? <= amount AND deleted_at IS NULL
+		`, refund.Amount, refund.TransactionID, refund.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to reserve refund amount: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to reserve refund amount: %w", err)
+		}
+		if rows == 0 {
+			return errors.ErrRefundAmountExceeded
+		}
+
+		return r.insert(ctx, tx, refund)
+	})
+}
+
+// Release saves a refund that will never complete and returns its amount to
+// the transaction's refundable balance in one database transaction
+func (r *RefundRepository) Release(ctx context.Context, refund *entities.Refund) error {
+	return sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.update(ctx, tx, refund); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE transactions
+			SET refunded_amount = GREATEST(refunded_amount - ?, 0)
+			WHERE id = ?
+		`, refund.Amount, refund.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to release refund amount: %w", err)
+		}
+		return nil
+	})
+}
+
+// refundInsert is the INSERT of insert and CreateBatch, to be followed by a
+// row of refundValues per refund
+const refundInsert = `
+		INSERT INTO refunds (
+			id, transaction_id, amount, currency, reason_code, reason, status,
+			created_at, updated_at
+		) VALUES `
+
+// insert writes a new refund row using the given executor
+func (r *RefundRepository) insert(ctx context.Context, db sqldb.Querier, refund *entities.Refund) error {
+	if err := execInsert(ctx, db, refundInsert, [][]interface{}{refundValues(refund)}); err != nil {
+		return fmt.Errorf("failed to create refund: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch creates refunds with multi-row INSERTs
+func (r *RefundRepository) CreateBatch(ctx context.Context, refunds []*entities.Refund) error {
+	rows := make([][]interface{}, len(refunds))
+	for i, refund := range refunds {
+		rows[i] = refundValues(refund)
+	}
+
+	if err := insertRows(ctx, r.db, refundInsert, rows); err != nil {
+		return fmt.Errorf("failed to create refunds: %w", err)
+	}
+	return nil
+}
+
+// refundValues returns the columns of refundInsert for refund
+func refundValues(refund *entities.Refund) []interface{} {
+	return []interface{}{
+		refund.ID,
+		refund.TransactionID,
+		refund.Amount,
+		refund.Amount.Currency,
+		string(refund.Reason.Code),
+		refund.Reason.Note,
+		string(refund.Status),
+		refund.CreatedAt,
+		refund.UpdatedAt,
+	}
+}
+
+// GetByID retrieves a refund by ID
+func (r *RefundRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Refund, error) {
+	query := `
+		SELECT id, transaction_id, amount, currency, reason_code, reason, status,
+			   provider_refund_id, COALESCE(error_code, ''), COALESCE(error_message, ''),
+			   approved_by, approved_at,
+			   created_at, updated_at, processed_at, cancelled_at, version
+		FROM refunds
+		WHERE id = ? AND deleted_at IS NULL
+	`
+	var refund entities.Refund
+	var status string
+	var reasonCode string
+	var reasonNote sql.NullString
+	var providerRefundID sql.NullString
+	var approvedBy sql.NullString
+	err := sqldb.ReadConn(ctx, r.db, r.replica).QueryRowContext(ctx, query, id).Scan(
+		&refund.ID,
+		&refund.TransactionID,
+		&refund.Amount,
+		&refund.Amount.Currency,
+		&reasonCode,
+		&reasonNote,
+		&status,
+		&providerRefundID,
+		&refund.ErrorCode,
+		&refund.ErrorMessage,
+		&approvedBy,
+		&refund.ApprovedAt,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+		&refund.ProcessedAt,
+		&refund.CancelledAt,
+		&refund.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrRefundNotFound
+		}
+		return nil, fmt.Errorf("failed to get refund: %w", err)
+	}
+
+	// Reconstruct value objects
+	refund.Reason = valueobjects.RefundReason{
+		Code: valueobjects.RefundReasonCode(reasonCode),
+		Note: reasonNote.String,
+	}
+	refund.Status = entities.RefundStatus(status)
+	if providerRefundID.Valid {
+		refund.ProviderRefundID = providerRefundID.String
+	}
+	if approvedBy.Valid {
+		refund.ApprovedBy = approvedBy.String
+	}
+	return &refund, nil
+}
+
+// GetByTransactionID retrieves all refunds for a transaction
+func (r *RefundRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error) {
+	query := `
+		SELECT id FROM refunds
+		WHERE transaction_id = ? AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %w", err)
+	}
+	defer rows.Close()
+	var refunds []*entities.Refund
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		refund, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, nil
+}
+
+// Update updates an existing refund if it is still at refund.Version, and
+// moves refund to the next version. A refund changed since it was loaded is
+// left alone and a *errors.VersionConflictError returned.
+func (r *RefundRepository) Update(ctx context.Context, refund *entities.Refund) error {
+	return r.update(ctx, sqldb.Conn(ctx, r.db), refund)
+}
+
+// update writes refund state using the given executor
+func (r *RefundRepository) update(ctx context.Context, db sqldb.Querier, refund *entities.Refund) error {
+	query := `
+		UPDATE refunds SET
+			status = ?,
+			provider_refund_id = ?,
+			error_code = ?,
+			error_message = ?,
+			approved_by = ?,
+			approved_at = ?,
+			updated_at = ?,
+			processed_at = ?,
+			cancelled_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+	result, err := db.ExecContext(ctx, query,
+		string(refund.Status),
+		refund.ProviderRefundID,
+		refund.ErrorCode,
+		refund.ErrorMessage,
+		sql.NullString{String: refund.ApprovedBy, Valid: refund.ApprovedBy != ""},
+		refund.ApprovedAt,
+		refund.UpdatedAt,
+		refund.ProcessedAt,
+		refund.CancelledAt,
+		refund.ID,
+		refund.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "refund", refund.ID, refund.Version); err != nil {
+		return err
+	}
+	refund.Version++
+	return nil
+}
+
+// Delete saves refund's DeletedAt if it is still at refund.Version, and moves
+// refund to the next version
+func (r *RefundRepository) Delete(ctx context.Context, refund *entities.Refund) error {
+	query := `
+		UPDATE refunds SET
+			deleted_at = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, refund.DeletedAt, refund.UpdatedAt, refund.ID, refund.Version)
+	if err != nil {
+		return fmt.Errorf("failed to delete refund: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "refund", refund.ID, refund.Version); err != nil {
+		return err
+	}
+	refund.Version++
+	return nil
+}
+
+// Restore clears the DeletedAt of a deleted refund
+func (r *RefundRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE refunds SET
+			deleted_at = NULL,
+			updated_at = UTC_TIMESTAMP(6),
+			version = version + 1
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore refund: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to restore refund: %w", err)
+	}
+	if rows == 0 {
+		return errors.ErrRefundNotFound
+	}
+	return nil
+}
+
+// GetTotalRefundedAmount calculates total refunded amount for a transaction
+func (r *RefundRepository) GetTotalRefundedAmount(ctx context.Context, transactionID uuid.UUID) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM refunds
+		WHERE transaction_id = ?
+		  AND status = 'completed'
+		  AND deleted_at IS NULL
+	`
+	var total int64
+	err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, query, transactionID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total refunded amount: %w", err)
+	}
+	return total, nil
+}
+
+// GetReasonSummary aggregates a partner's completed refunds by reason code
+func (r *RefundRepository) GetReasonSummary(ctx context.Context, filter ports.RefundReportFilter) ([]ports.RefundReasonSummary, error) {
+	query := `
+		SELECT r.reason_code, r.currency, COUNT(*), COALESCE(SUM(r.amount), 0)
+		FROM refunds r
+		JOIN transactions t ON t.id = r.transaction_id
+		WHERE t.partner_id = ?
+		  AND t.livemode = ?
+		  AND r.status = 'completed'
+		  AND r.deleted_at IS NULL
+		  AND t.deleted_at IS NULL
+	`
+	args := []interface{}{filter.PartnerID, filter.Livemode}
+	if filter.DateFrom != nil {
+		query += " AND r.created_at >= CAST(? AS DATE)"
+		args = append(args, *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query += " AND r.created_at < CAST(? AS DATE) + INTERVAL 1 DAY"
+		args = append(args, *filter.DateTo)
+	}
+	query += " GROUP BY r.reason_code, r.currency ORDER BY r.reason_code, r.currency"
+
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize refunds: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ports.RefundReasonSummary
+	for rows.Next() {
+		var summary ports.RefundReasonSummary
+		var reasonCode string
+		if err := rows.Scan(&reasonCode, &summary.Total.Currency, &summary.Count, &summary.Total); err != nil {
+			return nil, err
+		}
+		summary.ReasonCode = valueobjects.RefundReasonCode(reasonCode)
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// List returns a partner's refunds matching query, newest first
+func (r *RefundRepository) List(ctx context.Context, query ports.RefundQuery) ([]*entities.Refund, error) {
+	b := &sqlBuilder{}
+	b.where("t.partner_id = %s", query.PartnerID)
+	b.where("t.livemode = %s", query.Livemode)
+	b.where("r.deleted_at IS NULL")
+	b.where("t.deleted_at IS NULL")
+	if query.TransactionID != nil {
+		b.where("r.transaction_id = %s", *query.TransactionID)
+	}
+	if query.Status != nil {
+		b.where("r.status = %s", string(*query.Status))
+	}
+	if query.CreatedFrom != nil {
+		b.where("r.created_at >= %s", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		b.where("r.created_at < %s", *query.CreatedTo)
+	}
+	statement := "SELECT r.id FROM refunds r JOIN transactions t ON t.id = r.transaction_id" +
+		b.clause() + " ORDER BY r.created_at DESC, r.id DESC"
+	statement += " LIMIT " + b.arg(query.Limit) + " OFFSET " + b.arg(query.Offset)
+
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, statement, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*entities.Refund, 0, len(ids))
+	for _, id := range ids {
+		refund, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, nil
+}
+
+// AnonymizeCustomerData clears the reason notes of all of a partner's refunds
+func (r *RefundRepository) AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	query := `
+		UPDATE refunds SET
+			reason = '',
+			version = version + 1,
+			updated_at = UTC_TIMESTAMP(6)
+		WHERE transaction_id IN (SELECT id FROM transactions WHERE partner_id = ?)
+		  AND reason <> ''
+	`
+
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, partnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize refunds: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,308 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ReportScheduleRepository implements ports.ReportScheduleRepository for
+// MySQL. SFTP credentials are encrypted with cipher before they are
+// written.
+type ReportScheduleRepository struct {
+	db     *sql.DB
+	cipher ports.SecretCipher
+}
+
+// NewReportScheduleRepository creates a new MySQL report schedule repository
+func NewReportScheduleRepository(db *sql.DB, cipher ports.SecretCipher) *ReportScheduleRepository {
+	return &ReportScheduleRepository{db: db, cipher: cipher}
+}
+
+// reportScheduleColumns are the columns scanned by query, in order
+const reportScheduleColumns = `id, partner_id, livemode, name, resource, format, filters, csv_columns, timezone,
+			   frequency, run_hour, destination, destination_secret, next_run_at, attempts, last_run_at,
+			   last_status, last_error, version, created_at, updated_at`
+
+// Create stores a new schedule
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *entities.ReportSchedule) error {
+	filtersJSON, destinationJSON, secret, err := r.encode(schedule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO report_schedules (
+			id, partner_id, livemode, name, resource, format, filters, csv_columns,
+			timezone, frequency, run_hour, destination, destination_secret,
+			next_run_at, attempts, last_run_at, last_status, last_error,
+			version, created_at, updated_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		schedule.ID,
+		schedule.PartnerID,
+		schedule.Livemode,
+		schedule.Name,
+		schedule.Resource,
+		schedule.Format,
+		filtersJSON,
+		strings.Join(schedule.Layout.Columns, ","),
+		schedule.Layout.Timezone,
+		schedule.Frequency,
+		schedule.Hour,
+		destinationJSON,
+		secret,
+		schedule.NextRunAt,
+		schedule.Attempts,
+		schedule.LastRunAt,
+		schedule.LastStatus,
+		schedule.LastError,
+		schedule.Version,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create report schedule: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a schedule
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ReportSchedule, error) {
+	schedules, err := r.query(ctx, sqldb.Conn(ctx, r.db), "SELECT "+reportScheduleColumns+" FROM report_schedules WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, errors.ErrReportScheduleNotFound
+	}
+	return schedules[0], nil
+}
+
+// ListByPartner retrieves a partner's schedules in livemode, oldest first
+func (r *ReportScheduleRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, livemode bool) ([]*entities.ReportSchedule, error) {
+	query := `
+		SELECT ` + reportScheduleColumns + `
+		FROM report_schedules
+		WHERE partner_id = ? AND livemode = ?
+		ORDER BY created_at ASC, id ASC
+	`
+	return r.query(ctx, sqldb.Conn(ctx, r.db), query, partnerID, livemode)
+}
+
+// Update saves a schedule at its version
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *entities.ReportSchedule) error {
+	filtersJSON, destinationJSON, secret, err := r.encode(schedule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE report_schedules SET
+			name = ?,
+			resource = ?,
+			format = ?,
+			filters = ?,
+			csv_columns = ?,
+			timezone = ?,
+			frequency = ?,
+			run_hour = ?,
+			destination = ?,
+			destination_secret = ?,
+			next_run_at = ?,
+			attempts = ?,
+			last_run_at = ?,
+			last_status = ?,
+			last_error = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?
+	`
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		schedule.Name,
+		schedule.Resource,
+		schedule.Format,
+		filtersJSON,
+		strings.Join(schedule.Layout.Columns, ","),
+		schedule.Layout.Timezone,
+		schedule.Frequency,
+		schedule.Hour,
+		destinationJSON,
+		secret,
+		schedule.NextRunAt,
+		schedule.Attempts,
+		schedule.LastRunAt,
+		schedule.LastStatus,
+		schedule.LastError,
+		schedule.UpdatedAt,
+		schedule.ID,
+		schedule.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update report schedule: %w", err)
+	}
+	if err := sqldb.CheckVersionedUpdate(result, "report_schedule", schedule.ID, schedule.Version); err != nil {
+		return err
+	}
+	schedule.Version++
+	return nil
+}
+
+// Delete removes a schedule
+func (r *ReportScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM report_schedules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete report schedule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.ErrReportScheduleNotFound
+	}
+	return nil
+}
+
+// ClaimDue claims due schedules, longest due first. Rows another worker is
+// claiming are skipped rather than waited for.
+func (r *ReportScheduleRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.ReportSchedule, error) {
+	query := `
+		SELECT ` + reportScheduleColumns + `
+		FROM report_schedules
+		WHERE next_run_at <= UTC_TIMESTAMP(6)
+		ORDER BY next_run_at ASC
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var schedules []*entities.ReportSchedule
+	err := sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		schedules, err = r.query(ctx, tx, query, limit)
+		if err != nil || len(schedules) == 0 {
+			return err
+		}
+
+		claim := &sqlBuilder{}
+		until := claim.arg(claimedUntil)
+		placeholders := make([]string, len(schedules))
+		for i, schedule := range schedules {
+			placeholders[i] = claim.arg(schedule.ID)
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE report_schedules SET next_run_at = "+until+", version = version + 1 WHERE id IN ("+strings.Join(placeholders, ", ")+")", claim.args...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to claim report schedules: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, schedule := range schedules {
+		schedule.NextRunAt = claimedUntil
+		schedule.Version++
+	}
+	return schedules, nil
+}
+
+// RotateSecrets re-encrypts SFTP credentials with the current encryption key
+func (r *ReportScheduleRepository) RotateSecrets(ctx context.Context) (int, error) {
+	return rotateSecretColumn(ctx, r.db, r.cipher, "report_schedules", "destination_secret", "partner_id", "")
+}
+
+// encode returns the filters and destination of schedule as JSON, and its
+// encrypted SFTP credential
+func (r *ReportScheduleRepository) encode(schedule *entities.ReportSchedule) ([]byte, []byte, string, error) {
+	filtersJSON, err := json.Marshal(schedule.Filters)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to encode report filters: %w", err)
+	}
+	destinationJSON, err := json.Marshal(schedule.Destination)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to encode report destination: %w", err)
+	}
+	secret := ""
+	if schedule.Destination.Secret() != "" {
+		secret, err = r.cipher.Encrypt(schedule.Destination.Secret(), reportDestinationSecret(schedule.PartnerID))
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to encrypt report destination secret: %w", err)
+		}
+	}
+	return filtersJSON, destinationJSON, secret, nil
+}
+
+// query runs a SELECT of reportScheduleColumns on db
+func (r *ReportScheduleRepository) query(ctx context.Context, db sqldb.Querier, query string, args ...interface{}) ([]*entities.ReportSchedule, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*entities.ReportSchedule
+	for rows.Next() {
+		var schedule entities.ReportSchedule
+		var filtersJSON, destinationJSON []byte
+		var csvColumns, secret string
+		if err := rows.Scan(
+			&schedule.ID,
+			&schedule.PartnerID,
+			&schedule.Livemode,
+			&schedule.Name,
+			&schedule.Resource,
+			&schedule.Format,
+			&filtersJSON,
+			&csvColumns,
+			&schedule.Layout.Timezone,
+			&schedule.Frequency,
+			&schedule.Hour,
+			&destinationJSON,
+			&secret,
+			&schedule.NextRunAt,
+			&schedule.Attempts,
+			&schedule.LastRunAt,
+			&schedule.LastStatus,
+			&schedule.LastError,
+			&schedule.Version,
+			&schedule.CreatedAt,
+			&schedule.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan report schedule: %w", err)
+		}
+
+		if err := json.Unmarshal(filtersJSON, &schedule.Filters); err != nil {
+			return nil, fmt.Errorf("failed to decode report filters: %w", err)
+		}
+		if err := json.Unmarshal(destinationJSON, &schedule.Destination); err != nil {
+			return nil, fmt.Errorf("failed to decode report destination: %w", err)
+		}
+		if csvColumns != "" {
+			schedule.Layout.Columns = strings.Split(csvColumns, ",")
+		}
+		if secret != "" {
+			plaintext, err := r.cipher.Decrypt(secret, reportDestinationSecret(schedule.PartnerID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt report destination secret: %w", err)
+			}
+			schedule.Destination = schedule.Destination.WithSecret(plaintext)
+		}
+		schedules = append(schedules, &schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	return schedules, nil
+}
@@ -0,0 +1,177 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// StatementRepository implements ports.StatementRepository for MySQL
+type StatementRepository struct {
+	db *sql.DB
+}
+
+// NewStatementRepository creates a new MySQL statement repository
+func NewStatementRepository(db *sql.DB) *StatementRepository {
+	return &StatementRepository{db: db}
+}
+
+// statementColumns are the columns scanned by query, in order
+const statementColumns = `id, partner_id, period, pricing, rounding_policy, currencies, document_key, created_at`
+
+// Create saves a statement, failing with errors.ErrStatementExists when
+// the partner already has one for the period
+func (r *StatementRepository) Create(ctx context.Context, statement *entities.Statement) error {
+	pricingJSON, err := json.Marshal(statement.Pricing)
+	if err != nil {
+		return fmt.Errorf("failed to encode statement pricing: %w", err)
+	}
+	currenciesJSON, err := json.Marshal(statement.Currencies)
+	if err != nil {
+		return fmt.Errorf("failed to encode statement currencies: %w", err)
+	}
+
+	query := `
+		INSERT INTO statements (
+			id, partner_id, period, pricing, rounding_policy, currencies, document_key, created_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	_, err = sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		statement.ID,
+		statement.PartnerID,
+		string(statement.Period),
+		pricingJSON,
+		string(statement.RoundingPolicy),
+		currenciesJSON,
+		statement.DocumentKey,
+		statement.CreatedAt,
+	)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return errors.ErrStatementExists
+		}
+		return fmt.Errorf("failed to create statement: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a statement
+func (r *StatementRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Statement, error) {
+	statements, err := r.query(ctx, "SELECT "+statementColumns+" FROM statements WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, errors.ErrStatementNotFound
+	}
+	return statements[0], nil
+}
+
+// GetByPeriod retrieves a partner's statement of period
+func (r *StatementRepository) GetByPeriod(ctx context.Context, partnerID uuid.UUID, period entities.StatementPeriod) (*entities.Statement, error) {
+	statements, err := r.query(ctx, "SELECT "+statementColumns+" FROM statements WHERE partner_id = ? AND period = ?", partnerID, string(period))
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, errors.ErrStatementNotFound
+	}
+	return statements[0], nil
+}
+
+// ListByPartner retrieves a partner's statements, latest period first
+func (r *StatementRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Statement, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM statements WHERE partner_id = ?`
+	if err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, countQuery, partnerID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count statements: %w", err)
+	}
+
+	query := `
+		SELECT ` + statementColumns + `
+		FROM statements
+		WHERE partner_id = ?
+		ORDER BY period DESC
+		LIMIT ? OFFSET ?
+	`
+	statements, err := r.query(ctx, query, partnerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return statements, total, nil
+}
+
+// ListByPeriod retrieves every partner's statement of period, by partner
+func (r *StatementRepository) ListByPeriod(ctx context.Context, period entities.StatementPeriod, limit, offset int) ([]*entities.Statement, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM statements WHERE period = ?`
+	if err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, countQuery, string(period)).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count statements: %w", err)
+	}
+
+	query := `
+		SELECT ` + statementColumns + `
+		FROM statements
+		WHERE period = ?
+		ORDER BY partner_id
+		LIMIT ? OFFSET ?
+	`
+	statements, err := r.query(ctx, query, string(period), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return statements, total, nil
+}
+
+// query runs a SELECT of statementColumns
+func (r *StatementRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entities.Statement, error) {
+	rows, err := sqldb.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statements: %w", err)
+	}
+	defer rows.Close()
+
+	var statements []*entities.Statement
+	for rows.Next() {
+		var statement entities.Statement
+		var period, roundingPolicy string
+		var pricingJSON, currenciesJSON []byte
+		if err := rows.Scan(
+			&statement.ID,
+			&statement.PartnerID,
+			&period,
+			&pricingJSON,
+			&roundingPolicy,
+			&currenciesJSON,
+			&statement.DocumentKey,
+			&statement.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan statement: %w", err)
+		}
+
+		if err := json.Unmarshal(pricingJSON, &statement.Pricing); err != nil {
+			return nil, fmt.Errorf("failed to decode statement pricing: %w", err)
+		}
+		if err := json.Unmarshal(currenciesJSON, &statement.Currencies); err != nil {
+			return nil, fmt.Errorf("failed to decode statement currencies: %w", err)
+		}
+		statement.Period = entities.StatementPeriod(period)
+		statement.RoundingPolicy = valueobjects.RoundingPolicy(roundingPolicy)
+		statements = append(statements, &statement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list statements: %w", err)
+	}
+
+	return statements, nil
+}
@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UsageRepository implements ports.UsageRepository for MySQL
+type UsageRepository struct {
+	db *sql.DB
+	// replica serves reads in read-only contexts; nil reads from db
+	replica *sqldb.Replica
+}
+
+// NewUsageRepository creates a new MySQL usage repository
+func NewUsageRepository(db *sql.DB, replica *sqldb.Replica) *UsageRepository {
+	return &UsageRepository{db: db, replica: replica}
+}
+
+// AddUsage adds records to the stored counters in one database transaction
+func (r *UsageRepository) AddUsage(ctx context.Context, records []ports.UsageRecord) error {
+	query := `
+		INSERT INTO partner_usage (
+			partner_id, day, currency, api_calls, client_errors, server_errors,
+			payments, failed_payments, volume
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+		ON DUPLICATE KEY UPDATE
+			api_calls = api_calls + VALUES(api_calls),
+			client_errors = client_errors + VALUES(client_errors),
+			server_errors = server_errors + VALUES(server_errors),
+			payments = payments + VALUES(payments),
+			failed_payments = failed_payments + VALUES(failed_payments),
+			volume = volume + VALUES(volume)
+	`
+	return sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, record := range records {
+			if _, err := tx.ExecContext(ctx, query,
+				record.PartnerID,
+				ports.UsageDay(record.Day),
+				record.Currency,
+				record.APICalls,
+				record.ClientErrors,
+				record.ServerErrors,
+				record.Payments,
+				record.FailedPayments,
+				record.Volume,
+			); err != nil {
+				return fmt.Errorf("failed to add usage: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListUsage returns the usage matching q
+func (r *UsageRepository) ListUsage(ctx context.Context, q ports.UsageQuery) ([]ports.UsageRecord, error) {
+	query := `
+		SELECT partner_id, day, currency, api_calls, client_errors, server_errors,
+			   payments, failed_payments, volume
+		FROM partner_usage
+		WHERE day >= ? AND day <= ?`
+	args := []interface{}{ports.UsageDay(q.From), ports.UsageDay(q.To)}
+	if q.PartnerID != nil {
+		query += ` AND partner_id = ?`
+		args = append(args, *q.PartnerID)
+	}
+	query += ` ORDER BY day, partner_id, currency`
+
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	defer rows.Close()
+
+	var records []ports.UsageRecord
+	for rows.Next() {
+		var record ports.UsageRecord
+		if err := rows.Scan(
+			&record.PartnerID,
+			&record.Day,
+			&record.Currency,
+			&record.APICalls,
+			&record.ClientErrors,
+			&record.ServerErrors,
+			&record.Payments,
+			&record.FailedPayments,
+			&record.Volume,
+		); err != nil {
+			return nil, err
+		}
+		record.Day = record.Day.UTC()
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+)
+
+// NewAdvisoryLocker creates a ports.Locker on Postgres session advisory
+// locks. Keys are hashed to the lock's 64-bit ID; two keys sharing a hash
+// would turn each other away, which is rare enough to ignore.
+func NewAdvisoryLocker(db *sql.DB) *sqldb.SessionLocker {
+	return sqldb.NewSessionLocker(db,
+		`SELECT pg_try_advisory_lock(hashtextextended($1, 0))`,
+		`SELECT pg_advisory_unlock(hashtextextended($1, 0))`,
+	)
+}
@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// auditLogColumns are the columns scanAuditLogEntry reads
+const auditLogColumns = `id, partner_id, action, resource_type, resource_id,
+	host(ip_address), COALESCE(user_agent, ''), request_id, changes, created_at,
+	prev_hash, hash`
+
+// AuditLogRepository implements ports.AuditLogRepository for PostgreSQL
+type AuditLogRepository struct {
+	db *sql.DB
+	// replica serves reads in read-only contexts; nil reads from db
+	replica *sqldb.Replica
+}
+
+// NewAuditLogRepository creates a new PostgreSQL audit log repository
+func NewAuditLogRepository(db *sql.DB, replica *sqldb.Replica) *AuditLogRepository {
+	return &AuditLogRepository{db: db, replica: replica}
+}
+
+// LogAction appends an audit event to its partner's hash chain, in the
+// caller's unit of work if there is one. Appends to a chain are serialized
+// by a transaction-level advisory lock on it, held until the entry commits,
+// so they never race for the same previous hash; a failed insert would
+// abort the caller's transaction, with no way to retry it. Nil partner,
+// resource and request IDs are stored as NULL, and a missing IP address as
+// 0.0.0.0, since the column is required.
+func (r *AuditLogRepository) LogAction(ctx context.Context, action ports.AuditAction) error {
+	if action.IPAddress == "" {
+		action.IPAddress = "0.0.0.0"
+	}
+	entry := &ports.AuditLogEntry{
+		AuditAction: action,
+		CreatedAt:   ports.AuditChainTime(action.Time()),
+	}
+
+	var changesJSON []byte
+	if action.Changes != nil {
+		var err error
+		if changesJSON, err = json.Marshal(action.Changes); err != nil {
+			return fmt.Errorf("failed to encode audit changes: %w", err)
+		}
+	}
+
+	err := sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		return appendEntry(ctx, tx, entry, changesJSON)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log audit action: %w", err)
+	}
+	return nil
+}
+
+// appendEntry locks entry's chain for the rest of tx, links entry to the
+// latest entry of the chain and stores it
+func appendEntry(ctx context.Context, tx *sql.Tx, entry *ports.AuditLogEntry, changesJSON []byte) error {
+	if _, err := tx.ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, "audit_chain:"+entry.PartnerID.String(),
+	); err != nil {
+		return err
+	}
+
+	b := auditChainFilter(entry.PartnerID)
+	b.where("hash IS NOT NULL")
+	var last string
+	err := tx.QueryRowContext(ctx,
+		"SELECT hash FROM audit_logs"+b.clause()+" ORDER BY id DESC LIMIT 1", b.args...,
+	).Scan(&last)
+	switch {
+	case err == sql.ErrNoRows:
+		entry.PrevHash = ports.AuditChainGenesis(entry.PartnerID)
+	case err != nil:
+		return err
+	default:
+		entry.PrevHash = last
+	}
+
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	query := `
+		INSERT INTO audit_logs (
+			partner_id, action, resource_type, resource_id, ip_address,
+			user_agent, request_id, changes, created_at, prev_hash, hash
+		) VALUES (
+			$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11
+		)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		nullUUID(entry.PartnerID),
+		entry.Action,
+		entry.ResourceType,
+		nullUUID(entry.ResourceID),
+		entry.IPAddress,
+		entry.UserAgent,
+		nullUUID(entry.RequestID),
+		changesJSON,
+		entry.CreatedAt,
+		entry.PrevHash,
+		entry.Hash,
+	)
+	return err
+}
+
+// List retrieves the entries matching q, newest first
+func (r *AuditLogRepository) List(ctx context.Context, q ports.AuditLogQuery) ([]*ports.AuditLogEntry, int64, error) {
+	b := &sqlBuilder{}
+	if q.PartnerID != nil {
+		b.where("partner_id = %s", *q.PartnerID)
+	}
+	if q.Action != "" {
+		b.where("action = %s", q.Action)
+	}
+	if q.ResourceType != "" {
+		b.where("resource_type = %s", q.ResourceType)
+	}
+	if q.ResourceID != nil {
+		b.where("resource_id = %s", *q.ResourceID)
+	}
+	if q.CreatedFrom != nil {
+		b.where("created_at >= %s", *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		b.where("created_at < %s", *q.CreatedTo)
+	}
+
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM audit_logs" + b.clause()
+	if err := sqldb.ReadConn(ctx, r.db, r.replica).QueryRowContext(ctx, countQuery, b.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	query := "SELECT " + auditLogColumns + " FROM audit_logs" + b.clause() +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT %s OFFSET %s", b.arg(q.Limit), b.arg(q.Offset))
+	entries, err := r.query(ctx, query, b.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ListChain retrieves entries of a partner's chain, oldest first
+func (r *AuditLogRepository) ListChain(ctx context.Context, partnerID uuid.UUID, afterID int64, limit int) ([]*ports.AuditLogEntry, error) {
+	b := auditChainFilter(partnerID)
+	b.where("id > %s", afterID)
+	query := "SELECT " + auditLogColumns + " FROM audit_logs" + b.clause() +
+		fmt.Sprintf(" ORDER BY id ASC LIMIT %s", b.arg(limit))
+	return r.query(ctx, query, b.args...)
+}
+
+// ListChainPartnerIDs returns the partners that have audit entries
+func (r *AuditLogRepository) ListChainPartnerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx,
+		"SELECT DISTINCT partner_id FROM audit_logs ORDER BY partner_id",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit chains: %w", err)
+	}
+	defer rows.Close()
+
+	var partnerIDs []uuid.UUID
+	for rows.Next() {
+		var partnerID uuid.NullUUID
+		if err := rows.Scan(&partnerID); err != nil {
+			return nil, fmt.Errorf("failed to scan audit chain: %w", err)
+		}
+		partnerIDs = append(partnerIDs, partnerID.UUID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit chains: %w", err)
+	}
+
+	return partnerIDs, nil
+}
+
+// ListArchivable retrieves entries logged before before, lowest ID first,
+// leaving out the latest entry of every chain
+func (r *AuditLogRepository) ListArchivable(ctx context.Context, before time.Time, limit int) ([]*ports.AuditLogEntry, error) {
+	b := &sqlBuilder{}
+	b.where("created_at < %s", before)
+	b.where("id NOT IN (SELECT MAX(id) FROM audit_logs GROUP BY partner_id)")
+	query := "SELECT " + auditLogColumns + " FROM audit_logs" + b.clause() +
+		fmt.Sprintf(" ORDER BY id ASC LIMIT %s", b.arg(limit))
+	return r.query(ctx, query, b.args...)
+}
+
+// Delete removes entries by ID
+func (r *AuditLogRepository) Delete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	b := &sqlBuilder{}
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		placeholders[i] = b.arg(id)
+	}
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx,
+		"DELETE FROM audit_logs WHERE id IN ("+strings.Join(placeholders, ", ")+")", b.args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete audit logs: %w", err)
+	}
+	return nil
+}
+
+// query runs a SELECT of auditLogColumns
+func (r *AuditLogRepository) query(ctx context.Context, query string, args ...interface{}) ([]*ports.AuditLogEntry, error) {
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*ports.AuditLogEntry
+	for rows.Next() {
+		entry, err := scanAuditLogEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	return entries, nil
+}
+
+// scanAuditLogEntry scans a row of auditLogColumns
+func scanAuditLogEntry(row sqldb.RowScanner) (*ports.AuditLogEntry, error) {
+	var entry ports.AuditLogEntry
+	var partnerID, resourceID, requestID uuid.NullUUID
+	var changesJSON []byte
+	var prevHash, hash sql.NullString
+	if err := row.Scan(
+		&entry.ID,
+		&partnerID,
+		&entry.Action,
+		&entry.ResourceType,
+		&resourceID,
+		&entry.IPAddress,
+		&entry.UserAgent,
+		&requestID,
+		&changesJSON,
+		&entry.CreatedAt,
+		&prevHash,
+		&hash,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan audit log: %w", err)
+	}
+
+	entry.PartnerID = partnerID.UUID
+	entry.ResourceID = resourceID.UUID
+	entry.RequestID = requestID.UUID
+	entry.PrevHash = prevHash.String
+	entry.Hash = hash.String
+	if changesJSON != nil {
+		if err := json.Unmarshal(changesJSON, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode audit changes: %w", err)
+		}
+	}
+	return &entry, nil
+}
+
+// auditChainFilter selects the entries of a partner's chain
+func auditChainFilter(partnerID uuid.UUID) *sqlBuilder {
+	b := &sqlBuilder{}
+	if partnerID == uuid.Nil {
+		b.where("partner_id IS NULL")
+	} else {
+		b.where("partner_id = %s", partnerID)
+	}
+	return b
+}
+
+// nullUUID stores uuid.Nil as NULL
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+)
+
+// maxParams is the most bind parameters PostgreSQL accepts in one statement
+const maxParams = 65535
+
+// insertRows runs insert, which ends in VALUES, for rows of equal length. It
+// takes as few multi-row statements as maxParams allows; when it takes more
+// than one, they share a database transaction so all rows are inserted or none.
+func insertRows(ctx context.Context, db *sql.DB, insert string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	perStatement := maxParams / len(rows[0])
+	if len(rows) <= perStatement {
+		return execInsert(ctx, sqldb.Conn(ctx, db), insert, rows)
+	}
+
+	return sqldb.InTx(ctx, db, func(tx *sql.Tx) error {
+		for start := 0; start < len(rows); start += perStatement {
+			end := start + perStatement
+			if end > len(rows) {
+				end = len(rows)
+			}
+			if err := execInsert(ctx, tx, insert, rows[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// execInsert runs insert for rows in a single statement
+func execInsert(ctx context.Context, db sqldb.Querier, insert string, rows [][]interface{}) error {
+	columns := len(rows[0])
+	args := make([]interface{}, 0, len(rows)*columns)
+	var values strings.Builder
+	for i, row := range rows {
+		if i > 0 {
+			values.WriteString(", ")
+		}
+		values.WriteByte('(')
+		for j := range row {
+			if j > 0 {
+				values.WriteString(", ")
+			}
+			fmt.Fprintf(&values, "$%d", len(args)+j+1)
+		}
+		values.WriteByte(')')
+		args = append(args, row...)
+	}
+
+	_, err := db.ExecContext(ctx, insert+values.String(), args...)
+	return err
+}
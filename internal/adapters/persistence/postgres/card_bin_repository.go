@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// CardBINRepository implements ports.CardBINRepository for PostgreSQL
+type CardBINRepository struct {
+	db *sql.DB
+}
+
+// NewCardBINRepository creates a new PostgreSQL card BIN repository
+func NewCardBINRepository(db *sql.DB) *CardBINRepository {
+	return &CardBINRepository{db: db}
+}
+
+// Lookup returns the longest BIN range that is a prefix of bin
+func (r *CardBINRepository) Lookup(ctx context.Context, bin valueobjects.BIN) (*valueobjects.BINInfo, error) {
+	// Ranges are stored as 6 to 8 digit prefixes, so at most three candidates match
+	query := `
+		SELECT prefix, brand, issuer_country, issuer_name, funding
+		FROM card_bins
+		WHERE prefix IN ($1, $2, $3)
+		ORDER BY LENGTH(prefix) DESC
+		LIMIT 1
+	`
+
+	code := bin.String()
+	candidates := make([]string, 3)
+	for i := range candidates {
+		if length := 8 - i; length <= len(code) {
+			candidates[i] = code[:length]
+		}
+	}
+
+	var info valueobjects.BINInfo
+	var prefix, brand, country, funding string
+	err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, query, candidates[0], candidates[1], candidates[2]).Scan(
+		&prefix,
+		&brand,
+		&country,
+		&info.IssuerName,
+		&funding,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrCardBINNotFound
+		}
+		return nil, fmt.Errorf("failed to look up card BIN: %w", err)
+	}
+
+	// Reconstruct value objects
+	info.Prefix = valueobjects.BIN(prefix)
+	info.Brand, _ = valueobjects.NewCardBrand(brand)
+	info.Funding, _ = valueobjects.NewCardFunding(funding)
+	info.IssuerCountry, _ = valueobjects.NewCountry(country)
+
+	return &info, nil
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// partitionedTables are range-partitioned by the month of created_at
+var partitionedTables = []string{"transactions", "refunds"}
+
+// PartitionManager implements ports.PartitionManager with the
+// create_monthly_partition function of the schema
+type PartitionManager struct {
+	db *sql.DB
+}
+
+// NewPartitionManager creates a new partition manager
+func NewPartitionManager(db *sql.DB) *PartitionManager {
+	return &PartitionManager{db: db}
+}
+
+// EnsurePartitions creates the monthly partitions, in UTC, that do not exist
+// yet. Existing partitions are left alone, so it is safe to run repeatedly and
+// from several instances.
+func (m *PartitionManager) EnsurePartitions(ctx context.Context, now time.Time, monthsAhead int) ([]string, error) {
+	now = now.UTC()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var created []string
+	for i := 0; i <= monthsAhead; i++ {
+		month := first.AddDate(0, i, 0).Format("2006-01-02")
+		for _, table := range partitionedTables {
+			var name sql.NullString
+			err := m.db.QueryRowContext(ctx, `SELECT create_monthly_partition($1, $2::DATE)`, table, month).Scan(&name)
+			if err != nil {
+				return created, fmt.Errorf("failed to create %s partition for %s: %w", table, month, err)
+			}
+			if name.Valid {
+				created = append(created, name.String)
+			}
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+)
+
+// PaymentJobRepository implements ports.PaymentJobRepository for PostgreSQL
+type PaymentJobRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentJobRepository creates a new PostgreSQL payment job repository
+func NewPaymentJobRepository(db *sql.DB) *PaymentJobRepository {
+	return &PaymentJobRepository{db: db}
+}
+
+// Add queues a job in the caller's unit of work, if there is one
+func (r *PaymentJobRepository) Add(ctx context.Context, job *entities.PaymentJob) error {
+	query := `
+		INSERT INTO payment_jobs (
+			id, partner_id, transaction_id, attempts, next_attempt_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		job.ID,
+		job.PartnerID,
+		job.TransactionID,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add payment job: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue claims due jobs, oldest first. Rows another worker is claiming
+// are skipped rather than waited for.
+func (r *PaymentJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.PaymentJob, error) {
+	b := &sqlBuilder{}
+	b.where("completed_at IS NULL")
+	b.where("next_attempt_at <= NOW()")
+	b.where("attempts < %s", entities.MaxPaymentJobAttempts)
+	query := `
+		SELECT id, partner_id, transaction_id, attempts, next_attempt_at, last_error,
+			   created_at, completed_at
+		FROM payment_jobs
+		` + b.clause() + `
+		ORDER BY created_at ASC
+		LIMIT ` + b.arg(limit) + `
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var jobs []*entities.PaymentJob
+	err := sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		jobs, err = r.query(ctx, tx, query, b.args...)
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		claim := &sqlBuilder{}
+		until := claim.arg(claimedUntil)
+		placeholders := make([]string, len(jobs))
+		for i, job := range jobs {
+			placeholders[i] = claim.arg(job.ID)
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE payment_jobs SET next_attempt_at = "+until+" WHERE id IN ("+strings.Join(placeholders, ", ")+")", claim.args...,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to claim payment jobs: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		job.NextAttemptAt = claimedUntil
+	}
+	return jobs, nil
+}
+
+// Update saves the outcome of an attempt
+func (r *PaymentJobRepository) Update(ctx context.Context, job *entities.PaymentJob) error {
+	query := `
+		UPDATE payment_jobs SET
+			attempts = $1,
+			next_attempt_at = $2,
+			last_error = NULLIF($3, ''),
+			completed_at = $4
+		WHERE id = $5
+	`
+	_, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.LastError,
+		job.CompletedAt,
+		job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payment job: %w", err)
+	}
+	return nil
+}
+
+// query runs a SELECT of payment jobs on db
+func (r *PaymentJobRepository) query(ctx context.Context, db sqldb.Querier, query string, args ...interface{}) ([]*entities.PaymentJob, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entities.PaymentJob
+	for rows.Next() {
+		var job entities.PaymentJob
+		var lastError sql.NullString
+		if err := rows.Scan(
+			&job.ID,
+			&job.PartnerID,
+			&job.TransactionID,
+			&job.Attempts,
+			&job.NextAttemptAt,
+			&lastError,
+			&job.CreatedAt,
+			&job.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payment job: %w", err)
+		}
+
+		job.LastError = lastError.String
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list payment jobs: %w", err)
+	}
+
+	return jobs, nil
+}
@@ -1,186 +1,437 @@
 package postgres
-package postgres
 
 import (
 	"context"
 	"database/sql"
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return total, nil	}		return 0, fmt.Errorf("failed to get total refunded amount: %w", err)	if err != nil {	err := r.db.QueryRowContext(ctx, query, transactionID).Scan(&total)	var total float64	`		  AND deleted_at IS NULL		  AND status = 'completed'		WHERE transaction_id = $1		FROM refunds		SELECT COALESCE(SUM(amount), 0)	query := `func (r *RefundRepository) GetTotalRefundedAmount(ctx context.Context, transactionID uuid.UUID) (float64, error) {// GetTotalRefundedAmount calculates total refunded amount for a transaction}	return nil	}		return fmt.Errorf("failed to update refund: %w", err)	if err != nil {	)		refund.ID,		refund.ProcessedAt,		refund.UpdatedAt,		refund.ErrorMessage,		refund.ErrorCode,		refund.ProviderRefundID,		string(refund.Status),	_, err := r.db.ExecContext(ctx, query,	`		WHERE id = $7			processed_at = $6			updated_at = $5,			error_message = $4,			error_code = $3,			provider_refund_id = $2,			status = $1,		UPDATE refunds SET	query := `func (r *RefundRepository) Update(ctx context.Context, refund *entities.Refund) error {// Update updates an existing refund}	return refunds, nil	}		refunds = append(refunds, refund)		}			return nil, err		if err != nil {		refund, err := r.GetByID(ctx, id)		}			return nil, err		if err := rows.Scan(&id); err != nil {		var id uuid.UUID	for rows.Next() {	var refunds []*entities.Refund	defer rows.Close()	}		return nil, fmt.Errorf("failed to list refunds: %w", err)	if err != nil {	rows, err := r.db.QueryContext(ctx, query, transactionID)	`		ORDER BY created_at DESC		WHERE transaction_id = $1 AND deleted_at IS NULL		SELECT id FROM refunds	query := `func (r *RefundRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error) {// GetByTransactionID retrieves all refunds for a transaction}	return &refund, nil	}		refund.ProviderRefundID = providerRefundID.String	if providerRefundID.Valid {	refund.Status = entities.RefundStatus(status)	refund.Amount = money	money, _ := valueobjects.NewMoney(amount, currency)	// Reconstruct value objects	}		return nil, fmt.Errorf("failed to get refund: %w", err)		}			return nil, errors.NewDomainError("REFUND_NOT_FOUND", "refund not found", nil)		if err == sql.ErrNoRows {	if err != nil {	)		&refund.ProcessedAt,		&refund.UpdatedAt,		&refund.CreatedAt,		&refund.ErrorMessage,		&refund.ErrorCode,		&providerRefundID,		&status,		&refund.Reason,		&currency,		&amount,		&refund.TransactionID,		&refund.ID,	err := r.db.QueryRowContext(ctx, query, id).Scan(	var providerRefundID sql.NullString	var status string	var currency string	var amount float64	var refund entities.Refund	`		WHERE id = $1 AND deleted_at IS NULL		FROM refunds			   created_at, updated_at, processed_at			   provider_refund_id, error_code, error_message,		SELECT id, transaction_id, amount, currency, reason, status,	query := `func (r *RefundRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Refund, error) {// GetByID retrieves a refund by ID}	return nil	}		return fmt.Errorf("failed to create refund: %w", err)	if err != nil {	)		refund.UpdatedAt,		refund.CreatedAt,		string(refund.Status),		refund.Reason,		refund.Amount.Currency.String(),		refund.Amount.Amount,		refund.TransactionID,		refund.ID,	_, err := r.db.ExecContext(ctx, query,	`		)			$1, $2, $3, $4, $5, $6, $7, $8		) VALUES (			created_at, updated_at			id, transaction_id, amount, currency, reason, status,		INSERT INTO refunds (	query := `func (r *RefundRepository) Create(ctx context.Context, refund *entities.Refund) error {// Create creates a new refund}	return &RefundRepository{db: db}func NewRefundRepository(db *sql.DB) *RefundRepository {// NewRefundRepository creates a new PostgreSQL refund repository}	db *sql.DBtype RefundRepository struct {// RefundRepository implements ports.RefundRepository for PostgreSQL)	"Pay2Go/internal/domain/valueobjects"	"Pay2Go/internal/domain/errors"	"Pay2Go/internal/domain/entities"	"github.com/google/uuid"	"fmt"
\ No newline at end of file
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RefundRepository implements ports.RefundRepository for PostgreSQL
+type RefundRepository struct {
+	db *sql.DB
+	// replica serves reads in read-only contexts; nil reads from db
+	replica *sqldb.Replica
+}
+
+// NewRefundRepository creates a new PostgreSQL refund repository
+func NewRefundRepository(db *sql.DB, replica *sqldb.Replica) *RefundRepository {
+	return &RefundRepository{db: db, replica: replica}
+}
+
+// Create creates a new refund
+func (r *RefundRepository) Create(ctx context.Context, refund *entities.Refund) error {
+	return r.insert(ctx, sqldb.Conn(ctx, r.db), refund)
+}
+
+// Reserve creates a refund and adds its amount to the transaction's refunded
+// total in one database transaction. The conditional UPDATE takes a row lock,
+// so concurrent refunds are serialized and can never exceed the original amount.
+func (r *RefundRepository) Reserve(ctx context.Context, refund *entities.Refund) error {
+	return sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE transactions
+			SET refunded_amount = refunded_amount + $1
+			WHERE id = $2 AND refunded_amount + $1 <= amount AND deleted_at IS NULL
+		`, refund.Amount, refund.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to reserve refund amount: %w", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to reserve refund amount: %w", err)
+		}
+		if rows == 0 {
+			return errors.ErrRefundAmountExceeded
+		}
+
+		return r.insert(ctx, tx, refund)
+	})
+}
+
+// Release saves a refund that will never complete and returns its amount to
+// the transaction's refundable balance in one database transaction
+func (r *RefundRepository) Release(ctx context.Context, refund *entities.Refund) error {
+	return sqldb.InTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := r.update(ctx, tx, refund); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE transactions
+			SET refunded_amount = GREATEST(refunded_amount - $1, 0)
+			WHERE id = $2
+		`, refund.Amount, refund.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to release refund amount: %w", err)
+		}
+		return nil
+	})
+}
+
+// refundInsert is the INSERT of insert and CreateBatch, to be followed by a
+// row of refundValues per refund
+const refundInsert = `
+		INSERT INTO refunds (
+			id, transaction_id, amount, currency, reason_code, reason, status,
+			created_at, updated_at
+		) VALUES `
+
+// insert writes a new refund row using the given executor
+func (r *RefundRepository) insert(ctx context.Context, db sqldb.Querier, refund *entities.Refund) error {
+	if err := execInsert(ctx, db, refundInsert, [][]interface{}{refundValues(refund)}); err != nil {
+		return fmt.Errorf("failed to create refund: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch creates refunds with multi-row INSERTs
+func (r *RefundRepository) CreateBatch(ctx context.Context, refunds []*entities.Refund) error {
+	rows := make([][]interface{}, len(refunds))
+	for i, refund := range refunds {
+		rows[i] = refundValues(refund)
+	}
+
+	if err := insertRows(ctx, r.db, refundInsert, rows); err != nil {
+		return fmt.Errorf("failed to create refunds: %w", err)
+	}
+	return nil
+}
+
+// refundValues returns the columns of refundInsert for refund, whose
+// CreatedAt is first truncated to the microseconds Postgres stores, since
+// updates find the partition of a refund by it
+func refundValues(refund *entities.Refund) []interface{} {
+	refund.CreatedAt = refund.CreatedAt.Truncate(time.Microsecond)
+
+	return []interface{}{
+		refund.ID,
+		refund.TransactionID,
+		refund.Amount,
+		refund.Amount.Currency,
+		string(refund.Reason.Code),
+		refund.Reason.Note,
+		string(refund.Status),
+		refund.CreatedAt,
+		refund.UpdatedAt,
+	}
+}
+
+// GetByID retrieves a refund by ID
+func (r *RefundRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Refund, error) {
+	query := `
+		SELECT id, transaction_id, amount, currency, reason_code, reason, status,
+			   provider_refund_id, COALESCE(error_code, ''), COALESCE(error_message, ''),
+			   approved_by, approved_at,
+			   created_at, updated_at, processed_at, cancelled_at, version
+		FROM refunds
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	var refund entities.Refund
+	var status string
+	var reasonCode string
+	var reasonNote sql.NullString
+	var providerRefundID sql.NullString
+	var approvedBy sql.NullString
+	err := sqldb.ReadConn(ctx, r.db, r.replica).QueryRowContext(ctx, query, id).Scan(
+		&refund.ID,
+		&refund.TransactionID,
+		&refund.Amount,
+		&refund.Amount.Currency,
+		&reasonCode,
+		&reasonNote,
+		&status,
+		&providerRefundID,
+		&refund.ErrorCode,
+		&refund.ErrorMessage,
+		&approvedBy,
+		&refund.ApprovedAt,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+		&refund.ProcessedAt,
+		&refund.CancelledAt,
+		&refund.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrRefundNotFound
+		}
+		return nil, fmt.Errorf("failed to get refund: %w", err)
+	}
+
+	// Reconstruct value objects
+	refund.Reason = valueobjects.RefundReason{
+		Code: valueobjects.RefundReasonCode(reasonCode),
+		Note: reasonNote.String,
+	}
+	refund.Status = entities.RefundStatus(status)
+	if providerRefundID.Valid {
+		refund.ProviderRefundID = providerRefundID.String
+	}
+	if approvedBy.Valid {
+		refund.ApprovedBy = approvedBy.String
+	}
+	return &refund, nil
+}
+
+// GetByTransactionID retrieves all refunds for a transaction
+func (r *RefundRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error) {
+	query := `
+		SELECT id FROM refunds
+		WHERE transaction_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %w", err)
+	}
+	defer rows.Close()
+	var refunds []*entities.Refund
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		refund, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, nil
+}
+
+// Update updates an existing refund if it is still at refund.Version, and
+// moves refund to the next version. A refund changed since it was loaded is
+// left alone and a *errors.VersionConflictError returned.
+func (r *RefundRepository) Update(ctx context.Context, refund *entities.Refund) error {
+	return r.update(ctx, sqldb.Conn(ctx, r.db), refund)
+}
+
+// update writes refund state using the given executor
+func (r *RefundRepository) update(ctx context.Context, db sqldb.Querier, refund *entities.Refund) error {
+	query := `
+		UPDATE refunds SET
+			status = $1,
+			provider_refund_id = $2,
+			error_code = $3,
+			error_message = $4,
+			approved_by = $5,
+			approved_at = $6,
+			updated_at = $7,
+			processed_at = $8,
+			cancelled_at = $9,
+			version = version + 1
+		WHERE id = $10 AND created_at = $11 AND version = $12 AND deleted_at IS NULL
+	`
+	result, err := db.ExecContext(ctx, query,
+		string(refund.Status),
+		refund.ProviderRefundID,
+		refund.ErrorCode,
+		refund.ErrorMessage,
+		sql.NullString{String: refund.ApprovedBy, Valid: refund.ApprovedBy != ""},
+		refund.ApprovedAt,
+		refund.UpdatedAt,
+		refund.ProcessedAt,
+		refund.CancelledAt,
+		refund.ID,
+		refund.CreatedAt,
+		refund.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "refund", refund.ID, refund.Version); err != nil {
+		return err
+	}
+	refund.Version++
+	return nil
+}
+
+// Delete saves refund's DeletedAt if it is still at refund.Version, and moves
+// refund to the next version
+func (r *RefundRepository) Delete(ctx context.Context, refund *entities.Refund) error {
+	query := `
+		UPDATE refunds SET
+			deleted_at = $1,
+			updated_at = $2,
+			version = version + 1
+		WHERE id = $3 AND created_at = $4 AND version = $5 AND deleted_at IS NULL
+	`
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, refund.DeletedAt, refund.UpdatedAt, refund.ID, refund.CreatedAt, refund.Version)
+	if err != nil {
+		return fmt.Errorf("failed to delete refund: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "refund", refund.ID, refund.Version); err != nil {
+		return err
+	}
+	refund.Version++
+	return nil
+}
+
+// Restore clears the DeletedAt of a deleted refund
+func (r *RefundRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE refunds SET
+			deleted_at = NULL,
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore refund: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to restore refund: %w", err)
+	}
+	if rows == 0 {
+		return errors.ErrRefundNotFound
+	}
+	return nil
+}
+
+// GetTotalRefundedAmount calculates total refunded amount for a transaction
+func (r *RefundRepository) GetTotalRefundedAmount(ctx context.Context, transactionID uuid.UUID) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM refunds
+		WHERE transaction_id = $1
+		  AND status = 'completed'
+		  AND deleted_at IS NULL
+	`
+	var total int64
+	err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, query, transactionID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total refunded amount: %w", err)
+	}
+	return total, nil
+}
+
+// GetReasonSummary aggregates a partner's completed refunds by reason code
+func (r *RefundRepository) GetReasonSummary(ctx context.Context, filter ports.RefundReportFilter) ([]ports.RefundReasonSummary, error) {
+	query := `
+		SELECT r.reason_code, r.currency, COUNT(*), COALESCE(SUM(r.amount), 0)
+		FROM refunds r
+		JOIN transactions t ON t.id = r.transaction_id
+		WHERE t.partner_id = $1
+		  AND t.livemode = $2
+		  AND r.status = 'completed'
+		  AND r.deleted_at IS NULL
+		  AND t.deleted_at IS NULL
+	`
+	args := []interface{}{filter.PartnerID, filter.Livemode}
+	argPos := 3
+	if filter.DateFrom != nil {
+		query += fmt.Sprintf(" AND r.created_at >= $%d::date", argPos)
+		args = append(args, *filter.DateFrom)
+		argPos++
+	}
+	if filter.DateTo != nil {
+		query += fmt.Sprintf(" AND r.created_at < $%d::date + INTERVAL '1 day'", argPos)
+		args = append(args, *filter.DateTo)
+	}
+	query += " GROUP BY r.reason_code, r.currency ORDER BY r.reason_code, r.currency"
+
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize refunds: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ports.RefundReasonSummary
+	for rows.Next() {
+		var summary ports.RefundReasonSummary
+		var reasonCode string
+		if err := rows.Scan(&reasonCode, &summary.Total.Currency, &summary.Count, &summary.Total); err != nil {
+			return nil, err
+		}
+		summary.ReasonCode = valueobjects.RefundReasonCode(reasonCode)
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// List returns a partner's refunds matching query, newest first
+func (r *RefundRepository) List(ctx context.Context, query ports.RefundQuery) ([]*entities.Refund, error) {
+	b := &sqlBuilder{}
+	b.where("t.partner_id = %s", query.PartnerID)
+	b.where("t.livemode = %s", query.Livemode)
+	b.where("r.deleted_at IS NULL")
+	b.where("t.deleted_at IS NULL")
+	if query.TransactionID != nil {
+		b.where("r.transaction_id = %s", *query.TransactionID)
+	}
+	if query.Status != nil {
+		b.where("r.status = %s", string(*query.Status))
+	}
+	if query.CreatedFrom != nil {
+		b.where("r.created_at >= %s", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		b.where("r.created_at < %s", *query.CreatedTo)
+	}
+	statement := "SELECT r.id FROM refunds r JOIN transactions t ON t.id = r.transaction_id" +
+		b.clause() + " ORDER BY r.created_at DESC, r.id DESC"
+	statement += " LIMIT " + b.arg(query.Limit) + " OFFSET " + b.arg(query.Offset)
+
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, statement, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*entities.Refund, 0, len(ids))
+	for _, id := range ids {
+		refund, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, nil
+}
+
+// AnonymizeCustomerData clears the reason notes of all of a partner's refunds
+func (r *RefundRepository) AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	query := `
+		UPDATE refunds SET
+			reason = '',
+			version = version + 1,
+			updated_at = NOW()
+		WHERE transaction_id IN (SELECT id FROM transactions WHERE partner_id = $1)
+		  AND reason <> ''
+	`
+
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, partnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize refunds: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,11 @@
+package postgres
+
+// ReplicaLagQuery returns how many seconds a replica is behind: the age of
+// the last transaction it replayed, or 0 once it has replayed all WAL it
+// received, so an idle primary does not look like lag
+const ReplicaLagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+	END
+`
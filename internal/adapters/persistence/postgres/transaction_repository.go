@@ -1,289 +1,548 @@
-package postgres
 // Package postgres implements repository interfaces using PostgreSQL
 package postgres
 
 import (
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return txns, err	txns, _, err := r.List(ctx, filter)	}		Offset: offset,		Limit:  limit,		Status: &status,	filter := ports.TransactionFilter{func (r *TransactionRepository) GetByStatus(ctx context.Context, status entities.TransactionStatus, limit, offset int) ([]*entities.Transaction, error) {// GetByStatus retrieves transactions by status}	return txns, err	txns, _, err := r.List(ctx, filter)	}		Offset:    offset,		Limit:     limit,		PartnerID: &partnerID,	filter := ports.TransactionFilter{func (r *TransactionRepository) GetByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Transaction, error) {// GetByPartnerID retrieves transactions for a specific partner}	return transactions, total, nil	_ = r.db.QueryRowContext(ctx, countQuery).Scan(&total)	var total int64	countQuery := "SELECT COUNT(*) FROM transactions WHERE deleted_at IS NULL"	// Get total count	}		transactions[i] = txn		}			return nil, 0, err		if err != nil {		txn, err := r.GetByID(ctx, id)	for i, id := range ids {	transactions := make([]*entities.Transaction, len(ids))	// Get full transaction details	}		ids = append(ids, id)		}			return nil, 0, err		if err := rows.Scan(&id); err != nil {		var id uuid.UUID	for rows.Next() {	var ids []uuid.UUID	defer rows.Close()	}		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)	if err != nil {	rows, err := r.db.QueryContext(ctx, query, args...)	args = append(args, filter.Limit, filter.Offset)	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argPos, argPos+1)	query += " ORDER BY created_at DESC"	}		argPos++		args = append(args, string(*filter.Status))		query += fmt.Sprintf(" AND status = $%d", argPos)	if filter.Status != nil {	}		argPos++		args = append(args, *filter.PartnerID)		query += fmt.Sprintf(" AND partner_id = $%d", argPos)	if filter.PartnerID != nil {	argPos := 1	args := []interface{}{}	`		WHERE deleted_at IS NULL		SELECT id FROM transactions	query := `	// Build query dynamically based on filterfunc (r *TransactionRepository) List(ctx context.Context, filter ports.TransactionFilter) ([]*entities.Transaction, int64, error) {// List retrieves transactions with pagination}	return nil	}		return fmt.Errorf("failed to update transaction: %w", err)	if err != nil {	)		txn.ID,		txn.FailedAt,		txn.ProcessedAt,		txn.UpdatedAt,		txn.RetryCount,		txn.ErrorMessage,		txn.ErrorCode,		txn.ProviderTransactionID,		string(txn.Status),	_, err := r.db.ExecContext(ctx, query,	`		WHERE id = $9			failed_at = $8			processed_at = $7,			updated_at = $6,			retry_count = $5,			error_message = $4,			error_code = $3,			provider_transaction_id = $2,			status = $1,		UPDATE transactions SET	query := `func (r *TransactionRepository) Update(ctx context.Context, txn *entities.Transaction) error {// Update updates an existing transaction}	return r.GetByID(ctx, id)	}		return nil, fmt.Errorf("failed to check idempotency: %w", err)		}			return nil, nil // Not found, not an error		if err == sql.ErrNoRows {	if err != nil {	err := r.db.QueryRowContext(ctx, query, partnerID, idempotencyKey).Scan(&id)	var id uuid.UUID	`		WHERE partner_id = $1 AND idempotency_key = $2 AND deleted_at IS NULL		SELECT id FROM transactions	query := `func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, partnerID uuid.UUID, idempotencyKey string) (*entities.Transaction, error) {// GetByIdempotencyKey retrieves a transaction by partner and idempotency key}	return &txn, nil	}		json.Unmarshal(metadataJSON, &txn.Metadata)	if len(metadataJSON) > 0 {	}		txn.ProviderTransactionID = providerTxnID.String	if providerTxnID.Valid {	txn.Status = entities.TransactionStatus(status)	txn.Provider, _ = valueobjects.NewPaymentProvider(provider)	txn.PaymentMethod, _ = valueobjects.NewPaymentMethod(paymentMethod)	txn.Amount = money	money, _ := valueobjects.NewMoney(amount, currency)	// Reconstruct value objects	}		return nil, fmt.Errorf("failed to get transaction: %w", err)		}			return nil, errors.ErrTransactionNotFound		if err == sql.ErrNoRows {	if err != nil {	)		&txn.FailedAt,		&txn.ProcessedAt,		&txn.UpdatedAt,		&txn.CreatedAt,		&txn.RetryCount,		&txn.ErrorMessage,		&txn.ErrorCode,		&txn.RequestID,		&txn.UserAgent,		&txn.IPAddress,		&metadataJSON,		&txn.Description,		&txn.CustomerPhone,		&txn.CustomerName,		&txn.CustomerEmail,		&status,		&providerTxnID,		&provider,		&paymentMethod,		&currency,		&amount,		&txn.IdempotencyKey,		&txn.PartnerID,		&txn.ID,	err := r.db.QueryRowContext(ctx, query, id).Scan(	var providerTxnID sql.NullString	var status string	var provider string	var paymentMethod string	var currency string	var amount float64	var metadataJSON []byte	var txn entities.Transaction	`		WHERE id = $1 AND deleted_at IS NULL		FROM transactions			   processed_at, failed_at			   error_message, retry_count, created_at, updated_at,			   metadata, ip_address, user_agent, request_id, error_code,			   customer_email, customer_name, customer_phone, description,			   payment_method, provider, provider_transaction_id, status,		SELECT id, partner_id, idempotency_key, amount, currency,	query := `func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {// GetByID retrieves a transaction by ID}	return nil	}		return fmt.Errorf("failed to create transaction: %w", err)		}			}				return errors.ErrDuplicateTransaction			if pqErr.Code == "23505" { // Unique violation		if pqErr, ok := err.(*pq.Error); ok {	if err != nil {	)		txn.UpdatedAt,		txn.CreatedAt,		txn.RetryCount,		txn.RequestID,		txn.UserAgent,		txn.IPAddress,		metadataJSON,		txn.Description,		txn.CustomerPhone,		txn.CustomerName,		txn.CustomerEmail,		string(txn.Status),		txn.Provider.String(),		txn.PaymentMethod.String(),		txn.Amount.Currency.String(),		txn.Amount.Amount,		txn.IdempotencyKey,		txn.PartnerID,		txn.ID,	_, err := r.db.ExecContext(ctx, query,	metadataJSON, _ := json.Marshal(txn.Metadata)	`		)			$14, $15, $16, $17, $18, $19			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,		) VALUES (			created_at, updated_at			ip_address, user_agent, request_id, retry_count,			customer_name, customer_phone, description, metadata,			payment_method, provider, status, customer_email,			id, partner_id, idempotency_key, amount, currency,		INSERT INTO transactions (	query := `func (r *TransactionRepository) Create(ctx context.Context, txn *entities.Transaction) error {// Create creates a new transaction}	return &TransactionRepository{db: db}func NewTransactionRepository(db *sql.DB) *TransactionRepository {// NewTransactionRepository creates a new PostgreSQL transaction repository}	db *sql.DBtype TransactionRepository struct {// TransactionRepository implements ports.TransactionRepository for PostgreSQL)	"Pay2Go/internal/usecases/ports"	"Pay2Go/internal/domain/valueobjects"	"Pay2Go/internal/domain/errors"	"Pay2Go/internal/domain/entities"	"github.com/lib/pq"	"github.com/google/uuid"	"fmt"	"encoding/json"	"database/sql"	"context"
\ No newline at end of file
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// TransactionRepository implements ports.TransactionRepository for PostgreSQL
+type TransactionRepository struct {
+	db *sql.DB
+	// replica serves reads in read-only contexts; nil reads from db
+	replica *sqldb.Replica
+	stmts   *sqldb.Statements
+}
+
+// NewTransactionRepository creates a new PostgreSQL transaction repository
+func NewTransactionRepository(db *sql.DB, replica *sqldb.Replica) *TransactionRepository {
+	return &TransactionRepository{db: db, replica: replica, stmts: sqldb.NewStatements(db)}
+}
+
+// PrepareStatements prepares the queries of GetByID, GetByIdempotencyKey
+// and Update, the busiest of payment processing
+func (r *TransactionRepository) PrepareStatements(ctx context.Context) error {
+	return r.stmts.Prepare(ctx,
+		transactionQuery("id = $1"),
+		transactionQuery("id = $1 AND created_at = $2"),
+		transactionIdempotencyQuery,
+		transactionUpdate,
+	)
+}
+
+// transactionInsert is the INSERT of Create and CreateBatch, to be followed
+// by a row of transactionValues per transaction
+const transactionInsert = `
+		INSERT INTO transactions (
+			id, partner_id, idempotency_key, amount, currency,
+			payment_method, provider, status, customer_email,
+			customer_name, customer_phone, description, metadata,
+			ip_address, user_agent, request_id, retry_count,
+			livemode, created_at, updated_at, payment_method_details,
+			billing_country
+		) VALUES `
+
+// Create creates a new transaction
+func (r *TransactionRepository) Create(ctx context.Context, txn *entities.Transaction) error {
+	err := insertRows(ctx, r.db, transactionInsert, [][]interface{}{transactionValues(txn)})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" { // Unique violation
+				return errors.ErrDuplicateTransaction
+			}
+		}
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch creates transactions with multi-row INSERTs
+func (r *TransactionRepository) CreateBatch(ctx context.Context, txns []*entities.Transaction) error {
+	rows := make([][]interface{}, len(txns))
+	for i, txn := range txns {
+		rows[i] = transactionValues(txn)
+	}
+
+	err := insertRows(ctx, r.db, transactionInsert, rows)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" { // Unique violation
+				return errors.ErrDuplicateTransaction
+			}
+		}
+		return fmt.Errorf("failed to create transactions: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a transaction by ID
+func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+	return r.getOne(ctx, "id = $1", id)
+}
+
+// transactionColumns are the columns scanTransaction reads
+const transactionColumns = `id, partner_id, idempotency_key, amount, currency,
+			   payment_method, provider, provider_transaction_id, status,
+			   customer_email, customer_name, customer_phone, description,
+			   metadata, ip_address, user_agent, request_id, COALESCE(error_code, ''),
+			   COALESCE(error_message, ''), retry_count, created_at, updated_at,
+			   processed_at, failed_at, refunded_amount, livemode,
+			   provider_credential_id, payment_method_details, billing_country,
+			   version`
+
+// transactionQuery selects the transaction, not deleted, matching where.
+// Naming created_at in where lets Postgres read a single monthly partition.
+func transactionQuery(where string) string {
+	return `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE ` + where + ` AND deleted_at IS NULL
+	`
+}
+
+// getOne retrieves the transaction, not deleted, matching where
+func (r *TransactionRepository) getOne(ctx context.Context, where string, args ...interface{}) (*entities.Transaction, error) {
+	txn, err := scanTransaction(r.stmts.ReadConn(ctx, r.replica).QueryRowContext(ctx, transactionQuery(where), args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return txn, nil
+}
+
+// scanTransaction reads a row of transactionColumns, returning the row's
+// error, such as sql.ErrNoRows, as is
+func scanTransaction(row sqldb.RowScanner) (*entities.Transaction, error) {
+	var txn entities.Transaction
+	var metadataJSON, detailsJSON []byte
+	var paymentMethod string
+	var provider string
+	var status string
+	var providerTxnID, billingCountry sql.NullString
+	err := row.Scan(
+		&txn.ID,
+		&txn.PartnerID,
+		&txn.IdempotencyKey,
+		&txn.Amount,
+		&txn.Amount.Currency,
+		&paymentMethod,
+		&provider,
+		&providerTxnID,
+		&status,
+		&txn.CustomerEmail,
+		&txn.CustomerName,
+		&txn.CustomerPhone,
+		&txn.Description,
+		&metadataJSON,
+		&txn.IPAddress,
+		&txn.UserAgent,
+		&txn.RequestID,
+		&txn.ErrorCode,
+		&txn.ErrorMessage,
+		&txn.RetryCount,
+		&txn.CreatedAt,
+		&txn.UpdatedAt,
+		&txn.ProcessedAt,
+		&txn.FailedAt,
+		&txn.RefundedAmount,
+		&txn.Livemode,
+		&txn.ProviderCredentialID,
+		&detailsJSON,
+		&billingCountry,
+		&txn.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reconstruct value objects
+	txn.RefundedAmount.Currency = txn.Amount.Currency
+	txn.PaymentMethod, _ = valueobjects.NewPaymentMethod(paymentMethod)
+	txn.Provider, _ = valueobjects.NewPaymentProvider(provider)
+	txn.Status = entities.TransactionStatus(status)
+	if providerTxnID.Valid {
+		txn.ProviderTransactionID = providerTxnID.String
+	}
+	if billingCountry.Valid {
+		txn.BillingCountry, _ = valueobjects.NewCountry(billingCountry.String)
+	}
+	if len(metadataJSON) > 0 {
+		json.Unmarshal(metadataJSON, &txn.Metadata)
+	}
+	if len(detailsJSON) > 0 {
+		json.Unmarshal(detailsJSON, &txn.PaymentMethodDetails)
+	}
+	return &txn, nil
+}
+
+// transactionIdempotencyQuery finds the transaction of an idempotency key
+const transactionIdempotencyQuery = `
+		SELECT transaction_id, transaction_created_at FROM transaction_idempotency_keys
+		WHERE partner_id = $1 AND idempotency_key = $2
+	`
+
+// GetByIdempotencyKey retrieves a transaction by partner and idempotency key
+func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, partnerID uuid.UUID, idempotencyKey string) (*entities.Transaction, error) {
+	var id uuid.UUID
+	var createdAt time.Time
+	err := r.stmts.Conn(ctx).QueryRowContext(ctx, transactionIdempotencyQuery, partnerID, idempotencyKey).Scan(&id, &createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found, not an error
+		}
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	txn, err := r.getOne(ctx, "id = $1 AND created_at = $2", id, createdAt)
+	if err == errors.ErrTransactionNotFound {
+		return nil, nil // Deleted
+	}
+	return txn, err
+}
+
+// transactionUpdate saves a transaction at an expected version
+const transactionUpdate = `
+		UPDATE transactions SET
+			status = $1,
+			provider_transaction_id = $2,
+			error_code = $3,
+			error_message = $4,
+			retry_count = $5,
+			updated_at = $6,
+			processed_at = $7,
+			failed_at = $8,
+			provider_credential_id = $9,
+			payment_method_details = $10,
+			version = version + 1
+		WHERE id = $11 AND created_at = $12 AND version = $13 AND deleted_at IS NULL
+	`
+
+// Update updates an existing transaction if it is still at txn.Version, and
+// moves txn to the next version. A transaction changed since it was loaded is
+// left alone and a *errors.VersionConflictError returned.
+func (r *TransactionRepository) Update(ctx context.Context, txn *entities.Transaction) error {
+	result, err := r.stmts.Conn(ctx).ExecContext(ctx, transactionUpdate,
+		string(txn.Status),
+		txn.ProviderTransactionID,
+		txn.ErrorCode,
+		txn.ErrorMessage,
+		txn.RetryCount,
+		txn.UpdatedAt,
+		txn.ProcessedAt,
+		txn.FailedAt,
+		txn.ProviderCredentialID,
+		paymentMethodDetailsJSON(txn.PaymentMethodDetails),
+		txn.ID,
+		txn.CreatedAt,
+		txn.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "transaction", txn.ID, txn.Version); err != nil {
+		return err
+	}
+	txn.Version++
+	return nil
+}
+
+// Delete saves txn's DeletedAt if it is still at txn.Version, and moves txn
+// to the next version
+func (r *TransactionRepository) Delete(ctx context.Context, txn *entities.Transaction) error {
+	query := `
+		UPDATE transactions SET
+			deleted_at = $1,
+			updated_at = $2,
+			version = version + 1
+		WHERE id = $3 AND created_at = $4 AND version = $5 AND deleted_at IS NULL
+	`
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, txn.DeletedAt, txn.UpdatedAt, txn.ID, txn.CreatedAt, txn.Version)
+	if err != nil {
+		return fmt.Errorf("failed to delete transaction: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "transaction", txn.ID, txn.Version); err != nil {
+		return err
+	}
+	txn.Version++
+	return nil
+}
+
+// Restore clears the DeletedAt of a deleted transaction
+func (r *TransactionRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE transactions SET
+			deleted_at = NULL,
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore transaction: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to restore transaction: %w", err)
+	}
+	if rows == 0 {
+		return errors.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// IncrementRetryCount moves a failed transaction with retries left back to
+// pending, counting the retry in the same statement
+func (r *TransactionRepository) IncrementRetryCount(ctx context.Context, txn *entities.Transaction, maxRetries int) error {
+	query := `
+		UPDATE transactions SET
+			status = $1,
+			retry_count = retry_count + 1,
+			updated_at = $2,
+			version = version + 1
+		WHERE id = $3 AND created_at = $4 AND status = $5 AND retry_count < $6 AND deleted_at IS NULL
+		RETURNING retry_count, version
+	`
+	now := time.Now()
+	err := sqldb.Conn(ctx, r.db).QueryRowContext(ctx, query,
+		string(entities.StatusPending), now, txn.ID, txn.CreatedAt, string(entities.StatusFailed), maxRetries,
+	).Scan(&txn.RetryCount, &txn.Version)
+	if err == sql.ErrNoRows {
+		return errors.ErrRetryNotAllowed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to increment retry count: %w", err)
+	}
+
+	txn.Status = entities.StatusPending
+	txn.UpdatedAt = now
+	return nil
+}
+
+// AnonymizeCustomerData erases customer PII from all of a partner's transactions
+func (r *TransactionRepository) AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	// customer_email is NOT NULL and ip_address is read back as text, so both get placeholders.
+	// The paths removed from payment_method_details are the fields
+	// PaymentMethodDetails.Anonymized clears.
+	query := `
+		UPDATE transactions SET
+			customer_email = 'anonymized@invalid',
+			customer_name = '',
+			customer_phone = '',
+			provider_customer_id = NULL,
+			metadata = '{}',
+			ip_address = '0.0.0.0',
+			user_agent = '',
+			payment_method_details = payment_method_details #- '{card,fingerprint}' #- '{card,last4}' #- '{card,exp_month}' #- '{card,exp_year}' #- '{card,bin}' #- '{bank_account,last4}' #- '{wallet,account_id}',
+			version = version + 1,
+			updated_at = NOW()
+		WHERE partner_id = $1 AND customer_email <> 'anonymized@invalid'
+	`
+
+	result, err := sqldb.Conn(ctx, r.db).ExecContext(ctx, query, partnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize transactions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// processingSummaryQuery counts processed transactions by provider,
+// partner, outcome and latency bucket
+var processingSummaryQuery = `
+	SELECT provider, partner_id, processed_at IS NOT NULL, bucket, COUNT(*)
+	FROM (
+		SELECT provider, partner_id, processed_at,
+			   ` + sqldb.LatencyBucket("EXTRACT(EPOCH FROM COALESCE(processed_at, failed_at) - created_at)") + ` AS bucket
+		FROM transactions
+		WHERE created_at >= $1
+		  AND livemode = $2
+		  AND deleted_at IS NULL
+		  AND (processed_at IS NOT NULL OR failed_at IS NOT NULL)
+	) processed
+	GROUP BY provider, partner_id, processed_at IS NOT NULL, bucket
+`
+
+// GetProcessingSummary counts the processed transactions of a mode created
+// since since by provider, partner, outcome and latency
+func (r *TransactionRepository) GetProcessingSummary(ctx context.Context, since time.Time, livemode bool) ([]ports.ProcessingSummary, error) {
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, processingSummaryQuery, since, livemode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ports.ProcessingSummary
+	for rows.Next() {
+		var summary ports.ProcessingSummary
+		var provider string
+		if err := rows.Scan(&provider, &summary.PartnerID, &summary.Succeeded, &summary.LatencyBucket, &summary.Count); err != nil {
+			return nil, err
+		}
+		summary.Provider = valueobjects.PaymentProvider(provider)
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// List retrieves the transactions matching q, one page at a time
+func (r *TransactionRepository) List(ctx context.Context, q ports.TransactionQuery) ([]*entities.Transaction, int64, error) {
+	b, err := transactionFilter(q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The total ignores paging and the cursor
+	countQuery := "SELECT COUNT(*) FROM transactions" + b.clause()
+	countArgs := append([]interface{}(nil), b.args...)
+
+	page, err := transactionPage(b, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	query := "SELECT id, created_at FROM transactions" + b.clause() + page
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	defer rows.Close()
+	type transactionKey struct {
+		id        uuid.UUID
+		createdAt time.Time
+	}
+	var keys []transactionKey
+	for rows.Next() {
+		var key transactionKey
+		if err := rows.Scan(&key.id, &key.createdAt); err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, key)
+	}
+
+	// Get full transaction details, each from its own partition
+	transactions := make([]*entities.Transaction, len(keys))
+	for i, key := range keys {
+		txn, err := r.getOne(ctx, "id = $1 AND created_at = $2", key.id, key.createdAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		transactions[i] = txn
+	}
+
+	// Get total count (same filters, so test and live totals never mix)
+	var total int64
+	_ = sqldb.ReadConn(ctx, r.db, r.replica).QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	return transactions, total, nil
+}
+
+// Stream calls fn with each transaction matching q as its row is read
+func (r *TransactionRepository) Stream(ctx context.Context, q ports.TransactionQuery, fn func(*entities.Transaction) error) error {
+	b, err := transactionFilter(q)
+	if err != nil {
+		return err
+	}
+	order, err := transactionOrder(b, q)
+	if err != nil {
+		return err
+	}
+
+	query := "SELECT " + transactionColumns + " FROM transactions" + b.clause() + order
+	rows, err := sqldb.ReadConn(ctx, r.db, r.replica).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream transactions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		txn, err := scanTransaction(rows)
+		if err != nil {
+			return fmt.Errorf("failed to stream transactions: %w", err)
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream transactions: %w", err)
+	}
+	return nil
+}
+
+// GetByPartnerID retrieves transactions for a specific partner
+func (r *TransactionRepository) GetByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Transaction, error) {
+	q := ports.TransactionQuery{
+		PartnerID: &partnerID,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	txns, _, err := r.List(ctx, q)
+	return txns, err
+}
+
+// GetByStatus retrieves transactions by status
+func (r *TransactionRepository) GetByStatus(ctx context.Context, status entities.TransactionStatus, limit, offset int) ([]*entities.Transaction, error) {
+	q := ports.TransactionQuery{
+		Statuses: []entities.TransactionStatus{status},
+		Limit:    limit,
+		Offset:   offset,
+	}
+	txns, _, err := r.List(ctx, q)
+	return txns, err
+}
+
+// transactionValues returns the columns of transactionInsert for txn. Its
+// CreatedAt is first truncated to the microseconds Postgres stores, since
+// updates find the partition of a transaction by it.
+func transactionValues(txn *entities.Transaction) []interface{} {
+	txn.CreatedAt = txn.CreatedAt.Truncate(time.Microsecond)
+
+	metadataJSON, _ := json.Marshal(txn.Metadata)
+	return []interface{}{
+		txn.ID,
+		txn.PartnerID,
+		txn.IdempotencyKey,
+		txn.Amount,
+		txn.Amount.Currency,
+		txn.PaymentMethod.String(),
+		txn.Provider.String(),
+		string(txn.Status),
+		txn.CustomerEmail,
+		txn.CustomerName,
+		txn.CustomerPhone,
+		txn.Description,
+		metadataJSON,
+		txn.IPAddress,
+		txn.UserAgent,
+		txn.RequestID,
+		txn.RetryCount,
+		txn.Livemode,
+		txn.CreatedAt,
+		txn.UpdatedAt,
+		paymentMethodDetailsJSON(txn.PaymentMethodDetails),
+		sql.NullString{String: txn.BillingCountry.String(), Valid: txn.BillingCountry != ""},
+	}
+}
+
+// paymentMethodDetailsJSON stores unknown payment method details as NULL
+func paymentMethodDetailsJSON(details *valueobjects.PaymentMethodDetails) []byte {
+	if details == nil {
+		return nil
+	}
+	detailsJSON, _ := json.Marshal(details)
+	return detailsJSON
+}
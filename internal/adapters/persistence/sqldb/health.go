@@ -0,0 +1,76 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// PingCheck reports whether a database answers
+type PingCheck struct {
+	db *sql.DB
+}
+
+// NewPingCheck creates a health check of db
+func NewPingCheck(db *sql.DB) *PingCheck {
+	return &PingCheck{db: db}
+}
+
+// CheckHealth pings the database. It is down when the ping fails.
+func (p *PingCheck) CheckHealth(ctx context.Context) ports.ComponentHealth {
+	start := time.Now()
+	if err := p.db.PingContext(ctx); err != nil {
+		return ports.ComponentHealth{Status: ports.HealthDown, Error: err.Error()}
+	}
+
+	stats := p.db.Stats()
+	return ports.ComponentHealth{
+		Status: ports.HealthUp,
+		Details: map[string]interface{}{
+			"latency_ms":       time.Since(start).Milliseconds(),
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+		},
+	}
+}
+
+// ReplicaCheck reports how far a read replica is behind its primary. Reads
+// fall back to the primary when the replica fails, so a failing or lagging
+// replica degrades the API rather than taking it down.
+type ReplicaCheck struct {
+	db *sql.DB
+	// lagQuery returns the replica's lag in seconds, in the database's dialect
+	lagQuery string
+	maxLag   time.Duration
+}
+
+// NewReplicaCheck creates a health check of the replica db, which is
+// degraded when lagQuery returns more than maxLag
+func NewReplicaCheck(db *sql.DB, lagQuery string, maxLag time.Duration) *ReplicaCheck {
+	return &ReplicaCheck{db: db, lagQuery: lagQuery, maxLag: maxLag}
+}
+
+// CheckHealth measures the replica's lag
+func (r *ReplicaCheck) CheckHealth(ctx context.Context) ports.ComponentHealth {
+	var seconds float64
+	if err := r.db.QueryRowContext(ctx, r.lagQuery).Scan(&seconds); err != nil {
+		return ports.ComponentHealth{Status: ports.HealthDegraded, Error: err.Error()}
+	}
+
+	lag := time.Duration(seconds * float64(time.Second))
+	health := ports.ComponentHealth{
+		Status: ports.HealthUp,
+		Details: map[string]interface{}{
+			"lag_ms":     lag.Milliseconds(),
+			"max_lag_ms": r.maxLag.Milliseconds(),
+		},
+	}
+	if lag > r.maxLag {
+		health.Status = ports.HealthDegraded
+		health.Error = fmt.Sprintf("replica is %s behind the primary", lag.Round(time.Millisecond))
+	}
+	return health
+}
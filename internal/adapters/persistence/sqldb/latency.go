@@ -0,0 +1,22 @@
+package sqldb
+
+import (
+	"strconv"
+	"strings"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// LatencyBucket returns a CASE expression numbering the bucket of
+// ports.ProcessingLatencyBounds that seconds, an SQL expression of a
+// latency in seconds, falls in
+func LatencyBucket(seconds string) string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for i, bound := range ports.ProcessingLatencyBounds {
+		b.WriteString(" WHEN " + seconds + " <= " + strconv.FormatFloat(bound.Seconds(), 'f', -1, 64) +
+			" THEN " + strconv.Itoa(i))
+	}
+	b.WriteString(" ELSE " + strconv.Itoa(len(ports.ProcessingLatencyBounds)) + " END")
+	return b.String()
+}
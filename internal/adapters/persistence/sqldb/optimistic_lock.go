@@ -0,0 +1,29 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// CheckVersionedUpdate turns an UPDATE ... WHERE id = ? AND version = ? that
+// matched no row into a version conflict. A deleted row is reported the same
+// way: either way the caller's copy is stale.
+func CheckVersionedUpdate(result sql.Result, resource string, id uuid.UUID, version int) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", resource, err)
+	}
+
+	if rows == 0 {
+		return &errors.VersionConflictError{
+			Resource: resource,
+			ID:       id.String(),
+			Version:  version,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// replicaRetryAfter is how long reads stay on the primary after the replica failed
+const replicaRetryAfter = 30 * time.Second
+
+// Replica routes read-only queries to a read replica. Queries made with a
+// context marked by ports.ReadOnly go to the replica, everything else (and
+// anything inside a unit of work) to the primary. When a replica query fails
+// it is retried on the primary, and reads stay there for a while before the
+// replica is tried again.
+type Replica struct {
+	primary *sql.DB
+	db      *sql.DB
+
+	// retryAt is when the replica may be used again, in Unix nanoseconds
+	retryAt atomic.Int64
+}
+
+// NewReplica creates a router for reads to replica, falling back to primary
+func NewReplica(primary, replica *sql.DB) *Replica {
+	return &Replica{primary: primary, db: replica}
+}
+
+// Available reports whether reads currently go to the replica
+func (r *Replica) Available() bool {
+	return time.Now().UnixNano() >= r.retryAt.Load()
+}
+
+// fail moves reads to the primary for replicaRetryAfter
+func (r *Replica) fail() {
+	r.retryAt.Store(time.Now().Add(replicaRetryAfter).UnixNano())
+}
+
+// ExecContext never runs on the replica
+func (r *Replica) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs query on the replica, or on the primary if that fails
+func (r *Replica) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil && ctx.Err() == nil {
+		r.fail()
+		return r.primary.QueryContext(ctx, query, args...)
+	}
+	return rows, err
+}
+
+// QueryRowContext runs query on the replica, or on the primary if that fails
+func (r *Replica) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	row := r.db.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil && ctx.Err() == nil {
+		r.fail()
+		return r.primary.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+// ReadConn is Conn for read-only repository methods: it returns the replica
+// when ctx is marked read-only, outside a unit of work, and the replica is
+// configured and available
+func ReadConn(ctx context.Context, db *sql.DB, replica *Replica) Querier {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok || replica == nil || !ports.IsReadOnly(ctx) || !replica.Available() {
+		return Conn(ctx, db)
+	}
+	return replica
+}
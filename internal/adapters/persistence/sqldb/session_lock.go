@@ -0,0 +1,83 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SessionLocker implements ports.Locker with locks the database ties to a
+// session, such as Postgres advisory locks. Each held lock keeps a
+// connection out of the pool until it is released, and the database
+// releases it if the instance dies and the connection drops.
+type SessionLocker struct {
+	db *sql.DB
+	// tryLock takes the key and returns whether the lock was acquired;
+	// unlock takes the key
+	tryLock string
+	unlock  string
+}
+
+// NewSessionLocker creates a locker running tryLock and unlock, which take
+// the key as their only argument
+func NewSessionLocker(db *sql.DB, tryLock, unlock string) *SessionLocker {
+	return &SessionLocker{db: db, tryLock: tryLock, unlock: unlock}
+}
+
+// TryLock acquires the lock on a dedicated connection. The lock is released
+// after ttl even if Unlock is never called.
+func (l *SessionLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (ports.Lock, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, l.tryLock, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, errors.ErrResourceLocked
+	}
+
+	lock := &sessionLock{conn: conn, unlock: l.unlock, key: key}
+	lock.expiry = time.AfterFunc(ttl, func() { _ = lock.release(context.Background()) })
+	return lock, nil
+}
+
+type sessionLock struct {
+	conn   *sql.Conn
+	unlock string
+	key    string
+	expiry *time.Timer
+
+	once sync.Once
+	err  error
+}
+
+// Unlock releases the lock and returns its connection to the pool
+func (l *sessionLock) Unlock(ctx context.Context) error {
+	l.expiry.Stop()
+	return l.release(ctx)
+}
+
+func (l *sessionLock) release(ctx context.Context) error {
+	l.once.Do(func() {
+		defer l.conn.Close()
+		if _, err := l.conn.ExecContext(ctx, l.unlock, l.key); err != nil {
+			// Closing the connection would not end the session, which goes
+			// back to the pool still holding the lock, so discard it
+			_ = l.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+			l.err = fmt.Errorf("failed to release lock: %w", err)
+		}
+	})
+	return l.err
+}
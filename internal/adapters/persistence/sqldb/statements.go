@@ -0,0 +1,103 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Statements runs a repository's hottest queries as prepared statements, so
+// the database parses and plans them once instead of on every call. Queries
+// are prepared by Prepare at startup; anything not prepared, or everything
+// if Prepare is never called, runs as a plain query.
+//
+// Named prepared statements live on one server connection, which a pooler
+// in transaction mode (such as PgBouncer before 1.21) does not keep for a
+// client; leave them off behind one.
+type Statements struct {
+	db *sql.DB
+	// stmts is filled by Prepare before the repository is used and only
+	// read afterwards
+	stmts map[string]*sql.Stmt
+}
+
+// NewStatements creates an empty statement cache on db
+func NewStatements(db *sql.DB) *Statements {
+	return &Statements{db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+// Prepare prepares queries. Call it before the repository is used.
+func (s *Statements) Prepare(ctx context.Context, queries ...string) error {
+	for _, query := range queries {
+		if _, ok := s.stmts[query]; ok {
+			continue
+		}
+		stmt, err := s.db.PrepareContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		s.stmts[query] = stmt
+	}
+	return nil
+}
+
+// Conn is Conn with the prepared queries run as prepared statements, in the
+// unit of work's transaction when ctx has one
+func (s *Statements) Conn(ctx context.Context) Querier {
+	if len(s.stmts) == 0 {
+		return Conn(ctx, s.db)
+	}
+	tx, _ := ctx.Value(txKey{}).(*sql.Tx)
+	return preparedQuerier{statements: s, tx: tx, conn: Conn(ctx, s.db)}
+}
+
+// ReadConn is ReadConn with prepared statements on the primary; queries
+// sent to the replica run unprepared
+func (s *Statements) ReadConn(ctx context.Context, replica *Replica) Querier {
+	if conn, ok := ReadConn(ctx, s.db, replica).(*Replica); ok {
+		return conn
+	}
+	return s.Conn(ctx)
+}
+
+// preparedQuerier runs the queries it has a statement for as that statement
+type preparedQuerier struct {
+	statements *Statements
+	tx         *sql.Tx
+	conn       Querier
+}
+
+// stmt returns the statement of query, or nil if it is not prepared
+func (q preparedQuerier) stmt(ctx context.Context, query string) *sql.Stmt {
+	stmt, ok := q.statements.stmts[query]
+	if !ok {
+		return nil
+	}
+	if q.tx != nil {
+		// Closed with the transaction; reuses the statement when it is
+		// already prepared on the transaction's connection
+		return q.tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
+
+func (q preparedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if stmt := q.stmt(ctx, query); stmt != nil {
+		return stmt.ExecContext(ctx, args...)
+	}
+	return q.conn.ExecContext(ctx, query, args...)
+}
+
+func (q preparedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if stmt := q.stmt(ctx, query); stmt != nil {
+		return stmt.QueryContext(ctx, args...)
+	}
+	return q.conn.QueryContext(ctx, query, args...)
+}
+
+func (q preparedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if stmt := q.stmt(ctx, query); stmt != nil {
+		return stmt.QueryRowContext(ctx, args...)
+	}
+	return q.conn.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,97 @@
+// Package sqldb holds the database/sql plumbing shared by the SQL
+// persistence adapters: units of work carried by the context, read replica
+// routing and optimistic locking. It knows nothing about a particular
+// database's SQL dialect.
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Querier is satisfied by *sql.DB, *sql.Tx and *Replica
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// RowScanner is satisfied by both *sql.Row and *sql.Rows
+type RowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// txKey is the context key of the unit of work's transaction
+type txKey struct{}
+
+// UnitOfWork implements ports.UnitOfWork for any database/sql database
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a new unit of work on db
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn in a database transaction carried by the context, and then the
+// hooks fn added with ports.AfterCommit if the transaction commits
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Rolls back on error or panic; a no-op after Commit
+	defer tx.Rollback()
+
+	txCtx, hooks := ports.WithAfterCommit(context.WithValue(ctx, txKey{}, tx))
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	hooks.Run()
+	return nil
+}
+
+// Conn returns the transaction of the unit of work running in ctx, or db
+// outside one
+func Conn(ctx context.Context, db *sql.DB) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// InTx runs fn in the unit of work's transaction when ctx has one, otherwise
+// in a transaction of its own. Repository methods that must be atomic on
+// their own use it so they also join a caller's unit of work.
+func InTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
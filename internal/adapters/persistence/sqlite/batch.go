@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+)
+
+// maxParams is the most bind parameters SQLite accepts in one statement
+// (SQLITE_MAX_VARIABLE_NUMBER)
+const maxParams = 32766
+
+// insertRows runs insert, which ends in VALUES, for rows of equal length. It
+// takes as few multi-row statements as maxParams allows; when it takes more
+// than one, they share a database transaction so all rows are inserted or none.
+func insertRows(ctx context.Context, db *sql.DB, insert string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	perStatement := maxParams / len(rows[0])
+	if len(rows) <= perStatement {
+		return execInsert(ctx, conn(ctx, db), insert, rows)
+	}
+
+	return inTx(ctx, db, func(tx sqldb.Querier) error {
+		for start := 0; start < len(rows); start += perStatement {
+			end := start + perStatement
+			if end > len(rows) {
+				end = len(rows)
+			}
+			if err := execInsert(ctx, tx, insert, rows[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// execInsert runs insert for rows in a single statement
+func execInsert(ctx context.Context, db sqldb.Querier, insert string, rows [][]interface{}) error {
+	args := make([]interface{}, 0, len(rows)*len(rows[0]))
+	placeholders := "(?" + strings.Repeat(", ?", len(rows[0])-1) + ")"
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = placeholders
+		args = append(args, row...)
+	}
+
+	_, err := db.ExecContext(ctx, insert+strings.Join(values, ", "), args...)
+	return err
+}
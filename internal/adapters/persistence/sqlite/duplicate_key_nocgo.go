@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package sqlite
+
+// isDuplicateKey reports whether err is a unique key violation. Without cgo
+// the SQLite driver cannot open a database, so there are no errors to check.
+func isDuplicateKey(err error) bool {
+	return false
+}
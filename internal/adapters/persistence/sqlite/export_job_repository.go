@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+// ExportJobRepository implements ports.ExportJobRepository for SQLite
+type ExportJobRepository struct {
+	db *sql.DB
+}
+
+// NewExportJobRepository creates a new SQLite export job repository
+func NewExportJobRepository(db *sql.DB) *ExportJobRepository {
+	return &ExportJobRepository{db: db}
+}
+
+// exportJobColumns are the columns scanned by query, in order
+const exportJobColumns = `id, partner_id, livemode, resource, format, filters, csv_columns, timezone, status,
+			   attempts, next_attempt_at, last_error, object_key, row_count, created_at, completed_at`
+
+// Add queues a job
+func (r *ExportJobRepository) Add(ctx context.Context, job *entities.ExportJob) error {
+	filtersJSON, err := json.Marshal(job.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode export filters: %w", err)
+	}
+
+	query := `
+		INSERT INTO export_jobs (
+			id, partner_id, livemode, resource, format, filters, csv_columns,
+			timezone, status, attempts, next_attempt_at, created_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		job.ID,
+		job.PartnerID,
+		job.Livemode,
+		job.Resource,
+		job.Format,
+		filtersJSON,
+		strings.Join(job.Layout.Columns, ","),
+		job.Layout.Timezone,
+		job.Status,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add export job: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a job
+func (r *ExportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ExportJob, error) {
+	jobs, err := r.query(ctx, conn(ctx, r.db), "SELECT "+exportJobColumns+" FROM export_jobs WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, errors.ErrExportNotFound
+	}
+	return jobs[0], nil
+}
+
+// ClaimDue claims due jobs, oldest first. SQLite has a single writer, so
+// selecting and claiming the jobs in one UPDATE is enough to keep two
+// workers from claiming the same job.
+func (r *ExportJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.ExportJob, error) {
+	b := &sqlBuilder{}
+	until := b.arg(claimedUntil)
+	b.where("status = %s", entities.ExportJobStatusPending)
+	b.where("next_attempt_at <= %s", time.Now())
+	query := `
+		UPDATE export_jobs SET next_attempt_at = ` + until + `
+		WHERE id IN (
+			SELECT id FROM export_jobs` + b.clause() + `
+			ORDER BY created_at ASC
+			LIMIT ` + b.arg(limit) + `
+		)
+		RETURNING ` + exportJobColumns + `
+	`
+	jobs, err := r.query(ctx, conn(ctx, r.db), query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING gives the rows in no particular order
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// Update saves the outcome of an attempt
+func (r *ExportJobRepository) Update(ctx context.Context, job *entities.ExportJob) error {
+	query := `
+		UPDATE export_jobs SET
+			status = ?,
+			attempts = ?,
+			next_attempt_at = ?,
+			last_error = NULLIF(?, ''),
+			object_key = NULLIF(?, ''),
+			row_count = ?,
+			completed_at = ?
+		WHERE id = ?
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		job.Status,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.LastError,
+		job.ObjectKey,
+		job.RowCount,
+		job.CompletedAt,
+		job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update export job: %w", err)
+	}
+	return nil
+}
+
+// query runs a SELECT of exportJobColumns on db
+func (r *ExportJobRepository) query(ctx context.Context, db sqldb.Querier, query string, args ...interface{}) ([]*entities.ExportJob, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entities.ExportJob
+	for rows.Next() {
+		var job entities.ExportJob
+		var filtersJSON []byte
+		var csvColumns string
+		var lastError, objectKey sql.NullString
+		if err := rows.Scan(
+			&job.ID,
+			&job.PartnerID,
+			&job.Livemode,
+			&job.Resource,
+			&job.Format,
+			&filtersJSON,
+			&csvColumns,
+			&job.Layout.Timezone,
+			&job.Status,
+			&job.Attempts,
+			&job.NextAttemptAt,
+			&lastError,
+			&objectKey,
+			&job.RowCount,
+			&job.CreatedAt,
+			&job.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan export job: %w", err)
+		}
+
+		if err := json.Unmarshal(filtersJSON, &job.Filters); err != nil {
+			return nil, fmt.Errorf("failed to decode export filters: %w", err)
+		}
+		if csvColumns != "" {
+			job.Layout.Columns = strings.Split(csvColumns, ",")
+		}
+		job.LastError = lastError.String
+		job.ObjectKey = objectKey.String
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list export jobs: %w", err)
+	}
+
+	return jobs, nil
+}
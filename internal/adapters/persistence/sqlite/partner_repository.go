@@ -0,0 +1,502 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// PartnerRepository implements ports.PartnerRepository for SQLite.
+// Webhook secrets are encrypted with cipher before they are written.
+type PartnerRepository struct {
+	db     *sql.DB
+	cipher ports.SecretCipher
+}
+
+// NewPartnerRepository creates a new SQLite partner repository
+func NewPartnerRepository(db *sql.DB, cipher ports.SecretCipher) *PartnerRepository {
+	return &PartnerRepository{db: db, cipher: cipher}
+}
+
+// Create creates a new partner
+func (r *PartnerRepository) Create(ctx context.Context, partner *entities.Partner) error {
+	query := `
+		INSERT INTO partners (
+			id, name, email, api_key_hash, api_key_prefix, is_active,
+			rate_limit_per_minute, webhook_url, webhook_secret,
+			refund_approval_thresholds, refund_window_days, features,
+			allowed_currencies, amount_limits, locale, rounding_policy, event_destination, sms_sender, field_filter, receipt_logo_key, pricing, account_mapping,
+			metadata, version,
+			created_at, updated_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+
+	webhookSecret, err := r.cipher.Encrypt(partner.WebhookSecret, partnerWebhookSecret(partner.ID))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	featuresJSON, _ := json.Marshal(featuresOrEmpty(partner.Features))
+	amountLimitsJSON, _ := json.Marshal(amountLimitsOrEmpty(partner.AmountLimits))
+	approvalThresholdsJSON, _ := json.Marshal(amountLimitsOrEmpty(partner.RefundApprovalThresholds))
+	metadataJSON, _ := json.Marshal(partner.Metadata)
+	currenciesJSON, _ := json.Marshal(currencyCodes(partner.AllowedCurrencies))
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		partner.ID,
+		partner.Name,
+		partner.Email,
+		partner.APIKeyHash,
+		partner.APIKeyPrefix,
+		partner.IsActive,
+		partner.RateLimitPerMinute,
+		partner.WebhookURL,
+		webhookSecret,
+		string(approvalThresholdsJSON),
+		partner.RefundWindowDays,
+		string(featuresJSON),
+		string(currenciesJSON),
+		string(amountLimitsJSON),
+		partner.Locale.String(),
+		partner.RoundingPolicy.String(),
+		eventDestinationJSON(partner.EventDestination),
+		partner.SMSSenderID.String(),
+		fieldFilterJSON(partner.FieldFilter),
+		partner.ReceiptLogoKey,
+		pricingJSON(partner.Pricing),
+		accountMappingJSON(partner.AccountMapping),
+		string(metadataJSON),
+		partner.Version,
+		partner.CreatedAt,
+		partner.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create partner: %w", err)
+	}
+
+	return nil
+}
+
+// partnerColumns are the columns scanPartner reads, in order
+const partnerColumns = `
+	id, name, email, api_key_hash, api_key_prefix, is_active,
+	rate_limit_per_minute, webhook_url, webhook_secret,
+	refund_approval_thresholds, refund_window_days, features,
+	allowed_currencies, amount_limits, locale, rounding_policy, event_destination, sms_sender, field_filter, receipt_logo_key, pricing, account_mapping,
+	metadata, version,
+	created_at, updated_at`
+
+// GetByID retrieves a partner by ID
+func (r *PartnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Partner, error) {
+	query := `SELECT ` + partnerColumns + ` FROM partners WHERE id = ? AND deleted_at IS NULL`
+
+	return r.getOne(ctx, "failed to get partner", query, id)
+}
+
+// GetByEmail retrieves a partner by email
+func (r *PartnerRepository) GetByEmail(ctx context.Context, email string) (*entities.Partner, error) {
+	query := `SELECT ` + partnerColumns + ` FROM partners WHERE email = ? AND deleted_at IS NULL`
+
+	return r.getOne(ctx, "failed to get partner by email", query, email)
+}
+
+// GetByAPIKeyPrefix retrieves a partner by API key prefix
+func (r *PartnerRepository) GetByAPIKeyPrefix(ctx context.Context, prefix string) (*entities.Partner, error) {
+	query := `SELECT ` + partnerColumns + ` FROM partners WHERE api_key_prefix = ? AND deleted_at IS NULL`
+
+	return r.getOne(ctx, "failed to get partner by API key prefix", query, prefix)
+}
+
+// getOne runs a query selecting partnerColumns for at most one partner
+func (r *PartnerRepository) getOne(ctx context.Context, failure, query string, arg interface{}) (*entities.Partner, error) {
+	partner, err := r.scanPartner(conn(ctx, r.db).QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrPartnerNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+
+	return partner, nil
+}
+
+// scanPartner reads a row of partnerColumns and decrypts its webhook secret
+func (r *PartnerRepository) scanPartner(row sqldb.RowScanner) (*entities.Partner, error) {
+	var partner entities.Partner
+	var featuresJSON, currenciesJSON, amountLimitsJSON, approvalThresholdsJSON, eventDestinationJSON, fieldFilterJSON, pricingJSON, accountMappingJSON, metadataJSON []byte
+	var allowedCurrencies []string
+	var locale, roundingPolicy, smsSender string
+
+	err := row.Scan(
+		&partner.ID,
+		&partner.Name,
+		&partner.Email,
+		&partner.APIKeyHash,
+		&partner.APIKeyPrefix,
+		&partner.IsActive,
+		&partner.RateLimitPerMinute,
+		&partner.WebhookURL,
+		&partner.WebhookSecret,
+		&approvalThresholdsJSON,
+		&partner.RefundWindowDays,
+		&featuresJSON,
+		&currenciesJSON,
+		&amountLimitsJSON,
+		&locale,
+		&roundingPolicy,
+		&eventDestinationJSON,
+		&smsSender,
+		&fieldFilterJSON,
+		&partner.ReceiptLogoKey,
+		&pricingJSON,
+		&accountMappingJSON,
+		&metadataJSON,
+		&partner.Version,
+		&partner.CreatedAt,
+		&partner.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	partner.WebhookSecret, err = r.cipher.Decrypt(partner.WebhookSecret, partnerWebhookSecret(partner.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+
+	if len(featuresJSON) > 0 {
+		json.Unmarshal(featuresJSON, &partner.Features)
+	}
+
+	partner.Locale, _ = valueobjects.NewLocale(locale)
+	partner.RoundingPolicy, _ = valueobjects.NewRoundingPolicy(roundingPolicy)
+
+	if len(currenciesJSON) > 0 {
+		json.Unmarshal(currenciesJSON, &allowedCurrencies)
+	}
+	for _, code := range allowedCurrencies {
+		partner.AllowedCurrencies = append(partner.AllowedCurrencies, valueobjects.Currency(code))
+	}
+
+	if len(amountLimitsJSON) > 0 {
+		json.Unmarshal(amountLimitsJSON, &partner.AmountLimits)
+	}
+
+	if len(approvalThresholdsJSON) > 0 {
+		json.Unmarshal(approvalThresholdsJSON, &partner.RefundApprovalThresholds)
+	}
+
+	partner.SMSSenderID = valueobjects.SMSSenderID(smsSender)
+
+	if len(eventDestinationJSON) > 0 {
+		partner.EventDestination = &valueobjects.EventDestination{}
+		json.Unmarshal(eventDestinationJSON, partner.EventDestination)
+	}
+
+	if len(fieldFilterJSON) > 0 {
+		json.Unmarshal(fieldFilterJSON, &partner.FieldFilter)
+	}
+
+	if len(pricingJSON) > 0 {
+		partner.Pricing = &valueobjects.Pricing{}
+		json.Unmarshal(pricingJSON, partner.Pricing)
+	}
+
+	if len(accountMappingJSON) > 0 {
+		partner.AccountMapping = &valueobjects.AccountMapping{}
+		json.Unmarshal(accountMappingJSON, partner.AccountMapping)
+	}
+
+	if len(metadataJSON) > 0 {
+		json.Unmarshal(metadataJSON, &partner.Metadata)
+	}
+
+	return &partner, nil
+}
+
+// Update updates an existing partner if it is still at partner.Version, or
+// at the version ctx expects (see ports.ExpectVersion), and moves partner to
+// the next version. A partner changed since is left alone and a
+// *errors.VersionConflictError returned, or errors.ErrPreconditionFailed
+// when ctx expects a version.
+func (r *PartnerRepository) Update(ctx context.Context, partner *entities.Partner) error {
+	query := `
+		UPDATE partners SET
+			name = ?,
+			email = ?,
+			is_active = ?,
+			rate_limit_per_minute = ?,
+			webhook_url = ?,
+			webhook_secret = ?,
+			refund_approval_thresholds = ?,
+			refund_window_days = ?,
+			features = ?,
+			allowed_currencies = ?,
+			amount_limits = ?,
+			locale = ?,
+			rounding_policy = ?,
+			event_destination = ?,
+			sms_sender = ?,
+			field_filter = ?,
+			receipt_logo_key = ?,
+			pricing = ?,
+			account_mapping = ?,
+			updated_at = ?,
+			deleted_at = ?,
+			anonymize_after = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+	webhookSecret, err := r.cipher.Encrypt(partner.WebhookSecret, partnerWebhookSecret(partner.ID))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	featuresJSON, _ := json.Marshal(featuresOrEmpty(partner.Features))
+	currenciesJSON, _ := json.Marshal(currencyCodes(partner.AllowedCurrencies))
+	amountLimitsJSON, _ := json.Marshal(amountLimitsOrEmpty(partner.AmountLimits))
+	approvalThresholdsJSON, _ := json.Marshal(amountLimitsOrEmpty(partner.RefundApprovalThresholds))
+
+	version, expected := ports.ExpectedVersion(ctx)
+	if !expected {
+		version = partner.Version
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		partner.Name,
+		partner.Email,
+		partner.IsActive,
+		partner.RateLimitPerMinute,
+		partner.WebhookURL,
+		webhookSecret,
+		string(approvalThresholdsJSON),
+		partner.RefundWindowDays,
+		string(featuresJSON),
+		string(currenciesJSON),
+		string(amountLimitsJSON),
+		partner.Locale.String(),
+		partner.RoundingPolicy.String(),
+		eventDestinationJSON(partner.EventDestination),
+		partner.SMSSenderID.String(),
+		fieldFilterJSON(partner.FieldFilter),
+		partner.ReceiptLogoKey,
+		pricingJSON(partner.Pricing),
+		accountMappingJSON(partner.AccountMapping),
+		partner.UpdatedAt,
+		partner.DeletedAt,
+		partner.AnonymizeAfter,
+		partner.ID,
+		version,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "partner", partner.ID, version); err != nil {
+		if expected {
+			return errors.ErrPreconditionFailed
+		}
+		return err
+	}
+	partner.Version = version + 1
+	return nil
+}
+
+// List retrieves all partners with pagination
+func (r *PartnerRepository) List(ctx context.Context, limit, offset int) ([]*entities.Partner, error) {
+	query := `
+		SELECT ` + partnerColumns + `
+		FROM partners
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partners: %w", err)
+	}
+	defer rows.Close()
+
+	var partners []*entities.Partner
+	for rows.Next() {
+		partner, err := r.scanPartner(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list partners: %w", err)
+		}
+		partners = append(partners, partner)
+	}
+
+	return partners, rows.Err()
+}
+
+// ListWithTotal retrieves a page of partners and the number of partners on
+// all pages. The total comes from a window function in the same query, so it
+// matches the page; only a page past the end needs a separate count.
+func (r *PartnerRepository) ListWithTotal(ctx context.Context, limit, offset int) ([]*entities.Partner, int64, error) {
+	query := `
+		SELECT ` + partnerColumns + `, COUNT(*) OVER () AS total
+		FROM partners
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list partners: %w", err)
+	}
+	defer rows.Close()
+
+	var partners []*entities.Partner
+	var total int64
+	for rows.Next() {
+		partner, err := r.scanPartner(totalScanner{rows: rows, total: &total})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list partners: %w", err)
+		}
+		partners = append(partners, partner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list partners: %w", err)
+	}
+
+	if len(partners) == 0 && offset > 0 {
+		countQuery := `SELECT COUNT(*) FROM partners WHERE deleted_at IS NULL`
+		if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("failed to count partners: %w", err)
+		}
+	}
+
+	return partners, total, nil
+}
+
+// totalScanner scans a row with a trailing COUNT(*) OVER () column into total
+type totalScanner struct {
+	rows  *sql.Rows
+	total *int64
+}
+
+func (s totalScanner) Scan(dest ...interface{}) error {
+	return s.rows.Scan(append(dest, s.total)...)
+}
+
+// ListDueForAnonymization returns off-boarded partners whose customer data is due for anonymization
+func (r *PartnerRepository) ListDueForAnonymization(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM partners
+		WHERE deleted_at IS NOT NULL
+		  AND anonymize_after <= ?
+		  AND anonymized_at IS NULL
+		ORDER BY anonymize_after
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partners due for anonymization: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// MarkAnonymized records that a partner's customer data was anonymized
+func (r *PartnerRepository) MarkAnonymized(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE partners SET anonymized_at = ? WHERE id = ?`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, at, id); err != nil {
+		return fmt.Errorf("failed to mark partner anonymized: %w", err)
+	}
+
+	return nil
+}
+
+// RotateSecrets re-encrypts webhook secrets with the current encryption key
+func (r *PartnerRepository) RotateSecrets(ctx context.Context) (int, error) {
+	return rotateSecretColumn(ctx, r.db, r.cipher, "partners", "webhook_secret", "id", "")
+}
+
+// currencyCodes converts currencies to the codes stored in a JSON array column
+func currencyCodes(currencies []valueobjects.Currency) []string {
+	codes := make([]string, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency.String()
+	}
+	return codes
+}
+
+// featuresOrEmpty stores partners without flags as {} rather than null
+func featuresOrEmpty(features map[valueobjects.PartnerFeature]bool) map[valueobjects.PartnerFeature]bool {
+	if features == nil {
+		return map[valueobjects.PartnerFeature]bool{}
+	}
+	return features
+}
+
+// amountLimitsOrEmpty stores partners without limits as {} rather than null
+func amountLimitsOrEmpty(limits valueobjects.AmountLimits) valueobjects.AmountLimits {
+	if limits == nil {
+		return valueobjects.AmountLimits{}
+	}
+	return limits
+}
+
+// eventDestinationJSON stores partners without an event destination as NULL
+func eventDestinationJSON(destination *valueobjects.EventDestination) interface{} {
+	if destination == nil {
+		return nil
+	}
+	encoded, _ := json.Marshal(destination)
+	return string(encoded)
+}
+
+// fieldFilterJSON stores partners hiding no fields as NULL
+func fieldFilterJSON(filter valueobjects.FieldFilter) interface{} {
+	if filter.IsEmpty() {
+		return nil
+	}
+	encoded, _ := json.Marshal(filter)
+	return string(encoded)
+}
+
+// pricingJSON stores partners on the standard pricing as NULL
+func pricingJSON(pricing *valueobjects.Pricing) interface{} {
+	if pricing == nil {
+		return nil
+	}
+	encoded, _ := json.Marshal(pricing)
+	return encoded
+}
+
+// accountMappingJSON stores partners posting to the default accounts as NULL
+func accountMappingJSON(mapping *valueobjects.AccountMapping) interface{} {
+	if mapping == nil {
+		return nil
+	}
+	encoded, _ := json.Marshal(mapping)
+	return encoded
+}
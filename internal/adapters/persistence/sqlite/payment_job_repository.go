@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// PaymentJobRepository implements ports.PaymentJobRepository for SQLite
+type PaymentJobRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentJobRepository creates a new SQLite payment job repository
+func NewPaymentJobRepository(db *sql.DB) *PaymentJobRepository {
+	return &PaymentJobRepository{db: db}
+}
+
+// Add queues a job in the caller's unit of work, if there is one
+func (r *PaymentJobRepository) Add(ctx context.Context, job *entities.PaymentJob) error {
+	query := `
+		INSERT INTO payment_jobs (
+			id, partner_id, transaction_id, attempts, next_attempt_at, created_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?
+		)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		job.ID,
+		job.PartnerID,
+		job.TransactionID,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add payment job: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue claims due jobs, oldest first. SQLite has a single writer, so
+// selecting and claiming the jobs in one UPDATE is enough to keep two
+// workers from claiming the same job.
+func (r *PaymentJobRepository) ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.PaymentJob, error) {
+	b := &sqlBuilder{}
+	until := b.arg(claimedUntil)
+	b.where("completed_at IS NULL")
+	b.where("next_attempt_at <= %s", time.Now())
+	b.where("attempts < %s", entities.MaxPaymentJobAttempts)
+	query := `
+		UPDATE payment_jobs SET next_attempt_at = ` + until + `
+		WHERE id IN (
+			SELECT id FROM payment_jobs` + b.clause() + `
+			ORDER BY created_at ASC
+			LIMIT ` + b.arg(limit) + `
+		)
+		RETURNING id, partner_id, transaction_id, attempts, next_attempt_at, last_error,
+			created_at, completed_at
+	`
+	jobs, err := r.query(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING gives the rows in no particular order
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// Update saves the outcome of an attempt
+func (r *PaymentJobRepository) Update(ctx context.Context, job *entities.PaymentJob) error {
+	query := `
+		UPDATE payment_jobs SET
+			attempts = ?,
+			next_attempt_at = ?,
+			last_error = NULLIF(?, ''),
+			completed_at = ?
+		WHERE id = ?
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		job.Attempts,
+		job.NextAttemptAt,
+		job.LastError,
+		job.CompletedAt,
+		job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payment job: %w", err)
+	}
+	return nil
+}
+
+// query runs a SELECT of payment jobs
+func (r *PaymentJobRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entities.PaymentJob, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*entities.PaymentJob
+	for rows.Next() {
+		var job entities.PaymentJob
+		var lastError sql.NullString
+		if err := rows.Scan(
+			&job.ID,
+			&job.PartnerID,
+			&job.TransactionID,
+			&job.Attempts,
+			&job.NextAttemptAt,
+			&lastError,
+			&job.CreatedAt,
+			&job.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payment job: %w", err)
+		}
+
+		job.LastError = lastError.String
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list payment jobs: %w", err)
+	}
+
+	return jobs, nil
+}
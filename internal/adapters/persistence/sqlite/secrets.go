@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// rotateSecretColumn re-encrypts a table's secret column with the cipher's current key.
+// ownerColumn holds the owner secrets are bound to, and recordColumn, unless empty,
+// the row among the owner's. Rows changed concurrently are skipped and picked up by
+// the next rotation.
+func rotateSecretColumn(ctx context.Context, db *sql.DB, cipher ports.SecretCipher, table, column, ownerColumn, recordColumn string) (int, error) {
+	type storedSecret struct {
+		id     uuid.UUID
+		owner  uuid.UUID
+		record string
+		value  string
+	}
+
+	if recordColumn == "" {
+		recordColumn = "''"
+	}
+	query := fmt.Sprintf(`SELECT id, %s, %s, %s FROM %s WHERE %s IS NOT NULL AND %s <> ''`, ownerColumn, recordColumn, column, table, column, column)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s.%s: %w", table, column, err)
+	}
+
+	var stale []storedSecret
+	for rows.Next() {
+		var secret storedSecret
+		if err := rows.Scan(&secret.id, &secret.owner, &secret.record, &secret.value); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if cipher.NeedsRotation(secret.value) {
+			stale = append(stale, secret)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	update := fmt.Sprintf(`UPDATE %s SET %s = ? WHERE id = ? AND %s = ?`, table, column, column)
+
+	rotated := 0
+	for _, secret := range stale {
+		where := ports.SecretContext{OwnerID: secret.owner, Column: table + "." + column, Record: secret.record}
+		plaintext, err := cipher.Decrypt(secret.value, where)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to decrypt %s.%s of %s: %w", table, column, secret.id, err)
+		}
+
+		encrypted, err := cipher.Encrypt(plaintext, where)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to encrypt %s.%s of %s: %w", table, column, secret.id, err)
+		}
+
+		result, err := db.ExecContext(ctx, update, encrypted, secret.id, secret.value)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to update %s.%s of %s: %w", table, column, secret.id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			rotated++
+		}
+	}
+
+	return rotated, nil
+}
+
+// partnerWebhookSecret is where a partner's webhook secret is stored
+func partnerWebhookSecret(partnerID uuid.UUID) ports.SecretContext {
+	return ports.SecretContext{OwnerID: partnerID, Column: "partners.webhook_secret"}
+}
+
+// providerCredentialSecret is where a partner's secret key for provider is stored
+func providerCredentialSecret(partnerID uuid.UUID, provider valueobjects.PaymentProvider) ports.SecretContext {
+	return ports.SecretContext{OwnerID: partnerID, Column: "provider_credentials.secret_key", Record: provider.String()}
+}
+
+// adminTOTPSecret is where an admin user's TOTP secret is stored
+func adminTOTPSecret(adminID uuid.UUID) ports.SecretContext {
+	return ports.SecretContext{OwnerID: adminID, Column: "admin_users.totp_secret"}
+}
+
+// apiKeySigningSecret is where the signing secret of a partner's API key is stored
+func apiKeySigningSecret(partnerID, keyID uuid.UUID) ports.SecretContext {
+	return ports.SecretContext{OwnerID: partnerID, Column: "api_keys.signing_secret", Record: keyID.String()}
+}
+
+// reportDestinationSecret is where the SFTP credentials of a partner's report schedules are stored
+func reportDestinationSecret(partnerID uuid.UUID) ports.SecretContext {
+	return ports.SecretContext{OwnerID: partnerID, Column: "report_schedules.destination_secret"}
+}
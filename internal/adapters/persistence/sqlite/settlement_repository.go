@@ -0,0 +1,336 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SettlementEntryRepository implements ports.SettlementEntryRepository for SQLite
+type SettlementEntryRepository struct {
+	db *sql.DB
+}
+
+// NewSettlementEntryRepository creates a new SQLite settlement entry repository
+func NewSettlementEntryRepository(db *sql.DB) *SettlementEntryRepository {
+	return &SettlementEntryRepository{db: db}
+}
+
+// settlementEntryColumns are the columns scanSettlementEntry reads, in order
+const settlementEntryColumns = `
+	id, provider, reference, entry_type, provider_transaction_id, amount, fee,
+	currency, reason, occurred_at, source, transaction_id, partner_id,
+	discrepancy, discrepancy_detail, reconciled_at`
+
+// Create stores a reconciled entry in the caller's unit of work, if there is one
+func (r *SettlementEntryRepository) Create(ctx context.Context, entry *entities.SettlementEntry) error {
+	query := `
+		INSERT INTO settlement_entries (` + settlementEntryColumns + `
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?
+		)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		entry.ID,
+		entry.Provider.String(),
+		entry.Reference,
+		string(entry.Type),
+		entry.ProviderTransactionID,
+		entry.Amount,
+		entry.Fee,
+		entry.Amount.Currency.String(),
+		entry.Reason,
+		entry.OccurredAt,
+		entry.Source,
+		entry.TransactionID,
+		entry.PartnerID,
+		string(entry.Discrepancy),
+		entry.DiscrepancyDetail,
+		entry.ReconciledAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement entry: %w", err)
+	}
+	return nil
+}
+
+// GetByReference retrieves the entry provider reported under reference
+func (r *SettlementEntryRepository) GetByReference(ctx context.Context, provider valueobjects.PaymentProvider, reference string) (*entities.SettlementEntry, error) {
+	query := `SELECT ` + settlementEntryColumns + ` FROM settlement_entries WHERE provider = ? AND reference = ?`
+
+	entry, err := scanSettlementEntry(conn(ctx, r.db).QueryRowContext(ctx, query, provider.String(), reference))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement entry: %w", err)
+	}
+	return entry, nil
+}
+
+// ListDiscrepancies retrieves the entries with a discrepancy matching query, newest first
+func (r *SettlementEntryRepository) ListDiscrepancies(ctx context.Context, query ports.SettlementDiscrepancyQuery) ([]*entities.SettlementEntry, int64, error) {
+	b := &sqlBuilder{}
+	b.where("discrepancy IS NOT NULL")
+	if query.Provider != nil {
+		b.where("provider = %s", query.Provider.String())
+	}
+	if query.PartnerID != nil {
+		b.where("partner_id = %s", *query.PartnerID)
+	}
+	if query.ReconciledFrom != nil {
+		b.where("reconciled_at >= %s", *query.ReconciledFrom)
+	}
+	if query.ReconciledTo != nil {
+		b.where("reconciled_at < %s", *query.ReconciledTo)
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM settlement_entries` + b.clause()
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, b.args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count settlement discrepancies: %w", err)
+	}
+
+	listQuery := `
+		SELECT ` + settlementEntryColumns + `
+		FROM settlement_entries` + b.clause() + `
+		ORDER BY reconciled_at DESC, id
+		LIMIT ` + b.arg(query.Limit) + ` OFFSET ` + b.arg(query.Offset)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, listQuery, b.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list settlement discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*entities.SettlementEntry
+	for rows.Next() {
+		entry, err := scanSettlementEntry(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan settlement entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list settlement discrepancies: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// scanSettlementEntry reads a row of settlementEntryColumns, returning the
+// row's error, such as sql.ErrNoRows, as is
+func scanSettlementEntry(row sqldb.RowScanner) (*entities.SettlementEntry, error) {
+	var entry entities.SettlementEntry
+	var provider, entryType string
+	var discrepancy sql.NullString
+	err := row.Scan(
+		&entry.ID,
+		&provider,
+		&entry.Reference,
+		&entryType,
+		&entry.ProviderTransactionID,
+		&entry.Amount,
+		&entry.Fee,
+		&entry.Amount.Currency,
+		&entry.Reason,
+		&entry.OccurredAt,
+		&entry.Source,
+		&entry.TransactionID,
+		&entry.PartnerID,
+		&discrepancy,
+		&entry.DiscrepancyDetail,
+		&entry.ReconciledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Provider = valueobjects.PaymentProvider(provider)
+	entry.Type = entities.SettlementEntryType(entryType)
+	entry.Fee.Currency = entry.Amount.Currency
+	entry.Discrepancy = entities.SettlementDiscrepancy(discrepancy.String)
+	return &entry, nil
+}
+
+// DisputeRepository implements ports.DisputeRepository for SQLite
+type DisputeRepository struct {
+	db *sql.DB
+}
+
+// NewDisputeRepository creates a new SQLite dispute repository
+func NewDisputeRepository(db *sql.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// disputeColumns are the columns scanDispute reads, in order
+const disputeColumns = `
+	id, partner_id, transaction_id, provider, provider_reference, amount,
+	currency, reason, status, created_at, updated_at, reversed_at`
+
+// Create stores a new dispute in the caller's unit of work, if there is one
+func (r *DisputeRepository) Create(ctx context.Context, dispute *entities.Dispute) error {
+	query := `
+		INSERT INTO disputes (` + disputeColumns + `
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		dispute.ID,
+		dispute.PartnerID,
+		dispute.TransactionID,
+		dispute.Provider.String(),
+		dispute.ProviderReference,
+		dispute.Amount,
+		dispute.Amount.Currency.String(),
+		dispute.Reason,
+		string(dispute.Status),
+		dispute.CreatedAt,
+		dispute.UpdatedAt,
+		dispute.ReversedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create dispute: %w", err)
+	}
+	return nil
+}
+
+// Update saves a dispute's status in the caller's unit of work, if there is one
+func (r *DisputeRepository) Update(ctx context.Context, dispute *entities.Dispute) error {
+	query := `UPDATE disputes SET status = ?, updated_at = ?, reversed_at = ? WHERE id = ?`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		string(dispute.Status),
+		dispute.UpdatedAt,
+		dispute.ReversedAt,
+		dispute.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update dispute: %w", err)
+	}
+	return nil
+}
+
+// GetOpenByTransaction retrieves the oldest open dispute of a transaction
+func (r *DisputeRepository) GetOpenByTransaction(ctx context.Context, transactionID uuid.UUID) (*entities.Dispute, error) {
+	query := `
+		SELECT ` + disputeColumns + `
+		FROM disputes
+		WHERE transaction_id = ? AND status = ?
+		ORDER BY created_at, id
+		LIMIT 1
+	`
+
+	dispute, err := scanDispute(conn(ctx, r.db).QueryRowContext(ctx, query, transactionID, string(entities.DisputeStatusOpen)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+	return dispute, nil
+}
+
+// ListByPartner retrieves a partner's disputes, newest first
+func (r *DisputeRepository) ListByPartner(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Dispute, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM disputes WHERE partner_id = ?`
+	if err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, partnerID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count disputes: %w", err)
+	}
+
+	query := `
+		SELECT ` + disputeColumns + `
+		FROM disputes
+		WHERE partner_id = ?
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, partnerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list disputes: %w", err)
+	}
+	defer rows.Close()
+
+	var disputes []*entities.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan dispute: %w", err)
+		}
+		disputes = append(disputes, dispute)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list disputes: %w", err)
+	}
+
+	return disputes, total, nil
+}
+
+// ListOpenedOrReversed retrieves a partner's disputes opened or reversed
+// from from (inclusive) to to (exclusive), oldest first
+func (r *DisputeRepository) ListOpenedOrReversed(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]*entities.Dispute, error) {
+	query := `
+		SELECT ` + disputeColumns + `
+		FROM disputes
+		WHERE partner_id = ?
+		  AND ((created_at >= ? AND created_at < ?) OR (reversed_at >= ? AND reversed_at < ?))
+		ORDER BY created_at ASC, id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, partnerID, from, to, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list disputes: %w", err)
+	}
+	defer rows.Close()
+
+	var disputes []*entities.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dispute: %w", err)
+		}
+		disputes = append(disputes, dispute)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list disputes: %w", err)
+	}
+
+	return disputes, nil
+}
+
+// scanDispute reads a row of disputeColumns, returning the row's error,
+// such as sql.ErrNoRows, as is
+func scanDispute(row sqldb.RowScanner) (*entities.Dispute, error) {
+	var dispute entities.Dispute
+	var provider, status string
+	err := row.Scan(
+		&dispute.ID,
+		&dispute.PartnerID,
+		&dispute.TransactionID,
+		&provider,
+		&dispute.ProviderReference,
+		&dispute.Amount,
+		&dispute.Amount.Currency,
+		&dispute.Reason,
+		&status,
+		&dispute.CreatedAt,
+		&dispute.UpdatedAt,
+		&dispute.ReversedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dispute.Provider = valueobjects.PaymentProvider(provider)
+	dispute.Status = entities.DisputeStatus(status)
+	return &dispute, nil
+}
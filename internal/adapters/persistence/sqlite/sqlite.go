@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+)
+
+// conn is sqldb.Conn with times written in UTC. SQLite stores times as text
+// in the offset they were given, and compares them as text, so every time
+// must be in the same zone for ranges and ordering to work.
+func conn(ctx context.Context, db *sql.DB) sqldb.Querier {
+	return utcQuerier{sqldb.Conn(ctx, db)}
+}
+
+// preparedConn is conn with the prepared statements of stmts
+func preparedConn(ctx context.Context, stmts *sqldb.Statements) sqldb.Querier {
+	return utcQuerier{stmts.Conn(ctx)}
+}
+
+// inTx is sqldb.InTx with times written in UTC, see conn
+func inTx(ctx context.Context, db *sql.DB, fn func(tx sqldb.Querier) error) error {
+	return sqldb.InTx(ctx, db, func(tx *sql.Tx) error {
+		return fn(utcQuerier{tx})
+	})
+}
+
+// utcQuerier converts time arguments to UTC before running a query
+type utcQuerier struct {
+	q sqldb.Querier
+}
+
+func (u utcQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return u.q.ExecContext(ctx, query, utc(args)...)
+}
+
+func (u utcQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return u.q.QueryContext(ctx, query, utc(args)...)
+}
+
+func (u utcQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return u.q.QueryRowContext(ctx, query, utc(args)...)
+}
+
+// utc returns args with times and non-nil time pointers in UTC
+func utc(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			converted[i] = v.UTC()
+		case *time.Time:
+			if v != nil {
+				converted[i] = v.UTC()
+			}
+		default:
+			converted[i] = arg
+		}
+	}
+	return converted
+}
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// sqlBuilder collects WHERE conditions and their arguments in placeholder order
+type sqlBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg adds an argument and returns its placeholder
+func (b *sqlBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return "?"
+}
+
+// where adds a condition; %s verbs in it are replaced by placeholders for args
+func (b *sqlBuilder) where(condition string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for i, value := range args {
+		placeholders[i] = b.arg(value)
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+// clause returns the conditions as a WHERE clause
+func (b *sqlBuilder) clause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// transactionOrderColumns maps the orderable fields to their columns; only
+// these are ever written into the SQL
+var transactionOrderColumns = map[ports.TransactionOrder]string{
+	ports.TransactionOrderCreatedAt: "created_at",
+	ports.TransactionOrderAmount:    "amount",
+}
+
+// transactionFilter translates the filters of q, without paging, ordering
+// or cursor, so the total count matches the same rows as the page
+func transactionFilter(q ports.TransactionQuery) (*sqlBuilder, error) {
+	b := &sqlBuilder{}
+	b.where("deleted_at IS NULL")
+
+	if q.PartnerID != nil {
+		b.where("partner_id = %s", *q.PartnerID)
+	}
+	if q.Livemode != nil {
+		b.where("livemode = %s", *q.Livemode)
+	}
+	if len(q.Statuses) > 0 {
+		placeholders := make([]string, len(q.Statuses))
+		for i, status := range q.Statuses {
+			placeholders[i] = b.arg(string(status))
+		}
+		b.where("status IN (" + strings.Join(placeholders, ", ") + ")")
+	}
+
+	if (q.MinAmount != nil || q.MaxAmount != nil) && q.Currency == nil {
+		return nil, fmt.Errorf("amount range needs a currency")
+	}
+	if q.Currency != nil {
+		b.where("currency = %s", q.Currency.String())
+	}
+	if q.MinAmount != nil {
+		b.where("amount >= %s", *q.MinAmount)
+	}
+	if q.MaxAmount != nil {
+		b.where("amount <= %s", *q.MaxAmount)
+	}
+
+	if q.CreatedFrom != nil {
+		b.where("created_at >= %s", *q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		b.where("created_at < %s", *q.CreatedTo)
+	}
+
+	if q.Provider != nil {
+		b.where("provider = %s", q.Provider.String())
+	}
+	if q.ProviderTransactionID != "" {
+		b.where("provider_transaction_id = %s", q.ProviderTransactionID)
+	}
+
+	// Each pair must be a string member of metadata, as with containment on
+	// PostgreSQL; json_each takes the key as data, so any key is safe
+	for key, value := range q.Metadata {
+		b.where("EXISTS (SELECT 1 FROM json_each(transactions.metadata) WHERE key = %s AND type = 'text' AND value = %s)", key, value)
+	}
+
+	return b, nil
+}
+
+// transactionPage adds q's cursor to b and returns the ORDER BY, LIMIT and
+// OFFSET that follow the WHERE clause
+func transactionPage(b *sqlBuilder, q ports.TransactionQuery) (string, error) {
+	order, err := transactionOrder(b, q)
+	if err != nil {
+		return "", err
+	}
+	return order + " LIMIT " + b.arg(q.Limit) + " OFFSET " + b.arg(q.Offset), nil
+}
+
+// transactionOrder adds q's cursor to b and returns the ORDER BY that
+// follows the WHERE clause. The ID breaks ties, so the order and cursor are
+// stable for transactions created at the same instant or of the same amount.
+func transactionOrder(b *sqlBuilder, q ports.TransactionQuery) (string, error) {
+	orderBy := q.OrderBy
+	if orderBy == "" {
+		orderBy = ports.TransactionOrderCreatedAt
+	}
+	column, ok := transactionOrderColumns[orderBy]
+	if !ok {
+		return "", fmt.Errorf("cannot order transactions by %q", orderBy)
+	}
+
+	direction, comparison := "DESC", "<"
+	if q.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	// The cursor must be one of the listed partner's transactions; an
+	// unknown cursor lists nothing
+	if q.After != nil {
+		cursor := fmt.Sprintf("(SELECT %s, id FROM transactions WHERE id = %s", column, b.arg(*q.After))
+		if q.PartnerID != nil {
+			cursor += " AND partner_id = " + b.arg(*q.PartnerID)
+		}
+		b.where(fmt.Sprintf("(%s, id) %s %s)", column, comparison, cursor))
+	}
+
+	return fmt.Sprintf(" ORDER BY %[1]s %[2]s, id %[2]s", column, direction), nil
+}
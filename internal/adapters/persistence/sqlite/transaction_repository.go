@@ -0,0 +1,517 @@
+// Package sqlite implements repository interfaces using SQLite, for running
+// the API locally and in CI without a database server. It mirrors package
+// postgres over the schema in migrations/sqlite: UUIDs and times are text,
+// arrays and JSONB are JSON text.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// TransactionRepository implements ports.TransactionRepository for SQLite
+type TransactionRepository struct {
+	db    *sql.DB
+	stmts *sqldb.Statements
+}
+
+// NewTransactionRepository creates a new SQLite transaction repository
+func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+	return &TransactionRepository{db: db, stmts: sqldb.NewStatements(db)}
+}
+
+// PrepareStatements prepares the queries of GetByID, GetByIdempotencyKey
+// and Update, the busiest of payment processing
+func (r *TransactionRepository) PrepareStatements(ctx context.Context) error {
+	return r.stmts.Prepare(ctx, transactionByIDQuery, transactionIdempotencyQuery, transactionUpdate)
+}
+
+// transactionInsert is the INSERT of Create and CreateBatch, to be followed
+// by a row of transactionValues per transaction
+const transactionInsert = `
+		INSERT INTO transactions (
+			id, partner_id, idempotency_key, amount, currency,
+			payment_method, provider, status, customer_email,
+			customer_name, customer_phone, description, metadata,
+			ip_address, user_agent, request_id, retry_count,
+			livemode, created_at, updated_at, payment_method_details,
+			billing_country
+		) VALUES `
+
+// Create creates a new transaction
+func (r *TransactionRepository) Create(ctx context.Context, txn *entities.Transaction) error {
+	err := insertRows(ctx, r.db, transactionInsert, [][]interface{}{transactionValues(txn)})
+	if err != nil {
+		if isDuplicateKey(err) {
+			return errors.ErrDuplicateTransaction
+		}
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return nil
+}
+
+// CreateBatch creates transactions with multi-row INSERTs
+func (r *TransactionRepository) CreateBatch(ctx context.Context, txns []*entities.Transaction) error {
+	rows := make([][]interface{}, len(txns))
+	for i, txn := range txns {
+		rows[i] = transactionValues(txn)
+	}
+
+	err := insertRows(ctx, r.db, transactionInsert, rows)
+	if err != nil {
+		if isDuplicateKey(err) {
+			return errors.ErrDuplicateTransaction
+		}
+		return fmt.Errorf("failed to create transactions: %w", err)
+	}
+	return nil
+}
+
+// transactionColumns are the columns scanTransaction reads
+const transactionColumns = `id, partner_id, idempotency_key, amount, currency,
+			   payment_method, provider, provider_transaction_id, status,
+			   customer_email, customer_name, customer_phone, description,
+			   metadata, ip_address, user_agent, request_id, COALESCE(error_code, ''),
+			   COALESCE(error_message, ''), retry_count, created_at, updated_at,
+			   processed_at, failed_at, refunded_amount, livemode,
+			   provider_credential_id, payment_method_details, billing_country,
+			   version`
+
+// transactionByIDQuery selects a transaction, not deleted, by ID
+const transactionByIDQuery = `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+// GetByID retrieves a transaction by ID
+func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+	txn, err := scanTransaction(preparedConn(ctx, r.stmts).QueryRowContext(ctx, transactionByIDQuery, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return txn, nil
+}
+
+// scanTransaction reads a row of transactionColumns, returning the row's
+// error, such as sql.ErrNoRows, as is
+func scanTransaction(row sqldb.RowScanner) (*entities.Transaction, error) {
+	var txn entities.Transaction
+	var metadataJSON, detailsJSON []byte
+	var paymentMethod string
+	var provider string
+	var status string
+	var providerTxnID, billingCountry sql.NullString
+	err := row.Scan(
+		&txn.ID,
+		&txn.PartnerID,
+		&txn.IdempotencyKey,
+		&txn.Amount,
+		&txn.Amount.Currency,
+		&paymentMethod,
+		&provider,
+		&providerTxnID,
+		&status,
+		&txn.CustomerEmail,
+		&txn.CustomerName,
+		&txn.CustomerPhone,
+		&txn.Description,
+		&metadataJSON,
+		&txn.IPAddress,
+		&txn.UserAgent,
+		&txn.RequestID,
+		&txn.ErrorCode,
+		&txn.ErrorMessage,
+		&txn.RetryCount,
+		&txn.CreatedAt,
+		&txn.UpdatedAt,
+		&txn.ProcessedAt,
+		&txn.FailedAt,
+		&txn.RefundedAmount,
+		&txn.Livemode,
+		&txn.ProviderCredentialID,
+		&detailsJSON,
+		&billingCountry,
+		&txn.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reconstruct value objects
+	txn.RefundedAmount.Currency = txn.Amount.Currency
+	txn.PaymentMethod, _ = valueobjects.NewPaymentMethod(paymentMethod)
+	txn.Provider, _ = valueobjects.NewPaymentProvider(provider)
+	txn.Status = entities.TransactionStatus(status)
+	if providerTxnID.Valid {
+		txn.ProviderTransactionID = providerTxnID.String
+	}
+	if billingCountry.Valid {
+		txn.BillingCountry, _ = valueobjects.NewCountry(billingCountry.String)
+	}
+	if len(metadataJSON) > 0 {
+		json.Unmarshal(metadataJSON, &txn.Metadata)
+	}
+	if len(detailsJSON) > 0 {
+		json.Unmarshal(detailsJSON, &txn.PaymentMethodDetails)
+	}
+	return &txn, nil
+}
+
+// transactionIdempotencyQuery finds the transaction of an idempotency key
+const transactionIdempotencyQuery = `
+		SELECT id FROM transactions
+		WHERE partner_id = ? AND idempotency_key = ? AND deleted_at IS NULL
+	`
+
+// GetByIdempotencyKey retrieves a transaction by partner and idempotency key
+func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, partnerID uuid.UUID, idempotencyKey string) (*entities.Transaction, error) {
+	var id uuid.UUID
+	err := preparedConn(ctx, r.stmts).QueryRowContext(ctx, transactionIdempotencyQuery, partnerID, idempotencyKey).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found, not an error
+		}
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// transactionUpdate saves a transaction at an expected version
+const transactionUpdate = `
+		UPDATE transactions SET
+			status = ?,
+			provider_transaction_id = ?,
+			error_code = ?,
+			error_message = ?,
+			retry_count = ?,
+			updated_at = ?,
+			processed_at = ?,
+			failed_at = ?,
+			provider_credential_id = ?,
+			payment_method_details = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+
+// Update updates an existing transaction if it is still at txn.Version, and
+// moves txn to the next version. A transaction changed since it was loaded is
+// left alone and a *errors.VersionConflictError returned.
+func (r *TransactionRepository) Update(ctx context.Context, txn *entities.Transaction) error {
+	result, err := preparedConn(ctx, r.stmts).ExecContext(ctx, transactionUpdate,
+		string(txn.Status),
+		txn.ProviderTransactionID,
+		txn.ErrorCode,
+		txn.ErrorMessage,
+		txn.RetryCount,
+		txn.UpdatedAt,
+		txn.ProcessedAt,
+		txn.FailedAt,
+		txn.ProviderCredentialID,
+		paymentMethodDetailsJSON(txn.PaymentMethodDetails),
+		txn.ID,
+		txn.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "transaction", txn.ID, txn.Version); err != nil {
+		return err
+	}
+	txn.Version++
+	return nil
+}
+
+// Delete saves txn's DeletedAt if it is still at txn.Version, and moves txn
+// to the next version
+func (r *TransactionRepository) Delete(ctx context.Context, txn *entities.Transaction) error {
+	query := `
+		UPDATE transactions SET
+			deleted_at = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, txn.DeletedAt, txn.UpdatedAt, txn.ID, txn.Version)
+	if err != nil {
+		return fmt.Errorf("failed to delete transaction: %w", err)
+	}
+
+	if err := sqldb.CheckVersionedUpdate(result, "transaction", txn.ID, txn.Version); err != nil {
+		return err
+	}
+	txn.Version++
+	return nil
+}
+
+// Restore clears the DeletedAt of a deleted transaction
+func (r *TransactionRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE transactions SET
+			deleted_at = NULL,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to restore transaction: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to restore transaction: %w", err)
+	}
+	if rows == 0 {
+		return errors.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// IncrementRetryCount moves a failed transaction with retries left back to
+// pending, counting the retry in the same statement
+func (r *TransactionRepository) IncrementRetryCount(ctx context.Context, txn *entities.Transaction, maxRetries int) error {
+	query := `
+		UPDATE transactions SET
+			status = ?,
+			retry_count = retry_count + 1,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND status = ? AND retry_count < ? AND deleted_at IS NULL
+		RETURNING retry_count, version
+	`
+	now := time.Now()
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		string(entities.StatusPending), now, txn.ID, string(entities.StatusFailed), maxRetries,
+	).Scan(&txn.RetryCount, &txn.Version)
+	if err == sql.ErrNoRows {
+		return errors.ErrRetryNotAllowed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to increment retry count: %w", err)
+	}
+
+	txn.Status = entities.StatusPending
+	txn.UpdatedAt = now
+	return nil
+}
+
+// AnonymizeCustomerData erases customer PII from all of a partner's transactions
+func (r *TransactionRepository) AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error) {
+	// customer_email is NOT NULL and ip_address is scanned into a string, so both get placeholders.
+	// The paths removed from payment_method_details are the fields
+	// PaymentMethodDetails.Anonymized clears.
+	query := `
+		UPDATE transactions SET
+			customer_email = 'anonymized@invalid',
+			customer_name = '',
+			customer_phone = '',
+			provider_customer_id = NULL,
+			metadata = '{}',
+			ip_address = '0.0.0.0',
+			user_agent = '',
+			payment_method_details = json_remove(payment_method_details, '$.card.fingerprint', '$.card.last4', '$.card.exp_month', '$.card.exp_year', '$.card.bin', '$.bank_account.last4', '$.wallet.account_id'),
+			version = version + 1,
+			updated_at = ?
+		WHERE partner_id = ? AND customer_email <> 'anonymized@invalid'
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), partnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize transactions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// processingSummaryQuery counts processed transactions by provider,
+// partner, outcome and latency bucket. julianday reads
+// the times SQLite stores as text.
+var processingSummaryQuery = `
+	SELECT provider, partner_id, processed_at IS NOT NULL, bucket, COUNT(*)
+	FROM (
+		SELECT provider, partner_id, processed_at,
+			   ` + sqldb.LatencyBucket("(julianday(COALESCE(processed_at, failed_at)) - julianday(created_at)) * 86400") + ` AS bucket
+		FROM transactions
+		WHERE created_at >= ?
+		  AND livemode = ?
+		  AND deleted_at IS NULL
+		  AND (processed_at IS NOT NULL OR failed_at IS NOT NULL)
+	) processed
+	GROUP BY provider, partner_id, processed_at IS NOT NULL, bucket
+`
+
+// GetProcessingSummary counts the processed transactions of a mode created
+// since since by provider, partner, outcome and latency
+func (r *TransactionRepository) GetProcessingSummary(ctx context.Context, since time.Time, livemode bool) ([]ports.ProcessingSummary, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, processingSummaryQuery, since, livemode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []ports.ProcessingSummary
+	for rows.Next() {
+		var summary ports.ProcessingSummary
+		var provider string
+		if err := rows.Scan(&provider, &summary.PartnerID, &summary.Succeeded, &summary.LatencyBucket, &summary.Count); err != nil {
+			return nil, err
+		}
+		summary.Provider = valueobjects.PaymentProvider(provider)
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// List retrieves the transactions matching q, one page at a time
+func (r *TransactionRepository) List(ctx context.Context, q ports.TransactionQuery) ([]*entities.Transaction, int64, error) {
+	b, err := transactionFilter(q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The total ignores paging and the cursor
+	countQuery := "SELECT COUNT(*) FROM transactions" + b.clause()
+	countArgs := append([]interface{}(nil), b.args...)
+
+	page, err := transactionPage(b, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	query := "SELECT id FROM transactions" + b.clause() + page
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	defer rows.Close()
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, 0, err
+		}
+		ids = append(ids, id)
+	}
+
+	// Get full transaction details
+	transactions := make([]*entities.Transaction, len(ids))
+	for i, id := range ids {
+		txn, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, 0, err
+		}
+		transactions[i] = txn
+	}
+
+	// Get total count (same filters, so test and live totals never mix)
+	var total int64
+	_ = conn(ctx, r.db).QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	return transactions, total, nil
+}
+
+// Stream calls fn with each transaction matching q as its row is read
+func (r *TransactionRepository) Stream(ctx context.Context, q ports.TransactionQuery, fn func(*entities.Transaction) error) error {
+	b, err := transactionFilter(q)
+	if err != nil {
+		return err
+	}
+	order, err := transactionOrder(b, q)
+	if err != nil {
+		return err
+	}
+
+	query := "SELECT " + transactionColumns + " FROM transactions" + b.clause() + order
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream transactions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		txn, err := scanTransaction(rows)
+		if err != nil {
+			return fmt.Errorf("failed to stream transactions: %w", err)
+		}
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream transactions: %w", err)
+	}
+	return nil
+}
+
+// GetByPartnerID retrieves transactions for a specific partner
+func (r *TransactionRepository) GetByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Transaction, error) {
+	q := ports.TransactionQuery{
+		PartnerID: &partnerID,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	txns, _, err := r.List(ctx, q)
+	return txns, err
+}
+
+// GetByStatus retrieves transactions by status
+func (r *TransactionRepository) GetByStatus(ctx context.Context, status entities.TransactionStatus, limit, offset int) ([]*entities.Transaction, error) {
+	q := ports.TransactionQuery{
+		Statuses: []entities.TransactionStatus{status},
+		Limit:    limit,
+		Offset:   offset,
+	}
+	txns, _, err := r.List(ctx, q)
+	return txns, err
+}
+
+// transactionValues returns the columns of transactionInsert for txn
+func transactionValues(txn *entities.Transaction) []interface{} {
+	metadataJSON, _ := json.Marshal(txn.Metadata)
+	return []interface{}{
+		txn.ID,
+		txn.PartnerID,
+		txn.IdempotencyKey,
+		txn.Amount,
+		txn.Amount.Currency,
+		txn.PaymentMethod.String(),
+		txn.Provider.String(),
+		string(txn.Status),
+		txn.CustomerEmail,
+		txn.CustomerName,
+		txn.CustomerPhone,
+		txn.Description,
+		string(metadataJSON),
+		txn.IPAddress,
+		txn.UserAgent,
+		txn.RequestID,
+		txn.RetryCount,
+		txn.Livemode,
+		txn.CreatedAt,
+		txn.UpdatedAt,
+		paymentMethodDetailsJSON(txn.PaymentMethodDetails),
+		sql.NullString{String: txn.BillingCountry.String(), Valid: txn.BillingCountry != ""},
+	}
+}
+
+// paymentMethodDetailsJSON stores unknown payment method details as NULL
+func paymentMethodDetailsJSON(details *valueobjects.PaymentMethodDetails) sql.NullString {
+	if details == nil {
+		return sql.NullString{}
+	}
+	detailsJSON, _ := json.Marshal(details)
+	return sql.NullString{String: string(detailsJSON), Valid: true}
+}
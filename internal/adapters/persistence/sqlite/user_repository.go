@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// UserRepository implements ports.UserRepository for SQLite
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new SQLite user repository
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	query := `
+		INSERT INTO users (
+			id, partner_id, email, name, role, password_hash,
+			created_at, updated_at
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?, ?
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.ID,
+		user.PartnerID,
+		user.Email,
+		user.Name,
+		user.Role.String(),
+		user.PasswordHash,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+
+	if err != nil {
+		if isDuplicateKey(err) {
+			return errors.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	return r.getOne(ctx, "id = ?", id)
+}
+
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.getOne(ctx, "email = LOWER(?)", email)
+}
+
+// ListByPartnerID retrieves a partner's team members
+func (r *UserRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.User, error) {
+	query := `
+		SELECT id, partner_id, email, name, role, password_hash,
+			   created_at, updated_at, last_login_at, deleted_at
+		FROM users
+		WHERE partner_id = ? AND deleted_at IS NULL
+		ORDER BY created_at
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// CountByRole counts a partner's team members holding a role
+func (r *UserRepository) CountByRole(ctx context.Context, partnerID uuid.UUID, role valueobjects.UserRole) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM users
+		WHERE partner_id = ? AND role = ? AND deleted_at IS NULL
+	`
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, partnerID, role.String()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update updates an existing user
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	query := `
+		UPDATE users SET
+			name = ?,
+			role = ?,
+			password_hash = ?,
+			updated_at = ?,
+			last_login_at = ?,
+			deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.Name,
+		user.Role.String(),
+		user.PasswordHash,
+		user.UpdatedAt,
+		user.LastLoginAt,
+		user.DeletedAt,
+		user.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return nil
+}
+
+// getOne retrieves a single active user matching the condition
+func (r *UserRepository) getOne(ctx context.Context, condition string, arg interface{}) (*entities.User, error) {
+	query := `
+		SELECT id, partner_id, email, name, role, password_hash,
+			   created_at, updated_at, last_login_at, deleted_at
+		FROM users
+		WHERE deleted_at IS NULL AND ` + condition
+
+	user, err := scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// scanUser reads a user from a row
+func scanUser(row sqldb.RowScanner) (*entities.User, error) {
+	var user entities.User
+	var role string
+
+	err := row.Scan(
+		&user.ID,
+		&user.PartnerID,
+		&user.Email,
+		&user.Name,
+		&role,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.LastLoginAt,
+		&user.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = valueobjects.UserRole(role)
+
+	return &user, nil
+}
+
+// UserSessionRepository implements ports.UserSessionRepository for SQLite
+type UserSessionRepository struct {
+	db *sql.DB
+}
+
+// NewUserSessionRepository creates a new SQLite user session repository
+func NewUserSessionRepository(db *sql.DB) *UserSessionRepository {
+	return &UserSessionRepository{db: db}
+}
+
+// Create creates a new session
+func (r *UserSessionRepository) Create(ctx context.Context, session *entities.UserSession) error {
+	query := `
+		INSERT INTO user_sessions (id, user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.TokenHash,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTokenHash retrieves a session by the hash of its token
+func (r *UserSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.UserSession, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+		FROM user_sessions
+		WHERE token_hash = ?
+	`
+
+	var session entities.UserSession
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, tokenHash).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenHash,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// Update updates an existing session
+func (r *UserSessionRepository) Update(ctx context.Context, session *entities.UserSession) error {
+	query := `UPDATE user_sessions SET revoked_at = ? WHERE id = ?`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, session.RevokedAt, session.ID); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
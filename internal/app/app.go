@@ -0,0 +1,161 @@
+// Package app wires the Pay2Go API server. Each feature is built by a
+// constructor of its own in a wire_*.go file, from the platform and data
+// layers and the features it depends on; App runs the HTTP and gRPC servers
+// and the background work until shutdown.
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	grpcapi "Pay2Go/internal/adapters/grpc"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/logger"
+)
+
+// App is the API server with everything it serves wired
+type App struct {
+	platform   *platform
+	data       *data
+	auditing   *auditing
+	access     *access
+	partners   *partners
+	payments   *payments
+	events     *events
+	reports    *reports
+	operations *operations
+
+	http *fiber.App
+	// grpc is nil unless the gRPC API is served
+	grpc       *grpcapi.Server
+	background *backgroundWork
+
+	// failed receives the error of a server that stopped serving
+	failed chan error
+}
+
+// New connects to what cfg configures and wires the API. Close releases
+// what it opened, also when New fails.
+func New(cfg *config.Config, appLogger *logger.Logger) (a *App, err error) {
+	a = &App{failed: make(chan error, 2)}
+	if a.platform, err = newPlatform(cfg, appLogger); err != nil {
+		return a, err
+	}
+	if a.data, err = newData(a.platform); err != nil {
+		return a, err
+	}
+	a.auditing = newAuditing(a.platform, a.data)
+	a.access = newAccess(a.platform, a.data, a.auditing)
+	a.partners = newPartners(a.platform, a.data, a.auditing, a.access)
+	if a.payments, err = newPayments(a.platform, a.data, a.auditing, a.partners); err != nil {
+		return a, err
+	}
+	if a.events, err = newEvents(a.platform, a.data, a.auditing); err != nil {
+		return a, err
+	}
+	if a.reports, err = newReports(a.platform, a.data, a.auditing, a.partners, a.events); err != nil {
+		return a, err
+	}
+	if a.operations, err = newOperations(a.platform, a.data, a.payments, a.events); err != nil {
+		return a, err
+	}
+	a.http, err = newHTTPServer(
+		a.platform,
+		a.data,
+		a.auditing,
+		a.access,
+		a.partners,
+		a.payments,
+		a.events,
+		a.reports,
+		a.operations,
+	)
+	if err != nil {
+		return a, err
+	}
+	if cfg.Server.GRPCAddr != "" {
+		a.grpc = newGRPCServer(a.platform, a.data, a.access, a.payments, a.operations)
+	}
+	return a, nil
+}
+
+// Start starts the background work and the servers. A server that stops
+// serving afterwards reports to Failed.
+func (a *App) Start() error {
+	cfg, log := a.platform.cfg, a.platform.log
+	a.background = a.startBackground()
+
+	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
+	go func() {
+		log.Info("Server starting on %s", addr)
+		if err := a.http.Listen(addr); err != nil {
+			a.failed <- fmt.Errorf("server failed to start: %w", err)
+		}
+	}()
+
+	if a.grpc != nil {
+		listener, err := net.Listen("tcp", cfg.Server.GRPCAddr)
+		if err != nil {
+			return fmt.Errorf("gRPC server failed to start: %w", err)
+		}
+		go func() {
+			log.Info("gRPC server starting on %s", cfg.Server.GRPCAddr)
+			if err := a.grpc.Serve(listener); err != nil {
+				a.failed <- fmt.Errorf("gRPC server failed: %w", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// Failed receives the error of a server that stopped serving
+func (a *App) Failed() <-chan error {
+	return a.failed
+}
+
+// Shutdown stops accepting connections and drains the requests in flight,
+// which may still queue audit logs and webhooks, then lets the background
+// work finish. Both share the grace period of SHUTDOWN_TIMEOUT_SECONDS.
+func (a *App) Shutdown() {
+	cfg, log := a.platform.cfg, a.platform.log
+	gracePeriod := time.Duration(cfg.Server.ShutdownTimeoutSeconds) * time.Second
+	deadline := time.Now().Add(gracePeriod)
+
+	// Event streams and WebSockets stay open until ended; their clients
+	// reconnect elsewhere
+	a.events.streamHandler.Close()
+	a.payments.transactionSocketHandler.Close()
+	// gRPC health turns NOT_SERVING, so clients move elsewhere while the
+	// HTTP server drains
+	if a.grpc != nil {
+		a.grpc.Close()
+	}
+	if err := a.http.ShutdownWithTimeout(gracePeriod); err != nil {
+		log.Error("Requests still in flight after %s: %v", gracePeriod, err)
+	}
+	if a.grpc != nil {
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		if err := a.grpc.Stop(ctx); err != nil {
+			log.Error("gRPC calls still in flight after %s: %v", gracePeriod, err)
+		}
+		cancel()
+	}
+	if !a.background.shutdown(deadline) {
+		log.Error("Background work still running after %s; queued audit logs may be lost", gracePeriod)
+	}
+	log.Info("Server stopped")
+	if a.platform.logShipper != nil {
+		a.platform.logShipper.Flush()
+	}
+}
+
+// Close closes the database, Redis, the event broker and the log files
+func (a *App) Close() {
+	if a.platform != nil {
+		a.platform.close()
+	}
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/infrastructure/heartbeat"
+)
+
+// backgroundWork runs what the API does outside requests. Shutdown stops it
+// from starting anything new and lets what is in flight finish until a
+// deadline.
+type backgroundWork struct {
+	// stopping is done once shutdown begins
+	stopping context.Context
+	stop     context.CancelFunc
+	// ctx is what the work runs with; it is done once the deadline passes
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// heartbeats is told about every pass of the periodic work
+	heartbeats *heartbeat.Monitor
+}
+
+func newBackgroundWork(heartbeats *heartbeat.Monitor) *backgroundWork {
+	b := &backgroundWork{heartbeats: heartbeats}
+	b.stopping, b.stop = context.WithCancel(context.Background())
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	return b
+}
+
+// run calls fn in the background with a context that is done once shutdown
+// begins. Shutdown waits for fn to return, so it can finish what it holds.
+func (b *backgroundWork) run(fn func(ctx context.Context)) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		fn(b.stopping)
+	}()
+}
+
+// every calls fn every interval until shutdown begins. A call in flight then
+// finishes, unless it outlasts the deadline and sees its context done. Each
+// call that returns is a heartbeat of name, so a loop stuck in fn shows up
+// as stalled.
+func (b *backgroundWork) every(name string, interval time.Duration, fn func(ctx context.Context)) {
+	b.heartbeats.Register(name, interval)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fn(b.ctx)
+				b.heartbeats.Beat(name)
+			case <-b.stopping.Done():
+				return
+			}
+		}
+	}()
+}
+
+// shutdown stops the work and waits for it until deadline, then cancels what
+// is left. It reports whether everything finished in time.
+func (b *backgroundWork) shutdown(deadline time.Time) bool {
+	b.stop()
+	defer b.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"time"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/infrastructure/heartbeat"
+	"Pay2Go/internal/infrastructure/metrics"
+	"Pay2Go/internal/usecases/outbox"
+)
+
+// registerRuntimeMetrics exposes what the database pools, the webhook relay,
+// the outbound transport and the background heartbeats already count, read
+// at every scrape
+func registerRuntimeMetrics(
+	r *metrics.Registry,
+	pools map[string]handlers.DBStatsSource,
+	relay *outbox.Relay,
+	transport handlers.HTTPTransportStatsSource,
+	heartbeats *heartbeat.Monitor,
+) {
+	r.GaugeFunc("pay2go_db_connections",
+		"Database connections, by pool and state (in_use or idle).",
+		[]string{"pool", "state"},
+		func(set func(float64, ...string)) {
+			for name, pool := range pools {
+				stats := pool.Stats()
+				set(float64(stats.InUse), name, "in_use")
+				set(float64(stats.Idle), name, "idle")
+			}
+		})
+	r.CounterFunc("pay2go_db_connection_waits_total",
+		"Times a query waited for a free database connection, by pool.",
+		[]string{"pool"},
+		func(set func(float64, ...string)) {
+			for name, pool := range pools {
+				set(float64(pool.Stats().WaitCount), name)
+			}
+		})
+
+	r.GaugeFunc("pay2go_webhook_relay_events",
+		"Webhook events of the current relay pass, by state (queued or in_flight).",
+		[]string{"state"},
+		func(set func(float64, ...string)) {
+			stats := relay.Stats()
+			set(float64(stats.Queued), "queued")
+			set(float64(stats.InFlight), "in_flight")
+		})
+
+	r.CounterFunc("pay2go_http_client_requests_total",
+		"Outbound HTTP requests to providers and partners, by outcome (completed or failed).",
+		[]string{"outcome"},
+		func(set func(float64, ...string)) {
+			stats := transport.Stats()
+			set(float64(stats.Requests-stats.Failed), "completed")
+			set(float64(stats.Failed), "failed")
+		})
+	r.CounterFunc("pay2go_http_client_connections_total",
+		"Outbound connections, by whether they were opened or reused.",
+		[]string{"reuse"},
+		func(set func(float64, ...string)) {
+			stats := transport.Stats()
+			set(float64(stats.ConnectionsOpened), "opened")
+			set(float64(stats.ConnectionsReused), "reused")
+		})
+
+	r.GaugeFunc("pay2go_background_heartbeat_age_seconds",
+		"Seconds since a background loop last completed a pass, by loop.",
+		[]string{"loop"},
+		func(set func(float64, ...string)) {
+			now := time.Now()
+			for _, loop := range heartbeats.Loops() {
+				set(loop.Age(now).Seconds(), loop.Name)
+			}
+		})
+	r.GaugeFunc("pay2go_background_loop_stalled",
+		"1 while a background loop has missed too many passes, by loop.",
+		[]string{"loop"},
+		func(set func(float64, ...string)) {
+			for _, loop := range heartbeats.Loops() {
+				stalled := 0.0
+				if loop.Stalled {
+					stalled = 1
+				}
+				set(stalled, loop.Name)
+			}
+		})
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+
+	"Pay2Go/internal/adapters/persistence/mysql"
+	"Pay2Go/internal/adapters/persistence/postgres"
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/adapters/persistence/sqlite"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/lock"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// repositories are the persistence adapters of the configured database
+type repositories struct {
+	transactions        ports.TransactionRepository
+	partners            ports.PartnerRepository
+	cardBINs            ports.CardBINRepository
+	refunds             ports.RefundRepository
+	bulkRefundJobs      ports.BulkRefundJobRepository
+	paymentJobs         ports.PaymentJobRepository
+	exportJobs          ports.ExportJobRepository
+	settlementEntries   ports.SettlementEntryRepository
+	disputes            ports.DisputeRepository
+	statements          ports.StatementRepository
+	reportSchedules     ports.ReportScheduleRepository
+	apiKeys             ports.APIKeyRepository
+	providerCredentials ports.ProviderCredentialRepository
+	users               ports.UserRepository
+	userSessions        ports.UserSessionRepository
+	adminUsers          ports.AdminUserRepository
+	adminSessions       ports.AdminSessionRepository
+	outbox              ports.OutboxRepository
+	auditLogs           ports.AuditLogRepository
+	usage               ports.UsageRepository
+	analytics           ports.AnalyticsRepository
+	unitOfWork          ports.UnitOfWork
+
+	// secretRotators re-encrypt the secrets the repositories above store
+	secretRotators []ports.SecretRotator
+
+	// partitions creates the monthly partitions of transactions and refunds;
+	// nil when the database does not partition them
+	partitions ports.PartitionManager
+
+	// statementPreparers prepare the busiest queries of the repositories above
+	statementPreparers []statementPreparer
+
+	// locker holds locks in the database; with sqlite, which one instance
+	// serves, they are held in process
+	locker ports.Locker
+}
+
+// statementPreparer prepares a repository's busiest queries, see
+// sqldb.Statements
+type statementPreparer interface {
+	PrepareStatements(ctx context.Context) error
+}
+
+// newRepositories creates the repositories for driver. replica may be nil,
+// and is not used with sqlite.
+func newRepositories(driver string, db *sql.DB, replica *sqldb.Replica, cipher ports.SecretCipher) repositories {
+	switch driver {
+	case config.DriverMySQL:
+		partners := mysql.NewPartnerRepository(db, replica, cipher)
+		apiKeys := mysql.NewAPIKeyRepository(db, cipher)
+		transactions := mysql.NewTransactionRepository(db, replica)
+		providerCredentials := mysql.NewProviderCredentialRepository(db, cipher)
+		adminUsers := mysql.NewAdminUserRepository(db, cipher)
+		reportSchedules := mysql.NewReportScheduleRepository(db, cipher)
+
+		return repositories{
+			transactions:        transactions,
+			partners:            partners,
+			cardBINs:            mysql.NewCardBINRepository(db),
+			refunds:             mysql.NewRefundRepository(db, replica),
+			bulkRefundJobs:      mysql.NewBulkRefundJobRepository(db),
+			paymentJobs:         mysql.NewPaymentJobRepository(db),
+			exportJobs:          mysql.NewExportJobRepository(db),
+			settlementEntries:   mysql.NewSettlementEntryRepository(db),
+			disputes:            mysql.NewDisputeRepository(db),
+			statements:          mysql.NewStatementRepository(db),
+			reportSchedules:     reportSchedules,
+			apiKeys:             apiKeys,
+			providerCredentials: providerCredentials,
+			users:               mysql.NewUserRepository(db),
+			userSessions:        mysql.NewUserSessionRepository(db),
+			adminUsers:          adminUsers,
+			adminSessions:       mysql.NewAdminSessionRepository(db),
+			outbox:              mysql.NewOutboxRepository(db),
+			auditLogs:           mysql.NewAuditLogRepository(db, replica),
+			usage:               mysql.NewUsageRepository(db, replica),
+			analytics:           mysql.NewAnalyticsRepository(db, replica),
+			unitOfWork:          sqldb.NewUnitOfWork(db),
+			secretRotators:      []ports.SecretRotator{partners, apiKeys, providerCredentials, adminUsers, reportSchedules},
+			statementPreparers:  []statementPreparer{transactions, apiKeys},
+			locker:              mysql.NewNamedLocker(db),
+		}
+	case config.DriverSQLite:
+		partners := sqlite.NewPartnerRepository(db, cipher)
+		apiKeys := sqlite.NewAPIKeyRepository(db, cipher)
+		transactions := sqlite.NewTransactionRepository(db)
+		providerCredentials := sqlite.NewProviderCredentialRepository(db, cipher)
+		adminUsers := sqlite.NewAdminUserRepository(db, cipher)
+		reportSchedules := sqlite.NewReportScheduleRepository(db, cipher)
+
+		return repositories{
+			transactions:        transactions,
+			partners:            partners,
+			cardBINs:            sqlite.NewCardBINRepository(db),
+			refunds:             sqlite.NewRefundRepository(db),
+			bulkRefundJobs:      sqlite.NewBulkRefundJobRepository(db),
+			paymentJobs:         sqlite.NewPaymentJobRepository(db),
+			exportJobs:          sqlite.NewExportJobRepository(db),
+			settlementEntries:   sqlite.NewSettlementEntryRepository(db),
+			disputes:            sqlite.NewDisputeRepository(db),
+			statements:          sqlite.NewStatementRepository(db),
+			reportSchedules:     reportSchedules,
+			apiKeys:             apiKeys,
+			providerCredentials: providerCredentials,
+			users:               sqlite.NewUserRepository(db),
+			userSessions:        sqlite.NewUserSessionRepository(db),
+			adminUsers:          adminUsers,
+			adminSessions:       sqlite.NewAdminSessionRepository(db),
+			outbox:              sqlite.NewOutboxRepository(db),
+			auditLogs:           sqlite.NewAuditLogRepository(db),
+			usage:               sqlite.NewUsageRepository(db),
+			analytics:           sqlite.NewAnalyticsRepository(db),
+			unitOfWork:          sqldb.NewUnitOfWork(db),
+			secretRotators:      []ports.SecretRotator{partners, apiKeys, providerCredentials, adminUsers, reportSchedules},
+			statementPreparers:  []statementPreparer{transactions, apiKeys},
+			locker:              lock.NewMemoryLocker(),
+		}
+	}
+
+	partners := postgres.NewPartnerRepository(db, replica, cipher)
+	apiKeys := postgres.NewAPIKeyRepository(db, cipher)
+	transactions := postgres.NewTransactionRepository(db, replica)
+	providerCredentials := postgres.NewProviderCredentialRepository(db, cipher)
+	adminUsers := postgres.NewAdminUserRepository(db, cipher)
+	reportSchedules := postgres.NewReportScheduleRepository(db, cipher)
+
+	return repositories{
+		transactions:        transactions,
+		partners:            partners,
+		cardBINs:            postgres.NewCardBINRepository(db),
+		refunds:             postgres.NewRefundRepository(db, replica),
+		bulkRefundJobs:      postgres.NewBulkRefundJobRepository(db),
+		paymentJobs:         postgres.NewPaymentJobRepository(db),
+		exportJobs:          postgres.NewExportJobRepository(db),
+		settlementEntries:   postgres.NewSettlementEntryRepository(db),
+		disputes:            postgres.NewDisputeRepository(db),
+		statements:          postgres.NewStatementRepository(db),
+		reportSchedules:     reportSchedules,
+		apiKeys:             apiKeys,
+		providerCredentials: providerCredentials,
+		users:               postgres.NewUserRepository(db),
+		userSessions:        postgres.NewUserSessionRepository(db),
+		adminUsers:          adminUsers,
+		adminSessions:       postgres.NewAdminSessionRepository(db),
+		outbox:              postgres.NewOutboxRepository(db),
+		auditLogs:           postgres.NewAuditLogRepository(db, replica),
+		usage:               postgres.NewUsageRepository(db, replica),
+		analytics:           postgres.NewAnalyticsRepository(db, replica),
+		unitOfWork:          sqldb.NewUnitOfWork(db),
+		secretRotators:      []ports.SecretRotator{partners, apiKeys, providerCredentials, adminUsers, reportSchedules},
+		partitions:          postgres.NewPartitionManager(db),
+		statementPreparers:  []statementPreparer{transactions, apiKeys},
+		locker:              postgres.NewAdvisoryLocker(db),
+	}
+}
+
+// replicaLagQuery returns the query measuring how far a read replica of
+// driver is behind
+func replicaLagQuery(driver string) string {
+	if driver == config.DriverMySQL {
+		return mysql.ReplicaLagQuery
+	}
+	return postgres.ReplicaLagQuery
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/heartbeat"
+	"Pay2Go/internal/infrastructure/logger"
+)
+
+// newServingApp is an App serving slow on GET /slow, with what Shutdown
+// stops, and where it logs
+func newServingApp(t *testing.T, gracePeriod time.Duration, slow fiber.Handler) (*App, string, *bytes.Buffer) {
+	t.Helper()
+	var logs bytes.Buffer
+	cfg := &config.Config{}
+	cfg.Server.ShutdownTimeoutSeconds = int(gracePeriod / time.Second)
+	a := &App{
+		platform: &platform{
+			cfg:        cfg,
+			log:        logger.NewWithSink(logger.NewWriterSink(&logs, logger.FormatText)),
+			heartbeats: heartbeat.NewMonitor(3),
+		},
+		events:   &events{streamHandler: handlers.NewEventStreamHandler(nil, time.Second)},
+		payments: &payments{transactionSocketHandler: handlers.NewTransactionSocketHandler(nil, nil, time.Second)},
+		http:     fiber.New(fiber.Config{DisableStartupMessage: true}),
+	}
+	a.http.Get("/slow", slow)
+	a.background = newBackgroundWork(a.platform.heartbeats)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	go func() { _ = a.http.Listener(listener) }()
+	return a, "http://" + listener.Addr().String(), &logs
+}
+
+func TestShutdown_DrainsRequestsAndBackgroundWork(t *testing.T) {
+	handling := make(chan struct{})
+	a, url, logs := newServingApp(t, 5*time.Second, func(c *fiber.Ctx) error {
+		close(handling)
+		time.Sleep(300 * time.Millisecond)
+		return c.SendString("done")
+	})
+
+	// A worker that writes what it holds once shutdown begins, and a
+	// periodic one in the middle of a pass
+	var flushed, passed atomic.Bool
+	a.background.run(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(200 * time.Millisecond)
+		flushed.Store(true)
+	})
+	passing := make(chan struct{})
+	a.background.every("test", 10*time.Millisecond, func(ctx context.Context) {
+		if passed.Load() {
+			return
+		}
+		select {
+		case <-passing:
+		default:
+			close(passing)
+		}
+		time.Sleep(400 * time.Millisecond)
+		if ctx.Err() == nil {
+			passed.Store(true)
+		}
+	})
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- result{status: resp.StatusCode, body: string(body)}
+	}()
+	<-handling
+	<-passing
+
+	a.Shutdown()
+
+	// The request in flight was answered before Shutdown returned
+	select {
+	case r := <-response:
+		if r.err != nil || r.status != fiber.StatusOK || r.body != "done" {
+			t.Errorf("request in flight = %d %q, %v; want it answered", r.status, r.body, r.err)
+		}
+	default:
+		t.Error("Shutdown() returned before the request in flight was answered")
+	}
+	if !flushed.Load() {
+		t.Error("Shutdown() returned before the worker finished")
+	}
+	if !passed.Load() {
+		t.Error("Shutdown() cancelled the periodic pass in flight")
+	}
+	if strings.Contains(logs.String(), "still") {
+		t.Errorf("Shutdown() logged work left over: %s", logs.String())
+	}
+
+	// Nothing is served afterwards
+	if resp, err := http.Get(url + "/slow"); err == nil {
+		resp.Body.Close()
+		t.Error("server still serves after Shutdown()")
+	}
+}
+
+func TestShutdown_CancelsWorkPastTheGracePeriod(t *testing.T) {
+	a, _, logs := newServingApp(t, time.Second, func(c *fiber.Ctx) error {
+		return c.SendString("done")
+	})
+
+	// A periodic pass that would outlast the grace period
+	cancelled := make(chan struct{})
+	passing := make(chan struct{})
+	a.background.every("stuck", 10*time.Millisecond, func(ctx context.Context) {
+		select {
+		case <-passing:
+			return
+		default:
+			close(passing)
+		}
+		<-ctx.Done()
+		close(cancelled)
+	})
+	<-passing
+
+	start := time.Now()
+	a.Shutdown()
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 3*time.Second {
+		t.Errorf("Shutdown() took %v, want the grace period of 1s", elapsed)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("the pass outlasting the grace period was not cancelled")
+	}
+	if !strings.Contains(logs.String(), "Background work still running after 1s") {
+		t.Errorf("logs = %s, want the work left over reported", logs.String())
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"time"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/admin"
+	"Pay2Go/internal/usecases/apikey"
+	"Pay2Go/internal/usecases/credential"
+	"Pay2Go/internal/usecases/user"
+)
+
+// access is who may call the API: partners' API keys, their dashboard
+// users and provider credentials, and Pay2Go's admins
+type access struct {
+	createAPIKey  *apikey.CreateAPIKeyUseCase
+	getAPIKey     *apikey.GetAPIKeyUseCase
+	listAPIKeys   *apikey.ListAPIKeysUseCase
+	revokeAPIKey  *apikey.RevokeAPIKeyUseCase
+	authenticator *apikey.Authenticator
+	// usageTracker writes when keys were last used in the background
+	usageTracker *apikey.UsageTracker
+	failedAuth   *apikey.FailedAuthGuard
+
+	auth      *middleware.AuthMiddleware
+	adminAuth *middleware.AdminAuthMiddleware
+
+	apiKeyHandler     *handlers.APIKeyHandler
+	credentialHandler *handlers.ProviderCredentialHandler
+	userHandler       *handlers.UserHandler
+	authHandler       *handlers.AuthHandler
+	adminAuthHandler  *handlers.AdminAuthHandler
+}
+
+func newAccess(p *platform, d *data, audits *auditing) *access {
+	cfg := p.cfg
+	repos := d.repos
+	a := &access{
+		createAPIKey:  apikey.NewCreateAPIKeyUseCase(repos.apiKeys, audits.logger),
+		getAPIKey:     apikey.NewGetAPIKeyUseCase(repos.apiKeys),
+		listAPIKeys:   apikey.NewListAPIKeysUseCase(repos.apiKeys),
+		revokeAPIKey:  apikey.NewRevokeAPIKeyUseCase(repos.apiKeys, audits.logger),
+		authenticator: apikey.NewAuthenticator(repos.apiKeys),
+		usageTracker:  apikey.NewUsageTracker(repos.apiKeys, time.Minute),
+		failedAuth: apikey.NewFailedAuthGuard(d.rateLimits, audits.logger, apikey.LockoutPolicy{
+			MaxFailuresPerKey: cfg.Security.AuthMaxFailuresPerKey,
+			MaxFailuresPerIP:  cfg.Security.AuthMaxFailuresPerIP,
+			Window:            time.Duration(cfg.Security.AuthLockoutMinutes) * time.Minute,
+		}),
+	}
+	a.apiKeyHandler = handlers.NewAPIKeyHandler(a.createAPIKey, a.listAPIKeys, a.revokeAPIKey)
+
+	a.credentialHandler = handlers.NewProviderCredentialHandler(
+		credential.NewSaveProviderCredentialUseCase(repos.providerCredentials, audits.logger),
+		credential.NewListProviderCredentialsUseCase(repos.providerCredentials),
+		credential.NewDeleteProviderCredentialUseCase(repos.providerCredentials, audits.logger),
+	)
+
+	a.userHandler = handlers.NewUserHandler(
+		user.NewCreateUserUseCase(repos.users, audits.logger),
+		user.NewListUsersUseCase(repos.users),
+		user.NewUpdateUserRoleUseCase(repos.users, audits.logger),
+		user.NewRemoveUserUseCase(repos.users, audits.logger),
+	)
+	a.authHandler = handlers.NewAuthHandler(
+		user.NewLoginUseCase(
+			repos.users,
+			repos.userSessions,
+			audits.logger,
+			time.Duration(cfg.Security.SessionTTLHours)*time.Hour,
+		),
+		user.NewLogoutUseCase(repos.userSessions),
+	)
+	a.adminAuthHandler = handlers.NewAdminAuthHandler(
+		admin.NewLoginUseCase(
+			repos.adminUsers,
+			repos.adminSessions,
+			audits.logger,
+			time.Duration(cfg.Security.AdminSessionTTLHours)*time.Hour,
+		),
+		admin.NewLogoutUseCase(repos.adminSessions),
+	)
+
+	// Initialize authentication
+	a.auth = middleware.NewAuthMiddleware(
+		d.partners,
+		a.authenticator,
+		a.usageTracker,
+		a.failedAuth,
+		repos.users,
+		repos.userSessions,
+		d.rateLimits,
+	)
+	a.adminAuth = middleware.NewAdminAuthMiddleware(repos.adminUsers, repos.adminSessions)
+	return a
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"time"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/usecases/audit"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// auditing records what partners, users and admins do, in a hash chain the
+// background verifies
+type auditing struct {
+	// logger stamps actions with their request; features log through it
+	logger ports.AuditLogger
+	// async writes the log in the background; nil unless AUDIT_ASYNC=true
+	async     *audit.AsyncLogger
+	verifyAll *audit.VerifyAllAuditChainsUseCase
+	handler   *handlers.AuditLogHandler
+}
+
+func newAuditing(p *platform, d *data) *auditing {
+	cfg := p.cfg
+	a := &auditing{}
+
+	// Write audit logs in the background so requests do not wait on them
+	var auditLogger ports.AuditLogger = d.auditLogs
+	if cfg.Audit.Async {
+		a.async = audit.NewAsyncLogger(
+			d.auditLogs,
+			cfg.Audit.BufferSize,
+			cfg.Audit.BatchSize,
+			time.Duration(cfg.Audit.FlushIntervalMs)*time.Millisecond,
+			func(action ports.AuditAction, err error) {
+				p.log.Error("Failed to write audit log %s of %s %s: %v", action.Action, action.ResourceType, action.ResourceID, err)
+			},
+		)
+		auditLogger = a.async
+	}
+	// Stamp actions with the request they were taken in while its context
+	// is at hand
+	a.logger = audit.NewRequestLogger(auditLogger)
+
+	verifyAuditChainUC := audit.NewVerifyAuditChainUseCase(d.auditLogs)
+	a.verifyAll = audit.NewVerifyAllAuditChainsUseCase(d.auditLogs, verifyAuditChainUC)
+	a.handler = handlers.NewAuditLogHandler(
+		audit.NewListAuditLogsUseCase(d.auditLogs),
+		audit.NewListAllAuditLogsUseCase(d.auditLogs),
+		verifyAuditChainUC,
+		a.verifyAll,
+	)
+	return a
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"Pay2Go/internal/infrastructure/providerhealth"
+)
+
+// startBackground starts the work the API does outside requests; shutdown
+// lets what is in flight finish
+func (a *App) startBackground() *backgroundWork {
+	p, d, cfg, log := a.platform, a.data, a.platform.cfg, a.platform.log
+	background := newBackgroundWork(p.heartbeats)
+
+	// Write API key usage in the background
+	background.run(a.access.usageTracker.Run)
+
+	// Write metered partner usage; shutdown writes what is left
+	background.run(a.payments.usageMeter.Run)
+
+	// Write queued audit logs; shutdown waits for the last of them
+	if a.auditing.async != nil {
+		background.run(a.auditing.async.Run)
+	}
+
+	// Ship logs to the collector; shutdown ships those still queued
+	if p.logShipper != nil {
+		background.run(p.logShipper.Run)
+	}
+
+	// Send error reports; shutdown sends those still queued
+	if p.sentry != nil {
+		background.run(p.sentry.Run)
+	}
+
+	// Send queued emails; shutdown sends those still queued
+	if a.events.notifier != nil {
+		background.run(a.events.notifier.Run)
+	}
+
+	// Anonymize customer data of off-boarded partners once their retention period ends
+	background.every("anonymization", time.Hour, func(ctx context.Context) {
+		if n, err := a.partners.anonymize.Execute(ctx); err != nil {
+			log.Error("Customer data anonymization failed: %v", err)
+		} else if n > 0 {
+			log.Info("Anonymized customer data of %d off-boarded partners", n)
+		}
+	})
+
+	// Publish webhooks recorded in the outbox; a batch being delivered at
+	// shutdown is finished, the rest waits for the next instance
+	if cfg.Outbox.RelayEnabled {
+		background.every("outbox_relay", time.Duration(cfg.Outbox.PollIntervalSeconds)*time.Second, func(ctx context.Context) {
+			if _, err := a.events.relay.PublishDue(ctx); err != nil {
+				log.Error("Outbox relay failed: %v", err)
+			}
+		})
+	}
+
+	// Probe providers so their health is known without live traffic
+	if cfg.Providers.PollIntervalSeconds > 0 {
+		probes := make(map[string][]providerhealth.Probe)
+		probeClient := p.transport.Client(10 * time.Second)
+		for provider, url := range cfg.Providers.StatusURLs {
+			probes[provider] = append(probes[provider], providerhealth.StatusPageProbe(url, probeClient))
+		}
+		if cfg.Providers.CanaryTransactionID != "" {
+			gateway := a.payments.platformGateway
+			provider := gateway.GetProviderName()
+			probes[provider] = append(probes[provider], providerhealth.CanaryProbe(gateway, cfg.Providers.CanaryTransactionID))
+		}
+		if len(probes) > 0 {
+			poller := providerhealth.NewPoller(a.payments.providerHealth, probes, 10*time.Second)
+			background.every("provider_probes", time.Duration(cfg.Providers.PollIntervalSeconds)*time.Second, poller.Poll)
+		}
+	}
+
+	// Keep the monthly partitions of transactions and refunds created ahead
+	if d.repos.partitions != nil {
+		ensurePartitions := func(ctx context.Context) {
+			created, err := d.repos.partitions.EnsurePartitions(ctx, time.Now(), cfg.Database.PartitionMonthsAhead)
+			if err != nil {
+				log.Error("Partition creation failed: %v", err)
+			}
+			for _, name := range created {
+				log.Info("Created partition %s", name)
+			}
+		}
+		ensurePartitions(context.Background())
+		background.every("partitions", 24*time.Hour, ensurePartitions)
+	}
+
+	// Check that no audit entry was changed or removed since it was logged
+	if cfg.Audit.VerifyIntervalMinutes > 0 {
+		background.every("audit_verification", time.Duration(cfg.Audit.VerifyIntervalMinutes)*time.Minute, func(ctx context.Context) {
+			reports, err := a.auditing.verifyAll.Execute(ctx)
+			if err != nil {
+				log.Error("Audit chain verification failed: %v", err)
+				return
+			}
+			for _, report := range reports {
+				if !report.Intact() {
+					log.Error("Audit chain of partner %s is broken at entry %d: %s",
+						report.PartnerID, report.Break.EntryID, report.Break.Reason)
+				}
+			}
+		})
+	}
+
+	// Drop partners changed on other instances from the local cache
+	if d.cachedPartners != nil {
+		background.run(func(ctx context.Context) {
+			if err := d.cachedPartners.Run(ctx); err != nil {
+				log.Error("Partner cache invalidations stopped: %v", err)
+			}
+		})
+	}
+
+	// Move old audit entries and published outbox events to the archive
+	if d.archiveEvents != nil {
+		background.every("archive", time.Duration(cfg.Archive.IntervalMinutes)*time.Minute, func(ctx context.Context) {
+			result, err := d.archiveEvents.Execute(ctx)
+			if err != nil {
+				log.Error("Archiving failed: %v", err)
+			}
+			if result.AuditLogs > 0 || result.OutboxEvents > 0 {
+				log.Info("Archived %d audit logs and %d outbox events", result.AuditLogs, result.OutboxEvents)
+			}
+		})
+	}
+
+	// Run queued bulk refunds; shutdown finishes the refund in flight and
+	// hands the jobs back to the next instance
+	background.every("bulk_refunds", time.Duration(cfg.Refund.BulkPollIntervalSeconds)*time.Second, func(ctx context.Context) {
+		finished, err := a.payments.bulkRefunds.RunDue(ctx, background.stopping.Done())
+		if err != nil {
+			log.Error("Bulk refund worker pass failed: %v", err)
+		}
+		if finished > 0 {
+			log.Info("Finished %d bulk refund jobs", finished)
+		}
+	})
+
+	// Produce the exports partners queued
+	background.every("exports", time.Duration(cfg.Exports.PollIntervalSeconds)*time.Second, func(ctx context.Context) {
+		ran, err := a.reports.exportWorker.RunDue(ctx)
+		if err != nil {
+			log.Error("Export worker pass failed: %v", err)
+		}
+		if ran > 0 {
+			log.Info("Ran %d exports", ran)
+		}
+	})
+
+	// Issue last month's statements once it is over
+	if cfg.Statements.IntervalMinutes > 0 {
+		background.every("statements", time.Duration(cfg.Statements.IntervalMinutes)*time.Minute, func(ctx context.Context) {
+			issued, err := a.reports.generateStatements.GenerateDue(ctx, time.Now())
+			if err != nil {
+				log.Error("Statement generation failed: %v", err)
+			}
+			if issued > 0 {
+				log.Info("Issued %d partner statements", issued)
+			}
+		})
+	}
+
+	// Roll up the analytics of the last days
+	if cfg.Analytics.IntervalMinutes > 0 {
+		background.every("analytics", time.Duration(cfg.Analytics.IntervalMinutes)*time.Minute, func(ctx context.Context) {
+			if _, err := a.reports.rollupAnalytics.Execute(ctx, time.Now()); err != nil {
+				log.Error("Analytics rollup failed: %v", err)
+			}
+		})
+	}
+
+	// Deliver the reports partners scheduled
+	if cfg.Reports.IntervalSeconds > 0 {
+		background.every("reports", time.Duration(cfg.Reports.IntervalSeconds)*time.Second, func(ctx context.Context) {
+			ran, err := a.reports.deliverReports.RunDue(ctx)
+			if err != nil {
+				log.Error("Report delivery pass failed: %v", err)
+			}
+			if ran > 0 {
+				log.Info("Ran %d scheduled reports", ran)
+			}
+		})
+	}
+	return background
+}
@@ -0,0 +1,260 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	eventarchive "Pay2Go/internal/adapters/persistence/archive"
+	"Pay2Go/internal/adapters/persistence/cached"
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/infrastructure/cache"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/encryption"
+	"Pay2Go/internal/infrastructure/httpclient"
+	"Pay2Go/internal/infrastructure/lock"
+	"Pay2Go/internal/infrastructure/migrate"
+	"Pay2Go/internal/infrastructure/objectstore"
+	"Pay2Go/internal/infrastructure/ratelimit"
+	"Pay2Go/internal/usecases/archive"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/migrations"
+)
+
+// data is where the API keeps its state: the database and its read replica,
+// Redis, and the archive and export object stores
+type data struct {
+	db *sql.DB
+	// replicaDB is nil without DB_REPLICA_DSN
+	replicaDB *sql.DB
+	migrator  *migrate.Migrator
+	// redis is nil without REDIS_URL
+	redis      *redis.Client
+	rateLimits ports.RateLimitStore
+
+	// repos are the repositories of the configured database as they are;
+	// the fields below decorate some of them
+	repos repositories
+
+	// transactions, partners and refunds are cached where configured, and
+	// transactions count the retries they claim
+	transactions     ports.TransactionRepository
+	partners         ports.PartnerRepository
+	refunds          ports.RefundRepository
+	cachedPartners   *cached.PartnerRepository
+	transactionCache ports.TransactionCache
+	// auditLogs fall back to the archive when one is configured
+	auditLogs ports.AuditLogRepository
+	// locker keeps two instances from driving one payment at once
+	locker ports.Locker
+
+	// archiveEvents is nil without ARCHIVE_STORE
+	archiveEvents *archive.ArchiveEventsUseCase
+	// exportStore keeps export files, statements and receipt logos
+	exportStore ports.ObjectStore
+}
+
+func newData(p *platform) (*data, error) {
+	cfg := p.cfg
+	d := &data{}
+
+	// Connect to database (DB_DRIVER names the database/sql driver)
+	db, err := openDB(cfg, cfg.Database.GetDSN(), p.slowCalls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	p.onClose(func() { db.Close() })
+	cfg.Database.ConfigurePool(db)
+	d.db = db
+
+	// Test database connection
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	p.log.Info("Database connection established")
+
+	// Read-only queries go to the read replica when one is configured. It may
+	// be down at startup; reads fall back to the primary until it is back.
+	var replica *sqldb.Replica
+	if cfg.Database.ReplicaDSN != "" {
+		replicaDB, err := openDB(cfg, cfg.Database.ReplicaDSN, p.slowCalls)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DB_REPLICA_DSN: %w", err)
+		}
+		p.onClose(func() { replicaDB.Close() })
+		cfg.Database.ConfigurePool(replicaDB)
+
+		if err := replicaDB.Ping(); err != nil {
+			p.log.Warn("Read replica unavailable, reading from the primary: %v", err)
+		} else {
+			p.log.Info("Read replica connection established")
+		}
+		replica = sqldb.NewReplica(db, replicaDB)
+		d.replicaDB = replicaDB
+	}
+
+	// Load the schema migrations, which the readiness check compares the
+	// database with
+	dialect, files := migrate.Postgres, fs.FS(migrations.FS)
+	switch cfg.Database.Driver {
+	case config.DriverMySQL:
+		dialect, files = migrate.MySQL, migrations.MySQLFS
+	case config.DriverSQLite:
+		dialect, files = migrate.SQLite, migrations.SQLiteFS
+	}
+	d.migrator, err = migrate.New(db, dialect, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	// Apply pending schema migrations (otherwise run `migrate up` before deploying)
+	if cfg.Database.AutoMigrate {
+		applied, err := d.migrator.Up(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		p.log.Info("Applied %d database migrations", len(applied))
+	}
+
+	// Initialize rate limit store (Redis shares limits and seen request signatures across instances)
+	if cfg.Redis.URL != "" {
+		redisOptions, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		d.redis = redis.NewClient(redisOptions)
+		p.onClose(func() { d.redis.Close() })
+
+		if err := d.redis.Ping(context.Background()).Err(); err != nil {
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		d.rateLimits = ratelimit.NewRedisStore(d.redis)
+		p.log.Info("Redis connection established")
+	} else {
+		d.rateLimits = ratelimit.NewMemoryStore()
+		p.log.Warn("REDIS_URL not set, rate limits are enforced per instance")
+	}
+
+	// Initialize encryption of secrets at rest
+	secretCipher, err := encryption.NewEnvelopeCipher(cfg.Encryption.Keys, cfg.Encryption.PrimaryKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption configuration: %w", err)
+	}
+	secretCipher.AllowLegacy = cfg.Encryption.AllowLegacy
+
+	// Initialize repositories for the configured database
+	d.repos = newRepositories(cfg.Database.Driver, db, replica, secretCipher)
+	if cfg.Database.PreparedStatements {
+		for _, preparer := range d.repos.statementPreparers {
+			if err := preparer.PrepareStatements(context.Background()); err != nil {
+				p.log.Warn("Running queries unprepared: %v", err)
+			}
+		}
+	}
+	d.transactions = d.repos.transactions
+	d.partners = d.repos.partners
+	d.refunds = d.repos.refunds
+	d.auditLogs = d.repos.auditLogs
+
+	// Cache partners for authentication; with Redis the cache is shared and
+	// changes reach every instance at once
+	if cfg.Cache.PartnerTTLSeconds > 0 {
+		var sharedPartnerCache ports.PartnerCache
+		if d.redis != nil {
+			sharedPartnerCache = cache.NewRedisPartnerCache(d.redis)
+		}
+		d.cachedPartners = cached.NewPartnerRepository(
+			d.partners,
+			cache.NewMemoryCache(cfg.Cache.PartnerCacheSize),
+			sharedPartnerCache,
+			time.Duration(cfg.Cache.PartnerTTLSeconds)*time.Second,
+		)
+		d.partners = d.cachedPartners
+	}
+
+	// Cache transaction reads in Redis for partners polling their status;
+	// every save and refund reservation invalidates the transaction
+	if cfg.Cache.TransactionTTLSeconds > 0 {
+		if d.redis != nil {
+			d.transactionCache = cache.NewRedisTransactionCache(d.redis)
+			d.transactions = cached.NewTransactionRepository(d.transactions, d.transactionCache)
+			d.refunds = cached.NewRefundRepository(d.refunds, d.transactions, d.transactionCache)
+		} else {
+			p.log.Warn("TRANSACTION_CACHE_TTL_SECONDS needs REDIS_URL, transaction reads are not cached")
+		}
+	}
+
+	// Retries are counted where they are claimed
+	d.transactions = p.metrics.TransactionRepository(d.transactions)
+
+	// Lock transactions while they are processed, so two instances never
+	// drive one payment at once; Redis spares the database the connections
+	// held by its locks
+	d.locker = d.repos.locker
+	if d.redis != nil {
+		d.locker = lock.NewRedisLocker(d.redis)
+	}
+
+	// Old audit entries and published outbox events move to the archive, and
+	// audit reads fall back to it
+	if cfg.Archive.Store != "" {
+		archiveStore, err := newObjectStore(cfg.Archive.Store, cfg.Archive.Dir, objectstore.S3Config{
+			Endpoint:        cfg.Archive.S3Endpoint,
+			Region:          cfg.Archive.S3Region,
+			Bucket:          cfg.Archive.S3Bucket,
+			AccessKeyID:     cfg.Archive.S3AccessKeyID,
+			SecretAccessKey: cfg.Archive.S3SecretAccessKey,
+		}, p.transport)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive configuration: %w", err)
+		}
+		eventArchive := eventarchive.NewEventArchive(archiveStore)
+		retention := time.Duration(cfg.Archive.RetentionDays) * 24 * time.Hour
+		d.auditLogs = eventarchive.NewAuditLogRepository(d.auditLogs, eventArchive, retention)
+		d.archiveEvents = archive.NewArchiveEventsUseCase(d.auditLogs, d.repos.outbox, eventArchive, retention)
+	}
+
+	// Export files are written by the export worker and downloaded through
+	// signed URLs
+	d.exportStore, err = newObjectStore(cfg.Exports.Store, cfg.Exports.Dir, objectstore.S3Config{
+		Endpoint:        cfg.Exports.S3Endpoint,
+		Region:          cfg.Exports.S3Region,
+		Bucket:          cfg.Exports.S3Bucket,
+		AccessKeyID:     cfg.Exports.S3AccessKeyID,
+		SecretAccessKey: cfg.Exports.S3SecretAccessKey,
+	}, p.transport)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export configuration: %w", err)
+	}
+	return d, nil
+}
+
+// openDB opens the database at dsn, timing its queries when slow queries
+// are watched for
+func openDB(cfg *config.Config, dsn string, observer sqldb.QueryObserver) (*sql.DB, error) {
+	if cfg.Alerts.SlowQueryThresholdMs == 0 {
+		return sql.Open(cfg.Database.DriverName(), dsn)
+	}
+	return sqldb.OpenObserved(cfg.Database.DriverName(), dsn, observer)
+}
+
+// newObjectStore opens an object store: a directory with
+// config.ArchiveStoreFile, or an S3 bucket
+func newObjectStore(kind, dir string, s3 objectstore.S3Config, transport *httpclient.Transport) (ports.ObjectStore, error) {
+	if kind == config.ArchiveStoreFile {
+		store, err := objectstore.NewFileStore(dir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	store, err := objectstore.NewS3Store(s3, transport.Client(30*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/infrastructure/awsevents"
+	"Pay2Go/internal/infrastructure/cache"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/email"
+	"Pay2Go/internal/infrastructure/eventstream"
+	"Pay2Go/internal/infrastructure/httpclient"
+	"Pay2Go/internal/infrastructure/notify"
+	"Pay2Go/internal/infrastructure/sms"
+	"Pay2Go/internal/infrastructure/webhook"
+	"Pay2Go/internal/usecases/notification"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// events carry what happened to payments out of the API: webhooks and
+// event streams from the outbox, and the emails and texts customers get
+type events struct {
+	relay *outbox.Relay
+	// emailSender is nil when no email provider is configured
+	emailSender ports.EmailSender
+	// notifier is nil when neither email nor SMS is configured
+	notifier *notification.Notifier
+
+	streamHandler       *handlers.EventStreamHandler
+	verificationHandler *handlers.VerificationHandler
+}
+
+func newEvents(p *platform, d *data, audits *auditing) (*events, error) {
+	cfg := p.cfg
+	e := &events{}
+
+	eventStream, err := newEventStream(cfg.Events, p.transport)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event broker configuration: %w", err)
+	}
+	if closer, ok := eventStream.(io.Closer); ok {
+		p.onClose(func() { closer.Close() })
+	}
+	// Partners receive their events by webhook, or in their own AWS account
+	// when they set an event destination
+	webhookTimeout := time.Duration(cfg.Outbox.WebhookTimeoutSeconds) * time.Second
+	partnerEvents, err := awsevents.NewPublisher(
+		d.partners,
+		webhook.NewPublisher(d.partners, p.transport.Client(webhookTimeout)),
+		awsevents.Config{
+			AccessKeyID:     cfg.AWS.AccessKeyID,
+			SecretAccessKey: cfg.AWS.SecretAccessKey,
+			SessionToken:    cfg.AWS.SessionToken,
+			Endpoint:        cfg.AWS.Endpoint,
+		},
+		p.transport.Client(webhookTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AWS configuration: %w", err)
+	}
+	// Email customers their receipts and refund confirmations, and partners
+	// about webhooks the relay gave up on, for partners that enable them
+	e.emailSender, err = newEmailSender(cfg, p.transport)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email configuration: %w", err)
+	}
+	// Text customers payment confirmations and verification codes, from
+	// the partner's own sender when it has one
+	texts, err := newTexts(cfg.SMS, d.rateLimits, p.transport)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMS configuration: %w", err)
+	}
+	var outboxNotifiers outbox.Notifiers
+	if e.emailSender != nil || texts != nil {
+		e.notifier = notification.NewNotifier(
+			d.partners,
+			d.transactions,
+			e.emailSender,
+			texts,
+			cfg.Email.BufferSize,
+			time.Duration(cfg.Email.WebhookAlertIntervalMinutes)*time.Minute,
+			func(err error) { p.log.Error("Customer notification failed: %v", err) },
+		)
+		outboxNotifiers = append(outboxNotifiers, e.notifier)
+	}
+	// Alert operations about webhooks the relay gave up on
+	if p.alerts != nil {
+		outboxNotifiers = append(outboxNotifiers, notify.NewWebhookFailures(
+			p.alerts,
+			time.Duration(cfg.Alerts.WebhookFailureIntervalMinutes)*time.Minute,
+			p.log,
+		))
+	}
+	var outboxNotifier ports.OutboxNotifier
+	if len(outboxNotifiers) > 0 {
+		outboxNotifier = outboxNotifiers
+	}
+	e.relay = outbox.NewRelay(
+		d.repos.outbox,
+		outbox.NewFanoutPublisher(p.metrics.Publisher(partnerEvents), eventStream),
+		outboxNotifier,
+		cfg.Outbox.BatchSize,
+		cfg.Outbox.Workers,
+		cfg.Outbox.PartnerConcurrency,
+		ports.OutboxShards{Index: cfg.Outbox.ShardIndex, Count: cfg.Outbox.ShardCount},
+		time.Duration(cfg.Outbox.ClaimTimeoutSeconds)*time.Second,
+	)
+	e.streamHandler = handlers.NewEventStreamHandler(
+		outbox.NewStreamEventsUseCase(d.repos.outbox),
+		time.Duration(cfg.Outbox.StreamPollIntervalSeconds)*time.Second,
+	)
+
+	var verificationCodes ports.VerificationCodeStore = cache.NewMemoryCodeStore(10000)
+	if d.redis != nil {
+		verificationCodes = cache.NewRedisCodeStore(d.redis)
+	}
+	verificationPolicy := notification.VerificationPolicy{
+		TTL:         time.Duration(cfg.SMS.VerificationTTLMinutes) * time.Minute,
+		MaxAttempts: cfg.SMS.VerificationMaxAttempts,
+	}
+	e.verificationHandler = handlers.NewVerificationHandler(
+		notification.NewSendVerificationCodeUseCase(d.partners, d.transactions, verificationCodes, texts, verificationPolicy),
+		notification.NewVerifyCodeUseCase(d.transactions, verificationCodes, d.rateLimits, audits.logger, verificationPolicy),
+	)
+	return e, nil
+}
+
+// newEventStream creates the publisher of the broker cfg selects, or nil
+// when events are not streamed
+func newEventStream(cfg config.EventsConfig, transport *httpclient.Transport) (ports.EventPublisher, error) {
+	switch cfg.Broker {
+	case config.EventBrokerKafka:
+		return eventstream.NewKafkaPublisher(eventstream.KafkaConfig{
+			RESTURL:     cfg.KafkaRESTURL,
+			TopicPrefix: cfg.TopicPrefix,
+			Username:    cfg.KafkaUsername,
+			Password:    cfg.KafkaPassword,
+		}, transport.Client(10*time.Second))
+	case config.EventBrokerRabbitMQ:
+		return eventstream.NewRabbitMQPublisher(eventstream.RabbitMQConfig{
+			URL:         cfg.RabbitMQURL,
+			Exchange:    cfg.RabbitMQExchange,
+			TopicPrefix: cfg.TopicPrefix,
+			Timeout:     10 * time.Second,
+		})
+	case config.EventBrokerNATS:
+		return eventstream.NewNATSPublisher(eventstream.NATSConfig{
+			URL:         cfg.NATSURL,
+			TopicPrefix: cfg.TopicPrefix,
+			Username:    cfg.NATSUsername,
+			Password:    cfg.NATSPassword,
+			Token:       cfg.NATSToken,
+			CAFile:      cfg.NATSCAFile,
+			Timeout:     10 * time.Second,
+		})
+	}
+	return nil, nil
+}
+
+// newEmailSender creates the sender of the email provider cfg selects, or
+// nil when no email is sent
+func newEmailSender(cfg *config.Config, transport *httpclient.Transport) (ports.EmailSender, error) {
+	switch cfg.Email.Provider {
+	case config.EmailProviderSMTP:
+		return email.NewSMTPSender(email.SMTPConfig{
+			Host:     cfg.Email.SMTPHost,
+			Port:     cfg.Email.SMTPPort,
+			Username: cfg.Email.SMTPUsername,
+			Password: cfg.Email.SMTPPassword,
+			From:     cfg.Email.From,
+			Timeout:  30 * time.Second,
+		})
+	case config.EmailProviderSES:
+		return awsevents.NewSESSender(awsevents.Config{
+			AccessKeyID:     cfg.AWS.AccessKeyID,
+			SecretAccessKey: cfg.AWS.SecretAccessKey,
+			SessionToken:    cfg.AWS.SessionToken,
+			Endpoint:        cfg.AWS.Endpoint,
+		}, cfg.Email.SESRegion, cfg.Email.From, transport.Client(30*time.Second))
+	}
+	return nil, nil
+}
+
+// newTexts returns what texts customers, limited per partner and per
+// phone number, or nil when no SMS provider is configured
+func newTexts(cfg config.SMSConfig, limits ports.RateLimitStore, transport *httpclient.Transport) (*notification.Texts, error) {
+	if cfg.Provider != config.SMSProviderTwilio {
+		return nil, nil
+	}
+	sender, err := sms.NewTwilioSender(sms.TwilioConfig{
+		AccountSID: cfg.TwilioAccountSID,
+		AuthToken:  cfg.TwilioAuthToken,
+		From:       cfg.From,
+	}, transport.Client(10*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return notification.NewTexts(sender, limits, notification.SMSPolicy{
+		PerPartner:   cfg.PartnerLimitPerHour,
+		PerRecipient: cfg.RecipientLimitPerHour,
+		Window:       time.Hour,
+	}), nil
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+
+	grpcapi "Pay2Go/internal/adapters/grpc"
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/adapters/http/routes"
+	"Pay2Go/internal/infrastructure/config"
+)
+
+// newHTTPServer routes the REST, GraphQL and streaming API to the handlers
+// of the features
+func newHTTPServer(
+	p *platform,
+	d *data,
+	audits *auditing,
+	keys *access,
+	pt *partners,
+	pay *payments,
+	ev *events,
+	r *reports,
+	ops *operations,
+) (*fiber.App, error) {
+	cfg := p.cfg
+	rateLimiter := middleware.NewRateLimiter(d.rateLimits, cfg.RateLimit.DefaultPerMinute)
+
+	// Redacted access log for compliance review
+	var accessLog *middleware.AccessLog
+	if cfg.Server.AccessLogPath != "" {
+		accessLogFile, err := openAccessLog(cfg.Server.AccessLogPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open access log: %w", err)
+		}
+		p.onClose(func() { accessLogFile.Close() })
+		accessLog = middleware.NewAccessLog(accessLogFile, cfg.Server.AccessLogMaxBodyBytes, func(err error) {
+			p.log.Error("Failed to write access log: %v", err)
+		})
+	}
+
+	// Initialize Fiber app
+	app := fiber.New(fiber.Config{
+		AppName:      "Pay2Go API",
+		ErrorHandler: customErrorHandler,
+	})
+
+	// API v1 answers unversioned requests until clients move to v2
+	versioning := middleware.NewVersioning(middleware.APIVersion1, middleware.APIVersion1, middleware.APIVersion2)
+	v1Deprecation := middleware.Deprecation{
+		Since:     cfg.Server.V1DeprecatedAt,
+		Sunset:    cfg.Server.V1SunsetAt,
+		Successor: "/api/v2",
+	}
+
+	// Setup routes
+	routes.SetupRoutes(
+		app,
+		pay.transactionHandler,
+		pay.transactionV2Handler,
+		pay.refundHandler,
+		keys.apiKeyHandler,
+		keys.credentialHandler,
+		keys.userHandler,
+		pt.handler,
+		pt.apiKeyHandler,
+		audits.handler,
+		r.settlementHandler,
+		ev.verificationHandler,
+		pay.providerEventHandler,
+		pay.graphqlHandler,
+		ev.streamHandler,
+		pay.transactionSocketHandler,
+		r.exportHandler,
+		pt.fieldFilterHandler,
+		pay.receiptHandler,
+		r.statementHandler,
+		r.analyticsHandler,
+		r.reportScheduleHandler,
+		pt.accountMappingHandler,
+		ops.openAPIHandler,
+		keys.authHandler,
+		keys.adminAuthHandler,
+		ops.healthHandler,
+		ops.metricsHandler,
+		ops.sloHandler,
+		ops.usageHandler,
+		ops.runtimeHandler,
+		versioning,
+		v1Deprecation,
+		middleware.NewLogger(p.log),
+		accessLog,
+		middleware.NewRequestMetrics(p.metrics),
+		pay.usageMeter,
+		middleware.NewRecovery(p.log, p.errorReporter),
+		keys.auth,
+		keys.adminAuth,
+		rateLimiter,
+		middleware.NewLoginGuard(keys.failedAuth),
+	)
+	return app, nil
+}
+
+// newGRPCServer serves the payment use cases to internal services, which
+// call them over gRPC on a port of its own kept off the public load
+// balancer
+func newGRPCServer(p *platform, d *data, keys *access, pay *payments, ops *operations) *grpcapi.Server {
+	server := grpcapi.NewServer(
+		keys.authenticator,
+		d.partners,
+		keys.usageTracker,
+		pay.createTransaction,
+		pay.getTransaction,
+		pay.processPayment,
+		pay.enqueuePayment,
+		pay.refundTransaction,
+		ops.checkReadiness,
+	)
+	// Calls are traced, logged, measured and recovered from as HTTP
+	// requests are; authentication runs after these
+	server.Use(
+		grpcapi.RequestID,
+		grpcapi.Logger(p.log),
+		grpcapi.Metrics(p.metrics),
+		grpcapi.Recovery(p.errorReporter),
+	)
+	return server
+}
+
+// customErrorHandler handles Fiber errors
+func customErrorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	if e, ok := err.(*fiber.Error); ok {
+		code = e.Code
+	}
+	return middleware.RespondError(c, code, dto.ErrorResponse{
+		Error:   middleware.StatusErrorCode(code),
+		Message: err.Error(),
+	})
+}
+
+// openAccessLog opens the access log at path for appending, or standard
+// output
+func openAccessLog(path string) (*os.File, error) {
+	if path == config.AccessLogStdout {
+		return os.Stdout, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/routes"
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/infrastructure/diagnostics"
+	"Pay2Go/internal/usecases/health"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/usage"
+)
+
+// operations are the endpoints that keep the API running: health, metrics,
+// SLOs, usage, runtime tuning and the OpenAPI document
+type operations struct {
+	checkReadiness *health.CheckReadinessUseCase
+
+	healthHandler  *handlers.HealthHandler
+	metricsHandler *handlers.MetricsHandler
+	sloHandler     *handlers.SLOHandler
+	usageHandler   *handlers.UsageHandler
+	runtimeHandler *handlers.RuntimeHandler
+	openAPIHandler *handlers.OpenAPIHandler
+}
+
+func newOperations(p *platform, d *data, pay *payments, ev *events) (*operations, error) {
+	cfg := p.cfg
+	o := &operations{}
+
+	dbPools := map[string]handlers.DBStatsSource{"primary": d.db}
+	if d.replicaDB != nil {
+		dbPools["replica"] = d.replicaDB
+	}
+	registerRuntimeMetrics(p.metrics.Registry, dbPools, ev.relay, p.transport, p.heartbeats)
+	o.metricsHandler = handlers.NewMetricsHandler(dbPools, ev.relay, p.transport, p.metrics)
+	o.runtimeHandler = handlers.NewRuntimeHandler(diagnostics.NewRuntimeTuner(p.log))
+
+	openAPIHandler, err := handlers.NewOpenAPIHandler(routes.OpenAPI())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
+	o.openAPIHandler = openAPIHandler
+
+	readinessChecks := map[string]ports.HealthChecker{
+		"database":   sqldb.NewPingCheck(d.db),
+		"migrations": d.migrator,
+		"outbox":     outbox.NewBacklogCheck(d.repos.outbox, int64(cfg.Health.OutboxMaxBacklog)),
+		"providers":  pay.providerHealth,
+		"background": p.heartbeats,
+	}
+	if d.replicaDB != nil {
+		readinessChecks["replica"] = sqldb.NewReplicaCheck(
+			d.replicaDB,
+			replicaLagQuery(cfg.Database.Driver),
+			time.Duration(cfg.Health.ReplicaMaxLagSeconds)*time.Second,
+		)
+	}
+	// Load balancers give up on slow probes, so the checks get two seconds
+	o.checkReadiness = health.NewCheckReadinessUseCase(readinessChecks, 2*time.Second)
+	o.healthHandler = handlers.NewHealthHandler(o.checkReadiness)
+	o.sloHandler = handlers.NewSLOHandler(health.NewGetSLOSummaryUseCase(d.transactions, d.repos.outbox))
+	o.usageHandler = handlers.NewUsageHandler(
+		usage.NewGetUsageUseCase(d.repos.usage),
+		usage.NewGetUsageRollupUseCase(d.repos.usage),
+	)
+	return o, nil
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"time"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/usecases/partner"
+)
+
+// partners are the merchants Pay2Go serves and the settings they manage
+type partners struct {
+	get               *partner.GetPartnerUseCase
+	updateReceiptLogo *partner.UpdatePartnerReceiptLogoUseCase
+	updatePricing     *partner.UpdatePartnerPricingUseCase
+	// anonymize removes the customer data of off-boarded partners once
+	// their retention period ends
+	anonymize *partner.AnonymizeCustomerDataUseCase
+
+	handler               *handlers.PartnerHandler
+	apiKeyHandler         *handlers.PartnerAPIKeyHandler
+	fieldFilterHandler    *handlers.FieldFilterHandler
+	accountMappingHandler *handlers.AccountMappingHandler
+}
+
+func newPartners(p *platform, d *data, audits *auditing, keys *access) *partners {
+	repos := d.repos
+	auditLogger := audits.logger
+	pt := &partners{
+		get:               partner.NewGetPartnerUseCase(d.partners),
+		updateReceiptLogo: partner.NewUpdatePartnerReceiptLogoUseCase(d.partners, d.exportStore, auditLogger),
+		updatePricing:     partner.NewUpdatePartnerPricingUseCase(d.partners, auditLogger),
+		anonymize:         partner.NewAnonymizeCustomerDataUseCase(d.partners, d.transactions, d.refunds, repos.outbox, d.exportStore, auditLogger),
+	}
+
+	pt.handler = handlers.NewPartnerHandler(
+		pt.get,
+		partner.NewListPartnersUseCase(d.partners),
+		partner.NewPutPartnerUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerWebhookUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerFeaturesUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerCurrenciesUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerAmountLimitsUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerLocaleUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerSMSSenderUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerRoundingPolicyUseCase(d.partners, auditLogger),
+		partner.NewUpdatePartnerEventDestinationUseCase(d.partners, auditLogger),
+		partner.NewRotateSecretsUseCase(repos.secretRotators...),
+		partner.NewOffboardPartnerUseCase(
+			d.partners,
+			repos.apiKeys,
+			auditLogger,
+			time.Duration(p.cfg.Privacy.PIIRetentionDays)*24*time.Hour,
+		),
+	)
+	pt.apiKeyHandler = handlers.NewPartnerAPIKeyHandler(
+		pt.get,
+		keys.createAPIKey,
+		keys.getAPIKey,
+		keys.listAPIKeys,
+		keys.revokeAPIKey,
+	)
+	pt.fieldFilterHandler = handlers.NewFieldFilterHandler(pt.get, partner.NewUpdatePartnerFieldFilterUseCase(d.partners, auditLogger))
+	pt.accountMappingHandler = handlers.NewAccountMappingHandler(pt.get, partner.NewUpdatePartnerAccountMappingUseCase(d.partners, auditLogger))
+	return pt
+}
@@ -0,0 +1,273 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/cache"
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/infrastructure/providerhealth"
+	"Pay2Go/internal/infrastructure/receipt"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+	"Pay2Go/internal/usecases/usage"
+)
+
+// payments are the transactions and refunds partners create, and the
+// gateway that takes them to the payment providers
+type payments struct {
+	// platformGateway is Pay2Go's own provider account, which the canary
+	// probe pays through
+	platformGateway ports.PaymentGateway
+	providerHealth  *providerhealth.Tracker
+	// usageMeter counts calls and payment volume per partner
+	usageMeter *usage.Meter
+
+	createTransaction *transaction.CreateTransactionUseCase
+	getTransaction    *transaction.GetTransactionUseCase
+	processPayment    *transaction.ProcessPaymentUseCase
+	// enqueuePayment is nil unless payments are queued for cmd/worker
+	enqueuePayment    *transaction.EnqueuePaymentUseCase
+	refundTransaction *transaction.RefundTransactionUseCase
+	bulkRefunds       *transaction.BulkRefundWorker
+
+	transactionHandler       *handlers.TransactionHandler
+	transactionV2Handler     *handlers.TransactionV2Handler
+	refundHandler            *handlers.RefundHandler
+	receiptHandler           *handlers.ReceiptHandler
+	providerEventHandler     *handlers.ProviderEventHandler
+	graphqlHandler           *handlers.GraphQLHandler
+	transactionSocketHandler *handlers.TransactionSocketHandler
+}
+
+func newPayments(p *platform, d *data, audits *auditing, pt *partners) (*payments, error) {
+	cfg := p.cfg
+	repos := d.repos
+	auditLogger := audits.logger
+	pay := &payments{}
+
+	// Initialize payment gateway (test-mode transactions go to the sandbox,
+	// live ones to the partner's own provider account if they connected one)
+	pay.platformGateway = payment.NewMockPaymentGateway("mock")
+	paymentGateway := p.metrics.Gateway(payment.NewModeRouter(
+		payment.NewCredentialRouter(
+			repos.providerCredentials,
+			pay.platformGateway,
+			payment.NewPartnerAccountGateway,
+		),
+		payment.NewSandboxPaymentGateway(),
+	))
+	paymentGateway = p.slowCalls.Gateway(paymentGateway)
+
+	// Error rates of providers, from live calls and background probes; a
+	// provider failing too often is degraded in the readiness check
+	pay.providerHealth = providerhealth.NewTracker(providerhealth.Config{
+		Window:    time.Duration(cfg.Providers.WindowSeconds) * time.Second,
+		MinCalls:  cfg.Providers.MinCalls,
+		ErrorRate: cfg.Providers.DegradedErrorRate,
+	}, p.log, p.alerts)
+	paymentGateway = pay.providerHealth.Gateway(paymentGateway)
+
+	// Calls, errors and payment volume per partner and day, flushed to the
+	// usage store every minute
+	pay.usageMeter = usage.NewMeter(repos.usage, time.Minute, func(err error) {
+		p.log.Error("Failed to write usage, retrying with the next flush: %v", err)
+	})
+	paymentGateway = pay.usageMeter.Gateway(paymentGateway)
+	if p.errorReporter != nil {
+		paymentGateway = payment.NewReportingGateway(paymentGateway, p.errorReporter)
+	}
+
+	// Platform maximum transaction amounts per currency
+	amountLimits, err := valueobjects.ParseAmountLimits(cfg.Limits.MaxAmounts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAX_TRANSACTION_AMOUNTS: %w", err)
+	}
+
+	// Initialize use cases (caching is not wired yet)
+	pay.createTransaction = transaction.NewCreateTransactionUseCase(
+		d.transactions,
+		repos.outbox,
+		repos.unitOfWork,
+		d.partners,
+		repos.cardBINs,
+		paymentGateway,
+		auditLogger,
+		nil,
+		amountLimits,
+	)
+	pay.getTransaction = transaction.NewGetTransactionUseCase(
+		d.transactions,
+		d.transactionCache,
+		time.Duration(cfg.Cache.TransactionTTLSeconds)*time.Second,
+	)
+	listTransactionsUC := transaction.NewListTransactionsUseCase(
+		d.transactions,
+	)
+	pay.processPayment = transaction.NewProcessPaymentUseCase(
+		d.transactions,
+		repos.outbox,
+		repos.unitOfWork,
+		paymentGateway,
+		auditLogger,
+		d.locker,
+		time.Duration(cfg.Lock.PaymentTTLSeconds)*time.Second,
+	)
+	// Provider notifications complete payments and refunds left processing;
+	// each event is handled once, however often it is delivered
+	providerEventsUC, err := newProviderEventsUseCase(cfg, d.redis, repos, auditLogger, d.locker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider notification configuration: %w", err)
+	}
+	// In async mode payments are queued for cmd/worker, so requests do not
+	// wait on the provider
+	if cfg.Worker.AsyncProcessing {
+		pay.enqueuePayment = transaction.NewEnqueuePaymentUseCase(d.transactions, repos.paymentJobs)
+		p.log.Info("Payments are queued for cmd/worker")
+	}
+	pay.refundTransaction = transaction.NewRefundTransactionUseCase(
+		d.transactions,
+		d.refunds,
+		d.partners,
+		repos.outbox,
+		repos.unitOfWork,
+		paymentGateway,
+		nil,
+		auditLogger,
+		cfg.Refund.DefaultWindowDays,
+	)
+	// Bulk refunds are queued by the API and run by the background worker
+	pay.bulkRefunds = transaction.NewBulkRefundWorker(
+		d.transactions,
+		d.refunds,
+		repos.bulkRefundJobs,
+		pay.refundTransaction,
+		5,
+		time.Duration(cfg.Refund.BulkClaimTimeoutSeconds)*time.Second,
+	)
+	listRefundsUC := transaction.NewListRefundsUseCase(d.refunds)
+
+	pay.transactionHandler = handlers.NewTransactionHandler(
+		pay.createTransaction,
+		pay.getTransaction,
+		listTransactionsUC,
+		transaction.NewExportTransactionsUseCase(d.transactions),
+		pay.processPayment,
+		pay.enqueuePayment,
+		pay.refundTransaction,
+		transaction.NewDeleteTransactionUseCase(d.transactions, d.refunds, auditLogger),
+		transaction.NewRestoreTransactionUseCase(d.transactions, auditLogger),
+		listRefundsUC,
+	)
+	pay.transactionV2Handler = handlers.NewTransactionV2Handler(
+		pay.createTransaction,
+		pay.getTransaction,
+		listTransactionsUC,
+		pay.processPayment,
+		pay.enqueuePayment,
+		listRefundsUC,
+	)
+	pay.refundHandler = handlers.NewRefundHandler(
+		transaction.NewApproveRefundUseCase(
+			d.transactions,
+			d.refunds,
+			repos.outbox,
+			repos.unitOfWork,
+			paymentGateway,
+			auditLogger,
+		),
+		transaction.NewCancelRefundUseCase(
+			d.transactions,
+			d.refunds,
+			auditLogger,
+		),
+		transaction.NewBulkRefundUseCase(
+			d.partners,
+			repos.bulkRefundJobs,
+			auditLogger,
+		),
+		transaction.NewGetBulkRefundJobUseCase(repos.bulkRefundJobs),
+		transaction.NewGetRefundReasonSummaryUseCase(d.refunds),
+		transaction.NewDeleteRefundUseCase(d.transactions, d.refunds, auditLogger),
+		transaction.NewRestoreRefundUseCase(d.transactions, d.refunds, auditLogger),
+	)
+
+	// Receipts are rendered on request; the last ones are kept in memory,
+	// and logos live with the export files
+	var receiptCache ports.CacheService
+	if cfg.Cache.ReceiptCacheSize > 0 {
+		receiptCache = cache.NewMemoryCache(cfg.Cache.ReceiptCacheSize)
+	}
+	pay.receiptHandler = handlers.NewReceiptHandler(
+		transaction.NewGetReceiptUseCase(
+			d.transactions,
+			d.refunds,
+			d.partners,
+			d.exportStore,
+			receipt.NewRenderer(),
+			receiptCache,
+		),
+		pt.updateReceiptLogo,
+	)
+
+	pay.providerEventHandler = handlers.NewProviderEventHandler(providerEventsUC)
+	pay.graphqlHandler = handlers.NewGraphQLHandler(
+		pay.getTransaction,
+		listTransactionsUC,
+		transaction.NewGetRefundUseCase(d.transactions, d.refunds),
+		listRefundsUC,
+		outbox.NewListEventsUseCase(repos.outbox),
+	)
+	pay.transactionSocketHandler = handlers.NewTransactionSocketHandler(
+		pay.getTransaction,
+		outbox.NewStreamEventsUseCase(repos.outbox),
+		time.Duration(cfg.Server.WebSocketPollIntervalMs)*time.Millisecond,
+	)
+	return pay, nil
+}
+
+// newProviderEventsUseCase returns the use case handling the notifications
+// of the payment providers with a signing secret configured, remembering
+// handled events in Redis when it is available
+func newProviderEventsUseCase(
+	cfg *config.Config,
+	redisClient *redis.Client,
+	repos repositories,
+	auditLogger ports.AuditLogger,
+	locker ports.Locker,
+) (*transaction.HandleProviderEventsUseCase, error) {
+	parsers := make(map[valueobjects.PaymentProvider]ports.ProviderEventParser)
+	if cfg.ProviderEvents.StripeWebhookSecret != "" {
+		parsers[valueobjects.ProviderStripe] = payment.NewStripeEvents(cfg.ProviderEvents.StripeWebhookSecret)
+	}
+	if cfg.ProviderEvents.AdyenHMACKey != "" {
+		adyen, err := payment.NewAdyenEvents(cfg.ProviderEvents.AdyenHMACKey)
+		if err != nil {
+			return nil, err
+		}
+		parsers[valueobjects.ProviderAdyen] = adyen
+	}
+
+	var events ports.ProviderEventStore = cache.NewMemoryEventStore(100000)
+	if redisClient != nil {
+		events = cache.NewRedisEventStore(redisClient)
+	}
+	return transaction.NewHandleProviderEventsUseCase(
+		parsers,
+		events,
+		time.Duration(cfg.ProviderEvents.DedupTTLHours)*time.Hour,
+		repos.transactions,
+		repos.refunds,
+		repos.outbox,
+		repos.unitOfWork,
+		auditLogger,
+		locker,
+		time.Duration(cfg.Lock.PaymentTTLSeconds)*time.Second,
+	), nil
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/heartbeat"
+	"Pay2Go/internal/infrastructure/httpclient"
+	"Pay2Go/internal/infrastructure/logger"
+	"Pay2Go/internal/infrastructure/metrics"
+	"Pay2Go/internal/infrastructure/notify"
+	"Pay2Go/internal/infrastructure/sentry"
+	"Pay2Go/internal/infrastructure/slowcall"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// platform is what every feature shares: logging, metrics, outbound HTTP
+// and the reporting of errors, slow calls and stalled background loops
+type platform struct {
+	cfg *config.Config
+	log *logger.Logger
+
+	// transport pools the connections of outbound HTTP requests
+	transport *httpclient.Transport
+	// logShipper ships logs to the collector; nil unless LOG_SINK=http
+	logShipper *logger.HTTPSink
+
+	// errorReporter is sentry, or nil when SENTRY_DSN is not set
+	errorReporter ports.ErrorReporter
+	sentry        *sentry.Reporter
+
+	metrics    *metrics.Metrics
+	alerts     ports.AlertSink
+	slowCalls  *slowcall.Monitor
+	heartbeats *heartbeat.Monitor
+
+	// closers release what the platform and the features opened, in the
+	// reverse order
+	closers []func()
+}
+
+func newPlatform(cfg *config.Config, appLogger *logger.Logger) (*platform, error) {
+	p := &platform{cfg: cfg, log: appLogger}
+
+	// Outbound HTTP requests share one pool of connections
+	transport, err := httpclient.NewTransport(httpclient.Config{
+		MaxIdleConns:        cfg.HTTPClient.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.HTTPClient.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.HTTPClient.MaxConnsPerHost,
+		IdleConnTimeout:     time.Duration(cfg.HTTPClient.IdleConnTimeoutSeconds) * time.Second,
+		DialTimeout:         time.Duration(cfg.HTTPClient.DialTimeoutSeconds) * time.Second,
+		TLSHandshakeTimeout: time.Duration(cfg.HTTPClient.TLSHandshakeTimeoutSeconds) * time.Second,
+		ProxyURL:            cfg.HTTPClient.ProxyURL,
+		HTTP2:               cfg.HTTPClient.HTTP2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP client configuration: %w", err)
+	}
+	p.transport = transport
+	p.onClose(transport.CloseIdleConnections)
+
+	// Ship logs where the deployment collects them
+	logShipper, closeLogSink, err := setLogSink(appLogger, cfg.Logging, transport)
+	if err != nil {
+		p.close()
+		return nil, fmt.Errorf("failed to open log sink: %w", err)
+	}
+	p.logShipper = logShipper
+	p.onClose(closeLogSink)
+
+	// Report panics, server errors and provider failures to Sentry
+	if cfg.Sentry.DSN != "" {
+		p.sentry, err = sentry.NewReporter(sentry.Config{
+			DSN:         cfg.Sentry.DSN,
+			Environment: cfg.Sentry.Environment,
+			Release:     cfg.Sentry.Release,
+			SampleRate:  cfg.Sentry.SampleRate,
+			BufferSize:  cfg.Sentry.BufferSize,
+		}, transport.Client(10*time.Second), func(err error) {
+			appLogger.Warn("Failed to report error to Sentry: %v", err)
+		})
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("invalid SENTRY_DSN: %w", err)
+		}
+		p.errorReporter = p.sentry
+	}
+
+	// Prometheus metrics
+	p.metrics = metrics.New()
+
+	// Operational alerts go to Slack and Teams, when their webhooks are set
+	p.alerts = notify.NewSink(cfg.Alerts.SlackWebhookURL, cfg.Alerts.TeamsWebhookURL, transport.Client(10*time.Second))
+
+	// Log, count and alert on slow queries and provider calls
+	p.slowCalls = slowcall.NewMonitor(slowcall.Config{
+		QueryThreshold:   time.Duration(cfg.Alerts.SlowQueryThresholdMs) * time.Millisecond,
+		GatewayThreshold: time.Duration(cfg.Alerts.SlowGatewayThresholdMs) * time.Millisecond,
+		AlertWindow:      time.Duration(cfg.Alerts.WindowSeconds) * time.Second,
+		AlertAfter:       cfg.Alerts.MinBreaches,
+	}, appLogger, p.metrics, p.alerts)
+
+	// Background loops beat after every pass; one that stops makes the API
+	// not ready
+	p.heartbeats = heartbeat.NewMonitor(cfg.Health.HeartbeatMissedBeats)
+	return p, nil
+}
+
+// onClose registers fn to be called when the API closes
+func (p *platform) onClose(fn func()) {
+	p.closers = append(p.closers, fn)
+}
+
+// close calls the functions registered with onClose, the last first
+func (p *platform) close() {
+	for i := len(p.closers) - 1; i >= 0; i-- {
+		p.closers[i]()
+	}
+	p.closers = nil
+}
+
+// setLogSink points appLogger at the sink cfg selects. It returns the HTTP
+// sink, which ships in the background, and a function closing a log file.
+func setLogSink(appLogger *logger.Logger, cfg config.LoggingConfig, transport *httpclient.Transport) (*logger.HTTPSink, func(), error) {
+	format, _ := logger.ParseFormat(cfg.Format)
+	switch cfg.Sink {
+	case config.LogSinkFile:
+		file, err := logger.OpenRotatingFile(cfg.FilePath, int64(cfg.FileMaxSizeMB)<<20, cfg.FileMaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		appLogger.SetSink(logger.NewWriterSink(file, format))
+		return nil, func() { file.Close() }, nil
+	case config.LogSinkHTTP:
+		// Failures to ship go to standard error: logging them would ship
+		// them to the collector that failed
+		shipper, err := logger.NewHTTPSink(logger.HTTPSinkConfig{
+			URL:           cfg.HTTPURL,
+			Collector:     logger.Collector(cfg.HTTPCollector),
+			BatchSize:     cfg.HTTPBatchSize,
+			FlushInterval: time.Duration(cfg.HTTPFlushIntervalMs) * time.Millisecond,
+			BufferSize:    cfg.HTTPBufferSize,
+		}, transport.Client(10*time.Second), func(err error) {
+			fmt.Fprintf(os.Stderr, "Failed to ship logs: %v\n", err)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		appLogger.SetSink(shipper)
+		return shipper, func() {}, nil
+	}
+	appLogger.SetSink(logger.NewWriterSink(os.Stdout, format))
+	return nil, func() {}, nil
+}
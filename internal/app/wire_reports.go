@@ -0,0 +1,140 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/accounting"
+	"Pay2Go/internal/infrastructure/awsevents"
+	"Pay2Go/internal/infrastructure/reportupload"
+	statementcsv "Pay2Go/internal/infrastructure/statement"
+	"Pay2Go/internal/usecases/analytics"
+	"Pay2Go/internal/usecases/export"
+	"Pay2Go/internal/usecases/report"
+	"Pay2Go/internal/usecases/settlement"
+	"Pay2Go/internal/usecases/statement"
+)
+
+// reports are what partners read about their business: exports, monthly
+// statements, analytics, scheduled reports and settlement records
+type reports struct {
+	exportWorker       *export.Worker
+	generateStatements *statement.GenerateStatementUseCase
+	rollupAnalytics    *analytics.RollupAnalyticsUseCase
+	deliverReports     *report.DeliverReportsUseCase
+
+	exportHandler         *handlers.ExportHandler
+	statementHandler      *handlers.StatementHandler
+	analyticsHandler      *handlers.AnalyticsHandler
+	reportScheduleHandler *handlers.ReportScheduleHandler
+	settlementHandler     *handlers.SettlementHandler
+}
+
+func newReports(p *platform, d *data, audits *auditing, pt *partners, ev *events) (*reports, error) {
+	cfg := p.cfg
+	repos := d.repos
+	r := &reports{}
+
+	exportSigner := export.NewDownloadSigner(cfg.Exports.SigningKey, time.Duration(cfg.Exports.URLTTLMinutes)*time.Minute)
+	r.exportHandler = handlers.NewExportHandler(
+		export.NewCreateExportUseCase(repos.exportJobs),
+		export.NewGetExportUseCase(repos.exportJobs, exportSigner),
+		export.NewDownloadExportUseCase(repos.exportJobs, d.exportStore, exportSigner),
+	)
+	// Produce the exports partners queued
+	r.exportWorker = export.NewWorker(
+		repos.exportJobs,
+		d.partners,
+		d.transactions,
+		d.refunds,
+		d.exportStore,
+		handlers.NewExportEncoder(),
+		accounting.NewEncoder(),
+		10,
+		time.Duration(cfg.Exports.ClaimTimeoutSeconds)*time.Second,
+	)
+
+	// Monthly statements are kept with the export files; partners without
+	// pricing of their own are charged the standard pricing
+	standardPricing, err := valueobjects.NewPricing([]valueobjects.PricingTier{{
+		Name:    "standard",
+		Percent: cfg.Statements.FeePercent,
+		Fixed:   int64(cfg.Statements.FeeFixed),
+	}}, int64(cfg.Statements.ChargebackFee))
+	if err != nil {
+		return nil, fmt.Errorf("invalid standard statement pricing: %w", err)
+	}
+	r.generateStatements = statement.NewGenerateStatementUseCase(
+		d.partners,
+		d.transactions,
+		d.refunds,
+		repos.disputes,
+		repos.statements,
+		d.exportStore,
+		statementcsv.NewRenderer(),
+		standardPricing,
+	)
+	// Exports and scheduled reports share one writer; QuickBooks and Xero
+	// files post to the accounts partners map
+	exportWriter := export.NewWriter(d.partners, d.transactions, d.refunds, handlers.NewExportEncoder(), accounting.NewEncoder())
+	r.statementHandler = handlers.NewStatementHandler(
+		statement.NewListStatementsUseCase(repos.statements),
+		statement.NewGetStatementUseCase(repos.statements, d.exportStore),
+		r.generateStatements,
+		pt.get,
+		pt.updatePricing,
+		exportWriter,
+	)
+
+	// Analytics are read from daily rollups the background rebuilds
+	r.rollupAnalytics = analytics.NewRollupAnalyticsUseCase(
+		d.partners,
+		d.transactions,
+		d.refunds,
+		repos.analytics,
+		cfg.Analytics.LookbackDays,
+		cfg.Analytics.BackfillDays,
+	)
+	r.analyticsHandler = handlers.NewAnalyticsHandler(analytics.NewGetAnalyticsUseCase(repos.analytics))
+
+	// Scheduled reports: S3 delivery assumes partners' roles, so it needs
+	// AWS credentials of Pay2Go's own
+	var reportBuckets reportupload.S3Putter
+	if cfg.AWS.AccessKeyID != "" {
+		reportBuckets, err = awsevents.NewS3Uploader(awsevents.Config{
+			AccessKeyID:     cfg.AWS.AccessKeyID,
+			SecretAccessKey: cfg.AWS.SecretAccessKey,
+			SessionToken:    cfg.AWS.SessionToken,
+			Endpoint:        cfg.AWS.Endpoint,
+		}, p.transport.Client(2*time.Minute))
+		if err != nil {
+			return nil, fmt.Errorf("invalid AWS configuration: %w", err)
+		}
+	}
+	r.deliverReports = report.NewDeliverReportsUseCase(
+		repos.reportSchedules,
+		exportWriter,
+		r.generateStatements,
+		d.exportStore,
+		ev.emailSender,
+		reportupload.NewUploader(reportBuckets, time.Duration(cfg.Reports.SFTPTimeoutSeconds)*time.Second),
+		10,
+		time.Duration(cfg.Reports.ClaimTimeoutSeconds)*time.Second,
+	)
+	r.reportScheduleHandler = handlers.NewReportScheduleHandler(
+		report.NewCreateScheduleUseCase(repos.reportSchedules, audits.logger),
+		report.NewUpdateScheduleUseCase(repos.reportSchedules, audits.logger),
+		report.NewListSchedulesUseCase(repos.reportSchedules),
+		report.NewGetScheduleUseCase(repos.reportSchedules),
+		report.NewDeleteScheduleUseCase(repos.reportSchedules, audits.logger),
+		r.deliverReports,
+	)
+
+	r.settlementHandler = handlers.NewSettlementHandler(
+		settlement.NewListDisputesUseCase(repos.disputes),
+		settlement.NewListDiscrepanciesUseCase(repos.settlementEntries),
+	)
+	return r, nil
+}
@@ -0,0 +1,255 @@
+package entities
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+const (
+	// adminSessionTokenPrefix distinguishes admin session tokens from partner credentials
+	adminSessionTokenPrefix = "adm_"
+
+	// TOTP parameters (RFC 6238 defaults, which all authenticator apps support)
+	totpPeriod      = 30 * time.Second
+	totpDigits      = 6
+	totpSkew        = 1 // steps accepted either side of now, for clock drift
+	totpSecretBytes = 20
+)
+
+// totpEncoding is the unpadded base32 alphabet authenticator apps expect
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// AdminUser is a Pay2Go staff member with access to the back-office API.
+// Admins sign in with a password and a TOTP code from an authenticator app.
+type AdminUser struct {
+	// Identity
+	ID    uuid.UUID
+	Email string
+	Name  string
+
+	// Authentication
+	PasswordHash string // Hashed with bcrypt
+	TOTPSecret   string // Base32, encrypted at rest
+	TOTPLastStep int64  // Last accepted TOTP time step; codes cannot be reused
+
+	// Timestamps
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	LastLoginAt *time.Time
+	DisabledAt  *time.Time
+}
+
+// NewAdminUser creates a new admin with a hashed password and a fresh TOTP secret
+func NewAdminUser(email, name, password string) (*AdminUser, error) {
+	// Validate required fields
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, errors.NewValidationError("email", "cannot be empty")
+	}
+
+	if name == "" {
+		return nil, errors.NewValidationError("name", "cannot be empty")
+	}
+
+	secret := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	admin := &AdminUser{
+		ID:         uuid.New(),
+		Email:      email,
+		Name:       name,
+		TOTPSecret: totpEncoding.EncodeToString(secret),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := admin.SetPassword(password); err != nil {
+		return nil, err
+	}
+
+	return admin, nil
+}
+
+// SetPassword validates and hashes a new password
+func (a *AdminUser) SetPassword(password string) error {
+	if len(password) < minPasswordLength {
+		return errors.NewValidationError("password", "must be at least 12 characters")
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	a.PasswordHash = string(hashed)
+	a.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Authenticate checks the password and TOTP code. On success the code's time
+// step is recorded, so the same code cannot be used again.
+func (a *AdminUser) Authenticate(password, code string, now time.Time) error {
+	if a.IsDisabled() {
+		return errors.ErrInvalidAdminCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(password)); err != nil {
+		return errors.ErrInvalidAdminCredentials
+	}
+
+	step, ok := a.matchTOTP(code, now)
+	if !ok {
+		return errors.ErrInvalidAdminCredentials
+	}
+
+	a.TOTPLastStep = step
+
+	return nil
+}
+
+// matchTOTP finds the time step within the allowed skew that produces code.
+// Steps at or before the last accepted one are rejected.
+func (a *AdminUser) matchTOTP(code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	secret, err := totpEncoding.DecodeString(a.TOTPSecret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := now.Unix() / int64(totpPeriod/time.Second)
+
+	// Check every candidate so timing does not reveal which step matched
+	var matched int64
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected := totpCode(secret, step)
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 && step > a.TOTPLastStep {
+			matched = step
+		}
+	}
+
+	return matched, matched != 0
+}
+
+// totpCode computes the RFC 6238 code for a time step
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI to enroll the admin in an authenticator app
+func (a *AdminUser) TOTPProvisioningURI(issuer string) string {
+	values := url.Values{}
+	values.Set("secret", a.TOTPSecret)
+	values.Set("issuer", issuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprint(totpDigits))
+	values.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+
+	label := url.PathEscape(issuer + ":" + a.Email)
+
+	return "otpauth://totp/" + label + "?" + values.Encode()
+}
+
+// RecordLogin stamps a successful sign-in
+func (a *AdminUser) RecordLogin() {
+	now := time.Now()
+	a.LastLoginAt = &now
+}
+
+// Disable revokes the admin's access; existing sessions stop working with it
+func (a *AdminUser) Disable() {
+	now := time.Now()
+	a.DisabledAt = &now
+	a.UpdatedAt = now
+}
+
+// IsDisabled checks if the admin's access was revoked
+func (a *AdminUser) IsDisabled() bool {
+	return a.DisabledAt != nil
+}
+
+// AdminSession is a signed-in admin's bearer token
+type AdminSession struct {
+	ID        uuid.UUID
+	AdminID   uuid.UUID
+	TokenHash string // SHA-256 of the token, like team member sessions
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// NewAdminSession starts a session and returns it with the plaintext token,
+// which is only available at creation time
+func NewAdminSession(adminID uuid.UUID, ttl time.Duration) (*AdminSession, string, error) {
+	if adminID == uuid.Nil {
+		return nil, "", errors.NewValidationError("admin_id", "cannot be empty")
+	}
+
+	random, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	token := adminSessionTokenPrefix + random
+
+	now := time.Now()
+
+	return &AdminSession{
+		ID:        uuid.New(),
+		AdminID:   adminID,
+		TokenHash: HashSessionToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token, nil
+}
+
+// IsAdminSessionToken reports whether a bearer token looks like an admin session token
+func IsAdminSessionToken(token string) bool {
+	return strings.HasPrefix(token, adminSessionTokenPrefix)
+}
+
+// Validate checks the session can still be used
+func (s *AdminSession) Validate() error {
+	if s.RevokedAt != nil || time.Now().After(s.ExpiresAt) {
+		return errors.ErrSessionExpired
+	}
+
+	return nil
+}
+
+// Revoke ends the session
+func (s *AdminSession) Revoke() {
+	if s.RevokedAt == nil {
+		now := time.Now()
+		s.RevokedAt = &now
+	}
+}
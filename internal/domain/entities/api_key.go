@@ -0,0 +1,221 @@
+package entities
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// APIKeyAuthMethod defines how requests made with a key are authenticated
+type APIKeyAuthMethod string
+
+const (
+	// AuthMethodBearer sends the key itself in the Authorization header
+	AuthMethodBearer APIKeyAuthMethod = "bearer"
+	// AuthMethodHMAC signs each request with the key; the key never leaves the client
+	AuthMethodHMAC APIKeyAuthMethod = "hmac"
+)
+
+// APIKey is a named credential belonging to a partner, limited by scopes
+type APIKey struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+	Label     string
+
+	// Authentication
+	KeyHash   string // Hashed with argon2id (bcrypt for legacy keys)
+	KeyPrefix string // First 8 characters for identification
+
+	// AuthMethod is fixed at creation; HMAC keys keep the secret for signature checks
+	AuthMethod    APIKeyAuthMethod
+	SigningSecret string
+
+	// Authorization
+	Scopes []valueobjects.APIKeyScope
+
+	// Livemode is false for test keys, whose data is kept apart from live data
+	Livemode bool
+
+	// Usage, recorded asynchronously so it may lag behind by a flush interval
+	LastUsedAt *time.Time
+	LastUsedIP string
+
+	// Timestamps
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// NewAPIKey creates a new API key and returns it with the plaintext key,
+// which is only available at creation time
+func NewAPIKey(
+	partnerID uuid.UUID,
+	label string,
+	scopes []valueobjects.APIKeyScope,
+	authMethod APIKeyAuthMethod,
+	livemode bool,
+) (*APIKey, string, error) {
+	// Validate required fields
+	if partnerID == uuid.Nil {
+		return nil, "", errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	if label == "" {
+		return nil, "", errors.NewValidationError("label", "cannot be empty")
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", errors.NewValidationError("scopes", "at least one scope is required")
+	}
+
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, "", errors.NewValidationError("scopes", "invalid scope "+scope.String())
+		}
+	}
+
+	if authMethod == "" {
+		authMethod = AuthMethodBearer
+	}
+
+	if authMethod != AuthMethodBearer && authMethod != AuthMethodHMAC {
+		return nil, "", errors.NewValidationError("auth_method", "must be one of bearer, hmac")
+	}
+
+	// Generate and hash key
+	plaintext, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	hashedKey, err := hashAPIKey(plaintext)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+
+	key := &APIKey{
+		ID:         uuid.New(),
+		PartnerID:  partnerID,
+		Label:      label,
+		KeyHash:    hashedKey,
+		KeyPrefix:  plaintext[:8],
+		AuthMethod: authMethod,
+		Scopes:     scopes,
+		Livemode:   livemode,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if authMethod == AuthMethodHMAC {
+		key.SigningSecret = plaintext
+	}
+
+	return key, plaintext, nil
+}
+
+// Validate checks the provided plaintext key against the stored hash
+func (k *APIKey) Validate(plaintext string) error {
+	if k.IsRevoked() {
+		return errors.ErrAPIKeyRevoked
+	}
+
+	// HMAC keys must never be sent over the wire
+	if k.AuthMethod == AuthMethodHMAC {
+		return errors.ErrAuthMethod
+	}
+
+	return verifyAPIKeyHash(k.KeyHash, plaintext)
+}
+
+// NeedsRehash reports whether the stored hash predates the current hashing scheme
+func (k *APIKey) NeedsRehash() bool {
+	return apiKeyHashOutdated(k.KeyHash)
+}
+
+// Rehash replaces the stored hash using the current scheme.
+// Only call it with a plaintext that has just been validated.
+func (k *APIKey) Rehash(plaintext string) error {
+	hashedKey, err := hashAPIKey(plaintext)
+	if err != nil {
+		return err
+	}
+
+	k.KeyHash = hashedKey
+	k.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// VerifySignature checks a hex-encoded HMAC-SHA256 signature of message.
+// Only lowercase hex is accepted, so each signature has a single spelling
+// for replay protection to remember.
+func (k *APIKey) VerifySignature(message, signature string) error {
+	if k.IsRevoked() {
+		return errors.ErrAPIKeyRevoked
+	}
+
+	if k.AuthMethod != AuthMethodHMAC || k.SigningSecret == "" {
+		return errors.ErrAuthMethod
+	}
+
+	provided, err := hex.DecodeString(signature)
+	if err != nil || hex.EncodeToString(provided) != signature {
+		return errors.ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(k.SigningSecret))
+	mac.Write([]byte(message))
+
+	if !hmac.Equal(provided, mac.Sum(nil)) {
+		return errors.ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// HasScope checks if the key grants the required scope
+func (k *APIKey) HasScope(required valueobjects.APIKeyScope) bool {
+	return valueobjects.HasScope(k.Scopes, required)
+}
+
+// Revoke permanently disables the key
+func (k *APIKey) Revoke() error {
+	if k.IsRevoked() {
+		return errors.ErrAPIKeyRevoked
+	}
+
+	now := time.Now()
+	k.RevokedAt = &now
+	k.UpdatedAt = now
+
+	return nil
+}
+
+// IsUnusedSince reports whether an active key has not been used since cutoff.
+// Keys that were never used count from their creation.
+func (k *APIKey) IsUnusedSince(cutoff time.Time) bool {
+	if k.IsRevoked() {
+		return false
+	}
+
+	lastUsed := k.CreatedAt
+	if k.LastUsedAt != nil {
+		lastUsed = *k.LastUsedAt
+	}
+
+	return lastUsed.Before(cutoff)
+}
+
+// IsRevoked checks if the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,198 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// BulkRefundJobStatus represents the state of a bulk refund job
+type BulkRefundJobStatus string
+
+const (
+	BulkRefundJobStatusPending   BulkRefundJobStatus = "pending"
+	BulkRefundJobStatusRunning   BulkRefundJobStatus = "running"
+	BulkRefundJobStatusCompleted BulkRefundJobStatus = "completed"
+)
+
+// MaxBulkRefundTransactions caps how many transactions a single job may refund
+const MaxBulkRefundTransactions = 1000
+
+// BulkRefundItemProcessing is the status of the item whose refund a worker
+// has started; once saved, a worker resuming the job checks whether the
+// refund was made before making it again
+const BulkRefundItemProcessing = "processing"
+
+// BulkRefundItem records the outcome of refunding one transaction in a job
+type BulkRefundItem struct {
+	TransactionID uuid.UUID
+	// RefundID is the ID the item's refund is created with, chosen when the
+	// item starts so a worker resuming the job finds that refund and no other
+	RefundID *uuid.UUID
+	Status   string
+	Error    string
+	// StartedAt is when the refund was started, set while it is processing
+	StartedAt *time.Time `json:",omitempty"`
+}
+
+// BulkRefundJob represents an asynchronous batch of full refunds
+type BulkRefundJob struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+
+	// Request; refunds are made in the mode of the request and audited
+	// with the address it came from
+	TransactionIDs []uuid.UUID
+	Reason         valueobjects.RefundReason
+	Livemode       bool
+	IPAddress      string
+	UserAgent      string
+
+	// State; Results holds the items done, and the one processing, in the
+	// order of TransactionIDs
+	Status    BulkRefundJobStatus
+	Results   []BulkRefundItem
+	Succeeded int
+	Failed    int
+
+	// NextAttemptAt is when an unfinished job is next due for a worker;
+	// claimed jobs are pushed past their claim timeout
+	NextAttemptAt time.Time
+
+	// Timestamps
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// NewBulkRefundJob creates a new bulk refund job with validation
+func NewBulkRefundJob(partnerID uuid.UUID, transactionIDs []uuid.UUID, reason valueobjects.RefundReason) (*BulkRefundJob, error) {
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	if len(transactionIDs) == 0 {
+		return nil, errors.NewValidationError("transaction_ids", "cannot be empty")
+	}
+
+	if len(transactionIDs) > MaxBulkRefundTransactions {
+		return nil, errors.NewValidationError("transaction_ids", "too many transactions in one job")
+	}
+
+	if !reason.IsValid() {
+		return nil, errors.NewValidationError("reason", "invalid refund reason")
+	}
+
+	// Drop duplicates so a transaction is never refunded twice by the same job
+	seen := make(map[uuid.UUID]bool, len(transactionIDs))
+	unique := make([]uuid.UUID, 0, len(transactionIDs))
+	for _, id := range transactionIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	now := time.Now()
+
+	return &BulkRefundJob{
+		ID:             uuid.New(),
+		PartnerID:      partnerID,
+		TransactionIDs: unique,
+		Reason:         reason,
+		Status:         BulkRefundJobStatusPending,
+		Results:        make([]BulkRefundItem, 0, len(unique)),
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Start marks the job as running
+func (j *BulkRefundJob) Start() error {
+	if j.Status != BulkRefundJobStatusPending {
+		return errors.NewBusinessRuleError(
+			"invalid_state_transition",
+			"can only start pending jobs",
+		)
+	}
+
+	j.Status = BulkRefundJobStatusRunning
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+// Next returns the next transaction to refund, and false once all are
+// done. It is the one processing if a worker stopped during its refund.
+func (j *BulkRefundJob) Next() (uuid.UUID, bool) {
+	if done := j.Processed(); done < len(j.TransactionIDs) {
+		return j.TransactionIDs[done], true
+	}
+	return uuid.Nil, false
+}
+
+// InFlight returns the item whose refund was started but not recorded,
+// or nil
+func (j *BulkRefundJob) InFlight() *BulkRefundItem {
+	if n := len(j.Results); n > 0 && j.Results[n-1].Status == BulkRefundItemProcessing {
+		return &j.Results[n-1]
+	}
+	return nil
+}
+
+// StartItem marks the refund of transactionID as processing, with the ID
+// its refund is to be created with
+func (j *BulkRefundJob) StartItem(transactionID uuid.UUID) {
+	if j.InFlight() != nil {
+		return
+	}
+	now := time.Now()
+	refundID := uuid.New()
+	j.Results = append(j.Results, BulkRefundItem{
+		TransactionID: transactionID,
+		RefundID:      &refundID,
+		Status:        BulkRefundItemProcessing,
+		StartedAt:     &now,
+	})
+	j.UpdatedAt = now
+}
+
+// RecordResult records the outcome for one transaction, in place of the
+// item processing
+func (j *BulkRefundJob) RecordResult(item BulkRefundItem) {
+	if item.Error != "" {
+		j.Failed++
+	} else {
+		j.Succeeded++
+	}
+
+	item.StartedAt = nil
+	if inFlight := j.InFlight(); inFlight != nil && inFlight.TransactionID == item.TransactionID {
+		*inFlight = item
+	} else {
+		j.Results = append(j.Results, item)
+	}
+	j.UpdatedAt = time.Now()
+}
+
+// Complete marks the job as finished
+func (j *BulkRefundJob) Complete() {
+	now := time.Now()
+	j.Status = BulkRefundJobStatusCompleted
+	j.CompletedAt = &now
+	j.UpdatedAt = now
+}
+
+// Processed returns how many transactions have been handled so far
+func (j *BulkRefundJob) Processed() int {
+	return j.Succeeded + j.Failed
+}
+
+// IsCompleted checks if the job has finished
+func (j *BulkRefundJob) IsCompleted() bool {
+	return j.Status == BulkRefundJobStatusCompleted
+}
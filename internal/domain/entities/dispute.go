@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// DisputeStatus represents the state of a dispute
+type DisputeStatus string
+
+const (
+	// DisputeStatusOpen: the provider charged the payment back
+	DisputeStatusOpen DisputeStatus = "open"
+	// DisputeStatusReversed: the chargeback was reversed and its funds
+	// returned
+	DisputeStatusReversed DisputeStatus = "reversed"
+)
+
+// Dispute is a cardholder's dispute of a payment, opened when the
+// provider's feed reports a chargeback
+type Dispute struct {
+	// Identity
+	ID            uuid.UUID
+	PartnerID     uuid.UUID
+	TransactionID uuid.UUID
+	Provider      valueobjects.PaymentProvider
+	// ProviderReference is the reference of the chargeback entry
+	ProviderReference string
+
+	// What was charged back
+	Amount valueobjects.Money
+	Reason string // Provider's reason code, if any
+	Status DisputeStatus
+
+	// Timestamps
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ReversedAt *time.Time
+}
+
+// NewDispute opens a dispute of transaction for a chargeback entry
+func NewDispute(transaction *Transaction, chargeback *SettlementEntry) (*Dispute, error) {
+	if chargeback.Type != SettlementEntryChargeback {
+		return nil, errors.NewValidationError("type", "disputes are opened by chargebacks")
+	}
+
+	now := time.Now()
+	return &Dispute{
+		ID:                uuid.New(),
+		PartnerID:         transaction.PartnerID,
+		TransactionID:     transaction.ID,
+		Provider:          chargeback.Provider,
+		ProviderReference: chargeback.Reference,
+		Amount:            chargeback.Amount,
+		Reason:            chargeback.Reason,
+		Status:            DisputeStatusOpen,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// Reverse records that the chargeback was reversed
+func (d *Dispute) Reverse() error {
+	if d.Status != DisputeStatusOpen {
+		return errors.NewBusinessRuleError("invalid_state", "only open disputes can be reversed")
+	}
+
+	now := time.Now()
+	d.Status = DisputeStatusReversed
+	d.ReversedAt = &now
+	d.UpdatedAt = now
+	return nil
+}
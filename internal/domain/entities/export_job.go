@@ -0,0 +1,215 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// ExportResource is what an export lists
+type ExportResource string
+
+const (
+	ExportResourceTransactions ExportResource = "transactions"
+	ExportResourceRefunds      ExportResource = "refunds"
+)
+
+// IsValid checks if the resource can be exported
+func (r ExportResource) IsValid() bool {
+	return r == ExportResourceTransactions || r == ExportResourceRefunds
+}
+
+// ExportFormat is the file format of an export
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+
+	// Files accounting systems import, of paid payments and completed
+	// refunds posted to the partner's accounts
+	ExportFormatQuickBooksIIF ExportFormat = "quickbooks_iif"
+	ExportFormatXeroCSV       ExportFormat = "xero_csv"
+)
+
+// IsValid checks if the format is supported
+func (f ExportFormat) IsValid() bool {
+	return f == ExportFormatCSV || f == ExportFormatNDJSON || f.IsAccounting()
+}
+
+// IsAccounting reports whether the format is imported by an accounting
+// system
+func (f ExportFormat) IsAccounting() bool {
+	return f == ExportFormatQuickBooksIIF || f == ExportFormatXeroCSV
+}
+
+// Extension is the file name extension of the format
+func (f ExportFormat) Extension() string {
+	switch f {
+	case ExportFormatQuickBooksIIF:
+		return "iif"
+	case ExportFormatXeroCSV:
+		return "csv"
+	}
+	return string(f)
+}
+
+// ExportLayout is how an export writes its rows
+type ExportLayout struct {
+	// Columns are the CSV columns, in order; empty writes every column
+	Columns []string
+
+	// Timezone is the IANA name of the zone times are written in; empty is
+	// UTC
+	Timezone string
+}
+
+// Location is the zone times are written in
+func (l ExportLayout) Location() (*time.Location, error) {
+	if l.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(l.Timezone)
+	if err != nil {
+		return nil, errors.NewValidationError("timezone", "unknown time zone "+l.Timezone)
+	}
+	return location, nil
+}
+
+// ExportJobStatus represents the state of an export job
+type ExportJobStatus string
+
+const (
+	ExportJobStatusPending   ExportJobStatus = "pending"
+	ExportJobStatusCompleted ExportJobStatus = "completed"
+	ExportJobStatusFailed    ExportJobStatus = "failed"
+)
+
+// MaxExportJobAttempts is how often a worker tries to produce an export
+// before it is marked failed
+const MaxExportJobAttempts = 3
+
+// ExportFilters narrow what an export lists. Unset filters match
+// everything; the ones that do not apply to the resource are ignored.
+type ExportFilters struct {
+	// Statuses matches any of the given statuses; refunds take one
+	Statuses []string
+
+	// Currency matches one currency; MinAmount and MaxAmount are inclusive,
+	// in its minor units. Transactions only.
+	Currency  string
+	MinAmount *int64
+	MaxAmount *int64
+
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+
+	// Metadata matches transactions whose metadata has every given key set
+	// to the given value
+	Metadata map[string]string
+
+	// TransactionID matches the refunds of one transaction
+	TransactionID *uuid.UUID
+}
+
+// ExportJob is a file of transactions or refunds requested through the API
+// and produced by a worker, for exports too large to page through
+type ExportJob struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+	Livemode  bool
+
+	// Request
+	Resource ExportResource
+	Format   ExportFormat
+	Filters  ExportFilters
+	Layout   ExportLayout
+
+	// State
+	Status        ExportJobStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+
+	// Result: the key of the file in the object store, and how many
+	// transactions or refunds it holds
+	ObjectKey string
+	RowCount  int64
+
+	// Timestamps
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// NewExportJob creates a pending export job, due immediately
+func NewExportJob(partnerID uuid.UUID, livemode bool, resource ExportResource, format ExportFormat, filters ExportFilters, layout ExportLayout) (*ExportJob, error) {
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	if !resource.IsValid() {
+		return nil, errors.NewValidationError("resource", "must be transactions or refunds")
+	}
+
+	if !format.IsValid() {
+		return nil, errors.NewValidationError("format", "must be csv, ndjson, quickbooks_iif or xero_csv")
+	}
+
+	if len(layout.Columns) > 0 && format != ExportFormatCSV {
+		return nil, errors.NewValidationError("columns", "only CSV exports select columns")
+	}
+
+	if _, err := layout.Location(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &ExportJob{
+		ID:            uuid.New(),
+		PartnerID:     partnerID,
+		Livemode:      livemode,
+		Resource:      resource,
+		Format:        format,
+		Filters:       filters,
+		Layout:        layout,
+		Status:        ExportJobStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// MarkCompleted records the file the worker stored under objectKey
+func (j *ExportJob) MarkCompleted(objectKey string, rows int64) {
+	now := time.Now()
+	j.Attempts++
+	j.Status = ExportJobStatusCompleted
+	j.ObjectKey = objectKey
+	j.RowCount = rows
+	j.LastError = ""
+	j.CompletedAt = &now
+}
+
+// MarkFailed records an attempt that could not produce the file. The next
+// is scheduled with exponential backoff, 30s, 60s, ..., until the last
+// attempt marks the job failed.
+func (j *ExportJob) MarkFailed(reason string) {
+	j.Attempts++
+	j.LastError = reason
+
+	if j.Attempts >= MaxExportJobAttempts {
+		now := time.Now()
+		j.Status = ExportJobStatusFailed
+		j.CompletedAt = &now
+		return
+	}
+	j.NextAttemptAt = time.Now().Add(30 * time.Second << uint(j.Attempts-1))
+}
+
+// IsCompleted reports whether the file is ready to download
+func (j *ExportJob) IsCompleted() bool {
+	return j.Status == ExportJobStatusCompleted
+}
@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// Event types written to the outbox. Every event goes to the event stream
+// when one is configured; partners receive the WebhookEvents as webhooks.
+const (
+	EventPaymentCreated    = "payment.created"
+	EventPaymentProcessing = "payment.processing"
+	EventPaymentCompleted  = "payment.completed"
+	EventPaymentFailed     = "payment.failed"
+	EventRefundCompleted   = "refund.completed"
+	EventDisputeCreated    = "dispute.created"
+	EventDisputeReversed   = "dispute.reversed"
+)
+
+// WebhookEvents are the event types delivered to partners' webhooks
+var WebhookEvents = []string{
+	EventPaymentCompleted, EventPaymentFailed, EventRefundCompleted,
+	EventDisputeCreated, EventDisputeReversed,
+}
+
+// IsWebhookEvent reports whether partners receive events of eventType
+func IsWebhookEvent(eventType string) bool {
+	for _, webhookEvent := range WebhookEvents {
+		if eventType == webhookEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxOutboxAttempts is how often publishing an event is tried before it is
+// given up on and left for an operator
+const MaxOutboxAttempts = 10
+
+// OutboxShards is how many shards events are spread over by partner. Relays
+// sharing the outbox each take a subset of them, and all of a partner's
+// events share a shard.
+const OutboxShards = 256
+
+// OutboxShardOf returns the shard of partnerID's events: the first byte of
+// the ID, which is random in version 4 UUIDs
+func OutboxShardOf(partnerID uuid.UUID) int {
+	return int(partnerID[0])
+}
+
+// OutboxEvent is an event recorded in the same database transaction as the
+// state change it describes and published afterwards by the outbox relay.
+// An event therefore exists exactly when its change was committed.
+type OutboxEvent struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+
+	// What changed
+	AggregateType string
+	AggregateID   uuid.UUID
+	EventType     string
+	Payload       json.RawMessage // Encoded once, stored and delivered as is
+
+	// Publishing
+	Shard         int
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+
+	// Timestamps
+	CreatedAt   time.Time
+	PublishedAt *time.Time
+}
+
+// NewOutboxEvent creates a new event, due for publishing immediately.
+// payload is encoded to JSON here, so it is best a typed struct rather
+// than a map: relaying the event never decodes or re-encodes it.
+func NewOutboxEvent(
+	partnerID uuid.UUID,
+	aggregateType string,
+	aggregateID uuid.UUID,
+	eventType string,
+	payload interface{},
+) (*OutboxEvent, error) {
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	if aggregateType == "" || aggregateID == uuid.Nil {
+		return nil, errors.NewValidationError("aggregate", "cannot be empty")
+	}
+
+	if eventType == "" {
+		return nil, errors.NewValidationError("event_type", "cannot be empty")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.NewValidationError("payload", err.Error())
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		PartnerID:     partnerID,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+		Shard:         OutboxShardOf(partnerID),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// MarkPublished records a successful publish
+func (e *OutboxEvent) MarkPublished() {
+	now := time.Now()
+	e.Attempts++
+	e.LastError = ""
+	e.PublishedAt = &now
+}
+
+// MarkFailed records a failed publish and schedules the next attempt with
+// exponential backoff: 30s, 1m, 2m, ... capped at 1h
+func (e *OutboxEvent) MarkFailed(reason string) {
+	e.Attempts++
+	e.LastError = reason
+
+	backoff := 30 * time.Second << uint(e.Attempts-1)
+	if backoff > time.Hour || backoff <= 0 {
+		backoff = time.Hour
+	}
+	e.NextAttemptAt = time.Now().Add(backoff)
+}
+
+// IsPublished reports whether the event has been published
+func (e *OutboxEvent) IsPublished() bool {
+	return e.PublishedAt != nil
+}
+
+// IsExhausted reports whether the relay has stopped retrying the event
+func (e *OutboxEvent) IsExhausted() bool {
+	return !e.IsPublished() && e.Attempts >= MaxOutboxAttempts
+}
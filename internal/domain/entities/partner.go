@@ -1,187 +1,525 @@
 package entities
-package entities
 
 import (
-	"crypto/rand"
-	"encoding/base64"
+	"net/url"
 	"time"
 
+	"github.com/google/uuid"
 
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return string(hashedBytes), nil	}		return "", err	if err != nil {	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(apiKey), bcrypt.DefaultCost)func hashAPIKey(apiKey string) (string, error) {// hashAPIKey hashes an API key using bcrypt}	return base64.URLEncoding.EncodeToString(bytes), nil	}		return "", err	if _, err := rand.Read(bytes); err != nil {	bytes := make([]byte, 32)func generateAPIKey() (string, error) {// generateAPIKey generates a cryptographically secure API key// Helper functions}	return p.DeletedAt != nilfunc (p *Partner) IsDeleted() bool {// IsDeleted checks if partner is soft-deleted}	p.IsActive = false	p.UpdatedAt = now	p.DeletedAt = &now	now := time.Now()func (p *Partner) SoftDelete() {// SoftDelete marks partner as deleted}	return val, exists	val, exists := p.Metadata[key]	}		return nil, false	if p.Metadata == nil {func (p *Partner) GetMetadata(key string) (interface{}, bool) {// GetMetadata retrieves metadata value}	p.UpdatedAt = time.Now()	p.Metadata[key] = value	}		p.Metadata = make(map[string]interface{})	if p.Metadata == nil {func (p *Partner) SetMetadata(key string, value interface{}) {// SetMetadata sets metadata with validation}	return nil		p.UpdatedAt = time.Now()	p.RateLimitPerMinute = limit		}		return errors.NewValidationError("rate_limit", "cannot exceed 10000")	if limit > 10000 {		}		return errors.NewValidationError("rate_limit", "must be at least 1")	if limit < 1 {func (p *Partner) SetRateLimit(limit int) error {// SetRateLimit sets the rate limit for this partner}	return nil		p.UpdatedAt = time.Now()	p.WebhookSecret = secret	p.WebhookURL = url		}		return errors.NewValidationError("webhook_url", "cannot be empty")	if url == "" {func (p *Partner) SetWebhook(url, secret string) error {// SetWebhook sets the webhook configuration}	p.UpdatedAt = time.Now()	p.IsActive = falsefunc (p *Partner) Deactivate() {// Deactivate deactivates the partner account}	p.UpdatedAt = time.Now()	p.IsActive = truefunc (p *Partner) Activate() {// Activate activates the partner account}	return nil		}		return errors.ErrInvalidAPIKey	if err != nil {	err := bcrypt.CompareHashAndPassword([]byte(p.APIKeyHash), []byte(apiKey))		}		return errors.ErrPartnerInactive	if !p.IsActive {func (p *Partner) ValidateAPIKey(apiKey string) error {// ValidateAPIKey validates the provided API key against the stored hash}	return partner, nil		}		Metadata:           make(map[string]interface{}),		UpdatedAt:          now,		CreatedAt:          now,		RateLimitPerMinute: 100, // Default rate limit		IsActive:           true,		APIKeyPrefix:       apiKey[:8], // Store prefix for identification		APIKeyHash:         hashedKey,		Email:              email,		Name:               name,		ID:                 uuid.New(),	partner := &Partner{		now := time.Now()		}		return nil, err	if err != nil {	hashedKey, err := hashAPIKey(apiKey)	// Hash API key		}		return nil, err	if err != nil {	apiKey, err := generateAPIKey()	// Generate API key		}		return nil, errors.NewValidationError("email", "cannot be empty")	if email == "" {		}		return nil, errors.NewValidationError("name", "cannot be empty")	if name == "" {	// Validate required fieldsfunc NewPartner(name, email string) (*Partner, error) {// NewPartner creates a new partner with validation}	DeletedAt   *time.Time	UpdatedAt   time.Time	CreatedAt   time.Time	// Timestamps		Metadata    map[string]interface{}	// Additional data		WebhookSecret       string	WebhookURL          string	RateLimitPerMinute  int	IsActive            bool	// Configuration		APIKeyPrefix string // First 8 characters for identification	APIKeyHash   string // Hashed with bcrypt	// Authentication		Email       string	Name        string	ID          uuid.UUID	// Identitytype Partner struct {// Partner represents a merchant/client using the payment orchestration API)	"Pay2Go/internal/domain/errors"	"golang.org/x/crypto/bcrypt"	"github.com/google/uuid"
\ No newline at end of file
+// Partner represents a merchant/client using the payment orchestration API
+type Partner struct {
+	// Identity
+	ID    uuid.UUID
+	Name  string
+	Email string
+
+	// Authentication
+	APIKeyHash   string // Hashed with argon2id (bcrypt for legacy keys)
+	APIKeyPrefix string // First 8 characters for identification
+
+	// Configuration
+	IsActive           bool
+	RateLimitPerMinute int
+	WebhookURL         string
+	WebhookSecret      string
+
+	// EventDestination, when set, receives the partner's events in its own
+	// AWS account instead of WebhookURL
+	EventDestination *valueobjects.EventDestination
+
+	// RefundApprovalThresholds holds, per currency, the amount above which
+	// refunds need manual approval; refunds in other currencies need none
+	RefundApprovalThresholds valueobjects.AmountLimits
+
+	// Days after payment a refund is allowed (0 uses the platform default)
+	RefundWindowDays int
+
+	// Features holds explicit feature flags; missing flags use the feature's default
+	Features map[valueobjects.PartnerFeature]bool
+
+	// AllowedCurrencies restricts the currencies the partner may charge in (empty allows all)
+	AllowedCurrencies []valueobjects.Currency
+
+	// Locale is the partner's preferred language and region for dashboards,
+	// statements and emails
+	Locale valueobjects.Locale
+
+	// RoundingPolicy decides how fees, taxes and divided amounts are rounded,
+	// so totals reconcile with the partner's own accounting
+	RoundingPolicy valueobjects.RoundingPolicy
+
+	// AmountLimits overrides the platform maximum transaction amount per currency
+	AmountLimits valueobjects.AmountLimits
+
+	// SMSSenderID is who text messages to the partner's customers come
+	// from; empty uses the platform's sender
+	SMSSenderID valueobjects.SMSSenderID
+
+	// FieldFilter hides fields from API responses, by the scope of the
+	// calling key, and from delivered events
+	FieldFilter valueobjects.FieldFilter
+
+	// ReceiptLogoKey is where the logo printed on the partner's receipts is
+	// kept in the object store; empty prints the partner's name alone
+	ReceiptLogoKey string
+
+	// Pricing is what the partner's statements charge; nil charges the
+	// platform's standard pricing
+	Pricing *valueobjects.Pricing
+
+	// AccountMapping names the accounts accounting exports post to; nil
+	// posts to the default accounts
+	AccountMapping *valueobjects.AccountMapping
+
+	// Additional data
+	Metadata map[string]interface{}
+
+	// Version counts saved changes; an update only succeeds against the
+	// version it was loaded at (optimistic locking)
+	Version int
+
+	// Off-boarding: customer PII on the partner's transactions is anonymized
+	// once AnonymizeAfter has passed
+	AnonymizeAfter *time.Time
+	AnonymizedAt   *time.Time
+
+	// Timestamps
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// NewPartner creates a new partner with validation
+func NewPartner(name, email string) (*Partner, error) {
+	// Validate required fields
+	if name == "" {
+		return nil, errors.NewValidationError("name", "cannot be empty")
+	}
+
+	if email == "" {
+		return nil, errors.NewValidationError("email", "cannot be empty")
+	}
+
+	// Generate API key
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hash API key
+	hashedKey, err := hashAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	partner := &Partner{
+		ID:                 uuid.New(),
+		Name:               name,
+		Email:              email,
+		APIKeyHash:         hashedKey,
+		APIKeyPrefix:       apiKey[:8], // Store prefix for identification
+		IsActive:           true,
+		Locale:             valueobjects.DefaultLocale,
+		RoundingPolicy:     valueobjects.DefaultRoundingPolicy,
+		RateLimitPerMinute: 100, // Default rate limit
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		Features:           make(map[valueobjects.PartnerFeature]bool),
+		Metadata:           make(map[string]interface{}),
+		Version:            1,
+	}
+
+	return partner, nil
+}
+
+// SetContact changes the partner's name and email
+func (p *Partner) SetContact(name, email string) error {
+	if name == "" {
+		return errors.NewValidationError("name", "cannot be empty")
+	}
+
+	if email == "" {
+		return errors.NewValidationError("email", "cannot be empty")
+	}
+
+	p.Name = name
+	p.Email = email
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ValidateAPIKey validates the provided API key against the stored hash
+func (p *Partner) ValidateAPIKey(apiKey string) error {
+	if !p.IsActive {
+		return errors.ErrPartnerInactive
+	}
+
+	return verifyAPIKeyHash(p.APIKeyHash, apiKey)
+}
+
+// Activate activates the partner account
+func (p *Partner) Activate() {
+	p.IsActive = true
+	p.UpdatedAt = time.Now()
+}
+
+// Deactivate deactivates the partner account
+func (p *Partner) Deactivate() {
+	p.IsActive = false
+	p.UpdatedAt = time.Now()
+}
+
+// SetWebhook sets the webhook configuration
+func (p *Partner) SetWebhook(url, secret string) error {
+	if url == "" {
+		return errors.NewValidationError("webhook_url", "cannot be empty")
+	}
+
+	p.WebhookURL = url
+	p.WebhookSecret = secret
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ConfigureWebhook delivers the partner's events to url, signed with the
+// existing webhook secret, or with a new one when the partner has none. It
+// returns the new secret, or "" when the existing one was kept.
+func (p *Partner) ConfigureWebhook(webhookURL string) (string, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", errors.NewValidationError("webhook_url", "must be an absolute http or https URL")
+	}
+
+	secret := ""
+	if p.WebhookSecret == "" {
+		if secret, err = generateAPIKey(); err != nil {
+			return "", err
+		}
+		p.WebhookSecret = secret
+	}
+
+	p.WebhookURL = webhookURL
+	p.UpdatedAt = time.Now()
+
+	return secret, nil
+}
+
+// RemoveWebhook stops webhook deliveries and forgets the webhook secret, so
+// configuring a webhook again issues a new one
+func (p *Partner) RemoveWebhook() {
+	p.WebhookURL = ""
+	p.WebhookSecret = ""
+	p.UpdatedAt = time.Now()
+}
+
+// SetEventDestination delivers the partner's events to its own SNS topic or
+// SQS queue instead of its webhook URL; nil goes back to webhooks
+func (p *Partner) SetEventDestination(destination *valueobjects.EventDestination) {
+	p.EventDestination = destination
+	p.UpdatedAt = time.Now()
+}
+
+// SetRateLimit sets the rate limit for this partner
+func (p *Partner) SetRateLimit(limit int) error {
+	if limit < 1 {
+		return errors.NewValidationError("rate_limit", "must be at least 1")
+	}
+
+	if limit > 10000 {
+		return errors.NewValidationError("rate_limit", "cannot exceed 10000")
+	}
+
+	p.RateLimitPerMinute = limit
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetRefundApprovalThresholds sets the amounts above which refunds need
+// approval, given as decimal amounts in major units keyed by ISO 4217 code,
+// e.g. {"USD": "1000.00", "JPY": "150000"}. An empty map disables approval.
+func (p *Partner) SetRefundApprovalThresholds(thresholds map[string]string) error {
+	parsed, err := valueobjects.ParseCurrencyAmounts("refund_approval_thresholds", thresholds)
+	if err != nil {
+		return err
+	}
+
+	p.RefundApprovalThresholds = parsed
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// RequiresRefundApproval checks if refund is above the threshold for its
+// currency. Thresholds are only compared with amounts in their own currency.
+func (p *Partner) RequiresRefundApproval(refund valueobjects.Money) bool {
+	threshold, ok := p.RefundApprovalThresholds.Limit(refund.Currency)
+	return ok && refund.Amount > threshold
+}
+
+// SetRefundWindowDays sets how long after payment refunds are allowed
+func (p *Partner) SetRefundWindowDays(days int) error {
+	if days < 0 {
+		return errors.NewValidationError("refund_window_days", "cannot be negative")
+	}
+
+	if days > 3650 {
+		return errors.NewValidationError("refund_window_days", "cannot exceed 3650")
+	}
+
+	p.RefundWindowDays = days
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// EffectiveRefundWindowDays returns the partner's refund window or the platform default
+func (p *Partner) EffectiveRefundWindowDays(defaultDays int) int {
+	if p.RefundWindowDays > 0 {
+		return p.RefundWindowDays
+	}
+	return defaultDays
+}
+
+// HasFeature checks if a feature is enabled for this partner
+func (p *Partner) HasFeature(feature valueobjects.PartnerFeature) bool {
+	if enabled, ok := p.Features[feature]; ok {
+		return enabled
+	}
+	return feature.EnabledByDefault()
+}
+
+// SetFeature turns a feature on or off for this partner
+func (p *Partner) SetFeature(feature valueobjects.PartnerFeature, enabled bool) error {
+	if !feature.IsValid() {
+		return errors.NewValidationError("feature", "unknown feature "+feature.String())
+	}
+
+	if p.Features == nil {
+		p.Features = make(map[valueobjects.PartnerFeature]bool)
+	}
+	p.Features[feature] = enabled
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// EffectiveFeatures returns whether each known feature is enabled, defaults included
+func (p *Partner) EffectiveFeatures() map[valueobjects.PartnerFeature]bool {
+	features := make(map[valueobjects.PartnerFeature]bool)
+	for _, feature := range valueobjects.PartnerFeatures() {
+		features[feature] = p.HasFeature(feature)
+	}
+	return features
+}
+
+// AcceptsCurrency checks if the partner may create transactions in currency
+func (p *Partner) AcceptsCurrency(currency valueobjects.Currency) bool {
+	if len(p.AllowedCurrencies) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.AllowedCurrencies {
+		if allowed == currency {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetAllowedCurrencies restricts the partner to the given ISO 4217 codes.
+// An empty list lifts the restriction.
+func (p *Partner) SetAllowedCurrencies(codes []string) error {
+	currencies := make([]valueobjects.Currency, 0, len(codes))
+	seen := make(map[valueobjects.Currency]bool, len(codes))
+	for _, code := range codes {
+		currency, err := valueobjects.NewCurrency(code)
+		if err != nil {
+			return errors.NewValidationError("currencies", "unknown currency "+code)
+		}
+		if !seen[currency] {
+			seen[currency] = true
+			currencies = append(currencies, currency)
+		}
+	}
+
+	p.AllowedCurrencies = currencies
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetLocale sets the partner's preferred BCP 47 locale, e.g. "th-TH"
+func (p *Partner) SetLocale(tag string) error {
+	locale, err := valueobjects.NewLocale(tag)
+	if err != nil {
+		return errors.NewValidationError("locale", "must be a language tag such as en-US")
+	}
+
+	p.Locale = locale
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetSMSSenderID sets who text messages to the partner's customers come
+// from; empty goes back to the platform's sender
+func (p *Partner) SetSMSSenderID(sender string) error {
+	senderID, err := valueobjects.NewSMSSenderID(sender)
+	if err != nil {
+		return err
+	}
+
+	p.SMSSenderID = senderID
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetFieldFilter sets the fields hidden from the partner's API responses,
+// per API key scope, and from its webhooks; empty lists hide nothing
+func (p *Partner) SetFieldFilter(responses map[string][]string, webhooks []string) error {
+	filter, err := valueobjects.NewFieldFilter(responses, webhooks)
+	if err != nil {
+		return err
+	}
+
+	p.FieldFilter = filter
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetReceiptLogo sets the object store key of the logo printed on the
+// partner's receipts; empty removes the logo
+func (p *Partner) SetReceiptLogo(key string) {
+	p.ReceiptLogoKey = key
+	p.UpdatedAt = time.Now()
+}
+
+// SetPricing sets what the partner's statements charge; nil returns the
+// partner to the platform's standard pricing
+func (p *Partner) SetPricing(pricing *valueobjects.Pricing) {
+	p.Pricing = pricing
+	p.UpdatedAt = time.Now()
+}
+
+// SetAccountMapping sets the accounts the partner's accounting exports
+// post to; nil returns the partner to the default accounts
+func (p *Partner) SetAccountMapping(mapping *valueobjects.AccountMapping) {
+	p.AccountMapping = mapping
+	p.UpdatedAt = time.Now()
+}
+
+// Accounts returns the accounts the partner's accounting exports post to
+func (p *Partner) Accounts() valueobjects.AccountMapping {
+	if p.AccountMapping == nil {
+		return valueobjects.DefaultAccountMapping()
+	}
+	return *p.AccountMapping
+}
+
+// SetRoundingPolicy sets how the partner's fees, taxes and divided amounts are rounded
+func (p *Partner) SetRoundingPolicy(policy string) error {
+	rounding, err := valueobjects.NewRoundingPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	p.RoundingPolicy = rounding
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetAmountLimits sets the partner's maximum transaction amounts, given as
+// decimal amounts in major units keyed by ISO 4217 code. Currencies without
+// a limit fall back to the platform's; an empty map removes all overrides.
+func (p *Partner) SetAmountLimits(limits map[string]string) error {
+	parsed, err := valueobjects.ParseAmountLimits(limits)
+	if err != nil {
+		return err
+	}
+
+	p.AmountLimits = parsed
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// MaxAmount resolves the maximum transaction amount in currency's minor units:
+// the partner's own limit, then the platform limit, then the platform default.
+// scope names where the limit is configured.
+func (p *Partner) MaxAmount(currency valueobjects.Currency, platform valueobjects.AmountLimits) (limit int64, scope string) {
+	if limit, ok := p.AmountLimits.Limit(currency); ok {
+		return limit, errors.AmountLimitScopePartner
+	}
+	if limit, ok := platform.Limit(currency); ok {
+		return limit, errors.AmountLimitScopePlatform
+	}
+	return valueobjects.DefaultMaxAmount(currency), errors.AmountLimitScopePlatform
+}
+
+// CheckAmountLimit returns an *errors.AmountLimitError if money exceeds the
+// partner's maximum for its currency
+func (p *Partner) CheckAmountLimit(money valueobjects.Money, platform valueobjects.AmountLimits) error {
+	limit, scope := p.MaxAmount(money.Currency, platform)
+	return money.CheckLimit(limit, scope)
+}
+
+// SetMetadata sets metadata with validation
+func (p *Partner) SetMetadata(key string, value interface{}) {
+	if p.Metadata == nil {
+		p.Metadata = make(map[string]interface{})
+	}
+	p.Metadata[key] = value
+	p.UpdatedAt = time.Now()
+}
+
+// GetMetadata retrieves metadata value
+func (p *Partner) GetMetadata(key string) (interface{}, bool) {
+	if p.Metadata == nil {
+		return nil, false
+	}
+	val, exists := p.Metadata[key]
+	return val, exists
+}
+
+// SoftDelete marks partner as deleted
+func (p *Partner) SoftDelete() {
+	now := time.Now()
+	p.DeletedAt = &now
+	p.UpdatedAt = now
+	p.IsActive = false
+}
+
+// Offboard soft-deletes the partner, stops webhooks and schedules anonymization
+// of customer PII once the retention period has passed
+func (p *Partner) Offboard(retention time.Duration) error {
+	if p.IsDeleted() {
+		return errors.NewBusinessRuleError("partner_already_offboarded", "partner has already been off-boarded")
+	}
+
+	p.SoftDelete()
+	p.WebhookURL = ""
+	p.WebhookSecret = ""
+
+	anonymizeAfter := p.DeletedAt.Add(retention)
+	p.AnonymizeAfter = &anonymizeAfter
+
+	return nil
+}
+
+// IsDeleted checks if partner is soft-deleted
+func (p *Partner) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// MaxPaymentJobAttempts is how often a worker tries to process a queued
+// payment before it is given up on and left for an operator
+const MaxPaymentJobAttempts = 5
+
+// PaymentJob is a request to process a transaction's payment, queued by the
+// API and run by a worker
+type PaymentJob struct {
+	// Identity
+	ID            uuid.UUID
+	PartnerID     uuid.UUID
+	TransactionID uuid.UUID
+
+	// Running
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+
+	// Timestamps
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// NewPaymentJob creates a job processing transactionID, due immediately
+func NewPaymentJob(partnerID, transactionID uuid.UUID) (*PaymentJob, error) {
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	if transactionID == uuid.Nil {
+		return nil, errors.NewValidationError("transaction_id", "cannot be empty")
+	}
+
+	now := time.Now()
+	return &PaymentJob{
+		ID:            uuid.New(),
+		PartnerID:     partnerID,
+		TransactionID: transactionID,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// MarkCompleted records that the job ran to an outcome. reason is why the
+// payment was not made, or empty when it was.
+func (j *PaymentJob) MarkCompleted(reason string) {
+	now := time.Now()
+	j.Attempts++
+	j.LastError = reason
+	j.CompletedAt = &now
+}
+
+// MarkFailed records an attempt that could not run and schedules the next
+// with exponential backoff: 10s, 20s, 40s, ... capped at 10m
+func (j *PaymentJob) MarkFailed(reason string) {
+	j.Attempts++
+	j.LastError = reason
+
+	backoff := 10 * time.Second << uint(j.Attempts-1)
+	if backoff > 10*time.Minute || backoff <= 0 {
+		backoff = 10 * time.Minute
+	}
+	j.NextAttemptAt = time.Now().Add(backoff)
+}
+
+// IsCompleted reports whether the job has run to an outcome
+func (j *PaymentJob) IsCompleted() bool {
+	return j.CompletedAt != nil
+}
+
+// IsExhausted reports whether workers have stopped retrying the job
+func (j *PaymentJob) IsExhausted() bool {
+	return !j.IsCompleted() && j.Attempts >= MaxPaymentJobAttempts
+}
@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// ProviderCredential is a partner's own account with a payment provider.
+// Live payments for the provider are processed on this account instead of the platform's.
+type ProviderCredential struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+	Provider  valueobjects.PaymentProvider
+
+	// AccountID identifies the account (Stripe account ID, PayPal client ID)
+	AccountID string
+	// SecretKey authenticates with the provider (Stripe secret key, PayPal client secret)
+	SecretKey string
+
+	// Timestamps
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewProviderCredential creates a new provider credential with validation
+func NewProviderCredential(
+	partnerID uuid.UUID,
+	provider valueobjects.PaymentProvider,
+	accountID, secretKey string,
+) (*ProviderCredential, error) {
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	if err := validateProviderCredential(provider, accountID, secretKey); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &ProviderCredential{
+		ID:        uuid.New(),
+		PartnerID: partnerID,
+		Provider:  provider,
+		AccountID: accountID,
+		SecretKey: secretKey,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Replace swaps in new credentials, e.g. after the partner rolls their provider keys.
+// The credential keeps its ID, so payments made with the old keys can still be refunded.
+func (c *ProviderCredential) Replace(accountID, secretKey string) error {
+	if err := validateProviderCredential(c.Provider, accountID, secretKey); err != nil {
+		return err
+	}
+
+	c.AccountID = accountID
+	c.SecretKey = secretKey
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// MaskedSecret returns the secret with all but its last 4 characters hidden
+func (c *ProviderCredential) MaskedSecret() string {
+	if len(c.SecretKey) <= 4 {
+		return "****"
+	}
+	return "****" + c.SecretKey[len(c.SecretKey)-4:]
+}
+
+// validateProviderCredential checks the fields each provider needs
+func validateProviderCredential(provider valueobjects.PaymentProvider, accountID, secretKey string) error {
+	switch provider {
+	case valueobjects.ProviderStripe:
+		// Stripe authenticates with the secret key alone
+	case valueobjects.ProviderPayPal:
+		if accountID == "" {
+			return errors.NewValidationError("account_id", "PayPal client ID is required")
+		}
+	default:
+		return errors.NewValidationError("provider", "own credentials are supported for stripe and paypal")
+	}
+
+	if secretKey == "" {
+		return errors.NewValidationError("secret_key", "cannot be empty")
+	}
+
+	return nil
+}
@@ -13,10 +13,12 @@ import (
 type RefundStatus string
 
 const (
-	RefundStatusPending    RefundStatus = "pending"
-	RefundStatusProcessing RefundStatus = "processing"
-	RefundStatusCompleted  RefundStatus = "completed"
-	RefundStatusFailed     RefundStatus = "failed"
+	RefundStatusPending          RefundStatus = "pending"
+	RefundStatusRequiresApproval RefundStatus = "requires_approval"
+	RefundStatusProcessing       RefundStatus = "processing"
+	RefundStatusCompleted        RefundStatus = "completed"
+	RefundStatusFailed           RefundStatus = "failed"
+	RefundStatusCancelled        RefundStatus = "cancelled"
 )
 
 // Refund represents a refund entity
@@ -30,11 +32,19 @@ type Refund struct {
 
 	// State
 	Status RefundStatus
-	Reason string
+	Reason valueobjects.RefundReason
+
+	// Version counts saved changes; an update only succeeds against the
+	// version it was loaded at (optimistic locking)
+	Version int
 
 	// Provider details
 	ProviderRefundID string
 
+	// Approval (set when the refund required manual approval)
+	ApprovedBy string
+	ApprovedAt *time.Time
+
 	// Error handling
 	ErrorCode    string
 	ErrorMessage string
@@ -43,6 +53,7 @@ type Refund struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	ProcessedAt *time.Time
+	CancelledAt *time.Time
 	DeletedAt   *time.Time
 }
 
@@ -50,7 +61,7 @@ type Refund struct {
 func NewRefund(
 	transactionID uuid.UUID,
 	amount valueobjects.Money,
-	reason string,
+	reason valueobjects.RefundReason,
 ) (*Refund, error) {
 
 	// Validate required fields
@@ -58,8 +69,8 @@ func NewRefund(
 		return nil, errors.NewValidationError("transaction_id", "cannot be empty")
 	}
 
-	if reason == "" {
-		return nil, errors.NewValidationError("reason", "cannot be empty")
+	if !reason.IsValid() {
+		return nil, errors.NewValidationError("reason", "invalid refund reason")
 	}
 
 	// Validate amount
@@ -75,11 +86,48 @@ func NewRefund(
 		Amount:        amount,
 		Reason:        reason,
 		Status:        RefundStatusPending,
+		Version:       1,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}, nil
 }
 
+// RequireApproval parks a pending refund until an administrator approves it
+func (r *Refund) RequireApproval() error {
+	if r.Status != RefundStatusPending {
+		return errors.NewBusinessRuleError(
+			"invalid_state_transition",
+			"only pending refunds can require approval",
+		)
+	}
+
+	r.Status = RefundStatusRequiresApproval
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Approve records the approver and releases the refund back to pending
+func (r *Refund) Approve(approvedBy string) error {
+	if r.Status != RefundStatusRequiresApproval {
+		return errors.NewBusinessRuleError(
+			"invalid_state_transition",
+			"can only approve refunds awaiting approval",
+		)
+	}
+
+	if approvedBy == "" {
+		return errors.NewValidationError("approved_by", "cannot be empty")
+	}
+
+	now := time.Now()
+	r.Status = RefundStatusPending
+	r.ApprovedBy = approvedBy
+	r.ApprovedAt = &now
+	r.UpdatedAt = now
+
+	return nil
+}
+
 // MarkAsProcessing transitions refund to processing state
 func (r *Refund) MarkAsProcessing() error {
 	if r.Status != RefundStatusPending {
@@ -131,16 +179,43 @@ func (r *Refund) MarkAsFailed(errorCode, errorMessage string) error {
 	return nil
 }
 
+// Cancel withdraws a refund that has not yet been sent to the provider
+func (r *Refund) Cancel() error {
+	if r.Status != RefundStatusPending && r.Status != RefundStatusRequiresApproval {
+		return errors.NewBusinessRuleError(
+			"invalid_state_transition",
+			"can only cancel pending refunds or refunds awaiting approval",
+		)
+	}
+
+	now := time.Now()
+	r.Status = RefundStatusCancelled
+	r.CancelledAt = &now
+	r.UpdatedAt = now
+
+	return nil
+}
+
 // IsCompleted checks if refund is completed
 func (r *Refund) IsCompleted() bool {
 	return r.Status == RefundStatusCompleted
 }
 
+// RequiresApproval checks if refund is waiting for approval
+func (r *Refund) RequiresApproval() bool {
+	return r.Status == RefundStatusRequiresApproval
+}
+
 // IsFailed checks if refund has failed
 func (r *Refund) IsFailed() bool {
 	return r.Status == RefundStatusFailed
 }
 
+// IsCancelled checks if refund was cancelled
+func (r *Refund) IsCancelled() bool {
+	return r.Status == RefundStatusCancelled
+}
+
 // SoftDelete marks refund as deleted
 func (r *Refund) SoftDelete() {
 	now := time.Now()
@@ -0,0 +1,282 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// ReportResource is what a scheduled report lists: transactions or refunds
+// as exports write them, or the partner's monthly statement
+type ReportResource string
+
+const (
+	ReportResourceTransactions ReportResource = "transactions"
+	ReportResourceRefunds      ReportResource = "refunds"
+	ReportResourceStatements   ReportResource = "statements"
+)
+
+// ReportFrequency is how often a scheduled report is delivered
+type ReportFrequency string
+
+const (
+	ReportFrequencyDaily   ReportFrequency = "daily"
+	ReportFrequencyWeekly  ReportFrequency = "weekly"
+	ReportFrequencyMonthly ReportFrequency = "monthly"
+)
+
+// ReportRunStatus is the outcome of a schedule's last run
+type ReportRunStatus string
+
+const (
+	ReportRunDelivered ReportRunStatus = "delivered"
+	ReportRunFailed    ReportRunStatus = "failed"
+)
+
+// MaxReportRunAttempts is how often a run is tried before the schedule
+// moves on to its next run
+const MaxReportRunAttempts = 3
+
+// ReportSettings are what a partner configures on a report schedule
+type ReportSettings struct {
+	Name     string
+	Resource ReportResource
+	Format   ExportFormat
+
+	// Filters narrow transactions and refunds as an export's do, except
+	// that the range created is the period of each run
+	Filters ExportFilters
+
+	// Layout picks the CSV columns. Its time zone is also the zone the
+	// schedule runs in and its periods start in.
+	Layout ExportLayout
+
+	// A run is delivered at Hour, 0 to 23, every day, every Monday or on
+	// the first of every month, and covers the day, week or month before
+	Frequency ReportFrequency
+	Hour      int
+
+	Destination valueobjects.ReportDestination
+}
+
+// ReportSchedule delivers a report of a partner's transactions, refunds or
+// statements to the partner by email, SFTP or S3 on a schedule
+type ReportSchedule struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+	Livemode  bool
+
+	ReportSettings
+
+	// State: when the next run is due, how often it was tried, and the
+	// outcome of the last run
+	NextRunAt  time.Time
+	Attempts   int
+	LastRunAt  *time.Time
+	LastStatus ReportRunStatus
+	LastError  string
+
+	// Version increases on every change, for optimistic locking
+	Version int
+
+	// Timestamps
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewReportSchedule creates a schedule of partnerID in livemode whose first
+// run is the next after now
+func NewReportSchedule(partnerID uuid.UUID, livemode bool, settings ReportSettings, now time.Time) (*ReportSchedule, error) {
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+	settings, err := validateReportSettings(settings, livemode)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &ReportSchedule{
+		ID:             uuid.New(),
+		PartnerID:      partnerID,
+		Livemode:       livemode,
+		ReportSettings: settings,
+		Version:        1,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	schedule.NextRunAt = schedule.NextRun(now)
+	return schedule, nil
+}
+
+// Reconfigure replaces the schedule's settings. Its next run is the next
+// after now of the new settings, and past outcomes are kept.
+func (s *ReportSchedule) Reconfigure(settings ReportSettings, now time.Time) error {
+	settings, err := validateReportSettings(settings, s.Livemode)
+	if err != nil {
+		return err
+	}
+	s.ReportSettings = settings
+	s.NextRunAt = s.NextRun(now)
+	s.Attempts = 0
+	s.UpdatedAt = now
+	return nil
+}
+
+func validateReportSettings(settings ReportSettings, livemode bool) (ReportSettings, error) {
+	settings.Name = strings.TrimSpace(settings.Name)
+	if settings.Name == "" || len(settings.Name) > 100 {
+		return settings, errors.NewValidationError("name", "must be 1 to 100 characters")
+	}
+
+	switch settings.Resource {
+	case ReportResourceTransactions, ReportResourceRefunds:
+		if !settings.Format.IsValid() {
+			return settings, errors.NewValidationError("format", "must be csv, ndjson, quickbooks_iif or xero_csv")
+		}
+		if len(settings.Layout.Columns) > 0 && settings.Format != ExportFormatCSV {
+			return settings, errors.NewValidationError("columns", "only CSV reports select columns")
+		}
+		if settings.Resource == ReportResourceRefunds && len(settings.Filters.Statuses) > 1 {
+			return settings, errors.NewValidationError("status", "refunds are filtered by one status")
+		}
+	case ReportResourceStatements:
+		// Statements are issued for UTC months, of live payments, as one CSV
+		// document; accounting formats deliver the fees they charge
+		if !livemode {
+			return settings, errors.NewValidationError("resource", "statements are only issued in live mode")
+		}
+		if settings.Frequency != ReportFrequencyMonthly {
+			return settings, errors.NewValidationError("frequency", "statements are delivered monthly")
+		}
+		if settings.Format != ExportFormatCSV && !settings.Format.IsAccounting() {
+			return settings, errors.NewValidationError("format", "statements are delivered as csv, quickbooks_iif or xero_csv")
+		}
+		if settings.Layout.Timezone != "" && settings.Layout.Timezone != "UTC" {
+			return settings, errors.NewValidationError("timezone", "statements cover UTC months")
+		}
+		settings.Filters = ExportFilters{}
+		settings.Layout = ExportLayout{}
+	default:
+		return settings, errors.NewValidationError("resource", "must be transactions, refunds or statements")
+	}
+
+	if settings.Filters.CreatedFrom != nil || settings.Filters.CreatedTo != nil {
+		return settings, errors.NewValidationError("filters", "the range created is the period of each run")
+	}
+	if _, err := settings.Layout.Location(); err != nil {
+		return settings, err
+	}
+
+	switch settings.Frequency {
+	case ReportFrequencyDaily, ReportFrequencyWeekly, ReportFrequencyMonthly:
+	default:
+		return settings, errors.NewValidationError("frequency", "must be daily, weekly or monthly")
+	}
+	if settings.Hour < 0 || settings.Hour > 23 {
+		return settings, errors.NewValidationError("hour", "must be between 0 and 23")
+	}
+
+	destination, err := valueobjects.NewReportDestination(settings.Destination)
+	if err != nil {
+		return settings, err
+	}
+	settings.Destination = destination
+	return settings, nil
+}
+
+// location is the zone the schedule runs in; settings are validated, so
+// it always loads
+func (s *ReportSchedule) location() *time.Location {
+	location, err := s.Layout.Location()
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// periodStart returns the start of the day, week or month t falls in, in
+// the schedule's zone. Weeks start on Monday.
+func (s *ReportSchedule) periodStart(t time.Time) time.Time {
+	local := t.In(s.location())
+	switch s.Frequency {
+	case ReportFrequencyWeekly:
+		local = local.AddDate(0, 0, -(int(local.Weekday())+6)%7)
+	case ReportFrequencyMonthly:
+		local = local.AddDate(0, 0, 1-local.Day())
+	}
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
+
+// nextPeriodStart returns the start of the period after the one starting at
+// start
+func (s *ReportSchedule) nextPeriodStart(start time.Time) time.Time {
+	switch s.Frequency {
+	case ReportFrequencyWeekly:
+		return start.AddDate(0, 0, 7)
+	case ReportFrequencyMonthly:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// NextRun returns when the first run after now is due: Hour on the first
+// day of a period, in the schedule's zone
+func (s *ReportSchedule) NextRun(now time.Time) time.Time {
+	start := s.periodStart(now)
+	for {
+		run := time.Date(start.Year(), start.Month(), start.Day(), s.Hour, 0, 0, 0, start.Location())
+		if run.After(now) {
+			return run.UTC()
+		}
+		start = s.nextPeriodStart(start)
+	}
+}
+
+// Period returns the last full day, week or month before t, in the
+// schedule's zone: from inclusive, to exclusive. A run covers the period
+// before it starts.
+func (s *ReportSchedule) Period(t time.Time) (from, to time.Time) {
+	to = s.periodStart(t)
+	switch s.Frequency {
+	case ReportFrequencyWeekly:
+		from = to.AddDate(0, 0, -7)
+	case ReportFrequencyMonthly:
+		from = to.AddDate(0, -1, 0)
+	default:
+		from = to.AddDate(0, 0, -1)
+	}
+	return from, to
+}
+
+// MarkDelivered records a run delivered at now and schedules the next
+func (s *ReportSchedule) MarkDelivered(now time.Time) {
+	s.Attempts = 0
+	s.LastRunAt = &now
+	s.LastStatus = ReportRunDelivered
+	s.LastError = ""
+	s.NextRunAt = s.NextRun(now)
+	s.UpdatedAt = now
+}
+
+// MarkFailed records an attempt of a run that could not be delivered. It is
+// tried again after 1, then 2 minutes; once the last attempt fails, the run
+// is given up and the next is scheduled.
+func (s *ReportSchedule) MarkFailed(reason string, now time.Time) {
+	s.Attempts++
+	s.LastError = reason
+	s.UpdatedAt = now
+	if s.Attempts < MaxReportRunAttempts {
+		s.NextRunAt = now.Add(time.Minute << uint(s.Attempts-1))
+		return
+	}
+
+	s.Attempts = 0
+	s.LastRunAt = &now
+	s.LastStatus = ReportRunFailed
+	s.NextRunAt = s.NextRun(now)
+}
@@ -0,0 +1,149 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// SettlementEntryType is what a line of a provider's settlement feed reports
+type SettlementEntryType string
+
+const (
+	// SettlementEntrySettlement reports a payment's funds paid out
+	SettlementEntrySettlement SettlementEntryType = "settlement"
+	// SettlementEntryChargeback reports funds of a payment taken back after
+	// the cardholder disputed it
+	SettlementEntryChargeback SettlementEntryType = "chargeback"
+	// SettlementEntryChargebackReversal reports funds of a chargeback
+	// returned after the dispute was decided for the partner
+	SettlementEntryChargebackReversal SettlementEntryType = "chargeback_reversal"
+)
+
+// SettlementDiscrepancy is why a settlement entry does not agree with the
+// stored transaction
+type SettlementDiscrepancy string
+
+const (
+	// DiscrepancyUnknownTransaction: no transaction has the provider's ID
+	DiscrepancyUnknownTransaction SettlementDiscrepancy = "unknown_transaction"
+	// DiscrepancyCurrencyMismatch: the entry is in another currency than
+	// the transaction
+	DiscrepancyCurrencyMismatch SettlementDiscrepancy = "currency_mismatch"
+	// DiscrepancyAmountMismatch: a settlement differs from the transaction
+	// amount, or a chargeback exceeds it
+	DiscrepancyAmountMismatch SettlementDiscrepancy = "amount_mismatch"
+	// DiscrepancyStatusMismatch: funds were settled for a transaction that
+	// did not complete
+	DiscrepancyStatusMismatch SettlementDiscrepancy = "status_mismatch"
+	// DiscrepancyUnknownDispute: a chargeback was reversed without an open
+	// dispute on the transaction
+	DiscrepancyUnknownDispute SettlementDiscrepancy = "unknown_dispute"
+)
+
+// SettlementEntry is a line of a provider's settlement or chargeback feed,
+// kept with the outcome of reconciling it against the stored transactions
+type SettlementEntry struct {
+	// Identity
+	ID       uuid.UUID
+	Provider valueobjects.PaymentProvider
+	// Reference is the provider's ID of the entry, unique per provider, so
+	// an entry delivered twice is reconciled once
+	Reference string
+
+	// What the provider reports
+	Type                  SettlementEntryType
+	ProviderTransactionID string
+	Amount                valueobjects.Money // Gross amount of the entry
+	Fee                   valueobjects.Money // Provider fee, in the same currency
+	Reason                string             // Chargeback reason code, if any
+	OccurredAt            time.Time
+	// Source names the feed the entry came from, such as a Kafka topic or
+	// a file name
+	Source string
+
+	// Reconciliation: the matched transaction, if any, and the discrepancy
+	// found, empty when the entry agrees with it
+	TransactionID     *uuid.UUID
+	PartnerID         *uuid.UUID
+	Discrepancy       SettlementDiscrepancy
+	DiscrepancyDetail string
+
+	// Timestamps
+	ReconciledAt time.Time
+}
+
+// NewSettlementEntry creates an entry received from a provider's feed
+func NewSettlementEntry(
+	provider valueobjects.PaymentProvider,
+	reference string,
+	entryType SettlementEntryType,
+	providerTransactionID string,
+	amount, fee valueobjects.Money,
+	occurredAt time.Time,
+) (*SettlementEntry, error) {
+	if reference == "" {
+		return nil, errors.NewValidationError("reference", "cannot be empty")
+	}
+
+	switch entryType {
+	case SettlementEntrySettlement, SettlementEntryChargeback, SettlementEntryChargebackReversal:
+	default:
+		return nil, errors.NewValidationError("type", "must be settlement, chargeback or chargeback_reversal")
+	}
+
+	if providerTransactionID == "" {
+		return nil, errors.NewValidationError("provider_transaction_id", "cannot be empty")
+	}
+
+	if !amount.Currency.IsValid() {
+		return nil, errors.ErrInvalidCurrency
+	}
+
+	if amount.Amount <= 0 {
+		return nil, errors.ErrInvalidAmount
+	}
+
+	if fee.Currency == "" {
+		fee = valueobjects.Money{Currency: amount.Currency}
+	}
+	if fee.Currency != amount.Currency || fee.Amount < 0 {
+		return nil, errors.NewValidationError("fee", "must be a non-negative amount in the entry's currency")
+	}
+
+	if occurredAt.IsZero() {
+		return nil, errors.NewValidationError("occurred_at", "cannot be empty")
+	}
+
+	return &SettlementEntry{
+		ID:                    uuid.New(),
+		Provider:              provider,
+		Reference:             reference,
+		Type:                  entryType,
+		ProviderTransactionID: providerTransactionID,
+		Amount:                amount,
+		Fee:                   fee,
+		OccurredAt:            occurredAt,
+	}, nil
+}
+
+// Match records the transaction the entry is about
+func (e *SettlementEntry) Match(transaction *Transaction) {
+	e.TransactionID = &transaction.ID
+	e.PartnerID = &transaction.PartnerID
+}
+
+// Flag records a discrepancy found while reconciling the entry
+func (e *SettlementEntry) Flag(discrepancy SettlementDiscrepancy, detail string) {
+	e.Discrepancy = discrepancy
+	e.DiscrepancyDetail = detail
+}
+
+// HasDiscrepancy reports whether the entry disagrees with the stored
+// transactions
+func (e *SettlementEntry) HasDiscrepancy() bool {
+	return e.Discrepancy != ""
+}
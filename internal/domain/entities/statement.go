@@ -0,0 +1,234 @@
+package entities
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// StatementPeriod is the calendar month, in UTC, a statement covers, such
+// as 2024-03
+type StatementPeriod string
+
+// statementPeriodLayout is the format of a StatementPeriod
+const statementPeriodLayout = "2006-01"
+
+// NewStatementPeriod validates and creates a StatementPeriod
+func NewStatementPeriod(period string) (StatementPeriod, error) {
+	start, err := time.Parse(statementPeriodLayout, period)
+	if err != nil || start.Format(statementPeriodLayout) != period {
+		return "", errors.NewValidationError("period", "must be a month such as 2024-03")
+	}
+	return StatementPeriod(period), nil
+}
+
+// StatementPeriodOf returns the month t is in, in UTC
+func StatementPeriodOf(t time.Time) StatementPeriod {
+	return StatementPeriod(t.UTC().Format(statementPeriodLayout))
+}
+
+// Start is the first instant of the month
+func (p StatementPeriod) Start() time.Time {
+	start, _ := time.Parse(statementPeriodLayout, string(p))
+	return start
+}
+
+// End is the first instant of the next month
+func (p StatementPeriod) End() time.Time {
+	return p.Start().AddDate(0, 1, 0)
+}
+
+// Previous is the month before
+func (p StatementPeriod) Previous() StatementPeriod {
+	return StatementPeriodOf(p.Start().AddDate(0, -1, 0))
+}
+
+// String returns the period as YYYY-MM
+func (p StatementPeriod) String() string {
+	return string(p)
+}
+
+// StatementTotal is how many items there were and their amount, in minor
+// units
+type StatementTotal struct {
+	Count  int64
+	Amount int64
+}
+
+// add counts an item of amount
+func (t *StatementTotal) add(amount int64) {
+	t.Count++
+	t.Amount += amount
+}
+
+// StatementTierFee is what the payments of one pricing tier were charged
+type StatementTierFee struct {
+	Tier    string
+	Percent float64
+	Fixed   int64
+
+	// Payments is the tier's payments and their volume; Fee what they were
+	// charged
+	Payments StatementTotal
+	Fee      int64
+}
+
+// StatementCurrency is the activity and fees of one currency in a month.
+// Amounts are in its minor units.
+type StatementCurrency struct {
+	Currency valueobjects.Currency
+
+	Payments StatementTotal
+	Refunds  StatementTotal
+	// Chargebacks are the disputes opened in the month, and
+	// ChargebackReversals those reversed in it, whenever they were opened
+	Chargebacks         StatementTotal
+	ChargebackReversals StatementTotal
+
+	// TierFees are the payment fees, one per tier that had payments, in
+	// the order of the pricing; ChargebackFees the fees of Chargebacks
+	TierFees       []StatementTierFee
+	ChargebackFees StatementTotal
+	TotalFees      int64
+
+	// Net is what the partner is owed for the month: payments less
+	// refunds, chargebacks and fees, plus reversals
+	Net int64
+}
+
+// Statement is the activity of a partner's live payments in a month and
+// what the partner was charged for them, issued once the month is over
+type Statement struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+	Period    StatementPeriod
+
+	// Pricing is what the statement charged, as it was when issued
+	Pricing        valueobjects.Pricing
+	RoundingPolicy valueobjects.RoundingPolicy
+
+	// Currencies holds the month's activity by currency, in code order
+	Currencies []StatementCurrency
+
+	// DocumentKey is where the statement's document is kept in the object
+	// store
+	DocumentKey string
+
+	CreatedAt time.Time
+
+	// payments counts the payments added so far, across currencies, to
+	// find each one's tier
+	payments int
+}
+
+// NewStatement starts an empty statement of partner's period, charging
+// pricing with the partner's rounding policy
+func NewStatement(partner *Partner, period StatementPeriod, pricing valueobjects.Pricing) (*Statement, error) {
+	if partner == nil || partner.ID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+	if _, err := NewStatementPeriod(string(period)); err != nil {
+		return nil, err
+	}
+	if len(pricing.Tiers) == 0 {
+		return nil, errors.NewValidationError("pricing", "must have a tier")
+	}
+
+	return &Statement{
+		ID:             uuid.New(),
+		PartnerID:      partner.ID,
+		Period:         period,
+		Pricing:        pricing.Clone(),
+		RoundingPolicy: partner.RoundingPolicy,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// AddPayment counts a paid transaction and charges it the fee of its tier.
+// Payments must be added in the order they were made, since the month's
+// first payments fall in the first tier.
+func (s *Statement) AddPayment(txn *Transaction) error {
+	s.payments++
+	tier := s.Pricing.TierOf(s.payments)
+	fee, err := tier.Fee().Calculate(txn.Amount, s.RoundingPolicy)
+	if err != nil {
+		return err
+	}
+
+	line := s.currency(txn.Amount.Currency)
+	line.Payments.add(txn.Amount.Amount)
+	for i := range line.TierFees {
+		if line.TierFees[i].Tier == tier.Name {
+			line.TierFees[i].Payments.add(txn.Amount.Amount)
+			line.TierFees[i].Fee += fee.Amount
+			return nil
+		}
+	}
+	tierFee := StatementTierFee{Tier: tier.Name, Percent: tier.Percent, Fixed: tier.Fixed, Fee: fee.Amount}
+	tierFee.Payments.add(txn.Amount.Amount)
+	line.TierFees = append(line.TierFees, tierFee)
+	return nil
+}
+
+// AddRefund counts a completed refund
+func (s *Statement) AddRefund(refund *Refund) {
+	s.currency(refund.Amount.Currency).Refunds.add(refund.Amount.Amount)
+}
+
+// AddChargeback counts a dispute opened in the month and charges its fee
+func (s *Statement) AddChargeback(dispute *Dispute) {
+	line := s.currency(dispute.Amount.Currency)
+	line.Chargebacks.add(dispute.Amount.Amount)
+	line.ChargebackFees.add(s.Pricing.ChargebackFee)
+}
+
+// AddChargebackReversal counts a dispute reversed in the month. The fee
+// of the chargeback is kept.
+func (s *Statement) AddChargebackReversal(dispute *Dispute) {
+	s.currency(dispute.Amount.Currency).ChargebackReversals.add(dispute.Amount.Amount)
+}
+
+// Close totals the statement once everything is added
+func (s *Statement) Close() {
+	for i := range s.Currencies {
+		line := &s.Currencies[i]
+		sort.SliceStable(line.TierFees, func(a, b int) bool {
+			return s.tierIndex(line.TierFees[a].Tier) < s.tierIndex(line.TierFees[b].Tier)
+		})
+
+		line.TotalFees = line.ChargebackFees.Amount
+		for _, tierFee := range line.TierFees {
+			line.TotalFees += tierFee.Fee
+		}
+		line.Net = line.Payments.Amount - line.Refunds.Amount - line.Chargebacks.Amount +
+			line.ChargebackReversals.Amount - line.TotalFees
+	}
+}
+
+// currency returns the line of currency, adding it in code order
+func (s *Statement) currency(currency valueobjects.Currency) *StatementCurrency {
+	i := sort.Search(len(s.Currencies), func(i int) bool {
+		return s.Currencies[i].Currency >= currency
+	})
+	if i == len(s.Currencies) || s.Currencies[i].Currency != currency {
+		s.Currencies = append(s.Currencies, StatementCurrency{})
+		copy(s.Currencies[i+1:], s.Currencies[i:])
+		s.Currencies[i] = StatementCurrency{Currency: currency}
+	}
+	return &s.Currencies[i]
+}
+
+// tierIndex is the position of the named tier in the pricing
+func (s *Statement) tierIndex(name string) int {
+	for i, tier := range s.Pricing.Tiers {
+		if tier.Name == name {
+			return i
+		}
+	}
+	return len(s.Pricing.Tiers)
+}
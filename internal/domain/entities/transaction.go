@@ -1,299 +1,338 @@
-package entities
 // Package entities contains the core domain entities (Aggregates)
 // These represent the business objects with identity, lifecycle, and business logic
 package entities
 
 import (
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return t.DeletedAt != nilfunc (t *Transaction) IsDeleted() bool {// IsDeleted checks if transaction is soft-deleted}	t.UpdatedAt = now	t.DeletedAt = &now	now := time.Now()func (t *Transaction) SoftDelete() {// SoftDelete marks transaction as deleted (soft delete)}	return t.Status == StatusProcessingfunc (t *Transaction) IsProcessing() bool {// IsProcessing checks if transaction is being processed}	return t.Status == StatusPendingfunc (t *Transaction) IsPending() bool {// IsPending checks if transaction is pending}	return t.Status == StatusFailed || t.Status == StatusCancelledfunc (t *Transaction) IsFailed() bool {// IsFailed checks if transaction has failed}		t.Status == StatusPartiallyRefunded		t.Status == StatusRefunded ||	return t.Status == StatusCompleted ||func (t *Transaction) IsCompleted() bool {// IsCompleted checks if transaction is in a final completed state}	return val, exists	val, exists := t.Metadata[key]	}		return nil, false	if t.Metadata == nil {func (t *Transaction) GetMetadata(key string) (interface{}, bool) {// GetMetadata retrieves metadata value}	t.UpdatedAt = time.Now()	t.Metadata[key] = value	}		t.Metadata = make(map[string]interface{})	if t.Metadata == nil {func (t *Transaction) SetMetadata(key string, value interface{}) {// SetMetadata sets metadata with validation}	return nil		t.UpdatedAt = time.Now()	t.CustomerPhone = phone	t.CustomerName = name	t.CustomerEmail = email		}		return errors.NewValidationError("customer_email", "cannot be empty")	if email == "" {func (t *Transaction) SetCustomerInfo(email, name, phone string) error {// SetCustomerInfo sets customer information with validation}	return t.CreatedAt.After(ninetyDaysAgo)	ninetyDaysAgo := time.Now().AddDate(0, 0, -90)	// Check 90-day window		}		return false	if t.Status != StatusCompleted && t.Status != StatusPartiallyRefunded {func (t *Transaction) IsRefundable() bool {// Business Rule: Can refund within 90 days// IsRefundable checks if transaction can be refunded}	return nil	t.UpdatedAt = time.Now()		}		t.Status = StatusRefunded	} else {		t.Status = StatusPartiallyRefunded	if partial {		}		)			"can only refund completed transactions",			"invalid_refund",		return errors.NewBusinessRuleError(	if t.Status != StatusCompleted {func (t *Transaction) MarkAsRefunded(partial bool) error {// MarkAsRefunded marks transaction as refunded}	return nil	t.UpdatedAt = time.Now()	t.Status = StatusPending // Reset to pending for retry	t.RetryCount++		}		return errors.NewBusinessRuleError("max_retries_exceeded", "maximum retry attempts reached")	if !t.CanRetry() {func (t *Transaction) IncrementRetryCount() error {// IncrementRetryCount increments the retry counter}	return t.Status == StatusFailed && t.RetryCount < 3func (t *Transaction) CanRetry() bool {// Business Rule: Maximum 3 retry attempts// CanRetry checks if transaction can be retried}	return nil		t.UpdatedAt = now	t.FailedAt = &now	t.ErrorMessage = errorMessage	t.ErrorCode = errorCode	t.Status = StatusFailed	now := time.Now()		}		)			"can only fail pending or processing transactions",			"invalid_state_transition",		return errors.NewBusinessRuleError(	if t.Status != StatusProcessing && t.Status != StatusPending {func (t *Transaction) MarkAsFailed(errorCode, errorMessage string) error {// MarkAsFailed marks transaction as failed}	return nil		t.ErrorMessage = ""	t.ErrorCode = ""	t.UpdatedAt = now	t.ProcessedAt = &now	t.ProviderTransactionID = providerTransactionID	t.Status = StatusCompleted	now := time.Now()		}		return errors.NewValidationError("provider_transaction_id", "cannot be empty")	if providerTransactionID == "" {		}		)			"can only complete processing transactions",			"invalid_state_transition",		return errors.NewBusinessRuleError(	if t.Status != StatusProcessing {func (t *Transaction) MarkAsCompleted(providerTransactionID string) error {// MarkAsCompleted marks transaction as successfully completed}	return nil	t.UpdatedAt = time.Now()	t.Status = StatusProcessing		}		)			"can only process pending transactions",			"invalid_state_transition",		return errors.NewBusinessRuleError(	if t.Status != StatusPending {func (t *Transaction) MarkAsProcessing() error {// Domain logic: ensures valid state transitions// MarkAsProcessing transitions transaction to processing state}	}, nil		Metadata:       make(map[string]interface{}),		UpdatedAt:      now,		CreatedAt:      now,		RetryCount:     0,		RequestID:      uuid.New(),		CustomerEmail:  customerEmail,		Status:         StatusPending,		Provider:       provider,		PaymentMethod:  paymentMethod,		Amount:         amount,		IdempotencyKey: idempotencyKey,		PartnerID:      partnerID,		ID:             uuid.New(),	return &Transaction{		now := time.Now()		}		return nil, errors.ErrInvalidPaymentMethod	if !paymentMethod.IsValid() {		}		return nil, errors.ErrInvalidCurrency	if !amount.Currency.IsValid() {	// Validate value objects		}		return nil, errors.NewValidationError("customer_email", "cannot be empty")	if customerEmail == "" {		}		return nil, errors.NewValidationError("idempotency_key", "cannot be empty")	if idempotencyKey == "" {		}		return nil, errors.NewValidationError("partner_id", "cannot be empty")	if partnerID == uuid.Nil {	// Validate required fields	) (*Transaction, error) {	customerEmail string,	provider valueobjects.PaymentProvider,	paymentMethod valueobjects.PaymentMethod,	amount valueobjects.Money,	idempotencyKey string,	partnerID uuid.UUID,func NewTransaction(// This is a Factory Method ensuring all invariants are met// NewTransaction creates a new transaction with validation}	DeletedAt       *time.Time	FailedAt        *time.Time	ProcessedAt     *time.Time	UpdatedAt       time.Time	CreatedAt       time.Time	// Timestamps		RetryCount      int	ErrorMessage    string	ErrorCode       string	// Error handling		RequestID       uuid.UUID	UserAgent       string	IPAddress       string	// Tracking		Metadata        map[string]interface{} // Partner-specific data	Description     string	// Additional data		CustomerPhone   string	CustomerName    string	CustomerEmail   string	// Customer information		ProviderCustomerID    string	ProviderTransactionID string	// Provider details		Status          TransactionStatus	// State		Provider        valueobjects.PaymentProvider	PaymentMethod   valueobjects.PaymentMethod	Amount          valueobjects.Money	// Value Objects (immutable, validated)		IdempotencyKey  string // Prevents duplicate transactions	PartnerID       uuid.UUID	ID              uuid.UUID	// Identitytype Transaction struct {// It encapsulates all business logic related to payment processing// Transaction is the core aggregate root for payment transactions)	StatusPartiallyRefunded TransactionStatus = "partially_refunded"	StatusRefunded          TransactionStatus = "refunded"	StatusCancelled         TransactionStatus = "cancelled"	StatusFailed            TransactionStatus = "failed"	StatusCompleted         TransactionStatus = "completed"	StatusProcessing        TransactionStatus = "processing"	StatusPending           TransactionStatus = "pending"const (type TransactionStatus string// TransactionStatus represents the state of a transaction)	"Pay2Go/internal/domain/valueobjects"	"Pay2Go/internal/domain/errors"	"github.com/google/uuid"	"time"
\ No newline at end of file
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// TransactionStatus represents the state of a transaction
+type TransactionStatus string
+
+const (
+	StatusPending           TransactionStatus = "pending"
+	StatusProcessing        TransactionStatus = "processing"
+	StatusCompleted         TransactionStatus = "completed"
+	StatusFailed            TransactionStatus = "failed"
+	StatusCancelled         TransactionStatus = "cancelled"
+	StatusRefunded          TransactionStatus = "refunded"
+	StatusPartiallyRefunded TransactionStatus = "partially_refunded"
+)
+
+// Transaction is the core aggregate root for payment transactions
+// It encapsulates all business logic related to payment processing
+type Transaction struct {
+	// Identity
+	ID             uuid.UUID
+	PartnerID      uuid.UUID
+	IdempotencyKey string // Prevents duplicate transactions
+
+	// Value Objects (immutable, validated)
+	Amount        valueobjects.Money
+	PaymentMethod valueobjects.PaymentMethod
+	Provider      valueobjects.PaymentProvider
+
+	// PaymentMethodDetails describes the card, bank account or wallet used (nil if unknown)
+	PaymentMethodDetails *valueobjects.PaymentMethodDetails
+
+	// State
+	Status TransactionStatus
+
+	// Version counts saved changes; an update only succeeds against the
+	// version it was loaded at (optimistic locking)
+	Version int
+
+	// Livemode is false for sandbox transactions created with a test API key
+	Livemode bool
+
+	// Refund accounting: pending and completed refunds, kept in sync by the refund repository
+	RefundedAmount valueobjects.Money
+
+	// Provider details
+	ProviderTransactionID string
+	ProviderCustomerID    string
+
+	// ProviderCredentialID is the partner's own provider account the payment was
+	// processed on (nil for the platform account); refunds go through the same account
+	ProviderCredentialID *uuid.UUID
+
+	// Customer information
+	CustomerEmail  string
+	CustomerName   string
+	CustomerPhone  string
+	BillingCountry valueobjects.Country // Empty if not given
+
+	// Additional data
+	Description string
+	Metadata    map[string]interface{} // Partner-specific data
+
+	// Tracking
+	IPAddress string
+	UserAgent string
+	RequestID uuid.UUID
+
+	// Error handling
+	ErrorCode    string
+	ErrorMessage string
+	RetryCount   int
+
+	// Timestamps
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ProcessedAt *time.Time
+	FailedAt    *time.Time
+	DeletedAt   *time.Time
+}
+
+// NewTransaction creates a new transaction with validation
+// This is a Factory Method ensuring all invariants are met
+func NewTransaction(
+	partnerID uuid.UUID,
+	idempotencyKey string,
+	amount valueobjects.Money,
+	paymentMethod valueobjects.PaymentMethod,
+	provider valueobjects.PaymentProvider,
+	customerEmail string,
+) (*Transaction, error) {
+	// Validate required fields
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	if idempotencyKey == "" {
+		return nil, errors.NewValidationError("idempotency_key", "cannot be empty")
+	}
+
+	if customerEmail == "" {
+		return nil, errors.NewValidationError("customer_email", "cannot be empty")
+	}
+
+	// Validate value objects
+	if !amount.Currency.IsValid() {
+		return nil, errors.ErrInvalidCurrency
+	}
+
+	if amount.Amount <= 0 {
+		return nil, errors.ErrInvalidAmount
+	}
+
+	if !paymentMethod.IsValid() {
+		return nil, errors.ErrInvalidPaymentMethod
+	}
+
+	now := time.Now()
+
+	return &Transaction{
+		ID:             uuid.New(),
+		PartnerID:      partnerID,
+		IdempotencyKey: idempotencyKey,
+		Amount:         amount,
+		PaymentMethod:  paymentMethod,
+		Provider:       provider,
+		Status:         StatusPending,
+		Version:        1,
+		Livemode:       true,
+		CustomerEmail:  customerEmail,
+		RequestID:      uuid.New(),
+		RetryCount:     0,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Metadata:       make(map[string]interface{}),
+	}, nil
+}
+
+// MarkAsProcessing transitions transaction to processing state
+// Domain logic: ensures valid state transitions
+func (t *Transaction) MarkAsProcessing() error {
+	if t.Status != StatusPending {
+		return errors.NewBusinessRuleError(
+			"invalid_state_transition",
+			"can only process pending transactions",
+		)
+	}
+
+	t.Status = StatusProcessing
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkAsCompleted marks transaction as successfully completed
+func (t *Transaction) MarkAsCompleted(providerTransactionID string) error {
+	if t.Status != StatusProcessing {
+		return errors.NewBusinessRuleError(
+			"invalid_state_transition",
+			"can only complete processing transactions",
+		)
+	}
+
+	if providerTransactionID == "" {
+		return errors.NewValidationError("provider_transaction_id", "cannot be empty")
+	}
+
+	now := time.Now()
+	t.Status = StatusCompleted
+	t.ProviderTransactionID = providerTransactionID
+	t.ProcessedAt = &now
+	t.UpdatedAt = now
+	t.ErrorCode = ""
+	t.ErrorMessage = ""
+
+	return nil
+}
+
+// MarkAsFailed marks transaction as failed
+func (t *Transaction) MarkAsFailed(errorCode, errorMessage string) error {
+	if t.Status != StatusProcessing && t.Status != StatusPending {
+		return errors.NewBusinessRuleError(
+			"invalid_state_transition",
+			"can only fail pending or processing transactions",
+		)
+	}
+
+	now := time.Now()
+	t.Status = StatusFailed
+	t.ErrorCode = errorCode
+	t.ErrorMessage = errorMessage
+	t.FailedAt = &now
+	t.UpdatedAt = now
+
+	return nil
+}
+
+// MaxRetryAttempts is how many times a failed payment may be retried
+const MaxRetryAttempts = 3
+
+// CanRetry checks if transaction can be retried
+// Business Rule: Maximum MaxRetryAttempts retry attempts
+func (t *Transaction) CanRetry() bool {
+	return t.Status == StatusFailed && t.RetryCount < MaxRetryAttempts
+}
+
+// IncrementRetryCount increments the retry counter
+func (t *Transaction) IncrementRetryCount() error {
+	if t.Status != StatusFailed {
+		return errors.ErrInvalidStatus
+	}
+	if !t.CanRetry() {
+		return errors.NewBusinessRuleError("max_retries_exceeded", "maximum retry attempts reached")
+	}
+
+	t.RetryCount++
+	t.Status = StatusPending // Reset to pending for retry
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkAsRefunded marks transaction as refunded
+func (t *Transaction) MarkAsRefunded(partial bool) error {
+	if t.Status != StatusCompleted && t.Status != StatusPartiallyRefunded {
+		return errors.NewBusinessRuleError(
+			"invalid_refund",
+			"can only refund completed transactions",
+		)
+	}
+
+	if partial {
+		t.Status = StatusPartiallyRefunded
+	} else {
+		t.Status = StatusRefunded
+	}
+
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsRefundable checks if transaction can be refunded
+// The refund window is partner policy and is enforced by the refund use case
+func (t *Transaction) IsRefundable() bool {
+	return t.Status == StatusCompleted || t.Status == StatusPartiallyRefunded
+}
+
+// RefundableAmount returns how much of the transaction, in minor units, has not
+// been refunded or reserved
+func (t *Transaction) RefundableAmount() int64 {
+	return t.Amount.Amount - t.RefundedAmount.Amount
+}
+
+// SetCustomerInfo sets customer information with validation
+func (t *Transaction) SetCustomerInfo(email, name, phone string) error {
+	if email == "" {
+		return errors.NewValidationError("customer_email", "cannot be empty")
+	}
+
+	t.CustomerEmail = email
+	t.CustomerName = name
+	t.CustomerPhone = phone
+	t.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetPaymentMethodDetails records how the payer paid; details must match the payment method
+func (t *Transaction) SetPaymentMethodDetails(details valueobjects.PaymentMethodDetails) error {
+	if err := details.ValidateFor(t.PaymentMethod); err != nil {
+		return err
+	}
+
+	t.PaymentMethodDetails = &details
+	t.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// SetMetadata sets metadata with validation
+func (t *Transaction) SetMetadata(key string, value interface{}) {
+	if t.Metadata == nil {
+		t.Metadata = make(map[string]interface{})
+	}
+	t.Metadata[key] = value
+	t.UpdatedAt = time.Now()
+}
+
+// GetMetadata retrieves metadata value
+func (t *Transaction) GetMetadata(key string) (interface{}, bool) {
+	if t.Metadata == nil {
+		return nil, false
+	}
+	val, exists := t.Metadata[key]
+	return val, exists
+}
+
+// IsCompleted checks if transaction is in a final completed state
+func (t *Transaction) IsCompleted() bool {
+	return t.Status == StatusCompleted ||
+		t.Status == StatusRefunded ||
+		t.Status == StatusPartiallyRefunded
+}
+
+// IsFailed checks if transaction has failed
+func (t *Transaction) IsFailed() bool {
+	return t.Status == StatusFailed || t.Status == StatusCancelled
+}
+
+// IsPending checks if transaction is pending
+func (t *Transaction) IsPending() bool {
+	return t.Status == StatusPending
+}
+
+// IsProcessing checks if transaction is being processed
+func (t *Transaction) IsProcessing() bool {
+	return t.Status == StatusProcessing
+}
+
+// SoftDelete marks transaction as deleted (soft delete)
+func (t *Transaction) SoftDelete() {
+	now := time.Now()
+	t.DeletedAt = &now
+	t.UpdatedAt = now
+}
+
+// IsDeleted checks if transaction is soft-deleted
+func (t *Transaction) IsDeleted() bool {
+	return t.DeletedAt != nil
+}
@@ -0,0 +1,214 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+const (
+	// minPasswordLength is the shortest password a team member may set
+	minPasswordLength = 12
+
+	// sessionTokenPrefix distinguishes session tokens from API keys
+	sessionTokenPrefix = "ses_"
+)
+
+// User is a member of a partner's team who signs in with email and password
+type User struct {
+	// Identity
+	ID        uuid.UUID
+	PartnerID uuid.UUID
+	Email     string
+	Name      string
+
+	// Authorization
+	Role valueobjects.UserRole
+
+	// Authentication
+	PasswordHash string // Hashed with bcrypt
+
+	// Timestamps
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	LastLoginAt *time.Time
+	DeletedAt   *time.Time
+}
+
+// NewUser creates a new team member with a hashed password
+func NewUser(partnerID uuid.UUID, email, name string, role valueobjects.UserRole, password string) (*User, error) {
+	// Validate required fields
+	if partnerID == uuid.Nil {
+		return nil, errors.NewValidationError("partner_id", "cannot be empty")
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, errors.NewValidationError("email", "cannot be empty")
+	}
+
+	if name == "" {
+		return nil, errors.NewValidationError("name", "cannot be empty")
+	}
+
+	if !role.IsValid() {
+		return nil, errors.NewValidationError("role", "must be one of owner, developer, finance, read_only")
+	}
+
+	now := time.Now()
+	user := &User{
+		ID:        uuid.New(),
+		PartnerID: partnerID,
+		Email:     email,
+		Name:      name,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := user.SetPassword(password); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetPassword validates and hashes a new password
+func (u *User) SetPassword(password string) error {
+	if len(password) < minPasswordLength {
+		return errors.NewValidationError("password", "must be at least 12 characters")
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	u.PasswordHash = string(hashed)
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// CheckPassword verifies a password against the stored hash
+func (u *User) CheckPassword(password string) error {
+	if u.IsDeleted() {
+		return errors.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return errors.ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// SetRole changes the member's role
+func (u *User) SetRole(role valueobjects.UserRole) error {
+	if !role.IsValid() {
+		return errors.NewValidationError("role", "must be one of owner, developer, finance, read_only")
+	}
+
+	u.Role = role
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// HasRole checks if the member holds one of the given roles
+func (u *User) HasRole(roles ...valueobjects.UserRole) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RecordLogin stamps a successful sign-in
+func (u *User) RecordLogin() {
+	now := time.Now()
+	u.LastLoginAt = &now
+}
+
+// SoftDelete removes the member from the team
+func (u *User) SoftDelete() {
+	now := time.Now()
+	u.DeletedAt = &now
+	u.UpdatedAt = now
+}
+
+// IsDeleted checks if the member was removed
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// UserSession is a signed-in team member's bearer token
+type UserSession struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string // SHA-256 of the token; tokens are random so a fast hash suffices
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// NewUserSession starts a session and returns it with the plaintext token,
+// which is only available at creation time
+func NewUserSession(userID uuid.UUID, ttl time.Duration) (*UserSession, string, error) {
+	if userID == uuid.Nil {
+		return nil, "", errors.NewValidationError("user_id", "cannot be empty")
+	}
+
+	random, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	token := sessionTokenPrefix + random
+
+	now := time.Now()
+
+	return &UserSession{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: HashSessionToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token, nil
+}
+
+// IsSessionToken reports whether a bearer token looks like a session token
+func IsSessionToken(token string) bool {
+	return strings.HasPrefix(token, sessionTokenPrefix)
+}
+
+// HashSessionToken returns the lookup hash of a session token
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Validate checks the session can still be used
+func (s *UserSession) Validate() error {
+	if s.RevokedAt != nil || time.Now().After(s.ExpiresAt) {
+		return errors.ErrSessionExpired
+	}
+
+	return nil
+}
+
+// Revoke ends the session
+func (s *UserSession) Revoke() {
+	if s.RevokedAt == nil {
+		now := time.Now()
+		s.RevokedAt = &now
+	}
+}
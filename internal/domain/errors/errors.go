@@ -1,4 +1,3 @@
-package errors
 // Package errors defines domain-specific errors
 // These are business rule violations, not technical errors
 package errors
@@ -8,72 +7,185 @@ import (
 	"fmt"
 )
 
+// Common domain errors
+var (
+	// Transaction errors
+	ErrInvalidAmount        = errors.New("invalid transaction amount")
+	ErrInvalidCurrency      = errors.New("invalid currency code")
+	ErrInvalidCountry       = errors.New("invalid country code")
+	ErrInvalidLocale        = errors.New("invalid locale")
+	ErrInvalidPaymentMethod = errors.New("invalid payment method")
+	ErrCardBINNotFound      = errors.New("card BIN not found")
+	ErrInvalidStatus        = errors.New("invalid transaction status")
+	ErrTransactionNotFound  = errors.New("transaction not found")
+	ErrDuplicateTransaction = errors.New("duplicate transaction detected")
+	ErrRetryNotAllowed      = errors.New("transaction is not failed or has no retry attempts left")
+	ErrReceiptNotAvailable  = errors.New("receipts are only issued for paid transactions")
+
+	// Customer notification errors
+	ErrInvalidPhoneNumber      = errors.New("invalid phone number")
+	ErrSMSRateLimited          = errors.New("too many text messages, try again later")
+	ErrInvalidVerificationCode = errors.New("verification code is wrong or has expired")
+	ErrSMSUnavailable          = errors.New("text messages are not configured")
+
+	// Partner errors
+	ErrPartnerNotFound    = errors.New("partner not found")
+	ErrPartnerExists      = errors.New("another partner already uses this email")
+	ErrPartnerInactive    = errors.New("partner is inactive")
+	ErrFeatureDisabled    = errors.New("feature is not enabled for this partner")
+	ErrCurrencyNotAllowed = errors.New("currency is not enabled for this partner")
+	ErrInvalidAPIKey      = errors.New("invalid API key")
+	ErrAPIKeyNotFound     = errors.New("API key not found")
+	ErrAPIKeyRevoked      = errors.New("API key has been revoked")
+	ErrAPIKeyIDTaken      = errors.New("API key ID is taken by a different or revoked key")
+	ErrMissingScope       = errors.New("API key is missing the required scope")
+	ErrAuthMethod         = errors.New("API key does not accept this authentication method")
+	ErrInvalidSignature   = errors.New("invalid request signature")
+	ErrSignatureExpired   = errors.New("request timestamp is missing or outside the allowed window")
+	ErrSignatureReplayed  = errors.New("request signature has already been used")
+	ErrTooManyFailures    = errors.New("too many failed authentication attempts, try again later")
+
+	// Provider credential errors
+	ErrProviderCredentialNotFound = errors.New("provider credential not found")
+	ErrProviderCredentialChanged  = errors.New("provider account used for the payment is no longer configured")
+
+	// Currency conversion errors
+	ErrExchangeRateUnavailable = errors.New("exchange rate is not available for this currency pair")
+	ErrProviderNotConfigured   = errors.New("notifications of this payment provider are not configured")
+
+	// Team user errors
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserAlreadyExists  = errors.New("a user with this email already exists")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrSessionExpired     = errors.New("session has expired")
+	ErrLastOwner          = errors.New("partner must keep at least one owner")
+
+	// Back-office admin errors
+	ErrAdminNotFound           = errors.New("admin not found")
+	ErrAdminAlreadyExists      = errors.New("an admin with this email already exists")
+	ErrInvalidAdminCredentials = errors.New("invalid email, password or verification code")
+
+	// Refund errors
+	ErrRefundNotFound        = errors.New("refund not found")
+	ErrRefundAmountExceeded  = errors.New("refund amount exceeds transaction amount")
+	ErrRefundNotAllowed      = errors.New("refund not allowed for this transaction")
+	ErrRefundWindowExpired   = errors.New("refund window has expired")
+	ErrBulkRefundJobNotFound = errors.New("bulk refund job not found")
+
+	// Export errors
+	ErrExportNotFound    = errors.New("export not found")
+	ErrExportNotReady    = errors.New("export has not completed")
+	ErrExportLinkExpired = errors.New("export download link has expired")
+
+	// Statement errors
+	ErrStatementNotFound = errors.New("statement not found")
+	ErrStatementExists   = errors.New("partner already has a statement for the period")
+
+	// Report schedule errors
+	ErrReportScheduleNotFound = errors.New("report schedule not found")
+
+	// Business rule errors
+	ErrAmountBelowMinimum    = errors.New("amount below minimum allowed")
+	ErrAmountAboveMaximum    = errors.New("amount above maximum allowed")
+	ErrUnauthorizedOperation = errors.New("unauthorized operation")
+
+	// Concurrency errors
+	ErrVersionConflict    = errors.New("resource was modified by another request")
+	ErrResourceLocked     = errors.New("resource is being changed by another request")
+	ErrPreconditionFailed = errors.New("resource is not at the version the request expects")
+)
 
+// Codes of the domain errors built by NewValidationError and
+// NewBusinessRuleError
+const (
+	CodeValidation   = "VALIDATION_ERROR"
+	CodeBusinessRule = "BUSINESS_RULE_VIOLATION"
+)
 
+// DomainError represents a domain-specific error with context
+type DomainError struct {
+	Code    string
+	Message string
+	// Param is the input field a validation error is about
+	Param string
+	Err   error
+}
+
+func (e *DomainError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("[%s] %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// NewDomainError creates a new domain error
+func NewDomainError(code, message string, err error) *DomainError {
+	return &DomainError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// Validation errors
+func NewValidationError(field, message string) *DomainError {
+	return &DomainError{
+		Code:    CodeValidation,
+		Message: fmt.Sprintf("%s: %s", field, message),
+		Param:   field,
+	}
+}
+
+// Business rule errors
+func NewBusinessRuleError(rule, message string) *DomainError {
+	return &DomainError{
+		Code:    CodeBusinessRule,
+		Message: fmt.Sprintf("%s: %s", rule, message),
+	}
+}
+
+// Amount limit scopes, naming where the limit that was exceeded is configured
+const (
+	AmountLimitScopePartner  = "partner"
+	AmountLimitScopePlatform = "platform"
+)
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	}		Message: fmt.Sprintf("%s: %s", rule, message),		Code:    "BUSINESS_RULE_VIOLATION",	return &DomainError{func NewBusinessRuleError(rule, message string) *DomainError {// Business rule errors}	}		Message: fmt.Sprintf("%s: %s", field, message),		Code:    "VALIDATION_ERROR",	return &DomainError{func NewValidationError(field, message string) *DomainError {// Validation errors}	}		Err:     err,		Message: message,		Code:    code,	return &DomainError{func NewDomainError(code, message string, err error) *DomainError {// NewDomainError creates a new domain error}	return e.Errfunc (e *DomainError) Unwrap() error {}	return fmt.Sprintf("[%s] %s", e.Code, e.Message)	}		return fmt.Sprintf("[%s] %s: %v", e.Code, e.Message, e.Err)	if e.Err != nil {func (e *DomainError) Error() string {}	Err     error	Message string	Code    stringtype DomainError struct {// DomainError represents a domain-specific error with context)	ErrUnauthorizedOperation  = errors.New("unauthorized operation")	ErrAmountAboveMaximum     = errors.New("amount above maximum allowed")	ErrAmountBelowMinimum     = errors.New("amount below minimum allowed")	// Business rule errors		ErrRefundWindowExpired    = errors.New("refund window has expired")	ErrRefundNotAllowed       = errors.New("refund not allowed for this transaction")	ErrRefundAmountExceeded   = errors.New("refund amount exceeds transaction amount")	// Refund errors		ErrInvalidAPIKey          = errors.New("invalid API key")	ErrPartnerInactive        = errors.New("partner is inactive")	ErrPartnerNotFound        = errors.New("partner not found")	// Partner errors		ErrDuplicateTransaction   = errors.New("duplicate transaction detected")	ErrTransactionNotFound    = errors.New("transaction not found")	ErrInvalidStatus          = errors.New("invalid transaction status")	ErrInvalidPaymentMethod   = errors.New("invalid payment method")	ErrInvalidCurrency        = errors.New("invalid currency code")	ErrInvalidAmount          = errors.New("invalid transaction amount")	// Transaction errorsvar (// Common domain errors
\ No newline at end of file
+// AmountLimitError reports an amount above the maximum configured for its
+// currency. It matches ErrAmountAboveMaximum with errors.Is.
+type AmountLimitError struct {
+	Currency string
+	Amount   string // Decimal amount in major units, e.g. "1500.00"
+	Limit    string // Decimal limit in major units
+	Scope    string // AmountLimitScopePartner or AmountLimitScopePlatform
+}
+
+func (e *AmountLimitError) Error() string {
+	return fmt.Sprintf("%v: %s %s exceeds the %s limit of %s %s",
+		ErrAmountAboveMaximum, e.Currency, e.Amount, e.Scope, e.Currency, e.Limit)
+}
+
+func (e *AmountLimitError) Is(target error) bool {
+	return target == ErrAmountAboveMaximum
+}
+
+// VersionConflictError reports an update made against a stale version of a
+// row: another request saved a change after this one loaded it. Reload and
+// retry. It matches ErrVersionConflict with errors.Is.
+type VersionConflictError struct {
+	Resource string // "transaction" or "refund"
+	ID       string
+	Version  int // Version the update was made against
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%v: %s %s is no longer at version %d", ErrVersionConflict, e.Resource, e.ID, e.Version)
+}
+
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
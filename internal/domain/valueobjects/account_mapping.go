@@ -0,0 +1,101 @@
+package valueobjects
+
+import (
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// Date orders of Xero bank statements, which Xero asks for on import
+const (
+	AccountingDateDayFirst   = "dd/mm/yyyy"
+	AccountingDateMonthFirst = "mm/dd/yyyy"
+)
+
+// AccountMapping names the accounts of a partner's accounting system that
+// accounting exports post to. QuickBooks takes account names and posts
+// both sides of each entry; Xero imports into the bank account chosen on
+// import, so the clearing account is QuickBooks only, and takes account
+// codes for the other side.
+type AccountMapping struct {
+	// ClearingAccount is the bank account Pay2Go's payouts come from
+	ClearingAccount string `json:"clearing_account"`
+	// SalesAccount is the income account of payments
+	SalesAccount string `json:"sales_account"`
+	// RefundsAccount is the account refunds are charged to
+	RefundsAccount string `json:"refunds_account"`
+	// FeesAccount is the expense account of Pay2Go's fees
+	FeesAccount string `json:"fees_account"`
+	// DateFormat is the order of the dates of Xero bank statements
+	DateFormat string `json:"date_format"`
+}
+
+// DefaultAccountMapping is the mapping of partners that have not set one
+func DefaultAccountMapping() AccountMapping {
+	return AccountMapping{
+		ClearingAccount: "Pay2Go Clearing",
+		SalesAccount:    "Sales",
+		RefundsAccount:  "Refunds",
+		FeesAccount:     "Payment Processing Fees",
+		DateFormat:      AccountingDateDayFirst,
+	}
+}
+
+// NewAccountMapping validates and creates an AccountMapping; empty fields
+// take the defaults
+func NewAccountMapping(mapping AccountMapping) (AccountMapping, error) {
+	defaults := DefaultAccountMapping()
+	accounts := []struct {
+		field    string
+		value    *string
+		fallback string
+	}{
+		{"clearing_account", &mapping.ClearingAccount, defaults.ClearingAccount},
+		{"sales_account", &mapping.SalesAccount, defaults.SalesAccount},
+		{"refunds_account", &mapping.RefundsAccount, defaults.RefundsAccount},
+		{"fees_account", &mapping.FeesAccount, defaults.FeesAccount},
+	}
+	for _, account := range accounts {
+		*account.value = strings.TrimSpace(*account.value)
+		if *account.value == "" {
+			*account.value = account.fallback
+			continue
+		}
+		if err := validateAccountName(account.field, *account.value); err != nil {
+			return AccountMapping{}, err
+		}
+	}
+
+	switch mapping.DateFormat {
+	case "":
+		mapping.DateFormat = defaults.DateFormat
+	case AccountingDateDayFirst, AccountingDateMonthFirst:
+	default:
+		return AccountMapping{}, errors.NewValidationError("date_format", "must be dd/mm/yyyy or mm/dd/yyyy")
+	}
+	return mapping, nil
+}
+
+// validateAccountName checks that name can be written to IIF and CSV files
+// as it is: IIF separates fields by tabs, colons separate QuickBooks
+// subaccounts, and spreadsheets run cells starting with a formula sign
+func validateAccountName(field, name string) error {
+	if len(name) > 100 {
+		return errors.NewValidationError(field, "must be at most 100 characters")
+	}
+	if strings.ContainsAny(name, "\t\r\n\"") {
+		return errors.NewValidationError(field, "cannot contain tabs, line breaks or double quotes")
+	}
+	if strings.ContainsAny(name[:1], "=+-@") {
+		return errors.NewValidationError(field, "cannot start with =, +, - or @")
+	}
+	return nil
+}
+
+// GoDateLayout is the layout of DateFormat for time.Format
+func (m AccountMapping) GoDateLayout() string {
+	if m.DateFormat == AccountingDateMonthFirst {
+		return "01/02/2006"
+	}
+	return "02/01/2006"
+}
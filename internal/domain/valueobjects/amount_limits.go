@@ -0,0 +1,81 @@
+package valueobjects
+
+import (
+	"Pay2Go/internal/domain/errors"
+)
+
+// defaultMaxMajorUnits is the platform maximum, in major units, for currencies
+// without a configured limit
+const defaultMaxMajorUnits = 100000
+
+// AmountLimits maps a currency to an amount in that currency's minor units,
+// such as the maximum transaction amount or a refund approval threshold
+type AmountLimits map[Currency]int64
+
+// ParseAmountLimits builds limits from ISO 4217 codes and decimal amounts in
+// major units, e.g. {"USD": "100000", "JPY": "15000000"}
+func ParseAmountLimits(limits map[string]string) (AmountLimits, error) {
+	return ParseCurrencyAmounts("amount_limits", limits)
+}
+
+// ParseCurrencyAmounts builds a positive amount per currency from ISO 4217
+// codes and decimal amounts in major units, each converted to its
+// currency's minor units. field names the input in validation errors.
+func ParseCurrencyAmounts(field string, amounts map[string]string) (AmountLimits, error) {
+	parsed := make(AmountLimits, len(amounts))
+	for code, amount := range amounts {
+		currency, err := NewCurrency(code)
+		if err != nil {
+			return nil, errors.NewValidationError(field, "unknown currency "+code)
+		}
+
+		units, err := parseMinorUnits(amount, currency.Exponent())
+		if err != nil {
+			return nil, errors.NewValidationError(field, "invalid amount for "+code+": "+err.Error())
+		}
+		if units < 1 {
+			return nil, errors.NewValidationError(field, "amount for "+code+" must be positive")
+		}
+
+		parsed[currency] = units
+	}
+
+	return parsed, nil
+}
+
+// Limit returns the configured maximum for currency in minor units
+func (l AmountLimits) Limit(currency Currency) (int64, bool) {
+	limit, ok := l[currency]
+	return limit, ok
+}
+
+// Decimals returns the limits as decimal strings in major units keyed by
+// currency code, the inverse of ParseAmountLimits
+func (l AmountLimits) Decimals() map[string]string {
+	decimals := make(map[string]string, len(l))
+	for currency, limit := range l {
+		decimals[currency.String()] = FormatMinorUnits(limit, currency)
+	}
+	return decimals
+}
+
+// DefaultMaxAmount is the platform maximum in currency's minor units when no
+// limit is configured for it (100,000 major units)
+func DefaultMaxAmount(currency Currency) int64 {
+	return defaultMaxMajorUnits * pow10(currency.Exponent())
+}
+
+// CheckLimit returns an *errors.AmountLimitError if m exceeds limit, which was
+// configured at scope
+func (m Money) CheckLimit(limit int64, scope string) error {
+	if m.Amount <= limit {
+		return nil
+	}
+
+	return &errors.AmountLimitError{
+		Currency: m.Currency.String(),
+		Amount:   m.Decimal(),
+		Limit:    FormatMinorUnits(limit, m.Currency),
+		Scope:    scope,
+	}
+}
@@ -0,0 +1,58 @@
+package valueobjects
+
+import (
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// APIKeyScope restricts what an API key is allowed to do
+type APIKeyScope string
+
+const (
+	ScopeReadOnly APIKeyScope = "read_only"
+	ScopePayments APIKeyScope = "payments"
+	ScopeRefunds  APIKeyScope = "refunds"
+	ScopeAdmin    APIKeyScope = "admin"
+)
+
+// NewAPIKeyScope validates and creates an APIKeyScope
+func NewAPIKeyScope(scope string) (APIKeyScope, error) {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+
+	validScopes := map[string]bool{
+		"read_only": true,
+		"payments":  true,
+		"refunds":   true,
+		"admin":     true,
+	}
+
+	if !validScopes[scope] {
+		return "", errors.NewValidationError("scope", "must be one of read_only, payments, refunds, admin")
+	}
+
+	return APIKeyScope(scope), nil
+}
+
+// HasScope checks if scopes grant the required scope.
+// Admin grants everything, and any scope grants read-only access.
+func HasScope(scopes []APIKeyScope, required APIKeyScope) bool {
+	for _, scope := range scopes {
+		if scope == ScopeAdmin || scope == required {
+			return true
+		}
+	}
+
+	return required == ScopeReadOnly && len(scopes) > 0
+}
+
+// String returns the string representation
+func (s APIKeyScope) String() string {
+	return string(s)
+}
+
+// IsValid checks if scope is valid
+func (s APIKeyScope) IsValid() bool {
+	_, err := NewAPIKeyScope(string(s))
+	return err == nil
+}
@@ -0,0 +1,120 @@
+package valueobjects
+
+import (
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// CardFunding represents how a card is funded
+type CardFunding string
+
+const (
+	CardFundingCredit  CardFunding = "credit"
+	CardFundingDebit   CardFunding = "debit"
+	CardFundingPrepaid CardFunding = "prepaid"
+	CardFundingUnknown CardFunding = "unknown"
+)
+
+// NewCardFunding validates and creates a CardFunding
+func NewCardFunding(funding string) (CardFunding, error) {
+	switch CardFunding(strings.ToLower(strings.TrimSpace(funding))) {
+	case CardFundingCredit:
+		return CardFundingCredit, nil
+	case CardFundingDebit:
+		return CardFundingDebit, nil
+	case CardFundingPrepaid:
+		return CardFundingPrepaid, nil
+	case CardFundingUnknown, "":
+		return CardFundingUnknown, nil
+	}
+
+	return "", errors.NewValidationError("card.funding", "must be credit, debit or prepaid")
+}
+
+// String returns the string representation
+func (f CardFunding) String() string {
+	return string(f)
+}
+
+// BIN is a card's Bank Identification Number: the first 6 to 8 digits of the
+// card number, which identify the issuer. It is safe to store, unlike the
+// full card number.
+type BIN string
+
+const (
+	minBINLength = 6
+	maxBINLength = 8
+)
+
+// NewBIN creates a BIN from a card number or its prefix. Numbers longer than
+// 8 digits are cut to their first 8, so a full PAN never leaves this function.
+func NewBIN(number string) (BIN, error) {
+	number = strings.NewReplacer(" ", "", "-", "").Replace(number)
+
+	if !isDigits(number) || len(number) < minBINLength || len(number) > 19 {
+		return "", errors.NewValidationError("card.bin", "must be at least the first 6 digits of the card number")
+	}
+
+	if len(number) > maxBINLength {
+		number = number[:maxBINLength]
+	}
+
+	return BIN(number), nil
+}
+
+// String returns the string representation
+func (b BIN) String() string {
+	return string(b)
+}
+
+// Brand detects the card network from the BIN's IIN range. It needs no lookup
+// table, so it is the fallback when a BIN is not in the local table.
+func (b BIN) Brand() CardBrand {
+	prefix := func(n int) int {
+		value := 0
+		for _, digit := range string(b)[:n] {
+			value = value*10 + int(digit-'0')
+		}
+		return value
+	}
+
+	switch p1, p2, p3, p4 := prefix(1), prefix(2), prefix(3), prefix(4); {
+	case p2 == 34 || p2 == 37:
+		return CardBrandAmex
+	case (p3 >= 300 && p3 <= 305) || p2 == 36 || p2 == 38 || p2 == 39:
+		return CardBrandDiners
+	case p4 >= 3528 && p4 <= 3589:
+		return CardBrandJCB
+	case p1 == 4:
+		return CardBrandVisa
+	case (p2 >= 51 && p2 <= 55) || (p4 >= 2221 && p4 <= 2720):
+		return CardBrandMastercard
+	case p4 == 6011 || (p3 >= 644 && p3 <= 649) || p2 == 65:
+		return CardBrandDiscover
+	case p2 == 62:
+		return CardBrandUnionPay
+	}
+
+	return CardBrandUnknown
+}
+
+// BINInfo is what is known about the issuer of a BIN range
+type BINInfo struct {
+	// Prefix is the range in the BIN table that matched, 6 to 8 digits
+	Prefix        BIN
+	Brand         CardBrand
+	IssuerCountry Country
+	IssuerName    string
+	Funding       CardFunding
+}
+
+// FallbackInfo returns what can be derived from the BIN alone when it is not
+// in the BIN table: the brand, with the country and funding unknown
+func (b BIN) FallbackInfo() BINInfo {
+	return BINInfo{
+		Prefix:  b,
+		Brand:   b.Brand(),
+		Funding: CardFundingUnknown,
+	}
+}
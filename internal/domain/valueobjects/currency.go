@@ -0,0 +1,249 @@
+package valueobjects
+
+import (
+	"sort"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// Currency represents a currency code (ISO 4217)
+type Currency string
+
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+	JPY Currency = "JPY"
+	THB Currency = "THB"
+)
+
+// currencyInfo holds the ISO 4217 attributes of a currency
+type currencyInfo struct {
+	numeric  int    // ISO 4217 numeric code
+	exponent int    // Digits after the decimal point (minor units)
+	symbol   string // Common display symbol
+	name     string // ISO 4217 currency name
+}
+
+// iso4217 is the registry of circulating ISO 4217 currencies. Fund codes
+// (e.g. CLF, USN), precious metals, SDRs and the testing code XTS are
+// deliberately excluded; they are never used for card or wallet payments.
+var iso4217 = map[Currency]currencyInfo{
+	"AED": {784, 2, "د.إ", "UAE Dirham"},
+	"AFN": {971, 2, "؋", "Afghani"},
+	"ALL": {8, 2, "L", "Lek"},
+	"AMD": {51, 2, "֏", "Armenian Dram"},
+	"AOA": {973, 2, "Kz", "Kwanza"},
+	"ARS": {32, 2, "$", "Argentine Peso"},
+	"AUD": {36, 2, "A$", "Australian Dollar"},
+	"AWG": {533, 2, "ƒ", "Aruban Florin"},
+	"AZN": {944, 2, "₼", "Azerbaijan Manat"},
+	"BAM": {977, 2, "KM", "Convertible Mark"},
+	"BBD": {52, 2, "Bds$", "Barbados Dollar"},
+	"BDT": {50, 2, "৳", "Taka"},
+	"BHD": {48, 3, ".د.ب", "Bahraini Dinar"},
+	"BIF": {108, 0, "FBu", "Burundi Franc"},
+	"BMD": {60, 2, "$", "Bermudian Dollar"},
+	"BND": {96, 2, "B$", "Brunei Dollar"},
+	"BOB": {68, 2, "Bs.", "Boliviano"},
+	"BRL": {986, 2, "R$", "Brazilian Real"},
+	"BSD": {44, 2, "$", "Bahamian Dollar"},
+	"BTN": {64, 2, "Nu.", "Ngultrum"},
+	"BWP": {72, 2, "P", "Pula"},
+	"BYN": {933, 2, "Br", "Belarusian Ruble"},
+	"BZD": {84, 2, "BZ$", "Belize Dollar"},
+	"CAD": {124, 2, "CA$", "Canadian Dollar"},
+	"CDF": {976, 2, "FC", "Congolese Franc"},
+	"CHF": {756, 2, "CHF", "Swiss Franc"},
+	"CLP": {152, 0, "$", "Chilean Peso"},
+	"CNY": {156, 2, "¥", "Yuan Renminbi"},
+	"COP": {170, 2, "$", "Colombian Peso"},
+	"CRC": {188, 2, "₡", "Costa Rican Colon"},
+	"CUP": {192, 2, "$", "Cuban Peso"},
+	"CVE": {132, 2, "Esc", "Cabo Verde Escudo"},
+	"CZK": {203, 2, "Kč", "Czech Koruna"},
+	"DJF": {262, 0, "Fdj", "Djibouti Franc"},
+	"DKK": {208, 2, "kr", "Danish Krone"},
+	"DOP": {214, 2, "RD$", "Dominican Peso"},
+	"DZD": {12, 2, "دج", "Algerian Dinar"},
+	"EGP": {818, 2, "E£", "Egyptian Pound"},
+	"ERN": {232, 2, "Nfk", "Nakfa"},
+	"ETB": {230, 2, "Br", "Ethiopian Birr"},
+	"EUR": {978, 2, "€", "Euro"},
+	"FJD": {242, 2, "FJ$", "Fiji Dollar"},
+	"FKP": {238, 2, "£", "Falkland Islands Pound"},
+	"GBP": {826, 2, "£", "Pound Sterling"},
+	"GEL": {981, 2, "₾", "Lari"},
+	"GHS": {936, 2, "₵", "Ghana Cedi"},
+	"GIP": {292, 2, "£", "Gibraltar Pound"},
+	"GMD": {270, 2, "D", "Dalasi"},
+	"GNF": {324, 0, "FG", "Guinean Franc"},
+	"GTQ": {320, 2, "Q", "Quetzal"},
+	"GYD": {328, 2, "G$", "Guyana Dollar"},
+	"HKD": {344, 2, "HK$", "Hong Kong Dollar"},
+	"HNL": {340, 2, "L", "Lempira"},
+	"HTG": {332, 2, "G", "Gourde"},
+	"HUF": {348, 2, "Ft", "Forint"},
+	"IDR": {360, 2, "Rp", "Rupiah"},
+	"ILS": {376, 2, "₪", "New Israeli Sheqel"},
+	"INR": {356, 2, "₹", "Indian Rupee"},
+	"IQD": {368, 3, "ع.د", "Iraqi Dinar"},
+	"IRR": {364, 2, "﷼", "Iranian Rial"},
+	"ISK": {352, 0, "kr", "Iceland Krona"},
+	"JMD": {388, 2, "J$", "Jamaican Dollar"},
+	"JOD": {400, 3, "JD", "Jordanian Dinar"},
+	"JPY": {392, 0, "¥", "Yen"},
+	"KES": {404, 2, "KSh", "Kenyan Shilling"},
+	"KGS": {417, 2, "с", "Som"},
+	"KHR": {116, 2, "៛", "Riel"},
+	"KMF": {174, 0, "CF", "Comorian Franc"},
+	"KPW": {408, 2, "₩", "North Korean Won"},
+	"KRW": {410, 0, "₩", "Won"},
+	"KWD": {414, 3, "KD", "Kuwaiti Dinar"},
+	"KYD": {136, 2, "CI$", "Cayman Islands Dollar"},
+	"KZT": {398, 2, "₸", "Tenge"},
+	"LAK": {418, 2, "₭", "Lao Kip"},
+	"LBP": {422, 2, "ل.ل", "Lebanese Pound"},
+	"LKR": {144, 2, "Rs", "Sri Lanka Rupee"},
+	"LRD": {430, 2, "L$", "Liberian Dollar"},
+	"LSL": {426, 2, "L", "Loti"},
+	"LYD": {434, 3, "LD", "Libyan Dinar"},
+	"MAD": {504, 2, "DH", "Moroccan Dirham"},
+	"MDL": {498, 2, "L", "Moldovan Leu"},
+	"MGA": {969, 2, "Ar", "Malagasy Ariary"},
+	"MKD": {807, 2, "ден", "Denar"},
+	"MMK": {104, 2, "K", "Kyat"},
+	"MNT": {496, 2, "₮", "Tugrik"},
+	"MOP": {446, 2, "MOP$", "Pataca"},
+	"MRU": {929, 2, "UM", "Ouguiya"},
+	"MUR": {480, 2, "Rs", "Mauritius Rupee"},
+	"MVR": {462, 2, "Rf", "Rufiyaa"},
+	"MWK": {454, 2, "MK", "Malawi Kwacha"},
+	"MXN": {484, 2, "MX$", "Mexican Peso"},
+	"MYR": {458, 2, "RM", "Malaysian Ringgit"},
+	"MZN": {943, 2, "MT", "Mozambique Metical"},
+	"NAD": {516, 2, "N$", "Namibia Dollar"},
+	"NGN": {566, 2, "₦", "Naira"},
+	"NIO": {558, 2, "C$", "Cordoba Oro"},
+	"NOK": {578, 2, "kr", "Norwegian Krone"},
+	"NPR": {524, 2, "Rs", "Nepalese Rupee"},
+	"NZD": {554, 2, "NZ$", "New Zealand Dollar"},
+	"OMR": {512, 3, "ر.ع.", "Rial Omani"},
+	"PAB": {590, 2, "B/.", "Balboa"},
+	"PEN": {604, 2, "S/", "Sol"},
+	"PGK": {598, 2, "K", "Kina"},
+	"PHP": {608, 2, "₱", "Philippine Peso"},
+	"PKR": {586, 2, "Rs", "Pakistan Rupee"},
+	"PLN": {985, 2, "zł", "Zloty"},
+	"PYG": {600, 0, "₲", "Guarani"},
+	"QAR": {634, 2, "QR", "Qatari Rial"},
+	"RON": {946, 2, "lei", "Romanian Leu"},
+	"RSD": {941, 2, "дин.", "Serbian Dinar"},
+	"RUB": {643, 2, "₽", "Russian Ruble"},
+	"RWF": {646, 0, "FRw", "Rwanda Franc"},
+	"SAR": {682, 2, "SR", "Saudi Riyal"},
+	"SBD": {90, 2, "SI$", "Solomon Islands Dollar"},
+	"SCR": {690, 2, "SR", "Seychelles Rupee"},
+	"SDG": {938, 2, "£", "Sudanese Pound"},
+	"SEK": {752, 2, "kr", "Swedish Krona"},
+	"SGD": {702, 2, "S$", "Singapore Dollar"},
+	"SHP": {654, 2, "£", "Saint Helena Pound"},
+	"SLE": {925, 2, "Le", "Leone"},
+	"SOS": {706, 2, "Sh", "Somali Shilling"},
+	"SRD": {968, 2, "$", "Surinam Dollar"},
+	"SSP": {728, 2, "£", "South Sudanese Pound"},
+	"STN": {930, 2, "Db", "Dobra"},
+	"SVC": {222, 2, "₡", "El Salvador Colon"},
+	"SYP": {760, 2, "£S", "Syrian Pound"},
+	"SZL": {748, 2, "E", "Lilangeni"},
+	"THB": {764, 2, "฿", "Baht"},
+	"TJS": {972, 2, "SM", "Somoni"},
+	"TMT": {934, 2, "m", "Turkmenistan New Manat"},
+	"TND": {788, 3, "DT", "Tunisian Dinar"},
+	"TOP": {776, 2, "T$", "Pa'anga"},
+	"TRY": {949, 2, "₺", "Turkish Lira"},
+	"TTD": {780, 2, "TT$", "Trinidad and Tobago Dollar"},
+	"TWD": {901, 2, "NT$", "New Taiwan Dollar"},
+	"TZS": {834, 2, "TSh", "Tanzanian Shilling"},
+	"UAH": {980, 2, "₴", "Hryvnia"},
+	"UGX": {800, 0, "USh", "Uganda Shilling"},
+	"USD": {840, 2, "$", "US Dollar"},
+	"UYU": {858, 2, "$U", "Peso Uruguayo"},
+	"UZS": {860, 2, "soʻm", "Uzbekistan Sum"},
+	"VED": {926, 2, "Bs.D", "Bolívar Soberano"},
+	"VES": {928, 2, "Bs.S", "Bolívar Soberano"},
+	"VND": {704, 0, "₫", "Dong"},
+	"VUV": {548, 0, "VT", "Vatu"},
+	"WST": {882, 2, "WS$", "Tala"},
+	"XAF": {950, 0, "FCFA", "CFA Franc BEAC"},
+	"XCD": {951, 2, "EC$", "East Caribbean Dollar"},
+	"XCG": {532, 2, "Cg", "Caribbean Guilder"},
+	"XOF": {952, 0, "CFA", "CFA Franc BCEAO"},
+	"XPF": {953, 0, "₣", "CFP Franc"},
+	"YER": {886, 2, "﷼", "Yemeni Rial"},
+	"ZAR": {710, 2, "R", "Rand"},
+	"ZMW": {967, 2, "ZK", "Zambian Kwacha"},
+	"ZWG": {924, 2, "ZiG", "Zimbabwe Gold"},
+}
+
+// NewCurrency validates and creates a Currency
+func NewCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+
+	// Validate currency code
+	if _, ok := iso4217[currency]; !ok {
+		return "", errors.ErrInvalidCurrency
+	}
+
+	return currency, nil
+}
+
+// Currencies returns every supported currency, sorted by code
+func Currencies() []Currency {
+	currencies := make([]Currency, 0, len(iso4217))
+	for currency := range iso4217 {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+
+	return currencies
+}
+
+// String returns the string representation
+func (c Currency) String() string {
+	return string(c)
+}
+
+// IsValid checks if currency is valid
+func (c Currency) IsValid() bool {
+	_, ok := iso4217[c]
+	return ok
+}
+
+// NumericCode returns the ISO 4217 numeric code (0 for unknown currencies)
+func (c Currency) NumericCode() int {
+	return iso4217[c].numeric
+}
+
+// Exponent returns the number of digits after the decimal point, e.g. 2 for
+// USD cents, 0 for JPY and 3 for KWD. Unknown currencies default to 2.
+func (c Currency) Exponent() int {
+	if info, ok := iso4217[c]; ok {
+		return info.exponent
+	}
+	return 2
+}
+
+// Symbol returns the common display symbol, or the code if there is none
+func (c Currency) Symbol() string {
+	if info, ok := iso4217[c]; ok && info.symbol != "" {
+		return info.symbol
+	}
+	return string(c)
+}
+
+// Name returns the ISO 4217 currency name
+func (c Currency) Name() string {
+	return iso4217[c].name
+}
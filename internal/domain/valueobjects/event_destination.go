@@ -0,0 +1,103 @@
+package valueobjects
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// EventDestinationType is where a partner's events are delivered instead of
+// its webhook URL
+type EventDestinationType string
+
+const (
+	// EventDestinationSNS publishes events to an Amazon SNS topic
+	EventDestinationSNS EventDestinationType = "sns"
+	// EventDestinationSQS sends events to an Amazon SQS queue
+	EventDestinationSQS EventDestinationType = "sqs"
+)
+
+var (
+	// snsTopicARN matches arn:aws:sns:<region>:<account>:<topic>
+	snsTopicARN = regexp.MustCompile(`^arn:aws[a-z-]*:sns:([a-z0-9-]+):(\d{12}):([A-Za-z0-9_-]{1,256}(\.fifo)?)$`)
+	// iamRoleARN matches arn:aws:iam::<account>:role/<path/name>
+	iamRoleARN = regexp.MustCompile(`^arn:aws[a-z-]*:iam::(\d{12}):role/[A-Za-z0-9+=,.@_/-]{1,512}$`)
+	// sqsQueuePath matches /<account>/<queue> of an SQS queue URL
+	sqsQueuePath = regexp.MustCompile(`^/(\d{12})/([A-Za-z0-9_-]{1,80}(\.fifo)?)$`)
+	// sqsQueueHost matches sqs.<region>.amazonaws.com
+	sqsQueueHost = regexp.MustCompile(`^sqs\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+)
+
+// EventDestination is a partner's own AWS topic or queue that receives its
+// events. Pay2Go assumes RoleARN in the partner's account to deliver them, so
+// the partner grants access with the role's trust policy rather than by
+// sharing keys.
+type EventDestination struct {
+	Type EventDestinationType `json:"type"`
+	// Target is the topic ARN for SNS or the queue URL for SQS
+	Target  string `json:"target"`
+	RoleARN string `json:"role_arn"`
+}
+
+// NewEventDestination validates and creates an EventDestination
+func NewEventDestination(destinationType, target, roleARN string) (EventDestination, error) {
+	destination := EventDestination{
+		Type:    EventDestinationType(strings.ToLower(strings.TrimSpace(destinationType))),
+		Target:  strings.TrimSpace(target),
+		RoleARN: strings.TrimSpace(roleARN),
+	}
+
+	switch destination.Type {
+	case EventDestinationSNS:
+		if !snsTopicARN.MatchString(destination.Target) {
+			return EventDestination{}, errors.NewValidationError("target", "must be an SNS topic ARN")
+		}
+	case EventDestinationSQS:
+		if _, ok := parseQueueURL(destination.Target); !ok {
+			return EventDestination{}, errors.NewValidationError("target", "must be an SQS queue URL")
+		}
+	default:
+		return EventDestination{}, errors.NewValidationError("type", "must be sns or sqs")
+	}
+
+	if !iamRoleARN.MatchString(destination.RoleARN) {
+		return EventDestination{}, errors.NewValidationError("role_arn", "must be an IAM role ARN")
+	}
+
+	return destination, nil
+}
+
+// Region returns the AWS region of the topic or queue
+func (d EventDestination) Region() string {
+	if d.Type == EventDestinationSQS {
+		region, _ := parseQueueURL(d.Target)
+		return region
+	}
+	if match := snsTopicARN.FindStringSubmatch(d.Target); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// IsFIFO reports whether the topic or queue is FIFO, which requires a
+// message group on every message
+func (d EventDestination) IsFIFO() bool {
+	return strings.HasSuffix(d.Target, ".fifo")
+}
+
+// parseQueueURL returns the region of an SQS queue URL such as
+// https://sqs.eu-west-1.amazonaws.com/123456789012/events
+func parseQueueURL(queueURL string) (string, bool) {
+	parsed, err := url.Parse(queueURL)
+	if err != nil || parsed.Scheme != "https" || parsed.RawQuery != "" || parsed.User != nil {
+		return "", false
+	}
+
+	host := sqsQueueHost.FindStringSubmatch(parsed.Host)
+	if host == nil || !sqsQueuePath.MatchString(parsed.Path) {
+		return "", false
+	}
+	return host[1], true
+}
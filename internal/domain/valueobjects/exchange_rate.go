@@ -0,0 +1,111 @@
+package valueobjects
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// ExchangeRate is the price of one unit of From in units of To, as quoted by
+// Source at AsOf
+type ExchangeRate struct {
+	From   Currency
+	To     Currency
+	Rate   float64
+	Source string
+	AsOf   time.Time
+}
+
+// NewExchangeRate creates a new ExchangeRate value object with validation
+func NewExchangeRate(from, to string, rate float64, source string, asOf time.Time) (ExchangeRate, error) {
+	fromCurrency, err := NewCurrency(from)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+
+	toCurrency, err := NewCurrency(to)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+
+	if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return ExchangeRate{}, errors.NewValidationError("rate", "must be a positive number")
+	}
+
+	// Converting a currency to itself is always at par
+	if fromCurrency == toCurrency && rate != 1 {
+		return ExchangeRate{}, errors.NewValidationError("rate", "must be 1 between the same currency")
+	}
+
+	return ExchangeRate{
+		From:   fromCurrency,
+		To:     toCurrency,
+		Rate:   rate,
+		Source: source,
+		AsOf:   asOf,
+	}, nil
+}
+
+// Inverse returns the rate for converting To back into From
+func (r ExchangeRate) Inverse() ExchangeRate {
+	return ExchangeRate{
+		From:   r.To,
+		To:     r.From,
+		Rate:   1 / r.Rate,
+		Source: r.Source,
+		AsOf:   r.AsOf,
+	}
+}
+
+// String returns string representation
+func (r ExchangeRate) String() string {
+	return fmt.Sprintf("1 %s = %g %s", r.From, r.Rate, r.To)
+}
+
+// Conversion records a converted amount together with the original amount and
+// the rate used, so settlements and reports can show how it was derived
+type Conversion struct {
+	Original  Money
+	Converted Money
+	Rate      ExchangeRate
+}
+
+// ConvertTo converts m into currency at rate, rounding halves to even
+// (banker's rounding) to the target currency's minor unit. The rate must quote m's currency in currency.
+func (m Money) ConvertTo(currency Currency, rate ExchangeRate) (Conversion, error) {
+	if rate.From != m.Currency || rate.To != currency {
+		return Conversion{}, errors.NewValidationError("rate",
+			fmt.Sprintf("quotes %s to %s, cannot convert %s to %s", rate.From, rate.To, m.Currency, currency))
+	}
+
+	if rate.Rate <= 0 || math.IsNaN(rate.Rate) || math.IsInf(rate.Rate, 0) {
+		return Conversion{}, errors.NewValidationError("rate", "must be a positive number")
+	}
+	ratio, err := RatioOf(rate.Rate)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	// Scale to the target minor unit before rounding, e.g. USD cents to whole JPY
+	numerator, err := mulExact(ratio.Numerator(), pow10(currency.Exponent()))
+	if err != nil {
+		return Conversion{}, err
+	}
+	denominator, err := mulExact(ratio.Denominator(), pow10(m.Currency.Exponent()))
+	if err != nil {
+		return Conversion{}, err
+	}
+	units, err := RoundHalfEven.Scale(m.Amount, numerator, denominator)
+	if err != nil {
+		return Conversion{}, err
+	}
+	converted := Money{Currency: currency}.withAmount(units)
+
+	return Conversion{
+		Original:  m,
+		Converted: converted,
+		Rate:      rate,
+	}, nil
+}
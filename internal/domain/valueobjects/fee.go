@@ -0,0 +1,83 @@
+package valueobjects
+
+import (
+	"math"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// FeeSchedule is a processing fee of a percentage of the amount plus a fixed
+// amount, e.g. 2.9% + 30 cents
+type FeeSchedule struct {
+	Percent float64
+	Fixed   int64 // Minor units of the transaction's currency
+}
+
+// NewFeeSchedule validates and creates a FeeSchedule
+func NewFeeSchedule(percent float64, fixed int64) (FeeSchedule, error) {
+	if percent < 0 || percent > 100 || math.IsNaN(percent) {
+		return FeeSchedule{}, errors.NewValidationError("fee.percent", "must be between 0 and 100")
+	}
+
+	if fixed < 0 {
+		return FeeSchedule{}, errors.NewValidationError("fee.fixed", "cannot be negative")
+	}
+
+	return FeeSchedule{Percent: percent, Fixed: fixed}, nil
+}
+
+// Calculate returns the fee for amount. Only the percentage part is rounded,
+// with policy, so the fee matches what the partner computes in their books.
+func (f FeeSchedule) Calculate(amount Money, policy RoundingPolicy) (Money, error) {
+	percent, err := RatioOf(f.Percent)
+	if err != nil {
+		return Money{}, err
+	}
+	fee, err := amount.PercentOfWithPolicy(percent, policy)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return fee.withAmount(fee.Amount + f.Fixed), nil
+}
+
+// TaxRate is a sales tax or VAT rate, e.g. 7% Thai VAT
+type TaxRate struct {
+	Percent float64
+	// Inclusive rates are already part of the amount (e.g. consumer prices in
+	// the EU); exclusive rates are added on top (e.g. US sales tax)
+	Inclusive bool
+}
+
+// NewTaxRate validates and creates a TaxRate
+func NewTaxRate(percent float64, inclusive bool) (TaxRate, error) {
+	if percent < 0 || percent > 100 || math.IsNaN(percent) {
+		return TaxRate{}, errors.NewValidationError("tax.percent", "must be between 0 and 100")
+	}
+
+	return TaxRate{Percent: percent, Inclusive: inclusive}, nil
+}
+
+// Calculate returns the tax on amount, rounded with policy. For inclusive
+// rates the net amount is rounded and the tax is the rest, so net + tax
+// always equals amount.
+func (t TaxRate) Calculate(amount Money, policy RoundingPolicy) (Money, error) {
+	percent, err := RatioOf(t.Percent)
+	if err != nil {
+		return Money{}, err
+	}
+	if !t.Inclusive {
+		return amount.PercentOfWithPolicy(percent, policy)
+	}
+
+	// net = amount * 100 / (100 + percent), in whole numbers
+	whole, err := mulExact(percent.Denominator(), 100)
+	if err != nil {
+		return Money{}, err
+	}
+	net, err := policy.Scale(amount.Amount, whole, whole+percent.Numerator())
+	if err != nil {
+		return Money{}, err
+	}
+	return amount.withAmount(amount.Amount - net), nil
+}
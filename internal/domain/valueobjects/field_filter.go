@@ -0,0 +1,272 @@
+package valueobjects
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// MaxHiddenFields is how many fields one list of a field filter may hide
+const MaxHiddenFields = 50
+
+// fieldPath matches a field name, or names of nested fields joined by dots,
+// such as error_code or customer.email
+var fieldPath = regexp.MustCompile(`^[a-z0-9_]{1,64}(\.[a-z0-9_]{1,64}){0,4}$`)
+
+// FieldFilter hides fields from what a partner's integrations receive, such
+// as internal error codes from keys embedded in a frontend. Responses hides
+// fields from the API responses of keys with the given scope, and Webhooks
+// from the data of delivered events.
+//
+// A path matches at any depth, so error_code hides the error code of a
+// transaction on its own, in lists and nested in other resources.
+type FieldFilter struct {
+	Responses map[APIKeyScope][]string `json:"responses,omitempty"`
+	Webhooks  []string                 `json:"webhooks,omitempty"`
+}
+
+// NewFieldFilter validates and creates a FieldFilter. Paths are sorted and
+// deduplicated; scopes hiding nothing are dropped.
+func NewFieldFilter(responses map[string][]string, webhooks []string) (FieldFilter, error) {
+	var filter FieldFilter
+
+	for scope, paths := range responses {
+		apiKeyScope, err := NewAPIKeyScope(scope)
+		if err != nil {
+			return FieldFilter{}, errors.NewValidationError("responses", fmt.Sprintf("unknown scope %q", scope))
+		}
+		hidden, err := newHiddenFields("responses."+string(apiKeyScope), paths)
+		if err != nil {
+			return FieldFilter{}, err
+		}
+		if len(hidden) == 0 {
+			continue
+		}
+		if filter.Responses == nil {
+			filter.Responses = make(map[APIKeyScope][]string)
+		}
+		filter.Responses[apiKeyScope] = hidden
+	}
+
+	hidden, err := newHiddenFields("webhooks", webhooks)
+	if err != nil {
+		return FieldFilter{}, err
+	}
+	filter.Webhooks = hidden
+
+	return filter, nil
+}
+
+// newHiddenFields validates, sorts and deduplicates the paths of field
+func newHiddenFields(field string, paths []string) ([]string, error) {
+	if len(paths) > MaxHiddenFields {
+		return nil, errors.NewValidationError(field, fmt.Sprintf("can hide at most %d fields", MaxHiddenFields))
+	}
+
+	seen := make(map[string]bool, len(paths))
+	var hidden []string
+	for _, path := range paths {
+		path = strings.ToLower(strings.TrimSpace(path))
+		if !fieldPath.MatchString(path) {
+			return nil, errors.NewValidationError(field, fmt.Sprintf("%q is not a field name or dotted path", path))
+		}
+		if !seen[path] {
+			seen[path] = true
+			hidden = append(hidden, path)
+		}
+	}
+	sort.Strings(hidden)
+
+	return hidden, nil
+}
+
+// IsEmpty reports whether the filter hides nothing
+func (f FieldFilter) IsEmpty() bool {
+	return len(f.Responses) == 0 && len(f.Webhooks) == 0
+}
+
+// Clone returns a copy of the filter sharing nothing with it
+func (f FieldFilter) Clone() FieldFilter {
+	var c FieldFilter
+	if f.Responses != nil {
+		c.Responses = make(map[APIKeyScope][]string, len(f.Responses))
+		for scope, paths := range f.Responses {
+			c.Responses[scope] = append([]string{}, paths...)
+		}
+	}
+	if f.Webhooks != nil {
+		c.Webhooks = append([]string{}, f.Webhooks...)
+	}
+	return c
+}
+
+// HiddenFor returns the fields hidden from the responses of a credential
+// with scopes: those hidden for every one of them, as a key sees what any
+// of its scopes does
+func (f FieldFilter) HiddenFor(scopes []APIKeyScope) []string {
+	if len(f.Responses) == 0 || len(scopes) == 0 {
+		return nil
+	}
+
+	var hidden []string
+	for _, path := range f.Responses[scopes[0]] {
+		everywhere := true
+		for _, scope := range scopes[1:] {
+			if !containsString(f.Responses[scope], path) {
+				everywhere = false
+				break
+			}
+		}
+		if everywhere {
+			hidden = append(hidden, path)
+		}
+	}
+
+	return hidden
+}
+
+func containsString(sorted []string, s string) bool {
+	i := sort.SearchStrings(sorted, s)
+	return i < len(sorted) && sorted[i] == s
+}
+
+// fieldNode is a field name in a tree of hidden paths
+type fieldNode struct {
+	hidden   bool
+	children map[string]*fieldNode
+}
+
+// HideFields returns the JSON document body without the fields at paths.
+// Everything else keeps its order and encoding; an empty body stays empty.
+func HideFields(body []byte, paths []string) ([]byte, error) {
+	if len(paths) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return body, nil
+	}
+
+	root := &fieldNode{}
+	for _, path := range paths {
+		node := root
+		for _, name := range strings.Split(path, ".") {
+			if node.children == nil {
+				node.children = make(map[string]*fieldNode)
+			}
+			child := node.children[name]
+			if child == nil {
+				child = &fieldNode{}
+				node.children[name] = child
+			}
+			node = child
+		}
+		node.hidden = true
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(body))
+	if err := hideFields(&out, json.RawMessage(bytes.TrimSpace(body)), root, []*fieldNode{root}); err != nil {
+		return nil, err
+	}
+	// Keep the newline a JSON encoder ends the document with
+	if bytes.HasSuffix(body, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+// hideFields writes value without its hidden fields. active are the nodes
+// the fields of an object are looked up in: the root, since paths match at
+// any depth, and the children of the nodes that matched the names leading
+// to value.
+func hideFields(out *bytes.Buffer, value json.RawMessage, root *fieldNode, active []*fieldNode) error {
+	if len(value) == 0 {
+		return fmt.Errorf("empty JSON value")
+	}
+
+	switch value[0] {
+	case '{':
+		var fields orderedFields
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return err
+		}
+		out.WriteByte('{')
+		first := true
+		for _, field := range fields {
+			next := []*fieldNode{root}
+			hidden := false
+			for _, node := range active {
+				if child := node.children[field.name]; child != nil {
+					hidden = hidden || child.hidden
+					next = append(next, child)
+				}
+			}
+			if hidden {
+				continue
+			}
+			if !first {
+				out.WriteByte(',')
+			}
+			first = false
+			name, _ := json.Marshal(field.name)
+			out.Write(name)
+			out.WriteByte(':')
+			if err := hideFields(out, field.value, root, next); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil {
+			return err
+		}
+		out.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			// The items of a list are matched as the list's field is
+			if err := hideFields(out, item, root, active); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+	default:
+		out.Write(value)
+	}
+	return nil
+}
+
+// orderedField is a field of a JSON object
+type orderedField struct {
+	name  string
+	value json.RawMessage
+}
+
+// orderedFields are the fields of a JSON object in the order they came in
+type orderedFields []orderedField
+
+// UnmarshalJSON reads the fields of an object without sorting them, as
+// decoding into a map would
+func (f *orderedFields) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := token.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		*f = append(*f, orderedField{name: name, value: value})
+	}
+	return nil
+}
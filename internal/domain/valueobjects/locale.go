@@ -0,0 +1,130 @@
+package valueobjects
+
+import (
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// Locale represents a BCP 47 language tag limited to language, optional
+// script and optional region, e.g. "en", "th-TH" or "zh-Hant-TW"
+type Locale string
+
+// DefaultLocale is used for partners that have not chosen a locale
+const DefaultLocale Locale = "en-US"
+
+// iso639 is the set of ISO 639-1 two-letter language codes
+var iso639 = map[string]bool{
+	"aa": true, "ab": true, "ae": true, "af": true, "ak": true, "am": true, "an": true, "ar": true,
+	"as": true, "av": true, "ay": true, "az": true, "ba": true, "be": true, "bg": true, "bh": true,
+	"bi": true, "bm": true, "bn": true, "bo": true, "br": true, "bs": true, "ca": true, "ce": true,
+	"ch": true, "co": true, "cr": true, "cs": true, "cu": true, "cv": true, "cy": true, "da": true,
+	"de": true, "dv": true, "dz": true, "ee": true, "el": true, "en": true, "eo": true, "es": true,
+	"et": true, "eu": true, "fa": true, "ff": true, "fi": true, "fj": true, "fo": true, "fr": true,
+	"fy": true, "ga": true, "gd": true, "gl": true, "gn": true, "gu": true, "gv": true, "ha": true,
+	"he": true, "hi": true, "ho": true, "hr": true, "ht": true, "hu": true, "hy": true, "hz": true,
+	"ia": true, "id": true, "ie": true, "ig": true, "ii": true, "ik": true, "io": true, "is": true,
+	"it": true, "iu": true, "ja": true, "jv": true, "ka": true, "kg": true, "ki": true, "kj": true,
+	"kk": true, "kl": true, "km": true, "kn": true, "ko": true, "kr": true, "ks": true, "ku": true,
+	"kv": true, "kw": true, "ky": true, "la": true, "lb": true, "lg": true, "li": true, "ln": true,
+	"lo": true, "lt": true, "lu": true, "lv": true, "mg": true, "mh": true, "mi": true, "mk": true,
+	"ml": true, "mn": true, "mr": true, "ms": true, "mt": true, "my": true, "na": true, "nb": true,
+	"nd": true, "ne": true, "ng": true, "nl": true, "nn": true, "no": true, "nr": true, "nv": true,
+	"ny": true, "oc": true, "oj": true, "om": true, "or": true, "os": true, "pa": true, "pi": true,
+	"pl": true, "ps": true, "pt": true, "qu": true, "rm": true, "rn": true, "ro": true, "ru": true,
+	"rw": true, "sa": true, "sc": true, "sd": true, "se": true, "sg": true, "si": true, "sk": true,
+	"sl": true, "sm": true, "sn": true, "so": true, "sq": true, "sr": true, "ss": true, "st": true,
+	"su": true, "sv": true, "sw": true, "ta": true, "te": true, "tg": true, "th": true, "ti": true,
+	"tk": true, "tl": true, "tn": true, "to": true, "tr": true, "ts": true, "tt": true, "tw": true,
+	"ty": true, "ug": true, "uk": true, "ur": true, "uz": true, "ve": true, "vi": true, "vo": true,
+	"wa": true, "wo": true, "xh": true, "yi": true, "yo": true, "za": true, "zh": true, "zu": true,
+}
+
+// NewLocale validates and creates a Locale, normalizing case and accepting
+// "_" as a separator ("en_us" becomes "en-US")
+func NewLocale(tag string) (Locale, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if len(parts) > 3 {
+		return "", errors.ErrInvalidLocale
+	}
+
+	language := strings.ToLower(parts[0])
+	if !iso639[language] {
+		return "", errors.ErrInvalidLocale
+	}
+	normalized := []string{language}
+
+	rest := parts[1:]
+	if len(rest) > 0 && len(rest[0]) == 4 && isLetters(rest[0]) {
+		// Script subtag (ISO 15924), title case
+		script := strings.ToUpper(rest[0][:1]) + strings.ToLower(rest[0][1:])
+		normalized = append(normalized, script)
+		rest = rest[1:]
+	}
+
+	if len(rest) > 0 {
+		region, err := normalizeRegion(rest[0])
+		if err != nil {
+			return "", err
+		}
+		normalized = append(normalized, region)
+		rest = rest[1:]
+	}
+
+	if len(rest) > 0 {
+		return "", errors.ErrInvalidLocale
+	}
+
+	return Locale(strings.Join(normalized, "-")), nil
+}
+
+// normalizeRegion validates a region subtag: an ISO 3166-1 alpha-2 country
+// or a UN M.49 area code such as "419" (Latin America)
+func normalizeRegion(region string) (string, error) {
+	if len(region) == 3 && isDigits(region) {
+		return region, nil
+	}
+
+	country, err := NewCountry(region)
+	if err != nil || len(region) != 2 {
+		return "", errors.ErrInvalidLocale
+	}
+
+	return country.String(), nil
+}
+
+// isLetters reports whether s consists of ASCII letters only
+func isLetters(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the string representation
+func (l Locale) String() string {
+	return string(l)
+}
+
+// IsValid checks if locale is a valid, normalized tag
+func (l Locale) IsValid() bool {
+	normalized, err := NewLocale(string(l))
+	return err == nil && normalized == l
+}
+
+// Language returns the ISO 639-1 language code, e.g. "th" for th-TH
+func (l Locale) Language() string {
+	language, _, _ := strings.Cut(string(l), "-")
+	return language
+}
+
+// Country returns the locale's country, or "" if it has no country region
+func (l Locale) Country() Country {
+	parts := strings.Split(string(l), "-")
+	if last := parts[len(parts)-1]; len(parts) > 1 && len(last) == 2 {
+		return Country(last)
+	}
+	return ""
+}
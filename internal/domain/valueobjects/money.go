@@ -1,147 +1,311 @@
-package valueobjects
 // Package valueobjects contains immutable value objects that encapsulate
 // domain concepts with validation and business logic
 package valueobjects
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return err == nil	_, err := NewCurrency(string(c))func (c Currency) IsValid() bool {// IsValid checks if currency is valid}	return string(c)func (c Currency) String() string {// String returns the string representation}	return Currency(code), nil		}		return "", errors.ErrInvalidCurrency	if !validCurrencies[code] {		}		"THB": true,		"JPY": true,		"GBP": true,		"EUR": true,		"USD": true,	validCurrencies := map[string]bool{	// Validate currency code		code = strings.ToUpper(strings.TrimSpace(code))func NewCurrency(code string) (Currency, error) {// NewCurrency validates and creates a Currency)	THB Currency = "THB"	JPY Currency = "JPY"	GBP Currency = "GBP"	EUR Currency = "EUR"	USD Currency = "USD"const (type Currency string// Currency represents a currency code (ISO 4217)}	return fmt.Sprintf("%s %.2f", m.Currency, m.Amount)func (m Money) String() string {// String returns string representation}	return m.Amount == other.Amount && m.Currency == other.Currencyfunc (m Money) Equals(other Money) bool {// Equals checks if two Money values are equal}	return m.Amount < other.Amount	}		return false	if m.Currency != other.Currency {func (m Money) IsLessThan(other Money) bool {// IsLessThan checks if m is less than other}	return m.Amount > other.Amount	}		return false	if m.Currency != other.Currency {func (m Money) IsGreaterThan(other Money) bool {// IsGreaterThan checks if m is greater than other}	}, nil		Currency: m.Currency,		Amount:   result,	return Money{		}		return Money{}, fmt.Errorf("subtraction would result in negative amount")	if result < 0 {	result := m.Amount - other.Amount		}			m.Currency, other.Currency)		return Money{}, fmt.Errorf("cannot subtract different currencies: %s and %s", 	if m.Currency != other.Currency {func (m Money) Subtract(other Money) (Money, error) {// Subtract subtracts other from m (must have same currency)}	}, nil		Currency: m.Currency,		Amount:   m.Amount + other.Amount,	return Money{		}			m.Currency, other.Currency)		return Money{}, fmt.Errorf("cannot add different currencies: %s and %s", 	if m.Currency != other.Currency {func (m Money) Add(other Money) (Money, error) {// Add adds two Money values (must have same currency)}	}, nil		Currency: curr,		Amount:   amount,	return Money{		}		return Money{}, err	if err != nil {	curr, err := NewCurrency(currency)	// Validate currency		}		return Money{}, errors.ErrAmountAboveMaximum	if amount > 100000.00 {	// Business Rule: Maximum transaction amount		}		return Money{}, errors.ErrAmountBelowMinimum	if amount < 0.01 {	// Business Rule: Minimum transaction amount		}		return Money{}, errors.NewValidationError("amount", "cannot be negative")	if amount < 0 {	// Validate amountfunc NewMoney(amount float64, currency string) (Money, error) {// NewMoney creates a new Money value object with validation}	Currency Currency	Amount   float64type Money struct {// This is a Value Object: immutable, validated, and domain-centric// Money represents a monetary amount with currency)	"Pay2Go/internal/domain/errors"
\ No newline at end of file
+	"Pay2Go/internal/domain/errors"
+)
+
+// Money represents a monetary amount with currency
+// This is a Value Object: immutable, validated, and domain-centric
+type Money struct {
+	// Amount is in the currency's minor unit (e.g. cents for USD, yen for JPY),
+	// so money is never subject to floating point rounding
+	Amount   int64
+	Currency Currency
+}
+
+// NewMoney creates a new Money value object with validation.
+// amount is in minor units, e.g. 10000 for USD 100.00. Maximum amounts depend
+// on the currency and partner, so they are checked with CheckLimit.
+func NewMoney(amount int64, currency string) (Money, error) {
+	// Validate amount
+	if amount < 0 {
+		return Money{}, errors.NewValidationError("amount", "cannot be negative")
+	}
+
+	// Business Rule: Minimum transaction amount is one minor unit
+	if amount < 1 {
+		return Money{}, errors.ErrAmountBelowMinimum
+	}
+
+	// Validate currency
+	curr, err := NewCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{
+		Amount:   amount,
+		Currency: curr,
+	}, nil
+}
+
+// ParseMoney creates Money from a decimal string in major units, e.g. "100.00"
+// USD or "1500" JPY, as accepted by the JSON API. Amounts with more decimal
+// places than the currency's minor unit are rejected rather than rounded.
+func ParseMoney(amount, currency string) (Money, error) {
+	money, err := parseMoneyUnchecked(amount, currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(money.Amount, money.Currency.String())
+}
+
+// parseMinorUnits converts a plain decimal string to minor units without going
+// through floating point
+func parseMinorUnits(amount string, exponent int) (int64, error) {
+	if strings.HasPrefix(amount, "-") {
+		return 0, errors.NewValidationError("amount", "cannot be negative")
+	}
+
+	whole, fraction, _ := strings.Cut(amount, ".")
+	if whole == "" || !isDigits(whole) || (fraction != "" && !isDigits(fraction)) ||
+		strings.HasSuffix(amount, ".") || len(whole) > 15 {
+		return 0, errors.NewValidationError("amount", "must be a decimal number such as \"100.00\"")
+	}
+
+	// Amounts cannot be finer than the currency's minor unit (e.g. no JPY fractions)
+	if len(strings.TrimRight(fraction, "0")) > exponent {
+		return 0, errors.NewValidationError("amount",
+			fmt.Sprintf("allows at most %d decimal places", exponent))
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	units, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, errors.NewValidationError("amount", "is out of range")
+	}
+
+	return units, nil
+}
+
+// isDigits reports whether s consists of ASCII digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add adds two Money values (must have same currency)
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("cannot add different currencies: %s and %s",
+			m.Currency, other.Currency)
+	}
+
+	return Money{
+		Amount:   m.Amount + other.Amount,
+		Currency: m.Currency,
+	}, nil
+}
+
+// Subtract subtracts other from m (must have same currency)
+func (m Money) Subtract(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("cannot subtract different currencies: %s and %s",
+			m.Currency, other.Currency)
+	}
+
+	result := m.Amount - other.Amount
+	if result < 0 {
+		return Money{}, fmt.Errorf("subtraction would result in negative amount")
+	}
+
+	return Money{
+		Amount:   result,
+		Currency: m.Currency,
+	}, nil
+}
+
+// Multiply scales m by factor, rounding halves to even (banker's rounding)
+// to the minor unit, so ties do not all round the same way
+func (m Money) Multiply(factor Ratio) (Money, error) {
+	return m.MultiplyWithPolicy(factor, RoundHalfEven)
+}
+
+// MultiplyWithPolicy scales m by factor, rounding to the minor unit with
+// policy. Amounts too large for the result are an error.
+func (m Money) MultiplyWithPolicy(factor Ratio, policy RoundingPolicy) (Money, error) {
+	units, err := policy.Scale(m.Amount, factor.Numerator(), factor.Denominator())
+	if err != nil {
+		return Money{}, err
+	}
+
+	return m.withAmount(units), nil
+}
+
+// PercentOf returns percent % of m (e.g. a ratio of 2.9 for a 2.9% fee),
+// rounding halves to even (banker's rounding) to the minor unit
+func (m Money) PercentOf(percent Ratio) (Money, error) {
+	return m.PercentOfWithPolicy(percent, RoundHalfEven)
+}
+
+// PercentOfWithPolicy returns percent % of m, rounding to the minor unit with policy
+func (m Money) PercentOfWithPolicy(percent Ratio, policy RoundingPolicy) (Money, error) {
+	denominator, err := mulExact(percent.Denominator(), 100)
+	if err != nil {
+		return Money{}, err
+	}
+	units, err := policy.Scale(m.Amount, percent.Numerator(), denominator)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return m.withAmount(units), nil
+}
+
+// Divide returns m / divisor rounded to the minor unit with policy, e.g. a
+// unit price. Use Allocate instead to split m into parts that add up to m.
+func (m Money) Divide(divisor int64, policy RoundingPolicy) (Money, error) {
+	if divisor < 1 {
+		return Money{}, errors.NewValidationError("divisor", "must be at least 1")
+	}
+
+	return m.withAmount(policy.Quotient(m.Amount, divisor)), nil
+}
+
+// Allocate splits m into n parts as equal as possible. Leftover minor units
+// (cents) go to the first parts, so the parts always add up to m.
+func (m Money) Allocate(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, errors.NewValidationError("parts", "must be at least 1")
+	}
+
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.AllocateByRatios(ratios)
+}
+
+// AllocateByRatios splits m in proportion to ratios using the largest remainder
+// method: every part gets its rounded-down share, then the leftover minor units go
+// one each to the parts with the largest remainders (earlier parts win ties).
+// The parts always add up to m.
+func (m Money) AllocateByRatios(ratios []int) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.NewValidationError("ratios", "cannot be empty")
+	}
+
+	var total int64
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, errors.NewValidationError("ratios", "cannot be negative")
+		}
+		total += int64(ratio)
+	}
+	if total == 0 {
+		return nil, errors.NewValidationError("ratios", "must not all be zero")
+	}
+
+	units := m.Amount
+	shares := make([]int64, len(ratios))
+	remainders := make([]int64, len(ratios))
+	leftover := units
+	for i, ratio := range ratios {
+		shares[i] = units * int64(ratio) / total
+		remainders[i] = units * int64(ratio) % total
+		leftover -= shares[i]
+	}
+
+	// Hand out leftover units by largest remainder; leftover < len(ratios)
+	for ; leftover > 0; leftover-- {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		shares[largest]++
+		remainders[largest] = -1
+	}
+
+	parts := make([]Money, len(shares))
+	for i, share := range shares {
+		parts[i] = m.withAmount(share)
+	}
+
+	return parts, nil
+}
+
+// withAmount returns a Money of the same currency holding units minor units
+func (m Money) withAmount(units int64) Money {
+	return Money{
+		Amount:   units,
+		Currency: m.Currency,
+	}
+}
+
+// pow10 returns 10^n for small non-negative n
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
+
+// IsGreaterThan checks if m is greater than other
+func (m Money) IsGreaterThan(other Money) bool {
+	if m.Currency != other.Currency {
+		return false
+	}
+	return m.Amount > other.Amount
+}
+
+// IsLessThan checks if m is less than other
+func (m Money) IsLessThan(other Money) bool {
+	if m.Currency != other.Currency {
+		return false
+	}
+	return m.Amount < other.Amount
+}
+
+// Equals checks if two Money values are equal
+func (m Money) Equals(other Money) bool {
+	return m.Amount == other.Amount && m.Currency == other.Currency
+}
+
+// Decimal returns the amount in major units as a decimal string, e.g. "100.00"
+// for 10000 USD cents or "1500" for 1500 JPY
+func (m Money) Decimal() string {
+	return FormatMinorUnits(m.Amount, m.Currency)
+}
+
+// FormatMinorUnits formats an amount in currency's minor units as a decimal
+// string in major units
+func FormatMinorUnits(amount int64, currency Currency) string {
+	exponent := currency.Exponent()
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// String returns string representation
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Currency, m.Decimal())
+}
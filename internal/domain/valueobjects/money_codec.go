@@ -0,0 +1,116 @@
+package valueobjects
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// moneyJSON is the JSON form of Money. The amount is a decimal string in major
+// units so clients never round it through a float.
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes m as {"amount": "100.00", "currency": "USD"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency.String()})
+}
+
+// UnmarshalJSON decodes {"amount": "100.00", "currency": "USD"}. Only the
+// format is checked; transaction limits are applied by NewMoney.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return errors.NewValidationError("amount", "must be an object with a decimal string amount and a currency")
+	}
+
+	money, err := parseMoneyUnchecked(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// MarshalText encodes m as "USD 100.00", e.g. for map keys and query parameters
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes "USD 100.00"
+func (m *Money) UnmarshalText(text []byte) error {
+	currency, amount, found := strings.Cut(strings.TrimSpace(string(text)), " ")
+	if !found {
+		return errors.NewValidationError("amount", "must look like \"USD 100.00\"")
+	}
+
+	money, err := parseMoneyUnchecked(amount, currency)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// parseMoneyUnchecked parses a decimal amount and currency without applying
+// the transaction minimum and maximum, so totals and zero balances decode too
+func parseMoneyUnchecked(amount, currency string) (Money, error) {
+	curr, err := NewCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	units, err := parseMinorUnits(strings.TrimSpace(amount), curr.Exponent())
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: units, Currency: curr}, nil
+}
+
+// Value implements driver.Valuer. Money is stored as an amount column in minor
+// units next to a currency column, so only the amount is written here; pass
+// m.Currency for the currency column.
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount, nil
+}
+
+// Scan implements sql.Scanner for an amount column in minor units. It leaves
+// Currency alone, so scan the currency column into &m.Currency:
+//
+//	row.Scan(&txn.Amount, &txn.Amount.Currency)
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		m.Amount = v
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case nil:
+		m.Amount = 0
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	return nil
+}
+
+// scanString reads an integer amount sent as text, e.g. the NUMERIC result of SUM()
+func (m *Money) scanString(s string) error {
+	amount, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into Money: %w", s, err)
+	}
+
+	m.Amount = amount
+	return nil
+}
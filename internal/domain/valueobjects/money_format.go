@@ -0,0 +1,144 @@
+package valueobjects
+
+import (
+	"strings"
+)
+
+// NumberFormat describes how a locale writes money amounts
+type NumberFormat struct {
+	Decimal     string // Decimal separator
+	Group       string // Thousands separator
+	SymbolAfter bool   // "1.234,50 €" rather than "€1,234.50"
+	SymbolSpace bool   // No-break space between symbol and number
+}
+
+// Separators used by the formats below
+const (
+	nbsp       = "\u00a0" // No-break space
+	narrowNbsp = "\u202f" // Narrow no-break space (French grouping)
+)
+
+// englishFormat is used for unknown locales
+var englishFormat = NumberFormat{Decimal: ".", Group: ","}
+
+// localeFormats maps BCP 47 language tags (lowercase), with or without a
+// region, to their number format. Region-specific entries win over the language.
+var localeFormats = map[string]NumberFormat{
+	"en":    englishFormat,
+	"th":    englishFormat,
+	"ja":    englishFormat,
+	"ko":    englishFormat,
+	"zh":    englishFormat,
+	"ms":    englishFormat,
+	"he":    englishFormat,
+	"de":    {Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true},
+	"es":    {Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true},
+	"it":    {Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true},
+	"da":    {Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true},
+	"el":    {Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true},
+	"tr":    {Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true},
+	"id":    {Decimal: ",", Group: "."},
+	"vi":    {Decimal: ",", Group: ".", SymbolAfter: true, SymbolSpace: true},
+	"nl":    {Decimal: ",", Group: ".", SymbolSpace: true},
+	"pt":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"fr":    {Decimal: ",", Group: narrowNbsp, SymbolAfter: true, SymbolSpace: true},
+	"ru":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"pl":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"cs":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"sv":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"nb":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"fi":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"uk":    {Decimal: ",", Group: nbsp, SymbolAfter: true, SymbolSpace: true},
+	"pt-br": {Decimal: ",", Group: ".", SymbolSpace: true},
+	"de-ch": {Decimal: ".", Group: "’", SymbolSpace: true},
+	"fr-ch": {Decimal: ".", Group: narrowNbsp, SymbolAfter: true, SymbolSpace: true},
+}
+
+// Localize formats m for display in locale (a BCP 47 tag such as "en-US",
+// "th-TH" or "de-DE"), e.g. "$1,234.50", "฿1,234.50" or "1.234,50 €".
+// Unknown locales use English conventions. String remains the plain,
+// locale-independent form for logs.
+func (m Money) Localize(locale string) string {
+	return lookupNumberFormat(locale).WithSymbol(m.Decimal(), m.Currency.Symbol())
+}
+
+// NumberFormat returns how the locale writes money amounts
+func (l Locale) NumberFormat() NumberFormat {
+	return lookupNumberFormat(string(l))
+}
+
+// Number formats a plain decimal string such as "-1234.50" with the format's
+// separators, e.g. "-1.234,50"
+func (f NumberFormat) Number(decimal string) string {
+	sign := ""
+	if strings.HasPrefix(decimal, "-") {
+		sign, decimal = "-", decimal[1:]
+	}
+
+	whole, fraction, hasFraction := strings.Cut(decimal, ".")
+	number := groupThousands(whole, f.Group)
+	if hasFraction {
+		number += f.Decimal + fraction
+	}
+
+	return sign + number
+}
+
+// WithSymbol formats a plain decimal string with symbol placed the way the
+// format expects, e.g. "-€1,234.50" or "-1.234,50 €"
+func (f NumberFormat) WithSymbol(decimal, symbol string) string {
+	number := f.Number(decimal)
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+
+	space := ""
+	if f.SymbolSpace {
+		space = nbsp
+	}
+
+	if f.SymbolAfter {
+		return sign + number + space + symbol
+	}
+	return sign + symbol + space + number
+}
+
+// lookupNumberFormat resolves a BCP 47 tag to a number format, trying
+// language-region before language alone
+func lookupNumberFormat(locale string) NumberFormat {
+	tag := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+
+	language, rest, _ := strings.Cut(tag, "-")
+	if region, _, _ := strings.Cut(rest, "-"); region != "" {
+		if format, ok := localeFormats[language+"-"+region]; ok {
+			return format
+		}
+	}
+
+	if format, ok := localeFormats[language]; ok {
+		return format
+	}
+	return englishFormat
+}
+
+// groupThousands inserts sep between every three digits of whole
+func groupThousands(whole, sep string) string {
+	if len(whole) <= 3 {
+		return whole
+	}
+
+	var b strings.Builder
+	first := len(whole) % 3
+	if first > 0 {
+		b.WriteString(whole[:first])
+	}
+	for i := first; i < len(whole); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(whole[i : i+3])
+	}
+
+	return b.String()
+}
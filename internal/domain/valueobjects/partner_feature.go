@@ -0,0 +1,88 @@
+package valueobjects
+
+import (
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// PartnerFeature names a capability that is rolled out to partners gradually
+type PartnerFeature string
+
+const (
+	// FeaturePartialCapture allows capturing less than the authorized amount
+	FeaturePartialCapture PartnerFeature = "enable_partial_capture"
+	// FeatureCrypto allows creating crypto payments
+	FeatureCrypto PartnerFeature = "enable_crypto"
+	// FeatureBulkRefunds allows refunding transactions in batches
+	FeatureBulkRefunds PartnerFeature = "enable_bulk_refunds"
+	// FeatureReceiptEmails emails customers a receipt of completed payments
+	FeatureReceiptEmails PartnerFeature = "enable_receipt_emails"
+	// FeatureRefundEmails emails customers when their refund completed
+	FeatureRefundEmails PartnerFeature = "enable_refund_emails"
+	// FeatureWebhookAlertEmails emails the partner when Pay2Go stops
+	// retrying an event its webhook endpoint did not accept
+	FeatureWebhookAlertEmails PartnerFeature = "enable_webhook_alert_emails"
+	// FeatureSMSConfirmations texts customers a confirmation of completed
+	// payments
+	FeatureSMSConfirmations PartnerFeature = "enable_sms_confirmations"
+	// FeatureSMSVerification allows texting customers a one-time code to
+	// confirm a payment, where card authentication is not available
+	FeatureSMSVerification PartnerFeature = "enable_sms_verification"
+)
+
+// defaultFeatures holds features that are on unless a partner turns them off.
+// Bulk refunds predate flags, so existing partners keep them. Emails and
+// text messages to customers carry the partner's name, so partners opt in
+// to them.
+var defaultFeatures = map[PartnerFeature]bool{
+	FeaturePartialCapture:     false,
+	FeatureCrypto:             false,
+	FeatureBulkRefunds:        true,
+	FeatureReceiptEmails:      false,
+	FeatureRefundEmails:       false,
+	FeatureWebhookAlertEmails: true,
+	FeatureSMSConfirmations:   false,
+	FeatureSMSVerification:    false,
+}
+
+// NewPartnerFeature validates and creates a PartnerFeature
+func NewPartnerFeature(feature string) (PartnerFeature, error) {
+	feature = strings.ToLower(strings.TrimSpace(feature))
+
+	if _, ok := defaultFeatures[PartnerFeature(feature)]; !ok {
+		return "", errors.NewValidationError("feature", "must be one of enable_partial_capture, enable_crypto, enable_bulk_refunds, enable_receipt_emails, enable_refund_emails, enable_webhook_alert_emails, enable_sms_confirmations, enable_sms_verification")
+	}
+
+	return PartnerFeature(feature), nil
+}
+
+// PartnerFeatures returns every known feature
+func PartnerFeatures() []PartnerFeature {
+	return []PartnerFeature{
+		FeaturePartialCapture,
+		FeatureCrypto,
+		FeatureBulkRefunds,
+		FeatureReceiptEmails,
+		FeatureRefundEmails,
+		FeatureWebhookAlertEmails,
+		FeatureSMSConfirmations,
+		FeatureSMSVerification,
+	}
+}
+
+// EnabledByDefault reports whether partners without an explicit flag get the feature
+func (f PartnerFeature) EnabledByDefault() bool {
+	return defaultFeatures[f]
+}
+
+// String returns the string representation
+func (f PartnerFeature) String() string {
+	return string(f)
+}
+
+// IsValid checks if feature is known
+func (f PartnerFeature) IsValid() bool {
+	_, ok := defaultFeatures[f]
+	return ok
+}
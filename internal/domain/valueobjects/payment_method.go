@@ -1,5 +1,4 @@
 package valueobjects
-package valueobjects
 
 import (
 	"strings"
@@ -8,80 +7,86 @@ import (
 )
 
 // PaymentMethod represents the method of payment
+type PaymentMethod string
 
+const (
+	PaymentMethodCard         PaymentMethod = "card"
+	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
+	PaymentMethodEWallet      PaymentMethod = "e_wallet"
+	PaymentMethodCrypto       PaymentMethod = "crypto"
+)
 
+// NewPaymentMethod validates and creates a PaymentMethod
+func NewPaymentMethod(method string) (PaymentMethod, error) {
+	method = strings.ToLower(strings.TrimSpace(method))
+
+	validMethods := map[string]bool{
+		"card":          true,
+		"bank_transfer": true,
+		"e_wallet":      true,
+		"crypto":        true,
+	}
+
+	if !validMethods[method] {
+		return "", errors.ErrInvalidPaymentMethod
+	}
+
+	return PaymentMethod(method), nil
+}
+
+// String returns the string representation
+func (pm PaymentMethod) String() string {
+	return string(pm)
+}
+
+// IsValid checks if payment method is valid
+func (pm PaymentMethod) IsValid() bool {
+	_, err := NewPaymentMethod(string(pm))
+	return err == nil
+}
+
+// PaymentProvider represents external payment gateway providers
+type PaymentProvider string
+
+const (
+	ProviderStripe PaymentProvider = "stripe"
+	ProviderPayPal PaymentProvider = "paypal"
+	ProviderAdyen  PaymentProvider = "adyen"
+	ProviderManual PaymentProvider = "manual"
+)
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return err == nil	_, err := NewPaymentProvider(string(pp))func (pp PaymentProvider) IsValid() bool {// IsValid checks if payment provider is valid}	return string(pp)func (pp PaymentProvider) String() string {// String returns the string representation}	return PaymentProvider(provider), nil		}		return "", errors.NewValidationError("provider", "invalid payment provider")	if !validProviders[provider] {		}		"manual": true,		"adyen":  true,		"paypal": true,		"stripe": true,	validProviders := map[string]bool{		provider = strings.ToLower(strings.TrimSpace(provider))func NewPaymentProvider(provider string) (PaymentProvider, error) {// NewPaymentProvider validates and creates a PaymentProvider)	ProviderManual PaymentProvider = "manual"	ProviderAdyen  PaymentProvider = "adyen"	ProviderPayPal PaymentProvider = "paypal"	ProviderStripe PaymentProvider = "stripe"const (type PaymentProvider string// PaymentProvider represents external payment gateway providers}	return err == nil	_, err := NewPaymentMethod(string(pm))func (pm PaymentMethod) IsValid() bool {// IsValid checks if payment method is valid}	return string(pm)func (pm PaymentMethod) String() string {// String returns the string representation}	return PaymentMethod(method), nil		}		return "", errors.ErrInvalidPaymentMethod	if !validMethods[method] {		}		"crypto":        true,		"e_wallet":      true,		"bank_transfer": true,		"card":          true,	validMethods := map[string]bool{		method = strings.ToLower(strings.TrimSpace(method))func NewPaymentMethod(method string) (PaymentMethod, error) {// NewPaymentMethod validates and creates a PaymentMethod)	PaymentMethodCrypto       PaymentMethod = "crypto"	PaymentMethodEWallet      PaymentMethod = "e_wallet"	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"	PaymentMethodCard         PaymentMethod = "card"const (type PaymentMethod string
\ No newline at end of file
+// NewPaymentProvider validates and creates a PaymentProvider
+func NewPaymentProvider(provider string) (PaymentProvider, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+
+	validProviders := map[string]bool{
+		"stripe": true,
+		"paypal": true,
+		"adyen":  true,
+		"manual": true,
+	}
+
+	if !validProviders[provider] {
+		return "", errors.NewValidationError("provider", "invalid payment provider")
+	}
+
+	return PaymentProvider(provider), nil
+}
+
+// String returns the string representation
+func (pp PaymentProvider) String() string {
+	return string(pp)
+}
+
+// IsValid checks if payment provider is valid
+func (pp PaymentProvider) IsValid() bool {
+	_, err := NewPaymentProvider(string(pp))
+	return err == nil
+}
+
+// RequiresBillingCountry reports whether the provider needs the payer's billing
+// country for method. Adyen picks the local bank transfer scheme or wallet by
+// country, so it cannot process those without one.
+func (pp PaymentProvider) RequiresBillingCountry(method PaymentMethod) bool {
+	return pp == ProviderAdyen && (method == PaymentMethodBankTransfer || method == PaymentMethodEWallet)
+}
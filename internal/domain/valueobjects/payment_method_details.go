@@ -0,0 +1,290 @@
+package valueobjects
+
+import (
+	"strings"
+	"time"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// CardBrand represents a card network
+type CardBrand string
+
+const (
+	CardBrandVisa       CardBrand = "visa"
+	CardBrandMastercard CardBrand = "mastercard"
+	CardBrandAmex       CardBrand = "amex"
+	CardBrandDiscover   CardBrand = "discover"
+	CardBrandJCB        CardBrand = "jcb"
+	CardBrandUnionPay   CardBrand = "unionpay"
+	CardBrandDiners     CardBrand = "diners"
+	CardBrandUnknown    CardBrand = "unknown"
+)
+
+// NewCardBrand validates and creates a CardBrand
+func NewCardBrand(brand string) (CardBrand, error) {
+	brand = strings.ToLower(strings.TrimSpace(brand))
+
+	validBrands := map[string]bool{
+		"visa":       true,
+		"mastercard": true,
+		"amex":       true,
+		"discover":   true,
+		"jcb":        true,
+		"unionpay":   true,
+		"diners":     true,
+		"unknown":    true,
+	}
+
+	if !validBrands[brand] {
+		return "", errors.NewValidationError("card.brand", "invalid card brand")
+	}
+
+	return CardBrand(brand), nil
+}
+
+// String returns the string representation
+func (b CardBrand) String() string {
+	return string(b)
+}
+
+// CardDetails describes the card a payment was made with. It never holds the
+// full card number or CVC, only what is safe to show and report on.
+type CardDetails struct {
+	Brand    CardBrand `json:"brand"`
+	Last4    string    `json:"last4"`
+	ExpMonth int       `json:"exp_month"`
+	ExpYear  int       `json:"exp_year"`
+	// Fingerprint is the provider's identifier for the card number, the same
+	// for every payment with that card
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Issuer details derived from the BIN, kept for reporting and routing
+	BIN           BIN         `json:"bin,omitempty"`
+	IssuerCountry Country     `json:"issuer_country,omitempty"`
+	Funding       CardFunding `json:"funding,omitempty"`
+}
+
+// NewCardDetails validates and creates CardDetails
+func NewCardDetails(brand, last4 string, expMonth, expYear int, fingerprint string) (*CardDetails, error) {
+	cardBrand, err := NewCardBrand(brand)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(last4) != 4 || !isDigits(last4) {
+		return nil, errors.NewValidationError("card.last4", "must be the last 4 digits of the card number")
+	}
+
+	if expMonth < 1 || expMonth > 12 {
+		return nil, errors.NewValidationError("card.exp_month", "must be between 1 and 12")
+	}
+
+	if expYear < 2000 || expYear > 2099 {
+		return nil, errors.NewValidationError("card.exp_year", "must be a four-digit year")
+	}
+
+	if len(fingerprint) > 64 {
+		return nil, errors.NewValidationError("card.fingerprint", "must be at most 64 characters")
+	}
+
+	return &CardDetails{
+		Brand:       cardBrand,
+		Last4:       last4,
+		ExpMonth:    expMonth,
+		ExpYear:     expYear,
+		Fingerprint: fingerprint,
+	}, nil
+}
+
+// IsExpired reports whether the card expired before at; cards are valid
+// through the last day of their expiry month
+func (c CardDetails) IsExpired(at time.Time) bool {
+	firstInvalid := time.Date(c.ExpYear, time.Month(c.ExpMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !at.Before(firstInvalid)
+}
+
+// WithBINInfo returns a copy of the card with its BIN and the issuer details
+// looked up for it. A brand given by the provider is kept; an unknown brand
+// is taken from the BIN.
+func (c CardDetails) WithBINInfo(bin BIN, info BINInfo) *CardDetails {
+	c.BIN = bin
+	c.IssuerCountry = info.IssuerCountry
+	c.Funding = info.Funding
+	if c.Brand == CardBrandUnknown {
+		c.Brand = info.Brand
+	}
+	return &c
+}
+
+// BankAccountHolderType represents who owns a bank account
+type BankAccountHolderType string
+
+const (
+	BankAccountHolderIndividual BankAccountHolderType = "individual"
+	BankAccountHolderCompany    BankAccountHolderType = "company"
+)
+
+// BankAccountDetails describes the bank account a transfer came from. Only
+// the last 4 digits of the account number are kept.
+type BankAccountDetails struct {
+	BankName   string                `json:"bank_name,omitempty"`
+	BankCode   string                `json:"bank_code,omitempty"` // Routing number, sort code or BIC
+	Last4      string                `json:"last4"`
+	HolderType BankAccountHolderType `json:"holder_type,omitempty"`
+}
+
+// NewBankAccountDetails validates and creates BankAccountDetails
+func NewBankAccountDetails(bankName, bankCode, last4, holderType string) (*BankAccountDetails, error) {
+	if len(last4) != 4 || !isDigits(last4) {
+		return nil, errors.NewValidationError("bank_account.last4", "must be the last 4 digits of the account number")
+	}
+
+	if len(bankName) > 255 {
+		return nil, errors.NewValidationError("bank_account.bank_name", "must be at most 255 characters")
+	}
+
+	bankCode = strings.ToUpper(strings.TrimSpace(bankCode))
+	if len(bankCode) > 34 {
+		return nil, errors.NewValidationError("bank_account.bank_code", "must be at most 34 characters")
+	}
+
+	holder := BankAccountHolderType(strings.ToLower(strings.TrimSpace(holderType)))
+	if holder != "" && holder != BankAccountHolderIndividual && holder != BankAccountHolderCompany {
+		return nil, errors.NewValidationError("bank_account.holder_type", "must be individual or company")
+	}
+
+	return &BankAccountDetails{
+		BankName:   bankName,
+		BankCode:   bankCode,
+		Last4:      last4,
+		HolderType: holder,
+	}, nil
+}
+
+// WalletType represents an e-wallet
+type WalletType string
+
+const (
+	WalletApplePay  WalletType = "apple_pay"
+	WalletGooglePay WalletType = "google_pay"
+	WalletPayPal    WalletType = "paypal"
+	WalletAlipay    WalletType = "alipay"
+	WalletWeChatPay WalletType = "wechat_pay"
+	WalletPromptPay WalletType = "promptpay"
+	WalletTrueMoney WalletType = "truemoney"
+	WalletGrabPay   WalletType = "grabpay"
+)
+
+// NewWalletType validates and creates a WalletType
+func NewWalletType(wallet string) (WalletType, error) {
+	wallet = strings.ToLower(strings.TrimSpace(wallet))
+
+	validWallets := map[string]bool{
+		"apple_pay":  true,
+		"google_pay": true,
+		"paypal":     true,
+		"alipay":     true,
+		"wechat_pay": true,
+		"promptpay":  true,
+		"truemoney":  true,
+		"grabpay":    true,
+	}
+
+	if !validWallets[wallet] {
+		return "", errors.NewValidationError("wallet.type", "invalid wallet type")
+	}
+
+	return WalletType(wallet), nil
+}
+
+// String returns the string representation
+func (w WalletType) String() string {
+	return string(w)
+}
+
+// WalletDetails describes the e-wallet a payment was made with
+type WalletDetails struct {
+	Type WalletType `json:"type"`
+	// AccountID is the wallet's identifier for the payer's account (e.g. a
+	// PayPal payer ID), never a password or token that can be charged
+	AccountID string `json:"account_id,omitempty"`
+}
+
+// NewWalletDetails validates and creates WalletDetails
+func NewWalletDetails(walletType, accountID string) (*WalletDetails, error) {
+	wallet, err := NewWalletType(walletType)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accountID) > 128 {
+		return nil, errors.NewValidationError("wallet.account_id", "must be at most 128 characters")
+	}
+
+	return &WalletDetails{
+		Type:      wallet,
+		AccountID: accountID,
+	}, nil
+}
+
+// PaymentMethodDetails describes how the payer actually paid. Exactly one of
+// the fields is set, matching the transaction's payment method.
+type PaymentMethodDetails struct {
+	Card        *CardDetails        `json:"card,omitempty"`
+	BankAccount *BankAccountDetails `json:"bank_account,omitempty"`
+	Wallet      *WalletDetails      `json:"wallet,omitempty"`
+}
+
+// ValidateFor checks that the details describe a payment made with method
+func (d PaymentMethodDetails) ValidateFor(method PaymentMethod) error {
+	set := 0
+	for _, present := range []bool{d.Card != nil, d.BankAccount != nil, d.Wallet != nil} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.NewValidationError("payment_method_details", "must contain exactly one of card, bank_account or wallet")
+	}
+
+	switch method {
+	case PaymentMethodCard:
+		if d.Card != nil {
+			return nil
+		}
+	case PaymentMethodBankTransfer:
+		if d.BankAccount != nil {
+			return nil
+		}
+	case PaymentMethodEWallet:
+		if d.Wallet != nil {
+			return nil
+		}
+	}
+
+	return errors.NewValidationError("payment_method_details", "do not match payment method "+method.String())
+}
+
+// Anonymized returns a copy of the details without anything that identifies
+// the payer's card, account or wallet: card numbers, expiry and fingerprint,
+// account numbers and wallet accounts. The brand, funding, issuer country,
+// bank and wallet type are kept for reporting.
+func (d PaymentMethodDetails) Anonymized() *PaymentMethodDetails {
+	if d.Card != nil {
+		card := *d.Card
+		card.Last4, card.ExpMonth, card.ExpYear, card.Fingerprint, card.BIN = "", 0, 0, "", ""
+		d.Card = &card
+	}
+	if d.BankAccount != nil {
+		account := *d.BankAccount
+		account.Last4 = ""
+		d.BankAccount = &account
+	}
+	if d.Wallet != nil {
+		wallet := *d.Wallet
+		wallet.AccountID = ""
+		d.Wallet = &wallet
+	}
+	return &d
+}
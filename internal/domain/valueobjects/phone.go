@@ -0,0 +1,76 @@
+package valueobjects
+
+import (
+	"regexp"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+var (
+	// e164 matches a phone number in E.164 form, such as +66812345678
+	e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	// alphanumericSenderID matches a sender name such as "Acme Shop",
+	// which carriers limit to 11 characters
+	alphanumericSenderID = regexp.MustCompile(`^[A-Za-z0-9 ]{1,11}$`)
+	// messagingServiceSID matches the SID of a Twilio Messaging Service,
+	// which picks the number from its own pool
+	messagingServiceSID = regexp.MustCompile(`^MG[0-9a-fA-F]{32}$`)
+)
+
+// PhoneNumber is a phone number in E.164 form, such as +66812345678
+type PhoneNumber string
+
+// NewPhoneNumber parses a phone number written with its country code, such
+// as "+66 81-234-5678" or "0066 81 234 5678", into E.164 form
+func NewPhoneNumber(raw string) (PhoneNumber, error) {
+	number := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, raw)
+	if strings.HasPrefix(number, "00") {
+		number = "+" + number[2:]
+	}
+	if !e164.MatchString(number) {
+		return "", errors.ErrInvalidPhoneNumber
+	}
+	return PhoneNumber(number), nil
+}
+
+// String returns the number in E.164 form
+func (p PhoneNumber) String() string {
+	return string(p)
+}
+
+// SMSSenderID is who a partner's text messages come from: a phone number in
+// E.164 form, an alphanumeric sender name, or a Twilio Messaging Service SID.
+// Empty uses the platform's sender.
+type SMSSenderID string
+
+// NewSMSSenderID validates and creates an SMSSenderID
+func NewSMSSenderID(raw string) (SMSSenderID, error) {
+	sender := strings.TrimSpace(raw)
+	switch {
+	case sender == "":
+		return "", nil
+	case strings.HasPrefix(sender, "+"):
+		number, err := NewPhoneNumber(sender)
+		if err != nil {
+			return "", errors.NewValidationError("sms_sender", "must be a phone number in E.164 form")
+		}
+		return SMSSenderID(number), nil
+	case messagingServiceSID.MatchString(sender):
+		return SMSSenderID(sender), nil
+	case alphanumericSenderID.MatchString(sender) && strings.ContainsAny(strings.ToLower(sender), "abcdefghijklmnopqrstuvwxyz"):
+		return SMSSenderID(sender), nil
+	}
+	return "", errors.NewValidationError("sms_sender", "must be a phone number, a sender name of up to 11 letters, digits and spaces, or a Messaging Service SID")
+}
+
+// String returns the sender as configured
+func (s SMSSenderID) String() string {
+	return string(s)
+}
@@ -0,0 +1,96 @@
+package valueobjects
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// MaxPricingTiers is how many tiers a pricing may have
+const MaxPricingTiers = 10
+
+// PricingTier is the fee of a partner's payments while the month's count
+// of paid payments is at most UpTo
+type PricingTier struct {
+	Name string `json:"name"`
+	// UpTo is the last payment of the month the tier covers, counting from
+	// the first; 0 covers every payment after the tier before
+	UpTo    int     `json:"up_to"`
+	Percent float64 `json:"percent"`
+	Fixed   int64   `json:"fixed"` // Minor units of the payment's currency
+}
+
+// Fee is the fee schedule of the tier
+func (t PricingTier) Fee() FeeSchedule {
+	return FeeSchedule{Percent: t.Percent, Fixed: t.Fixed}
+}
+
+// Pricing is what a partner is charged: graduated tiers by the number of
+// payments in a month, e.g. 2.9% + 30 for the first 1,000 and 2.5% + 25
+// after, and a fee per chargeback
+type Pricing struct {
+	Tiers         []PricingTier `json:"tiers"`
+	ChargebackFee int64         `json:"chargeback_fee"` // Minor units of the chargeback's currency
+}
+
+// NewPricing validates and creates a Pricing. Tiers are in order, each
+// covering more payments than the one before, and the last covers the rest.
+func NewPricing(tiers []PricingTier, chargebackFee int64) (Pricing, error) {
+	if len(tiers) == 0 || len(tiers) > MaxPricingTiers {
+		return Pricing{}, errors.NewValidationError("tiers", fmt.Sprintf("must have between 1 and %d tiers", MaxPricingTiers))
+	}
+	if chargebackFee < 0 {
+		return Pricing{}, errors.NewValidationError("chargeback_fee", "cannot be negative")
+	}
+
+	pricing := Pricing{Tiers: make([]PricingTier, len(tiers)), ChargebackFee: chargebackFee}
+	names := make(map[string]bool, len(tiers))
+	for i, tier := range tiers {
+		field := fmt.Sprintf("tiers[%d]", i)
+		tier.Name = strings.TrimSpace(tier.Name)
+		if tier.Name == "" || len(tier.Name) > 50 {
+			return Pricing{}, errors.NewValidationError(field+".name", "must be between 1 and 50 characters")
+		}
+		if names[tier.Name] {
+			return Pricing{}, errors.NewValidationError(field+".name", "is used by another tier")
+		}
+		names[tier.Name] = true
+
+		last := i == len(tiers)-1
+		switch {
+		case last && tier.UpTo != 0:
+			return Pricing{}, errors.NewValidationError(field+".up_to", "must be 0 on the last tier, which covers the rest")
+		case !last && tier.UpTo <= 0:
+			return Pricing{}, errors.NewValidationError(field+".up_to", "must be positive on every tier but the last")
+		case !last && i > 0 && tier.UpTo <= tiers[i-1].UpTo:
+			return Pricing{}, errors.NewValidationError(field+".up_to", "must be above the tier before")
+		}
+
+		if tier.Percent < 0 || tier.Percent > 100 || math.IsNaN(tier.Percent) {
+			return Pricing{}, errors.NewValidationError(field+".percent", "must be between 0 and 100")
+		}
+		if tier.Fixed < 0 {
+			return Pricing{}, errors.NewValidationError(field+".fixed", "cannot be negative")
+		}
+		pricing.Tiers[i] = tier
+	}
+	return pricing, nil
+}
+
+// TierOf returns the tier of the nth payment of a month, counting from 1
+func (p Pricing) TierOf(n int) PricingTier {
+	for _, tier := range p.Tiers {
+		if tier.UpTo == 0 || n <= tier.UpTo {
+			return tier
+		}
+	}
+	return p.Tiers[len(p.Tiers)-1]
+}
+
+// Clone returns a copy of the pricing sharing nothing with it
+func (p Pricing) Clone() Pricing {
+	p.Tiers = append([]PricingTier(nil), p.Tiers...)
+	return p
+}
@@ -0,0 +1,144 @@
+package valueobjects
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// maxRatioDigits is how many significant digits a decimal ratio may have,
+// and maxRatioDecimals how many decimal places, so its numerator and
+// denominator fit in an int64
+const (
+	maxRatioDigits   = 15
+	maxRatioDecimals = 18
+)
+
+// Ratio is an exact non-negative rational number, such as a factor of 3/2,
+// a fee of 2.9 percent or an exchange rate of 0.0067. Money is scaled by a
+// Ratio with integer arithmetic only, so binary floating point never decides
+// a rounding. The zero value is zero.
+type Ratio struct {
+	num, den int64
+}
+
+// NewRatio creates the ratio numerator / denominator, in lowest terms
+func NewRatio(numerator, denominator int64) (Ratio, error) {
+	if numerator < 0 {
+		return Ratio{}, errors.NewValidationError("ratio", "cannot be negative")
+	}
+	if denominator < 1 {
+		return Ratio{}, errors.NewValidationError("ratio", "denominator must be at least 1")
+	}
+
+	divisor := gcd(numerator, denominator)
+	return Ratio{num: numerator / divisor, den: denominator / divisor}, nil
+}
+
+// BasisPoints returns the ratio of bps hundredths of a percent, e.g. 290 for
+// 2.9%, as a fraction of one
+func BasisPoints(bps int64) (Ratio, error) {
+	return NewRatio(bps, 10000)
+}
+
+// ParseRatio creates a ratio from a plain decimal string such as "2.9" or
+// "0.0067", exactly
+func ParseRatio(decimal string) (Ratio, error) {
+	whole, fraction, _ := strings.Cut(decimal, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) || strings.HasSuffix(decimal, ".") {
+		return Ratio{}, errors.NewValidationError("ratio", "must be a decimal number such as \"2.9\"")
+	}
+	digits := strings.TrimLeft(whole+fraction, "0")
+	if len(digits) > maxRatioDigits || len(fraction) > maxRatioDecimals {
+		return Ratio{}, errors.NewValidationError("ratio",
+			fmt.Sprintf("allows at most %d significant digits and %d decimal places", maxRatioDigits, maxRatioDecimals))
+	}
+
+	numerator, err := strconv.ParseInt("0"+digits, 10, 64)
+	if err != nil {
+		return Ratio{}, errors.NewValidationError("ratio", "is out of range")
+	}
+	return NewRatio(numerator, pow10(len(fraction)))
+}
+
+// RatioOf creates a ratio from a number configured as a float, such as the
+// percent of a fee schedule, from its shortest decimal form: 2.9 is exactly
+// 29/10, not the binary fraction closest to it. Numbers with more than 15
+// significant digits, such as the inverse of an exchange rate, are rounded
+// to 15.
+func RatioOf(value float64) (Ratio, error) {
+	if value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return Ratio{}, errors.NewValidationError("ratio", "must be a non-negative number")
+	}
+
+	// d.ddde±xx, from which the ratio is digits * 10^(exponent - decimals)
+	decimal := strconv.FormatFloat(value, 'e', -1, 64)
+	if mantissa, _, _ := strings.Cut(decimal, "e"); len(mantissa) > maxRatioDigits+1 {
+		decimal = strconv.FormatFloat(value, 'e', maxRatioDigits-1, 64)
+	}
+	mantissa, exponentText, _ := strings.Cut(decimal, "e")
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	exponent, _ := strconv.Atoi(exponentText)
+
+	digits, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Ratio{}, errors.NewValidationError("ratio", "is out of range")
+	}
+	shift := exponent - len(fraction)
+	if shift > maxRatioDecimals || -shift > maxRatioDecimals {
+		return Ratio{}, errors.NewValidationError("ratio", "is out of range")
+	}
+	if shift < 0 {
+		return NewRatio(digits, pow10(-shift))
+	}
+	numerator, err := mulExact(digits, pow10(shift))
+	if err != nil {
+		return Ratio{}, err
+	}
+	return NewRatio(numerator, 1)
+}
+
+// Numerator returns the numerator, in lowest terms
+func (r Ratio) Numerator() int64 {
+	return r.num
+}
+
+// Denominator returns the denominator, in lowest terms
+func (r Ratio) Denominator() int64 {
+	if r.den == 0 {
+		return 1
+	}
+	return r.den
+}
+
+// String returns the ratio as a fraction, e.g. "29/10"
+func (r Ratio) String() string {
+	return fmt.Sprintf("%d/%d", r.num, r.Denominator())
+}
+
+// mulExact returns a * b, or an error when it overflows an int64. Both must
+// be non-negative.
+func mulExact(a, b int64) (int64, error) {
+	high, low := bits.Mul64(uint64(a), uint64(b))
+	if high != 0 || low > math.MaxInt64 {
+		return 0, errors.NewValidationError("amount", "is out of range")
+	}
+	return int64(low), nil
+}
+
+// gcd returns the greatest common divisor of a and b, at least 1
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return 1
+	}
+	return a
+}
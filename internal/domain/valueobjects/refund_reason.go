@@ -0,0 +1,71 @@
+package valueobjects
+
+import (
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// RefundReasonCode classifies why a refund was issued
+type RefundReasonCode string
+
+const (
+	RefundReasonRequestedByCustomer RefundReasonCode = "requested_by_customer"
+	RefundReasonDuplicate           RefundReasonCode = "duplicate"
+	RefundReasonFraudulent          RefundReasonCode = "fraudulent"
+	RefundReasonOther               RefundReasonCode = "other"
+)
+
+// maxRefundReasonNoteLength matches the refunds.reason column size
+const maxRefundReasonNoteLength = 255
+
+// RefundReason is a reason code with an optional free-text note
+type RefundReason struct {
+	Code RefundReasonCode
+	Note string
+}
+
+// NewRefundReason validates and creates a RefundReason
+func NewRefundReason(code, note string) (RefundReason, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	note = strings.TrimSpace(note)
+
+	validCodes := map[string]bool{
+		"requested_by_customer": true,
+		"duplicate":             true,
+		"fraudulent":            true,
+		"other":                 true,
+	}
+
+	if !validCodes[code] {
+		return RefundReason{}, errors.NewValidationError("reason", "must be one of requested_by_customer, duplicate, fraudulent, other")
+	}
+
+	// "other" says nothing on its own, so it must be explained
+	if RefundReasonCode(code) == RefundReasonOther && note == "" {
+		return RefundReason{}, errors.NewValidationError("reason_note", "is required when reason is other")
+	}
+
+	if len(note) > maxRefundReasonNoteLength {
+		return RefundReason{}, errors.NewValidationError("reason_note", "is too long")
+	}
+
+	return RefundReason{
+		Code: RefundReasonCode(code),
+		Note: note,
+	}, nil
+}
+
+// String returns the string representation
+func (r RefundReason) String() string {
+	if r.Note == "" {
+		return string(r.Code)
+	}
+	return string(r.Code) + ": " + r.Note
+}
+
+// IsValid checks if refund reason is valid
+func (r RefundReason) IsValid() bool {
+	_, err := NewRefundReason(string(r.Code), r.Note)
+	return err == nil
+}
@@ -0,0 +1,234 @@
+package valueobjects
+
+import (
+	"net"
+	"net/mail"
+	"path"
+	"regexp"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// ReportDestinationType is how a scheduled report reaches the partner
+type ReportDestinationType string
+
+const (
+	// ReportDestinationEmail attaches the report to an email
+	ReportDestinationEmail ReportDestinationType = "email"
+	// ReportDestinationSFTP uploads the report to an SFTP server
+	ReportDestinationSFTP ReportDestinationType = "sftp"
+	// ReportDestinationS3 puts the report in an Amazon S3 bucket
+	ReportDestinationS3 ReportDestinationType = "s3"
+)
+
+// SFTP authentication methods
+const (
+	SFTPAuthPassword   = "password"
+	SFTPAuthPrivateKey = "private_key"
+)
+
+// MaxReportRecipients is how many addresses a report can be emailed to
+const MaxReportRecipients = 5
+
+var (
+	// sshHostKey matches a public key line of known_hosts or
+	// authorized_keys, without options or comment
+	sshHostKey = regexp.MustCompile(`^(ssh-ed25519|ssh-rsa|ecdsa-sha2-nistp(256|384|521)) [A-Za-z0-9+/]+={0,2}$`)
+	// s3Bucket matches the name of an S3 bucket
+	s3Bucket = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	// awsRegion matches a region such as eu-west-1
+	awsRegion = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d$`)
+	// sftpUsername matches a login name
+	sftpUsername = regexp.MustCompile(`^[A-Za-z0-9._@-]{1,64}$`)
+)
+
+// ReportDestination is where a partner's scheduled reports are delivered:
+// email recipients, a directory of the partner's SFTP server, or a prefix
+// of the partner's S3 bucket. As with EventDestination, Pay2Go assumes
+// RoleARN in the partner's account to write to the bucket.
+//
+// Password and PrivateKey are the SFTP credentials. They are never
+// answered by the API and are encrypted at rest.
+type ReportDestination struct {
+	Type ReportDestinationType `json:"type"`
+
+	// Email
+	Recipients []string `json:"recipients,omitempty"`
+
+	// SFTP. HostKey pins the server's public key, in known_hosts form, so
+	// reports are never handed to a server impersonating it.
+	Host       string `json:"host,omitempty"`
+	Port       int    `json:"port,omitempty"`
+	Username   string `json:"username,omitempty"`
+	HostKey    string `json:"host_key,omitempty"`
+	Directory  string `json:"directory,omitempty"`
+	Auth       string `json:"auth,omitempty"` // SFTPAuthPassword or SFTPAuthPrivateKey
+	Password   string `json:"-"`
+	PrivateKey string `json:"-"`
+
+	// S3
+	Bucket  string `json:"bucket,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Prefix  string `json:"prefix,omitempty"`
+	RoleARN string `json:"role_arn,omitempty"`
+}
+
+// NewReportDestination validates and normalizes a ReportDestination. Only
+// the fields of its type are kept; the SFTP port defaults to 22.
+func NewReportDestination(d ReportDestination) (ReportDestination, error) {
+	switch ReportDestinationType(strings.ToLower(strings.TrimSpace(string(d.Type)))) {
+	case ReportDestinationEmail:
+		return newEmailReportDestination(d.Recipients)
+	case ReportDestinationSFTP:
+		return newSFTPReportDestination(d)
+	case ReportDestinationS3:
+		return newS3ReportDestination(d)
+	}
+	return ReportDestination{}, errors.NewValidationError("destination.type", "must be email, sftp or s3")
+}
+
+func newEmailReportDestination(recipients []string) (ReportDestination, error) {
+	if len(recipients) == 0 || len(recipients) > MaxReportRecipients {
+		return ReportDestination{}, errors.NewValidationError("destination.recipients", "must list 1 to 5 email addresses")
+	}
+
+	destination := ReportDestination{Type: ReportDestinationEmail}
+	seen := make(map[string]bool)
+	for _, recipient := range recipients {
+		address, err := mail.ParseAddress(strings.TrimSpace(recipient))
+		if err != nil || address.Name != "" || address.Address != strings.TrimSpace(recipient) {
+			return ReportDestination{}, errors.NewValidationError("destination.recipients", "must be email addresses, such as finance@example.com")
+		}
+		key := strings.ToLower(address.Address)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		destination.Recipients = append(destination.Recipients, address.Address)
+	}
+	return destination, nil
+}
+
+func newSFTPReportDestination(d ReportDestination) (ReportDestination, error) {
+	destination := ReportDestination{
+		Type:       ReportDestinationSFTP,
+		Host:       strings.ToLower(strings.TrimSpace(d.Host)),
+		Port:       d.Port,
+		Username:   strings.TrimSpace(d.Username),
+		HostKey:    strings.TrimSpace(d.HostKey),
+		Directory:  strings.TrimSpace(d.Directory),
+		Password:   d.Password,
+		PrivateKey: strings.TrimSpace(d.PrivateKey),
+	}
+	if destination.Port == 0 {
+		destination.Port = 22
+	}
+
+	if !isPublicHost(destination.Host) {
+		return ReportDestination{}, errors.NewValidationError("destination.host", "must be a public host name or IP address")
+	}
+	if destination.Port < 1 || destination.Port > 65535 {
+		return ReportDestination{}, errors.NewValidationError("destination.port", "must be between 1 and 65535")
+	}
+	if !sftpUsername.MatchString(destination.Username) {
+		return ReportDestination{}, errors.NewValidationError("destination.username", "must be 1 to 64 letters, digits or ._@-")
+	}
+	if !sshHostKey.MatchString(destination.HostKey) {
+		return ReportDestination{}, errors.NewValidationError("destination.host_key", "must be the server's public key, such as ssh-ed25519 AAAA...")
+	}
+	if (destination.Password == "") == (destination.PrivateKey == "") {
+		return ReportDestination{}, errors.NewValidationError("destination.password", "give either a password or a private key")
+	}
+	destination.Auth = SFTPAuthPassword
+	if destination.PrivateKey != "" {
+		if !strings.Contains(destination.PrivateKey, "PRIVATE KEY-----") {
+			return ReportDestination{}, errors.NewValidationError("destination.private_key", "must be an unencrypted private key in PEM or OpenSSH form")
+		}
+		destination.Auth = SFTPAuthPrivateKey
+	}
+	if destination.Directory == "" {
+		destination.Directory = "."
+	}
+	if len(destination.Directory) > 255 || strings.ContainsAny(destination.Directory, "\x00\n\r") {
+		return ReportDestination{}, errors.NewValidationError("destination.directory", "must be a directory path of up to 255 characters")
+	}
+	destination.Directory = path.Clean(destination.Directory)
+	return destination, nil
+}
+
+func newS3ReportDestination(d ReportDestination) (ReportDestination, error) {
+	destination := ReportDestination{
+		Type:    ReportDestinationS3,
+		Bucket:  strings.TrimSpace(d.Bucket),
+		Region:  strings.TrimSpace(d.Region),
+		Prefix:  strings.TrimLeft(strings.TrimSpace(d.Prefix), "/"),
+		RoleARN: strings.TrimSpace(d.RoleARN),
+	}
+
+	if !s3Bucket.MatchString(destination.Bucket) {
+		return ReportDestination{}, errors.NewValidationError("destination.bucket", "must be an S3 bucket name")
+	}
+	if !awsRegion.MatchString(destination.Region) {
+		return ReportDestination{}, errors.NewValidationError("destination.region", "must be an AWS region, such as eu-west-1")
+	}
+	if len(destination.Prefix) > 512 || strings.ContainsAny(destination.Prefix, "\x00\n\r") {
+		return ReportDestination{}, errors.NewValidationError("destination.prefix", "must be a key prefix of up to 512 characters")
+	}
+	if !iamRoleARN.MatchString(destination.RoleARN) {
+		return ReportDestination{}, errors.NewValidationError("destination.role_arn", "must be an IAM role ARN")
+	}
+	return destination, nil
+}
+
+// Secret is the SFTP password or private key, whichever is set
+func (d ReportDestination) Secret() string {
+	if d.Auth == SFTPAuthPrivateKey {
+		return d.PrivateKey
+	}
+	return d.Password
+}
+
+// WithSecret returns the destination with its SFTP password or private key,
+// by Auth, set to secret, as read back from storage
+func (d ReportDestination) WithSecret(secret string) ReportDestination {
+	if d.Auth == SFTPAuthPrivateKey {
+		d.PrivateKey = secret
+	} else if d.Auth == SFTPAuthPassword {
+		d.Password = secret
+	}
+	return d
+}
+
+// isPublicHost reports whether host is a DNS name or an IP address outside
+// private, loopback and link-local ranges, so schedules cannot reach into
+// Pay2Go's own network. Names are checked again when they are resolved.
+func isPublicHost(host string) bool {
+	if host == "" || len(host) > 253 {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicIP(ip)
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") || !strings.Contains(host, ".") {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsPublicIP reports whether ip is reachable on the internet rather than a
+// private, loopback, link-local or unspecified address
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() || ip.IsInterfaceLocalMulticast())
+}
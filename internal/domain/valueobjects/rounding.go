@@ -0,0 +1,115 @@
+package valueobjects
+
+import (
+	"math"
+	"math/bits"
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// RoundingPolicy decides how fractional minor units (e.g. half a cent) are
+// rounded in fee, tax and division results
+type RoundingPolicy string
+
+const (
+	// RoundHalfUp rounds halves away from zero: 0.5 -> 1, 2.5 -> 3
+	RoundHalfUp RoundingPolicy = "half_up"
+	// RoundHalfEven rounds halves to the nearest even unit (banker's rounding): 0.5 -> 0, 2.5 -> 2
+	RoundHalfEven RoundingPolicy = "half_even"
+	// RoundTruncate drops the fraction: 2.9 -> 2
+	RoundTruncate RoundingPolicy = "truncate"
+)
+
+// DefaultRoundingPolicy is used for partners that have not chosen a policy
+const DefaultRoundingPolicy = RoundHalfUp
+
+// NewRoundingPolicy validates and creates a RoundingPolicy
+func NewRoundingPolicy(policy string) (RoundingPolicy, error) {
+	switch RoundingPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case RoundHalfUp:
+		return RoundHalfUp, nil
+	case RoundHalfEven:
+		return RoundHalfEven, nil
+	case RoundTruncate:
+		return RoundTruncate, nil
+	}
+
+	return "", errors.NewValidationError("rounding_policy", "must be half_up, half_even or truncate")
+}
+
+// String returns the string representation
+func (p RoundingPolicy) String() string {
+	return string(p)
+}
+
+// IsValid checks if rounding policy is valid
+func (p RoundingPolicy) IsValid() bool {
+	_, err := NewRoundingPolicy(string(p))
+	return err == nil
+}
+
+// Quotient returns numerator / denominator rounded with the policy, using
+// integer arithmetic only. denominator must be positive.
+func (p RoundingPolicy) Quotient(numerator, denominator int64) int64 {
+	quotient, remainder := numerator/denominator, numerator%denominator
+
+	// Step away from zero when the remainder says so
+	away := int64(1)
+	if remainder < 0 {
+		away, remainder = -1, -remainder
+	}
+	if p.roundsAway(uint64(quotient%2), uint64(remainder), uint64(denominator)) {
+		return quotient + away
+	}
+	return quotient
+}
+
+// Scale returns units * numerator / denominator rounded with the policy. The
+// product is taken in 128 bits, so it cannot overflow; a result that does
+// not fit an int64 is an error. numerator must be non-negative and
+// denominator positive.
+func (p RoundingPolicy) Scale(units, numerator, denominator int64) (int64, error) {
+	negative := units < 0
+	magnitude := uint64(units)
+	if negative {
+		magnitude = -magnitude
+	}
+
+	high, low := bits.Mul64(magnitude, uint64(numerator))
+	if high >= uint64(denominator) {
+		return 0, errors.NewValidationError("amount", "is out of range")
+	}
+	quotient, remainder := bits.Div64(high, low, uint64(denominator))
+	if p.roundsAway(quotient, remainder, uint64(denominator)) {
+		quotient++
+	}
+	if quotient > math.MaxInt64 {
+		return 0, errors.NewValidationError("amount", "is out of range")
+	}
+
+	if negative {
+		return -int64(quotient), nil
+	}
+	return int64(quotient), nil
+}
+
+// roundsAway reports whether a quotient whose magnitude is quotient, with
+// remainder left of denominator, rounds away from zero: when the remainder
+// is more than half, or exactly half and the policy says so. Only the
+// parity of quotient matters.
+func (p RoundingPolicy) roundsAway(quotient, remainder, denominator uint64) bool {
+	if remainder == 0 || p == RoundTruncate {
+		return false
+	}
+
+	switch twice := 2 * remainder; {
+	case twice > denominator:
+		return true
+	case twice == denominator && p == RoundHalfEven:
+		return quotient%2 != 0
+	case twice == denominator:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package valueobjects
+
+import (
+	"strconv"
+	"strings"
+)
+
+// formulaSigns are the characters spreadsheets start a formula with, or
+// skip before one
+const formulaSigns = "=+-@\t\r"
+
+// SpreadsheetCell guards a field of a CSV file people open in a
+// spreadsheet: one starting with =, +, -, @, a tab or a carriage return
+// would run as a formula, such as =HYPERLINK(...), so it is prefixed with a
+// quote. Numbers such as -12.50 are left alone, to stay numbers.
+//
+// Every CSV export goes through it: transaction exports, partner
+// statements and accounting exports.
+func SpreadsheetCell(field string) string {
+	if field == "" || !strings.ContainsRune(formulaSigns, rune(field[0])) {
+		return field
+	}
+	if _, err := strconv.ParseFloat(field, 64); err == nil {
+		return field
+	}
+	return "'" + field
+}
@@ -0,0 +1,61 @@
+package valueobjects
+
+import (
+	"strings"
+
+	"Pay2Go/internal/domain/errors"
+)
+
+// UserRole defines what a partner team member may do
+type UserRole string
+
+const (
+	RoleOwner     UserRole = "owner"
+	RoleDeveloper UserRole = "developer"
+	RoleFinance   UserRole = "finance"
+	RoleReadOnly  UserRole = "read_only"
+)
+
+// NewUserRole validates and creates a UserRole
+func NewUserRole(role string) (UserRole, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+
+	validRoles := map[string]bool{
+		"owner":     true,
+		"developer": true,
+		"finance":   true,
+		"read_only": true,
+	}
+
+	if !validRoles[role] {
+		return "", errors.NewValidationError("role", "must be one of owner, developer, finance, read_only")
+	}
+
+	return UserRole(role), nil
+}
+
+// Scopes returns the API scopes a session with this role carries.
+// Role checks on sensitive routes narrow these further.
+func (r UserRole) Scopes() []APIKeyScope {
+	switch r {
+	case RoleOwner, RoleDeveloper:
+		return []APIKeyScope{ScopeAdmin}
+	case RoleFinance:
+		return []APIKeyScope{ScopeRefunds, ScopeReadOnly}
+	case RoleReadOnly:
+		return []APIKeyScope{ScopeReadOnly}
+	default:
+		return nil
+	}
+}
+
+// String returns the string representation
+func (r UserRole) String() string {
+	return string(r)
+}
+
+// IsValid checks if role is valid
+func (r UserRole) IsValid() bool {
+	_, err := NewUserRole(string(r))
+	return err == nil
+}
@@ -0,0 +1,144 @@
+// Package accounting writes payments, refunds and fees in the files
+// accounting systems import: QuickBooks IIF and Xero bank statements
+package accounting
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// payee names Pay2Go on the entries of its fees
+const payee = "Pay2Go"
+
+// Encoder implements ports.AccountingEncoder
+type Encoder struct{}
+
+// NewEncoder creates an encoder of accounting files
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// NewFile starts a file in format posting to accounts, written to w
+func (Encoder) NewFile(w io.Writer, format entities.ExportFormat, accounts valueobjects.AccountMapping, location *time.Location) (ports.AccountingFile, error) {
+	var entries entryWriter
+	out := bufio.NewWriter(w)
+	switch format {
+	case entities.ExportFormatQuickBooksIIF:
+		entries = newIIFWriter(out, accounts)
+	case entities.ExportFormatXeroCSV:
+		entries = newXeroWriter(out, accounts)
+	default:
+		return nil, fmt.Errorf("%s is not an accounting format", format)
+	}
+	return &file{entries: entries, out: out, accounts: accounts, location: location}, nil
+}
+
+// entry is a movement of the clearing account: positive amounts are paid
+// into it, negative ones out of it, posted against account
+type entry struct {
+	date        time.Time
+	amount      int64
+	currency    valueobjects.Currency
+	account     string
+	payee       string
+	description string
+	reference   string
+}
+
+// entryWriter writes entries in one format
+type entryWriter interface {
+	write(e entry) error
+	flush() error
+}
+
+// file is an accounting file of payments, refunds or statement fees
+type file struct {
+	entries  entryWriter
+	out      *bufio.Writer
+	accounts valueobjects.AccountMapping
+	location *time.Location
+}
+
+func (f *file) WriteTransaction(txn *entities.Transaction) error {
+	return f.entries.write(entry{
+		date:        txn.CreatedAt.In(f.location),
+		amount:      txn.Amount.Amount,
+		currency:    txn.Amount.Currency,
+		account:     f.accounts.SalesAccount,
+		payee:       txn.CustomerName,
+		description: describe("Payment", txn.Amount.Currency, txn.Description),
+		reference:   txn.ID.String(),
+	})
+}
+
+func (f *file) WriteRefund(refund *entities.Refund) error {
+	return f.entries.write(entry{
+		date:        refund.CreatedAt.In(f.location),
+		amount:      -refund.Amount.Amount,
+		currency:    refund.Amount.Currency,
+		account:     f.accounts.RefundsAccount,
+		description: describe("Refund", refund.Amount.Currency, refund.Reason.String()),
+		reference:   refund.ID.String(),
+	})
+}
+
+// WriteStatementFees writes the statement's fees on the last day of its
+// month, which statements cover in UTC
+func (f *file) WriteStatementFees(statement *entities.Statement) error {
+	date := statement.Period.End().AddDate(0, 0, -1)
+	for _, line := range statement.Currencies {
+		fees := make([]entry, 0, len(line.TierFees)+1)
+		for _, tier := range line.TierFees {
+			fees = append(fees, entry{
+				amount:      -tier.Fee,
+				description: fmt.Sprintf("%s tier, %d payments", tier.Tier, tier.Payments.Count),
+			})
+		}
+		if line.ChargebackFees.Count > 0 {
+			fees = append(fees, entry{
+				amount:      -line.ChargebackFees.Amount,
+				description: fmt.Sprintf("%d chargebacks", line.ChargebackFees.Count),
+			})
+		}
+
+		for _, fee := range fees {
+			if fee.amount == 0 {
+				continue
+			}
+			fee.date = date
+			fee.currency = line.Currency
+			fee.account = f.accounts.FeesAccount
+			fee.payee = payee
+			fee.description = describe("Fees "+statement.Period.String(), line.Currency, fee.description)
+			fee.reference = statement.ID.String()
+			if err := f.entries.write(fee); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *file) Close() error {
+	if err := f.entries.flush(); err != nil {
+		return err
+	}
+	return f.out.Flush()
+}
+
+// describe is the description of an entry, naming its currency since
+// accounting systems import amounts alone, e.g. "Payment in USD: Order 42"
+func describe(kind string, currency valueobjects.Currency, detail string) string {
+	description := kind + " in " + currency.String()
+	if detail = strings.Join(strings.Fields(detail), " "); detail != "" {
+		description += ": " + detail
+	}
+	return description
+}
@@ -0,0 +1,74 @@
+package accounting
+
+import (
+	"io"
+	"strings"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// iifHeader declares the fields of IIF transactions: a TRNS line posts to
+// the clearing account and a SPL line, its split, to the other account
+const iifHeader = "!TRNS\tTRNSID\tTRNSTYPE\tDATE\tACCNT\tNAME\tAMOUNT\tDOCNUM\tMEMO\r\n" +
+	"!SPL\tSPLID\tTRNSTYPE\tDATE\tACCNT\tNAME\tAMOUNT\tDOCNUM\tMEMO\r\n" +
+	"!ENDTRNS\r\n"
+
+// iifWriter writes entries as QuickBooks Desktop IIF transactions: money
+// paid into the clearing account as deposits and paid out as checks. IIF
+// dates are month first, as QuickBooks reads them.
+type iifWriter struct {
+	out      io.Writer
+	clearing string
+	header   bool
+}
+
+func newIIFWriter(out io.Writer, accounts valueobjects.AccountMapping) *iifWriter {
+	return &iifWriter{out: out, clearing: accounts.ClearingAccount}
+}
+
+func (w *iifWriter) write(e entry) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	kind := "DEPOSIT"
+	if e.amount < 0 {
+		kind = "CHECK"
+	}
+	date := e.date.Format("01/02/2006")
+	// The memo carries the reference: QuickBooks limits DOCNUM to 11
+	// characters
+	memo := iifField(e.description + " (" + e.reference + ")")
+	amount := valueobjects.FormatMinorUnits(e.amount, e.currency)
+	split := valueobjects.FormatMinorUnits(-e.amount, e.currency)
+	_, err := io.WriteString(w.out,
+		iifLine("TRNS", "", kind, date, w.clearing, iifField(e.payee), amount, "", memo)+
+			iifLine("SPL", "", kind, date, e.account, "", split, "", memo)+
+			"ENDTRNS\r\n")
+	return err
+}
+
+// flush writes the header of a file without entries, so QuickBooks still
+// recognizes it
+func (w *iifWriter) flush() error {
+	return w.writeHeader()
+}
+
+func (w *iifWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	_, err := io.WriteString(w.out, iifHeader)
+	return err
+}
+
+// iifField makes field safe to write between tabs
+func iifField(field string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(field, `"`, "'")), " ")
+}
+
+// iifLine joins fields into a line of an IIF file
+func iifLine(fields ...string) string {
+	return strings.Join(fields, "\t") + "\r\n"
+}
@@ -0,0 +1,61 @@
+package accounting
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// xeroColumns is the header row of a Xero bank statement
+var xeroColumns = []string{"Date", "Amount", "Payee", "Description", "Reference", "Account Code"}
+
+// xeroWriter writes entries as a Xero bank statement, imported into the
+// bank account chosen on import; Account Code suggests the account each
+// entry is reconciled against
+type xeroWriter struct {
+	out        *csv.Writer
+	dateLayout string
+	header     bool
+}
+
+func newXeroWriter(out io.Writer, accounts valueobjects.AccountMapping) *xeroWriter {
+	return &xeroWriter{out: csv.NewWriter(out), dateLayout: accounts.GoDateLayout()}
+}
+
+func (w *xeroWriter) write(e entry) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.out.Write([]string{
+		e.date.Format(w.dateLayout),
+		valueobjects.FormatMinorUnits(e.amount, e.currency),
+		xeroField(e.payee),
+		xeroField(e.description),
+		e.reference,
+		e.account,
+	})
+}
+
+func (w *xeroWriter) flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.out.Flush()
+	return w.out.Error()
+}
+
+func (w *xeroWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	return w.out.Write(xeroColumns)
+}
+
+// xeroField puts field on one line, as Xero imports it, and guards it
+// against running as a spreadsheet formula
+func xeroField(field string) string {
+	return valueobjects.SpreadsheetCell(strings.Join(strings.Fields(field), " "))
+}
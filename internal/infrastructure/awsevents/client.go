@@ -0,0 +1,138 @@
+// Package awsevents delivers partners' events to an SNS topic or SQS queue
+// in their own AWS account, through the AWS SDK and a role the partner lets
+// Pay2Go assume, and puts partners' scheduled reports in their S3 buckets
+// with the same roles. It also sends Pay2Go's emails through Amazon SES.
+package awsevents
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Config holds Pay2Go's own AWS credentials, which only need permission to
+// assume the roles partners create for it and, to send email, ses:SendEmail
+// and ses:SendRawEmail
+type Config struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Endpoint is empty for AWS, or the URL of a service that emulates it,
+	// such as LocalStack, that every request is sent to instead
+	Endpoint string
+}
+
+// roleSessionName names Pay2Go's sessions in the partner's CloudTrail
+const roleSessionName = "pay2go-events"
+
+// roleDuration is how long assumed role credentials last
+const roleDuration = time.Hour
+
+// refreshBefore is how long before they expire assumed role credentials
+// are replaced, so none expire during a delivery
+const refreshBefore = 5 * time.Minute
+
+// client builds the AWS SDK configurations of Pay2Go's own credentials and
+// of the partner roles it assumes
+type client struct {
+	cfg  Config
+	http *http.Client
+
+	mu    sync.Mutex
+	roles map[roleKey]*aws.CredentialsCache
+}
+
+// roleKey identifies the credentials of an assumed role
+type roleKey struct {
+	roleARN    string
+	externalID string
+	region     string
+}
+
+func newClient(cfg Config, httpClient *http.Client) (*client, error) {
+	if cfg.Endpoint != "" {
+		endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid AWS endpoint %q", cfg.Endpoint)
+		}
+	}
+	return &client{
+		cfg:   cfg,
+		http:  httpClient,
+		roles: make(map[roleKey]*aws.CredentialsCache),
+	}, nil
+}
+
+// awsConfig is the SDK configuration calling region with creds
+func (c *client) awsConfig(region string, creds aws.CredentialsProvider) aws.Config {
+	cfg := aws.Config{
+		Region:      region,
+		Credentials: creds,
+		HTTPClient:  c.http,
+	}
+	if c.cfg.Endpoint != "" {
+		cfg.BaseEndpoint = aws.String(c.cfg.Endpoint)
+	}
+	return cfg
+}
+
+// ownCredentials are Pay2Go's credentials of the configuration
+func (c *client) ownCredentials() aws.CredentialsProvider {
+	return credentials.NewStaticCredentialsProvider(c.cfg.AccessKeyID, c.cfg.SecretAccessKey, c.cfg.SessionToken)
+}
+
+// assumeRole returns the credentials of roleARN, assumed with externalID
+// through STS in region the first time they are retrieved and again
+// shortly before they expire
+func (c *client) assumeRole(roleARN, externalID, region string) (aws.CredentialsProvider, error) {
+	if c.cfg.AccessKeyID == "" || c.cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS credentials for assuming partner roles are not configured")
+	}
+
+	key := roleKey{roleARN: roleARN, externalID: externalID, region: region}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if creds, ok := c.roles[key]; ok {
+		return creds, nil
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(
+		sts.NewFromConfig(c.awsConfig(region, c.ownCredentials())),
+		roleARN,
+		func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName
+			o.ExternalID = aws.String(externalID)
+			o.Duration = roleDuration
+		},
+	)
+	creds := aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = refreshBefore
+	})
+	c.roles[key] = creds
+	return creds, nil
+}
+
+// forgetRole drops the cached credentials of a role, after they were
+// refused, so the next delivery assumes it again
+func (c *client) forgetRole(roleARN, externalID, region string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.roles, roleKey{roleARN: roleARN, externalID: externalID, region: region})
+}
+
+// isForbidden reports whether AWS refused a call with err for lack of
+// permission
+func isForbidden(err error) bool {
+	var respErr *awshttp.ResponseError
+	return stderrors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusForbidden
+}
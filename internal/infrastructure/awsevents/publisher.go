@@ -0,0 +1,142 @@
+package awsevents
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/webhook"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Publisher implements ports.OutboxPublisher by delivering events of
+// partners with an event destination to their SNS topic or SQS queue, and
+// those of every other partner with webhooks.
+//
+// Each delivery assumes the destination's role with the partner's ID as
+// external ID, so a role only trusts Pay2Go acting for that partner.
+type Publisher struct {
+	partnerRepo ports.PartnerRepository
+	webhooks    ports.OutboxPublisher
+	client      *client
+}
+
+// NewPublisher creates a publisher calling AWS with httpClient, whose
+// timeout bounds each call
+func NewPublisher(partnerRepo ports.PartnerRepository, webhooks ports.OutboxPublisher, cfg Config, httpClient *http.Client) (*Publisher, error) {
+	c, err := newClient(cfg, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &Publisher{partnerRepo: partnerRepo, webhooks: webhooks, client: c}, nil
+}
+
+// Publish delivers event to its partner's event destination, or with
+// webhooks when it has none. Failures are retried later by the relay.
+func (p *Publisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	partner, err := p.partnerRepo.GetByID(ctx, event.PartnerID)
+	if err == errors.ErrPartnerNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get partner: %w", err)
+	}
+	if partner.EventDestination == nil {
+		return p.webhooks.Publish(ctx, event)
+	}
+
+	body, err := webhook.EncodePayload(event, partner.FieldFilter.Webhooks)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	destination := *partner.EventDestination
+	region := destination.Region()
+	externalID := partner.ID.String()
+	creds, err := p.client.assumeRole(destination.RoleARN, externalID, region)
+	if err != nil {
+		return err
+	}
+
+	cfg := p.client.awsConfig(region, creds)
+	switch destination.Type {
+	case valueobjects.EventDestinationSNS:
+		_, err = sns.NewFromConfig(cfg).Publish(ctx, snsPublish(destination, event, body))
+	case valueobjects.EventDestinationSQS:
+		_, err = sqs.NewFromConfig(cfg).SendMessage(ctx, sqsSendMessage(destination, event, body))
+	default:
+		return fmt.Errorf("unknown event destination type %q", destination.Type)
+	}
+
+	// Credentials of a role whose trust or permissions the partner changed
+	// are refused before they expire; assume it again next time
+	if isForbidden(err) {
+		p.client.forgetRole(destination.RoleARN, externalID, region)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deliver event to %s: %w", destination.Target, err)
+	}
+	return nil
+}
+
+// snsPublish is the SNS Publish request for event
+func snsPublish(destination valueobjects.EventDestination, event *entities.OutboxEvent, body []byte) *sns.PublishInput {
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(destination.Target),
+		Message:           aws.String(string(body)),
+		MessageAttributes: make(map[string]snstypes.MessageAttributeValue),
+	}
+	for _, attribute := range attributes(event) {
+		input.MessageAttributes[attribute[0]] = snstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(attribute[1]),
+		}
+	}
+	input.MessageGroupId, input.MessageDeduplicationId = fifo(destination, event)
+	return input
+}
+
+// sqsSendMessage is the SQS SendMessage request for event
+func sqsSendMessage(destination valueobjects.EventDestination, event *entities.OutboxEvent, body []byte) *sqs.SendMessageInput {
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(destination.Target),
+		MessageBody:       aws.String(string(body)),
+		MessageAttributes: make(map[string]sqstypes.MessageAttributeValue),
+	}
+	for _, attribute := range attributes(event) {
+		input.MessageAttributes[attribute[0]] = sqstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(attribute[1]),
+		}
+	}
+	input.MessageGroupId, input.MessageDeduplicationId = fifo(destination, event)
+	return input
+}
+
+// attributes are the message attributes of event, so subscriptions can
+// filter on the event type without parsing the message
+func attributes(event *entities.OutboxEvent) [][2]string {
+	return [][2]string{
+		{"event_id", event.ID.String()},
+		{"event_type", event.EventType},
+	}
+}
+
+// fifo groups the messages of a FIFO destination by aggregate, which keeps
+// each transaction's or refund's events in order, and deduplicates them by
+// event ID, so a delivery the relay retries is received once
+func fifo(destination valueobjects.EventDestination, event *entities.OutboxEvent) (group, deduplication *string) {
+	if !destination.IsFIFO() {
+		return nil, nil
+	}
+	return aws.String(event.AggregateID.String()), aws.String(event.ID.String())
+}
@@ -0,0 +1,56 @@
+package awsevents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+)
+
+// S3Uploader puts files in partners' own S3 buckets, with the role each
+// partner lets Pay2Go assume, as the Publisher delivers events
+type S3Uploader struct {
+	client *client
+}
+
+// NewS3Uploader creates an uploader assuming partners' roles with the
+// credentials of cfg
+func NewS3Uploader(cfg Config, httpClient *http.Client) (*S3Uploader, error) {
+	c, err := newClient(cfg, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &S3Uploader{client: c}, nil
+}
+
+// Put stores data as key in partnerID's bucket in region, assuming roleARN
+// with the partner's ID as external ID
+func (u *S3Uploader) Put(ctx context.Context, partnerID uuid.UUID, roleARN, bucket, region, key string, data []byte) error {
+	externalID := partnerID.String()
+	creds, err := u.client.assumeRole(roleARN, externalID, region)
+	if err != nil {
+		return err
+	}
+
+	// Services emulating AWS at one endpoint address buckets by path
+	store := s3.NewFromConfig(u.client.awsConfig(region, creds), func(o *s3.Options) {
+		o.UsePathStyle = u.client.cfg.Endpoint != ""
+	})
+	_, err = store.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		// A role whose trust or permissions the partner changed is refused
+		// before its credentials expire; assume it again next time
+		u.client.forgetRole(roleARN, externalID, region)
+		return fmt.Errorf("failed to put report in bucket %s: %w", bucket, err)
+	}
+	return nil
+}
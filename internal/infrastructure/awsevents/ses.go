@@ -0,0 +1,96 @@
+package awsevents
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	sestypes "github.com/aws/aws-sdk-go-v2/service/ses/types"
+
+	emailmsg "Pay2Go/internal/infrastructure/email"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SESSender implements ports.EmailSender with the SendEmail action of
+// Amazon SES, or SendRawEmail for emails with attachments, signed with
+// Pay2Go's own credentials
+type SESSender struct {
+	ses  *ses.Client
+	from string
+}
+
+// NewSESSender creates a sender through SES in region, from the verified
+// address or domain of from
+func NewSESSender(cfg Config, region, from string, httpClient *http.Client) (*SESSender, error) {
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS credentials are required to send email through SES")
+	}
+	if region == "" || from == "" {
+		return nil, fmt.Errorf("SES region and sender address are required")
+	}
+	c, err := newClient(cfg, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &SESSender{ses: ses.NewFromConfig(c.awsConfig(region, c.ownCredentials())), from: from}, nil
+}
+
+// SendEmail sends email through SES
+func (s *SESSender) SendEmail(ctx context.Context, email ports.Email) error {
+	if len(email.Attachments) > 0 {
+		return s.sendRaw(ctx, email)
+	}
+
+	input := &ses.SendEmailInput{
+		Source:      aws.String(s.from),
+		Destination: &sestypes.Destination{ToAddresses: []string{email.To}},
+		Message: &sestypes.Message{
+			Subject: utf8(email.Subject),
+			Body:    &sestypes.Body{Text: utf8(email.Text)},
+		},
+	}
+	if email.HTML != "" {
+		input.Message.Body.Html = utf8(email.HTML)
+	}
+
+	if _, err := s.ses.SendEmail(ctx, input); err != nil {
+		return fmt.Errorf("failed to send email through SES: %w", err)
+	}
+	return nil
+}
+
+// utf8 is SES content of text
+func utf8(text string) *sestypes.Content {
+	return &sestypes.Content{Data: aws.String(text), Charset: aws.String("UTF-8")}
+}
+
+// sendRaw sends email as a MIME message built like the SMTP sender's,
+// which SES needs for attachments
+func (s *SESSender) sendRaw(ctx context.Context, email ports.Email) error {
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.from, err)
+	}
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", email.To, err)
+	}
+	message, err := emailmsg.Message(from, to, email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	input := &ses.SendRawEmailInput{
+		Source:       aws.String(s.from),
+		Destinations: []string{email.To},
+		RawMessage:   &sestypes.RawMessage{Data: message},
+	}
+	if _, err := s.ses.SendRawEmail(ctx, input); err != nil {
+		return fmt.Errorf("failed to send email through SES: %w", err)
+	}
+	return nil
+}
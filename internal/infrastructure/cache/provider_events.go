@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// providerEventKeyPrefix prefixes the key of a claimed provider event
+const providerEventKeyPrefix = "provider_event:"
+
+// providerEventKey returns the key of provider's event id
+func providerEventKey(provider valueobjects.PaymentProvider, id string) string {
+	return providerEventKeyPrefix + provider.String() + ":" + id
+}
+
+// MemoryEventStore implements ports.ProviderEventStore in process memory.
+// Claims are only known to the instance that made them, so it is only
+// suitable for single-instance deployments; past capacity the oldest claims
+// are forgotten before their TTL.
+type MemoryEventStore struct {
+	mu    sync.Mutex
+	cache *MemoryCache
+}
+
+// NewMemoryEventStore creates a store holding up to capacity claims
+func NewMemoryEventStore(capacity int) *MemoryEventStore {
+	return &MemoryEventStore{cache: NewMemoryCache(capacity)}
+}
+
+// Claim records provider's event id until ttl passes, unless it is recorded
+func (s *MemoryEventStore) Claim(ctx context.Context, provider valueobjects.PaymentProvider, id string, ttl time.Duration) (bool, error) {
+	key := providerEventKey(provider, id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	claimed, err := s.cache.Exists(ctx, key)
+	if err != nil || claimed {
+		return false, err
+	}
+	if err := s.cache.Set(ctx, key, true, int(ttl.Seconds())); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Release forgets the claim of provider's event id
+func (s *MemoryEventStore) Release(ctx context.Context, provider valueobjects.PaymentProvider, id string) error {
+	return s.cache.Delete(ctx, providerEventKey(provider, id))
+}
+
+// RedisEventStore implements ports.ProviderEventStore with a Redis key per
+// event, set only if absent, so a notification delivered to two instances
+// at once is handled by one of them
+type RedisEventStore struct {
+	client *redis.Client
+}
+
+// NewRedisEventStore creates a Redis-backed provider event store
+func NewRedisEventStore(client *redis.Client) *RedisEventStore {
+	return &RedisEventStore{client: client}
+}
+
+// Claim sets the key of provider's event id for ttl unless it is set
+func (s *RedisEventStore) Claim(ctx context.Context, provider valueobjects.PaymentProvider, id string, ttl time.Duration) (bool, error) {
+	claimed, err := s.client.SetNX(ctx, providerEventKey(provider, id), 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim provider event: %w", err)
+	}
+	return claimed, nil
+}
+
+// Release deletes the key of provider's event id
+func (s *RedisEventStore) Release(ctx context.Context, provider valueobjects.PaymentProvider, id string) error {
+	if err := s.client.Del(ctx, providerEventKey(provider, id)).Err(); err != nil {
+		return fmt.Errorf("failed to release provider event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+const (
+	// partnerKeyPrefix prefixes the key of a cached partner
+	partnerKeyPrefix = "partner:"
+
+	// partnerInvalidationChannel carries the IDs of changed partners to
+	// every API instance
+	partnerInvalidationChannel = "partner-invalidations"
+)
+
+// RedisPartnerCache implements ports.PartnerCache with Redis, storing
+// partners as JSON and announcing invalidations over pub/sub
+type RedisPartnerCache struct {
+	client *redis.Client
+}
+
+// NewRedisPartnerCache creates a Redis-backed partner cache
+func NewRedisPartnerCache(client *redis.Client) *RedisPartnerCache {
+	return &RedisPartnerCache{client: client}
+}
+
+// Get returns the cached partner, or nil if it is not cached
+func (c *RedisPartnerCache) Get(ctx context.Context, id uuid.UUID) (*entities.Partner, error) {
+	data, err := c.client.Get(ctx, partnerKeyPrefix+id.String()).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached partner: %w", err)
+	}
+
+	var partner entities.Partner
+	if err := json.Unmarshal(data, &partner); err != nil {
+		return nil, fmt.Errorf("failed to decode cached partner: %w", err)
+	}
+	return &partner, nil
+}
+
+// Set caches partner for ttl
+func (c *RedisPartnerCache) Set(ctx context.Context, partner *entities.Partner, ttl time.Duration) error {
+	data, err := json.Marshal(partner)
+	if err != nil {
+		return fmt.Errorf("failed to encode partner: %w", err)
+	}
+	if err := c.client.Set(ctx, partnerKeyPrefix+partner.ID.String(), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache partner: %w", err)
+	}
+	return nil
+}
+
+// Invalidate deletes the cached partner and publishes its ID
+func (c *RedisPartnerCache) Invalidate(ctx context.Context, id uuid.UUID) error {
+	if err := c.client.Del(ctx, partnerKeyPrefix+id.String()).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate cached partner: %w", err)
+	}
+	if err := c.client.Publish(ctx, partnerInvalidationChannel, id.String()).Err(); err != nil {
+		return fmt.Errorf("failed to announce partner invalidation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe calls onInvalidate with the ID of every partner invalidated by
+// any instance until ctx is done
+func (c *RedisPartnerCache) Subscribe(ctx context.Context, onInvalidate func(id uuid.UUID)) error {
+	pubsub := c.client.Subscribe(ctx, partnerInvalidationChannel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to partner invalidations: %w", err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			if id, err := uuid.Parse(message.Payload); err == nil {
+				onInvalidate(id)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// transactionKeyPrefix prefixes the key of a cached transaction
+const transactionKeyPrefix = "transaction:"
+
+// RedisTransactionCache implements ports.TransactionCache with Redis,
+// storing transactions as JSON
+type RedisTransactionCache struct {
+	client *redis.Client
+}
+
+// NewRedisTransactionCache creates a Redis-backed transaction cache
+func NewRedisTransactionCache(client *redis.Client) *RedisTransactionCache {
+	return &RedisTransactionCache{client: client}
+}
+
+// Get returns the cached transaction, or nil if it is not cached
+func (c *RedisTransactionCache) Get(ctx context.Context, partnerID, id uuid.UUID) (*entities.Transaction, error) {
+	data, err := c.client.Get(ctx, transactionKey(partnerID, id)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached transaction: %w", err)
+	}
+
+	var transaction entities.Transaction
+	if err := json.Unmarshal(data, &transaction); err != nil {
+		return nil, fmt.Errorf("failed to decode cached transaction: %w", err)
+	}
+	return &transaction, nil
+}
+
+// Set caches transaction for ttl
+func (c *RedisTransactionCache) Set(ctx context.Context, transaction *entities.Transaction, ttl time.Duration) error {
+	data, err := json.Marshal(transaction)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	if err := c.client.Set(ctx, transactionKey(transaction.PartnerID, transaction.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache transaction: %w", err)
+	}
+	return nil
+}
+
+// Invalidate deletes the cached transaction
+func (c *RedisTransactionCache) Invalidate(ctx context.Context, partnerID, id uuid.UUID) error {
+	if err := c.client.Del(ctx, transactionKey(partnerID, id)).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate cached transaction: %w", err)
+	}
+	return nil
+}
+
+func transactionKey(partnerID, id uuid.UUID) string {
+	return transactionKeyPrefix + partnerID.String() + ":" + id.String()
+}
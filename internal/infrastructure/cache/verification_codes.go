@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// verificationKeyPrefix prefixes the key of a verification code's hash
+const verificationKeyPrefix = "verification:"
+
+// MemoryCodeStore implements ports.VerificationCodeStore in process memory.
+// Codes are only known to the instance that sent them, so it is only
+// suitable for single-instance deployments.
+type MemoryCodeStore struct {
+	cache *MemoryCache
+}
+
+// NewMemoryCodeStore creates a store holding up to capacity codes
+func NewMemoryCodeStore(capacity int) *MemoryCodeStore {
+	return &MemoryCodeStore{cache: NewMemoryCache(capacity)}
+}
+
+// Save keeps hash for key until ttl passes
+func (s *MemoryCodeStore) Save(ctx context.Context, key, hash string, ttl time.Duration) error {
+	return s.cache.Set(ctx, verificationKeyPrefix+key, hash, int(ttl.Seconds()))
+}
+
+// Get returns the hash kept for key, or "" when there is none
+func (s *MemoryCodeStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.cache.Get(ctx, verificationKeyPrefix+key)
+	if err != nil || value == nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// Delete removes the hash kept for key
+func (s *MemoryCodeStore) Delete(ctx context.Context, key string) error {
+	return s.cache.Delete(ctx, verificationKeyPrefix+key)
+}
+
+// RedisCodeStore implements ports.VerificationCodeStore with Redis, so any
+// API instance can check a code another one sent
+type RedisCodeStore struct {
+	client *redis.Client
+}
+
+// NewRedisCodeStore creates a Redis-backed verification code store
+func NewRedisCodeStore(client *redis.Client) *RedisCodeStore {
+	return &RedisCodeStore{client: client}
+}
+
+// Save keeps hash for key until ttl passes
+func (s *RedisCodeStore) Save(ctx context.Context, key, hash string, ttl time.Duration) error {
+	if err := s.client.Set(ctx, verificationKeyPrefix+key, hash, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save verification code: %w", err)
+	}
+	return nil
+}
+
+// Get returns the hash kept for key, or "" when there is none
+func (s *RedisCodeStore) Get(ctx context.Context, key string) (string, error) {
+	hash, err := s.client.Get(ctx, verificationKeyPrefix+key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read verification code: %w", err)
+	}
+	return hash, nil
+}
+
+// Delete removes the hash kept for key
+func (s *RedisCodeStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, verificationKeyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to delete verification code: %w", err)
+	}
+	return nil
+}
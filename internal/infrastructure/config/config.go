@@ -1,98 +1,1316 @@
-package config
 // Package config manages application configuration
 package config
 
 import (
+	"database/sql"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return value	}		return defaultValue	if err != nil {	value, err := strconv.Atoi(valueStr)	}		return defaultValue	if valueStr == "" {	valueStr := os.Getenv(key)func getEnvAsInt(key string, defaultValue int) int {// getEnvAsInt retrieves environment variable as integer or returns default}	return value	}		return defaultValue	if value == "" {	value := os.Getenv(key)func getEnv(key, defaultValue string) string {// getEnv retrieves environment variable or returns default}	)		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",	return fmt.Sprintf(func (c *DatabaseConfig) GetDSN() string {// GetDSN returns PostgreSQL connection string}	return config, nil	}		return nil, fmt.Errorf("DB_PASSWORD is required")	if config.Database.Password == "" {	// Validate required fields	}		},			JWTSecret: getEnv("JWT_SECRET", "change-me-in-production"),		Security: SecurityConfig{		},			SSLMode:  getEnv("DB_SSLMODE", "disable"),			DBName:   getEnv("DB_NAME", "pay2go"),			Password: getEnv("DB_PASSWORD", ""),			User:     getEnv("DB_USER", "postgres"),			Port:     getEnv("DB_PORT", "5432"),			Host:     getEnv("DB_HOST", "localhost"),		Database: DatabaseConfig{		},			Host: getEnv("SERVER_HOST", "0.0.0.0"),			Port: getEnv("SERVER_PORT", "8080"),		Server: ServerConfig{	config := &Config{func Load() (*Config, error) {// Load loads configuration from environment variables}	JWTSecret stringtype SecurityConfig struct {// SecurityConfig holds security configuration}	SSLMode  string	DBName   string	Password string	User     string	Port     string	Host     stringtype DatabaseConfig struct {// DatabaseConfig holds database configuration}	Host string	Port stringtype ServerConfig struct {// ServerConfig holds server configuration}	Security SecurityConfig	Database DatabaseConfig	Server   ServerConfigtype Config struct {// Config holds all application configuration)
\ No newline at end of file
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/infrastructure/logger"
+)
+
+// Config holds all application configuration
+type Config struct {
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Security   SecurityConfig
+	Encryption EncryptionConfig
+	Refund     RefundConfig
+	Limits     LimitsConfig
+	Privacy    PrivacyConfig
+	Redis      RedisConfig
+	RateLimit  RateLimitConfig
+	Outbox     OutboxConfig
+	Worker     WorkerConfig
+	Health     HealthConfig
+	Audit      AuditConfig
+	Archive    ArchiveConfig
+	Exports    ExportsConfig
+	Statements StatementsConfig
+	Analytics  AnalyticsConfig
+	Reports    ReportsConfig
+	Cache      CacheConfig
+	Lock       LockConfig
+	HTTPClient HTTPClientConfig
+	Sentry     SentryConfig
+	Alerts     AlertsConfig
+	Providers  ProviderHealthConfig
+	Logging    LoggingConfig
+	Events     EventsConfig
+	AWS        AWSConfig
+	Settlement SettlementConfig
+	Email      EmailConfig
+	SMS        SMSConfig
+	// ProviderEvents holds how notifications from payment providers are
+	// verified and deduplicated
+	ProviderEvents ProviderEventsConfig
+}
+
+// ServerConfig holds server configuration
+type ServerConfig struct {
+	Port string
+	Host string
+	// ShutdownTimeoutSeconds is how long a stopping server waits for
+	// in-flight requests and background work before closing connections
+	ShutdownTimeoutSeconds int
+	// LogLevel is the least severe level logged at startup; admins can
+	// change it at runtime
+	LogLevel string
+	// AccessLogPath is the file the redacted access log is appended to,
+	// AccessLogStdout for standard output, or empty for no access log
+	AccessLogPath string
+	// AccessLogMaxBodyBytes is how much of each redacted request body is
+	// kept; 0 leaves bodies out
+	AccessLogMaxBodyBytes int
+	// GRPCAddr is the host:port the gRPC API for internal services listens
+	// on in clear-text HTTP/2; empty leaves it off
+	GRPCAddr string
+	// WebSocketPollIntervalMs is how often transaction WebSockets read
+	// their partner's new events from the outbox
+	WebSocketPollIntervalMs int
+	// V1DeprecatedAt is when API v1 was deprecated in favor of v2, and
+	// V1SunsetAt when it stops answering (zero if not decided); v1
+	// responses announce both
+	V1DeprecatedAt time.Time
+	V1SunsetAt     time.Time
+}
+
+// AccessLogStdout writes the access log to standard output
+const AccessLogStdout = "stdout"
+
+// Supported database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+)
+
+// DatabaseConfig holds database configuration
+type DatabaseConfig struct {
+	// Driver selects the database and its repositories: postgres, mysql or
+	// sqlite. With sqlite, DBName is the path of the database file and the
+	// server settings are unused.
+	Driver   string
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+	// AutoMigrate applies pending schema migrations when the API starts
+	AutoMigrate bool
+	// ReplicaDSN is the connection string of a read replica for read-only
+	// queries; empty reads everything from the primary
+	ReplicaDSN string
+
+	// Connection pool settings, applied to the primary and the replica.
+	// MaxOpenConns 0 means unlimited.
+	MaxOpenConns           int
+	MaxIdleConns           int
+	ConnMaxLifetimeMinutes int
+
+	// PartitionMonthsAhead is how many months of transaction and refund
+	// partitions are kept created beyond the current one (postgres only)
+	PartitionMonthsAhead int
+
+	// PreparedStatements runs the busiest queries as prepared statements;
+	// turn it off behind a pooler in transaction mode such as PgBouncer
+	PreparedStatements bool
+}
+
+// SecurityConfig holds security configuration
+type SecurityConfig struct {
+	JWTSecret string
+	// SessionTTLHours is how long a team member's session token stays valid
+	SessionTTLHours int
+	// AdminSessionTTLHours is how long a back-office admin's session token stays valid
+	AdminSessionTTLHours int
+	// AuthMaxFailuresPerKey locks out a key prefix from an IP after this many failed attempts
+	AuthMaxFailuresPerKey int
+	// AuthMaxFailuresPerIP locks out an IP after this many failed attempts across all keys
+	AuthMaxFailuresPerIP int
+	// AuthLockoutMinutes is the window failures are counted in, and so the lockout length
+	AuthLockoutMinutes int
+}
+
+// EncryptionConfig holds master keys for encrypting secrets at rest
+type EncryptionConfig struct {
+	// Keys maps key ID -> base64-encoded 32-byte master key
+	Keys map[string]string
+	// PrimaryKeyID selects the key new secrets are encrypted with; older keys only decrypt
+	PrimaryKeyID string
+	// AllowLegacy reads secrets stored in plaintext or encrypted without
+	// their owner, until they are rotated; leave it off otherwise
+	AllowLegacy bool
+}
+
+// RefundConfig holds platform-wide refund policy defaults
+type RefundConfig struct {
+	// DefaultWindowDays applies to partners without their own refund window
+	DefaultWindowDays int
+	// BulkPollIntervalSeconds is how often the API looks for queued bulk
+	// refund jobs
+	BulkPollIntervalSeconds int
+	// BulkClaimTimeoutSeconds is how long after its last saved item a bulk
+	// refund job is left to its instance before others resume it
+	BulkClaimTimeoutSeconds int
+}
+
+// LimitsConfig holds platform-wide transaction amount limits
+type LimitsConfig struct {
+	// MaxAmounts maps ISO 4217 code -> maximum transaction amount in major units,
+	// for partners without their own limit; other currencies default to 100000
+	MaxAmounts map[string]string
+}
+
+// PrivacyConfig holds personal data retention settings
+type PrivacyConfig struct {
+	// PIIRetentionDays is how long customer PII is kept after a partner is off-boarded
+	PIIRetentionDays int
+}
+
+// RedisConfig holds Redis configuration
+type RedisConfig struct {
+	// URL is a redis:// connection URL; empty disables Redis
+	URL string
+}
+
+// RateLimitConfig holds rate limiting configuration
+type RateLimitConfig struct {
+	// DefaultPerMinute applies to partners without their own limit
+	DefaultPerMinute int
+}
+
+// OutboxConfig holds transactional outbox relay settings
+type OutboxConfig struct {
+	// RelayEnabled runs the relay in this process
+	RelayEnabled bool
+	// PollIntervalSeconds is how often the relay looks for due events
+	PollIntervalSeconds int
+	// BatchSize caps how many events one pass publishes
+	BatchSize int
+	// WebhookTimeoutSeconds bounds each webhook delivery
+	WebhookTimeoutSeconds int
+	// Workers is how many webhooks the relay delivers at once
+	Workers int
+	// PartnerConcurrency caps the deliveries to one partner at once
+	PartnerConcurrency int
+	// ShardCount and ShardIndex split the outbox between relays: this one
+	// publishes the shards whose number modulo ShardCount is ShardIndex
+	ShardCount int
+	ShardIndex int
+	// ClaimTimeoutSeconds is how long events a relay claimed are left to
+	// it before other relays may claim them again
+	ClaimTimeoutSeconds int
+	// StreamPollIntervalSeconds is how often open event streams look for
+	// new events
+	StreamPollIntervalSeconds int
+}
+
+// WorkerConfig holds the settings of asynchronous payment processing
+type WorkerConfig struct {
+	// AsyncProcessing makes the API queue payments for cmd/worker instead of
+	// processing them in the request
+	AsyncProcessing bool
+	// PollIntervalMs is how often a worker looks for due jobs when the
+	// last pass found none
+	PollIntervalMs int
+	// BatchSize caps how many jobs one pass claims
+	BatchSize int
+	// Concurrency is how many payments a worker processes at once
+	Concurrency int
+	// ClaimTimeoutSeconds is how long jobs a worker claimed are left to it
+	// before other workers may claim them again
+	ClaimTimeoutSeconds int
+}
+
+// HealthConfig holds readiness check thresholds
+type HealthConfig struct {
+	// ReplicaMaxLagSeconds is the replica lag beyond which it is degraded
+	ReplicaMaxLagSeconds int
+	// OutboxMaxBacklog is the number of unpublished outbox events beyond
+	// which the outbox is degraded
+	OutboxMaxBacklog int
+	// HeartbeatMissedBeats is how many of its intervals a background loop
+	// may go without completing a pass before the API is not ready
+	HeartbeatMissedBeats int
+}
+
+// AuditConfig holds audit log integrity and writing settings
+type AuditConfig struct {
+	// VerifyIntervalMinutes is how often every audit chain is verified; 0
+	// turns the check off
+	VerifyIntervalMinutes int
+	// Async writes audit logs off the request path
+	Async bool
+	// BufferSize is how many actions wait to be written before logging falls
+	// back to writing inline
+	BufferSize int
+	// BatchSize is how many actions are written at once
+	BatchSize int
+	// FlushIntervalMs is the longest an action waits to be written
+	FlushIntervalMs int
+}
+
+// CacheConfig holds cache settings
+type CacheConfig struct {
+	// PartnerTTLSeconds is how long authentication may use a cached partner;
+	// 0 turns the partner cache off
+	PartnerTTLSeconds int
+	// PartnerCacheSize is how many partners each instance keeps in memory
+	PartnerCacheSize int
+	// TransactionTTLSeconds is how long GET /transactions/:id may answer
+	// from Redis; 0 turns the transaction cache off
+	TransactionTTLSeconds int
+	// ReceiptCacheSize is how many rendered receipts each instance keeps in
+	// memory; 0 renders every receipt
+	ReceiptCacheSize int
+}
+
+// HTTPClientConfig tunes the connection pool of outbound HTTP requests:
+// webhook deliveries, the S3 archive store and exchange rate lookups
+type HTTPClientConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps the connections to one host; 0 means no limit
+	MaxConnsPerHost            int
+	IdleConnTimeoutSeconds     int
+	DialTimeoutSeconds         int
+	TLSHandshakeTimeoutSeconds int
+	// ProxyURL routes outbound requests through a proxy; empty falls back
+	// to HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	ProxyURL string
+	// HTTP2 negotiates HTTP/2 with servers that support it
+	HTTP2 bool
+}
+
+// SentryConfig holds where errors and panics are reported
+type SentryConfig struct {
+	// DSN is the Sentry project's client key URL; empty turns reporting off
+	DSN         string
+	Environment string
+	Release     string
+	// SampleRate is the share of errors reported, from 0 to 1; panics are
+	// always reported
+	SampleRate float64
+	// BufferSize is how many reports wait to be sent before more are dropped
+	BufferSize int
+}
+
+// AlertsConfig holds what counts as slow and where sustained slowness is
+// alerted
+type AlertsConfig struct {
+	// SlowQueryThresholdMs and SlowGatewayThresholdMs are the durations
+	// above which a query or payment provider call is logged and counted;
+	// 0 turns the check off
+	SlowQueryThresholdMs   int
+	SlowGatewayThresholdMs int
+	// MinBreaches slow calls of one kind within WindowSeconds raise an
+	// alert, at most once per window
+	WindowSeconds int
+	MinBreaches   int
+	// SlackWebhookURL and TeamsWebhookURL are the webhooks alerts are
+	// posted to; with neither, problems are only logged and counted
+	SlackWebhookURL string
+	TeamsWebhookURL string
+	// WebhookFailureIntervalMinutes is the least time between two alerts
+	// about the failing webhook endpoint of one partner
+	WebhookFailureIntervalMinutes int
+	// DiscrepancyThreshold settlement discrepancies within
+	// DiscrepancyWindowMinutes raise an alert, at most once per window; 0
+	// turns the alert off
+	DiscrepancyThreshold     int
+	DiscrepancyWindowMinutes int
+}
+
+// Event brokers
+const (
+	EventBrokerKafka    = "kafka"
+	EventBrokerRabbitMQ = "rabbitmq"
+	EventBrokerNATS     = "nats"
+)
+
+// EventsConfig holds where outbox events are streamed for downstream
+// consumers such as analytics and risk
+type EventsConfig struct {
+	// Broker is EventBrokerKafka, EventBrokerRabbitMQ or EventBrokerNATS,
+	// or empty to stream no events
+	Broker string
+	// TopicPrefix is put before the topic of each aggregate type, as in
+	// pay2go.transactions
+	TopicPrefix string
+	// KafkaRESTURL is the Kafka REST Proxy produced through, authenticated
+	// with KafkaUsername and KafkaPassword when set
+	KafkaRESTURL  string
+	KafkaUsername string
+	KafkaPassword string
+	// RabbitMQURL is the AMQP broker published to, amqp:// or amqps:// with
+	// the credentials and vhost, on RabbitMQExchange
+	RabbitMQURL      string
+	RabbitMQExchange string
+	// NATSURL is the NATS server with JetStream, nats:// or tls://,
+	// authenticated with NATSUsername and NATSPassword or NATSToken when
+	// set; NATSCAFile verifies its certificate against a private CA
+	NATSURL      string
+	NATSUsername string
+	NATSPassword string
+	NATSToken    string
+	NATSCAFile   string
+}
+
+// AWSConfig holds Pay2Go's AWS credentials, used to assume the roles that
+// partners delivering events to their own SNS topic or SQS queue grant
+type AWSConfig struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Endpoint is empty for AWS, or a URL that emulates it for testing
+	Endpoint string
+}
+
+// Email providers
+const (
+	EmailProviderSMTP = "smtp"
+	EmailProviderSES  = "ses"
+)
+
+// EmailConfig holds how receipts, refund confirmations and webhook alerts
+// are emailed
+type EmailConfig struct {
+	// Provider is EmailProviderSMTP or EmailProviderSES, or empty to send
+	// no email
+	Provider string
+	// From is the sender, such as "Pay2Go <no-reply@pay2go.example>"; with
+	// SES it must be a verified identity
+	From string
+	// SMTPHost and SMTPPort locate the SMTP server, authenticated with
+	// SMTPUsername and SMTPPassword when set
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// SESRegion is the region of SES, called with the AWS credentials
+	SESRegion string
+	// BufferSize caps the emails waiting to be sent; more are dropped
+	BufferSize int
+	// WebhookAlertIntervalMinutes is the least time between two webhook
+	// alerts to one partner
+	WebhookAlertIntervalMinutes int
+}
+
+// ProviderEventsConfig holds the secrets payment providers sign their
+// notifications with; a provider without one has its notifications refused
+type ProviderEventsConfig struct {
+	StripeWebhookSecret string
+	// AdyenHMACKey is hex, as the Customer Area shows it
+	AdyenHMACKey string
+	// DedupTTLHours is how long a handled event is remembered, so a
+	// duplicate delivery is skipped; longer than providers keep retrying
+	DedupTTLHours int
+}
+
+// SMS providers
+const (
+	SMSProviderTwilio = "twilio"
+)
+
+// SMSConfig holds how payment confirmations and verification codes are
+// texted to customers
+type SMSConfig struct {
+	// Provider is SMSProviderTwilio, or empty to send no text messages
+	Provider         string
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	// From is the sender of partners without their own: a number of the
+	// account, a sender name or a Messaging Service SID
+	From string
+	// PartnerLimitPerHour and RecipientLimitPerHour bound the messages of
+	// one partner and to one phone number
+	PartnerLimitPerHour   int
+	RecipientLimitPerHour int
+	// VerificationTTLMinutes is how long a verification code lasts, and
+	// VerificationMaxAttempts how many wrong codes discard it
+	VerificationTTLMinutes  int
+	VerificationMaxAttempts int
+}
+
+// Settlement feed sources
+const (
+	SettlementSourceKafka     = "kafka"
+	SettlementSourceDirectory = "directory"
+)
+
+// SettlementConfig holds where cmd/settlement reads providers' settlement
+// and chargeback feeds
+type SettlementConfig struct {
+	// Source is SettlementSourceKafka or SettlementSourceDirectory
+	Source string
+	// KafkaTopic is consumed through the Kafka REST Proxy of
+	// Events.KafkaRESTURL, as KafkaGroup
+	KafkaTopic string
+	KafkaGroup string
+	// DropDirectory is where providers upload settlement files, such as the
+	// home of an SFTP account; reconciled files are moved to its processed
+	// subdirectory
+	DropDirectory string
+	// PollIntervalSeconds is how often the feed is checked when it had no
+	// entries
+	PollIntervalSeconds int
+}
+
+// Log sinks
+const (
+	LogSinkStdout = "stdout"
+	LogSinkFile   = "file"
+	LogSinkHTTP   = "http"
+)
+
+// LoggingConfig holds where application logs are shipped
+type LoggingConfig struct {
+	// Sink is LogSinkStdout, LogSinkFile or LogSinkHTTP
+	Sink string
+	// Format is text or json, for the stdout and file sinks; the HTTP sink
+	// always ships JSON
+	Format string
+	// FilePath is the file of the file sink, rotated at FileMaxSizeMB
+	// keeping FileMaxBackups rotated files
+	FilePath       string
+	FileMaxSizeMB  int
+	FileMaxBackups int
+	// HTTPURL is the collector of the HTTP sink, speaking HTTPCollector:
+	// loki or json
+	HTTPURL       string
+	HTTPCollector string
+	// Entries are shipped in batches of up to HTTPBatchSize, at least every
+	// HTTPFlushIntervalMs; beyond HTTPBufferSize waiting they are dropped
+	HTTPBatchSize       int
+	HTTPFlushIntervalMs int
+	HTTPBufferSize      int
+}
+
+// ProviderHealthConfig holds how payment providers are watched for
+// degradation
+type ProviderHealthConfig struct {
+	// PollIntervalSeconds is how often providers are probed; 0 stops
+	// probing, leaving live payments and refunds alone to count
+	PollIntervalSeconds int
+	// StatusURLs maps providers to status endpoints answering 2xx while
+	// they are healthy
+	StatusURLs map[string]string
+	// CanaryTransactionID is a payment looked up on the platform account
+	// on every poll; empty skips the lookup
+	CanaryTransactionID string
+	// A provider is degraded while at least MinCalls of its calls within
+	// WindowSeconds happened and DegradedErrorRate or more of them failed
+	WindowSeconds     int
+	MinCalls          int
+	DegradedErrorRate float64
+}
+
+// LockConfig holds the settings of the locks shared by every instance
+type LockConfig struct {
+	// PaymentTTLSeconds is the longest a transaction stays locked for
+	// processing if its instance dies before releasing it
+	PaymentTTLSeconds int
+}
+
+// Archive stores
+const (
+	ArchiveStoreS3   = "s3"
+	ArchiveStoreFile = "file"
+)
+
+// ArchiveConfig holds where old audit entries and published outbox events
+// are moved to
+type ArchiveConfig struct {
+	// Store is ArchiveStoreS3, ArchiveStoreFile or empty to keep everything
+	// in the database
+	Store string
+	// Dir is the directory of ArchiveStoreFile
+	Dir string
+	// S3Endpoint is empty for Amazon S3, or the URL of a compatible service
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// RetentionDays is how long entries and events stay in the database
+	RetentionDays int
+	// IntervalMinutes is how often they are moved
+	IntervalMinutes int
+}
+
+// ExportsConfig holds where the files of export jobs, and the logos of
+// partners' receipts, are stored and how exports are produced and
+// downloaded
+type ExportsConfig struct {
+	// Store is ArchiveStoreS3 or ArchiveStoreFile
+	Store string
+	// Dir is the directory of ArchiveStoreFile
+	Dir string
+	// S3Endpoint is empty for Amazon S3, or the URL of a compatible service
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// SigningKey signs download URLs; changing it breaks the URLs handed out.
+	// It is required, and must not be the JWT secret.
+	SigningKey string
+	// URLTTLMinutes is how long a download URL works
+	URLTTLMinutes int
+	// PollIntervalSeconds is how often the API looks for queued exports
+	PollIntervalSeconds int
+	// ClaimTimeoutSeconds is how long the exports an instance claimed are
+	// left to it before others may claim them again
+	ClaimTimeoutSeconds int
+}
+
+// StatementsConfig holds when partners' monthly statements are issued and
+// the standard pricing they charge partners without pricing of their own.
+// Documents are kept in the export store.
+type StatementsConfig struct {
+	// IntervalMinutes is how often the API issues the statements of last
+	// month that are due; 0 issues them only on request
+	IntervalMinutes int
+	// FeePercent and FeeFixed, in minor units of the payment's currency,
+	// are the standard fee of a payment
+	FeePercent float64
+	FeeFixed   int
+	// ChargebackFee is the standard fee of a chargeback, in minor units of
+	// its currency
+	ChargebackFee int
+}
+
+// AnalyticsConfig holds how the daily analytics rollups are kept up to
+// date
+type AnalyticsConfig struct {
+	// IntervalMinutes is how often the API rebuilds the last days'
+	// rollups; 0 stops rolling up
+	IntervalMinutes int
+	// LookbackDays is how many days up to today each run rebuilds, as
+	// payments settle and are refunded after the day they are made
+	LookbackDays int
+	// BackfillDays is how many days the first run rolls up
+	BackfillDays int
+}
+
+// ReportsConfig holds how partners' scheduled reports are delivered
+type ReportsConfig struct {
+	// IntervalSeconds is how often the API looks for reports that are due;
+	// 0 delivers them only when partners run a schedule
+	IntervalSeconds int
+	// ClaimTimeoutSeconds is how long the schedules an instance claimed are
+	// left to it before others may claim them again
+	ClaimTimeoutSeconds int
+	// SFTPTimeoutSeconds bounds an upload to a partner's SFTP server
+	SFTPTimeoutSeconds int
+}
+
+// Load loads configuration from environment variables
+func Load() (*Config, error) {
+	config := &Config{
+		Server: ServerConfig{
+			Port: getEnv("SERVER_PORT", "8080"),
+			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+
+			ShutdownTimeoutSeconds: getEnvAsInt("SHUTDOWN_TIMEOUT_SECONDS", 30),
+			LogLevel:               getEnv("LOG_LEVEL", "info"),
+			AccessLogPath:          getEnv("ACCESS_LOG_PATH", ""),
+			AccessLogMaxBodyBytes:  getEnvAsInt("ACCESS_LOG_MAX_BODY_BYTES", 4096),
+			GRPCAddr:               getEnv("GRPC_ADDR", ""),
+
+			WebSocketPollIntervalMs: getEnvAsInt("WEBSOCKET_POLL_INTERVAL_MS", 1000),
+		},
+		Database: DatabaseConfig{
+			Driver:   getEnv("DB_DRIVER", DriverPostgres),
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnv("DB_PORT", ""),
+			User:     getEnv("DB_USER", "postgres"),
+			Password: getEnv("DB_PASSWORD", ""),
+			DBName:   getEnv("DB_NAME", "pay2go"),
+			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", false),
+			ReplicaDSN:  getEnv("DB_REPLICA_DSN", ""),
+
+			MaxOpenConns:           getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:           getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetimeMinutes: getEnvAsInt("DB_CONN_MAX_LIFETIME_MINUTES", 5),
+
+			PartitionMonthsAhead: getEnvAsInt("DB_PARTITION_MONTHS_AHEAD", 3),
+
+			PreparedStatements: getEnvAsBool("DB_PREPARED_STATEMENTS", true),
+		},
+		Security: SecurityConfig{
+			JWTSecret:             getEnv("JWT_SECRET", "change-me-in-production"),
+			SessionTTLHours:       getEnvAsInt("SESSION_TTL_HOURS", 12),
+			AdminSessionTTLHours:  getEnvAsInt("ADMIN_SESSION_TTL_HOURS", 8),
+			AuthMaxFailuresPerKey: getEnvAsInt("AUTH_MAX_FAILURES_PER_KEY", 5),
+			AuthMaxFailuresPerIP:  getEnvAsInt("AUTH_MAX_FAILURES_PER_IP", 20),
+			AuthLockoutMinutes:    getEnvAsInt("AUTH_LOCKOUT_MINUTES", 15),
+		},
+		Encryption: EncryptionConfig{
+			Keys:         getEnvAsPairs("ENCRYPTION_KEYS"),
+			PrimaryKeyID: getEnv("ENCRYPTION_PRIMARY_KEY_ID", ""),
+			AllowLegacy:  getEnvAsBool("ENCRYPTION_ALLOW_LEGACY", false),
+		},
+		Refund: RefundConfig{
+			DefaultWindowDays:       getEnvAsInt("REFUND_WINDOW_DAYS", 90),
+			BulkPollIntervalSeconds: getEnvAsInt("BULK_REFUND_POLL_INTERVAL_SECONDS", 5),
+			BulkClaimTimeoutSeconds: getEnvAsInt("BULK_REFUND_CLAIM_TIMEOUT_SECONDS", 300),
+		},
+		Limits: LimitsConfig{
+			MaxAmounts: getEnvAsPairs("MAX_TRANSACTION_AMOUNTS"),
+		},
+		Privacy: PrivacyConfig{
+			PIIRetentionDays: getEnvAsInt("PII_RETENTION_DAYS", 90),
+		},
+		Redis: RedisConfig{
+			URL: getEnv("REDIS_URL", ""),
+		},
+		RateLimit: RateLimitConfig{
+			DefaultPerMinute: getEnvAsInt("RATE_LIMIT_PER_MINUTE", 100),
+		},
+		Outbox: OutboxConfig{
+			RelayEnabled:          getEnvAsBool("OUTBOX_RELAY_ENABLED", true),
+			PollIntervalSeconds:   getEnvAsInt("OUTBOX_POLL_INTERVAL_SECONDS", 5),
+			BatchSize:             getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			WebhookTimeoutSeconds: getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+			Workers:               getEnvAsInt("WEBHOOK_WORKERS", 8),
+			PartnerConcurrency:    getEnvAsInt("WEBHOOK_PARTNER_CONCURRENCY", 2),
+			ShardCount:            getEnvAsInt("OUTBOX_SHARD_COUNT", 1),
+			ShardIndex:            getEnvAsInt("OUTBOX_SHARD_INDEX", 0),
+			ClaimTimeoutSeconds:   getEnvAsInt("OUTBOX_CLAIM_TIMEOUT_SECONDS", 600),
+
+			StreamPollIntervalSeconds: getEnvAsInt("EVENT_STREAM_POLL_INTERVAL_SECONDS", 2),
+		},
+		Worker: WorkerConfig{
+			AsyncProcessing:     getEnvAsBool("PAYMENT_ASYNC_PROCESSING", false),
+			PollIntervalMs:      getEnvAsInt("WORKER_POLL_INTERVAL_MS", 500),
+			BatchSize:           getEnvAsInt("WORKER_BATCH_SIZE", 20),
+			Concurrency:         getEnvAsInt("WORKER_CONCURRENCY", 4),
+			ClaimTimeoutSeconds: getEnvAsInt("WORKER_CLAIM_TIMEOUT_SECONDS", 900),
+		},
+		Health: HealthConfig{
+			ReplicaMaxLagSeconds: getEnvAsInt("HEALTH_REPLICA_MAX_LAG_SECONDS", 30),
+			OutboxMaxBacklog:     getEnvAsInt("HEALTH_OUTBOX_MAX_BACKLOG", 1000),
+			HeartbeatMissedBeats: getEnvAsInt("HEALTH_HEARTBEAT_MISSED_BEATS", 3),
+		},
+		Audit: AuditConfig{
+			VerifyIntervalMinutes: getEnvAsInt("AUDIT_VERIFY_INTERVAL_MINUTES", 60),
+			Async:                 getEnvAsBool("AUDIT_ASYNC", true),
+			BufferSize:            getEnvAsInt("AUDIT_BUFFER_SIZE", 10000),
+			BatchSize:             getEnvAsInt("AUDIT_BATCH_SIZE", 100),
+			FlushIntervalMs:       getEnvAsInt("AUDIT_FLUSH_INTERVAL_MS", 200),
+		},
+		Archive: ArchiveConfig{
+			Store:             getEnv("ARCHIVE_STORE", ""),
+			Dir:               getEnv("ARCHIVE_DIR", "archive"),
+			S3Endpoint:        getEnv("ARCHIVE_S3_ENDPOINT", ""),
+			S3Region:          getEnv("ARCHIVE_S3_REGION", "us-east-1"),
+			S3Bucket:          getEnv("ARCHIVE_S3_BUCKET", ""),
+			S3AccessKeyID:     getEnv("ARCHIVE_S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("ARCHIVE_S3_SECRET_ACCESS_KEY", ""),
+			RetentionDays:     getEnvAsInt("ARCHIVE_RETENTION_DAYS", 90),
+			IntervalMinutes:   getEnvAsInt("ARCHIVE_INTERVAL_MINUTES", 60),
+		},
+		Exports: ExportsConfig{
+			Store:               getEnv("EXPORT_STORE", ArchiveStoreFile),
+			Dir:                 getEnv("EXPORT_DIR", "exports"),
+			S3Endpoint:          getEnv("EXPORT_S3_ENDPOINT", ""),
+			S3Region:            getEnv("EXPORT_S3_REGION", "us-east-1"),
+			S3Bucket:            getEnv("EXPORT_S3_BUCKET", ""),
+			S3AccessKeyID:       getEnv("EXPORT_S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey:   getEnv("EXPORT_S3_SECRET_ACCESS_KEY", ""),
+			SigningKey:          getEnv("EXPORT_SIGNING_KEY", ""),
+			URLTTLMinutes:       getEnvAsInt("EXPORT_URL_TTL_MINUTES", 15),
+			PollIntervalSeconds: getEnvAsInt("EXPORT_POLL_INTERVAL_SECONDS", 5),
+			ClaimTimeoutSeconds: getEnvAsInt("EXPORT_CLAIM_TIMEOUT_SECONDS", 1800),
+		},
+		Statements: StatementsConfig{
+			IntervalMinutes: getEnvAsInt("STATEMENT_INTERVAL_MINUTES", 60),
+			FeePercent:      getEnvAsFloat("STATEMENT_FEE_PERCENT", 2.9),
+			FeeFixed:        getEnvAsInt("STATEMENT_FEE_FIXED", 30),
+			ChargebackFee:   getEnvAsInt("STATEMENT_CHARGEBACK_FEE", 1500),
+		},
+		Analytics: AnalyticsConfig{
+			IntervalMinutes: getEnvAsInt("ANALYTICS_INTERVAL_MINUTES", 15),
+			LookbackDays:    getEnvAsInt("ANALYTICS_LOOKBACK_DAYS", 3),
+			BackfillDays:    getEnvAsInt("ANALYTICS_BACKFILL_DAYS", 90),
+		},
+		Reports: ReportsConfig{
+			IntervalSeconds:     getEnvAsInt("REPORT_INTERVAL_SECONDS", 60),
+			ClaimTimeoutSeconds: getEnvAsInt("REPORT_CLAIM_TIMEOUT_SECONDS", 1800),
+			SFTPTimeoutSeconds:  getEnvAsInt("REPORT_SFTP_TIMEOUT_SECONDS", 120),
+		},
+		Cache: CacheConfig{
+			PartnerTTLSeconds: getEnvAsInt("PARTNER_CACHE_TTL_SECONDS", 30),
+			PartnerCacheSize:  getEnvAsInt("PARTNER_CACHE_SIZE", 10000),
+
+			TransactionTTLSeconds: getEnvAsInt("TRANSACTION_CACHE_TTL_SECONDS", 0),
+			ReceiptCacheSize:      getEnvAsInt("RECEIPT_CACHE_SIZE", 200),
+		},
+		Lock: LockConfig{
+			PaymentTTLSeconds: getEnvAsInt("PAYMENT_LOCK_TTL_SECONDS", 120),
+		},
+		HTTPClient: HTTPClientConfig{
+			MaxIdleConns:               getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS", 100),
+			MaxIdleConnsPerHost:        getEnvAsInt("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", 10),
+			MaxConnsPerHost:            getEnvAsInt("HTTP_CLIENT_MAX_CONNS_PER_HOST", 0),
+			IdleConnTimeoutSeconds:     getEnvAsInt("HTTP_CLIENT_IDLE_CONN_TIMEOUT_SECONDS", 90),
+			DialTimeoutSeconds:         getEnvAsInt("HTTP_CLIENT_DIAL_TIMEOUT_SECONDS", 5),
+			TLSHandshakeTimeoutSeconds: getEnvAsInt("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT_SECONDS", 5),
+			ProxyURL:                   getEnv("HTTP_CLIENT_PROXY_URL", ""),
+			HTTP2:                      getEnvAsBool("HTTP_CLIENT_HTTP2", true),
+		},
+		Sentry: SentryConfig{
+			DSN:         getEnv("SENTRY_DSN", ""),
+			Environment: getEnv("SENTRY_ENVIRONMENT", "production"),
+			Release:     getEnv("SENTRY_RELEASE", ""),
+			SampleRate:  getEnvAsFloat("SENTRY_SAMPLE_RATE", 1),
+			BufferSize:  getEnvAsInt("SENTRY_BUFFER_SIZE", 100),
+		},
+		Alerts: AlertsConfig{
+			SlowQueryThresholdMs:          getEnvAsInt("SLOW_QUERY_THRESHOLD_MS", 500),
+			SlowGatewayThresholdMs:        getEnvAsInt("SLOW_GATEWAY_THRESHOLD_MS", 5000),
+			WindowSeconds:                 getEnvAsInt("SLOW_ALERT_WINDOW_SECONDS", 300),
+			MinBreaches:                   getEnvAsInt("SLOW_ALERT_MIN_BREACHES", 20),
+			SlackWebhookURL:               getEnv("ALERT_SLACK_WEBHOOK_URL", ""),
+			TeamsWebhookURL:               getEnv("ALERT_TEAMS_WEBHOOK_URL", ""),
+			WebhookFailureIntervalMinutes: getEnvAsInt("ALERT_WEBHOOK_FAILURE_INTERVAL_MINUTES", 60),
+			DiscrepancyThreshold:          getEnvAsInt("ALERT_DISCREPANCY_THRESHOLD", 10),
+			DiscrepancyWindowMinutes:      getEnvAsInt("ALERT_DISCREPANCY_WINDOW_MINUTES", 60),
+		},
+		Providers: ProviderHealthConfig{
+			PollIntervalSeconds: getEnvAsInt("PROVIDER_POLL_INTERVAL_SECONDS", 30),
+			StatusURLs:          getEnvAsPairs("PROVIDER_STATUS_URLS"),
+			CanaryTransactionID: getEnv("PROVIDER_CANARY_TRANSACTION_ID", ""),
+			WindowSeconds:       getEnvAsInt("PROVIDER_ERROR_WINDOW_SECONDS", 300),
+			MinCalls:            getEnvAsInt("PROVIDER_MIN_CALLS", 10),
+			DegradedErrorRate:   getEnvAsFloat("PROVIDER_DEGRADED_ERROR_RATE", 0.25),
+		},
+		Events: EventsConfig{
+			Broker:        getEnv("EVENT_BROKER", ""),
+			TopicPrefix:   getEnv("EVENT_TOPIC_PREFIX", "pay2go."),
+			KafkaRESTURL:  getEnv("KAFKA_REST_URL", ""),
+			KafkaUsername: getEnv("KAFKA_USERNAME", ""),
+			KafkaPassword: getEnv("KAFKA_PASSWORD", ""),
+
+			RabbitMQURL:      getEnv("RABBITMQ_URL", ""),
+			RabbitMQExchange: getEnv("RABBITMQ_EXCHANGE", "pay2go.events"),
+
+			NATSURL:      getEnv("NATS_URL", ""),
+			NATSUsername: getEnv("NATS_USERNAME", ""),
+			NATSPassword: getEnv("NATS_PASSWORD", ""),
+			NATSToken:    getEnv("NATS_TOKEN", ""),
+			NATSCAFile:   getEnv("NATS_CA_FILE", ""),
+		},
+		AWS: AWSConfig{
+			AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			SessionToken:    getEnv("AWS_SESSION_TOKEN", ""),
+			Endpoint:        getEnv("AWS_ENDPOINT_URL", ""),
+		},
+		Email: EmailConfig{
+			Provider:                    getEnv("EMAIL_PROVIDER", ""),
+			From:                        getEnv("EMAIL_FROM", ""),
+			SMTPHost:                    getEnv("SMTP_HOST", ""),
+			SMTPPort:                    getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername:                getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:                getEnv("SMTP_PASSWORD", ""),
+			SESRegion:                   getEnv("SES_REGION", ""),
+			BufferSize:                  getEnvAsInt("EMAIL_BUFFER_SIZE", 1000),
+			WebhookAlertIntervalMinutes: getEnvAsInt("WEBHOOK_ALERT_INTERVAL_MINUTES", 1440),
+		},
+		SMS: SMSConfig{
+			Provider:                getEnv("SMS_PROVIDER", ""),
+			TwilioAccountSID:        getEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:         getEnv("TWILIO_AUTH_TOKEN", ""),
+			From:                    getEnv("SMS_FROM", ""),
+			PartnerLimitPerHour:     getEnvAsInt("SMS_PARTNER_LIMIT_PER_HOUR", 1000),
+			RecipientLimitPerHour:   getEnvAsInt("SMS_RECIPIENT_LIMIT_PER_HOUR", 5),
+			VerificationTTLMinutes:  getEnvAsInt("SMS_VERIFICATION_TTL_MINUTES", 10),
+			VerificationMaxAttempts: getEnvAsInt("SMS_VERIFICATION_MAX_ATTEMPTS", 5),
+		},
+		ProviderEvents: ProviderEventsConfig{
+			StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			AdyenHMACKey:        getEnv("ADYEN_HMAC_KEY", ""),
+			DedupTTLHours:       getEnvAsInt("PROVIDER_EVENT_DEDUP_TTL_HOURS", 72),
+		},
+		Settlement: SettlementConfig{
+			Source:              getEnv("SETTLEMENT_SOURCE", SettlementSourceDirectory),
+			KafkaTopic:          getEnv("SETTLEMENT_KAFKA_TOPIC", "settlements"),
+			KafkaGroup:          getEnv("SETTLEMENT_KAFKA_GROUP", "pay2go-settlement"),
+			DropDirectory:       getEnv("SETTLEMENT_DROP_DIR", "./settlements"),
+			PollIntervalSeconds: getEnvAsInt("SETTLEMENT_POLL_INTERVAL_SECONDS", 30),
+		},
+		Logging: LoggingConfig{
+			Sink:                getEnv("LOG_SINK", LogSinkStdout),
+			Format:              getEnv("LOG_FORMAT", "text"),
+			FilePath:            getEnv("LOG_FILE_PATH", "pay2go.log"),
+			FileMaxSizeMB:       getEnvAsInt("LOG_FILE_MAX_SIZE_MB", 100),
+			FileMaxBackups:      getEnvAsInt("LOG_FILE_MAX_BACKUPS", 5),
+			HTTPURL:             getEnv("LOG_HTTP_URL", ""),
+			HTTPCollector:       getEnv("LOG_HTTP_COLLECTOR", "loki"),
+			HTTPBatchSize:       getEnvAsInt("LOG_HTTP_BATCH_SIZE", 500),
+			HTTPFlushIntervalMs: getEnvAsInt("LOG_HTTP_FLUSH_INTERVAL_MS", 1000),
+			HTTPBufferSize:      getEnvAsInt("LOG_HTTP_BUFFER_SIZE", 10000),
+		},
+	}
+
+	// API v1 deprecation dates
+	var err error
+	if config.Server.V1DeprecatedAt, err = getEnvAsDate("API_V1_DEPRECATED_AT", "2026-10-17"); err != nil {
+		return nil, err
+	}
+	if config.Server.V1SunsetAt, err = getEnvAsDate("API_V1_SUNSET_AT", ""); err != nil {
+		return nil, err
+	}
+
+	// Validate required fields
+	switch config.Database.Driver {
+	case DriverPostgres:
+		if config.Database.Port == "" {
+			config.Database.Port = "5432"
+		}
+	case DriverMySQL:
+		if config.Database.Port == "" {
+			config.Database.Port = "3306"
+		}
+	case DriverSQLite:
+		if config.Database.ReplicaDSN != "" {
+			return nil, fmt.Errorf("DB_REPLICA_DSN is not supported with sqlite")
+		}
+	default:
+		return nil, fmt.Errorf("DB_DRIVER must be postgres, mysql or sqlite")
+	}
+	if config.Database.Password == "" && config.Database.Driver != DriverSQLite {
+		return nil, fmt.Errorf("DB_PASSWORD is required")
+	}
+	if len(config.Encryption.Keys) == 0 {
+		return nil, fmt.Errorf("ENCRYPTION_KEYS is required")
+	}
+	if config.Encryption.PrimaryKeyID == "" {
+		return nil, fmt.Errorf("ENCRYPTION_PRIMARY_KEY_ID is required")
+	}
+	if config.Database.MaxOpenConns < 0 || config.Database.MaxIdleConns < 0 || config.Database.ConnMaxLifetimeMinutes < 0 {
+		return nil, fmt.Errorf("DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and DB_CONN_MAX_LIFETIME_MINUTES must not be negative")
+	}
+	if config.Database.PartitionMonthsAhead < 0 {
+		return nil, fmt.Errorf("DB_PARTITION_MONTHS_AHEAD must not be negative")
+	}
+	if config.Security.SessionTTLHours < 1 {
+		return nil, fmt.Errorf("SESSION_TTL_HOURS must be at least 1")
+	}
+	if config.Security.AdminSessionTTLHours < 1 {
+		return nil, fmt.Errorf("ADMIN_SESSION_TTL_HOURS must be at least 1")
+	}
+	if config.Security.AuthMaxFailuresPerKey < 1 || config.Security.AuthMaxFailuresPerIP < 1 {
+		return nil, fmt.Errorf("AUTH_MAX_FAILURES_PER_KEY and AUTH_MAX_FAILURES_PER_IP must be at least 1")
+	}
+	if config.Security.AuthLockoutMinutes < 1 {
+		return nil, fmt.Errorf("AUTH_LOCKOUT_MINUTES must be at least 1")
+	}
+	if config.Refund.DefaultWindowDays < 1 {
+		return nil, fmt.Errorf("REFUND_WINDOW_DAYS must be at least 1")
+	}
+	if config.Refund.BulkPollIntervalSeconds < 1 || config.Refund.BulkClaimTimeoutSeconds < 1 {
+		return nil, fmt.Errorf("BULK_REFUND_POLL_INTERVAL_SECONDS and BULK_REFUND_CLAIM_TIMEOUT_SECONDS must be at least 1")
+	}
+	if config.Privacy.PIIRetentionDays < 0 {
+		return nil, fmt.Errorf("PII_RETENTION_DAYS must not be negative")
+	}
+	if config.Outbox.PollIntervalSeconds < 1 || config.Outbox.BatchSize < 1 || config.Outbox.WebhookTimeoutSeconds < 1 {
+		return nil, fmt.Errorf("OUTBOX_POLL_INTERVAL_SECONDS, OUTBOX_BATCH_SIZE and WEBHOOK_TIMEOUT_SECONDS must be at least 1")
+	}
+	if config.Server.WebSocketPollIntervalMs < 100 {
+		return nil, fmt.Errorf("WEBSOCKET_POLL_INTERVAL_MS must be at least 100")
+	}
+	if config.Outbox.StreamPollIntervalSeconds < 1 {
+		return nil, fmt.Errorf("EVENT_STREAM_POLL_INTERVAL_SECONDS must be at least 1")
+	}
+	if config.Outbox.Workers < 1 || config.Outbox.PartnerConcurrency < 1 {
+		return nil, fmt.Errorf("WEBHOOK_WORKERS and WEBHOOK_PARTNER_CONCURRENCY must be at least 1")
+	}
+	if config.Outbox.ShardCount < 1 || config.Outbox.ShardCount > entities.OutboxShards {
+		return nil, fmt.Errorf("OUTBOX_SHARD_COUNT must be between 1 and %d", entities.OutboxShards)
+	}
+	if config.Outbox.ShardIndex < 0 || config.Outbox.ShardIndex >= config.Outbox.ShardCount {
+		return nil, fmt.Errorf("OUTBOX_SHARD_INDEX must be between 0 and OUTBOX_SHARD_COUNT - 1")
+	}
+	// A pass must finish within its claim, or another relay could deliver
+	// its last events again. The slowest pass is a full batch for one
+	// partner whose endpoint times out every time.
+	concurrency := min(config.Outbox.Workers, config.Outbox.PartnerConcurrency)
+	slowestPass := (config.Outbox.BatchSize + concurrency - 1) / concurrency * config.Outbox.WebhookTimeoutSeconds
+	if config.Outbox.ClaimTimeoutSeconds <= slowestPass {
+		return nil, fmt.Errorf("OUTBOX_CLAIM_TIMEOUT_SECONDS must be above %d, the longest a pass of OUTBOX_BATCH_SIZE webhooks to one partner can take", slowestPass)
+	}
+	if config.Health.ReplicaMaxLagSeconds < 0 || config.Health.OutboxMaxBacklog < 0 {
+		return nil, fmt.Errorf("HEALTH_REPLICA_MAX_LAG_SECONDS and HEALTH_OUTBOX_MAX_BACKLOG must not be negative")
+	}
+	if config.Health.HeartbeatMissedBeats < 1 {
+		return nil, fmt.Errorf("HEALTH_HEARTBEAT_MISSED_BEATS must be at least 1")
+	}
+	if config.Audit.VerifyIntervalMinutes < 0 {
+		return nil, fmt.Errorf("AUDIT_VERIFY_INTERVAL_MINUTES must not be negative")
+	}
+	if config.Audit.BufferSize < 1 || config.Audit.BatchSize < 1 || config.Audit.FlushIntervalMs < 1 {
+		return nil, fmt.Errorf("AUDIT_BUFFER_SIZE, AUDIT_BATCH_SIZE and AUDIT_FLUSH_INTERVAL_MS must be at least 1")
+	}
+	switch config.Archive.Store {
+	case "", ArchiveStoreFile:
+	case ArchiveStoreS3:
+		if config.Archive.S3Bucket == "" {
+			return nil, fmt.Errorf("ARCHIVE_S3_BUCKET is required with ARCHIVE_STORE=s3")
+		}
+	default:
+		return nil, fmt.Errorf("ARCHIVE_STORE must be s3, file or empty")
+	}
+	switch config.Exports.Store {
+	case ArchiveStoreFile:
+	case ArchiveStoreS3:
+		if config.Exports.S3Bucket == "" {
+			return nil, fmt.Errorf("EXPORT_S3_BUCKET is required with EXPORT_STORE=s3")
+		}
+	default:
+		return nil, fmt.Errorf("EXPORT_STORE must be s3 or file")
+	}
+	switch config.Exports.SigningKey {
+	case "":
+		return nil, fmt.Errorf("EXPORT_SIGNING_KEY is required")
+	case "change-me-in-production", config.Security.JWTSecret:
+		return nil, fmt.Errorf("EXPORT_SIGNING_KEY must be a key of its own, not the default or JWT_SECRET")
+	}
+	if config.Exports.URLTTLMinutes < 1 || config.Exports.PollIntervalSeconds < 1 || config.Exports.ClaimTimeoutSeconds < 1 {
+		return nil, fmt.Errorf("EXPORT_URL_TTL_MINUTES, EXPORT_POLL_INTERVAL_SECONDS and EXPORT_CLAIM_TIMEOUT_SECONDS must be at least 1")
+	}
+	if config.Statements.IntervalMinutes < 0 {
+		return nil, fmt.Errorf("STATEMENT_INTERVAL_MINUTES must not be negative")
+	}
+	if config.Statements.FeePercent < 0 || config.Statements.FeePercent > 100 || config.Statements.FeeFixed < 0 || config.Statements.ChargebackFee < 0 {
+		return nil, fmt.Errorf("STATEMENT_FEE_PERCENT must be between 0 and 100, and STATEMENT_FEE_FIXED and STATEMENT_CHARGEBACK_FEE must not be negative")
+	}
+	if config.Analytics.IntervalMinutes < 0 {
+		return nil, fmt.Errorf("ANALYTICS_INTERVAL_MINUTES must not be negative")
+	}
+	if config.Analytics.LookbackDays < 1 || config.Analytics.BackfillDays < config.Analytics.LookbackDays {
+		return nil, fmt.Errorf("ANALYTICS_LOOKBACK_DAYS must be at least 1, and ANALYTICS_BACKFILL_DAYS at least ANALYTICS_LOOKBACK_DAYS")
+	}
+	if config.Reports.IntervalSeconds < 0 {
+		return nil, fmt.Errorf("REPORT_INTERVAL_SECONDS must not be negative")
+	}
+	if config.Reports.ClaimTimeoutSeconds < 1 || config.Reports.SFTPTimeoutSeconds < 1 {
+		return nil, fmt.Errorf("REPORT_CLAIM_TIMEOUT_SECONDS and REPORT_SFTP_TIMEOUT_SECONDS must be at least 1")
+	}
+	if config.Archive.RetentionDays < 1 || config.Archive.IntervalMinutes < 1 {
+		return nil, fmt.Errorf("ARCHIVE_RETENTION_DAYS and ARCHIVE_INTERVAL_MINUTES must be at least 1")
+	}
+	if config.Cache.PartnerTTLSeconds < 0 || config.Cache.PartnerCacheSize < 1 {
+		return nil, fmt.Errorf("PARTNER_CACHE_TTL_SECONDS must not be negative and PARTNER_CACHE_SIZE must be at least 1")
+	}
+	if _, err := logger.ParseLevel(config.Server.LogLevel); err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error")
+	}
+	if _, err := logger.ParseFormat(config.Logging.Format); err != nil {
+		return nil, fmt.Errorf("LOG_FORMAT must be text or json")
+	}
+	switch config.Logging.Sink {
+	case LogSinkStdout:
+	case LogSinkFile:
+		if config.Logging.FilePath == "" || config.Logging.FileMaxSizeMB < 1 || config.Logging.FileMaxBackups < 0 {
+			return nil, fmt.Errorf("LOG_FILE_PATH is required, LOG_FILE_MAX_SIZE_MB must be at least 1 and LOG_FILE_MAX_BACKUPS must not be negative")
+		}
+	case LogSinkHTTP:
+		if config.Logging.HTTPURL == "" {
+			return nil, fmt.Errorf("LOG_HTTP_URL is required with LOG_SINK=http")
+		}
+		if config.Logging.HTTPCollector != string(logger.CollectorLoki) && config.Logging.HTTPCollector != string(logger.CollectorJSON) {
+			return nil, fmt.Errorf("LOG_HTTP_COLLECTOR must be loki or json")
+		}
+		if config.Logging.HTTPBatchSize < 1 || config.Logging.HTTPFlushIntervalMs < 1 || config.Logging.HTTPBufferSize < 1 {
+			return nil, fmt.Errorf("LOG_HTTP_BATCH_SIZE, LOG_HTTP_FLUSH_INTERVAL_MS and LOG_HTTP_BUFFER_SIZE must be at least 1")
+		}
+	default:
+		return nil, fmt.Errorf("LOG_SINK must be stdout, file or http")
+	}
+	switch config.Events.Broker {
+	case "":
+	case EventBrokerKafka:
+		if config.Events.KafkaRESTURL == "" {
+			return nil, fmt.Errorf("KAFKA_REST_URL is required with EVENT_BROKER=kafka")
+		}
+	case EventBrokerRabbitMQ:
+		if config.Events.RabbitMQURL == "" || config.Events.RabbitMQExchange == "" {
+			return nil, fmt.Errorf("RABBITMQ_URL and RABBITMQ_EXCHANGE are required with EVENT_BROKER=rabbitmq")
+		}
+	case EventBrokerNATS:
+		if config.Events.NATSURL == "" {
+			return nil, fmt.Errorf("NATS_URL is required with EVENT_BROKER=nats")
+		}
+	default:
+		return nil, fmt.Errorf("EVENT_BROKER must be kafka, rabbitmq, nats or empty")
+	}
+	switch config.Email.Provider {
+	case "":
+	case EmailProviderSMTP:
+		if config.Email.SMTPHost == "" || config.Email.From == "" {
+			return nil, fmt.Errorf("SMTP_HOST and EMAIL_FROM are required with EMAIL_PROVIDER=smtp")
+		}
+	case EmailProviderSES:
+		if config.Email.SESRegion == "" || config.Email.From == "" || config.AWS.AccessKeyID == "" {
+			return nil, fmt.Errorf("SES_REGION, EMAIL_FROM and AWS_ACCESS_KEY_ID are required with EMAIL_PROVIDER=ses")
+		}
+	default:
+		return nil, fmt.Errorf("EMAIL_PROVIDER must be smtp, ses or empty")
+	}
+	if config.Email.BufferSize < 1 || config.Email.WebhookAlertIntervalMinutes < 0 {
+		return nil, fmt.Errorf("EMAIL_BUFFER_SIZE must be at least 1 and WEBHOOK_ALERT_INTERVAL_MINUTES must not be negative")
+	}
+	switch config.SMS.Provider {
+	case "":
+	case SMSProviderTwilio:
+		if config.SMS.TwilioAccountSID == "" || config.SMS.TwilioAuthToken == "" || config.SMS.From == "" {
+			return nil, fmt.Errorf("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and SMS_FROM are required with SMS_PROVIDER=twilio")
+		}
+	default:
+		return nil, fmt.Errorf("SMS_PROVIDER must be twilio or empty")
+	}
+	if config.SMS.PartnerLimitPerHour < 1 || config.SMS.RecipientLimitPerHour < 1 {
+		return nil, fmt.Errorf("SMS_PARTNER_LIMIT_PER_HOUR and SMS_RECIPIENT_LIMIT_PER_HOUR must be at least 1")
+	}
+	if config.SMS.VerificationTTLMinutes < 1 || config.SMS.VerificationMaxAttempts < 1 {
+		return nil, fmt.Errorf("SMS_VERIFICATION_TTL_MINUTES and SMS_VERIFICATION_MAX_ATTEMPTS must be at least 1")
+	}
+	if config.ProviderEvents.DedupTTLHours < 1 {
+		return nil, fmt.Errorf("PROVIDER_EVENT_DEDUP_TTL_HOURS must be at least 1")
+	}
+	switch config.Settlement.Source {
+	case SettlementSourceKafka:
+		if config.Events.KafkaRESTURL == "" || config.Settlement.KafkaTopic == "" || config.Settlement.KafkaGroup == "" {
+			return nil, fmt.Errorf("KAFKA_REST_URL, SETTLEMENT_KAFKA_TOPIC and SETTLEMENT_KAFKA_GROUP are required with SETTLEMENT_SOURCE=kafka")
+		}
+	case SettlementSourceDirectory:
+		if config.Settlement.DropDirectory == "" {
+			return nil, fmt.Errorf("SETTLEMENT_DROP_DIR is required with SETTLEMENT_SOURCE=directory")
+		}
+	default:
+		return nil, fmt.Errorf("SETTLEMENT_SOURCE must be kafka or directory")
+	}
+	if config.Settlement.PollIntervalSeconds < 1 {
+		return nil, fmt.Errorf("SETTLEMENT_POLL_INTERVAL_SECONDS must be at least 1")
+	}
+	if config.Server.ShutdownTimeoutSeconds < 1 {
+		return nil, fmt.Errorf("SHUTDOWN_TIMEOUT_SECONDS must be at least 1")
+	}
+	if config.Server.AccessLogMaxBodyBytes < 0 {
+		return nil, fmt.Errorf("ACCESS_LOG_MAX_BODY_BYTES must not be negative")
+	}
+	if config.Cache.TransactionTTLSeconds < 0 {
+		return nil, fmt.Errorf("TRANSACTION_CACHE_TTL_SECONDS must not be negative")
+	}
+	if config.Cache.ReceiptCacheSize < 0 {
+		return nil, fmt.Errorf("RECEIPT_CACHE_SIZE must not be negative")
+	}
+	if config.Lock.PaymentTTLSeconds < 1 {
+		return nil, fmt.Errorf("PAYMENT_LOCK_TTL_SECONDS must be at least 1")
+	}
+	if config.Worker.PollIntervalMs < 1 || config.Worker.BatchSize < 1 || config.Worker.Concurrency < 1 {
+		return nil, fmt.Errorf("WORKER_POLL_INTERVAL_MS, WORKER_BATCH_SIZE and WORKER_CONCURRENCY must be at least 1")
+	}
+	// A pass must finish within its claim, or another worker could claim its
+	// last jobs again. A payment takes at most the lock TTL, which is meant
+	// to exceed the longest gateway call.
+	slowestJobs := (config.Worker.BatchSize + config.Worker.Concurrency - 1) / config.Worker.Concurrency * config.Lock.PaymentTTLSeconds
+	if config.Worker.ClaimTimeoutSeconds <= slowestJobs {
+		return nil, fmt.Errorf("WORKER_CLAIM_TIMEOUT_SECONDS must be above %d, the longest a pass of WORKER_BATCH_SIZE payments can take", slowestJobs)
+	}
+	if config.HTTPClient.MaxIdleConns < 1 || config.HTTPClient.MaxIdleConnsPerHost < 1 || config.HTTPClient.MaxConnsPerHost < 0 {
+		return nil, fmt.Errorf("HTTP_CLIENT_MAX_IDLE_CONNS and HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST must be at least 1 and HTTP_CLIENT_MAX_CONNS_PER_HOST must not be negative")
+	}
+	if config.HTTPClient.IdleConnTimeoutSeconds < 1 || config.HTTPClient.DialTimeoutSeconds < 1 || config.HTTPClient.TLSHandshakeTimeoutSeconds < 1 {
+		return nil, fmt.Errorf("HTTP_CLIENT_IDLE_CONN_TIMEOUT_SECONDS, HTTP_CLIENT_DIAL_TIMEOUT_SECONDS and HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT_SECONDS must be at least 1")
+	}
+	if config.Sentry.SampleRate < 0 || config.Sentry.SampleRate > 1 {
+		return nil, fmt.Errorf("SENTRY_SAMPLE_RATE must be between 0 and 1")
+	}
+	if config.Sentry.BufferSize < 1 {
+		return nil, fmt.Errorf("SENTRY_BUFFER_SIZE must be at least 1")
+	}
+	if config.Alerts.SlowQueryThresholdMs < 0 || config.Alerts.SlowGatewayThresholdMs < 0 {
+		return nil, fmt.Errorf("SLOW_QUERY_THRESHOLD_MS and SLOW_GATEWAY_THRESHOLD_MS must not be negative")
+	}
+	if config.Alerts.WindowSeconds < 1 || config.Alerts.MinBreaches < 1 {
+		return nil, fmt.Errorf("SLOW_ALERT_WINDOW_SECONDS and SLOW_ALERT_MIN_BREACHES must be at least 1")
+	}
+	if config.Alerts.WebhookFailureIntervalMinutes < 0 || config.Alerts.DiscrepancyThreshold < 0 {
+		return nil, fmt.Errorf("ALERT_WEBHOOK_FAILURE_INTERVAL_MINUTES and ALERT_DISCREPANCY_THRESHOLD must not be negative")
+	}
+	if config.Alerts.DiscrepancyWindowMinutes < 1 {
+		return nil, fmt.Errorf("ALERT_DISCREPANCY_WINDOW_MINUTES must be at least 1")
+	}
+	if config.Providers.PollIntervalSeconds < 0 {
+		return nil, fmt.Errorf("PROVIDER_POLL_INTERVAL_SECONDS must not be negative")
+	}
+	if config.Providers.WindowSeconds < 1 || config.Providers.MinCalls < 1 {
+		return nil, fmt.Errorf("PROVIDER_ERROR_WINDOW_SECONDS and PROVIDER_MIN_CALLS must be at least 1")
+	}
+	if config.Providers.DegradedErrorRate <= 0 || config.Providers.DegradedErrorRate > 1 {
+		return nil, fmt.Errorf("PROVIDER_DEGRADED_ERROR_RATE must be above 0 and at most 1")
+	}
+	for provider, rawURL := range config.Providers.StatusURLs {
+		if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("PROVIDER_STATUS_URLS has an invalid URL for %s", provider)
+		}
+	}
+	return config, nil
+}
+
+// ConfigurePool applies the connection pool settings to db
+func (c *DatabaseConfig) ConfigurePool(db *sql.DB) {
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(c.ConnMaxLifetimeMinutes) * time.Minute)
+}
+
+// DriverName returns the database/sql driver name of the configured driver
+func (c *DatabaseConfig) DriverName() string {
+	if c.Driver == DriverSQLite {
+		return "sqlite3"
+	}
+	return c.Driver
+}
+
+// GetDSN returns the connection string for the configured driver
+func (c *DatabaseConfig) GetDSN() string {
+	switch c.Driver {
+	case DriverMySQL:
+		return c.mysqlDSN()
+	case DriverSQLite:
+		return c.sqliteDSN()
+	}
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,
+	)
+}
+
+// mysqlDSN returns a go-sql-driver/mysql connection string. Times are read
+// and written in UTC, and UPDATE reports matched rather than changed rows,
+// as PostgreSQL does, so optimistic locking works the same on both.
+// Migration scripts hold several statements, so those are allowed too.
+func (c *DatabaseConfig) mysqlDSN() string {
+	dsn := mysql.NewConfig()
+	dsn.User = c.User
+	dsn.Passwd = c.Password
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(c.Host, c.Port)
+	dsn.DBName = c.DBName
+	dsn.ParseTime = true
+	dsn.Loc = time.UTC
+	dsn.ClientFoundRows = true
+	dsn.MultiStatements = true
+	dsn.Params = map[string]string{"time_zone": "'+00:00'"}
+	if c.SSLMode != "" && c.SSLMode != "disable" {
+		dsn.TLSConfig = "true"
+	}
+	return dsn.FormatDSN()
+}
+
+// sqliteDSN returns a mattn/go-sqlite3 connection string for the file DBName.
+// Foreign keys are enforced, times are read as UTC, and transactions take the
+// write lock when they begin, so concurrent writers wait on busy_timeout
+// instead of failing when a read lock cannot be upgraded.
+func (c *DatabaseConfig) sqliteDSN() string {
+	params := url.Values{}
+	params.Set("_foreign_keys", "on")
+	params.Set("_busy_timeout", "5000")
+	params.Set("_journal_mode", "WAL")
+	params.Set("_txlock", "immediate")
+	params.Set("_loc", "UTC")
+	return "file:" + c.DBName + "?" + params.Encode()
+}
+
+// getEnv retrieves environment variable or returns default
+func getEnv(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsInt retrieves environment variable as integer or returns default
+func getEnvAsInt(key string, defaultValue int) int {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsFloat retrieves environment variable as float or returns default
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsBool retrieves environment variable as boolean or returns default
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsDate parses a YYYY-MM-DD environment variable as midnight UTC, or
+// returns the zero time when it and defaultValue are empty
+func getEnvAsDate(key, defaultValue string) (time.Time, error) {
+	value := getEnv(key, defaultValue)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a YYYY-MM-DD date", key)
+	}
+	return date, nil
+}
+
+// getEnvAsPairs parses "name:value,name:value" pairs into a name -> value map
+func getEnvAsPairs(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || name == "" || value == "" {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
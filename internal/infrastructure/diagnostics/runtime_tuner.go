@@ -0,0 +1,93 @@
+// Package diagnostics changes how the running process logs, collects
+// garbage and profiles, so production problems can be looked into without a
+// redeploy
+package diagnostics
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"Pay2Go/internal/infrastructure/logger"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RuntimeTuner reads and changes the ports.RuntimeSettings of the process.
+// Changes last until the process restarts.
+type RuntimeTuner struct {
+	mu     sync.Mutex
+	logger *logger.Logger
+	// gcPercent and blockProfileRate are remembered, since the runtime only
+	// reports the first when changing it and the second not at all
+	gcPercent        int
+	blockProfileRate int
+}
+
+// NewRuntimeTuner creates a tuner changing the level of appLogger
+func NewRuntimeTuner(appLogger *logger.Logger) *RuntimeTuner {
+	// Setting the GC percent returns the one set by GOGC; put it back
+	gcPercent := debug.SetGCPercent(100)
+	debug.SetGCPercent(gcPercent)
+	return &RuntimeTuner{logger: appLogger, gcPercent: gcPercent}
+}
+
+// Settings returns the current settings
+func (t *RuntimeTuner) Settings() ports.RuntimeSettings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.settings()
+}
+
+// Update validates every change of update before applying any of them, and
+// returns the resulting settings. changedBy is logged with the change.
+func (t *RuntimeTuner) Update(update ports.RuntimeSettingsUpdate, changedBy string) (ports.RuntimeSettings, error) {
+	level := t.logger.Level()
+	if update.LogLevel != nil {
+		parsed, err := logger.ParseLevel(*update.LogLevel)
+		if err != nil {
+			return ports.RuntimeSettings{}, err
+		}
+		level = parsed
+	}
+	if update.GCPercent != nil && *update.GCPercent < -1 {
+		return ports.RuntimeSettings{}, fmt.Errorf("GC percent must be -1 or more")
+	}
+	if update.BlockProfileRate != nil && *update.BlockProfileRate < 0 {
+		return ports.RuntimeSettings{}, fmt.Errorf("block profile rate must not be negative")
+	}
+	if update.MutexProfileFraction != nil && *update.MutexProfileFraction < 0 {
+		return ports.RuntimeSettings{}, fmt.Errorf("mutex profile fraction must not be negative")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.logger.SetLevel(level)
+	if update.GCPercent != nil {
+		debug.SetGCPercent(*update.GCPercent)
+		t.gcPercent = *update.GCPercent
+	}
+	if update.BlockProfileRate != nil {
+		runtime.SetBlockProfileRate(*update.BlockProfileRate)
+		t.blockProfileRate = *update.BlockProfileRate
+	}
+	if update.MutexProfileFraction != nil {
+		runtime.SetMutexProfileFraction(*update.MutexProfileFraction)
+	}
+
+	settings := t.settings()
+	t.logger.Warn("Runtime settings changed by %s: log level %s, GC percent %d, block profile rate %d, mutex profile fraction %d",
+		changedBy, settings.LogLevel, settings.GCPercent, settings.BlockProfileRate, settings.MutexProfileFraction)
+	return settings, nil
+}
+
+// settings reads the current settings; the caller holds the lock
+func (t *RuntimeTuner) settings() ports.RuntimeSettings {
+	return ports.RuntimeSettings{
+		LogLevel:             t.logger.Level().String(),
+		GCPercent:            t.gcPercent,
+		BlockProfileRate:     t.blockProfileRate,
+		MutexProfileFraction: runtime.SetMutexProfileFraction(-1),
+	}
+}
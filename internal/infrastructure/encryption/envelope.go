@@ -0,0 +1,226 @@
+// Package encryption provides application-level encryption of secrets at rest
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// envelopePrefix marks encrypted values bound to where they are stored.
+// ownerPrefix marks values bound to their owner and column only, which is
+// where they are stored unless the owner has several rows of the column.
+// legacyPrefix marks values encrypted before either; anything else is
+// legacy plaintext.
+const (
+	envelopePrefix = "enc:v3:"
+	ownerPrefix    = "enc:v2:"
+	legacyPrefix   = "enc:v1:"
+)
+
+// EnvelopeCipher implements ports.SecretCipher with envelope encryption.
+// Each value is encrypted with its own random data key (AES-256-GCM), and the
+// data key is encrypted with a master key. Stored values name the master key
+// they were wrapped with, so old keys keep decrypting after rotation. The
+// owner, column and row of a value are authenticated with it, so a value
+// copied into another row or column does not decrypt.
+type EnvelopeCipher struct {
+	masterKeys map[string]cipher.AEAD
+	primaryID  string
+
+	// AllowLegacy lets Decrypt read plaintext and values encrypted without
+	// their owner, column or row, for as long as it takes to rotate them. Left
+	// off, such values are refused, so one written into the database
+	// directly cannot stand in for an encrypted secret.
+	AllowLegacy bool
+}
+
+// NewEnvelopeCipher creates a cipher from base64-encoded 32-byte master keys by ID.
+// New values are encrypted with the primary key.
+func NewEnvelopeCipher(keys map[string]string, primaryID string) (*EnvelopeCipher, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one encryption key is required")
+	}
+
+	c := &EnvelopeCipher{
+		masterKeys: make(map[string]cipher.AEAD, len(keys)),
+		primaryID:  primaryID,
+	}
+
+	for id, encoded := range keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("encryption key ID %q must not contain ':'", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes, got %d", id, len(key))
+		}
+
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		c.masterKeys[id] = aead
+	}
+
+	if _, ok := c.masterKeys[primaryID]; !ok {
+		return nil, fmt.Errorf("primary encryption key %q is not configured", primaryID)
+	}
+
+	return c, nil
+}
+
+// Encrypt encrypts a secret stored at where with a fresh data key wrapped by
+// the primary key. Empty secrets stay empty.
+func (c *EnvelopeCipher) Encrypt(plaintext string, where ports.SecretContext) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	dataAEAD, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	// The key ID is authenticated with the wrapped data key so it cannot be swapped
+	wrappedKey, err := seal(c.masterKeys[c.primaryID], dataKey, []byte(c.primaryID))
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(dataAEAD, []byte(plaintext), additionalData(where))
+	if err != nil {
+		return "", err
+	}
+
+	return envelopePrefix + c.primaryID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts a secret stored at where. Plaintext and values encrypted
+// without their owner, column or row are only read with AllowLegacy.
+func (c *EnvelopeCipher) Decrypt(value string, where ports.SecretContext) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	var aad []byte
+	switch {
+	case strings.HasPrefix(value, envelopePrefix):
+		value = strings.TrimPrefix(value, envelopePrefix)
+		aad = additionalData(where)
+	case strings.HasPrefix(value, ownerPrefix) && where.Record == "":
+		// Bound to all there is of where
+		value = strings.TrimPrefix(value, ownerPrefix)
+		aad = additionalData(where)
+	case !c.AllowLegacy:
+		return "", fmt.Errorf("%s of %s is not encrypted for it; rotate secrets with ENCRYPTION_ALLOW_LEGACY set", where.Column, where.OwnerID)
+	case strings.HasPrefix(value, ownerPrefix):
+		value = strings.TrimPrefix(value, ownerPrefix)
+		aad = additionalData(ports.SecretContext{OwnerID: where.OwnerID, Column: where.Column})
+	case strings.HasPrefix(value, legacyPrefix):
+		value = strings.TrimPrefix(value, legacyPrefix)
+	default:
+		return value, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	masterAEAD, ok := c.masterKeys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("encryption key %q is not configured", parts[0])
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+
+	dataKey, err := open(masterAEAD, wrappedKey, []byte(parts[0]))
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	dataAEAD, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataAEAD, ciphertext, aad)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a stored secret is plaintext, encrypted
+// with an older format, or wrapped with a key other than the primary one
+func (c *EnvelopeCipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	return !strings.HasPrefix(value, envelopePrefix+c.primaryID+":")
+}
+
+// additionalData is what a secret is bound to: its owner, column and row
+func additionalData(where ports.SecretContext) []byte {
+	data := where.OwnerID.String() + "/" + where.Column
+	if where.Record != "" {
+		data += "/" + where.Record
+	}
+	return []byte(data)
+}
+
+// newGCM creates an AES-GCM AEAD for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data with a random nonce prepended to the result
+func seal(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, data, additionalData), nil
+}
+
+// open decrypts data produced by seal
+func open(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
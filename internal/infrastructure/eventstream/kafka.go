@@ -0,0 +1,107 @@
+package eventstream
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// KafkaConfig locates a Kafka REST Proxy, such as Confluent's or Redpanda's
+// HTTP proxy, in front of the Kafka cluster
+type KafkaConfig struct {
+	// RESTURL is the proxy's base URL
+	RESTURL string
+	// TopicPrefix is put before the topic of each aggregate type
+	TopicPrefix string
+	// Username and Password authenticate with HTTP basic authentication,
+	// as Confluent Cloud's API keys do; empty sends none
+	Username string
+	Password string
+}
+
+// KafkaPublisher implements ports.EventPublisher by producing to Kafka
+// through the REST Proxy API v2, one record per event keyed by its
+// aggregate's ID
+type KafkaPublisher struct {
+	cfg    KafkaConfig
+	base   string
+	client *http.Client
+}
+
+// NewKafkaPublisher creates a publisher producing through the proxy of cfg
+// with client
+func NewKafkaPublisher(cfg KafkaConfig, client *http.Client) (*KafkaPublisher, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.RESTURL, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid Kafka REST URL %q", cfg.RESTURL)
+	}
+	return &KafkaPublisher{cfg: cfg, base: base.String(), client: client}, nil
+}
+
+// kafkaRecords is the body of a produce request of the REST Proxy
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string  `json:"key"`
+	Value Message `json:"value"`
+}
+
+// kafkaOffsets is the answer to a produce request: one offset per record,
+// with an error for records the cluster did not take
+type kafkaOffsets struct {
+	Offsets []struct {
+		Partition int    `json:"partition"`
+		Offset    int64  `json:"offset"`
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// PublishEvent produces event to its topic
+func (p *KafkaPublisher) PublishEvent(ctx context.Context, event *entities.OutboxEvent) error {
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: Key(event), Value: NewMessage(event)}}})
+	if err != nil {
+		return fmt.Errorf("failed to encode Kafka record: %w", err)
+	}
+
+	topic := Topic(p.cfg.TopicPrefix, event)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.base+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Kafka request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if p.cfg.Username != "" {
+		req.SetBasicAuth(p.cfg.Username, p.cfg.Password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to produce to Kafka topic %s: %w", topic, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to produce to Kafka topic %s: %s %s", topic, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var offsets kafkaOffsets
+	if err := json.NewDecoder(resp.Body).Decode(&offsets); err != nil {
+		return fmt.Errorf("failed to decode Kafka produce response: %w", err)
+	}
+	for _, offset := range offsets.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("failed to produce to Kafka topic %s: error %d: %s", topic, *offset.ErrorCode, offset.Error)
+		}
+	}
+	return nil
+}
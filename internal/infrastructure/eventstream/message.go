@@ -0,0 +1,47 @@
+// Package eventstream publishes outbox events to message brokers, so
+// downstream systems such as analytics and risk can consume the payment
+// stream. Every broker carries the same Message.
+package eventstream
+
+import (
+	"encoding/json"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// Message is an outbox event as consumers of the stream receive it
+type Message struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	PartnerID     string          `json:"partner_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// NewMessage returns the message of event
+func NewMessage(event *entities.OutboxEvent) Message {
+	return Message{
+		ID:            event.ID.String(),
+		Type:          event.EventType,
+		PartnerID:     event.PartnerID.String(),
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID.String(),
+		CreatedAt:     event.CreatedAt.UTC(),
+		Data:          event.Payload,
+	}
+}
+
+// Topic returns the topic of event: prefix followed by its aggregate type
+// in the plural, such as pay2go.transactions or pay2go.refunds
+func Topic(prefix string, event *entities.OutboxEvent) string {
+	return prefix + event.AggregateType + "s"
+}
+
+// Key returns the key of event, its aggregate's ID. Brokers that partition
+// by key keep each transaction's and refund's events in order.
+func Key(event *entities.OutboxEvent) string {
+	return event.AggregateID.String()
+}
@@ -0,0 +1,110 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// NATSConfig locates a NATS server with JetStream enabled
+type NATSConfig struct {
+	// URL is the server, nats://host:4222, or tls://host:4222 to require
+	// TLS; user and password in it are used unless Username is set
+	URL         string
+	TopicPrefix string
+	Username    string
+	Password    string
+	Token       string
+	// CAFile verifies the server's certificate instead of the system's
+	// roots, for servers with a private CA
+	CAFile string
+	// Timeout bounds connecting and waiting for each acknowledgement
+	Timeout time.Duration
+}
+
+// NATSPublisher implements ports.EventPublisher by publishing to NATS
+// JetStream with the nats.go client. A stream must capture the subjects,
+// such as pay2go.>.
+//
+// Each publish waits for JetStream's acknowledgement that the stream stored
+// the message, and carries the event ID as Nats-Msg-Id, so JetStream drops
+// the duplicates a retry would otherwise store. The client reconnects to a
+// restarted server by itself, and upgrades to TLS when the server requires
+// it.
+type NATSPublisher struct {
+	cfg  NATSConfig
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+// NewNATSPublisher creates a publisher to the server of cfg. It connects in
+// the background, so a server that is down does not stop the API starting.
+func NewNATSPublisher(cfg NATSConfig) (*NATSPublisher, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q: want nats://host:port or tls://host:port", cfg.URL)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	options := []nats.Option{
+		nats.Name("pay2go"),
+		nats.Timeout(cfg.Timeout),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	}
+	if cfg.Username != "" {
+		options = append(options, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+	if cfg.Token != "" {
+		options = append(options, nats.Token(cfg.Token))
+	}
+	if cfg.CAFile != "" {
+		options = append(options, nats.RootCAs(cfg.CAFile))
+	}
+	conn, err := nats.Connect(cfg.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to use NATS JetStream: %w", err)
+	}
+	return &NATSPublisher{cfg: cfg, conn: conn, js: js}, nil
+}
+
+// PublishEvent publishes event to its subject and waits for JetStream to
+// store it
+func (p *NATSPublisher) PublishEvent(ctx context.Context, event *entities.OutboxEvent) error {
+	data, err := json.Marshal(NewMessage(event))
+	if err != nil {
+		return fmt.Errorf("failed to encode NATS message: %w", err)
+	}
+	subject := Topic(p.cfg.TopicPrefix, event)
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+	if _, err := p.js.Publish(ctx, subject, data, jetstream.WithMsgID(event.ID.String())); err != nil {
+		if stderrors.Is(err, jetstream.ErrNoStreamResponse) || stderrors.Is(err, nats.ErrNoResponders) {
+			return fmt.Errorf("no JetStream stream captures subject %s", subject)
+		}
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection
+func (p *NATSPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package formatting renders amounts for people: receipts, statements and
+// report exports. Amounts in API responses stay plain decimal strings.
+package formatting
+
+import (
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// Style selects how the currency is shown next to the number
+type Style string
+
+const (
+	// StyleSymbol shows the currency symbol: "฿1,234.50", "1.234,50 €"
+	StyleSymbol Style = "symbol"
+	// StyleCode shows the ISO 4217 code: "THB 1,234.50", "1.234,50 EUR".
+	// Use it when the symbol is ambiguous, e.g. "$" on a multi-currency report.
+	StyleCode Style = "code"
+	// StyleNumber shows the number alone: "1,234.50", "1.234,50". Use it for
+	// spreadsheet columns that carry the currency in a column of their own.
+	StyleNumber Style = "number"
+)
+
+// MoneyFormatter formats amounts in one locale and style
+type MoneyFormatter struct {
+	locale valueobjects.Locale
+	format valueobjects.NumberFormat
+	style  Style
+}
+
+// NewMoneyFormatter creates a formatter for locale (a BCP 47 tag such as
+// "th-TH"). An empty locale uses the default locale, an empty style StyleSymbol.
+func NewMoneyFormatter(locale string, style Style) (*MoneyFormatter, error) {
+	loc := valueobjects.DefaultLocale
+	if locale != "" {
+		parsed, err := valueobjects.NewLocale(locale)
+		if err != nil {
+			return nil, err
+		}
+		loc = parsed
+	}
+
+	return NewLocaleMoneyFormatter(loc, style), nil
+}
+
+// NewLocaleMoneyFormatter creates a formatter for an already validated
+// locale, such as a partner's
+func NewLocaleMoneyFormatter(locale valueobjects.Locale, style Style) *MoneyFormatter {
+	if style != StyleCode && style != StyleNumber {
+		style = StyleSymbol
+	}
+
+	return &MoneyFormatter{
+		locale: locale,
+		format: locale.NumberFormat(),
+		style:  style,
+	}
+}
+
+// Locale returns the formatter's locale
+func (f *MoneyFormatter) Locale() valueobjects.Locale {
+	return f.locale
+}
+
+// Money formats m, e.g. "฿1,234.50". Negative amounts (refunds, chargebacks)
+// keep the sign in front of the symbol: "-€1,234.50".
+func (f *MoneyFormatter) Money(m valueobjects.Money) string {
+	return f.MinorUnits(m.Amount, m.Currency)
+}
+
+// MinorUnits formats an amount in currency's minor units. Report totals are
+// summed in minor units and may be negative, so they cannot always be Money.
+func (f *MoneyFormatter) MinorUnits(amount int64, currency valueobjects.Currency) string {
+	decimal := valueobjects.FormatMinorUnits(amount, currency)
+
+	switch f.style {
+	case StyleNumber:
+		return f.format.Number(decimal)
+	case StyleCode:
+		// Codes are letters, so they are always spaced from the number
+		format := f.format
+		format.SymbolSpace = true
+		return format.WithSymbol(decimal, currency.String())
+	}
+	return f.format.WithSymbol(decimal, currency.Symbol())
+}
@@ -0,0 +1,59 @@
+package fx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// FixedRateProvider implements ports.ExchangeRateProvider with rates set in code.
+// Useful for tests and local development where no FX API is reachable.
+type FixedRateProvider struct {
+	rates map[string]float64
+	asOf  time.Time
+	mu    sync.RWMutex
+}
+
+// NewFixedRateProvider creates a provider with no rates; add them with SetRate
+func NewFixedRateProvider() *FixedRateProvider {
+	return &FixedRateProvider{
+		rates: make(map[string]float64),
+		asOf:  time.Now().UTC(),
+	}
+}
+
+// SetRate sets the rate for converting one unit of from into to
+func (p *FixedRateProvider) SetRate(from, to valueobjects.Currency, rate float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rates[from.String()+"/"+to.String()] = rate
+}
+
+// GetRate returns the configured rate for converting from into to. A pair only
+// set the other way round is answered with the inverse rate.
+func (p *FixedRateProvider) GetRate(ctx context.Context, from, to valueobjects.Currency) (valueobjects.ExchangeRate, error) {
+	if from == to {
+		return valueobjects.NewExchangeRate(from.String(), to.String(), 1, "fixed", p.asOf)
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if rate, ok := p.rates[from.String()+"/"+to.String()]; ok {
+		return valueobjects.NewExchangeRate(from.String(), to.String(), rate, "fixed", p.asOf)
+	}
+
+	if rate, ok := p.rates[to.String()+"/"+from.String()]; ok {
+		inverse, err := valueobjects.NewExchangeRate(to.String(), from.String(), rate, "fixed", p.asOf)
+		if err != nil {
+			return valueobjects.ExchangeRate{}, err
+		}
+		return inverse.Inverse(), nil
+	}
+
+	return valueobjects.ExchangeRate{}, errors.ErrExchangeRateUnavailable
+}
@@ -0,0 +1,130 @@
+// Package fx provides foreign exchange rate provider implementations
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// HTTPRateProvider implements ports.ExchangeRateProvider against an external FX
+// API that answers GET {baseURL}/latest?from=USD&to=EUR with
+// {"base": "USD", "date": "2024-01-15", "rates": {"EUR": 0.91}} (e.g. Frankfurter).
+// Rates are cached for cacheTTL so conversions don't call the API every time.
+type HTTPRateProvider struct {
+	baseURL  string
+	apiKey   string
+	client   *http.Client
+	cacheTTL time.Duration
+
+	cache map[string]cachedRate
+	mu    sync.RWMutex
+}
+
+// cachedRate is a rate and when it was fetched
+type cachedRate struct {
+	rate      valueobjects.ExchangeRate
+	fetchedAt time.Time
+}
+
+// latestRatesResponse is the API response body
+type latestRatesResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// NewHTTPRateProvider creates a new FX API rate provider calling the API with
+// client. apiKey is sent as a bearer token when set.
+func NewHTTPRateProvider(baseURL, apiKey string, client *http.Client, cacheTTL time.Duration) *HTTPRateProvider {
+	return &HTTPRateProvider{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiKey:   apiKey,
+		client:   client,
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]cachedRate),
+	}
+}
+
+// GetRate returns the current rate for converting from into to
+func (p *HTTPRateProvider) GetRate(ctx context.Context, from, to valueobjects.Currency) (valueobjects.ExchangeRate, error) {
+	if from == to {
+		return valueobjects.NewExchangeRate(from.String(), to.String(), 1, "par", time.Now())
+	}
+
+	key := from.String() + "/" + to.String()
+
+	p.mu.RLock()
+	cached, ok := p.cache[key]
+	p.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < p.cacheTTL {
+		return cached.rate, nil
+	}
+
+	rate, err := p.fetchRate(ctx, from, to)
+	if err != nil {
+		return valueobjects.ExchangeRate{}, err
+	}
+
+	p.mu.Lock()
+	p.cache[key] = cachedRate{rate: rate, fetchedAt: time.Now()}
+	p.mu.Unlock()
+
+	return rate, nil
+}
+
+// fetchRate asks the API for the latest from/to rate
+func (p *HTTPRateProvider) fetchRate(ctx context.Context, from, to valueobjects.Currency) (valueobjects.ExchangeRate, error) {
+	query := url.Values{}
+	query.Set("from", from.String())
+	query.Set("to", to.String())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/latest?"+query.Encode(), nil)
+	if err != nil {
+		return valueobjects.ExchangeRate{}, fmt.Errorf("failed to build exchange rate request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return valueobjects.ExchangeRate{}, fmt.Errorf("failed to fetch exchange rate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The API answers unsupported currencies with a client error
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity {
+		return valueobjects.ExchangeRate{}, errors.ErrExchangeRateUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return valueobjects.ExchangeRate{}, fmt.Errorf("failed to fetch exchange rate: unexpected status %d", resp.StatusCode)
+	}
+
+	var body latestRatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return valueobjects.ExchangeRate{}, fmt.Errorf("failed to decode exchange rate response: %w", err)
+	}
+
+	rate, ok := body.Rates[to.String()]
+	if !ok || !strings.EqualFold(body.Base, from.String()) {
+		return valueobjects.ExchangeRate{}, errors.ErrExchangeRateUnavailable
+	}
+
+	// Quotes are dated by day; fall back to now if the date is missing
+	asOf, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		asOf = time.Now().UTC()
+	}
+
+	return valueobjects.NewExchangeRate(from.String(), to.String(), rate, p.baseURL, asOf)
+}
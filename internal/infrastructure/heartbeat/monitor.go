@@ -0,0 +1,115 @@
+// Package heartbeat tracks that the API's background loops keep running.
+// Each loop beats whenever it completes a pass; a loop that misses several
+// passes in a row has stalled, even though the HTTP server still answers.
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Loop is the heartbeat of one background loop
+type Loop struct {
+	Name string
+	// Interval is how often the loop is meant to beat
+	Interval time.Duration
+	// LastBeat is when the loop last completed a pass, or when it was
+	// registered before its first pass
+	LastBeat time.Time
+	// Beats counts the passes completed since registration
+	Beats int64
+	// Stalled reports whether the loop missed too many passes
+	Stalled bool
+}
+
+// Age is how long ago the loop last beat
+func (l Loop) Age(now time.Time) time.Duration {
+	return now.Sub(l.LastBeat)
+}
+
+// Monitor keeps the heartbeats of the registered loops. It is a
+// ports.HealthChecker for the readiness check: a stalled loop makes the API
+// not ready, so it is restarted instead of silently not publishing webhooks.
+type Monitor struct {
+	// missedBeats is how many intervals a loop may go without beating
+	// before it has stalled
+	missedBeats int
+
+	mu    sync.Mutex
+	loops map[string]*Loop
+}
+
+// NewMonitor creates a monitor that considers a loop stalled once it has
+// not beaten for missedBeats of its intervals
+func NewMonitor(missedBeats int) *Monitor {
+	if missedBeats < 1 {
+		missedBeats = 1
+	}
+	return &Monitor{missedBeats: missedBeats, loops: make(map[string]*Loop)}
+}
+
+// Register starts tracking a loop that beats every interval. Its first beat
+// is due one interval from now.
+func (m *Monitor) Register(name string, interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loops[name] = &Loop{Name: name, Interval: interval, LastBeat: time.Now()}
+}
+
+// Beat records that the loop completed a pass. Beats of loops never
+// registered are ignored.
+func (m *Monitor) Beat(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if loop, ok := m.loops[name]; ok {
+		loop.LastBeat = time.Now()
+		loop.Beats++
+	}
+}
+
+// Loops returns the heartbeats of every registered loop ordered by name
+func (m *Monitor) Loops() []Loop {
+	now := time.Now()
+	m.mu.Lock()
+	loops := make([]Loop, 0, len(m.loops))
+	for _, loop := range m.loops {
+		l := *loop
+		l.Stalled = l.Age(now) > time.Duration(m.missedBeats)*l.Interval
+		loops = append(loops, l)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(loops, func(i, j int) bool { return loops[i].Name < loops[j].Name })
+	return loops
+}
+
+// CheckHealth reports each loop's last beat. A stalled loop takes the API
+// down, since the work it does piles up unseen.
+func (m *Monitor) CheckHealth(ctx context.Context) ports.ComponentHealth {
+	now := time.Now()
+	health := ports.ComponentHealth{Status: ports.HealthUp, Details: map[string]interface{}{}}
+	var stalled []string
+	for _, loop := range m.Loops() {
+		status := ports.HealthUp
+		if loop.Stalled {
+			status = ports.HealthDown
+			stalled = append(stalled, loop.Name)
+		}
+		health.Details[loop.Name] = map[string]interface{}{
+			"status":           status,
+			"interval_seconds": loop.Interval.Seconds(),
+			"age_seconds":      int64(loop.Age(now).Seconds()),
+			"beats":            loop.Beats,
+		}
+	}
+	if len(stalled) > 0 {
+		health.Status = ports.HealthDown
+		health.Error = fmt.Sprintf("stalled background loops: %v", stalled)
+	}
+	return health
+}
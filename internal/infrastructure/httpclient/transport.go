@@ -0,0 +1,131 @@
+// Package httpclient provides the pooled HTTP transport shared by every
+// outbound integration, such as webhook delivery and the archive's S3 store,
+// so they reuse connections instead of each keeping its own pool.
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Config tunes the shared transport
+type Config struct {
+	// MaxIdleConns caps the idle connections kept across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps the idle connections kept to one host; more
+	// concurrent requests to it open connections that are closed afterwards
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps all connections to one host; 0 means no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer
+	IdleConnTimeout time.Duration
+	// DialTimeout and TLSHandshakeTimeout bound setting a connection up
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+	// ProxyURL sends every request through this proxy; empty uses the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables
+	ProxyURL string
+	// HTTP2 negotiates HTTP/2 with servers that offer it over TLS
+	HTTP2 bool
+}
+
+// Transport is the shared http.RoundTripper, counting requests and
+// connections for GET /metrics
+type Transport struct {
+	base *http.Transport
+
+	requests, failed, inFlight atomic.Int64
+	opened, reused             atomic.Int64
+}
+
+// NewTransport creates a transport tuned by cfg
+func NewTransport(cfg Config) (*Transport, error) {
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.ProxyURL)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}
+	return &Transport{
+		base: &http.Transport{
+			Proxy:               proxy,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   cfg.HTTP2,
+			MaxIdleConns:        cfg.MaxIdleConns,
+			MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     cfg.MaxConnsPerHost,
+			IdleConnTimeout:     cfg.IdleConnTimeout,
+			TLSHandshakeTimeout: cfg.TLSHandshakeTimeout,
+		},
+	}, nil
+}
+
+// Client returns a client sending its requests through the transport. Each
+// request, reading the response body included, takes at most timeout.
+func (t *Transport) Client(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: t, Timeout: timeout}
+}
+
+// headerRequestID tells providers which API request a call was made for,
+// so their logs and support can be matched with ours
+const headerRequestID = "X-Request-ID"
+
+// RoundTrip sends req over a pooled connection. Requests made while serving
+// an API request carry its ID in the X-Request-ID header, unless the caller
+// set the header itself.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+
+	if requestID := ports.RequestID(req.Context()); requestID != uuid.Nil && req.Header.Get(headerRequestID) == "" {
+		// A RoundTripper must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set(headerRequestID, requestID.String())
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.reused.Add(1)
+			} else {
+				t.opened.Add(1)
+			}
+		},
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+
+	t.requests.Add(1)
+	if err != nil {
+		t.failed.Add(1)
+	}
+	return resp, err
+}
+
+// CloseIdleConnections closes the pooled connections no request is using
+func (t *Transport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// Stats returns the transport's load so far
+func (t *Transport) Stats() ports.HTTPTransportStats {
+	return ports.HTTPTransportStats{
+		Requests:          t.requests.Load(),
+		Failed:            t.failed.Load(),
+		InFlight:          t.inFlight.Load(),
+		ConnectionsOpened: t.opened.Load(),
+		ConnectionsReused: t.reused.Load(),
+	}
+}
@@ -0,0 +1,56 @@
+// Package lock provides the locks that keep API instances from working on
+// the same resource at once
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// MemoryLocker implements ports.Locker in process memory.
+// Locks are per instance, so it is only suitable for single-instance deployments.
+type MemoryLocker struct {
+	mu sync.Mutex
+	// held maps keys to the lock holding them
+	held map[string]*memoryLock
+}
+
+type memoryLock struct {
+	locker    *MemoryLocker
+	key       string
+	expiresAt time.Time
+}
+
+// NewMemoryLocker creates an in-memory locker
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{held: make(map[string]*memoryLock)}
+}
+
+// TryLock acquires the lock on key unless an unexpired lock holds it
+func (l *MemoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (ports.Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if current, ok := l.held[key]; ok && now.Before(current.expiresAt) {
+		return nil, errors.ErrResourceLocked
+	}
+	lock := &memoryLock{locker: l, key: key, expiresAt: now.Add(ttl)}
+	l.held[key] = lock
+	return lock, nil
+}
+
+// Unlock releases the lock unless it expired and another holder took it
+func (m *memoryLock) Unlock(ctx context.Context) error {
+	m.locker.mu.Lock()
+	defer m.locker.mu.Unlock()
+
+	if m.locker.held[m.key] == m {
+		delete(m.locker.held, m.key)
+	}
+	return nil
+}
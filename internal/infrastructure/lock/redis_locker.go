@@ -0,0 +1,69 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// lockKeyPrefix prefixes the Redis key of a lock
+const lockKeyPrefix = "lock:"
+
+// unlockScript deletes the lock only if it still holds the caller's token,
+// so a holder whose lock expired cannot release the next holder's.
+//
+// KEYS[1] = lock key
+// ARGV[1] = token
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker implements ports.Locker with a Redis key per lock, set only
+// if absent and expiring after the TTL. It runs on a single Redis primary,
+// so a failover that loses the key can let a second holder in; processing
+// still reloads and version-checks the transaction, which stops the second
+// holder from saving over the first.
+type RedisLocker struct {
+	client *redis.Client
+}
+
+// NewRedisLocker creates a Redis-backed locker
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+// TryLock sets the lock key to a token of this holder unless it is set
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (ports.Lock, error) {
+	token := uuid.NewString()
+	acquired, err := l.client.SetNX(ctx, lockKeyPrefix+key, token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !acquired {
+		return nil, errors.ErrResourceLocked
+	}
+	return &redisLock{client: l.client, key: lockKeyPrefix + key, token: token}, nil
+}
+
+type redisLock struct {
+	client *redis.Client
+	key    string
+	token  string
+}
+
+// Unlock deletes the lock key if this holder still has it
+func (l *redisLock) Unlock(ctx context.Context) error {
+	if err := unlockScript.Run(ctx, l.client, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
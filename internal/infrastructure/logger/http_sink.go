@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Collector is the API of the remote log collector
+type Collector string
+
+const (
+	// CollectorLoki pushes to Grafana Loki's /loki/api/v1/push, one stream
+	// per level
+	CollectorLoki Collector = "loki"
+	// CollectorJSON posts a JSON array of entries, as Fluentd's and Fluent
+	// Bit's HTTP inputs and most log vendors accept
+	CollectorJSON Collector = "json"
+)
+
+// HTTPSinkConfig configures an HTTPSink
+type HTTPSinkConfig struct {
+	URL       string
+	Collector Collector
+	// BatchSize is how many entries are sent in one request at most
+	BatchSize int
+	// FlushInterval is how long an entry waits for its batch to fill
+	FlushInterval time.Duration
+	// BufferSize is how many entries wait to be sent; more are dropped
+	BufferSize int
+}
+
+// HTTPSink ships entries to a remote collector in batches, in the
+// background so logging never waits on the network. Batches that cannot be
+// sent are dropped: logs are not worth holding the API up for.
+type HTTPSink struct {
+	cfg     HTTPSinkConfig
+	client  *http.Client
+	entries chan Entry
+
+	sent, dropped, failed atomic.Int64
+
+	// onError is told about batches that could not be sent. It must not log
+	// to this sink, or a collector that is down would be told about itself.
+	onError func(err error)
+}
+
+// NewHTTPSink creates a sink shipping to cfg.URL with client
+func NewHTTPSink(cfg HTTPSinkConfig, client *http.Client, onError func(err error)) (*HTTPSink, error) {
+	switch cfg.Collector {
+	case CollectorLoki, CollectorJSON:
+	default:
+		return nil, fmt.Errorf("unknown log collector %q", cfg.Collector)
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("log collector URL is required")
+	}
+	return &HTTPSink{
+		cfg:     cfg,
+		client:  client,
+		entries: make(chan Entry, cfg.BufferSize),
+		onError: onError,
+	}, nil
+}
+
+// Write queues entry to be shipped, or drops it when the queue is full
+func (s *HTTPSink) Write(entry Entry) error {
+	select {
+	case s.entries <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// Run ships queued entries until ctx is done, then ships what is left
+func (s *HTTPSink) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= s.cfg.BatchSize {
+				s.send(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.send(batch)
+				batch = batch[:0]
+			}
+		case <-ctx.Done():
+			s.drain(batch)
+			return
+		}
+	}
+}
+
+// Flush ships every queued entry now. Shutdown calls it once the last
+// entries are logged.
+func (s *HTTPSink) Flush() {
+	s.drain(make([]Entry, 0, s.cfg.BatchSize))
+}
+
+// drain ships batch together with every queued entry
+func (s *HTTPSink) drain(batch []Entry) {
+	for {
+		select {
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= s.cfg.BatchSize {
+				s.send(batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				s.send(batch)
+			}
+			return
+		}
+	}
+}
+
+// Stats returns how many entries were shipped, dropped with the queue full
+// and failed to ship
+func (s *HTTPSink) Stats() (sent, dropped, failed int64) {
+	return s.sent.Load(), s.dropped.Load(), s.failed.Load()
+}
+
+// send posts batch in the collector's format
+func (s *HTTPSink) send(batch []Entry) {
+	var body []byte
+	var err error
+	if s.cfg.Collector == CollectorLoki {
+		body, err = lokiPush(batch)
+	} else {
+		entries := make([]jsonEntry, len(batch))
+		for i, entry := range batch {
+			entries[i] = newJSONEntry(entry)
+		}
+		body, err = json.Marshal(entries)
+	}
+	if err != nil {
+		s.fail(len(batch), fmt.Errorf("encode log entries: %w", err))
+		return
+	}
+
+	resp, err := s.client.Post(s.cfg.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		s.fail(len(batch), fmt.Errorf("ship logs: %w", err))
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		s.fail(len(batch), fmt.Errorf("ship logs: %s", resp.Status))
+		return
+	}
+	s.sent.Add(int64(len(batch)))
+}
+
+func (s *HTTPSink) fail(entries int, err error) {
+	s.failed.Add(int64(entries))
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
+// lokiStream is one stream of a Loki push: entries sharing labels, each a
+// pair of a nanosecond timestamp and the line
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiPush encodes batch as a Loki push request, one stream per level so
+// levels can be selected by label. Lines are the JSON entries.
+func lokiPush(batch []Entry) ([]byte, error) {
+	var streams []*lokiStream
+	byLevel := make(map[Level]*lokiStream)
+	for _, entry := range batch {
+		stream, ok := byLevel[entry.Level]
+		if !ok {
+			stream = &lokiStream{Stream: map[string]string{"service": "pay2go", "level": entry.Level.String()}}
+			byLevel[entry.Level] = stream
+			streams = append(streams, stream)
+		}
+		line, err := json.Marshal(newJSONEntry(entry))
+		if err != nil {
+			return nil, err
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(entry.Time.UnixNano(), 10), string(line)})
+	}
+	return json.Marshal(map[string]interface{}{"streams": streams})
+}
@@ -1,41 +1,143 @@
-package logger
-// Package logger provides structured logging
+// Package logger provides structured logging. Where log entries go is up to
+// the Sink: standard output, a rotating file or a remote collector.
 package logger
 
 import (
-	"log"
+	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// Level is the least severe kind of message a Logger writes
+type Level int32
 
+// Levels from the most to the least verbose
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	l.logger.Printf("[WARN] "+message, args...)func (l *Logger) Warn(message string, args ...interface{}) {// Warn logs warning level messages}	l.logger.Printf("[DEBUG] "+message, args...)func (l *Logger) Debug(message string, args ...interface{}) {// Debug logs debug level messages}	l.logger.Printf("[ERROR] "+message, args...)func (l *Logger) Error(message string, args ...interface{}) {// Error logs error level messages}	l.logger.Printf("[INFO] "+message, args...)func (l *Logger) Info(message string, args ...interface{}) {// Info logs info level messages}	}		logger: log.New(os.Stdout, "[Pay2Go] ", log.LstdFlags|log.Lshortfile),	return &Logger{func New() *Logger {// New creates a new logger}	logger *log.Loggertype Logger struct {// Logger represents application logger
\ No newline at end of file
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// ParseLevel parses "debug", "info", "warn" or "error"
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", name)
+}
+
+// String returns the level's name
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// Entry is one logged message
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	// Caller is the file and line that logged the message, as "file.go:12"
+	Caller string
+}
+
+// Sink is where a Logger writes its entries. Write is called by every
+// goroutine that logs, so it must be safe for concurrent use.
+type Sink interface {
+	Write(entry Entry) error
+}
+
+// Logger represents application logger
+type Logger struct {
+	sink  atomic.Pointer[sinkHolder]
+	level atomic.Int32
+}
+
+// sinkHolder lets the sink of a Logger be swapped atomically
+type sinkHolder struct {
+	sink Sink
+}
+
+// New creates a new logger writing info messages and above as text to
+// standard output
+func New() *Logger {
+	return NewWithSink(NewWriterSink(os.Stdout, FormatText))
+}
+
+// NewWithSink creates a new logger writing info messages and above to sink
+func NewWithSink(sink Sink) *Logger {
+	l := &Logger{}
+	l.SetSink(sink)
+	l.SetLevel(LevelInfo)
+	return l
+}
+
+// SetSink changes where entries are written; it is safe to call while other
+// goroutines log
+func (l *Logger) SetSink(sink Sink) {
+	l.sink.Store(&sinkHolder{sink: sink})
+}
+
+// Level returns the least severe level written
+func (l *Logger) Level() Level {
+	return Level(l.level.Load())
+}
+
+// SetLevel changes the least severe level written; it is safe to call
+// while other goroutines log
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// Info logs info level messages
+func (l *Logger) Info(message string, args ...interface{}) {
+	if l.Level() <= LevelInfo {
+		l.write(LevelInfo, message, args)
+	}
+}
+
+// Error logs error level messages
+func (l *Logger) Error(message string, args ...interface{}) {
+	l.write(LevelError, message, args)
+}
+
+// Debug logs debug level messages
+func (l *Logger) Debug(message string, args ...interface{}) {
+	if l.Level() <= LevelDebug {
+		l.write(LevelDebug, message, args)
+	}
+}
+
+// Warn logs warning level messages
+func (l *Logger) Warn(message string, args ...interface{}) {
+	if l.Level() <= LevelWarn {
+		l.write(LevelWarn, message, args)
+	}
+}
+
+// write hands the entry to the sink. An entry the sink cannot take goes to
+// standard error, so it is not lost silently.
+func (l *Logger) write(level Level, message string, args []interface{}) {
+	entry := Entry{Time: time.Now(), Level: level, Message: fmt.Sprintf(message, args...)}
+	// Skip runtime.Caller, write and the level's method
+	if _, file, line, ok := runtime.Caller(2); ok {
+		entry.Caller = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	if err := l.sink.Load().sink.Write(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "%s (log sink failed: %v)\n", formatText(entry), err)
+	}
+}
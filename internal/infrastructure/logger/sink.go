@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format is how entries are written as lines
+type Format string
+
+const (
+	// FormatText writes "[Pay2Go] 2006/01/02 15:04:05 file.go:12: [INFO] message"
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per line, for log collectors
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses "text" or "json"
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unknown log format %q", name)
+}
+
+// WriterSink writes entries as lines to a writer, such as standard output
+// or a RotatingFile
+type WriterSink struct {
+	format Format
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink writing entries to w in format
+func NewWriterSink(w io.Writer, format Format) *WriterSink {
+	return &WriterSink{w: w, format: format}
+}
+
+// Write writes entry as one line
+func (s *WriterSink) Write(entry Entry) error {
+	var line []byte
+	if s.format == FormatJSON {
+		var err error
+		if line, err = json.Marshal(newJSONEntry(entry)); err != nil {
+			return err
+		}
+	} else {
+		line = []byte(formatText(entry))
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(line)
+	return err
+}
+
+// formatText formats entry as the standard library's logger did before
+// sinks existed, so existing log parsing keeps working
+func formatText(entry Entry) string {
+	return fmt.Sprintf("[Pay2Go] %s %s: [%s] %s",
+		entry.Time.Format("2006/01/02 15:04:05"), entry.Caller, strings.ToUpper(entry.Level.String()), entry.Message)
+}
+
+// jsonEntry is an entry as collectors index it
+type jsonEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Caller  string `json:"caller,omitempty"`
+	Service string `json:"service"`
+}
+
+func newJSONEntry(entry Entry) jsonEntry {
+	return jsonEntry{
+		Time:    entry.Time.UTC().Format(time.RFC3339Nano),
+		Level:   entry.Level.String(),
+		Message: entry.Message,
+		Caller:  entry.Caller,
+		Service: "pay2go",
+	}
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// gateway records payments, refunds and provider latency around a
+// ports.PaymentGateway
+type gateway struct {
+	ports.PaymentGateway
+	metrics *Metrics
+}
+
+// Gateway wraps g so its payments, refunds and latency are recorded
+func (m *Metrics) Gateway(g ports.PaymentGateway) ports.PaymentGateway {
+	return &gateway{PaymentGateway: g, metrics: m}
+}
+
+// ProcessPayment processes the payment and records its outcome and latency
+func (g *gateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	start := time.Now()
+	id, err := g.PaymentGateway.ProcessPayment(ctx, transaction)
+	provider := string(transaction.Provider)
+
+	g.metrics.gatewayDuration.Observe(time.Since(start).Seconds(), provider, "process_payment")
+	g.metrics.payments.Inc(provider, mode(transaction.Livemode), outcome(err, "succeeded", "failed"))
+	return id, err
+}
+
+// ProcessRefund processes the refund and records its outcome and latency
+func (g *gateway) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	start := time.Now()
+	id, err := g.PaymentGateway.ProcessRefund(ctx, refund, transaction)
+	provider := string(transaction.Provider)
+
+	g.metrics.gatewayDuration.Observe(time.Since(start).Seconds(), provider, "process_refund")
+	g.metrics.refunds.Inc(provider, mode(transaction.Livemode), outcome(err, "succeeded", "failed"))
+	return id, err
+}
+
+// GetPaymentStatus asks the provider and records its latency
+func (g *gateway) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {
+	start := time.Now()
+	status, err := g.PaymentGateway.GetPaymentStatus(ctx, providerTransactionID)
+	g.metrics.gatewayDuration.Observe(time.Since(start).Seconds(), g.GetProviderName(), "get_payment_status")
+	return status, err
+}
+
+// publisher records webhook deliveries around a ports.OutboxPublisher
+type publisher struct {
+	ports.OutboxPublisher
+	metrics *Metrics
+}
+
+// Publisher wraps p so its deliveries are recorded
+func (m *Metrics) Publisher(p ports.OutboxPublisher) ports.OutboxPublisher {
+	return &publisher{OutboxPublisher: p, metrics: m}
+}
+
+// Publish delivers event and records the outcome and latency
+func (p *publisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	start := time.Now()
+	err := p.OutboxPublisher.Publish(ctx, event)
+	result := outcome(err, "delivered", "failed")
+
+	p.metrics.webhookDuration.Observe(time.Since(start).Seconds(), result)
+	p.metrics.webhooks.Inc(event.EventType, result)
+	return err
+}
+
+// transactionRepository counts payment retries around a
+// ports.TransactionRepository
+type transactionRepository struct {
+	ports.TransactionRepository
+	metrics *Metrics
+}
+
+// TransactionRepository wraps repo so retries of failed payments are counted
+func (m *Metrics) TransactionRepository(repo ports.TransactionRepository) ports.TransactionRepository {
+	return &transactionRepository{TransactionRepository: repo, metrics: m}
+}
+
+// IncrementRetryCount counts a retry, or one rejected for being out of
+// attempts
+func (r *transactionRepository) IncrementRetryCount(ctx context.Context, transaction *entities.Transaction, maxRetries int) error {
+	err := r.TransactionRepository.IncrementRetryCount(ctx, transaction, maxRetries)
+	switch err {
+	case nil:
+		r.metrics.retries.Inc("allowed")
+	case errors.ErrRetryNotAllowed:
+		r.metrics.retries.Inc("rejected")
+	}
+	return err
+}
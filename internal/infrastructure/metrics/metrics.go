@@ -0,0 +1,104 @@
+// Package metrics exposes the API's metrics in the Prometheus text format:
+// request latency from the HTTP middleware, and payment, refund, retry,
+// gateway and webhook metrics from decorators around the ports that do the
+// work.
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// Metrics holds the registry and the metrics recorded into it
+type Metrics struct {
+	*Registry
+
+	requestDuration *HistogramVec
+	callDuration    *HistogramVec
+	payments        *CounterVec
+	refunds         *CounterVec
+	gatewayDuration *HistogramVec
+	retries         *CounterVec
+	webhooks        *CounterVec
+	webhookDuration *HistogramVec
+	slowQueries     *CounterVec
+	slowGateway     *CounterVec
+}
+
+// New creates the API's metrics in a new registry
+func New() *Metrics {
+	r := NewRegistry()
+	return &Metrics{
+		Registry: r,
+		requestDuration: r.Histogram("pay2go_http_request_duration_seconds",
+			"Time to serve HTTP requests, by route pattern and status code.",
+			DefaultBuckets, "method", "route", "status"),
+		callDuration: r.Histogram("pay2go_grpc_call_duration_seconds",
+			"Time to serve gRPC calls, by method and status code.",
+			DefaultBuckets, "method", "code"),
+		payments: r.Counter("pay2go_payments_total",
+			"Payments sent to a provider, by provider, mode (live or test) and outcome (succeeded or failed).",
+			"provider", "mode", "outcome"),
+		refunds: r.Counter("pay2go_refunds_total",
+			"Refunds sent to a provider, by provider, mode (live or test) and outcome (succeeded or failed).",
+			"provider", "mode", "outcome"),
+		gatewayDuration: r.Histogram("pay2go_gateway_request_duration_seconds",
+			"Time payment providers take to answer, by provider and operation.",
+			DefaultBuckets, "provider", "operation"),
+		retries: r.Counter("pay2go_payment_retries_total",
+			"Retries of failed payments, by outcome (allowed or rejected once out of attempts).",
+			"outcome"),
+		webhooks: r.Counter("pay2go_webhook_deliveries_total",
+			"Webhook deliveries, by event type and outcome (delivered or failed).",
+			"event_type", "outcome"),
+		webhookDuration: r.Histogram("pay2go_webhook_delivery_duration_seconds",
+			"Time partner endpoints take to accept webhooks, by outcome.",
+			DefaultBuckets, "outcome"),
+		slowQueries: r.Counter("pay2go_slow_queries_total",
+			"Database queries slower than SLOW_QUERY_THRESHOLD_MS, by statement (select, insert, update, delete, with or other).",
+			"statement"),
+		slowGateway: r.Counter("pay2go_slow_gateway_calls_total",
+			"Payment provider calls slower than SLOW_GATEWAY_THRESHOLD_MS, by provider and operation.",
+			"provider", "operation"),
+	}
+}
+
+// ObserveRequest records a served HTTP request. route is the pattern the
+// request matched, such as /api/v1/transactions/:id, so IDs in paths do not
+// each become a series.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	m.requestDuration.Observe(duration.Seconds(), method, route, strconv.Itoa(status))
+}
+
+// ObserveCall records a served gRPC call. method is the full method name,
+// such as /pay2go.v1.Payments/GetTransaction, and code the name of its
+// status code.
+func (m *Metrics) ObserveCall(method, code string, duration time.Duration) {
+	m.callDuration.Observe(duration.Seconds(), method, code)
+}
+
+// SlowQuery counts a query slower than its threshold
+func (m *Metrics) SlowQuery(statement string) {
+	m.slowQueries.Inc(statement)
+}
+
+// SlowGatewayCall counts a provider call slower than its threshold
+func (m *Metrics) SlowGatewayCall(provider, operation string) {
+	m.slowGateway.Inc(provider, operation)
+}
+
+// outcome labels a call by whether it failed
+func outcome(err error, ok, failed string) string {
+	if err != nil {
+		return failed
+	}
+	return ok
+}
+
+// mode labels a transaction live or test
+func mode(livemode bool) string {
+	if livemode {
+		return "live"
+	}
+	return "test"
+}
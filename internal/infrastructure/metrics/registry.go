@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets are latency buckets in seconds, from 5ms to 10s
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry holds metric families and writes them in the Prometheus text
+// exposition format. Families are written in the order they were added.
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+// family is a metric with its help text and type, written as one block
+type family interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) add(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families = append(r.families, f)
+}
+
+// WritePrometheus writes every family in the text exposition format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]family(nil), r.families...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Counter adds a counter with the given label names
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec: newVec(name, help, "counter", labels)}
+	r.add(c)
+	return c
+}
+
+// Histogram adds a histogram with the given upper bucket bounds, in
+// ascending order, and label names
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{vec: newVec(name, help, "histogram", labels), buckets: buckets}
+	r.add(h)
+	return h
+}
+
+// GaugeFunc adds a gauge whose values are read from collect at every
+// scrape. collect calls set once for each combination of label values.
+func (r *Registry) GaugeFunc(name, help string, labels []string, collect func(set func(value float64, labelValues ...string))) {
+	r.add(&funcFamily{vec: newVec(name, help, "gauge", labels), collect: collect})
+}
+
+// CounterFunc adds a counter read from collect at every scrape, for totals
+// another component already keeps
+func (r *Registry) CounterFunc(name, help string, labels []string, collect func(set func(value float64, labelValues ...string))) {
+	r.add(&funcFamily{vec: newVec(name, help, "counter", labels), collect: collect})
+}
+
+// vec is what every family shares: its name, help, type and label names
+type vec struct {
+	name   string
+	help   string
+	typ    string
+	labels []string
+}
+
+func newVec(name, help, typ string, labels []string) vec {
+	return vec{name: name, help: help, typ: typ, labels: labels}
+}
+
+func (v *vec) writeHeader(w *bufio.Writer) {
+	w.WriteString("# HELP " + v.name + " " + escapeHelp(v.help) + "\n")
+	w.WriteString("# TYPE " + v.name + " " + v.typ + "\n")
+}
+
+// key joins label values into a map key; 0xff never occurs in UTF-8
+func key(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// CounterVec counts events by label values
+type CounterVec struct {
+	vec
+	children sync.Map // key -> *counter
+}
+
+type counter struct {
+	values []string
+	n      atomic.Uint64
+}
+
+// Inc adds one for the given label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds n for the given label values
+func (c *CounterVec) Add(n uint64, labelValues ...string) {
+	k := key(labelValues)
+	child, ok := c.children.Load(k)
+	if !ok {
+		child, _ = c.children.LoadOrStore(k, &counter{values: append([]string(nil), labelValues...)})
+	}
+	child.(*counter).n.Add(n)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.writeHeader(w)
+	for _, child := range sortedChildren[*counter](&c.children) {
+		writeSample(w, c.name, c.labels, child.values, "", "", float64(child.n.Load()))
+	}
+}
+
+// HistogramVec records the distribution of observations by label values
+type HistogramVec struct {
+	vec
+	buckets  []float64
+	children sync.Map // key -> *histogram
+}
+
+type histogram struct {
+	values []string
+	mu     sync.Mutex
+	counts []uint64 // per bucket, not cumulative; the last is +Inf
+	sum    float64
+}
+
+// Observe records value for the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	k := key(labelValues)
+	child, ok := h.children.Load(k)
+	if !ok {
+		child, _ = h.children.LoadOrStore(k, &histogram{
+			values: append([]string(nil), labelValues...),
+			counts: make([]uint64, len(h.buckets)+1),
+		})
+	}
+
+	hist := child.(*histogram)
+	i := sort.SearchFloat64s(h.buckets, value)
+	hist.mu.Lock()
+	hist.counts[i]++
+	hist.sum += value
+	hist.mu.Unlock()
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.writeHeader(w)
+	for _, child := range sortedChildren[*histogram](&h.children) {
+		child.mu.Lock()
+		counts := append([]uint64(nil), child.counts...)
+		sum := child.sum
+		child.mu.Unlock()
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += counts[i]
+			writeSample(w, h.name+"_bucket", h.labels, child.values, "le", formatFloat(bound), float64(cumulative))
+		}
+		cumulative += counts[len(h.buckets)]
+		writeSample(w, h.name+"_bucket", h.labels, child.values, "le", "+Inf", float64(cumulative))
+		writeSample(w, h.name+"_sum", h.labels, child.values, "", "", sum)
+		writeSample(w, h.name+"_count", h.labels, child.values, "", "", float64(cumulative))
+	}
+}
+
+// funcFamily is a gauge or counter whose values are collected on scrape
+type funcFamily struct {
+	vec
+	collect func(set func(value float64, labelValues ...string))
+}
+
+func (f *funcFamily) write(w *bufio.Writer) {
+	f.writeHeader(w)
+	f.collect(func(value float64, labelValues ...string) {
+		writeSample(w, f.name, f.labels, labelValues, "", "", value)
+	})
+}
+
+// labeled is a child of a vec, with its label values
+type labeled interface {
+	labelValues() []string
+}
+
+func (c *counter) labelValues() []string   { return c.values }
+func (h *histogram) labelValues() []string { return h.values }
+
+// sortedChildren returns a vec's children ordered by label values, so
+// scrapes list series in a stable order
+func sortedChildren[T labeled](children *sync.Map) []T {
+	var list []T
+	children.Range(func(_, child interface{}) bool {
+		list = append(list, child.(T))
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return key(list[i].labelValues()) < key(list[j].labelValues())
+	})
+	return list
+}
+
+// writeSample writes one line: name{labels} value. extraName adds a label
+// such as a histogram bucket's le.
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			v := ""
+			if i < len(values) {
+				v = values[i]
+			}
+			w.WriteString(label + `="` + escapeLabel(v) + `"`)
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraName + `="` + extraValue + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(v string) string { return labelEscaper.Replace(v) }
+func escapeHelp(v string) string  { return helpEscaper.Replace(v) }
@@ -0,0 +1,55 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// CheckHealth compares the database's schema version with the migrations
+// the API was built with. It does not wait for the migration lock, so it
+// answers while another instance migrates. Pending migrations or a dirty
+// version take the database down for this API; a newer schema, as during a
+// rolling deploy, only degrades it.
+func (m *Migrator) CheckHealth(ctx context.Context) ports.ComponentHealth {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return ports.ComponentHealth{Status: ports.HealthDown, Error: err.Error()}
+	}
+	defer conn.Close()
+
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return ports.ComponentHealth{Status: ports.HealthDown, Error: err.Error()}
+	}
+
+	pending := 0
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			pending++
+		}
+	}
+
+	health := ports.ComponentHealth{
+		Status: ports.HealthUp,
+		Details: map[string]interface{}{
+			"version": version,
+			"latest":  m.Latest(),
+			"pending": pending,
+			"dirty":   dirty,
+		},
+	}
+	switch {
+	case dirty:
+		health.Status = ports.HealthDown
+		health.Error = fmt.Sprintf("migration %06d failed part-way", version)
+	case pending > 0:
+		health.Status = ports.HealthDown
+		health.Error = fmt.Sprintf("%d migrations are not applied", pending)
+	case version > m.Latest():
+		health.Status = ports.HealthDegraded
+		health.Error = fmt.Sprintf("schema version %06d is newer than this build", version)
+	}
+	return health
+}
@@ -0,0 +1,361 @@
+// Package migrate applies versioned SQL migrations to PostgreSQL, MySQL or
+// SQLite.
+//
+// Migrations are pairs of files named NNNNNN_name.up.sql and
+// NNNNNN_name.down.sql. The applied version is kept in schema_migrations in
+// the same layout as golang-migrate, so databases migrated with the migrate
+// CLI before this package existed carry on from their current version.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Dialect holds the statements the migrator needs for its own bookkeeping
+type Dialect struct {
+	// lock and unlock take and release the lock that stops two instances
+	// migrating at once; the lock is held by the session. Both are empty
+	// when the database has no such lock.
+	lock, unlock string
+	// insertVersion records a version and its dirty flag
+	insertVersion string
+	// transactionalDDL is set when schema changes can be rolled back, so a
+	// failing migration leaves nothing behind
+	transactionalDDL bool
+}
+
+// Dialects of the supported databases
+var (
+	Postgres = Dialect{
+		lock:             "SELECT pg_advisory_lock(72650001)",
+		unlock:           "SELECT pg_advisory_unlock(72650001)",
+		insertVersion:    "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)",
+		transactionalDDL: true,
+	}
+	MySQL = Dialect{
+		lock:          "SELECT GET_LOCK('pay2go_migrate', -1)",
+		unlock:        "SELECT RELEASE_LOCK('pay2go_migrate')",
+		insertVersion: "INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)",
+	}
+	// SQLite has no session locks. A database file is used by one API
+	// process, and a second migrator would fail on the write lock of the
+	// migration transaction rather than apply it twice.
+	SQLite = Dialect{
+		insertVersion:    "INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)",
+		transactionalDDL: true,
+	}
+)
+
+// fileName matches "000001_init_schema.up.sql"
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// ErrDirty is returned when a previous migration failed part-way outside a
+// transaction: on MySQL, whose schema changes commit as they run, or with
+// external tools. The schema has to be checked by hand and the version set
+// with Force.
+var ErrDirty = errors.New("database is in a dirty migration state")
+
+// Migration is one schema version
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load reads the migrations in fsys, ordered by version. Every version needs
+// both an up and a down file, and versions may not repeat.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %06d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New creates a migrator for the migrations in fsys, written for dialect
+func New(db *sql.DB, dialect Dialect, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Latest returns the newest migration version known to the migrator
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the database's current version, 0 if nothing is applied
+func (m *Migrator) Version(ctx context.Context) (version int64, dirty bool, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn) error {
+		version, dirty, err = currentVersion(ctx, conn)
+		return err
+	})
+	return version, dirty, err
+}
+
+// Up applies every migration newer than the database's version and returns
+// the versions applied. On PostgreSQL each migration runs in its own
+// transaction, so a failing migration leaves the database at the previous
+// version; on MySQL it leaves the database dirty at the failed version.
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	var applied []int64
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, migration.Up, migration.Version, migration.Version); err != nil {
+				return fmt.Errorf("failed to apply migration %06d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration.Version)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back up to steps migrations, newest first, and returns the
+// versions rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	var reverted []int64
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+
+			previous := int64(0)
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+
+			if err := m.apply(ctx, conn, migration.Down, migration.Version, previous); err != nil {
+				return fmt.Errorf("failed to roll back migration %06d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration.Version)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Force records version as applied and clean without running anything. It is
+// the way out of a dirty state once the schema has been fixed by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := m.setVersion(ctx, tx, version, false); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// withLock runs fn on one connection holding the migration advisory lock, so
+// API instances starting together apply each migration once
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect.lock != "" {
+		if _, err := conn.ExecContext(ctx, m.dialect.lock); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), m.dialect.unlock)
+	}
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// currentVersion reads the applied version; the table holds at most one row
+func currentVersion(ctx context.Context, conn *sql.Conn) (int64, bool, error) {
+	var version int64
+	var dirty bool
+
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+// cleanVersion reads the applied version and refuses to go on from a dirty one
+func cleanVersion(ctx context.Context, conn *sql.Conn) (int64, error) {
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d: fix the schema, then force the version", ErrDirty, version)
+	}
+	return version, nil
+}
+
+// apply runs the script of migration version and records the resulting
+// version in the same transaction. Without transactional DDL the migration is
+// first recorded as dirty, and only marked clean once the script succeeded.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script string, version, result int64) error {
+	if !m.dialect.transactionalDDL {
+		if err := m.setVersionInTx(ctx, conn, version, true); err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, script); err != nil {
+			return err
+		}
+		return m.setVersionInTx(ctx, conn, result, false)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if err := m.setVersion(ctx, tx, result, false); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setVersionInTx runs setVersion in a transaction of its own
+func (m *Migrator) setVersionInTx(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.setVersion(ctx, tx, version, dirty); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setVersion replaces the recorded version; a clean version 0 means nothing
+// applied
+func (m *Migrator) setVersion(ctx context.Context, tx *sql.Tx, version int64, dirty bool) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("failed to clear schema version: %w", err)
+	}
+
+	if version == 0 && !dirty {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.dialect.insertVersion, version, dirty); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// alertTimeout bounds sending one alert
+const alertTimeout = 10 * time.Second
+
+// Log is where alerts that could not be sent are logged
+type Log interface {
+	Warn(message string, args ...interface{})
+}
+
+// WebhookFailures implements ports.OutboxNotifier by alerting when the relay
+// gives up on delivering a webhook, so someone can reach the partner before
+// more of its events are lost
+type WebhookFailures struct {
+	sink ports.AlertSink
+	log  Log
+
+	// interval is the least time between two alerts about one partner, so
+	// an endpoint that is down raises one alert rather than one per event
+	interval  time.Duration
+	mu        sync.Mutex
+	lastAlert map[uuid.UUID]time.Time
+}
+
+// NewWebhookFailures creates a notifier alerting sink at most once per
+// interval for each partner
+func NewWebhookFailures(sink ports.AlertSink, interval time.Duration, log Log) *WebhookFailures {
+	return &WebhookFailures{sink: sink, log: log, interval: interval, lastAlert: make(map[uuid.UUID]time.Time)}
+}
+
+// Published does nothing: delivered events need no attention
+func (w *WebhookFailures) Published(ctx context.Context, event *entities.OutboxEvent) {}
+
+// Exhausted alerts about a webhook event the relay will not retry, unless
+// its partner was alerted about less than interval ago
+func (w *WebhookFailures) Exhausted(ctx context.Context, event *entities.OutboxEvent) {
+	if !entities.IsWebhookEvent(event.EventType) {
+		return
+	}
+
+	now := time.Now()
+	w.mu.Lock()
+	if last, ok := w.lastAlert[event.PartnerID]; ok && now.Sub(last) < w.interval {
+		w.mu.Unlock()
+		return
+	}
+	w.lastAlert[event.PartnerID] = now
+	w.mu.Unlock()
+
+	alert := ports.Alert{
+		Title: "Webhook deliveries are failing",
+		Text: fmt.Sprintf("Partner %s did not accept a %s webhook after %d attempts; the relay stopped retrying it.",
+			event.PartnerID, event.EventType, event.Attempts),
+		Fields: map[string]string{
+			"Partner":    event.PartnerID.String(),
+			"Event":      event.ID.String(),
+			"Event type": event.EventType,
+			"Last error": event.LastError,
+		},
+	}
+	// Sent apart from the relay, which must not wait on the channel
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+		if err := w.sink.Send(ctx, alert); err != nil {
+			w.log.Warn("Failed to send alert %q: %v", alert.Title, err)
+		}
+	}()
+}
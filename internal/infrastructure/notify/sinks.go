@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Sinks sends each alert to every one of its sinks
+type Sinks []ports.AlertSink
+
+// Send sends alert to every sink, even when one fails
+func (s Sinks) Send(ctx context.Context, alert ports.Alert) error {
+	var errs []error
+	for _, sink := range s {
+		if err := sink.Send(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// NewSink creates the sink posting to the Slack and Teams webhooks that are
+// set, or nil when neither is
+func NewSink(slackURL, teamsURL string, client *http.Client) ports.AlertSink {
+	var sinks Sinks
+	if slackURL != "" {
+		sinks = append(sinks, NewSlackWebhook(slackURL, client))
+	}
+	if teamsURL != "" {
+		sinks = append(sinks, NewTeamsWebhook(teamsURL, client))
+	}
+	switch len(sinks) {
+	case 0:
+		return nil
+	case 1:
+		return sinks[0]
+	}
+	return sinks
+}
@@ -0,0 +1,70 @@
+// Package notify delivers operational alerts to the channels people watch
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SlackWebhook posts alerts to a Slack channel through an incoming webhook
+type SlackWebhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackWebhook creates a sink posting to the incoming webhook url
+func NewSlackWebhook(url string, client *http.Client) *SlackWebhook {
+	return &SlackWebhook{url: url, client: client}
+}
+
+// slackMessage is the body of an incoming webhook; text is mrkdwn
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Send posts alert as one message: the title in bold, the text and a line
+// for each field
+func (s *SlackWebhook) Send(ctx context.Context, alert ports.Alert) error {
+	var text strings.Builder
+	text.WriteString("*" + alert.Title + "*")
+	if alert.Text != "" {
+		text.WriteString("\n" + alert.Text)
+	}
+	names := make([]string, 0, len(alert.Fields))
+	for name := range alert.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		text.WriteString("\n*" + name + ":* " + alert.Fields[name])
+	}
+
+	body, err := json.Marshal(slackMessage{Text: text.String()})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to Slack: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to post to Slack: %s", resp.Status)
+	}
+	return nil
+}
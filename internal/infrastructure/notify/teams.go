@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// TeamsWebhook posts alerts to a Microsoft Teams channel through an incoming
+// webhook or a Workflows "post to a channel when a webhook request is
+// received" flow, both of which accept Adaptive Cards
+type TeamsWebhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewTeamsWebhook creates a sink posting to the webhook url
+func NewTeamsWebhook(url string, client *http.Client) *TeamsWebhook {
+	return &TeamsWebhook{url: url, client: client}
+}
+
+// teamsMessage is a message carrying one Adaptive Card
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []interface{} `json:"body"`
+}
+
+type teamsText struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Weight string `json:"weight,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Wrap   bool   `json:"wrap"`
+}
+
+type teamsFactSet struct {
+	Type  string      `json:"type"`
+	Facts []teamsFact `json:"facts"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Send posts alert as a card: the title in bold, the text and the fields as
+// facts
+func (s *TeamsWebhook) Send(ctx context.Context, alert ports.Alert) error {
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []interface{}{
+			teamsText{Type: "TextBlock", Text: alert.Title, Weight: "Bolder", Size: "Medium", Wrap: true},
+		},
+	}
+	if alert.Text != "" {
+		card.Body = append(card.Body, teamsText{Type: "TextBlock", Text: alert.Text, Wrap: true})
+	}
+	if len(alert.Fields) > 0 {
+		names := make([]string, 0, len(alert.Fields))
+		for name := range alert.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		facts := teamsFactSet{Type: "FactSet"}
+		for _, name := range names {
+			facts.Facts = append(facts.Facts, teamsFact{Title: name, Value: alert.Fields[name]})
+		}
+		card.Body = append(card.Body, facts)
+	}
+
+	body, err := json.Marshal(teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to Teams: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	// Incoming webhooks answer 200, Workflows 202
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("failed to post to Teams: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// adyenNotification is an Adyen standard webhook, a batch of items
+type adyenNotification struct {
+	NotificationItems []struct {
+		Item adyenNotificationItem `json:"NotificationRequestItem"`
+	} `json:"notificationItems"`
+}
+
+// adyenNotificationItem is an event of an Adyen standard webhook
+type adyenNotificationItem struct {
+	AdditionalData map[string]string `json:"additionalData"`
+	Amount         struct {
+		Currency string `json:"currency"`
+		Value    int64  `json:"value"`
+	} `json:"amount"`
+	EventCode           string `json:"eventCode"`
+	MerchantAccountCode string `json:"merchantAccountCode"`
+	MerchantReference   string `json:"merchantReference"`
+	OriginalReference   string `json:"originalReference"`
+	PSPReference        string `json:"pspReference"`
+	Reason              string `json:"reason"`
+	Success             string `json:"success"`
+}
+
+// AdyenEvents implements ports.ProviderEventParser for Adyen standard
+// webhooks. Payments are expected to have the transaction ID, and refunds
+// the refund ID, as their merchant reference.
+type AdyenEvents struct {
+	key []byte
+}
+
+// NewAdyenEvents creates a parser of webhooks signed with the HMAC key of
+// the webhook, given in hex as the Customer Area shows it
+func NewAdyenEvents(hmacKey string) (*AdyenEvents, error) {
+	key, err := hex.DecodeString(hmacKey)
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("adyen HMAC key must be hex")
+	}
+	return &AdyenEvents{key: key}, nil
+}
+
+// Parse verifies the HMAC signature of every item and returns the
+// authorisation and refund outcomes among them
+func (p *AdyenEvents) Parse(body []byte, _ func(name string) string) ([]ports.ProviderEvent, error) {
+	var notification adyenNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, errors.NewValidationError("body", "is not an Adyen notification")
+	}
+
+	var events []ports.ProviderEvent
+	for _, wrapper := range notification.NotificationItems {
+		item := wrapper.Item
+		if !p.verify(item) {
+			return nil, errors.ErrInvalidSignature
+		}
+
+		// Adyen gives notifications no ID of their own; an event code and
+		// outcome are reported once per PSP reference
+		event := ports.ProviderEvent{
+			Provider: valueobjects.ProviderAdyen,
+			ID:       item.EventCode + ":" + item.PSPReference + ":" + item.Success,
+		}
+		reference, _ := uuid.Parse(item.MerchantReference)
+		switch {
+		case item.EventCode == "AUTHORISATION" && item.Success == "true":
+			event.Type = ports.ProviderEventPaymentSucceeded
+			event.TransactionID = reference
+			event.ProviderTransactionID = item.PSPReference
+		case item.EventCode == "AUTHORISATION":
+			event.Type = ports.ProviderEventPaymentFailed
+			event.TransactionID = reference
+			event.ProviderTransactionID = item.PSPReference
+			event.Reason = item.Reason
+		case item.EventCode == "REFUND" && item.Success == "true":
+			event.Type = ports.ProviderEventRefundSucceeded
+			event.RefundID = reference
+			event.ProviderTransactionID = item.OriginalReference
+			event.ProviderRefundID = item.PSPReference
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// verify checks the hmacSignature of item against its signed fields
+func (p *AdyenEvents) verify(item adyenNotificationItem) bool {
+	signature, err := base64.StdEncoding.DecodeString(item.AdditionalData["hmacSignature"])
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+
+	signed := strings.Join([]string{
+		item.PSPReference,
+		item.OriginalReference,
+		item.MerchantAccountCode,
+		item.MerchantReference,
+		fmt.Sprint(item.Amount.Value),
+		item.Amount.Currency,
+		item.EventCode,
+		item.Success,
+	}, ":")
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(signed))
+	return hmac.Equal(signature, mac.Sum(nil))
+}
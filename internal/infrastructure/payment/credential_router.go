@@ -0,0 +1,95 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// GatewayFactory builds a gateway that acts on a partner's own provider account
+type GatewayFactory func(credential *entities.ProviderCredential) (ports.PaymentGateway, error)
+
+// CredentialRouter processes payments on the partner's own provider account when
+// they have stored credentials for the transaction's provider, and on the
+// platform account otherwise
+type CredentialRouter struct {
+	credentials ports.ProviderCredentialRepository
+	platform    ports.PaymentGateway
+	factory     GatewayFactory
+}
+
+// NewCredentialRouter creates a gateway that resolves credentials per transaction's partner
+func NewCredentialRouter(
+	credentials ports.ProviderCredentialRepository,
+	platform ports.PaymentGateway,
+	factory GatewayFactory,
+) ports.PaymentGateway {
+	return &CredentialRouter{
+		credentials: credentials,
+		platform:    platform,
+		factory:     factory,
+	}
+}
+
+// ProcessPayment processes the payment on the partner's account if they have one,
+// recording which account was used on the transaction
+func (r *CredentialRouter) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	credential, err := r.credentials.GetByPartnerAndProvider(ctx, transaction.PartnerID, transaction.Provider)
+	if err == errors.ErrProviderCredentialNotFound {
+		transaction.ProviderCredentialID = nil
+		return r.platform.ProcessPayment(ctx, transaction)
+	}
+	if err != nil {
+		// Never fall back to the platform account for a partner that has its own
+		return "", fmt.Errorf("failed to resolve provider credentials: %w", err)
+	}
+
+	gateway, err := r.factory(credential)
+	if err != nil {
+		return "", fmt.Errorf("failed to create gateway for partner account: %w", err)
+	}
+
+	transaction.ProviderCredentialID = &credential.ID
+	return gateway.ProcessPayment(ctx, transaction)
+}
+
+// ProcessRefund refunds through the account that took the payment
+func (r *CredentialRouter) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	if transaction.ProviderCredentialID == nil {
+		return r.platform.ProcessRefund(ctx, refund, transaction)
+	}
+
+	credential, err := r.credentials.GetByPartnerAndProvider(ctx, transaction.PartnerID, transaction.Provider)
+	if err == errors.ErrProviderCredentialNotFound {
+		return "", errors.ErrProviderCredentialChanged
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve provider credentials: %w", err)
+	}
+
+	// Credentials deleted and re-added may belong to another account
+	if credential.ID != *transaction.ProviderCredentialID {
+		return "", errors.ErrProviderCredentialChanged
+	}
+
+	gateway, err := r.factory(credential)
+	if err != nil {
+		return "", fmt.Errorf("failed to create gateway for partner account: %w", err)
+	}
+
+	return gateway.ProcessRefund(ctx, refund, transaction)
+}
+
+// GetPaymentStatus asks the platform account; the provider ID alone does not
+// identify the partner account
+func (r *CredentialRouter) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {
+	return r.platform.GetPaymentStatus(ctx, providerTransactionID)
+}
+
+// GetProviderName returns the platform provider name
+func (r *CredentialRouter) GetProviderName() string {
+	return r.platform.GetProviderName()
+}
@@ -1,4 +1,3 @@
-package payment
 // Package payment provides payment gateway implementations
 package payment
 
@@ -7,59 +6,65 @@ import (
 	"fmt"
 
 	"Pay2Go/internal/domain/entities"
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	}		return NewMockPaymentGateway("manual")	default:		return NewMockPaymentGateway("adyen")	case "adyen":		return NewMockPaymentGateway("paypal")	case "paypal":		return NewMockPaymentGateway("stripe")	case "stripe":	switch provider {func NewPaymentGateway(provider string) ports.PaymentGateway {// Factory creates appropriate payment gateway based on provider}	return g.namefunc (g *MockPaymentGateway) GetProviderName() string {// GetProviderName returns the provider name}	return "completed", nil	// In production, query provider APIfunc (g *MockPaymentGateway) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {// GetPaymentStatus checks payment status from provider}	return providerRefundID, nil		providerRefundID := fmt.Sprintf("mock_refund_%s_%s", g.name, refund.ID.String()[:8])	// In production, this would call Stripe/PayPal refund APIfunc (g *MockPaymentGateway) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {// ProcessRefund simulates refund processing}	return providerTransactionID, nil	// Simulate processing		providerTransactionID := fmt.Sprintf("mock_%s_%s", g.name, transaction.ID.String()[:8])	// For now, simulate successful payment	// In production, this would call Stripe/PayPal APIfunc (g *MockPaymentGateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {// ProcessPayment simulates payment processing}	return &MockPaymentGateway{name: name}func NewMockPaymentGateway(name string) ports.PaymentGateway {// NewMockPaymentGateway creates a new mock payment gateway}	name stringtype MockPaymentGateway struct {// MockPaymentGateway is a mock implementation for testing/demo)	"Pay2Go/internal/usecases/ports"
\ No newline at end of file
+	"Pay2Go/internal/usecases/ports"
+)
+
+// MockPaymentGateway is a mock implementation for testing/demo
+type MockPaymentGateway struct {
+	name string
+}
+
+// NewMockPaymentGateway creates a new mock payment gateway
+func NewMockPaymentGateway(name string) ports.PaymentGateway {
+	return &MockPaymentGateway{name: name}
+}
+
+// ProcessPayment simulates payment processing
+func (g *MockPaymentGateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	// In production, this would call Stripe/PayPal API
+	// For now, simulate successful payment
+	providerTransactionID := fmt.Sprintf("mock_%s_%s", g.name, transaction.ID.String()[:8])
+
+	// Simulate processing
+	return providerTransactionID, nil
+}
+
+// ProcessRefund simulates refund processing
+func (g *MockPaymentGateway) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	// In production, this would call Stripe/PayPal refund API
+	providerRefundID := fmt.Sprintf("mock_refund_%s_%s", g.name, refund.ID.String()[:8])
+
+	return providerRefundID, nil
+}
+
+// GetPaymentStatus checks payment status from provider
+func (g *MockPaymentGateway) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {
+	// In production, query provider API
+	return "completed", nil
+}
+
+// GetProviderName returns the provider name
+func (g *MockPaymentGateway) GetProviderName() string {
+	return g.name
+}
+
+// Factory creates appropriate payment gateway based on provider
+func NewPaymentGateway(provider string) ports.PaymentGateway {
+	switch provider {
+	case "stripe":
+		return NewMockPaymentGateway("stripe")
+	case "paypal":
+		return NewMockPaymentGateway("paypal")
+	case "adyen":
+		return NewMockPaymentGateway("adyen")
+	default:
+		return NewMockPaymentGateway("manual")
+	}
+}
+
+// NewPartnerAccountGateway creates a gateway acting on a partner's own provider account
+func NewPartnerAccountGateway(credential *entities.ProviderCredential) (ports.PaymentGateway, error) {
+	// In production, this would create a Stripe/PayPal client authenticated
+	// with credential.SecretKey (and credential.AccountID for PayPal)
+	return NewPaymentGateway(credential.Provider.String()), nil
+}
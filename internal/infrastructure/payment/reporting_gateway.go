@@ -0,0 +1,67 @@
+package payment
+
+import (
+	"context"
+	"strconv"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ReportingGateway reports the failures of a gateway to the error tracker,
+// tagged with the partner, transaction and provider they happened for
+type ReportingGateway struct {
+	ports.PaymentGateway
+	reporter ports.ErrorReporter
+}
+
+// NewReportingGateway creates a gateway reporting the failures of next
+func NewReportingGateway(next ports.PaymentGateway, reporter ports.ErrorReporter) ports.PaymentGateway {
+	return &ReportingGateway{PaymentGateway: next, reporter: reporter}
+}
+
+// ProcessPayment processes the payment and reports its failure
+func (g *ReportingGateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	id, err := g.PaymentGateway.ProcessPayment(ctx, transaction)
+	if err != nil {
+		tags := transactionTags(transaction)
+		tags["operation"] = "process_payment"
+		g.reporter.Report(ctx, ports.ErrorReport{Err: err, Tags: tags})
+	}
+	return id, err
+}
+
+// ProcessRefund processes the refund and reports its failure
+func (g *ReportingGateway) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	id, err := g.PaymentGateway.ProcessRefund(ctx, refund, transaction)
+	if err != nil {
+		tags := transactionTags(transaction)
+		tags["operation"] = "process_refund"
+		tags["refund_id"] = refund.ID.String()
+		g.reporter.Report(ctx, ports.ErrorReport{Err: err, Tags: tags})
+	}
+	return id, err
+}
+
+// GetPaymentStatus asks the provider and reports its failure
+func (g *ReportingGateway) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {
+	status, err := g.PaymentGateway.GetPaymentStatus(ctx, providerTransactionID)
+	if err != nil {
+		g.reporter.Report(ctx, ports.ErrorReport{Err: err, Tags: map[string]string{
+			"operation":               "get_payment_status",
+			"provider":                g.GetProviderName(),
+			"provider_transaction_id": providerTransactionID,
+		}})
+	}
+	return status, err
+}
+
+// transactionTags describes the payment a failure happened for
+func transactionTags(transaction *entities.Transaction) map[string]string {
+	return map[string]string{
+		"partner_id":     transaction.PartnerID.String(),
+		"transaction_id": transaction.ID.String(),
+		"provider":       string(transaction.Provider),
+		"livemode":       strconv.FormatBool(transaction.Livemode),
+	}
+}
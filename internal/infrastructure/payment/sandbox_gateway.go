@@ -0,0 +1,88 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// sandboxIDPrefix marks provider IDs issued by the sandbox gateway
+const sandboxIDPrefix = "sandbox_"
+
+// SandboxPaymentGateway simulates a provider for test-mode transactions.
+// It never contacts a real provider and always succeeds.
+type SandboxPaymentGateway struct{}
+
+// NewSandboxPaymentGateway creates a new sandbox payment gateway
+func NewSandboxPaymentGateway() ports.PaymentGateway {
+	return &SandboxPaymentGateway{}
+}
+
+// ProcessPayment simulates payment processing
+func (g *SandboxPaymentGateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	return fmt.Sprintf("%s%s", sandboxIDPrefix, transaction.ID.String()[:8]), nil
+}
+
+// ProcessRefund simulates refund processing
+func (g *SandboxPaymentGateway) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	return fmt.Sprintf("%srefund_%s", sandboxIDPrefix, refund.ID.String()[:8]), nil
+}
+
+// GetPaymentStatus reports every sandbox payment as completed
+func (g *SandboxPaymentGateway) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {
+	return "completed", nil
+}
+
+// GetProviderName returns the provider name
+func (g *SandboxPaymentGateway) GetProviderName() string {
+	return "sandbox"
+}
+
+// ModeRouter sends test-mode transactions to the sandbox gateway and
+// everything else to the live gateway
+type ModeRouter struct {
+	live    ports.PaymentGateway
+	sandbox ports.PaymentGateway
+}
+
+// NewModeRouter creates a gateway that routes by transaction livemode
+func NewModeRouter(live, sandbox ports.PaymentGateway) ports.PaymentGateway {
+	return &ModeRouter{
+		live:    live,
+		sandbox: sandbox,
+	}
+}
+
+// ProcessPayment processes the payment through the gateway for its mode
+func (r *ModeRouter) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	return r.forTransaction(transaction).ProcessPayment(ctx, transaction)
+}
+
+// ProcessRefund processes the refund through the gateway that took the payment
+func (r *ModeRouter) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	return r.forTransaction(transaction).ProcessRefund(ctx, refund, transaction)
+}
+
+// GetPaymentStatus asks the gateway that issued the provider ID
+func (r *ModeRouter) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {
+	if strings.HasPrefix(providerTransactionID, sandboxIDPrefix) {
+		return r.sandbox.GetPaymentStatus(ctx, providerTransactionID)
+	}
+	return r.live.GetPaymentStatus(ctx, providerTransactionID)
+}
+
+// GetProviderName returns the live provider name
+func (r *ModeRouter) GetProviderName() string {
+	return r.live.GetProviderName()
+}
+
+// forTransaction picks the gateway matching the transaction's mode
+func (r *ModeRouter) forTransaction(transaction *entities.Transaction) ports.PaymentGateway {
+	if !transaction.Livemode {
+		return r.sandbox
+	}
+	return r.live
+}
@@ -0,0 +1,143 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// stripeSignatureTolerance is how old a signed Stripe notification may be,
+// so a captured one cannot be replayed later
+const stripeSignatureTolerance = 5 * time.Minute
+
+// stripeEvent is the part of a Stripe event the parser reads
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID               string            `json:"id"`
+			Status           string            `json:"status"`
+			PaymentIntent    string            `json:"payment_intent"`
+			Metadata         map[string]string `json:"metadata"`
+			LastPaymentError *struct {
+				Message string `json:"message"`
+			} `json:"last_payment_error"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// StripeEvents implements ports.ProviderEventParser for the events of a
+// Stripe webhook endpoint. Payment intents are expected to carry the
+// transaction ID, and refunds the refund ID, as transaction_id and
+// refund_id metadata; events without are matched on Stripe's IDs.
+type StripeEvents struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewStripeEvents creates a parser of events signed with the endpoint's
+// signing secret
+func NewStripeEvents(secret string) *StripeEvents {
+	return &StripeEvents{secret: []byte(secret), now: time.Now}
+}
+
+// Parse verifies the Stripe-Signature header and returns the payment
+// intent and refund outcomes of the event
+func (p *StripeEvents) Parse(body []byte, header func(name string) string) ([]ports.ProviderEvent, error) {
+	if err := p.verify(body, header("Stripe-Signature")); err != nil {
+		return nil, err
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, errors.NewValidationError("body", "is not a Stripe event")
+	}
+	if event.ID == "" {
+		return nil, errors.NewValidationError("id", "cannot be empty")
+	}
+	object := event.Data.Object
+
+	parsed := ports.ProviderEvent{
+		Provider: valueobjects.ProviderStripe,
+		ID:       event.ID,
+	}
+	switch {
+	case event.Type == "payment_intent.succeeded":
+		parsed.Type = ports.ProviderEventPaymentSucceeded
+		parsed.ProviderTransactionID = object.ID
+	case event.Type == "payment_intent.payment_failed":
+		parsed.Type = ports.ProviderEventPaymentFailed
+		parsed.ProviderTransactionID = object.ID
+		if object.LastPaymentError != nil {
+			parsed.Reason = object.LastPaymentError.Message
+		}
+	case (event.Type == "refund.created" || event.Type == "refund.updated") && object.Status == "succeeded":
+		parsed.Type = ports.ProviderEventRefundSucceeded
+		parsed.ProviderTransactionID = object.PaymentIntent
+		parsed.ProviderRefundID = object.ID
+		parsed.RefundID = metadataID(object.Metadata, "refund_id")
+	default:
+		return nil, nil
+	}
+	parsed.TransactionID = metadataID(object.Metadata, "transaction_id")
+
+	return []ports.ProviderEvent{parsed}, nil
+}
+
+// verify checks that one of the v1 signatures of header is the HMAC of its
+// timestamp and body, made within the tolerance
+func (p *StripeEvents) verify(body []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return errors.ErrInvalidSignature
+	}
+	age := p.now().Sub(time.Unix(seconds, 0))
+	if age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return errors.ErrSignatureExpired
+	}
+
+	mac := hmac.New(sha256.New, p.secret)
+	fmt.Fprintf(mac, "%s.", timestamp)
+	mac.Write(body)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		got, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return errors.ErrInvalidSignature
+}
+
+// metadataID returns the UUID under key of metadata, or uuid.Nil
+func metadataID(metadata map[string]string, key string) uuid.UUID {
+	id, err := uuid.Parse(metadata[key])
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
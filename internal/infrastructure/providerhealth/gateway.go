@@ -0,0 +1,44 @@
+package providerhealth
+
+import (
+	"context"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// gateway records the outcomes of live payments and refunds
+type gateway struct {
+	ports.PaymentGateway
+	tracker *Tracker
+}
+
+// Gateway wraps g so the outcomes of live payments and refunds count towards
+// their provider's error rate. Sandbox calls never reach a provider and are
+// not counted.
+func (t *Tracker) Gateway(g ports.PaymentGateway) ports.PaymentGateway {
+	return &gateway{PaymentGateway: g, tracker: t}
+}
+
+// ProcessPayment processes the payment and records the outcome
+func (g *gateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	id, err := g.PaymentGateway.ProcessPayment(ctx, transaction)
+	g.record(ctx, transaction, err)
+	return id, err
+}
+
+// ProcessRefund processes the refund and records the outcome
+func (g *gateway) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	id, err := g.PaymentGateway.ProcessRefund(ctx, refund, transaction)
+	g.record(ctx, transaction, err)
+	return id, err
+}
+
+// record counts a call, unless it was in the sandbox or the caller gave up
+// on it, which says nothing about the provider
+func (g *gateway) record(ctx context.Context, transaction *entities.Transaction, err error) {
+	if !transaction.Livemode || ctx.Err() != nil {
+		return
+	}
+	g.tracker.Record(string(transaction.Provider), err)
+}
@@ -0,0 +1,79 @@
+package providerhealth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Probe checks a provider once, returning an error when it is unhealthy
+type Probe func(ctx context.Context) error
+
+// StatusPageProbe checks a provider's status endpoint, which must answer
+// with a 2xx status while the provider is healthy
+func StatusPageProbe(url string, client *http.Client) Probe {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("status endpoint answered %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+// CanaryProbe looks up a known payment through gateway, exercising the
+// provider's API as payments do
+func CanaryProbe(gateway ports.PaymentGateway, providerTransactionID string) Probe {
+	return func(ctx context.Context) error {
+		_, err := gateway.GetPaymentStatus(ctx, providerTransactionID)
+		return err
+	}
+}
+
+// Poller runs probes against providers and records their outcomes
+type Poller struct {
+	tracker *Tracker
+	probes  map[string][]Probe
+	timeout time.Duration
+}
+
+// NewPoller creates a poller running the probes of each provider, each
+// bounded by timeout
+func NewPoller(tracker *Tracker, probes map[string][]Probe, timeout time.Duration) *Poller {
+	return &Poller{tracker: tracker, probes: probes, timeout: timeout}
+}
+
+// Poll runs every probe once, concurrently. Probes cut short by ctx ending,
+// as on shutdown, are not recorded.
+func (p *Poller) Poll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for provider, probes := range p.probes {
+		for _, probe := range probes {
+			wg.Add(1)
+			go func(provider string, probe Probe) {
+				defer wg.Done()
+				probeCtx, cancel := context.WithTimeout(ctx, p.timeout)
+				defer cancel()
+				err := probe(probeCtx)
+				if ctx.Err() == nil {
+					p.tracker.Record(provider, err)
+				}
+			}(provider, probe)
+		}
+	}
+	wg.Wait()
+}
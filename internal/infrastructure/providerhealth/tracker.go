@@ -0,0 +1,195 @@
+// Package providerhealth tracks the error rate of each payment provider,
+// from live calls and from probes polled in the background, and marks
+// providers degraded while too many of their calls fail
+package providerhealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// slots is how many parts the window is counted in; calls leave the window
+// one slot at a time
+const slots = 30
+
+// alertTimeout bounds sending one alert
+const alertTimeout = 10 * time.Second
+
+// Config sets when a provider is degraded
+type Config struct {
+	// A provider is degraded while at least MinCalls of its calls happened
+	// within Window and ErrorRate or more of them failed
+	Window    time.Duration
+	MinCalls  int
+	ErrorRate float64
+}
+
+// Log is where providers becoming degraded or recovering are logged
+type Log interface {
+	Info(message string, args ...interface{})
+	Warn(message string, args ...interface{})
+}
+
+// Tracker counts the calls and failures of each provider over a rolling
+// window. It is a ports.HealthChecker for the readiness check.
+type Tracker struct {
+	cfg  Config
+	log  Log
+	sink ports.AlertSink
+
+	mu        sync.Mutex
+	providers map[string]*counts
+}
+
+// counts are a provider's calls and failures per slot of the window
+type counts struct {
+	calls    [slots]int
+	failures [slots]int
+	// starts are when each slot began; a slot older than the window is reset
+	starts   [slots]time.Time
+	degraded bool
+}
+
+// Rate is a provider's calls and failures within the window
+type Rate struct {
+	Calls    int
+	Failures int
+	Degraded bool
+}
+
+// NewTracker creates a tracker alerting sink when a provider becomes
+// degraded and when it recovers; sink may be nil
+func NewTracker(cfg Config, log Log, sink ports.AlertSink) *Tracker {
+	return &Tracker{cfg: cfg, log: log, sink: sink, providers: make(map[string]*counts)}
+}
+
+// Record counts a call to provider, failed when err is not nil
+func (t *Tracker) Record(provider string, err error) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c, ok := t.providers[provider]
+	if !ok {
+		c = &counts{}
+		t.providers[provider] = c
+	}
+	slotLength := t.cfg.Window / slots
+	slot := int(now.UnixNano()/int64(slotLength)) % slots
+	if now.Sub(c.starts[slot]) >= slotLength {
+		c.calls[slot], c.failures[slot] = 0, 0
+		c.starts[slot] = now.Truncate(slotLength)
+	}
+	c.calls[slot]++
+	if err != nil {
+		c.failures[slot]++
+	}
+
+	t.update(provider, c, now)
+}
+
+// update works out whether provider is degraded now, logging when that
+// changes, and returns its rate. Reads update too, so a provider that stops
+// being called recovers once its failures leave the window.
+func (t *Tracker) update(provider string, c *counts, now time.Time) Rate {
+	var rate Rate
+	for i := range c.calls {
+		if now.Sub(c.starts[i]) < t.cfg.Window {
+			rate.Calls += c.calls[i]
+			rate.Failures += c.failures[i]
+		}
+	}
+	rate.Degraded = rate.Calls >= t.cfg.MinCalls && float64(rate.Failures) >= t.cfg.ErrorRate*float64(rate.Calls)
+	if rate.Degraded == c.degraded {
+		return rate
+	}
+	c.degraded = rate.Degraded
+	fields := map[string]string{
+		"Calls":    fmt.Sprint(rate.Calls),
+		"Failures": fmt.Sprint(rate.Failures),
+		"Window":   t.cfg.Window.String(),
+	}
+	if rate.Degraded {
+		t.log.Warn("Payment provider %s is degraded: %d of %d calls failed in the last %s",
+			provider, rate.Failures, rate.Calls, t.cfg.Window)
+		t.alert(ports.Alert{
+			Title:  "Payment provider " + provider + " is degraded",
+			Text:   fmt.Sprintf("%d of %d calls to %s failed in the last %s. The readiness check reports the API degraded until it recovers.", rate.Failures, rate.Calls, provider, t.cfg.Window),
+			Fields: fields,
+		})
+	} else {
+		t.log.Info("Payment provider %s recovered: %d of %d calls failed in the last %s",
+			provider, rate.Failures, rate.Calls, t.cfg.Window)
+		t.alert(ports.Alert{
+			Title:  "Payment provider " + provider + " recovered",
+			Text:   fmt.Sprintf("%d of %d calls to %s failed in the last %s.", rate.Failures, rate.Calls, provider, t.cfg.Window),
+			Fields: fields,
+		})
+	}
+	return rate
+}
+
+// alert sends alert without holding up the call that changed the state
+func (t *Tracker) alert(alert ports.Alert) {
+	if t.sink == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+		if err := t.sink.Send(ctx, alert); err != nil {
+			t.log.Warn("Failed to send alert %q: %v", alert.Title, err)
+		}
+	}()
+}
+
+// Degraded reports whether provider is degraded, for routing payments away
+// from it. Providers without calls are not.
+func (t *Tracker) Degraded(provider string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.providers[provider]
+	return ok && t.update(provider, c, time.Now()).Degraded
+}
+
+// Rates returns the rate of every provider called so far
+func (t *Tracker) Rates() map[string]Rate {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rates := make(map[string]Rate, len(t.providers))
+	for provider, c := range t.providers {
+		rates[provider] = t.update(provider, c, now)
+	}
+	return rates
+}
+
+// CheckHealth reports the providers' error rates. A degraded provider only
+// degrades the API: payments through the others still work.
+func (t *Tracker) CheckHealth(ctx context.Context) ports.ComponentHealth {
+	health := ports.ComponentHealth{Status: ports.HealthUp, Details: map[string]interface{}{}}
+	var degraded []string
+	for provider, rate := range t.Rates() {
+		status := ports.HealthUp
+		if rate.Degraded {
+			status = ports.HealthDegraded
+			degraded = append(degraded, provider)
+		}
+		health.Details[provider] = map[string]interface{}{
+			"status":   status,
+			"calls":    rate.Calls,
+			"failures": rate.Failures,
+		}
+	}
+	if len(degraded) > 0 {
+		sort.Strings(degraded)
+		health.Status = ports.HealthDegraded
+		health.Error = fmt.Sprintf("degraded providers: %v", degraded)
+	}
+	return health
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// MemoryStore implements ports.RateLimitStore in process memory.
+// Limits are per instance, so it is only suitable for single-instance deployments.
+type MemoryStore struct {
+	windows map[string][]time.Time
+	mu      sync.Mutex
+}
+
+// NewMemoryStore creates an in-memory rate limit store
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		windows: make(map[string][]time.Time),
+	}
+
+	// Cleanup goroutine to remove stale windows
+	go s.cleanup()
+	return s
+}
+
+// Allow records a request for key and reports whether it fits within limit per window
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (ports.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	requests := trimBefore(s.windows[key], now.Add(-window))
+
+	allowed := len(requests) < limit
+	if allowed {
+		requests = append(requests, now)
+	}
+	s.windows[key] = requests
+
+	oldest := now
+	if len(requests) > 0 {
+		oldest = requests[0]
+	}
+
+	return newResult(allowed, limit, len(requests), oldest.Add(window), now), nil
+}
+
+// Peek reports whether another request for key would fit within limit per window,
+// without recording one
+func (s *MemoryStore) Peek(ctx context.Context, key string, limit int, window time.Duration) (ports.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	requests := trimBefore(s.windows[key], now.Add(-window))
+
+	oldest := now
+	if len(requests) > 0 {
+		oldest = requests[0]
+	}
+
+	return newResult(len(requests) < limit, limit, len(requests), oldest.Add(window), now), nil
+}
+
+// cleanup drops windows that have not seen traffic recently
+func (s *MemoryStore) cleanup() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.mu.Lock()
+		cutoff := time.Now().Add(-30 * time.Minute)
+		for key, requests := range s.windows {
+			if len(requests) == 0 || requests[len(requests)-1].Before(cutoff) {
+				delete(s.windows, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// trimBefore drops timestamps older than cutoff (timestamps are in order)
+func trimBefore(requests []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(requests) && !requests[i].After(cutoff) {
+		i++
+	}
+	return requests[i:]
+}
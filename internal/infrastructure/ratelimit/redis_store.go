@@ -0,0 +1,136 @@
+// Package ratelimit provides sliding-window rate limit stores
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// slidingWindowScript trims entries older than the window, then records the
+// request only if the window still has room. Running it as one script keeps
+// the check-and-add atomic across API instances.
+//
+// KEYS[1] = limiter key
+// ARGV[1] = now (ms), ARGV[2] = window (ms), ARGV[3] = limit, ARGV[4] = member
+// Returns {allowed, count, oldest timestamp in window (ms)}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local oldestScore = now
+if oldest[2] then
+	oldestScore = tonumber(oldest[2])
+end
+return {allowed, count, oldestScore}
+`)
+
+// peekWindowScript trims entries older than the window and reports whether
+// another request would fit, without recording one.
+//
+// KEYS[1] = limiter key
+// ARGV[1] = now (ms), ARGV[2] = window (ms), ARGV[3] = limit
+// Returns {allowed, count, oldest timestamp in window (ms)}
+var peekWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	allowed = 1
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local oldestScore = now
+if oldest[2] then
+	oldestScore = tonumber(oldest[2])
+end
+return {allowed, count, oldestScore}
+`)
+
+// RedisStore implements ports.RateLimitStore with a Redis sorted set per key
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Redis-backed rate limit store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Allow records a request for key and reports whether it fits within limit per window
+func (s *RedisStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (ports.RateLimitResult, error) {
+	now := time.Now()
+	values, err := slidingWindowScript.Run(ctx, s.client,
+		[]string{key},
+		now.UnixMilli(),
+		window.Milliseconds(),
+		limit,
+		uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return ports.RateLimitResult{}, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	allowed, count, oldest := values[0] == 1, int(values[1]), values[2]
+
+	return newResult(allowed, limit, count, time.UnixMilli(oldest).Add(window), now), nil
+}
+
+// Peek reports whether another request for key would fit within limit per window,
+// without recording one
+func (s *RedisStore) Peek(ctx context.Context, key string, limit int, window time.Duration) (ports.RateLimitResult, error) {
+	now := time.Now()
+	values, err := peekWindowScript.Run(ctx, s.client,
+		[]string{key},
+		now.UnixMilli(),
+		window.Milliseconds(),
+		limit,
+	).Int64Slice()
+	if err != nil {
+		return ports.RateLimitResult{}, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	allowed, count, oldest := values[0] == 1, int(values[1]), values[2]
+
+	return newResult(allowed, limit, count, time.UnixMilli(oldest).Add(window), now), nil
+}
+
+// newResult builds a rate limit result from the window state
+func newResult(allowed bool, limit, count int, resetAt, now time.Time) ports.RateLimitResult {
+	result := ports.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: limit - count,
+		ResetAt:   resetAt,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !allowed {
+		result.RetryAfter = resetAt.Sub(now)
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+package receipt
+
+// font is one of the standard PDF fonts every reader has, so receipts
+// embed none
+type font int
+
+const (
+	regular font = iota
+	bold
+)
+
+// resource is the name the page's resources give the font
+func (f font) resource() string {
+	if f == bold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// helveticaWidths and helveticaBoldWidths are the advances of the
+// printable ASCII characters, from space to tilde, in thousandths of the
+// font size (Adobe's font metrics)
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// width is how wide text, encoded by encodeText, is at size. Characters
+// beyond ASCII are taken to be as wide as a digit.
+func (f font) width(text []byte, size float64) float64 {
+	widths := &helveticaWidths
+	if f == bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, b := range text {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// winAnsiExtras are the characters WinAnsiEncoding places between 0x80 and
+// 0x9F; from 0xA0 it matches Latin-1
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeText encodes text in WinAnsiEncoding, the encoding of the
+// standard fonts. Characters it lacks, such as Thai, become question marks
+// and control characters spaces.
+func encodeText(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r < 0x20:
+			encoded = append(encoded, ' ')
+		case r < 0x7F:
+			encoded = append(encoded, byte(r))
+		case r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				encoded = append(encoded, b)
+			} else {
+				encoded = append(encoded, '?')
+			}
+		}
+	}
+	return encoded
+}
+
+// fitText shortens encoded text with an ellipsis until it is at most
+// maxWidth wide at size
+func (f font) fitText(text []byte, size, maxWidth float64) []byte {
+	if f.width(text, size) <= maxWidth {
+		return text
+	}
+	ellipsis := []byte("...")
+	for len(text) > 0 && f.width(text, size)+f.width(ellipsis, size) > maxWidth {
+		text = text[:len(text)-1]
+	}
+	return append(append([]byte{}, text...), ellipsis...)
+}
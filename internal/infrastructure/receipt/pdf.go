@@ -0,0 +1,166 @@
+package receipt
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // Logos may be JPEG
+	_ "image/png"  // or PNG
+	"strconv"
+	"strings"
+)
+
+// A4 page size, in points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+)
+
+// page is the content of a one-page PDF document. Coordinates are in
+// points from the bottom-left corner of the page.
+type page struct {
+	content bytes.Buffer
+	images  []*pdfImage
+}
+
+// pdfImage is an image XObject: 8-bit RGB samples, compressed
+type pdfImage struct {
+	width, height int
+	data          []byte
+}
+
+// text draws text in font at size, starting at x on baseline y, in a gray
+// from 0 (black) to 1 (white)
+func (p *page) text(x, y float64, f font, size float64, gray float64, text []byte) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s g %s %s Td (", f.resource(), num(size), num(gray), num(x), num(y))
+	for _, b := range text {
+		if b == '(' || b == ')' || b == '\\' {
+			p.content.WriteByte('\\')
+		}
+		p.content.WriteByte(b)
+	}
+	p.content.WriteString(") Tj ET\n")
+}
+
+// textRight draws text ending at x
+func (p *page) textRight(x, y float64, f font, size float64, gray float64, text []byte) {
+	p.text(x-f.width(text, size), y, f, size, gray, text)
+}
+
+// line draws a line of width from (x1, y1) to (x2, y2) in gray
+func (p *page) line(x1, y1, x2, y2, width, gray float64) {
+	fmt.Fprintf(&p.content, "%s G %s w %s %s m %s %s l S\n", num(gray), num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// image draws img scaled to w by h, with its bottom-left corner at (x, y)
+func (p *page) image(img *pdfImage, x, y, w, h float64) {
+	p.images = append(p.images, img)
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /Im%d Do Q\n", num(w), num(h), num(x), num(y), len(p.images))
+}
+
+// document writes the page out as a PDF file. It carries no dates or IDs
+// of its own, so the same page always gives the same bytes.
+func (p *page) document(title string) []byte {
+	var objects [][]byte
+
+	// 1: catalog, 2: page tree, 3: page, 4-5: fonts, 6: content, then the
+	// images and the document information
+	imageRefs := ""
+	for i := range p.images {
+		imageRefs += fmt.Sprintf(" /Im%d %d 0 R", i+1, 7+i)
+	}
+	objects = append(objects,
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte("<< /Type /Pages /Kids [3 0 R] /Count 1 >>"),
+		[]byte(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> /XObject <<%s >> >> /Contents 6 0 R >>",
+			num(pageWidth), num(pageHeight), imageRefs)),
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"),
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"),
+		stream("", p.content.Bytes()),
+	)
+	for _, img := range p.images {
+		objects = append(objects, stream(
+			fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode ", img.width, img.height),
+			img.data,
+		))
+	}
+	var info bytes.Buffer
+	info.WriteString("<< /Title (")
+	for _, b := range encodeText(title) {
+		if b == '(' || b == ')' || b == '\\' {
+			info.WriteByte('\\')
+		}
+		info.WriteByte(b)
+	}
+	info.WriteString(") /Producer (Pay2Go) >>")
+	objects = append(objects, info.Bytes())
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n", i+1)
+		out.Write(object)
+		out.WriteString("\nendobj\n")
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+	return out.Bytes()
+}
+
+// stream is a stream object of data with the given extra dictionary
+// entries
+func stream(dict string, data []byte) []byte {
+	var object bytes.Buffer
+	fmt.Fprintf(&object, "<< %s/Length %d >>\nstream\n", dict, len(data))
+	object.Write(data)
+	object.WriteString("\nendstream")
+	return object.Bytes()
+}
+
+// num formats a coordinate or size with at most two decimals
+func num(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+// loadImage decodes a PNG or JPEG image for the page. Transparent pixels
+// are blended onto white, the color of the page.
+func loadImage(data []byte) (*pdfImage, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode logo: %w", err)
+	}
+
+	bounds := img.Bounds()
+	samples := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			a := int(c.A)
+			samples = append(samples,
+				byte((int(c.R)*a+255*(255-a))/255),
+				byte((int(c.G)*a+255*(255-a))/255),
+				byte((int(c.B)*a+255*(255-a))/255),
+			)
+		}
+	}
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(samples); err != nil {
+		return nil, fmt.Errorf("failed to compress logo: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress logo: %w", err)
+	}
+	return &pdfImage{width: bounds.Dx(), height: bounds.Dy(), data: compressed.Bytes()}, nil
+}
@@ -0,0 +1,275 @@
+// Package receipt renders the PDF receipts of paid transactions, branded
+// with the partner's name and logo
+package receipt
+
+import (
+	"fmt"
+
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/formatting"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// maxRefundLines is how many refunds get a line of their own; the older
+// ones share one, so a receipt always fits its page
+const maxRefundLines = 15
+
+// receiptDateFormat is how receipts show dates, in UTC like emails do
+const receiptDateFormat = "2 January 2006 15:04 UTC"
+
+// Display names of the codes a receipt shows
+var (
+	paymentMethodNames = map[valueobjects.PaymentMethod]string{
+		valueobjects.PaymentMethodCard:         "Card",
+		valueobjects.PaymentMethodBankTransfer: "Bank transfer",
+		valueobjects.PaymentMethodEWallet:      "E-wallet",
+		valueobjects.PaymentMethodCrypto:       "Cryptocurrency",
+	}
+	providerNames = map[valueobjects.PaymentProvider]string{
+		valueobjects.ProviderStripe: "Stripe",
+		valueobjects.ProviderPayPal: "PayPal",
+		valueobjects.ProviderAdyen:  "Adyen",
+		valueobjects.ProviderManual: "Manual",
+	}
+	cardBrandNames = map[valueobjects.CardBrand]string{
+		valueobjects.CardBrandVisa:       "Visa",
+		valueobjects.CardBrandMastercard: "Mastercard",
+		valueobjects.CardBrandAmex:       "American Express",
+		valueobjects.CardBrandDiscover:   "Discover",
+		valueobjects.CardBrandJCB:        "JCB",
+		valueobjects.CardBrandUnionPay:   "UnionPay",
+		valueobjects.CardBrandDiners:     "Diners Club",
+	}
+	walletNames = map[valueobjects.WalletType]string{
+		valueobjects.WalletApplePay:  "Apple Pay",
+		valueobjects.WalletGooglePay: "Google Pay",
+		valueobjects.WalletPayPal:    "PayPal",
+		valueobjects.WalletAlipay:    "Alipay",
+		valueobjects.WalletWeChatPay: "WeChat Pay",
+		valueobjects.WalletPromptPay: "PromptPay",
+		valueobjects.WalletTrueMoney: "TrueMoney",
+		valueobjects.WalletGrabPay:   "GrabPay",
+	}
+	refundReasonNames = map[valueobjects.RefundReasonCode]string{
+		valueobjects.RefundReasonRequestedByCustomer: "requested by customer",
+		valueobjects.RefundReasonDuplicate:           "duplicate payment",
+		valueobjects.RefundReasonFraudulent:          "fraudulent payment",
+		valueobjects.RefundReasonOther:               "other",
+	}
+)
+
+// Renderer implements ports.ReceiptRenderer, laying a receipt out on one
+// A4 page with the standard PDF fonts
+type Renderer struct{}
+
+// NewRenderer creates a receipt renderer
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// receiptView is a receipt as the page shows it: every text formatted
+type receiptView struct {
+	title   string
+	partner string
+	details []receiptLine
+	items   []receiptLine
+	totals  []receiptLine
+	footer  string
+}
+
+// receiptLine is a label and its value or amount
+type receiptLine struct {
+	label string
+	value string
+}
+
+// Render returns receipt as a PDF document
+func (r *Renderer) Render(receipt ports.Receipt) ([]byte, error) {
+	view, err := newReceiptView(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	var logo *pdfImage
+	if receipt.Logo != nil {
+		if logo, err = loadImage(receipt.Logo); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &page{}
+	drawReceipt(p, view, logo)
+	return p.document(view.title + " " + receipt.Transaction.ID.String()), nil
+}
+
+// newReceiptView formats what receipt shows
+func newReceiptView(receipt ports.Receipt) (receiptView, error) {
+	txn := receipt.Transaction
+	money, err := formatting.NewMoneyFormatter(receipt.Locale.String(), formatting.StyleCode)
+	if err != nil {
+		return receiptView{}, fmt.Errorf("failed to format receipt: %w", err)
+	}
+
+	view := receiptView{
+		title:   "Receipt",
+		partner: receipt.PartnerName,
+		footer:  "Issued by Pay2Go on behalf of " + receipt.PartnerName + ". Keep it for your records.",
+	}
+	if !txn.Livemode {
+		view.title = "Test receipt"
+		view.footer = "Test mode: no money was moved."
+	}
+
+	paidAt := txn.CreatedAt
+	if txn.ProcessedAt != nil {
+		paidAt = *txn.ProcessedAt
+	}
+	view.details = []receiptLine{
+		{"Receipt number", txn.ID.String()},
+		{"Date paid", paidAt.UTC().Format(receiptDateFormat)},
+		{"Payment method", paymentMethod(txn.PaymentMethod, txn.PaymentMethodDetails)},
+		{"Processed by", displayName(providerNames, txn.Provider)},
+	}
+	if txn.ProviderTransactionID != "" {
+		view.details = append(view.details, receiptLine{"Provider reference", txn.ProviderTransactionID})
+	}
+	if txn.CustomerName != "" {
+		view.details = append(view.details, receiptLine{"Billed to", txn.CustomerName})
+	}
+	view.details = append(view.details, receiptLine{"Email", txn.CustomerEmail})
+
+	// The payment, then its refunds; the oldest refunds share a line when
+	// there are too many
+	description := txn.Description
+	if description == "" {
+		description = "Payment"
+	}
+	view.items = []receiptLine{{description, money.Money(txn.Amount)}}
+
+	var refunded int64
+	refunds := receipt.Refunds
+	if len(refunds) > maxRefundLines {
+		var earlier int64
+		for _, refund := range refunds[:len(refunds)-maxRefundLines+1] {
+			earlier += refund.Amount.Amount
+		}
+		view.items = append(view.items, receiptLine{
+			fmt.Sprintf("%d earlier refunds", len(refunds)-maxRefundLines+1),
+			money.MinorUnits(-earlier, txn.Amount.Currency),
+		})
+		refunded += earlier
+		refunds = refunds[len(refunds)-maxRefundLines+1:]
+	}
+	for _, refund := range refunds {
+		label := "Refund, " + refund.CreatedAt.UTC().Format("2 January 2006")
+		if reason, ok := refundReasonNames[refund.Reason.Code]; ok {
+			label += " (" + reason + ")"
+		}
+		view.items = append(view.items, receiptLine{label, money.MinorUnits(-refund.Amount.Amount, refund.Amount.Currency)})
+		refunded += refund.Amount.Amount
+	}
+
+	view.totals = []receiptLine{{"Total paid", money.Money(txn.Amount)}}
+	if refunded > 0 {
+		view.totals = append(view.totals,
+			receiptLine{"Refunded", money.MinorUnits(-refunded, txn.Amount.Currency)},
+			receiptLine{"Net paid", money.MinorUnits(txn.Amount.Amount-refunded, txn.Amount.Currency)},
+		)
+	}
+	return view, nil
+}
+
+// paymentMethod describes how a transaction was paid, as precisely as its
+// details allow: "Visa ending in 4242"
+func paymentMethod(method valueobjects.PaymentMethod, details *valueobjects.PaymentMethodDetails) string {
+	switch {
+	case details == nil:
+	case details.Card != nil:
+		brand, ok := cardBrandNames[details.Card.Brand]
+		if !ok {
+			brand = "Card"
+		}
+		return brand + " ending in " + details.Card.Last4
+	case details.BankAccount != nil:
+		account := "Bank account ending in " + details.BankAccount.Last4
+		if details.BankAccount.BankName != "" {
+			account += " (" + details.BankAccount.BankName + ")"
+		}
+		return account
+	case details.Wallet != nil:
+		return displayName(walletNames, details.Wallet.Type)
+	}
+	return displayName(paymentMethodNames, method)
+}
+
+// displayName is the name of code in names, or code itself
+func displayName[T ~string](names map[T]string, code T) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	return string(code)
+}
+
+// Page layout, in points
+const (
+	margin      = 56.0
+	valueColumn = 180.0
+	logoWidth   = 180.0
+	logoHeight  = 60.0
+	itemsWidth  = 360.0
+	totalsLabel = 330.0
+)
+
+// drawReceipt lays view out on p: the brand and title, the details of the
+// payment, its items and totals, and a footer
+func drawReceipt(p *page, view receiptView, logo *pdfImage) {
+	right := pageWidth - margin
+	top := pageHeight - margin
+
+	// Brand: the logo, scaled to fit its box, over the partner's name
+	if logo != nil {
+		w, h := logoWidth, logoWidth*float64(logo.height)/float64(logo.width)
+		if h > logoHeight {
+			w, h = logoHeight*float64(logo.width)/float64(logo.height), logoHeight
+		}
+		p.image(logo, margin, top-h, w, h)
+	}
+	p.textRight(right, top-20, bold, 20, 0, encodeText(view.title))
+	y := top - logoHeight - 24
+	p.text(margin, y, bold, 14, 0, bold.fitText(encodeText(view.partner), 14, right-margin))
+
+	// Details
+	y -= 32
+	for _, line := range view.details {
+		p.text(margin, y, regular, 9, 0.45, encodeText(line.label))
+		p.text(valueColumn, y, regular, 10, 0, regular.fitText(encodeText(line.value), 10, right-valueColumn))
+		y -= 16
+	}
+
+	// Items
+	y -= 16
+	p.text(margin, y, bold, 10, 0, encodeText("Description"))
+	p.textRight(right, y, bold, 10, 0, encodeText("Amount"))
+	y -= 8
+	p.line(margin, y, right, y, 0.75, 0.6)
+	for _, item := range view.items {
+		y -= 18
+		p.text(margin, y, regular, 10, 0, regular.fitText(encodeText(item.label), 10, itemsWidth))
+		p.textRight(right, y, regular, 10, 0, encodeText(item.value))
+	}
+	y -= 10
+	p.line(margin, y, right, y, 0.75, 0.6)
+
+	// Totals; the last is what the customer paid in the end
+	for i, total := range view.totals {
+		y -= 18
+		f := regular
+		if i == len(view.totals)-1 {
+			f = bold
+		}
+		p.text(totalsLabel, y, f, 10, 0, encodeText(total.label))
+		p.textRight(right, y, f, 10, 0, encodeText(total.value))
+	}
+
+	p.text(margin, margin, regular, 8, 0.45, regular.fitText(encodeText(view.footer), 8, right-margin))
+}
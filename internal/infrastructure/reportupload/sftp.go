@@ -0,0 +1,134 @@
+package reportupload
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// uploadSFTP writes data as name in the destination's directory. The file
+// is written as name.part and renamed once complete, so the partner's
+// systems never pick up half a report.
+func uploadSFTP(ctx context.Context, destination valueobjects.ReportDestination, name string, data []byte, timeout time.Duration) error {
+	config, err := sshConfig(destination, timeout)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(destination.Host, strconv.Itoa(destination.Port))
+	dialer := &net.Dialer{Control: publicAddressesOnly}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SFTP server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		return fmt.Errorf("failed to open SSH session: %w", err)
+	}
+	client := ssh.NewClient(sshConn, channels, requests)
+	defer client.Close()
+
+	files, err := sftp.NewClient(client)
+	if err != nil {
+		return fmt.Errorf("SFTP server refused the sftp subsystem: %w", err)
+	}
+	defer files.Close()
+
+	target := path.Join(destination.Directory, name)
+	if err := putFile(files, target, data); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", target, err)
+	}
+	return nil
+}
+
+// putFile writes data to target.part, then replaces target with it
+func putFile(files *sftp.Client, target string, data []byte) error {
+	partial := target + ".part"
+	file, err := files.OpenFile(partial, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	// OpenSSH replaces the target in one step; plain version 3 renames
+	// fail when the target exists, as it does when a report is delivered
+	// again
+	if _, ok := files.HasExtension("posix-rename@openssh.com"); ok {
+		return files.PosixRename(partial, target)
+	}
+	_ = files.Remove(target)
+	return files.Rename(partial, target)
+}
+
+// sshConfig authenticates with the destination's password or private key
+// and accepts only its pinned host key
+func sshConfig(destination valueobjects.ReportDestination, timeout time.Duration) (*ssh.ClientConfig, error) {
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(destination.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SFTP host key: %w", err)
+	}
+
+	var auth ssh.AuthMethod
+	if destination.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(destination.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SFTP private key: %w", err)
+		}
+		auth = ssh.PublicKeys(signer)
+	} else {
+		auth = ssh.Password(destination.Password)
+	}
+
+	// Ask for the pinned key's algorithm, or a server with several keys
+	// may present another one
+	algorithms := []string{hostKey.Type()}
+	if hostKey.Type() == ssh.KeyAlgoRSA {
+		algorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+
+	return &ssh.ClientConfig{
+		User:              destination.Username,
+		Auth:              []ssh.AuthMethod{auth},
+		HostKeyCallback:   ssh.FixedHostKey(hostKey),
+		HostKeyAlgorithms: algorithms,
+		Timeout:           timeout,
+	}, nil
+}
+
+// publicAddressesOnly refuses connections to private, loopback and
+// link-local addresses, which a host name may resolve to even though it
+// was public when the schedule was saved
+func publicAddressesOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !valueobjects.IsPublicIP(ip) {
+		return fmt.Errorf("SFTP host resolves to %s, which is not a public address", host)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package reportupload delivers partners' scheduled reports to their own
+// SFTP servers and S3 buckets
+package reportupload
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// S3Putter puts a file in a partner's S3 bucket, such as
+// awsevents.S3Uploader
+type S3Putter interface {
+	Put(ctx context.Context, partnerID uuid.UUID, roleARN, bucket, region, key string, data []byte) error
+}
+
+// Uploader implements ports.ReportUploader
+type Uploader struct {
+	s3          S3Putter
+	sftpTimeout time.Duration
+}
+
+// NewUploader creates an uploader putting files in buckets with s3, which
+// may be nil when Pay2Go has no AWS credentials, and bounding SFTP uploads
+// by sftpTimeout
+func NewUploader(s3 S3Putter, sftpTimeout time.Duration) *Uploader {
+	return &Uploader{s3: s3, sftpTimeout: sftpTimeout}
+}
+
+// Upload writes file to the SFTP server or S3 bucket of destination
+func (u *Uploader) Upload(ctx context.Context, partnerID uuid.UUID, destination valueobjects.ReportDestination, file ports.ReportFile) error {
+	switch destination.Type {
+	case valueobjects.ReportDestinationSFTP:
+		return uploadSFTP(ctx, destination, file.Name, file.Data, u.sftpTimeout)
+	case valueobjects.ReportDestinationS3:
+		if u.s3 == nil {
+			return fmt.Errorf("S3 delivery is not configured")
+		}
+		return u.s3.Put(ctx, partnerID, destination.RoleARN, destination.Bucket, destination.Region, destination.Prefix+file.Name, file.Data)
+	}
+	return fmt.Errorf("cannot upload to a %s destination", destination.Type)
+}
@@ -0,0 +1,284 @@
+// Package sentry reports errors and panics to Sentry, or any tracker that
+// accepts Sentry's envelope API, such as GlitchTip. Events are sent in the
+// background so reporting never slows a request down.
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// modulePrefix marks the frames of our own code, which Sentry shows first
+const modulePrefix = "Pay2Go/"
+
+// Config configures the reporter
+type Config struct {
+	// DSN is the project's client key URL,
+	// https://<key>@<host>/<project id>
+	DSN         string
+	Environment string
+	Release     string
+	// SampleRate is the share of errors sent, from 0 to 1. Panics are
+	// always sent.
+	SampleRate float64
+	// BufferSize is how many events wait to be sent; more are dropped
+	BufferSize int
+}
+
+// Reporter is a ports.ErrorReporter sending events to Sentry
+type Reporter struct {
+	cfg        Config
+	endpoint   string
+	auth       string
+	serverName string
+	client     *http.Client
+	events     chan event
+
+	sent, dropped, failed atomic.Int64
+
+	// onError is told about events that could not be sent
+	onError func(err error)
+}
+
+// NewReporter creates a reporter for cfg.DSN sending with client
+func NewReporter(cfg Config, client *http.Client, onError func(err error)) (*Reporter, error) {
+	dsn, err := url.Parse(cfg.DSN)
+	if err != nil || dsn.User == nil || dsn.User.Username() == "" || dsn.Host == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN")
+	}
+	path, projectID := "", strings.Trim(dsn.Path, "/")
+	if i := strings.LastIndexByte(projectID, '/'); i != -1 {
+		path, projectID = "/"+projectID[:i], projectID[i+1:]
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid Sentry DSN: no project ID")
+	}
+
+	serverName, _ := os.Hostname()
+	return &Reporter{
+		cfg:        cfg,
+		endpoint:   fmt.Sprintf("%s://%s%s/api/%s/envelope/", dsn.Scheme, dsn.Host, path, projectID),
+		auth:       "Sentry sentry_version=7, sentry_client=pay2go/1.0, sentry_key=" + dsn.User.Username(),
+		serverName: serverName,
+		client:     client,
+		events:     make(chan event, cfg.BufferSize),
+		onError:    onError,
+	}, nil
+}
+
+// Report queues an event for report, unless it is sampled out or the queue
+// is full
+func (r *Reporter) Report(ctx context.Context, report ports.ErrorReport) {
+	if report.Panic == nil && (report.Err == nil || rand.Float64() >= r.cfg.SampleRate) {
+		return
+	}
+
+	select {
+	case r.events <- r.event(ctx, report):
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Run sends queued events until ctx is done, then sends what is left
+func (r *Reporter) Run(ctx context.Context) {
+	for {
+		select {
+		case e := <-r.events:
+			r.send(e)
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-r.events:
+					r.send(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Stats returns how many events were sent, dropped with the queue full and
+// failed to send
+func (r *Reporter) Stats() (sent, dropped, failed int64) {
+	return r.sent.Load(), r.dropped.Load(), r.failed.Load()
+}
+
+// event is a Sentry event, trimmed to what the API reports
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Exception   struct {
+		Values []exception `json:"values"`
+	} `json:"exception"`
+}
+
+type exception struct {
+	Type       string     `json:"type"`
+	Value      string     `json:"value"`
+	Stacktrace stacktrace `json:"stacktrace"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// event builds the event of report, with the stack of the caller. For a
+// panic that is the panicking goroutine's stack, which deferred recovers
+// run on top of.
+func (r *Reporter) event(ctx context.Context, report ports.ErrorReport) event {
+	e := event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Platform:    "go",
+		Level:       "error",
+		ServerName:  r.serverName,
+		Environment: r.cfg.Environment,
+		Release:     r.cfg.Release,
+		Tags:        make(map[string]string, len(report.Tags)+1),
+	}
+	for name, value := range report.Tags {
+		e.Tags[name] = value
+	}
+	if requestID := ports.RequestID(ctx); requestID != uuid.Nil {
+		e.Tags["request_id"] = requestID.String()
+	}
+
+	ex := exception{Stacktrace: stacktrace{Frames: callers()}}
+	if report.Panic != nil {
+		e.Level = "fatal"
+		ex.Type = "panic"
+		ex.Value = fmt.Sprint(report.Panic)
+	} else {
+		ex.Type, ex.Value = errorType(report.Err), report.Err.Error()
+	}
+	e.Exception.Values = []exception{ex}
+	return e
+}
+
+// send posts e in an envelope
+func (r *Reporter) send(e event) {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	_ = enc.Encode(map[string]interface{}{"event_id": e.EventID, "sent_at": time.Now().UTC()})
+	_ = enc.Encode(map[string]string{"type": "event"})
+	if err := enc.Encode(e); err != nil {
+		r.fail(fmt.Errorf("encode Sentry event: %w", err))
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, &body)
+	if err != nil {
+		r.fail(err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		r.fail(fmt.Errorf("send Sentry event: %w", err))
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		r.fail(fmt.Errorf("send Sentry event: %s", resp.Status))
+		return
+	}
+	r.sent.Add(1)
+}
+
+func (r *Reporter) fail(err error) {
+	r.failed.Add(1)
+	if r.onError != nil {
+		r.onError(err)
+	}
+}
+
+// callers returns the stack of Report's caller, oldest call first, as
+// Sentry lists frames
+func callers() []frame {
+	pcs := make([]uintptr, 64)
+	// Skip runtime.Callers, callers, event and Report
+	n := runtime.Callers(4, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var list []frame
+	for {
+		f, more := frames.Next()
+		module, function := splitFunction(f.Function)
+		list = append(list, frame{
+			Function: function,
+			Module:   module,
+			AbsPath:  f.File,
+			Lineno:   f.Line,
+			InApp:    strings.HasPrefix(module, modulePrefix),
+		})
+		if !more {
+			break
+		}
+	}
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list
+}
+
+// splitFunction splits Pay2Go/internal/x.(*T).Method into its package and
+// function
+func splitFunction(name string) (module, function string) {
+	slash := strings.LastIndexByte(name, '/')
+	if dot := strings.IndexByte(name[slash+1:], '.'); dot != -1 {
+		return name[:slash+1+dot], name[slash+2+dot:]
+	}
+	return "", name
+}
+
+// errorType names err by its innermost error, since wrapping with
+// fmt.Errorf would otherwise group every error as *fmt.wrapError
+func errorType(err error) string {
+	for {
+		next := stderrors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = next
+	}
+}
+
+// newEventID returns a UUID without dashes, as Sentry expects event IDs
+func newEventID() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")
+}
@@ -0,0 +1,97 @@
+package settlementfeed
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// Subdirectories of the drop directory: processed has the reconciled files,
+// and rejected those that could not be read at all
+const (
+	processedDirectory = "processed"
+	rejectedDirectory  = "rejected"
+)
+
+// DirectoryFeed implements ports.SettlementFeed with the CSV files providers
+// upload to a drop directory, usually over SFTP. Files are read one at a
+// time in name order and moved to the processed subdirectory once
+// committed, or to the rejected one when they are not settlement files. Providers must upload under a name not ending in .csv, or
+// starting with a dot, and rename the file when it is complete.
+type DirectoryFeed struct {
+	dir     string
+	invalid InvalidFunc
+
+	// current is the file Next last returned, until it is committed
+	current string
+}
+
+// NewDirectoryFeed creates a feed of the CSV files in dir, creating its
+// subdirectories
+func NewDirectoryFeed(dir string, invalid InvalidFunc) (*DirectoryFeed, error) {
+	for _, sub := range []string{processedDirectory, rejectedDirectory} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create settlement drop directory: %w", err)
+		}
+	}
+	return &DirectoryFeed{dir: dir, invalid: invalid}, nil
+}
+
+// Next returns the entries of the first file in the directory, or none when
+// it has no file. A file that is not a settlement file is passed to the
+// feed's InvalidFunc and moved to the rejected subdirectory.
+func (f *DirectoryFeed) Next(ctx context.Context) ([]*entities.SettlementEntry, error) {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settlement drop directory: %w", err)
+	}
+
+	var names []string
+	for _, file := range files {
+		name := file.Name()
+		if file.Type().IsRegular() && !strings.HasPrefix(name, ".") && strings.EqualFold(filepath.Ext(name), ".csv") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		f.current = ""
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	f.current = names[0]
+	file, err := os.Open(filepath.Join(f.dir, f.current))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open settlement file %s: %w", f.current, err)
+	}
+	entries, err := DecodeCSV(file, f.current, f.invalid)
+	file.Close()
+	if err != nil {
+		f.invalid(f.current, err)
+		return nil, f.move(rejectedDirectory)
+	}
+	return entries, nil
+}
+
+// Commit moves the file Next last returned to the processed subdirectory
+func (f *DirectoryFeed) Commit(ctx context.Context) error {
+	return f.move(processedDirectory)
+}
+
+// move moves the file Next last returned to the subdirectory sub
+func (f *DirectoryFeed) move(sub string) error {
+	if f.current == "" {
+		return nil
+	}
+	name := f.current
+	if err := os.Rename(filepath.Join(f.dir, name), filepath.Join(f.dir, sub, name)); err != nil {
+		return fmt.Errorf("failed to move settlement file %s: %w", name, err)
+	}
+	f.current = ""
+	return nil
+}
@@ -0,0 +1,194 @@
+package settlementfeed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// KafkaConfig locates the topic providers publish settlement entries to,
+// consumed through a Kafka REST Proxy as the eventstream package produces
+type KafkaConfig struct {
+	// RESTURL is the proxy's base URL
+	RESTURL string
+	Topic   string
+	// Group is the consumer group, whose committed offsets survive restarts
+	Group string
+	// Username and Password authenticate with HTTP basic authentication;
+	// empty sends none
+	Username string
+	Password string
+}
+
+// KafkaFeed implements ports.SettlementFeed by consuming a topic of JSON
+// Records through the REST Proxy API v2. Offsets are committed only after
+// the entries are reconciled, so a restarted consumer delivers them again.
+type KafkaFeed struct {
+	cfg     KafkaConfig
+	base    string
+	client  *http.Client
+	invalid InvalidFunc
+
+	// instance is the consumer instance's URL, once created
+	instance string
+}
+
+// NewKafkaFeed creates a feed consuming through the proxy of cfg with
+// client, whose timeout must exceed the proxy's fetch timeout
+func NewKafkaFeed(cfg KafkaConfig, client *http.Client, invalid InvalidFunc) (*KafkaFeed, error) {
+	base, err := url.Parse(strings.TrimSuffix(cfg.RESTURL, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid Kafka REST URL %q", cfg.RESTURL)
+	}
+	return &KafkaFeed{cfg: cfg, base: base.String(), client: client, invalid: invalid}, nil
+}
+
+// kafkaConsumer is the answer to creating a consumer instance
+type kafkaConsumer struct {
+	InstanceID string `json:"instance_id"`
+	BaseURI    string `json:"base_uri"`
+}
+
+// kafkaMessage is a record fetched by a consumer instance
+type kafkaMessage struct {
+	Topic     string          `json:"topic"`
+	Partition int             `json:"partition"`
+	Offset    int64           `json:"offset"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Next fetches the records the proxy has for the consumer, creating and
+// subscribing it first
+func (f *KafkaFeed) Next(ctx context.Context) ([]*entities.SettlementEntry, error) {
+	if f.instance == "" {
+		if err := f.subscribe(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var messages []kafkaMessage
+	if err := f.do(ctx, http.MethodGet, f.instance+"/records", nil, &messages); err != nil {
+		return nil, fmt.Errorf("failed to fetch Kafka records: %w", err)
+	}
+
+	entries := make([]*entities.SettlementEntry, 0, len(messages))
+	for _, message := range messages {
+		source := fmt.Sprintf("%s/%d/%d", message.Topic, message.Partition, message.Offset)
+		var record Record
+		if err := json.Unmarshal(message.Value, &record); err != nil {
+			f.invalid(source, fmt.Errorf("not a settlement record: %w", err))
+			continue
+		}
+		entry, err := record.Entry(source)
+		if err != nil {
+			f.invalid(source, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Commit commits the offsets of every record fetched so far
+func (f *KafkaFeed) Commit(ctx context.Context) error {
+	if f.instance == "" {
+		return nil
+	}
+	if err := f.do(ctx, http.MethodPost, f.instance+"/offsets", nil, nil); err != nil {
+		return fmt.Errorf("failed to commit Kafka offsets: %w", err)
+	}
+	return nil
+}
+
+// Close deletes the consumer instance, so the group rebalances at once
+// rather than after the proxy times it out
+func (f *KafkaFeed) Close(ctx context.Context) error {
+	if f.instance == "" {
+		return nil
+	}
+	instance := f.instance
+	f.instance = ""
+	if err := f.do(ctx, http.MethodDelete, instance, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete Kafka consumer: %w", err)
+	}
+	return nil
+}
+
+// subscribe creates a consumer instance in the group and subscribes it to
+// the topic. Without committed offsets, the group starts at the earliest
+// record, so no entry published before the first start is missed.
+func (f *KafkaFeed) subscribe(ctx context.Context) error {
+	var consumer kafkaConsumer
+	err := f.do(ctx, http.MethodPost, f.base+"/consumers/"+url.PathEscape(f.cfg.Group), map[string]string{
+		"name":               "pay2go-" + uuid.NewString(),
+		"format":             "json",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}, &consumer)
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+
+	if err := f.do(ctx, http.MethodPost, consumer.BaseURI+"/subscription", map[string][]string{
+		"topics": {f.cfg.Topic},
+	}, nil); err != nil {
+		// Leave no instance behind holding partitions of the group
+		_ = f.do(ctx, http.MethodDelete, consumer.BaseURI, nil, nil)
+		return fmt.Errorf("failed to subscribe to Kafka topic %s: %w", f.cfg.Topic, err)
+	}
+
+	f.instance = consumer.BaseURI
+	return nil
+}
+
+// do calls the proxy, sending body and decoding the answer into out when
+// they are not nil
+func (f *KafkaFeed) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil || method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/vnd.kafka.v2+json")
+	}
+	req.Header.Set("Accept", "application/vnd.kafka.json.v2+json")
+	if f.cfg.Username != "" {
+		req.SetBasicAuth(f.cfg.Username, f.cfg.Password)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		// The proxy forgets instances idle past its timeout; create a new one
+		if resp.StatusCode == http.StatusNotFound && f.instance != "" && strings.HasPrefix(endpoint, f.instance) {
+			f.instance = ""
+		}
+		return fmt.Errorf("%s %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
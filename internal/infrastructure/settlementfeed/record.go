@@ -0,0 +1,130 @@
+// Package settlementfeed reads payment providers' settlement and chargeback
+// reports, from a Kafka topic or from CSV files dropped in a directory, for
+// the settlement use cases to reconcile
+package settlementfeed
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// Record is an entry as providers report it: a JSON message on Kafka, or a
+// row of a CSV file whose header names these fields. Amounts are decimals in
+// the currency's major units and OccurredAt is RFC 3339.
+type Record struct {
+	Provider              string `json:"provider"`
+	Reference             string `json:"reference"`
+	Type                  string `json:"type"`
+	ProviderTransactionID string `json:"provider_transaction_id"`
+	Amount                string `json:"amount"`
+	Fee                   string `json:"fee"`
+	Currency              string `json:"currency"`
+	Reason                string `json:"reason"`
+	OccurredAt            string `json:"occurred_at"`
+}
+
+// InvalidFunc is told about records that are not valid entries. They are
+// skipped, since delivering them again would not make them valid.
+type InvalidFunc func(source string, err error)
+
+// Entry validates the record as an entry received from source
+func (r Record) Entry(source string) (*entities.SettlementEntry, error) {
+	provider, err := valueobjects.NewPaymentProvider(r.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := valueobjects.ParseMoney(r.Amount, r.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("amount: %w", err)
+	}
+
+	fee := valueobjects.Money{Currency: amount.Currency}
+	if r.Fee != "" {
+		if fee, err = valueobjects.ParseMoney(r.Fee, r.Currency); err != nil {
+			return nil, fmt.Errorf("fee: %w", err)
+		}
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, r.OccurredAt)
+	if err != nil {
+		return nil, fmt.Errorf("occurred_at must be RFC 3339: %q", r.OccurredAt)
+	}
+
+	entry, err := entities.NewSettlementEntry(
+		provider, r.Reference, entities.SettlementEntryType(r.Type), r.ProviderTransactionID, amount, fee, occurredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entry.Reason = r.Reason
+	entry.Source = source
+	return entry, nil
+}
+
+// csvColumns are the columns of a settlement file that must be present
+var csvColumns = []string{"provider", "reference", "type", "provider_transaction_id", "amount", "currency", "occurred_at"}
+
+// DecodeCSV reads the entries of a settlement file named source. Rows that
+// are not valid entries are passed to invalid and skipped; an error means
+// the file itself could not be read.
+func DecodeCSV(r io.Reader, source string, invalid InvalidFunc) ([]*entities.SettlementEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("header has no %s column", name)
+		}
+	}
+
+	field := func(row []string, name string) string {
+		i, ok := index[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var entries []*entities.SettlementEntry
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+
+		entry, err := Record{
+			Provider:              field(row, "provider"),
+			Reference:             field(row, "reference"),
+			Type:                  field(row, "type"),
+			ProviderTransactionID: field(row, "provider_transaction_id"),
+			Amount:                field(row, "amount"),
+			Fee:                   field(row, "fee"),
+			Currency:              field(row, "currency"),
+			Reason:                field(row, "reason"),
+			OccurredAt:            field(row, "occurred_at"),
+		}.Entry(source)
+		if err != nil {
+			invalid(source, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+}
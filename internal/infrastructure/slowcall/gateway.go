@@ -0,0 +1,44 @@
+package slowcall
+
+import (
+	"context"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// gateway checks the duration of the calls of a ports.PaymentGateway
+type gateway struct {
+	ports.PaymentGateway
+	monitor *Monitor
+}
+
+// Gateway wraps g so its slow calls are logged, counted and alerted on
+func (m *Monitor) Gateway(g ports.PaymentGateway) ports.PaymentGateway {
+	return &gateway{PaymentGateway: g, monitor: m}
+}
+
+// ProcessPayment processes the payment and checks how long it took
+func (g *gateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	start := time.Now()
+	id, err := g.PaymentGateway.ProcessPayment(ctx, transaction)
+	g.monitor.observeGateway(ctx, string(transaction.Provider), "process_payment", time.Since(start))
+	return id, err
+}
+
+// ProcessRefund processes the refund and checks how long it took
+func (g *gateway) ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (string, error) {
+	start := time.Now()
+	id, err := g.PaymentGateway.ProcessRefund(ctx, refund, transaction)
+	g.monitor.observeGateway(ctx, string(transaction.Provider), "process_refund", time.Since(start))
+	return id, err
+}
+
+// GetPaymentStatus asks the provider and checks how long it took
+func (g *gateway) GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error) {
+	start := time.Now()
+	status, err := g.PaymentGateway.GetPaymentStatus(ctx, providerTransactionID)
+	g.monitor.observeGateway(ctx, g.GetProviderName(), "get_payment_status", time.Since(start))
+	return status, err
+}
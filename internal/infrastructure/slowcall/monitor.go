@@ -0,0 +1,187 @@
+// Package slowcall watches database queries and payment provider calls for
+// ones slower than their thresholds. Each slow call is logged as a warning
+// and counted for metrics; when they keep coming, people are alerted.
+package slowcall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// maxQueryLength is how much of a query is logged and alerted with
+const maxQueryLength = 300
+
+// alertTimeout bounds sending one alert
+const alertTimeout = 10 * time.Second
+
+// Config sets what is slow and when slowness is alerted on
+type Config struct {
+	// QueryThreshold and GatewayThreshold are the durations above which a
+	// query or provider call is slow; 0 turns the check off
+	QueryThreshold   time.Duration
+	GatewayThreshold time.Duration
+	// An alert is raised once AlertAfter slow calls of one kind, queries or
+	// one provider's calls, happen within AlertWindow, and at most once per
+	// window
+	AlertWindow time.Duration
+	AlertAfter  int
+}
+
+// Log is where slow calls are logged
+type Log interface {
+	Warn(message string, args ...interface{})
+}
+
+// Counter counts slow calls for metrics
+type Counter interface {
+	SlowQuery(statement string)
+	SlowGatewayCall(provider, operation string)
+}
+
+// Monitor checks the durations of queries and provider calls
+type Monitor struct {
+	cfg     Config
+	log     Log
+	counter Counter
+	// sink is nil when slow calls are only logged and counted
+	sink ports.AlertSink
+
+	mu     sync.Mutex
+	series map[string]*breaches
+}
+
+// breaches are the recent slow calls of one kind
+type breaches struct {
+	times     []time.Time
+	alertedAt time.Time
+}
+
+// NewMonitor creates a monitor; sink may be nil
+func NewMonitor(cfg Config, log Log, counter Counter, sink ports.AlertSink) *Monitor {
+	return &Monitor{
+		cfg:     cfg,
+		log:     log,
+		counter: counter,
+		sink:    sink,
+		series:  make(map[string]*breaches),
+	}
+}
+
+// ObserveQuery checks the duration of a database query
+func (m *Monitor) ObserveQuery(ctx context.Context, query string, duration time.Duration, err error) {
+	if m.cfg.QueryThreshold == 0 || duration < m.cfg.QueryThreshold {
+		return
+	}
+
+	query = compact(query)
+	statement := statementOf(query)
+	m.log.Warn("Slow query: statement=%s duration=%s threshold=%s request_id=%s query=%q",
+		statement, duration, m.cfg.QueryThreshold, requestID(ctx), query)
+	m.counter.SlowQuery(statement)
+
+	if count, alert := m.breach("query"); alert {
+		m.alert(ports.Alert{
+			Title: "Database queries are slow",
+			Text:  fmt.Sprintf("%d queries took longer than %s in the last %s.", count, m.cfg.QueryThreshold, m.cfg.AlertWindow),
+			Fields: map[string]string{
+				"Latest query":    query,
+				"Latest duration": duration.String(),
+			},
+		})
+	}
+}
+
+// observeGateway checks the duration of a payment provider call
+func (m *Monitor) observeGateway(ctx context.Context, provider, operation string, duration time.Duration) {
+	if m.cfg.GatewayThreshold == 0 || duration < m.cfg.GatewayThreshold {
+		return
+	}
+
+	m.log.Warn("Slow gateway call: provider=%s operation=%s duration=%s threshold=%s request_id=%s",
+		provider, operation, duration, m.cfg.GatewayThreshold, requestID(ctx))
+	m.counter.SlowGatewayCall(provider, operation)
+
+	if count, alert := m.breach("gateway:" + provider); alert {
+		m.alert(ports.Alert{
+			Title: "Payment provider " + provider + " is slow",
+			Text:  fmt.Sprintf("%d calls to %s took longer than %s in the last %s.", count, provider, m.cfg.GatewayThreshold, m.cfg.AlertWindow),
+			Fields: map[string]string{
+				"Latest operation": operation,
+				"Latest duration":  duration.String(),
+			},
+		})
+	}
+}
+
+// breach records a slow call of kind and reports how many happened in the
+// window and whether they call for an alert now
+func (m *Monitor) breach(kind string) (int, bool) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.series[kind]
+	if !ok {
+		b = &breaches{}
+		m.series[kind] = b
+	}
+	cutoff := now.Add(-m.cfg.AlertWindow)
+	kept := b.times[:0]
+	for _, t := range b.times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	b.times = append(kept, now)
+
+	if m.sink == nil || len(b.times) < m.cfg.AlertAfter || now.Sub(b.alertedAt) < m.cfg.AlertWindow {
+		return len(b.times), false
+	}
+	b.alertedAt = now
+	return len(b.times), true
+}
+
+// alert sends alert without holding up the slow call
+func (m *Monitor) alert(alert ports.Alert) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+		if err := m.sink.Send(ctx, alert); err != nil {
+			m.log.Warn("Failed to send alert %q: %v", alert.Title, err)
+		}
+	}()
+}
+
+// compact puts a query on one line and shortens it for logs
+func compact(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxQueryLength {
+		query = query[:maxQueryLength] + "..."
+	}
+	return query
+}
+
+// statementOf returns the kind of statement, such as select, so the metric
+// has one series per kind rather than per query
+func statementOf(query string) string {
+	verb, _, _ := strings.Cut(query, " ")
+	switch verb = strings.ToLower(verb); verb {
+	case "select", "insert", "update", "delete", "with":
+		return verb
+	}
+	return "other"
+}
+
+func requestID(ctx context.Context) string {
+	if id := ports.RequestID(ctx); id != uuid.Nil {
+		return id.String()
+	}
+	return ""
+}
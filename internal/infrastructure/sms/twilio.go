@@ -0,0 +1,107 @@
+// Package sms delivers text messages to customers
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// twilioAPI is the base URL of Twilio's REST API
+const twilioAPI = "https://api.twilio.com"
+
+// TwilioConfig holds the Twilio account messages are sent with
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// From is the default sender: a number of the account, a sender name
+	// or a Messaging Service SID
+	From string
+	// BaseURL replaces Twilio's API, e.g. a mock for testing
+	BaseURL string
+}
+
+// TwilioSender implements ports.SMSSender with Twilio's Messages API
+type TwilioSender struct {
+	endpoint  string
+	sid       string
+	authToken string
+	from      string
+	client    *http.Client
+}
+
+// NewTwilioSender creates a sender calling Twilio with client, whose timeout
+// bounds each message
+func NewTwilioSender(cfg TwilioConfig, client *http.Client) (*TwilioSender, error) {
+	if cfg.AccountSID == "" || cfg.AuthToken == "" {
+		return nil, fmt.Errorf("Twilio account SID and auth token are required")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("a default SMS sender is required")
+	}
+	base := cfg.BaseURL
+	if base == "" {
+		base = twilioAPI
+	}
+	return &TwilioSender{
+		endpoint:  strings.TrimSuffix(base, "/") + "/2010-04-01/Accounts/" + url.PathEscape(cfg.AccountSID) + "/Messages.json",
+		sid:       cfg.AccountSID,
+		authToken: cfg.AuthToken,
+		from:      cfg.From,
+		client:    client,
+	}, nil
+}
+
+// twilioError is the body of a refused request
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// SendSMS queues sms at Twilio, from the account's default sender unless
+// it names another. Twilio delivers it on its own; a message the carrier
+// then rejects is not reported back.
+func (s *TwilioSender) SendSMS(ctx context.Context, sms ports.SMS) error {
+	from := sms.From
+	if from == "" {
+		from = s.from
+	}
+	form := url.Values{
+		"To":   {sms.To},
+		"Body": {sms.Body},
+	}
+	if strings.HasPrefix(from, "MG") {
+		form.Set("MessagingServiceSid", from)
+	} else {
+		form.Set("From", from)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.sid, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS through Twilio: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var refused twilioError
+	if json.Unmarshal(body, &refused) == nil && refused.Message != "" {
+		return fmt.Errorf("Twilio refused the SMS: %s (code %d)", refused.Message, refused.Code)
+	}
+	return fmt.Errorf("Twilio refused the SMS: %s", resp.Status)
+}
@@ -0,0 +1,117 @@
+// Package statement renders monthly partner statements as the CSV
+// documents invoicing imports
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// columns is the header row of a statement
+var columns = []string{
+	"statement_id", "period", "partner_id", "partner_name", "currency",
+	"item", "description", "count", "volume", "amount",
+}
+
+// Renderer implements ports.StatementRenderer. A statement has one row per
+// item of each currency; amounts are in major units, signed by their
+// effect on what the partner is owed, and the net row sums them.
+type Renderer struct{}
+
+// NewRenderer creates a statement renderer
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// Render returns statement as a CSV document
+func (r *Renderer) Render(statement *entities.Statement, partner *entities.Partner) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, fmt.Errorf("failed to render statement: %w", err)
+	}
+
+	for _, line := range statement.Currencies {
+		for _, row := range rows(statement, line) {
+			count, volume := "", ""
+			if !row.total {
+				count = strconv.FormatInt(row.count, 10)
+				volume = valueobjects.FormatMinorUnits(row.volume, line.Currency)
+			}
+			err := w.Write([]string{
+				statement.ID.String(),
+				statement.Period.String(),
+				statement.PartnerID.String(),
+				valueobjects.SpreadsheetCell(partner.Name),
+				string(line.Currency),
+				row.item,
+				valueobjects.SpreadsheetCell(row.description),
+				count,
+				volume,
+				valueobjects.FormatMinorUnits(row.amount, line.Currency),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to render statement: %w", err)
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to render statement: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// row is an item of a statement's currency. Totals have no count or
+// volume.
+type row struct {
+	item        string
+	description string
+	count       int64
+	volume      int64
+	amount      int64
+	total       bool
+}
+
+// rows are the items of line, in the order a statement lists them
+func rows(statement *entities.Statement, line entities.StatementCurrency) []row {
+	items := []row{
+		{item: "payments", description: "Paid payments", count: line.Payments.Count, volume: line.Payments.Amount, amount: line.Payments.Amount},
+		{item: "refunds", description: "Completed refunds", count: line.Refunds.Count, volume: line.Refunds.Amount, amount: -line.Refunds.Amount},
+		{item: "chargebacks", description: "Disputes opened", count: line.Chargebacks.Count, volume: line.Chargebacks.Amount, amount: -line.Chargebacks.Amount},
+		{item: "chargeback_reversals", description: "Disputes reversed", count: line.ChargebackReversals.Count, volume: line.ChargebackReversals.Amount, amount: line.ChargebackReversals.Amount},
+	}
+	for _, tierFee := range line.TierFees {
+		items = append(items, row{
+			item:        "fee",
+			description: feeDescription(tierFee, line.Currency),
+			count:       tierFee.Payments.Count,
+			volume:      tierFee.Payments.Amount,
+			amount:      -tierFee.Fee,
+		})
+	}
+	return append(items,
+		row{
+			item:        "chargeback_fee",
+			description: valueobjects.FormatMinorUnits(statement.Pricing.ChargebackFee, line.Currency) + " per chargeback",
+			count:       line.ChargebackFees.Count,
+			volume:      line.Chargebacks.Amount,
+			amount:      -line.ChargebackFees.Amount,
+		},
+		row{item: "total_fees", description: "Total fees", amount: -line.TotalFees, total: true},
+		row{item: "net", description: "Net for the month", amount: line.Net, total: true},
+	)
+}
+
+// feeDescription describes a tier's fee, such as "standard: 2.9% + 0.30"
+func feeDescription(tierFee entities.StatementTierFee, currency valueobjects.Currency) string {
+	return fmt.Sprintf("%s: %s%% + %s", tierFee.Tier,
+		strconv.FormatFloat(tierFee.Percent, 'f', -1, 64),
+		valueobjects.FormatMinorUnits(tierFee.Fixed, currency))
+}
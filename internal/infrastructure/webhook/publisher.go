@@ -0,0 +1,125 @@
+// Package webhook delivers outbox events to partners' webhook endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEventID   = "X-Pay2Go-Event-Id"
+	HeaderEventType = "X-Pay2Go-Event"
+	HeaderSignature = "X-Pay2Go-Signature"
+)
+
+// Publisher implements ports.OutboxPublisher by POSTing events to the
+// partner's webhook URL, signed with the partner's webhook secret
+type Publisher struct {
+	partnerRepo ports.PartnerRepository
+	client      *http.Client
+}
+
+// NewPublisher creates a webhook publisher delivering with client, whose
+// timeout bounds each delivery
+func NewPublisher(partnerRepo ports.PartnerRepository, client *http.Client) *Publisher {
+	return &Publisher{
+		partnerRepo: partnerRepo,
+		client:      client,
+	}
+}
+
+// payload is the JSON body partners receive
+type payload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Publish delivers event to its partner. Partners without a webhook URL
+// (or that no longer exist) have nothing to deliver to, which is not an
+// error. Any non-2xx response is retried later by the relay.
+func (p *Publisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	partner, err := p.partnerRepo.GetByID(ctx, event.PartnerID)
+	if err == errors.ErrPartnerNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get partner: %w", err)
+	}
+	if partner.WebhookURL == "" {
+		return nil
+	}
+
+	body, err := EncodePayload(event, partner.FieldFilter.Webhooks)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, partner.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, event.ID.String())
+	req.Header.Set(HeaderEventType, event.EventType)
+	req.Header.Set(HeaderSignature, Sign(partner.WebhookSecret, time.Now(), body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EncodePayload returns the JSON body partners receive for event, which is
+// the same wherever it is delivered, without the fields of its data the
+// partner hides from webhooks
+func EncodePayload(event *entities.OutboxEvent, hidden []string) ([]byte, error) {
+	data, err := valueobjects.HideFields(event.Payload, hidden)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload{
+		ID:        event.ID.String(),
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt.UTC(),
+		Data:      data,
+	})
+}
+
+// Sign returns the signature header value for body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">".
+// Partners recompute it with their webhook secret and reject deliveries
+// whose timestamp is too old, which stops replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
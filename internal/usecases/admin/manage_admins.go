@@ -0,0 +1,50 @@
+// Package admin contains use cases for back-office admins and their sessions
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// CreateAdminInput represents input for adding a back-office admin
+type CreateAdminInput struct {
+	Email    string
+	Name     string
+	Password string
+}
+
+// CreateAdminUseCase adds a back-office admin
+type CreateAdminUseCase struct {
+	adminRepo ports.AdminUserRepository
+}
+
+// NewCreateAdminUseCase creates a new instance
+func NewCreateAdminUseCase(adminRepo ports.AdminUserRepository) *CreateAdminUseCase {
+	return &CreateAdminUseCase{
+		adminRepo: adminRepo,
+	}
+}
+
+// Execute creates and persists a new admin. The returned admin carries the
+// TOTP secret to enroll in an authenticator app.
+func (uc *CreateAdminUseCase) Execute(ctx context.Context, input CreateAdminInput) (*entities.AdminUser, error) {
+	// Step 1: Create admin entity (generates the TOTP secret)
+	admin, err := entities.NewAdminUser(input.Email, input.Name, input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Persist (fails if the email is already taken)
+	if err := uc.adminRepo.Create(ctx, admin); err != nil {
+		if err == errors.ErrAdminAlreadyExists {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	return admin, nil
+}
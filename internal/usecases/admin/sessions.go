@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// LoginInput represents an admin's sign-in attempt
+type LoginInput struct {
+	Email     string
+	Password  string
+	Code      string // TOTP code from the admin's authenticator app
+	IPAddress string
+	UserAgent string
+}
+
+// LoginOutput carries the new session and its one-time plaintext token
+type LoginOutput struct {
+	Admin   *entities.AdminUser
+	Session *entities.AdminSession
+	Token   string
+}
+
+// LoginUseCase signs an admin in and starts a session
+type LoginUseCase struct {
+	adminRepo   ports.AdminUserRepository
+	sessionRepo ports.AdminSessionRepository
+	auditLogger ports.AuditLogger
+
+	// sessionTTL is how long an admin session token stays valid
+	sessionTTL time.Duration
+}
+
+// NewLoginUseCase creates a new instance
+func NewLoginUseCase(
+	adminRepo ports.AdminUserRepository,
+	sessionRepo ports.AdminSessionRepository,
+	auditLogger ports.AuditLogger,
+	sessionTTL time.Duration,
+) *LoginUseCase {
+	return &LoginUseCase{
+		adminRepo:   adminRepo,
+		sessionRepo: sessionRepo,
+		auditLogger: auditLogger,
+		sessionTTL:  sessionTTL,
+	}
+}
+
+// Execute verifies the password and TOTP code and issues a session token
+func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOutput, error) {
+	// Step 1: Retrieve admin (unknown emails and wrong credentials look the same)
+	admin, err := uc.adminRepo.GetByEmail(ctx, input.Email)
+	if err != nil || admin == nil {
+		return nil, errors.ErrInvalidAdminCredentials
+	}
+
+	// Step 2: Verify password and second factor
+	if err := admin.Authenticate(input.Password, input.Code, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Step 3: Burn the TOTP code before handing out a session
+	admin.RecordLogin()
+	if err := uc.adminRepo.RecordLogin(ctx, admin); err != nil {
+		if err == errors.ErrInvalidAdminCredentials {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record admin login: %w", err)
+	}
+
+	// Step 4: Start session
+	session, token, err := entities.NewAdminSession(admin.ID, uc.sessionTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// Step 5: Log audit event (admins belong to no partner)
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    uuid.Nil,
+			Action:       "admin_logged_in",
+			ResourceType: "admin_user",
+			ResourceID:   admin.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+		})
+	}
+
+	return &LoginOutput{
+		Admin:   admin,
+		Session: session,
+		Token:   token,
+	}, nil
+}
+
+// LogoutUseCase ends an admin's session
+type LogoutUseCase struct {
+	sessionRepo ports.AdminSessionRepository
+}
+
+// NewLogoutUseCase creates a new instance
+func NewLogoutUseCase(sessionRepo ports.AdminSessionRepository) *LogoutUseCase {
+	return &LogoutUseCase{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Execute revokes the session
+func (uc *LogoutUseCase) Execute(ctx context.Context, session *entities.AdminSession) error {
+	session.Revoke()
+
+	if err := uc.sessionRepo.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// MaxRangeDays is the most days analytics can be read for at once
+const MaxRangeDays = 366
+
+// Interval is how volume over time is bucketed
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week"
+	IntervalMonth Interval = "month"
+)
+
+// NewInterval validates and creates an Interval, day by default
+func NewInterval(interval string) (Interval, error) {
+	switch Interval(interval) {
+	case "", IntervalDay:
+		return IntervalDay, nil
+	case IntervalWeek, IntervalMonth:
+		return Interval(interval), nil
+	}
+	return "", errors.NewValidationError("interval", "must be day, week or month")
+}
+
+// Start returns the first day of the bucket day falls in. Weeks start on
+// Monday.
+func (i Interval) Start(day time.Time) time.Time {
+	switch i {
+	case IntervalWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case IntervalMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// Dimension is what success rates are grouped by
+type Dimension string
+
+const (
+	DimensionProvider      Dimension = "provider"
+	DimensionPaymentMethod Dimension = "payment_method"
+)
+
+// NewDimension validates and creates a Dimension, provider by default
+func NewDimension(dimension string) (Dimension, error) {
+	switch Dimension(dimension) {
+	case "", DimensionProvider:
+		return DimensionProvider, nil
+	case DimensionPaymentMethod:
+		return DimensionPaymentMethod, nil
+	}
+	return "", errors.NewValidationError("group_by", "must be provider or payment_method")
+}
+
+// of returns the value of the dimension of rollup
+func (d Dimension) of(rollup ports.AnalyticsRollup) string {
+	if d == DimensionPaymentMethod {
+		return rollup.PaymentMethod
+	}
+	return rollup.Provider
+}
+
+// Query is whose analytics are read, over which days
+type Query struct {
+	PartnerID uuid.UUID
+	Livemode  bool
+	// From and To are UTC days, both inclusive
+	From time.Time
+	To   time.Time
+	// Currency narrows the analytics to one currency when set
+	Currency string
+}
+
+// VolumePoint is the succeeded payments of one currency in a bucket
+type VolumePoint struct {
+	// Start is the first day of the bucket
+	Start    time.Time
+	Currency string
+	Payments int64
+	Volume   int64 // Minor units
+}
+
+// SuccessRate is the outcome of the payments of one provider or payment
+// method, across currencies
+type SuccessRate struct {
+	Key       string
+	Succeeded int64
+	Failed    int64
+}
+
+// Rate is the share of payments that succeeded; nil without payments
+func (s SuccessRate) Rate() *float64 {
+	return share(s.Succeeded, s.Succeeded+s.Failed)
+}
+
+// CurrencyTotals are the payments and refunds of one currency over the
+// range. Amounts are in its minor units.
+type CurrencyTotals struct {
+	Currency       string
+	Payments       int64 // Succeeded payments
+	FailedPayments int64
+	Volume         int64
+	Refunds        int64
+	RefundedVolume int64
+}
+
+// AverageAmount is the average amount of the succeeded payments, rounded
+// half up; nil without payments
+func (t CurrencyTotals) AverageAmount() *int64 {
+	if t.Payments == 0 {
+		return nil
+	}
+	average := (t.Volume + t.Payments/2) / t.Payments
+	return &average
+}
+
+// RefundRate is the share of the volume that was refunded; nil without
+// volume. Refunds are counted on the day they are made, so a range's
+// refunds can be of payments before it.
+func (t CurrencyTotals) RefundRate() *float64 {
+	return share(t.RefundedVolume, t.Volume)
+}
+
+// share is part of whole; nil when whole is 0
+func share(part, whole int64) *float64 {
+	if whole == 0 {
+		return nil
+	}
+	rate := float64(part) / float64(whole)
+	return &rate
+}
+
+// GetAnalyticsUseCase answers a partner's analytics from the daily rollups,
+// so reading them never scans transactions. Today's rollups are as fresh as
+// the last rollup run.
+type GetAnalyticsUseCase struct {
+	analyticsRepo ports.AnalyticsRepository
+}
+
+// NewGetAnalyticsUseCase creates a new instance
+func NewGetAnalyticsUseCase(analyticsRepo ports.AnalyticsRepository) *GetAnalyticsUseCase {
+	return &GetAnalyticsUseCase{analyticsRepo: analyticsRepo}
+}
+
+// Volume returns the succeeded payments of each bucket of interval with
+// any, ordered by bucket and currency. The first and last buckets only
+// count the days of the range.
+func (uc *GetAnalyticsUseCase) Volume(ctx context.Context, query Query, interval Interval) ([]VolumePoint, error) {
+	rollups, err := uc.listRollups(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	type bucket struct {
+		start    time.Time
+		currency string
+	}
+	points := make(map[bucket]*VolumePoint)
+	for _, rollup := range rollups {
+		if rollup.SucceededPayments == 0 {
+			continue
+		}
+		key := bucket{start: interval.Start(rollup.Day), currency: rollup.Currency}
+		point, ok := points[key]
+		if !ok {
+			point = &VolumePoint{Start: key.start, Currency: key.currency}
+			points[key] = point
+		}
+		point.Payments += rollup.SucceededPayments
+		point.Volume += rollup.Volume
+	}
+
+	series := make([]VolumePoint, 0, len(points))
+	for _, point := range points {
+		series = append(series, *point)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if !series[i].Start.Equal(series[j].Start) {
+			return series[i].Start.Before(series[j].Start)
+		}
+		return series[i].Currency < series[j].Currency
+	})
+	return series, nil
+}
+
+// SuccessRates returns the success rate of each provider or payment
+// method, by dimension, most payments first
+func (uc *GetAnalyticsUseCase) SuccessRates(ctx context.Context, query Query, dimension Dimension) ([]SuccessRate, error) {
+	rollups, err := uc.listRollups(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*SuccessRate)
+	for _, rollup := range rollups {
+		if rollup.SucceededPayments+rollup.FailedPayments == 0 {
+			continue
+		}
+		key := dimension.of(rollup)
+		rate, ok := byKey[key]
+		if !ok {
+			rate = &SuccessRate{Key: key}
+			byKey[key] = rate
+		}
+		rate.Succeeded += rollup.SucceededPayments
+		rate.Failed += rollup.FailedPayments
+	}
+
+	rates := make([]SuccessRate, 0, len(byKey))
+	for _, rate := range byKey {
+		rates = append(rates, *rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		a, b := rates[i], rates[j]
+		if a.Succeeded+a.Failed != b.Succeeded+b.Failed {
+			return a.Succeeded+a.Failed > b.Succeeded+b.Failed
+		}
+		return a.Key < b.Key
+	})
+	return rates, nil
+}
+
+// Totals returns the payments and refunds of each currency over the range,
+// ordered by currency, for average amounts and refund rates
+func (uc *GetAnalyticsUseCase) Totals(ctx context.Context, query Query) ([]CurrencyTotals, error) {
+	rollups, err := uc.listRollups(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals []CurrencyTotals
+	byCurrency := make(map[string]int)
+	for _, rollup := range rollups {
+		i, ok := byCurrency[rollup.Currency]
+		if !ok {
+			i = len(totals)
+			byCurrency[rollup.Currency] = i
+			totals = append(totals, CurrencyTotals{Currency: rollup.Currency})
+		}
+		totals[i].Payments += rollup.SucceededPayments
+		totals[i].FailedPayments += rollup.FailedPayments
+		totals[i].Volume += rollup.Volume
+		totals[i].Refunds += rollup.Refunds
+		totals[i].RefundedVolume += rollup.RefundedVolume
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Currency < totals[j].Currency
+	})
+	return totals, nil
+}
+
+// listRollups checks the range of query and reads its rollups from the
+// replica when there is one
+func (uc *GetAnalyticsUseCase) listRollups(ctx context.Context, query Query) ([]ports.AnalyticsRollup, error) {
+	from, to := ports.UsageDay(query.From), ports.UsageDay(query.To)
+	if to.Before(from) {
+		return nil, errors.NewValidationError("date_to", "must not be before date_from")
+	}
+	if to.Sub(from) >= MaxRangeDays*24*time.Hour {
+		return nil, errors.NewValidationError("date_from", fmt.Sprintf("range must not exceed %d days", MaxRangeDays))
+	}
+
+	rollups, err := uc.analyticsRepo.ListRollups(ports.ReadOnly(ctx), ports.AnalyticsQuery{
+		PartnerID: query.PartnerID,
+		Livemode:  query.Livemode,
+		From:      from,
+		To:        to,
+		Currency:  query.Currency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics: %w", err)
+	}
+	return rollups, nil
+}
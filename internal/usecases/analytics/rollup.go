@@ -0,0 +1,265 @@
+// Package analytics rolls partners' payments up by day and answers the
+// analytics endpoints from the rollups
+package analytics
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Page sizes of what rollups are built from; refunds and partners have no
+// stream
+const (
+	refundPageSize  = 500
+	partnerPageSize = 100
+)
+
+// rolledUpStatuses are the statuses of the payments rollups count: those
+// that succeeded, refunded since or not, and those that failed
+var rolledUpStatuses = []entities.TransactionStatus{
+	entities.StatusCompleted,
+	entities.StatusPartiallyRefunded,
+	entities.StatusRefunded,
+	entities.StatusFailed,
+}
+
+// RollupAnalyticsUseCase rebuilds the daily analytics rollups from the
+// transactions and refunds. Payments settle and are refunded after the day
+// they are made, so each run rebuilds the last days rather than only
+// today. Instances running it at once rebuild the same rollups.
+type RollupAnalyticsUseCase struct {
+	partnerRepo     ports.PartnerRepository
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	analyticsRepo   ports.AnalyticsRepository
+
+	// lookbackDays is how many days up to today each run rebuilds;
+	// backfillDays how many the first run builds, and the most any does
+	lookbackDays int
+	backfillDays int
+}
+
+// NewRollupAnalyticsUseCase creates a new instance rebuilding lookbackDays
+// days each run and backfilling backfillDays days into empty rollups
+func NewRollupAnalyticsUseCase(
+	partnerRepo ports.PartnerRepository,
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	analyticsRepo ports.AnalyticsRepository,
+	lookbackDays, backfillDays int,
+) *RollupAnalyticsUseCase {
+	return &RollupAnalyticsUseCase{
+		partnerRepo:     partnerRepo,
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		analyticsRepo:   analyticsRepo,
+		lookbackDays:    lookbackDays,
+		backfillDays:    backfillDays,
+	}
+}
+
+// Execute rebuilds every partner's rollups of the last days as of now and
+// returns how many partners it rolled up. The days rebuilt reach back from
+// the latest rolled up, so runs missed while no instance was up are caught
+// up. A partner failing is reported after the others are rolled up.
+func (uc *RollupAnalyticsUseCase) Execute(ctx context.Context, now time.Time) (int, error) {
+	today := ports.UsageDay(now)
+	from, err := uc.firstDay(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	rolledUp := 0
+	var failed error
+	for offset := 0; ; offset += partnerPageSize {
+		partners, err := uc.partnerRepo.List(ports.ReadOnly(ctx), partnerPageSize, offset)
+		if err != nil {
+			return rolledUp, fmt.Errorf("failed to list partners: %w", err)
+		}
+		for _, partner := range partners {
+			if err := uc.rollupPartner(ctx, partner.ID, from, today); err != nil {
+				if failed == nil {
+					failed = fmt.Errorf("failed to roll up the analytics of partner %s: %w", partner.ID, err)
+				}
+				continue
+			}
+			rolledUp++
+		}
+		if len(partners) < partnerPageSize {
+			return rolledUp, failed
+		}
+	}
+}
+
+// firstDay returns the first day a run on today rebuilds
+func (uc *RollupAnalyticsUseCase) firstDay(ctx context.Context, today time.Time) (time.Time, error) {
+	earliest := today.AddDate(0, 0, 1-uc.backfillDays)
+	latest, ok, err := uc.analyticsRepo.LatestDay(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
+		return earliest, nil
+	}
+
+	from := today
+	if latest.Before(from) {
+		from = latest
+	}
+	from = from.AddDate(0, 0, 1-uc.lookbackDays)
+	if from.Before(earliest) {
+		return earliest, nil
+	}
+	return from, nil
+}
+
+// refundedPayment is what a refund's rollup takes from its payment
+type refundedPayment struct {
+	provider      valueobjects.PaymentProvider
+	paymentMethod valueobjects.PaymentMethod
+}
+
+// rollupPartner rebuilds partnerID's rollups of the days from from to to,
+// both inclusive
+func (uc *RollupAnalyticsUseCase) rollupPartner(ctx context.Context, partnerID uuid.UUID, from, to time.Time) error {
+	rollups := make(map[analyticsKey]*ports.AnalyticsRollup)
+	rollupOf := func(day time.Time, livemode bool, currency valueobjects.Currency, payment refundedPayment) *ports.AnalyticsRollup {
+		key := analyticsKey{
+			day:           ports.UsageDay(day),
+			livemode:      livemode,
+			currency:      string(currency),
+			provider:      string(payment.provider),
+			paymentMethod: string(payment.paymentMethod),
+		}
+		rollup, ok := rollups[key]
+		if !ok {
+			rollup = &ports.AnalyticsRollup{
+				PartnerID:     partnerID,
+				Day:           key.day,
+				Livemode:      livemode,
+				Currency:      key.currency,
+				Provider:      key.provider,
+				PaymentMethod: key.paymentMethod,
+			}
+			rollups[key] = rollup
+		}
+		return rollup
+	}
+
+	// Payments, keeping those refunded so their refunds need not load them
+	end := to.AddDate(0, 0, 1)
+	refunded := make(map[uuid.UUID]refundedPayment)
+	query := ports.TransactionQuery{
+		PartnerID:   &partnerID,
+		Statuses:    rolledUpStatuses,
+		CreatedFrom: &from,
+		CreatedTo:   &end,
+		Ascending:   true,
+	}
+	err := uc.transactionRepo.Stream(ports.ReadOnly(ctx), query, func(txn *entities.Transaction) error {
+		payment := refundedPayment{provider: txn.Provider, paymentMethod: txn.PaymentMethod}
+		rollup := rollupOf(txn.CreatedAt, txn.Livemode, txn.Amount.Currency, payment)
+		if txn.Status == entities.StatusFailed {
+			rollup.FailedPayments++
+			return nil
+		}
+		rollup.SucceededPayments++
+		rollup.Volume += txn.Amount.Amount
+		if txn.Status != entities.StatusCompleted {
+			refunded[txn.ID] = payment
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read payments: %w", err)
+	}
+
+	// Refunds, of both modes
+	for _, livemode := range []bool{true, false} {
+		status := entities.RefundStatusCompleted
+		query := ports.RefundQuery{
+			PartnerID:   partnerID,
+			Livemode:    livemode,
+			Status:      &status,
+			CreatedFrom: &from,
+			CreatedTo:   &end,
+			Limit:       refundPageSize,
+		}
+		for {
+			refunds, err := uc.refundRepo.List(ports.ReadOnly(ctx), query)
+			if err != nil {
+				return fmt.Errorf("failed to read refunds: %w", err)
+			}
+			for _, refund := range refunds {
+				payment, ok := refunded[refund.TransactionID]
+				if !ok {
+					txn, err := uc.transactionRepo.GetByID(ports.ReadOnly(ctx), refund.TransactionID)
+					if stderrors.Is(err, errors.ErrTransactionNotFound) {
+						// Deleted payments are not rolled up, nor their refunds
+						continue
+					}
+					if err != nil {
+						return fmt.Errorf("failed to read refunded payment: %w", err)
+					}
+					payment = refundedPayment{provider: txn.Provider, paymentMethod: txn.PaymentMethod}
+					refunded[refund.TransactionID] = payment
+				}
+				rollup := rollupOf(refund.CreatedAt, livemode, refund.Amount.Currency, payment)
+				rollup.Refunds++
+				rollup.RefundedVolume += refund.Amount.Amount
+			}
+			if len(refunds) < refundPageSize {
+				break
+			}
+			query.Offset += refundPageSize
+		}
+	}
+
+	list := make([]ports.AnalyticsRollup, 0, len(rollups))
+	for _, rollup := range rollups {
+		list = append(list, *rollup)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return rollupBefore(list[i], list[j])
+	})
+	if err := uc.analyticsRepo.ReplaceRollups(ctx, partnerID, from, to, list); err != nil {
+		return fmt.Errorf("failed to save rollups: %w", err)
+	}
+	return nil
+}
+
+// analyticsKey identifies a rollup of a partner
+type analyticsKey struct {
+	day           time.Time
+	livemode      bool
+	currency      string
+	provider      string
+	paymentMethod string
+}
+
+// rollupBefore orders rollups as the repository lists them
+func rollupBefore(a, b ports.AnalyticsRollup) bool {
+	if !a.Day.Equal(b.Day) {
+		return a.Day.Before(b.Day)
+	}
+	if a.Livemode != b.Livemode {
+		return a.Livemode
+	}
+	if a.Currency != b.Currency {
+		return a.Currency < b.Currency
+	}
+	if a.Provider != b.Provider {
+		return a.Provider < b.Provider
+	}
+	return a.PaymentMethod < b.PaymentMethod
+}
@@ -0,0 +1,84 @@
+package apikey
+
+import (
+	"context"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// keyPrefixLength is how many leading characters identify a key
+const keyPrefixLength = 8
+
+// Authenticator resolves API key credentials to the key they belong to.
+// It is the single place where API key secrets are checked.
+type Authenticator struct {
+	apiKeyRepo ports.APIKeyRepository
+}
+
+// NewAuthenticator creates a new API key authenticator
+func NewAuthenticator(apiKeyRepo ports.APIKeyRepository) *Authenticator {
+	return &Authenticator{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Authenticate validates a plaintext bearer key
+func (a *Authenticator) Authenticate(ctx context.Context, plaintext string) (*entities.APIKey, error) {
+	// Step 1: Find key by prefix; unknown prefixes cost the same as known ones
+	key, err := a.lookup(ctx, plaintext)
+	if err != nil {
+		entities.SimulateAPIKeyValidation(plaintext)
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	// Step 2: Validate key (revocation, auth method, constant-time hash check)
+	if err := key.Validate(plaintext); err != nil {
+		return nil, err
+	}
+
+	// Step 3: Upgrade legacy hashes now that the plaintext is known to be right
+	if key.NeedsRehash() {
+		if err := key.Rehash(plaintext); err == nil {
+			_ = a.apiKeyRepo.Update(ctx, key)
+		}
+	}
+
+	return key, nil
+}
+
+// AuthenticateSigned validates an HMAC signature made with the key identified by prefix
+func (a *Authenticator) AuthenticateSigned(ctx context.Context, prefix, message, signature string) (*entities.APIKey, error) {
+	if len(prefix) != keyPrefixLength {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	key, err := a.apiKeyRepo.GetByPrefix(ctx, prefix)
+	if err != nil || key == nil {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	if err := key.VerifySignature(message, signature); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// lookup finds the key a plaintext belongs to by its prefix
+func (a *Authenticator) lookup(ctx context.Context, plaintext string) (*entities.APIKey, error) {
+	if len(plaintext) < keyPrefixLength {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	key, err := a.apiKeyRepo.GetByPrefix(ctx, plaintext[:keyPrefixLength])
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, errors.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
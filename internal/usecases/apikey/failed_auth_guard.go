@@ -0,0 +1,98 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// LockoutPolicy bounds failed authentication attempts within a sliding window
+type LockoutPolicy struct {
+	// MaxFailuresPerKey limits failures for one key prefix from one source IP.
+	// Counting per IP keeps an attacker who knows a prefix from locking the
+	// partner out everywhere.
+	MaxFailuresPerKey int
+
+	// MaxFailuresPerIP limits failures from one source IP across all keys
+	MaxFailuresPerIP int
+
+	// Window is how long failures count, and so how long a lockout lasts
+	Window time.Duration
+}
+
+// FailedAuthGuard throttles credential guessing. Once a key prefix or source IP
+// reaches its failure limit, further attempts are refused before any
+// repository lookup until the oldest failures leave the window.
+type FailedAuthGuard struct {
+	store       ports.RateLimitStore
+	auditLogger ports.AuditLogger
+	policy      LockoutPolicy
+}
+
+// NewFailedAuthGuard creates a new failed authentication guard
+func NewFailedAuthGuard(store ports.RateLimitStore, auditLogger ports.AuditLogger, policy LockoutPolicy) *FailedAuthGuard {
+	return &FailedAuthGuard{
+		store:       store,
+		auditLogger: auditLogger,
+		policy:      policy,
+	}
+}
+
+// Check reports whether credentials with prefix may be tried from ipAddress.
+// Locked out callers get ErrTooManyFailures and how long until they may retry.
+// An unavailable store fails open, like rate limiting.
+func (g *FailedAuthGuard) Check(ctx context.Context, prefix, ipAddress string) (time.Duration, error) {
+	for _, counter := range g.counters(prefix, ipAddress) {
+		result, err := g.store.Peek(ctx, counter.key, counter.limit, g.policy.Window)
+		if err == nil && !result.Allowed {
+			return result.RetryAfter, errors.ErrTooManyFailures
+		}
+	}
+
+	return 0, nil
+}
+
+// RecordFailure counts a failed attempt and audits the attempt that triggers a lockout
+func (g *FailedAuthGuard) RecordFailure(ctx context.Context, prefix, ipAddress, userAgent string) {
+	for _, counter := range g.counters(prefix, ipAddress) {
+		// Only the attempt that uses up the window starts a lockout
+		result, err := g.store.Allow(ctx, counter.key, counter.limit, g.policy.Window)
+		if err != nil || !result.Allowed || result.Remaining > 0 {
+			continue
+		}
+
+		if g.auditLogger != nil {
+			_ = g.auditLogger.LogAction(ctx, ports.AuditAction{
+				PartnerID:    uuid.Nil,
+				Action:       "auth_locked_out",
+				ResourceType: counter.scope,
+				IPAddress:    ipAddress,
+				UserAgent:    userAgent,
+				Changes: map[string]interface{}{
+					"key_prefix":   prefix,
+					"max_failures": counter.limit,
+					"locked_until": result.ResetAt,
+				},
+			})
+		}
+	}
+}
+
+// failureCounter is one sliding window of failed attempts
+type failureCounter struct {
+	scope string
+	key   string
+	limit int
+}
+
+// counters lists the windows an attempt counts against
+func (g *FailedAuthGuard) counters(prefix, ipAddress string) []failureCounter {
+	return []failureCounter{
+		{scope: "api_key_prefix", key: "authfail:key:" + prefix + ":" + ipAddress, limit: g.policy.MaxFailuresPerKey},
+		{scope: "ip_address", key: "authfail:ip:" + ipAddress, limit: g.policy.MaxFailuresPerIP},
+	}
+}
@@ -0,0 +1,265 @@
+// Package apikey contains use cases for managing partner API keys
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// CreateAPIKeyInput represents input for creating an API key
+type CreateAPIKeyInput struct {
+	PartnerID  uuid.UUID
+	KeyID      uuid.UUID // Chosen by the caller to make creation idempotent; uuid.Nil generates one
+	Label      string
+	Scopes     []string
+	AuthMethod string
+	Livemode   bool
+	IPAddress  string
+	UserAgent  string
+}
+
+// CreateAPIKeyOutput carries the new key and its one-time plaintext value.
+// When input.KeyID names an existing identical key, that key is returned
+// instead, without plaintext, and Created is false.
+type CreateAPIKeyOutput struct {
+	Key       *entities.APIKey
+	Plaintext string
+	Created   bool
+}
+
+// CreateAPIKeyUseCase issues a new scoped API key for a partner
+type CreateAPIKeyUseCase struct {
+	apiKeyRepo  ports.APIKeyRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewCreateAPIKeyUseCase creates a new instance
+func NewCreateAPIKeyUseCase(apiKeyRepo ports.APIKeyRepository, auditLogger ports.AuditLogger) *CreateAPIKeyUseCase {
+	return &CreateAPIKeyUseCase{
+		apiKeyRepo:  apiKeyRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute creates and persists a new API key
+func (uc *CreateAPIKeyUseCase) Execute(ctx context.Context, input CreateAPIKeyInput) (*CreateAPIKeyOutput, error) {
+	// Step 1: Validate scopes
+	scopes := make([]valueobjects.APIKeyScope, len(input.Scopes))
+	for i, raw := range input.Scopes {
+		scope, err := valueobjects.NewAPIKeyScope(raw)
+		if err != nil {
+			return nil, err
+		}
+		scopes[i] = scope
+	}
+
+	// Step 2: Create key entity
+	key, plaintext, err := entities.NewAPIKey(
+		input.PartnerID,
+		input.Label,
+		scopes,
+		entities.APIKeyAuthMethod(input.AuthMethod),
+		input.Livemode,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: A caller-chosen ID that exists is only fine for the same key
+	if input.KeyID != uuid.Nil {
+		existing, err := uc.apiKeyRepo.GetByID(ctx, input.KeyID)
+		switch {
+		case err == nil && sameAPIKey(existing, key):
+			return &CreateAPIKeyOutput{Key: existing}, nil
+		case err == nil:
+			return nil, errors.ErrAPIKeyIDTaken
+		case err != errors.ErrAPIKeyNotFound:
+			return nil, err
+		}
+		key.ID = input.KeyID
+	}
+
+	// Step 4: Persist
+	if err := uc.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	// Step 5: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "api_key_created",
+			ResourceType: "api_key",
+			ResourceID:   key.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"label":       key.Label,
+				"scopes":      input.Scopes,
+				"auth_method": key.AuthMethod,
+				"livemode":    key.Livemode,
+			},
+		})
+	}
+
+	return &CreateAPIKeyOutput{
+		Key:       key,
+		Plaintext: plaintext,
+		Created:   true,
+	}, nil
+}
+
+// sameAPIKey reports whether existing is an active key of candidate's
+// partner with candidate's label, scopes, authentication method and mode
+func sameAPIKey(existing, candidate *entities.APIKey) bool {
+	if existing.IsRevoked() ||
+		existing.PartnerID != candidate.PartnerID ||
+		existing.Label != candidate.Label ||
+		existing.AuthMethod != candidate.AuthMethod ||
+		existing.Livemode != candidate.Livemode {
+		return false
+	}
+
+	return reflect.DeepEqual(scopeSet(existing.Scopes), scopeSet(candidate.Scopes))
+}
+
+// scopeSet returns scopes without order or repetition
+func scopeSet(scopes []valueobjects.APIKeyScope) map[valueobjects.APIKeyScope]bool {
+	set := make(map[valueobjects.APIKeyScope]bool, len(scopes))
+	for _, scope := range scopes {
+		set[scope] = true
+	}
+	return set
+}
+
+// ListAPIKeysUseCase lists a partner's API keys
+type ListAPIKeysUseCase struct {
+	apiKeyRepo ports.APIKeyRepository
+}
+
+// NewListAPIKeysUseCase creates a new instance
+func NewListAPIKeysUseCase(apiKeyRepo ports.APIKeyRepository) *ListAPIKeysUseCase {
+	return &ListAPIKeysUseCase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute lists API keys for the partner. A positive unusedFor narrows the list
+// to active keys not used for at least that long, which are candidates for revocation.
+func (uc *ListAPIKeysUseCase) Execute(ctx context.Context, partnerID uuid.UUID, unusedFor time.Duration) ([]*entities.APIKey, error) {
+	keys, err := uc.apiKeyRepo.ListByPartnerID(ctx, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	if unusedFor <= 0 {
+		return keys, nil
+	}
+
+	cutoff := time.Now().Add(-unusedFor)
+	stale := make([]*entities.APIKey, 0, len(keys))
+	for _, key := range keys {
+		if key.IsUnusedSince(cutoff) {
+			stale = append(stale, key)
+		}
+	}
+
+	return stale, nil
+}
+
+// GetAPIKeyUseCase retrieves one of a partner's API keys
+type GetAPIKeyUseCase struct {
+	apiKeyRepo ports.APIKeyRepository
+}
+
+// NewGetAPIKeyUseCase creates a new instance
+func NewGetAPIKeyUseCase(apiKeyRepo ports.APIKeyRepository) *GetAPIKeyUseCase {
+	return &GetAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute retrieves the key, revoked or not, if the partner owns it
+func (uc *GetAPIKeyUseCase) Execute(ctx context.Context, partnerID, keyID uuid.UUID) (*entities.APIKey, error) {
+	key, err := uc.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.PartnerID != partnerID {
+		return nil, errors.ErrAPIKeyNotFound
+	}
+
+	return key, nil
+}
+
+// RevokeAPIKeyInput represents input for revoking an API key
+type RevokeAPIKeyInput struct {
+	KeyID     uuid.UUID
+	PartnerID uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// RevokeAPIKeyUseCase permanently disables an API key
+type RevokeAPIKeyUseCase struct {
+	apiKeyRepo  ports.APIKeyRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewRevokeAPIKeyUseCase creates a new instance
+func NewRevokeAPIKeyUseCase(apiKeyRepo ports.APIKeyRepository, auditLogger ports.AuditLogger) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{
+		apiKeyRepo:  apiKeyRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute revokes a key owned by the partner
+func (uc *RevokeAPIKeyUseCase) Execute(ctx context.Context, input RevokeAPIKeyInput) (*entities.APIKey, error) {
+	// Step 1: Retrieve key
+	key, err := uc.apiKeyRepo.GetByID(ctx, input.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Authorization - verify partner owns this key
+	if key.PartnerID != input.PartnerID {
+		return nil, errors.ErrAPIKeyNotFound
+	}
+
+	// Step 3: Revoke
+	if err := key.Revoke(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "api_key_revoked",
+			ResourceType: "api_key",
+			ResourceID:   key.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"label": key.Label,
+			},
+		})
+	}
+
+	return key, nil
+}
@@ -0,0 +1,75 @@
+package apikey
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// keyUsage is the latest recorded use of a key
+type keyUsage struct {
+	usedAt    time.Time
+	ipAddress string
+}
+
+// UsageTracker records API key usage off the request path.
+// Uses are kept in memory, coalesced per key and written in batches,
+// so authenticating never waits on a database write.
+type UsageTracker struct {
+	apiKeyRepo ports.APIKeyRepository
+
+	// flushInterval is how often pending usage is written
+	flushInterval time.Duration
+
+	pending map[uuid.UUID]keyUsage
+	mu      sync.Mutex
+}
+
+// NewUsageTracker creates a new API key usage tracker
+func NewUsageTracker(apiKeyRepo ports.APIKeyRepository, flushInterval time.Duration) *UsageTracker {
+	return &UsageTracker{
+		apiKeyRepo:    apiKeyRepo,
+		flushInterval: flushInterval,
+		pending:       make(map[uuid.UUID]keyUsage),
+	}
+}
+
+// Record notes that a key was used just now from ipAddress
+func (t *UsageTracker) Record(keyID uuid.UUID, ipAddress string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending[keyID] = keyUsage{usedAt: time.Now(), ipAddress: ipAddress}
+}
+
+// Run flushes pending usage every interval until ctx is done, then flushes once more
+func (t *UsageTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.Flush(ctx)
+		case <-ctx.Done():
+			t.Flush(context.Background())
+			return
+		}
+	}
+}
+
+// Flush writes pending usage. Failed writes are dropped; the next use records again.
+func (t *UsageTracker) Flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[uuid.UUID]keyUsage)
+	t.mu.Unlock()
+
+	for keyID, usage := range pending {
+		_ = t.apiKeyRepo.RecordUsage(ctx, keyID, usage.usedAt, usage.ipAddress)
+	}
+}
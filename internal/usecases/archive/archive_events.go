@@ -0,0 +1,106 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// archiveBatchSize is how many entries or events are moved at a time
+const archiveBatchSize = 500
+
+// ArchiveResult counts what one run moved to the archive
+type ArchiveResult struct {
+	AuditLogs    int
+	OutboxEvents int
+}
+
+// ArchiveEventsUseCase moves audit entries and published outbox events older
+// than the retention period from the database to the archive
+type ArchiveEventsUseCase struct {
+	auditLogRepo ports.AuditLogRepository
+	outboxRepo   ports.OutboxRepository
+	archive      ports.EventArchive
+	retention    time.Duration
+}
+
+// NewArchiveEventsUseCase creates a new instance
+func NewArchiveEventsUseCase(
+	auditLogRepo ports.AuditLogRepository,
+	outboxRepo ports.OutboxRepository,
+	archive ports.EventArchive,
+	retention time.Duration,
+) *ArchiveEventsUseCase {
+	return &ArchiveEventsUseCase{
+		auditLogRepo: auditLogRepo,
+		outboxRepo:   outboxRepo,
+		archive:      archive,
+		retention:    retention,
+	}
+}
+
+// Execute moves everything older than the retention period, a batch at a
+// time. Each batch is deleted only once it is archived; a run that fails in
+// between archives the batch again next time.
+func (uc *ArchiveEventsUseCase) Execute(ctx context.Context) (*ArchiveResult, error) {
+	before := time.Now().Add(-uc.retention)
+	result := &ArchiveResult{}
+
+	for {
+		entries, err := uc.auditLogRepo.ListArchivable(ctx, before, archiveBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list audit logs to archive: %w", err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+		if err := uc.archive.ArchiveAuditLogs(ctx, entries); err != nil {
+			return result, err
+		}
+
+		ids := make([]int64, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.ID
+		}
+		if err := uc.auditLogRepo.Delete(ctx, ids); err != nil {
+			return result, fmt.Errorf("failed to delete archived audit logs: %w", err)
+		}
+		result.AuditLogs += len(entries)
+
+		if len(entries) < archiveBatchSize {
+			break
+		}
+	}
+
+	for {
+		events, err := uc.outboxRepo.ListPublishedBefore(ctx, before, archiveBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list outbox events to archive: %w", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+		if err := uc.archive.ArchiveOutboxEvents(ctx, events); err != nil {
+			return result, err
+		}
+
+		ids := make([]uuid.UUID, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		if err := uc.outboxRepo.Delete(ctx, ids); err != nil {
+			return result, fmt.Errorf("failed to delete archived outbox events: %w", err)
+		}
+		result.OutboxEvents += len(events)
+
+		if len(events) < archiveBatchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// AsyncLogger logs audit actions off the request path. Actions wait in a
+// bounded buffer and are written in batches, when a batch fills up or the
+// flush interval passes, so logging never waits on the database unless the
+// buffer is full.
+type AsyncLogger struct {
+	next    ports.AuditLogger
+	actions chan ports.AuditAction
+
+	batchSize     int
+	flushInterval time.Duration
+
+	// onError is told about actions that could not be written
+	onError func(action ports.AuditAction, err error)
+}
+
+// NewAsyncLogger creates a logger writing to next, buffering up to
+// bufferSize actions
+func NewAsyncLogger(
+	next ports.AuditLogger,
+	bufferSize, batchSize int,
+	flushInterval time.Duration,
+	onError func(action ports.AuditAction, err error),
+) *AsyncLogger {
+	return &AsyncLogger{
+		next:          next,
+		actions:       make(chan ports.AuditAction, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		onError:       onError,
+	}
+}
+
+// LogAction queues the action. With the buffer full it is written inline
+// instead, so a slow database slows requests down rather than losing actions.
+func (l *AsyncLogger) LogAction(ctx context.Context, action ports.AuditAction) error {
+	if action.OccurredAt.IsZero() {
+		action.OccurredAt = time.Now()
+	}
+	select {
+	case l.actions <- action:
+		return nil
+	default:
+		return l.next.LogAction(ctx, action)
+	}
+}
+
+// Run writes queued actions until ctx is done, then writes what is left
+func (l *AsyncLogger) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ports.AuditAction, 0, l.batchSize)
+	for {
+		select {
+		case action := <-l.actions:
+			batch = append(batch, action)
+			if len(batch) >= l.batchSize {
+				batch = l.write(batch)
+			}
+		case <-ticker.C:
+			batch = l.write(batch)
+		case <-ctx.Done():
+			l.drain(batch)
+			return
+		}
+	}
+}
+
+// drain writes batch and every action still queued
+func (l *AsyncLogger) drain(batch []ports.AuditAction) {
+	for {
+		select {
+		case action := <-l.actions:
+			batch = append(batch, action)
+			if len(batch) >= l.batchSize {
+				batch = l.write(batch)
+			}
+		default:
+			l.write(batch)
+			return
+		}
+	}
+}
+
+// write logs batch in order and returns it emptied for reuse. Writes do not
+// use the requests' contexts, which are done long before.
+func (l *AsyncLogger) write(batch []ports.AuditAction) []ports.AuditAction {
+	for _, action := range batch {
+		if err := l.next.LogAction(context.Background(), action); err != nil && l.onError != nil {
+			l.onError(action, err)
+		}
+	}
+	return batch[:0]
+}
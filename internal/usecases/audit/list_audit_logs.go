@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ListAuditLogsUseCase lists a partner's own audit trail
+type ListAuditLogsUseCase struct {
+	auditLogRepo ports.AuditLogRepository
+}
+
+// NewListAuditLogsUseCase creates a new instance
+func NewListAuditLogsUseCase(auditLogRepo ports.AuditLogRepository) *ListAuditLogsUseCase {
+	return &ListAuditLogsUseCase{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// Execute returns a page of the partner's audit entries, newest first, and
+// the total number of matches
+func (uc *ListAuditLogsUseCase) Execute(ctx context.Context, partnerID uuid.UUID, query ports.AuditLogQuery) ([]*ports.AuditLogEntry, int64, error) {
+	// Enforce partner isolation
+	query.PartnerID = &partnerID
+
+	return listAuditLogs(ctx, uc.auditLogRepo, query)
+}
+
+// ListAllAuditLogsUseCase lists audit entries across partners for the back
+// office, including the ones logged without a partner
+type ListAllAuditLogsUseCase struct {
+	auditLogRepo ports.AuditLogRepository
+}
+
+// NewListAllAuditLogsUseCase creates a new instance
+func NewListAllAuditLogsUseCase(auditLogRepo ports.AuditLogRepository) *ListAllAuditLogsUseCase {
+	return &ListAllAuditLogsUseCase{
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// Execute returns a page of audit entries, newest first, and the total
+// number of matches; query.PartnerID optionally narrows them to one partner
+func (uc *ListAllAuditLogsUseCase) Execute(ctx context.Context, query ports.AuditLogQuery) ([]*ports.AuditLogEntry, int64, error) {
+	return listAuditLogs(ctx, uc.auditLogRepo, query)
+}
+
+// listAuditLogs applies the default and maximum page size and reads from the
+// replica when there is one
+func listAuditLogs(ctx context.Context, auditLogRepo ports.AuditLogRepository, query ports.AuditLogQuery) ([]*ports.AuditLogEntry, int64, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 100 {
+		query.Limit = 100 // Max 100 per page
+	}
+
+	entries, total, err := auditLogRepo.List(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RequestLogger stamps audit actions with the ID of the API request they
+// were taken in, unless the use case set one, so an entry can be matched to
+// the request logs and to what the partner saw
+type RequestLogger struct {
+	next ports.AuditLogger
+}
+
+// NewRequestLogger creates a logger stamping actions before passing them to
+// next
+func NewRequestLogger(next ports.AuditLogger) *RequestLogger {
+	return &RequestLogger{next: next}
+}
+
+// LogAction stamps the action with the request ID in ctx and logs it
+func (l *RequestLogger) LogAction(ctx context.Context, action ports.AuditAction) error {
+	if action.RequestID == uuid.Nil {
+		action.RequestID = ports.RequestID(ctx)
+	}
+	return l.next.LogAction(ctx, action)
+}
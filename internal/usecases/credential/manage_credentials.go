@@ -0,0 +1,178 @@
+// Package credential contains use cases for partners' own payment provider accounts
+package credential
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SaveProviderCredentialInput represents input for connecting a partner's provider account
+type SaveProviderCredentialInput struct {
+	PartnerID uuid.UUID
+	Provider  string
+	AccountID string
+	SecretKey string
+	IPAddress string
+	UserAgent string
+}
+
+// SaveProviderCredentialUseCase stores or replaces a partner's provider credentials
+type SaveProviderCredentialUseCase struct {
+	credentialRepo ports.ProviderCredentialRepository
+	auditLogger    ports.AuditLogger
+}
+
+// NewSaveProviderCredentialUseCase creates a new instance
+func NewSaveProviderCredentialUseCase(
+	credentialRepo ports.ProviderCredentialRepository,
+	auditLogger ports.AuditLogger,
+) *SaveProviderCredentialUseCase {
+	return &SaveProviderCredentialUseCase{
+		credentialRepo: credentialRepo,
+		auditLogger:    auditLogger,
+	}
+}
+
+// Execute creates the credential, or replaces the keys of an existing one
+func (uc *SaveProviderCredentialUseCase) Execute(ctx context.Context, input SaveProviderCredentialInput) (*entities.ProviderCredential, error) {
+	// Step 1: Validate provider
+	provider, err := valueobjects.NewPaymentProvider(input.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Replace existing credential in place, or create a new one
+	credential, err := uc.credentialRepo.GetByPartnerAndProvider(ctx, input.PartnerID, provider)
+	action := "provider_credential_updated"
+	switch {
+	case err == nil:
+		if err := credential.Replace(input.AccountID, input.SecretKey); err != nil {
+			return nil, err
+		}
+	case err == errors.ErrProviderCredentialNotFound:
+		credential, err = entities.NewProviderCredential(input.PartnerID, provider, input.AccountID, input.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+		action = "provider_credential_created"
+	default:
+		return nil, fmt.Errorf("failed to get provider credential: %w", err)
+	}
+
+	// Step 3: Persist (the secret is encrypted by the repository)
+	if err := uc.credentialRepo.Save(ctx, credential); err != nil {
+		return nil, fmt.Errorf("failed to save provider credential: %w", err)
+	}
+
+	// Step 4: Log audit event (never the secret)
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       action,
+			ResourceType: "provider_credential",
+			ResourceID:   credential.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"provider":   credential.Provider,
+				"account_id": credential.AccountID,
+			},
+		})
+	}
+
+	return credential, nil
+}
+
+// ListProviderCredentialsUseCase lists a partner's provider credentials
+type ListProviderCredentialsUseCase struct {
+	credentialRepo ports.ProviderCredentialRepository
+}
+
+// NewListProviderCredentialsUseCase creates a new instance
+func NewListProviderCredentialsUseCase(credentialRepo ports.ProviderCredentialRepository) *ListProviderCredentialsUseCase {
+	return &ListProviderCredentialsUseCase{
+		credentialRepo: credentialRepo,
+	}
+}
+
+// Execute lists provider credentials for the partner
+func (uc *ListProviderCredentialsUseCase) Execute(ctx context.Context, partnerID uuid.UUID) ([]*entities.ProviderCredential, error) {
+	credentials, err := uc.credentialRepo.ListByPartnerID(ctx, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider credentials: %w", err)
+	}
+
+	return credentials, nil
+}
+
+// DeleteProviderCredentialInput represents input for disconnecting a provider account
+type DeleteProviderCredentialInput struct {
+	PartnerID uuid.UUID
+	Provider  string
+	IPAddress string
+	UserAgent string
+}
+
+// DeleteProviderCredentialUseCase removes a partner's provider credentials.
+// New payments go back to the platform account; refunds of payments taken on
+// the removed account fail until it is connected again.
+type DeleteProviderCredentialUseCase struct {
+	credentialRepo ports.ProviderCredentialRepository
+	auditLogger    ports.AuditLogger
+}
+
+// NewDeleteProviderCredentialUseCase creates a new instance
+func NewDeleteProviderCredentialUseCase(
+	credentialRepo ports.ProviderCredentialRepository,
+	auditLogger ports.AuditLogger,
+) *DeleteProviderCredentialUseCase {
+	return &DeleteProviderCredentialUseCase{
+		credentialRepo: credentialRepo,
+		auditLogger:    auditLogger,
+	}
+}
+
+// Execute deletes the partner's credential for the provider
+func (uc *DeleteProviderCredentialUseCase) Execute(ctx context.Context, input DeleteProviderCredentialInput) error {
+	// Step 1: Validate provider
+	provider, err := valueobjects.NewPaymentProvider(input.Provider)
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Retrieve credential (for the audit trail)
+	credential, err := uc.credentialRepo.GetByPartnerAndProvider(ctx, input.PartnerID, provider)
+	if err != nil {
+		return err
+	}
+
+	// Step 3: Delete
+	if err := uc.credentialRepo.Delete(ctx, input.PartnerID, provider); err != nil {
+		return err
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "provider_credential_deleted",
+			ResourceType: "provider_credential",
+			ResourceID:   credential.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"provider":   credential.Provider,
+				"account_id": credential.AccountID,
+			},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package export runs the exports partners request through the API: a job
+// is queued, a worker writes the file to an object store, and the partner
+// downloads it through a signed URL
+package export
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// CreateExportUseCase queues an export for the worker
+type CreateExportUseCase struct {
+	jobRepo ports.ExportJobRepository
+}
+
+// NewCreateExportUseCase creates a new instance
+func NewCreateExportUseCase(jobRepo ports.ExportJobRepository) *CreateExportUseCase {
+	return &CreateExportUseCase{
+		jobRepo: jobRepo,
+	}
+}
+
+// CreateExportInput is the export a partner asks for
+type CreateExportInput struct {
+	PartnerID uuid.UUID
+	Livemode  bool
+	Resource  entities.ExportResource
+	Format    entities.ExportFormat
+	Filters   entities.ExportFilters
+	Layout    entities.ExportLayout
+}
+
+// Execute queues the export
+func (uc *CreateExportUseCase) Execute(ctx context.Context, input CreateExportInput) (*entities.ExportJob, error) {
+	if input.Resource == entities.ExportResourceRefunds && len(input.Filters.Statuses) > 1 {
+		return nil, errors.NewValidationError("status", "refunds are filtered by one status")
+	}
+
+	job, err := entities.NewExportJob(input.PartnerID, input.Livemode, input.Resource, input.Format, input.Filters, input.Layout)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobRepo.Add(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to queue export: %w", err)
+	}
+	return job, nil
+}
+
+// DownloadSigner signs the download URLs of exports, so they can be handed
+// to a browser or a script without an API key. A signature covers the
+// export and when it expires.
+type DownloadSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewDownloadSigner creates a signer of URLs valid for ttl
+func NewDownloadSigner(key string, ttl time.Duration) *DownloadSigner {
+	return &DownloadSigner{key: []byte(key), ttl: ttl}
+}
+
+// Sign returns when a download of the export signed now expires, and its
+// signature
+func (s *DownloadSigner) Sign(exportID uuid.UUID, now time.Time) (time.Time, string) {
+	expires := now.Add(s.ttl).Truncate(time.Second)
+	return expires, s.signature(exportID, expires.Unix())
+}
+
+// Verify checks a download's signature, then that it has not expired
+func (s *DownloadSigner) Verify(exportID uuid.UUID, expires int64, signature string, now time.Time) error {
+	if !hmac.Equal([]byte(signature), []byte(s.signature(exportID, expires))) {
+		return errors.ErrInvalidSignature
+	}
+	if now.Unix() >= expires {
+		return errors.ErrExportLinkExpired
+	}
+	return nil
+}
+
+// signature is the hex HMAC-SHA256 of "<export ID>.<expiry>"
+func (s *DownloadSigner) signature(exportID uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(exportID.String() + "." + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetExportUseCase reports an export's progress
+type GetExportUseCase struct {
+	jobRepo ports.ExportJobRepository
+	signer  *DownloadSigner
+}
+
+// NewGetExportUseCase creates a new instance
+func NewGetExportUseCase(jobRepo ports.ExportJobRepository, signer *DownloadSigner) *GetExportUseCase {
+	return &GetExportUseCase{
+		jobRepo: jobRepo,
+		signer:  signer,
+	}
+}
+
+// ExportStatus is an export and, once it completed, a freshly signed
+// download
+type ExportStatus struct {
+	Job *entities.ExportJob
+
+	// DownloadExpiresAt and DownloadSignature are set for completed exports
+	DownloadExpiresAt *time.Time
+	DownloadSignature string
+}
+
+// Execute returns partnerID's export in livemode
+func (uc *GetExportUseCase) Execute(ctx context.Context, partnerID uuid.UUID, livemode bool, exportID uuid.UUID) (*ExportStatus, error) {
+	job, err := uc.jobRepo.GetByID(ports.ReadOnly(ctx), exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enforce partner and mode isolation
+	if job.PartnerID != partnerID || job.Livemode != livemode {
+		return nil, errors.ErrExportNotFound
+	}
+
+	status := &ExportStatus{Job: job}
+	if job.IsCompleted() {
+		expires, signature := uc.signer.Sign(job.ID, time.Now())
+		status.DownloadExpiresAt = &expires
+		status.DownloadSignature = signature
+	}
+	return status, nil
+}
+
+// DownloadExportUseCase reads a completed export's file for a signed
+// download
+type DownloadExportUseCase struct {
+	jobRepo ports.ExportJobRepository
+	store   ports.ObjectStore
+	signer  *DownloadSigner
+}
+
+// NewDownloadExportUseCase creates a new instance
+func NewDownloadExportUseCase(jobRepo ports.ExportJobRepository, store ports.ObjectStore, signer *DownloadSigner) *DownloadExportUseCase {
+	return &DownloadExportUseCase{
+		jobRepo: jobRepo,
+		store:   store,
+		signer:  signer,
+	}
+}
+
+// Execute returns the export and its file, once the signature checks out
+func (uc *DownloadExportUseCase) Execute(ctx context.Context, exportID uuid.UUID, expires int64, signature string) (*entities.ExportJob, []byte, error) {
+	if err := uc.signer.Verify(exportID, expires, signature, time.Now()); err != nil {
+		return nil, nil, err
+	}
+
+	job, err := uc.jobRepo.GetByID(ports.ReadOnly(ctx), exportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !job.IsCompleted() {
+		return nil, nil, errors.ErrExportNotReady
+	}
+
+	data, err := uc.store.Get(ctx, job.ObjectKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	return job, data, nil
+}
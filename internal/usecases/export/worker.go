@@ -0,0 +1,320 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// refundPageSize is how many refunds are read at a time; refunds have no
+// stream
+const refundPageSize = 500
+
+// Worker produces the queued exports. A file is written in full before it
+// is stored, and the job completes only once it is, so an attempt that
+// fails leaves nothing behind but the reason and is run again after a
+// backoff.
+//
+// Several API instances can share the queue: a pass claims its jobs until
+// claimTimeout from now, so the others skip them.
+type Worker struct {
+	jobRepo ports.ExportJobRepository
+	writer  *Writer
+	store   ports.ObjectStore
+
+	batchSize    int
+	claimTimeout time.Duration
+}
+
+// NewWorker creates a worker producing up to batchSize exports a pass, one
+// at a time. claimTimeout must be longer than a pass takes.
+func NewWorker(
+	jobRepo ports.ExportJobRepository,
+	partnerRepo ports.PartnerRepository,
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	store ports.ObjectStore,
+	encoder ports.ExportEncoder,
+	accounting ports.AccountingEncoder,
+	batchSize int,
+	claimTimeout time.Duration,
+) *Worker {
+	return &Worker{
+		jobRepo:      jobRepo,
+		writer:       NewWriter(partnerRepo, transactionRepo, refundRepo, encoder, accounting),
+		store:        store,
+		batchSize:    batchSize,
+		claimTimeout: claimTimeout,
+	}
+}
+
+// RunDue produces the exports that are due and returns how many it ran.
+// Only errors saving a job are returned.
+func (w *Worker) RunDue(ctx context.Context) (int, error) {
+	jobs, err := w.jobRepo.ClaimDue(ctx, w.batchSize, time.Now().Add(w.claimTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim export jobs: %w", err)
+	}
+
+	for i, job := range jobs {
+		key, rows, err := w.produce(ctx, job)
+		if err != nil {
+			job.MarkFailed(err.Error())
+		} else {
+			job.MarkCompleted(key, rows)
+		}
+		if err := w.jobRepo.Update(ctx, job); err != nil {
+			return i, fmt.Errorf("failed to save export job %s: %w", job.ID, err)
+		}
+	}
+	return len(jobs), nil
+}
+
+// produce writes job's file to the store and returns its key and how many
+// rows it holds
+func (w *Worker) produce(ctx context.Context, job *entities.ExportJob) (string, int64, error) {
+	var buf bytes.Buffer
+	rows, err := w.writer.Write(ctx, &buf, job)
+	if err != nil {
+		return "", 0, err
+	}
+
+	key := fmt.Sprintf("exports/%s/%s.%s", job.PartnerID, job.ID, job.Format.Extension())
+	if err := w.store.Put(ctx, key, buf.Bytes()); err != nil {
+		return "", 0, fmt.Errorf("failed to store export: %w", err)
+	}
+	return key, rows, nil
+}
+
+// paidStatuses are the statuses of payments that were paid, the ones
+// accounting files post
+var paidStatuses = map[string]bool{
+	string(entities.StatusCompleted):         true,
+	string(entities.StatusPartiallyRefunded): true,
+	string(entities.StatusRefunded):          true,
+}
+
+// Writer writes the files of exports, and of the scheduled reports that
+// list transactions or refunds as exports do; in the accounting formats it
+// also writes the fees of statements
+type Writer struct {
+	partnerRepo     ports.PartnerRepository
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	encoder         ports.ExportEncoder
+	accounting      ports.AccountingEncoder
+}
+
+// NewWriter creates a writer of files encoded by encoder, or by accounting
+// in the accounting formats
+func NewWriter(
+	partnerRepo ports.PartnerRepository,
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	encoder ports.ExportEncoder,
+	accounting ports.AccountingEncoder,
+) *Writer {
+	return &Writer{
+		partnerRepo:     partnerRepo,
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		encoder:         encoder,
+		accounting:      accounting,
+	}
+}
+
+// Write writes the file of job to out and returns how many rows it holds
+func (w *Writer) Write(ctx context.Context, out io.Writer, job *entities.ExportJob) (int64, error) {
+	if job.Format.IsAccounting() {
+		return w.writeAccounting(ctx, out, job)
+	}
+
+	file, err := w.encoder.NewFile(out, job.Resource, job.Format, job.Layout)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := w.writeRows(ctx, job, file)
+	if err != nil {
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write export: %w", err)
+	}
+	return rows, nil
+}
+
+// WriteStatement writes the fees statement charges to out in format, one
+// of the accounting formats, posted to the partner's accounts
+func (w *Writer) WriteStatement(ctx context.Context, out io.Writer, statement *entities.Statement, format entities.ExportFormat) error {
+	file, err := w.accountingFile(ctx, out, statement.PartnerID, format, entities.ExportLayout{})
+	if err != nil {
+		return err
+	}
+	if err := file.WriteStatementFees(statement); err != nil {
+		return fmt.Errorf("failed to write statement: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write statement: %w", err)
+	}
+	return nil
+}
+
+// writeAccounting writes job's file in an accounting format: the payments
+// that were paid and the refunds completed, since only they moved money
+func (w *Writer) writeAccounting(ctx context.Context, out io.Writer, job *entities.ExportJob) (int64, error) {
+	file, err := w.accountingFile(ctx, out, job.PartnerID, job.Format, job.Layout)
+	if err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	if booked, ok := bookedJob(job); ok {
+		if rows, err = w.writeRows(ctx, booked, file); err != nil {
+			return 0, err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write export: %w", err)
+	}
+	return rows, nil
+}
+
+// accountingFile starts a file in format posting to the accounts of
+// partnerID, with times in the zone of layout
+func (w *Writer) accountingFile(ctx context.Context, out io.Writer, partnerID uuid.UUID, format entities.ExportFormat, layout entities.ExportLayout) (ports.AccountingFile, error) {
+	partner, err := w.partnerRepo.GetByID(ports.ReadOnly(ctx), partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+	location, err := layout.Location()
+	if err != nil {
+		return nil, err
+	}
+	return w.accounting.NewFile(out, format, partner.Accounts(), location)
+}
+
+// writeRows writes the transactions or refunds of job to file
+func (w *Writer) writeRows(ctx context.Context, job *entities.ExportJob, file ports.ExportFile) (int64, error) {
+	if job.Resource == entities.ExportResourceRefunds {
+		return w.writeRefunds(ctx, job, file)
+	}
+	return w.writeTransactions(ctx, job, file)
+}
+
+// writeTransactions writes the transactions matching job's filters, newest
+// first, as the list API returns them
+func (w *Writer) writeTransactions(ctx context.Context, job *entities.ExportJob, file ports.ExportFile) (int64, error) {
+	query, err := transactionQuery(job)
+	if err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err = w.transactionRepo.Stream(ports.ReadOnly(ctx), query, func(txn *entities.Transaction) error {
+		rows++
+		return file.WriteTransaction(txn)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to export transactions: %w", err)
+	}
+	return rows, nil
+}
+
+// writeRefunds writes the refunds matching job's filters, newest first
+func (w *Writer) writeRefunds(ctx context.Context, job *entities.ExportJob, file ports.ExportFile) (int64, error) {
+	query := ports.RefundQuery{
+		PartnerID:     job.PartnerID,
+		Livemode:      job.Livemode,
+		TransactionID: job.Filters.TransactionID,
+		CreatedFrom:   job.Filters.CreatedFrom,
+		CreatedTo:     job.Filters.CreatedTo,
+		Limit:         refundPageSize,
+	}
+	if len(job.Filters.Statuses) > 0 {
+		status := entities.RefundStatus(job.Filters.Statuses[0])
+		query.Status = &status
+	}
+
+	var rows int64
+	for {
+		refunds, err := w.refundRepo.List(ports.ReadOnly(ctx), query)
+		if err != nil {
+			return 0, fmt.Errorf("failed to export refunds: %w", err)
+		}
+		for _, refund := range refunds {
+			if err := file.WriteRefund(refund); err != nil {
+				return 0, fmt.Errorf("failed to write export: %w", err)
+			}
+		}
+		rows += int64(len(refunds))
+		if len(refunds) < refundPageSize {
+			return rows, nil
+		}
+		query.Offset += refundPageSize
+	}
+}
+
+// bookedJob narrows job's status filter to the paid payments or completed
+// refunds it matches, reporting false if it matches none
+func bookedJob(job *entities.ExportJob) (*entities.ExportJob, bool) {
+	booked := *job
+	booked.Filters.Statuses = nil
+	if job.Resource == entities.ExportResourceRefunds {
+		booked.Filters.Statuses = []string{string(entities.RefundStatusCompleted)}
+		for _, status := range job.Filters.Statuses {
+			if status != string(entities.RefundStatusCompleted) {
+				return nil, false
+			}
+		}
+		return &booked, true
+	}
+
+	statuses := job.Filters.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{string(entities.StatusCompleted), string(entities.StatusPartiallyRefunded), string(entities.StatusRefunded)}
+	}
+	for _, status := range statuses {
+		if paidStatuses[status] {
+			booked.Filters.Statuses = append(booked.Filters.Statuses, status)
+		}
+	}
+	return &booked, len(booked.Filters.Statuses) > 0
+}
+
+// transactionQuery is the query of job's filters, limited to its partner
+// and mode
+func transactionQuery(job *entities.ExportJob) (ports.TransactionQuery, error) {
+	filters := job.Filters
+	query := ports.TransactionQuery{
+		PartnerID:   &job.PartnerID,
+		Livemode:    &job.Livemode,
+		MinAmount:   filters.MinAmount,
+		MaxAmount:   filters.MaxAmount,
+		CreatedFrom: filters.CreatedFrom,
+		CreatedTo:   filters.CreatedTo,
+		Metadata:    filters.Metadata,
+	}
+	for _, status := range filters.Statuses {
+		query.Statuses = append(query.Statuses, entities.TransactionStatus(status))
+	}
+	if filters.Currency != "" {
+		currency, err := valueobjects.NewCurrency(filters.Currency)
+		if err != nil {
+			return query, err
+		}
+		query.Currency = &currency
+	}
+	return query, nil
+}
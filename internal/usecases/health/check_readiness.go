@@ -0,0 +1,78 @@
+// Package health contains the readiness check of the API's dependencies and
+// the summary of its service levels
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Readiness is the outcome of checking every dependency
+type Readiness struct {
+	// Status is the worst status of the components
+	Status     ports.HealthStatus
+	Components map[string]ports.ComponentHealth
+}
+
+// Ready reports whether the API can serve requests: no dependency is down
+func (r *Readiness) Ready() bool {
+	return r.Status != ports.HealthDown
+}
+
+// CheckReadinessUseCase checks the API's dependencies for load balancers and
+// orchestrators
+type CheckReadinessUseCase struct {
+	checks map[string]ports.HealthChecker
+	// timeout bounds the checks; each decides what timing out means for it
+	timeout time.Duration
+}
+
+// NewCheckReadinessUseCase creates a new instance for the named checks
+func NewCheckReadinessUseCase(checks map[string]ports.HealthChecker, timeout time.Duration) *CheckReadinessUseCase {
+	return &CheckReadinessUseCase{checks: checks, timeout: timeout}
+}
+
+// Execute runs every check concurrently
+func (uc *CheckReadinessUseCase) Execute(ctx context.Context) *Readiness {
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	readiness := &Readiness{
+		Status:     ports.HealthUp,
+		Components: make(map[string]ports.ComponentHealth, len(uc.checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range uc.checks {
+		wg.Add(1)
+		go func(name string, check ports.HealthChecker) {
+			defer wg.Done()
+			health := check.CheckHealth(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Components[name] = health
+			if severity(health.Status) > severity(readiness.Status) {
+				readiness.Status = health.Status
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return readiness
+}
+
+// severity orders statuses from up to down
+func severity(status ports.HealthStatus) int {
+	switch status {
+	case ports.HealthUp:
+		return 0
+	case ports.HealthDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,217 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SLOWindows are the rolling windows service levels are summarized over
+var SLOWindows = []time.Duration{time.Hour, 24 * time.Hour}
+
+// PaymentLevels are the service levels of payments sent to providers
+type PaymentLevels struct {
+	Succeeded int64
+	Failed    int64
+	// SuccessRate is the share of payments that succeeded; nil without
+	// payments
+	SuccessRate *float64
+	// P95Latency is estimated from latency buckets, interpolating within
+	// the bucket holding the 95th percentile; nil without payments
+	P95Latency *time.Duration
+
+	buckets []int64
+}
+
+// WebhookLevels are the service levels of webhook delivery
+type WebhookLevels struct {
+	Delivered int64
+	Failing   int64
+	// SuccessRate is the share of attempted webhooks that were delivered;
+	// nil without attempts
+	SuccessRate *float64
+}
+
+// ProviderLevels are the payment service levels of one provider
+type ProviderLevels struct {
+	Provider valueobjects.PaymentProvider
+	Payments PaymentLevels
+}
+
+// PartnerLevels are the service levels one partner got
+type PartnerLevels struct {
+	PartnerID uuid.UUID
+	Payments  PaymentLevels
+	Webhooks  WebhookLevels
+}
+
+// SLOWindow summarizes the service levels of one window
+type SLOWindow struct {
+	Window    time.Duration
+	Since     time.Time
+	Payments  PaymentLevels
+	Webhooks  WebhookLevels
+	Providers []ProviderLevels
+	Partners  []PartnerLevels
+}
+
+// GetSLOSummaryUseCase summarizes payment success, payment latency and
+// webhook delivery over rolling windows, from the stored transactions and
+// outbox events, for the status page
+type GetSLOSummaryUseCase struct {
+	transactionRepo ports.TransactionRepository
+	outboxRepo      ports.OutboxRepository
+}
+
+// NewGetSLOSummaryUseCase creates a new instance
+func NewGetSLOSummaryUseCase(transactionRepo ports.TransactionRepository, outboxRepo ports.OutboxRepository) *GetSLOSummaryUseCase {
+	return &GetSLOSummaryUseCase{
+		transactionRepo: transactionRepo,
+		outboxRepo:      outboxRepo,
+	}
+}
+
+// Execute summarizes each of SLOWindows up to now, for live or sandbox
+// payments. Webhooks are not split by mode.
+func (uc *GetSLOSummaryUseCase) Execute(ctx context.Context, livemode bool) ([]SLOWindow, error) {
+	ctx = ports.ReadOnly(ctx)
+	now := time.Now()
+
+	windows := make([]SLOWindow, 0, len(SLOWindows))
+	for _, window := range SLOWindows {
+		since := now.Add(-window)
+		processing, err := uc.transactionRepo.GetProcessingSummary(ctx, since, livemode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize payments: %w", err)
+		}
+		deliveries, err := uc.outboxRepo.GetDeliverySummary(ctx, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize webhooks: %w", err)
+		}
+		windows = append(windows, summarize(window, since, processing, deliveries))
+	}
+	return windows, nil
+}
+
+// summarize adds the counts up overall, per provider and per partner
+func summarize(window time.Duration, since time.Time, processing []ports.ProcessingSummary, deliveries []ports.DeliverySummary) SLOWindow {
+	summary := SLOWindow{Window: window, Since: since}
+	providers := make(map[valueobjects.PaymentProvider]*ProviderLevels)
+	partners := make(map[uuid.UUID]*PartnerLevels)
+	partner := func(id uuid.UUID) *PartnerLevels {
+		levels, ok := partners[id]
+		if !ok {
+			levels = &PartnerLevels{PartnerID: id}
+			partners[id] = levels
+		}
+		return levels
+	}
+
+	for _, s := range processing {
+		provider, ok := providers[s.Provider]
+		if !ok {
+			provider = &ProviderLevels{Provider: s.Provider}
+			providers[s.Provider] = provider
+		}
+		for _, levels := range []*PaymentLevels{&summary.Payments, &provider.Payments, &partner(s.PartnerID).Payments} {
+			levels.add(s)
+		}
+	}
+	for _, d := range deliveries {
+		for _, levels := range []*WebhookLevels{&summary.Webhooks, &partner(d.PartnerID).Webhooks} {
+			levels.Delivered += d.Delivered
+			levels.Failing += d.Failing
+		}
+	}
+
+	summary.Payments.finish()
+	summary.Webhooks.finish()
+	for _, provider := range providers {
+		provider.Payments.finish()
+		summary.Providers = append(summary.Providers, *provider)
+	}
+	for _, levels := range partners {
+		levels.Payments.finish()
+		levels.Webhooks.finish()
+		summary.Partners = append(summary.Partners, *levels)
+	}
+	sort.Slice(summary.Providers, func(i, j int) bool {
+		return summary.Providers[i].Provider < summary.Providers[j].Provider
+	})
+	sort.Slice(summary.Partners, func(i, j int) bool {
+		return summary.Partners[i].PartnerID.String() < summary.Partners[j].PartnerID.String()
+	})
+	return summary
+}
+
+// add counts the payments of s
+func (l *PaymentLevels) add(s ports.ProcessingSummary) {
+	if s.Succeeded {
+		l.Succeeded += s.Count
+	} else {
+		l.Failed += s.Count
+	}
+	if l.buckets == nil {
+		l.buckets = make([]int64, len(ports.ProcessingLatencyBounds)+1)
+	}
+	if s.LatencyBucket >= 0 && s.LatencyBucket < len(l.buckets) {
+		l.buckets[s.LatencyBucket] += s.Count
+	}
+}
+
+// finish works out the rate and latency once every payment is counted
+func (l *PaymentLevels) finish() {
+	total := l.Succeeded + l.Failed
+	if total == 0 {
+		return
+	}
+	rate := float64(l.Succeeded) / float64(total)
+	l.SuccessRate = &rate
+	p95 := quantile(0.95, l.buckets)
+	l.P95Latency = &p95
+}
+
+// finish works out the rate once every webhook is counted
+func (l *WebhookLevels) finish() {
+	if attempted := l.Delivered + l.Failing; attempted > 0 {
+		rate := float64(l.Delivered) / float64(attempted)
+		l.SuccessRate = &rate
+	}
+}
+
+// quantile estimates the q quantile of latencies counted in the buckets of
+// ports.ProcessingLatencyBounds, assuming latencies are spread evenly within
+// a bucket, as Prometheus' histogram_quantile does. Latencies beyond the
+// last bound are reported as that bound.
+func quantile(q float64, buckets []int64) time.Duration {
+	var total int64
+	for _, count := range buckets {
+		total += count
+	}
+	rank := q * float64(total)
+
+	bounds := ports.ProcessingLatencyBounds
+	var below int64
+	for i, count := range buckets {
+		if i == len(bounds) {
+			break
+		}
+		if float64(below+count) >= rank && count > 0 {
+			lower := time.Duration(0)
+			if i > 0 {
+				lower = bounds[i-1]
+			}
+			share := (rank - float64(below)) / float64(count)
+			return lower + time.Duration(math.Round(share*float64(bounds[i]-lower)))
+		}
+		below += count
+	}
+	return bounds[len(bounds)-1]
+}
@@ -0,0 +1,293 @@
+// Package notification emails and texts customers and partners about the
+// events of their payments
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// notificationKind is which email or text message an event calls for
+type notificationKind int
+
+const (
+	kindReceipt notificationKind = iota
+	kindRefund
+	kindWebhookAlert
+	kindConfirmationSMS
+)
+
+// notification is an email or text message waiting to be sent
+type notification struct {
+	kind  notificationKind
+	event *entities.OutboxEvent
+}
+
+// eventData are the fields of payment and refund event payloads the
+// notifications use
+type eventData struct {
+	TransactionID string `json:"transaction_id"`
+	Amount        string `json:"amount"`
+	Currency      string `json:"currency"`
+	Livemode      bool   `json:"livemode"`
+}
+
+// Notifier implements ports.OutboxNotifier by emailing:
+//   - customers a receipt when their payment completed
+//   - customers a confirmation when their refund completed
+//   - partners an alert when the relay stopped retrying one of their webhooks
+//
+// and by texting customers a confirmation when their payment completed.
+//
+// Each notification is sent only if the partner has its feature flag on,
+// and customers only hear about live payments. Notifications are sent off
+// the relay's path, from a bounded buffer; when it is full, or sending
+// fails, the notification is dropped and reported, since retrying would
+// hold up deliveries.
+type Notifier struct {
+	partnerRepo     ports.PartnerRepository
+	transactionRepo ports.TransactionRepository
+	email           ports.EmailSender
+	texts           *Texts
+	queue           chan notification
+
+	// alertInterval is the least time between two webhook alerts to one
+	// partner, so an endpoint that is down does not flood its inbox
+	alertInterval time.Duration
+	mu            sync.Mutex
+	lastAlert     map[uuid.UUID]time.Time
+
+	// onError is told about notifications that were not sent
+	onError func(err error)
+}
+
+// NewNotifier creates a notifier sending emails with email and text
+// messages with texts, either of which may be nil, buffering up to
+// bufferSize notifications
+func NewNotifier(
+	partnerRepo ports.PartnerRepository,
+	transactionRepo ports.TransactionRepository,
+	email ports.EmailSender,
+	texts *Texts,
+	bufferSize int,
+	alertInterval time.Duration,
+	onError func(err error),
+) *Notifier {
+	return &Notifier{
+		partnerRepo:     partnerRepo,
+		transactionRepo: transactionRepo,
+		email:           email,
+		texts:           texts,
+		queue:           make(chan notification, bufferSize),
+		alertInterval:   alertInterval,
+		lastAlert:       make(map[uuid.UUID]time.Time),
+		onError:         onError,
+	}
+}
+
+// Published queues the customer notifications event calls for, if any
+func (n *Notifier) Published(ctx context.Context, event *entities.OutboxEvent) {
+	switch event.EventType {
+	case entities.EventPaymentCompleted:
+		if n.email != nil {
+			n.enqueue(notification{kind: kindReceipt, event: event})
+		}
+		if n.texts != nil {
+			n.enqueue(notification{kind: kindConfirmationSMS, event: event})
+		}
+	case entities.EventRefundCompleted:
+		if n.email != nil {
+			n.enqueue(notification{kind: kindRefund, event: event})
+		}
+	}
+}
+
+// Exhausted queues an alert to the partner whose webhook endpoint did not
+// accept event
+func (n *Notifier) Exhausted(ctx context.Context, event *entities.OutboxEvent) {
+	if n.email != nil && entities.IsWebhookEvent(event.EventType) {
+		n.enqueue(notification{kind: kindWebhookAlert, event: event})
+	}
+}
+
+// enqueue queues a notification without waiting
+func (n *Notifier) enqueue(item notification) {
+	select {
+	case n.queue <- item:
+	default:
+		n.onError(fmt.Errorf("notification buffer full, dropped the notification for event %s", item.event.ID))
+	}
+}
+
+// Run sends queued notifications until ctx is done, then sends what is
+// left
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case item := <-n.queue:
+			n.send(ctx, item)
+		case <-ctx.Done():
+			for {
+				select {
+				case item := <-n.queue:
+					n.send(context.WithoutCancel(ctx), item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send renders and sends the notification of item, reporting failures
+func (n *Notifier) send(ctx context.Context, item notification) {
+	var err error
+	if item.kind == kindConfirmationSMS {
+		err = n.text(ctx, item)
+	} else {
+		var email ports.Email
+		var ok bool
+		email, ok, err = n.render(ctx, item)
+		if err == nil && ok {
+			err = n.email.SendEmail(ctx, email)
+		}
+	}
+	if err != nil {
+		n.onError(fmt.Errorf("failed to notify about event %s: %w", item.event.ID, err))
+	}
+}
+
+// render returns the email of item, or false when none is to be sent
+func (n *Notifier) render(ctx context.Context, item notification) (ports.Email, bool, error) {
+	if item.kind == kindWebhookAlert {
+		partner, err := n.partnerRepo.GetByID(ctx, item.event.PartnerID)
+		if err == errors.ErrPartnerNotFound {
+			return ports.Email{}, false, nil
+		}
+		if err != nil {
+			return ports.Email{}, false, fmt.Errorf("failed to get partner: %w", err)
+		}
+		return n.renderWebhookAlert(partner, item.event)
+	}
+
+	feature := valueobjects.FeatureReceiptEmails
+	if item.kind == kindRefund {
+		feature = valueobjects.FeatureRefundEmails
+	}
+	partner, transaction, data, ok, err := n.load(ctx, item.event, feature)
+	if err != nil || !ok || transaction.CustomerEmail == "" {
+		return ports.Email{}, false, err
+	}
+
+	amount := transaction.Amount
+	tmpl := receiptTemplate
+	if item.kind == kindRefund {
+		if amount, err = valueobjects.ParseMoney(data.Amount, data.Currency); err != nil {
+			return ports.Email{}, false, fmt.Errorf("event has no refund amount: %w", err)
+		}
+		tmpl = refundTemplate
+	}
+
+	email, err := tmpl.render(transaction.CustomerEmail, paymentData{
+		PartnerName:   partner.Name,
+		Amount:        amount.Localize(string(partner.Locale)),
+		TransactionID: transaction.ID.String(),
+		Date:          item.event.CreatedAt.UTC().Format("2 January 2006 15:04 UTC"),
+	})
+	return email, err == nil, err
+}
+
+// text sends the payment confirmation of item by text message, if the
+// partner turned them on and the customer gave a phone number
+func (n *Notifier) text(ctx context.Context, item notification) error {
+	partner, transaction, _, ok, err := n.load(ctx, item.event, valueobjects.FeatureSMSConfirmations)
+	if err != nil || !ok || transaction.CustomerPhone == "" {
+		return err
+	}
+	phone, err := valueobjects.NewPhoneNumber(transaction.CustomerPhone)
+	if err != nil {
+		return fmt.Errorf("customer phone of transaction %s: %w", transaction.ID, err)
+	}
+
+	body, err := confirmationSMSTemplate.render(paymentData{
+		PartnerName:   partner.Name,
+		Amount:        transaction.Amount.Localize(string(partner.Locale)),
+		TransactionID: transaction.ID.String(),
+	})
+	if err != nil {
+		return err
+	}
+	return n.texts.Send(ctx, partner, phone, body)
+}
+
+// load returns the partner and transaction of a payment or refund event,
+// or false when the partner does not have feature or the event is not
+// about a live payment
+func (n *Notifier) load(ctx context.Context, event *entities.OutboxEvent, feature valueobjects.PartnerFeature) (*entities.Partner, *entities.Transaction, eventData, bool, error) {
+	var data eventData
+	partner, err := n.partnerRepo.GetByID(ctx, event.PartnerID)
+	if err == errors.ErrPartnerNotFound {
+		return nil, nil, data, false, nil
+	}
+	if err != nil {
+		return nil, nil, data, false, fmt.Errorf("failed to get partner: %w", err)
+	}
+	if !partner.HasFeature(feature) {
+		return nil, nil, data, false, nil
+	}
+
+	if err := json.Unmarshal(event.Payload, &data); err != nil {
+		return nil, nil, data, false, fmt.Errorf("failed to decode event: %w", err)
+	}
+	if !data.Livemode {
+		return nil, nil, data, false, nil
+	}
+	transactionID, err := uuid.Parse(data.TransactionID)
+	if err != nil {
+		return nil, nil, data, false, fmt.Errorf("event has no transaction: %w", err)
+	}
+	transaction, err := n.transactionRepo.GetByID(ctx, transactionID)
+	if err == errors.ErrTransactionNotFound || (err == nil && transaction == nil) {
+		return nil, nil, data, false, nil
+	}
+	if err != nil {
+		return nil, nil, data, false, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return partner, transaction, data, true, nil
+}
+
+// renderWebhookAlert returns the alert about event, unless the partner
+// turned alerts off or was alerted less than alertInterval ago
+func (n *Notifier) renderWebhookAlert(partner *entities.Partner, event *entities.OutboxEvent) (ports.Email, bool, error) {
+	if !partner.HasFeature(valueobjects.FeatureWebhookAlertEmails) || partner.WebhookURL == "" || partner.Email == "" {
+		return ports.Email{}, false, nil
+	}
+
+	n.mu.Lock()
+	if last, ok := n.lastAlert[partner.ID]; ok && time.Since(last) < n.alertInterval {
+		n.mu.Unlock()
+		return ports.Email{}, false, nil
+	}
+	n.lastAlert[partner.ID] = time.Now()
+	n.mu.Unlock()
+
+	email, err := webhookAlertTemplate.render(partner.Email, webhookAlertData{
+		PartnerName: partner.Name,
+		WebhookURL:  partner.WebhookURL,
+		EventType:   event.EventType,
+		EventID:     event.ID.String(),
+		Attempts:    event.Attempts,
+		LastError:   event.LastError,
+	})
+	return email, err == nil, err
+}
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SMSPolicy bounds the text messages sent to customers within a sliding
+// window
+type SMSPolicy struct {
+	// PerPartner limits the messages of one partner, so a bug or a caller
+	// requesting codes in a loop cannot run up the SMS bill
+	PerPartner int
+
+	// PerRecipient limits the messages to one phone number, whichever
+	// partner sends them
+	PerRecipient int
+
+	Window time.Duration
+}
+
+// Texts sends text messages to customers from their partner's sender,
+// within the limits of its policy
+type Texts struct {
+	sender ports.SMSSender
+	limits ports.RateLimitStore
+	policy SMSPolicy
+}
+
+// NewTexts creates a sender of text messages counting them in limits
+func NewTexts(sender ports.SMSSender, limits ports.RateLimitStore, policy SMSPolicy) *Texts {
+	return &Texts{sender: sender, limits: limits, policy: policy}
+}
+
+// Send texts body to to on behalf of partner. Messages over a limit are
+// refused with ErrSMSRateLimited; an unavailable store fails open, like
+// rate limiting.
+func (t *Texts) Send(ctx context.Context, partner *entities.Partner, to valueobjects.PhoneNumber, body string) error {
+	counters := []struct {
+		key   string
+		limit int
+	}{
+		{key: "sms:partner:" + partner.ID.String(), limit: t.policy.PerPartner},
+		{key: "sms:to:" + to.String(), limit: t.policy.PerRecipient},
+	}
+	for _, counter := range counters {
+		result, err := t.limits.Allow(ctx, counter.key, counter.limit, t.policy.Window)
+		if err == nil && !result.Allowed {
+			return errors.ErrSMSRateLimited
+		}
+	}
+
+	return t.sender.SendSMS(ctx, ports.SMS{
+		From: partner.SMSSenderID.String(),
+		To:   to.String(),
+		Body: body,
+	})
+}
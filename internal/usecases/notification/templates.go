@@ -0,0 +1,152 @@
+package notification
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"text/template"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// emailTemplate renders one kind of email. The subject and text body are
+// plain text; the HTML body escapes what it is given.
+type emailTemplate struct {
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+}
+
+// newEmailTemplate parses the templates of one kind of email
+func newEmailTemplate(name, subject, text, html string) emailTemplate {
+	return emailTemplate{
+		subject: template.Must(template.New(name + "_subject").Parse(subject)),
+		text:    template.Must(template.New(name + "_text").Parse(text)),
+		html:    htmltemplate.Must(htmltemplate.New(name + "_html").Parse(html)),
+	}
+}
+
+// render renders the email to to with data
+func (t emailTemplate) render(to string, data interface{}) (ports.Email, error) {
+	var subject, text, html bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return ports.Email{}, err
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return ports.Email{}, err
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return ports.Email{}, err
+	}
+	return ports.Email{To: to, Subject: subject.String(), Text: text.String(), HTML: html.String()}, nil
+}
+
+// paymentData is what receipts and refund confirmations show the customer
+type paymentData struct {
+	PartnerName   string
+	Amount        string // Localized in the partner's locale
+	TransactionID string
+	Date          string
+}
+
+// webhookAlertData is what webhook alerts show the partner
+type webhookAlertData struct {
+	PartnerName string
+	WebhookURL  string
+	EventType   string
+	EventID     string
+	Attempts    int
+	LastError   string
+}
+
+var receiptTemplate = newEmailTemplate("receipt",
+	`Your payment to {{.PartnerName}}`,
+	`Thank you for your payment.
+
+Paid to: {{.PartnerName}}
+Amount: {{.Amount}}
+Date: {{.Date}}
+Reference: {{.TransactionID}}
+
+Keep this email as your receipt.
+`,
+	`<p>Thank you for your payment.</p>
+<table>
+<tr><td>Paid to</td><td>{{.PartnerName}}</td></tr>
+<tr><td>Amount</td><td><strong>{{.Amount}}</strong></td></tr>
+<tr><td>Date</td><td>{{.Date}}</td></tr>
+<tr><td>Reference</td><td>{{.TransactionID}}</td></tr>
+</table>
+<p>Keep this email as your receipt.</p>
+`)
+
+var refundTemplate = newEmailTemplate("refund",
+	`Your refund from {{.PartnerName}}`,
+	`{{.PartnerName}} refunded {{.Amount}} of your payment.
+
+Refunded: {{.Amount}}
+Date: {{.Date}}
+Payment reference: {{.TransactionID}}
+
+Depending on your bank, the refund takes up to 10 business days to show on
+your statement.
+`,
+	`<p>{{.PartnerName}} refunded <strong>{{.Amount}}</strong> of your payment.</p>
+<table>
+<tr><td>Refunded</td><td>{{.Amount}}</td></tr>
+<tr><td>Date</td><td>{{.Date}}</td></tr>
+<tr><td>Payment reference</td><td>{{.TransactionID}}</td></tr>
+</table>
+<p>Depending on your bank, the refund takes up to 10 business days to show on your statement.</p>
+`)
+
+var webhookAlertTemplate = newEmailTemplate("webhook_alert",
+	`Pay2Go stopped retrying a webhook to {{.WebhookURL}}`,
+	`Hello {{.PartnerName}},
+
+Your webhook endpoint {{.WebhookURL}} did not accept the {{.EventType}}
+event {{.EventID}} in {{.Attempts}} attempts, so Pay2Go stopped retrying it.
+
+Last error: {{.LastError}}
+
+Check that the endpoint is reachable and answers with a 2xx status. Later
+events are still delivered; fetch the state of what you missed from the API.
+`,
+	`<p>Hello {{.PartnerName}},</p>
+<p>Your webhook endpoint <code>{{.WebhookURL}}</code> did not accept the <code>{{.EventType}}</code> event <code>{{.EventID}}</code> in {{.Attempts}} attempts, so Pay2Go stopped retrying it.</p>
+<p>Last error: {{.LastError}}</p>
+<p>Check that the endpoint is reachable and answers with a 2xx status. Later events are still delivered; fetch the state of what you missed from the API.</p>
+`)
+
+// smsTemplate renders one kind of text message. Messages are kept short:
+// past 160 characters, carriers split and bill them as several.
+type smsTemplate struct {
+	body *template.Template
+}
+
+// newSMSTemplate parses the template of one kind of text message
+func newSMSTemplate(name, body string) smsTemplate {
+	return smsTemplate{body: template.Must(template.New(name + "_sms").Parse(body))}
+}
+
+// render renders the message with data
+func (t smsTemplate) render(data interface{}) (string, error) {
+	var body bytes.Buffer
+	if err := t.body.Execute(&body, data); err != nil {
+		return "", err
+	}
+	return body.String(), nil
+}
+
+// verificationData is what verification codes tell the customer
+type verificationData struct {
+	PartnerName string
+	Amount      string // Localized in the partner's locale
+	Code        string
+	Minutes     int
+}
+
+var confirmationSMSTemplate = newSMSTemplate("confirmation",
+	`{{.PartnerName}}: we received your payment of {{.Amount}}. Ref {{.TransactionID}}`)
+
+var verificationSMSTemplate = newSMSTemplate("verification",
+	`{{.Code}} is your code to confirm your payment of {{.Amount}} to {{.PartnerName}}. It expires in {{.Minutes}} minutes. Never share it.`)
@@ -0,0 +1,231 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// VerificationPolicy sets how long a verification code lasts and how often
+// it may be guessed
+type VerificationPolicy struct {
+	TTL time.Duration
+
+	// MaxAttempts wrong codes within TTL discard the code, so it cannot be
+	// guessed; the customer asks for a new one
+	MaxAttempts int
+}
+
+// SendVerificationCodeUseCase texts a customer a one-time code to confirm a
+// pending payment. Partners use it where card authentication such as 3-D
+// Secure is not available, and check the code the customer enters with
+// VerifyCodeUseCase before processing the payment.
+type SendVerificationCodeUseCase struct {
+	partnerRepo     ports.PartnerRepository
+	transactionRepo ports.TransactionRepository
+	codes           ports.VerificationCodeStore
+	texts           *Texts
+	policy          VerificationPolicy
+}
+
+// NewSendVerificationCodeUseCase creates a new instance
+func NewSendVerificationCodeUseCase(
+	partnerRepo ports.PartnerRepository,
+	transactionRepo ports.TransactionRepository,
+	codes ports.VerificationCodeStore,
+	texts *Texts,
+	policy VerificationPolicy,
+) *SendVerificationCodeUseCase {
+	return &SendVerificationCodeUseCase{
+		partnerRepo:     partnerRepo,
+		transactionRepo: transactionRepo,
+		codes:           codes,
+		texts:           texts,
+		policy:          policy,
+	}
+}
+
+// Execute texts a new code to the customer phone of the transaction,
+// replacing any code sent before, and returns when it expires
+func (uc *SendVerificationCodeUseCase) Execute(ctx context.Context, partnerID, transactionID uuid.UUID) (time.Time, error) {
+	if uc.texts == nil {
+		return time.Time{}, errors.ErrSMSUnavailable
+	}
+
+	// Step 1: Retrieve partner and transaction
+	partner, err := uc.partnerRepo.GetByID(ctx, partnerID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if partner == nil {
+		return time.Time{}, errors.ErrPartnerNotFound
+	}
+	if !partner.HasFeature(valueobjects.FeatureSMSVerification) {
+		return time.Time{}, errors.ErrFeatureDisabled
+	}
+
+	transaction, err := getPartnerTransaction(ctx, uc.transactionRepo, partnerID, transactionID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !transaction.IsPending() {
+		return time.Time{}, errors.NewBusinessRuleError(
+			"invalid_state",
+			fmt.Sprintf("transaction is in %s state, only pending payments are verified", transaction.Status),
+		)
+	}
+	phone, err := valueobjects.NewPhoneNumber(transaction.CustomerPhone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Step 2: Keep the hash of a new code and text the code
+	code, err := newCode()
+	if err != nil {
+		return time.Time{}, err
+	}
+	expiresAt := time.Now().Add(uc.policy.TTL)
+	if err := uc.codes.Save(ctx, transaction.ID.String(), hashCode(transaction.ID, code), uc.policy.TTL); err != nil {
+		return time.Time{}, err
+	}
+
+	body, err := verificationSMSTemplate.render(verificationData{
+		PartnerName: partner.Name,
+		Amount:      transaction.Amount.Localize(string(partner.Locale)),
+		Code:        code,
+		Minutes:     int(uc.policy.TTL.Minutes()),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := uc.texts.Send(ctx, partner, phone, body); err != nil {
+		_ = uc.codes.Delete(ctx, transaction.ID.String())
+		return time.Time{}, err
+	}
+
+	return expiresAt, nil
+}
+
+// VerifyCodeInput represents a code a customer entered to confirm a payment
+type VerifyCodeInput struct {
+	PartnerID     uuid.UUID
+	TransactionID uuid.UUID
+	Code          string
+	IPAddress     string
+	UserAgent     string
+}
+
+// VerifyCodeUseCase checks the code a customer entered against the one
+// texted by SendVerificationCodeUseCase
+type VerifyCodeUseCase struct {
+	transactionRepo ports.TransactionRepository
+	codes           ports.VerificationCodeStore
+	attempts        ports.RateLimitStore
+	auditLogger     ports.AuditLogger
+	policy          VerificationPolicy
+}
+
+// NewVerifyCodeUseCase creates a new instance
+func NewVerifyCodeUseCase(
+	transactionRepo ports.TransactionRepository,
+	codes ports.VerificationCodeStore,
+	attempts ports.RateLimitStore,
+	auditLogger ports.AuditLogger,
+	policy VerificationPolicy,
+) *VerifyCodeUseCase {
+	return &VerifyCodeUseCase{
+		transactionRepo: transactionRepo,
+		codes:           codes,
+		attempts:        attempts,
+		auditLogger:     auditLogger,
+		policy:          policy,
+	}
+}
+
+// Execute verifies input.Code. A code is good for one successful check;
+// a wrong or expired one returns ErrInvalidVerificationCode, and the
+// attempt after MaxAttempts wrong ones ErrTooManyFailures.
+func (uc *VerifyCodeUseCase) Execute(ctx context.Context, input VerifyCodeInput) error {
+	// Step 1: Retrieve transaction
+	transaction, err := getPartnerTransaction(ctx, uc.transactionRepo, input.PartnerID, input.TransactionID)
+	if err != nil {
+		return err
+	}
+	key := transaction.ID.String()
+
+	// Step 2: Count the attempt; once they run out the code is discarded
+	result, err := uc.attempts.Allow(ctx, "verification:attempts:"+key, uc.policy.MaxAttempts, uc.policy.TTL)
+	if err == nil && !result.Allowed {
+		_ = uc.codes.Delete(ctx, key)
+		return errors.ErrTooManyFailures
+	}
+
+	// Step 3: Compare with the code sent
+	hash, err := uc.codes.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if hash == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(hashCode(transaction.ID, input.Code))) != 1 {
+		return errors.ErrInvalidVerificationCode
+	}
+	if err := uc.codes.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    transaction.PartnerID,
+			Action:       "transaction_customer_verified",
+			ResourceType: "transaction",
+			ResourceID:   transaction.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"method": "sms",
+			},
+		})
+	}
+
+	return nil
+}
+
+// getPartnerTransaction returns the transaction of partnerID with id
+func getPartnerTransaction(ctx context.Context, repo ports.TransactionRepository, partnerID, id uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil || transaction.PartnerID != partnerID {
+		return nil, errors.ErrTransactionNotFound
+	}
+	return transaction, nil
+}
+
+// newCode returns a random six-digit code
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashCode is what is kept of a code, bound to its transaction so a hash
+// copied to another transaction's key does not match there
+func hashCode(transactionID uuid.UUID, code string) string {
+	sum := sha256.Sum256([]byte(transactionID.String() + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
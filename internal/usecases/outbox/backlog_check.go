@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// BacklogCheck reports how many outbox events wait to be published. A large
+// backlog delays webhooks but loses nothing, so it only degrades the API.
+type BacklogCheck struct {
+	outboxRepo ports.OutboxRepository
+	maxPending int64
+}
+
+// NewBacklogCheck creates a health check that is degraded beyond maxPending
+// waiting events
+func NewBacklogCheck(outboxRepo ports.OutboxRepository, maxPending int64) *BacklogCheck {
+	return &BacklogCheck{outboxRepo: outboxRepo, maxPending: maxPending}
+}
+
+// CheckHealth measures the outbox backlog
+func (c *BacklogCheck) CheckHealth(ctx context.Context) ports.ComponentHealth {
+	backlog, err := c.outboxRepo.Backlog(ctx)
+	if err != nil {
+		return ports.ComponentHealth{Status: ports.HealthDown, Error: err.Error()}
+	}
+
+	health := ports.ComponentHealth{
+		Status: ports.HealthUp,
+		Details: map[string]interface{}{
+			"pending":     backlog.Pending,
+			"max_pending": c.maxPending,
+		},
+	}
+	if backlog.OldestCreatedAt != nil {
+		health.Details["oldest_age_seconds"] = int64(time.Since(*backlog.OldestCreatedAt).Seconds())
+	}
+	if backlog.Pending > c.maxPending {
+		health.Status = ports.HealthDegraded
+		health.Error = fmt.Sprintf("%d events wait to be published", backlog.Pending)
+	}
+	return health
+}
@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// FanoutPublisher is the relay's publisher: it delivers webhook events to
+// partners, then publishes every event to the event stream, if there is one.
+//
+// An event counts as published once both succeeded. When the stream fails
+// after the webhook was delivered, the relay retries the event and the
+// partner receives the webhook again, which the at-least-once contract of
+// webhooks allows. A broker outage thus never holds webhooks back.
+type FanoutPublisher struct {
+	webhooks ports.OutboxPublisher
+	stream   ports.EventPublisher
+}
+
+// NewFanoutPublisher creates a publisher delivering to webhooks and stream;
+// stream may be nil
+func NewFanoutPublisher(webhooks ports.OutboxPublisher, stream ports.EventPublisher) *FanoutPublisher {
+	return &FanoutPublisher{webhooks: webhooks, stream: stream}
+}
+
+// Publish delivers event to its consumers
+func (p *FanoutPublisher) Publish(ctx context.Context, event *entities.OutboxEvent) error {
+	if entities.IsWebhookEvent(event.EventType) {
+		if err := p.webhooks.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	if p.stream != nil {
+		return p.stream.PublishEvent(ctx, event)
+	}
+	return nil
+}
+
+// Notifiers is the relay's notifier when several are told about events
+type Notifiers []ports.OutboxNotifier
+
+// Published tells every notifier that event was published
+func (n Notifiers) Published(ctx context.Context, event *entities.OutboxEvent) {
+	for _, notifier := range n {
+		notifier.Published(ctx, event)
+	}
+}
+
+// Exhausted tells every notifier that event will not be retried
+func (n Notifiers) Exhausted(ctx context.Context, event *entities.OutboxEvent) {
+	for _, notifier := range n {
+		notifier.Exhausted(ctx, event)
+	}
+}
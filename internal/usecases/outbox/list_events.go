@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// maxListedEvents bounds the events listed about one aggregate
+const maxListedEvents = 100
+
+// ListEventsUseCase lists the webhook events recorded about one of a
+// partner's transactions or refunds, delivered or not
+type ListEventsUseCase struct {
+	outboxRepo ports.OutboxRepository
+}
+
+// NewListEventsUseCase creates a new instance
+func NewListEventsUseCase(outboxRepo ports.OutboxRepository) *ListEventsUseCase {
+	return &ListEventsUseCase{
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Execute lists up to limit events about the aggregate, oldest first. The
+// caller has checked the partner may see the aggregate; events of other
+// partners are left out all the same.
+func (uc *ListEventsUseCase) Execute(ctx context.Context, partnerID uuid.UUID, aggregateType string, aggregateID uuid.UUID, limit int) ([]*entities.OutboxEvent, error) {
+	if limit <= 0 || limit > maxListedEvents {
+		limit = maxListedEvents
+	}
+
+	events, err := uc.outboxRepo.ListByAggregate(ctx, aggregateType, aggregateID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	owned := events[:0]
+	for _, event := range events {
+		if event.PartnerID == partnerID {
+			owned = append(owned, event)
+		}
+	}
+	return owned, nil
+}
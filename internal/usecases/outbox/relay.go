@@ -0,0 +1,257 @@
+// Package outbox contains the relay that publishes transactional outbox events
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Relay publishes events from the transactional outbox. Events are written
+// in the same database transaction as the change they describe, so one is
+// published exactly when its change committed; a crash between commit and
+// publish only delays it until the relay's next pass.
+//
+// Delivery is at least once: an event whose publish succeeded but whose
+// outcome could not be saved is published again.
+//
+// Each pass publishes with a fixed number of workers, and at most
+// perPartner of them deliver to the same partner at once, so one slow
+// endpoint holds up only its own events. With perPartner above 1 a
+// partner's events may arrive out of order.
+//
+// Several relays can share an outbox. A pass claims its events until
+// claimTimeout from now, so other relays skip them, and relays given
+// disjoint shards do not even compete for the same rows. All of a
+// partner's events are in one shard, so with disjoint shards perPartner
+// still holds across relays.
+type Relay struct {
+	outboxRepo ports.OutboxRepository
+	publisher  ports.OutboxPublisher
+	notifier   ports.OutboxNotifier
+
+	// batchSize caps how many events one pass publishes
+	batchSize    int
+	workers      int
+	perPartner   int
+	shards       ports.OutboxShards
+	claimTimeout time.Duration
+
+	queued      atomic.Int64
+	inFlight    atomic.Int64
+	published   atomic.Int64
+	failed      atomic.Int64
+	throttled   atomic.Int64
+	fullBatches atomic.Int64
+}
+
+// RelayStats shows how hard the relay is pushed. Queued events and full
+// batches growing pass after pass mean events arrive faster than they are
+// delivered; throttled waits mean workers sat idle behind busy partners.
+type RelayStats struct {
+	Workers    int
+	PerPartner int
+	// Shards are the outbox shards this relay publishes
+	Shards ports.OutboxShards
+	// Queued and InFlight are the events of the current pass waiting for a
+	// worker and being delivered
+	Queued   int64
+	InFlight int64
+	// The counters below run since the relay started
+	Published int64
+	Failed    int64
+	// Throttled counts the times a worker waited because every queued
+	// event's partner was at its limit
+	Throttled int64
+	// FullBatches counts the passes that found a full batch due, so more
+	// events were waiting
+	FullBatches int64
+}
+
+// NewRelay creates a new outbox relay publishing the events of shards with
+// workers workers, at most perPartner of them for one partner. claimTimeout
+// must be longer than a pass takes, or another relay may publish an event
+// of a pass still running again. notifier, which may be nil, is told about
+// the events published and given up on.
+func NewRelay(
+	outboxRepo ports.OutboxRepository,
+	publisher ports.OutboxPublisher,
+	notifier ports.OutboxNotifier,
+	batchSize, workers, perPartner int,
+	shards ports.OutboxShards,
+	claimTimeout time.Duration,
+) *Relay {
+	return &Relay{
+		outboxRepo:   outboxRepo,
+		publisher:    publisher,
+		notifier:     notifier,
+		batchSize:    batchSize,
+		workers:      workers,
+		perPartner:   perPartner,
+		shards:       shards,
+		claimTimeout: claimTimeout,
+	}
+}
+
+// PublishDue publishes the events that are due and returns how many were
+// published. A failed publish is saved with its next attempt time and does
+// not stop the others; only storage errors are returned, once the events
+// already being delivered are saved. Events left undelivered then stay
+// claimed until the claim times out.
+func (r *Relay) PublishDue(ctx context.Context) (int, error) {
+	events, err := r.outboxRepo.ClaimDue(ctx, r.shards, r.batchSize, time.Now().Add(r.claimTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	if len(events) == r.batchSize {
+		r.fullBatches.Add(1)
+	}
+
+	queue := newPartnerQueue(events, r.perPartner, r)
+	var (
+		published atomic.Int64
+		errOnce   sync.Once
+		storeErr  error
+		wg        sync.WaitGroup
+	)
+	workers := r.workers
+	if workers > len(events) {
+		workers = len(events)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := queue.next(); event != nil; event = queue.next() {
+				ok, err := r.publish(ctx, event)
+				queue.done(event.PartnerID)
+				if err != nil {
+					errOnce.Do(func() { storeErr = err })
+					queue.drop()
+					return
+				}
+				if ok {
+					published.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(published.Load()), storeErr
+}
+
+// Stats returns the relay's current load and counters
+func (r *Relay) Stats() RelayStats {
+	return RelayStats{
+		Workers:     r.workers,
+		PerPartner:  r.perPartner,
+		Shards:      r.shards,
+		Queued:      r.queued.Load(),
+		InFlight:    r.inFlight.Load(),
+		Published:   r.published.Load(),
+		Failed:      r.failed.Load(),
+		Throttled:   r.throttled.Load(),
+		FullBatches: r.fullBatches.Load(),
+	}
+}
+
+// publish delivers event and saves the outcome, reporting whether the
+// delivery succeeded
+func (r *Relay) publish(ctx context.Context, event *entities.OutboxEvent) (bool, error) {
+	r.inFlight.Add(1)
+	err := r.publisher.Publish(ctx, event)
+	r.inFlight.Add(-1)
+
+	if err != nil {
+		event.MarkFailed(err.Error())
+		r.failed.Add(1)
+	} else {
+		event.MarkPublished()
+		r.published.Add(1)
+	}
+
+	if err := r.outboxRepo.Update(ctx, event); err != nil {
+		return false, fmt.Errorf("failed to update outbox event: %w", err)
+	}
+
+	if r.notifier != nil {
+		switch {
+		case err == nil:
+			r.notifier.Published(ctx, event)
+		case event.IsExhausted():
+			r.notifier.Exhausted(ctx, event)
+		}
+	}
+	return err == nil, nil
+}
+
+// partnerQueue hands a pass's events to workers in order, skipping events
+// of partners that already have perPartner deliveries in flight
+type partnerQueue struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	events     []*entities.OutboxEvent
+	inFlight   map[uuid.UUID]int
+	perPartner int
+	relay      *Relay
+}
+
+func newPartnerQueue(events []*entities.OutboxEvent, perPartner int, relay *Relay) *partnerQueue {
+	q := &partnerQueue{
+		events:     events,
+		inFlight:   make(map[uuid.UUID]int),
+		perPartner: perPartner,
+		relay:      relay,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	relay.queued.Add(int64(len(events)))
+	return q
+}
+
+// next returns the first event whose partner is below its limit, waiting
+// for a delivery to finish if there is none, or nil once the queue is empty
+func (q *partnerQueue) next() *entities.OutboxEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.events) > 0 {
+		for i, event := range q.events {
+			if q.inFlight[event.PartnerID] < q.perPartner {
+				q.events = append(q.events[:i], q.events[i+1:]...)
+				q.inFlight[event.PartnerID]++
+				q.relay.queued.Add(-1)
+				return event
+			}
+		}
+		q.relay.throttled.Add(1)
+		q.cond.Wait()
+	}
+	return nil
+}
+
+// done records that a delivery to partnerID finished
+func (q *partnerQueue) done(partnerID uuid.UUID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.inFlight[partnerID]--
+	q.cond.Broadcast()
+}
+
+// drop empties the queue, leaving its events to be claimed again
+func (q *partnerQueue) drop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.relay.queued.Add(-int64(len(q.events)))
+	q.events = nil
+	q.cond.Broadcast()
+}
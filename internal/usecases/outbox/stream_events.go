@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// streamSettleDelay is how long a recorded event is held back from the
+// stream. Events are created before their unit of work commits, so one may
+// appear after later events were already read; waiting out the commit keeps
+// the cursor from passing it.
+const streamSettleDelay = 2 * time.Second
+
+// streamedAggregates are the aggregates whose events are streamed
+var streamedAggregates = map[string]bool{"transaction": true, "refund": true}
+
+// StreamEventsUseCase reads a partner's transaction and refund events in the
+// order they were recorded, for clients that follow them live instead of
+// receiving webhooks. Events are read from the outbox whether or not the
+// relay published them yet, so any instance can serve a stream.
+type StreamEventsUseCase struct {
+	outboxRepo ports.OutboxRepository
+}
+
+// NewStreamEventsUseCase creates a new instance
+func NewStreamEventsUseCase(outboxRepo ports.OutboxRepository) *StreamEventsUseCase {
+	return &StreamEventsUseCase{
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Start returns the cursor of a stream that begins now
+func (uc *StreamEventsUseCase) Start() ports.OutboxCursor {
+	return ports.OutboxCursor{CreatedAt: time.Now().Add(-streamSettleDelay)}
+}
+
+// Execute reads up to limit of the partner's events after the cursor and
+// returns those of transactions and refunds in livemode, with the cursor to
+// read on from. Fewer than limit events were read when more is false.
+func (uc *StreamEventsUseCase) Execute(
+	ctx context.Context,
+	partnerID uuid.UUID,
+	livemode bool,
+	after ports.OutboxCursor,
+	limit int,
+) (events []*entities.OutboxEvent, next ports.OutboxCursor, more bool, err error) {
+	read, err := uc.outboxRepo.ListByPartner(ctx, partnerID, after, time.Now().Add(-streamSettleDelay), limit)
+	if err != nil {
+		return nil, after, false, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	next = after
+	for _, event := range read {
+		next = ports.OutboxCursor{CreatedAt: event.CreatedAt, ID: event.ID}
+		if !streamedAggregates[event.AggregateType] {
+			continue
+		}
+		// Test keys only see test payments, live keys live ones
+		var mode struct {
+			Livemode bool `json:"livemode"`
+		}
+		if err := json.Unmarshal(event.Payload, &mode); err != nil || mode.Livemode != livemode {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, next, len(read) == limit, nil
+}
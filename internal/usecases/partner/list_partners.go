@@ -0,0 +1,33 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ListPartnersUseCase pages through partners for the back office
+type ListPartnersUseCase struct {
+	partnerRepo ports.PartnerRepository
+}
+
+// NewListPartnersUseCase creates a new instance
+func NewListPartnersUseCase(partnerRepo ports.PartnerRepository) *ListPartnersUseCase {
+	return &ListPartnersUseCase{
+		partnerRepo: partnerRepo,
+	}
+}
+
+// Execute returns a page of partners, newest first, and the total number of partners
+func (uc *ListPartnersUseCase) Execute(ctx context.Context, limit, offset int) ([]*entities.Partner, int64, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	partners, total, err := uc.partnerRepo.ListWithTotal(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list partners: %w", err)
+	}
+
+	return partners, total, nil
+}
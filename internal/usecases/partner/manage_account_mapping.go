@@ -0,0 +1,84 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerAccountMappingInput represents input for naming the accounts a partner's accounting exports post to
+type UpdatePartnerAccountMappingInput struct {
+	PartnerID uuid.UUID
+	Mapping   *valueobjects.AccountMapping // Nil posts to the default accounts
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerAccountMappingUseCase sets the accounts of a partner's accounting system its QuickBooks and Xero exports post to
+type UpdatePartnerAccountMappingUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerAccountMappingUseCase creates a new instance
+func NewUpdatePartnerAccountMappingUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerAccountMappingUseCase {
+	return &UpdatePartnerAccountMappingUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute replaces the partner's account mapping. Files already written
+// keep the accounts they were written with.
+func (uc *UpdatePartnerAccountMappingUseCase) Execute(ctx context.Context, input UpdatePartnerAccountMappingInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply mapping
+	var mapping *valueobjects.AccountMapping
+	if input.Mapping != nil {
+		validated, err := valueobjects.NewAccountMapping(*input.Mapping)
+		if err != nil {
+			return nil, err
+		}
+		mapping = &validated
+	}
+
+	previous := partner.AccountMapping
+	partner.SetAccountMapping(mapping)
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_account_mapping_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"previous_account_mapping": previous,
+				"account_mapping":          partner.AccountMapping,
+			},
+		})
+	}
+
+	return partner, nil
+}
@@ -0,0 +1,83 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerAmountLimitsInput represents input for setting a partner's maximum amounts
+type UpdatePartnerAmountLimitsInput struct {
+	PartnerID uuid.UUID
+	Limits    map[string]string // ISO 4217 code -> decimal amount in major units; empty uses platform limits
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerAmountLimitsUseCase sets the per-currency maximum transaction amounts of a partner
+type UpdatePartnerAmountLimitsUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerAmountLimitsUseCase creates a new instance
+func NewUpdatePartnerAmountLimitsUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerAmountLimitsUseCase {
+	return &UpdatePartnerAmountLimitsUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute replaces the partner's amount limits. A partner already at those
+// limits is left alone, whatever version ctx expects, so a retried request
+// succeeds.
+func (uc *UpdatePartnerAmountLimitsUseCase) Execute(ctx context.Context, input UpdatePartnerAmountLimitsInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply limits
+	previous := partner.AmountLimits.Decimals()
+	if err := partner.SetAmountLimits(input.Limits); err != nil {
+		return nil, err
+	}
+	if maps.Equal(partner.AmountLimits.Decimals(), previous) {
+		return partner, nil
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_amount_limits_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":        input.AdminID,
+				"previous_limits": previous,
+				"limits":          partner.AmountLimits.Decimals(),
+			},
+		})
+	}
+
+	return partner, nil
+}
@@ -0,0 +1,83 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerCurrenciesInput represents input for restricting a partner's currencies
+type UpdatePartnerCurrenciesInput struct {
+	PartnerID  uuid.UUID
+	Currencies []string // ISO 4217 codes; empty allows every currency
+	AdminID    string
+	IPAddress  string
+	UserAgent  string
+}
+
+// UpdatePartnerCurrenciesUseCase sets the currencies a partner may create transactions in
+type UpdatePartnerCurrenciesUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerCurrenciesUseCase creates a new instance
+func NewUpdatePartnerCurrenciesUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerCurrenciesUseCase {
+	return &UpdatePartnerCurrenciesUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute replaces the partner's allowed currencies. A partner already
+// allowing those currencies is left alone, whatever version ctx expects, so a
+// retried request succeeds.
+func (uc *UpdatePartnerCurrenciesUseCase) Execute(ctx context.Context, input UpdatePartnerCurrenciesInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply currencies
+	previous := partner.AllowedCurrencies
+	if err := partner.SetAllowedCurrencies(input.Currencies); err != nil {
+		return nil, err
+	}
+	if slices.Equal(partner.AllowedCurrencies, previous) {
+		return partner, nil
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_currencies_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":            input.AdminID,
+				"previous_currencies": previous,
+				"currencies":          partner.AllowedCurrencies,
+			},
+		})
+	}
+
+	return partner, nil
+}
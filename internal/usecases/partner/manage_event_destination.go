@@ -0,0 +1,93 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerEventDestinationInput represents input for changing where a partner's events are delivered
+type UpdatePartnerEventDestinationInput struct {
+	PartnerID uuid.UUID
+	Type      string // sns or sqs; empty goes back to webhooks
+	Target    string // SNS topic ARN or SQS queue URL
+	RoleARN   string // Role in the partner's account that Pay2Go assumes
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerEventDestinationUseCase sets the SNS topic or SQS queue in a
+// partner's own AWS account that receives its events instead of its webhook URL
+type UpdatePartnerEventDestinationUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerEventDestinationUseCase creates a new instance
+func NewUpdatePartnerEventDestinationUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerEventDestinationUseCase {
+	return &UpdatePartnerEventDestinationUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute changes the partner's event destination, or removes it when
+// input.Type is empty
+func (uc *UpdatePartnerEventDestinationUseCase) Execute(ctx context.Context, input UpdatePartnerEventDestinationInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply event destination
+	var destination *valueobjects.EventDestination
+	if input.Type != "" {
+		parsed, err := valueobjects.NewEventDestination(input.Type, input.Target, input.RoleARN)
+		if err != nil {
+			return nil, err
+		}
+		destination = &parsed
+	}
+
+	previous := partner.EventDestination
+	partner.SetEventDestination(destination)
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		action := "partner_event_destination_updated"
+		if destination == nil {
+			action = "partner_event_destination_removed"
+		}
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       action,
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":             input.AdminID,
+				"previous_destination": previous,
+				"event_destination":    destination,
+			},
+		})
+	}
+
+	return partner, nil
+}
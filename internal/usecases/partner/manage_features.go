@@ -0,0 +1,136 @@
+// Package partner contains back-office use cases for managing partners
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// GetPartnerUseCase retrieves a partner for back-office admins
+type GetPartnerUseCase struct {
+	partnerRepo ports.PartnerRepository
+}
+
+// NewGetPartnerUseCase creates a new instance
+func NewGetPartnerUseCase(partnerRepo ports.PartnerRepository) *GetPartnerUseCase {
+	return &GetPartnerUseCase{
+		partnerRepo: partnerRepo,
+	}
+}
+
+// Execute retrieves the partner
+func (uc *GetPartnerUseCase) Execute(ctx context.Context, partnerID uuid.UUID) (*entities.Partner, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	partner, err := uc.partnerRepo.GetByID(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	return partner, nil
+}
+
+// UpdatePartnerFeaturesInput represents input for changing a partner's feature flags
+type UpdatePartnerFeaturesInput struct {
+	PartnerID uuid.UUID
+	Features  map[string]bool // Flags to set; others are left unchanged
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerFeaturesUseCase turns features on or off for a partner
+type UpdatePartnerFeaturesUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerFeaturesUseCase creates a new instance
+func NewUpdatePartnerFeaturesUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerFeaturesUseCase {
+	return &UpdatePartnerFeaturesUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute applies the given flags to the partner. A partner with every flag
+// already set that way is left alone, whatever version ctx expects, so a
+// retried request succeeds.
+func (uc *UpdatePartnerFeaturesUseCase) Execute(ctx context.Context, input UpdatePartnerFeaturesInput) (*entities.Partner, error) {
+	// Step 1: Validate feature names before changing anything
+	features := make(map[valueobjects.PartnerFeature]bool, len(input.Features))
+	for name, enabled := range input.Features {
+		feature, err := valueobjects.NewPartnerFeature(name)
+		if err != nil {
+			return nil, err
+		}
+		features[feature] = enabled
+	}
+
+	// Step 2: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 3: Apply flags
+	if featuresSet(partner, features) {
+		return partner, nil
+	}
+
+	previous := partner.EffectiveFeatures()
+	for feature, enabled := range features {
+		if err := partner.SetFeature(feature, enabled); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_features_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":          input.AdminID,
+				"previous_features": previous,
+				"features":          partner.EffectiveFeatures(),
+			},
+		})
+	}
+
+	return partner, nil
+}
+
+// featuresSet reports whether partner has each of features explicitly set
+// to its value; a flag left at its default is not set yet
+func featuresSet(partner *entities.Partner, features map[valueobjects.PartnerFeature]bool) bool {
+	for feature, enabled := range features {
+		if set, ok := partner.Features[feature]; !ok || set != enabled {
+			return false
+		}
+	}
+	return true
+}
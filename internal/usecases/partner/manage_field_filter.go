@@ -0,0 +1,76 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerFieldFilterInput represents input for choosing the fields hidden from a partner's integrations
+type UpdatePartnerFieldFilterInput struct {
+	PartnerID uuid.UUID
+	Responses map[string][]string // Fields hidden from API responses, by API key scope
+	Webhooks  []string            // Fields hidden from the data of delivered events
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerFieldFilterUseCase replaces the fields hidden from a partner's API responses and webhooks
+type UpdatePartnerFieldFilterUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerFieldFilterUseCase creates a new instance
+func NewUpdatePartnerFieldFilterUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerFieldFilterUseCase {
+	return &UpdatePartnerFieldFilterUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute replaces the partner's field filter
+func (uc *UpdatePartnerFieldFilterUseCase) Execute(ctx context.Context, input UpdatePartnerFieldFilterInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply filter
+	previous := partner.FieldFilter
+	if err := partner.SetFieldFilter(input.Responses, input.Webhooks); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_field_filter_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"previous_field_filter": previous,
+				"field_filter":          partner.FieldFilter,
+			},
+		})
+	}
+
+	return partner, nil
+}
@@ -0,0 +1,81 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerLocaleInput represents input for setting a partner's locale
+type UpdatePartnerLocaleInput struct {
+	PartnerID uuid.UUID
+	Locale    string // BCP 47 tag, e.g. "th-TH"
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerLocaleUseCase sets the language and region a partner's dashboards, statements and emails use
+type UpdatePartnerLocaleUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerLocaleUseCase creates a new instance
+func NewUpdatePartnerLocaleUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerLocaleUseCase {
+	return &UpdatePartnerLocaleUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute changes the partner's locale. A partner already at that locale
+// is left alone, whatever version ctx expects, so a retried request succeeds.
+func (uc *UpdatePartnerLocaleUseCase) Execute(ctx context.Context, input UpdatePartnerLocaleInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply locale
+	previous := partner.Locale
+	if err := partner.SetLocale(input.Locale); err != nil {
+		return nil, err
+	}
+	if partner.Locale == previous {
+		return partner, nil
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_locale_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":        input.AdminID,
+				"previous_locale": previous,
+				"locale":          partner.Locale,
+			},
+		})
+	}
+
+	return partner, nil
+}
@@ -0,0 +1,87 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerPricingInput represents input for setting what a partner's statements charge
+type UpdatePartnerPricingInput struct {
+	PartnerID     uuid.UUID
+	Tiers         []valueobjects.PricingTier // Empty charges the platform's standard pricing
+	ChargebackFee int64
+	AdminID       string
+	IPAddress     string
+	UserAgent     string
+}
+
+// UpdatePartnerPricingUseCase sets the fee tiers and chargeback fee a partner's monthly statements charge
+type UpdatePartnerPricingUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerPricingUseCase creates a new instance
+func NewUpdatePartnerPricingUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerPricingUseCase {
+	return &UpdatePartnerPricingUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute changes the partner's pricing. Statements already issued keep
+// the pricing they were issued with.
+func (uc *UpdatePartnerPricingUseCase) Execute(ctx context.Context, input UpdatePartnerPricingInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply pricing
+	var pricing *valueobjects.Pricing
+	if len(input.Tiers) > 0 {
+		validated, err := valueobjects.NewPricing(input.Tiers, input.ChargebackFee)
+		if err != nil {
+			return nil, err
+		}
+		pricing = &validated
+	}
+
+	previous := partner.Pricing
+	partner.SetPricing(pricing)
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_pricing_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":         input.AdminID,
+				"previous_pricing": previous,
+				"pricing":          partner.Pricing,
+			},
+		})
+	}
+
+	return partner, nil
+}
@@ -0,0 +1,122 @@
+package partner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Logos may be JPEG
+	_ "image/png"  // or PNG
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Limits on receipt logos, which are drawn at most 180x60 points
+const (
+	maxReceiptLogoBytes     = 512 << 10
+	maxReceiptLogoDimension = 1000
+)
+
+// UpdatePartnerReceiptLogoInput represents input for setting the logo printed on a partner's receipts
+type UpdatePartnerReceiptLogoInput struct {
+	PartnerID uuid.UUID
+	Image     []byte // PNG or JPEG; empty removes the logo
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerReceiptLogoUseCase sets or removes the logo printed on a partner's receipts
+type UpdatePartnerReceiptLogoUseCase struct {
+	partnerRepo ports.PartnerRepository
+	store       ports.ObjectStore
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerReceiptLogoUseCase creates a new instance. Logos are kept
+// in store.
+func NewUpdatePartnerReceiptLogoUseCase(partnerRepo ports.PartnerRepository, store ports.ObjectStore, auditLogger ports.AuditLogger) *UpdatePartnerReceiptLogoUseCase {
+	return &UpdatePartnerReceiptLogoUseCase{
+		partnerRepo: partnerRepo,
+		store:       store,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute validates and stores the logo, then points the partner at it.
+// Logos are stored under their content hash, so a new logo never
+// overwrites one a cached receipt was rendered with.
+func (uc *UpdatePartnerReceiptLogoUseCase) Execute(ctx context.Context, input UpdatePartnerReceiptLogoInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and store the logo
+	key := ""
+	if len(input.Image) > 0 {
+		if err := validateReceiptLogo(input.Image); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(input.Image)
+		key = fmt.Sprintf("branding/%s/logo-%s", partner.ID, hex.EncodeToString(sum[:8]))
+		if err := uc.store.Put(ctx, key, input.Image); err != nil {
+			return nil, fmt.Errorf("failed to store receipt logo: %w", err)
+		}
+	}
+
+	previous := partner.ReceiptLogoKey
+	partner.SetReceiptLogo(key)
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		action := "partner_receipt_logo_updated"
+		if key == "" {
+			action = "partner_receipt_logo_removed"
+		}
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       action,
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"previous_receipt_logo_key": previous,
+				"receipt_logo_key":          key,
+			},
+		})
+	}
+
+	return partner, nil
+}
+
+// validateReceiptLogo checks that data is a PNG or JPEG image small enough
+// to print on every receipt
+func validateReceiptLogo(data []byte) error {
+	if len(data) > maxReceiptLogoBytes {
+		return errors.NewValidationError("image", fmt.Sprintf("must be at most %d KB", maxReceiptLogoBytes>>10))
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return errors.NewValidationError("image", "must be a PNG or JPEG image")
+	}
+	if config.Width == 0 || config.Height == 0 || config.Width > maxReceiptLogoDimension || config.Height > maxReceiptLogoDimension {
+		return errors.NewValidationError("image", fmt.Sprintf("must be at most %dx%d pixels", maxReceiptLogoDimension, maxReceiptLogoDimension))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerRoundingPolicyInput represents input for changing how a partner's amounts are rounded
+type UpdatePartnerRoundingPolicyInput struct {
+	PartnerID uuid.UUID
+	Policy    string // half_up, half_even or truncate
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerRoundingPolicyUseCase sets how a partner's fees, taxes and divided amounts are rounded
+type UpdatePartnerRoundingPolicyUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerRoundingPolicyUseCase creates a new instance
+func NewUpdatePartnerRoundingPolicyUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerRoundingPolicyUseCase {
+	return &UpdatePartnerRoundingPolicyUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute changes the partner's rounding policy
+func (uc *UpdatePartnerRoundingPolicyUseCase) Execute(ctx context.Context, input UpdatePartnerRoundingPolicyInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply rounding policy
+	previous := partner.RoundingPolicy
+	if err := partner.SetRoundingPolicy(input.Policy); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_rounding_policy_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":        input.AdminID,
+				"previous_policy": previous,
+				"rounding_policy": partner.RoundingPolicy,
+			},
+		})
+	}
+
+	return partner, nil
+}
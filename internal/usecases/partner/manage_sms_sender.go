@@ -0,0 +1,77 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerSMSSenderInput represents input for setting who a partner's text messages come from
+type UpdatePartnerSMSSenderInput struct {
+	PartnerID uuid.UUID
+	SMSSender string // Phone number, sender name or Messaging Service SID; empty for the platform's sender
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerSMSSenderUseCase sets the sender of the text messages a partner's customers receive
+type UpdatePartnerSMSSenderUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerSMSSenderUseCase creates a new instance
+func NewUpdatePartnerSMSSenderUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerSMSSenderUseCase {
+	return &UpdatePartnerSMSSenderUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute changes the partner's SMS sender
+func (uc *UpdatePartnerSMSSenderUseCase) Execute(ctx context.Context, input UpdatePartnerSMSSenderInput) (*entities.Partner, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 2: Validate and apply sender
+	previous := partner.SMSSenderID
+	if err := partner.SetSMSSenderID(input.SMSSender); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_sms_sender_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":            input.AdminID,
+				"previous_sms_sender": previous,
+				"sms_sender":          partner.SMSSenderID,
+			},
+		})
+	}
+
+	return partner, nil
+}
@@ -0,0 +1,99 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// UpdatePartnerWebhookInput represents input for changing where a partner's webhooks are delivered
+type UpdatePartnerWebhookInput struct {
+	PartnerID uuid.UUID
+	URL       string // Empty removes the webhook
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdatePartnerWebhookOutput carries the partner and, when one was issued,
+// its new webhook secret
+type UpdatePartnerWebhookOutput struct {
+	Partner *entities.Partner
+	Secret  string
+}
+
+// UpdatePartnerWebhookUseCase sets or removes a partner's webhook URL
+type UpdatePartnerWebhookUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdatePartnerWebhookUseCase creates a new instance
+func NewUpdatePartnerWebhookUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *UpdatePartnerWebhookUseCase {
+	return &UpdatePartnerWebhookUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute changes the partner's webhook URL, issuing a webhook secret if
+// the partner has none, or removes the webhook when input.URL is empty. A
+// partner already in that state is left alone, whatever version ctx
+// expects, so a retried request succeeds.
+func (uc *UpdatePartnerWebhookUseCase) Execute(ctx context.Context, input UpdatePartnerWebhookInput) (*UpdatePartnerWebhookOutput, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	if partner.WebhookURL == input.URL {
+		return &UpdatePartnerWebhookOutput{Partner: partner}, nil
+	}
+
+	// Step 2: Validate and apply webhook
+	previous := partner.WebhookURL
+	secret := ""
+	if input.URL == "" {
+		partner.RemoveWebhook()
+	} else if secret, err = partner.ConfigureWebhook(input.URL); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		action := "partner_webhook_updated"
+		if input.URL == "" {
+			action = "partner_webhook_removed"
+		}
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       action,
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":             input.AdminID,
+				"previous_webhook_url": previous,
+				"webhook_url":          partner.WebhookURL,
+				"secret_issued":        secret != "",
+			},
+		})
+	}
+
+	return &UpdatePartnerWebhookOutput{Partner: partner, Secret: secret}, nil
+}
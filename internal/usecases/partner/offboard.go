@@ -0,0 +1,236 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// OffboardPartnerInput represents input for removing a partner from the platform
+type OffboardPartnerInput struct {
+	PartnerID uuid.UUID
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// OffboardPartnerOutput summarizes what off-boarding changed
+type OffboardPartnerOutput struct {
+	Partner        *entities.Partner
+	RevokedAPIKeys int
+}
+
+// OffboardPartnerUseCase shuts a partner's account down and schedules
+// anonymization of their customers' personal data
+type OffboardPartnerUseCase struct {
+	partnerRepo ports.PartnerRepository
+	apiKeyRepo  ports.APIKeyRepository
+	auditLogger ports.AuditLogger
+
+	// retention is how long customer PII is kept after off-boarding
+	retention time.Duration
+}
+
+// NewOffboardPartnerUseCase creates a new instance
+func NewOffboardPartnerUseCase(
+	partnerRepo ports.PartnerRepository,
+	apiKeyRepo ports.APIKeyRepository,
+	auditLogger ports.AuditLogger,
+	retention time.Duration,
+) *OffboardPartnerUseCase {
+	return &OffboardPartnerUseCase{
+		partnerRepo: partnerRepo,
+		apiKeyRepo:  apiKeyRepo,
+		auditLogger: auditLogger,
+		retention:   retention,
+	}
+}
+
+// Execute revokes the partner's API keys, stops their webhooks and soft-deletes them
+func (uc *OffboardPartnerUseCase) Execute(ctx context.Context, input OffboardPartnerInput) (*OffboardPartnerOutput, error) {
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Keys are revoked before the partner is saved, so a stale version must
+	// be turned down before then
+	if version, ok := ports.ExpectedVersion(ctx); ok && version != partner.Version {
+		return nil, errors.ErrPreconditionFailed
+	}
+
+	// Step 2: Revoke every active API key
+	keys, err := uc.apiKeyRepo.ListByPartnerID(ctx, partner.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	revoked := 0
+	for _, key := range keys {
+		if key.IsRevoked() {
+			continue
+		}
+		if err := key.Revoke(); err != nil {
+			return nil, err
+		}
+		if err := uc.apiKeyRepo.Update(ctx, key); err != nil {
+			return nil, fmt.Errorf("failed to revoke API key: %w", err)
+		}
+		revoked++
+	}
+
+	// Step 3: Stop webhooks, soft-delete and schedule anonymization
+	if err := partner.Offboard(uc.retention); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_offboarded",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":         input.AdminID,
+				"revoked_api_keys": revoked,
+				"anonymize_after":  partner.AnonymizeAfter,
+			},
+		})
+	}
+
+	return &OffboardPartnerOutput{
+		Partner:        partner,
+		RevokedAPIKeys: revoked,
+	}, nil
+}
+
+// AnonymizeCustomerDataUseCase erases customer PII of off-boarded partners
+// whose retention period has ended. It is meant to run periodically.
+//
+// It anonymizes transactions and the notes of refunds, deletes the
+// partner's outbox events, whose webhooks stopped at off-boarding, and
+// deletes their export files. Left as they are:
+//   - the audit log, whose entries are hash-chained and cannot change
+//     without failing verification; they record actions on the partner's
+//     resources and refer to transactions and refunds by ID only
+//   - outbox events moved to the event archive; their payloads carry IDs,
+//     amounts, statuses and provider codes, the fields kept on anonymized
+//     transactions, never customer details or refund notes
+//   - export job rows, which hold the filters and row count of an export,
+//     not its rows
+type AnonymizeCustomerDataUseCase struct {
+	partnerRepo     ports.PartnerRepository
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	outboxRepo      ports.OutboxRepository
+	exportStore     ports.ObjectStore
+	auditLogger     ports.AuditLogger
+}
+
+// NewAnonymizeCustomerDataUseCase creates a new instance
+func NewAnonymizeCustomerDataUseCase(
+	partnerRepo ports.PartnerRepository,
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	outboxRepo ports.OutboxRepository,
+	exportStore ports.ObjectStore,
+	auditLogger ports.AuditLogger,
+) *AnonymizeCustomerDataUseCase {
+	return &AnonymizeCustomerDataUseCase{
+		partnerRepo:     partnerRepo,
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		outboxRepo:      outboxRepo,
+		exportStore:     exportStore,
+		auditLogger:     auditLogger,
+	}
+}
+
+// Execute anonymizes every partner that is due and returns how many were processed
+func (uc *AnonymizeCustomerDataUseCase) Execute(ctx context.Context) (int, error) {
+	// Step 1: Find partners past their retention period
+	partnerIDs, err := uc.partnerRepo.ListDueForAnonymization(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for i, partnerID := range partnerIDs {
+		// Step 2: Erase customer PII (safe to repeat if marking fails below)
+		changes, err := uc.anonymize(ctx, partnerID)
+		if err != nil {
+			return i, err
+		}
+
+		// Step 3: Record completion
+		if err := uc.partnerRepo.MarkAnonymized(ctx, partnerID, time.Now()); err != nil {
+			return i, err
+		}
+
+		// Step 4: Log audit event
+		if uc.auditLogger != nil {
+			_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+				PartnerID:    partnerID,
+				Action:       "partner_customer_data_anonymized",
+				ResourceType: "partner",
+				ResourceID:   partnerID,
+				Changes:      changes,
+			})
+		}
+	}
+
+	return len(partnerIDs), nil
+}
+
+// anonymize erases a partner's customer PII from every store holding it and
+// returns how much changed in each
+func (uc *AnonymizeCustomerDataUseCase) anonymize(ctx context.Context, partnerID uuid.UUID) (map[string]interface{}, error) {
+	transactions, err := uc.transactionRepo.AnonymizeCustomerData(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := uc.refundRepo.AnonymizeCustomerData(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.outboxRepo.DeleteByPartner(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := uc.exportStore.List(ctx, fmt.Sprintf("exports/%s/", partnerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list export files: %w", err)
+	}
+	for _, key := range keys {
+		if err := uc.exportStore.Delete(ctx, key); err != nil {
+			return nil, fmt.Errorf("failed to delete export file: %w", err)
+		}
+	}
+
+	return map[string]interface{}{
+		"transactions":  transactions,
+		"refunds":       refunds,
+		"outbox_events": events,
+		"export_files":  len(keys),
+	}, nil
+}
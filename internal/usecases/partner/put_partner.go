@@ -0,0 +1,210 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// defaultRateLimitPerMinute is the rate limit of a partner that does not name one
+const defaultRateLimitPerMinute = 100
+
+// PutPartnerInput represents the desired state of a partner, identified by
+// an ID chosen by the caller
+type PutPartnerInput struct {
+	PartnerID          uuid.UUID
+	Name               string
+	Email              string
+	Active             bool
+	RateLimitPerMinute int // 0 uses the default
+	// RefundApprovalThresholds maps ISO 4217 codes to decimal amounts above
+	// which refunds need manual approval; empty disables approval
+	RefundApprovalThresholds map[string]string
+	RefundWindowDays         int  // 0 uses the platform default
+	CreateOnly               bool // Fail with ErrPreconditionFailed if the partner exists
+	UpdateOnly               bool // Fail with ErrPreconditionFailed if it does not
+	AdminID                  string
+	IPAddress                string
+	UserAgent                string
+}
+
+// PutPartnerOutput carries the partner and whether it was created
+type PutPartnerOutput struct {
+	Partner *entities.Partner
+	Created bool
+}
+
+// PutPartnerUseCase creates or updates a partner at an ID chosen by the
+// caller, so that infrastructure-as-code tools can apply the same
+// configuration again and again
+type PutPartnerUseCase struct {
+	partnerRepo ports.PartnerRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewPutPartnerUseCase creates a new instance
+func NewPutPartnerUseCase(partnerRepo ports.PartnerRepository, auditLogger ports.AuditLogger) *PutPartnerUseCase {
+	return &PutPartnerUseCase{
+		partnerRepo: partnerRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute creates the partner, or brings the existing one to the input's
+// state. A partner already in that state is left alone, whatever version
+// ctx expects, so a retried request succeeds.
+func (uc *PutPartnerUseCase) Execute(ctx context.Context, input PutPartnerInput) (*PutPartnerOutput, error) {
+	if input.RateLimitPerMinute == 0 {
+		input.RateLimitPerMinute = defaultRateLimitPerMinute
+	}
+
+	// Step 1: Retrieve partner
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil && err != errors.ErrPartnerNotFound {
+		return nil, err
+	}
+	exists := err == nil && partner != nil
+
+	_, expected := ports.ExpectedVersion(ctx)
+	switch {
+	case exists && input.CreateOnly:
+		return nil, errors.ErrPreconditionFailed
+	case !exists && (input.UpdateOnly || expected):
+		return nil, errors.ErrPreconditionFailed
+	}
+
+	// Step 2: The email identifies a partner too
+	if other, err := uc.partnerRepo.GetByEmail(ctx, input.Email); err == nil && other != nil && other.ID != input.PartnerID {
+		return nil, errors.ErrPartnerExists
+	} else if err != nil && err != errors.ErrPartnerNotFound {
+		return nil, err
+	}
+
+	// Step 3: Create, or validate and apply changes
+	if !exists {
+		return uc.create(ctx, input)
+	}
+
+	if samePartnerState(partner, input) {
+		return &PutPartnerOutput{Partner: partner}, nil
+	}
+
+	previous := partnerState(partner)
+	if err := applyPartnerState(partner, input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partnerRepo.Update(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to update partner: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_updated",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id": input.AdminID,
+				"previous": previous,
+				"partner":  partnerState(partner),
+			},
+		})
+	}
+
+	return &PutPartnerOutput{Partner: partner}, nil
+}
+
+// create creates the partner of input
+func (uc *PutPartnerUseCase) create(ctx context.Context, input PutPartnerInput) (*PutPartnerOutput, error) {
+	partner, err := entities.NewPartner(input.Name, input.Email)
+	if err != nil {
+		return nil, err
+	}
+	partner.ID = input.PartnerID
+
+	if err := applyPartnerState(partner, input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.partnerRepo.Create(ctx, partner); err != nil {
+		return nil, fmt.Errorf("failed to create partner: %w", err)
+	}
+
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    partner.ID,
+			Action:       "partner_created",
+			ResourceType: "partner",
+			ResourceID:   partner.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id": input.AdminID,
+				"partner":  partnerState(partner),
+			},
+		})
+	}
+
+	return &PutPartnerOutput{Partner: partner, Created: true}, nil
+}
+
+// applyPartnerState validates and applies the state of input to partner
+func applyPartnerState(partner *entities.Partner, input PutPartnerInput) error {
+	if err := partner.SetContact(input.Name, input.Email); err != nil {
+		return err
+	}
+	if err := partner.SetRateLimit(input.RateLimitPerMinute); err != nil {
+		return err
+	}
+	if err := partner.SetRefundApprovalThresholds(input.RefundApprovalThresholds); err != nil {
+		return err
+	}
+	if err := partner.SetRefundWindowDays(input.RefundWindowDays); err != nil {
+		return err
+	}
+
+	if input.Active {
+		partner.Activate()
+	} else {
+		partner.Deactivate()
+	}
+	return nil
+}
+
+// samePartnerState reports whether partner is already in the state of input
+func samePartnerState(partner *entities.Partner, input PutPartnerInput) bool {
+	thresholds, err := valueobjects.ParseCurrencyAmounts("refund_approval_thresholds", input.RefundApprovalThresholds)
+	if err != nil {
+		return false
+	}
+	return partner.Name == input.Name &&
+		partner.Email == input.Email &&
+		partner.IsActive == input.Active &&
+		partner.RateLimitPerMinute == input.RateLimitPerMinute &&
+		maps.Equal(partner.RefundApprovalThresholds, thresholds) &&
+		partner.RefundWindowDays == input.RefundWindowDays
+}
+
+// partnerState is the part of partner PutPartnerUseCase manages, for the
+// audit log
+func partnerState(partner *entities.Partner) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                       partner.Name,
+		"email":                      partner.Email,
+		"is_active":                  partner.IsActive,
+		"rate_limit_per_minute":      partner.RateLimitPerMinute,
+		"refund_approval_thresholds": partner.RefundApprovalThresholds.Decimals(),
+		"refund_window_days":         partner.RefundWindowDays,
+	}
+}
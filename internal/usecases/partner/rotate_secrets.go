@@ -0,0 +1,35 @@
+package partner
+
+import (
+	"context"
+	"fmt"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// RotateSecretsUseCase re-encrypts stored secrets with the current encryption key
+type RotateSecretsUseCase struct {
+	rotators []ports.SecretRotator
+}
+
+// NewRotateSecretsUseCase creates a new instance
+func NewRotateSecretsUseCase(rotators ...ports.SecretRotator) *RotateSecretsUseCase {
+	return &RotateSecretsUseCase{
+		rotators: rotators,
+	}
+}
+
+// Execute rotates every store and returns how many secrets were re-encrypted.
+// It is safe to run again after a failure; finished rows are skipped.
+func (uc *RotateSecretsUseCase) Execute(ctx context.Context) (int, error) {
+	total := 0
+	for _, rotator := range uc.rotators {
+		rotated, err := rotator.RotateSecrets(ctx)
+		total += rotated
+		if err != nil {
+			return total, fmt.Errorf("failed to rotate secrets: %w", err)
+		}
+	}
+
+	return total, nil
+}
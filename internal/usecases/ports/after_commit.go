@@ -0,0 +1,50 @@
+package ports
+
+import (
+	"context"
+	"sync"
+)
+
+// afterCommitKey is the context key of the hooks of a running unit of work
+type afterCommitKey struct{}
+
+// AfterCommitHooks are the functions to run once a unit of work commits.
+// UnitOfWork implementations create them with WithAfterCommit.
+type AfterCommitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// WithAfterCommit returns ctx carrying a fresh set of hooks, for a unit of
+// work starting a transaction to run with Run once it commits and drop if
+// it rolls back
+func WithAfterCommit(ctx context.Context) (context.Context, *AfterCommitHooks) {
+	hooks := &AfterCommitHooks{}
+	return context.WithValue(ctx, afterCommitKey{}, hooks), hooks
+}
+
+// Run calls the hooks in the order they were added
+func (h *AfterCommitHooks) Run() {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// AfterCommit calls fn once the unit of work running in ctx commits, or
+// right away outside one, where whatever was written is committed already.
+// fn is never called if the unit of work rolls back.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*AfterCommitHooks)
+	if !ok {
+		fn()
+		return
+	}
+	hooks.mu.Lock()
+	hooks.fns = append(hooks.fns, fn)
+	hooks.mu.Unlock()
+}
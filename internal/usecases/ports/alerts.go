@@ -0,0 +1,17 @@
+package ports
+
+import "context"
+
+// Alert is an operational problem people should look at, such as the
+// database answering slowly for minutes
+type Alert struct {
+	Title string
+	Text  string
+	// Fields are details shown with the alert, such as the threshold
+	Fields map[string]string
+}
+
+// AlertSink delivers alerts to the people on call, such as a Slack channel
+type AlertSink interface {
+	Send(ctx context.Context, alert Alert) error
+}
@@ -0,0 +1,59 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsRollup is the payments of a partner on one UTC day, in one mode,
+// with one currency, provider and payment method, rolled up from the
+// transactions and refunds so analytics never scan them
+type AnalyticsRollup struct {
+	PartnerID uuid.UUID
+	// Day is midnight UTC of the day
+	Day           time.Time
+	Livemode      bool
+	Currency      string
+	Provider      string
+	PaymentMethod string
+
+	// SucceededPayments and FailedPayments count the payments made on the
+	// day that succeeded or failed; Volume is the amount of the succeeded
+	// ones in minor units
+	SucceededPayments int64
+	FailedPayments    int64
+	Volume            int64
+
+	// Refunds count the completed refunds made on the day of payments with
+	// the currency, provider and payment method, and RefundedVolume their
+	// amount in minor units
+	Refunds        int64
+	RefundedVolume int64
+}
+
+// AnalyticsQuery specifies which rollups to list
+type AnalyticsQuery struct {
+	PartnerID uuid.UUID
+	Livemode  bool
+	// From and To are UTC days, both inclusive
+	From time.Time
+	To   time.Time
+	// Currency narrows the rollups to one currency when set
+	Currency string
+}
+
+// AnalyticsRepository stores the daily analytics rollups. They are rebuilt
+// rather than added to, so rolling up a day again replaces it.
+type AnalyticsRepository interface {
+	// ReplaceRollups replaces partnerID's rollups of the days from from to
+	// to, both inclusive, with rollups in one database transaction
+	ReplaceRollups(ctx context.Context, partnerID uuid.UUID, from, to time.Time, rollups []AnalyticsRollup) error
+	// ListRollups returns the rollups matching query ordered by day,
+	// currency, provider and payment method
+	ListRollups(ctx context.Context, query AnalyticsQuery) ([]AnalyticsRollup, error)
+	// LatestDay returns the latest day any partner has rollups of, and
+	// false when there are none
+	LatestDay(ctx context.Context) (time.Time, bool, error)
+}
@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// ObjectStore stores blobs by key, such as the objects of an S3 bucket
+type ObjectStore interface {
+	// Put stores data under key, replacing what was there
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Get returns the data stored under key
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// List returns the keys starting with prefix, in lexical order
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Delete removes the object key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// EventArchive keeps the audit entries and published outbox events moved out
+// of the database once they are old. Archiving the same entries or events
+// again replaces them, so a move interrupted before the rows were deleted can
+// simply be repeated.
+type EventArchive interface {
+	// ArchiveAuditLogs stores audit entries as they were read from the database
+	ArchiveAuditLogs(ctx context.Context, entries []*AuditLogEntry) error
+
+	// ListAuditLogs is AuditLogRepository.List for the archived entries
+	ListAuditLogs(ctx context.Context, q AuditLogQuery) ([]*AuditLogEntry, int64, error)
+
+	// ListAuditChain is AuditLogRepository.ListChain for the archived entries
+	ListAuditChain(ctx context.Context, partnerID uuid.UUID, afterID int64, limit int) ([]*AuditLogEntry, error)
+
+	// ListAuditChainPartnerIDs returns the partners with archived entries
+	ListAuditChainPartnerIDs(ctx context.Context) ([]uuid.UUID, error)
+
+	// ArchiveOutboxEvents stores published outbox events
+	ArchiveOutboxEvents(ctx context.Context, events []*entities.OutboxEvent) error
+}
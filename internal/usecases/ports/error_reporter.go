@@ -0,0 +1,20 @@
+package ports
+
+import "context"
+
+// ErrorReport is an error worth alerting on, with tags saying where it
+// happened, such as the partner, transaction and provider
+type ErrorReport struct {
+	Err error
+	// Panic holds what a recovered panic was raised with, and Stack where
+	Panic interface{}
+	Stack []byte
+	Tags  map[string]string
+}
+
+// ErrorReporter sends errors to an error tracker, such as Sentry. Reports
+// are tagged with the ID of the request in ctx. Report must not block the
+// caller, so reports may be sampled or dropped.
+type ErrorReporter interface {
+	Report(ctx context.Context, report ErrorReport)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// EventPublisher publishes outbox events to a message broker, such as Kafka,
+// for downstream systems like analytics and risk to consume the payment
+// stream
+type EventPublisher interface {
+	// PublishEvent publishes event. An error leaves the event to be published
+	// again later, so consumers must tolerate receiving it more than once.
+	PublishEvent(ctx context.Context, event *entities.OutboxEvent) error
+}
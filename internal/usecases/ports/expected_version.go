@@ -0,0 +1,21 @@
+package ports
+
+import "context"
+
+// expectedVersionKey is the context key of ExpectVersion
+type expectedVersionKey struct{}
+
+// ExpectVersion marks ctx as changing a resource on behalf of a client that
+// last saw it at version, as named by an If-Match header. Repositories then
+// save only over that version and fail with errors.ErrPreconditionFailed
+// otherwise, so a change made since the client read the resource is never
+// overwritten.
+func ExpectVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersion returns the version ctx was marked with by ExpectVersion
+func ExpectedVersion(ctx context.Context) (int, bool) {
+	version, ok := ctx.Value(expectedVersionKey{}).(int)
+	return version, ok
+}
@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"io"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// ExportFile writes the rows of one export file
+type ExportFile interface {
+	// WriteTransaction adds a transaction to a file of transactions
+	WriteTransaction(txn *entities.Transaction) error
+
+	// WriteRefund adds a refund to a file of refunds
+	WriteRefund(refund *entities.Refund) error
+
+	// Close writes out what is buffered
+	Close() error
+}
+
+// ExportEncoder writes export files with transactions and refunds as the
+// API represents them
+type ExportEncoder interface {
+	// NewFile starts a file of resource in format and layout, written to
+	// w, failing if the layout does not fit the resource
+	NewFile(w io.Writer, resource entities.ExportResource, format entities.ExportFormat, layout entities.ExportLayout) (ExportFile, error)
+}
+
+// AccountingFile writes the entries of a file imported by an accounting
+// system
+type AccountingFile interface {
+	ExportFile
+
+	// WriteStatementFees adds the fees a statement charges, an entry per
+	// fee tier and chargeback fees of each currency
+	WriteStatementFees(statement *entities.Statement) error
+}
+
+// AccountingEncoder writes export files in the import formats of
+// accounting systems
+type AccountingEncoder interface {
+	// NewFile starts a file in format, one of the accounting formats,
+	// posting to accounts with dates in location, written to w
+	NewFile(w io.Writer, format entities.ExportFormat, accounts valueobjects.AccountMapping, location *time.Location) (AccountingFile, error)
+}
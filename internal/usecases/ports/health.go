@@ -0,0 +1,31 @@
+package ports
+
+import "context"
+
+// HealthStatus is the state of one dependency of the API
+type HealthStatus string
+
+const (
+	// HealthUp means the dependency works normally
+	HealthUp HealthStatus = "up"
+	// HealthDegraded means the dependency works but is slow or behind; the
+	// API can still serve requests
+	HealthDegraded HealthStatus = "degraded"
+	// HealthDown means requests that need the dependency fail
+	HealthDown HealthStatus = "down"
+)
+
+// ComponentHealth is the outcome of checking one dependency
+type ComponentHealth struct {
+	Status HealthStatus
+	// Error explains any status but HealthUp
+	Error string
+	// Details are measurements such as versions, lag or queue depth
+	Details map[string]interface{}
+}
+
+// HealthChecker checks one dependency of the API. It returns once ctx is
+// done at the latest.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) ComponentHealth
+}
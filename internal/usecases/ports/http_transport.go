@@ -0,0 +1,14 @@
+package ports
+
+// HTTPTransportStats is the load of the HTTP transport outbound integrations
+// share. Connections opened growing as fast as requests means connections
+// are not being reused, so the idle pool is too small.
+type HTTPTransportStats struct {
+	Requests int64
+	// Failed counts requests that got no response at all
+	Failed int64
+	// InFlight counts requests waiting for their response headers
+	InFlight          int64
+	ConnectionsOpened int64
+	ConnectionsReused int64
+}
@@ -0,0 +1,67 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// Email is a message to one recipient, with a plain text body and, for
+// clients that show it, an HTML one
+type Email struct {
+	To          string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []EmailAttachment
+}
+
+// EmailAttachment is a file attached to an email
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// EmailSender delivers emails, such as through an SMTP server or Amazon SES
+type EmailSender interface {
+	SendEmail(ctx context.Context, email Email) error
+}
+
+// SMS is a text message to one phone number
+type SMS struct {
+	// From is a phone number, sender name or Messaging Service SID; empty
+	// uses the provider account's default sender
+	From string
+	To   string // E.164 form
+	Body string
+}
+
+// SMSSender delivers text messages, such as through Twilio
+type SMSSender interface {
+	SendSMS(ctx context.Context, sms SMS) error
+}
+
+// VerificationCodeStore keeps the hashes of one-time codes until they are
+// used or expire. Implementations backed by shared storage let a code sent
+// by one API instance be checked by another.
+type VerificationCodeStore interface {
+	// Save keeps hash for key until ttl passes, replacing any hash it had
+	Save(ctx context.Context, key, hash string, ttl time.Duration) error
+
+	// Get returns the hash kept for key, or "" when there is none
+	Get(ctx context.Context, key string) (string, error)
+
+	// Delete removes the hash kept for key, so its code cannot be used again
+	Delete(ctx context.Context, key string) error
+}
+
+// OutboxNotifier is told about the outbox events the relay is done with, to
+// notify people of them. Deliveries wait on it, so it must not block.
+type OutboxNotifier interface {
+	// Published is called after event was published
+	Published(ctx context.Context, event *entities.OutboxEvent)
+	// Exhausted is called after the relay gave up delivering event
+	Exhausted(ctx context.Context, event *entities.OutboxEvent)
+}
@@ -0,0 +1,64 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// ProviderEventType is what a payment provider's notification reports
+type ProviderEventType string
+
+const (
+	// ProviderEventPaymentSucceeded reports a payment the provider captured
+	ProviderEventPaymentSucceeded ProviderEventType = "payment_succeeded"
+	// ProviderEventPaymentFailed reports a payment the provider declined
+	ProviderEventPaymentFailed ProviderEventType = "payment_failed"
+	// ProviderEventRefundSucceeded reports a refund the provider made
+	ProviderEventRefundSucceeded ProviderEventType = "refund_succeeded"
+)
+
+// ProviderEvent is a notification a payment provider sent about a payment
+// or a refund
+type ProviderEvent struct {
+	Provider valueobjects.PaymentProvider
+	// ID identifies the notification at the provider; every delivery of it
+	// has the same ID
+	ID   string
+	Type ProviderEventType
+
+	// TransactionID and RefundID are Pay2Go's IDs the provider echoes back,
+	// such as Stripe metadata or an Adyen merchant reference; uuid.Nil when
+	// it did not
+	TransactionID uuid.UUID
+	RefundID      uuid.UUID
+
+	ProviderTransactionID string
+	ProviderRefundID      string
+	// Reason is why a payment failed, as the provider put it
+	Reason string
+}
+
+// ProviderEventParser verifies and decodes the notifications of a payment
+// provider
+type ProviderEventParser interface {
+	// Parse returns the events of a notification with body and headers,
+	// failing with errors.ErrInvalidSignature unless the provider signed it.
+	// Events Pay2Go does not act on are left out.
+	Parse(body []byte, header func(name string) string) ([]ProviderEvent, error)
+}
+
+// ProviderEventStore remembers the provider events being or recently
+// handled, since providers deliver their notifications at least once
+type ProviderEventStore interface {
+	// Claim records that provider's event id is being handled and reports
+	// whether it was not already, within ttl, by any instance
+	Claim(ctx context.Context, provider valueobjects.PaymentProvider, id string, ttl time.Duration) (bool, error)
+
+	// Release forgets a claim whose handling failed, so the provider's
+	// next delivery of the event is handled
+	Release(ctx context.Context, provider valueobjects.PaymentProvider, id string) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import "context"
+
+// readOnlyKey is the context key marking read-only use cases
+type readOnlyKey struct{}
+
+// ReadOnly marks ctx as serving a read-only use case. Repositories may then
+// answer from a read replica, which can lag behind the primary by a moment;
+// use cases that read in order to write must not use it, or they would work
+// on stale data.
+func ReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether ctx was marked with ReadOnly
+func IsReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// Receipt is what the receipt of a paid transaction shows
+type Receipt struct {
+	// PartnerName and Logo brand the receipt; Logo is a PNG or JPEG image,
+	// nil when the partner has none
+	PartnerName string
+	Logo        []byte
+
+	// Locale formats the amounts
+	Locale valueobjects.Locale
+
+	Transaction *entities.Transaction
+
+	// Refunds are the transaction's completed refunds, oldest first
+	Refunds []*entities.Refund
+}
+
+// ReceiptRenderer lays out receipts as documents customers can keep
+type ReceiptRenderer interface {
+	// Render returns receipt as a PDF document. The same receipt always
+	// renders the same document.
+	Render(receipt Receipt) ([]byte, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// ReportFile is a file of a scheduled report
+type ReportFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// ReportUploader uploads scheduled reports to partners' SFTP servers and S3
+// buckets; emailed reports go through the EmailSender
+type ReportUploader interface {
+	// Upload writes file to destination, an SFTP or S3 destination of
+	// partnerID, replacing a file of the same name
+	Upload(ctx context.Context, partnerID uuid.UUID, destination valueobjects.ReportDestination, file ReportFile) error
+}
@@ -1,145 +1,878 @@
-package ports
 // Package ports defines interfaces (contracts) for the use case layer
 // This follows the Dependency Inversion Principle (SOLID)
 // Use cases depend on abstractions, not concretions
 package ports
 
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// TransactionRepository defines the contract for transaction persistence
+// Infrastructure layer will implement this interface
+type TransactionRepository interface {
+	// Create creates a new transaction
+	Create(ctx context.Context, transaction *entities.Transaction) error
+
+	// CreateBatch creates transactions with as few round-trips as possible.
+	// Either all are created or none: a duplicate idempotency key, also one
+	// repeated within transactions, returns ErrDuplicateTransaction.
+	CreateBatch(ctx context.Context, transactions []*entities.Transaction) error
+
+	// GetByID retrieves a transaction by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
+
+	// GetByIdempotencyKey retrieves a transaction by partner and idempotency key
+	GetByIdempotencyKey(ctx context.Context, partnerID uuid.UUID, idempotencyKey string) (*entities.Transaction, error)
+
+	// Update updates an existing transaction
+	Update(ctx context.Context, transaction *entities.Transaction) error
+
+	// Delete saves a transaction soft-deleted with SoftDelete, which hides it
+	// from every other method but Restore. It fails with a
+	// *errors.VersionConflictError if the transaction changed since it was loaded.
+	Delete(ctx context.Context, transaction *entities.Transaction) error
+
+	// Restore undoes Delete, returning ErrTransactionNotFound unless a deleted
+	// transaction has the ID
+	Restore(ctx context.Context, id uuid.UUID) error
+
+	// IncrementRetryCount moves a failed transaction back to pending and
+	// counts the retry in one atomic update, so concurrent retries can never
+	// take more than maxRetries between them. It returns ErrRetryNotAllowed
+	// unless the transaction is failed with fewer than maxRetries retries,
+	// and otherwise gives transaction the saved status, count and version.
+	IncrementRetryCount(ctx context.Context, transaction *entities.Transaction, maxRetries int) error
+
+	// List retrieves the transactions matching query, one page at a time,
+	// and the total number matching it regardless of paging
+	List(ctx context.Context, query TransactionQuery) ([]*entities.Transaction, int64, error)
+
+	// Stream calls fn with each transaction matching query, in its order and
+	// after its cursor, reading them as fn consumes them so memory stays flat
+	// however many match. Limit and Offset are ignored. fn must not use the
+	// database, whose connection the stream may be holding; an error from fn
+	// stops the stream and is returned.
+	Stream(ctx context.Context, query TransactionQuery, fn func(*entities.Transaction) error) error
+
+	// GetByPartnerID retrieves transactions for a specific partner
+	GetByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Transaction, error)
+
+	// GetByStatus retrieves transactions by status
+	GetByStatus(ctx context.Context, status entities.TransactionStatus, limit, offset int) ([]*entities.Transaction, error)
+
+	// AnonymizeCustomerData erases customer PII from all of a partner's transactions,
+	// payment method identifiers included as PaymentMethodDetails.Anonymized removes
+	// them, moves each to its next version and returns how many were changed;
+	// amounts and statuses are kept for accounting
+	AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error)
+
+	// GetProcessingSummary counts the transactions of a mode created since
+	// since that completed or failed, by provider, partner, outcome and how
+	// long processing took
+	GetProcessingSummary(ctx context.Context, since time.Time, livemode bool) ([]ProcessingSummary, error)
+}
+
+// ProcessingLatencyBounds are the upper bounds of the latency buckets of
+// ProcessingSummary, from creating a transaction to it completing or
+// failing. Slower transactions are in bucket len(ProcessingLatencyBounds).
+var ProcessingLatencyBounds = []time.Duration{
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+}
+
+// ProcessingLatencyBucket returns the bucket of latency in
+// ProcessingLatencyBounds
+func ProcessingLatencyBucket(latency time.Duration) int {
+	for i, bound := range ProcessingLatencyBounds {
+		if latency <= bound {
+			return i
+		}
+	}
+	return len(ProcessingLatencyBounds)
+}
+
+// ProcessingSummary counts the processed transactions sharing a provider,
+// partner, outcome and latency bucket
+type ProcessingSummary struct {
+	Provider      valueobjects.PaymentProvider
+	PartnerID     uuid.UUID
+	Succeeded     bool
+	LatencyBucket int
+	Count         int64
+}
+
+// TransactionQuery specifies which transactions to list and in what order.
+// Zero-valued fields do not filter; all set fields must match.
+type TransactionQuery struct {
+	PartnerID *uuid.UUID
+	Livemode  *bool
+
+	// Statuses matches any of the given statuses
+	Statuses []entities.TransactionStatus
+
+	// Currency matches one currency; MinAmount and MaxAmount (inclusive, in
+	// its minor units) need it, since amounts in different currencies do not
+	// compare
+	Currency  *valueobjects.Currency
+	MinAmount *int64
+	MaxAmount *int64
+
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+
+	// Provider and ProviderTransactionID match the transactions a provider
+	// reports under its own ID, as in settlement files
+	Provider              *valueobjects.PaymentProvider
+	ProviderTransactionID string
+
+	// Metadata matches transactions whose metadata has every given key set
+	// to the given value
+	Metadata map[string]string
+
+	// OrderBy defaults to TransactionOrderCreatedAt, newest first
+	OrderBy   TransactionOrder
+	Ascending bool
+
+	// After is a keyset cursor: only transactions after this one in the
+	// chosen order are listed. Prefer it to Offset for deep pages.
+	After *uuid.UUID
+
+	Limit  int
+	Offset int
+}
+
+// TransactionOrder is a column transactions can be listed by
+type TransactionOrder string
+
+const (
+	TransactionOrderCreatedAt TransactionOrder = "created_at"
+	TransactionOrderAmount    TransactionOrder = "amount"
+)
+
+// IsValid reports whether transactions can be ordered by o
+func (o TransactionOrder) IsValid() bool {
+	return o == TransactionOrderCreatedAt || o == TransactionOrderAmount
+}
+
+// PartnerRepository defines the contract for partner persistence
+type PartnerRepository interface {
+	// Create creates a new partner
+	Create(ctx context.Context, partner *entities.Partner) error
+
+	// GetByID retrieves a partner by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Partner, error)
+
+	// GetByEmail retrieves a partner by email
+	GetByEmail(ctx context.Context, email string) (*entities.Partner, error)
+
+	// GetByAPIKeyPrefix retrieves a partner by API key prefix
+	GetByAPIKeyPrefix(ctx context.Context, prefix string) (*entities.Partner, error)
+
+	// Update updates an existing partner. It fails with a
+	// *errors.VersionConflictError if the partner changed or was deleted since
+	// it was loaded, or with errors.ErrPreconditionFailed if it is not at the
+	// version ctx expects (see ExpectVersion).
+	Update(ctx context.Context, partner *entities.Partner) error
+
+	// List retrieves all partners with pagination
+	List(ctx context.Context, limit, offset int) ([]*entities.Partner, error)
+
+	// ListWithTotal retrieves a page of partners and the total number of partners
+	ListWithTotal(ctx context.Context, limit, offset int) ([]*entities.Partner, int64, error)
+
+	// ListDueForAnonymization returns off-boarded partners whose retention period
+	// ended before now and whose customer data has not been anonymized yet
+	ListDueForAnonymization(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+
+	// MarkAnonymized records that a partner's customer data was anonymized
+	MarkAnonymized(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// APIKeyRepository defines the contract for partner API key persistence
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, key *entities.APIKey) error
+
+	// GetByID retrieves an API key by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error)
 
+	// GetByPrefix retrieves an API key by its 8-character prefix
+	GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error)
 
+	// ListByPartnerID retrieves all API keys of a partner, including revoked ones
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.APIKey, error)
 
+	// Update updates an existing API key
+	Update(ctx context.Context, key *entities.APIKey) error
 
+	// RecordUsage stores when and from where a key was last used,
+	// never moving last_used_at backwards
+	RecordUsage(ctx context.Context, id uuid.UUID, usedAt time.Time, ipAddress string) error
+}
 
+// ProviderCredentialRepository defines the contract for partners' own provider account persistence
+type ProviderCredentialRepository interface {
+	// Save creates or replaces the partner's credential for its provider
+	Save(ctx context.Context, credential *entities.ProviderCredential) error
 
+	// GetByPartnerAndProvider retrieves a partner's credential for a provider,
+	// returning ErrProviderCredentialNotFound if the partner uses the platform account
+	GetByPartnerAndProvider(ctx context.Context, partnerID uuid.UUID, provider valueobjects.PaymentProvider) (*entities.ProviderCredential, error)
 
+	// ListByPartnerID retrieves all credentials of a partner
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.ProviderCredential, error)
 
+	// Delete removes a partner's credential for a provider
+	Delete(ctx context.Context, partnerID uuid.UUID, provider valueobjects.PaymentProvider) error
+}
 
+// UserRepository defines the contract for partner team member persistence
+type UserRepository interface {
+	// Create creates a new user, returning ErrUserAlreadyExists if the email is taken
+	Create(ctx context.Context, user *entities.User) error
 
+	// GetByID retrieves a user by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 
+	// GetByEmail retrieves a user by email
+	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 
+	// ListByPartnerID retrieves a partner's team members
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*entities.User, error)
 
+	// CountByRole counts a partner's team members holding a role
+	CountByRole(ctx context.Context, partnerID uuid.UUID, role valueobjects.UserRole) (int, error)
 
+	// Update updates an existing user; a deleted user is left alone
+	Update(ctx context.Context, user *entities.User) error
+}
 
+// UserSessionRepository defines the contract for team member session persistence
+type UserSessionRepository interface {
+	// Create creates a new session
+	Create(ctx context.Context, session *entities.UserSession) error
 
+	// GetByTokenHash retrieves a session by the hash of its token
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entities.UserSession, error)
 
+	// Update updates an existing session
+	Update(ctx context.Context, session *entities.UserSession) error
+}
 
+// AdminUserRepository defines the contract for back-office admin persistence
+type AdminUserRepository interface {
+	// Create creates a new admin
+	Create(ctx context.Context, admin *entities.AdminUser) error
 
+	// GetByID retrieves an admin by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.AdminUser, error)
 
+	// GetByEmail retrieves an admin by email
+	GetByEmail(ctx context.Context, email string) (*entities.AdminUser, error)
+
+	// RecordLogin stores the accepted TOTP step and login time. It fails with
+	// ErrInvalidAdminCredentials if a concurrent login already used that step.
+	RecordLogin(ctx context.Context, admin *entities.AdminUser) error
+}
+
+// AdminSessionRepository defines the contract for admin session persistence
+type AdminSessionRepository interface {
+	// Create creates a new session
+	Create(ctx context.Context, session *entities.AdminSession) error
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	Changes      map[string]interface{}	RequestID    uuid.UUID	UserAgent    string	IPAddress    string	ResourceID   uuid.UUID	ResourceType string	Action       string	PartnerID    uuid.UUIDtype AuditAction struct {// AuditAction represents an audit log entry}	LogAction(ctx context.Context, action AuditAction) error	// LogAction logs an audit eventtype AuditLogger interface {// AuditLogger defines the contract for audit logging}	Exists(ctx context.Context, key string) (bool, error)	// Exists checks if key exists in cache		Delete(ctx context.Context, key string) error	// Delete removes a value from cache		Set(ctx context.Context, key string, value interface{}, ttlSeconds int) error	// Set stores a value in cache with TTL		Get(ctx context.Context, key string) (interface{}, error)	// Get retrieves a value from cachetype CacheService interface {// CacheService defines the contract for caching}	SendEmail(ctx context.Context, to, subject, body string) error	// SendEmail sends an email notification		SendWebhook(ctx context.Context, partnerWebhookURL string, payload interface{}) error	// SendWebhook sends a webhook notification to partnertype NotificationService interface {// NotificationService defines the contract for sending notifications}	GetProviderName() string	// GetProviderName returns the name of the payment provider		GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error)	// GetPaymentStatus checks payment status from provider		ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (providerRefundID string, err error)	// ProcessRefund processes a refund through the provider		ProcessPayment(ctx context.Context, transaction *entities.Transaction) (providerTransactionID string, err error)	// ProcessPayment processes a payment through the providertype PaymentGateway interface {// PaymentGateway defines the contract for payment provider integration}	GetTotalRefundedAmount(ctx context.Context, transactionID uuid.UUID) (float64, error)	// GetTotalRefundedAmount calculates total refunded amount for a transaction		Update(ctx context.Context, refund *entities.Refund) error	// Update updates an existing refund		GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error)	// GetByTransactionID retrieves all refunds for a transaction		GetByID(ctx context.Context, id uuid.UUID) (*entities.Refund, error)	// GetByID retrieves a refund by ID		Create(ctx context.Context, refund *entities.Refund) error	// Create creates a new refundtype RefundRepository interface {// RefundRepository defines the contract for refund persistence}	List(ctx context.Context, limit, offset int) ([]*entities.Partner, error)	// List retrieves all partners with pagination		Update(ctx context.Context, partner *entities.Partner) error	// Update updates an existing partner		GetByAPIKeyPrefix(ctx context.Context, prefix string) (*entities.Partner, error)	// GetByAPIKeyPrefix retrieves a partner by API key prefix		GetByEmail(ctx context.Context, email string) (*entities.Partner, error)	// GetByEmail retrieves a partner by email		GetByID(ctx context.Context, id uuid.UUID) (*entities.Partner, error)	// GetByID retrieves a partner by ID		Create(ctx context.Context, partner *entities.Partner) error	// Create creates a new partnertype PartnerRepository interface {// PartnerRepository defines the contract for partner persistence}	Offset    int	Limit     int	DateTo    *string	DateFrom  *string	Status    *entities.TransactionStatus	PartnerID *uuid.UUIDtype TransactionFilter struct {// TransactionFilter represents filter criteria for listing transactions}	GetByStatus(ctx context.Context, status entities.TransactionStatus, limit, offset int) ([]*entities.Transaction, error)	// GetByStatus retrieves transactions by status		GetByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Transaction, error)	// GetByPartnerID retrieves transactions for a specific partner		List(ctx context.Context, filter TransactionFilter) ([]*entities.Transaction, int64, error)	// List retrieves transactions with pagination		Update(ctx context.Context, transaction *entities.Transaction) error	// Update updates an existing transaction		GetByIdempotencyKey(ctx context.Context, partnerID uuid.UUID, idempotencyKey string) (*entities.Transaction, error)	// GetByIdempotencyKey retrieves a transaction by partner and idempotency key		GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)	// GetByID retrieves a transaction by ID		Create(ctx context.Context, transaction *entities.Transaction) error	// Create creates a new transactiontype TransactionRepository interface {// Infrastructure layer will implement this interface// TransactionRepository defines the contract for transaction persistence)	"Pay2Go/internal/domain/entities"	"github.com/google/uuid"	"context"import (
\ No newline at end of file
+	// GetByTokenHash retrieves a session by the hash of its token
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entities.AdminSession, error)
+
+	// Update updates an existing session
+	Update(ctx context.Context, session *entities.AdminSession) error
+}
+
+// RefundRepository defines the contract for refund persistence
+type RefundRepository interface {
+	// Create creates a new refund
+	Create(ctx context.Context, refund *entities.Refund) error
+
+	// CreateBatch creates refunds like Create, all or none, with as few
+	// round-trips as possible. Like Create, it reserves no refunded amount.
+	CreateBatch(ctx context.Context, refunds []*entities.Refund) error
+
+	// GetByID retrieves a refund by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Refund, error)
+
+	// GetByTransactionID retrieves all refunds for a transaction
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error)
+
+	// Update updates an existing refund
+	Update(ctx context.Context, refund *entities.Refund) error
+
+	// Delete saves a refund soft-deleted with SoftDelete, which hides it from
+	// every other method but Restore. It fails with a *errors.VersionConflictError
+	// if the refund changed since it was loaded.
+	Delete(ctx context.Context, refund *entities.Refund) error
+
+	// Restore undoes Delete, returning ErrRefundNotFound unless a deleted refund
+	// has the ID
+	Restore(ctx context.Context, id uuid.UUID) error
+
+	// Reserve creates a refund and atomically adds its amount to the transaction's
+	// refunded total, returning ErrRefundAmountExceeded if that would exceed the
+	// original amount or the transaction has been deleted
+	Reserve(ctx context.Context, refund *entities.Refund) error
+
+	// Release updates a failed or cancelled refund and returns its amount to the
+	// transaction's refundable balance
+	Release(ctx context.Context, refund *entities.Refund) error
+
+	// GetTotalRefundedAmount calculates total refunded amount, in minor units, for a transaction
+	GetTotalRefundedAmount(ctx context.Context, transactionID uuid.UUID) (int64, error)
+
+	// GetReasonSummary aggregates a partner's completed refunds by reason code
+	GetReasonSummary(ctx context.Context, filter RefundReportFilter) ([]RefundReasonSummary, error)
+
+	// List returns a partner's refunds matching query, newest first
+	List(ctx context.Context, query RefundQuery) ([]*entities.Refund, error)
+
+	// AnonymizeCustomerData clears the reason notes of all of a partner's
+	// refunds, deleted ones included: free text written by the partner's
+	// staff that can name the customer. Refunds without a note are skipped,
+	// so it returns how many changed and is safe to repeat.
+	AnonymizeCustomerData(ctx context.Context, partnerID uuid.UUID) (int64, error)
+}
+
+// RefundQuery selects the refunds of a partner's transactions in one mode
+type RefundQuery struct {
+	PartnerID uuid.UUID
+	Livemode  bool
+
+	// TransactionID and Status each match one value when set
+	TransactionID *uuid.UUID
+	Status        *entities.RefundStatus
+
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+
+	Limit  int
+	Offset int
+}
+
+// RefundReportFilter represents filter criteria for refund reporting
+type RefundReportFilter struct {
+	PartnerID uuid.UUID
+	Livemode  bool
+	DateFrom  *string
+	DateTo    *string
+}
+
+// RefundReasonSummary represents completed refunds grouped by reason and currency
+type RefundReasonSummary struct {
+	ReasonCode valueobjects.RefundReasonCode
+	Count      int64
+	Total      valueobjects.Money
+}
+
+// BulkRefundJobRepository defines the contract for bulk refund job persistence
+type BulkRefundJobRepository interface {
+	// Create creates a new bulk refund job
+	Create(ctx context.Context, job *entities.BulkRefundJob) error
+
+	// GetByID retrieves a bulk refund job by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.BulkRefundJob, error)
+
+	// ClaimDue claims up to limit unfinished jobs that are due, oldest
+	// first: pending ones, and running ones whose worker stopped. Claimed
+	// jobs are not due again before claimedUntil, as with
+	// PaymentJobRepository.ClaimDue.
+	ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.BulkRefundJob, error)
+
+	// Update updates job progress and results, and when it is next due
+	Update(ctx context.Context, job *entities.BulkRefundJob) error
+}
+
+// SettlementEntryRepository defines the contract for the entries of
+// providers' settlement feeds and how they reconciled
+type SettlementEntryRepository interface {
+	// Create stores a reconciled entry; the provider's reference must not
+	// be stored yet
+	Create(ctx context.Context, entry *entities.SettlementEntry) error
+
+	// GetByReference retrieves the entry provider reported under reference,
+	// or nil if it has not been reconciled
+	GetByReference(ctx context.Context, provider valueobjects.PaymentProvider, reference string) (*entities.SettlementEntry, error)
+
+	// ListDiscrepancies retrieves the entries with a discrepancy matching
+	// query, newest first, and the total number matching it
+	ListDiscrepancies(ctx context.Context, query SettlementDiscrepancyQuery) ([]*entities.SettlementEntry, int64, error)
+}
+
+// SettlementDiscrepancyQuery filters the discrepancy report
+type SettlementDiscrepancyQuery struct {
+	Provider  *valueobjects.PaymentProvider
+	PartnerID *uuid.UUID
+
+	// ReconciledFrom is inclusive and ReconciledTo exclusive
+	ReconciledFrom *time.Time
+	ReconciledTo   *time.Time
+
+	Limit  int
+	Offset int
+}
+
+// DisputeRepository defines the contract for disputes opened by chargebacks
+type DisputeRepository interface {
+	// Create stores a new dispute
+	Create(ctx context.Context, dispute *entities.Dispute) error
+
+	// Update saves a dispute's status
+	Update(ctx context.Context, dispute *entities.Dispute) error
+
+	// GetOpenByTransaction retrieves the oldest open dispute of a
+	// transaction, or nil if it has none
+	GetOpenByTransaction(ctx context.Context, transactionID uuid.UUID) (*entities.Dispute, error)
+
+	// ListByPartner retrieves a partner's disputes, newest first, and how
+	// many the partner has
+	ListByPartner(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Dispute, int64, error)
+
+	// ListOpenedOrReversed retrieves a partner's disputes opened or reversed
+	// from from (inclusive) to to (exclusive), oldest first
+	ListOpenedOrReversed(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]*entities.Dispute, error)
+}
+
+// StatementRepository defines the contract for partner statement persistence
+type StatementRepository interface {
+	// Create stores a new statement, failing with ErrStatementExists if the
+	// partner has one for the period
+	Create(ctx context.Context, statement *entities.Statement) error
+
+	// GetByID retrieves a statement
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Statement, error)
+
+	// GetByPeriod retrieves a partner's statement of period, failing with
+	// ErrStatementNotFound if none was issued
+	GetByPeriod(ctx context.Context, partnerID uuid.UUID, period entities.StatementPeriod) (*entities.Statement, error)
+
+	// ListByPartner retrieves a partner's statements, latest period first,
+	// and how many the partner has
+	ListByPartner(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Statement, int64, error)
+
+	// ListByPeriod retrieves every partner's statement of period, by partner,
+	// and how many there are
+	ListByPeriod(ctx context.Context, period entities.StatementPeriod, limit, offset int) ([]*entities.Statement, int64, error)
+}
+
+// PaymentJobRepository defines the contract for the queue of payments
+// waiting to be processed by a worker
+type PaymentJobRepository interface {
+	// Add queues a job
+	Add(ctx context.Context, job *entities.PaymentJob) error
+
+	// ClaimDue claims up to limit uncompleted jobs that are due, oldest
+	// first, skipping jobs that have run out of attempts. Claimed jobs are
+	// not due again before claimedUntil, so workers claiming at the same
+	// time never get the same job; jobs whose worker stops before saving
+	// the outcome are claimed again after that.
+	ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.PaymentJob, error)
+
+	// Update saves the outcome of an attempt
+	Update(ctx context.Context, job *entities.PaymentJob) error
+}
+
+// ExportJobRepository defines the contract for the queue of exports waiting
+// to be produced by a worker
+type ExportJobRepository interface {
+	// Add queues a job
+	Add(ctx context.Context, job *entities.ExportJob) error
+
+	// GetByID retrieves a job, or returns errors.ErrExportNotFound
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ExportJob, error)
+
+	// ClaimDue claims up to limit pending jobs that are due, oldest first.
+	// Claimed jobs are not due again before claimedUntil, as with
+	// PaymentJobRepository.ClaimDue.
+	ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.ExportJob, error)
+
+	// Update saves the outcome of an attempt
+	Update(ctx context.Context, job *entities.ExportJob) error
+}
+
+// ReportScheduleRepository defines the contract for the schedules of
+// partners' report deliveries. SFTP credentials are encrypted before they
+// are written.
+type ReportScheduleRepository interface {
+	// Create stores a new schedule
+	Create(ctx context.Context, schedule *entities.ReportSchedule) error
+
+	// GetByID retrieves a schedule, or returns errors.ErrReportScheduleNotFound
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ReportSchedule, error)
+
+	// ListByPartner retrieves a partner's schedules in livemode, oldest first
+	ListByPartner(ctx context.Context, partnerID uuid.UUID, livemode bool) ([]*entities.ReportSchedule, error)
+
+	// Update saves a schedule if it is still at schedule.Version, which it
+	// then increments, failing with a version conflict otherwise
+	Update(ctx context.Context, schedule *entities.ReportSchedule) error
+
+	// Delete removes a schedule, or returns errors.ErrReportScheduleNotFound
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ClaimDue claims up to limit schedules whose next run is due, longest
+	// due first. Claimed schedules are not due again before claimedUntil, as
+	// with PaymentJobRepository.ClaimDue, and their version is incremented.
+	ClaimDue(ctx context.Context, limit int, claimedUntil time.Time) ([]*entities.ReportSchedule, error)
+}
+
+// OutboxRepository defines the contract for the transactional outbox
+type OutboxRepository interface {
+	// Add records an event. Called inside a unit of work it commits or rolls
+	// back with the state change the event describes.
+	Add(ctx context.Context, event *entities.OutboxEvent) error
+
+	// ClaimDue claims up to limit unpublished events of shards that are
+	// due, oldest first, skipping events that have run out of attempts.
+	// Claimed events are not due again before claimedUntil, so relays
+	// claiming at the same time never get the same event; events whose
+	// relay stops before saving the outcome are claimed again after that.
+	ClaimDue(ctx context.Context, shards OutboxShards, limit int, claimedUntil time.Time) ([]*entities.OutboxEvent, error)
+
+	// Update saves the outcome of a publish attempt
+	Update(ctx context.Context, event *entities.OutboxEvent) error
+
+	// Backlog counts the unpublished events that have attempts left
+	Backlog(ctx context.Context) (OutboxBacklog, error)
+
+	// ListPublishedBefore returns up to limit events published before
+	// before, oldest first, for archiving
+	ListPublishedBefore(ctx context.Context, before time.Time, limit int) ([]*entities.OutboxEvent, error)
+
+	// Delete removes events by ID, once they are archived
+	Delete(ctx context.Context, ids []uuid.UUID) error
+
+	// DeleteByPartner removes all of a partner's events, published or not,
+	// and returns how many there were
+	DeleteByPartner(ctx context.Context, partnerID uuid.UUID) (int64, error)
+
+	// GetDeliverySummary counts, per partner, the webhook events recorded
+	// since since that were published and those whose every attempt so far
+	// failed
+	GetDeliverySummary(ctx context.Context, since time.Time) ([]DeliverySummary, error)
+
+	// ListByAggregate returns up to limit events about one aggregate, such
+	// as a transaction, oldest first. Archived events are not included.
+	ListByAggregate(ctx context.Context, aggregateType string, aggregateID uuid.UUID, limit int) ([]*entities.OutboxEvent, error)
+
+	// ListByPartner returns up to limit of a partner's events recorded
+	// after the cursor and no later than until, oldest first. Archived
+	// events are not included.
+	ListByPartner(ctx context.Context, partnerID uuid.UUID, after OutboxCursor, until time.Time, limit int) ([]*entities.OutboxEvent, error)
+}
+
+// OutboxCursor is the position of an event among a partner's events, which
+// are ordered by creation time, then ID. The zero cursor comes before every
+// event.
+type OutboxCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// DeliverySummary counts the outcomes of a partner's recent events.
+// Events not yet attempted are in neither count.
+type DeliverySummary struct {
+	PartnerID uuid.UUID
+	Delivered int64
+	Failing   int64
+}
+
+// OutboxShards selects the outbox shards a relay publishes: those whose
+// number modulo Count is Index. A zero Count selects every shard.
+type OutboxShards struct {
+	Index int
+	Count int
+}
+
+// Includes reports whether shard is selected
+func (s OutboxShards) Includes(shard int) bool {
+	return s.Count <= 1 || shard%s.Count == s.Index
+}
+
+// OutboxBacklog describes the events waiting to be published
+type OutboxBacklog struct {
+	Pending int64
+	// OldestCreatedAt is when the oldest pending event was recorded; nil
+	// without pending events
+	OldestCreatedAt *time.Time
+}
+
+// CardBINRepository defines the contract for the card BIN table
+type CardBINRepository interface {
+	// Lookup returns the longest BIN range matching bin, or ErrCardBINNotFound
+	Lookup(ctx context.Context, bin valueobjects.BIN) (*valueobjects.BINInfo, error)
+}
+
+// UnitOfWork defines the contract for running several repository operations
+// atomically
+type UnitOfWork interface {
+	// Do runs fn in one database transaction. Repository calls made with the
+	// context passed to fn take part in it. The transaction commits when fn
+	// returns nil and rolls back when it returns an error or panics. Calling
+	// Do again inside fn joins the outer transaction.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// PaymentGateway defines the contract for payment provider integration
+type PaymentGateway interface {
+	// ProcessPayment processes a payment through the provider
+	ProcessPayment(ctx context.Context, transaction *entities.Transaction) (providerTransactionID string, err error)
+
+	// ProcessRefund processes a refund through the provider
+	ProcessRefund(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction) (providerRefundID string, err error)
+
+	// GetPaymentStatus checks payment status from provider
+	GetPaymentStatus(ctx context.Context, providerTransactionID string) (string, error)
+
+	// GetProviderName returns the name of the payment provider
+	GetProviderName() string
+}
+
+// ExchangeRateProvider defines the contract for looking up foreign exchange rates
+type ExchangeRateProvider interface {
+	// GetRate returns the current rate for converting from into to.
+	// Unknown pairs return ErrExchangeRateUnavailable.
+	GetRate(ctx context.Context, from, to valueobjects.Currency) (valueobjects.ExchangeRate, error)
+}
+
+// NotificationService defines the contract for sending notifications
+type NotificationService interface {
+	// SendWebhook sends a webhook notification to partner
+	SendWebhook(ctx context.Context, partnerWebhookURL string, payload interface{}) error
+
+	// SendEmail sends an email notification
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+// OutboxPublisher defines the contract for delivering outbox events to
+// their consumers, such as partner webhooks
+type OutboxPublisher interface {
+	// Publish delivers event. An error leaves the event to be retried later,
+	// so consumers must tolerate receiving an event more than once.
+	Publish(ctx context.Context, event *entities.OutboxEvent) error
+}
+
+// CacheService defines the contract for caching
+type CacheService interface {
+	// Get retrieves a value from cache
+	Get(ctx context.Context, key string) (interface{}, error)
+
+	// Set stores a value in cache with TTL
+	Set(ctx context.Context, key string, value interface{}, ttlSeconds int) error
+
+	// Delete removes a value from cache
+	Delete(ctx context.Context, key string) error
+
+	// Exists checks if key exists in cache
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// PartnerCache defines the contract for a cache of partners shared by every
+// API instance, such as Redis
+type PartnerCache interface {
+	// Get returns the cached partner, or nil if it is not cached
+	Get(ctx context.Context, id uuid.UUID) (*entities.Partner, error)
+
+	// Set caches partner for ttl
+	Set(ctx context.Context, partner *entities.Partner, ttl time.Duration) error
+
+	// Invalidate removes the cached partner and tells every instance, so they
+	// drop the copies they keep themselves
+	Invalidate(ctx context.Context, id uuid.UUID) error
+
+	// Subscribe calls onInvalidate with the ID of every partner invalidated
+	// by any instance, until ctx is done
+	Subscribe(ctx context.Context, onInvalidate func(id uuid.UUID)) error
+}
+
+// TransactionCache defines the contract for a short-lived cache of
+// transactions shared by every API instance, such as Redis. Entries are
+// keyed by partner and ID, so a partner can only ever be served its own.
+type TransactionCache interface {
+	// Get returns the cached transaction, or nil if it is not cached
+	Get(ctx context.Context, partnerID, id uuid.UUID) (*entities.Transaction, error)
+
+	// Set caches transaction for ttl
+	Set(ctx context.Context, transaction *entities.Transaction, ttl time.Duration) error
+
+	// Invalidate removes the cached transaction
+	Invalidate(ctx context.Context, partnerID, id uuid.UUID) error
+}
+
+// RateLimitStore defines the contract for counting requests in a sliding window.
+// Implementations backed by shared storage enforce limits across all instances.
+type RateLimitStore interface {
+	// Allow records a request for key and reports whether it fits within limit per window
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+
+	// Peek reports whether another request for key would fit within limit per window,
+	// without recording one
+	Peek(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+}
+
+// RateLimitResult represents the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAt    time.Time
+	RetryAfter time.Duration
+}
+
+// Locker defines the contract for locks held across every API instance, so
+// only one of them works on a resource at a time
+type Locker interface {
+	// TryLock acquires the lock on key without waiting, failing with
+	// errors.ErrResourceLocked while another holder has it. A lock whose holder
+	// dies is released after ttl at the latest.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+}
+
+// Lock is a held lock
+type Lock interface {
+	// Unlock releases the lock; it is a no-op once the lock has expired
+	Unlock(ctx context.Context) error
+}
+
+// SecretContext is where a secret is stored: the partner it belongs to, or
+// the admin user for admin secrets, its column as table.column and, in
+// columns an owner has several rows of, the row. Encrypted secrets are bound
+// to it, so one copied elsewhere does not decrypt.
+type SecretContext struct {
+	OwnerID uuid.UUID
+	Column  string
+	// Record names the row among the owner's, such as the ID of an API key
+	// or the name of a provider; it is empty for one row per owner
+	Record string
+}
+
+// SecretCipher defines the contract for encrypting secrets before they are stored
+type SecretCipher interface {
+	// Encrypt encrypts a secret stored at where with the current key
+	Encrypt(plaintext string, where SecretContext) (string, error)
+
+	// Decrypt decrypts a secret stored at where
+	Decrypt(ciphertext string, where SecretContext) (string, error)
+
+	// NeedsRotation reports whether a stored secret should be re-encrypted with the current key
+	NeedsRotation(ciphertext string) bool
+}
+
+// SecretRotator is implemented by repositories that store encrypted secrets
+type SecretRotator interface {
+	// RotateSecrets re-encrypts stored secrets with the current key and returns how many changed
+	RotateSecrets(ctx context.Context) (int, error)
+}
+
+// PartitionManager is implemented by databases that partition transactions
+// and refunds by month
+type PartitionManager interface {
+	// EnsurePartitions creates the missing partitions from the month of now
+	// to monthsAhead months later, and returns the names of those created
+	EnsurePartitions(ctx context.Context, now time.Time, monthsAhead int) ([]string, error)
+}
+
+// AuditLogger defines the contract for audit logging
+type AuditLogger interface {
+	// LogAction logs an audit event
+	LogAction(ctx context.Context, action AuditAction) error
+}
+
+// AuditAction represents an audit log entry
+type AuditAction struct {
+	PartnerID    uuid.UUID
+	Action       string
+	ResourceType string
+	ResourceID   uuid.UUID
+	IPAddress    string
+	UserAgent    string
+	RequestID    uuid.UUID
+	Changes      map[string]interface{}
+
+	// OccurredAt is when the action happened; zero means when it is logged
+	OccurredAt time.Time
+}
+
+// Time returns when the action happened
+func (a AuditAction) Time() time.Time {
+	if a.OccurredAt.IsZero() {
+		return time.Now()
+	}
+	return a.OccurredAt
+}
+
+// AuditLogRepository stores audit events and lists them back
+type AuditLogRepository interface {
+	AuditLogger
+
+	// List retrieves the entries matching q, newest first, and the total
+	// number of matches ignoring paging
+	List(ctx context.Context, q AuditLogQuery) ([]*AuditLogEntry, int64, error)
+
+	// ListChain retrieves up to limit entries of a partner's hash chain with
+	// IDs above afterID, oldest first; uuid.Nil lists the actions logged
+	// without a partner. Entries logged before chaining have no hashes.
+	ListChain(ctx context.Context, partnerID uuid.UUID, afterID int64, limit int) ([]*AuditLogEntry, error)
+
+	// ListChainPartnerIDs returns the partners that have audit entries, and
+	// uuid.Nil if there are actions logged without a partner
+	ListChainPartnerIDs(ctx context.Context) ([]uuid.UUID, error)
+
+	// ListArchivable retrieves up to limit entries logged before before,
+	// lowest ID first, for archiving. The latest entry of every chain is
+	// left out, since the next entry links to it.
+	ListArchivable(ctx context.Context, before time.Time, limit int) ([]*AuditLogEntry, error)
+
+	// Delete removes entries by ID, once they are archived
+	Delete(ctx context.Context, ids []int64) error
+}
+
+// AuditLogQuery specifies which audit entries to list. Zero-valued fields do
+// not filter; all set fields must match.
+type AuditLogQuery struct {
+	PartnerID    *uuid.UUID
+	Action       string
+	ResourceType string
+	ResourceID   *uuid.UUID
+
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+
+	Limit  int
+	Offset int
+}
+
+// AuditLogEntry is a stored audit event. Actions logged without a partner,
+// resource or request have uuid.Nil in those fields.
+type AuditLogEntry struct {
+	ID int64
+	AuditAction
+	CreatedAt time.Time
+
+	// PrevHash and Hash link the entry into its partner's chain, see
+	// AuditChainGenesis
+	PrevHash string
+	Hash     string
+}
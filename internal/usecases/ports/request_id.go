@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDKey is the context key of the ID of the API request being
+// served. The HTTP middleware stores the ID under it in the request's
+// locals, which Fiber's request context exposes as context values.
+type RequestIDKey struct{}
+
+// WithRequestID returns ctx carrying the ID of the request it serves
+func WithRequestID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, RequestIDKey{}, id)
+}
+
+// RequestID returns the ID of the request ctx serves, or uuid.Nil outside
+// requests, such as in background work
+func RequestID(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(RequestIDKey{}).(uuid.UUID)
+	return id
+}
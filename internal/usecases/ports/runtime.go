@@ -0,0 +1,25 @@
+package ports
+
+// RuntimeSettings are the settings of the running process that can change
+// without a restart, to diagnose it in production
+type RuntimeSettings struct {
+	// LogLevel is "debug", "info", "warn" or "error"
+	LogLevel string
+	// GCPercent is the heap growth that triggers a garbage collection, in
+	// percent of the live heap; -1 turns the collector off
+	GCPercent int
+	// BlockProfileRate samples one blocking event per this many nanoseconds
+	// blocked, for the block profile; 0 turns it off
+	BlockProfileRate int
+	// MutexProfileFraction samples one in this many mutex contentions, for
+	// the mutex profile; 0 turns it off
+	MutexProfileFraction int
+}
+
+// RuntimeSettingsUpdate changes RuntimeSettings; nil fields stay as they are
+type RuntimeSettingsUpdate struct {
+	LogLevel             *string
+	GCPercent            *int
+	BlockProfileRate     *int
+	MutexProfileFraction *int
+}
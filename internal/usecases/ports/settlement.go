@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// SettlementFeed delivers the entries of payment providers' settlement and
+// chargeback reports, such as from a Kafka topic or files dropped over SFTP
+type SettlementFeed interface {
+	// Next returns the next entries, or none when there are none yet. Until
+	// Commit is called, a restarted feed delivers them again.
+	Next(ctx context.Context) ([]*entities.SettlementEntry, error)
+	// Commit acknowledges the entries Next last returned
+	Commit(ctx context.Context) error
+}
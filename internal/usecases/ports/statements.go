@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"Pay2Go/internal/domain/entities"
+)
+
+// StatementRenderer renders the document of a partner's statement, a CSV
+// file of its lines for the invoicing process
+type StatementRenderer interface {
+	// Render returns the document of statement, issued to partner
+	Render(statement *entities.Statement, partner *entities.Partner) ([]byte, error)
+}
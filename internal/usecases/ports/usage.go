@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageRecord is usage metered for a partner on one UTC day. API calls are
+// metered with an empty Currency, payments per currency.
+type UsageRecord struct {
+	PartnerID uuid.UUID
+	// Day is midnight UTC of the day
+	Day      time.Time
+	Currency string
+
+	APICalls     int64
+	ClientErrors int64 // Calls answered with a 4xx status
+	ServerErrors int64 // Calls answered with a 5xx status
+
+	// Payments and FailedPayments count live payments sent to a provider;
+	// Volume is the amount of the successful ones in minor units
+	Payments       int64
+	FailedPayments int64
+	Volume         int64
+}
+
+// UsageDay returns the day t is metered on
+func UsageDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// UsageQuery specifies which usage to list
+type UsageQuery struct {
+	// PartnerID narrows the usage to one partner
+	PartnerID *uuid.UUID
+	// From and To are UTC days, both inclusive
+	From time.Time
+	To   time.Time
+}
+
+// UsageRepository stores metered usage. Meters of several API instances add
+// to the same records, so records are only ever added to.
+type UsageRepository interface {
+	// AddUsage adds the counters of records to the stored ones of their
+	// partner, day and currency
+	AddUsage(ctx context.Context, records []UsageRecord) error
+	// ListUsage returns the records matching query ordered by day, partner
+	// and currency
+	ListUsage(ctx context.Context, query UsageQuery) ([]UsageRecord, error)
+}
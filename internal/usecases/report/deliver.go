@@ -0,0 +1,265 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// MaxEmailAttachmentSize is the largest report delivered by email; mail
+// servers commonly refuse messages much larger once encoded
+const MaxEmailAttachmentSize = 10 << 20
+
+// FileWriter writes the file of an export, and the fees of statements in
+// the accounting formats, such as export.Writer
+type FileWriter interface {
+	Write(ctx context.Context, out io.Writer, job *entities.ExportJob) (int64, error)
+	WriteStatement(ctx context.Context, out io.Writer, statement *entities.Statement, format entities.ExportFormat) error
+}
+
+// StatementIssuer returns a partner's statement of a month, issuing it if
+// it was not yet, such as statement.GenerateStatementUseCase
+type StatementIssuer interface {
+	Execute(ctx context.Context, partnerID uuid.UUID, period entities.StatementPeriod) (*entities.Statement, error)
+}
+
+// contentTypes are the media types of report files by format
+var contentTypes = map[entities.ExportFormat]string{
+	entities.ExportFormatCSV:    "text/csv; charset=utf-8",
+	entities.ExportFormatNDJSON: "application/x-ndjson",
+	// QuickBooks reads IIF files as tab-separated text
+	entities.ExportFormatQuickBooksIIF: "text/plain; charset=utf-8",
+	entities.ExportFormatXeroCSV:       "text/csv; charset=utf-8",
+}
+
+// DeliverReportsUseCase delivers the runs of report schedules. A run covers
+// the period before it: transactions and refunds are written as exports
+// are, and statements are the documents issued for the month.
+//
+// Several API instances can share the schedules: a pass claims its
+// schedules until claimTimeout from now, so the others skip them. A run
+// that fails is tried again, as ReportSchedule.MarkFailed describes.
+type DeliverReportsUseCase struct {
+	scheduleRepo ports.ReportScheduleRepository
+	writer       FileWriter
+	statements   StatementIssuer
+	store        ports.ObjectStore
+	emailSender  ports.EmailSender
+	uploader     ports.ReportUploader
+
+	batchSize    int
+	claimTimeout time.Duration
+}
+
+// NewDeliverReportsUseCase creates a new instance running up to batchSize
+// schedules a pass, one at a time. Statement documents are read from
+// store. emailSender may be nil when no email provider is configured;
+// schedules delivering by email then fail. claimTimeout must be longer
+// than a pass takes.
+func NewDeliverReportsUseCase(
+	scheduleRepo ports.ReportScheduleRepository,
+	writer FileWriter,
+	statements StatementIssuer,
+	store ports.ObjectStore,
+	emailSender ports.EmailSender,
+	uploader ports.ReportUploader,
+	batchSize int,
+	claimTimeout time.Duration,
+) *DeliverReportsUseCase {
+	return &DeliverReportsUseCase{
+		scheduleRepo: scheduleRepo,
+		writer:       writer,
+		statements:   statements,
+		store:        store,
+		emailSender:  emailSender,
+		uploader:     uploader,
+		batchSize:    batchSize,
+		claimTimeout: claimTimeout,
+	}
+}
+
+// RunDue delivers the runs that are due and returns how many it ran. Only
+// errors saving a schedule are returned; a schedule the partner changed or
+// deleted during its run is left as they saved it.
+func (uc *DeliverReportsUseCase) RunDue(ctx context.Context) (int, error) {
+	schedules, err := uc.scheduleRepo.ClaimDue(ctx, uc.batchSize, time.Now().Add(uc.claimTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim report schedules: %w", err)
+	}
+
+	for i, schedule := range schedules {
+		if err := uc.deliver(ctx, schedule, time.Now()); err != nil {
+			schedule.MarkFailed(err.Error(), time.Now())
+		} else {
+			schedule.MarkDelivered(time.Now())
+		}
+		if err := uc.scheduleRepo.Update(ctx, schedule); err != nil {
+			if stderrors.Is(err, errors.ErrVersionConflict) {
+				continue
+			}
+			return i, fmt.Errorf("failed to save report schedule %s: %w", schedule.ID, err)
+		}
+	}
+	return len(schedules), nil
+}
+
+// RunNowInput represents input for delivering a schedule's report on demand
+type RunNowInput struct {
+	PartnerID  uuid.UUID
+	Livemode   bool
+	ScheduleID uuid.UUID
+}
+
+// RunNow delivers the report of partnerID's schedule for the period before
+// now, such as to try a new destination. Its outcome is recorded as a
+// delivered run's; a failure is returned rather than tried again.
+func (uc *DeliverReportsUseCase) RunNow(ctx context.Context, input RunNowInput) (*entities.ReportSchedule, error) {
+	schedule, err := getSchedule(ctx, uc.scheduleRepo, input.PartnerID, input.Livemode, input.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.deliver(ctx, schedule, time.Now()); err != nil {
+		return nil, errors.NewBusinessRuleError("report_delivery_failed", fmt.Sprintf("the report could not be delivered: %v", err))
+	}
+	schedule.MarkDelivered(time.Now())
+	if err := uc.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// deliver writes the report of schedule's period before now and sends it
+// to the schedule's destination
+func (uc *DeliverReportsUseCase) deliver(ctx context.Context, schedule *entities.ReportSchedule, now time.Time) error {
+	from, to := schedule.Period(now)
+
+	var file ports.ReportFile
+	var err error
+	if schedule.Resource == entities.ReportResourceStatements {
+		file, err = uc.statementFile(ctx, schedule, from)
+	} else {
+		file, err = uc.exportFile(ctx, schedule, from, to)
+	}
+	if err != nil {
+		return err
+	}
+
+	if schedule.Destination.Type == valueobjects.ReportDestinationEmail {
+		return uc.email(ctx, schedule, file, from, to)
+	}
+	return uc.uploader.Upload(ctx, schedule.PartnerID, schedule.Destination, file)
+}
+
+// exportFile writes the transactions or refunds created from from to to
+func (uc *DeliverReportsUseCase) exportFile(ctx context.Context, schedule *entities.ReportSchedule, from, to time.Time) (ports.ReportFile, error) {
+	filters := schedule.Filters
+	filters.CreatedFrom = &from
+	filters.CreatedTo = &to
+	job := &entities.ExportJob{
+		ID:        schedule.ID,
+		PartnerID: schedule.PartnerID,
+		Livemode:  schedule.Livemode,
+		Resource:  entities.ExportResource(schedule.Resource),
+		Format:    schedule.Format,
+		Filters:   filters,
+		Layout:    schedule.Layout,
+	}
+
+	var buf bytes.Buffer
+	if _, err := uc.writer.Write(ctx, &buf, job); err != nil {
+		return ports.ReportFile{}, err
+	}
+	return ports.ReportFile{
+		Name:        fmt.Sprintf("%s_%s.%s", schedule.Resource, periodLabel(schedule.Frequency, from, to), schedule.Format.Extension()),
+		ContentType: contentTypes[schedule.Format],
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// statementFile reads the document of the partner's statement of the month
+// starting at from, issuing the statement if it was not yet. Accounting
+// formats hold the fees the statement charges instead.
+func (uc *DeliverReportsUseCase) statementFile(ctx context.Context, schedule *entities.ReportSchedule, from time.Time) (ports.ReportFile, error) {
+	period := entities.StatementPeriodOf(from)
+	statement, err := uc.statements.Execute(ctx, schedule.PartnerID, period)
+	if err != nil {
+		return ports.ReportFile{}, fmt.Errorf("failed to issue statement: %w", err)
+	}
+
+	if schedule.Format.IsAccounting() {
+		var buf bytes.Buffer
+		if err := uc.writer.WriteStatement(ctx, &buf, statement, schedule.Format); err != nil {
+			return ports.ReportFile{}, err
+		}
+		return ports.ReportFile{
+			Name:        fmt.Sprintf("statement_%s_fees.%s", period, schedule.Format.Extension()),
+			ContentType: contentTypes[schedule.Format],
+			Data:        buf.Bytes(),
+		}, nil
+	}
+
+	document, err := uc.store.Get(ctx, statement.DocumentKey)
+	if err != nil {
+		return ports.ReportFile{}, fmt.Errorf("failed to read statement: %w", err)
+	}
+	return ports.ReportFile{
+		Name:        fmt.Sprintf("statement_%s.csv", period),
+		ContentType: contentTypes[entities.ExportFormatCSV],
+		Data:        document,
+	}, nil
+}
+
+// email sends file to each of the schedule's recipients, separately so
+// they do not see each other's addresses
+func (uc *DeliverReportsUseCase) email(ctx context.Context, schedule *entities.ReportSchedule, file ports.ReportFile, from, to time.Time) error {
+	if uc.emailSender == nil {
+		return fmt.Errorf("email delivery is not configured")
+	}
+	if len(file.Data) > MaxEmailAttachmentSize {
+		return fmt.Errorf("the report is %d bytes, too large to email; deliver it by SFTP or S3", len(file.Data))
+	}
+
+	location := to.Location()
+	period := from.In(location).Format("2006-01-02") + " to " + to.Add(-time.Nanosecond).In(location).Format("2006-01-02")
+	for _, recipient := range schedule.Destination.Recipients {
+		err := uc.emailSender.SendEmail(ctx, ports.Email{
+			To:      recipient,
+			Subject: fmt.Sprintf("Pay2Go report: %s", schedule.Name),
+			Text: fmt.Sprintf("Your scheduled report %q, covering %s, is attached as %s.\n\n"+
+				"You receive it because it is scheduled to be emailed to this address. To stop it, delete or change the schedule through the Pay2Go API.\n",
+				schedule.Name, period, file.Name),
+			Attachments: []ports.EmailAttachment{{
+				Filename:    file.Name,
+				ContentType: file.ContentType,
+				Content:     file.Data,
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to email report to %s: %w", recipient, err)
+		}
+	}
+	return nil
+}
+
+// periodLabel names the period from from to to in file names: its day, its
+// first and last days, or its month
+func periodLabel(frequency entities.ReportFrequency, from, to time.Time) string {
+	switch frequency {
+	case entities.ReportFrequencyWeekly:
+		return from.Format("2006-01-02") + "_" + to.AddDate(0, 0, -1).Format("2006-01-02")
+	case entities.ReportFrequencyMonthly:
+		return from.Format("2006-01")
+	}
+	return from.Format("2006-01-02")
+}
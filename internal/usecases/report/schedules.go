@@ -0,0 +1,223 @@
+// Package report manages the reports partners schedule for delivery to
+// their inbox, SFTP server or S3 bucket, and delivers them when due
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// MaxReportSchedules is how many schedules a partner can keep in each mode
+const MaxReportSchedules = 20
+
+// ScheduleInput represents input for creating or reconfiguring a schedule
+type ScheduleInput struct {
+	PartnerID uuid.UUID
+	Livemode  bool
+	Settings  entities.ReportSettings
+	IPAddress string
+	UserAgent string
+}
+
+// CreateScheduleUseCase adds a report schedule
+type CreateScheduleUseCase struct {
+	scheduleRepo ports.ReportScheduleRepository
+	auditLogger  ports.AuditLogger
+}
+
+// NewCreateScheduleUseCase creates a new instance
+func NewCreateScheduleUseCase(scheduleRepo ports.ReportScheduleRepository, auditLogger ports.AuditLogger) *CreateScheduleUseCase {
+	return &CreateScheduleUseCase{
+		scheduleRepo: scheduleRepo,
+		auditLogger:  auditLogger,
+	}
+}
+
+// Execute creates the schedule; its first run is the next after now
+func (uc *CreateScheduleUseCase) Execute(ctx context.Context, input ScheduleInput) (*entities.ReportSchedule, error) {
+	// Step 1: Enforce the limit
+	existing, err := uc.scheduleRepo.ListByPartner(ctx, input.PartnerID, input.Livemode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	if len(existing) >= MaxReportSchedules {
+		return nil, errors.NewBusinessRuleError("report_schedule_limit", fmt.Sprintf("a partner can keep up to %d report schedules", MaxReportSchedules))
+	}
+
+	// Step 2: Create (SFTP credentials are encrypted by the repository)
+	schedule, err := entities.NewReportSchedule(input.PartnerID, input.Livemode, input.Settings, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create report schedule: %w", err)
+	}
+
+	// Step 3: Log audit event (never the credentials)
+	logScheduleAction(ctx, uc.auditLogger, "report_schedule_created", schedule, input.IPAddress, input.UserAgent)
+	return schedule, nil
+}
+
+// UpdateScheduleInput represents input for reconfiguring a schedule
+type UpdateScheduleInput struct {
+	ScheduleInput
+	ScheduleID uuid.UUID
+}
+
+// UpdateScheduleUseCase reconfigures a report schedule
+type UpdateScheduleUseCase struct {
+	scheduleRepo ports.ReportScheduleRepository
+	auditLogger  ports.AuditLogger
+}
+
+// NewUpdateScheduleUseCase creates a new instance
+func NewUpdateScheduleUseCase(scheduleRepo ports.ReportScheduleRepository, auditLogger ports.AuditLogger) *UpdateScheduleUseCase {
+	return &UpdateScheduleUseCase{
+		scheduleRepo: scheduleRepo,
+		auditLogger:  auditLogger,
+	}
+}
+
+// Execute replaces the schedule's settings. An SFTP destination given
+// without a password or private key keeps the credentials the schedule
+// has, so partners need not send them again to rename a report.
+func (uc *UpdateScheduleUseCase) Execute(ctx context.Context, input UpdateScheduleInput) (*entities.ReportSchedule, error) {
+	schedule, err := getSchedule(ctx, uc.scheduleRepo, input.PartnerID, input.Livemode, input.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := input.Settings
+	current := schedule.Destination
+	if settings.Destination.Type == valueobjects.ReportDestinationSFTP && current.Type == valueobjects.ReportDestinationSFTP &&
+		settings.Destination.Password == "" && settings.Destination.PrivateKey == "" {
+		settings.Destination.Password = current.Password
+		settings.Destination.PrivateKey = current.PrivateKey
+	}
+
+	if err := schedule.Reconfigure(settings, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	logScheduleAction(ctx, uc.auditLogger, "report_schedule_updated", schedule, input.IPAddress, input.UserAgent)
+	return schedule, nil
+}
+
+// ListSchedulesUseCase lists a partner's report schedules
+type ListSchedulesUseCase struct {
+	scheduleRepo ports.ReportScheduleRepository
+}
+
+// NewListSchedulesUseCase creates a new instance
+func NewListSchedulesUseCase(scheduleRepo ports.ReportScheduleRepository) *ListSchedulesUseCase {
+	return &ListSchedulesUseCase{scheduleRepo: scheduleRepo}
+}
+
+// Execute returns partnerID's schedules in livemode, oldest first
+func (uc *ListSchedulesUseCase) Execute(ctx context.Context, partnerID uuid.UUID, livemode bool) ([]*entities.ReportSchedule, error) {
+	schedules, err := uc.scheduleRepo.ListByPartner(ports.ReadOnly(ctx), partnerID, livemode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// GetScheduleUseCase retrieves a report schedule
+type GetScheduleUseCase struct {
+	scheduleRepo ports.ReportScheduleRepository
+}
+
+// NewGetScheduleUseCase creates a new instance
+func NewGetScheduleUseCase(scheduleRepo ports.ReportScheduleRepository) *GetScheduleUseCase {
+	return &GetScheduleUseCase{scheduleRepo: scheduleRepo}
+}
+
+// Execute returns partnerID's schedule in livemode
+func (uc *GetScheduleUseCase) Execute(ctx context.Context, partnerID uuid.UUID, livemode bool, scheduleID uuid.UUID) (*entities.ReportSchedule, error) {
+	return getSchedule(ports.ReadOnly(ctx), uc.scheduleRepo, partnerID, livemode, scheduleID)
+}
+
+// DeleteScheduleInput represents input for deleting a schedule
+type DeleteScheduleInput struct {
+	PartnerID  uuid.UUID
+	Livemode   bool
+	ScheduleID uuid.UUID
+	IPAddress  string
+	UserAgent  string
+}
+
+// DeleteScheduleUseCase deletes a report schedule
+type DeleteScheduleUseCase struct {
+	scheduleRepo ports.ReportScheduleRepository
+	auditLogger  ports.AuditLogger
+}
+
+// NewDeleteScheduleUseCase creates a new instance
+func NewDeleteScheduleUseCase(scheduleRepo ports.ReportScheduleRepository, auditLogger ports.AuditLogger) *DeleteScheduleUseCase {
+	return &DeleteScheduleUseCase{
+		scheduleRepo: scheduleRepo,
+		auditLogger:  auditLogger,
+	}
+}
+
+// Execute deletes the schedule; reports already delivered are kept by the
+// partner
+func (uc *DeleteScheduleUseCase) Execute(ctx context.Context, input DeleteScheduleInput) error {
+	schedule, err := getSchedule(ctx, uc.scheduleRepo, input.PartnerID, input.Livemode, input.ScheduleID)
+	if err != nil {
+		return err
+	}
+	if err := uc.scheduleRepo.Delete(ctx, schedule.ID); err != nil {
+		return err
+	}
+
+	logScheduleAction(ctx, uc.auditLogger, "report_schedule_deleted", schedule, input.IPAddress, input.UserAgent)
+	return nil
+}
+
+// getSchedule returns partnerID's schedule in livemode
+func getSchedule(ctx context.Context, scheduleRepo ports.ReportScheduleRepository, partnerID uuid.UUID, livemode bool, scheduleID uuid.UUID) (*entities.ReportSchedule, error) {
+	schedule, err := scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enforce partner and mode isolation
+	if schedule.PartnerID != partnerID || schedule.Livemode != livemode {
+		return nil, errors.ErrReportScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// logScheduleAction records action on schedule in the audit log, with where
+// it delivers but never its credentials
+func logScheduleAction(ctx context.Context, auditLogger ports.AuditLogger, action string, schedule *entities.ReportSchedule, ipAddress, userAgent string) {
+	if auditLogger == nil {
+		return
+	}
+	_ = auditLogger.LogAction(ctx, ports.AuditAction{
+		PartnerID:    schedule.PartnerID,
+		Action:       action,
+		ResourceType: "report_schedule",
+		ResourceID:   schedule.ID,
+		IPAddress:    ipAddress,
+		UserAgent:    userAgent,
+		Changes: map[string]interface{}{
+			"name":        schedule.Name,
+			"resource":    schedule.Resource,
+			"frequency":   schedule.Frequency,
+			"destination": schedule.Destination.Type,
+		},
+	})
+}
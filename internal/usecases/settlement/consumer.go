@@ -0,0 +1,144 @@
+package settlement
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// alertTimeout bounds sending one alert
+const alertTimeout = 10 * time.Second
+
+// DiscrepancyAlerts sets when people are alerted about discrepancies
+type DiscrepancyAlerts struct {
+	// Sink receives the alerts; nil turns them off
+	Sink ports.AlertSink
+	// An alert is raised once Threshold discrepancies were found within
+	// Window, at most once per window; a Threshold of 0 turns alerts off
+	Threshold int
+	Window    time.Duration
+	// OnError is told about alerts that could not be sent
+	OnError func(err error)
+}
+
+// Consumer reconciles the entries of a settlement feed as they arrive
+type Consumer struct {
+	feed      ports.SettlementFeed
+	reconcile *ReconcileUseCase
+	alerts    DiscrepancyAlerts
+
+	reconciled    atomic.Int64
+	discrepancies atomic.Int64
+	duplicates    atomic.Int64
+
+	// found are when the discrepancies within the alert window were found,
+	// and alertedAt when the last alert was sent; RunOnce is not called
+	// concurrently, so they need no lock
+	found     []time.Time
+	alertedAt time.Time
+}
+
+// ConsumerStats counts the entries reconciled since the consumer started
+type ConsumerStats struct {
+	// Reconciled entries were matched and saved, discrepancies included
+	Reconciled int64
+	// Discrepancies are the reconciled entries that disagree with the
+	// stored transactions
+	Discrepancies int64
+	// Duplicates were delivered again after being reconciled
+	Duplicates int64
+}
+
+// NewConsumer creates a consumer of feed
+func NewConsumer(feed ports.SettlementFeed, reconcile *ReconcileUseCase, alerts DiscrepancyAlerts) *Consumer {
+	return &Consumer{feed: feed, reconcile: reconcile, alerts: alerts}
+}
+
+// RunOnce reconciles the feed's next entries and commits them, returning
+// how many it received. On error nothing is committed, so the feed delivers
+// the entries again and those already reconciled are skipped.
+func (c *Consumer) RunOnce(ctx context.Context) (int, error) {
+	entries, err := c.feed.Next(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read settlement feed: %w", err)
+	}
+
+	var flagged []*entities.SettlementEntry
+	for _, entry := range entries {
+		reconciled, err := c.reconcile.Execute(ctx, entry)
+		if err != nil {
+			return 0, fmt.Errorf("failed to reconcile %s entry %s: %w", entry.Provider, entry.Reference, err)
+		}
+		switch {
+		case !reconciled:
+			c.duplicates.Add(1)
+		case entry.HasDiscrepancy():
+			c.reconciled.Add(1)
+			c.discrepancies.Add(1)
+			flagged = append(flagged, entry)
+		default:
+			c.reconciled.Add(1)
+		}
+	}
+
+	if err := c.feed.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit settlement feed: %w", err)
+	}
+	c.alertDiscrepancies(ctx, flagged)
+	return len(entries), nil
+}
+
+// alertDiscrepancies counts the discrepancies of a pass towards the alert
+// threshold and alerts once it is reached
+func (c *Consumer) alertDiscrepancies(ctx context.Context, flagged []*entities.SettlementEntry) {
+	if c.alerts.Sink == nil || c.alerts.Threshold == 0 || len(flagged) == 0 {
+		return
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-c.alerts.Window)
+	kept := c.found[:0]
+	for _, t := range c.found {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	for range flagged {
+		kept = append(kept, now)
+	}
+	c.found = kept
+	if len(c.found) < c.alerts.Threshold || now.Sub(c.alertedAt) < c.alerts.Window {
+		return
+	}
+	c.alertedAt = now
+
+	latest := flagged[len(flagged)-1]
+	alert := ports.Alert{
+		Title: "Settlement reconciliation found discrepancies",
+		Text: fmt.Sprintf("%d settlement entries disagreed with the stored transactions in the last %s. They are listed at GET /api/v1/admin/settlements/discrepancies.",
+			len(c.found), c.alerts.Window),
+		Fields: map[string]string{
+			"Latest entry":       string(latest.Provider) + " " + latest.Reference,
+			"Latest discrepancy": string(latest.Discrepancy),
+			"Latest detail":      latest.DiscrepancyDetail,
+		},
+	}
+	ctx, cancel := context.WithTimeout(ctx, alertTimeout)
+	defer cancel()
+	if err := c.alerts.Sink.Send(ctx, alert); err != nil && c.alerts.OnError != nil {
+		c.alerts.OnError(fmt.Errorf("failed to send discrepancy alert: %w", err))
+	}
+}
+
+// Stats returns the entries reconciled since the consumer started
+func (c *Consumer) Stats() ConsumerStats {
+	return ConsumerStats{
+		Reconciled:    c.reconciled.Load(),
+		Discrepancies: c.discrepancies.Load(),
+		Duplicates:    c.duplicates.Load(),
+	}
+}
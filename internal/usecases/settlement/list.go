@@ -0,0 +1,50 @@
+package settlement
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ListDisputesUseCase lists a partner's disputes
+type ListDisputesUseCase struct {
+	disputeRepo ports.DisputeRepository
+}
+
+// NewListDisputesUseCase creates a new instance
+func NewListDisputesUseCase(disputeRepo ports.DisputeRepository) *ListDisputesUseCase {
+	return &ListDisputesUseCase{disputeRepo: disputeRepo}
+}
+
+// Execute returns a page of partnerID's disputes, newest first, and their total
+func (uc *ListDisputesUseCase) Execute(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Dispute, int64, error) {
+	disputes, total, err := uc.disputeRepo.ListByPartner(ctx, partnerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list disputes: %w", err)
+	}
+	return disputes, total, nil
+}
+
+// ListDiscrepanciesUseCase lists the settlement entries that disagree with
+// the stored transactions
+type ListDiscrepanciesUseCase struct {
+	entryRepo ports.SettlementEntryRepository
+}
+
+// NewListDiscrepanciesUseCase creates a new instance
+func NewListDiscrepanciesUseCase(entryRepo ports.SettlementEntryRepository) *ListDiscrepanciesUseCase {
+	return &ListDiscrepanciesUseCase{entryRepo: entryRepo}
+}
+
+// Execute returns a page of the discrepancies matching query, newest first, and their total
+func (uc *ListDiscrepanciesUseCase) Execute(ctx context.Context, query ports.SettlementDiscrepancyQuery) ([]*entities.SettlementEntry, int64, error) {
+	entries, total, err := uc.entryRepo.ListDiscrepancies(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list settlement discrepancies: %w", err)
+	}
+	return entries, total, nil
+}
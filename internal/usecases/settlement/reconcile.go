@@ -0,0 +1,201 @@
+package settlement
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// disputeEventPayload is the data of dispute events
+type disputeEventPayload struct {
+	DisputeID         string `json:"dispute_id"`
+	TransactionID     string `json:"transaction_id"`
+	Status            string `json:"status"`
+	Amount            string `json:"amount"`
+	Currency          string `json:"currency"`
+	Reason            string `json:"reason,omitempty"`
+	ProviderReference string `json:"provider_reference"`
+	Livemode          bool   `json:"livemode"`
+}
+
+// ReconcileUseCase matches the entries of providers' settlement and
+// chargeback feeds against the stored transactions. Chargebacks open
+// disputes and their reversals close them; entries that disagree with the
+// transactions are kept as discrepancies for operations to review.
+type ReconcileUseCase struct {
+	entryRepo       ports.SettlementEntryRepository
+	disputeRepo     ports.DisputeRepository
+	transactionRepo ports.TransactionRepository
+	outboxRepo      ports.OutboxRepository
+	unitOfWork      ports.UnitOfWork
+}
+
+// NewReconcileUseCase creates a new instance
+func NewReconcileUseCase(
+	entryRepo ports.SettlementEntryRepository,
+	disputeRepo ports.DisputeRepository,
+	transactionRepo ports.TransactionRepository,
+	outboxRepo ports.OutboxRepository,
+	unitOfWork ports.UnitOfWork,
+) *ReconcileUseCase {
+	return &ReconcileUseCase{
+		entryRepo:       entryRepo,
+		disputeRepo:     disputeRepo,
+		transactionRepo: transactionRepo,
+		outboxRepo:      outboxRepo,
+		unitOfWork:      unitOfWork,
+	}
+}
+
+// Execute reconciles entry, recording the outcome on it, and reports
+// whether it did. An entry whose reference the provider already reported is
+// skipped, since feeds deliver entries at least once.
+func (uc *ReconcileUseCase) Execute(ctx context.Context, entry *entities.SettlementEntry) (bool, error) {
+	existing, err := uc.entryRepo.GetByReference(ctx, entry.Provider, entry.Reference)
+	if err != nil {
+		return false, fmt.Errorf("failed to get settlement entry: %w", err)
+	}
+	if existing != nil {
+		return false, nil
+	}
+
+	transaction, err := uc.findTransaction(ctx, entry)
+	if err != nil {
+		return false, err
+	}
+
+	err = uc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if transaction == nil {
+			entry.Flag(entities.DiscrepancyUnknownTransaction, "no "+entry.Provider.String()+" transaction "+entry.ProviderTransactionID)
+		} else {
+			entry.Match(transaction)
+			if err := uc.reconcile(ctx, entry, transaction); err != nil {
+				return err
+			}
+		}
+
+		entry.ReconciledAt = time.Now()
+		if err := uc.entryRepo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("failed to save settlement entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findTransaction returns the transaction entry is about, or nil when no
+// single transaction has its provider ID
+func (uc *ReconcileUseCase) findTransaction(ctx context.Context, entry *entities.SettlementEntry) (*entities.Transaction, error) {
+	provider := entry.Provider
+	transactions, _, err := uc.transactionRepo.List(ctx, ports.TransactionQuery{
+		Provider:              &provider,
+		ProviderTransactionID: entry.ProviderTransactionID,
+		Limit:                 2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	}
+	if len(transactions) != 1 {
+		return nil, nil
+	}
+	return transactions[0], nil
+}
+
+// reconcile compares entry with its transaction and applies chargebacks
+func (uc *ReconcileUseCase) reconcile(ctx context.Context, entry *entities.SettlementEntry, transaction *entities.Transaction) error {
+	if entry.Amount.Currency != transaction.Amount.Currency {
+		entry.Flag(entities.DiscrepancyCurrencyMismatch, fmt.Sprintf(
+			"entry is in %s, transaction in %s", entry.Amount.Currency, transaction.Amount.Currency,
+		))
+	}
+
+	switch entry.Type {
+	case entities.SettlementEntrySettlement:
+		switch {
+		case !transaction.IsCompleted():
+			entry.Flag(entities.DiscrepancyStatusMismatch, fmt.Sprintf("transaction is %s", transaction.Status))
+		case entry.HasDiscrepancy():
+		case !entry.Amount.Equals(transaction.Amount):
+			entry.Flag(entities.DiscrepancyAmountMismatch, fmt.Sprintf(
+				"settled %s, transaction amount %s", entry.Amount.Decimal(), transaction.Amount.Decimal(),
+			))
+		}
+		return nil
+
+	case entities.SettlementEntryChargeback:
+		if !entry.HasDiscrepancy() && entry.Amount.IsGreaterThan(transaction.Amount) {
+			entry.Flag(entities.DiscrepancyAmountMismatch, fmt.Sprintf(
+				"charged back %s, transaction amount %s", entry.Amount.Decimal(), transaction.Amount.Decimal(),
+			))
+		}
+		// The provider took the funds either way, so the dispute is opened
+		// even when the chargeback disagrees with the transaction
+		dispute, err := entities.NewDispute(transaction, entry)
+		if err != nil {
+			return err
+		}
+		if err := uc.disputeRepo.Create(ctx, dispute); err != nil {
+			return fmt.Errorf("failed to create dispute: %w", err)
+		}
+		return uc.addDisputeEvent(ctx, entities.EventDisputeCreated, dispute, transaction)
+
+	case entities.SettlementEntryChargebackReversal:
+		dispute, err := uc.disputeRepo.GetOpenByTransaction(ctx, transaction.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get dispute: %w", err)
+		}
+		if dispute == nil {
+			entry.Flag(entities.DiscrepancyUnknownDispute, "transaction has no open dispute")
+			return nil
+		}
+		if !entry.HasDiscrepancy() && !entry.Amount.Equals(dispute.Amount) {
+			entry.Flag(entities.DiscrepancyAmountMismatch, fmt.Sprintf(
+				"reversed %s, dispute amount %s", entry.Amount.Decimal(), dispute.Amount.Decimal(),
+			))
+		}
+		if err := dispute.Reverse(); err != nil {
+			return err
+		}
+		if err := uc.disputeRepo.Update(ctx, dispute); err != nil {
+			return fmt.Errorf("failed to update dispute: %w", err)
+		}
+		return uc.addDisputeEvent(ctx, entities.EventDisputeReversed, dispute, transaction)
+	}
+
+	return fmt.Errorf("unknown settlement entry type %q", entry.Type)
+}
+
+// addDisputeEvent records a dispute event in the outbox. Call it in the
+// unit of work that saves the dispute.
+func (uc *ReconcileUseCase) addDisputeEvent(
+	ctx context.Context,
+	eventType string,
+	dispute *entities.Dispute,
+	transaction *entities.Transaction,
+) error {
+	payload := disputeEventPayload{
+		DisputeID:         dispute.ID.String(),
+		TransactionID:     transaction.ID.String(),
+		Status:            string(dispute.Status),
+		Amount:            dispute.Amount.Decimal(),
+		Currency:          dispute.Amount.Currency.String(),
+		Reason:            dispute.Reason,
+		ProviderReference: dispute.ProviderReference,
+		Livemode:          transaction.Livemode,
+	}
+
+	event, err := entities.NewOutboxEvent(transaction.PartnerID, "dispute", dispute.ID, eventType, payload)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+	if err := uc.outboxRepo.Add(ctx, event); err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package statement
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Page sizes of what a statement is generated from; refunds and partners
+// have no stream
+const (
+	refundPageSize  = 500
+	partnerPageSize = 100
+)
+
+// GenerateStatementUseCase issues partners' monthly statements: their live
+// payments, refunds and chargebacks in the month, and the fees of the
+// partner's pricing, saved with a CSV document for invoicing. A statement
+// is issued once; generating it again returns the one issued.
+type GenerateStatementUseCase struct {
+	partnerRepo     ports.PartnerRepository
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	disputeRepo     ports.DisputeRepository
+	statementRepo   ports.StatementRepository
+	store           ports.ObjectStore
+	renderer        ports.StatementRenderer
+
+	// standard is the pricing of partners without their own
+	standard valueobjects.Pricing
+}
+
+// NewGenerateStatementUseCase creates a new instance. Documents are kept in
+// store; partners without a pricing of their own are charged standard.
+func NewGenerateStatementUseCase(
+	partnerRepo ports.PartnerRepository,
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	disputeRepo ports.DisputeRepository,
+	statementRepo ports.StatementRepository,
+	store ports.ObjectStore,
+	renderer ports.StatementRenderer,
+	standard valueobjects.Pricing,
+) *GenerateStatementUseCase {
+	return &GenerateStatementUseCase{
+		partnerRepo:     partnerRepo,
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		disputeRepo:     disputeRepo,
+		statementRepo:   statementRepo,
+		store:           store,
+		renderer:        renderer,
+		standard:        standard,
+	}
+}
+
+// Execute issues partnerID's statement of period, or returns the one
+// already issued. A month can only be issued once it is over.
+func (uc *GenerateStatementUseCase) Execute(ctx context.Context, partnerID uuid.UUID, period entities.StatementPeriod) (*entities.Statement, error) {
+	if _, err := entities.NewStatementPeriod(string(period)); err != nil {
+		return nil, err
+	}
+	if period.End().After(time.Now()) {
+		return nil, errors.NewValidationError("period", "must be a month that is over")
+	}
+
+	existing, err := uc.statementRepo.GetByPeriod(ctx, partnerID, period)
+	if err == nil {
+		return existing, nil
+	}
+	if !stderrors.Is(err, errors.ErrStatementNotFound) {
+		return nil, fmt.Errorf("failed to get statement: %w", err)
+	}
+
+	partner, err := uc.partnerRepo.GetByID(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+	return uc.generate(ctx, partner, period)
+}
+
+// GenerateDue issues the statements of last month, as of now, that every
+// partner is due and returns how many it issued. A partner failing is
+// reported after the others are issued.
+func (uc *GenerateStatementUseCase) GenerateDue(ctx context.Context, now time.Time) (int, error) {
+	period := entities.StatementPeriodOf(now).Previous()
+
+	issued := 0
+	var failed error
+	for offset := 0; ; offset += partnerPageSize {
+		partners, err := uc.partnerRepo.List(ports.ReadOnly(ctx), partnerPageSize, offset)
+		if err != nil {
+			return issued, fmt.Errorf("failed to list partners: %w", err)
+		}
+		for _, partner := range partners {
+			_, err := uc.statementRepo.GetByPeriod(ctx, partner.ID, period)
+			if err == nil {
+				continue
+			}
+			if stderrors.Is(err, errors.ErrStatementNotFound) {
+				_, err = uc.generate(ctx, partner, period)
+			}
+			if err != nil {
+				if failed == nil {
+					failed = fmt.Errorf("failed to issue the %s statement of partner %s: %w", period, partner.ID, err)
+				}
+				continue
+			}
+			issued++
+		}
+		if len(partners) < partnerPageSize {
+			return issued, failed
+		}
+	}
+}
+
+// PricingOf returns the pricing partner's statements charge, and whether
+// it is the platform's standard pricing
+func (uc *GenerateStatementUseCase) PricingOf(partner *entities.Partner) (valueobjects.Pricing, bool) {
+	if partner.Pricing == nil {
+		return uc.standard, true
+	}
+	return *partner.Pricing, false
+}
+
+// generate builds, stores and saves partner's statement of period. The
+// document is stored first and under the period, so a statement is never
+// saved without one and a second instance issuing the same statement
+// overwrites it with the same figures.
+func (uc *GenerateStatementUseCase) generate(ctx context.Context, partner *entities.Partner, period entities.StatementPeriod) (*entities.Statement, error) {
+	pricing, _ := uc.PricingOf(partner)
+	statement, err := entities.NewStatement(partner, period, pricing)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.addPayments(ctx, statement); err != nil {
+		return nil, err
+	}
+	if err := uc.addRefunds(ctx, statement); err != nil {
+		return nil, err
+	}
+	if err := uc.addDisputes(ctx, statement); err != nil {
+		return nil, err
+	}
+	statement.Close()
+
+	document, err := uc.renderer.Render(statement, partner)
+	if err != nil {
+		return nil, err
+	}
+	statement.DocumentKey = fmt.Sprintf("statements/%s/%s.csv", partner.ID, period)
+	if err := uc.store.Put(ctx, statement.DocumentKey, document); err != nil {
+		return nil, fmt.Errorf("failed to store statement: %w", err)
+	}
+
+	if err := uc.statementRepo.Create(ctx, statement); err != nil {
+		if stderrors.Is(err, errors.ErrStatementExists) {
+			return uc.statementRepo.GetByPeriod(ctx, partner.ID, period)
+		}
+		return nil, fmt.Errorf("failed to save statement: %w", err)
+	}
+	return statement, nil
+}
+
+// addPayments adds the live payments made in the month that were paid,
+// oldest first so the tiers fill in order
+func (uc *GenerateStatementUseCase) addPayments(ctx context.Context, statement *entities.Statement) error {
+	livemode := true
+	from, to := statement.Period.Start(), statement.Period.End()
+	query := ports.TransactionQuery{
+		PartnerID:   &statement.PartnerID,
+		Livemode:    &livemode,
+		Statuses:    []entities.TransactionStatus{entities.StatusCompleted, entities.StatusPartiallyRefunded, entities.StatusRefunded},
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		OrderBy:     ports.TransactionOrderCreatedAt,
+		Ascending:   true,
+	}
+	err := uc.transactionRepo.Stream(ports.ReadOnly(ctx), query, statement.AddPayment)
+	if err != nil {
+		return fmt.Errorf("failed to read statement payments: %w", err)
+	}
+	return nil
+}
+
+// addRefunds adds the live refunds completed in the month
+func (uc *GenerateStatementUseCase) addRefunds(ctx context.Context, statement *entities.Statement) error {
+	status := entities.RefundStatusCompleted
+	from, to := statement.Period.Start(), statement.Period.End()
+	query := ports.RefundQuery{
+		PartnerID:   statement.PartnerID,
+		Livemode:    true,
+		Status:      &status,
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Limit:       refundPageSize,
+	}
+	for {
+		refunds, err := uc.refundRepo.List(ports.ReadOnly(ctx), query)
+		if err != nil {
+			return fmt.Errorf("failed to read statement refunds: %w", err)
+		}
+		for _, refund := range refunds {
+			statement.AddRefund(refund)
+		}
+		if len(refunds) < refundPageSize {
+			return nil
+		}
+		query.Offset += refundPageSize
+	}
+}
+
+// addDisputes adds the disputes opened in the month as chargebacks, and
+// those reversed in it as reversals
+func (uc *GenerateStatementUseCase) addDisputes(ctx context.Context, statement *entities.Statement) error {
+	from, to := statement.Period.Start(), statement.Period.End()
+	disputes, err := uc.disputeRepo.ListOpenedOrReversed(ports.ReadOnly(ctx), statement.PartnerID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to read statement disputes: %w", err)
+	}
+	within := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+	for _, dispute := range disputes {
+		if within(dispute.CreatedAt) {
+			statement.AddChargeback(dispute)
+		}
+		if dispute.ReversedAt != nil && within(*dispute.ReversedAt) {
+			statement.AddChargebackReversal(dispute)
+		}
+	}
+	return nil
+}
+
+// ListStatementsUseCase lists partners' statements
+type ListStatementsUseCase struct {
+	statementRepo ports.StatementRepository
+}
+
+// NewListStatementsUseCase creates a new instance
+func NewListStatementsUseCase(statementRepo ports.StatementRepository) *ListStatementsUseCase {
+	return &ListStatementsUseCase{statementRepo: statementRepo}
+}
+
+// Execute returns a page of partnerID's statements, latest first, and their total
+func (uc *ListStatementsUseCase) Execute(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*entities.Statement, int64, error) {
+	statements, total, err := uc.statementRepo.ListByPartner(ctx, partnerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list statements: %w", err)
+	}
+	return statements, total, nil
+}
+
+// ByPeriod returns a page of every partner's statement of period, by
+// partner, and their total, for invoicing
+func (uc *ListStatementsUseCase) ByPeriod(ctx context.Context, period entities.StatementPeriod, limit, offset int) ([]*entities.Statement, int64, error) {
+	statements, total, err := uc.statementRepo.ListByPeriod(ctx, period, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list statements: %w", err)
+	}
+	return statements, total, nil
+}
+
+// GetStatementUseCase retrieves a statement and its document
+type GetStatementUseCase struct {
+	statementRepo ports.StatementRepository
+	store         ports.ObjectStore
+}
+
+// NewGetStatementUseCase creates a new instance reading documents from store
+func NewGetStatementUseCase(statementRepo ports.StatementRepository, store ports.ObjectStore) *GetStatementUseCase {
+	return &GetStatementUseCase{statementRepo: statementRepo, store: store}
+}
+
+// Execute returns statement id. A partnerID other than uuid.Nil must own
+// it, so partners cannot tell another's statements exist.
+func (uc *GetStatementUseCase) Execute(ctx context.Context, partnerID, id uuid.UUID) (*entities.Statement, error) {
+	statement, err := uc.statementRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if partnerID != uuid.Nil && statement.PartnerID != partnerID {
+		return nil, errors.ErrStatementNotFound
+	}
+	return statement, nil
+}
+
+// Document returns statement id, as Execute does, and its CSV document
+func (uc *GetStatementUseCase) Document(ctx context.Context, partnerID, id uuid.UUID) (*entities.Statement, []byte, error) {
+	statement, err := uc.Execute(ctx, partnerID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	document, err := uc.store.Get(ctx, statement.DocumentKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read statement: %w", err)
+	}
+	return statement, document, nil
+}
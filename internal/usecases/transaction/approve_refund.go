@@ -0,0 +1,185 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ApproveRefundInput represents the input for approving a held refund
+type ApproveRefundInput struct {
+	RefundID   uuid.UUID
+	ApprovedBy string
+	// PartnerID restricts partner team approvals to the partner's own refunds;
+	// nil for back-office admins
+	PartnerID *uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// ApproveRefundUseCase releases refunds held for manual approval to the gateway
+type ApproveRefundUseCase struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	outboxRepo      ports.OutboxRepository
+	unitOfWork      ports.UnitOfWork
+	paymentGateway  ports.PaymentGateway
+	auditLogger     ports.AuditLogger
+}
+
+// NewApproveRefundUseCase creates a new instance
+func NewApproveRefundUseCase(
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	outboxRepo ports.OutboxRepository,
+	unitOfWork ports.UnitOfWork,
+	paymentGateway ports.PaymentGateway,
+	auditLogger ports.AuditLogger,
+) *ApproveRefundUseCase {
+	return &ApproveRefundUseCase{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		outboxRepo:      outboxRepo,
+		unitOfWork:      unitOfWork,
+		paymentGateway:  paymentGateway,
+		auditLogger:     auditLogger,
+	}
+}
+
+// Execute approves a refund and processes it through the payment gateway
+func (uc *ApproveRefundUseCase) Execute(ctx context.Context, input ApproveRefundInput) (*entities.Refund, error) {
+	// Step 1: Retrieve refund
+	refund, err := uc.refundRepo.GetByID(ctx, input.RefundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund == nil {
+		return nil, errors.ErrRefundNotFound
+	}
+
+	// Step 2: Retrieve the refunded transaction
+	transaction, err := uc.transactionRepo.GetByID(ctx, refund.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if transaction == nil {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	if input.PartnerID != nil && transaction.PartnerID != *input.PartnerID {
+		return nil, errors.ErrRefundNotFound
+	}
+
+	// Step 3: Approve refund (fails unless it is awaiting approval)
+	if err := refund.Approve(input.ApprovedBy); err != nil {
+		return nil, err
+	}
+
+	// Step 4: Business Rule - transaction may have changed while the refund was held
+	// (the amount itself stays reserved from the moment the refund was requested).
+	// A refund that can no longer be made is rejected, so it neither waits for
+	// an approval that cannot succeed nor keeps its amount reserved.
+	if !transaction.IsRefundable() {
+		if err := uc.reject(ctx, refund, transaction, input); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrRefundNotAllowed
+	}
+
+	// Step 5: Persist approval
+	if err := uc.refundRepo.Update(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	// Step 6: Log approver identity before the refund leaves the system
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    transaction.PartnerID,
+			Action:       "refund_approved",
+			ResourceType: "refund",
+			ResourceID:   refund.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"transaction_id": transaction.ID.String(),
+				"amount":         refund.Amount,
+				"approved_by":    input.ApprovedBy,
+			},
+		})
+	}
+
+	// Step 7: Process refund through the gateway and update the transaction
+	processor := refundProcessor{
+		transactionRepo: uc.transactionRepo,
+		refundRepo:      uc.refundRepo,
+		outboxRepo:      uc.outboxRepo,
+		unitOfWork:      uc.unitOfWork,
+		paymentGateway:  uc.paymentGateway,
+	}
+	providerRefundID, err := processor.process(ctx, refund, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 8: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    transaction.PartnerID,
+			Action:       "refund_completed",
+			ResourceType: "refund",
+			ResourceID:   refund.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"transaction_id":     transaction.ID.String(),
+				"amount":             refund.Amount,
+				"provider_refund_id": providerRefundID,
+				"transaction_status": transaction.Status,
+				"approved_by":        input.ApprovedBy,
+			},
+		})
+	}
+
+	return refund, nil
+}
+
+// reject fails an approved refund whose transaction is no longer refundable
+// and returns its reserved amount to the transaction in one unit of work
+func (uc *ApproveRefundUseCase) reject(ctx context.Context, refund *entities.Refund, transaction *entities.Transaction, input ApproveRefundInput) error {
+	if err := refund.MarkAsFailed("TRANSACTION_NOT_REFUNDABLE", "transaction is no longer refundable"); err != nil {
+		return fmt.Errorf("failed to mark refund as failed: %w", err)
+	}
+
+	err := uc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		return uc.refundRepo.Release(ctx, refund)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release refund: %w", err)
+	}
+
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    transaction.PartnerID,
+			Action:       "refund_rejected",
+			ResourceType: "refund",
+			ResourceID:   refund.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"transaction_id":     transaction.ID.String(),
+				"amount":             refund.Amount,
+				"transaction_status": transaction.Status,
+				"approved_by":        input.ApprovedBy,
+			},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,341 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// BulkRefundInput represents the input for a bulk refund
+type BulkRefundInput struct {
+	PartnerID      uuid.UUID
+	TransactionIDs []uuid.UUID
+	ReasonCode     string
+	ReasonNote     string
+	Livemode       bool
+	IPAddress      string
+	UserAgent      string
+}
+
+// BulkRefundUseCase queues a batch of transactions to be fully refunded by
+// a BulkRefundWorker
+type BulkRefundUseCase struct {
+	partnerRepo ports.PartnerRepository
+	jobRepo     ports.BulkRefundJobRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewBulkRefundUseCase creates a new instance
+func NewBulkRefundUseCase(
+	partnerRepo ports.PartnerRepository,
+	jobRepo ports.BulkRefundJobRepository,
+	auditLogger ports.AuditLogger,
+) *BulkRefundUseCase {
+	return &BulkRefundUseCase{
+		partnerRepo: partnerRepo,
+		jobRepo:     jobRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute creates a bulk refund job; it is due for a worker at once
+func (uc *BulkRefundUseCase) Execute(ctx context.Context, input BulkRefundInput) (*entities.BulkRefundJob, error) {
+	// Step 1: Check the partner has bulk refunds enabled
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+
+	if !partner.HasFeature(valueobjects.FeatureBulkRefunds) {
+		return nil, errors.ErrFeatureDisabled
+	}
+
+	// Step 2: Create job entity
+	reason, err := valueobjects.NewRefundReason(input.ReasonCode, input.ReasonNote)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := entities.NewBulkRefundJob(input.PartnerID, input.TransactionIDs, reason)
+	if err != nil {
+		return nil, err
+	}
+	job.Livemode = input.Livemode
+	job.IPAddress = input.IPAddress
+	job.UserAgent = input.UserAgent
+
+	// Step 3: Persist job before returning its ID to the caller; workers
+	// pick it up from there
+	if err := uc.jobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create bulk refund job: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "bulk_refund_requested",
+			ResourceType: "bulk_refund_job",
+			ResourceID:   job.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"transaction_count": len(job.TransactionIDs),
+				"reason":            string(job.Reason.Code),
+			},
+		})
+	}
+
+	return job, nil
+}
+
+// BulkRefundWorker runs queued bulk refund jobs. Every item is saved as
+// processing before its refund is made and with its outcome after, so a
+// job whose worker stopped is resumed where it was: after its claim times
+// out, another pass claims it again, and a refund made just before the
+// stop is recorded rather than made twice.
+//
+// Several API instances can share the jobs: a pass claims its jobs until
+// claimTimeout from now, and extends the claim as each item is saved.
+type BulkRefundWorker struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	jobRepo         ports.BulkRefundJobRepository
+	refundUseCase   *RefundTransactionUseCase
+
+	batchSize    int
+	claimTimeout time.Duration
+}
+
+// NewBulkRefundWorker creates a worker running up to batchSize jobs a pass,
+// one at a time. claimTimeout must be longer than one refund takes.
+func NewBulkRefundWorker(
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	jobRepo ports.BulkRefundJobRepository,
+	refundUseCase *RefundTransactionUseCase,
+	batchSize int,
+	claimTimeout time.Duration,
+) *BulkRefundWorker {
+	return &BulkRefundWorker{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		jobRepo:         jobRepo,
+		refundUseCase:   refundUseCase,
+		batchSize:       batchSize,
+		claimTimeout:    claimTimeout,
+	}
+}
+
+// RunDue runs the jobs that are due and returns how many it finished. Once
+// stopping is closed, as when the server shuts down, the refund in flight
+// is finished and saved, and the jobs claimed are handed back, due at once
+// for the next worker. Only errors saving a job are returned.
+func (w *BulkRefundWorker) RunDue(ctx context.Context, stopping <-chan struct{}) (int, error) {
+	jobs, err := w.jobRepo.ClaimDue(ctx, w.batchSize, time.Now().Add(w.claimTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim bulk refund jobs: %w", err)
+	}
+
+	finished := 0
+	for _, job := range jobs {
+		if err := w.run(ctx, job, stopping); err != nil {
+			return finished, fmt.Errorf("failed to save bulk refund job %s: %w", job.ID, err)
+		}
+		if job.IsCompleted() {
+			finished++
+		}
+	}
+	return finished, nil
+}
+
+// run refunds the transactions of job left to refund, saving it after
+// every step
+func (w *BulkRefundWorker) run(ctx context.Context, job *entities.BulkRefundJob, stopping <-chan struct{}) error {
+	if job.Status == entities.BulkRefundJobStatusPending {
+		if err := job.Start(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		transactionID, ok := job.Next()
+		if !ok {
+			break
+		}
+		select {
+		case <-stopping:
+			job.NextAttemptAt = time.Now()
+			return w.jobRepo.Update(ctx, job)
+		default:
+		}
+
+		var item entities.BulkRefundItem
+		if inFlight := job.InFlight(); inFlight != nil {
+			var done bool
+			if item, done = w.resume(ctx, job, *inFlight); !done {
+				// The refund is still with the provider; the job waits
+				// for its outcome and is tried again after the claim
+				return w.save(ctx, job)
+			}
+		} else {
+			job.StartItem(transactionID)
+			if err := w.save(ctx, job); err != nil {
+				return err
+			}
+			item = w.refundOne(ctx, job, *job.InFlight().RefundID, transactionID)
+		}
+		job.RecordResult(item)
+		if err := w.save(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	job.Complete()
+	return w.jobRepo.Update(ctx, job)
+}
+
+// save saves job's progress and extends its claim
+func (w *BulkRefundWorker) save(ctx context.Context, job *entities.BulkRefundJob) error {
+	job.NextAttemptAt = time.Now().Add(w.claimTimeout)
+	return w.jobRepo.Update(ctx, job)
+}
+
+// resume finishes an item whose worker stopped during its refund. The
+// refund created with the item's ID is its outcome, or, when none was, the
+// refund is made now. A refund still pending or processing has no outcome
+// yet, and resume returns false, as it does when the refund cannot be read.
+func (w *BulkRefundWorker) resume(ctx context.Context, job *entities.BulkRefundJob, inFlight entities.BulkRefundItem) (entities.BulkRefundItem, bool) {
+	if inFlight.RefundID == nil {
+		return w.resumeUnrecorded(ctx, job, inFlight)
+	}
+
+	refund, err := w.refundRepo.GetByID(ctx, *inFlight.RefundID)
+	if err == errors.ErrRefundNotFound {
+		return w.refundOne(ctx, job, *inFlight.RefundID, inFlight.TransactionID), true
+	}
+	if err != nil {
+		return inFlight, false
+	}
+	return refundOutcome(inFlight.TransactionID, refund)
+}
+
+// resumeUnrecorded finishes an item started before items recorded the ID
+// of their refund: a refund of the transaction made since the item
+// started is taken as its outcome
+func (w *BulkRefundWorker) resumeUnrecorded(ctx context.Context, job *entities.BulkRefundJob, inFlight entities.BulkRefundItem) (entities.BulkRefundItem, bool) {
+	refunds, err := w.refundRepo.List(ctx, ports.RefundQuery{
+		PartnerID:     job.PartnerID,
+		Livemode:      job.Livemode,
+		TransactionID: &inFlight.TransactionID,
+		CreatedFrom:   inFlight.StartedAt,
+		Limit:         1,
+	})
+	if err != nil {
+		return inFlight, false
+	}
+	if len(refunds) > 0 {
+		return refundOutcome(inFlight.TransactionID, refunds[0])
+	}
+	return w.refundOne(ctx, job, uuid.New(), inFlight.TransactionID), true
+}
+
+// refundOutcome is the item of a refund of transactionID, and false while
+// the refund is pending or processing
+func refundOutcome(transactionID uuid.UUID, refund *entities.Refund) (entities.BulkRefundItem, bool) {
+	item := entities.BulkRefundItem{TransactionID: transactionID, RefundID: &refund.ID, Status: string(refund.Status)}
+	switch refund.Status {
+	case entities.RefundStatusPending, entities.RefundStatusProcessing:
+		return item, false
+	case entities.RefundStatusFailed, entities.RefundStatusCancelled:
+		item.Status = "failed"
+		item.Error = refund.ErrorMessage
+		if item.Error == "" {
+			item.Error = "refund " + string(refund.Status)
+		}
+	}
+	return item, true
+}
+
+// refundOne issues a full refund of the remaining amount on one
+// transaction, created with refundID
+func (w *BulkRefundWorker) refundOne(ctx context.Context, job *entities.BulkRefundJob, refundID, transactionID uuid.UUID) entities.BulkRefundItem {
+	item := entities.BulkRefundItem{TransactionID: transactionID}
+
+	transaction, err := w.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		item.Status = "failed"
+		item.Error = err.Error()
+		return item
+	}
+
+	// Report other partners' (or the other mode's) transactions as missing rather than leaking them
+	if transaction == nil || transaction.PartnerID != job.PartnerID || transaction.Livemode != job.Livemode {
+		item.Status = "failed"
+		item.Error = errors.ErrTransactionNotFound.Error()
+		return item
+	}
+
+	remaining := transaction.RefundableAmount()
+	if remaining <= 0 {
+		item.Status = "failed"
+		item.Error = errors.ErrRefundAmountExceeded.Error()
+		return item
+	}
+
+	refund, err := w.refundUseCase.Execute(ctx, RefundTransactionInput{
+		TransactionID: transaction.ID,
+		PartnerID:     job.PartnerID,
+		Amount:        remaining,
+		Currency:      transaction.Amount.Currency.String(),
+		ReasonCode:    string(job.Reason.Code),
+		ReasonNote:    job.Reason.Note,
+		Livemode:      job.Livemode,
+		IPAddress:     job.IPAddress,
+		UserAgent:     job.UserAgent,
+		RefundID:      refundID,
+	})
+	if err != nil {
+		item.Status = "failed"
+		item.Error = err.Error()
+		return item
+	}
+
+	item.RefundID = &refund.ID
+	item.Status = string(refund.Status)
+	return item
+}
+
+// GetBulkRefundJobUseCase retrieves bulk refund job progress
+type GetBulkRefundJobUseCase struct {
+	jobRepo ports.BulkRefundJobRepository
+}
+
+// NewGetBulkRefundJobUseCase creates a new instance
+func NewGetBulkRefundJobUseCase(jobRepo ports.BulkRefundJobRepository) *GetBulkRefundJobUseCase {
+	return &GetBulkRefundJobUseCase{
+		jobRepo: jobRepo,
+	}
+}
+
+// Execute retrieves a bulk refund job owned by the partner, in the mode of
+// the key asking; jobs of the other mode are not found
+func (uc *GetBulkRefundJobUseCase) Execute(ctx context.Context, jobID uuid.UUID, partnerID uuid.UUID, livemode bool) (*entities.BulkRefundJob, error) {
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil || job.PartnerID != partnerID || job.Livemode != livemode {
+		return nil, errors.ErrBulkRefundJobNotFound
+	}
+
+	return job, nil
+}
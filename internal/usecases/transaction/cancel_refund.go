@@ -0,0 +1,94 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// CancelRefundInput represents the input for cancelling a refund
+type CancelRefundInput struct {
+	RefundID  uuid.UUID
+	PartnerID uuid.UUID
+	Livemode  bool
+	IPAddress string
+	UserAgent string
+}
+
+// CancelRefundUseCase cancels refunds that have not reached the provider yet
+type CancelRefundUseCase struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	auditLogger     ports.AuditLogger
+}
+
+// NewCancelRefundUseCase creates a new instance
+func NewCancelRefundUseCase(
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	auditLogger ports.AuditLogger,
+) *CancelRefundUseCase {
+	return &CancelRefundUseCase{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		auditLogger:     auditLogger,
+	}
+}
+
+// Execute cancels a pending refund
+func (uc *CancelRefundUseCase) Execute(ctx context.Context, input CancelRefundInput) (*entities.Refund, error) {
+	// Step 1: Retrieve refund
+	refund, err := uc.refundRepo.GetByID(ctx, input.RefundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund == nil {
+		return nil, errors.ErrRefundNotFound
+	}
+
+	// Step 2: Authorization - verify partner owns the refunded transaction
+	transaction, err := uc.transactionRepo.GetByID(ctx, refund.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if transaction == nil || transaction.PartnerID != input.PartnerID || transaction.Livemode != input.Livemode {
+		return nil, errors.ErrRefundNotFound
+	}
+
+	// Step 3: Cancel refund (fails once it has been sent to the provider)
+	previousStatus := refund.Status
+	if err := refund.Cancel(); err != nil {
+		return nil, err
+	}
+
+	// Step 4: Persist and return the reserved amount to the transaction
+	if err := uc.refundRepo.Release(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	// Step 5: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "refund_cancelled",
+			ResourceType: "refund",
+			ResourceID:   refund.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"transaction_id":  transaction.ID.String(),
+				"previous_status": previousStatus,
+				"status":          refund.Status,
+			},
+		})
+	}
+
+	return refund, nil
+}
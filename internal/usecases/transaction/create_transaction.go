@@ -1,269 +1,366 @@
-package transaction
 // Package transaction contains use cases for transaction operations
 // Use cases orchestrate the flow of data to/from entities
 // They contain application-specific business rules
 package transaction
 
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-}	return transactions, total, nil		}		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)	if err != nil {	transactions, total, err := uc.transactionRepo.List(ctx, filter)		}		filter.Limit = 100 // Max 100 per page	if filter.Limit > 100 {	}		filter.Limit = 20	if filter.Limit == 0 {	// Set default pagination if not provided		filter.PartnerID = &partnerID	// Enforce partner isolationfunc (uc *ListTransactionsUseCase) Execute(ctx context.Context, partnerID uuid.UUID, filter ports.TransactionFilter) ([]*entities.Transaction, int64, error) {// Execute lists transactions with filters}	}		transactionRepo: transactionRepo,	return &ListTransactionsUseCase{func NewListTransactionsUseCase(transactionRepo ports.TransactionRepository) *ListTransactionsUseCase {// NewListTransactionsUseCase creates a new instance}	transactionRepo ports.TransactionRepositorytype ListTransactionsUseCase struct {// ListTransactionsUseCase handles listing transactions with filtering}	return transaction, nil		}		_ = uc.cache.Set(ctx, cacheKey, transaction, 300)		cacheKey := fmt.Sprintf("transaction:%s", transactionID.String())	if uc.cache != nil {	// Cache the result (TTL: 5 minutes)		}		return nil, errors.ErrUnauthorizedOperation	if transaction.PartnerID != partnerID {	// Authorization: Verify partner owns this transaction		}		return nil, errors.ErrTransactionNotFound	if transaction == nil {		}		return nil, fmt.Errorf("failed to get transaction: %w", err)	if err != nil {	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)	// Get from database		}		}			}				return txn, nil				}					return nil, errors.ErrUnauthorizedOperation				if txn.PartnerID != partnerID {				// Verify partner owns this transaction (authorization)			if txn, ok := cached.(*entities.Transaction); ok {		if cached != nil {		cached, _ := uc.cache.Get(ctx, cacheKey)		cacheKey := fmt.Sprintf("transaction:%s", transactionID.String())	if uc.cache != nil {	// Try cache first (if available)func (uc *GetTransactionUseCase) Execute(ctx context.Context, transactionID uuid.UUID, partnerID uuid.UUID) (*entities.Transaction, error) {// Execute retrieves a transaction by ID}	}		cache:           cache,		transactionRepo: transactionRepo,	return &GetTransactionUseCase{) *GetTransactionUseCase {	cache ports.CacheService,	transactionRepo ports.TransactionRepository,func NewGetTransactionUseCase(// NewGetTransactionUseCase creates a new instance}	cache           ports.CacheService	transactionRepo ports.TransactionRepositorytype GetTransactionUseCase struct {// GetTransactionUseCase handles the business logic for retrieving a transaction}	}, nil		CreatedAt:     transaction.CreatedAt,		Status:        string(transaction.Status),		TransactionID: transaction.ID,	return &CreateTransactionOutput{	// Step 10: Return output		}		})			},				"status":   transaction.Status,				"currency": input.Currency,				"amount":   input.Amount,			Changes: map[string]interface{}{			RequestID:    transaction.RequestID,			UserAgent:    input.UserAgent,			IPAddress:    input.IPAddress,			ResourceID:   transaction.ID,			ResourceType: "transaction",			Action:       "create_transaction",			PartnerID:    input.PartnerID,		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{	if uc.auditLogger != nil {	// Step 9: Log audit event		}		return nil, fmt.Errorf("failed to create transaction: %w", err)	if err := uc.transactionRepo.Create(ctx, transaction); err != nil {	// Step 8: Persist transaction		transaction.UserAgent = input.UserAgent	transaction.IPAddress = input.IPAddress	}		}			transaction.SetMetadata(key, value)		for key, value := range input.Metadata {	if input.Metadata != nil {	}		transaction.Description = input.Description	if input.Description != "" {	}		transaction.CustomerPhone = input.CustomerPhone	if input.CustomerPhone != "" {	}		transaction.CustomerName = input.CustomerName	if input.CustomerName != "" {	// Step 7: Set optional fields		}		return nil, fmt.Errorf("failed to create transaction entity: %w", err)	if err != nil {	)		input.CustomerEmail,		provider,		paymentMethod,		money,		input.IdempotencyKey,		input.PartnerID,	transaction, err := entities.NewTransaction(	// Step 6: Create Transaction entity (validates business rules)		}		return nil, fmt.Errorf("invalid payment provider: %w", err)	if err != nil {	provider, err := valueobjects.NewPaymentProvider(input.Provider)	// Step 5: Create PaymentProvider value object (validates provider)		}		return nil, fmt.Errorf("invalid payment method: %w", err)	if err != nil {	paymentMethod, err := valueobjects.NewPaymentMethod(input.PaymentMethod)	// Step 4: Create PaymentMethod value object (validates payment method)		}		return nil, fmt.Errorf("invalid money: %w", err)	if err != nil {	money, err := valueobjects.NewMoney(input.Amount, input.Currency)	// Step 3: Create Money value object (validates amount and currency)		}		}, nil			CreatedAt:     existingTxn.CreatedAt,			Status:        string(existingTxn.Status),			TransactionID: existingTxn.ID,		return &CreateTransactionOutput{		// Return existing transaction (idempotent behavior)	if err == nil && existingTxn != nil {	existingTxn, err := uc.transactionRepo.GetByIdempotencyKey(ctx, input.PartnerID, input.IdempotencyKey)	// Step 2: Check for duplicate transaction (idempotency)		}		return nil, errors.ErrPartnerInactive	if !partner.IsActive {		}		return nil, errors.ErrPartnerNotFound	if partner == nil {		}		return nil, fmt.Errorf("failed to get partner: %w", err)	if err != nil {	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)	// Step 1: Validate partner exists and is activefunc (uc *CreateTransactionUseCase) Execute(ctx context.Context, input CreateTransactionInput) (*CreateTransactionOutput, error) {// This is the main orchestration logic// Execute executes the create transaction use case}	}		cache:           cache,		auditLogger:     auditLogger,		paymentGateway:  paymentGateway,		partnerRepo:     partnerRepo,		transactionRepo: transactionRepo,	return &CreateTransactionUseCase{) *CreateTransactionUseCase {	cache ports.CacheService,	auditLogger ports.AuditLogger,	paymentGateway ports.PaymentGateway,	partnerRepo ports.PartnerRepository,	transactionRepo ports.TransactionRepository,func NewCreateTransactionUseCase(// Dependency Injection: all dependencies are interfaces (ports)// NewCreateTransactionUseCase creates a new instance of the use case}	cache           ports.CacheService	auditLogger     ports.AuditLogger	paymentGateway  ports.PaymentGateway	partnerRepo     ports.PartnerRepository	transactionRepo ports.TransactionRepositorytype CreateTransactionUseCase struct {// CreateTransactionUseCase handles the business logic for creating transactions}	CreatedAt     time.Time	Status        string	TransactionID uuid.UUIDtype CreateTransactionOutput struct {// CreateTransactionOutput represents the output of transaction creation}	UserAgent      string	IPAddress      string	Metadata       map[string]interface{}	Description    string	CustomerPhone  string	CustomerName   string	CustomerEmail  string	Provider       string	PaymentMethod  string	Currency       string	Amount         float64	IdempotencyKey string	PartnerID      uuid.UUIDtype CreateTransactionInput struct {// CreateTransactionInput represents the input for creating a transaction)	"Pay2Go/internal/usecases/ports"	"Pay2Go/internal/domain/valueobjects"	"Pay2Go/internal/domain/errors"	"Pay2Go/internal/domain/entities"	"github.com/google/uuid"	"time"	"fmt"	"context"import (
\ No newline at end of file
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// CreateTransactionInput represents the input for creating a transaction
+type CreateTransactionInput struct {
+	PartnerID            uuid.UUID
+	IdempotencyKey       string
+	Amount               int64 // Minor units of Currency, e.g. cents
+	Currency             string
+	PaymentMethod        string
+	Provider             string
+	PaymentMethodDetails *valueobjects.PaymentMethodDetails // Optional card, bank account or wallet used
+	CustomerEmail        string
+	CustomerName         string
+	CustomerPhone        string
+	BillingCountry       string // ISO 3166-1 code; required by some providers
+	Description          string
+	Metadata             map[string]interface{}
+	Livemode             bool
+	IPAddress            string
+	UserAgent            string
+}
+
+// CreateTransactionOutput represents the output of transaction creation
+type CreateTransactionOutput struct {
+	TransactionID uuid.UUID
+	Status        string
+	Livemode      bool
+	CreatedAt     time.Time
+}
+
+// CreateTransactionUseCase handles the business logic for creating transactions
+type CreateTransactionUseCase struct {
+	transactionRepo ports.TransactionRepository
+	outboxRepo      ports.OutboxRepository
+	unitOfWork      ports.UnitOfWork
+	partnerRepo     ports.PartnerRepository
+	binRepo         ports.CardBINRepository
+	paymentGateway  ports.PaymentGateway
+	auditLogger     ports.AuditLogger
+	cache           ports.CacheService
+	amountLimits    valueobjects.AmountLimits
+}
+
+// NewCreateTransactionUseCase creates a new instance of the use case
+// Dependency Injection: all dependencies are interfaces (ports)
+// amountLimits are the platform maximums for partners without their own
+func NewCreateTransactionUseCase(
+	transactionRepo ports.TransactionRepository,
+	outboxRepo ports.OutboxRepository,
+	unitOfWork ports.UnitOfWork,
+	partnerRepo ports.PartnerRepository,
+	binRepo ports.CardBINRepository,
+	paymentGateway ports.PaymentGateway,
+	auditLogger ports.AuditLogger,
+	cache ports.CacheService,
+	amountLimits valueobjects.AmountLimits,
+) *CreateTransactionUseCase {
+	return &CreateTransactionUseCase{
+		transactionRepo: transactionRepo,
+		outboxRepo:      outboxRepo,
+		unitOfWork:      unitOfWork,
+		partnerRepo:     partnerRepo,
+		binRepo:         binRepo,
+		paymentGateway:  paymentGateway,
+		auditLogger:     auditLogger,
+		cache:           cache,
+		amountLimits:    amountLimits,
+	}
+}
+
+// Execute executes the create transaction use case
+// This is the main orchestration logic
+func (uc *CreateTransactionUseCase) Execute(ctx context.Context, input CreateTransactionInput) (*CreateTransactionOutput, error) {
+	// Step 1: Validate partner exists and is active
+	partner, err := uc.partnerRepo.GetByID(ctx, input.PartnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	if !partner.IsActive {
+		return nil, errors.ErrPartnerInactive
+	}
+
+	// Step 2: Check for duplicate transaction (idempotency)
+	existingTxn, err := uc.transactionRepo.GetByIdempotencyKey(ctx, input.PartnerID, input.IdempotencyKey)
+	if err == nil && existingTxn != nil {
+		// A key used in the other mode must not expose that transaction
+		if existingTxn.Livemode != input.Livemode {
+			return nil, errors.ErrDuplicateTransaction
+		}
+
+		// Return existing transaction (idempotent behavior)
+		return &CreateTransactionOutput{
+			TransactionID: existingTxn.ID,
+			Status:        string(existingTxn.Status),
+			Livemode:      existingTxn.Livemode,
+			CreatedAt:     existingTxn.CreatedAt,
+		}, nil
+	}
+
+	// Step 3: Create Money value object (validates amount and currency)
+	money, err := valueobjects.NewMoney(input.Amount, input.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid money: %w", err)
+	}
+
+	// Partners may be restricted to the currencies they settle in
+	if !partner.AcceptsCurrency(money.Currency) {
+		return nil, errors.ErrCurrencyNotAllowed
+	}
+
+	// Maximum amounts are configured per currency, and partners may have their own
+	if err := partner.CheckAmountLimit(money, uc.amountLimits); err != nil {
+		return nil, err
+	}
+
+	// Step 4: Create PaymentMethod value object (validates payment method)
+	paymentMethod, err := valueobjects.NewPaymentMethod(input.PaymentMethod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment method: %w", err)
+	}
+
+	// Crypto payments are only available to partners with the feature enabled
+	if paymentMethod == valueobjects.PaymentMethodCrypto && !partner.HasFeature(valueobjects.FeatureCrypto) {
+		return nil, errors.ErrFeatureDisabled
+	}
+
+	// Step 5: Create PaymentProvider value object (validates provider)
+	provider, err := valueobjects.NewPaymentProvider(input.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment provider: %w", err)
+	}
+
+	// Some providers cannot route local payment methods without the payer's country
+	var billingCountry valueobjects.Country
+	if input.BillingCountry != "" {
+		billingCountry, err = valueobjects.NewCountry(input.BillingCountry)
+		if err != nil {
+			return nil, errors.NewValidationError("billing_country", "must be an ISO 3166-1 country code")
+		}
+	} else if provider.RequiresBillingCountry(paymentMethod) {
+		return nil, errors.NewValidationError("billing_country",
+			"is required for "+paymentMethod.String()+" payments with "+provider.String())
+	}
+
+	// Step 6: Create Transaction entity (validates business rules)
+	transaction, err := entities.NewTransaction(
+		input.PartnerID,
+		input.IdempotencyKey,
+		money,
+		paymentMethod,
+		provider,
+		input.CustomerEmail,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction entity: %w", err)
+	}
+
+	// Step 7: Set optional fields
+	if input.CustomerName != "" {
+		transaction.CustomerName = input.CustomerName
+	}
+	if input.CustomerPhone != "" {
+		transaction.CustomerPhone = input.CustomerPhone
+	}
+	transaction.BillingCountry = billingCountry
+	if input.Description != "" {
+		transaction.Description = input.Description
+	}
+	if input.Metadata != nil {
+		for key, value := range input.Metadata {
+			transaction.SetMetadata(key, value)
+		}
+	}
+	if input.PaymentMethodDetails != nil {
+		details := *input.PaymentMethodDetails
+		if details.Card != nil && details.Card.BIN != "" {
+			details.Card = details.Card.WithBINInfo(details.Card.BIN, uc.lookupBIN(ctx, details.Card.BIN))
+		}
+		if err := transaction.SetPaymentMethodDetails(details); err != nil {
+			return nil, err
+		}
+	}
+	transaction.Livemode = input.Livemode
+	transaction.IPAddress = input.IPAddress
+	transaction.UserAgent = input.UserAgent
+	if requestID := ports.RequestID(ctx); requestID != uuid.Nil {
+		transaction.RequestID = requestID
+	}
+
+	// Step 8: Persist transaction, with the payment.created event for the
+	// event stream
+	err = uc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := uc.transactionRepo.Create(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+		return addTransactionEvent(ctx, uc.outboxRepo, entities.EventPaymentCreated, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 9: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "create_transaction",
+			ResourceType: "transaction",
+			ResourceID:   transaction.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			RequestID:    transaction.RequestID,
+			Changes: map[string]interface{}{
+				"amount":   money,
+				"status":   transaction.Status,
+				"livemode": transaction.Livemode,
+			},
+		})
+	}
+
+	// Step 10: Return output
+	return &CreateTransactionOutput{
+		TransactionID: transaction.ID,
+		Status:        string(transaction.Status),
+		Livemode:      transaction.Livemode,
+		CreatedAt:     transaction.CreatedAt,
+	}, nil
+}
+
+// lookupBIN finds the issuer of a card in the BIN table. Issuer details are
+// for reporting and routing only, so a failed lookup falls back to what the
+// BIN itself reveals instead of failing the payment.
+func (uc *CreateTransactionUseCase) lookupBIN(ctx context.Context, bin valueobjects.BIN) valueobjects.BINInfo {
+	if uc.binRepo == nil {
+		return bin.FallbackInfo()
+	}
+
+	info, err := uc.binRepo.Lookup(ctx, bin)
+	if err != nil || info == nil {
+		return bin.FallbackInfo()
+	}
+
+	return *info
+}
+
+// GetTransactionUseCase handles the business logic for retrieving a transaction
+type GetTransactionUseCase struct {
+	transactionRepo ports.TransactionRepository
+	// cache is nil when transaction reads are not cached
+	cache    ports.TransactionCache
+	cacheTTL time.Duration
+}
+
+// NewGetTransactionUseCase creates a new instance. Transactions are cached
+// for cacheTTL; the repository must invalidate them whenever they change.
+func NewGetTransactionUseCase(
+	transactionRepo ports.TransactionRepository,
+	cache ports.TransactionCache,
+	cacheTTL time.Duration,
+) *GetTransactionUseCase {
+	return &GetTransactionUseCase{
+		transactionRepo: transactionRepo,
+		cache:           cache,
+		cacheTTL:        cacheTTL,
+	}
+}
+
+// Execute retrieves a transaction by ID, visible only to keys of the same mode
+func (uc *GetTransactionUseCase) Execute(ctx context.Context, transactionID uuid.UUID, partnerID uuid.UUID, livemode bool) (*entities.Transaction, error) {
+	// A read replica may answer; a transaction created a moment ago can
+	// briefly be missing from it
+	ctx = ports.ReadOnly(ctx)
+
+	// The cache is keyed by partner, so a hit is always the partner's own
+	// transaction; a cache that fails is treated as a miss
+	if uc.cache != nil {
+		if cached, err := uc.cache.Get(ctx, partnerID, transactionID); err == nil && cached != nil {
+			if cached.Livemode != livemode {
+				return nil, errors.ErrTransactionNotFound
+			}
+			return cached, nil
+		}
+	}
+
+	// Get from database
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if transaction == nil {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	// Authorization: Verify partner owns this transaction
+	if transaction.PartnerID != partnerID {
+		return nil, errors.ErrUnauthorizedOperation
+	}
+
+	// Test keys only see sandbox data and live keys only live data
+	if transaction.Livemode != livemode {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	if uc.cache != nil {
+		_ = uc.cache.Set(ctx, transaction, uc.cacheTTL)
+	}
+
+	return transaction, nil
+}
+
+// ListTransactionsUseCase handles listing transactions with filtering
+type ListTransactionsUseCase struct {
+	transactionRepo ports.TransactionRepository
+}
+
+// NewListTransactionsUseCase creates a new instance
+func NewListTransactionsUseCase(transactionRepo ports.TransactionRepository) *ListTransactionsUseCase {
+	return &ListTransactionsUseCase{
+		transactionRepo: transactionRepo,
+	}
+}
+
+// Execute lists transactions with filters
+func (uc *ListTransactionsUseCase) Execute(ctx context.Context, partnerID uuid.UUID, livemode bool, query ports.TransactionQuery) ([]*entities.Transaction, int64, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	// Enforce partner and mode isolation
+	query.PartnerID = &partnerID
+	query.Livemode = &livemode
+
+	// Set default pagination if not provided
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 100 {
+		query.Limit = 100 // Max 100 per page
+	}
+
+	transactions, total, err := uc.transactionRepo.List(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return transactions, total, nil
+}
@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// transactionEventPayload is the data of transaction events
+type transactionEventPayload struct {
+	TransactionID         string `json:"transaction_id"`
+	IdempotencyKey        string `json:"idempotency_key"`
+	Status                string `json:"status"`
+	Amount                string `json:"amount"`
+	Currency              string `json:"currency"`
+	ProviderTransactionID string `json:"provider_transaction_id"`
+	Livemode              bool   `json:"livemode"`
+	ErrorCode             string `json:"error_code,omitempty"`
+	ErrorMessage          string `json:"error_message,omitempty"`
+}
+
+// refundEventPayload is the data of refund events
+type refundEventPayload struct {
+	RefundID          string `json:"refund_id"`
+	TransactionID     string `json:"transaction_id"`
+	Status            string `json:"status"`
+	Amount            string `json:"amount"`
+	Currency          string `json:"currency"`
+	Reason            string `json:"reason"`
+	ProviderRefundID  string `json:"provider_refund_id"`
+	TransactionStatus string `json:"transaction_status"`
+	Livemode          bool   `json:"livemode"`
+}
+
+// addTransactionEvent records a transaction event in the outbox. Call it in
+// the unit of work that saves the transaction.
+func addTransactionEvent(
+	ctx context.Context,
+	outboxRepo ports.OutboxRepository,
+	eventType string,
+	transaction *entities.Transaction,
+) error {
+	payload := transactionEventPayload{
+		TransactionID:         transaction.ID.String(),
+		IdempotencyKey:        transaction.IdempotencyKey,
+		Status:                string(transaction.Status),
+		Amount:                transaction.Amount.Decimal(),
+		Currency:              transaction.Amount.Currency.String(),
+		ProviderTransactionID: transaction.ProviderTransactionID,
+		Livemode:              transaction.Livemode,
+	}
+	if transaction.ErrorCode != "" {
+		payload.ErrorCode = transaction.ErrorCode
+		payload.ErrorMessage = transaction.ErrorMessage
+	}
+
+	return addEvent(ctx, outboxRepo, eventType, "transaction", transaction, transaction.ID, payload)
+}
+
+// addRefundEvent records a refund event in the outbox. Call it in the unit
+// of work that saves the refund.
+func addRefundEvent(
+	ctx context.Context,
+	outboxRepo ports.OutboxRepository,
+	eventType string,
+	refund *entities.Refund,
+	transaction *entities.Transaction,
+) error {
+	payload := refundEventPayload{
+		RefundID:          refund.ID.String(),
+		TransactionID:     transaction.ID.String(),
+		Status:            string(refund.Status),
+		Amount:            refund.Amount.Decimal(),
+		Currency:          refund.Amount.Currency.String(),
+		Reason:            string(refund.Reason.Code),
+		ProviderRefundID:  refund.ProviderRefundID,
+		TransactionStatus: string(transaction.Status),
+		Livemode:          transaction.Livemode,
+	}
+
+	return addEvent(ctx, outboxRepo, eventType, "refund", transaction, refund.ID, payload)
+}
+
+// addEvent records an event about an aggregate of transaction's partner
+func addEvent(
+	ctx context.Context,
+	outboxRepo ports.OutboxRepository,
+	eventType, aggregateType string,
+	transaction *entities.Transaction,
+	aggregateID uuid.UUID,
+	payload interface{},
+) error {
+	event, err := entities.NewOutboxEvent(transaction.PartnerID, aggregateType, aggregateID, eventType, payload)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	if err := outboxRepo.Add(ctx, event); err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ExportTransactionsUseCase streams every transaction matching a query, for
+// exports too large to page through
+type ExportTransactionsUseCase struct {
+	transactionRepo ports.TransactionRepository
+}
+
+// NewExportTransactionsUseCase creates a new instance
+func NewExportTransactionsUseCase(transactionRepo ports.TransactionRepository) *ExportTransactionsUseCase {
+	return &ExportTransactionsUseCase{
+		transactionRepo: transactionRepo,
+	}
+}
+
+// Execute calls fn with each of the partner's transactions in livemode that
+// match query, one at a time; paging in query is ignored. An error from fn,
+// such as the client going away, stops the export and is returned.
+func (uc *ExportTransactionsUseCase) Execute(ctx context.Context, partnerID uuid.UUID, livemode bool, query ports.TransactionQuery, fn func(*entities.Transaction) error) error {
+	ctx = ports.ReadOnly(ctx)
+
+	// Enforce partner and mode isolation
+	query.PartnerID = &partnerID
+	query.Livemode = &livemode
+
+	return uc.transactionRepo.Stream(ctx, query, fn)
+}
@@ -0,0 +1,174 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// receiptCacheTTLSeconds is how long a rendered receipt is kept. Entries
+// are keyed by what the receipt shows, so they never go stale; the TTL
+// only frees memory.
+const receiptCacheTTLSeconds = 3600
+
+// receiptStatuses are the statuses of transactions that were paid
+var receiptStatuses = map[entities.TransactionStatus]bool{
+	entities.StatusCompleted:         true,
+	entities.StatusPartiallyRefunded: true,
+	entities.StatusRefunded:          true,
+}
+
+// ReceiptOutput is a transaction's receipt and the version of what it
+// shows
+type ReceiptOutput struct {
+	// Version changes whenever the receipt would: when the transaction or
+	// its refunds change, or the partner's name, locale or logo
+	Version string
+
+	// Document is the PDF; it is nil when NotModified
+	Document    []byte
+	NotModified bool
+}
+
+// GetReceiptUseCase renders the receipt of a paid transaction, with its
+// completed refunds, branded with the partner's name and logo
+type GetReceiptUseCase struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	partnerRepo     ports.PartnerRepository
+	logoStore       ports.ObjectStore
+	renderer        ports.ReceiptRenderer
+	// cache is nil when rendered receipts are not cached
+	cache ports.CacheService
+}
+
+// NewGetReceiptUseCase creates a new instance. Logos are read from
+// logoStore.
+func NewGetReceiptUseCase(
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	partnerRepo ports.PartnerRepository,
+	logoStore ports.ObjectStore,
+	renderer ports.ReceiptRenderer,
+	cache ports.CacheService,
+) *GetReceiptUseCase {
+	return &GetReceiptUseCase{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		partnerRepo:     partnerRepo,
+		logoStore:       logoStore,
+		renderer:        renderer,
+		cache:           cache,
+	}
+}
+
+// Execute returns the receipt of a transaction, visible only to keys of
+// the same mode. A caller that already has knownVersion gets NotModified
+// instead of the document.
+func (uc *GetReceiptUseCase) Execute(ctx context.Context, transactionID, partnerID uuid.UUID, livemode bool, knownVersion string) (*ReceiptOutput, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	// Step 1: Retrieve the transaction
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil && err != errors.ErrTransactionNotFound {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if transaction == nil {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	// Authorization: Verify partner owns this transaction
+	if transaction.PartnerID != partnerID {
+		return nil, errors.ErrUnauthorizedOperation
+	}
+
+	// Test keys only see sandbox data and live keys only live data
+	if transaction.Livemode != livemode {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	if !receiptStatuses[transaction.Status] {
+		return nil, errors.ErrReceiptNotAvailable
+	}
+
+	// Step 2: Retrieve what else the receipt shows
+	refunds, err := uc.refundRepo.GetByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunds: %w", err)
+	}
+	completed := make([]*entities.Refund, 0, len(refunds))
+	for _, refund := range refunds {
+		if refund.Status == entities.RefundStatusCompleted {
+			completed = append(completed, refund)
+		}
+	}
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].CreatedAt.Before(completed[j].CreatedAt)
+	})
+
+	partner, err := uc.partnerRepo.GetByID(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if partner == nil {
+		return nil, errors.ErrPartnerNotFound
+	}
+
+	// Step 3: Render, unless the caller or the cache has this version
+	version := receiptVersion(partner, transaction, completed)
+	if version == knownVersion {
+		return &ReceiptOutput{Version: version, NotModified: true}, nil
+	}
+
+	cacheKey := "receipt:" + version
+	if uc.cache != nil {
+		if cached, err := uc.cache.Get(ctx, cacheKey); err == nil {
+			if document, ok := cached.([]byte); ok {
+				return &ReceiptOutput{Version: version, Document: document}, nil
+			}
+		}
+	}
+
+	receipt := ports.Receipt{
+		PartnerName: partner.Name,
+		Locale:      partner.Locale,
+		Transaction: transaction,
+		Refunds:     completed,
+	}
+	if partner.ReceiptLogoKey != "" {
+		if receipt.Logo, err = uc.logoStore.Get(ctx, partner.ReceiptLogoKey); err != nil {
+			return nil, fmt.Errorf("failed to read receipt logo: %w", err)
+		}
+	}
+
+	document, err := uc.renderer.Render(receipt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+
+	if uc.cache != nil {
+		_ = uc.cache.Set(ctx, cacheKey, document, receiptCacheTTLSeconds)
+	}
+
+	return &ReceiptOutput{Version: version, Document: document}, nil
+}
+
+// receiptVersion identifies what a receipt shows by the versions of the
+// transaction and its refunds, and the partner's branding. The IDs in it
+// keep one partner's receipts from matching another's.
+func receiptVersion(partner *entities.Partner, transaction *entities.Transaction, refunds []*entities.Refund) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s\x00%s\x00%d", partner.ID, partner.Name, partner.Locale, partner.ReceiptLogoKey, transaction.ID, transaction.Version)
+	for _, refund := range refunds {
+		fmt.Fprintf(hash, "\x00%s:%d", refund.ID, refund.Version)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
@@ -0,0 +1,88 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ListRefundsUseCase lists a partner's refunds
+type ListRefundsUseCase struct {
+	refundRepo ports.RefundRepository
+}
+
+// NewListRefundsUseCase creates a new instance
+func NewListRefundsUseCase(refundRepo ports.RefundRepository) *ListRefundsUseCase {
+	return &ListRefundsUseCase{
+		refundRepo: refundRepo,
+	}
+}
+
+// Execute lists the refunds of the partner's transactions in one mode
+func (uc *ListRefundsUseCase) Execute(ctx context.Context, partnerID uuid.UUID, livemode bool, query ports.RefundQuery) ([]*entities.Refund, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	// Enforce partner and mode isolation
+	query.PartnerID = partnerID
+	query.Livemode = livemode
+
+	// Set default pagination if not provided
+	if query.Limit <= 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 100 {
+		query.Limit = 100 // Max 100 per page
+	}
+
+	refunds, err := uc.refundRepo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refunds: %w", err)
+	}
+
+	return refunds, nil
+}
+
+// GetRefundUseCase retrieves one of a partner's refunds
+type GetRefundUseCase struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+}
+
+// NewGetRefundUseCase creates a new instance
+func NewGetRefundUseCase(transactionRepo ports.TransactionRepository, refundRepo ports.RefundRepository) *GetRefundUseCase {
+	return &GetRefundUseCase{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+	}
+}
+
+// Execute retrieves a refund by ID, visible only to its partner's keys of
+// the same mode
+func (uc *GetRefundUseCase) Execute(ctx context.Context, refundID uuid.UUID, partnerID uuid.UUID, livemode bool) (*entities.Refund, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	// Step 1: Retrieve refund
+	refund, err := uc.refundRepo.GetByID(ctx, refundID)
+	if err != nil && err != errors.ErrRefundNotFound {
+		return nil, fmt.Errorf("failed to get refund: %w", err)
+	}
+	if refund == nil {
+		return nil, errors.ErrRefundNotFound
+	}
+
+	// Step 2: Authorization - verify partner owns the refunded transaction
+	transaction, err := uc.transactionRepo.GetByID(ctx, refund.TransactionID)
+	if err != nil && err != errors.ErrTransactionNotFound {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if transaction == nil || transaction.PartnerID != partnerID || transaction.Livemode != livemode {
+		return nil, errors.ErrRefundNotFound
+	}
+
+	return refund, nil
+}
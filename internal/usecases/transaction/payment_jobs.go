@@ -0,0 +1,206 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// EnqueuePaymentUseCase queues a transaction's payment for a worker to
+// process, so the request returns before the provider is called
+type EnqueuePaymentUseCase struct {
+	transactionRepo ports.TransactionRepository
+	jobRepo         ports.PaymentJobRepository
+}
+
+// NewEnqueuePaymentUseCase creates a new instance
+func NewEnqueuePaymentUseCase(
+	transactionRepo ports.TransactionRepository,
+	jobRepo ports.PaymentJobRepository,
+) *EnqueuePaymentUseCase {
+	return &EnqueuePaymentUseCase{
+		transactionRepo: transactionRepo,
+		jobRepo:         jobRepo,
+	}
+}
+
+// Execute queues processing partnerID's transaction. The transaction must be
+// one processing accepts, so a request that could never succeed fails now
+// rather than in the worker.
+func (uc *EnqueuePaymentUseCase) Execute(ctx context.Context, partnerID, transactionID uuid.UUID) (*entities.PaymentJob, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if transaction == nil || transaction.PartnerID != partnerID {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	if !transaction.IsPending() && !transaction.IsFailed() {
+		return nil, errors.NewBusinessRuleError(
+			"invalid_state",
+			fmt.Sprintf("transaction is in %s state, cannot process", transaction.Status),
+		)
+	}
+
+	job, err := entities.NewPaymentJob(partnerID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobRepo.Add(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to queue payment: %w", err)
+	}
+	return job, nil
+}
+
+// PaymentWorker runs the queued payment jobs with ProcessPaymentUseCase.
+//
+// A job is done once processing reached an outcome: the payment completed,
+// the provider declined it, or the transaction was not in a state to be
+// processed, as when a second job for it arrives after the first. Only
+// attempts that left the transaction pending, such as a database that was
+// down or a transaction locked by another instance, are retried.
+//
+// Several workers can share the queue. A pass claims its jobs until
+// claimTimeout from now, so other workers skip them; a job whose worker
+// died is claimed again after that, and the transaction lock keeps it from
+// being processed twice at once.
+type PaymentWorker struct {
+	jobRepo         ports.PaymentJobRepository
+	transactionRepo ports.TransactionRepository
+	processUseCase  *ProcessPaymentUseCase
+
+	batchSize    int
+	concurrency  int
+	claimTimeout time.Duration
+
+	completed atomic.Int64
+	declined  atomic.Int64
+	retried   atomic.Int64
+}
+
+// PaymentWorkerStats counts the outcomes of jobs since the worker started
+type PaymentWorkerStats struct {
+	// Completed jobs made their payment
+	Completed int64
+	// Declined jobs reached an outcome without a payment, such as a decline
+	// by the provider
+	Declined int64
+	// Retried attempts left the transaction pending and are tried again
+	Retried int64
+}
+
+// NewPaymentWorker creates a worker running up to batchSize jobs a pass,
+// concurrency of them at once. claimTimeout must be longer than a pass
+// takes, or another worker may run a job of a pass still running again.
+func NewPaymentWorker(
+	jobRepo ports.PaymentJobRepository,
+	transactionRepo ports.TransactionRepository,
+	processUseCase *ProcessPaymentUseCase,
+	batchSize, concurrency int,
+	claimTimeout time.Duration,
+) *PaymentWorker {
+	return &PaymentWorker{
+		jobRepo:         jobRepo,
+		transactionRepo: transactionRepo,
+		processUseCase:  processUseCase,
+		batchSize:       batchSize,
+		concurrency:     concurrency,
+		claimTimeout:    claimTimeout,
+	}
+}
+
+// RunDue runs the jobs that are due and returns how many it ran. Only
+// storage errors are returned, once the jobs already running are saved;
+// jobs left unrun stay claimed until the claim times out.
+func (w *PaymentWorker) RunDue(ctx context.Context) (int, error) {
+	jobs, err := w.jobRepo.ClaimDue(ctx, w.batchSize, time.Now().Add(w.claimTimeout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim payment jobs: %w", err)
+	}
+
+	queue := make(chan *entities.PaymentJob, len(jobs))
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+
+	var (
+		ran      atomic.Int64
+		errOnce  sync.Once
+		storeErr error
+		wg       sync.WaitGroup
+	)
+	workers := w.concurrency
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if err := w.run(ctx, job); err != nil {
+					errOnce.Do(func() { storeErr = err })
+					return
+				}
+				ran.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(ran.Load()), storeErr
+}
+
+// Stats returns the outcomes of jobs since the worker started
+func (w *PaymentWorker) Stats() PaymentWorkerStats {
+	return PaymentWorkerStats{
+		Completed: w.completed.Load(),
+		Declined:  w.declined.Load(),
+		Retried:   w.retried.Load(),
+	}
+}
+
+// run processes job's transaction and saves the outcome
+func (w *PaymentWorker) run(ctx context.Context, job *entities.PaymentJob) error {
+	err := w.processUseCase.Execute(ctx, job.TransactionID)
+	switch {
+	case err == nil:
+		job.MarkCompleted("")
+		w.completed.Add(1)
+	case w.retryable(ctx, job, err):
+		job.MarkFailed(err.Error())
+		w.retried.Add(1)
+	default:
+		job.MarkCompleted(err.Error())
+		w.declined.Add(1)
+	}
+
+	if err := w.jobRepo.Update(ctx, job); err != nil {
+		return fmt.Errorf("failed to save payment job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// retryable reports whether processing failed with err before the
+// transaction reached an outcome
+func (w *PaymentWorker) retryable(ctx context.Context, job *entities.PaymentJob, err error) bool {
+	if err == errors.ErrResourceLocked {
+		return true
+	}
+	transaction, getErr := w.transactionRepo.GetByID(ctx, job.TransactionID)
+	if getErr == errors.ErrTransactionNotFound || (getErr == nil && transaction == nil) {
+		return false
+	}
+	return getErr != nil || transaction.IsPending()
+}
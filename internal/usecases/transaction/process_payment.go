@@ -3,9 +3,11 @@ package transaction
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
+	"Pay2Go/internal/domain/entities"
 	"Pay2Go/internal/domain/errors"
 	"Pay2Go/internal/usecases/ports"
 )
@@ -14,28 +16,68 @@ import (
 // This orchestrates the interaction with external payment providers
 type ProcessPaymentUseCase struct {
 	transactionRepo ports.TransactionRepository
+	outboxRepo      ports.OutboxRepository
+	unitOfWork      ports.UnitOfWork
 	paymentGateway  ports.PaymentGateway
-	notification    ports.NotificationService
 	auditLogger     ports.AuditLogger
+	// locker keeps two instances from processing a transaction at once; nil
+	// leaves it to the version check on saving
+	locker  ports.Locker
+	lockTTL time.Duration
 }
 
-// NewProcessPaymentUseCase creates a new instance
+// NewProcessPaymentUseCase creates a new instance. lockTTL bounds how long a
+// crashed instance keeps the transaction locked, so it should exceed the
+// longest gateway call.
 func NewProcessPaymentUseCase(
 	transactionRepo ports.TransactionRepository,
+	outboxRepo ports.OutboxRepository,
+	unitOfWork ports.UnitOfWork,
 	paymentGateway ports.PaymentGateway,
-	notification ports.NotificationService,
 	auditLogger ports.AuditLogger,
+	locker ports.Locker,
+	lockTTL time.Duration,
 ) *ProcessPaymentUseCase {
 	return &ProcessPaymentUseCase{
 		transactionRepo: transactionRepo,
+		outboxRepo:      outboxRepo,
+		unitOfWork:      unitOfWork,
 		paymentGateway:  paymentGateway,
-		notification:    notification,
 		auditLogger:     auditLogger,
+		locker:          locker,
+		lockTTL:         lockTTL,
 	}
 }
 
-// Execute processes a payment through the payment gateway
+// Execute processes a payment through the payment gateway. It fails with
+// errors.ErrResourceLocked while another request is processing the
+// transaction.
 func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, transactionID uuid.UUID) error {
+	unlock, err := uc.lock(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return uc.process(ctx, transactionID)
+}
+
+// lock locks the transaction and returns the function releasing it
+func (uc *ProcessPaymentUseCase) lock(ctx context.Context, transactionID uuid.UUID) (func(), error) {
+	if uc.locker == nil {
+		return func() {}, nil
+	}
+	lock, err := uc.locker.TryLock(ctx, "transaction:"+transactionID.String(), uc.lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	// Released with a fresh context, since the request's may be done by now;
+	// a failed release expires with the TTL
+	return func() { _ = lock.Unlock(context.Background()) }, nil
+}
+
+// process runs the payment while the caller holds the lock
+func (uc *ProcessPaymentUseCase) process(ctx context.Context, transactionID uuid.UUID) error {
 	// Step 1: Retrieve transaction
 	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
@@ -59,16 +101,16 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, transactionID uuid
 		return fmt.Errorf("failed to mark as processing: %w", err)
 	}
 
-	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to update transaction: %w", err)
+	if err := uc.saveWithEvent(ctx, transaction, entities.EventPaymentProcessing); err != nil {
+		return err
 	}
 
 	// Step 4: Process payment through gateway
 	providerTxnID, err := uc.paymentGateway.ProcessPayment(ctx, transaction)
 	if err != nil {
-		// Payment failed - mark transaction as failed
+		// Payment failed - mark transaction as failed and notify the partner
 		_ = transaction.MarkAsFailed("PAYMENT_FAILED", err.Error())
-		_ = uc.transactionRepo.Update(ctx, transaction)
+		_ = uc.saveWithEvent(ctx, transaction, entities.EventPaymentFailed)
 
 		// Log audit event
 		if uc.auditLogger != nil {
@@ -92,8 +134,10 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, transactionID uuid
 		return fmt.Errorf("failed to mark as completed: %w", err)
 	}
 
-	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to update transaction: %w", err)
+	// The payment.completed webhook is written with the status, so it is sent
+	// exactly when the completion is committed
+	if err := uc.saveWithEvent(ctx, transaction, entities.EventPaymentCompleted); err != nil {
+		return err
 	}
 
 	// Step 6: Log audit event
@@ -110,48 +154,61 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, transactionID uuid
 		})
 	}
 
-	// Step 7: Send webhook notification (async, fire-and-forget)
-	// In production, this would be done via message queue
-	if uc.notification != nil {
-		go func() {
-			payload := map[string]interface{}{
-				"event":          "payment.completed",
-				"transaction_id": transaction.ID.String(),
-				"status":         transaction.Status,
-				"amount":         transaction.Amount.Amount,
-				"currency":       transaction.Amount.Currency,
-			}
-			// Note: Webhook URL would come from partner configuration
-			// _ = uc.notification.SendWebhook(context.Background(), webhookURL, payload)
-			_ = payload
-		}()
-	}
-
 	return nil
 }
 
+// saveWithEvent saves transaction and records eventType in the outbox in one
+// database transaction
+func (uc *ProcessPaymentUseCase) saveWithEvent(ctx context.Context, transaction *entities.Transaction, eventType string) error {
+	return uc.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+		return addTransactionEvent(ctx, uc.outboxRepo, eventType, transaction)
+	})
+}
+
 // RetryFailedPaymentUseCase handles retrying failed payments
 type RetryFailedPaymentUseCase struct {
 	transactionRepo ports.TransactionRepository
-	paymentGateway  ports.PaymentGateway
 	auditLogger     ports.AuditLogger
+	processUseCase  *ProcessPaymentUseCase
 }
 
-// NewRetryFailedPaymentUseCase creates a new instance
+// NewRetryFailedPaymentUseCase creates a new instance; the retry holds the
+// same lock as processing
 func NewRetryFailedPaymentUseCase(
 	transactionRepo ports.TransactionRepository,
+	outboxRepo ports.OutboxRepository,
+	unitOfWork ports.UnitOfWork,
 	paymentGateway ports.PaymentGateway,
 	auditLogger ports.AuditLogger,
+	locker ports.Locker,
+	lockTTL time.Duration,
 ) *RetryFailedPaymentUseCase {
 	return &RetryFailedPaymentUseCase{
 		transactionRepo: transactionRepo,
-		paymentGateway:  paymentGateway,
 		auditLogger:     auditLogger,
+		processUseCase: NewProcessPaymentUseCase(
+			transactionRepo,
+			outboxRepo,
+			unitOfWork,
+			paymentGateway,
+			auditLogger,
+			locker,
+			lockTTL,
+		),
 	}
 }
 
 // Execute retries a failed payment
 func (uc *RetryFailedPaymentUseCase) Execute(ctx context.Context, transactionID uuid.UUID) error {
+	unlock, err := uc.processUseCase.lock(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Get transaction
 	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
@@ -162,23 +219,16 @@ func (uc *RetryFailedPaymentUseCase) Execute(ctx context.Context, transactionID
 		return errors.ErrTransactionNotFound
 	}
 
-	// Check if retry is allowed (business rule: max 3 retries)
-	if !transaction.CanRetry() {
-		return errors.NewBusinessRuleError(
-			"max_retries_exceeded",
-			"transaction has reached maximum retry attempts",
-		)
+	// Count the retry in the database, so retries racing past the lock
+	// (or running without one) cannot exceed the maximum between them
+	err = uc.transactionRepo.IncrementRetryCount(ctx, transaction, entities.MaxRetryAttempts)
+	if err == errors.ErrRetryNotAllowed {
+		return uc.retryRefusal(ctx, transaction)
 	}
-
-	// Increment retry count
-	if err := transaction.IncrementRetryCount(); err != nil {
+	if err != nil {
 		return fmt.Errorf("failed to increment retry count: %w", err)
 	}
 
-	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to update transaction: %w", err)
-	}
-
 	// Log audit event
 	if uc.auditLogger != nil {
 		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
@@ -192,13 +242,22 @@ func (uc *RetryFailedPaymentUseCase) Execute(ctx context.Context, transactionID
 		})
 	}
 
-	// Process payment again
-	processUseCase := NewProcessPaymentUseCase(
-		uc.transactionRepo,
-		uc.paymentGateway,
-		nil,
-		uc.auditLogger,
-	)
+	// Process payment again, still under the lock
+	return uc.processUseCase.process(ctx, transactionID)
+}
 
-	return processUseCase.Execute(ctx, transactionID)
+// retryRefusal tells why the database refused to count a retry: only failed
+// payments are retried, and only up to the maximum. The transaction is read
+// again, since a retry racing past the lock may have moved it on
+func (uc *RetryFailedPaymentUseCase) retryRefusal(ctx context.Context, transaction *entities.Transaction) error {
+	if current, err := uc.transactionRepo.GetByID(ctx, transaction.ID); err == nil && current != nil {
+		transaction = current
+	}
+	if transaction.Status != entities.StatusFailed {
+		return fmt.Errorf("%w: only failed payments can be retried, this one is %s", errors.ErrInvalidStatus, transaction.Status)
+	}
+	return errors.NewBusinessRuleError(
+		"max_retries_exceeded",
+		"transaction has reached maximum retry attempts",
+	)
 }
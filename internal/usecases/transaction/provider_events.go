@@ -0,0 +1,228 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// ProviderEventsResult counts the events of a provider notification
+type ProviderEventsResult struct {
+	// Handled events were new; they may still have changed nothing, such as
+	// a success for a payment that already completed
+	Handled int
+	// Duplicates were handled before, from an earlier delivery
+	Duplicates int
+}
+
+// HandleProviderEventsUseCase applies the notifications payment providers
+// send about payments and refunds: payments left processing are completed
+// or failed, and refunds left processing completed. Providers deliver a
+// notification at least once, so each event is claimed in the event store
+// before it is handled and a duplicate is skipped, rather than completing a
+// payment or a refund twice.
+type HandleProviderEventsUseCase struct {
+	parsers         map[valueobjects.PaymentProvider]ports.ProviderEventParser
+	events          ports.ProviderEventStore
+	dedupTTL        time.Duration
+	transactionRepo ports.TransactionRepository
+	refunds         refundProcessor
+	auditLogger     ports.AuditLogger
+	processUseCase  *ProcessPaymentUseCase
+}
+
+// NewHandleProviderEventsUseCase creates a new instance for the providers
+// of parsers. Events are remembered for dedupTTL, which should exceed how
+// long the providers keep retrying a notification. Payments are updated
+// under the same lock as processing.
+func NewHandleProviderEventsUseCase(
+	parsers map[valueobjects.PaymentProvider]ports.ProviderEventParser,
+	events ports.ProviderEventStore,
+	dedupTTL time.Duration,
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	outboxRepo ports.OutboxRepository,
+	unitOfWork ports.UnitOfWork,
+	auditLogger ports.AuditLogger,
+	locker ports.Locker,
+	lockTTL time.Duration,
+) *HandleProviderEventsUseCase {
+	return &HandleProviderEventsUseCase{
+		parsers:         parsers,
+		events:          events,
+		dedupTTL:        dedupTTL,
+		transactionRepo: transactionRepo,
+		refunds: refundProcessor{
+			transactionRepo: transactionRepo,
+			refundRepo:      refundRepo,
+			outboxRepo:      outboxRepo,
+			unitOfWork:      unitOfWork,
+		},
+		auditLogger:    auditLogger,
+		processUseCase: NewProcessPaymentUseCase(transactionRepo, outboxRepo, unitOfWork, nil, auditLogger, locker, lockTTL),
+	}
+}
+
+// Execute verifies and handles a notification of provider. On error the
+// event that failed, and those after it, are left unclaimed, so the
+// provider's retry handles them; those before it are skipped then.
+func (uc *HandleProviderEventsUseCase) Execute(ctx context.Context, provider string, body []byte, header func(name string) string) (ProviderEventsResult, error) {
+	var result ProviderEventsResult
+
+	// Step 1: Verify and decode the notification
+	paymentProvider, err := valueobjects.NewPaymentProvider(provider)
+	if err != nil {
+		return result, errors.ErrProviderNotConfigured
+	}
+	parser, ok := uc.parsers[paymentProvider]
+	if !ok {
+		return result, errors.ErrProviderNotConfigured
+	}
+	events, err := parser.Parse(body, header)
+	if err != nil {
+		return result, err
+	}
+
+	// Step 2: Handle each event once
+	for _, event := range events {
+		claimed, err := uc.events.Claim(ctx, event.Provider, event.ID, uc.dedupTTL)
+		if err != nil {
+			return result, err
+		}
+		if !claimed {
+			result.Duplicates++
+			continue
+		}
+
+		if err := uc.handle(ctx, event); err != nil {
+			_ = uc.events.Release(context.WithoutCancel(ctx), event.Provider, event.ID)
+			return result, fmt.Errorf("failed to handle %s event %s: %w", event.Provider, event.ID, err)
+		}
+		result.Handled++
+	}
+
+	return result, nil
+}
+
+// handle applies event to its payment or refund
+func (uc *HandleProviderEventsUseCase) handle(ctx context.Context, event ports.ProviderEvent) error {
+	transaction, err := uc.findTransaction(ctx, event)
+	if err != nil || transaction == nil {
+		return err
+	}
+
+	if event.Type == ports.ProviderEventRefundSucceeded {
+		return uc.completeRefund(ctx, event, transaction)
+	}
+
+	unlock, err := uc.processUseCase.lock(ctx, transaction.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Reload under the lock, as processing may have finished meanwhile
+	transaction, err = uc.transactionRepo.GetByID(ctx, transaction.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if transaction == nil || !transaction.IsProcessing() {
+		return nil
+	}
+
+	action := "payment_completed"
+	if event.Type == ports.ProviderEventPaymentSucceeded {
+		if err := transaction.MarkAsCompleted(event.ProviderTransactionID); err != nil {
+			return err
+		}
+		err = uc.processUseCase.saveWithEvent(ctx, transaction, entities.EventPaymentCompleted)
+	} else {
+		action = "payment_failed"
+		if err := transaction.MarkAsFailed("PAYMENT_FAILED", event.Reason); err != nil {
+			return err
+		}
+		err = uc.processUseCase.saveWithEvent(ctx, transaction, entities.EventPaymentFailed)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    transaction.PartnerID,
+			Action:       action,
+			ResourceType: "transaction",
+			ResourceID:   transaction.ID,
+			Changes: map[string]interface{}{
+				"provider_event_id":       event.ID,
+				"provider_transaction_id": transaction.ProviderTransactionID,
+				"status":                  transaction.Status,
+			},
+		})
+	}
+
+	return nil
+}
+
+// completeRefund completes the refund event reports, if it is processing
+func (uc *HandleProviderEventsUseCase) completeRefund(ctx context.Context, event ports.ProviderEvent, transaction *entities.Transaction) error {
+	refunds, err := uc.refunds.refundRepo.GetByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get refunds: %w", err)
+	}
+
+	for _, refund := range refunds {
+		matches := refund.ID == event.RefundID ||
+			(event.ProviderRefundID != "" && refund.ProviderRefundID == event.ProviderRefundID)
+		if !matches {
+			continue
+		}
+		if refund.Status != entities.RefundStatusProcessing {
+			return nil
+		}
+		return uc.refunds.unitOfWork.Do(ctx, func(ctx context.Context) error {
+			return uc.refunds.complete(ctx, refund, transaction, event.ProviderRefundID)
+		})
+	}
+	return nil
+}
+
+// findTransaction returns the transaction of event: the one it names, or
+// else the only one with its provider ID; nil when there is none, such as
+// for a payment made outside Pay2Go
+func (uc *HandleProviderEventsUseCase) findTransaction(ctx context.Context, event ports.ProviderEvent) (*entities.Transaction, error) {
+	if event.TransactionID != uuid.Nil {
+		transaction, err := uc.transactionRepo.GetByID(ctx, event.TransactionID)
+		if err != nil && err != errors.ErrTransactionNotFound {
+			return nil, fmt.Errorf("failed to get transaction: %w", err)
+		}
+		if transaction != nil && transaction.Provider == event.Provider {
+			return transaction, nil
+		}
+	}
+
+	if event.ProviderTransactionID == "" {
+		return nil, nil
+	}
+	provider := event.Provider
+	transactions, _, err := uc.transactionRepo.List(ctx, ports.TransactionQuery{
+		Provider:              &provider,
+		ProviderTransactionID: event.ProviderTransactionID,
+		Limit:                 2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	}
+	if len(transactions) != 1 {
+		return nil, nil
+	}
+	return transactions[0], nil
+}
@@ -0,0 +1,32 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"Pay2Go/internal/usecases/ports"
+)
+
+// GetRefundReasonSummaryUseCase reports completed refunds grouped by reason
+type GetRefundReasonSummaryUseCase struct {
+	refundRepo ports.RefundRepository
+}
+
+// NewGetRefundReasonSummaryUseCase creates a new instance
+func NewGetRefundReasonSummaryUseCase(refundRepo ports.RefundRepository) *GetRefundReasonSummaryUseCase {
+	return &GetRefundReasonSummaryUseCase{
+		refundRepo: refundRepo,
+	}
+}
+
+// Execute returns the partner's refund totals per reason code and currency
+func (uc *GetRefundReasonSummaryUseCase) Execute(ctx context.Context, filter ports.RefundReportFilter) ([]ports.RefundReasonSummary, error) {
+	ctx = ports.ReadOnly(ctx)
+
+	summaries, err := uc.refundRepo.GetReasonSummary(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refund reason summary: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -17,36 +17,55 @@ import (
 type RefundTransactionInput struct {
 	TransactionID uuid.UUID
 	PartnerID     uuid.UUID
-	Amount        float64
+	Amount        int64 // Minor units of Currency, e.g. cents
 	Currency      string
-	Reason        string
+	ReasonCode    string
+	ReasonNote    string
+	Livemode      bool
 	IPAddress     string
 	UserAgent     string
+	// RefundID is the ID to create the refund with, when the caller
+	// recorded it before the call; otherwise the refund gets a new one
+	RefundID uuid.UUID
 }
 
 // RefundTransactionUseCase handles the business logic for refunds
 type RefundTransactionUseCase struct {
 	transactionRepo ports.TransactionRepository
 	refundRepo      ports.RefundRepository
+	partnerRepo     ports.PartnerRepository
+	outboxRepo      ports.OutboxRepository
+	unitOfWork      ports.UnitOfWork
 	paymentGateway  ports.PaymentGateway
 	notification    ports.NotificationService
 	auditLogger     ports.AuditLogger
+
+	// defaultRefundWindowDays applies when the partner has no window of its own
+	defaultRefundWindowDays int
 }
 
 // NewRefundTransactionUseCase creates a new instance
 func NewRefundTransactionUseCase(
 	transactionRepo ports.TransactionRepository,
 	refundRepo ports.RefundRepository,
+	partnerRepo ports.PartnerRepository,
+	outboxRepo ports.OutboxRepository,
+	unitOfWork ports.UnitOfWork,
 	paymentGateway ports.PaymentGateway,
 	notification ports.NotificationService,
 	auditLogger ports.AuditLogger,
+	defaultRefundWindowDays int,
 ) *RefundTransactionUseCase {
 	return &RefundTransactionUseCase{
-		transactionRepo: transactionRepo,
-		refundRepo:      refundRepo,
-		paymentGateway:  paymentGateway,
-		notification:    notification,
-		auditLogger:     auditLogger,
+		transactionRepo:         transactionRepo,
+		refundRepo:              refundRepo,
+		partnerRepo:             partnerRepo,
+		outboxRepo:              outboxRepo,
+		unitOfWork:              unitOfWork,
+		paymentGateway:          paymentGateway,
+		notification:            notification,
+		auditLogger:             auditLogger,
+		defaultRefundWindowDays: defaultRefundWindowDays,
 	}
 }
 
@@ -67,14 +86,25 @@ func (uc *RefundTransactionUseCase) Execute(ctx context.Context, input RefundTra
 		return nil, errors.ErrUnauthorizedOperation
 	}
 
+	// Test keys cannot touch live transactions and vice versa
+	if transaction.Livemode != input.Livemode {
+		return nil, errors.ErrTransactionNotFound
+	}
+
 	// Step 3: Business Rule - Check if transaction is refundable
 	if !transaction.IsRefundable() {
 		return nil, errors.ErrRefundNotAllowed
 	}
 
-	// Step 4: Business Rule - Check 90-day window
-	ninetyDaysAgo := time.Now().AddDate(0, 0, -90)
-	if transaction.CreatedAt.Before(ninetyDaysAgo) {
+	// Step 4: Business Rule - Check the partner's refund window
+	partner, err := uc.partnerRepo.GetByID(ctx, transaction.PartnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+
+	windowDays := partner.EffectiveRefundWindowDays(uc.defaultRefundWindowDays)
+	windowStart := time.Now().AddDate(0, 0, -windowDays)
+	if transaction.CreatedAt.Before(windowStart) {
 		return nil, errors.ErrRefundWindowExpired
 	}
 
@@ -89,13 +119,8 @@ func (uc *RefundTransactionUseCase) Execute(ctx context.Context, input RefundTra
 		return nil, errors.ErrRefundAmountExceeded
 	}
 
-	// Step 7: Check total refunded amount
-	totalRefunded, err := uc.refundRepo.GetTotalRefundedAmount(ctx, transaction.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get total refunded: %w", err)
-	}
-
-	if totalRefunded+input.Amount > transaction.Amount.Amount {
+	// Step 7: Check remaining refundable amount (fast path; Reserve enforces it atomically)
+	if refundMoney.Amount > transaction.RefundableAmount() {
 		return nil, errors.ErrRefundAmountExceeded
 	}
 
@@ -105,59 +130,74 @@ func (uc *RefundTransactionUseCase) Execute(ctx context.Context, input RefundTra
 	}
 
 	// Step 9: Create Refund entity
+	reason, err := valueobjects.NewRefundReason(input.ReasonCode, input.ReasonNote)
+	if err != nil {
+		return nil, err
+	}
+
 	refund, err := entities.NewRefund(
 		transaction.ID,
 		refundMoney,
-		input.Reason,
+		reason,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create refund entity: %w", err)
 	}
-
-	// Step 10: Persist refund
-	if err := uc.refundRepo.Create(ctx, refund); err != nil {
-		return nil, fmt.Errorf("failed to create refund: %w", err)
-	}
-
-	// Step 11: Mark refund as processing
-	if err := refund.MarkAsProcessing(); err != nil {
-		return nil, fmt.Errorf("failed to mark refund as processing: %w", err)
+	if input.RefundID != uuid.Nil {
+		refund.ID = input.RefundID
 	}
 
-	if err := uc.refundRepo.Update(ctx, refund); err != nil {
-		return nil, fmt.Errorf("failed to update refund: %w", err)
-	}
+	// Step 10: Park refunds above the partner's approval threshold
+	if partner.RequiresRefundApproval(refundMoney) {
+		if err := refund.RequireApproval(); err != nil {
+			return nil, fmt.Errorf("failed to mark refund as requiring approval: %w", err)
+		}
 
-	// Step 12: Process refund through payment gateway
-	providerRefundID, err := uc.paymentGateway.ProcessRefund(ctx, refund, transaction)
-	if err != nil {
-		// Refund failed
-		_ = refund.MarkAsFailed("REFUND_FAILED", err.Error())
-		_ = uc.refundRepo.Update(ctx, refund)
+		if err := uc.reserve(ctx, refund); err != nil {
+			return nil, err
+		}
 
-		return nil, fmt.Errorf("refund processing failed: %w", err)
-	}
+		if uc.auditLogger != nil {
+			_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+				PartnerID:    input.PartnerID,
+				Action:       "refund_approval_requested",
+				ResourceType: "refund",
+				ResourceID:   refund.ID,
+				IPAddress:    input.IPAddress,
+				UserAgent:    input.UserAgent,
+				Changes: map[string]interface{}{
+					"transaction_id": transaction.ID.String(),
+					"amount":         refundMoney,
+					"threshold": valueobjects.Money{
+						Amount:   partner.RefundApprovalThresholds[refundMoney.Currency],
+						Currency: refundMoney.Currency,
+					},
+				},
+			})
+		}
 
-	// Step 13: Mark refund as completed
-	if err := refund.MarkAsCompleted(providerRefundID); err != nil {
-		return nil, fmt.Errorf("failed to mark refund as completed: %w", err)
+		return refund, nil
 	}
 
-	if err := uc.refundRepo.Update(ctx, refund); err != nil {
-		return nil, fmt.Errorf("failed to update refund: %w", err)
+	// Step 11: Persist refund and reserve its amount against the transaction
+	if err := uc.reserve(ctx, refund); err != nil {
+		return nil, err
 	}
 
-	// Step 14: Update transaction status
-	isFullRefund := (totalRefunded + input.Amount) >= transaction.Amount.Amount
-	if err := transaction.MarkAsRefunded(!isFullRefund); err != nil {
-		return nil, fmt.Errorf("failed to update transaction status: %w", err)
+	// Step 12: Process refund through the gateway and update the transaction
+	processor := refundProcessor{
+		transactionRepo: uc.transactionRepo,
+		refundRepo:      uc.refundRepo,
+		outboxRepo:      uc.outboxRepo,
+		unitOfWork:      uc.unitOfWork,
+		paymentGateway:  uc.paymentGateway,
 	}
-
-	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-		return nil, fmt.Errorf("failed to update transaction: %w", err)
+	providerRefundID, err := processor.process(ctx, refund, transaction)
+	if err != nil {
+		return nil, err
 	}
 
-	// Step 15: Log audit event
+	// Step 13: Log audit event
 	if uc.auditLogger != nil {
 		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
 			PartnerID:    input.PartnerID,
@@ -168,7 +208,8 @@ func (uc *RefundTransactionUseCase) Execute(ctx context.Context, input RefundTra
 			UserAgent:    input.UserAgent,
 			Changes: map[string]interface{}{
 				"transaction_id":     transaction.ID.String(),
-				"amount":             input.Amount,
+				"amount":             refundMoney,
+				"reason":             string(reason.Code),
 				"provider_refund_id": providerRefundID,
 				"transaction_status": transaction.Status,
 			},
@@ -177,3 +218,115 @@ func (uc *RefundTransactionUseCase) Execute(ctx context.Context, input RefundTra
 
 	return refund, nil
 }
+
+// reserve persists a refund, keeping the domain error when the amount is no longer available
+func (uc *RefundTransactionUseCase) reserve(ctx context.Context, refund *entities.Refund) error {
+	if err := uc.refundRepo.Reserve(ctx, refund); err != nil {
+		if err == errors.ErrRefundAmountExceeded {
+			return err
+		}
+		return fmt.Errorf("failed to create refund: %w", err)
+	}
+	return nil
+}
+
+// maxStatusUpdateAttempts bounds how often a refunded transaction's status is
+// recomputed after losing a race with another refund
+const maxStatusUpdateAttempts = 3
+
+// refundProcessor sends a persisted refund to the payment gateway and
+// updates the refund and its transaction with the outcome
+type refundProcessor struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	outboxRepo      ports.OutboxRepository
+	unitOfWork      ports.UnitOfWork
+	paymentGateway  ports.PaymentGateway
+}
+
+// process runs the refund through the gateway and returns the provider refund ID
+func (p refundProcessor) process(
+	ctx context.Context,
+	refund *entities.Refund,
+	transaction *entities.Transaction,
+) (string, error) {
+	// Mark refund as processing
+	if err := refund.MarkAsProcessing(); err != nil {
+		return "", fmt.Errorf("failed to mark refund as processing: %w", err)
+	}
+
+	if err := p.refundRepo.Update(ctx, refund); err != nil {
+		return "", fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	// Process refund through payment gateway
+	providerRefundID, err := p.paymentGateway.ProcessRefund(ctx, refund, transaction)
+	if err != nil {
+		// Refund failed; give the reserved amount back to the transaction
+		_ = refund.MarkAsFailed("REFUND_FAILED", err.Error())
+		_ = p.refundRepo.Release(ctx, refund)
+
+		return "", fmt.Errorf("refund processing failed: %w", err)
+	}
+
+	// Record the outcome on the refund and its transaction together with the
+	// refund.completed event, so a crash cannot leave a completed refund on a
+	// transaction that shows none, or one the partner is never told about
+	err = p.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		return p.complete(ctx, refund, transaction, providerRefundID)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return providerRefundID, nil
+}
+
+// complete marks the refund as completed, updates the transaction's status
+// and records the refund.completed event
+func (p refundProcessor) complete(
+	ctx context.Context,
+	refund *entities.Refund,
+	transaction *entities.Transaction,
+	providerRefundID string,
+) error {
+	// Mark refund as completed
+	if err := refund.MarkAsCompleted(providerRefundID); err != nil {
+		return fmt.Errorf("failed to mark refund as completed: %w", err)
+	}
+
+	if err := p.refundRepo.Update(ctx, refund); err != nil {
+		return fmt.Errorf("failed to update refund: %w", err)
+	}
+
+	// Update transaction status (the completed total now includes this refund).
+	// Another refund of the same transaction may have completed since it was
+	// loaded; the status only depends on the refunded total, so it is worked
+	// out again on the latest version instead of failing a refund the
+	// provider has already made.
+	for attempt := 1; ; attempt++ {
+		totalRefunded, err := p.refundRepo.GetTotalRefundedAmount(ctx, transaction.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get total refunded: %w", err)
+		}
+
+		isFullRefund := totalRefunded >= transaction.Amount.Amount
+		if err := transaction.MarkAsRefunded(!isFullRefund); err != nil {
+			return fmt.Errorf("failed to update transaction status: %w", err)
+		}
+
+		err = p.transactionRepo.Update(ctx, transaction)
+		if err == nil {
+			return addRefundEvent(ctx, p.outboxRepo, entities.EventRefundCompleted, refund, transaction)
+		}
+		if _, conflict := err.(*errors.VersionConflictError); !conflict || attempt == maxStatusUpdateAttempts {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+
+		latest, err := p.transactionRepo.GetByID(ctx, transaction.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+		*transaction = *latest
+	}
+}
@@ -0,0 +1,278 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// SoftDeleteInput identifies the transaction or refund an admin deletes or
+// restores
+type SoftDeleteInput struct {
+	ID        uuid.UUID
+	AdminID   string
+	IPAddress string
+	UserAgent string
+}
+
+// DeleteTransactionUseCase soft-deletes transactions recorded by mistake.
+// Deleted transactions are hidden from partners and can be restored.
+type DeleteTransactionUseCase struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	auditLogger     ports.AuditLogger
+}
+
+// NewDeleteTransactionUseCase creates a new instance
+func NewDeleteTransactionUseCase(
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	auditLogger ports.AuditLogger,
+) *DeleteTransactionUseCase {
+	return &DeleteTransactionUseCase{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		auditLogger:     auditLogger,
+	}
+}
+
+// Execute deletes a transaction that is neither being paid nor refunded
+func (uc *DeleteTransactionUseCase) Execute(ctx context.Context, input SoftDeleteInput) (*entities.Transaction, error) {
+	// Step 1: Retrieve transaction
+	transaction, err := uc.transactionRepo.GetByID(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction == nil {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	// Step 2: The payment and refund flows cannot finish on a hidden transaction
+	if transaction.IsPending() || transaction.IsProcessing() {
+		return nil, errors.NewBusinessRuleError(
+			"transaction_in_progress",
+			"cannot delete a transaction that is still being paid",
+		)
+	}
+
+	refunds, err := uc.refundRepo.GetByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunds: %w", err)
+	}
+	for _, refund := range refunds {
+		if !refund.IsFailed() && !refund.IsCancelled() {
+			return nil, errors.NewBusinessRuleError(
+				"transaction_has_refunds",
+				"can only delete transactions whose refunds all failed or were cancelled",
+			)
+		}
+	}
+
+	// Step 3: Soft-delete
+	transaction.SoftDelete()
+	if err := uc.transactionRepo.Delete(ctx, transaction); err != nil {
+		return nil, fmt.Errorf("failed to delete transaction: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    transaction.PartnerID,
+			Action:       "transaction_deleted",
+			ResourceType: "transaction",
+			ResourceID:   transaction.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":   input.AdminID,
+				"deleted_at": transaction.DeletedAt,
+			},
+		})
+	}
+
+	return transaction, nil
+}
+
+// RestoreTransactionUseCase undoes DeleteTransactionUseCase
+type RestoreTransactionUseCase struct {
+	transactionRepo ports.TransactionRepository
+	auditLogger     ports.AuditLogger
+}
+
+// NewRestoreTransactionUseCase creates a new instance
+func NewRestoreTransactionUseCase(
+	transactionRepo ports.TransactionRepository,
+	auditLogger ports.AuditLogger,
+) *RestoreTransactionUseCase {
+	return &RestoreTransactionUseCase{
+		transactionRepo: transactionRepo,
+		auditLogger:     auditLogger,
+	}
+}
+
+// Execute restores a deleted transaction and returns it
+func (uc *RestoreTransactionUseCase) Execute(ctx context.Context, input SoftDeleteInput) (*entities.Transaction, error) {
+	// Step 1: Restore
+	if err := uc.transactionRepo.Restore(ctx, input.ID); err != nil {
+		return nil, err
+	}
+
+	// Step 2: Retrieve the restored transaction
+	transaction, err := uc.transactionRepo.GetByID(ctx, input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    transaction.PartnerID,
+			Action:       "transaction_restored",
+			ResourceType: "transaction",
+			ResourceID:   transaction.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id": input.AdminID,
+			},
+		})
+	}
+
+	return transaction, nil
+}
+
+// DeleteRefundUseCase soft-deletes refunds recorded by mistake. Only refunds
+// that never moved money can be deleted, so refunded totals stay correct.
+type DeleteRefundUseCase struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	auditLogger     ports.AuditLogger
+}
+
+// NewDeleteRefundUseCase creates a new instance
+func NewDeleteRefundUseCase(
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	auditLogger ports.AuditLogger,
+) *DeleteRefundUseCase {
+	return &DeleteRefundUseCase{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		auditLogger:     auditLogger,
+	}
+}
+
+// Execute deletes a failed or cancelled refund
+func (uc *DeleteRefundUseCase) Execute(ctx context.Context, input SoftDeleteInput) (*entities.Refund, error) {
+	// Step 1: Retrieve refund
+	refund, err := uc.refundRepo.GetByID(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund == nil {
+		return nil, errors.ErrRefundNotFound
+	}
+
+	// Step 2: Completed refunds count towards the refunded total, and the
+	// others still hold part of the transaction's refundable balance
+	if !refund.IsFailed() && !refund.IsCancelled() {
+		return nil, errors.NewBusinessRuleError(
+			"refund_not_deletable",
+			"can only delete failed or cancelled refunds",
+		)
+	}
+
+	// Step 3: Soft-delete
+	refund.SoftDelete()
+	if err := uc.refundRepo.Delete(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to delete refund: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    refundPartnerID(ctx, uc.transactionRepo, refund),
+			Action:       "refund_deleted",
+			ResourceType: "refund",
+			ResourceID:   refund.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":       input.AdminID,
+				"transaction_id": refund.TransactionID.String(),
+				"deleted_at":     refund.DeletedAt,
+			},
+		})
+	}
+
+	return refund, nil
+}
+
+// RestoreRefundUseCase undoes DeleteRefundUseCase
+type RestoreRefundUseCase struct {
+	transactionRepo ports.TransactionRepository
+	refundRepo      ports.RefundRepository
+	auditLogger     ports.AuditLogger
+}
+
+// NewRestoreRefundUseCase creates a new instance
+func NewRestoreRefundUseCase(
+	transactionRepo ports.TransactionRepository,
+	refundRepo ports.RefundRepository,
+	auditLogger ports.AuditLogger,
+) *RestoreRefundUseCase {
+	return &RestoreRefundUseCase{
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		auditLogger:     auditLogger,
+	}
+}
+
+// Execute restores a deleted refund and returns it
+func (uc *RestoreRefundUseCase) Execute(ctx context.Context, input SoftDeleteInput) (*entities.Refund, error) {
+	// Step 1: Restore
+	if err := uc.refundRepo.Restore(ctx, input.ID); err != nil {
+		return nil, err
+	}
+
+	// Step 2: Retrieve the restored refund
+	refund, err := uc.refundRepo.GetByID(ctx, input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refund: %w", err)
+	}
+
+	// Step 3: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    refundPartnerID(ctx, uc.transactionRepo, refund),
+			Action:       "refund_restored",
+			ResourceType: "refund",
+			ResourceID:   refund.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"admin_id":       input.AdminID,
+				"transaction_id": refund.TransactionID.String(),
+			},
+		})
+	}
+
+	return refund, nil
+}
+
+// refundPartnerID returns the partner the refunded transaction belongs to,
+// or uuid.Nil if the transaction has been deleted too
+func refundPartnerID(ctx context.Context, transactionRepo ports.TransactionRepository, refund *entities.Refund) uuid.UUID {
+	transaction, err := transactionRepo.GetByID(ctx, refund.TransactionID)
+	if err != nil || transaction == nil {
+		return uuid.Nil
+	}
+	return transaction.PartnerID
+}
@@ -0,0 +1,166 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// MaxRangeDays is the most days usage can be read for at once
+const MaxRangeDays = 366
+
+// PaymentVolume is the live payments of one currency
+type PaymentVolume struct {
+	Currency       string
+	Payments       int64
+	FailedPayments int64
+	Volume         int64 // Amount of the successful payments in minor units
+}
+
+// Usage is the usage of a partner over some time
+type Usage struct {
+	APICalls     int64
+	ClientErrors int64
+	ServerErrors int64
+	// Payments are ordered by currency
+	Payments []PaymentVolume
+}
+
+// ErrorRate is the share of API calls answered with an error; nil without
+// calls
+func (u Usage) ErrorRate() *float64 {
+	if u.APICalls == 0 {
+		return nil
+	}
+	rate := float64(u.ClientErrors+u.ServerErrors) / float64(u.APICalls)
+	return &rate
+}
+
+// add adds record to the usage
+func (u *Usage) add(record ports.UsageRecord) {
+	u.APICalls += record.APICalls
+	u.ClientErrors += record.ClientErrors
+	u.ServerErrors += record.ServerErrors
+	if record.Currency == "" {
+		return
+	}
+	i := sort.Search(len(u.Payments), func(i int) bool { return u.Payments[i].Currency >= record.Currency })
+	if i == len(u.Payments) || u.Payments[i].Currency != record.Currency {
+		u.Payments = append(u.Payments, PaymentVolume{})
+		copy(u.Payments[i+1:], u.Payments[i:])
+		u.Payments[i] = PaymentVolume{Currency: record.Currency}
+	}
+	u.Payments[i].Payments += record.Payments
+	u.Payments[i].FailedPayments += record.FailedPayments
+	u.Payments[i].Volume += record.Volume
+}
+
+// DailyUsage is a partner's usage on one UTC day
+type DailyUsage struct {
+	Day time.Time
+	Usage
+}
+
+// PartnerUsage is a partner's usage over a range of days
+type PartnerUsage struct {
+	PartnerID uuid.UUID
+	Usage
+}
+
+// GetUsageUseCase reads a partner's own usage per day
+type GetUsageUseCase struct {
+	usageRepo ports.UsageRepository
+}
+
+// NewGetUsageUseCase creates a new instance
+func NewGetUsageUseCase(usageRepo ports.UsageRepository) *GetUsageUseCase {
+	return &GetUsageUseCase{
+		usageRepo: usageRepo,
+	}
+}
+
+// Execute returns the partner's usage on each day from from to to, both
+// inclusive, that it used the API. Usage of the last flush interval is not
+// included yet.
+func (uc *GetUsageUseCase) Execute(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]DailyUsage, error) {
+	records, err := listUsage(ctx, uc.usageRepo, ports.UsageQuery{PartnerID: &partnerID, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	var days []DailyUsage
+	for _, record := range records {
+		if len(days) == 0 || !days[len(days)-1].Day.Equal(record.Day) {
+			days = append(days, DailyUsage{Day: record.Day})
+		}
+		days[len(days)-1].add(record)
+	}
+	return days, nil
+}
+
+// GetUsageRollupUseCase adds up the usage of every partner for the back
+// office
+type GetUsageRollupUseCase struct {
+	usageRepo ports.UsageRepository
+}
+
+// NewGetUsageRollupUseCase creates a new instance
+func NewGetUsageRollupUseCase(usageRepo ports.UsageRepository) *GetUsageRollupUseCase {
+	return &GetUsageRollupUseCase{
+		usageRepo: usageRepo,
+	}
+}
+
+// Execute returns each partner's usage from from to to, both inclusive,
+// busiest partners first
+func (uc *GetUsageRollupUseCase) Execute(ctx context.Context, from, to time.Time) ([]PartnerUsage, error) {
+	records, err := listUsage(ctx, uc.usageRepo, ports.UsageQuery{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	byPartner := make(map[uuid.UUID]*PartnerUsage)
+	for _, record := range records {
+		partner, ok := byPartner[record.PartnerID]
+		if !ok {
+			partner = &PartnerUsage{PartnerID: record.PartnerID}
+			byPartner[record.PartnerID] = partner
+		}
+		partner.add(record)
+	}
+	partners := make([]PartnerUsage, 0, len(byPartner))
+	for _, partner := range byPartner {
+		partners = append(partners, *partner)
+	}
+	sort.Slice(partners, func(i, j int) bool {
+		if partners[i].APICalls != partners[j].APICalls {
+			return partners[i].APICalls > partners[j].APICalls
+		}
+		return partners[i].PartnerID.String() < partners[j].PartnerID.String()
+	})
+	return partners, nil
+}
+
+// listUsage checks the range of query and reads it from the replica when
+// there is one
+func listUsage(ctx context.Context, usageRepo ports.UsageRepository, query ports.UsageQuery) ([]ports.UsageRecord, error) {
+	query.From, query.To = ports.UsageDay(query.From), ports.UsageDay(query.To)
+	if query.To.Before(query.From) {
+		return nil, errors.NewValidationError("date_to", "must not be before date_from")
+	}
+	if query.To.Sub(query.From) >= MaxRangeDays*24*time.Hour {
+		return nil, errors.NewValidationError("date_from", fmt.Sprintf("range must not exceed %d days", MaxRangeDays))
+	}
+
+	records, err := usageRepo.ListUsage(ports.ReadOnly(ctx), query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	return records, nil
+}
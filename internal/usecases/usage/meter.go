@@ -0,0 +1,178 @@
+// Package usage meters what each partner uses of the API, its calls, errors
+// and payment volume per day, as the basis for billing and capacity planning
+package usage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// Meter counts usage in memory and adds it to the usage repository every
+// flush interval, so metering never waits on the database. Usage counted
+// since the last flush is lost if the process dies without shutting down.
+type Meter struct {
+	repo          ports.UsageRepository
+	flushInterval time.Duration
+
+	// onError is told about usage that could not be written; it is kept and
+	// written with the next flush
+	onError func(err error)
+
+	mu      sync.Mutex
+	pending map[meterKey]*ports.UsageRecord
+}
+
+// meterKey identifies the record a call or payment is counted in
+type meterKey struct {
+	partnerID uuid.UUID
+	day       time.Time
+	currency  string
+}
+
+// NewMeter creates a meter flushing to repo
+func NewMeter(repo ports.UsageRepository, flushInterval time.Duration, onError func(err error)) *Meter {
+	return &Meter{
+		repo:          repo,
+		flushInterval: flushInterval,
+		onError:       onError,
+		pending:       make(map[meterKey]*ports.UsageRecord),
+	}
+}
+
+// RecordCall counts an API call of partnerID answered with status
+func (m *Meter) RecordCall(partnerID uuid.UUID, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record := m.record(partnerID, "")
+	record.APICalls++
+	switch {
+	case status >= 500:
+		record.ServerErrors++
+	case status >= 400:
+		record.ClientErrors++
+	}
+}
+
+// RecordPayment counts a live payment sent to a provider, failed when err
+// is not nil
+func (m *Meter) RecordPayment(transaction *entities.Transaction, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record := m.record(transaction.PartnerID, string(transaction.Amount.Currency))
+	if err != nil {
+		record.FailedPayments++
+		return
+	}
+	record.Payments++
+	record.Volume += transaction.Amount.Amount
+}
+
+// record returns today's pending record of partnerID and currency
+func (m *Meter) record(partnerID uuid.UUID, currency string) *ports.UsageRecord {
+	key := meterKey{partnerID: partnerID, day: ports.UsageDay(time.Now()), currency: currency}
+	record, ok := m.pending[key]
+	if !ok {
+		record = &ports.UsageRecord{PartnerID: partnerID, Day: key.day, Currency: currency}
+		m.pending[key] = record
+	}
+	return record
+}
+
+// Run flushes every flush interval until ctx is done, then flushes what is
+// left
+func (m *Meter) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Flush(ctx)
+		case <-ctx.Done():
+			// ctx is done, the last flush needs one that is not
+			m.Flush(context.Background())
+			return
+		}
+	}
+}
+
+// Flush adds the usage counted since the last flush to the repository. Usage
+// that cannot be written is counted again in the next flush.
+func (m *Meter) Flush(ctx context.Context) {
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[meterKey]*ports.UsageRecord)
+	m.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	// In key order, so flushes of several instances lock rows in the same
+	// order
+	records := make([]ports.UsageRecord, 0, len(pending))
+	for _, record := range pending {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.PartnerID != b.PartnerID {
+			return a.PartnerID.String() < b.PartnerID.String()
+		}
+		return a.Currency < b.Currency
+	})
+
+	err := m.repo.AddUsage(ctx, records)
+	if err == nil {
+		return
+	}
+	if m.onError != nil {
+		m.onError(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, record := range pending {
+		current, ok := m.pending[key]
+		if !ok {
+			m.pending[key] = record
+			continue
+		}
+		current.APICalls += record.APICalls
+		current.ClientErrors += record.ClientErrors
+		current.ServerErrors += record.ServerErrors
+		current.Payments += record.Payments
+		current.FailedPayments += record.FailedPayments
+		current.Volume += record.Volume
+	}
+}
+
+// gateway meters live payments
+type gateway struct {
+	ports.PaymentGateway
+	meter *Meter
+}
+
+// Gateway wraps g so live payments are metered. Sandbox payments and
+// payments the caller gave up on are not.
+func (m *Meter) Gateway(g ports.PaymentGateway) ports.PaymentGateway {
+	return &gateway{PaymentGateway: g, meter: m}
+}
+
+// ProcessPayment processes the payment and meters it
+func (g *gateway) ProcessPayment(ctx context.Context, transaction *entities.Transaction) (string, error) {
+	id, err := g.PaymentGateway.ProcessPayment(ctx, transaction)
+	if transaction.Livemode && ctx.Err() == nil {
+		g.meter.RecordPayment(transaction, err)
+	}
+	return id, err
+}
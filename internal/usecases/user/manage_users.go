@@ -0,0 +1,265 @@
+// Package user contains use cases for partner team members and their sessions
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// CreateUserInput represents input for adding a team member
+type CreateUserInput struct {
+	PartnerID uuid.UUID
+	Email     string
+	Name      string
+	Role      string
+	Password  string
+	IPAddress string
+	UserAgent string
+}
+
+// CreateUserUseCase adds a member to a partner's team
+type CreateUserUseCase struct {
+	userRepo    ports.UserRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewCreateUserUseCase creates a new instance
+func NewCreateUserUseCase(userRepo ports.UserRepository, auditLogger ports.AuditLogger) *CreateUserUseCase {
+	return &CreateUserUseCase{
+		userRepo:    userRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute creates and persists a new team member
+func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput) (*entities.User, error) {
+	// Step 1: Validate role
+	role, err := valueobjects.NewUserRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Create user entity
+	user, err := entities.NewUser(input.PartnerID, input.Email, input.Name, role, input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Persist (fails if the email is already taken)
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		if err == errors.ErrUserAlreadyExists {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "user_created",
+			ResourceType: "user",
+			ResourceID:   user.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"email": user.Email,
+				"role":  user.Role,
+			},
+		})
+	}
+
+	return user, nil
+}
+
+// ListUsersUseCase lists a partner's team members
+type ListUsersUseCase struct {
+	userRepo ports.UserRepository
+}
+
+// NewListUsersUseCase creates a new instance
+func NewListUsersUseCase(userRepo ports.UserRepository) *ListUsersUseCase {
+	return &ListUsersUseCase{
+		userRepo: userRepo,
+	}
+}
+
+// Execute lists team members of the partner
+func (uc *ListUsersUseCase) Execute(ctx context.Context, partnerID uuid.UUID) ([]*entities.User, error) {
+	users, err := uc.userRepo.ListByPartnerID(ctx, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateUserRoleInput represents input for changing a member's role
+type UpdateUserRoleInput struct {
+	UserID    uuid.UUID
+	PartnerID uuid.UUID
+	Role      string
+	IPAddress string
+	UserAgent string
+}
+
+// UpdateUserRoleUseCase changes a team member's role
+type UpdateUserRoleUseCase struct {
+	userRepo    ports.UserRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewUpdateUserRoleUseCase creates a new instance
+func NewUpdateUserRoleUseCase(userRepo ports.UserRepository, auditLogger ports.AuditLogger) *UpdateUserRoleUseCase {
+	return &UpdateUserRoleUseCase{
+		userRepo:    userRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute changes the role of a member of the partner's team
+func (uc *UpdateUserRoleUseCase) Execute(ctx context.Context, input UpdateUserRoleInput) (*entities.User, error) {
+	// Step 1: Validate role
+	role, err := valueobjects.NewUserRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Retrieve user and verify partner owns it
+	user, err := getTeamMember(ctx, uc.userRepo, input.UserID, input.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Business Rule - the last owner cannot be demoted
+	previousRole := user.Role
+	if previousRole == valueobjects.RoleOwner && role != valueobjects.RoleOwner {
+		if err := ensureAnotherOwner(ctx, uc.userRepo, input.PartnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 4: Update role
+	if err := user.SetRole(role); err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Step 5: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "user_role_changed",
+			ResourceType: "user",
+			ResourceID:   user.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"previous_role": previousRole,
+				"role":          user.Role,
+			},
+		})
+	}
+
+	return user, nil
+}
+
+// RemoveUserInput represents input for removing a team member
+type RemoveUserInput struct {
+	UserID    uuid.UUID
+	PartnerID uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// RemoveUserUseCase removes a member from a partner's team
+type RemoveUserUseCase struct {
+	userRepo    ports.UserRepository
+	auditLogger ports.AuditLogger
+}
+
+// NewRemoveUserUseCase creates a new instance
+func NewRemoveUserUseCase(userRepo ports.UserRepository, auditLogger ports.AuditLogger) *RemoveUserUseCase {
+	return &RemoveUserUseCase{
+		userRepo:    userRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute soft-deletes a member of the partner's team
+func (uc *RemoveUserUseCase) Execute(ctx context.Context, input RemoveUserInput) error {
+	// Step 1: Retrieve user and verify partner owns it
+	user, err := getTeamMember(ctx, uc.userRepo, input.UserID, input.PartnerID)
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Business Rule - the last owner cannot be removed
+	if user.Role == valueobjects.RoleOwner {
+		if err := ensureAnotherOwner(ctx, uc.userRepo, input.PartnerID); err != nil {
+			return err
+		}
+	}
+
+	// Step 3: Soft delete (existing sessions stop working with the user)
+	user.SoftDelete()
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    input.PartnerID,
+			Action:       "user_removed",
+			ResourceType: "user",
+			ResourceID:   user.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+			Changes: map[string]interface{}{
+				"email": user.Email,
+				"role":  user.Role,
+			},
+		})
+	}
+
+	return nil
+}
+
+// getTeamMember retrieves a user, reporting other partners' users as missing
+func getTeamMember(ctx context.Context, userRepo ports.UserRepository, userID, partnerID uuid.UUID) (*entities.User, error) {
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.PartnerID != partnerID {
+		return nil, errors.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// ensureAnotherOwner fails unless the partner has more than one owner
+func ensureAnotherOwner(ctx context.Context, userRepo ports.UserRepository, partnerID uuid.UUID) error {
+	owners, err := userRepo.CountByRole(ctx, partnerID, valueobjects.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return errors.ErrLastOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// LoginInput represents a team member's sign-in attempt
+type LoginInput struct {
+	Email     string
+	Password  string
+	IPAddress string
+	UserAgent string
+}
+
+// LoginOutput carries the new session and its one-time plaintext token
+type LoginOutput struct {
+	User    *entities.User
+	Session *entities.UserSession
+	Token   string
+}
+
+// LoginUseCase signs a team member in and starts a session
+type LoginUseCase struct {
+	userRepo    ports.UserRepository
+	sessionRepo ports.UserSessionRepository
+	auditLogger ports.AuditLogger
+
+	// sessionTTL is how long a session token stays valid
+	sessionTTL time.Duration
+}
+
+// NewLoginUseCase creates a new instance
+func NewLoginUseCase(
+	userRepo ports.UserRepository,
+	sessionRepo ports.UserSessionRepository,
+	auditLogger ports.AuditLogger,
+	sessionTTL time.Duration,
+) *LoginUseCase {
+	return &LoginUseCase{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		auditLogger: auditLogger,
+		sessionTTL:  sessionTTL,
+	}
+}
+
+// Execute verifies credentials and issues a session token
+func (uc *LoginUseCase) Execute(ctx context.Context, input LoginInput) (*LoginOutput, error) {
+	// Step 1: Retrieve user (unknown emails and wrong passwords look the same)
+	user, err := uc.userRepo.GetByEmail(ctx, input.Email)
+	if err != nil || user == nil {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	// Step 2: Verify password
+	if err := user.CheckPassword(input.Password); err != nil {
+		return nil, err
+	}
+
+	// Step 3: Start session
+	session, token, err := entities.NewUserSession(user.ID, uc.sessionTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	user.RecordLogin()
+	_ = uc.userRepo.Update(ctx, user)
+
+	// Step 4: Log audit event
+	if uc.auditLogger != nil {
+		_ = uc.auditLogger.LogAction(ctx, ports.AuditAction{
+			PartnerID:    user.PartnerID,
+			Action:       "user_logged_in",
+			ResourceType: "user",
+			ResourceID:   user.ID,
+			IPAddress:    input.IPAddress,
+			UserAgent:    input.UserAgent,
+		})
+	}
+
+	return &LoginOutput{
+		User:    user,
+		Session: session,
+		Token:   token,
+	}, nil
+}
+
+// LogoutUseCase ends a team member's session
+type LogoutUseCase struct {
+	sessionRepo ports.UserSessionRepository
+}
+
+// NewLogoutUseCase creates a new instance
+func NewLogoutUseCase(sessionRepo ports.UserSessionRepository) *LogoutUseCase {
+	return &LogoutUseCase{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Execute revokes the session
+func (uc *LogoutUseCase) Execute(ctx context.Context, session *entities.UserSession) error {
+	session.Revoke()
+
+	if err := uc.sessionRepo.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
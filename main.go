@@ -2,9 +2,12 @@ package main
 
 import (
 	"Pay2Go/adapter"
-	"Pay2Go/entities"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/infrastructure/migrate"
+	"Pay2Go/migrations"
 	"Pay2Go/repositories"
 	"Pay2Go/usecases"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -52,11 +55,31 @@ func main() {
 		log.Fatal("failed to connect database")
 	}
 
-	db.AutoMigrate(&entities.Transaction{})
+	// The schema is owned by the versioned SQL migrations; AutoMigrate would
+	// alter the shared transactions table to fit this model
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("failed to get database handle: " + err.Error())
+	}
+	migrator, err := migrate.New(sqlDB, migrate.Postgres, migrations.FS)
+	if err != nil {
+		log.Fatal("failed to load migrations: " + err.Error())
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatal("failed to migrate database: " + err.Error())
+	}
+
 	repo := repositories.NewGormTransactionRepository(db)
 	usecase := usecases.NewTransactionService(repo)
 	handler := adapter.NewHTTPHandler(usecase)
 
+	// These routes predate the versioned API (cmd/api), which also answers
+	// them at the root for old clients; new clients use /api/v2/transactions
+	app.Use(middleware.Deprecation{
+		Since:     time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v2",
+	}.Handle)
+
 	app.Post("/transactions", handler.CreateTransaction)
 	app.Get("/transactions/:id", handler.GetTransactionByID)
 	app.Put("/transactions/:id", handler.UpdateTransaction)
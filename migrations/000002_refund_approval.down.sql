@@ -0,0 +1,16 @@
+-- Rollback migration for refund approval workflow
+
+DROP INDEX IF EXISTS idx_refunds_requires_approval;
+
+-- Release refunds still waiting for approval before removing the columns
+UPDATE refunds SET status = 'pending' WHERE status = 'requires_approval';
+
+ALTER TABLE refunds
+    DROP COLUMN IF EXISTS approved_at,
+    DROP COLUMN IF EXISTS approved_by;
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS refund_approval_threshold;
+
+-- Note: PostgreSQL cannot drop a value from an ENUM type; 'requires_approval'
+-- stays in refund_status and is simply no longer used.
@@ -0,0 +1,18 @@
+-- Migration: Refund approval workflow
+-- Version: 000002
+-- Description: Lets partners require manual approval for refunds above a threshold
+
+ALTER TYPE refund_status ADD VALUE IF NOT EXISTS 'requires_approval' AFTER 'pending';
+
+ALTER TABLE partners
+    ADD COLUMN refund_approval_threshold DECIMAL(19, 4) NOT NULL DEFAULT 0
+        CHECK (refund_approval_threshold >= 0);
+
+ALTER TABLE refunds
+    ADD COLUMN approved_by VARCHAR(255),
+    ADD COLUMN approved_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_refunds_requires_approval ON refunds(created_at) WHERE status = 'requires_approval' AND deleted_at IS NULL;
+
+COMMENT ON COLUMN partners.refund_approval_threshold IS 'Refunds above this amount wait for admin approval (0 disables approval)';
+COMMENT ON COLUMN refunds.approved_by IS 'Identity of the administrator who approved the refund';
@@ -0,0 +1,12 @@
+-- Rollback migration for refund cancellation
+
+-- Cancelled refunds never reached the provider; mark them failed so they stay excluded from totals
+UPDATE refunds
+SET status = 'failed', error_code = 'CANCELLED', error_message = 'refund cancelled by partner'
+WHERE status = 'cancelled';
+
+ALTER TABLE refunds
+    DROP COLUMN IF EXISTS cancelled_at;
+
+-- Note: PostgreSQL cannot drop a value from an ENUM type; 'cancelled'
+-- stays in refund_status and is simply no longer used.
@@ -0,0 +1,10 @@
+-- Migration: Refund cancellation
+-- Version: 000003
+-- Description: Lets partners cancel refunds before they reach the provider
+
+ALTER TYPE refund_status ADD VALUE IF NOT EXISTS 'cancelled' AFTER 'failed';
+
+ALTER TABLE refunds
+    ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN refunds.cancelled_at IS 'When the partner cancelled the refund before it was processed';
@@ -0,0 +1,4 @@
+-- Rollback migration for bulk refund jobs
+
+DROP TABLE IF EXISTS bulk_refund_jobs;
+DROP TYPE IF EXISTS bulk_refund_job_status;
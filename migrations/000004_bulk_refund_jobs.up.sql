@@ -0,0 +1,30 @@
+-- Migration: Bulk refund jobs
+-- Version: 000004
+-- Description: Tracks asynchronous bulk refunds used for incident remediation
+
+CREATE TYPE bulk_refund_job_status AS ENUM (
+    'pending',
+    'running',
+    'completed'
+);
+
+CREATE TABLE bulk_refund_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+
+    transaction_ids JSONB NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+
+    status bulk_refund_job_status NOT NULL DEFAULT 'pending',
+    results JSONB NOT NULL DEFAULT '[]',
+    succeeded INTEGER NOT NULL DEFAULT 0 CHECK (succeeded >= 0),
+    failed INTEGER NOT NULL DEFAULT 0 CHECK (failed >= 0),
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_bulk_refund_jobs_partner_id ON bulk_refund_jobs(partner_id, created_at DESC);
+
+COMMENT ON TABLE bulk_refund_jobs IS 'Asynchronous batches of full refunds with per-transaction results';
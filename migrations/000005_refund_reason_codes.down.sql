@@ -0,0 +1,26 @@
+-- Rollback migration for refund reason codes
+
+DROP INDEX IF EXISTS idx_refunds_reason_code;
+
+-- Fold codes back into the free-text reason before dropping them
+UPDATE refunds
+SET reason = CASE
+    WHEN reason IS NULL OR reason = '' THEN reason_code::text
+    ELSE reason_code::text || ': ' || reason
+END;
+
+UPDATE bulk_refund_jobs
+SET reason = CASE
+    WHEN reason IS NULL OR reason = '' THEN reason_code::text
+    ELSE reason_code::text || ': ' || reason
+END;
+
+ALTER TABLE refunds
+    ALTER COLUMN reason SET NOT NULL,
+    DROP COLUMN IF EXISTS reason_code;
+
+ALTER TABLE bulk_refund_jobs
+    ALTER COLUMN reason SET NOT NULL,
+    DROP COLUMN IF EXISTS reason_code;
+
+DROP TYPE IF EXISTS refund_reason_code;
@@ -0,0 +1,24 @@
+-- Migration: Refund reason codes
+-- Version: 000005
+-- Description: Replaces free-text refund reasons with a reason code plus optional note
+
+CREATE TYPE refund_reason_code AS ENUM (
+    'requested_by_customer',
+    'duplicate',
+    'fraudulent',
+    'other'
+);
+
+-- Existing free-text reasons become notes on the "other" code
+ALTER TABLE refunds
+    ADD COLUMN reason_code refund_reason_code NOT NULL DEFAULT 'other',
+    ALTER COLUMN reason DROP NOT NULL;
+
+ALTER TABLE bulk_refund_jobs
+    ADD COLUMN reason_code refund_reason_code NOT NULL DEFAULT 'other',
+    ALTER COLUMN reason DROP NOT NULL;
+
+CREATE INDEX idx_refunds_reason_code ON refunds(reason_code, created_at) WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN refunds.reason_code IS 'Reason taxonomy used for reconciliation and dispute analysis';
+COMMENT ON COLUMN refunds.reason IS 'Optional free-text note (required when reason_code is other)';
@@ -0,0 +1,4 @@
+-- Rollback migration for per-partner refund window
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS refund_window_days;
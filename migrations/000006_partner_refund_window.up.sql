@@ -0,0 +1,9 @@
+-- Migration: Per-partner refund window
+-- Version: 000006
+-- Description: Lets each partner override the platform default refund window
+
+ALTER TABLE partners
+    ADD COLUMN refund_window_days INTEGER NOT NULL DEFAULT 0
+        CHECK (refund_window_days >= 0 AND refund_window_days <= 3650);
+
+COMMENT ON COLUMN partners.refund_window_days IS 'Days after payment a refund is allowed (0 uses the platform default)';
@@ -0,0 +1,5 @@
+-- Rollback migration for atomic refund accounting
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS chk_transactions_refunded_amount,
+    DROP COLUMN IF EXISTS refunded_amount;
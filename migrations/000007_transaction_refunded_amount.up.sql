@@ -0,0 +1,24 @@
+-- Migration: Atomic refund accounting
+-- Version: 000007
+-- Description: Tracks refunded totals on the transaction row so concurrent refunds cannot over-refund
+
+ALTER TABLE transactions
+    ADD COLUMN refunded_amount DECIMAL(19, 4) NOT NULL DEFAULT 0;
+
+-- Backfill from refunds that are completed or still in flight
+UPDATE transactions t
+SET refunded_amount = r.total
+FROM (
+    SELECT transaction_id, SUM(amount) AS total
+    FROM refunds
+    WHERE status IN ('pending', 'requires_approval', 'processing', 'completed')
+      AND deleted_at IS NULL
+    GROUP BY transaction_id
+) r
+WHERE t.id = r.transaction_id;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT chk_transactions_refunded_amount
+        CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
+
+COMMENT ON COLUMN transactions.refunded_amount IS 'Sum of pending and completed refunds, reserved atomically when a refund is created';
@@ -0,0 +1,3 @@
+-- Rollback migration for multiple API keys per partner
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,29 @@
+-- Migration: Multiple API keys per partner
+-- Version: 000008
+-- Description: Named, scoped API keys; existing partner keys become a "default" admin key
+
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+
+    label VARCHAR(100) NOT NULL,
+    key_hash VARCHAR(255) NOT NULL UNIQUE,
+    key_prefix VARCHAR(10) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL
+        CHECK (cardinality(scopes) > 0 AND scopes <@ ARRAY['read_only', 'payments', 'refunds', 'admin']),
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_keys_partner_id ON api_keys(partner_id);
+
+-- Keep existing integrations working with full access
+INSERT INTO api_keys (partner_id, label, key_hash, key_prefix, scopes, created_at, updated_at)
+SELECT id, 'default', api_key_hash, api_key_prefix, ARRAY['admin'], created_at, updated_at
+FROM partners
+WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE api_keys IS 'Partner API keys; the plaintext key is never stored';
+COMMENT ON COLUMN api_keys.scopes IS 'read_only, payments, refunds, admin (admin grants all)';
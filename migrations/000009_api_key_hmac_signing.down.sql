@@ -0,0 +1,7 @@
+-- Rollback migration for HMAC request signing
+
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_signing_secret_check;
+
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS signing_secret,
+    DROP COLUMN IF EXISTS auth_method;
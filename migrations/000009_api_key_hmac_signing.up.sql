@@ -0,0 +1,16 @@
+-- Migration: HMAC request signing
+-- Version: 000009
+-- Description: Per-key choice between bearer tokens and HMAC-signed requests
+
+ALTER TABLE api_keys
+    ADD COLUMN auth_method VARCHAR(10) NOT NULL DEFAULT 'bearer'
+        CHECK (auth_method IN ('bearer', 'hmac')),
+    ADD COLUMN signing_secret VARCHAR(255);
+
+-- HMAC keys need the secret to recompute signatures; bearer keys must not keep one
+ALTER TABLE api_keys
+    ADD CONSTRAINT api_keys_signing_secret_check
+        CHECK ((auth_method = 'hmac') = (signing_secret IS NOT NULL));
+
+COMMENT ON COLUMN api_keys.auth_method IS 'bearer (key sent in Authorization header) or hmac (requests signed with the key)';
+COMMENT ON COLUMN api_keys.signing_secret IS 'Shared HMAC secret, only set for hmac keys';
@@ -0,0 +1,7 @@
+-- Rollback migration for partner sandbox mode
+
+DROP INDEX IF EXISTS idx_transactions_partner_livemode;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS livemode;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS livemode;
@@ -0,0 +1,16 @@
+-- Migration: Partner sandbox mode
+-- Version: 000010
+-- Description: Test-mode API keys and transactions, kept apart from live data
+
+ALTER TABLE api_keys
+    ADD COLUMN livemode BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE transactions
+    ADD COLUMN livemode BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Every partner listing and report filters by mode
+CREATE INDEX idx_transactions_partner_livemode ON transactions(partner_id, livemode, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN api_keys.livemode IS 'FALSE for test keys; their transactions use the sandbox gateway';
+COMMENT ON COLUMN transactions.livemode IS 'FALSE for sandbox transactions, excluded from live listings and reports';
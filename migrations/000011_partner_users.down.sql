@@ -0,0 +1,4 @@
+-- Rollback migration for partner team users
+
+DROP TABLE IF EXISTS user_sessions;
+DROP TABLE IF EXISTS users;
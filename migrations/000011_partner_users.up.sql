@@ -0,0 +1,39 @@
+-- Migration: Partner team users
+-- Version: 000011
+-- Description: Team members with roles, signing in with email and password
+
+CREATE TABLE users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL
+        CHECK (role IN ('owner', 'developer', 'finance', 'read_only')),
+    password_hash VARCHAR(255) NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Emails sign users in, so they are unique across partners
+CREATE UNIQUE INDEX idx_users_email ON users(LOWER(email)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_partner_id ON users(partner_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE user_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_user_sessions_user_id ON user_sessions(user_id);
+
+COMMENT ON TABLE users IS 'Partner team members; role is owner, developer, finance or read_only';
+COMMENT ON COLUMN user_sessions.token_hash IS 'SHA-256 of the session token; the token itself is never stored';
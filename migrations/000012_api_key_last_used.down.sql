@@ -0,0 +1,5 @@
+-- Rollback migration for API key last-used tracking
+
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS last_used_ip,
+    DROP COLUMN IF EXISTS last_used_at;
@@ -0,0 +1,10 @@
+-- Migration: API key last-used tracking
+-- Version: 000012
+-- Description: Record when and from where each API key was last used
+
+ALTER TABLE api_keys
+    ADD COLUMN last_used_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN last_used_ip VARCHAR(45);
+
+COMMENT ON COLUMN api_keys.last_used_at IS 'Last successful authentication, written asynchronously (may lag by a flush interval)';
+COMMENT ON COLUMN api_keys.last_used_ip IS 'Client IP of the last successful authentication';
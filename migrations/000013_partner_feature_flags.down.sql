@@ -0,0 +1,3 @@
+-- Rollback migration for partner feature flags
+
+ALTER TABLE partners DROP COLUMN IF EXISTS features;
@@ -0,0 +1,8 @@
+-- Migration: Partner feature flags
+-- Version: 000013
+-- Description: Per-partner flags for rolling out capabilities gradually
+
+ALTER TABLE partners
+    ADD COLUMN features JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN partners.features IS 'Explicit feature flags, e.g. {"enable_crypto": true}; missing flags use the feature default';
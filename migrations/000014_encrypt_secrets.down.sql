@@ -0,0 +1,6 @@
+-- Rollback migration for encrypting secrets at rest
+-- Encrypted values do not fit VARCHAR(255) and cannot be read without the keys;
+-- decrypt them before rolling back.
+
+ALTER TABLE api_keys ALTER COLUMN signing_secret TYPE VARCHAR(255);
+ALTER TABLE partners ALTER COLUMN webhook_secret TYPE VARCHAR(255);
@@ -0,0 +1,12 @@
+-- Migration: Encrypt secrets at rest
+-- Version: 000014
+-- Description: Widen secret columns to hold envelope-encrypted values
+
+-- Encrypted values carry a wrapped data key and nonces, so they outgrow VARCHAR(255).
+-- Existing plaintext values stay readable and are encrypted on the next write
+-- or by POST /api/v1/admin/secrets/rotate.
+ALTER TABLE partners ALTER COLUMN webhook_secret TYPE TEXT;
+ALTER TABLE api_keys ALTER COLUMN signing_secret TYPE TEXT;
+
+COMMENT ON COLUMN partners.webhook_secret IS 'Webhook signing secret, envelope-encrypted (enc:v1:<key id>:...)';
+COMMENT ON COLUMN api_keys.signing_secret IS 'Shared HMAC secret, envelope-encrypted (enc:v1:<key id>:...); only set for hmac keys';
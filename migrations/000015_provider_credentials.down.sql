@@ -0,0 +1,5 @@
+-- Rollback migration for partner provider credentials
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS provider_credential_id;
+
+DROP TABLE IF EXISTS provider_credentials;
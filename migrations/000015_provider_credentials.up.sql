@@ -0,0 +1,29 @@
+-- Migration: Partner provider credentials
+-- Version: 000015
+-- Description: Partners' own Stripe/PayPal accounts, used instead of the platform account
+
+CREATE TABLE provider_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+
+    provider VARCHAR(20) NOT NULL
+        CHECK (provider IN ('stripe', 'paypal')),
+    account_id VARCHAR(255) NOT NULL DEFAULT '',
+    secret_key TEXT NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- One account per provider and partner
+    UNIQUE (partner_id, provider)
+);
+
+-- Refunds must go through the account that took the payment. No foreign key:
+-- the ID must survive the credential being deleted so those refunds fail
+-- instead of silently going through the platform account.
+ALTER TABLE transactions
+    ADD COLUMN provider_credential_id UUID;
+
+COMMENT ON TABLE provider_credentials IS 'Partners'' own payment provider accounts';
+COMMENT ON COLUMN provider_credentials.secret_key IS 'Provider secret, envelope-encrypted (enc:v1:<key id>:...)';
+COMMENT ON COLUMN transactions.provider_credential_id IS 'Partner provider account the payment was processed on; NULL for the platform account';
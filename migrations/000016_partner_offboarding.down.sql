@@ -0,0 +1,7 @@
+-- Rollback migration for partner off-boarding
+
+DROP INDEX IF EXISTS idx_partners_anonymize_after;
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS anonymized_at,
+    DROP COLUMN IF EXISTS anonymize_after;
@@ -0,0 +1,14 @@
+-- Migration: Partner off-boarding
+-- Version: 000016
+-- Description: Schedule anonymization of customer PII after a partner is off-boarded
+
+ALTER TABLE partners
+    ADD COLUMN anonymize_after TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN anonymized_at TIMESTAMP WITH TIME ZONE;
+
+-- The anonymization job only looks at off-boarded partners still waiting for it
+CREATE INDEX idx_partners_anonymize_after ON partners(anonymize_after)
+    WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL;
+
+COMMENT ON COLUMN partners.anonymize_after IS 'End of the PII retention period after off-boarding';
+COMMENT ON COLUMN partners.anonymized_at IS 'When customer PII on the partner''s transactions was anonymized';
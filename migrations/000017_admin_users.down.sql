@@ -0,0 +1,4 @@
+-- Rollback migration for back-office admin users
+
+DROP TABLE IF EXISTS admin_sessions;
+DROP TABLE IF EXISTS admin_users;
@@ -0,0 +1,38 @@
+-- Migration: Back-office admin users
+-- Version: 000017
+-- Description: Pay2Go staff accounts with password + TOTP sign-in and session tokens
+
+CREATE TABLE admin_users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    totp_secret TEXT NOT NULL,
+    totp_last_step BIGINT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    disabled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_admin_users_email ON admin_users(LOWER(email));
+
+CREATE TABLE admin_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id UUID NOT NULL REFERENCES admin_users(id),
+
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_admin_sessions_admin_id ON admin_sessions(admin_id);
+
+COMMENT ON TABLE admin_users IS 'Pay2Go staff with access to /api/v1/admin; separate from partner team users';
+COMMENT ON COLUMN admin_users.totp_secret IS 'Base32 TOTP secret, envelope-encrypted like other secrets';
+COMMENT ON COLUMN admin_users.totp_last_step IS 'Last accepted TOTP time step; a code is accepted at most once';
+COMMENT ON COLUMN admin_sessions.token_hash IS 'SHA-256 of the session token; the token itself is never stored';
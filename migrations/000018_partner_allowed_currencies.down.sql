@@ -0,0 +1,4 @@
+-- Rollback migration for partner allowed currencies
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS allowed_currencies;
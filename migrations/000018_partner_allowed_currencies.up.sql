@@ -0,0 +1,8 @@
+-- Migration: Partner allowed currencies
+-- Version: 000018
+-- Description: Restrict the ISO 4217 currencies a partner may create transactions in
+
+ALTER TABLE partners
+    ADD COLUMN allowed_currencies TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN partners.allowed_currencies IS 'ISO 4217 codes the partner may charge in; empty allows every supported currency';
@@ -0,0 +1,46 @@
+-- Rollback migration for integer money amounts
+
+ALTER TABLE partners
+    ALTER COLUMN refund_approval_threshold TYPE DECIMAL(19, 4)
+        USING refund_approval_threshold / 100.0;
+
+ALTER TABLE refunds
+    ALTER COLUMN amount TYPE DECIMAL(19, 4)
+        USING amount / POWER(10, currency_exponent(currency));
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_amount_positive;
+
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE DECIMAL(19, 4)
+        USING amount / POWER(10, currency_exponent(currency)),
+    ALTER COLUMN refunded_amount TYPE DECIMAL(19, 4)
+        USING refunded_amount / POWER(10, currency_exponent(currency)),
+    ADD CONSTRAINT check_amount_positive CHECK (amount >= 0.01),
+    ADD CONSTRAINT check_amount_max CHECK (amount <= 100000.00);
+
+CREATE OR REPLACE FUNCTION validate_refund_amount()
+RETURNS TRIGGER AS $$
+DECLARE
+    total_refunded DECIMAL(19, 4);
+    transaction_amount DECIMAL(19, 4);
+BEGIN
+    SELECT amount INTO transaction_amount
+    FROM transactions
+    WHERE id = NEW.transaction_id;
+
+    SELECT COALESCE(SUM(amount), 0) INTO total_refunded
+    FROM refunds
+    WHERE transaction_id = NEW.transaction_id
+    AND status = 'completed'
+    AND id != NEW.id;
+
+    IF (total_refunded + NEW.amount) > transaction_amount THEN
+        RAISE EXCEPTION 'Refund amount exceeds transaction amount';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS currency_exponent(VARCHAR);
@@ -0,0 +1,65 @@
+-- Migration: Integer money amounts
+-- Version: 000019
+-- Description: Store amounts as BIGINT minor units (e.g. cents) instead of DECIMAL major units
+
+-- ISO 4217 minor unit digits; currencies not listed use 2
+CREATE OR REPLACE FUNCTION currency_exponent(code VARCHAR)
+RETURNS INTEGER AS $$
+    SELECT CASE
+        WHEN code IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG',
+                      'RWF', 'UGX', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 0
+        WHEN code IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 3
+        ELSE 2
+    END;
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- The fixed major-unit bounds no longer apply; limits are enforced per currency by the application
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS check_amount_positive,
+    DROP CONSTRAINT IF EXISTS check_amount_max;
+
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE BIGINT
+        USING ROUND(amount * POWER(10, currency_exponent(currency)))::BIGINT,
+    ALTER COLUMN refunded_amount TYPE BIGINT
+        USING ROUND(refunded_amount * POWER(10, currency_exponent(currency)))::BIGINT,
+    ADD CONSTRAINT check_amount_positive CHECK (amount >= 1);
+
+ALTER TABLE refunds
+    ALTER COLUMN amount TYPE BIGINT
+        USING ROUND(amount * POWER(10, currency_exponent(currency)))::BIGINT;
+
+-- Thresholds have no currency of their own; they were entered in major units of two-decimal currencies
+ALTER TABLE partners
+    ALTER COLUMN refund_approval_threshold TYPE BIGINT
+        USING ROUND(refund_approval_threshold * 100)::BIGINT;
+
+-- The refund trigger compared DECIMAL totals; BIGINT keeps the comparison exact
+CREATE OR REPLACE FUNCTION validate_refund_amount()
+RETURNS TRIGGER AS $$
+DECLARE
+    total_refunded BIGINT;
+    transaction_amount BIGINT;
+BEGIN
+    SELECT amount INTO transaction_amount
+    FROM transactions
+    WHERE id = NEW.transaction_id;
+
+    SELECT COALESCE(SUM(amount), 0) INTO total_refunded
+    FROM refunds
+    WHERE transaction_id = NEW.transaction_id
+    AND status = 'completed'
+    AND id != NEW.id;
+
+    IF (total_refunded + NEW.amount) > transaction_amount THEN
+        RAISE EXCEPTION 'Refund amount exceeds transaction amount';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON COLUMN transactions.amount IS 'Amount in minor units of the currency (e.g. cents)';
+COMMENT ON COLUMN transactions.refunded_amount IS 'Sum of pending and completed refunds in minor units, reserved atomically when a refund is created';
+COMMENT ON COLUMN refunds.amount IS 'Amount in minor units of the currency (e.g. cents)';
+COMMENT ON COLUMN partners.refund_approval_threshold IS 'Refunds above this amount, in minor units, wait for admin approval (0 disables approval)';
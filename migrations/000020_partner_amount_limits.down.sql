@@ -0,0 +1,4 @@
+-- Rollback migration for partner amount limits
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS amount_limits;
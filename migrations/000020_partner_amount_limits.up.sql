@@ -0,0 +1,8 @@
+-- Migration: Partner amount limits
+-- Version: 000020
+-- Description: Per-currency maximum transaction amounts overriding the platform limits
+
+ALTER TABLE partners
+    ADD COLUMN amount_limits JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN partners.amount_limits IS 'Maximum transaction amount per ISO 4217 code in minor units, e.g. {"USD": 10000000}; currencies not listed use the platform limit';
@@ -0,0 +1,4 @@
+-- Rollback migration for payment method details
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS payment_method_details;
@@ -0,0 +1,8 @@
+-- Migration: Payment method details
+-- Version: 000021
+-- Description: Store the card, bank account or e-wallet a transaction was paid with
+
+ALTER TABLE transactions
+    ADD COLUMN payment_method_details JSONB;
+
+COMMENT ON COLUMN transactions.payment_method_details IS 'Non-sensitive details of how the payer paid: {"card": {...}}, {"bank_account": {...}} or {"wallet": {...}}; NULL if unknown. Never holds full card or account numbers';
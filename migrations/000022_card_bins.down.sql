@@ -0,0 +1,4 @@
+-- Rollback migration for card BIN table
+
+DROP INDEX IF EXISTS idx_transactions_card_issuer_country;
+DROP TABLE IF EXISTS card_bins;
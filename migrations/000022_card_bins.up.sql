@@ -0,0 +1,40 @@
+-- Migration: Card BIN table
+-- Version: 000022
+-- Description: Local BIN range table for deriving card brand, issuing country and funding type
+
+CREATE TABLE card_bins (
+    prefix VARCHAR(8) PRIMARY KEY,
+    brand VARCHAR(20) NOT NULL,
+    issuer_country CHAR(2) NOT NULL DEFAULT '',
+    issuer_name VARCHAR(255) NOT NULL DEFAULT '',
+    funding VARCHAR(10) NOT NULL DEFAULT 'unknown',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT check_card_bin_prefix CHECK (prefix ~ '^[0-9]{6,8}$'),
+    CONSTRAINT check_card_bin_brand CHECK (brand IN ('visa', 'mastercard', 'amex', 'discover', 'jcb', 'unionpay', 'diners', 'unknown')),
+    CONSTRAINT check_card_bin_funding CHECK (funding IN ('credit', 'debit', 'prepaid', 'unknown'))
+);
+
+COMMENT ON TABLE card_bins IS 'BIN ranges as 6-8 digit prefixes; the longest matching prefix wins';
+
+-- Sandbox test card ranges; load the production table from the BIN data provider
+INSERT INTO card_bins (prefix, brand, issuer_country, issuer_name, funding) VALUES
+    ('424242', 'visa', 'US', 'Test Bank', 'credit'),
+    ('400005', 'visa', 'US', 'Test Bank', 'debit'),
+    ('555555', 'mastercard', 'US', 'Test Bank', 'credit'),
+    ('520082', 'mastercard', 'US', 'Test Bank', 'debit'),
+    ('510510', 'mastercard', 'US', 'Test Bank', 'prepaid'),
+    ('378282', 'amex', 'US', 'American Express', 'credit'),
+    ('601111', 'discover', 'US', 'Discover', 'credit'),
+    ('356600', 'jcb', 'JP', 'JCB', 'credit'),
+    ('620000', 'unionpay', 'CN', 'UnionPay', 'debit'),
+    ('400000', 'visa', 'US', 'Test Bank', 'credit'),
+    ('40000076', 'visa', 'GB', 'Test Bank', 'credit'),
+    ('40000276', 'visa', 'DE', 'Test Bank', 'credit'),
+    ('40003920', 'visa', 'JP', 'Test Bank', 'credit'),
+    ('40007640', 'visa', 'TH', 'Test Bank', 'debit');
+
+-- Reporting and routing by card issuer
+CREATE INDEX idx_transactions_card_issuer_country
+    ON transactions ((payment_method_details->'card'->>'issuer_country'))
+    WHERE payment_method_details ? 'card';
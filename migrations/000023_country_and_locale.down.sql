@@ -0,0 +1,7 @@
+-- Rollback migration for country and locale
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS locale;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS billing_country;
@@ -0,0 +1,12 @@
+-- Migration: Country and locale
+-- Version: 000023
+-- Description: Billing country on transactions and preferred locale on partners
+
+ALTER TABLE transactions
+    ADD COLUMN billing_country CHAR(2);
+
+ALTER TABLE partners
+    ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT 'en-US';
+
+COMMENT ON COLUMN transactions.billing_country IS 'Payer billing country, ISO 3166-1 alpha-2; NULL if not given';
+COMMENT ON COLUMN partners.locale IS 'Preferred BCP 47 locale for dashboards, statements and emails, e.g. th-TH';
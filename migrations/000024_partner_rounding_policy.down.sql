@@ -0,0 +1,5 @@
+-- Rollback migration for partner rounding policy
+
+ALTER TABLE partners
+    DROP CONSTRAINT IF EXISTS check_rounding_policy,
+    DROP COLUMN IF EXISTS rounding_policy;
@@ -0,0 +1,9 @@
+-- Migration: Partner rounding policy
+-- Version: 000024
+-- Description: How fees, taxes and divided amounts are rounded for each partner
+
+ALTER TABLE partners
+    ADD COLUMN rounding_policy VARCHAR(10) NOT NULL DEFAULT 'half_up',
+    ADD CONSTRAINT check_rounding_policy CHECK (rounding_policy IN ('half_up', 'half_even', 'truncate'));
+
+COMMENT ON COLUMN partners.rounding_policy IS 'Rounding of fractional minor units in fees, taxes and divisions: half_up, half_even or truncate';
@@ -0,0 +1,4 @@
+-- Rollback migration for webhook events
+
+DROP TABLE IF EXISTS webhook_events;
+DROP TYPE IF EXISTS webhook_event_status;
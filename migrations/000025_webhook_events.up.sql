@@ -0,0 +1,34 @@
+-- Migration: Webhook events
+-- Version: 000025
+-- Description: Webhook notifications to partners and their delivery attempts
+
+CREATE TYPE webhook_event_status AS ENUM (
+    'pending',
+    'delivered',
+    'failed'
+);
+
+CREATE TABLE webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+    transaction_id UUID REFERENCES transactions(id),
+
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+
+    status webhook_event_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_response_code INTEGER,
+    last_error TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_webhook_events_partner_id ON webhook_events(partner_id, created_at DESC);
+CREATE INDEX idx_webhook_events_transaction_id ON webhook_events(transaction_id);
+CREATE INDEX idx_webhook_events_due ON webhook_events(next_attempt_at) WHERE status = 'pending';
+
+COMMENT ON TABLE webhook_events IS 'Webhook notifications to partners, kept for retries and delivery history';
+COMMENT ON COLUMN webhook_events.next_attempt_at IS 'When a pending event is next due for delivery';
@@ -0,0 +1,4 @@
+-- Rollback migration for optimistic locking
+
+ALTER TABLE refunds DROP COLUMN IF EXISTS version;
+ALTER TABLE transactions DROP COLUMN IF EXISTS version;
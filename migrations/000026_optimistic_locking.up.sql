@@ -0,0 +1,9 @@
+-- Migration: Optimistic locking
+-- Version: 000026
+-- Description: Version columns so concurrent updates to transactions and refunds cannot overwrite each other
+
+ALTER TABLE transactions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE refunds ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN transactions.version IS 'Incremented on every update; updates must name the version they were loaded at';
+COMMENT ON COLUMN refunds.version IS 'Incremented on every update; updates must name the version they were loaded at';
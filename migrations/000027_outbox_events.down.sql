@@ -0,0 +1,3 @@
+-- Rollback migration for outbox events
+
+DROP TABLE IF EXISTS outbox_events;
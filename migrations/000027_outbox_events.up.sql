@@ -0,0 +1,26 @@
+-- Migration: Outbox events
+-- Version: 000027
+-- Description: Transactional outbox, written with the state change it describes and published by the relay
+
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at, created_at) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
+
+COMMENT ON TABLE outbox_events IS 'Events committed with their state change, published afterwards by the outbox relay';
+COMMENT ON COLUMN outbox_events.next_attempt_at IS 'When an unpublished event is next due for publishing';
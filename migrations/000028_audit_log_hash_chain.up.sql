@@ -0,0 +1,13 @@
+-- Migration: Audit log hash chain
+-- Version: 000028
+-- Description: Each audit entry stores its hash and the hash of the previous entry of its partner, so changed or removed entries can be detected
+
+ALTER TABLE audit_logs ADD COLUMN prev_hash CHAR(64);
+ALTER TABLE audit_logs ADD COLUMN hash CHAR(64);
+
+-- An entry can follow only one other, so concurrent writers cannot fork a chain
+CREATE UNIQUE INDEX idx_audit_logs_prev_hash ON audit_logs(prev_hash);
+CREATE INDEX idx_audit_logs_chain ON audit_logs(partner_id, id);
+
+COMMENT ON COLUMN audit_logs.prev_hash IS 'Hash of the previous entry of the same partner; NULL for entries logged before chaining';
+COMMENT ON COLUMN audit_logs.hash IS 'SHA-256 of the entry and prev_hash, hex encoded';
@@ -0,0 +1,77 @@
+-- Rollback migration for monthly partitions
+
+DROP TRIGGER IF EXISTS claim_idempotency_key_trigger ON transactions;
+DROP FUNCTION IF EXISTS claim_idempotency_key();
+DROP TABLE IF EXISTS transaction_idempotency_keys;
+
+ALTER TABLE refunds RENAME TO refunds_partitioned;
+ALTER TABLE transactions RENAME TO transactions_partitioned;
+
+CREATE TABLE transactions (
+    LIKE transactions_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+);
+
+CREATE TABLE refunds (
+    LIKE refunds_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+);
+
+INSERT INTO transactions SELECT * FROM transactions_partitioned;
+INSERT INTO refunds SELECT * FROM refunds_partitioned;
+
+-- Drops every partition along with its parent
+DROP TABLE refunds_partitioned;
+DROP TABLE transactions_partitioned;
+
+DROP FUNCTION IF EXISTS create_monthly_partition(TEXT, DATE);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_pkey PRIMARY KEY (id),
+    ADD CONSTRAINT transactions_partner_id_fkey FOREIGN KEY (partner_id) REFERENCES partners(id),
+    ADD CONSTRAINT unique_partner_idempotency UNIQUE (partner_id, idempotency_key);
+
+ALTER TABLE refunds
+    ADD CONSTRAINT refunds_pkey PRIMARY KEY (id),
+    ADD CONSTRAINT refunds_transaction_id_fkey FOREIGN KEY (transaction_id) REFERENCES transactions(id);
+
+ALTER TABLE transaction_events
+    ADD CONSTRAINT transaction_events_transaction_id_fkey FOREIGN KEY (transaction_id) REFERENCES transactions(id);
+ALTER TABLE webhook_events
+    ADD CONSTRAINT webhook_events_transaction_id_fkey FOREIGN KEY (transaction_id) REFERENCES transactions(id);
+
+CREATE INDEX idx_transactions_partner_id ON transactions(partner_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_status ON transactions(status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_created_at ON transactions(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_provider_transaction_id ON transactions(provider_transaction_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_customer_email ON transactions(customer_email) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_idempotency ON transactions(partner_id, idempotency_key);
+CREATE INDEX idx_transactions_partner_status_created ON transactions(partner_id, status, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata);
+CREATE INDEX idx_transactions_partner_livemode ON transactions(partner_id, livemode, created_at DESC)
+    WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_card_issuer_country
+    ON transactions ((payment_method_details->'card'->>'issuer_country'))
+    WHERE payment_method_details ? 'card';
+
+CREATE INDEX idx_refunds_transaction_id ON refunds(transaction_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_status ON refunds(status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_created_at ON refunds(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_requires_approval ON refunds(created_at) WHERE status = 'requires_approval' AND deleted_at IS NULL;
+CREATE INDEX idx_refunds_reason_code ON refunds(reason_code, created_at) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_transactions_updated_at
+    BEFORE UPDATE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER log_transaction_changes_trigger
+    AFTER INSERT OR UPDATE ON transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION log_transaction_changes();
+
+CREATE TRIGGER update_refunds_updated_at
+    BEFORE UPDATE ON refunds
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER validate_refund_amount_trigger
+    BEFORE INSERT OR UPDATE ON refunds
+    FOR EACH ROW
+    EXECUTE FUNCTION validate_refund_amount();
@@ -0,0 +1,161 @@
+-- Migration: Monthly partitions for transactions and refunds
+-- Version: 000029
+-- Description: Range-partition transactions and refunds by the month of created_at, so indexes on the hot path stay the size of a month of data
+
+-- Creates the partition of parent_table holding the rows created in the
+-- calendar month (UTC) of for_month, unless it exists. Returns the name of
+-- the new partition, or NULL. The API calls it ahead of time on a schedule.
+CREATE OR REPLACE FUNCTION create_monthly_partition(parent_table TEXT, for_month DATE)
+RETURNS TEXT AS $$
+DECLARE
+    first_day DATE := date_trunc('month', for_month)::DATE;
+    partition_name TEXT := parent_table || '_p' || to_char(first_day, 'YYYY_MM');
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN NULL;
+    END IF;
+
+    EXECUTE format(
+        'CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        partition_name,
+        parent_table,
+        first_day::TIMESTAMP AT TIME ZONE 'UTC',
+        (first_day + INTERVAL '1 month')::TIMESTAMP AT TIME ZONE 'UTC'
+    );
+    RETURN partition_name;
+EXCEPTION
+    -- Another instance created it first
+    WHEN duplicate_table THEN
+        RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE refunds RENAME TO refunds_unpartitioned;
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+
+-- Unique constraints of a partitioned table must include created_at, so
+-- nothing can reference transactions(id) or refunds(id) anymore. Refunds and
+-- events are only written for transactions the API has just loaded.
+ALTER TABLE refunds_unpartitioned DROP CONSTRAINT IF EXISTS refunds_transaction_id_fkey;
+ALTER TABLE transaction_events DROP CONSTRAINT IF EXISTS transaction_events_transaction_id_fkey;
+ALTER TABLE webhook_events DROP CONSTRAINT IF EXISTS webhook_events_transaction_id_fkey;
+
+CREATE TABLE transactions (
+    LIKE transactions_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+) PARTITION BY RANGE (created_at);
+
+CREATE TABLE refunds (
+    LIKE refunds_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING COMMENTS
+) PARTITION BY RANGE (created_at);
+
+-- Rows outside every monthly partition land here; the partition job keeps it
+-- empty by creating partitions months ahead
+CREATE TABLE transactions_default PARTITION OF transactions DEFAULT;
+CREATE TABLE refunds_default PARTITION OF refunds DEFAULT;
+
+-- One partition per month from the oldest row to three months ahead
+DO $$
+DECLARE
+    first_month DATE;
+    partition_month DATE;
+BEGIN
+    SELECT date_trunc('month', LEAST(
+        COALESCE((SELECT MIN(created_at) FROM transactions_unpartitioned), NOW()),
+        COALESCE((SELECT MIN(created_at) FROM refunds_unpartitioned), NOW())
+    ) AT TIME ZONE 'UTC')::DATE INTO first_month;
+
+    partition_month := first_month;
+    WHILE partition_month <= (date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '3 months')::DATE LOOP
+        PERFORM create_monthly_partition('transactions', partition_month);
+        PERFORM create_monthly_partition('refunds', partition_month);
+        partition_month := (partition_month + INTERVAL '1 month')::DATE;
+    END LOOP;
+END;
+$$;
+
+-- Copied before the triggers exist, so no events are logged and no refund is
+-- validated twice
+INSERT INTO transactions SELECT * FROM transactions_unpartitioned;
+INSERT INTO refunds SELECT * FROM refunds_unpartitioned;
+
+DROP TABLE refunds_unpartitioned;
+DROP TABLE transactions_unpartitioned;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_pkey PRIMARY KEY (id, created_at),
+    ADD CONSTRAINT transactions_partner_id_fkey FOREIGN KEY (partner_id) REFERENCES partners(id);
+
+ALTER TABLE refunds
+    ADD CONSTRAINT refunds_pkey PRIMARY KEY (id, created_at);
+
+CREATE INDEX idx_transactions_partner_id ON transactions(partner_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_status ON transactions(status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_created_at ON transactions(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_provider_transaction_id ON transactions(provider_transaction_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_customer_email ON transactions(customer_email) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_idempotency ON transactions(partner_id, idempotency_key);
+CREATE INDEX idx_transactions_partner_status_created ON transactions(partner_id, status, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata);
+CREATE INDEX idx_transactions_partner_livemode ON transactions(partner_id, livemode, created_at DESC)
+    WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_card_issuer_country
+    ON transactions ((payment_method_details->'card'->>'issuer_country'))
+    WHERE payment_method_details ? 'card';
+
+CREATE INDEX idx_refunds_transaction_id ON refunds(transaction_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_status ON refunds(status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_created_at ON refunds(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_requires_approval ON refunds(created_at) WHERE status = 'requires_approval' AND deleted_at IS NULL;
+CREATE INDEX idx_refunds_reason_code ON refunds(reason_code, created_at) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_transactions_updated_at
+    BEFORE UPDATE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER log_transaction_changes_trigger
+    AFTER INSERT OR UPDATE ON transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION log_transaction_changes();
+
+CREATE TRIGGER update_refunds_updated_at
+    BEFORE UPDATE ON refunds
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER validate_refund_amount_trigger
+    BEFORE INSERT OR UPDATE ON refunds
+    FOR EACH ROW
+    EXECUTE FUNCTION validate_refund_amount();
+
+-- Idempotency keys are unique across all months, which a partitioned table
+-- cannot enforce. Each insert claims its key here instead; a second claim
+-- fails with the same unique violation as before.
+CREATE TABLE transaction_idempotency_keys (
+    partner_id UUID NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    transaction_id UUID NOT NULL,
+    transaction_created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT unique_partner_idempotency PRIMARY KEY (partner_id, idempotency_key)
+);
+
+INSERT INTO transaction_idempotency_keys (partner_id, idempotency_key, transaction_id, transaction_created_at)
+SELECT partner_id, idempotency_key, id, created_at FROM transactions;
+
+CREATE OR REPLACE FUNCTION claim_idempotency_key()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO transaction_idempotency_keys (partner_id, idempotency_key, transaction_id, transaction_created_at)
+    VALUES (NEW.partner_id, NEW.idempotency_key, NEW.id, NEW.created_at);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER claim_idempotency_key_trigger
+    BEFORE INSERT ON transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION claim_idempotency_key();
+
+COMMENT ON TABLE transactions IS 'Payments, partitioned by month of created_at';
+COMMENT ON TABLE refunds IS 'Refunds, partitioned by month of created_at';
+COMMENT ON TABLE transaction_idempotency_keys IS 'Idempotency key of every transaction, unique per partner across partitions';
+COMMENT ON COLUMN transaction_idempotency_keys.transaction_created_at IS 'Partition key of the transaction, so lookups by key read a single partition';
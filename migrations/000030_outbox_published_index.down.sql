@@ -0,0 +1,3 @@
+-- Rollback migration for the index of published outbox events
+
+DROP INDEX IF EXISTS idx_outbox_events_published;
@@ -0,0 +1,5 @@
+-- Migration: Index of published outbox events
+-- Version: 000030
+-- Description: Lets the archiving job find the events published before its cutoff without scanning the outbox
+
+CREATE INDEX idx_outbox_events_published ON outbox_events(published_at) WHERE published_at IS NOT NULL;
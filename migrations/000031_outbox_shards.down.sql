@@ -0,0 +1,3 @@
+-- Rollback migration for outbox shards
+
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS shard;
@@ -0,0 +1,11 @@
+-- Migration: Outbox shards
+-- Version: 000031
+-- Description: Spreads outbox events over 256 shards by partner, so several relays can split the outbox between them
+
+ALTER TABLE outbox_events ADD COLUMN shard SMALLINT NOT NULL DEFAULT 0;
+
+-- The shard is the first byte of the partner ID. Published events are never
+-- claimed again, so only pending ones need it.
+UPDATE outbox_events SET shard = get_byte(uuid_send(partner_id), 0) WHERE published_at IS NULL;
+
+COMMENT ON COLUMN outbox_events.shard IS 'Shard of the partner (first byte of partner_id); relays publish the shards assigned to them';
@@ -0,0 +1,24 @@
+-- Migration: Partner usage
+-- Version: 000032
+-- Description: Daily API calls, errors and payment volume of each partner, metered for billing and capacity planning
+
+CREATE TABLE partner_usage (
+    partner_id UUID NOT NULL,
+    day DATE NOT NULL,
+    -- API calls are metered with an empty currency, payments per currency
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+
+    api_calls BIGINT NOT NULL DEFAULT 0,
+    client_errors BIGINT NOT NULL DEFAULT 0,
+    server_errors BIGINT NOT NULL DEFAULT 0,
+    payments BIGINT NOT NULL DEFAULT 0,
+    failed_payments BIGINT NOT NULL DEFAULT 0,
+    volume BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (partner_id, day, currency)
+);
+
+CREATE INDEX idx_partner_usage_day ON partner_usage(day);
+
+COMMENT ON TABLE partner_usage IS 'Usage counters per partner and UTC day, added to by the API instances as they flush their meters';
+COMMENT ON COLUMN partner_usage.volume IS 'Amount of the successful live payments, in minor units of the currency';
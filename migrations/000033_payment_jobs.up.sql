@@ -0,0 +1,23 @@
+-- Migration: Payment jobs
+-- Version: 000033
+-- Description: Queue of payments waiting to be processed by a worker, when the API processes payments asynchronously
+
+CREATE TABLE payment_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+    transaction_id UUID NOT NULL,
+
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_payment_jobs_due ON payment_jobs(next_attempt_at, created_at) WHERE completed_at IS NULL;
+CREATE INDEX idx_payment_jobs_transaction ON payment_jobs(transaction_id);
+
+COMMENT ON TABLE payment_jobs IS 'Payments queued by the API and processed by cmd/worker';
+COMMENT ON COLUMN payment_jobs.next_attempt_at IS 'When an uncompleted job is next due; claimed jobs are pushed past their claim timeout';
+COMMENT ON COLUMN payment_jobs.last_error IS 'Why the last attempt failed, or why a completed job made no payment';
@@ -0,0 +1,4 @@
+-- Rollback migration for partner event destination
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS event_destination;
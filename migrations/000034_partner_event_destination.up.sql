@@ -0,0 +1,8 @@
+-- Migration: Partner event destination
+-- Version: 000034
+-- Description: Deliver a partner's events to an SNS topic or SQS queue in its own AWS account instead of its webhook URL
+
+ALTER TABLE partners
+    ADD COLUMN event_destination JSONB;
+
+COMMENT ON COLUMN partners.event_destination IS 'SNS topic or SQS queue and the IAM role assumed to deliver events to it; NULL delivers to webhook_url';
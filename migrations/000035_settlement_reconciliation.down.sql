@@ -0,0 +1,4 @@
+-- Rollback migration for settlement reconciliation
+
+DROP TABLE IF EXISTS disputes;
+DROP TABLE IF EXISTS settlement_entries;
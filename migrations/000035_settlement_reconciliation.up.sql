@@ -0,0 +1,57 @@
+-- Migration: Settlement reconciliation
+-- Version: 000035
+-- Description: Entries of providers' settlement and chargeback feeds reconciled against transactions, and the disputes chargebacks open
+
+CREATE TABLE settlement_entries (
+    id UUID PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL,
+    reference VARCHAR(255) NOT NULL,
+    entry_type VARCHAR(30) NOT NULL,
+    provider_transaction_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    fee BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    source VARCHAR(500) NOT NULL DEFAULT '',
+
+    transaction_id UUID,
+    partner_id UUID REFERENCES partners(id),
+    discrepancy VARCHAR(30),
+    discrepancy_detail TEXT NOT NULL DEFAULT '',
+    reconciled_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_settlement_entries_reference UNIQUE (provider, reference),
+    CONSTRAINT check_settlement_entry_type CHECK (entry_type IN ('settlement', 'chargeback', 'chargeback_reversal'))
+);
+
+CREATE INDEX idx_settlement_entries_discrepancies ON settlement_entries(reconciled_at DESC) WHERE discrepancy IS NOT NULL;
+CREATE INDEX idx_settlement_entries_transaction ON settlement_entries(transaction_id);
+
+COMMENT ON TABLE settlement_entries IS 'Lines of providers'' settlement and chargeback feeds, each reconciled once';
+COMMENT ON COLUMN settlement_entries.reference IS 'Provider''s ID of the entry; a feed delivered twice is reconciled once';
+COMMENT ON COLUMN settlement_entries.discrepancy IS 'Why the entry disagrees with the stored transaction; NULL when it agrees';
+
+CREATE TABLE disputes (
+    id UUID PRIMARY KEY,
+    partner_id UUID NOT NULL REFERENCES partners(id),
+    transaction_id UUID NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    provider_reference VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reversed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT check_dispute_status CHECK (status IN ('open', 'reversed'))
+);
+
+CREATE INDEX idx_disputes_partner ON disputes(partner_id, created_at DESC);
+CREATE INDEX idx_disputes_open ON disputes(transaction_id, created_at) WHERE status = 'open';
+
+COMMENT ON TABLE disputes IS 'Cardholder disputes, opened by chargebacks in providers'' feeds';
+COMMENT ON COLUMN disputes.provider_reference IS 'Reference of the chargeback entry that opened the dispute';
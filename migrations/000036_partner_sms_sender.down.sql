@@ -0,0 +1,4 @@
+-- Rollback migration for partner SMS sender
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS sms_sender;
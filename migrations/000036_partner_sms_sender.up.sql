@@ -0,0 +1,8 @@
+-- Migration: Partner SMS sender
+-- Version: 000036
+-- Description: Send a partner's text messages to customers from its own number, sender name or Twilio Messaging Service
+
+ALTER TABLE partners
+    ADD COLUMN sms_sender VARCHAR(64) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN partners.sms_sender IS 'E.164 number, alphanumeric sender name or Messaging Service SID text messages come from; empty uses the platform sender';
@@ -0,0 +1,3 @@
+-- Rollback migration for export jobs
+
+DROP TABLE IF EXISTS export_jobs;
@@ -0,0 +1,30 @@
+-- Migration: Export jobs
+-- Version: 000038
+-- Description: Exports of transactions or refunds requested through the API and produced by a worker
+
+CREATE TABLE export_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+    livemode BOOLEAN NOT NULL,
+
+    resource VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+
+    object_key TEXT,
+    row_count BIGINT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_export_jobs_due ON export_jobs(next_attempt_at, created_at) WHERE status = 'pending';
+
+COMMENT ON TABLE export_jobs IS 'Exports queued by POST /exports and produced by the API''s export worker';
+COMMENT ON COLUMN export_jobs.next_attempt_at IS 'When a pending job is next due; claimed jobs are pushed past their claim timeout';
+COMMENT ON COLUMN export_jobs.object_key IS 'Key of the finished file in the export object store';
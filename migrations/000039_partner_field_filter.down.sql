@@ -0,0 +1,4 @@
+-- Rollback migration for partner field filter
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS field_filter;
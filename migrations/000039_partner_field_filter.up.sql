@@ -0,0 +1,8 @@
+-- Migration: Partner field filter
+-- Version: 000039
+-- Description: Hide fields from a partner's API responses, per API key scope, and from its webhooks
+
+ALTER TABLE partners
+    ADD COLUMN field_filter JSONB;
+
+COMMENT ON COLUMN partners.field_filter IS 'Fields hidden from API responses per API key scope and from webhook payloads; NULL hides nothing';
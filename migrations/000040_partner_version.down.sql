@@ -0,0 +1,3 @@
+-- Rollback migration for partner version
+
+ALTER TABLE partners DROP COLUMN IF EXISTS version;
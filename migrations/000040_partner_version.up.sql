@@ -0,0 +1,7 @@
+-- Migration: Partner version
+-- Version: 000040
+-- Description: Version column so concurrent admin changes to a partner cannot overwrite each other, exposed as the ETag of its admin resources
+
+ALTER TABLE partners ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN partners.version IS 'Incremented on every update; updates must name the version they were loaded at';
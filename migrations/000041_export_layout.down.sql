@@ -0,0 +1,5 @@
+-- Rollback migration for export layout
+
+ALTER TABLE export_jobs
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS csv_columns;
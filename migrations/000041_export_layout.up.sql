@@ -0,0 +1,10 @@
+-- Migration: Export layout
+-- Version: 000041
+-- Description: Columns and time zone chosen for an export's file
+
+ALTER TABLE export_jobs
+    ADD COLUMN csv_columns TEXT NOT NULL DEFAULT '',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN export_jobs.csv_columns IS 'Comma-separated CSV columns, in order; empty writes every column';
+COMMENT ON COLUMN export_jobs.timezone IS 'IANA name of the zone the file''s times are written in; empty is UTC';
@@ -0,0 +1,3 @@
+-- Rollback migration for partner receipt logo
+
+ALTER TABLE partners DROP COLUMN IF EXISTS receipt_logo_key;
@@ -0,0 +1,8 @@
+-- Migration: Partner receipt logo
+-- Version: 000042
+-- Description: Logo printed on the PDF receipts of a partner's transactions
+
+ALTER TABLE partners
+    ADD COLUMN receipt_logo_key VARCHAR(255) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN partners.receipt_logo_key IS 'Key of the receipt logo in the object store; empty prints the partner name alone';
@@ -0,0 +1,5 @@
+-- Rollback migration for partner statements
+
+DROP TABLE IF EXISTS statements;
+
+ALTER TABLE partners DROP COLUMN IF EXISTS pricing;
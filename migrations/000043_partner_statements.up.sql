@@ -0,0 +1,31 @@
+-- Migration: Partner statements
+-- Version: 000043
+-- Description: Monthly statements of each partner's volume, refunds, chargebacks and fees, and the pricing they are charged at
+
+ALTER TABLE partners
+    ADD COLUMN pricing JSONB;
+
+COMMENT ON COLUMN partners.pricing IS 'Fee tiers and chargeback fee the partner''s statements charge; NULL charges the platform''s standard pricing';
+
+CREATE TABLE statements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+    period CHAR(7) NOT NULL,
+
+    pricing JSONB NOT NULL,
+    rounding_policy VARCHAR(10) NOT NULL,
+    currencies JSONB NOT NULL DEFAULT '[]',
+
+    document_key TEXT NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_statements_partner_period UNIQUE (partner_id, period)
+);
+
+CREATE INDEX idx_statements_period ON statements(period, partner_id);
+
+COMMENT ON TABLE statements IS 'Monthly partner statements, issued once the month is over and fed to invoicing';
+COMMENT ON COLUMN statements.period IS 'Calendar month in UTC the statement covers, as YYYY-MM';
+COMMENT ON COLUMN statements.pricing IS 'Pricing the statement charged, as it was when issued';
+COMMENT ON COLUMN statements.document_key IS 'Key of the CSV document in the export object store';
@@ -0,0 +1,26 @@
+-- Migration: Payment analytics
+-- Version: 000044
+-- Description: Daily rollups of each partner's payments and refunds by currency, provider and payment method, read by the analytics endpoints
+
+CREATE TABLE payment_analytics_daily (
+    partner_id UUID NOT NULL,
+    day DATE NOT NULL,
+    livemode BOOLEAN NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    payment_method VARCHAR(50) NOT NULL,
+
+    succeeded_payments BIGINT NOT NULL DEFAULT 0,
+    failed_payments BIGINT NOT NULL DEFAULT 0,
+    volume BIGINT NOT NULL DEFAULT 0,
+    refunds BIGINT NOT NULL DEFAULT 0,
+    refunded_volume BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (partner_id, day, livemode, currency, provider, payment_method)
+);
+
+CREATE INDEX idx_payment_analytics_daily_day ON payment_analytics_daily(day);
+
+COMMENT ON TABLE payment_analytics_daily IS 'Payments and refunds per partner and UTC day, rebuilt by the analytics rollup job';
+COMMENT ON COLUMN payment_analytics_daily.volume IS 'Amount of the succeeded payments made on the day, in minor units of the currency';
+COMMENT ON COLUMN payment_analytics_daily.refunds IS 'Completed refunds made on the day, of payments with the currency, provider and payment method';
@@ -0,0 +1,40 @@
+-- Migration: Report schedules
+-- Version: 000045
+-- Description: Partners' schedules delivering transaction, refund and statement reports by email, SFTP or S3
+
+CREATE TABLE report_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    partner_id UUID NOT NULL REFERENCES partners(id),
+    livemode BOOLEAN NOT NULL,
+    name VARCHAR(100) NOT NULL,
+
+    resource VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    csv_columns TEXT NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    frequency VARCHAR(10) NOT NULL,
+    run_hour SMALLINT NOT NULL,
+
+    destination JSONB NOT NULL,
+    destination_secret TEXT NOT NULL DEFAULT '',
+
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_status VARCHAR(20) NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_schedules_partner ON report_schedules(partner_id, livemode, created_at);
+CREATE INDEX idx_report_schedules_due ON report_schedules(next_run_at);
+
+COMMENT ON TABLE report_schedules IS 'Reports delivered to partners on a schedule by the API''s report worker';
+COMMENT ON COLUMN report_schedules.run_hour IS 'Hour of the day, in timezone, runs are due at';
+COMMENT ON COLUMN report_schedules.destination IS 'Email recipients, SFTP server or S3 bucket, without credentials';
+COMMENT ON COLUMN report_schedules.destination_secret IS 'Encrypted SFTP password or private key';
+COMMENT ON COLUMN report_schedules.next_run_at IS 'When the next run, or retry, is due; claimed schedules are pushed past their claim timeout';
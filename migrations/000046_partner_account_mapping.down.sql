@@ -0,0 +1,3 @@
+-- Rollback migration for partner account mapping
+
+ALTER TABLE partners DROP COLUMN IF EXISTS account_mapping;
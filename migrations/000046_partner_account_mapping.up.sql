@@ -0,0 +1,8 @@
+-- Migration: Partner account mapping
+-- Version: 000046
+-- Description: Accounts of each partner's accounting system that QuickBooks and Xero exports post to
+
+ALTER TABLE partners
+    ADD COLUMN account_mapping JSONB;
+
+COMMENT ON COLUMN partners.account_mapping IS 'Accounts QuickBooks and Xero exports post to; NULL posts to the default accounts';
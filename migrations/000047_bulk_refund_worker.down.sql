@@ -0,0 +1,9 @@
+-- Rollback migration for the bulk refund worker
+
+DROP INDEX IF EXISTS idx_bulk_refund_jobs_due;
+
+ALTER TABLE bulk_refund_jobs
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS livemode;
@@ -0,0 +1,14 @@
+-- Migration: Bulk refund worker
+-- Version: 000047
+-- Description: Bulk refund jobs run by a worker that claims them and resumes the unfinished
+
+ALTER TABLE bulk_refund_jobs
+    ADD COLUMN livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN ip_address VARCHAR(45),
+    ADD COLUMN user_agent TEXT,
+    ADD COLUMN next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE INDEX idx_bulk_refund_jobs_due ON bulk_refund_jobs(next_attempt_at, created_at) WHERE status <> 'completed';
+
+COMMENT ON COLUMN bulk_refund_jobs.livemode IS 'Mode of the request; jobs queued before this column existed were live';
+COMMENT ON COLUMN bulk_refund_jobs.next_attempt_at IS 'When an unfinished job is next due; claimed jobs are pushed past their claim timeout';
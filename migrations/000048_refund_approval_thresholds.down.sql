@@ -0,0 +1,18 @@
+-- Rollback migration for refund approval thresholds per currency
+
+ALTER TABLE partners
+    ADD COLUMN refund_approval_threshold BIGINT NOT NULL DEFAULT 0
+        CHECK (refund_approval_threshold >= 0);
+
+-- A single threshold cannot hold several; the lowest keeps every refund
+-- that needed approval held
+UPDATE partners
+SET refund_approval_threshold = (
+    SELECT MIN(value::BIGINT) FROM jsonb_each_text(refund_approval_thresholds)
+)
+WHERE refund_approval_thresholds <> '{}';
+
+ALTER TABLE partners
+    DROP COLUMN IF EXISTS refund_approval_thresholds;
+
+COMMENT ON COLUMN partners.refund_approval_threshold IS 'Refunds above this amount, in minor units, wait for admin approval (0 disables approval)';
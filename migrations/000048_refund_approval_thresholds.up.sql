@@ -0,0 +1,24 @@
+-- Migration: Refund approval thresholds per currency
+-- Version: 000048
+-- Description: Refund approval thresholds keyed by currency, replacing the single threshold in minor units
+
+ALTER TABLE partners
+    ADD COLUMN refund_approval_thresholds JSONB NOT NULL DEFAULT '{}';
+
+-- The single threshold was compared in minor units whatever the currency;
+-- it is kept as is for each currency the partner allows or has charged in
+UPDATE partners p
+SET refund_approval_thresholds = (
+    SELECT COALESCE(jsonb_object_agg(c.code, p.refund_approval_threshold), '{}')
+    FROM (
+        SELECT unnest(p.allowed_currencies) AS code
+        UNION
+        SELECT DISTINCT t.currency FROM transactions t WHERE t.partner_id = p.id
+    ) c
+)
+WHERE p.refund_approval_threshold > 0;
+
+ALTER TABLE partners
+    DROP COLUMN refund_approval_threshold;
+
+COMMENT ON COLUMN partners.refund_approval_thresholds IS 'Refunds above the amount for their currency, in minor units, wait for admin approval, e.g. {"USD": 50000}; currencies not listed need no approval';
@@ -0,0 +1,28 @@
+// Package migrations embeds the versioned SQL migrations so the API and the
+// migrate command apply exactly the schema they were built with.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS holds every NNNNNN_name.up.sql and NNNNNN_name.down.sql file
+//
+//go:embed *.up.sql *.down.sql
+var FS embed.FS
+
+//go:embed mysql/*.up.sql mysql/*.down.sql
+var mysqlFiles embed.FS
+
+// MySQLFS holds the MySQL schema. It starts from the PostgreSQL schema as of
+// 000027 in a single migration; later changes get a file in both directories,
+// except ones MySQL does not need, such as the partitioning of 000029 or the
+// outbox index of 000030, which idx_outbox_events_due already covers there.
+var MySQLFS, _ = fs.Sub(mysqlFiles, "mysql")
+
+//go:embed sqlite/*.up.sql sqlite/*.down.sql
+var sqliteFiles embed.FS
+
+// SQLiteFS holds the SQLite schema, which starts at the same point as MySQLFS
+var SQLiteFS, _ = fs.Sub(sqliteFiles, "sqlite")
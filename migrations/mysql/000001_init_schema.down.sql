@@ -0,0 +1,20 @@
+-- Rollback migration for initial schema (MySQL)
+
+-- Drop tables in reverse order of their foreign keys
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS card_bins;
+DROP TABLE IF EXISTS admin_sessions;
+DROP TABLE IF EXISTS admin_users;
+DROP TABLE IF EXISTS provider_credentials;
+DROP TABLE IF EXISTS user_sessions;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS bulk_refund_jobs;
+DROP TABLE IF EXISTS currencies;
+DROP TABLE IF EXISTS payment_methods;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS transaction_events;
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS partners;
@@ -0,0 +1,383 @@
+-- Migration: Initial schema (MySQL)
+-- Version: 000001
+-- Description: The PostgreSQL schema as of migration 000027, for MySQL 8.0.16 or later
+--
+-- Differences from PostgreSQL:
+--   * UUIDs are CHAR(36), arrays (allowed_currencies, scopes) JSON arrays
+--   * Enum types are VARCHAR columns with CHECK constraints
+--   * Partial indexes are plain indexes; the unique email of active users
+--     uses a generated column instead
+--   * There are no triggers: the application sets updated_at, the
+--     refunded_amount CHECK guards refunds, and transaction_events is not
+--     written (managed MySQL often refuses triggers without SUPER)
+
+CREATE TABLE partners (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    api_key_hash VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    api_key_prefix VARCHAR(10) COLLATE utf8mb4_bin NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    rate_limit_per_minute INT NOT NULL DEFAULT 100,
+    webhook_url VARCHAR(512),
+    webhook_secret TEXT COMMENT 'Webhook signing secret, envelope-encrypted (enc:v1:<key id>:...)',
+    refund_approval_threshold BIGINT NOT NULL DEFAULT 0
+        COMMENT 'Refunds above this amount, in minor units, wait for admin approval (0 disables approval)',
+    refund_window_days INT NOT NULL DEFAULT 0
+        COMMENT 'Days after payment a refund is allowed (0 uses the platform default)',
+    features JSON NOT NULL DEFAULT (JSON_OBJECT()),
+    allowed_currencies JSON NOT NULL DEFAULT (JSON_ARRAY())
+        COMMENT 'ISO 4217 codes the partner may charge in; empty allows every supported currency',
+    amount_limits JSON NOT NULL DEFAULT (JSON_OBJECT()),
+    locale VARCHAR(35) NOT NULL DEFAULT 'en-US',
+    rounding_policy VARCHAR(10) NOT NULL DEFAULT 'half_up',
+    metadata JSON,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at DATETIME(6),
+    anonymize_after DATETIME(6),
+    anonymized_at DATETIME(6),
+    CONSTRAINT unique_partners_email UNIQUE (email),
+    CONSTRAINT unique_partners_api_key_hash UNIQUE (api_key_hash),
+    CONSTRAINT check_refund_approval_threshold CHECK (refund_approval_threshold >= 0),
+    CONSTRAINT check_refund_window_days CHECK (refund_window_days >= 0 AND refund_window_days <= 3650),
+    CONSTRAINT check_rounding_policy CHECK (rounding_policy IN ('half_up', 'half_even', 'truncate')),
+    INDEX idx_partners_api_key_prefix (api_key_prefix),
+    INDEX idx_partners_anonymize_after (anonymize_after)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Stores partner/merchant information who use the payment orchestration API';
+
+CREATE TABLE transactions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    idempotency_key VARCHAR(255) COLLATE utf8mb4_bin NOT NULL
+        COMMENT 'Unique key per partner to prevent duplicate transactions',
+    amount BIGINT NOT NULL COMMENT 'Amount in minor units of the currency (e.g. cents)',
+    refunded_amount BIGINT NOT NULL DEFAULT 0
+        COMMENT 'Sum of pending and completed refunds in minor units, reserved atomically when a refund is created',
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    payment_method VARCHAR(50) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    provider_transaction_id VARCHAR(255),
+    provider_customer_id VARCHAR(255),
+    provider_credential_id CHAR(36),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    customer_email VARCHAR(255) NOT NULL,
+    customer_name VARCHAR(255),
+    customer_phone VARCHAR(50),
+    description TEXT,
+    metadata JSON,
+    payment_method_details JSON,
+    billing_country CHAR(2),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    request_id CHAR(36),
+    error_code VARCHAR(50),
+    error_message TEXT,
+    retry_count INT NOT NULL DEFAULT 0,
+    version INT NOT NULL DEFAULT 1
+        COMMENT 'Incremented on every update; updates must name the version they were loaded at',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    processed_at DATETIME(6),
+    failed_at DATETIME(6),
+    deleted_at DATETIME(6),
+    CONSTRAINT fk_transactions_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT unique_partner_idempotency UNIQUE (partner_id, idempotency_key),
+    CONSTRAINT check_amount_positive CHECK (amount >= 1),
+    CONSTRAINT chk_transactions_refunded_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount),
+    CONSTRAINT check_transaction_status CHECK (status IN (
+        'pending', 'processing', 'completed', 'failed', 'cancelled', 'refunded', 'partially_refunded'
+    )),
+    CONSTRAINT check_transaction_provider CHECK (provider IN ('stripe', 'paypal', 'adyen', 'manual')),
+    INDEX idx_transactions_partner_livemode (partner_id, livemode, created_at DESC),
+    INDEX idx_transactions_partner_status_created (partner_id, status, created_at DESC),
+    INDEX idx_transactions_created_at (created_at DESC),
+    INDEX idx_transactions_provider_transaction_id (provider_transaction_id),
+    INDEX idx_transactions_customer_email (customer_email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Core table storing all payment transactions';
+
+CREATE TABLE refunds (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    transaction_id CHAR(36) NOT NULL,
+    amount BIGINT NOT NULL COMMENT 'Amount in minor units of the currency (e.g. cents)',
+    currency VARCHAR(3) NOT NULL,
+    reason_code VARCHAR(30) NOT NULL DEFAULT 'other',
+    reason VARCHAR(255) COMMENT 'Optional free-text note (required when reason_code is other)',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    provider_refund_id VARCHAR(255),
+    error_code VARCHAR(50),
+    error_message TEXT,
+    approved_by VARCHAR(255),
+    approved_at DATETIME(6),
+    version INT NOT NULL DEFAULT 1,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    processed_at DATETIME(6),
+    cancelled_at DATETIME(6),
+    deleted_at DATETIME(6),
+    CONSTRAINT fk_refunds_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    CONSTRAINT check_refund_amount_positive CHECK (amount > 0),
+    CONSTRAINT check_refund_status CHECK (status IN (
+        'pending', 'requires_approval', 'processing', 'completed', 'failed', 'cancelled'
+    )),
+    CONSTRAINT check_refund_reason_code CHECK (reason_code IN (
+        'requested_by_customer', 'duplicate', 'fraudulent', 'other'
+    )),
+    INDEX idx_refunds_transaction_id (transaction_id),
+    INDEX idx_refunds_status_created (status, created_at),
+    INDEX idx_refunds_reason_code (reason_code, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Stores refund records linked to original transactions';
+
+CREATE TABLE transaction_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    transaction_id CHAR(36) NOT NULL,
+    event_type VARCHAR(30) NOT NULL,
+    status VARCHAR(50),
+    provider_response JSON,
+    error_message TEXT,
+    created_by VARCHAR(100),
+    ip_address VARCHAR(45),
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_transaction_events_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    INDEX idx_transaction_events_txn_created (transaction_id, created_at DESC)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Audit trail for transaction lifecycle events';
+
+CREATE TABLE audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    partner_id CHAR(36),
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id CHAR(36),
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent TEXT,
+    request_id CHAR(36),
+    changes JSON,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_audit_logs_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    INDEX idx_audit_logs_partner_id (partner_id),
+    INDEX idx_audit_logs_created_at (created_at DESC),
+    INDEX idx_audit_logs_resource (resource_type, resource_id),
+    INDEX idx_audit_logs_action (action)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Security and compliance audit logs for all operations';
+
+CREATE TABLE payment_methods (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO payment_methods (code, name) VALUES
+    ('card', 'Credit/Debit Card'),
+    ('bank_transfer', 'Bank Transfer'),
+    ('e_wallet', 'E-Wallet'),
+    ('crypto', 'Cryptocurrency');
+
+CREATE TABLE currencies (
+    code VARCHAR(3) NOT NULL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    symbol VARCHAR(10),
+    decimal_places SMALLINT NOT NULL DEFAULT 2,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO currencies (code, name, symbol, decimal_places) VALUES
+    ('USD', 'US Dollar', '$', 2),
+    ('EUR', 'Euro', '€', 2),
+    ('GBP', 'British Pound', '£', 2),
+    ('JPY', 'Japanese Yen', '¥', 0),
+    ('THB', 'Thai Baht', '฿', 2);
+
+CREATE TABLE bulk_refund_jobs (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    transaction_ids JSON NOT NULL,
+    reason_code VARCHAR(30) NOT NULL DEFAULT 'other',
+    reason VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    results JSON NOT NULL DEFAULT (JSON_ARRAY()),
+    succeeded INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    completed_at DATETIME(6),
+    CONSTRAINT fk_bulk_refund_jobs_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT check_bulk_refund_job_status CHECK (status IN ('pending', 'running', 'completed')),
+    CONSTRAINT check_bulk_refund_job_counts CHECK (succeeded >= 0 AND failed >= 0),
+    INDEX idx_bulk_refund_jobs_partner_id (partner_id, created_at DESC)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Asynchronous batches of full refunds with per-transaction results';
+
+CREATE TABLE api_keys (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    key_hash VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+    key_prefix VARCHAR(10) COLLATE utf8mb4_bin NOT NULL,
+    scopes JSON NOT NULL COMMENT 'read_only, payments, refunds, admin (admin grants all)',
+    auth_method VARCHAR(10) NOT NULL DEFAULT 'bearer',
+    signing_secret TEXT COMMENT 'Shared HMAC secret, envelope-encrypted (enc:v1:<key id>:...); only set for hmac keys',
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    last_used_at DATETIME(6),
+    last_used_ip VARCHAR(45),
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    revoked_at DATETIME(6),
+    CONSTRAINT fk_api_keys_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT unique_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT unique_api_keys_key_prefix UNIQUE (key_prefix),
+    CONSTRAINT check_api_key_scopes CHECK (JSON_LENGTH(scopes) > 0),
+    CONSTRAINT check_api_key_auth_method CHECK (auth_method IN ('bearer', 'hmac')),
+    CONSTRAINT api_keys_signing_secret_check CHECK ((auth_method = 'hmac') = (signing_secret IS NOT NULL)),
+    INDEX idx_api_keys_partner_id (partner_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Partner API keys; the plaintext key is never stored';
+
+CREATE TABLE users (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    last_login_at DATETIME(6),
+    deleted_at DATETIME(6),
+    -- NULL once removed, so a removed member's email can be invited again
+    active_email VARCHAR(255) AS (IF(deleted_at IS NULL, LOWER(email), NULL)) STORED,
+    CONSTRAINT fk_users_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT unique_users_active_email UNIQUE (active_email),
+    CONSTRAINT check_user_role CHECK (role IN ('owner', 'developer', 'finance', 'read_only')),
+    INDEX idx_users_partner_id (partner_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Partner team members; role is owner, developer, finance or read_only';
+
+CREATE TABLE user_sessions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    token_hash VARCHAR(64) COLLATE utf8mb4_bin NOT NULL UNIQUE
+        COMMENT 'SHA-256 of the session token; the token itself is never stored',
+    expires_at DATETIME(6) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    revoked_at DATETIME(6),
+    CONSTRAINT fk_user_sessions_user FOREIGN KEY (user_id) REFERENCES users(id),
+    INDEX idx_user_sessions_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE provider_credentials (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    account_id VARCHAR(255) NOT NULL DEFAULT '',
+    secret_key TEXT NOT NULL COMMENT 'Provider secret, envelope-encrypted (enc:v1:<key id>:...)',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_provider_credentials_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT unique_provider_credentials_partner_provider UNIQUE (partner_id, provider),
+    CONSTRAINT check_provider_credential_provider CHECK (provider IN ('stripe', 'paypal'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Partners'' own payment provider accounts';
+
+CREATE TABLE admin_users (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    totp_secret TEXT NOT NULL COMMENT 'Base32 TOTP secret, envelope-encrypted like other secrets',
+    totp_last_step BIGINT NOT NULL DEFAULT 0
+        COMMENT 'Last accepted TOTP time step; a code is accepted at most once',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    last_login_at DATETIME(6),
+    disabled_at DATETIME(6),
+    CONSTRAINT unique_admin_users_email UNIQUE (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Pay2Go staff with access to /api/v1/admin; separate from partner team users';
+
+CREATE TABLE admin_sessions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    admin_id CHAR(36) NOT NULL,
+    token_hash VARCHAR(64) COLLATE utf8mb4_bin NOT NULL UNIQUE,
+    expires_at DATETIME(6) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    revoked_at DATETIME(6),
+    CONSTRAINT fk_admin_sessions_admin FOREIGN KEY (admin_id) REFERENCES admin_users(id),
+    INDEX idx_admin_sessions_admin_id (admin_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE card_bins (
+    prefix VARCHAR(8) NOT NULL PRIMARY KEY,
+    brand VARCHAR(20) NOT NULL,
+    issuer_country CHAR(2) NOT NULL DEFAULT '',
+    issuer_name VARCHAR(255) NOT NULL DEFAULT '',
+    funding VARCHAR(10) NOT NULL DEFAULT 'unknown',
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT check_card_bin_prefix CHECK (REGEXP_LIKE(prefix, '^[0-9]{6,8}$')),
+    CONSTRAINT check_card_bin_brand CHECK (brand IN ('visa', 'mastercard', 'amex', 'discover', 'jcb', 'unionpay', 'diners', 'unknown')),
+    CONSTRAINT check_card_bin_funding CHECK (funding IN ('credit', 'debit', 'prepaid', 'unknown'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='BIN ranges as 6-8 digit prefixes; the longest matching prefix wins';
+
+INSERT INTO card_bins (prefix, brand, issuer_country, issuer_name, funding) VALUES
+    ('424242', 'visa', 'US', 'Test Bank', 'credit'),
+    ('400005', 'visa', 'US', 'Test Bank', 'debit'),
+    ('555555', 'mastercard', 'US', 'Test Bank', 'credit'),
+    ('520082', 'mastercard', 'US', 'Test Bank', 'debit'),
+    ('510510', 'mastercard', 'US', 'Test Bank', 'prepaid'),
+    ('378282', 'amex', 'US', 'American Express', 'credit'),
+    ('601111', 'discover', 'US', 'Discover', 'credit'),
+    ('356600', 'jcb', 'JP', 'JCB', 'credit'),
+    ('620000', 'unionpay', 'CN', 'UnionPay', 'debit'),
+    ('400000', 'visa', 'US', 'Test Bank', 'credit'),
+    ('40000076', 'visa', 'GB', 'Test Bank', 'credit'),
+    ('40000276', 'visa', 'DE', 'Test Bank', 'credit'),
+    ('40003920', 'visa', 'JP', 'Test Bank', 'credit'),
+    ('40007640', 'visa', 'TH', 'Test Bank', 'debit');
+
+CREATE TABLE webhook_events (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    transaction_id CHAR(36),
+    event_type VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    last_response_code INT,
+    last_error TEXT,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    delivered_at DATETIME(6),
+    CONSTRAINT fk_webhook_events_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT fk_webhook_events_transaction FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    CONSTRAINT check_webhook_event_status CHECK (status IN ('pending', 'delivered', 'failed')),
+    INDEX idx_webhook_events_partner_id (partner_id, created_at DESC),
+    INDEX idx_webhook_events_due (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Webhook notifications to partners, kept for retries and delivery history';
+
+CREATE TABLE outbox_events (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+        COMMENT 'When an unpublished event is next due for publishing',
+    last_error TEXT,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    published_at DATETIME(6),
+    CONSTRAINT fk_outbox_events_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    INDEX idx_outbox_events_due (published_at, next_attempt_at, created_at),
+    INDEX idx_outbox_events_aggregate (aggregate_type, aggregate_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Events committed with their state change, published afterwards by the outbox relay';
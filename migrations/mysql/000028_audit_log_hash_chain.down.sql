@@ -0,0 +1,7 @@
+-- Rollback migration for audit log hash chain (MySQL)
+
+ALTER TABLE audit_logs
+    DROP INDEX idx_audit_logs_chain,
+    DROP INDEX idx_audit_logs_prev_hash,
+    DROP COLUMN hash,
+    DROP COLUMN prev_hash;
@@ -0,0 +1,9 @@
+-- Migration: Audit log hash chain (MySQL)
+-- Version: 000028
+-- Description: Each audit entry stores its hash and the hash of the previous entry of its partner, so changed or removed entries can be detected
+
+ALTER TABLE audit_logs
+    ADD COLUMN prev_hash CHAR(64),
+    ADD COLUMN hash CHAR(64),
+    ADD UNIQUE INDEX idx_audit_logs_prev_hash (prev_hash),
+    ADD INDEX idx_audit_logs_chain (partner_id, id);
@@ -0,0 +1,11 @@
+-- Migration: Outbox shards (MySQL)
+-- Version: 000031
+-- Description: Spreads outbox events over 256 shards by partner, so several relays can split the outbox between them
+
+ALTER TABLE outbox_events
+    ADD COLUMN shard SMALLINT NOT NULL DEFAULT 0
+        COMMENT 'Shard of the partner (first byte of partner_id); relays publish the shards assigned to them'
+        AFTER payload;
+
+-- Published events are never claimed again, so only pending ones need it
+UPDATE outbox_events SET shard = CONV(LEFT(partner_id, 2), 16, 10) WHERE published_at IS NULL;
@@ -0,0 +1,3 @@
+-- Rollback migration for partner usage (MySQL)
+
+DROP TABLE IF EXISTS partner_usage;
@@ -0,0 +1,20 @@
+-- Migration: Partner usage (MySQL)
+-- Version: 000032
+-- Description: Daily API calls, errors and payment volume of each partner, metered for billing and capacity planning
+
+CREATE TABLE partner_usage (
+    partner_id CHAR(36) NOT NULL,
+    day DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT ''
+        COMMENT 'API calls are metered with an empty currency, payments per currency',
+    api_calls BIGINT NOT NULL DEFAULT 0,
+    client_errors BIGINT NOT NULL DEFAULT 0,
+    server_errors BIGINT NOT NULL DEFAULT 0,
+    payments BIGINT NOT NULL DEFAULT 0,
+    failed_payments BIGINT NOT NULL DEFAULT 0,
+    volume BIGINT NOT NULL DEFAULT 0
+        COMMENT 'Amount of the successful live payments, in minor units of the currency',
+    PRIMARY KEY (partner_id, day, currency),
+    INDEX idx_partner_usage_day (day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Usage counters per partner and UTC day, added to by the API instances as they flush their meters';
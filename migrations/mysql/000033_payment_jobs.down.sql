@@ -0,0 +1,3 @@
+-- Rollback migration for payment jobs (MySQL)
+
+DROP TABLE IF EXISTS payment_jobs;
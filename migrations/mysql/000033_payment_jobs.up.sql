@@ -0,0 +1,20 @@
+-- Migration: Payment jobs (MySQL)
+-- Version: 000033
+-- Description: Queue of payments waiting to be processed by a worker, when the API processes payments asynchronously
+
+CREATE TABLE payment_jobs (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    transaction_id CHAR(36) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+        COMMENT 'When an uncompleted job is next due; claimed jobs are pushed past their claim timeout',
+    last_error TEXT
+        COMMENT 'Why the last attempt failed, or why a completed job made no payment',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    completed_at DATETIME(6),
+    CONSTRAINT fk_payment_jobs_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    INDEX idx_payment_jobs_due (completed_at, next_attempt_at, created_at),
+    INDEX idx_payment_jobs_transaction (transaction_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Payments queued by the API and processed by cmd/worker';
@@ -0,0 +1,4 @@
+-- Rollback migration for partner event destination (MySQL)
+
+ALTER TABLE partners
+    DROP COLUMN event_destination;
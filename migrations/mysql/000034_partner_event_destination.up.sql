@@ -0,0 +1,7 @@
+-- Migration: Partner event destination (MySQL)
+-- Version: 000034
+-- Description: Deliver a partner's events to an SNS topic or SQS queue in its own AWS account instead of its webhook URL
+
+ALTER TABLE partners
+    ADD COLUMN event_destination JSON NULL
+        COMMENT 'SNS topic or SQS queue and the IAM role assumed to deliver events to it; NULL delivers to webhook_url';
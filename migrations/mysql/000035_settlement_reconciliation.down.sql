@@ -0,0 +1,4 @@
+-- Rollback migration for settlement reconciliation (MySQL)
+
+DROP TABLE IF EXISTS disputes;
+DROP TABLE IF EXISTS settlement_entries;
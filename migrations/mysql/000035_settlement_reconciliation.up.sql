@@ -0,0 +1,51 @@
+-- Migration: Settlement reconciliation (MySQL)
+-- Version: 000035
+-- Description: Entries of providers' settlement and chargeback feeds reconciled against transactions, and the disputes chargebacks open
+
+CREATE TABLE settlement_entries (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL,
+    reference VARCHAR(255) NOT NULL
+        COMMENT 'Provider''s ID of the entry; a feed delivered twice is reconciled once',
+    entry_type VARCHAR(30) NOT NULL,
+    provider_transaction_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    fee BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at DATETIME(6) NOT NULL,
+    source VARCHAR(500) NOT NULL DEFAULT '',
+    transaction_id CHAR(36),
+    partner_id CHAR(36),
+    discrepancy VARCHAR(30)
+        COMMENT 'Why the entry disagrees with the stored transaction; NULL when it agrees',
+    discrepancy_detail TEXT NOT NULL,
+    reconciled_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT uq_settlement_entries_reference UNIQUE (provider, reference),
+    CONSTRAINT fk_settlement_entries_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT check_settlement_entry_type CHECK (entry_type IN ('settlement', 'chargeback', 'chargeback_reversal')),
+    INDEX idx_settlement_entries_discrepancies (discrepancy, reconciled_at),
+    INDEX idx_settlement_entries_transaction (transaction_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Lines of providers'' settlement and chargeback feeds, each reconciled once';
+
+CREATE TABLE disputes (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    transaction_id CHAR(36) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    provider_reference VARCHAR(255) NOT NULL
+        COMMENT 'Reference of the chargeback entry that opened the dispute',
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    reversed_at DATETIME(6),
+    CONSTRAINT fk_disputes_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT check_dispute_status CHECK (status IN ('open', 'reversed')),
+    INDEX idx_disputes_partner (partner_id, created_at),
+    INDEX idx_disputes_transaction (transaction_id, status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Cardholder disputes, opened by chargebacks in providers'' feeds';
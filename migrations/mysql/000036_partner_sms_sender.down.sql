@@ -0,0 +1,4 @@
+-- Rollback migration for partner SMS sender (MySQL)
+
+ALTER TABLE partners
+    DROP COLUMN sms_sender;
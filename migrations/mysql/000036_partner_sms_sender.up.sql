@@ -0,0 +1,7 @@
+-- Migration: Partner SMS sender (MySQL)
+-- Version: 000036
+-- Description: Send a partner's text messages to customers from its own number, sender name or Twilio Messaging Service
+
+ALTER TABLE partners
+    ADD COLUMN sms_sender VARCHAR(64) NOT NULL DEFAULT ''
+        COMMENT 'E.164 number, alphanumeric sender name or Messaging Service SID text messages come from; empty uses the platform sender';
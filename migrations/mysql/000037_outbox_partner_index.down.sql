@@ -0,0 +1,3 @@
+-- Rollback migration for outbox events by partner (MySQL)
+
+DROP INDEX idx_outbox_events_partner ON outbox_events;
@@ -0,0 +1,25 @@
+-- Migration: Export jobs (MySQL)
+-- Version: 000038
+-- Description: Exports of transactions or refunds requested through the API and produced by a worker
+
+CREATE TABLE export_jobs (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    livemode BOOLEAN NOT NULL,
+    resource VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    filters JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+        COMMENT 'When a pending job is next due; claimed jobs are pushed past their claim timeout',
+    last_error TEXT,
+    object_key TEXT
+        COMMENT 'Key of the finished file in the export object store',
+    row_count BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    completed_at DATETIME(6),
+    CONSTRAINT fk_export_jobs_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    INDEX idx_export_jobs_due (status, next_attempt_at, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Exports queued by POST /exports and produced by the API''s export worker';
@@ -0,0 +1,7 @@
+-- Migration: Partner field filter (MySQL)
+-- Version: 000039
+-- Description: Hide fields from a partner's API responses, per API key scope, and from its webhooks
+
+ALTER TABLE partners
+    ADD COLUMN field_filter JSON NULL
+        COMMENT 'Fields hidden from API responses per API key scope and from webhook payloads; NULL hides nothing';
@@ -0,0 +1,7 @@
+-- Migration: Partner version (MySQL)
+-- Version: 000040
+-- Description: Version column so concurrent admin changes to a partner cannot overwrite each other, exposed as the ETag of its admin resources
+
+ALTER TABLE partners
+    ADD COLUMN version INT NOT NULL DEFAULT 1
+        COMMENT 'Incremented on every update; updates must name the version they were loaded at';
@@ -0,0 +1,5 @@
+-- Rollback migration for export layout (MySQL)
+
+ALTER TABLE export_jobs
+    DROP COLUMN timezone,
+    DROP COLUMN csv_columns;
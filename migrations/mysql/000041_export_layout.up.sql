@@ -0,0 +1,9 @@
+-- Migration: Export layout (MySQL)
+-- Version: 000041
+-- Description: Columns and time zone chosen for an export's file
+
+ALTER TABLE export_jobs
+    ADD COLUMN csv_columns VARCHAR(1024) NOT NULL DEFAULT ''
+        COMMENT 'Comma-separated CSV columns, in order; empty writes every column',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT ''
+        COMMENT 'IANA name of the zone the file''s times are written in; empty is UTC';
@@ -0,0 +1,7 @@
+-- Migration: Partner receipt logo (MySQL)
+-- Version: 000042
+-- Description: Logo printed on the PDF receipts of a partner's transactions
+
+ALTER TABLE partners
+    ADD COLUMN receipt_logo_key VARCHAR(255) NOT NULL DEFAULT ''
+        COMMENT 'Key of the receipt logo in the object store; empty prints the partner name alone';
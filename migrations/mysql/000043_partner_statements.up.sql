@@ -0,0 +1,25 @@
+-- Migration: Partner statements (MySQL)
+-- Version: 000043
+-- Description: Monthly statements of each partner's volume, refunds, chargebacks and fees, and the pricing they are charged at
+
+ALTER TABLE partners
+    ADD COLUMN pricing JSON
+        COMMENT 'Fee tiers and chargeback fee the partner''s statements charge; NULL charges the platform''s standard pricing';
+
+CREATE TABLE statements (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    period CHAR(7) NOT NULL
+        COMMENT 'Calendar month in UTC the statement covers, as YYYY-MM',
+    pricing JSON NOT NULL
+        COMMENT 'Pricing the statement charged, as it was when issued',
+    rounding_policy VARCHAR(10) NOT NULL,
+    currencies JSON NOT NULL,
+    document_key TEXT NOT NULL
+        COMMENT 'Key of the CSV document in the export object store',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_statements_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    CONSTRAINT uq_statements_partner_period UNIQUE (partner_id, period),
+    INDEX idx_statements_period (period, partner_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Monthly partner statements, issued once the month is over and fed to invoicing';
@@ -0,0 +1,22 @@
+-- Migration: Payment analytics (MySQL)
+-- Version: 000044
+-- Description: Daily rollups of each partner's payments and refunds by currency, provider and payment method, read by the analytics endpoints
+
+CREATE TABLE payment_analytics_daily (
+    partner_id CHAR(36) NOT NULL,
+    day DATE NOT NULL,
+    livemode BOOLEAN NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    payment_method VARCHAR(50) NOT NULL,
+    succeeded_payments BIGINT NOT NULL DEFAULT 0,
+    failed_payments BIGINT NOT NULL DEFAULT 0,
+    volume BIGINT NOT NULL DEFAULT 0
+        COMMENT 'Amount of the succeeded payments made on the day, in minor units of the currency',
+    refunds BIGINT NOT NULL DEFAULT 0
+        COMMENT 'Completed refunds made on the day, of payments with the currency, provider and payment method',
+    refunded_volume BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (partner_id, day, livemode, currency, provider, payment_method),
+    INDEX idx_payment_analytics_daily_day (day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Payments and refunds per partner and UTC day, rebuilt by the analytics rollup job';
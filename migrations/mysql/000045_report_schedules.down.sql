@@ -0,0 +1,3 @@
+-- Rollback migration for report schedules (MySQL)
+
+DROP TABLE IF EXISTS report_schedules;
@@ -0,0 +1,35 @@
+-- Migration: Report schedules (MySQL)
+-- Version: 000045
+-- Description: Partners' schedules delivering transaction, refund and statement reports by email, SFTP or S3
+
+CREATE TABLE report_schedules (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    partner_id CHAR(36) NOT NULL,
+    livemode BOOLEAN NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    resource VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    filters JSON NOT NULL,
+    csv_columns TEXT NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    frequency VARCHAR(10) NOT NULL,
+    run_hour SMALLINT NOT NULL
+        COMMENT 'Hour of the day, in timezone, runs are due at',
+    destination JSON NOT NULL
+        COMMENT 'Email recipients, SFTP server or S3 bucket, without credentials',
+    destination_secret TEXT NOT NULL
+        COMMENT 'Encrypted SFTP password or private key',
+    next_run_at DATETIME(6) NOT NULL
+        COMMENT 'When the next run, or retry, is due; claimed schedules are pushed past their claim timeout',
+    attempts INT NOT NULL DEFAULT 0,
+    last_run_at DATETIME(6),
+    last_status VARCHAR(20) NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_report_schedules_partner FOREIGN KEY (partner_id) REFERENCES partners(id),
+    INDEX idx_report_schedules_partner (partner_id, livemode, created_at),
+    INDEX idx_report_schedules_due (next_run_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+  COMMENT='Reports delivered to partners on a schedule by the API''s report worker';
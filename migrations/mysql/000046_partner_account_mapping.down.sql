@@ -0,0 +1,4 @@
+-- Rollback migration for partner account mapping (MySQL)
+
+ALTER TABLE partners
+    DROP COLUMN account_mapping;
@@ -0,0 +1,7 @@
+-- Migration: Partner account mapping (MySQL)
+-- Version: 000046
+-- Description: Accounts of each partner's accounting system that QuickBooks and Xero exports post to
+
+ALTER TABLE partners
+    ADD COLUMN account_mapping JSON
+        COMMENT 'Accounts QuickBooks and Xero exports post to; NULL posts to the default accounts';
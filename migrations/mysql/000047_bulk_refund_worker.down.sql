@@ -0,0 +1,8 @@
+-- Rollback migration for the bulk refund worker (MySQL)
+
+ALTER TABLE bulk_refund_jobs
+    DROP INDEX idx_bulk_refund_jobs_due,
+    DROP COLUMN next_attempt_at,
+    DROP COLUMN user_agent,
+    DROP COLUMN ip_address,
+    DROP COLUMN livemode;
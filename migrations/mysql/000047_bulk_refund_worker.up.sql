@@ -0,0 +1,12 @@
+-- Migration: Bulk refund worker (MySQL)
+-- Version: 000047
+-- Description: Bulk refund jobs run by a worker that claims them and resumes the unfinished
+
+ALTER TABLE bulk_refund_jobs
+    ADD COLUMN livemode BOOLEAN NOT NULL DEFAULT TRUE
+        COMMENT 'Mode of the request; jobs queued before this column existed were live',
+    ADD COLUMN ip_address VARCHAR(45),
+    ADD COLUMN user_agent TEXT,
+    ADD COLUMN next_attempt_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+        COMMENT 'When an unfinished job is next due; claimed jobs are pushed past their claim timeout',
+    ADD INDEX idx_bulk_refund_jobs_due (status, next_attempt_at, created_at);
@@ -0,0 +1,17 @@
+-- Rollback migration for refund approval thresholds per currency (MySQL)
+
+ALTER TABLE partners
+    ADD COLUMN refund_approval_threshold BIGINT NOT NULL DEFAULT 0
+        COMMENT 'Refunds above this amount, in minor units, wait for admin approval (0 disables approval)',
+    ADD CONSTRAINT check_refund_approval_threshold CHECK (refund_approval_threshold >= 0);
+
+-- A single threshold cannot hold several; the lowest keeps every refund
+-- that needed approval held
+UPDATE partners p
+SET refund_approval_threshold = COALESCE((
+    SELECT MIN(CAST(JSON_EXTRACT(p.refund_approval_thresholds, CONCAT('$.', k.code)) AS SIGNED))
+    FROM JSON_TABLE(JSON_KEYS(p.refund_approval_thresholds), '$[*]' COLUMNS (code VARCHAR(3) PATH '$')) k
+), 0);
+
+ALTER TABLE partners
+    DROP COLUMN refund_approval_thresholds;
@@ -0,0 +1,25 @@
+-- Migration: Refund approval thresholds per currency (MySQL)
+-- Version: 000048
+-- Description: Refund approval thresholds keyed by currency, replacing the single threshold in minor units
+
+ALTER TABLE partners
+    ADD COLUMN refund_approval_thresholds JSON NOT NULL DEFAULT (JSON_OBJECT())
+        COMMENT 'Refunds above the amount for their currency, in minor units, wait for admin approval, e.g. {"USD": 50000}; currencies not listed need no approval';
+
+-- The single threshold was compared in minor units whatever the currency;
+-- it is kept as is for each currency the partner allows or has charged in
+UPDATE partners p
+SET refund_approval_thresholds = COALESCE((
+    SELECT JSON_OBJECTAGG(c.code, p.refund_approval_threshold)
+    FROM (
+        SELECT j.code
+        FROM JSON_TABLE(p.allowed_currencies, '$[*]' COLUMNS (code VARCHAR(3) PATH '$')) j
+        UNION
+        SELECT DISTINCT t.currency FROM transactions t WHERE t.partner_id = p.id
+    ) c
+), JSON_OBJECT())
+WHERE p.refund_approval_threshold > 0;
+
+ALTER TABLE partners
+    DROP CHECK check_refund_approval_threshold,
+    DROP COLUMN refund_approval_threshold;
@@ -0,0 +1,357 @@
+-- Migration: Initial schema (SQLite)
+-- Version: 000001
+-- Description: The PostgreSQL schema as of migration 000027, for SQLite 3.38 or later
+--
+-- Differences from PostgreSQL:
+--   * UUIDs are TEXT, arrays (allowed_currencies, scopes) and JSONB are JSON text
+--   * Enum types are TEXT columns with CHECK constraints
+--   * Times are DATETIME text in UTC; the repositories write every time in UTC
+--   * There are no triggers: the application sets updated_at, the
+--     refunded_amount CHECK guards refunds, and transaction_events is not written
+--   * Column comments are left out; see the PostgreSQL migrations
+
+CREATE TABLE partners (
+    id TEXT NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    api_key_hash TEXT NOT NULL,
+    api_key_prefix TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    rate_limit_per_minute INTEGER NOT NULL DEFAULT 100,
+    webhook_url TEXT,
+    webhook_secret TEXT,
+    refund_approval_threshold INTEGER NOT NULL DEFAULT 0,
+    refund_window_days INTEGER NOT NULL DEFAULT 0,
+    features TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(features)),
+    allowed_currencies TEXT NOT NULL DEFAULT '[]' CHECK (json_valid(allowed_currencies)),
+    amount_limits TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(amount_limits)),
+    locale TEXT NOT NULL DEFAULT 'en-US',
+    rounding_policy TEXT NOT NULL DEFAULT 'half_up',
+    metadata TEXT CHECK (metadata IS NULL OR json_valid(metadata)),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    anonymize_after DATETIME,
+    anonymized_at DATETIME,
+    CONSTRAINT unique_partners_email UNIQUE (email),
+    CONSTRAINT unique_partners_api_key_hash UNIQUE (api_key_hash),
+    CONSTRAINT check_refund_approval_threshold CHECK (refund_approval_threshold >= 0),
+    CONSTRAINT check_refund_window_days CHECK (refund_window_days >= 0 AND refund_window_days <= 3650),
+    CONSTRAINT check_rounding_policy CHECK (rounding_policy IN ('half_up', 'half_even', 'truncate'))
+);
+
+CREATE INDEX idx_partners_api_key_prefix ON partners(api_key_prefix) WHERE deleted_at IS NULL;
+CREATE INDEX idx_partners_anonymize_after ON partners(anonymize_after) WHERE anonymize_after IS NOT NULL;
+
+CREATE TABLE transactions (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    idempotency_key TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    refunded_amount INTEGER NOT NULL DEFAULT 0,
+    currency TEXT NOT NULL DEFAULT 'USD',
+    payment_method TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    provider_transaction_id TEXT,
+    provider_customer_id TEXT,
+    provider_credential_id TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    customer_email TEXT NOT NULL,
+    customer_name TEXT,
+    customer_phone TEXT,
+    description TEXT,
+    metadata TEXT CHECK (metadata IS NULL OR json_valid(metadata)),
+    payment_method_details TEXT CHECK (payment_method_details IS NULL OR json_valid(payment_method_details)),
+    billing_country TEXT,
+    ip_address TEXT,
+    user_agent TEXT,
+    request_id TEXT,
+    error_code TEXT,
+    error_message TEXT,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at DATETIME,
+    failed_at DATETIME,
+    deleted_at DATETIME,
+    CONSTRAINT unique_partner_idempotency UNIQUE (partner_id, idempotency_key),
+    CONSTRAINT check_amount_positive CHECK (amount >= 1),
+    CONSTRAINT chk_transactions_refunded_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount),
+    CONSTRAINT check_transaction_status CHECK (status IN (
+        'pending', 'processing', 'completed', 'failed', 'cancelled', 'refunded', 'partially_refunded'
+    )),
+    CONSTRAINT check_transaction_provider CHECK (provider IN ('stripe', 'paypal', 'adyen', 'manual'))
+);
+
+CREATE INDEX idx_transactions_partner_livemode ON transactions(partner_id, livemode, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_partner_status_created ON transactions(partner_id, status, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_created_at ON transactions(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_provider_transaction_id ON transactions(provider_transaction_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_transactions_customer_email ON transactions(customer_email) WHERE deleted_at IS NULL;
+
+CREATE TABLE refunds (
+    id TEXT NOT NULL PRIMARY KEY,
+    transaction_id TEXT NOT NULL REFERENCES transactions(id),
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    reason_code TEXT NOT NULL DEFAULT 'other',
+    reason TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    provider_refund_id TEXT,
+    error_code TEXT,
+    error_message TEXT,
+    approved_by TEXT,
+    approved_at DATETIME,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at DATETIME,
+    cancelled_at DATETIME,
+    deleted_at DATETIME,
+    CONSTRAINT check_refund_amount_positive CHECK (amount > 0),
+    CONSTRAINT check_refund_status CHECK (status IN (
+        'pending', 'requires_approval', 'processing', 'completed', 'failed', 'cancelled'
+    )),
+    CONSTRAINT check_refund_reason_code CHECK (reason_code IN (
+        'requested_by_customer', 'duplicate', 'fraudulent', 'other'
+    ))
+);
+
+CREATE INDEX idx_refunds_transaction_id ON refunds(transaction_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_status_created ON refunds(status, created_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_refunds_reason_code ON refunds(reason_code, created_at) WHERE deleted_at IS NULL;
+
+CREATE TABLE transaction_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id TEXT NOT NULL REFERENCES transactions(id),
+    event_type TEXT NOT NULL,
+    status TEXT,
+    provider_response TEXT,
+    error_message TEXT,
+    created_by TEXT,
+    ip_address TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transaction_events_txn_created ON transaction_events(transaction_id, created_at DESC);
+
+CREATE TABLE audit_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    partner_id TEXT REFERENCES partners(id),
+    action TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    resource_id TEXT,
+    ip_address TEXT NOT NULL,
+    user_agent TEXT,
+    request_id TEXT,
+    changes TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_partner_id ON audit_logs(partner_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at DESC);
+CREATE INDEX idx_audit_logs_resource ON audit_logs(resource_type, resource_id);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action);
+
+CREATE TABLE payment_methods (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO payment_methods (code, name) VALUES
+    ('card', 'Credit/Debit Card'),
+    ('bank_transfer', 'Bank Transfer'),
+    ('e_wallet', 'E-Wallet'),
+    ('crypto', 'Cryptocurrency');
+
+CREATE TABLE currencies (
+    code TEXT NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    symbol TEXT,
+    decimal_places INTEGER NOT NULL DEFAULT 2,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+INSERT INTO currencies (code, name, symbol, decimal_places) VALUES
+    ('USD', 'US Dollar', '$', 2),
+    ('EUR', 'Euro', '€', 2),
+    ('GBP', 'British Pound', '£', 2),
+    ('JPY', 'Japanese Yen', '¥', 0),
+    ('THB', 'Thai Baht', '฿', 2);
+
+CREATE TABLE bulk_refund_jobs (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    transaction_ids TEXT NOT NULL CHECK (json_valid(transaction_ids)),
+    reason_code TEXT NOT NULL DEFAULT 'other',
+    reason TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    results TEXT NOT NULL DEFAULT '[]' CHECK (json_valid(results)),
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at DATETIME,
+    CONSTRAINT check_bulk_refund_job_status CHECK (status IN ('pending', 'running', 'completed')),
+    CONSTRAINT check_bulk_refund_job_counts CHECK (succeeded >= 0 AND failed >= 0)
+);
+
+CREATE INDEX idx_bulk_refund_jobs_partner_id ON bulk_refund_jobs(partner_id, created_at DESC);
+
+CREATE TABLE api_keys (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    label TEXT NOT NULL,
+    key_hash TEXT NOT NULL,
+    key_prefix TEXT NOT NULL,
+    scopes TEXT NOT NULL CHECK (json_valid(scopes)),
+    auth_method TEXT NOT NULL DEFAULT 'bearer',
+    signing_secret TEXT,
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    last_used_at DATETIME,
+    last_used_ip TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME,
+    CONSTRAINT unique_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT unique_api_keys_key_prefix UNIQUE (key_prefix),
+    CONSTRAINT check_api_key_scopes CHECK (json_array_length(scopes) > 0),
+    CONSTRAINT check_api_key_auth_method CHECK (auth_method IN ('bearer', 'hmac')),
+    CONSTRAINT api_keys_signing_secret_check CHECK ((auth_method = 'hmac') = (signing_secret IS NOT NULL))
+);
+
+CREATE INDEX idx_api_keys_partner_id ON api_keys(partner_id);
+
+CREATE TABLE users (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    email TEXT NOT NULL,
+    name TEXT NOT NULL,
+    role TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at DATETIME,
+    deleted_at DATETIME,
+    CONSTRAINT check_user_role CHECK (role IN ('owner', 'developer', 'finance', 'read_only'))
+);
+
+CREATE UNIQUE INDEX idx_users_email ON users(LOWER(email)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_partner_id ON users(partner_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE user_sessions (
+    id TEXT NOT NULL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id),
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME
+);
+
+CREATE INDEX idx_user_sessions_user_id ON user_sessions(user_id);
+
+CREATE TABLE provider_credentials (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    provider TEXT NOT NULL,
+    account_id TEXT NOT NULL DEFAULT '',
+    secret_key TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_provider_credentials_partner_provider UNIQUE (partner_id, provider),
+    CONSTRAINT check_provider_credential_provider CHECK (provider IN ('stripe', 'paypal'))
+);
+
+CREATE TABLE admin_users (
+    id TEXT NOT NULL PRIMARY KEY,
+    email TEXT NOT NULL,
+    name TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    totp_secret TEXT NOT NULL,
+    totp_last_step INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at DATETIME,
+    disabled_at DATETIME,
+    CONSTRAINT unique_admin_users_email UNIQUE (email)
+);
+
+CREATE TABLE admin_sessions (
+    id TEXT NOT NULL PRIMARY KEY,
+    admin_id TEXT NOT NULL REFERENCES admin_users(id),
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME
+);
+
+CREATE INDEX idx_admin_sessions_admin_id ON admin_sessions(admin_id);
+
+CREATE TABLE card_bins (
+    prefix TEXT NOT NULL PRIMARY KEY,
+    brand TEXT NOT NULL,
+    issuer_country TEXT NOT NULL DEFAULT '',
+    issuer_name TEXT NOT NULL DEFAULT '',
+    funding TEXT NOT NULL DEFAULT 'unknown',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_card_bin_prefix CHECK (LENGTH(prefix) BETWEEN 6 AND 8 AND prefix NOT GLOB '*[^0-9]*'),
+    CONSTRAINT check_card_bin_brand CHECK (brand IN ('visa', 'mastercard', 'amex', 'discover', 'jcb', 'unionpay', 'diners', 'unknown')),
+    CONSTRAINT check_card_bin_funding CHECK (funding IN ('credit', 'debit', 'prepaid', 'unknown'))
+);
+
+INSERT INTO card_bins (prefix, brand, issuer_country, issuer_name, funding) VALUES
+    ('424242', 'visa', 'US', 'Test Bank', 'credit'),
+    ('400005', 'visa', 'US', 'Test Bank', 'debit'),
+    ('555555', 'mastercard', 'US', 'Test Bank', 'credit'),
+    ('520082', 'mastercard', 'US', 'Test Bank', 'debit'),
+    ('510510', 'mastercard', 'US', 'Test Bank', 'prepaid'),
+    ('378282', 'amex', 'US', 'American Express', 'credit'),
+    ('601111', 'discover', 'US', 'Discover', 'credit'),
+    ('356600', 'jcb', 'JP', 'JCB', 'credit'),
+    ('620000', 'unionpay', 'CN', 'UnionPay', 'debit'),
+    ('400000', 'visa', 'US', 'Test Bank', 'credit'),
+    ('40000076', 'visa', 'GB', 'Test Bank', 'credit'),
+    ('40000276', 'visa', 'DE', 'Test Bank', 'credit'),
+    ('40003920', 'visa', 'JP', 'Test Bank', 'credit'),
+    ('40007640', 'visa', 'TH', 'Test Bank', 'debit');
+
+CREATE TABLE webhook_events (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    transaction_id TEXT REFERENCES transactions(id),
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL CHECK (json_valid(payload)),
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_response_code INTEGER,
+    last_error TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at DATETIME,
+    CONSTRAINT check_webhook_event_status CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+CREATE INDEX idx_webhook_events_partner_id ON webhook_events(partner_id, created_at DESC);
+CREATE INDEX idx_webhook_events_due ON webhook_events(next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE outbox_events (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL CHECK (json_valid(payload)),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at DATETIME
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at, created_at) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
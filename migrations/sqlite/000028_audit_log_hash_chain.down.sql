@@ -0,0 +1,6 @@
+-- Rollback migration for audit log hash chain (SQLite)
+
+DROP INDEX IF EXISTS idx_audit_logs_chain;
+DROP INDEX IF EXISTS idx_audit_logs_prev_hash;
+ALTER TABLE audit_logs DROP COLUMN hash;
+ALTER TABLE audit_logs DROP COLUMN prev_hash;
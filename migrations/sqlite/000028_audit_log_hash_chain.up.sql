@@ -0,0 +1,9 @@
+-- Migration: Audit log hash chain (SQLite)
+-- Version: 000028
+-- Description: Each audit entry stores its hash and the hash of the previous entry of its partner, so changed or removed entries can be detected
+
+ALTER TABLE audit_logs ADD COLUMN prev_hash TEXT;
+ALTER TABLE audit_logs ADD COLUMN hash TEXT;
+
+CREATE UNIQUE INDEX idx_audit_logs_prev_hash ON audit_logs(prev_hash);
+CREATE INDEX idx_audit_logs_chain ON audit_logs(partner_id, id);
@@ -0,0 +1,3 @@
+-- Rollback migration for outbox shards (SQLite)
+
+ALTER TABLE outbox_events DROP COLUMN shard;
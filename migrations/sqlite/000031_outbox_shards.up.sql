@@ -0,0 +1,13 @@
+-- Migration: Outbox shards (SQLite)
+-- Version: 000031
+-- Description: Spreads outbox events over 256 shards by partner, so several relays can split the outbox between them
+
+ALTER TABLE outbox_events ADD COLUMN shard INTEGER NOT NULL DEFAULT 0;
+
+-- The shard is the first byte of the partner ID, i.e. its first two hex
+-- digits. Published events are never claimed again, so only pending ones
+-- need it.
+UPDATE outbox_events
+SET shard = (instr('0123456789abcdef', lower(substr(partner_id, 1, 1))) - 1) * 16
+          + (instr('0123456789abcdef', lower(substr(partner_id, 2, 1))) - 1)
+WHERE published_at IS NULL;
@@ -0,0 +1,3 @@
+-- Rollback migration for partner usage (SQLite)
+
+DROP TABLE IF EXISTS partner_usage;
@@ -0,0 +1,19 @@
+-- Migration: Partner usage (SQLite)
+-- Version: 000032
+-- Description: Daily API calls, errors and payment volume of each partner, metered for billing and capacity planning
+
+CREATE TABLE partner_usage (
+    partner_id TEXT NOT NULL,
+    day DATE NOT NULL,
+    -- API calls are metered with an empty currency, payments per currency
+    currency TEXT NOT NULL DEFAULT '',
+    api_calls INTEGER NOT NULL DEFAULT 0,
+    client_errors INTEGER NOT NULL DEFAULT 0,
+    server_errors INTEGER NOT NULL DEFAULT 0,
+    payments INTEGER NOT NULL DEFAULT 0,
+    failed_payments INTEGER NOT NULL DEFAULT 0,
+    volume INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (partner_id, day, currency)
+);
+
+CREATE INDEX idx_partner_usage_day ON partner_usage(day);
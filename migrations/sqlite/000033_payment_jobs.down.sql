@@ -0,0 +1,3 @@
+-- Rollback migration for payment jobs (SQLite)
+
+DROP TABLE IF EXISTS payment_jobs;
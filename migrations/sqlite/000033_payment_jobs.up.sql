@@ -0,0 +1,19 @@
+-- Migration: Payment jobs (SQLite)
+-- Version: 000033
+-- Description: Queue of payments waiting to be processed by a worker, when the API processes payments asynchronously
+
+CREATE TABLE payment_jobs (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    transaction_id TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- When an uncompleted job is next due; claimed jobs are pushed past their
+    -- claim timeout
+    next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at DATETIME
+);
+
+CREATE INDEX idx_payment_jobs_due ON payment_jobs(next_attempt_at, created_at) WHERE completed_at IS NULL;
+CREATE INDEX idx_payment_jobs_transaction ON payment_jobs(transaction_id);
@@ -0,0 +1,4 @@
+-- Rollback migration for partner event destination (SQLite)
+
+ALTER TABLE partners
+    DROP COLUMN event_destination;
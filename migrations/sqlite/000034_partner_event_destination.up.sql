@@ -0,0 +1,8 @@
+-- Migration: Partner event destination (SQLite)
+-- Version: 000034
+-- Description: Deliver a partner's events to an SNS topic or SQS queue in its own AWS account instead of its webhook URL
+
+-- SNS topic or SQS queue and the IAM role assumed to deliver events to it,
+-- as JSON; NULL delivers to webhook_url
+ALTER TABLE partners
+    ADD COLUMN event_destination TEXT;
@@ -0,0 +1,49 @@
+-- Migration: Settlement reconciliation (SQLite)
+-- Version: 000035
+-- Description: Entries of providers' settlement and chargeback feeds reconciled against transactions, and the disputes chargebacks open
+
+CREATE TABLE settlement_entries (
+    id TEXT NOT NULL PRIMARY KEY,
+    provider TEXT NOT NULL,
+    -- Provider's ID of the entry; a feed delivered twice is reconciled once
+    reference TEXT NOT NULL,
+    entry_type TEXT NOT NULL,
+    provider_transaction_id TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    fee INTEGER NOT NULL DEFAULT 0,
+    currency TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    occurred_at DATETIME NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    transaction_id TEXT,
+    partner_id TEXT REFERENCES partners(id),
+    -- Why the entry disagrees with the stored transaction; NULL when it agrees
+    discrepancy TEXT,
+    discrepancy_detail TEXT NOT NULL DEFAULT '',
+    reconciled_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_settlement_entries_reference UNIQUE (provider, reference),
+    CONSTRAINT check_settlement_entry_type CHECK (entry_type IN ('settlement', 'chargeback', 'chargeback_reversal'))
+);
+
+CREATE INDEX idx_settlement_entries_discrepancies ON settlement_entries(reconciled_at) WHERE discrepancy IS NOT NULL;
+CREATE INDEX idx_settlement_entries_transaction ON settlement_entries(transaction_id);
+
+CREATE TABLE disputes (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    transaction_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    -- Reference of the chargeback entry that opened the dispute
+    provider_reference TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reversed_at DATETIME,
+    CONSTRAINT check_dispute_status CHECK (status IN ('open', 'reversed'))
+);
+
+CREATE INDEX idx_disputes_partner ON disputes(partner_id, created_at);
+CREATE INDEX idx_disputes_open ON disputes(transaction_id, created_at) WHERE status = 'open';
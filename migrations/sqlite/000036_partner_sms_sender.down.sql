@@ -0,0 +1,4 @@
+-- Rollback migration for partner SMS sender (SQLite)
+
+ALTER TABLE partners
+    DROP COLUMN sms_sender;
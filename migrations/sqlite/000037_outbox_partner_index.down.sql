@@ -0,0 +1,3 @@
+-- Rollback migration for outbox events by partner (SQLite)
+
+DROP INDEX IF EXISTS idx_outbox_events_partner;
@@ -0,0 +1,5 @@
+-- Migration: Outbox events by partner (SQLite)
+-- Version: 000037
+-- Description: Index a partner's outbox events in order, for the event stream to read them from a cursor
+
+CREATE INDEX idx_outbox_events_partner ON outbox_events(partner_id, created_at, id);
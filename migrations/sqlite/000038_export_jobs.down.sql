@@ -0,0 +1,3 @@
+-- Rollback migration for export jobs (SQLite)
+
+DROP TABLE IF EXISTS export_jobs;
@@ -0,0 +1,25 @@
+-- Migration: Export jobs (SQLite)
+-- Version: 000038
+-- Description: Exports of transactions or refunds requested through the API and produced by a worker
+
+CREATE TABLE export_jobs (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    livemode BOOLEAN NOT NULL,
+    resource TEXT NOT NULL,
+    format TEXT NOT NULL,
+    filters TEXT NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- When a pending job is next due; claimed jobs are pushed past their
+    -- claim timeout
+    next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    -- Key of the finished file in the export object store
+    object_key TEXT,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at DATETIME
+);
+
+CREATE INDEX idx_export_jobs_due ON export_jobs(next_attempt_at, created_at) WHERE status = 'pending';
@@ -0,0 +1,4 @@
+-- Rollback migration for partner field filter (SQLite)
+
+ALTER TABLE partners
+    DROP COLUMN field_filter;
@@ -0,0 +1,8 @@
+-- Migration: Partner field filter (SQLite)
+-- Version: 000039
+-- Description: Hide fields from a partner's API responses, per API key scope, and from its webhooks
+
+-- Fields hidden from API responses per API key scope and from webhook
+-- payloads, as JSON; NULL hides nothing
+ALTER TABLE partners
+    ADD COLUMN field_filter TEXT;
@@ -0,0 +1,4 @@
+-- Rollback migration for partner version (SQLite)
+
+ALTER TABLE partners
+    DROP COLUMN version;
@@ -0,0 +1,8 @@
+-- Migration: Partner version (SQLite)
+-- Version: 000040
+-- Description: Version column so concurrent admin changes to a partner cannot overwrite each other, exposed as the ETag of its admin resources
+
+-- Incremented on every update; updates must name the version they were
+-- loaded at
+ALTER TABLE partners
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,7 @@
+-- Rollback migration for export layout (SQLite)
+
+ALTER TABLE export_jobs
+    DROP COLUMN timezone;
+
+ALTER TABLE export_jobs
+    DROP COLUMN csv_columns;
@@ -0,0 +1,11 @@
+-- Migration: Export layout (SQLite)
+-- Version: 000041
+-- Description: Columns and time zone chosen for an export's file
+
+-- Comma-separated CSV columns, in order; empty writes every column
+ALTER TABLE export_jobs
+    ADD COLUMN csv_columns TEXT NOT NULL DEFAULT '';
+
+-- IANA name of the zone the file's times are written in; empty is UTC
+ALTER TABLE export_jobs
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
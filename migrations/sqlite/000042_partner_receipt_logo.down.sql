@@ -0,0 +1,4 @@
+-- Rollback migration for partner receipt logo (SQLite)
+
+ALTER TABLE partners
+    DROP COLUMN receipt_logo_key;
@@ -0,0 +1,8 @@
+-- Migration: Partner receipt logo (SQLite)
+-- Version: 000042
+-- Description: Logo printed on the PDF receipts of a partner's transactions
+
+-- Key of the receipt logo in the object store; empty prints the partner
+-- name alone
+ALTER TABLE partners
+    ADD COLUMN receipt_logo_key TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- Rollback migration for partner statements (SQLite)
+
+DROP TABLE IF EXISTS statements;
+
+ALTER TABLE partners
+    DROP COLUMN pricing;
@@ -0,0 +1,27 @@
+-- Migration: Partner statements (SQLite)
+-- Version: 000043
+-- Description: Monthly statements of each partner's volume, refunds, chargebacks and fees, and the pricing they are charged at
+
+-- Fee tiers and chargeback fee the partner's statements charge; NULL
+-- charges the platform's standard pricing
+ALTER TABLE partners
+    ADD COLUMN pricing TEXT;
+
+-- Monthly partner statements, issued once the month is over and fed to
+-- invoicing
+CREATE TABLE statements (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    -- Calendar month in UTC the statement covers, as YYYY-MM
+    period TEXT NOT NULL,
+    -- Pricing the statement charged, as it was when issued
+    pricing TEXT NOT NULL,
+    rounding_policy TEXT NOT NULL,
+    currencies TEXT NOT NULL DEFAULT '[]',
+    -- Key of the CSV document in the export object store
+    document_key TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (partner_id, period)
+);
+
+CREATE INDEX idx_statements_period ON statements(period, partner_id);
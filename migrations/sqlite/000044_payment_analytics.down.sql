@@ -0,0 +1,3 @@
+-- Rollback migration for payment analytics (SQLite)
+
+DROP TABLE IF EXISTS payment_analytics_daily;
@@ -0,0 +1,22 @@
+-- Migration: Payment analytics (SQLite)
+-- Version: 000044
+-- Description: Daily rollups of each partner's payments and refunds by currency, provider and payment method, read by the analytics endpoints
+
+CREATE TABLE payment_analytics_daily (
+    partner_id TEXT NOT NULL,
+    day DATE NOT NULL,
+    livemode BOOLEAN NOT NULL,
+    currency TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    payment_method TEXT NOT NULL,
+    succeeded_payments INTEGER NOT NULL DEFAULT 0,
+    failed_payments INTEGER NOT NULL DEFAULT 0,
+    -- Amount of the succeeded payments made on the day, in minor units of the currency
+    volume INTEGER NOT NULL DEFAULT 0,
+    -- Completed refunds made on the day, of payments with the currency, provider and payment method
+    refunds INTEGER NOT NULL DEFAULT 0,
+    refunded_volume INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (partner_id, day, livemode, currency, provider, payment_method)
+);
+
+CREATE INDEX idx_payment_analytics_daily_day ON payment_analytics_daily(day);
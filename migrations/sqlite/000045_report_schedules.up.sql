@@ -0,0 +1,35 @@
+-- Migration: Report schedules (SQLite)
+-- Version: 000045
+-- Description: Partners' schedules delivering transaction, refund and statement reports by email, SFTP or S3
+
+CREATE TABLE report_schedules (
+    id TEXT NOT NULL PRIMARY KEY,
+    partner_id TEXT NOT NULL REFERENCES partners(id),
+    livemode BOOLEAN NOT NULL,
+    name TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    format TEXT NOT NULL,
+    filters TEXT NOT NULL DEFAULT '{}',
+    csv_columns TEXT NOT NULL DEFAULT '',
+    timezone TEXT NOT NULL DEFAULT '',
+    frequency TEXT NOT NULL,
+    -- Hour of the day, in timezone, runs are due at
+    run_hour INTEGER NOT NULL,
+    -- Email recipients, SFTP server or S3 bucket, without credentials
+    destination TEXT NOT NULL,
+    -- Encrypted SFTP password or private key
+    destination_secret TEXT NOT NULL DEFAULT '',
+    -- When the next run, or retry, is due; claimed schedules are pushed past
+    -- their claim timeout
+    next_run_at DATETIME NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_run_at DATETIME,
+    last_status TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_schedules_partner ON report_schedules(partner_id, livemode, created_at);
+CREATE INDEX idx_report_schedules_due ON report_schedules(next_run_at);
@@ -0,0 +1,8 @@
+-- Migration: Partner account mapping (SQLite)
+-- Version: 000046
+-- Description: Accounts of each partner's accounting system that QuickBooks and Xero exports post to
+
+-- Accounts QuickBooks and Xero exports post to; NULL posts to the default
+-- accounts
+ALTER TABLE partners
+    ADD COLUMN account_mapping TEXT;
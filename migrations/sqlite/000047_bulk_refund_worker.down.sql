@@ -0,0 +1,15 @@
+-- Rollback migration for the bulk refund worker (SQLite)
+
+DROP INDEX IF EXISTS idx_bulk_refund_jobs_due;
+
+ALTER TABLE bulk_refund_jobs
+    DROP COLUMN next_attempt_at;
+
+ALTER TABLE bulk_refund_jobs
+    DROP COLUMN user_agent;
+
+ALTER TABLE bulk_refund_jobs
+    DROP COLUMN ip_address;
+
+ALTER TABLE bulk_refund_jobs
+    DROP COLUMN livemode;
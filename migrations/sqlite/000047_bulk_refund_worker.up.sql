@@ -0,0 +1,21 @@
+-- Migration: Bulk refund worker (SQLite)
+-- Version: 000047
+-- Description: Bulk refund jobs run by a worker that claims them and resumes the unfinished
+
+-- Mode of the request; jobs queued before this column existed were live
+ALTER TABLE bulk_refund_jobs
+    ADD COLUMN livemode BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE bulk_refund_jobs
+    ADD COLUMN ip_address TEXT;
+
+ALTER TABLE bulk_refund_jobs
+    ADD COLUMN user_agent TEXT;
+
+-- When an unfinished job is next due; claimed jobs are pushed past their
+-- claim timeout. SQLite cannot add a column with a non-constant default, so
+-- existing jobs are due at once.
+ALTER TABLE bulk_refund_jobs
+    ADD COLUMN next_attempt_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+CREATE INDEX idx_bulk_refund_jobs_due ON bulk_refund_jobs(next_attempt_at, created_at) WHERE status <> 'completed';
@@ -0,0 +1,11 @@
+-- Rollback migration for refund approval thresholds per currency (SQLite)
+
+-- A single threshold cannot hold several; the lowest keeps every refund
+-- that needed approval held
+UPDATE partners
+SET refund_approval_threshold = COALESCE((
+    SELECT MIN(CAST(j.value AS INTEGER)) FROM json_each(partners.refund_approval_thresholds) j
+), 0);
+
+ALTER TABLE partners
+    DROP COLUMN refund_approval_thresholds;
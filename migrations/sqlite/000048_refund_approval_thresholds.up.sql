@@ -0,0 +1,24 @@
+-- Migration: Refund approval thresholds per currency (SQLite)
+-- Version: 000048
+-- Description: Refund approval thresholds keyed by currency, replacing the single threshold in minor units
+
+-- Refunds above the amount for their currency, in minor units, wait for
+-- admin approval, e.g. {"USD": 50000}; currencies not listed need no approval
+ALTER TABLE partners
+    ADD COLUMN refund_approval_thresholds TEXT NOT NULL DEFAULT '{}';
+
+-- The single threshold was compared in minor units whatever the currency;
+-- it is kept as is for each currency the partner allows or has charged in
+UPDATE partners
+SET refund_approval_thresholds = COALESCE((
+    SELECT json_group_object(c.code, partners.refund_approval_threshold)
+    FROM (
+        SELECT j.value AS code FROM json_each(partners.allowed_currencies) j
+        UNION
+        SELECT DISTINCT t.currency FROM transactions t WHERE t.partner_id = partners.id
+    ) c
+), '{}')
+WHERE refund_approval_threshold > 0;
+
+-- SQLite cannot drop a column named in a table CHECK constraint, so
+-- refund_approval_threshold stays in place, unread and left at its value
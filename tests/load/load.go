@@ -0,0 +1,288 @@
+// Package load drives a running Pay2Go API with payment flows at a fixed
+// arrival rate and reports the latency and throughput of each step, so
+// releases can be compared against each other.
+//
+// A flow creates a transaction, processes it and refunds half of it. Use a
+// test-mode API key with the payments and refunds scopes: the sandbox gateway
+// processes the payments and no provider is contacted.
+package load
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Step is one request of a flow
+type Step string
+
+// Steps of a flow, in order
+const (
+	StepCreate  Step = "create"
+	StepProcess Step = "process"
+	StepRefund  Step = "refund"
+)
+
+// Steps lists the steps in flow order
+var Steps = []Step{StepCreate, StepProcess, StepRefund}
+
+// Config describes a load run
+type Config struct {
+	// BaseURL is the API's address, e.g. http://localhost:8080
+	BaseURL string
+	// APIKey authenticates every request; use a test-mode key
+	APIKey string
+	// Rate is how many flows start per second
+	Rate int
+	// Duration is how long flows keep starting
+	Duration time.Duration
+	// MaxInFlight caps the flows running at once; a flow due while the cap
+	// is reached is dropped and counted, which shows the API falling behind
+	MaxInFlight int
+	// Client sends the requests; nil uses a client with a 30 second timeout
+	Client *http.Client
+}
+
+// StepStats are the outcomes of one step across a run
+type StepStats struct {
+	Requests int
+	Errors   int
+	// latencies of the successful requests, sorted once the run is over
+	latencies []time.Duration
+}
+
+// Percentile returns the latency below which p percent of the successful
+// requests finished
+func (s *StepStats) Percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	i := int(float64(len(s.latencies)-1) * p / 100)
+	return s.latencies[i]
+}
+
+// Report is the outcome of a run
+type Report struct {
+	Started   int
+	Completed int
+	Dropped   int
+	Elapsed   time.Duration
+	Steps     map[Step]*StepStats
+	// Errors counts failures by step and reason
+	Errors map[string]int
+}
+
+// Throughput returns the completed flows per second
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Completed) / r.Elapsed.Seconds()
+}
+
+// String renders the report as a table
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "flows: %d started, %d completed, %d dropped in %s (%.1f/s)\n",
+		r.Started, r.Completed, r.Dropped, r.Elapsed.Round(time.Millisecond), r.Throughput())
+	fmt.Fprintf(&b, "%-8s %8s %7s %9s %9s %9s %9s\n", "step", "requests", "errors", "p50", "p90", "p95", "p99")
+	for _, step := range Steps {
+		s := r.Steps[step]
+		fmt.Fprintf(&b, "%-8s %8d %7d %9s %9s %9s %9s\n", step, s.Requests, s.Errors,
+			s.Percentile(50).Round(time.Microsecond), s.Percentile(90).Round(time.Microsecond),
+			s.Percentile(95).Round(time.Microsecond), s.Percentile(99).Round(time.Microsecond))
+	}
+	reasons := make([]string, 0, len(r.Errors))
+	for reason := range r.Errors {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(&b, "error %s: %d\n", reason, r.Errors[reason])
+	}
+	return b.String()
+}
+
+// Run starts cfg.Rate flows a second for cfg.Duration, waits for the flows
+// still running and reports on them. It stops early when ctx is done.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.BaseURL == "" || cfg.APIKey == "" {
+		return nil, fmt.Errorf("a base URL and an API key are required")
+	}
+	if cfg.Rate < 1 || cfg.Duration <= 0 || cfg.MaxInFlight < 1 {
+		return nil, fmt.Errorf("rate, duration and max in flight must be positive")
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	d := &driver{
+		cfg:    cfg,
+		client: client,
+		report: &Report{Steps: make(map[Step]*StepStats), Errors: make(map[string]int)},
+		slots:  make(chan struct{}, cfg.MaxInFlight),
+	}
+	for _, step := range Steps {
+		d.report.Steps[step] = &StepStats{}
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.Rate))
+	defer ticker.Stop()
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+
+	var wg sync.WaitGroup
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case d.slots <- struct{}{}:
+			default:
+				d.mu.Lock()
+				d.report.Dropped++
+				d.mu.Unlock()
+				continue
+			}
+			d.mu.Lock()
+			d.report.Started++
+			d.mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-d.slots }()
+				d.flow(ctx)
+			}()
+		case <-deadline.C:
+			break loop
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	wg.Wait()
+
+	d.report.Elapsed = time.Since(start)
+	for _, s := range d.report.Steps {
+		sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	}
+	return d.report, nil
+}
+
+// driver runs the flows of one Run
+type driver struct {
+	cfg    Config
+	client *http.Client
+	slots  chan struct{}
+
+	mu     sync.Mutex
+	report *Report
+}
+
+// flow runs one create, process and refund, stopping at the first failure
+func (d *driver) flow(ctx context.Context) {
+	var created struct {
+		TransactionID string `json:"transaction_id"`
+	}
+	ok := d.step(ctx, StepCreate, "/api/v1/transactions", map[string]interface{}{
+		"idempotency_key": "load-" + uuid.NewString(),
+		"amount":          "10.00",
+		"currency":        "USD",
+		"payment_method":  "card",
+		"provider":        "stripe",
+		"customer_email":  "load@example.com",
+		"description":     "Load test",
+	}, &created)
+	if !ok {
+		return
+	}
+
+	path := "/api/v1/transactions/" + created.TransactionID
+	if !d.step(ctx, StepProcess, path+"/process", nil, nil) {
+		return
+	}
+	if !d.step(ctx, StepRefund, path+"/refund", map[string]interface{}{
+		"amount":   "5.00",
+		"currency": "USD",
+		"reason":   "requested_by_customer",
+	}, nil) {
+		return
+	}
+
+	d.mu.Lock()
+	d.report.Completed++
+	d.mu.Unlock()
+}
+
+// step POSTs body to path, decodes a 2xx response into out and records the
+// outcome, reporting whether the request succeeded
+func (d *driver) step(ctx context.Context, step Step, path string, body, out interface{}) bool {
+	latency, err := d.post(ctx, path, body, out)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := d.report.Steps[step]
+	stats.Requests++
+	if err != nil {
+		stats.Errors++
+		d.report.Errors[string(step)+": "+err.Error()]++
+		return false
+	}
+	stats.latencies = append(stats.latencies, latency)
+	return true
+}
+
+func (d *driver) post(ctx context.Context, path string, body, out interface{}) (time.Duration, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.cfg.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+d.cfg.APIKey)
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("canceled")
+		}
+		return 0, fmt.Errorf("request failed")
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	latency := time.Since(start)
+	if err != nil {
+		return 0, fmt.Errorf("reading the response failed")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		return 0, fmt.Errorf("%d %s", resp.StatusCode, apiErr.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return 0, fmt.Errorf("invalid response")
+		}
+	}
+	return latency, nil
+}
@@ -0,0 +1,152 @@
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLoad runs flows against the instance at PAY2GO_LOAD_URL and fails when
+// the p95 of a step or the share of failed flows crosses its threshold.
+// Without PAY2GO_LOAD_URL it is skipped, so go test ./... stays offline.
+//
+//	PAY2GO_LOAD_URL          the API, e.g. http://localhost:8080
+//	PAY2GO_LOAD_API_KEY      a test-mode key with the payments and refunds scopes
+//	PAY2GO_LOAD_RATE         flows started per second (default 20)
+//	PAY2GO_LOAD_DURATION     how long to start flows (default 30s)
+//	PAY2GO_LOAD_MAX_IN_FLIGHT flows running at once (default 200)
+//	PAY2GO_LOAD_MAX_P95_MS   fail above this p95 for any step (default 0, off)
+//	PAY2GO_LOAD_MAX_ERROR_RATE fail above this share of failed flows (default 0.01)
+func TestLoad(t *testing.T) {
+	baseURL := os.Getenv("PAY2GO_LOAD_URL")
+	if baseURL == "" {
+		t.Skip("PAY2GO_LOAD_URL is not set")
+	}
+	duration, err := time.ParseDuration(envOr("PAY2GO_LOAD_DURATION", "30s"))
+	if err != nil {
+		t.Fatalf("invalid PAY2GO_LOAD_DURATION: %v", err)
+	}
+	cfg := Config{
+		BaseURL:     strings.TrimRight(baseURL, "/"),
+		APIKey:      os.Getenv("PAY2GO_LOAD_API_KEY"),
+		Rate:        envInt(t, "PAY2GO_LOAD_RATE", 20),
+		Duration:    duration,
+		MaxInFlight: envInt(t, "PAY2GO_LOAD_MAX_IN_FLIGHT", 200),
+	}
+	maxP95 := time.Duration(envInt(t, "PAY2GO_LOAD_MAX_P95_MS", 0)) * time.Millisecond
+	maxErrorRate, err := strconv.ParseFloat(envOr("PAY2GO_LOAD_MAX_ERROR_RATE", "0.01"), 64)
+	if err != nil {
+		t.Fatalf("invalid PAY2GO_LOAD_MAX_ERROR_RATE: %v", err)
+	}
+
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	t.Logf("\n%s", report)
+
+	if report.Started == 0 {
+		t.Fatal("no flow started")
+	}
+	if rate := float64(report.Started-report.Completed) / float64(report.Started); rate > maxErrorRate {
+		t.Errorf("%.1f%% of flows failed, want at most %.1f%%", rate*100, maxErrorRate*100)
+	}
+	if maxP95 > 0 {
+		for _, step := range Steps {
+			if p95 := report.Steps[step].Percentile(95); p95 > maxP95 {
+				t.Errorf("%s p95 = %s, want at most %s", step, p95, maxP95)
+			}
+		}
+	}
+}
+
+func TestRun_AgainstFakeAPI(t *testing.T) {
+	var processed, refunds int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/api/v1/transactions":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["idempotency_key"] == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"transaction_id":"txn-1"}`))
+		case r.URL.Path == "/api/v1/transactions/txn-1/process":
+			atomic.AddInt32(&processed, 1)
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/api/v1/transactions/txn-1/refund":
+			// every other refund fails, as an over-refund would
+			if atomic.AddInt32(&refunds, 1)%2 == 0 {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error":"refund exceeds the refundable amount"}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	report, err := Run(context.Background(), Config{
+		BaseURL:     server.URL,
+		APIKey:      "sk_test",
+		Rate:        100,
+		Duration:    200 * time.Millisecond,
+		MaxInFlight: 10,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if report.Started == 0 || report.Started != report.Steps[StepCreate].Requests {
+		t.Fatalf("started %d flows with %d creates", report.Started, report.Steps[StepCreate].Requests)
+	}
+	if got := report.Steps[StepProcess].Requests; got != report.Started || int32(got) != atomic.LoadInt32(&processed) {
+		t.Errorf("process requests = %d, want %d", got, report.Started)
+	}
+	refund := report.Steps[StepRefund]
+	if refund.Errors != refund.Requests/2 || report.Completed != refund.Requests-refund.Errors {
+		t.Errorf("refunds = %d with %d errors, completed %d", refund.Requests, refund.Errors, report.Completed)
+	}
+	if report.Errors["refund: 409 refund exceeds the refundable amount"] != refund.Errors {
+		t.Errorf("Errors = %v", report.Errors)
+	}
+	if report.Steps[StepCreate].Percentile(99) <= 0 {
+		t.Error("create p99 is zero")
+	}
+}
+
+func TestRun_RejectsIncompleteConfig(t *testing.T) {
+	if _, err := Run(context.Background(), Config{BaseURL: "http://localhost", APIKey: "sk_test"}); err == nil {
+		t.Error("Run() without a rate succeeded")
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func envInt(t *testing.T, key string, fallback int) int {
+	value, err := strconv.Atoi(envOr(key, strconv.Itoa(fallback)))
+	if err != nil {
+		t.Fatalf("invalid %s: %v", key, err)
+	}
+	return value
+}
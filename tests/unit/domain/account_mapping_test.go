@@ -0,0 +1,34 @@
+package domain_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestNewAccountMapping_Defaults(t *testing.T) {
+	mapping, err := valueobjects.NewAccountMapping(valueobjects.AccountMapping{SalesAccount: " 4000 "})
+	if err != nil {
+		t.Fatalf("NewAccountMapping() error: %v", err)
+	}
+	want := valueobjects.DefaultAccountMapping()
+	want.SalesAccount = "4000"
+	if mapping != want {
+		t.Errorf("NewAccountMapping() = %+v, want %+v", mapping, want)
+	}
+}
+
+func TestNewAccountMapping_Invalid(t *testing.T) {
+	tests := map[string]valueobjects.AccountMapping{
+		"tab in a name":      {SalesAccount: "Sales\tIncome"},
+		"formula sign":       {FeesAccount: "=SUM(A1)"},
+		"unknown date order": {DateFormat: "yyyy-mm-dd"},
+	}
+	for name, mapping := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := valueobjects.NewAccountMapping(mapping); err == nil {
+				t.Error("NewAccountMapping() error = nil, want a validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package domain_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+)
+
+const adminPassword = "correct horse battery"
+
+// rfc6238Secret is the SHA-1 key of the RFC 6238 test vectors
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func newAdminUser(t *testing.T) *entities.AdminUser {
+	t.Helper()
+	admin, err := entities.NewAdminUser("ops@pay2go.example", "Ops", adminPassword)
+	if err != nil {
+		t.Fatalf("NewAdminUser() error: %v", err)
+	}
+	return admin
+}
+
+// totp computes the 6 digit code of a base32 secret at a time, as an
+// authenticator app would
+func totp(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decoding the secret error: %v", err)
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:])&0x7fffffff)%1000000)
+}
+
+func TestAdminUser_TOTPTestVectors(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, code := range vectors {
+		t.Run(fmt.Sprint(unix), func(t *testing.T) {
+			admin := newAdminUser(t)
+			admin.TOTPSecret = rfc6238Secret
+			if err := admin.Authenticate(adminPassword, code, time.Unix(unix, 0)); err != nil {
+				t.Errorf("Authenticate(%s) error: %v", code, err)
+			}
+		})
+	}
+}
+
+func TestAdminUser_Authenticate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	step := 30 * time.Second
+
+	tests := map[string]struct {
+		password string
+		codeAt   time.Time
+		wantErr  bool
+	}{
+		"current code":          {adminPassword, now, false},
+		"previous step":         {adminPassword, now.Add(-step), false},
+		"next step":             {adminPassword, now.Add(step), false},
+		"two steps ago":         {adminPassword, now.Add(-2 * step), true},
+		"two steps ahead":       {adminPassword, now.Add(2 * step), true},
+		"wrong password":        {adminPassword + "!", now, true},
+		"password without code": {adminPassword, time.Time{}, true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			admin := newAdminUser(t)
+			code := ""
+			if !tc.codeAt.IsZero() {
+				code = totp(t, admin.TOTPSecret, tc.codeAt)
+			}
+			err := admin.Authenticate(tc.password, code, now)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Authenticate() error = %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil && !stderrors.Is(err, errors.ErrInvalidAdminCredentials) {
+				t.Errorf("Authenticate() error = %v, want ErrInvalidAdminCredentials", err)
+			}
+		})
+	}
+}
+
+func TestAdminUser_TOTPCodesAreSingleUse(t *testing.T) {
+	admin := newAdminUser(t)
+	now := time.Unix(1700000000, 0)
+	code := totp(t, admin.TOTPSecret, now)
+
+	if err := admin.Authenticate(adminPassword, code, now); err != nil {
+		t.Fatalf("Authenticate() error: %v", err)
+	}
+	if err := admin.Authenticate(adminPassword, code, now.Add(10*time.Second)); !stderrors.Is(err, errors.ErrInvalidAdminCredentials) {
+		t.Errorf("Authenticate() with the same code again error = %v, want ErrInvalidAdminCredentials", err)
+	}
+
+	// Nor can a code from before the last accepted one be used, though it
+	// is still within the skew
+	earlier := totp(t, admin.TOTPSecret, now.Add(-30*time.Second))
+	if err := admin.Authenticate(adminPassword, earlier, now); !stderrors.Is(err, errors.ErrInvalidAdminCredentials) {
+		t.Errorf("Authenticate() with an earlier code error = %v, want ErrInvalidAdminCredentials", err)
+	}
+
+	later := now.Add(30 * time.Second)
+	if err := admin.Authenticate(adminPassword, totp(t, admin.TOTPSecret, later), later); err != nil {
+		t.Errorf("Authenticate() with the next code error: %v", err)
+	}
+}
+
+func TestAdminUser_Disabled(t *testing.T) {
+	admin := newAdminUser(t)
+	now := time.Now()
+	admin.Disable()
+
+	if err := admin.Authenticate(adminPassword, totp(t, admin.TOTPSecret, now), now); !stderrors.Is(err, errors.ErrInvalidAdminCredentials) {
+		t.Errorf("Authenticate() of a disabled admin error = %v, want ErrInvalidAdminCredentials", err)
+	}
+}
+
+func TestAdminUser_TOTPProvisioningURI(t *testing.T) {
+	admin := newAdminUser(t)
+	uri, err := url.Parse(admin.TOTPProvisioningURI("Pay2Go"))
+	if err != nil {
+		t.Fatalf("parsing the URI error: %v", err)
+	}
+
+	query := uri.Query()
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Pay2Go:ops@pay2go.example" {
+		t.Errorf("URI = %s, want otpauth://totp/Pay2Go:ops@pay2go.example", uri)
+	}
+	if query.Get("secret") != admin.TOTPSecret || query.Get("digits") != "6" || query.Get("period") != "30" || query.Get("algorithm") != "SHA1" {
+		t.Errorf("URI query = %v, want the secret with 6 digits every 30 seconds over SHA1", query)
+	}
+}
@@ -0,0 +1,149 @@
+package domain_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestParseAmountLimits(t *testing.T) {
+	// Act
+	limits, err := valueobjects.ParseAmountLimits(map[string]string{
+		"USD": "250000",
+		"JPY": "15000000",
+		"BHD": "1000.5",
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ParseAmountLimits: %v", err)
+	}
+	want := valueobjects.AmountLimits{
+		valueobjects.USD: 25000000,
+		valueobjects.JPY: 15000000,
+		"BHD":            1000500,
+	}
+	for currency, limit := range want {
+		if got, _ := limits.Limit(currency); got != limit {
+			t.Errorf("Limit(%s) = %d, want %d", currency, got, limit)
+		}
+	}
+	if got := limits.Decimals()["USD"]; got != "250000.00" {
+		t.Errorf("Decimals()[USD] = %q, want %q", got, "250000.00")
+	}
+}
+
+func TestParseAmountLimits_Invalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"Unknown currency":  {"XXX": "100"},
+		"Fraction of a yen": {"JPY": "100.5"},
+		"Zero limit":        {"USD": "0"},
+		"Not a number":      {"USD": "lots"},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := valueobjects.ParseAmountLimits(input); err == nil {
+				t.Errorf("ParseAmountLimits(%v): expected error, got nil", input)
+			}
+		})
+	}
+}
+
+func TestPartner_MaxAmount(t *testing.T) {
+	// Arrange
+	partner, _ := entities.NewPartner("Acme", "billing@acme.test")
+	if err := partner.SetAmountLimits(map[string]string{"USD": "500"}); err != nil {
+		t.Fatalf("SetAmountLimits: %v", err)
+	}
+	platform := valueobjects.AmountLimits{valueobjects.USD: 1000000, valueobjects.JPY: 500000}
+
+	tests := []struct {
+		currency  valueobjects.Currency
+		wantLimit int64
+		wantScope string
+	}{
+		{currency: valueobjects.USD, wantLimit: 50000, wantScope: errors.AmountLimitScopePartner},
+		{currency: valueobjects.JPY, wantLimit: 500000, wantScope: errors.AmountLimitScopePlatform},
+		{currency: valueobjects.EUR, wantLimit: 10000000, wantScope: errors.AmountLimitScopePlatform},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency.String(), func(t *testing.T) {
+			limit, scope := partner.MaxAmount(tt.currency, platform)
+			if limit != tt.wantLimit || scope != tt.wantScope {
+				t.Errorf("MaxAmount(%s) = %d (%s), want %d (%s)",
+					tt.currency, limit, scope, tt.wantLimit, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestPartner_CheckAmountLimit(t *testing.T) {
+	// Arrange
+	partner, _ := entities.NewPartner("Acme", "billing@acme.test")
+	_ = partner.SetAmountLimits(map[string]string{"USD": "500"})
+	atLimit, _ := valueobjects.ParseMoney("500.00", "USD")
+	overLimit, _ := valueobjects.ParseMoney("500.01", "USD")
+
+	// Act
+	okErr := partner.CheckAmountLimit(atLimit, nil)
+	err := partner.CheckAmountLimit(overLimit, nil)
+
+	// Assert
+	if okErr != nil {
+		t.Errorf("Expected amount at the limit to pass, got %v", okErr)
+	}
+	if !stderrors.Is(err, errors.ErrAmountAboveMaximum) {
+		t.Fatalf("Expected ErrAmountAboveMaximum, got %v", err)
+	}
+	var limitErr *errors.AmountLimitError
+	if !stderrors.As(err, &limitErr) {
+		t.Fatalf("Expected *AmountLimitError, got %T", err)
+	}
+	if limitErr.Currency != "USD" || limitErr.Limit != "500.00" ||
+		limitErr.Amount != "500.01" || limitErr.Scope != errors.AmountLimitScopePartner {
+		t.Errorf("AmountLimitError = %+v", limitErr)
+	}
+}
+
+func TestPartner_RequiresRefundApproval(t *testing.T) {
+	// Arrange: USD 1,000 and JPY 100,000 are held; no other currency is
+	partner, _ := entities.NewPartner("Acme", "billing@acme.test")
+	if err := partner.SetRefundApprovalThresholds(map[string]string{"USD": "1000", "JPY": "100000", "KWD": "250.5"}); err != nil {
+		t.Fatalf("SetRefundApprovalThresholds: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		refund valueobjects.Money
+		want   bool
+	}{
+		{"USD at the threshold", valueobjects.Money{Amount: 100000, Currency: valueobjects.USD}, false},
+		{"USD above the threshold", valueobjects.Money{Amount: 100001, Currency: valueobjects.USD}, true},
+		{"JPY of the same minor units", valueobjects.Money{Amount: 100001, Currency: valueobjects.JPY}, true},
+		{"JPY below its own threshold", valueobjects.Money{Amount: 99999, Currency: valueobjects.JPY}, false},
+		{"KWD in fils", valueobjects.Money{Amount: 250501, Currency: "KWD"}, true},
+		{"Currency without a threshold", valueobjects.Money{Amount: 100000000, Currency: valueobjects.EUR}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := partner.RequiresRefundApproval(tt.refund); got != tt.want {
+				t.Errorf("RequiresRefundApproval(%d %s) = %v, want %v", tt.refund.Amount, tt.refund.Currency, got, tt.want)
+			}
+		})
+	}
+
+	// An unknown currency is rejected and an empty map disables approval
+	if err := partner.SetRefundApprovalThresholds(map[string]string{"XXX": "1"}); err == nil {
+		t.Error("SetRefundApprovalThresholds(XXX): expected error, got nil")
+	}
+	_ = partner.SetRefundApprovalThresholds(nil)
+	if partner.RequiresRefundApproval(valueobjects.Money{Amount: 100000000, Currency: valueobjects.USD}) {
+		t.Error("RequiresRefundApproval() without thresholds = true, want false")
+	}
+}
@@ -0,0 +1,181 @@
+package domain_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func newAPIKey(t *testing.T, method entities.APIKeyAuthMethod) (*entities.APIKey, string) {
+	t.Helper()
+	key, plaintext, err := entities.NewAPIKey(uuid.New(), "ci", []valueobjects.APIKeyScope{valueobjects.ScopePayments}, method, true)
+	if err != nil {
+		t.Fatalf("NewAPIKey() error: %v", err)
+	}
+	return key, plaintext
+}
+
+func TestAPIKey_Argon2idHash(t *testing.T) {
+	key, plaintext := newAPIKey(t, entities.AuthMethodBearer)
+
+	if !strings.HasPrefix(key.KeyHash, "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("KeyHash = %q, want an argon2id hash with the current parameters", key.KeyHash)
+	}
+	if strings.Contains(key.KeyHash, plaintext) || key.KeyPrefix != plaintext[:8] {
+		t.Errorf("KeyHash = %q, KeyPrefix = %q; want the key hashed and its first 8 characters as prefix", key.KeyHash, key.KeyPrefix)
+	}
+	if key.NeedsRehash() {
+		t.Error("NeedsRehash() = true for a new key")
+	}
+	if err := key.Validate(plaintext); err != nil {
+		t.Errorf("Validate(plaintext) error: %v", err)
+	}
+
+	// The same key hashes differently each time, with a fresh salt
+	again := *key
+	if err := again.Rehash(plaintext); err != nil {
+		t.Fatalf("Rehash() error: %v", err)
+	}
+	if again.KeyHash == key.KeyHash {
+		t.Error("Rehash() kept the hash, want a new salt")
+	}
+
+	tests := map[string]string{
+		"wrong key":       plaintext[:len(plaintext)-1] + "x",
+		"empty key":       "",
+		"key with suffix": plaintext + "x",
+	}
+	for name, attempt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := key.Validate(attempt); !stderrors.Is(err, errors.ErrInvalidAPIKey) {
+				t.Errorf("Validate() error = %v, want ErrInvalidAPIKey", err)
+			}
+		})
+	}
+}
+
+func TestAPIKey_MalformedHash(t *testing.T) {
+	key, plaintext := newAPIKey(t, entities.AuthMethodBearer)
+	parts := strings.Split(key.KeyHash, "$")
+
+	tests := map[string]string{
+		"other version":      strings.Replace(key.KeyHash, "v=19", "v=16", 1),
+		"bad parameters":     strings.Replace(key.KeyHash, "m=19456", "m=x", 1),
+		"bad salt":           strings.Join(append(append([]string{}, parts[:4]...), "!!", parts[5]), "$"),
+		"missing hash":       strings.Join(parts[:5], "$"),
+		"altered parameters": strings.Replace(key.KeyHash, "t=2", "t=3", 1),
+		"not a hash":         "plaintext",
+	}
+	for name, hash := range tests {
+		t.Run(name, func(t *testing.T) {
+			broken := *key
+			broken.KeyHash = hash
+			if err := broken.Validate(plaintext); !stderrors.Is(err, errors.ErrInvalidAPIKey) {
+				t.Errorf("Validate() error = %v, want ErrInvalidAPIKey", err)
+			}
+		})
+	}
+}
+
+func TestAPIKey_LegacyHashes(t *testing.T) {
+	key, plaintext := newAPIKey(t, entities.AuthMethodBearer)
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(plaintext), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error: %v", err)
+	}
+	salt := []byte("0123456789abcdef")
+	weaker := argon2.IDKey([]byte(plaintext), salt, 1, 8*1024, 1, 32)
+
+	tests := map[string]string{
+		"bcrypt": string(bcryptHash),
+		"argon2id with older parameters": "$argon2id$v=19$m=8192,t=1,p=1$" +
+			base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(weaker),
+	}
+	for name, hash := range tests {
+		t.Run(name, func(t *testing.T) {
+			legacy := *key
+			legacy.KeyHash = hash
+
+			if err := legacy.Validate(plaintext); err != nil {
+				t.Fatalf("Validate() error: %v", err)
+			}
+			if err := legacy.Validate(plaintext + "x"); !stderrors.Is(err, errors.ErrInvalidAPIKey) {
+				t.Errorf("Validate(wrong key) error = %v, want ErrInvalidAPIKey", err)
+			}
+			if !legacy.NeedsRehash() {
+				t.Fatal("NeedsRehash() = false, want true")
+			}
+
+			if err := legacy.Rehash(plaintext); err != nil {
+				t.Fatalf("Rehash() error: %v", err)
+			}
+			if legacy.NeedsRehash() || !strings.HasPrefix(legacy.KeyHash, "$argon2id$v=19$m=19456,t=2,p=1$") {
+				t.Errorf("KeyHash after Rehash() = %q, want the current argon2id parameters", legacy.KeyHash)
+			}
+			if err := legacy.Validate(plaintext); err != nil {
+				t.Errorf("Validate() after Rehash() error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAPIKey_VerifySignature(t *testing.T) {
+	key, secret := newAPIKey(t, entities.AuthMethodHMAC)
+	message := "1700000000\nPOST\n/api/v1/payments\n{}"
+	sign := func(secret, message string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(message))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	if err := key.VerifySignature(message, sign(secret, message)); err != nil {
+		t.Errorf("VerifySignature() error: %v", err)
+	}
+	tests := map[string]struct {
+		message, signature string
+	}{
+		"other message":    {message + " ", sign(secret, message)},
+		"other secret":     {message, sign(secret+"x", message)},
+		"not hex":          {message, "not-a-signature"},
+		"upper case hex":   {message, strings.ToUpper(sign(secret, message))},
+		"truncated":        {message, sign(secret, message)[:32]},
+		"empty":            {message, ""},
+		"plaintext secret": {message, secret},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := key.VerifySignature(tc.message, tc.signature); !stderrors.Is(err, errors.ErrInvalidSignature) {
+				t.Errorf("VerifySignature() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	// An HMAC key is never accepted as a bearer token, nor a bearer key as a signer
+	if err := key.Validate(secret); !stderrors.Is(err, errors.ErrAuthMethod) {
+		t.Errorf("Validate() of an HMAC key error = %v, want ErrAuthMethod", err)
+	}
+	bearer, bearerSecret := newAPIKey(t, entities.AuthMethodBearer)
+	if err := bearer.VerifySignature(message, sign(bearerSecret, message)); !stderrors.Is(err, errors.ErrAuthMethod) {
+		t.Errorf("VerifySignature() of a bearer key error = %v, want ErrAuthMethod", err)
+	}
+
+	if err := key.Revoke(); err != nil {
+		t.Fatalf("Revoke() error: %v", err)
+	}
+	if err := key.VerifySignature(message, sign(secret, message)); !stderrors.Is(err, errors.ErrAPIKeyRevoked) {
+		t.Errorf("VerifySignature() of a revoked key error = %v, want ErrAPIKeyRevoked", err)
+	}
+}
@@ -0,0 +1,77 @@
+package domain_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestBIN_Brand(t *testing.T) {
+	tests := []struct {
+		number string
+		want   valueobjects.CardBrand
+	}{
+		{number: "4242424242424242", want: valueobjects.CardBrandVisa},
+		{number: "5555 5555 5555 4444", want: valueobjects.CardBrandMastercard},
+		{number: "222300", want: valueobjects.CardBrandMastercard},
+		{number: "378282", want: valueobjects.CardBrandAmex},
+		{number: "601111", want: valueobjects.CardBrandDiscover},
+		{number: "356600", want: valueobjects.CardBrandJCB},
+		{number: "620000", want: valueobjects.CardBrandUnionPay},
+		{number: "305693", want: valueobjects.CardBrandDiners},
+		{number: "900000", want: valueobjects.CardBrandUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			bin, err := valueobjects.NewBIN(tt.number)
+			if err != nil {
+				t.Fatalf("NewBIN(%q): %v", tt.number, err)
+			}
+			if got := bin.Brand(); got != tt.want {
+				t.Errorf("Brand() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBIN(t *testing.T) {
+	// A full card number is cut to its first 8 digits
+	bin, err := valueobjects.NewBIN("4000 0576 0000 0008")
+	if err != nil {
+		t.Fatalf("NewBIN: %v", err)
+	}
+	if bin != "40000576" {
+		t.Errorf("NewBIN = %q, want %q", bin, "40000576")
+	}
+
+	for _, invalid := range []string{"42424", "4242ab", "", "42424242424242424242"} {
+		if _, err := valueobjects.NewBIN(invalid); err == nil {
+			t.Errorf("NewBIN(%q): expected error, got nil", invalid)
+		}
+	}
+}
+
+func TestCardDetails_WithBINInfo(t *testing.T) {
+	// Arrange
+	card, _ := valueobjects.NewCardDetails("unknown", "0008", 12, 2030, "")
+	bin, _ := valueobjects.NewBIN("40007640")
+	info := valueobjects.BINInfo{
+		Prefix:        bin,
+		Brand:         valueobjects.CardBrandVisa,
+		IssuerCountry: valueobjects.CountryTH,
+		Funding:       valueobjects.CardFundingDebit,
+	}
+
+	// Act
+	enriched := card.WithBINInfo(bin, info)
+
+	// Assert
+	if enriched.Brand != valueobjects.CardBrandVisa || enriched.IssuerCountry != "TH" ||
+		enriched.Funding != valueobjects.CardFundingDebit || enriched.BIN != bin {
+		t.Errorf("WithBINInfo = %+v", enriched)
+	}
+	if card.Brand != valueobjects.CardBrandUnknown {
+		t.Error("WithBINInfo must not modify the original card")
+	}
+}
@@ -0,0 +1,76 @@
+package domain_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestNewCountry(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    valueobjects.Country
+		wantErr bool
+	}{
+		{input: "TH", want: valueobjects.CountryTH},
+		{input: " us ", want: valueobjects.CountryUS},
+		{input: "DEU", want: valueobjects.CountryDE},
+		{input: "UK", wantErr: true},
+		{input: "ZZ", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := valueobjects.NewCountry(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCountry(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewCountry(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if valueobjects.CountryTH.Alpha3() != "THA" || valueobjects.CountryTH.NumericCode() != 764 {
+		t.Errorf("TH attributes = %s %d", valueobjects.CountryTH.Alpha3(), valueobjects.CountryTH.NumericCode())
+	}
+	if !valueobjects.CountryDE.IsEEA() || valueobjects.CountryGB.IsEEA() {
+		t.Error("IsEEA: DE is in the EEA, GB is not")
+	}
+}
+
+func TestNewLocale(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    valueobjects.Locale
+		wantErr bool
+	}{
+		{input: "en-US", want: "en-US"},
+		{input: "th_th", want: "th-TH"},
+		{input: "ZH-hant-tw", want: "zh-Hant-TW"},
+		{input: "es-419", want: "es-419"},
+		{input: "fr", want: "fr"},
+		{input: "xx-US", wantErr: true},
+		{input: "en-ZZ", wantErr: true},
+		{input: "en-US-extra", wantErr: true},
+		{input: "english", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := valueobjects.NewLocale(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewLocale(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewLocale(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	locale, _ := valueobjects.NewLocale("zh-Hant-TW")
+	if locale.Language() != "zh" || locale.Country() != "TW" {
+		t.Errorf("Language/Country = %s/%s, want zh/TW", locale.Language(), locale.Country())
+	}
+}
@@ -0,0 +1,125 @@
+package domain_test
+
+import (
+	stderrors "errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestCurrency_Registry(t *testing.T) {
+	tests := []struct {
+		code     string
+		want     valueobjects.Currency
+		exponent int
+		numeric  int
+		symbol   string
+	}{
+		{"USD", valueobjects.USD, 2, 840, "$"},
+		{"eur", valueobjects.EUR, 2, 978, "€"},
+		{" jpy ", valueobjects.JPY, 0, 392, "¥"},
+		{"KRW", "KRW", 0, 410, "₩"},
+		{"KWD", "KWD", 3, 414, "KD"},
+		{"BHD", "BHD", 3, 48, ".د.ب"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			currency, err := valueobjects.NewCurrency(tt.code)
+			if err != nil {
+				t.Fatalf("NewCurrency(%q) error: %v", tt.code, err)
+			}
+			if currency != tt.want || !currency.IsValid() {
+				t.Errorf("NewCurrency(%q) = %q, want %q", tt.code, currency, tt.want)
+			}
+			if got := currency.Exponent(); got != tt.exponent {
+				t.Errorf("Exponent() = %d, want %d", got, tt.exponent)
+			}
+			if got := currency.NumericCode(); got != tt.numeric {
+				t.Errorf("NumericCode() = %d, want %d", got, tt.numeric)
+			}
+			if got := currency.Symbol(); got != tt.symbol {
+				t.Errorf("Symbol() = %q, want %q", got, tt.symbol)
+			}
+			if currency.Name() == "" {
+				t.Error("Name() is empty")
+			}
+		})
+	}
+}
+
+func TestCurrency_UnknownCodes(t *testing.T) {
+	// Codes that are not ISO 4217, and those deliberately left out of the
+	// registry, are refused
+	for _, code := range []string{"", "US", "USDT", "ABC", "XTS", "XAU", "CLF"} {
+		if currency, err := valueobjects.NewCurrency(code); !stderrors.Is(err, errors.ErrInvalidCurrency) {
+			t.Errorf("NewCurrency(%q) = %q, %v; want ErrInvalidCurrency", code, currency, err)
+		}
+		if _, err := valueobjects.NewMoney(100, code); !stderrors.Is(err, errors.ErrInvalidCurrency) {
+			t.Errorf("NewMoney(100, %q) error = %v, want ErrInvalidCurrency", code, err)
+		}
+	}
+
+	// An unknown currency read from storage keeps working with defaults
+	unknown := valueobjects.Currency("ABC")
+	if unknown.IsValid() || unknown.Exponent() != 2 || unknown.NumericCode() != 0 || unknown.Symbol() != "ABC" || unknown.Name() != "" {
+		t.Errorf("unknown currency = valid %v, exponent %d, numeric %d, symbol %q, name %q; want the defaults",
+			unknown.IsValid(), unknown.Exponent(), unknown.NumericCode(), unknown.Symbol(), unknown.Name())
+	}
+}
+
+func TestCurrencies_Sorted(t *testing.T) {
+	currencies := valueobjects.Currencies()
+	if !sort.SliceIsSorted(currencies, func(i, j int) bool { return currencies[i] < currencies[j] }) {
+		t.Error("Currencies() is not sorted by code")
+	}
+	for _, currency := range currencies {
+		if !currency.IsValid() {
+			t.Errorf("Currencies() lists %q, which is not valid", currency)
+		}
+	}
+	if len(currencies) < 150 {
+		t.Errorf("Currencies() lists %d currencies, want every circulating one", len(currencies))
+	}
+}
+
+func TestPartner_AllowedCurrencies(t *testing.T) {
+	partner, err := entities.NewPartner("Acme", "acme@example.com")
+	if err != nil {
+		t.Fatalf("NewPartner() error: %v", err)
+	}
+
+	// Without a restriction every currency is accepted
+	if !partner.AcceptsCurrency(valueobjects.JPY) {
+		t.Error("unrestricted partner refuses JPY")
+	}
+
+	// Codes are normalized and kept once each
+	if err := partner.SetAllowedCurrencies([]string{"usd", "EUR", " USD "}); err != nil {
+		t.Fatalf("SetAllowedCurrencies() error: %v", err)
+	}
+	if want := []valueobjects.Currency{valueobjects.USD, valueobjects.EUR}; !reflect.DeepEqual(partner.AllowedCurrencies, want) {
+		t.Errorf("AllowedCurrencies = %v, want %v", partner.AllowedCurrencies, want)
+	}
+	if !partner.AcceptsCurrency(valueobjects.EUR) || partner.AcceptsCurrency(valueobjects.JPY) {
+		t.Error("restricted partner accepts the wrong currencies")
+	}
+
+	// An unknown code refuses the whole list
+	err = partner.SetAllowedCurrencies([]string{"JPY", "ABC"})
+	var domainErr *errors.DomainError
+	if !stderrors.As(err, &domainErr) || domainErr.Code != errors.CodeValidation || domainErr.Param != "currencies" {
+		t.Errorf("SetAllowedCurrencies(unknown) error = %v, want a currencies validation error", err)
+	}
+	if len(partner.AllowedCurrencies) != 2 {
+		t.Errorf("AllowedCurrencies = %v after a refused update, want them unchanged", partner.AllowedCurrencies)
+	}
+
+	// An empty list lifts the restriction
+	if err := partner.SetAllowedCurrencies(nil); err != nil || !partner.AcceptsCurrency(valueobjects.JPY) {
+		t.Errorf("SetAllowedCurrencies(nil) = %v, want every currency accepted", err)
+	}
+}
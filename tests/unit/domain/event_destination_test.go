@@ -0,0 +1,34 @@
+package domain_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestNewEventDestination_ValidatesTargets(t *testing.T) {
+	role := "arn:aws:iam::123456789012:role/pay2go-events"
+	cases := []struct {
+		kind, target, role string
+		region             string
+	}{
+		{"sns", "arn:aws:sns:ap-southeast-1:123456789012:events", role, "ap-southeast-1"},
+		{"SQS", "https://sqs.us-east-2.amazonaws.com/123456789012/events", role, "us-east-2"},
+		{"sns", "https://sqs.us-east-2.amazonaws.com/123456789012/events", role, ""},
+		{"sqs", "https://evil.example.com/123456789012/events", role, ""},
+		{"sqs", "https://sqs.us-east-2.amazonaws.com/123456789012/events", "arn:aws:iam::123456789012:user/pay2go", ""},
+		{"kinesis", "arn:aws:sns:ap-southeast-1:123456789012:events", role, ""},
+	}
+	for _, tc := range cases {
+		destination, err := valueobjects.NewEventDestination(tc.kind, tc.target, tc.role)
+		if tc.region == "" {
+			if err == nil {
+				t.Errorf("NewEventDestination(%s, %s, %s) succeeded, want an error", tc.kind, tc.target, tc.role)
+			}
+			continue
+		}
+		if err != nil || destination.Region() != tc.region {
+			t.Errorf("NewEventDestination(%s, %s) = region %q, %v; want %q", tc.kind, tc.target, destination.Region(), err, tc.region)
+		}
+	}
+}
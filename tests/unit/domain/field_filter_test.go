@@ -0,0 +1,126 @@
+package domain_test
+
+import (
+	"reflect"
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestNewFieldFilter(t *testing.T) {
+	filter, err := valueobjects.NewFieldFilter(map[string][]string{
+		"payments":  {"error_code", " Customer.Email ", "error_code"},
+		"read_only": {},
+	}, []string{"metadata"})
+	if err != nil {
+		t.Fatalf("NewFieldFilter() error: %v", err)
+	}
+	want := valueobjects.FieldFilter{
+		Responses: map[valueobjects.APIKeyScope][]string{valueobjects.ScopePayments: {"customer.email", "error_code"}},
+		Webhooks:  []string{"metadata"},
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("NewFieldFilter() = %+v, want %+v", filter, want)
+	}
+
+	invalid := []struct {
+		name      string
+		responses map[string][]string
+		webhooks  []string
+	}{
+		{"unknown scope", map[string][]string{"owner": {"error_code"}}, nil},
+		{"empty path", nil, []string{""}},
+		{"wildcard", nil, []string{"customer.*"}},
+		{"too deep", nil, []string{"a.b.c.d.e.f"}},
+	}
+	for _, tt := range invalid {
+		if _, err := valueobjects.NewFieldFilter(tt.responses, tt.webhooks); err == nil {
+			t.Errorf("NewFieldFilter() with %s succeeded", tt.name)
+		}
+	}
+}
+
+func TestFieldFilter_HiddenFor(t *testing.T) {
+	filter, _ := valueobjects.NewFieldFilter(map[string][]string{
+		"payments": {"error_code", "provider"},
+		"refunds":  {"provider"},
+	}, nil)
+
+	tests := []struct {
+		scopes []valueobjects.APIKeyScope
+		want   []string
+	}{
+		{[]valueobjects.APIKeyScope{valueobjects.ScopePayments}, []string{"error_code", "provider"}},
+		// A key sees what any of its scopes does
+		{[]valueobjects.APIKeyScope{valueobjects.ScopePayments, valueobjects.ScopeRefunds}, []string{"provider"}},
+		{[]valueobjects.APIKeyScope{valueobjects.ScopePayments, valueobjects.ScopeAdmin}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := filter.HiddenFor(tt.scopes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("HiddenFor(%v) = %v, want %v", tt.scopes, got, tt.want)
+		}
+	}
+}
+
+func TestHideFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		paths []string
+		want  string
+	}{
+		{
+			name:  "top level, keeping order and encoding",
+			body:  `{"id":"t1","error_code":"card_declined","amount":"10.50","note":"<b>"}` + "\n",
+			paths: []string{"error_code"},
+			want:  `{"id":"t1","amount":"10.50","note":"<b>"}` + "\n",
+		},
+		{
+			name:  "any depth and in lists",
+			body:  `{"transactions":[{"id":"t1","error_code":"x"},{"id":"t2","refunds":[{"error_code":"y"}]}]}`,
+			paths: []string{"error_code"},
+			want:  `{"transactions":[{"id":"t1"},{"id":"t2","refunds":[{}]}]}`,
+		},
+		{
+			name:  "dotted path only under its parent",
+			body:  `{"customer":{"email":"a@example.com","name":"A"},"email":"kept@example.com"}`,
+			paths: []string{"customer.email"},
+			want:  `{"customer":{"name":"A"},"email":"kept@example.com"}`,
+		},
+		{
+			name:  "dotted path nested at any depth",
+			body:  `{"data":{"customer":{"email":"a@example.com"}}}`,
+			paths: []string{"customer.email"},
+			want:  `{"data":{"customer":{}}}`,
+		},
+		{
+			name:  "scalars are left alone",
+			body:  `"error_code"`,
+			paths: []string{"error_code"},
+			want:  `"error_code"`,
+		},
+		{
+			name:  "empty body",
+			body:  ``,
+			paths: []string{"error_code"},
+			want:  ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valueobjects.HideFields([]byte(tt.body), tt.paths)
+			if err != nil {
+				t.Fatalf("HideFields() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("HideFields() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := valueobjects.HideFields([]byte(`{"id":`), []string{"id"}); err == nil {
+		t.Error("HideFields() of invalid JSON succeeded")
+	}
+}
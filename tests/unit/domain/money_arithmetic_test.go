@@ -0,0 +1,309 @@
+package domain_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestMoney_Multiply(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		factor string
+		want   int64
+	}{
+		{name: "Whole factor", amount: 1000, factor: "3", want: 3000},
+		{name: "Identity", amount: 1999, factor: "1", want: 1999},
+		{name: "Zero factor", amount: 1999, factor: "0", want: 0},
+		{name: "Exact fraction", amount: 1000, factor: "0.25", want: 250},
+		{name: "Rounds down below half a cent", amount: 10, factor: "0.14", want: 1},
+		{name: "Half a cent rounds to even up", amount: 10, factor: "0.15", want: 2},   // 1.5 cents
+		{name: "Half a cent rounds to even down", amount: 10, factor: "0.25", want: 2}, // 2.5 cents
+		{name: "Rounds up above half a cent", amount: 10, factor: "0.16", want: 2},
+		{name: "Half a cent without float error", amount: 100, factor: "0.145", want: 14}, // 14.5 cents
+		{name: "Just below half a cent", amount: 100, factor: "0.144999", want: 14},
+		{name: "Just above half a cent", amount: 100, factor: "0.145001", want: 15},
+		{name: "Large amount", amount: 10000000, factor: "1.5", want: 15000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			money := valueobjects.Money{Amount: tt.amount, Currency: valueobjects.USD}
+			factor, err := valueobjects.ParseRatio(tt.factor)
+			if err != nil {
+				t.Fatalf("ParseRatio(%q): %v", tt.factor, err)
+			}
+
+			// Act
+			result, err := money.Multiply(factor)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Amount != tt.want {
+				t.Errorf("Amount = %d, want %d", result.Amount, tt.want)
+			}
+			if result.Currency != valueobjects.USD {
+				t.Errorf("Currency = %s, want USD", result.Currency)
+			}
+		})
+	}
+}
+
+func TestMoney_Multiply_Overflow(t *testing.T) {
+	money := valueobjects.Money{Amount: math.MaxInt64 / 2, Currency: valueobjects.USD}
+	factor, _ := valueobjects.NewRatio(3, 1)
+
+	if _, err := money.Multiply(factor); err == nil {
+		t.Error("Expected error for a product out of range, got nil")
+	}
+}
+
+func TestMoney_PercentOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		percent string
+		want    int64
+	}{
+		{name: "Card fee", amount: 10000, percent: "2.9", want: 290},
+		{name: "Card fee rounds up", amount: 1550, percent: "2.9", want: 45},            // 44.95 cents
+		{name: "Card fee rounds down", amount: 1050, percent: "2.9", want: 30},          // 30.45 cents
+		{name: "Half a cent rounds to even", amount: 50, percent: "1", want: 0},         // 0.5 cents
+		{name: "Under half a cent is zero", amount: 49, percent: "1", want: 0},          // 0.49 cents
+		{name: "Odd half a cent rounds up", amount: 150, percent: "1", want: 2},         // 1.5 cents
+		{name: "Half a cent without float error", amount: 145, percent: "10", want: 14}, // 14.5 cents
+		{name: "Fractional percent", amount: 123456, percent: "0.35", want: 432},        // 432.096 cents
+		{name: "Zero percent", amount: 9999, percent: "0", want: 0},
+		{name: "Whole amount", amount: 9999, percent: "100", want: 9999},
+		{name: "Over one hundred percent", amount: 8000, percent: "125", want: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			money := valueobjects.Money{Amount: tt.amount, Currency: valueobjects.EUR}
+			percent, err := valueobjects.ParseRatio(tt.percent)
+			if err != nil {
+				t.Fatalf("ParseRatio(%q): %v", tt.percent, err)
+			}
+
+			// Act
+			result, err := money.PercentOf(percent)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Amount != tt.want {
+				t.Errorf("Amount = %d, want %d", result.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_PercentOf_BasisPoints(t *testing.T) {
+	money := valueobjects.Money{Amount: 10000, Currency: valueobjects.USD}
+	rate, _ := valueobjects.BasisPoints(290)
+
+	// 290 basis points of a factor are 2.9%
+	if result, _ := money.Multiply(rate); result.Amount != 290 {
+		t.Errorf("Multiply(290 bps) = %d, want 290", result.Amount)
+	}
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		parts  int
+		want   []int64
+	}{
+		{name: "Even split", amount: 3000, parts: 3, want: []int64{1000, 1000, 1000}},
+		{name: "One leftover cent", amount: 10000, parts: 3, want: []int64{3334, 3333, 3333}},
+		{name: "Two leftover cents", amount: 5, parts: 3, want: []int64{2, 2, 1}},
+		{name: "Fewer cents than parts", amount: 2, parts: 5, want: []int64{1, 1, 0, 0, 0}},
+		{name: "Single part", amount: 1234, parts: 1, want: []int64{1234}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			money := valueobjects.Money{Amount: tt.amount, Currency: valueobjects.USD}
+
+			// Act
+			parts, err := money.Allocate(tt.parts)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assertParts(t, parts, tt.want)
+			assertSumsTo(t, parts, money)
+		})
+	}
+}
+
+func TestMoney_Allocate_InvalidParts(t *testing.T) {
+	money := valueobjects.Money{Amount: 1000, Currency: valueobjects.USD}
+
+	for _, n := range []int{0, -1} {
+		if _, err := money.Allocate(n); err == nil {
+			t.Errorf("Allocate(%d): expected error, got nil", n)
+		}
+	}
+}
+
+func TestMoney_AllocateByRatios(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		ratios []int
+		want   []int64
+	}{
+		{name: "Exact split", amount: 10000, ratios: []int{70, 30}, want: []int64{7000, 3000}},
+		{name: "Largest remainder gets the cent", amount: 6, ratios: []int{3, 7}, want: []int64{2, 4}}, // 1.8 / 4.2 cents
+		{name: "Ties go to the earlier part", amount: 1, ratios: []int{1, 1}, want: []int64{1, 0}},
+		{name: "Platform fee and partner share", amount: 1001, ratios: []int{3, 97}, want: []int64{30, 971}}, // 30.03 / 970.97 cents
+		{name: "Three way uneven", amount: 100, ratios: []int{1, 1, 1}, want: []int64{34, 33, 33}},
+		{name: "Largest remainder of three", amount: 100, ratios: []int{1, 2, 3}, want: []int64{17, 33, 50}}, // 16.67 / 33.33 / 50 cents
+		{name: "Zero ratio gets nothing", amount: 1000, ratios: []int{0, 1, 1}, want: []int64{0, 500, 500}},
+		{name: "Zero amount", amount: 0, ratios: []int{1, 2}, want: []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			money := valueobjects.Money{Amount: tt.amount, Currency: valueobjects.THB}
+
+			// Act
+			parts, err := money.AllocateByRatios(tt.ratios)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assertParts(t, parts, tt.want)
+			assertSumsTo(t, parts, money)
+		})
+	}
+}
+
+func TestMoney_AllocateByRatios_NeverLosesCents(t *testing.T) {
+	ratioSets := [][]int{{1, 1}, {1, 1, 1}, {1, 2, 3}, {3, 97}, {5, 5, 5, 5, 5, 5, 5}, {13, 29, 58}}
+
+	for cents := int64(0); cents <= 1000; cents++ {
+		money := valueobjects.Money{Amount: cents, Currency: valueobjects.USD}
+
+		for _, ratios := range ratioSets {
+			parts, err := money.AllocateByRatios(ratios)
+			if err != nil {
+				t.Fatalf("AllocateByRatios(%v) of %s: %v", ratios, money, err)
+			}
+			assertSumsTo(t, parts, money)
+		}
+	}
+}
+
+func TestMoney_AllocateByRatios_InvalidRatios(t *testing.T) {
+	money := valueobjects.Money{Amount: 1000, Currency: valueobjects.USD}
+
+	tests := map[string][]int{
+		"Empty":    {},
+		"Negative": {1, -1},
+		"All zero": {0, 0},
+	}
+
+	for name, ratios := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := money.AllocateByRatios(ratios); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+// assertParts checks allocated amounts part by part
+func assertParts(t *testing.T, parts []valueobjects.Money, want []int64) {
+	t.Helper()
+
+	if len(parts) != len(want) {
+		t.Fatalf("got %d parts, want %d", len(parts), len(want))
+	}
+	for i := range parts {
+		if parts[i].Amount != want[i] {
+			t.Errorf("part %d = %d, want %d", i, parts[i].Amount, want[i])
+		}
+	}
+}
+
+// assertSumsTo checks allocated parts add up to the original amount to the cent
+func assertSumsTo(t *testing.T, parts []valueobjects.Money, total valueobjects.Money) {
+	t.Helper()
+
+	var cents int64
+	for _, part := range parts {
+		if part.Currency != total.Currency {
+			t.Errorf("part currency = %s, want %s", part.Currency, total.Currency)
+		}
+		cents += part.Amount
+	}
+	if want := total.Amount; cents != want {
+		t.Errorf("parts add up to %d cents, want %d", cents, want)
+	}
+}
+
+func TestMoney_ConvertTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		from   valueobjects.Currency
+		to     valueobjects.Currency
+		rate   float64
+		want   int64
+	}{
+		{name: "Dollars to euros", amount: 1000, from: valueobjects.USD, to: valueobjects.EUR, rate: 0.91, want: 910},
+		{name: "Rounds half a cent up", amount: 100, from: valueobjects.USD, to: valueobjects.EUR, rate: 0.915, want: 92},
+		{name: "To a zero-decimal currency", amount: 1234, from: valueobjects.USD, to: valueobjects.JPY, rate: 149.5, want: 1845}, // 1844.83 yen
+		{name: "From a zero-decimal currency", amount: 1000, from: valueobjects.JPY, to: valueobjects.USD, rate: 0.0067, want: 670},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			money := valueobjects.Money{Amount: tt.amount, Currency: tt.from}
+			rate, err := valueobjects.NewExchangeRate(string(tt.from), string(tt.to), tt.rate, "test", time.Now())
+			if err != nil {
+				t.Fatalf("NewExchangeRate: %v", err)
+			}
+
+			// Act
+			conversion, err := money.ConvertTo(tt.to, rate)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if conversion.Converted.Amount != tt.want || conversion.Converted.Currency != tt.to {
+				t.Errorf("Converted = %s, want %d %s minor units", conversion.Converted, tt.want, tt.to)
+			}
+			if conversion.Original != money || conversion.Rate != rate {
+				t.Errorf("Conversion does not record the original amount and rate: %+v", conversion)
+			}
+		})
+	}
+}
+
+func TestMoney_ConvertTo_WrongRate(t *testing.T) {
+	money := valueobjects.Money{Amount: 1000, Currency: valueobjects.USD}
+	rate, _ := valueobjects.NewExchangeRate("EUR", "USD", 1.10, "test", time.Now())
+
+	if _, err := money.ConvertTo(valueobjects.EUR, rate); err == nil {
+		t.Error("Expected error for a rate quoting another pair, got nil")
+	}
+}
@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+// The Money benchmarks cover the operations every payment and refund goes
+// through. Compare releases with benchstat: make bench BENCH=Money.
+
+func BenchmarkMoney_ParseMoney(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := valueobjects.ParseMoney("1234.56", "USD"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMoney_Add(b *testing.B) {
+	m, _ := valueobjects.NewMoney(123456, "USD")
+	other, _ := valueobjects.NewMoney(789, "USD")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := m.Add(other); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMoney_PercentOf(b *testing.B) {
+	m, _ := valueobjects.NewMoney(123456, "USD")
+	percent, _ := valueobjects.ParseRatio("2.9")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := m.PercentOf(percent); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMoney_AllocateByRatios(b *testing.B) {
+	m, _ := valueobjects.NewMoney(100000, "USD")
+	ratios := []int{70, 20, 10}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := m.AllocateByRatios(ratios); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMoney_String(b *testing.B) {
+	m, _ := valueobjects.NewMoney(123456, "USD")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = m.String()
+	}
+}
+
+func BenchmarkMoney_JSON(b *testing.B) {
+	m, _ := valueobjects.NewMoney(123456, "USD")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := json.Marshal(m)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var decoded valueobjects.Money
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestMoney_JSONRoundTrip(t *testing.T) {
+	// Arrange
+	money := valueobjects.Money{Amount: 123456, Currency: valueobjects.USD}
+
+	// Act
+	data, err := json.Marshal(money)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded valueobjects.Money
+	err = json.Unmarshal(data, &decoded)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if string(data) != `{"amount":"1234.56","currency":"USD"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+	if decoded != money {
+		t.Errorf("Round trip = %+v, want %+v", decoded, money)
+	}
+}
+
+func TestMoney_UnmarshalJSON_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"Number amount":      `{"amount": 12.34, "currency": "USD"}`,
+		"Too many decimals":  `{"amount": "1.234", "currency": "USD"}`,
+		"Unknown currency":   `{"amount": "1.00", "currency": "XXX"}`,
+		"Not an object":      `"USD 1.00"`,
+		"Fraction of a yen":  `{"amount": "10.5", "currency": "JPY"}`,
+		"Negative amount":    `{"amount": "-1.00", "currency": "USD"}`,
+		"Missing the amount": `{"currency": "USD"}`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			var money valueobjects.Money
+			if err := json.Unmarshal([]byte(input), &money); err == nil {
+				t.Errorf("Expected error, got %+v", money)
+			}
+		})
+	}
+}
+
+func TestMoney_TextRoundTrip(t *testing.T) {
+	money := valueobjects.Money{Amount: 1500, Currency: valueobjects.JPY}
+
+	text, _ := money.MarshalText()
+	var decoded valueobjects.Money
+	if err := decoded.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText(%q): %v", text, err)
+	}
+
+	if string(text) != "JPY 1500" || decoded != money {
+		t.Errorf("Round trip of %q = %+v, want %+v", text, decoded, money)
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want int64
+	}{
+		{name: "BIGINT column", src: int64(10000), want: 10000},
+		{name: "NUMERIC sum", src: []byte("48000"), want: 48000},
+		{name: "NULL", src: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Currency comes from its own column and must be left alone
+			money := valueobjects.Money{Currency: valueobjects.EUR}
+
+			if err := money.Scan(tt.src); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if money.Amount != tt.want || money.Currency != valueobjects.EUR {
+				t.Errorf("Scan(%v) = %+v, want %d EUR", tt.src, money, tt.want)
+			}
+		})
+	}
+
+	var money valueobjects.Money
+	if err := money.Scan("12.50"); err == nil {
+		t.Error("Expected error scanning a decimal, got nil")
+	}
+}
+
+func TestMoney_Localize(t *testing.T) {
+	tests := []struct {
+		locale string
+		money  valueobjects.Money
+		want   string
+	}{
+		{locale: "en-US", money: valueobjects.Money{Amount: 123450, Currency: valueobjects.USD}, want: "$1,234.50"},
+		{locale: "th-TH", money: valueobjects.Money{Amount: 123450, Currency: valueobjects.THB}, want: "฿1,234.50"},
+		{locale: "de-DE", money: valueobjects.Money{Amount: 123450, Currency: valueobjects.EUR}, want: "1.234,50\u00a0€"},
+		{locale: "fr_FR", money: valueobjects.Money{Amount: 123450, Currency: valueobjects.EUR}, want: "1\u202f234,50\u00a0€"},
+		{locale: "pt-BR", money: valueobjects.Money{Amount: 123450, Currency: "BRL"}, want: "R$\u00a01.234,50"},
+		{locale: "ja-JP", money: valueobjects.Money{Amount: 1234567, Currency: valueobjects.JPY}, want: "¥1,234,567"},
+		{locale: "xx", money: valueobjects.Money{Amount: 99, Currency: valueobjects.USD}, want: "$0.99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := tt.money.Localize(tt.locale); got != tt.want {
+				t.Errorf("Localize(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+)
+
+// eventPayload has the shape of a transaction event's data
+type eventPayload struct {
+	TransactionID         string `json:"transaction_id"`
+	IdempotencyKey        string `json:"idempotency_key"`
+	Status                string `json:"status"`
+	Amount                string `json:"amount"`
+	Currency              string `json:"currency"`
+	ProviderTransactionID string `json:"provider_transaction_id"`
+	Livemode              bool   `json:"livemode"`
+}
+
+// BenchmarkOutboxEventLifecycle follows one event from being recorded to
+// its webhook body: encoded for the database, read back by the relay and
+// wrapped for delivery. "Map" is how events were handled while payloads
+// were maps, decoded from the database and re-encoded for every delivery;
+// "Typed" is the current typed payload, kept encoded throughout.
+func BenchmarkOutboxEventLifecycle(b *testing.B) {
+	partnerID, txnID := uuid.New(), uuid.New()
+	typed := eventPayload{
+		TransactionID:         txnID.String(),
+		IdempotencyKey:        "order-10023",
+		Status:                "completed",
+		Amount:                "149.90",
+		Currency:              "USD",
+		ProviderTransactionID: "pi_3Nq8xYz",
+	}
+
+	b.Run("Map", func(b *testing.B) {
+		type envelope struct {
+			ID        string                 `json:"id"`
+			Type      string                 `json:"type"`
+			CreatedAt time.Time              `json:"created_at"`
+			Data      map[string]interface{} `json:"data"`
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			payload := map[string]interface{}{
+				"transaction_id":          typed.TransactionID,
+				"idempotency_key":         typed.IdempotencyKey,
+				"status":                  typed.Status,
+				"amount":                  typed.Amount,
+				"currency":                typed.Currency,
+				"provider_transaction_id": typed.ProviderTransactionID,
+				"livemode":                typed.Livemode,
+			}
+			stored, err := json.Marshal(payload)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var loaded map[string]interface{}
+			if err := json.Unmarshal(stored, &loaded); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := json.Marshal(envelope{ID: txnID.String(), Type: entities.EventPaymentCompleted, Data: loaded}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Typed", func(b *testing.B) {
+		type envelope struct {
+			ID        string          `json:"id"`
+			Type      string          `json:"type"`
+			CreatedAt time.Time       `json:"created_at"`
+			Data      json.RawMessage `json:"data"`
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			event, err := entities.NewOutboxEvent(partnerID, "transaction", txnID, entities.EventPaymentCompleted, typed)
+			if err != nil {
+				b.Fatal(err)
+			}
+			loaded := []byte(string(event.Payload))
+			if _, err := json.Marshal(envelope{ID: event.ID.String(), Type: event.EventType, Data: loaded}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
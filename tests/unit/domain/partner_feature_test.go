@@ -0,0 +1,83 @@
+package domain_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestPartner_HasFeatureDefaults(t *testing.T) {
+	// Features partners had before flags, and alerts to the partner itself,
+	// are on; messages to customers and new payment flows are opt-in
+	want := map[valueobjects.PartnerFeature]bool{
+		valueobjects.FeaturePartialCapture:     false,
+		valueobjects.FeatureCrypto:             false,
+		valueobjects.FeatureBulkRefunds:        true,
+		valueobjects.FeatureReceiptEmails:      false,
+		valueobjects.FeatureRefundEmails:       false,
+		valueobjects.FeatureWebhookAlertEmails: true,
+		valueobjects.FeatureSMSConfirmations:   false,
+		valueobjects.FeatureSMSVerification:    false,
+	}
+	if got := len(valueobjects.PartnerFeatures()); got != len(want) {
+		t.Fatalf("PartnerFeatures() lists %d features, want %d", got, len(want))
+	}
+
+	partner, err := entities.NewPartner("Acme", "acme@example.com")
+	if err != nil {
+		t.Fatalf("NewPartner() error: %v", err)
+	}
+	// A partner read from storage without flags gets the same defaults
+	stored := &entities.Partner{}
+	for _, feature := range valueobjects.PartnerFeatures() {
+		if got := partner.HasFeature(feature); got != want[feature] {
+			t.Errorf("new partner HasFeature(%s) = %v, want %v", feature, got, want[feature])
+		}
+		if got := stored.HasFeature(feature); got != want[feature] {
+			t.Errorf("stored partner HasFeature(%s) = %v, want %v", feature, got, want[feature])
+		}
+	}
+	if effective := partner.EffectiveFeatures(); len(effective) != len(want) || !effective[valueobjects.FeatureBulkRefunds] || effective[valueobjects.FeatureCrypto] {
+		t.Errorf("EffectiveFeatures() = %v, want the defaults", effective)
+	}
+
+	// Unknown features are never on
+	if partner.HasFeature("enable_teleportation") {
+		t.Error("HasFeature(unknown) = true")
+	}
+}
+
+func TestPartner_SetFeature(t *testing.T) {
+	partner := &entities.Partner{}
+
+	// Explicit flags win over the defaults, both ways
+	if err := partner.SetFeature(valueobjects.FeatureCrypto, true); err != nil {
+		t.Fatalf("SetFeature() error: %v", err)
+	}
+	if err := partner.SetFeature(valueobjects.FeatureBulkRefunds, false); err != nil {
+		t.Fatalf("SetFeature() error: %v", err)
+	}
+	if !partner.HasFeature(valueobjects.FeatureCrypto) || partner.HasFeature(valueobjects.FeatureBulkRefunds) {
+		t.Errorf("flags = %v, want crypto on and bulk refunds off", partner.Features)
+	}
+	if effective := partner.EffectiveFeatures(); !effective[valueobjects.FeatureCrypto] || effective[valueobjects.FeatureBulkRefunds] {
+		t.Errorf("EffectiveFeatures() = %v, want the explicit flags", effective)
+	}
+
+	// Unknown features are refused
+	err := partner.SetFeature("enable_teleportation", true)
+	var domainErr *errors.DomainError
+	if !stderrors.As(err, &domainErr) || domainErr.Param != "feature" {
+		t.Errorf("SetFeature(unknown) error = %v, want a feature validation error", err)
+	}
+	if _, ok := partner.Features["enable_teleportation"]; ok {
+		t.Error("SetFeature(unknown) stored the flag")
+	}
+
+	if feature, err := valueobjects.NewPartnerFeature(" ENABLE_CRYPTO "); err != nil || feature != valueobjects.FeatureCrypto {
+		t.Errorf("NewPartnerFeature() = %q, %v; want enable_crypto", feature, err)
+	}
+}
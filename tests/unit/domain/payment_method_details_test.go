@@ -0,0 +1,77 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestNewCardDetails_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		brand    string
+		last4    string
+		expMonth int
+		expYear  int
+	}{
+		{name: "Unknown brand", brand: "bankcard", last4: "4242", expMonth: 12, expYear: 2030},
+		{name: "Full card number", brand: "visa", last4: "4242424242424242", expMonth: 12, expYear: 2030},
+		{name: "Non-digit last4", brand: "visa", last4: "42a2", expMonth: 12, expYear: 2030},
+		{name: "Month 13", brand: "visa", last4: "4242", expMonth: 13, expYear: 2030},
+		{name: "Two-digit year", brand: "visa", last4: "4242", expMonth: 12, expYear: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := valueobjects.NewCardDetails(tt.brand, tt.last4, tt.expMonth, tt.expYear, ""); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCardDetails_IsExpired(t *testing.T) {
+	card, err := valueobjects.NewCardDetails("Visa", "4242", 2, 2028, "fp_123")
+	if err != nil {
+		t.Fatalf("NewCardDetails: %v", err)
+	}
+
+	if card.IsExpired(time.Date(2028, 2, 29, 23, 59, 0, 0, time.UTC)) {
+		t.Error("Card should be valid through the last day of its expiry month")
+	}
+	if !card.IsExpired(time.Date(2028, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Card should be expired after its expiry month")
+	}
+}
+
+func TestTransaction_SetPaymentMethodDetails(t *testing.T) {
+	// Arrange
+	money, _ := valueobjects.NewMoney(10000, "USD")
+	txn, _ := entities.NewTransaction(uuid.New(), "key-1", money,
+		valueobjects.PaymentMethodEWallet, valueobjects.ProviderStripe, "payer@example.com")
+	wallet, _ := valueobjects.NewWalletDetails("promptpay", "0812345678")
+	card, _ := valueobjects.NewCardDetails("visa", "4242", 12, 2030, "")
+
+	// Act
+	mismatchErr := txn.SetPaymentMethodDetails(valueobjects.PaymentMethodDetails{Card: card})
+	bothErr := txn.SetPaymentMethodDetails(valueobjects.PaymentMethodDetails{Card: card, Wallet: wallet})
+	err := txn.SetPaymentMethodDetails(valueobjects.PaymentMethodDetails{Wallet: wallet})
+
+	// Assert
+	if mismatchErr == nil {
+		t.Error("Expected error for card details on an e-wallet payment")
+	}
+	if bothErr == nil {
+		t.Error("Expected error for more than one kind of details")
+	}
+	if err != nil {
+		t.Fatalf("SetPaymentMethodDetails: %v", err)
+	}
+	if txn.PaymentMethodDetails == nil || txn.PaymentMethodDetails.Wallet.Type != valueobjects.WalletPromptPay {
+		t.Errorf("PaymentMethodDetails = %+v", txn.PaymentMethodDetails)
+	}
+}
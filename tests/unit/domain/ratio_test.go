@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestParseRatio(t *testing.T) {
+	tests := []struct {
+		decimal  string
+		num, den int64
+		wantErr  bool
+	}{
+		{decimal: "2.9", num: 29, den: 10},
+		{decimal: "0.0067", num: 67, den: 10000},
+		{decimal: "149.50", num: 299, den: 2},
+		{decimal: "007", num: 7, den: 1},
+		{decimal: "0", num: 0, den: 1},
+		{decimal: "0.000000000000000001", num: 1, den: 1000000000000000000},
+		{decimal: "0.0000000000000000001", wantErr: true},
+		{decimal: "1234567890.123456", wantErr: true},
+		{decimal: "-1", wantErr: true},
+		{decimal: "1e3", wantErr: true},
+		{decimal: "1.", wantErr: true},
+		{decimal: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		ratio, err := valueobjects.ParseRatio(tt.decimal)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRatio(%q) = %s, want an error", tt.decimal, ratio)
+			}
+			continue
+		}
+		if err != nil || ratio.Numerator() != tt.num || ratio.Denominator() != tt.den {
+			t.Errorf("ParseRatio(%q) = %s, %v; want %d/%d", tt.decimal, ratio, err, tt.num, tt.den)
+		}
+	}
+}
+
+func TestRatioOf(t *testing.T) {
+	tests := []struct {
+		value    float64
+		num, den int64
+	}{
+		{value: 2.9, num: 29, den: 10},
+		{value: 0.145, num: 29, den: 200},
+		{value: 1e15, num: 1e15, den: 1},
+		// The shortest form has 16 digits, rounded to 15
+		{value: 1 / 0.91, num: 10989010989011, den: 1e13},
+	}
+
+	for _, tt := range tests {
+		ratio, err := valueobjects.RatioOf(tt.value)
+		if err != nil || ratio.Numerator() != tt.num || ratio.Denominator() != tt.den {
+			t.Errorf("RatioOf(%v) = %s, %v; want %d/%d", tt.value, ratio, err, tt.num, tt.den)
+		}
+	}
+
+	for _, value := range []float64{-1, 1e-20, 1e20} {
+		if _, err := valueobjects.RatioOf(value); err == nil {
+			t.Errorf("RatioOf(%v): expected error, got nil", value)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestNewRefundReason(t *testing.T) {
+	tests := map[string]struct {
+		code, note string
+		want       valueobjects.RefundReason
+	}{
+		"Code alone":           {"duplicate", "", valueobjects.RefundReason{Code: valueobjects.RefundReasonDuplicate}},
+		"Code is normalised":   {"  Fraudulent ", "", valueobjects.RefundReason{Code: valueobjects.RefundReasonFraudulent}},
+		"Note is trimmed":      {"requested_by_customer", " changed mind ", valueobjects.RefundReason{Code: valueobjects.RefundReasonRequestedByCustomer, Note: "changed mind"}},
+		"Other with its note":  {"other", "shipping damage", valueobjects.RefundReason{Code: valueobjects.RefundReasonOther, Note: "shipping damage"}},
+		"Longest note allowed": {"duplicate", strings.Repeat("n", 255), valueobjects.RefundReason{Code: valueobjects.RefundReasonDuplicate, Note: strings.Repeat("n", 255)}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := valueobjects.NewRefundReason(tt.code, tt.note)
+			if err != nil {
+				t.Fatalf("NewRefundReason(%q, %q): %v", tt.code, tt.note, err)
+			}
+			if got != tt.want || !got.IsValid() {
+				t.Errorf("NewRefundReason(%q, %q) = %+v, want %+v", tt.code, tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRefundReason_Invalid(t *testing.T) {
+	tests := map[string][2]string{
+		"Free text instead of a code": {"customer asked", ""},
+		"Empty code":                  {"", "note"},
+		"Other without a note":        {"other", "   "},
+		"Note too long":               {"duplicate", strings.Repeat("n", 256)},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := valueobjects.NewRefundReason(input[0], input[1]); err == nil {
+				t.Errorf("NewRefundReason(%q, %q): expected error, got nil", input[0], input[1])
+			}
+		})
+	}
+}
+
+func TestRefundReason_String(t *testing.T) {
+	if got := (valueobjects.RefundReason{Code: valueobjects.RefundReasonDuplicate}).String(); got != "duplicate" {
+		t.Errorf("String() = %q, want %q", got, "duplicate")
+	}
+	if got := (valueobjects.RefundReason{Code: valueobjects.RefundReasonOther, Note: "damaged"}).String(); got != "other: damaged" {
+		t.Errorf("String() = %q, want %q", got, "other: damaged")
+	}
+}
@@ -0,0 +1,97 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func newReportSchedule(t *testing.T, frequency entities.ReportFrequency, timezone string, now time.Time) *entities.ReportSchedule {
+	t.Helper()
+	schedule, err := entities.NewReportSchedule(uuid.New(), true, entities.ReportSettings{
+		Name:        "Report",
+		Resource:    entities.ReportResourceTransactions,
+		Format:      entities.ExportFormatCSV,
+		Layout:      entities.ExportLayout{Timezone: timezone},
+		Frequency:   frequency,
+		Hour:        6,
+		Destination: valueobjects.ReportDestination{Type: valueobjects.ReportDestinationEmail, Recipients: []string{"finance@example.com"}},
+	}, now)
+	if err != nil {
+		t.Fatalf("NewReportSchedule() error: %v", err)
+	}
+	return schedule
+}
+
+func TestReportSchedule_NextRunAndPeriod(t *testing.T) {
+	// Wednesday 13 March 2024, 10:00 in Bangkok
+	now := time.Date(2024, 3, 13, 3, 0, 0, 0, time.UTC)
+	bangkok, _ := time.LoadLocation("Asia/Bangkok")
+
+	tests := []struct {
+		frequency entities.ReportFrequency
+		next      time.Time
+		from, to  time.Time
+	}{
+		{entities.ReportFrequencyDaily, time.Date(2024, 3, 14, 6, 0, 0, 0, bangkok), time.Date(2024, 3, 13, 0, 0, 0, 0, bangkok), time.Date(2024, 3, 14, 0, 0, 0, 0, bangkok)},
+		{entities.ReportFrequencyWeekly, time.Date(2024, 3, 18, 6, 0, 0, 0, bangkok), time.Date(2024, 3, 11, 0, 0, 0, 0, bangkok), time.Date(2024, 3, 18, 0, 0, 0, 0, bangkok)},
+		{entities.ReportFrequencyMonthly, time.Date(2024, 4, 1, 6, 0, 0, 0, bangkok), time.Date(2024, 3, 1, 0, 0, 0, 0, bangkok), time.Date(2024, 4, 1, 0, 0, 0, 0, bangkok)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.frequency), func(t *testing.T) {
+			schedule := newReportSchedule(t, tt.frequency, "Asia/Bangkok", now)
+			if !schedule.NextRunAt.Equal(tt.next) {
+				t.Errorf("NextRunAt = %v, want %v", schedule.NextRunAt, tt.next)
+			}
+			// A run covers the period before it
+			from, to := schedule.Period(schedule.NextRunAt)
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("Period() = %v to %v, want %v to %v", from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestReportSchedule_MarkFailed(t *testing.T) {
+	now := time.Date(2024, 3, 13, 3, 0, 0, 0, time.UTC)
+	schedule := newReportSchedule(t, entities.ReportFrequencyDaily, "", now)
+	next := schedule.NextRunAt
+
+	schedule.MarkFailed("connection refused", next)
+	if schedule.Attempts != 1 || !schedule.NextRunAt.Equal(next.Add(time.Minute)) || schedule.LastStatus != "" {
+		t.Fatalf("after 1 failure: attempts %d, next %v, status %q; want a retry in a minute", schedule.Attempts, schedule.NextRunAt, schedule.LastStatus)
+	}
+	schedule.MarkFailed("connection refused", next.Add(time.Minute))
+	if !schedule.NextRunAt.Equal(next.Add(3 * time.Minute)) {
+		t.Fatalf("after 2 failures: next %v, want a retry in 2 minutes", schedule.NextRunAt)
+	}
+
+	// The last attempt gives up the run and schedules the next
+	schedule.MarkFailed("connection refused", next.Add(3*time.Minute))
+	if schedule.Attempts != 0 || schedule.LastStatus != entities.ReportRunFailed || !schedule.NextRunAt.Equal(next.AddDate(0, 0, 1)) {
+		t.Errorf("after 3 failures: attempts %d, status %q, next %v; want the next day's run", schedule.Attempts, schedule.LastStatus, schedule.NextRunAt)
+	}
+}
+
+func TestNewReportSchedule_Invalid(t *testing.T) {
+	email := valueobjects.ReportDestination{Type: valueobjects.ReportDestinationEmail, Recipients: []string{"finance@example.com"}}
+	tests := map[string]entities.ReportSettings{
+		"no name":             {Resource: entities.ReportResourceTransactions, Format: entities.ExportFormatCSV, Frequency: entities.ReportFrequencyDaily, Destination: email},
+		"hour out of range":   {Name: "r", Resource: entities.ReportResourceTransactions, Format: entities.ExportFormatCSV, Frequency: entities.ReportFrequencyDaily, Hour: 24, Destination: email},
+		"weekly statements":   {Name: "r", Resource: entities.ReportResourceStatements, Format: entities.ExportFormatCSV, Frequency: entities.ReportFrequencyWeekly, Destination: email},
+		"private SFTP host":   {Name: "r", Resource: entities.ReportResourceTransactions, Format: entities.ExportFormatCSV, Frequency: entities.ReportFrequencyDaily, Destination: valueobjects.ReportDestination{Type: valueobjects.ReportDestinationSFTP, Host: "10.0.0.5", Username: "u", HostKey: "ssh-ed25519 AAAA", Password: "p"}},
+		"SFTP without secret": {Name: "r", Resource: entities.ReportResourceTransactions, Format: entities.ExportFormatCSV, Frequency: entities.ReportFrequencyDaily, Destination: valueobjects.ReportDestination{Type: valueobjects.ReportDestinationSFTP, Host: "sftp.example.com", Username: "u", HostKey: "ssh-ed25519 AAAA"}},
+		"S3 without role":     {Name: "r", Resource: entities.ReportResourceTransactions, Format: entities.ExportFormatCSV, Frequency: entities.ReportFrequencyDaily, Destination: valueobjects.ReportDestination{Type: valueobjects.ReportDestinationS3, Bucket: "reports", Region: "eu-west-1"}},
+	}
+	for name, settings := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := entities.NewReportSchedule(uuid.New(), true, settings, time.Now()); err == nil {
+				t.Error("NewReportSchedule() error = nil, want a validation error")
+			}
+		})
+	}
+}
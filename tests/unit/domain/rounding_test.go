@@ -0,0 +1,156 @@
+package domain_test
+
+import (
+	"math"
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestRoundingPolicy_Scale(t *testing.T) {
+	tests := []struct {
+		name                        string
+		units, num, den             int64
+		halfUp, halfEven, truncated int64
+	}{
+		{name: "Half a cent", units: 1, num: 1, den: 2, halfUp: 1, halfEven: 0, truncated: 0},
+		{name: "Two and a half cents", units: 5, num: 1, den: 2, halfUp: 3, halfEven: 2, truncated: 2},
+		{name: "Three and a half cents", units: 7, num: 1, den: 2, halfUp: 4, halfEven: 4, truncated: 3},
+		{name: "Negative half", units: -5, num: 1, den: 2, halfUp: -3, halfEven: -2, truncated: -2},
+		{name: "Not quite half", units: 2899, num: 1, den: 200, halfUp: 14, halfEven: 14, truncated: 14}, // 14.495
+		{name: "Just over half", units: 2901, num: 1, den: 200, halfUp: 15, halfEven: 15, truncated: 14}, // 14.505
+		{name: "Ten percent tie", units: 145, num: 1, den: 10, halfUp: 15, halfEven: 14, truncated: 14},  // 14.5, 14.499999999999998 as a float
+		{name: "Exact", units: 10000, num: 29, den: 1000, halfUp: 290, halfEven: 290, truncated: 290},
+		// 2^53 + 1 cents is the first amount a float64 cannot hold, so its
+		// half would tie the wrong way
+		{name: "Tie beyond float precision", units: 9007199254740993, num: 1, den: 2,
+			halfUp: 4503599627370497, halfEven: 4503599627370496, truncated: 4503599627370496},
+		{name: "Largest amount", units: math.MaxInt64, num: 3, den: 3,
+			halfUp: math.MaxInt64, halfEven: math.MaxInt64, truncated: math.MaxInt64},
+		{name: "Product beyond 64 bits", units: 999999999999999999, num: 29, den: 1000,
+			halfUp: 29000000000000000, halfEven: 29000000000000000, truncated: 28999999999999999}, // ...999.971
+		{name: "Large tie", units: 999999999999999, num: 1, den: 2,
+			halfUp: 500000000000000, halfEven: 500000000000000, truncated: 499999999999999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for policy, want := range map[valueobjects.RoundingPolicy]int64{
+				valueobjects.RoundHalfUp:   tt.halfUp,
+				valueobjects.RoundHalfEven: tt.halfEven,
+				valueobjects.RoundTruncate: tt.truncated,
+			} {
+				got, err := policy.Scale(tt.units, tt.num, tt.den)
+				if err != nil || got != want {
+					t.Errorf("%s: Scale(%d, %d/%d) = %d, %v; want %d", policy, tt.units, tt.num, tt.den, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundingPolicy_Scale_OutOfRange(t *testing.T) {
+	for _, units := range []int64{math.MaxInt64, math.MinInt64} {
+		if got, err := valueobjects.RoundHalfEven.Scale(units, 2, 1); err == nil {
+			t.Errorf("Scale(%d, 2/1) = %d, want an error", units, got)
+		}
+	}
+	// (2^32 + 1) * (2^32 - 1) / 2 ties at MaxInt64 + 0.5: rounding it up
+	// leaves int64, truncating it does not
+	units, num := int64(1<<32+1), int64(1<<32-1)
+	if got, err := valueobjects.RoundHalfEven.Scale(units, num, 2); err == nil {
+		t.Errorf("half_even: Scale() = %d, want an error", got)
+	}
+	if got, err := valueobjects.RoundTruncate.Scale(units, num, 2); err != nil || got != math.MaxInt64 {
+		t.Errorf("truncate: Scale() = %d, %v; want MaxInt64", got, err)
+	}
+}
+
+func TestRoundingPolicy_Quotient(t *testing.T) {
+	tests := []struct {
+		numerator, denominator      int64
+		halfUp, halfEven, truncated int64
+	}{
+		{numerator: 1, denominator: 2, halfUp: 1, halfEven: 0, truncated: 0},
+		{numerator: 3, denominator: 2, halfUp: 2, halfEven: 2, truncated: 1},
+		{numerator: -3, denominator: 2, halfUp: -2, halfEven: -2, truncated: -1},
+		{numerator: -5, denominator: 2, halfUp: -3, halfEven: -2, truncated: -2},
+		{numerator: 2, denominator: 3, halfUp: 1, halfEven: 1, truncated: 0},
+		{numerator: math.MaxInt64, denominator: 2, halfUp: 1 << 62, halfEven: 1 << 62, truncated: 1<<62 - 1},
+	}
+
+	for _, tt := range tests {
+		if got := valueobjects.RoundHalfUp.Quotient(tt.numerator, tt.denominator); got != tt.halfUp {
+			t.Errorf("half_up(%d/%d) = %d, want %d", tt.numerator, tt.denominator, got, tt.halfUp)
+		}
+		if got := valueobjects.RoundHalfEven.Quotient(tt.numerator, tt.denominator); got != tt.halfEven {
+			t.Errorf("half_even(%d/%d) = %d, want %d", tt.numerator, tt.denominator, got, tt.halfEven)
+		}
+		if got := valueobjects.RoundTruncate.Quotient(tt.numerator, tt.denominator); got != tt.truncated {
+			t.Errorf("truncate(%d/%d) = %d, want %d", tt.numerator, tt.denominator, got, tt.truncated)
+		}
+	}
+}
+
+func TestMoney_Divide(t *testing.T) {
+	money := valueobjects.Money{Amount: 1005, Currency: valueobjects.USD}
+
+	tests := []struct {
+		policy valueobjects.RoundingPolicy
+		want   int64
+	}{
+		{policy: valueobjects.RoundHalfUp, want: 503},   // 502.5
+		{policy: valueobjects.RoundHalfEven, want: 502}, // 502.5
+		{policy: valueobjects.RoundTruncate, want: 502},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			got, err := money.Divide(2, tt.policy)
+			if err != nil {
+				t.Fatalf("Divide: %v", err)
+			}
+			if got.Amount != tt.want || got.Currency != valueobjects.USD {
+				t.Errorf("Divide(2) = %+v, want %d USD", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := money.Divide(0, valueobjects.RoundHalfUp); err == nil {
+		t.Error("Expected error dividing by zero, got nil")
+	}
+}
+
+func TestFeeSchedule_Calculate(t *testing.T) {
+	// 2.5% + 30 cents of $10.60 is 26.5 + 30 cents
+	fee, _ := valueobjects.NewFeeSchedule(2.5, 30)
+	amount := valueobjects.Money{Amount: 1060, Currency: valueobjects.USD}
+
+	halfUp, _ := fee.Calculate(amount, valueobjects.RoundHalfUp)
+	halfEven, _ := fee.Calculate(amount, valueobjects.RoundHalfEven)
+
+	if halfUp.Amount != 57 || halfEven.Amount != 56 {
+		t.Errorf("Fee = %d (half_up), %d (half_even), want 57 and 56", halfUp.Amount, halfEven.Amount)
+	}
+}
+
+func TestTaxRate_Calculate(t *testing.T) {
+	amount := valueobjects.Money{Amount: 10700, Currency: valueobjects.THB}
+
+	exclusive, _ := valueobjects.NewTaxRate(7, false)
+	inclusive, _ := valueobjects.NewTaxRate(7, true)
+
+	added, _ := exclusive.Calculate(amount, valueobjects.RoundHalfUp)
+	included, _ := inclusive.Calculate(amount, valueobjects.RoundHalfUp)
+
+	if added.Amount != 749 {
+		t.Errorf("Exclusive tax = %d, want 749", added.Amount)
+	}
+	if included.Amount != 700 {
+		t.Errorf("Inclusive tax = %d, want 700", included.Amount)
+	}
+
+	if _, err := valueobjects.NewRoundingPolicy("ceiling"); err == nil {
+		t.Error("Expected error for unknown rounding policy, got nil")
+	}
+}
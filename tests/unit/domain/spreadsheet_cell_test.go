@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestSpreadsheetCell(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"text", "Ann Lee", "Ann Lee"},
+		{"sign inside", "a=b+c", "a=b+c"},
+		{"formula", `=HYPERLINK("http://evil.example")`, `'=HYPERLINK("http://evil.example")`},
+		{"plus", "+1-2", "'+1-2"},
+		{"minus", "-cmd|' /C calc'!A0", "'-cmd|' /C calc'!A0"},
+		{"at", "@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"tab", "\t=1+1", "'\t=1+1"},
+		{"carriage return", "\r=1+1", "'\r=1+1"},
+		{"negative amount", "-12.50", "-12.50"},
+		{"signed amount", "+3", "+3"},
+		{"exponent", "-1e3", "-1e3"},
+		{"already quoted", "'=1", "'=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := valueobjects.SpreadsheetCell(tt.field); got != tt.want {
+				t.Errorf("SpreadsheetCell(%q) = %q, want %q", tt.field, got, tt.want)
+			}
+		})
+	}
+}
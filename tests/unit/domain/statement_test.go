@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestNewPricing_Invalid(t *testing.T) {
+	tests := map[string][]valueobjects.PricingTier{
+		"no tiers":          nil,
+		"last tier capped":  {{Name: "standard", UpTo: 1000, Percent: 2.9}},
+		"tier not capped":   {{Name: "first", Percent: 2.9}, {Name: "rest", Percent: 2.5}},
+		"tiers not rising":  {{Name: "first", UpTo: 100, Percent: 2.9}, {Name: "second", UpTo: 100, Percent: 2.7}, {Name: "rest", Percent: 2.5}},
+		"duplicate name":    {{Name: "tier", UpTo: 100, Percent: 2.9}, {Name: "tier", Percent: 2.5}},
+		"percent above 100": {{Name: "standard", Percent: 101}},
+		"negative fixed":    {{Name: "standard", Percent: 2.9, Fixed: -1}},
+	}
+	for name, tiers := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := valueobjects.NewPricing(tiers, 1500); err == nil {
+				t.Error("NewPricing() error = nil, want a validation error")
+			}
+		})
+	}
+}
+
+func TestStatement_GraduatedTiers(t *testing.T) {
+	// Arrange
+	pricing, err := valueobjects.NewPricing([]valueobjects.PricingTier{
+		{Name: "first", UpTo: 2, Percent: 3, Fixed: 30},
+		{Name: "rest", Percent: 2, Fixed: 20},
+	}, 1500)
+	if err != nil {
+		t.Fatalf("NewPricing: %v", err)
+	}
+	partner := &entities.Partner{ID: uuid.New(), RoundingPolicy: valueobjects.RoundHalfUp}
+	statement, err := entities.NewStatement(partner, "2026-09", pricing)
+	if err != nil {
+		t.Fatalf("NewStatement: %v", err)
+	}
+
+	// Act
+	for i := 0; i < 3; i++ {
+		amount, _ := valueobjects.NewMoney(10000, "USD")
+		if err := statement.AddPayment(&entities.Transaction{Amount: amount}); err != nil {
+			t.Fatalf("AddPayment: %v", err)
+		}
+	}
+	refund, _ := valueobjects.NewMoney(2500, "USD")
+	statement.AddRefund(&entities.Refund{Amount: refund})
+	disputed, _ := valueobjects.NewMoney(10000, "USD")
+	statement.AddChargeback(&entities.Dispute{Amount: disputed})
+	statement.Close()
+
+	// Assert
+	if len(statement.Currencies) != 1 {
+		t.Fatalf("Currencies = %d, want 1", len(statement.Currencies))
+	}
+	line := statement.Currencies[0]
+	if len(line.TierFees) != 2 {
+		t.Fatalf("TierFees = %d, want 2", len(line.TierFees))
+	}
+	// Two payments of 100.00 at 3% + 0.30, then one at 2% + 0.20
+	if first := line.TierFees[0]; first.Tier != "first" || first.Payments.Count != 2 || first.Fee != 660 {
+		t.Errorf("first tier = %+v, want 2 payments charged 660", first)
+	}
+	if rest := line.TierFees[1]; rest.Tier != "rest" || rest.Payments.Count != 1 || rest.Fee != 220 {
+		t.Errorf("rest tier = %+v, want 1 payment charged 220", rest)
+	}
+	if line.TotalFees != 660+220+1500 {
+		t.Errorf("TotalFees = %d, want %d", line.TotalFees, 660+220+1500)
+	}
+	if want := int64(30000 - 2500 - 10000 - 2380); line.Net != want {
+		t.Errorf("Net = %d, want %d", line.Net, want)
+	}
+}
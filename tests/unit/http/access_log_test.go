@@ -0,0 +1,100 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/middleware"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "card numbers keep their last 4 digits",
+			contentType: "application/json",
+			body:        `{"card":"4242 4242 4242 4242","pan":5555555555554444,"amount":1000}`,
+			want:        `{"amount":1000,"card":"************4242","pan":"************4444"}`,
+		},
+		{
+			name:        "numbers failing the Luhn check are kept",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"order":"1234567890123"}`,
+			want:        `{"order":"1234567890123"}`,
+		},
+		{
+			name:        "secrets and emails in nested fields",
+			contentType: "application/json",
+			body:        `{"customer":{"email":"jane.doe@example.com"},"credentials":[{"api_key":"abc","secret_key":"def","cvv":123}]}`,
+			want:        `{"credentials":[{"api_key":"[REDACTED]","cvv":"[REDACTED]","secret_key":"[REDACTED]"}],"customer":{"email":"j***@example.com"}}`,
+		},
+		{
+			name:        "form fields",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "password=hunter2&username=ops%40example.com",
+			want:        "password=[REDACTED]&username=o%2A%2A%2A%40example.com",
+		},
+		{
+			name:        "invalid JSON is not logged",
+			contentType: "application/json",
+			body:        `{"password":"hunter2"`,
+			want:        "[invalid JSON, 21 bytes]",
+		},
+		{
+			name:        "other bodies are described",
+			contentType: "text/plain",
+			body:        "4242424242424242",
+			want:        "[text/plain, 16 bytes]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := middleware.RedactBody(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("RedactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var out bytes.Buffer
+	partnerID := uuid.New()
+	app := fiber.New()
+	app.Use(middleware.RequestID)
+	app.Use(middleware.NewAccessLog(&out, 40, nil).Handle)
+	app.Post("/payments", func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partnerID)
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "declined")
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/payments?token=tok_123&email=a@b.io",
+		strings.NewReader(`{"card_number":"4111111111111111","description":"a long description"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("Test() error: %v", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("access log %q is not one JSON line: %v", out.String(), err)
+	}
+	if entry["partner_id"] != partnerID.String() || entry["status"] != float64(fiber.StatusUnprocessableEntity) || entry["request_id"] == "" {
+		t.Errorf("entry = %v, want the partner, status 422 and a request ID", entry)
+	}
+	if entry["query"] != "email=a%2A%2A%2A%40b.io&token=[REDACTED]" {
+		t.Errorf("query = %v, want the token and email redacted", entry["query"])
+	}
+	if entry["body"] != `{"card_number":"************1111","descr` || entry["body_truncated"] != true {
+		t.Errorf("body = %v, truncated = %v, want the masked card cut at 40 bytes", entry["body"], entry["body_truncated"])
+	}
+}
@@ -0,0 +1,223 @@
+package http_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/usecases/apikey"
+	"Pay2Go/internal/usecases/partner"
+)
+
+func TestAdminPartnerDeclarativeConfiguration(t *testing.T) {
+	store := memory.NewStore()
+	partners := memory.NewPartnerRepository(store)
+	apiKeys := memory.NewAPIKeyRepository(store)
+	getPartner := partner.NewGetPartnerUseCase(partners)
+	partnerHandler := handlers.NewPartnerHandler(
+		getPartner,
+		nil,
+		partner.NewPutPartnerUseCase(partners, nil),
+		partner.NewUpdatePartnerWebhookUseCase(partners, nil),
+		nil,
+		nil,
+		nil,
+		partner.NewUpdatePartnerLocaleUseCase(partners, nil),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	apiKeyHandler := handlers.NewPartnerAPIKeyHandler(
+		getPartner,
+		apikey.NewCreateAPIKeyUseCase(apiKeys, nil),
+		apikey.NewGetAPIKeyUseCase(apiKeys),
+		apikey.NewListAPIKeysUseCase(apiKeys),
+		apikey.NewRevokeAPIKeyUseCase(apiKeys, nil),
+	)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("admin_id", "admin-1")
+		return c.Next()
+	})
+	app.Put("/partners/:id", partnerHandler.PutPartner)
+	app.Put("/partners/:id/locale", partnerHandler.UpdateLocale)
+	app.Put("/partners/:id/webhook", partnerHandler.UpdateWebhook)
+	app.Put("/partners/:id/api-keys/:key_id", apiKeyHandler.PutAPIKey)
+	app.Delete("/partners/:id/api-keys/:key_id", apiKeyHandler.RevokeAPIKey)
+
+	type response struct {
+		status int
+		etag   string
+		body   map[string]interface{}
+	}
+	call := func(method, path, body string, headers ...string) response {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s %s error: %v", method, path, err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		var decoded map[string]interface{}
+		_ = json.Unmarshal(raw, &decoded)
+		return response{resp.StatusCode, resp.Header.Get(fiber.HeaderETag), decoded}
+	}
+	expect := func(name string, got response, status int, etag string) {
+		t.Helper()
+		if got.status != status || got.etag != etag {
+			t.Errorf("%s = %d with ETag %s (%v), want %d with ETag %s", name, got.status, got.etag, got.body, status, etag)
+		}
+	}
+
+	id := uuid.New().String()
+	acme := `{"name":"Acme","email":"acme@example.com"}`
+
+	// Created once at the client's ID, then found again
+	expect("PUT create-only", call("PUT", "/partners/"+id, acme, "If-None-Match", "*"), fiber.StatusCreated, `"1"`)
+	expect("PUT again", call("PUT", "/partners/"+id, acme), fiber.StatusOK, `"1"`)
+	expect("PUT create-only again", call("PUT", "/partners/"+id, acme, "If-None-Match", "*"), fiber.StatusPreconditionFailed, "")
+	expect("PUT unknown partner at a version", call("PUT", "/partners/"+uuid.New().String(), acme, "If-Match", `"1"`), fiber.StatusPreconditionFailed, "")
+
+	// Any change to the partner moves every resource of it to a new version
+	expect("PUT locale", call("PUT", "/partners/"+id+"/locale", `{"locale":"th-TH"}`, "If-Match", `"1"`), fiber.StatusOK, `"2"`)
+	expect("PUT locale at a stale version", call("PUT", "/partners/"+id+"/locale", `{"locale":"fr-FR"}`, "If-Match", `"1"`), fiber.StatusPreconditionFailed, "")
+	expect("PUT already applied at a stale version", call("PUT", "/partners/"+id, acme, "If-Match", `"1"`), fiber.StatusOK, `"2"`)
+	expect("PUT weak tag", call("PUT", "/partners/"+id, `{"name":"Acme Store","email":"acme@example.com"}`, "If-Match", `W/"2"`), fiber.StatusPreconditionFailed, "")
+	renamed := call("PUT", "/partners/"+id, `{"name":"Acme Store","email":"acme@example.com"}`, "If-Match", `"2"`)
+	expect("PUT rename", renamed, fiber.StatusOK, `"3"`)
+	if renamed.body["name"] != "Acme Store" || renamed.body["version"] != float64(3) {
+		t.Errorf("PUT rename body = %v, want Acme Store at version 3", renamed.body)
+	}
+
+	// The webhook secret is issued once
+	webhook := call("PUT", "/partners/"+id+"/webhook", `{"url":"https://acme.example/hooks"}`)
+	if webhook.status != fiber.StatusOK || webhook.body["secret"] == nil {
+		t.Errorf("PUT webhook = %d %v, want a secret", webhook.status, webhook.body)
+	}
+	if webhook = call("PUT", "/partners/"+id+"/webhook", `{"url":"https://acme.example/hooks"}`); webhook.body["secret"] != nil {
+		t.Errorf("PUT webhook again = %v, want no secret", webhook.body)
+	}
+	expect("PUT invalid webhook", call("PUT", "/partners/"+id+"/webhook", `{"url":"acme.example"}`), fiber.StatusBadRequest, "")
+
+	// So is an API key's
+	keyPath := "/partners/" + id + "/api-keys/" + uuid.New().String()
+	key := `{"label":"Terraform","scopes":["payments","read_only"]}`
+	if created := call("PUT", keyPath, key); created.status != fiber.StatusCreated || created.body["key"] == nil {
+		t.Errorf("PUT key = %d %v, want 201 with the key", created.status, created.body)
+	}
+	if again := call("PUT", keyPath, `{"label":"Terraform","scopes":["read_only","payments"]}`); again.status != fiber.StatusOK || again.body["key"] != nil {
+		t.Errorf("PUT key again = %d %v, want 200 without the key", again.status, again.body)
+	}
+	if changed := call("PUT", keyPath, `{"label":"Renamed","scopes":["payments"]}`); changed.status != fiber.StatusConflict || changed.body["error"] != "api_key_id_taken" {
+		t.Errorf("PUT changed key = %d %v, want 409 api_key_id_taken", changed.status, changed.body)
+	}
+	expect("DELETE key", call("DELETE", keyPath, ""), fiber.StatusNoContent, "")
+	expect("DELETE key again", call("DELETE", keyPath, ""), fiber.StatusNoContent, "")
+	expect("PUT revoked key", call("PUT", keyPath, key), fiber.StatusConflict, "")
+}
+
+func TestAdminPartnerSettingsPreconditions(t *testing.T) {
+	partners := memory.NewPartnerRepository(memory.NewStore())
+	partnerHandler := handlers.NewPartnerHandler(
+		partner.NewGetPartnerUseCase(partners),
+		nil,
+		partner.NewPutPartnerUseCase(partners, nil),
+		nil,
+		partner.NewUpdatePartnerFeaturesUseCase(partners, nil),
+		partner.NewUpdatePartnerCurrenciesUseCase(partners, nil),
+		partner.NewUpdatePartnerAmountLimitsUseCase(partners, nil),
+		partner.NewUpdatePartnerLocaleUseCase(partners, nil),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("admin_id", "admin-1")
+		return c.Next()
+	})
+	app.Put("/partners/:id", partnerHandler.PutPartner)
+	app.Patch("/partners/:id/features", partnerHandler.UpdateFeatures)
+	app.Put("/partners/:id/currencies", partnerHandler.UpdateCurrencies)
+	app.Put("/partners/:id/amount-limits", partnerHandler.UpdateAmountLimits)
+	app.Put("/partners/:id/locale", partnerHandler.UpdateLocale)
+
+	call := func(method, path, body, ifMatch string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if ifMatch != "" {
+			req.Header.Set(fiber.HeaderIfMatch, ifMatch)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s %s error: %v", method, path, err)
+		}
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderETag)
+	}
+
+	id := uuid.New().String()
+	if status, _ := call("PUT", "/partners/"+id, `{"name":"Acme","email":"acme@example.com"}`, ""); status != fiber.StatusCreated {
+		t.Fatalf("PUT partner = %d, want 201", status)
+	}
+
+	settings := []struct {
+		name, method, path string
+		change, other      string
+	}{
+		{"locale", "PUT", "/locale", `{"locale":"th-TH"}`, `{"locale":"fr-FR"}`},
+		{"currencies", "PUT", "/currencies", `{"currencies":["THB","USD"]}`, `{"currencies":["EUR"]}`},
+		{"amount limits", "PUT", "/amount-limits", `{"amount_limits":{"USD":"5000.00"}}`, `{"amount_limits":{"USD":"100.00"}}`},
+		{"features", "PATCH", "/features", `{"features":{"enable_crypto":true}}`, `{"features":{"enable_crypto":false}}`},
+	}
+	version := 1
+	for _, s := range settings {
+		path := "/partners/" + id + s.path
+		current, next := `"`+strconv.Itoa(version)+`"`, `"`+strconv.Itoa(version+1)+`"`
+
+		// A change at the version the client read moves the partner on
+		if status, etag := call(s.method, path, s.change, current); status != fiber.StatusOK || etag != next {
+			t.Fatalf("%s at %s = %d with ETag %s, want 200 with %s", s.name, current, status, etag, next)
+		}
+
+		// Another change made from that version would overwrite it
+		if status, _ := call(s.method, path, s.other, current); status != fiber.StatusPreconditionFailed {
+			t.Errorf("%s at the stale %s = %d, want 412", s.name, current, status)
+		}
+		if status, _ := call(s.method, path, s.other, "W/"+next); status != fiber.StatusPreconditionFailed {
+			t.Errorf("%s at the weak W/%s = %d, want 412", s.name, next, status)
+		}
+
+		// Retrying the change that was applied succeeds, without a new version
+		if status, etag := call(s.method, path, s.change, current); status != fiber.StatusOK || etag != next {
+			t.Errorf("%s retried at %s = %d with ETag %s, want 200 with %s", s.name, current, status, etag, next)
+		}
+		if status, etag := call(s.method, path, s.change, "*"); status != fiber.StatusOK || etag != next {
+			t.Errorf("%s retried at * = %d with ETag %s, want 200 with %s", s.name, status, etag, next)
+		}
+		version++
+	}
+
+	// Settings of a partner that does not exist are not found at any version
+	if status, _ := call("PUT", "/partners/"+uuid.New().String()+"/locale", `{"locale":"th-TH"}`, "*"); status != fiber.StatusNotFound {
+		t.Errorf("locale of an unknown partner = %d, want 404", status)
+	}
+}
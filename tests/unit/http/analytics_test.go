@@ -0,0 +1,133 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/analytics"
+)
+
+func TestAnalytics(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	partners := memory.NewPartnerRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+	refunds := memory.NewRefundRepository(store)
+	rollups := memory.NewAnalyticsRepository(store)
+
+	partner, _ := entities.NewPartner("Acme", "acme@example.com")
+	if err := partners.Create(ctx, partner); err != nil {
+		t.Fatalf("Create partner: %v", err)
+	}
+
+	// Three live payments today, one failed, and a test mode one
+	now := time.Now().UTC()
+	pay := func(amount int64, provider valueobjects.PaymentProvider, method valueobjects.PaymentMethod, status entities.TransactionStatus, livemode bool) *entities.Transaction {
+		t.Helper()
+		money, _ := valueobjects.NewMoney(amount, "USD")
+		txn, err := entities.NewTransaction(partner.ID, uuid.NewString(), money, method, provider, "customer@example.com")
+		if err != nil {
+			t.Fatalf("NewTransaction: %v", err)
+		}
+		txn.Status, txn.Livemode = status, livemode
+		if err := transactions.Create(ctx, txn); err != nil {
+			t.Fatalf("Create transaction: %v", err)
+		}
+		return txn
+	}
+	refunded := pay(4000, valueobjects.ProviderStripe, valueobjects.PaymentMethodCard, entities.StatusPartiallyRefunded, true)
+	pay(2001, valueobjects.ProviderStripe, valueobjects.PaymentMethodCard, entities.StatusCompleted, true)
+	pay(1000, valueobjects.ProviderAdyen, valueobjects.PaymentMethodEWallet, entities.StatusFailed, true)
+	pay(9999, valueobjects.ProviderStripe, valueobjects.PaymentMethodCard, entities.StatusCompleted, false)
+
+	amount, _ := valueobjects.NewMoney(1000, "USD")
+	reason, _ := valueobjects.NewRefundReason("requested_by_customer", "")
+	refund, _ := entities.NewRefund(refunded.ID, amount, reason)
+	refund.Status = entities.RefundStatusCompleted
+	if err := refunds.Create(ctx, refund); err != nil {
+		t.Fatalf("Create refund: %v", err)
+	}
+
+	rolledUp, err := analytics.NewRollupAnalyticsUseCase(partners, transactions, refunds, rollups, 3, 90).Execute(ctx, now)
+	if err != nil || rolledUp != 1 {
+		t.Fatalf("rollup = %d, %v, want 1 partner", rolledUp, err)
+	}
+
+	analyticsHandler := handlers.NewAnalyticsHandler(analytics.NewGetAnalyticsUseCase(rollups))
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partner.ID)
+		return c.Next()
+	})
+	app.Get("/analytics/volume", analyticsHandler.GetVolume)
+	app.Get("/analytics/success-rates", analyticsHandler.GetSuccessRates)
+	app.Get("/analytics/average-amounts", analyticsHandler.GetAverageAmounts)
+	app.Get("/analytics/refund-rates", analyticsHandler.GetRefundRates)
+
+	get := func(path string, out interface{}) int {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("GET %s error: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if out != nil && resp.StatusCode == fiber.StatusOK {
+			if err := json.Unmarshal(body, out); err != nil {
+				t.Fatalf("GET %s: %v", path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	var volume dto.VolumeResponse
+	if status := get("/analytics/volume?interval=month", &volume); status != fiber.StatusOK {
+		t.Fatalf("GET volume = %d, want 200", status)
+	}
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	if len(volume.Series) != 1 || volume.Series[0].Start != month || volume.Series[0].Payments != 2 || volume.Series[0].Volume != "60.01" {
+		t.Errorf("volume = %+v, want 2 live payments of 60.01 in the month from %s", volume.Series, month)
+	}
+
+	var rates dto.SuccessRatesResponse
+	get("/analytics/success-rates?group_by=payment_method", &rates)
+	if len(rates.Rates) != 2 || rates.Rates[0].Key != "card" || *rates.Rates[0].SuccessRate != 1 ||
+		rates.Rates[1].Key != "e_wallet" || *rates.Rates[1].SuccessRate != 0 {
+		t.Errorf("success rates = %+v, want card at 1 then e_wallet at 0", rates.Rates)
+	}
+
+	var averages dto.AverageAmountsResponse
+	get("/analytics/average-amounts", &averages)
+	if len(averages.Currencies) != 1 || averages.Currencies[0].AverageAmount != "30.01" {
+		t.Errorf("average amounts = %+v, want 30.01 rounded half up", averages.Currencies)
+	}
+
+	var refundRates dto.RefundRatesResponse
+	get("/analytics/refund-rates", &refundRates)
+	if len(refundRates.Currencies) != 1 || refundRates.Currencies[0].Refunds != 1 ||
+		refundRates.Currencies[0].RefundedVolume != "10.00" || *refundRates.Currencies[0].RefundRate != 1000.0/6001 {
+		t.Errorf("refund rates = %+v, want one refund of 10.00", refundRates.Currencies)
+	}
+
+	for _, path := range []string{
+		"/analytics/volume?interval=year",
+		"/analytics/success-rates?group_by=country",
+		"/analytics/refund-rates?date_from=2026-01-02&date_to=2026-01-01",
+		"/analytics/average-amounts?currency=XX",
+	} {
+		if status := get(path, nil); status != fiber.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", path, status)
+		}
+	}
+}
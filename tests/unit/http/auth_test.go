@@ -0,0 +1,193 @@
+package http_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/ratelimit"
+	"Pay2Go/internal/usecases/apikey"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestAuthMiddleware_ScopesAndRoles(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	partners := memory.NewPartnerRepository(store)
+	apiKeys := memory.NewAPIKeyRepository(store)
+	users := memory.NewUserRepository(store)
+	sessions := memory.NewUserSessionRepository(store)
+
+	acme, _ := entities.NewPartner("Acme", "acme@example.com")
+	if err := partners.Create(ctx, acme); err != nil {
+		t.Fatalf("Create(partner) error: %v", err)
+	}
+	apiKey := func(scopes ...valueobjects.APIKeyScope) string {
+		t.Helper()
+		key, plaintext, err := entities.NewAPIKey(acme.ID, "ci", scopes, entities.AuthMethodBearer, true)
+		if err != nil {
+			t.Fatalf("NewAPIKey() error: %v", err)
+		}
+		if err := apiKeys.Create(ctx, key); err != nil {
+			t.Fatalf("Create(API key) error: %v", err)
+		}
+		return plaintext
+	}
+	session := func(role valueobjects.UserRole) string {
+		t.Helper()
+		user, err := entities.NewUser(acme.ID, string(role)+"@acme.example", "Member", role, "correct horse battery")
+		if err != nil {
+			t.Fatalf("NewUser() error: %v", err)
+		}
+		if err := users.Create(ctx, user); err != nil {
+			t.Fatalf("Create(user) error: %v", err)
+		}
+		session, token, err := entities.NewUserSession(user.ID, time.Hour)
+		if err != nil {
+			t.Fatalf("NewUserSession() error: %v", err)
+		}
+		if err := sessions.Create(ctx, session); err != nil {
+			t.Fatalf("Create(session) error: %v", err)
+		}
+		return token
+	}
+
+	auth := middleware.NewAuthMiddleware(partners, apikey.NewAuthenticator(apiKeys), nil, nil, users, sessions, nil)
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) }
+	app := fiber.New()
+	app.Use(auth.Handle)
+	app.Get("/transactions", middleware.RequireScope(valueobjects.ScopeReadOnly), ok)
+	app.Post("/refunds", middleware.RequireScope(valueobjects.ScopeRefunds), ok)
+	app.Post("/api-keys", middleware.RequireScope(valueobjects.ScopeAdmin), middleware.RequireRole(valueobjects.RoleOwner), ok)
+
+	payments := apiKey(valueobjects.ScopePayments)
+	refunds := apiKey(valueobjects.ScopeRefunds)
+	admin := apiKey(valueobjects.ScopeAdmin)
+	owner := session(valueobjects.RoleOwner)
+	developer := session(valueobjects.RoleDeveloper)
+	finance := session(valueobjects.RoleFinance)
+	readOnly := session(valueobjects.RoleReadOnly)
+
+	tests := []struct {
+		name, method, path, token string
+		want                      int
+	}{
+		// Any scope reads; writes need their own scope or admin
+		{"payments key reads", "GET", "/transactions", payments, fiber.StatusNoContent},
+		{"payments key refunds", "POST", "/refunds", payments, fiber.StatusForbidden},
+		{"refunds key refunds", "POST", "/refunds", refunds, fiber.StatusNoContent},
+		{"admin key refunds", "POST", "/refunds", admin, fiber.StatusNoContent},
+		{"refunds key manages keys", "POST", "/api-keys", refunds, fiber.StatusForbidden},
+
+		// Roles are only checked for sessions; keys go by scope alone
+		{"admin key manages keys", "POST", "/api-keys", admin, fiber.StatusNoContent},
+
+		// Sessions carry the scopes of the member's role, then the role itself is checked
+		{"owner manages keys", "POST", "/api-keys", owner, fiber.StatusNoContent},
+		{"developer manages keys", "POST", "/api-keys", developer, fiber.StatusForbidden},
+		{"finance refunds", "POST", "/refunds", finance, fiber.StatusNoContent},
+		{"finance manages keys", "POST", "/api-keys", finance, fiber.StatusForbidden},
+		{"read-only member reads", "GET", "/transactions", readOnly, fiber.StatusNoContent},
+		{"read-only member refunds", "POST", "/refunds", readOnly, fiber.StatusForbidden},
+
+		{"unknown key", "GET", "/transactions", payments[:8] + "unknown", fiber.StatusUnauthorized},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+tc.token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("%s %s error: %v", tc.method, tc.path, err)
+			}
+			if resp.StatusCode != tc.want {
+				t.Errorf("%s %s = %d, want %d", tc.method, tc.path, resp.StatusCode, tc.want)
+			}
+		})
+	}
+}
+
+// failingStore is a replay store that is down
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, int, time.Duration) (ports.RateLimitResult, error) {
+	return ports.RateLimitResult{}, errors.New("connection refused")
+}
+
+func (failingStore) Peek(context.Context, string, int, time.Duration) (ports.RateLimitResult, error) {
+	return ports.RateLimitResult{}, errors.New("connection refused")
+}
+
+func TestAuthMiddleware_SignedRequests(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	partners := memory.NewPartnerRepository(store)
+	apiKeys := memory.NewAPIKeyRepository(store)
+
+	acme, _ := entities.NewPartner("Acme", "acme@example.com")
+	if err := partners.Create(ctx, acme); err != nil {
+		t.Fatalf("Create(partner) error: %v", err)
+	}
+	key, secret, err := entities.NewAPIKey(acme.ID, "ci", []valueobjects.APIKeyScope{valueobjects.ScopeReadOnly}, entities.AuthMethodHMAC, true)
+	if err != nil {
+		t.Fatalf("NewAPIKey() error: %v", err)
+	}
+	if err := apiKeys.Create(ctx, key); err != nil {
+		t.Fatalf("Create(API key) error: %v", err)
+	}
+
+	newApp := func(replays ports.RateLimitStore) *fiber.App {
+		auth := middleware.NewAuthMiddleware(partners, apikey.NewAuthenticator(apiKeys), nil, nil, nil, nil, replays)
+		app := fiber.New()
+		app.Use(auth.Handle)
+		app.Get("/transactions", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+		return app
+	}
+	// sign signs a GET of /transactions made now, as partners do
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	emptyBody := sha256.Sum256(nil)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{timestamp, "GET", "/transactions", hex.EncodeToString(emptyBody[:])}, "\n")))
+	signature := hex.EncodeToString(mac.Sum(nil))
+	send := func(app *fiber.App, signature string) int {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/transactions", nil)
+		req.Header.Set("Authorization", "HMAC-SHA256 "+key.KeyPrefix+":"+signature)
+		req.Header.Set("X-Pay2Go-Timestamp", timestamp)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test() error: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	app := newApp(ratelimit.NewMemoryStore())
+	if got := send(app, signature); got != fiber.StatusNoContent {
+		t.Fatalf("signed request = %d, want %d", got, fiber.StatusNoContent)
+	}
+	// The same signature is refused the second time, however it is spelled
+	for _, replay := range []string{signature, strings.ToUpper(signature), strings.ToUpper(signature[:1]) + signature[1:]} {
+		if got := send(app, replay); got != fiber.StatusUnauthorized {
+			t.Errorf("replay %q = %d, want %d", replay, got, fiber.StatusUnauthorized)
+		}
+	}
+
+	// Without its replay store the API refuses signatures rather than
+	// accepting them unchecked
+	if got := send(newApp(failingStore{}), signature); got != fiber.StatusServiceUnavailable {
+		t.Errorf("signed request with the replay store down = %d, want %d", got, fiber.StatusServiceUnavailable)
+	}
+}
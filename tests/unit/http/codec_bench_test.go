@@ -0,0 +1,78 @@
+package http_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"Pay2Go/internal/adapters/http/codec"
+	"Pay2Go/internal/adapters/http/dto"
+)
+
+var createTransactionBody = []byte(`{
+	"idempotency_key": "order-10023",
+	"amount": "149.90",
+	"currency": "USD",
+	"payment_method": "card",
+	"provider": "stripe",
+	"payment_method_details": {"card": {"brand": "visa", "last4": "4242", "exp_month": 12, "exp_year": 2030}},
+	"customer_email": "jane@example.com",
+	"customer_name": "Jane Doe",
+	"description": "Order #10023",
+	"metadata": {"order_id": "10023", "channel": "web"}
+}`)
+
+var createTransactionResponse = dto.CreateTransactionResponse{
+	TransactionID: "0b8f7f3e-6c1a-4a8e-9d55-2f1d6c1f7a10",
+	Status:        "pending",
+	Amount:        "149.90",
+	Currency:      "USD",
+	CreatedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+// newCtx returns a context for a JSON request with body, released when the
+// benchmark or test ends
+func newCtx(tb testing.TB, body []byte) *fiber.Ctx {
+	app := fiber.New()
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(fiber.MethodPost)
+	fctx.Request.Header.SetContentType(fiber.MIMEApplicationJSON)
+	fctx.Request.SetBody(body)
+	c := app.AcquireCtx(fctx)
+	tb.Cleanup(func() { app.ReleaseCtx(c) })
+	return c
+}
+
+// BenchmarkCreateTransactionJSON compares Fiber's BodyParser and JSON with
+// the pooled codec the transaction endpoints use, for a typical create
+// request and its response. Run with -benchmem.
+func BenchmarkCreateTransactionJSON(b *testing.B) {
+	b.Run("Fiber", func(b *testing.B) {
+		c := newCtx(b, createTransactionBody)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var req dto.CreateTransactionRequest
+			if err := c.BodyParser(&req); err != nil {
+				b.Fatal(err)
+			}
+			if err := c.Status(fiber.StatusCreated).JSON(createTransactionResponse); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Codec", func(b *testing.B) {
+		c := newCtx(b, createTransactionBody)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var req dto.CreateTransactionRequest
+			if err := codec.Decode(c, &req); err != nil {
+				b.Fatal(err)
+			}
+			if err := codec.Respond(c, fiber.StatusCreated, createTransactionResponse); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package http_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/codec"
+	"Pay2Go/internal/adapters/http/dto"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"create request", string(createTransactionBody), false},
+		{"trailing whitespace", `{"amount":"1.00"}` + " \n\t", false},
+		{"trailing value", `{"amount":"1.00"} {"amount":"2.00"}`, true},
+		{"trailing brace", `{"amount":"1.00"}}`, true},
+		{"truncated", `{"amount":"1.0`, true},
+		{"empty", ``, true},
+		// Pooled decoders must not carry anything over from the bodies above
+		{"after failures", `{"amount":"3.00","currency":"EUR"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req dto.CreateTransactionRequest
+			err := codec.Decode(newCtx(t, []byte(tt.body)), &req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var want dto.CreateTransactionRequest
+			if err := json.Unmarshal([]byte(tt.body), &want); err != nil {
+				t.Fatalf("json.Unmarshal() error: %v", err)
+			}
+			if req.Amount != want.Amount || req.Currency != want.Currency || len(req.Metadata) != len(want.Metadata) {
+				t.Errorf("Decode() = %+v, want %+v", req, want)
+			}
+		})
+	}
+}
+
+func TestDecode_FormBody(t *testing.T) {
+	c := newCtx(t, []byte("amount=5.00&currency=USD"))
+	c.Request().Header.SetContentType(fiber.MIMEApplicationForm)
+
+	var req struct {
+		Amount   string `form:"amount"`
+		Currency string `form:"currency"`
+	}
+	if err := codec.Decode(c, &req); err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	if req.Amount != "5.00" || req.Currency != "USD" {
+		t.Errorf("Decode() = %+v, want the form values", req)
+	}
+}
+
+func TestRespond(t *testing.T) {
+	c := newCtx(t, nil)
+	c.Response().SetBodyString("stale")
+
+	if err := codec.Respond(c, fiber.StatusCreated, createTransactionResponse); err != nil {
+		t.Fatalf("Respond() error: %v", err)
+	}
+	if got := c.Response().StatusCode(); got != fiber.StatusCreated {
+		t.Errorf("status = %d, want %d", got, fiber.StatusCreated)
+	}
+	if got := string(c.Response().Header.ContentType()); got != fiber.MIMEApplicationJSON {
+		t.Errorf("content type = %q, want %q", got, fiber.MIMEApplicationJSON)
+	}
+
+	want, _ := json.Marshal(createTransactionResponse)
+	if got := string(c.Response().Body()); got != string(want)+"\n" {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestConditionalList_Transactions(t *testing.T) {
+	ctx := context.Background()
+	transactions := memory.NewTransactionRepository(memory.NewStore())
+	partnerID := uuid.New()
+	money, _ := valueobjects.NewMoney(1250, "USD")
+	create := func(key string) *entities.Transaction {
+		t.Helper()
+		txn, err := entities.NewTransaction(partnerID, key, money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+		if err != nil {
+			t.Fatalf("NewTransaction() error: %v", err)
+		}
+		if err := transactions.Create(ctx, txn); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+		return txn
+	}
+	create("key-1")
+
+	transactionHandler := handlers.NewTransactionHandler(
+		nil, nil,
+		transaction.NewListTransactionsUseCase(transactions),
+		nil, nil, nil, nil, nil, nil, nil,
+	)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partnerID)
+		return c.Next()
+	})
+	app.Get("/transactions", middleware.NewConditionalList().Handle, transactionHandler.ListTransactions)
+
+	get := func(path, ifNoneMatch string, want int) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("GET %s error: %v", path, err)
+		}
+		if resp.StatusCode != want {
+			raw, _ := io.ReadAll(resp.Body)
+			t.Fatalf("GET %s with If-None-Match %q status = %d, want %d: %s", path, ifNoneMatch, resp.StatusCode, want, raw)
+		}
+		return resp
+	}
+
+	// Pages are tagged weakly and must be checked back every time
+	first := get("/transactions", "", fiber.StatusOK)
+	etag := first.Header.Get(fiber.HeaderETag)
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q, want a weak tag", etag)
+	}
+	if got := first.Header.Get(fiber.HeaderCacheControl); got != "private, no-cache" {
+		t.Errorf("Cache-Control = %q, want private, no-cache", got)
+	}
+
+	// An unchanged page is not sent again, however the tag comes back
+	for _, ifNoneMatch := range []string{
+		etag,
+		strings.TrimPrefix(etag, "W/"),
+		`W/"0-0", ` + etag,
+		"*",
+	} {
+		resp := get("/transactions", ifNoneMatch, fiber.StatusNotModified)
+		if body, _ := io.ReadAll(resp.Body); len(body) != 0 {
+			t.Errorf("If-None-Match %q: 304 has body %q", ifNoneMatch, body)
+		}
+		if got := resp.Header.Get(fiber.HeaderETag); got != etag {
+			t.Errorf("If-None-Match %q: 304 ETag = %q, want %q", ifNoneMatch, got, etag)
+		}
+	}
+
+	// Another page has a tag of its own
+	if other := get("/transactions?limit=1&offset=1", etag, fiber.StatusOK).Header.Get(fiber.HeaderETag); other == etag {
+		t.Errorf("another page has the same ETag %q", other)
+	}
+
+	// Once the page changes it is sent in full, with a new tag
+	create("key-2")
+	changed := get("/transactions", etag, fiber.StatusOK)
+	newTag := changed.Header.Get(fiber.HeaderETag)
+	if newTag == "" || newTag == etag {
+		t.Errorf("changed page ETag = %q, want a new one", newTag)
+	}
+	if body, _ := io.ReadAll(changed.Body); !strings.Contains(string(body), "key-2") {
+		t.Errorf("changed page = %s, want the new transaction", body)
+	}
+	get("/transactions", newTag, fiber.StatusNotModified)
+
+	// Errors are neither tagged nor reported unchanged
+	refused := get("/transactions?limit=abc", "*", fiber.StatusBadRequest)
+	if got := refused.Header.Get(fiber.HeaderETag); got != "" {
+		t.Errorf("error response ETag = %q, want none", got)
+	}
+}
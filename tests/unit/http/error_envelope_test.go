@@ -0,0 +1,121 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+)
+
+// panicLog records what Recovery logs
+type panicLog struct {
+	entries []string
+}
+
+func (l *panicLog) Error(message string, args ...interface{}) {
+	l.entries = append(l.entries, fmt.Sprintf(message, args...))
+}
+
+func TestRespondError(t *testing.T) {
+	log := &panicLog{}
+	app := fiber.New()
+	app.Use(middleware.RequestID)
+	app.Use(middleware.NewRecovery(log, nil).Handle)
+	app.Get("/refunds", func(c *fiber.Ctx) error {
+		return middleware.RespondError(c, fiber.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "amount: must be positive",
+			Param:   "amount",
+		})
+	})
+	app.Get("/panic", func(c *fiber.Ctx) error {
+		panic("boom")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		want   dto.ErrorResponse
+	}{
+		{
+			path:   "/refunds",
+			status: fiber.StatusBadRequest,
+			want: dto.ErrorResponse{
+				Type:    middleware.ErrorTypeInvalidRequest,
+				Code:    "validation_error",
+				Error:   "validation_error",
+				Message: "amount: must be positive",
+				Param:   "amount",
+				DocURL:  middleware.ErrorDocsURL + "validation_error",
+			},
+		},
+		{
+			path:   "/panic",
+			status: fiber.StatusInternalServerError,
+			want: dto.ErrorResponse{
+				Type:    middleware.ErrorTypeAPI,
+				Code:    "internal_server_error",
+				Error:   "internal_server_error",
+				Message: "An unexpected error occurred",
+				DocURL:  middleware.ErrorDocsURL + "internal_server_error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil))
+			if err != nil || resp.StatusCode != tt.status {
+				t.Fatalf("GET %s = %v, %v; want %d", tt.path, resp, err, tt.status)
+			}
+			var got dto.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.RequestID == "" || got.RequestID != resp.Header.Get(middleware.HeaderRequestID) {
+				t.Errorf("request_id = %q, want the X-Request-ID header %q", got.RequestID, resp.Header.Get(middleware.HeaderRequestID))
+			}
+			got.RequestID = ""
+			if got.Type != tt.want.Type || got.Code != tt.want.Code || got.Error != tt.want.Error ||
+				got.Message != tt.want.Message || got.Param != tt.want.Param || got.DocURL != tt.want.DocURL {
+				t.Errorf("body = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// The panic is logged with its request and where it was raised
+	if len(log.entries) != 1 {
+		t.Fatalf("logged %d panics, want 1", len(log.entries))
+	}
+	entry := log.entries[0]
+	if !strings.Contains(entry, "request_id=") || strings.Contains(entry, "request_id= ") ||
+		!strings.Contains(entry, "path=/panic panic=boom") || !strings.Contains(entry, "error_envelope_test.go") {
+		t.Errorf("logged %q, want the request ID, path, panic and stack", entry)
+	}
+}
+
+func TestErrorTypeAndStatusErrorCode(t *testing.T) {
+	types := map[int]string{
+		fiber.StatusNotFound:            middleware.ErrorTypeInvalidRequest,
+		fiber.StatusUnauthorized:        middleware.ErrorTypeAuthentication,
+		fiber.StatusForbidden:           middleware.ErrorTypePermission,
+		fiber.StatusConflict:            middleware.ErrorTypeConflict,
+		fiber.StatusPreconditionFailed:  middleware.ErrorTypeConflict,
+		fiber.StatusTooManyRequests:     middleware.ErrorTypeRateLimit,
+		fiber.StatusServiceUnavailable:  middleware.ErrorTypeAPI,
+		fiber.StatusUnprocessableEntity: middleware.ErrorTypeInvalidRequest,
+	}
+	for status, want := range types {
+		if got := middleware.ErrorType(status); got != want {
+			t.Errorf("ErrorType(%d) = %s, want %s", status, got, want)
+		}
+	}
+	if got := middleware.StatusErrorCode(fiber.StatusRequestEntityTooLarge); got != "request_entity_too_large" {
+		t.Errorf("StatusErrorCode(413) = %s", got)
+	}
+}
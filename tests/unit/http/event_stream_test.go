@@ -0,0 +1,107 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/usecases/outbox"
+)
+
+func TestEventStreamHandler_Stream(t *testing.T) {
+	ctx := context.Background()
+	outboxRepo := memory.NewOutboxRepository(memory.NewStore())
+	partnerID, otherID := uuid.New(), uuid.New()
+
+	recorded := time.Now().Add(-time.Minute)
+	add := func(partnerID uuid.UUID, aggregateType, eventType string, livemode bool) *entities.OutboxEvent {
+		event, err := entities.NewOutboxEvent(partnerID, aggregateType, uuid.New(), eventType, map[string]bool{"livemode": livemode})
+		if err != nil {
+			t.Fatalf("NewOutboxEvent() error = %v", err)
+		}
+		recorded = recorded.Add(time.Second)
+		event.CreatedAt = recorded
+		if err := outboxRepo.Add(ctx, event); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		return event
+	}
+	completed := add(partnerID, "transaction", entities.EventPaymentCompleted, true)
+	add(partnerID, "transaction", entities.EventPaymentCompleted, false)
+	add(partnerID, "dispute", entities.EventDisputeCreated, true)
+	add(otherID, "transaction", entities.EventPaymentCompleted, true)
+	refunded := add(partnerID, "refund", entities.EventRefundCompleted, true)
+
+	// A closed handler answers one read of the stream, then ends it
+	eventStreamHandler := handlers.NewEventStreamHandler(outbox.NewStreamEventsUseCase(outboxRepo), time.Millisecond)
+	eventStreamHandler.Close()
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partnerID)
+		c.Locals("livemode", true)
+		return c.Next()
+	})
+	app.Get("/events/stream", eventStreamHandler.Stream)
+
+	stream := func(lastEventID string, want int) string {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodGet, "/events/stream", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("GET /events/stream error: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != want {
+			t.Fatalf("status = %d, want %d: %s", resp.StatusCode, want, body)
+		}
+		if want == fiber.StatusOK && resp.Header.Get(fiber.HeaderContentType) != "text/event-stream" {
+			t.Errorf("content type = %q", resp.Header.Get(fiber.HeaderContentType))
+		}
+		return string(body)
+	}
+
+	// A new stream only carries what is recorded from now on
+	if body := stream("", fiber.StatusOK); strings.Contains(body, "event:") {
+		t.Errorf("new stream replayed events: %s", body)
+	}
+
+	// Resuming from the start carries the partner's live transaction and
+	// refund events, in order
+	body := stream("0_"+uuid.Nil.String(), fiber.StatusOK)
+	if !strings.HasPrefix(body, "retry: 5000\n\n") {
+		t.Errorf("stream does not start with the retry delay: %q", body)
+	}
+	messages := strings.Split(strings.TrimSpace(body), "\n\n")[1:]
+	if len(messages) != 2 {
+		t.Fatalf("stream = %q, want two events", body)
+	}
+	for i, event := range []*entities.OutboxEvent{completed, refunded} {
+		if !strings.Contains(messages[i], "event: "+event.EventType+"\n") ||
+			!strings.Contains(messages[i], `data: {"id":"`+event.ID.String()+`"`) {
+			t.Errorf("message %d = %q, want %s %s", i, messages[i], event.EventType, event.ID)
+		}
+	}
+
+	// Resuming after the first event carries the second
+	firstID := strings.TrimPrefix(strings.SplitN(messages[0], "\n", 2)[0], "id: ")
+	body = stream(firstID, fiber.StatusOK)
+	if strings.Contains(body, completed.ID.String()) || !strings.Contains(body, refunded.ID.String()) {
+		t.Errorf("resumed stream = %q, want only %s", body, refunded.ID)
+	}
+
+	if body := stream("not-an-id", fiber.StatusBadRequest); !strings.Contains(body, "invalid_last_event_id") {
+		t.Errorf("body = %s, want invalid_last_event_id", body)
+	}
+}
@@ -0,0 +1,90 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestExportEncoderLayout(t *testing.T) {
+	money, _ := valueobjects.NewMoney(1050, "USD")
+	txn, err := entities.NewTransaction(uuid.New(), "order-1", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	if err != nil {
+		t.Fatalf("NewTransaction() error: %v", err)
+	}
+	txn.CreatedAt = time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	txn.Description = `=HYPERLINK("http://evil.example"), "quoted"`
+
+	encode := func(format entities.ExportFormat, layout entities.ExportLayout) (string, error) {
+		t.Helper()
+		var buf bytes.Buffer
+		file, err := handlers.NewExportEncoder().NewFile(&buf, entities.ExportResourceTransactions, format, layout)
+		if err != nil {
+			return "", err
+		}
+		if err := file.WriteTransaction(txn); err != nil {
+			t.Fatalf("WriteTransaction() error: %v", err)
+		}
+		if err := file.Close(); err != nil {
+			t.Fatalf("Close() error: %v", err)
+		}
+		return buf.String(), nil
+	}
+
+	// Chosen columns, in their order, with times in the chosen zone; the
+	// description is quoted and kept from running as a formula
+	data, err := encode(entities.ExportFormatCSV, entities.ExportLayout{
+		Columns:  []string{"description", "amount", "created_at"},
+		Timezone: "Asia/Tokyo",
+	})
+	if err != nil {
+		t.Fatalf("NewFile() error: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewBufferString(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("export %q is not CSV: %v", data, err)
+	}
+	want := [][]string{
+		{"description", "amount", "created_at"},
+		{`'=HYPERLINK("http://evil.example"), "quoted"`, "10.50", "2024-03-02T05:00:00+09:00"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("export = %q, want a header and one row", data)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Errorf("record %d field %d = %q, want %q", i, j, records[i][j], want[i][j])
+			}
+		}
+	}
+
+	// NDJSON keeps the API's representation, in the chosen zone
+	data, err = encode(entities.ExportFormatNDJSON, entities.ExportLayout{Timezone: "America/New_York"})
+	if err != nil {
+		t.Fatalf("NewFile() error: %v", err)
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &row); err != nil || row["created_at"] != "2024-03-01T15:00:00-05:00" || row["description"] != txn.Description {
+		t.Errorf("export = %q, want the transaction in New York time", data)
+	}
+
+	// Layouts that do not fit the resource are refused
+	for name, layout := range map[string]entities.ExportLayout{
+		"unknown column":   {Columns: []string{"id", "reason"}},
+		"repeated column":  {Columns: []string{"id", "id"}},
+		"unknown timezone": {Timezone: "Mars/Olympus_Mons"},
+	} {
+		if _, err := encode(entities.ExportFormatCSV, layout); err == nil {
+			t.Errorf("NewFile() with %s succeeded, want an error", name)
+		}
+	}
+}
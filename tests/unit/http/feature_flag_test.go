@@ -0,0 +1,107 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestFeatureFlags_RejectDisabledFeatures(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	partners := memory.NewPartnerRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+
+	partner, err := entities.NewPartner("Acme", "acme@example.com")
+	if err != nil {
+		t.Fatalf("NewPartner() error: %v", err)
+	}
+	if err := partners.Create(ctx, partner); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	setFeature := func(feature valueobjects.PartnerFeature, enabled bool) {
+		t.Helper()
+		stored, err := partners.GetByID(ctx, partner.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error: %v", err)
+		}
+		if err := stored.SetFeature(feature, enabled); err != nil {
+			t.Fatalf("SetFeature() error: %v", err)
+		}
+		if err := partners.Update(ctx, stored); err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+	}
+
+	transactionHandler := handlers.NewTransactionHandler(
+		transaction.NewCreateTransactionUseCase(transactions, memory.NewOutboxRepository(store), memory.NewUnitOfWork(store), partners, nil, payment.NewMockPaymentGateway("mock"), nil, nil, nil),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil,
+	)
+	refundHandler := handlers.NewRefundHandler(
+		nil, nil,
+		transaction.NewBulkRefundUseCase(partners, memory.NewBulkRefundJobRepository(store), nil),
+		nil, nil, nil, nil,
+	)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partner.ID)
+		return c.Next()
+	})
+	app.Post("/transactions", transactionHandler.CreateTransaction)
+	app.Post("/refunds/bulk", refundHandler.BulkRefund)
+
+	post := func(path, body string, want int) dto.ErrorResponse {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("POST %s error: %v", path, err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != want {
+			t.Fatalf("POST %s status = %d, want %d: %s", path, resp.StatusCode, want, raw)
+		}
+		var response dto.ErrorResponse
+		_ = json.Unmarshal(raw, &response)
+		return response
+	}
+	crypto := func(key string) string {
+		return `{"idempotency_key":"` + key + `","amount":"25.00","currency":"USD","payment_method":"crypto","provider":"stripe","customer_email":"customer@example.com"}`
+	}
+	bulkRefund := `{"transaction_ids":["` + uuid.NewString() + `"],"reason":"duplicate"}`
+
+	// Crypto payments are off by default
+	refused := post("/transactions", crypto("crypto-1"), fiber.StatusForbidden)
+	if refused.Code != "feature_disabled" || refused.Type != middleware.ErrorTypePermission || !strings.Contains(refused.Message, "crypto") {
+		t.Errorf("crypto payment refused with %+v, want feature_disabled", refused)
+	}
+	if txn, err := transactions.GetByIdempotencyKey(ctx, partner.ID, "crypto-1"); err == nil && txn != nil {
+		t.Errorf("transaction %s stored after the refusal", txn.ID)
+	}
+	setFeature(valueobjects.FeatureCrypto, true)
+	post("/transactions", crypto("crypto-2"), fiber.StatusCreated)
+
+	// Bulk refunds are on by default, until turned off
+	post("/refunds/bulk", bulkRefund, fiber.StatusAccepted)
+	setFeature(valueobjects.FeatureBulkRefunds, false)
+	refused = post("/refunds/bulk", bulkRefund, fiber.StatusForbidden)
+	if refused.Code != "feature_disabled" || !strings.Contains(refused.Message, "bulk refunds") {
+		t.Errorf("bulk refund refused with %+v, want feature_disabled", refused)
+	}
+}
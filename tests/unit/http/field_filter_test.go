@@ -0,0 +1,74 @@
+package http_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+)
+
+func TestFilterFields(t *testing.T) {
+	partner, err := entities.NewPartner("Acme", "acme@example.com")
+	if err != nil {
+		t.Fatalf("NewPartner() error: %v", err)
+	}
+	if err := partner.SetFieldFilter(map[string][]string{"payments": {"error_code"}}, nil); err != nil {
+		t.Fatalf("SetFieldFilter() error: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner", partner)
+		var scopes []valueobjects.APIKeyScope
+		for _, scope := range c.Request().Header.PeekAll("X-Scope") {
+			scopes = append(scopes, valueobjects.APIKeyScope(scope))
+		}
+		c.Locals("scopes", scopes)
+		return c.Next()
+	})
+	app.Use(middleware.FilterFields)
+	app.Get("/json", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"transactions": []fiber.Map{{"id": "t1", "error_code": "card_declined"}}})
+	})
+	app.Get("/csv", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "text/csv")
+		return c.SendString(`{"error_code":"card_declined"}`)
+	})
+
+	call := func(path string, scopes ...string) string {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		for _, scope := range scopes {
+			req.Header.Add("X-Scope", scope)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("GET %s error: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	tests := []struct {
+		path   string
+		scopes []string
+		want   string
+	}{
+		{"/json", []string{"payments"}, `{"transactions":[{"id":"t1"}]}`},
+		// Another scope of the key sees the field
+		{"/json", []string{"payments", "read_only"}, `{"transactions":[{"error_code":"card_declined","id":"t1"}]}`},
+		{"/json", []string{"admin"}, `{"transactions":[{"error_code":"card_declined","id":"t1"}]}`},
+		// Only JSON is shaped
+		{"/csv", []string{"payments"}, `{"error_code":"card_declined"}`},
+	}
+	for _, tt := range tests {
+		if got := call(tt.path, tt.scopes...); got != tt.want {
+			t.Errorf("GET %s with %v = %s, want %s", tt.path, tt.scopes, got, tt.want)
+		}
+	}
+}
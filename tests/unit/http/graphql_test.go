@@ -0,0 +1,232 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+	refunds := memory.NewRefundRepository(store)
+	events := memory.NewOutboxRepository(store)
+
+	partnerID := uuid.New()
+	money, _ := valueobjects.NewMoney(1250, "USD")
+	txn, _ := entities.NewTransaction(partnerID, "key-1", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	_ = transactions.Create(ctx, txn)
+	others, _ := entities.NewTransaction(uuid.New(), "key-1", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "other@example.com")
+	_ = transactions.Create(ctx, others)
+	refundAmount, _ := valueobjects.NewMoney(500, "USD")
+	reason, _ := valueobjects.NewRefundReason("requested_by_customer", "")
+	refund, _ := entities.NewRefund(txn.ID, refundAmount, reason)
+	_ = refunds.Create(ctx, refund)
+	event, _ := entities.NewOutboxEvent(partnerID, "transaction", txn.ID, entities.EventPaymentCreated, map[string]string{"id": txn.ID.String()})
+	_ = events.Add(ctx, event)
+
+	graphqlHandler := handlers.NewGraphQLHandler(
+		transaction.NewGetTransactionUseCase(transactions, nil, 0),
+		transaction.NewListTransactionsUseCase(transactions),
+		transaction.NewGetRefundUseCase(transactions, refunds),
+		transaction.NewListRefundsUseCase(refunds),
+		outbox.NewListEventsUseCase(events),
+	)
+	app := fiber.New()
+	app.Post("/graphql", func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partnerID)
+		return c.Next()
+	}, graphqlHandler.Query)
+
+	post := func(body string) (int, map[string]interface{}) {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodPost, "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("POST /graphql error: %v", err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			t.Fatalf("response %s is not JSON: %v", raw, err)
+		}
+		return resp.StatusCode, decoded
+	}
+
+	query, _ := json.Marshal(map[string]interface{}{
+		"query": `query Dashboard($id: ID!) {
+			transaction(id: $id) { ...summary refunds { amount reason transaction { id } } events { type delivered } }
+			transactions(status: ["pending"]) { totalCount nodes { id } }
+			other: transaction(id: "` + others.ID.String() + `") { id }
+		}
+		fragment summary on Transaction { id amount currency refundedAmount }`,
+		"variables": map[string]interface{}{"id": txn.ID.String()},
+	})
+	status, body := post(string(query))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200: %v", status, body)
+	}
+	data := body["data"].(map[string]interface{})
+
+	got := data["transaction"].(map[string]interface{})
+	if got["id"] != txn.ID.String() || got["amount"] != "12.50" || got["currency"] != "USD" || got["refundedAmount"] != "0.00" {
+		t.Errorf("transaction = %v", got)
+	}
+	refundList := got["refunds"].([]interface{})
+	if len(refundList) != 1 {
+		t.Fatalf("refunds = %v, want one", refundList)
+	}
+	gotRefund := refundList[0].(map[string]interface{})
+	if gotRefund["amount"] != "5.00" || gotRefund["reason"] != "requested_by_customer" ||
+		gotRefund["transaction"].(map[string]interface{})["id"] != txn.ID.String() {
+		t.Errorf("refund = %v", gotRefund)
+	}
+	if eventList := got["events"].([]interface{}); len(eventList) != 1 ||
+		eventList[0].(map[string]interface{})["type"] != entities.EventPaymentCreated {
+		t.Errorf("events = %v", eventList)
+	}
+	if page := data["transactions"].(map[string]interface{}); page["totalCount"] != float64(1) {
+		t.Errorf("transactions = %v, want only the partner's own", page)
+	}
+
+	// Another partner's transaction is refused, without failing the rest
+	if data["other"] != nil {
+		t.Errorf("other = %v, want null", data["other"])
+	}
+	errs := body["errors"].([]interface{})
+	if len(errs) != 1 {
+		t.Fatalf("errors = %v, want one", errs)
+	}
+	gotErr := errs[0].(map[string]interface{})
+	if code := gotErr["extensions"].(map[string]interface{})["code"]; code != "unauthorized_operation" {
+		t.Errorf("error code = %v, want unauthorized_operation", code)
+	}
+	if path := gotErr["path"].([]interface{}); len(path) != 1 || path[0] != "other" {
+		t.Errorf("error path = %v, want [other]", path)
+	}
+
+	// Invalid queries do not run
+	status, body = post(`{"query": "{ transaction(id: \"x\") { id cardNumber } }"}`)
+	if status != fiber.StatusBadRequest {
+		t.Errorf("invalid query status = %d, want 400", status)
+	}
+	if _, hasData := body["data"]; hasData {
+		t.Errorf("invalid query answered with data: %v", body)
+	}
+	errs, _ = body["errors"].([]interface{})
+	if len(errs) != 1 || !strings.Contains(errs[0].(map[string]interface{})["message"].(string), `"cardNumber"`) {
+		t.Errorf("invalid query errors = %v", errs)
+	}
+
+	// The schema can be introspected, down to type references nesting
+	// deeper than queries may
+	status, body = post(`{"query": "{ __type(name: \"Transaction\") { fields { name type { kind ofType { kind ofType { kind ofType { name } } } } } } }"}`)
+	if status != fiber.StatusOK {
+		t.Fatalf("introspection status = %d, want 200: %v", status, body)
+	}
+	fields := body["data"].(map[string]interface{})["__type"].(map[string]interface{})["fields"].([]interface{})
+	var refundsType interface{}
+	for _, field := range fields {
+		if field := field.(map[string]interface{}); field["name"] == "refunds" {
+			refundsType = field["type"]
+		}
+	}
+	// [Refund!]!
+	if got, _ := json.Marshal(refundsType); !strings.Contains(string(got), `"ofType":{"name":"Refund"}`) {
+		t.Errorf("refunds type = %s, want [Refund!]!", got)
+	}
+}
+
+func TestGraphQLHandler_RejectsExpensiveQueries(t *testing.T) {
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+	refunds := memory.NewRefundRepository(store)
+	graphqlHandler := handlers.NewGraphQLHandler(
+		transaction.NewGetTransactionUseCase(transactions, nil, 0),
+		transaction.NewListTransactionsUseCase(transactions),
+		transaction.NewGetRefundUseCase(transactions, refunds),
+		transaction.NewListRefundsUseCase(refunds),
+		outbox.NewListEventsUseCase(memory.NewOutboxRepository(store)),
+	)
+	app := fiber.New()
+	app.Post("/graphql", func(c *fiber.Ctx) error {
+		c.Locals("partner_id", uuid.New())
+		return c.Next()
+	}, graphqlHandler.Query)
+
+	// fragments builds a chain of n fragments, each spreading the next one
+	// through body, the last selecting only id
+	fragments := func(n int, body string) string {
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			next := "id"
+			if i < n-1 {
+				next = strings.ReplaceAll(body, "NEXT", fmt.Sprintf("f%d", i+1))
+			}
+			fmt.Fprintf(&b, "fragment f%d on Transaction { %s }\n", i, next)
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "fragments doubling at every spread",
+			query: `{ transaction(id: "` + uuid.NewString() + `") { ...f0 } }` + "\n" + fragments(26, "...NEXT ...NEXT"),
+			want:  "maximum of 500 fields",
+		},
+		{
+			name:  "fragments nesting past the maximum depth",
+			query: `{ transaction(id: "` + uuid.NewString() + `") { ...f0 } }` + "\n" + fragments(8, "refunds(first: 1) { transaction { ...NEXT } }"),
+			want:  "exceeds max depth 6",
+		},
+		{
+			name:  "lists of lists",
+			query: `{ transactions(first: 100) { nodes { refunds(first: 1000) { id } } } }`,
+			want:  "maximum of 50000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"query": tt.query})
+			req := httptest.NewRequest(fiber.MethodPost, "/graphql", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			start := time.Now()
+			resp, err := app.Test(req, 5000)
+			if err != nil {
+				t.Fatalf("POST /graphql error: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("query took %v to refuse", elapsed)
+			}
+			if resp.StatusCode != fiber.StatusBadRequest {
+				t.Errorf("status = %d, want 400", resp.StatusCode)
+			}
+			raw, _ := io.ReadAll(resp.Body)
+			if !strings.Contains(string(raw), tt.want) {
+				t.Errorf("response = %s, want an error mentioning %q", raw, tt.want)
+			}
+		})
+	}
+}
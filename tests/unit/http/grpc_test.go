@@ -0,0 +1,260 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	grpcapi "Pay2Go/internal/adapters/grpc"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	pay2gov1 "Pay2Go/internal/gen/pay2go/v1"
+	"Pay2Go/internal/usecases/apikey"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// serveGRPC serves api on a local port until the test ends, returning a
+// client connection to it
+func serveGRPC(t *testing.T, api *grpcapi.Server) *grpc.ClientConn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	go func() { _ = api.Serve(listener) }()
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = api.Stop(ctx)
+	})
+	return conn
+}
+
+// withAPIKey returns a context whose calls carry apiKey
+func withAPIKey(apiKey string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+apiKey)
+}
+
+func TestGRPCServer_GetTransaction(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	partners := memory.NewPartnerRepository(store)
+	apiKeys := memory.NewAPIKeyRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+
+	partner, _ := entities.NewPartner("Acme", "acme@example.com")
+	_ = partners.Create(ctx, partner)
+	readKey, readPlaintext, _ := entities.NewAPIKey(partner.ID, "reports", []valueobjects.APIKeyScope{valueobjects.ScopeReadOnly}, entities.AuthMethodBearer, true)
+	_ = apiKeys.Create(ctx, readKey)
+	money, _ := valueobjects.NewMoney(1250, "USD")
+	txn, _ := entities.NewTransaction(partner.ID, "key-1", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	_ = transactions.Create(ctx, txn)
+
+	conn := serveGRPC(t, grpcapi.NewServer(
+		apikey.NewAuthenticator(apiKeys),
+		partners,
+		nil,
+		nil,
+		transaction.NewGetTransactionUseCase(transactions, nil, 0),
+		nil,
+		nil,
+		nil,
+		nil,
+	))
+	client := pay2gov1.NewPaymentsClient(conn)
+
+	got, err := client.GetTransaction(withAPIKey(readPlaintext), &pay2gov1.GetTransactionRequest{TransactionId: txn.ID.String()})
+	if err != nil {
+		t.Fatalf("GetTransaction() error: %v", err)
+	}
+	if got.Id != txn.ID.String() || got.Amount != "12.50" || got.Currency != "USD" || !got.Livemode ||
+		!got.CreatedAt.AsTime().Equal(txn.CreatedAt) || got.ProcessedAt != nil {
+		t.Errorf("GetTransaction = %v", got)
+	}
+
+	cases := []struct {
+		name string
+		call func(ctx context.Context, trailer *metadata.MD) error
+		key  string
+		code codes.Code
+		want string
+	}{
+		{"unknown key", func(ctx context.Context, trailer *metadata.MD) error {
+			_, err := client.GetTransaction(ctx, &pay2gov1.GetTransactionRequest{TransactionId: txn.ID.String()}, grpc.Trailer(trailer))
+			return err
+		}, "pk_live_unknown", codes.Unauthenticated, "unauthorized"},
+		{"missing scope", func(ctx context.Context, trailer *metadata.MD) error {
+			_, err := client.Refund(ctx, &pay2gov1.RefundRequest{}, grpc.Trailer(trailer))
+			return err
+		}, readPlaintext, codes.PermissionDenied, "insufficient_scope"},
+		{"unknown transaction", func(ctx context.Context, trailer *metadata.MD) error {
+			_, err := client.GetTransaction(ctx, &pay2gov1.GetTransactionRequest{TransactionId: uuid.New().String()}, grpc.Trailer(trailer))
+			return err
+		}, readPlaintext, codes.NotFound, "transaction_not_found"},
+		{"invalid transaction ID", func(ctx context.Context, trailer *metadata.MD) error {
+			_, err := client.GetTransaction(ctx, &pay2gov1.GetTransactionRequest{TransactionId: "txn-1"}, grpc.Trailer(trailer))
+			return err
+		}, readPlaintext, codes.InvalidArgument, "invalid_transaction_id"},
+		{"unknown method", func(ctx context.Context, trailer *metadata.MD) error {
+			return conn.Invoke(ctx, "/"+grpcapi.ServiceName+"/CaptureTransaction", &pay2gov1.GetTransactionRequest{}, &pay2gov1.Transaction{}, grpc.Trailer(trailer))
+		}, readPlaintext, codes.Unimplemented, "unknown_method"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var trailer metadata.MD
+			err := tc.call(withAPIKey(tc.key), &trailer)
+			if code := status.Code(err); code != tc.code || strings.Join(trailer.Get("pay2go-error-code"), ",") != tc.want {
+				t.Errorf("error = %v with %v, want %s with %q", err, trailer.Get("pay2go-error-code"), tc.code, tc.want)
+			}
+		})
+	}
+}
+
+// callObserver records the calls the metrics interceptor observes
+type callObserver struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (o *callObserver) ObserveCall(method, code string, _ time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, method+" "+code)
+}
+
+func TestGRPCServer_HealthAndReflection(t *testing.T) {
+	observer := &callObserver{}
+	api := grpcapi.NewServer(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	api.Use(grpcapi.RequestID, grpcapi.Metrics(observer), grpcapi.Recovery(nil))
+	conn := serveGRPC(t, api)
+	health := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+
+	// Health checks need no API key
+	check := func(service string) (healthpb.HealthCheckResponse_ServingStatus, codes.Code) {
+		t.Helper()
+		response, err := health.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		return response.GetStatus(), status.Code(err)
+	}
+	if got, code := check(""); code != codes.OK || got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check() = %s with %s, want SERVING", got, code)
+	}
+	if got, code := check(grpcapi.ServiceName); code != codes.OK || got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check(Payments) = %s with %s, want SERVING", got, code)
+	}
+	if _, code := check("pay2go.v1.Refunds"); code != codes.NotFound {
+		t.Errorf("Check(unknown) code = %s, want NOT_FOUND", code)
+	}
+
+	// Reflection answers each request on the stream, with the descriptors
+	// the code was generated from
+	reflection, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo() error: %v", err)
+	}
+	ask := func(request *reflectionpb.ServerReflectionRequest) *reflectionpb.ServerReflectionResponse {
+		t.Helper()
+		if err := reflection.Send(request); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+		response, err := reflection.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error: %v", err)
+		}
+		return response
+	}
+
+	listed := ask(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{ListServices: "*"}})
+	var services []string
+	for _, service := range listed.GetListServicesResponse().GetService() {
+		services = append(services, service.Name)
+	}
+	sort.Strings(services)
+	want := []string{grpcapi.HealthServiceName, "grpc.reflection.v1.ServerReflection", "grpc.reflection.v1alpha.ServerReflection", grpcapi.ServiceName}
+	if strings.Join(services, ",") != strings.Join(want, ",") {
+		t.Errorf("services = %v, want %v", services, want)
+	}
+	if header, _ := reflection.Header(); len(header.Get("x-request-id")) == 0 {
+		t.Error("call has no x-request-id")
+	}
+
+	described := ask(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "pay2go.v1.Payments.GetTransaction"}})
+	var files []string
+	for _, data := range described.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		var file descriptorpb.FileDescriptorProto
+		if err := proto.Unmarshal(data, &file); err != nil {
+			t.Fatalf("Unmarshal(descriptor) error: %v", err)
+		}
+		files = append(files, file.GetName())
+	}
+	if strings.Join(files, ",") != "pay2go/v1/payments.proto,google/protobuf/timestamp.proto" {
+		t.Errorf("descriptors = %v, want payments.proto and its import", files)
+	}
+
+	missing := ask(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "pay2go.v1.Charge"}})
+	if missing.GetErrorResponse().GetErrorCode() != int32(codes.NotFound) {
+		t.Errorf("unknown symbol answered %v, want NOT_FOUND", missing)
+	}
+	_ = reflection.CloseSend()
+	if _, err := reflection.Recv(); err != io.EOF {
+		t.Errorf("reflection ended with %v, want OK", err)
+	}
+
+	// A watch sends the status, and NOT_SERVING when the server stops
+	watch, err := health.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch() error: %v", err)
+	}
+	if watched, err := watch.Recv(); err != nil || watched.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("first watched status = %v, %v; want SERVING", watched, err)
+	}
+	api.Close()
+	if watched, err := watch.Recv(); err != nil || watched.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("last watched status = %v, %v; want NOT_SERVING", watched, err)
+	}
+	if _, err := watch.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("watch ended with %v, want UNAVAILABLE", err)
+	}
+	if got, _ := check(""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Check() after Close = %s, want NOT_SERVING", got)
+	}
+
+	// Metrics label calls by method and code, and unknown methods alike
+	_ = conn.Invoke(ctx, "/pay2go.v1.Charges/Charge", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	wantCalls := []string{
+		"/grpc.health.v1.Health/Check OK",
+		"/grpc.health.v1.Health/Check OK",
+		"/grpc.health.v1.Health/Check NOT_FOUND",
+		"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo OK",
+		"/grpc.health.v1.Health/Watch UNAVAILABLE",
+		"/grpc.health.v1.Health/Check OK",
+		"unknown UNIMPLEMENTED",
+	}
+	if strings.Join(observer.calls, "\n") != strings.Join(wantCalls, "\n") {
+		t.Errorf("observed calls = %v, want %v", observer.calls, wantCalls)
+	}
+}
@@ -0,0 +1,119 @@
+package http_test
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	grpcapi "Pay2Go/internal/adapters/grpc"
+	pay2gov1 "Pay2Go/internal/gen/pay2go/v1"
+	"Pay2Go/internal/usecases/health"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// TestGRPCWire_Golden decodes messages as clients built before the code was
+// generated encoded them, and encodes them back to the same bytes, so the
+// field numbers of payments.proto stay as deployed clients know them
+func TestGRPCWire_Golden(t *testing.T) {
+	at := timestamppb.New(time.Date(2024, 1, 15, 10, 30, 0, 123000000, time.UTC))
+	later := timestamppb.New(at.AsTime().Add(time.Second))
+	const txnID = "3f1c9a52-8a1e-4c1b-9d2e-5b7f0e6a1c33"
+
+	tests := []struct {
+		name    string
+		message proto.Message
+		golden  string
+	}{
+		{"CreateTransactionRequest", &pay2gov1.CreateTransactionRequest{
+			IdempotencyKey: "order-1001", Amount: "12.50", Currency: "USD", PaymentMethod: "card", Provider: "stripe",
+			CustomerEmail: "jane@example.com", CustomerName: "Jane Doe", CustomerPhone: "+15550100", BillingCountry: "US",
+			Description: "Order 1001", Metadata: map[string]string{"order": "1001"},
+		}, "0a0a6f726465722d31303031120531322e35301a035553442204636172642a0673747269706532106a616e65406578616d706c652e636f6d3a084a616e6520446f6542092b31353535303130304a025553520a4f7264657220313030315a0d0a056f72646572120431303031"},
+		{"CreateTransactionResponse", &pay2gov1.CreateTransactionResponse{
+			TransactionId: txnID, Status: "pending", Livemode: true, CreatedAt: at,
+		}, "0a2433663163396135322d386131652d346331622d396432652d356237663065366131633333120770656e64696e671801220b08a89294ad0610c0a9d33a"},
+		{"ProcessPaymentRequest", &pay2gov1.ProcessPaymentRequest{TransactionId: txnID},
+			"0a2433663163396135322d386131652d346331622d396432652d356237663065366131633333"},
+		{"ProcessPaymentResponse", &pay2gov1.ProcessPaymentResponse{Queued: true, JobId: "7d0e4b1a-2c3d-4e5f-8a9b-0c1d2e3f4a5b"},
+			"0801122437643065346231612d326333642d346535662d386139622d306331643265336634613562"},
+		{"RefundRequest", &pay2gov1.RefundRequest{
+			TransactionId: txnID, Amount: "5.00", Currency: "USD", Reason: "requested_by_customer", ReasonNote: "Changed mind",
+		}, "0a2433663163396135322d386131652d346331622d396432652d3562376630653661316333331204352e30301a0355534422157265717565737465645f62795f637573746f6d65722a0c4368616e676564206d696e64"},
+		{"Refund", &pay2gov1.Refund{
+			Id: "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d", TransactionId: txnID, Amount: "5.00", Currency: "USD", Status: "completed",
+			Reason: "requested_by_customer", ReasonNote: "Changed mind", CreatedAt: at,
+		}, "0a2439613862376336642d356534662d346133622d386332642d316530663961386237633664122433663163396135322d386131652d346331622d396432652d3562376630653661316333331a04352e303022035553442a09636f6d706c6574656432157265717565737465645f62795f637573746f6d65723a0c4368616e676564206d696e64420b08a89294ad0610c0a9d33a"},
+		{"GetTransactionRequest", &pay2gov1.GetTransactionRequest{TransactionId: txnID},
+			"0a2433663163396135322d386131652d346331622d396432652d356237663065366131633333"},
+		{"Transaction", &pay2gov1.Transaction{
+			Id: txnID, IdempotencyKey: "order-1001", Amount: "12.50", Currency: "USD", PaymentMethod: "card", Provider: "stripe",
+			ProviderTransactionId: "ch_123", Status: "failed", Livemode: true, CustomerEmail: "jane@example.com", CustomerName: "Jane Doe",
+			Description: "Order 1001", ErrorCode: "card_declined", ErrorMessage: "Your card was declined",
+			CreatedAt: at, UpdatedAt: later, ProcessedAt: later,
+		}, "0a2433663163396135322d386131652d346331622d396432652d356237663065366131633333120a6f726465722d313030311a0531322e353022035553442a046361726432067374726970653a0663685f31323342066661696c6564480152106a616e65406578616d706c652e636f6d5a084a616e6520446f65620a4f7264657220313030316a0d636172645f6465636c696e65647216596f7572206361726420776173206465636c696e65647a0b08a89294ad0610c0a9d33a82010b08a99294ad0610c0a9d33a8a010b08a99294ad0610c0a9d33a"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			golden, _ := hex.DecodeString(tc.golden)
+
+			decoded := tc.message.ProtoReflect().New().Interface()
+			if err := proto.Unmarshal(golden, decoded); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if !proto.Equal(decoded, tc.message) {
+				t.Errorf("decoded %v, want %v", decoded, tc.message)
+			}
+
+			encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(tc.message)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			if got := hex.EncodeToString(encoded); got != tc.golden {
+				t.Errorf("encoded %s, want %s", got, tc.golden)
+			}
+		})
+	}
+}
+
+// databaseCheck is a readiness check of a database that is up or down
+type databaseCheck struct {
+	down bool
+}
+
+func (c databaseCheck) CheckHealth(context.Context) ports.ComponentHealth {
+	if c.down {
+		return ports.ComponentHealth{Status: ports.HealthDown, Error: "connection refused"}
+	}
+	return ports.ComponentHealth{Status: ports.HealthUp}
+}
+
+func TestGRPCServer_HealthFollowsReadiness(t *testing.T) {
+	tests := map[string]struct {
+		check databaseCheck
+		want  healthpb.HealthCheckResponse_ServingStatus
+	}{
+		"database up":   {databaseCheck{}, healthpb.HealthCheckResponse_SERVING},
+		"database down": {databaseCheck{down: true}, healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			readiness := health.NewCheckReadinessUseCase(map[string]ports.HealthChecker{"database": tc.check}, time.Second)
+			conn := serveGRPC(t, grpcapi.NewServer(nil, nil, nil, nil, nil, nil, nil, nil, readiness))
+			client := healthpb.NewHealthClient(conn)
+
+			for _, service := range []string{"", grpcapi.ServiceName} {
+				response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+				if status.Code(err) != codes.OK || response.GetStatus() != tc.want {
+					t.Errorf("Check(%q) = %v, %v; want %s", service, response, err, tc.want)
+				}
+			}
+		})
+	}
+}
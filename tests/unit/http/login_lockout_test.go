@@ -0,0 +1,109 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/ratelimit"
+	"Pay2Go/internal/usecases/admin"
+	"Pay2Go/internal/usecases/apikey"
+	"Pay2Go/internal/usecases/user"
+)
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	userSessions := memory.NewUserSessionRepository(store)
+	admins := memory.NewAdminUserRepository(store)
+	adminSessions := memory.NewAdminSessionRepository(store)
+
+	acme, _ := entities.NewPartner("Acme", "acme@example.com")
+	for _, email := range []string{"jane@acme.example", "john@acme.example"} {
+		member, err := entities.NewUser(acme.ID, email, "Member", valueobjects.RoleOwner, "correct horse battery")
+		if err != nil {
+			t.Fatalf("NewUser() error: %v", err)
+		}
+		if err := users.Create(ctx, member); err != nil {
+			t.Fatalf("Create(user) error: %v", err)
+		}
+	}
+	ops, err := entities.NewAdminUser("ops@pay2go.example", "Ops", "correct horse battery")
+	if err != nil {
+		t.Fatalf("NewAdminUser() error: %v", err)
+	}
+	if err := admins.Create(ctx, ops); err != nil {
+		t.Fatalf("Create(admin) error: %v", err)
+	}
+
+	// Three failures per account and IP, five per IP
+	guard := apikey.NewFailedAuthGuard(ratelimit.NewMemoryStore(), nil, apikey.LockoutPolicy{
+		MaxFailuresPerKey: 3,
+		MaxFailuresPerIP:  5,
+		Window:            time.Minute,
+	})
+	loginGuard := middleware.NewLoginGuard(guard)
+	authHandler := handlers.NewAuthHandler(user.NewLoginUseCase(users, userSessions, nil, time.Hour), user.NewLogoutUseCase(userSessions))
+	adminAuthHandler := handlers.NewAdminAuthHandler(admin.NewLoginUseCase(admins, adminSessions, nil, time.Hour), admin.NewLogoutUseCase(adminSessions))
+
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Post("/auth/login", loginGuard.Handle("user"), authHandler.Login)
+	app.Post("/admin/auth/login", loginGuard.Handle("admin"), adminAuthHandler.Login)
+
+	login := func(path, ip, email, password string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"email":"`+email+`","password":"`+password+`","code":"000000"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("POST %s error: %v", path, err)
+		}
+		return resp
+	}
+
+	// Wrong passwords are refused until the account is locked out from
+	// this IP, and then even the right one is
+	for i := 0; i < 3; i++ {
+		if got := login("/auth/login", "203.0.113.7", "jane@acme.example", "wrong password").StatusCode; got != fiber.StatusUnauthorized {
+			t.Fatalf("failure %d = %d, want 401", i+1, got)
+		}
+	}
+	locked := login("/auth/login", "203.0.113.7", "Jane@Acme.example", "correct horse battery")
+	if locked.StatusCode != fiber.StatusTooManyRequests || locked.Header.Get(fiber.HeaderRetryAfter) == "" {
+		t.Fatalf("login after 3 failures = %d with Retry-After %q, want 429 with Retry-After", locked.StatusCode, locked.Header.Get(fiber.HeaderRetryAfter))
+	}
+
+	// The account stays open from elsewhere, and other accounts from here
+	if got := login("/auth/login", "198.51.100.1", "jane@acme.example", "correct horse battery").StatusCode; got != fiber.StatusCreated {
+		t.Errorf("login from another IP = %d, want 201", got)
+	}
+	if got := login("/auth/login", "203.0.113.7", "john@acme.example", "correct horse battery").StatusCode; got != fiber.StatusCreated {
+		t.Errorf("login to another account = %d, want 201", got)
+	}
+
+	// Back-office sign-in counts towards the same IP: two more failures use
+	// up its five, locking out every account from it
+	for i := 0; i < 2; i++ {
+		if got := login("/admin/auth/login", "203.0.113.7", "ops@pay2go.example", "wrong password").StatusCode; got != fiber.StatusUnauthorized {
+			t.Fatalf("admin failure %d = %d, want 401", i+1, got)
+		}
+	}
+	if got := login("/auth/login", "203.0.113.7", "john@acme.example", "correct horse battery").StatusCode; got != fiber.StatusTooManyRequests {
+		t.Errorf("login after 5 failures from the IP = %d, want 429", got)
+	}
+	if got := login("/admin/auth/login", "203.0.113.7", "ops@pay2go.example", "correct horse battery").StatusCode; got != fiber.StatusTooManyRequests {
+		t.Errorf("admin login after 5 failures from the IP = %d, want 429", got)
+	}
+}
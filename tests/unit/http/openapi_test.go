@@ -0,0 +1,111 @@
+package http_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/openapi"
+	"Pay2Go/internal/adapters/http/routes"
+)
+
+func TestOpenAPI_Document(t *testing.T) {
+	doc := routes.OpenAPI()
+
+	for path, method := range map[string]string{
+		"/api/v1/transactions":             "post",
+		"/api/v1/transactions/{id}":        "get",
+		"/api/v1/transactions/{id}/refund": "post",
+		"/api/v1/refunds/bulk":             "post",
+		"/api/v1/auth/login":               "post",
+	} {
+		if doc.Paths[path][method] == nil {
+			t.Errorf("%s %s is missing", method, path)
+		}
+	}
+	for path := range doc.Paths {
+		if strings.Contains(path, "/admin") {
+			t.Errorf("back-office path %s is documented", path)
+		}
+	}
+
+	// Path parameters are declared with the query, and public calls need
+	// no credentials
+	get := doc.Paths["/api/v1/transactions/{id}"]["get"]
+	if len(get.Parameters) != 2 || get.Parameters[0].Name != "id" || get.Parameters[0].In != "path" || get.Parameters[1].Name != "expand" {
+		t.Errorf("getTransaction parameters = %+v, want the id path parameter and expand", get.Parameters)
+	}
+	if get.Security != nil {
+		t.Errorf("getTransaction security = %v, want the document's", *get.Security)
+	}
+	if login := doc.Paths["/api/v1/auth/login"]["post"]; login.Security == nil || len(*login.Security) != 0 {
+		t.Errorf("login security = %v, want none", login.Security)
+	}
+
+	// Validation rules become constraints
+	request := doc.Components.Schemas["CreateTransactionRequest"]
+	if request == nil {
+		t.Fatal("CreateTransactionRequest schema is missing")
+	}
+	if email := request.Properties["customer_email"]; email.Format != "email" {
+		t.Errorf("customer_email format = %q, want email", email.Format)
+	}
+	if provider := request.Properties["provider"]; len(provider.Enum) == 0 {
+		t.Error("provider has no enum")
+	}
+	required := strings.Join(request.Required, ",")
+	if !strings.Contains(required, "idempotency_key") || strings.Contains(required, "description") {
+		t.Errorf("required = %v", request.Required)
+	}
+
+	// Every reference resolves
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, part := range strings.Split(string(raw), `"$ref":"#/components/schemas/`)[1:] {
+		name := part[:strings.IndexByte(part, '"')]
+		if doc.Components.Schemas[name] == nil {
+			t.Errorf("reference to undefined schema %s", name)
+		}
+	}
+}
+
+func TestOpenAPIHandler_Spec(t *testing.T) {
+	openAPIHandler, err := handlers.NewOpenAPIHandler(routes.OpenAPI())
+	if err != nil {
+		t.Fatalf("NewOpenAPIHandler() error = %v", err)
+	}
+	app := fiber.New()
+	app.Get("/openapi.json", openAPIHandler.Spec)
+	app.Get("/docs", openAPIHandler.Docs)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/openapi.json", nil))
+	if err != nil {
+		t.Fatalf("GET /openapi.json error: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK || !strings.HasPrefix(resp.Header.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		t.Fatalf("status = %d, content type = %q", resp.StatusCode, resp.Header.Get(fiber.HeaderContentType))
+	}
+	var served openapi.Document
+	if err := json.NewDecoder(resp.Body).Decode(&served); err != nil {
+		t.Fatalf("spec is not JSON: %v", err)
+	}
+	if served.OpenAPI != openapi.Version || served.Paths["/api/v1/transactions"]["post"] == nil {
+		t.Errorf("served spec = %s with %d paths", served.OpenAPI, len(served.Paths))
+	}
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/docs", nil))
+	if err != nil {
+		t.Fatalf("GET /docs error: %v", err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(page), `url: "/api/v1/openapi.json"`) {
+		t.Errorf("docs page does not load the spec: %s", page)
+	}
+}
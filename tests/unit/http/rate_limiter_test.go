@@ -0,0 +1,132 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/infrastructure/ratelimit"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// downRateLimitStore is a rate limit store that cannot be reached
+type downRateLimitStore struct{}
+
+func (downRateLimitStore) Allow(context.Context, string, int, time.Duration) (ports.RateLimitResult, error) {
+	return ports.RateLimitResult{}, stderrors.New("connection refused")
+}
+
+func (downRateLimitStore) Peek(context.Context, string, int, time.Duration) (ports.RateLimitResult, error) {
+	return ports.RateLimitResult{}, stderrors.New("connection refused")
+}
+
+// newRateLimitedApp serves /ping behind limiter, as partner when it is set
+func newRateLimitedApp(limiter *middleware.RateLimiter, partner *entities.Partner) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if partner != nil {
+			c.Locals("partner", partner)
+		}
+		return c.Next()
+	})
+	app.Use(limiter.Handle)
+	app.Get("/ping", func(c *fiber.Ctx) error {
+		return c.SendString("pong")
+	})
+	return app
+}
+
+func TestRateLimiter_RefusesOverLimit(t *testing.T) {
+	partner, err := entities.NewPartner("Acme", "acme@example.com")
+	if err != nil {
+		t.Fatalf("NewPartner() error: %v", err)
+	}
+	partner.RateLimitPerMinute = 2
+	app := newRateLimitedApp(middleware.NewRateLimiter(ratelimit.NewMemoryStore(), 100), partner)
+
+	start := time.Now()
+	for i, remaining := range []string{"1", "0"} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ping", nil))
+		if err != nil || resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("request %d = %v, %v; want 200", i+1, resp, err)
+		}
+		if got := resp.Header.Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d X-RateLimit-Limit = %q, want the partner's 2", i+1, got)
+		}
+		if got := resp.Header.Get("X-RateLimit-Remaining"); got != remaining {
+			t.Errorf("request %d X-RateLimit-Remaining = %q, want %s", i+1, got, remaining)
+		}
+		if resp.Header.Get(fiber.HeaderRetryAfter) != "" {
+			t.Errorf("request %d has Retry-After, want it only when refused", i+1)
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ping", nil))
+	if err != nil || resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("third request = %v, %v; want 429", resp, err)
+	}
+	var body dto.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Code != "rate_limit_exceeded" || body.Type != middleware.ErrorTypeRateLimit {
+		t.Errorf("body = %+v, %v; want a rate_limit_exceeded error", body, err)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if wantReset := start.Add(time.Minute).Unix(); err != nil || reset < wantReset-1 || reset > wantReset+1 {
+		t.Errorf("X-RateLimit-Reset = %q, want about %d, when the first request leaves the window", resp.Header.Get("X-RateLimit-Reset"), wantReset)
+	}
+	retryAfter, err := strconv.Atoi(resp.Header.Get(fiber.HeaderRetryAfter))
+	if err != nil || retryAfter < 59 || retryAfter > 60 {
+		t.Errorf("Retry-After = %q, want the seconds left in the window", resp.Header.Get(fiber.HeaderRetryAfter))
+	}
+}
+
+func TestRateLimiter_Limits(t *testing.T) {
+	unlimited, err := entities.NewPartner("Acme", "acme@example.com")
+	if err != nil {
+		t.Fatalf("NewPartner() error: %v", err)
+	}
+	unlimited.RateLimitPerMinute = 0
+
+	tests := map[string]struct {
+		partner *entities.Partner
+		want    string
+	}{
+		"anonymous by IP":             {nil, "60"},
+		"partner without a limit set": {unlimited, "100"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := newRateLimitedApp(middleware.NewRateLimiter(ratelimit.NewMemoryStore(), 100), tt.partner)
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ping", nil))
+			if err != nil || resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("GET /ping = %v, %v; want 200", resp, err)
+			}
+			if got := resp.Header.Get("X-RateLimit-Limit"); got != tt.want {
+				t.Errorf("X-RateLimit-Limit = %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_FailsOpen(t *testing.T) {
+	app := newRateLimitedApp(middleware.NewRateLimiter(downRateLimitStore{}, 100), nil)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ping", nil))
+	if err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("GET /ping with the store down = %v, %v; want 200", resp, err)
+	}
+	if got := resp.Header.Get("X-RateLimit-Limit"); got != "" {
+		t.Errorf("X-RateLimit-Limit = %q, want no limit headers without a store", got)
+	}
+}
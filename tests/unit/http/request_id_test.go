@@ -0,0 +1,57 @@
+package http_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestID)
+	var seen uuid.UUID
+	app.Get("/", func(c *fiber.Ctx) error {
+		// Use cases get the ID from the request context
+		seen = ports.RequestID(c.Context())
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	sent := uuid.New()
+	tests := []struct {
+		name   string
+		header string
+		want   uuid.UUID
+	}{
+		{"generated", "", uuid.Nil},
+		{"propagated", sent.String(), sent},
+		{"not a UUID", "abc-123", uuid.Nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.HeaderRequestID, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test() error: %v", err)
+			}
+
+			got, err := uuid.Parse(resp.Header.Get(middleware.HeaderRequestID))
+			if err != nil || got == uuid.Nil {
+				t.Fatalf("X-Request-ID = %q, want a UUID", resp.Header.Get(middleware.HeaderRequestID))
+			}
+			if tt.want != uuid.Nil && got != tt.want {
+				t.Errorf("X-Request-ID = %s, want %s", got, tt.want)
+			}
+			if seen != got {
+				t.Errorf("ports.RequestID() = %s, want %s", seen, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/health"
+)
+
+func TestSLOSummary(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+	outbox := memory.NewOutboxRepository(store)
+	partnerID := uuid.New()
+	now := time.Now()
+
+	// 19 fast payments an hour ago, one slow failure, and one slow payment
+	// yesterday that only the day window sees
+	pay := func(key string, createdAt time.Time, after time.Duration, succeeded bool) {
+		t.Helper()
+		money, _ := valueobjects.NewMoney(1000, "USD")
+		txn, err := entities.NewTransaction(partnerID, key, money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+		if err != nil {
+			t.Fatalf("NewTransaction() error: %v", err)
+		}
+		txn.CreatedAt = createdAt
+		finishedAt := createdAt.Add(after)
+		if succeeded {
+			txn.Status, txn.ProcessedAt = entities.StatusCompleted, &finishedAt
+		} else {
+			txn.Status, txn.FailedAt = entities.StatusFailed, &finishedAt
+		}
+		if err := transactions.Create(ctx, txn); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+	for i := 0; i < 19; i++ {
+		pay(uuid.NewString(), now.Add(-30*time.Minute), 50*time.Millisecond, true)
+	}
+	pay("slow-failure", now.Add(-30*time.Minute), 20*time.Second, false)
+	pay("yesterday", now.Add(-5*time.Hour), 2*time.Minute, true)
+
+	event, _ := entities.NewOutboxEvent(partnerID, "transaction", uuid.New(), entities.EventPaymentCompleted, map[string]string{})
+	event.Attempts = 1
+	published := now
+	event.PublishedAt = &published
+	if err := outbox.Add(ctx, event); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/slo", handlers.NewSLOHandler(health.NewGetSLOSummaryUseCase(transactions, outbox)).Summary)
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/slo", nil))
+	if err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("GET /slo = %v, %v; want 200", resp, err)
+	}
+	var body dto.SLOResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if len(body.Windows) != 2 || body.Windows[0].Window != "1h" || body.Windows[1].Window != "24h" {
+		t.Fatalf("windows = %+v, want 1h and 24h", body.Windows)
+	}
+	hour, day := body.Windows[0], body.Windows[1]
+	if hour.Payments.Succeeded != 19 || hour.Payments.Failed != 1 || *hour.Payments.SuccessRate != 0.95 {
+		t.Errorf("hour payments = %+v, want 19 of 20 succeeded", hour.Payments)
+	}
+	// The 95th percentile is the last of the 19 fast payments
+	if p95 := *hour.Payments.P95LatencyMs; p95 != 100 {
+		t.Errorf("hour P95 = %dms, want 100ms", p95)
+	}
+	if day.Payments.Succeeded != 20 || *day.Payments.P95LatencyMs <= 100 {
+		t.Errorf("day payments = %+v, want yesterday's slow payment counted", day.Payments)
+	}
+	if len(hour.Providers) != 1 || hour.Providers[0].Provider != "stripe" ||
+		len(hour.Partners) != 1 || hour.Partners[0].PartnerID != partnerID.String() ||
+		*hour.Partners[0].Webhooks.SuccessRate != 1 {
+		t.Errorf("hour = %+v, want stripe and the partner with its webhook delivered", hour)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(fiber.MethodGet, "/slo?livemode=false", nil))
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	if body.Livemode || body.Windows[0].Payments.SuccessRate != nil || body.Windows[0].Payments.P95LatencyMs != nil {
+		t.Errorf("sandbox = %+v, want no payments and null rates", body.Windows[0].Payments)
+	}
+}
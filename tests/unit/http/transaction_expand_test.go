@@ -0,0 +1,118 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestTransactionHandler_Expand(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+	refunds := memory.NewRefundRepository(store)
+
+	partnerID := uuid.New()
+	money, _ := valueobjects.NewMoney(1250, "USD")
+	txn, _ := entities.NewTransaction(partnerID, "key-1", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	txn.CustomerName = "Jane Doe"
+	_ = txn.MarkAsProcessing()
+	_ = txn.MarkAsCompleted("psp_1")
+	_ = transactions.Create(ctx, txn)
+	pending, _ := entities.NewTransaction(partnerID, "key-2", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	_ = transactions.Create(ctx, pending)
+	refundAmount, _ := valueobjects.NewMoney(500, "USD")
+	reason, _ := valueobjects.NewRefundReason("requested_by_customer", "")
+	refund, _ := entities.NewRefund(txn.ID, refundAmount, reason)
+	_ = refunds.Create(ctx, refund)
+
+	transactionHandler := handlers.NewTransactionHandler(
+		nil,
+		transaction.NewGetTransactionUseCase(transactions, nil, 0),
+		transaction.NewListTransactionsUseCase(transactions),
+		nil, nil, nil, nil, nil, nil,
+		transaction.NewListRefundsUseCase(refunds),
+	)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partnerID)
+		return c.Next()
+	})
+	app.Get("/transactions/:id", transactionHandler.GetTransaction)
+	app.Get("/transactions", transactionHandler.ListTransactions)
+
+	get := func(path string, want int, response interface{}) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("GET %s error: %v", path, err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != want {
+			t.Fatalf("GET %s status = %d, want %d: %s", path, resp.StatusCode, want, raw)
+		}
+		if response != nil {
+			if err := json.Unmarshal(raw, response); err != nil {
+				t.Fatalf("GET %s response: %v", path, err)
+			}
+		}
+	}
+
+	// Related resources are left out unless expanded
+	var plain dto.GetTransactionResponse
+	get("/transactions/"+txn.ID.String(), fiber.StatusOK, &plain)
+	if plain.Refunds != nil || plain.Customer != nil {
+		t.Errorf("unexpanded transaction embeds refunds %v, customer %v", plain.Refunds, plain.Customer)
+	}
+	self := "/api/v1/transactions/" + txn.ID.String()
+	if plain.Links["self"] != (dto.Link{Href: self, Method: "GET"}) || plain.Links["refund"] != (dto.Link{Href: self + "/refund", Method: "POST"}) {
+		t.Errorf("links = %+v", plain.Links)
+	}
+	if _, ok := plain.Links["process"]; ok {
+		t.Error("completed transaction links to process")
+	}
+
+	var expanded dto.GetTransactionResponse
+	get("/transactions/"+txn.ID.String()+"?expand=refunds,customer", fiber.StatusOK, &expanded)
+	if expanded.Refunds == nil || len(expanded.Refunds.Data) != 1 || expanded.Refunds.Data[0].RefundID != refund.ID.String() || expanded.Refunds.HasMore {
+		t.Errorf("refunds = %+v, want %s", expanded.Refunds, refund.ID)
+	}
+	if expanded.Customer == nil || *expanded.Customer != (dto.CustomerResponse{Email: "customer@example.com", Name: "Jane Doe"}) {
+		t.Errorf("customer = %+v", expanded.Customer)
+	}
+	get("/transactions/"+txn.ID.String()+"?expand=partner", fiber.StatusBadRequest, nil)
+
+	// Expansions apply to every transaction of a page, which links to the
+	// next one
+	var page dto.ListTransactionsResponse
+	get("/transactions?limit=1&offset=0&expand=refunds", fiber.StatusOK, &page)
+	if len(page.Transactions) != 1 || page.Transactions[0].Refunds == nil {
+		t.Fatalf("page = %+v, want one transaction with refunds", page)
+	}
+	if page.Links["self"].Href != "/api/v1/transactions?expand=refunds&limit=1&offset=0" ||
+		page.Links["next"].Href != "/api/v1/transactions?expand=refunds&limit=1&starting_after="+page.NextCursor {
+		t.Errorf("page links = %+v", page.Links)
+	}
+	var last dto.ListTransactionsResponse
+	get("/transactions?limit=5", fiber.StatusOK, &last)
+	if _, ok := last.Links["next"]; ok || len(last.Transactions) != 2 {
+		t.Errorf("last page = %+v, want two transactions and no next link", last)
+	}
+	for _, txn := range last.Transactions {
+		if _, ok := txn.Links["process"]; ok != (txn.ID == pending.ID.String()) {
+			t.Errorf("transaction %s links = %+v", txn.Status, txn.Links)
+		}
+	}
+}
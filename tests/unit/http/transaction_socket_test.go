@@ -0,0 +1,214 @@
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/outbox"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// socketClient is the client side of a WebSocket, enough for the tests
+type socketClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialSocket(t *testing.T, addr, path string) *socketClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+addr+path, nil)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("handshake write error = %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatalf("handshake read error = %v", err)
+	}
+	// The accept value of the sample key in RFC 6455
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake = %d, accept %q", resp.StatusCode, resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+	return &socketClient{t: t, conn: conn, reader: reader}
+}
+
+// write sends a masked frame
+func (s *socketClient) write(opcode byte, payload []byte) {
+	s.t.Helper()
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := s.conn.Write(frame); err != nil {
+		s.t.Fatalf("write error = %v", err)
+	}
+}
+
+// read returns the next frame the server sent
+func (s *socketClient) read() (byte, []byte) {
+	s.t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(s.reader, header[:]); err != nil {
+		s.t.Fatalf("read error = %v", err)
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		_, _ = io.ReadFull(s.reader, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(s.reader, payload); err != nil {
+		s.t.Fatalf("read error = %v", err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+// message returns the next text message, decoded
+func (s *socketClient) message() map[string]interface{} {
+	s.t.Helper()
+	opcode, payload := s.read()
+	if opcode != 0x1 {
+		s.t.Fatalf("opcode = %#x, want text", opcode)
+	}
+	var message map[string]interface{}
+	if err := json.Unmarshal(payload, &message); err != nil {
+		s.t.Fatalf("message %s is not JSON: %v", payload, err)
+	}
+	return message
+}
+
+func TestTransactionSocketHandler(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+	outboxRepo := memory.NewOutboxRepository(store)
+	partnerID := uuid.New()
+	money, _ := valueobjects.NewMoney(4500, "THB")
+	txn, err := entities.NewTransaction(partnerID, "qr-1", money, valueobjects.PaymentMethodEWallet, valueobjects.ProviderAdyen, "customer@example.com")
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	if err := transactions.Create(ctx, txn); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Changes reach sockets through the outbox. Events are backdated past
+	// the delay that lets their units of work commit, so they are read at
+	// once.
+	update := func(txn *entities.Transaction, eventType string) {
+		t.Helper()
+		if err := transactions.Update(ctx, txn); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		event, err := entities.NewOutboxEvent(partnerID, "transaction", txn.ID, eventType, map[string]interface{}{
+			"transaction_id": txn.ID.String(),
+			"status":         string(txn.Status),
+			"livemode":       txn.Livemode,
+		})
+		if err != nil {
+			t.Fatalf("NewOutboxEvent() error = %v", err)
+		}
+		event.CreatedAt = time.Now().Add(-2 * time.Second)
+		if err := outboxRepo.Add(ctx, event); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	transactionSocketHandler := handlers.NewTransactionSocketHandler(
+		transaction.NewGetTransactionUseCase(transactions, nil, 0),
+		outbox.NewStreamEventsUseCase(outboxRepo),
+		10*time.Millisecond,
+	)
+	defer transactionSocketHandler.Close()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partnerID)
+		return c.Next()
+	})
+	app.Get("/ws/transactions", transactionSocketHandler.Connect)
+
+	// Plain requests are told to upgrade
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ws/transactions", nil))
+	if err != nil {
+		t.Fatalf("GET error: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUpgradeRequired {
+		t.Errorf("plain GET status = %d, want 426", resp.StatusCode)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go func() { _ = app.Listener(listener) }()
+	defer app.Shutdown()
+
+	// The status is sent on subscribing, then whenever it changes
+	client := dialSocket(t, listener.Addr().String(), "/ws/transactions?transaction_id="+txn.ID.String())
+	if message := client.message(); message["type"] != "transaction.status" || message["status"] != "pending" || message["transaction_id"] != txn.ID.String() {
+		t.Fatalf("first message = %v, want the pending status", message)
+	}
+	_ = txn.MarkAsProcessing()
+	update(txn, entities.EventPaymentProcessing)
+	if message := client.message(); message["status"] != "processing" {
+		t.Fatalf("message = %v, want processing", message)
+	}
+	_ = txn.MarkAsCompleted("psp_1")
+	update(txn, entities.EventPaymentCompleted)
+	if message := client.message(); message["status"] != "completed" || message["processed_at"] == nil {
+		t.Fatalf("message = %v, want completed", message)
+	}
+
+	// Events that change nothing the client saw send nothing
+	update(txn, entities.EventPaymentCompleted)
+
+	// Other partners' transactions cannot be followed
+	client.write(0x1, []byte(`{"type":"subscribe","transaction_id":"`+uuid.NewString()+`"}`))
+	if message := client.message(); message["type"] != "error" || message["error"] != "transaction_not_found" {
+		t.Errorf("message = %v, want transaction_not_found", message)
+	}
+	client.write(0x1, []byte(`{"type":"watch"}`))
+	if message := client.message(); message["type"] != "error" {
+		t.Errorf("message = %v, want an error", message)
+	}
+
+	// Pings are answered, and a close is returned
+	client.write(0x9, []byte("hi"))
+	if opcode, payload := client.read(); opcode != 0xA || string(payload) != "hi" {
+		t.Errorf("ping answered with %#x %q, want a pong", opcode, payload)
+	}
+	client.write(0x8, []byte{0x03, 0xE8})
+	if opcode, payload := client.read(); opcode != 0x8 || binary.BigEndian.Uint16(payload) != 1000 {
+		t.Errorf("close answered with %#x %v, want close 1000", opcode, payload)
+	}
+}
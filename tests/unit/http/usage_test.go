@@ -0,0 +1,112 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/usage"
+)
+
+func TestUsageMetering(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUsageRepository(memory.NewStore())
+	meter := usage.NewMeter(repo, time.Minute, nil)
+	partnerID, otherID := uuid.New(), uuid.New()
+
+	usageHandler := handlers.NewUsageHandler(usage.NewGetUsageUseCase(repo), usage.NewGetUsageRollupUseCase(repo))
+	app := fiber.New()
+	app.Use(middleware.MeterUsage(meter))
+	authenticate := func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Get("X-Partner"))
+		if err != nil {
+			return fiber.ErrUnauthorized
+		}
+		c.Locals("partner_id", id)
+		return c.Next()
+	}
+	app.Get("/ok", authenticate, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Get("/missing", authenticate, func(c *fiber.Ctx) error { return fiber.ErrNotFound })
+	app.Get("/broken", authenticate, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusInternalServerError) })
+	app.Get("/usage", authenticate, usageHandler.GetUsage)
+	app.Get("/admin/usage", usageHandler.GetUsageRollup)
+
+	call := func(path, partner string) (int, []byte) {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set("X-Partner", partner)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("GET %s error: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, body
+	}
+	for _, path := range []string{"/ok", "/ok", "/missing", "/broken"} {
+		call(path, partnerID.String())
+	}
+	call("/ok", otherID.String())
+	// Unauthenticated calls have no partner to count against
+	call("/ok", "nobody")
+
+	// Live payments are metered per currency, sandbox ones are not
+	gateway := meter.Gateway(payment.NewMockPaymentGateway("mock"))
+	for _, livemode := range []bool{true, true, false} {
+		money, _ := valueobjects.NewMoney(1250, "USD")
+		txn, _ := entities.NewTransaction(partnerID, uuid.NewString(), money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+		txn.Livemode = livemode
+		if _, err := gateway.ProcessPayment(ctx, txn); err != nil {
+			t.Fatalf("ProcessPayment() error: %v", err)
+		}
+	}
+	meter.Flush(ctx)
+
+	status, body := call("/usage", partnerID.String())
+	if status != fiber.StatusOK {
+		t.Fatalf("GET /usage = %d %s, want 200", status, body)
+	}
+	var own dto.ListUsageResponse
+	if err := json.Unmarshal(body, &own); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	if len(own.Days) != 1 || own.Days[0].Date != today {
+		t.Fatalf("days = %+v, want today only", own.Days)
+	}
+	day := own.Days[0]
+	if day.APICalls != 4 || day.ClientErrors != 1 || day.ServerErrors != 1 || *day.ErrorRate != 0.5 {
+		t.Errorf("today = %+v, want 4 calls, half of them errors", day.UsageResponse)
+	}
+	if len(day.Payments) != 1 || day.Payments[0].Currency != "USD" || day.Payments[0].Payments != 2 || day.Payments[0].Volume != "25.00" {
+		t.Errorf("payments = %+v, want 2 live USD payments of 25.00", day.Payments)
+	}
+
+	// The partner's own call to /usage is metered for the next flush
+	meter.Flush(ctx)
+	_, body = call("/admin/usage?date_from="+today+"&date_to="+today, "")
+	var rollup dto.UsageRollupResponse
+	if err := json.Unmarshal(body, &rollup); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(rollup.Partners) != 2 || rollup.Partners[0].PartnerID != partnerID.String() ||
+		rollup.Partners[0].APICalls != 5 || rollup.Partners[1].APICalls != 1 {
+		t.Errorf("rollup = %+v, want the partner with 5 calls before the other with 1", rollup.Partners)
+	}
+
+	if status, _ := call("/admin/usage?date_from=2024-03-02&date_to=2024-03-01", ""); status != fiber.StatusBadRequest {
+		t.Errorf("GET /admin/usage with an inverted range = %d, want 400", status)
+	}
+}
@@ -0,0 +1,224 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Pay2Go/internal/adapters/http/dto"
+	"Pay2Go/internal/adapters/http/handlers"
+	"Pay2Go/internal/adapters/http/middleware"
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestVersioning_Handle(t *testing.T) {
+	since := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 17, 0, 0, 0, 0, time.UTC)
+	deprecation := middleware.Deprecation{Since: since, Sunset: sunset, Successor: "/api/v2"}
+
+	app := fiber.New()
+	app.Use(middleware.RequestID)
+	app.Use(middleware.NewVersioning(middleware.APIVersion1, middleware.APIVersion1, middleware.APIVersion2).Handle)
+	app.Get("/api/v1/transactions/:id", deprecation.Handle, func(c *fiber.Ctx) error {
+		return c.SendString("v1 " + c.Params("id"))
+	})
+	app.Get("/api/v2/transactions/:id", func(c *fiber.Ctx) error {
+		return c.SendString(middleware.GetAPIVersion(c) + " " + c.Params("id"))
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		status  int
+		body    string
+		version string
+	}{
+		{name: "versioned path", path: "/api/v2/transactions/42", status: fiber.StatusOK, body: "v2 42", version: "v2"},
+		{name: "unversioned defaults to v1", path: "/api/transactions/42", status: fiber.StatusOK, body: "v1 42", version: "v1"},
+		{name: "legacy root path", path: "/transactions/42", status: fiber.StatusOK, body: "v1 42", version: "v1"},
+		{
+			name:    "version header",
+			path:    "/api/transactions/42",
+			headers: map[string]string{middleware.HeaderAPIVersion: "2"},
+			status:  fiber.StatusOK, body: "v2 42", version: "v2",
+		},
+		{
+			name:    "accept media type",
+			path:    "/transactions/42",
+			headers: map[string]string{fiber.HeaderAccept: "text/html, application/vnd.pay2go.v2+json; q=0.9"},
+			status:  fiber.StatusOK, body: "v2 42", version: "v2",
+		},
+		{name: "unknown versioned path", path: "/api/v9/transactions/42", status: fiber.StatusBadRequest},
+		{
+			name:    "unknown version header",
+			path:    "/api/transactions/42",
+			headers: map[string]string{middleware.HeaderAPIVersion: "v9"},
+			status:  fiber.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("GET %s error: %v", tt.path, err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if tt.status != fiber.StatusOK {
+				if !strings.Contains(string(body), "unsupported_api_version") {
+					t.Errorf("body = %s, want unsupported_api_version", body)
+				}
+				return
+			}
+			if string(body) != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+			if got := resp.Header.Get(middleware.HeaderAPIVersion); got != tt.version {
+				t.Errorf("%s = %q, want %q", middleware.HeaderAPIVersion, got, tt.version)
+			}
+			if got := resp.Header.Values(middleware.HeaderRequestID); len(got) != 1 {
+				t.Errorf("%s = %v, want one ID", middleware.HeaderRequestID, got)
+			}
+
+			deprecated := resp.Header.Get("Deprecation")
+			if tt.version == middleware.APIVersion2 {
+				if deprecated != "" {
+					t.Errorf("v2 response is deprecated: %s", deprecated)
+				}
+				return
+			}
+			if deprecated != "@1792195200" {
+				t.Errorf("Deprecation = %q", deprecated)
+			}
+			if got := resp.Header.Get("Sunset"); got != "Sat, 17 Apr 2027 00:00:00 GMT" {
+				t.Errorf("Sunset = %q", got)
+			}
+			if got := resp.Header.Get(fiber.HeaderLink); got != `</api/v2/transactions/42>; rel="successor-version"` {
+				t.Errorf("Link = %q", got)
+			}
+		})
+	}
+}
+
+func TestTransactionV2Handler(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	partners := memory.NewPartnerRepository(store)
+	transactions := memory.NewTransactionRepository(store)
+	outbox := memory.NewOutboxRepository(store)
+	uow := memory.NewUnitOfWork(store)
+	gateway := payment.NewMockPaymentGateway("mock")
+
+	partner, err := entities.NewPartner("Acme", "billing@acme.test")
+	if err != nil {
+		t.Fatalf("NewPartner() error = %v", err)
+	}
+	if err := partners.Create(ctx, partner); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	transactionV2Handler := handlers.NewTransactionV2Handler(
+		transaction.NewCreateTransactionUseCase(transactions, outbox, uow, partners, nil, gateway, nil, nil, nil),
+		transaction.NewGetTransactionUseCase(transactions, nil, 0),
+		transaction.NewListTransactionsUseCase(transactions),
+		transaction.NewProcessPaymentUseCase(transactions, outbox, uow, gateway, nil, nil, time.Minute),
+		nil,
+		transaction.NewListRefundsUseCase(memory.NewRefundRepository(store)),
+	)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("partner_id", partner.ID)
+		return c.Next()
+	})
+	app.Post("/transactions", transactionV2Handler.CreateTransaction)
+	app.Get("/transactions", transactionV2Handler.ListTransactions)
+	app.Post("/transactions/:id/confirm", transactionV2Handler.ConfirmTransaction)
+
+	send := func(method, path, body string, want int) []byte {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s %s error: %v", method, path, err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s status = %d, want %d: %s", method, path, resp.StatusCode, want, raw)
+		}
+		return raw
+	}
+
+	request := `{
+		"idempotency_key": "order-1",
+		"amount": {"value": 1250, "currency": "USD"},
+		"payment_method": "card",
+		"provider": "stripe",
+		"customer_email": "jane@example.com",
+		"metadata": {"order_id": "1001"}
+	}`
+	var created dto.TransactionV2Response
+	if err := json.Unmarshal(send(fiber.MethodPost, "/transactions", request, fiber.StatusCreated), &created); err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	if created.Status != "requires_confirmation" || created.Object != "transaction" {
+		t.Errorf("created status = %q, object = %q", created.Status, created.Object)
+	}
+	if created.Amount != (dto.Money{Value: 1250, Currency: "USD"}) || created.Metadata["order_id"] != "1001" {
+		t.Errorf("created amount = %+v, metadata = %v", created.Amount, created.Metadata)
+	}
+	if created.Links["confirm"] != (dto.Link{Href: "/api/v2/transactions/" + created.ID + "/confirm", Method: "POST"}) {
+		t.Errorf("created links = %+v", created.Links)
+	}
+
+	var confirmed dto.TransactionV2Response
+	if err := json.Unmarshal(send(fiber.MethodPost, "/transactions/"+created.ID+"/confirm", "", fiber.StatusOK), &confirmed); err != nil {
+		t.Fatalf("confirm response: %v", err)
+	}
+	if confirmed.Status != "succeeded" || confirmed.ProcessedAt == nil {
+		t.Errorf("confirmed status = %q, processed_at = %v", confirmed.Status, confirmed.ProcessedAt)
+	}
+	if _, ok := confirmed.Links["refund"]; !ok || confirmed.Links["confirm"] != (dto.Link{}) {
+		t.Errorf("confirmed links = %+v", confirmed.Links)
+	}
+
+	var page dto.ListTransactionsV2Response
+	if err := json.Unmarshal(send(fiber.MethodGet, "/transactions?status=succeeded", "", fiber.StatusOK), &page); err != nil {
+		t.Fatalf("list response: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].ID != created.ID || page.HasMore {
+		t.Errorf("succeeded page = %+v", page)
+	}
+	if page.Links["self"].Href != "/api/v2/transactions?status=succeeded" || page.Data[0].Customer != nil {
+		t.Errorf("succeeded page links = %+v, customer = %+v", page.Links, page.Data[0].Customer)
+	}
+	var expanded dto.ListTransactionsV2Response
+	if err := json.Unmarshal(send(fiber.MethodGet, "/transactions?expand=customer,refunds", "", fiber.StatusOK), &expanded); err != nil {
+		t.Fatalf("list response: %v", err)
+	}
+	if len(expanded.Data) != 1 || expanded.Data[0].Customer == nil || expanded.Data[0].Customer.Email != "jane@example.com" ||
+		expanded.Data[0].Refunds == nil || len(expanded.Data[0].Refunds.Data) != 0 {
+		t.Errorf("expanded page = %+v", expanded)
+	}
+	send(fiber.MethodGet, "/transactions?status=completed", "", fiber.StatusBadRequest)
+
+	// Metadata values are strings of bounded length
+	tooLong := strings.Replace(request, `"1001"`, `"`+strings.Repeat("x", 501)+`"`, 1)
+	send(fiber.MethodPost, "/transactions", strings.Replace(tooLong, "order-1", "order-2", 1), fiber.StatusBadRequest)
+}
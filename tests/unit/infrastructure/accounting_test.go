@@ -0,0 +1,109 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/accounting"
+)
+
+// accountingFixtures are a payment, one of its refunds and a statement
+// charging fees on two tiers and a chargeback
+func accountingFixtures() (*entities.Transaction, *entities.Refund, *entities.Statement) {
+	txn := &entities.Transaction{
+		ID:           uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Amount:       valueobjects.Money{Amount: 1050, Currency: "USD"},
+		Status:       entities.StatusCompleted,
+		CustomerName: "=Ann\tLee",
+		Description:  "Annual plan",
+		CreatedAt:    time.Date(2024, 3, 13, 23, 30, 0, 0, time.UTC),
+	}
+	reason, _ := valueobjects.NewRefundReason("duplicate", "")
+	refund := &entities.Refund{
+		ID:        uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		Amount:    valueobjects.Money{Amount: 250, Currency: "USD"},
+		Status:    entities.RefundStatusCompleted,
+		Reason:    reason,
+		CreatedAt: time.Date(2024, 3, 14, 8, 0, 0, 0, time.UTC),
+	}
+	statement := &entities.Statement{
+		ID:     uuid.MustParse("33333333-3333-3333-3333-333333333333"),
+		Period: "2024-02",
+		Currencies: []entities.StatementCurrency{{
+			Currency: "USD",
+			TierFees: []entities.StatementTierFee{
+				{Tier: "first", Payments: entities.StatementTotal{Count: 1000}, Fee: 32000},
+				{Tier: "rest", Payments: entities.StatementTotal{Count: 10}, Fee: 0},
+			},
+			ChargebackFees: entities.StatementTotal{Count: 1, Amount: 1500},
+		}},
+	}
+	return txn, refund, statement
+}
+
+func TestAccountingEncoder_QuickBooksIIF(t *testing.T) {
+	txn, refund, statement := accountingFixtures()
+	mapping, _ := valueobjects.NewAccountMapping(valueobjects.AccountMapping{ClearingAccount: "Pay2Go", SalesAccount: "Income:Sales"})
+
+	var buf bytes.Buffer
+	file, err := accounting.NewEncoder().NewFile(&buf, entities.ExportFormatQuickBooksIIF, mapping, time.UTC)
+	if err != nil {
+		t.Fatalf("NewFile() error: %v", err)
+	}
+	_ = file.WriteTransaction(txn)
+	_ = file.WriteRefund(refund)
+	_ = file.WriteStatementFees(statement)
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	want := "!TRNS\tTRNSID\tTRNSTYPE\tDATE\tACCNT\tNAME\tAMOUNT\tDOCNUM\tMEMO\r\n" +
+		"!SPL\tSPLID\tTRNSTYPE\tDATE\tACCNT\tNAME\tAMOUNT\tDOCNUM\tMEMO\r\n" +
+		"!ENDTRNS\r\n" +
+		"TRNS\t\tDEPOSIT\t03/13/2024\tPay2Go\t=Ann Lee\t10.50\t\tPayment in USD: Annual plan (11111111-1111-1111-1111-111111111111)\r\n" +
+		"SPL\t\tDEPOSIT\t03/13/2024\tIncome:Sales\t\t-10.50\t\tPayment in USD: Annual plan (11111111-1111-1111-1111-111111111111)\r\n" +
+		"ENDTRNS\r\n" +
+		"TRNS\t\tCHECK\t03/14/2024\tPay2Go\t\t-2.50\t\tRefund in USD: duplicate (22222222-2222-2222-2222-222222222222)\r\n" +
+		"SPL\t\tCHECK\t03/14/2024\tRefunds\t\t2.50\t\tRefund in USD: duplicate (22222222-2222-2222-2222-222222222222)\r\n" +
+		"ENDTRNS\r\n" +
+		"TRNS\t\tCHECK\t02/29/2024\tPay2Go\tPay2Go\t-320.00\t\tFees 2024-02 in USD: first tier, 1000 payments (33333333-3333-3333-3333-333333333333)\r\n" +
+		"SPL\t\tCHECK\t02/29/2024\tPayment Processing Fees\t\t320.00\t\tFees 2024-02 in USD: first tier, 1000 payments (33333333-3333-3333-3333-333333333333)\r\n" +
+		"ENDTRNS\r\n" +
+		"TRNS\t\tCHECK\t02/29/2024\tPay2Go\tPay2Go\t-15.00\t\tFees 2024-02 in USD: 1 chargebacks (33333333-3333-3333-3333-333333333333)\r\n" +
+		"SPL\t\tCHECK\t02/29/2024\tPayment Processing Fees\t\t15.00\t\tFees 2024-02 in USD: 1 chargebacks (33333333-3333-3333-3333-333333333333)\r\n" +
+		"ENDTRNS\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("IIF file =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestAccountingEncoder_XeroCSV(t *testing.T) {
+	txn, refund, _ := accountingFixtures()
+	mapping, _ := valueobjects.NewAccountMapping(valueobjects.AccountMapping{SalesAccount: "200", RefundsAccount: "210"})
+	bangkok, _ := time.LoadLocation("Asia/Bangkok")
+
+	var buf bytes.Buffer
+	file, err := accounting.NewEncoder().NewFile(&buf, entities.ExportFormatXeroCSV, mapping, bangkok)
+	if err != nil {
+		t.Fatalf("NewFile() error: %v", err)
+	}
+	_ = file.WriteTransaction(txn)
+	_ = file.WriteRefund(refund)
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	// Dates are day first by default, in the file's zone; a payee starting
+	// with a formula sign is escaped
+	want := "Date,Amount,Payee,Description,Reference,Account Code\n" +
+		"14/03/2024,10.50,'=Ann Lee,Payment in USD: Annual plan,11111111-1111-1111-1111-111111111111,200\n" +
+		"14/03/2024,-2.50,,Refund in USD: duplicate,22222222-2222-2222-2222-222222222222,210\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Xero file =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package infrastructure_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/awsevents"
+)
+
+// fakeAWS answers the STS, SNS, SES and SQS calls of awsevents, recording
+// them. The Query API calls of the first three are forms; those of SQS are
+// JSON, whose fields are recorded like a form's with the action named in the
+// X-Amz-Target header.
+type fakeAWS struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	forms    []map[string]string
+}
+
+func (f *fakeAWS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	form := make(map[string]string)
+	if target := r.Header.Get("X-Amz-Target"); target != "" {
+		var fields map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for name, value := range fields {
+			if text, ok := value.(string); ok {
+				form[name] = text
+			}
+		}
+		form["Action"] = target[strings.LastIndex(target, ".")+1:]
+	} else {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for name := range r.PostForm {
+			form[name] = r.PostForm.Get(name)
+		}
+	}
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	f.forms = append(f.forms, form)
+	f.mu.Unlock()
+
+	switch action := form["Action"]; {
+	case action == "AssumeRole":
+		fmt.Fprintf(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIAPARTNER</AccessKeyId>
+      <SecretAccessKey>partner-secret</SecretAccessKey>
+      <SessionToken>partner-session</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	case r.Header.Get("X-Amz-Target") != "":
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		fmt.Fprint(w, `{"MessageId":"1"}`)
+	default:
+		fmt.Fprintf(w, `<%[1]sResponse><%[1]sResult><MessageId>1</MessageId></%[1]sResult></%[1]sResponse>`, action)
+	}
+}
+
+// webhookRecorder is the webhook publisher partners without a destination get
+type webhookRecorder struct {
+	events []*entities.OutboxEvent
+}
+
+func (w *webhookRecorder) Publish(_ context.Context, event *entities.OutboxEvent) error {
+	w.events = append(w.events, event)
+	return nil
+}
+
+func TestAWSPublisher_AssumesPartnerRoleOnce(t *testing.T) {
+	aws := &fakeAWS{}
+	server := httptest.NewServer(aws)
+	defer server.Close()
+
+	repo, partner := newWebhookPartner(t)
+	destination, err := valueobjects.NewEventDestination(
+		"sqs",
+		"https://sqs.eu-west-1.amazonaws.com/123456789012/pay2go-events.fifo",
+		"arn:aws:iam::123456789012:role/pay2go-events",
+	)
+	if err != nil {
+		t.Fatalf("NewEventDestination() error: %v", err)
+	}
+	partner.SetEventDestination(&destination)
+	if err := repo.Update(context.Background(), partner); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	webhooks := &webhookRecorder{}
+	publisher, err := awsevents.NewPublisher(repo, webhooks, awsevents.Config{
+		AccessKeyID:     "AKIAPAY2GO",
+		SecretAccessKey: "pay2go-secret",
+		Endpoint:        server.URL,
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("NewPublisher() error: %v", err)
+	}
+
+	first, second := newPaymentEvent(t, partner.ID), newPaymentEvent(t, partner.ID)
+	for _, event := range []*entities.OutboxEvent{first, second} {
+		if err := publisher.Publish(context.Background(), event); err != nil {
+			t.Fatalf("Publish() error: %v", err)
+		}
+	}
+
+	if len(webhooks.events) != 0 {
+		t.Errorf("webhooks delivered %d events, want none", len(webhooks.events))
+	}
+	if len(aws.forms) != 3 {
+		t.Fatalf("AWS got %d calls, want AssumeRole then two SendMessage", len(aws.forms))
+	}
+
+	assume := aws.forms[0]
+	if assume["Action"] != "AssumeRole" || assume["RoleArn"] != destination.RoleARN || assume["ExternalId"] != partner.ID.String() {
+		t.Errorf("first call = %v, want AssumeRole of the partner's role with its ID as external ID", assume)
+	}
+	if auth := aws.requests[0].Header.Get("Authorization"); !strings.Contains(auth, "Credential=AKIAPAY2GO/") || !strings.Contains(auth, "/eu-west-1/sts/aws4_request") {
+		t.Errorf("AssumeRole Authorization = %q, want Pay2Go's key scoped to STS in eu-west-1", auth)
+	}
+
+	send := aws.forms[1]
+	if send["Action"] != "SendMessage" || send["QueueUrl"] != destination.Target || !strings.Contains(send["MessageBody"], first.ID.String()) {
+		t.Errorf("second call = %v, want SendMessage of the first event to the queue", send)
+	}
+	if send["MessageGroupId"] != first.AggregateID.String() || send["MessageDeduplicationId"] != first.ID.String() {
+		t.Errorf("FIFO group %q dedup %q, want the aggregate and event IDs", send["MessageGroupId"], send["MessageDeduplicationId"])
+	}
+	for _, req := range aws.requests[1:] {
+		if req.Header.Get("X-Amz-Security-Token") != "partner-session" || !strings.Contains(req.Header.Get("Authorization"), "Credential=ASIAPARTNER/") {
+			t.Errorf("SendMessage signed with %q, want the assumed role's credentials", req.Header.Get("Authorization"))
+		}
+	}
+
+	// Removing the destination goes back to webhooks
+	partner.SetEventDestination(nil)
+	if err := repo.Update(context.Background(), partner); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if err := publisher.Publish(context.Background(), first); err != nil {
+		t.Fatalf("Publish() error: %v", err)
+	}
+	if len(webhooks.events) != 1 || len(aws.forms) != 3 {
+		t.Errorf("after removing the destination: %d webhooks, %d AWS calls; want 1 and 3", len(webhooks.events), len(aws.forms))
+	}
+}
@@ -0,0 +1,55 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/infrastructure/diagnostics"
+	"Pay2Go/internal/infrastructure/logger"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestRuntimeTuner(t *testing.T) {
+	appLogger := logger.New()
+	tuner := diagnostics.NewRuntimeTuner(appLogger)
+	before := tuner.Settings()
+	t.Cleanup(func() {
+		_, _ = tuner.Update(ports.RuntimeSettingsUpdate{
+			GCPercent:            &before.GCPercent,
+			BlockProfileRate:     &before.BlockProfileRate,
+			MutexProfileFraction: &before.MutexProfileFraction,
+		}, "test")
+	})
+	if before.LogLevel != "info" {
+		t.Errorf("Settings().LogLevel = %q, want info", before.LogLevel)
+	}
+
+	level, gcPercent, rate := "WARN", 50, 5
+	settings, err := tuner.Update(ports.RuntimeSettingsUpdate{
+		LogLevel:             &level,
+		GCPercent:            &gcPercent,
+		MutexProfileFraction: &rate,
+	}, "admin@example.com")
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	want := ports.RuntimeSettings{LogLevel: "warn", GCPercent: 50, BlockProfileRate: before.BlockProfileRate, MutexProfileFraction: 5}
+	if settings != want || tuner.Settings() != want {
+		t.Errorf("Update() = %+v, want %+v", settings, want)
+	}
+	if appLogger.Level() != logger.LevelWarn {
+		t.Errorf("logger level = %s, want warn", appLogger.Level())
+	}
+
+	// An invalid change leaves every setting alone
+	level, gcPercent = "debug", -2
+	if _, err := tuner.Update(ports.RuntimeSettingsUpdate{LogLevel: &level, GCPercent: &gcPercent}, "admin@example.com"); err == nil {
+		t.Fatal("Update(GC percent -2) succeeded")
+	}
+	level = "verbose"
+	if _, err := tuner.Update(ports.RuntimeSettingsUpdate{LogLevel: &level}, "admin@example.com"); err == nil {
+		t.Fatal("Update(log level verbose) succeeded")
+	}
+	if got := tuner.Settings(); got != want {
+		t.Errorf("Settings() after invalid updates = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package infrastructure_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/infrastructure/awsevents"
+	"Pay2Go/internal/infrastructure/email"
+	"Pay2Go/internal/usecases/notification"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// emailRecorder is an email sender keeping what it was asked to send
+type emailRecorder struct {
+	emails []ports.Email
+}
+
+func (r *emailRecorder) SendEmail(_ context.Context, email ports.Email) error {
+	r.emails = append(r.emails, email)
+	return nil
+}
+
+func TestMessage_IsMultipartAlternative(t *testing.T) {
+	from := &mail.Address{Name: "Pay2Go", Address: "no-reply@pay2go.example"}
+	to := &mail.Address{Address: "jane@example.com"}
+	raw, err := email.Message(from, to, ports.Email{
+		To:      to.Address,
+		Subject: "Votre reçu",
+		Text:    "Montant : 1 234,50 €",
+		HTML:    "<p>Montant : 1 234,50 €</p>",
+	}, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Message() error: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage() error: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Votre reçu" {
+		t.Errorf("Subject = %q (%v), want the encoded subject", subject, err)
+	}
+	if got := msg.Header.Get("Message-Id"); !strings.HasSuffix(got, "@pay2go.example>") {
+		t.Errorf("Message-ID = %q, want one at the sender's domain", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var types, bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart() error: %v", err)
+		}
+		body, _ := io.ReadAll(part) // quoted-printable is decoded by the reader
+		types = append(types, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+	if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Fatalf("parts = %v, want text then HTML", types)
+	}
+	if bodies[0] != "Montant : 1 234,50 €" {
+		t.Errorf("text part = %q", bodies[0])
+	}
+}
+
+func TestSESSender_SendsWithOwnCredentials(t *testing.T) {
+	aws := &fakeAWS{}
+	server := httptest.NewServer(aws)
+	defer server.Close()
+
+	sender, err := awsevents.NewSESSender(awsevents.Config{
+		AccessKeyID:     "AKIAPAY2GO",
+		SecretAccessKey: "pay2go-secret",
+		Endpoint:        server.URL,
+	}, "eu-west-1", "no-reply@pay2go.example", server.Client())
+	if err != nil {
+		t.Fatalf("NewSESSender() error: %v", err)
+	}
+
+	if err := sender.SendEmail(context.Background(), ports.Email{
+		To:      "jane@example.com",
+		Subject: "Your receipt",
+		Text:    "Thank you",
+	}); err != nil {
+		t.Fatalf("SendEmail() error: %v", err)
+	}
+
+	if len(aws.forms) != 1 {
+		t.Fatalf("AWS got %d calls, want one SendEmail", len(aws.forms))
+	}
+	form := aws.forms[0]
+	if form["Action"] != "SendEmail" || form["Source"] != "no-reply@pay2go.example" || form["Destination.ToAddresses.member.1"] != "jane@example.com" {
+		t.Errorf("call = %v, want SendEmail from the sender to the customer", form)
+	}
+	if _, ok := form["Message.Body.Html.Data"]; ok {
+		t.Errorf("call has an HTML body, want text only")
+	}
+	if auth := aws.requests[0].Header.Get("Authorization"); !strings.Contains(auth, "Credential=AKIAPAY2GO/") || !strings.Contains(auth, "/eu-west-1/ses/aws4_request") {
+		t.Errorf("Authorization = %q, want Pay2Go's key scoped to SES in eu-west-1", auth)
+	}
+}
+
+func TestNotifier_ThrottlesWebhookAlerts(t *testing.T) {
+	repo, partner := newWebhookPartner(t)
+	sender := &emailRecorder{}
+	var failures []error
+	notifier := notification.NewNotifier(
+		repo,
+		memory.NewTransactionRepository(memory.NewStore()),
+		sender,
+		nil,
+		10,
+		time.Hour,
+		func(err error) { failures = append(failures, err) },
+	)
+
+	first, second := newPaymentEvent(t, partner.ID), newPaymentEvent(t, partner.ID)
+	first.MarkFailed("connection refused")
+	notifier.Exhausted(context.Background(), first)
+	notifier.Exhausted(context.Background(), second)
+	// Receipts are off by default
+	notifier.Published(context.Background(), first)
+
+	// A canceled context sends what is queued and returns
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	notifier.Run(ctx)
+
+	if len(failures) != 0 {
+		t.Fatalf("failures = %v", failures)
+	}
+	if len(sender.emails) != 1 {
+		t.Fatalf("sent %d emails, want one alert within the interval", len(sender.emails))
+	}
+	alert := sender.emails[0]
+	if alert.To != partner.Email || !strings.Contains(alert.Text, partner.WebhookURL) || !strings.Contains(alert.Text, "connection refused") {
+		t.Errorf("alert = %+v, want the partner told which endpoint failed and why", alert)
+	}
+}
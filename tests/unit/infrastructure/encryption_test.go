@@ -0,0 +1,253 @@
+package infrastructure_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/infrastructure/encryption"
+	"Pay2Go/internal/usecases/ports"
+)
+
+const (
+	oldMasterKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	newMasterKey = "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+)
+
+func newEnvelopeCipher(t *testing.T, keys map[string]string, primaryID string) *encryption.EnvelopeCipher {
+	t.Helper()
+	c, err := encryption.NewEnvelopeCipher(keys, primaryID)
+	if err != nil {
+		t.Fatalf("NewEnvelopeCipher() error: %v", err)
+	}
+	return c
+}
+
+func TestEnvelopeCipher_RoundTrip(t *testing.T) {
+	c := newEnvelopeCipher(t, map[string]string{"old": oldMasterKey}, "old")
+	where := ports.SecretContext{OwnerID: uuid.New(), Column: "partners.webhook_secret"}
+
+	encrypted, err := c.Encrypt("whsec_123", where)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+	if strings.Contains(encrypted, "whsec_123") || !strings.HasPrefix(encrypted, "enc:v3:old:") {
+		t.Fatalf("Encrypt() = %q, want an enc:v3 value wrapped with old", encrypted)
+	}
+	if again, _ := c.Encrypt("whsec_123", where); again == encrypted {
+		t.Error("Encrypt() twice gave the same value, want a fresh data key and nonce each time")
+	}
+
+	decrypted, err := c.Decrypt(encrypted, where)
+	if err != nil {
+		t.Fatalf("Decrypt() error: %v", err)
+	}
+	if decrypted != "whsec_123" {
+		t.Errorf("Decrypt() = %q, want whsec_123", decrypted)
+	}
+	if c.NeedsRotation(encrypted) {
+		t.Error("NeedsRotation() = true for a value wrapped with the primary key")
+	}
+
+	if empty, err := c.Encrypt("", where); err != nil || empty != "" {
+		t.Errorf("Encrypt(\"\") = %q, %v, want empty", empty, err)
+	}
+}
+
+func TestEnvelopeCipher_KeyRotation(t *testing.T) {
+	where := ports.SecretContext{OwnerID: uuid.New(), Column: "api_keys.signing_secret"}
+	before := newEnvelopeCipher(t, map[string]string{"old": oldMasterKey}, "old")
+	encrypted, err := before.Encrypt("hmac-secret", where)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+
+	after := newEnvelopeCipher(t, map[string]string{"old": oldMasterKey, "new": newMasterKey}, "new")
+	if !after.NeedsRotation(encrypted) {
+		t.Error("NeedsRotation() = false for a value wrapped with a key that is no longer primary")
+	}
+	decrypted, err := after.Decrypt(encrypted, where)
+	if err != nil || decrypted != "hmac-secret" {
+		t.Fatalf("Decrypt() with the old key still configured = %q, %v", decrypted, err)
+	}
+	rotated, err := after.Encrypt(decrypted, where)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+	if after.NeedsRotation(rotated) {
+		t.Error("NeedsRotation() = true after re-encrypting with the primary key")
+	}
+
+	retired := newEnvelopeCipher(t, map[string]string{"new": newMasterKey}, "new")
+	if _, err := retired.Decrypt(encrypted, where); err == nil {
+		t.Error("Decrypt() with the old key removed succeeded, want an error")
+	}
+	if got, err := retired.Decrypt(rotated, where); err != nil || got != "hmac-secret" {
+		t.Errorf("Decrypt() of the rotated value = %q, %v", got, err)
+	}
+}
+
+func TestEnvelopeCipher_Tampering(t *testing.T) {
+	c := newEnvelopeCipher(t, map[string]string{"old": oldMasterKey, "new": newMasterKey}, "old")
+	where := ports.SecretContext{OwnerID: uuid.New(), Column: "provider_credentials.secret_key", Record: "stripe"}
+	encrypted, err := c.Encrypt("sk_live_123", where)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+	parts := strings.Split(strings.TrimPrefix(encrypted, "enc:v3:"), ":")
+
+	flip := func(part string) string {
+		raw, _ := base64.RawStdEncoding.DecodeString(part)
+		raw[len(raw)-1] ^= 1
+		return base64.RawStdEncoding.EncodeToString(raw)
+	}
+	tests := map[string]string{
+		"ciphertext":  "enc:v3:" + parts[0] + ":" + parts[1] + ":" + flip(parts[2]),
+		"wrapped key": "enc:v3:" + parts[0] + ":" + flip(parts[1]) + ":" + parts[2],
+		"key ID":      "enc:v3:new:" + parts[1] + ":" + parts[2],
+		"truncated":   "enc:v3:" + parts[0] + ":" + parts[1],
+		"downgraded":  "enc:v1:" + parts[0] + ":" + parts[1] + ":" + parts[2],
+		"unbound row": "enc:v2:" + parts[0] + ":" + parts[1] + ":" + parts[2],
+	}
+	for name, tampered := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got, err := c.Decrypt(tampered, where); err == nil {
+				t.Errorf("Decrypt() = %q, want an error", got)
+			}
+		})
+	}
+}
+
+func TestEnvelopeCipher_BoundToRow(t *testing.T) {
+	c := newEnvelopeCipher(t, map[string]string{"old": oldMasterKey}, "old")
+	where := ports.SecretContext{OwnerID: uuid.New(), Column: "partners.webhook_secret"}
+	encrypted, err := c.Encrypt("whsec_123", where)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+
+	keyID := uuid.NewString()
+	signing := ports.SecretContext{OwnerID: where.OwnerID, Column: "api_keys.signing_secret", Record: keyID}
+	signingSecret, err := c.Encrypt("hmac-secret", signing)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+
+	// A value copied into another partner's row, another column of the
+	// same partner, or another of the partner's API keys, does not decrypt
+	// there
+	elsewhere := map[string]struct {
+		value string
+		where ports.SecretContext
+	}{
+		"another partner": {encrypted, ports.SecretContext{OwnerID: uuid.New(), Column: where.Column}},
+		"another column":  {encrypted, ports.SecretContext{OwnerID: where.OwnerID, Column: "api_keys.signing_secret"}},
+		"another key":     {signingSecret, ports.SecretContext{OwnerID: where.OwnerID, Column: signing.Column, Record: uuid.NewString()}},
+		"no key":          {signingSecret, ports.SecretContext{OwnerID: where.OwnerID, Column: signing.Column}},
+	}
+	for name, other := range elsewhere {
+		t.Run(name, func(t *testing.T) {
+			if got, err := c.Decrypt(other.value, other.where); err == nil {
+				t.Errorf("Decrypt() = %q, want an error", got)
+			}
+		})
+	}
+}
+
+func TestEnvelopeCipher_OwnerBound(t *testing.T) {
+	c := newEnvelopeCipher(t, map[string]string{"old": oldMasterKey}, "old")
+	owner := uuid.New()
+
+	// enc:v2 values were bound to their owner and column only, as values of
+	// columns an owner has one row of still are
+	webhook := ports.SecretContext{OwnerID: owner, Column: "partners.webhook_secret"}
+	encrypted, err := c.Encrypt("whsec_123", webhook)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+	v2 := "enc:v2:" + strings.TrimPrefix(encrypted, "enc:v3:")
+	if got, err := c.Decrypt(v2, webhook); err != nil || got != "whsec_123" {
+		t.Errorf("Decrypt() of a webhook secret = %q, %v; want it read without AllowLegacy", got, err)
+	}
+	if !c.NeedsRotation(v2) {
+		t.Error("NeedsRotation() = false for an enc:v2 value, want true")
+	}
+
+	// In columns bound to the row they are legacy until rotated
+	unbound := ports.SecretContext{OwnerID: owner, Column: "api_keys.signing_secret"}
+	encrypted, err = c.Encrypt("hmac-secret", unbound)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+	v2 = "enc:v2:" + strings.TrimPrefix(encrypted, "enc:v3:")
+	signing := ports.SecretContext{OwnerID: owner, Column: unbound.Column, Record: uuid.NewString()}
+	if got, err := c.Decrypt(v2, signing); err == nil {
+		t.Errorf("Decrypt() of an unbound signing secret without AllowLegacy = %q, want an error", got)
+	}
+	c.AllowLegacy = true
+	if got, err := c.Decrypt(v2, signing); err != nil || got != "hmac-secret" {
+		t.Errorf("Decrypt() of an unbound signing secret with AllowLegacy = %q, %v", got, err)
+	}
+	if got, err := c.Decrypt(v2, ports.SecretContext{OwnerID: uuid.New(), Column: unbound.Column, Record: signing.Record}); err == nil {
+		t.Errorf("Decrypt() for another partner with AllowLegacy = %q, want an error", got)
+	}
+}
+
+func TestEnvelopeCipher_Legacy(t *testing.T) {
+	c := newEnvelopeCipher(t, map[string]string{"old": oldMasterKey}, "old")
+	where := ports.SecretContext{OwnerID: uuid.New(), Column: "admin_users.totp_secret"}
+	legacy := map[string]string{
+		"plaintext": "JBSWY3DPEHPK3PXP",
+		"enc:v1":    legacyEncrypt(t, "old", oldMasterKey, "JBSWY3DPEHPK3PXP"),
+	}
+
+	for name, value := range legacy {
+		t.Run(name, func(t *testing.T) {
+			c.AllowLegacy = false
+			if got, err := c.Decrypt(value, where); err == nil {
+				t.Errorf("Decrypt() without AllowLegacy = %q, want an error", got)
+			}
+			if !c.NeedsRotation(value) {
+				t.Error("NeedsRotation() = false, want true")
+			}
+
+			c.AllowLegacy = true
+			got, err := c.Decrypt(value, where)
+			if err != nil || got != "JBSWY3DPEHPK3PXP" {
+				t.Errorf("Decrypt() with AllowLegacy = %q, %v", got, err)
+			}
+		})
+	}
+}
+
+// legacyEncrypt encrypts plaintext as values were before they were bound
+// to their row: enc:v1, with no additional data on the ciphertext
+func legacyEncrypt(t *testing.T, keyID, masterKey, plaintext string) string {
+	t.Helper()
+	gcm := func(key []byte) cipher.AEAD {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			t.Fatalf("aes.NewCipher() error: %v", err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			t.Fatalf("cipher.NewGCM() error: %v", err)
+		}
+		return aead
+	}
+	seal := func(aead cipher.AEAD, data, additionalData []byte) string {
+		nonce := make([]byte, aead.NonceSize())
+		_, _ = rand.Read(nonce)
+		return base64.RawStdEncoding.EncodeToString(aead.Seal(nonce, nonce, data, additionalData))
+	}
+
+	master, _ := base64.StdEncoding.DecodeString(masterKey)
+	dataKey := make([]byte, 32)
+	_, _ = rand.Read(dataKey)
+	return "enc:v1:" + keyID + ":" + seal(gcm(master), dataKey, []byte(keyID)) + ":" + seal(gcm(dataKey), []byte(plaintext), nil)
+}
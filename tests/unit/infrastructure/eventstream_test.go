@@ -0,0 +1,308 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/infrastructure/eventstream"
+	"Pay2Go/internal/usecases/outbox"
+)
+
+// recordingPublisher records the events it is asked to deliver
+type recordingPublisher struct {
+	events []string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event *entities.OutboxEvent) error {
+	p.events = append(p.events, event.EventType)
+	return nil
+}
+
+func TestKafkaPublisher_FansOutWithWebhooks(t *testing.T) {
+	type produced struct {
+		topic   string
+		key     string
+		message eventstream.Message
+	}
+	var records []produced
+	failing := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+			t.Errorf("Content-Type = %s", r.Header.Get("Content-Type"))
+		}
+		if user, password, _ := r.BasicAuth(); user != "key" || password != "secret" {
+			t.Errorf("basic auth = %s:%s", user, password)
+		}
+		var body struct {
+			Records []struct {
+				Key   string              `json:"key"`
+				Value eventstream.Message `json:"value"`
+			} `json:"records"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Records) != 1 {
+			t.Errorf("decode produce request: %v, %d records", err, len(body.Records))
+			return
+		}
+		if failing {
+			w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"not enough replicas"}]}`))
+			return
+		}
+		records = append(records, produced{
+			topic:   strings.TrimPrefix(r.URL.Path, "/topics/"),
+			key:     body.Records[0].Key,
+			message: body.Records[0].Value,
+		})
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":42,"error_code":null,"error":null}]}`))
+	}))
+	defer proxy.Close()
+
+	kafka, err := eventstream.NewKafkaPublisher(eventstream.KafkaConfig{
+		RESTURL:     proxy.URL + "/",
+		TopicPrefix: "pay2go.",
+		Username:    "key",
+		Password:    "secret",
+	}, proxy.Client())
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher() error: %v", err)
+	}
+	webhooks := &recordingPublisher{}
+	publisher := outbox.NewFanoutPublisher(webhooks, kafka)
+
+	partnerID, transactionID := uuid.New(), uuid.New()
+	for _, eventType := range []string{entities.EventPaymentCreated, entities.EventPaymentCompleted} {
+		event, _ := entities.NewOutboxEvent(partnerID, "transaction", transactionID, eventType, map[string]string{"status": "completed"})
+		if err := publisher.Publish(context.Background(), event); err != nil {
+			t.Fatalf("Publish(%s) error: %v", eventType, err)
+		}
+	}
+
+	// Partners only receive the webhook events; the stream receives all
+	if len(webhooks.events) != 1 || webhooks.events[0] != entities.EventPaymentCompleted {
+		t.Errorf("webhooks = %v, want only payment.completed", webhooks.events)
+	}
+	if len(records) != 2 {
+		t.Fatalf("produced %d records, want 2", len(records))
+	}
+	first := records[0]
+	if first.topic != "pay2go.transactions" || first.key != transactionID.String() ||
+		first.message.Type != entities.EventPaymentCreated || first.message.PartnerID != partnerID.String() ||
+		string(first.message.Data) != `{"status":"completed"}` {
+		t.Errorf("record = %+v", first)
+	}
+
+	// A record the cluster rejects fails the publish, so the relay retries
+	failing = true
+	event, _ := entities.NewOutboxEvent(partnerID, "refund", uuid.New(), entities.EventRefundCompleted, nil)
+	if err := publisher.Publish(context.Background(), event); err == nil || !strings.Contains(err.Error(), "not enough replicas") {
+		t.Errorf("Publish() error = %v, want the rejection", err)
+	}
+}
+
+func TestRabbitMQPublisher_ConfirmsAndRequiresRouting(t *testing.T) {
+	// Needs a broker, such as docker run -p 5672:5672 rabbitmq
+	brokerURL := os.Getenv("PAY2GO_TEST_RABBITMQ_URL")
+	if brokerURL == "" {
+		t.Skip("PAY2GO_TEST_RABBITMQ_URL is not set")
+	}
+	conn, err := amqp.Dial(brokerURL)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer conn.Close()
+	channel, err := conn.Channel()
+	if err != nil {
+		t.Fatalf("Channel() error: %v", err)
+	}
+	exchange := "pay2go.test." + uuid.NewString()
+	if err := channel.ExchangeDeclare(exchange, "topic", false, true, false, false, nil); err != nil {
+		t.Fatalf("ExchangeDeclare() error: %v", err)
+	}
+
+	rabbit, err := eventstream.NewRabbitMQPublisher(eventstream.RabbitMQConfig{
+		URL:         brokerURL,
+		Exchange:    exchange,
+		TopicPrefix: "pay2go.",
+		Timeout:     5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewRabbitMQPublisher() error: %v", err)
+	}
+	defer rabbit.Close()
+	event, _ := entities.NewOutboxEvent(uuid.New(), "refund", uuid.New(), entities.EventRefundCompleted, nil)
+
+	// Without a queue bound the message would be lost, so it is retried
+	if err := rabbit.PublishEvent(context.Background(), event); err == nil || !strings.Contains(err.Error(), "routed no queue") {
+		t.Errorf("PublishEvent() of an unroutable message error = %v, want it returned", err)
+	}
+
+	queue, err := channel.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		t.Fatalf("QueueDeclare() error: %v", err)
+	}
+	if err := channel.QueueBind(queue.Name, "pay2go.#", exchange, false, nil); err != nil {
+		t.Fatalf("QueueBind() error: %v", err)
+	}
+	if err := rabbit.PublishEvent(context.Background(), event); err != nil {
+		t.Fatalf("PublishEvent() error: %v", err)
+	}
+	delivery, ok, err := channel.Get(queue.Name, true)
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v; want the message", ok, err)
+	}
+	if delivery.RoutingKey != "pay2go.refunds" || delivery.MessageId != event.ID.String() ||
+		delivery.DeliveryMode != amqp.Persistent || !strings.Contains(string(delivery.Body), `"type":"refund.completed"`) {
+		t.Errorf("delivery = %s %s mode %d: %s", delivery.RoutingKey, delivery.MessageId, delivery.DeliveryMode, delivery.Body)
+	}
+}
+
+// runNATS runs a NATS server with JetStream until the test ends
+func runNATS(t *testing.T, tlsConfig *tls.Config) *natsserver.Server {
+	t.Helper()
+	server, err := natsserver.NewServer(&natsserver.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+		TLSConfig: tlsConfig,
+	})
+	if err != nil {
+		t.Fatalf("NewServer() error: %v", err)
+	}
+	go server.Start()
+	if !server.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+	t.Cleanup(server.Shutdown)
+	return server
+}
+
+// addStream creates a stream capturing pay2go.> on server
+func addStream(t *testing.T, url string, options ...nats.Option) jetstream.Stream {
+	t.Helper()
+	conn, err := nats.Connect(url, options...)
+	if err != nil {
+		t.Fatalf("Connect() error: %v", err)
+	}
+	t.Cleanup(conn.Close)
+	js, _ := jetstream.New(conn)
+	stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{Name: "PAY2GO", Subjects: []string{"pay2go.>"}})
+	if err != nil {
+		t.Fatalf("CreateStream() error: %v", err)
+	}
+	return stream
+}
+
+func TestNATSPublisher_StoresOnceInJetStream(t *testing.T) {
+	server := runNATS(t, nil)
+	publisher, err := eventstream.NewNATSPublisher(eventstream.NATSConfig{
+		URL:         server.ClientURL(),
+		TopicPrefix: "pay2go.",
+		Timeout:     2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() error: %v", err)
+	}
+	defer publisher.Close()
+	event, _ := entities.NewOutboxEvent(uuid.New(), "transaction", uuid.New(), entities.EventPaymentProcessing, nil)
+
+	// Without a stream nothing stores the message, so it is retried
+	if err := publisher.PublishEvent(context.Background(), event); err == nil || !strings.Contains(err.Error(), "no JetStream stream") {
+		t.Errorf("PublishEvent() without a stream error = %v", err)
+	}
+
+	// A retried event is stored once, by its Nats-Msg-Id
+	stream := addStream(t, server.ClientURL())
+	for i := 0; i < 2; i++ {
+		if err := publisher.PublishEvent(context.Background(), event); err != nil {
+			t.Fatalf("PublishEvent() #%d error: %v", i+1, err)
+		}
+	}
+	info, err := stream.Info(context.Background())
+	if err != nil {
+		t.Fatalf("Info() error: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("stream holds %d messages, want 1", info.State.Msgs)
+	}
+	msg, err := stream.GetLastMsgForSubject(context.Background(), "pay2go.transactions")
+	if err != nil {
+		t.Fatalf("GetLastMsgForSubject() error: %v", err)
+	}
+	if msg.Header.Get(nats.MsgIdHdr) != event.ID.String() {
+		t.Errorf("Nats-Msg-Id = %q, want %s", msg.Header.Get(nats.MsgIdHdr), event.ID)
+	}
+}
+
+func TestNATSPublisher_TLS(t *testing.T) {
+	// A server requiring TLS with a certificate of a private CA
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nats.test"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error: %v", err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+	server := runNATS(t, &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	})
+	url := strings.Replace(server.ClientURL(), "nats://", "tls://", 1)
+	addStream(t, url, nats.RootCAs(caFile))
+
+	publish := func(cfg eventstream.NATSConfig) error {
+		t.Helper()
+		cfg.TopicPrefix, cfg.Timeout = "pay2go.", time.Second
+		publisher, err := eventstream.NewNATSPublisher(cfg)
+		if err != nil {
+			return err
+		}
+		defer publisher.Close()
+		event, _ := entities.NewOutboxEvent(uuid.New(), "refund", uuid.New(), entities.EventRefundCompleted, nil)
+		return publisher.PublishEvent(context.Background(), event)
+	}
+	if err := publish(eventstream.NATSConfig{URL: url, CAFile: caFile}); err != nil {
+		t.Errorf("PublishEvent() over TLS error: %v", err)
+	}
+	// The server's certificate is checked: without the CA it is not trusted
+	if err := publish(eventstream.NATSConfig{URL: url}); err == nil {
+		t.Error("PublishEvent() trusting an unknown CA succeeded")
+	}
+}
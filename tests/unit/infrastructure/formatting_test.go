@@ -0,0 +1,49 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/formatting"
+)
+
+func TestMoneyFormatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		style  formatting.Style
+		amount int64
+		cur    valueobjects.Currency
+		want   string
+	}{
+		{name: "thai baht", locale: "th-TH", amount: 123450, cur: valueobjects.THB, want: "฿1,234.50"},
+		{name: "euro indonesian", locale: "id-ID", amount: 123450, cur: valueobjects.EUR, want: "€1.234,50"},
+		{name: "euro german", locale: "de-DE", amount: 123450, cur: valueobjects.EUR, want: "1.234,50\u00a0€"},
+		{name: "refund", locale: "en-US", amount: -123450, cur: valueobjects.EUR, want: "-€1,234.50"},
+		{name: "refund symbol after", locale: "de-DE", amount: -5, cur: valueobjects.EUR, want: "-0,05\u00a0€"},
+		{name: "code", locale: "en-US", style: formatting.StyleCode, amount: 123450, cur: valueobjects.THB, want: "THB\u00a01,234.50"},
+		{name: "code symbol after", locale: "de-DE", style: formatting.StyleCode, amount: 123450, cur: valueobjects.EUR, want: "1.234,50\u00a0EUR"},
+		{name: "number", locale: "de-DE", style: formatting.StyleNumber, amount: 123450000, cur: valueobjects.EUR, want: "1.234.500,00"},
+		{name: "zero decimals", locale: "ja-JP", amount: 1234567, cur: valueobjects.JPY, want: "¥1,234,567"},
+		{name: "default locale", locale: "", amount: 99, cur: valueobjects.USD, want: "$0.99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := formatting.NewMoneyFormatter(tt.locale, tt.style)
+			if err != nil {
+				t.Fatalf("NewMoneyFormatter(%q) error: %v", tt.locale, err)
+			}
+
+			if got := formatter.MinorUnits(tt.amount, tt.cur); got != tt.want {
+				t.Errorf("MinorUnits(%d, %s) = %q, want %q", tt.amount, tt.cur, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMoneyFormatter_InvalidLocale(t *testing.T) {
+	if _, err := formatting.NewMoneyFormatter("not a locale", formatting.StyleSymbol); err == nil {
+		t.Error("Expected error for invalid locale, got nil")
+	}
+}
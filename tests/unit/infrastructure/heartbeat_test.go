@@ -0,0 +1,46 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/infrastructure/heartbeat"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestMonitor_ReportsStalledLoops(t *testing.T) {
+	monitor := heartbeat.NewMonitor(2)
+	monitor.Register("outbox_relay", 50*time.Millisecond)
+	monitor.Register("partitions", time.Hour)
+
+	if health := monitor.CheckHealth(context.Background()); health.Status != ports.HealthUp {
+		t.Fatalf("CheckHealth() = %+v before any pass was due, want up", health)
+	}
+
+	// The relay keeps beating within two of its intervals
+	for i := 0; i < 3; i++ {
+		time.Sleep(20 * time.Millisecond)
+		monitor.Beat("outbox_relay")
+	}
+	if health := monitor.CheckHealth(context.Background()); health.Status != ports.HealthUp {
+		t.Fatalf("CheckHealth() = %+v while the relay beats, want up", health)
+	}
+
+	// Then stops
+	time.Sleep(150 * time.Millisecond)
+	health := monitor.CheckHealth(context.Background())
+	if health.Status != ports.HealthDown || health.Error != "stalled background loops: [outbox_relay]" {
+		t.Errorf("CheckHealth() = %+v, want down by the relay", health)
+	}
+	loops := monitor.Loops()
+	if len(loops) != 2 || loops[0].Name != "outbox_relay" || !loops[0].Stalled || loops[0].Beats != 3 || loops[1].Stalled {
+		t.Errorf("Loops() = %+v, want the relay stalled after 3 beats and partitions not", loops)
+	}
+
+	// Beats of unknown loops are ignored
+	monitor.Beat("unknown")
+	if len(monitor.Loops()) != 2 {
+		t.Error("Beat() registered an unknown loop")
+	}
+}
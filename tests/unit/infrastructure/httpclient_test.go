@@ -0,0 +1,119 @@
+package infrastructure_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/infrastructure/httpclient"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func newTransport(t *testing.T, proxyURL string) *httpclient.Transport {
+	t.Helper()
+	transport, err := httpclient.NewTransport(httpclient.Config{
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     time.Minute,
+		DialTimeout:         time.Second,
+		TLSHandshakeTimeout: time.Second,
+		ProxyURL:            proxyURL,
+	})
+	if err != nil {
+		t.Fatalf("NewTransport() error: %v", err)
+	}
+	t.Cleanup(transport.CloseIdleConnections)
+	return transport
+}
+
+func get(t *testing.T, client *http.Client, url string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	// The connection goes back to the pool once the body is read
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func TestTransport_ReusesConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	transport := newTransport(t, "")
+	// Clients with different timeouts share the pool
+	get(t, transport.Client(time.Second), server.URL)
+	get(t, transport.Client(5*time.Second), server.URL)
+	get(t, transport.Client(time.Second), server.URL)
+
+	stats := transport.Stats()
+	if stats.Requests != 3 || stats.ConnectionsOpened != 1 || stats.ConnectionsReused != 2 || stats.InFlight != 0 {
+		t.Errorf("Stats() = %+v, want 3 requests over 1 opened connection", stats)
+	}
+
+	server.Close()
+	if _, err := transport.Client(time.Second).Get(server.URL); err == nil {
+		t.Fatal("Get(closed server) succeeded")
+	}
+	if stats := transport.Stats(); stats.Failed != 1 {
+		t.Errorf("Stats().Failed = %d, want 1", stats.Failed)
+	}
+}
+
+func TestTransport_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxy receives the absolute URL of plain HTTP requests
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	get(t, newTransport(t, proxy.URL).Client(time.Second), "http://partner.example/webhooks")
+	if proxied != "http://partner.example/webhooks" {
+		t.Errorf("proxy received %q, want the partner's URL", proxied)
+	}
+
+	if _, err := httpclient.NewTransport(httpclient.Config{ProxyURL: "not a url"}); err == nil {
+		t.Error("NewTransport(invalid proxy) succeeded")
+	}
+}
+
+func TestTransport_PropagatesRequestID(t *testing.T) {
+	received := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("X-Request-ID")
+	}))
+	defer server.Close()
+
+	client := newTransport(t, "").Client(time.Second)
+	requestID := uuid.New()
+	req, err := http.NewRequestWithContext(ports.WithRequestID(context.Background(), requestID), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	resp.Body.Close()
+	if got := <-received; got != requestID.String() {
+		t.Errorf("X-Request-ID = %q, want %s", got, requestID)
+	}
+	if req.Header.Get("X-Request-ID") != "" {
+		t.Error("RoundTrip() modified the caller's request")
+	}
+
+	// Background work has no request to refer to
+	get(t, client, server.URL)
+	if got := <-received; got != "" {
+		t.Errorf("X-Request-ID = %q outside a request, want none", got)
+	}
+}
@@ -0,0 +1,59 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/infrastructure/lock"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	ctx := context.Background()
+	locker := lock.NewMemoryLocker()
+
+	held, err := locker.TryLock(ctx, "transaction:1", time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock() error: %v", err)
+	}
+	if _, err := locker.TryLock(ctx, "transaction:1", time.Minute); err != errors.ErrResourceLocked {
+		t.Fatalf("TryLock() on a held key error = %v, want ErrResourceLocked", err)
+	}
+	if other, err := locker.TryLock(ctx, "transaction:2", time.Minute); err != nil {
+		t.Fatalf("TryLock() on another key error: %v", err)
+	} else {
+		_ = other.Unlock(ctx)
+	}
+
+	if err := held.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error: %v", err)
+	}
+	if _, err := locker.TryLock(ctx, "transaction:1", time.Minute); err != nil {
+		t.Fatalf("TryLock() after Unlock() error: %v", err)
+	}
+}
+
+func TestMemoryLocker_Expiry(t *testing.T) {
+	ctx := context.Background()
+	locker := lock.NewMemoryLocker()
+
+	expired, err := locker.TryLock(ctx, "transaction:1", time.Millisecond)
+	if err != nil {
+		t.Fatalf("TryLock() error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	current, err := locker.TryLock(ctx, "transaction:1", time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock() after expiry error: %v", err)
+	}
+	// The expired holder must not release the lock it lost
+	if err := expired.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error: %v", err)
+	}
+	if _, err := locker.TryLock(ctx, "transaction:1", time.Minute); err != errors.ErrResourceLocked {
+		t.Errorf("TryLock() after a stale Unlock() error = %v, want ErrResourceLocked", err)
+	}
+	_ = current.Unlock(ctx)
+}
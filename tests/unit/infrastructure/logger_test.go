@@ -0,0 +1,112 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/infrastructure/logger"
+)
+
+func TestLogger_WritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	appLogger := logger.NewWithSink(logger.NewWriterSink(&buf, logger.FormatJSON))
+	appLogger.Debug("not written at info")
+	appLogger.Warn("payment %s slow", "txn_1")
+
+	var entry map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("line %q is not one JSON entry: %v", buf.String(), err)
+	}
+	if entry["level"] != "warn" || entry["message"] != "payment txn_1 slow" ||
+		!strings.HasPrefix(entry["caller"], "logger_test.go:") || entry["service"] != "pay2go" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestRotatingFile_KeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pay2go.log")
+	file, err := logger.OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile() error: %v", err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+	want := map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"}
+	for name, content := range want {
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != content {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(name), got, err, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("want only 2 backups kept, stat .3: %v", err)
+	}
+}
+
+// lokiPush is the body of a push to Loki
+type lokiPush map[string][]struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func TestHTTPSink_PushesToLoki(t *testing.T) {
+	var mu sync.Mutex
+	var pushes []lokiPush
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var push lokiPush
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			t.Errorf("decode push: %v", err)
+		}
+		mu.Lock()
+		pushes = append(pushes, push)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer collector.Close()
+
+	sink, err := logger.NewHTTPSink(logger.HTTPSinkConfig{
+		URL:           collector.URL,
+		Collector:     logger.CollectorLoki,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		BufferSize:    10,
+	}, collector.Client(), func(err error) { t.Errorf("ship error: %v", err) })
+	if err != nil {
+		t.Fatalf("NewHTTPSink() error: %v", err)
+	}
+	appLogger := logger.NewWithSink(sink)
+	appLogger.Info("server starting")
+	appLogger.Error("database unreachable")
+	appLogger.Info("server started")
+
+	// Shutdown ships what is queued
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sink.Run(ctx)
+
+	if len(pushes) != 1 || len(pushes[0]["streams"]) != 2 {
+		t.Fatalf("pushes = %+v, want one push with an info and an error stream", pushes)
+	}
+	info := pushes[0]["streams"][0]
+	if info.Stream["level"] != "info" || info.Stream["service"] != "pay2go" || len(info.Values) != 2 ||
+		!strings.Contains(info.Values[1][1], `"message":"server started"`) {
+		t.Errorf("info stream = %+v", info)
+	}
+	if sent, dropped, failed := sink.Stats(); sent != 3 || dropped != 0 || failed != 0 {
+		t.Errorf("Stats() = %d, %d, %d; want 3 sent", sent, dropped, failed)
+	}
+}
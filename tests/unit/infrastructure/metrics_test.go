@@ -0,0 +1,91 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/metrics"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestRegistry_WritesTextFormat(t *testing.T) {
+	r := metrics.NewRegistry()
+	requests := r.Counter("requests_total", "Requests.\nBy path.", "path")
+	latency := r.Histogram("latency_seconds", "Latency.", []float64{0.1, 1}, "path")
+	r.GaugeFunc("queue_depth", "Queued work.", nil, func(set func(float64, ...string)) { set(3) })
+
+	requests.Inc(`/a"b`)
+	requests.Add(2, "/c")
+	latency.Observe(0.05, "/c")
+	latency.Observe(0.5, "/c")
+	latency.Observe(5, "/c")
+
+	var b strings.Builder
+	if err := r.WritePrometheus(&b); err != nil {
+		t.Fatalf("WritePrometheus() error: %v", err)
+	}
+
+	want := `# HELP requests_total Requests.\nBy path.
+# TYPE requests_total counter
+requests_total{path="/a\"b"} 1
+requests_total{path="/c"} 2
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{path="/c",le="0.1"} 1
+latency_seconds_bucket{path="/c",le="1"} 2
+latency_seconds_bucket{path="/c",le="+Inf"} 3
+latency_seconds_sum{path="/c"} 5.55
+latency_seconds_count{path="/c"} 3
+# HELP queue_depth Queued work.
+# TYPE queue_depth gauge
+queue_depth 3
+`
+	if got := b.String(); got != want {
+		t.Errorf("WritePrometheus() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// failingGateway declines payments over 100.00
+type failingGateway struct {
+	ports.PaymentGateway
+}
+
+func (failingGateway) ProcessPayment(_ context.Context, transaction *entities.Transaction) (string, error) {
+	if transaction.Amount.Amount > 10000 {
+		return "", errors.New("declined")
+	}
+	return "pi_1", nil
+}
+
+func TestMetrics_GatewayCountsPaymentsByOutcome(t *testing.T) {
+	m := metrics.New()
+	gateway := m.Gateway(failingGateway{})
+
+	for _, amount := range []int64{500, 900, 50000} {
+		money, err := valueobjects.NewMoney(amount, "USD")
+		if err != nil {
+			t.Fatalf("NewMoney() error: %v", err)
+		}
+		transaction := &entities.Transaction{Amount: money, Provider: valueobjects.ProviderStripe, Livemode: true}
+		gateway.ProcessPayment(context.Background(), transaction)
+	}
+
+	var b strings.Builder
+	if err := m.WritePrometheus(&b); err != nil {
+		t.Fatalf("WritePrometheus() error: %v", err)
+	}
+	out := b.String()
+	for _, line := range []string{
+		`pay2go_payments_total{provider="stripe",mode="live",outcome="failed"} 1`,
+		`pay2go_payments_total{provider="stripe",mode="live",outcome="succeeded"} 2`,
+		`pay2go_gateway_request_duration_seconds_count{provider="stripe",operation="process_payment"} 3`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("scrape is missing %q:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package infrastructure_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"Pay2Go/internal/infrastructure/config"
+	"Pay2Go/internal/infrastructure/migrate"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/migrations"
+)
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if len(loaded) == 0 {
+		t.Fatal("Expected embedded migrations, got none")
+	}
+
+	// Versions are sequential so a missing file shows up as a gap
+	for i, migration := range loaded {
+		if migration.Version != int64(i+1) {
+			t.Fatalf("Migration %d has version %d, want %d", i, migration.Version, i+1)
+		}
+	}
+
+	if loaded[0].Name != "init_schema" || !strings.Contains(loaded[0].Up, "CREATE TABLE partners") {
+		t.Errorf("First migration = %s, want init_schema creating partners", loaded[0].Name)
+	}
+}
+
+func TestLoad_EmbeddedMySQLMigrations(t *testing.T) {
+	loaded, err := migrate.Load(migrations.MySQLFS)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if len(loaded) == 0 || loaded[0].Name != "init_schema" {
+		t.Fatalf("Load() = %+v, want init_schema first", loaded)
+	}
+
+	// The MySQL schema must not pick up PostgreSQL-only syntax
+	for _, postgresOnly := range []string{"UUID PRIMARY KEY", "JSONB", "TIMESTAMP WITH TIME ZONE", "TEXT[]"} {
+		if strings.Contains(loaded[0].Up, postgresOnly) {
+			t.Errorf("MySQL init_schema contains %q", postgresOnly)
+		}
+	}
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	cfg := config.DatabaseConfig{Driver: config.DriverSQLite, DBName: filepath.Join(t.TempDir(), "pay2go.db")}
+	db, err := sql.Open(cfg.DriverName(), cfg.GetDSN())
+	if err != nil {
+		t.Fatalf("sql.Open() error: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrate.SQLite, migrations.SQLiteFS)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error: %v", err)
+	}
+	if version, dirty, err := migrator.Version(ctx); err != nil || dirty || version != migrator.Latest() {
+		t.Fatalf("Version() = %d, %v, %v; want %d, false, nil", version, dirty, err, migrator.Latest())
+	}
+	if health := migrator.CheckHealth(ctx); health.Status != ports.HealthUp || health.Details["pending"] != 0 {
+		t.Errorf("CheckHealth() = %+v, want up with nothing pending", health)
+	}
+
+	var bins int
+	if err := db.QueryRow("SELECT COUNT(*) FROM card_bins").Scan(&bins); err != nil || bins == 0 {
+		t.Errorf("card_bins seed count = %d, %v; want rows", bins, err)
+	}
+
+	if _, err := migrator.Down(ctx, int(migrator.Latest())); err != nil {
+		t.Fatalf("Down() error: %v", err)
+	}
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'partners'").Scan(&tables); err != nil || tables != 0 {
+		t.Errorf("partners tables after Down() = %d, %v; want 0", tables, err)
+	}
+	if health := migrator.CheckHealth(ctx); health.Status != ports.HealthDown {
+		t.Errorf("CheckHealth() after Down() = %+v, want down", health)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := func(sql string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(sql)} }
+
+	t.Run("orders by version and skips other files", func(t *testing.T) {
+		loaded, err := migrate.Load(fstest.MapFS{
+			"000010_b.up.sql":   file("UP B"),
+			"000010_b.down.sql": file("DOWN B"),
+			"000002_a.up.sql":   file("UP A"),
+			"000002_a.down.sql": file("DOWN A"),
+			"seed.sql":          file("INSERT"),
+			"embed.go":          file("package migrations"),
+		})
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+
+		if len(loaded) != 2 || loaded[0].Version != 2 || loaded[1].Version != 10 {
+			t.Fatalf("Load() = %+v, want versions 2 and 10", loaded)
+		}
+		if loaded[0].Up != "UP A" || loaded[0].Down != "DOWN A" {
+			t.Errorf("Migration 2 = %q/%q, want UP A/DOWN A", loaded[0].Up, loaded[0].Down)
+		}
+	})
+
+	t.Run("requires a down file", func(t *testing.T) {
+		_, err := migrate.Load(fstest.MapFS{"000001_a.up.sql": file("UP")})
+		if err == nil {
+			t.Error("Expected error for missing down file, got nil")
+		}
+	})
+
+	t.Run("rejects a repeated version", func(t *testing.T) {
+		_, err := migrate.Load(fstest.MapFS{
+			"000001_a.up.sql":   file("UP"),
+			"000001_a.down.sql": file("DOWN"),
+			"000001_b.up.sql":   file("UP"),
+			"000001_b.down.sql": file("DOWN"),
+		})
+		if err == nil {
+			t.Error("Expected error for repeated version, got nil")
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package infrastructure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/infrastructure/notify"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestTeamsWebhook_PostsAdaptiveCard(t *testing.T) {
+	var message struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string `json:"type"`
+					Text  string `json:"text"`
+					Facts []struct {
+						Title string `json:"title"`
+						Value string `json:"value"`
+					} `json:"facts"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	// Slack refuses, Teams still gets the alert
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer slack.Close()
+
+	sink := notify.NewSink(slack.URL, server.URL, server.Client())
+	err := sink.Send(context.Background(), ports.Alert{
+		Title:  "Payment provider stripe is degraded",
+		Text:   "12 of 20 calls to stripe failed in the last 5m0s.",
+		Fields: map[string]string{"Failures": "12", "Calls": "20"},
+	})
+	if err == nil {
+		t.Error("Send() error = nil, want Slack's refusal")
+	}
+
+	if message.Type != "message" || len(message.Attachments) != 1 {
+		t.Fatalf("message = %+v, want one attachment", message)
+	}
+	card := message.Attachments[0]
+	if card.ContentType != "application/vnd.microsoft.card.adaptive" || card.Content.Type != "AdaptiveCard" || len(card.Content.Body) != 3 {
+		t.Fatalf("attachment = %+v, want an Adaptive Card with title, text and facts", card)
+	}
+	if card.Content.Body[0].Text != "Payment provider stripe is degraded" {
+		t.Errorf("title = %q", card.Content.Body[0].Text)
+	}
+	facts := card.Content.Body[2].Facts
+	if len(facts) != 2 || facts[0].Title != "Calls" || facts[1].Value != "12" {
+		t.Errorf("facts = %+v, want the fields sorted by name", facts)
+	}
+
+	if notify.NewSink("", "", server.Client()) != nil {
+		t.Error("NewSink() without webhooks is not nil")
+	}
+}
+
+func TestWebhookFailures_AlertsOncePerPartner(t *testing.T) {
+	recorder := newSlowCallRecorder()
+	_, partner := newWebhookPartner(t)
+	failures := notify.NewWebhookFailures(recorder, time.Hour, recorder)
+
+	first, second := newPaymentEvent(t, partner.ID), newPaymentEvent(t, partner.ID)
+	first.MarkFailed("connection refused")
+	failures.Exhausted(context.Background(), first)
+	failures.Exhausted(context.Background(), second)
+
+	select {
+	case alert := <-recorder.alerts:
+		if alert.Fields["Partner"] != partner.ID.String() || alert.Fields["Last error"] != "connection refused" {
+			t.Errorf("alert = %+v, want the partner and the last error", alert)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no alert")
+	}
+	select {
+	case alert := <-recorder.alerts:
+		t.Errorf("second alert %+v within the interval", alert)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
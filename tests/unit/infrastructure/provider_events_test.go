@@ -0,0 +1,145 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/cache"
+	"Pay2Go/internal/infrastructure/lock"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/ports"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// stripeHeader returns a Stripe-Signature header signing body with secret
+func stripeHeader(secret string, body []byte) func(string) string {
+	timestamp := fmt.Sprint(time.Now().Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	signature := "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+	return func(name string) string {
+		if name == "Stripe-Signature" {
+			return signature
+		}
+		return ""
+	}
+}
+
+func TestStripeEvents_VerifiesSignature(t *testing.T) {
+	parser := payment.NewStripeEvents("whsec_stripe")
+	body := []byte(`{"id":"evt_1","type":"payment_intent.payment_failed","data":{"object":{"id":"pi_1","metadata":{"transaction_id":"not-a-uuid"},"last_payment_error":{"message":"card declined"}}}}`)
+
+	events, err := parser.Parse(body, stripeHeader("whsec_stripe", body))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Parse() = %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.ID != "evt_1" || event.Type != ports.ProviderEventPaymentFailed || event.ProviderTransactionID != "pi_1" || event.Reason != "card declined" {
+		t.Errorf("event = %+v", event)
+	}
+
+	if _, err := parser.Parse(body, stripeHeader("whsec_other", body)); !stderrors.Is(err, errors.ErrInvalidSignature) {
+		t.Errorf("Parse() with another secret error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestAdyenEvents_VerifiesEachItem(t *testing.T) {
+	key := "44782DEF547AAA06C910C43932B1EB0C71FC68D9D0C057550C48EC2ACF6BA056"
+	parser, err := payment.NewAdyenEvents(key)
+	if err != nil {
+		t.Fatalf("NewAdyenEvents() error: %v", err)
+	}
+
+	rawKey, _ := hex.DecodeString(key)
+	mac := hmac.New(sha256.New, rawKey)
+	mac.Write([]byte("psp_refund::Pay2GoECOM:5a1c7c3e-8f5d-4a34-9f0e-1f2b3c4d5e6f:1000:EUR:REFUND:true"))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	body := fmt.Sprintf(`{"live":"false","notificationItems":[{"NotificationRequestItem":{
+		"additionalData":{"hmacSignature":%q},"amount":{"currency":"EUR","value":1000},
+		"eventCode":"REFUND","merchantAccountCode":"Pay2GoECOM","merchantReference":"5a1c7c3e-8f5d-4a34-9f0e-1f2b3c4d5e6f",
+		"originalReference":"","pspReference":"psp_refund","reason":"","success":"true"}}]}`, signature)
+
+	events, err := parser.Parse([]byte(body), nil)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != ports.ProviderEventRefundSucceeded || events[0].RefundID.String() != "5a1c7c3e-8f5d-4a34-9f0e-1f2b3c4d5e6f" {
+		t.Fatalf("Parse() = %+v, want the refund", events)
+	}
+
+	tampered := []byte(fmt.Sprintf(`{"notificationItems":[{"NotificationRequestItem":{
+		"additionalData":{"hmacSignature":%q},"amount":{"currency":"EUR","value":100000},
+		"eventCode":"REFUND","merchantAccountCode":"Pay2GoECOM","merchantReference":"5a1c7c3e-8f5d-4a34-9f0e-1f2b3c4d5e6f",
+		"pspReference":"psp_refund","success":"true"}}]}`, signature))
+	if _, err := parser.Parse(tampered, nil); !stderrors.Is(err, errors.ErrInvalidSignature) {
+		t.Errorf("Parse() of a changed amount error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestHandleProviderEvents_SkipsDuplicateDeliveries(t *testing.T) {
+	store := memory.NewStore()
+	transactions := memory.NewTransactionRepository(store)
+	outbox := memory.NewOutboxRepository(store)
+	money, _ := valueobjects.NewMoney(1000, "USD")
+	txn, _ := entities.NewTransaction(uuid.New(), "key", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	_ = txn.MarkAsProcessing()
+	if err := transactions.Create(context.Background(), txn); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+
+	uc := transaction.NewHandleProviderEventsUseCase(
+		map[valueobjects.PaymentProvider]ports.ProviderEventParser{
+			valueobjects.ProviderStripe: payment.NewStripeEvents("whsec_stripe"),
+		},
+		cache.NewMemoryEventStore(100),
+		time.Hour,
+		transactions,
+		memory.NewRefundRepository(store),
+		outbox,
+		memory.NewUnitOfWork(store),
+		nil,
+		lock.NewMemoryLocker(),
+		time.Minute,
+	)
+
+	body := []byte(fmt.Sprintf(`{"id":"evt_1","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","metadata":{"transaction_id":%q}}}}`, txn.ID))
+	ctx := context.Background()
+	for delivery := 1; delivery <= 2; delivery++ {
+		result, err := uc.Execute(ctx, "stripe", body, stripeHeader("whsec_stripe", body))
+		if err != nil {
+			t.Fatalf("delivery %d error: %v", delivery, err)
+		}
+		if want := (transaction.ProviderEventsResult{Handled: 2 - delivery, Duplicates: delivery - 1}); result != want {
+			t.Errorf("delivery %d = %+v, want %+v", delivery, result, want)
+		}
+	}
+
+	got, _ := transactions.GetByID(ctx, txn.ID)
+	if !got.IsCompleted() || got.ProviderTransactionID != "pi_1" {
+		t.Errorf("transaction = %s with %q, want completed with the payment intent", got.Status, got.ProviderTransactionID)
+	}
+	if backlog, _ := outbox.Backlog(ctx); backlog.Pending != 1 {
+		t.Errorf("outbox has %d events, want one payment.completed", backlog.Pending)
+	}
+
+	if _, err := uc.Execute(ctx, "adyen", body, nil); !stderrors.Is(err, errors.ErrProviderNotConfigured) {
+		t.Errorf("Execute() for adyen error = %v, want ErrProviderNotConfigured", err)
+	}
+}
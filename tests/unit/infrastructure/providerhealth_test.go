@@ -0,0 +1,89 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/infrastructure/providerhealth"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// quietLog drops the log lines of a tracker
+type quietLog struct{}
+
+func (quietLog) Info(string, ...interface{}) {}
+func (quietLog) Warn(string, ...interface{}) {}
+
+func TestTracker_DegradesAndRecovers(t *testing.T) {
+	tracker := providerhealth.NewTracker(providerhealth.Config{
+		Window:    300 * time.Millisecond,
+		MinCalls:  4,
+		ErrorRate: 0.5,
+	}, quietLog{}, nil)
+
+	failure := errors.New("503 Service Unavailable")
+	tracker.Record("stripe", nil)
+	tracker.Record("stripe", failure)
+	tracker.Record("stripe", failure)
+	if tracker.Degraded("stripe") {
+		t.Fatal("degraded after 3 calls, want MinCalls first")
+	}
+	tracker.Record("stripe", nil)
+	tracker.Record("paypal", nil)
+	if !tracker.Degraded("stripe") || tracker.Degraded("paypal") {
+		t.Fatal("want stripe degraded at 2 of 4 failed, paypal not")
+	}
+
+	health := tracker.CheckHealth(context.Background())
+	if health.Status != ports.HealthDegraded || health.Error != "degraded providers: [stripe]" {
+		t.Errorf("CheckHealth() = %+v, want degraded by stripe", health)
+	}
+
+	// Without calls the failures leave the window and the provider recovers
+	time.Sleep(350 * time.Millisecond)
+	if tracker.Degraded("stripe") {
+		t.Error("still degraded after the window")
+	}
+	if health := tracker.CheckHealth(context.Background()); health.Status != ports.HealthUp {
+		t.Errorf("CheckHealth() = %+v, want up", health)
+	}
+}
+
+func TestTracker_CountsLiveCallsAndProbes(t *testing.T) {
+	tracker := providerhealth.NewTracker(providerhealth.Config{Window: time.Minute, MinCalls: 1, ErrorRate: 0.5}, quietLog{}, nil)
+	gateway := tracker.Gateway(payment.NewMockPaymentGateway("mock"))
+
+	money, _ := valueobjects.NewMoney(1000, "USD")
+	txn, _ := entities.NewTransaction(uuid.New(), "key", money, valueobjects.PaymentMethodCard, valueobjects.ProviderAdyen, "customer@example.com")
+	if _, err := gateway.ProcessPayment(context.Background(), txn); err != nil {
+		t.Fatalf("ProcessPayment() error: %v", err)
+	}
+	txn.Livemode = false
+	_, _ = gateway.ProcessPayment(context.Background(), txn)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	poller := providerhealth.NewPoller(tracker, map[string][]providerhealth.Probe{
+		"paypal": {providerhealth.StatusPageProbe(server.URL, server.Client())},
+	}, time.Second)
+	poller.Poll(context.Background())
+
+	rates := tracker.Rates()
+	if rates["adyen"].Calls != 1 || rates["adyen"].Failures != 0 {
+		t.Errorf("adyen = %+v, want the live payment only", rates["adyen"])
+	}
+	if rates["paypal"].Failures != 1 || !rates["paypal"].Degraded {
+		t.Errorf("paypal = %+v, want degraded by the failed probe", rates["paypal"])
+	}
+}
@@ -0,0 +1,129 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"Pay2Go/internal/infrastructure/ratelimit"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// rateLimitWindow is short enough to wait out, and long enough for the
+// requests of a test to land where they are meant to in it
+const rateLimitWindow = 400 * time.Millisecond
+
+func TestMemoryStore_SlidingWindow(t *testing.T) {
+	testSlidingWindow(t, ratelimit.NewMemoryStore())
+}
+
+func TestRedisStore_SlidingWindow(t *testing.T) {
+	testSlidingWindow(t, newRedisRateLimitStore(t, miniredis.RunT(t)))
+}
+
+func newRedisRateLimitStore(t *testing.T, server *miniredis.Miniredis) *ratelimit.RedisStore {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return ratelimit.NewRedisStore(client)
+}
+
+// testSlidingWindow checks a store counts requests in a window sliding
+// with each of them, rather than one reset on the clock
+func testSlidingWindow(t *testing.T, store ports.RateLimitStore) {
+	ctx := context.Background()
+	allow := func(key string) ports.RateLimitResult {
+		t.Helper()
+		result, err := store.Allow(ctx, key, 2, rateLimitWindow)
+		if err != nil {
+			t.Fatalf("Allow() error: %v", err)
+		}
+		return result
+	}
+
+	start := time.Now()
+	first := allow("ratelimit:partner:acme")
+	if !first.Allowed || first.Limit != 2 || first.Remaining != 1 || first.RetryAfter != 0 {
+		t.Fatalf("first request = %+v, want allowed with 1 remaining", first)
+	}
+	if reset := first.ResetAt.Sub(start); reset < rateLimitWindow-50*time.Millisecond || reset > rateLimitWindow+50*time.Millisecond {
+		t.Errorf("first request resets in %v, want a window", reset)
+	}
+
+	time.Sleep(rateLimitWindow / 2)
+	if second := allow("ratelimit:partner:acme"); !second.Allowed || second.Remaining != 0 {
+		t.Fatalf("second request = %+v, want allowed with none remaining", second)
+	}
+
+	// Over the limit: refused until the first request leaves the window,
+	// and not counted
+	third := allow("ratelimit:partner:acme")
+	if third.Allowed || third.Remaining != 0 || !third.ResetAt.Equal(first.ResetAt) {
+		t.Errorf("third request = %+v, want refused until %v", third, first.ResetAt)
+	}
+	if third.RetryAfter <= 0 || third.RetryAfter > rateLimitWindow/2 {
+		t.Errorf("third request retries after %v, want until the first request leaves the window", third.RetryAfter)
+	}
+	if peek, err := store.Peek(ctx, "ratelimit:partner:acme", 2, rateLimitWindow); err != nil || peek.Allowed || peek.Remaining != 0 {
+		t.Errorf("Peek() = %+v, %v; want refused", peek, err)
+	}
+
+	// Keys are counted apart
+	if other := allow("ratelimit:partner:globex"); !other.Allowed || other.Remaining != 1 {
+		t.Errorf("other key = %+v, want allowed with 1 remaining", other)
+	}
+
+	// Once the first request leaves the window the second still counts,
+	// the refused one does not
+	time.Sleep(time.Until(first.ResetAt.Add(50 * time.Millisecond)))
+	if peek, err := store.Peek(ctx, "ratelimit:partner:acme", 2, rateLimitWindow); err != nil || !peek.Allowed || peek.Remaining != 1 {
+		t.Errorf("Peek() after the first request left = %+v, %v; want 1 remaining", peek, err)
+	}
+	fourth := allow("ratelimit:partner:acme")
+	if !fourth.Allowed || fourth.Remaining != 0 {
+		t.Errorf("fourth request = %+v, want allowed with none remaining", fourth)
+	}
+
+	// Once every request has left the window, the key is back to its limit
+	time.Sleep(rateLimitWindow + 50*time.Millisecond)
+	if peek, err := store.Peek(ctx, "ratelimit:partner:acme", 2, rateLimitWindow); err != nil || !peek.Allowed || peek.Remaining != 2 {
+		t.Errorf("Peek() after the window = %+v, %v; want the full limit", peek, err)
+	}
+}
+
+func TestRedisStore_KeysExpire(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := newRedisRateLimitStore(t, server)
+
+	// Peeking does not create a key
+	if _, err := store.Peek(ctx, "ratelimit:ip:10.0.0.1", 2, time.Minute); err != nil {
+		t.Fatalf("Peek() error: %v", err)
+	}
+	if server.Exists("ratelimit:ip:10.0.0.1") {
+		t.Error("Peek() created the key")
+	}
+
+	// A key lives a window from its latest request, so idle keys go away
+	for i := 0; i < 3; i++ {
+		if _, err := store.Allow(ctx, "ratelimit:ip:10.0.0.1", 2, time.Minute); err != nil {
+			t.Fatalf("Allow() error: %v", err)
+		}
+	}
+	if ttl := server.TTL("ratelimit:ip:10.0.0.1"); ttl != time.Minute {
+		t.Errorf("key TTL = %v, want the window", ttl)
+	}
+	server.FastForward(time.Minute)
+	if server.Exists("ratelimit:ip:10.0.0.1") {
+		t.Error("key still exists a window after its latest request")
+	}
+
+	// An unavailable Redis is reported, for the limiter to fail open
+	server.Close()
+	if _, err := store.Allow(ctx, "ratelimit:ip:10.0.0.1", 2, time.Minute); err == nil {
+		t.Error("Allow() with Redis down succeeded, want an error")
+	}
+}
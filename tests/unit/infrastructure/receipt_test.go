@@ -0,0 +1,96 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/receipt"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestReceiptRenderer(t *testing.T) {
+	money, _ := valueobjects.NewMoney(1050, "USD")
+	txn, err := entities.NewTransaction(uuid.New(), "order-1", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	if err != nil {
+		t.Fatalf("NewTransaction() error: %v", err)
+	}
+	txn.Livemode = true
+	txn.Description = "Annual plan (2 seats)"
+	_ = txn.MarkAsProcessing()
+	if err := txn.MarkAsCompleted("ch_3xyz789"); err != nil {
+		t.Fatalf("MarkAsCompleted() error: %v", err)
+	}
+
+	reason, _ := valueobjects.NewRefundReason("duplicate", "")
+	refundAmount, _ := valueobjects.NewMoney(250, "USD")
+	refund, err := entities.NewRefund(txn.ID, refundAmount, reason)
+	if err != nil {
+		t.Fatalf("NewRefund() error: %v", err)
+	}
+
+	logo := image.NewNRGBA(image.Rect(0, 0, 30, 10))
+	for x := 0; x < 30; x++ {
+		logo.Set(x, 5, color.NRGBA{R: 200, A: 255})
+	}
+	var logoPNG bytes.Buffer
+	if err := png.Encode(&logoPNG, logo); err != nil {
+		t.Fatalf("png.Encode() error: %v", err)
+	}
+
+	input := ports.Receipt{
+		PartnerName: "Café Bangkok ร้าน",
+		Logo:        logoPNG.Bytes(),
+		Locale:      valueobjects.Locale("en-US"),
+		Transaction: txn,
+		Refunds:     []*entities.Refund{refund},
+	}
+	renderer := receipt.NewRenderer()
+	pdf, err := renderer.Render(input)
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("Render() = %q..., want a PDF document", pdf[:20])
+	}
+	for _, want := range []string{
+		// Latin-1 is kept and Thai replaced; parentheses are escaped
+		"(Receipt)", "(Caf\xe9 Bangkok ????)", "(Annual plan \\(2 seats\\))",
+		// How it was paid
+		"(Card)", "(Stripe)", "(ch_3xyz789)",
+		// The payment, the refund and what was paid in the end
+		"(USD\xa010.50)", "\\(duplicate payment\\))", "(-USD\xa02.50)", "(Net paid)", "(USD\xa08.00)",
+		"/Im1 Do", "/Width 30 /Height 10",
+	} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("Render() lacks %q", want)
+		}
+	}
+
+	// The same receipt always renders to the same bytes, so it can be
+	// cached and compared
+	again, err := renderer.Render(input)
+	if err != nil || !bytes.Equal(again, pdf) {
+		t.Errorf("Render() again = %d bytes, %v; want the same %d bytes", len(again), err, len(pdf))
+	}
+
+	// Sandbox receipts say so
+	txn.Livemode = false
+	input.Logo = nil
+	pdf, err = renderer.Render(input)
+	if err != nil || !bytes.Contains(pdf, []byte("(Test receipt)")) || bytes.Contains(pdf, []byte("/Im1")) {
+		t.Errorf("Render(test mode) = %v; want a test receipt without a logo", err)
+	}
+
+	input.Logo = []byte("not an image")
+	if _, err := renderer.Render(input); err == nil {
+		t.Error("Render() with a broken logo succeeded, want an error")
+	}
+}
@@ -0,0 +1,124 @@
+package infrastructure_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/infrastructure/sentry"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// sentryEvent is what the tests read back from an envelope
+type sentryEvent struct {
+	Level     string            `json:"level"`
+	Tags      map[string]string `json:"tags"`
+	Exception struct {
+		Values []struct {
+			Type       string `json:"type"`
+			Value      string `json:"value"`
+			Stacktrace struct {
+				Frames []struct {
+					Function string `json:"function"`
+					InApp    bool   `json:"in_app"`
+				} `json:"frames"`
+			} `json:"stacktrace"`
+		} `json:"values"`
+	} `json:"exception"`
+}
+
+// reportAll sends reports through a reporter with sampleRate and returns
+// the events the server received
+func reportAll(t *testing.T, sampleRate float64, reports ...ports.ErrorReport) []sentryEvent {
+	t.Helper()
+	var events []sentryEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/envelope/" || !strings.Contains(r.Header.Get("X-Sentry-Auth"), "sentry_key=public") {
+			t.Errorf("request to %s with auth %q", r.URL.Path, r.Header.Get("X-Sentry-Auth"))
+		}
+		// An envelope is a header line, an item header line and the event
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(nil, 1<<20)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) != 3 {
+			t.Errorf("envelope has %d lines, want 3", len(lines))
+			return
+		}
+		var e sentryEvent
+		if err := json.Unmarshal([]byte(lines[2]), &e); err != nil {
+			t.Errorf("event is not JSON: %v", err)
+			return
+		}
+		events = append(events, e)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "http://", "http://public@", 1) + "/42"
+	reporter, err := sentry.NewReporter(sentry.Config{DSN: dsn, SampleRate: sampleRate, BufferSize: 10}, server.Client(), func(err error) {
+		t.Errorf("send failed: %v", err)
+	})
+	if err != nil {
+		t.Fatalf("NewReporter() error: %v", err)
+	}
+
+	ctx := ports.WithRequestID(context.Background(), uuid.MustParse("8f5b1c2e-3d4a-4b6c-9e7f-0a1b2c3d4e5f"))
+	for _, report := range reports {
+		reporter.Report(ctx, report)
+	}
+	// Run sends what is queued once its context is done
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	reporter.Run(done)
+	return events
+}
+
+func TestSentryReporter_SendsTaggedErrors(t *testing.T) {
+	err := fmt.Errorf("payment processing failed: %w", errors.New("card declined"))
+	events := reportAll(t, 1, ports.ErrorReport{Err: err, Tags: map[string]string{"provider": "stripe"}})
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+
+	e := events[0]
+	ex := e.Exception.Values[0]
+	if e.Level != "error" || ex.Type != "*errors.errorString" || ex.Value != err.Error() {
+		t.Errorf("event = %s %s: %s, want error *errors.errorString: %s", e.Level, ex.Type, ex.Value, err)
+	}
+	if e.Tags["provider"] != "stripe" || e.Tags["request_id"] != "8f5b1c2e-3d4a-4b6c-9e7f-0a1b2c3d4e5f" {
+		t.Errorf("tags = %v, want provider and request_id", e.Tags)
+	}
+	// The newest frame is the code that reported
+	frames := ex.Stacktrace.Frames
+	if last := frames[len(frames)-1]; !strings.Contains(last.Function, "reportAll") {
+		t.Errorf("newest frame = %s, want reportAll", last.Function)
+	}
+}
+
+func TestSentryReporter_SamplesErrorsButNotPanics(t *testing.T) {
+	events := reportAll(t, 0,
+		ports.ErrorReport{Err: errors.New("sampled out")},
+		ports.ErrorReport{Panic: "index out of range"},
+	)
+	if len(events) != 1 || events[0].Level != "fatal" || events[0].Exception.Values[0].Value != "index out of range" {
+		t.Fatalf("events = %+v, want only the panic", events)
+	}
+}
+
+func TestSentryReporter_RejectsInvalidDSN(t *testing.T) {
+	for _, dsn := range []string{"not a url", "https://sentry.example.com/42", "https://key@sentry.example.com/"} {
+		if _, err := sentry.NewReporter(sentry.Config{DSN: dsn, BufferSize: 1}, http.DefaultClient, nil); err == nil {
+			t.Errorf("NewReporter(%q) succeeded", dsn)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package infrastructure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/infrastructure/settlementfeed"
+)
+
+// invalidRecorder collects the records a feed skipped
+type invalidRecorder struct {
+	sources []string
+}
+
+func (r *invalidRecorder) record(source string, err error) {
+	r.sources = append(r.sources, source+": "+err.Error())
+}
+
+func TestDirectoryFeed_ReadsFilesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"2024-03-02.csv": "reference,provider,type,provider_transaction_id,amount,currency,occurred_at\n" +
+			"cb_1,stripe,chargeback,ch_1,10.00,USD,2024-03-02T09:00:00Z\n",
+		"2024-03-01.csv": "provider,reference,type,provider_transaction_id,amount,fee,currency,reason,occurred_at\n" +
+			"stripe,st_1,settlement,ch_1,10.00,0.59,USD,,2024-03-01T09:00:00Z\n" +
+			"stripe,st_2,settlement,ch_2,-1,,USD,,2024-03-01T09:00:00Z\n",
+		"broken.csv":          "not,a,settlement,file\n",
+		".uploading.csv":      "provider\n",
+		"2024-03-03.csv.part": "provider\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	invalid := &invalidRecorder{}
+	feed, err := settlementfeed.NewDirectoryFeed(dir, invalid.record)
+	if err != nil {
+		t.Fatalf("NewDirectoryFeed() error: %v", err)
+	}
+	ctx := context.Background()
+
+	entries, err := feed.Next(ctx)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Next() = %d entries, %v; want the valid row of the first file", len(entries), err)
+	}
+	if e := entries[0]; e.Reference != "st_1" || e.Amount.Amount != 1000 || e.Fee.Amount != 59 || e.Source != "2024-03-01.csv" {
+		t.Errorf("entry = %+v, want st_1 of 10.00 with a 0.59 fee from 2024-03-01.csv", e)
+	}
+	if len(invalid.sources) != 1 || !strings.HasPrefix(invalid.sources[0], "2024-03-01.csv: line 3:") {
+		t.Errorf("invalid = %v, want line 3 of the first file", invalid.sources)
+	}
+
+	// Until committed, the same file is read again
+	if again, _ := feed.Next(ctx); len(again) != 1 || again[0].Reference != "st_1" {
+		t.Errorf("Next() before Commit = %v, want the first file again", again)
+	}
+	if err := feed.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "processed", "2024-03-01.csv")); err != nil {
+		t.Errorf("committed file not in processed: %v", err)
+	}
+
+	entries, err = feed.Next(ctx)
+	if err != nil || len(entries) != 1 || entries[0].Type != entities.SettlementEntryChargeback {
+		t.Fatalf("Next() = %v, %v; want the chargeback of the second file", entries, err)
+	}
+	if err := feed.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+
+	// A file without the columns is rejected as a whole
+	if entries, err := feed.Next(ctx); err != nil || len(entries) != 0 {
+		t.Fatalf("Next() of a broken file = %v, %v; want none", entries, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "rejected", "broken.csv")); err != nil {
+		t.Errorf("broken file not in rejected: %v", err)
+	}
+
+	// Files still uploading are left alone
+	if entries, err := feed.Next(ctx); err != nil || len(entries) != 0 {
+		t.Errorf("Next() of an empty directory = %v, %v; want none", entries, err)
+	}
+}
+
+func TestKafkaFeed_CommitsAfterReconciling(t *testing.T) {
+	var calls []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/consumers/reconciliation":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["auto.commit.enable"] != "false" || body["format"] != "json" {
+				t.Errorf("consumer config = %v, want JSON without auto commit", body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"instance_id": "pay2go",
+				"base_uri":    server.URL + "/consumers/reconciliation/instances/pay2go",
+			})
+		case r.URL.Path == "/consumers/reconciliation/instances/pay2go/records":
+			w.Write([]byte(`[
+				{"topic":"settlements","partition":0,"offset":7,"value":{"provider":"stripe","reference":"st_1","type":"settlement","provider_transaction_id":"ch_1","amount":"10.00","currency":"USD","occurred_at":"2024-03-01T09:00:00Z"}},
+				{"topic":"settlements","partition":0,"offset":8,"value":{"provider":"stripe","reference":"st_2","type":"refund"}}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	invalid := &invalidRecorder{}
+	feed, err := settlementfeed.NewKafkaFeed(settlementfeed.KafkaConfig{
+		RESTURL: server.URL,
+		Topic:   "settlements",
+		Group:   "reconciliation",
+	}, server.Client(), invalid.record)
+	if err != nil {
+		t.Fatalf("NewKafkaFeed() error: %v", err)
+	}
+	ctx := context.Background()
+
+	entries, err := feed.Next(ctx)
+	if err != nil || len(entries) != 1 || entries[0].Reference != "st_1" || entries[0].Source != "settlements/0/7" {
+		t.Fatalf("Next() = %v, %v; want st_1 from offset 7", entries, err)
+	}
+	if len(invalid.sources) != 1 || !strings.HasPrefix(invalid.sources[0], "settlements/0/8") {
+		t.Errorf("invalid = %v, want offset 8", invalid.sources)
+	}
+	if err := feed.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+	if err := feed.Close(ctx); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	want := []string{
+		"POST /consumers/reconciliation",
+		"POST /consumers/reconciliation/instances/pay2go/subscription",
+		"GET /consumers/reconciliation/instances/pay2go/records",
+		"POST /consumers/reconciliation/instances/pay2go/offsets",
+		"DELETE /consumers/reconciliation/instances/pay2go",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,96 @@
+package infrastructure_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/infrastructure/slowcall"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// slowCallRecorder is the log, counter and alert sink of a monitor
+type slowCallRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+	counts   map[string]int
+	alerts   chan ports.Alert
+}
+
+func newSlowCallRecorder() *slowCallRecorder {
+	return &slowCallRecorder{counts: make(map[string]int), alerts: make(chan ports.Alert, 10)}
+}
+
+func (r *slowCallRecorder) Warn(message string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warnings = append(r.warnings, fmt.Sprintf(message, args...))
+}
+
+func (r *slowCallRecorder) SlowQuery(statement string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts["query:"+statement]++
+}
+
+func (r *slowCallRecorder) SlowGatewayCall(provider, operation string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts["gateway:"+provider+":"+operation]++
+}
+
+func (r *slowCallRecorder) Send(_ context.Context, alert ports.Alert) error {
+	r.alerts <- alert
+	return nil
+}
+
+func TestMonitor_AlertsOnSustainedSlowQueries(t *testing.T) {
+	recorder := newSlowCallRecorder()
+	monitor := slowcall.NewMonitor(slowcall.Config{
+		QueryThreshold: 100 * time.Millisecond,
+		AlertWindow:    time.Minute,
+		AlertAfter:     3,
+	}, recorder, recorder, recorder)
+
+	ctx := context.Background()
+	query := "SELECT id\n\t  FROM transactions WHERE partner_id = $1"
+	monitor.ObserveQuery(ctx, query, 50*time.Millisecond, nil)
+	for i := 0; i < 4; i++ {
+		monitor.ObserveQuery(ctx, query, 200*time.Millisecond, nil)
+	}
+
+	if got := recorder.counts["query:select"]; got != 4 {
+		t.Errorf("slow selects counted = %d, want 4", got)
+	}
+	if len(recorder.warnings) != 4 || !strings.Contains(recorder.warnings[0], `"SELECT id FROM transactions WHERE partner_id = $1"`) {
+		t.Errorf("warnings = %q, want 4 with the query on one line", recorder.warnings)
+	}
+
+	select {
+	case alert := <-recorder.alerts:
+		if alert.Title != "Database queries are slow" || !strings.HasPrefix(alert.Text, "3 queries") {
+			t.Errorf("alert = %+v, want slow queries after 3 breaches", alert)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no alert after 3 slow queries")
+	}
+	// The fourth breach falls in the same window and is not alerted again
+	select {
+	case alert := <-recorder.alerts:
+		t.Errorf("second alert %+v within the window", alert)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMonitor_ThresholdZeroIsOff(t *testing.T) {
+	recorder := newSlowCallRecorder()
+	monitor := slowcall.NewMonitor(slowcall.Config{AlertWindow: time.Minute, AlertAfter: 1}, recorder, recorder, recorder)
+
+	monitor.ObserveQuery(context.Background(), "UPDATE transactions SET status = $1", time.Hour, nil)
+	if len(recorder.warnings) != 0 || len(recorder.counts) != 0 {
+		t.Errorf("warnings = %q, counts = %v with the check off", recorder.warnings, recorder.counts)
+	}
+}
@@ -0,0 +1,152 @@
+package infrastructure_test
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/cache"
+	"Pay2Go/internal/infrastructure/ratelimit"
+	"Pay2Go/internal/infrastructure/sms"
+	"Pay2Go/internal/usecases/notification"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// smsRecorder is an SMS sender keeping what it was asked to send
+type smsRecorder struct {
+	messages []ports.SMS
+}
+
+func (r *smsRecorder) SendSMS(_ context.Context, sms ports.SMS) error {
+	r.messages = append(r.messages, sms)
+	return nil
+}
+
+func TestTwilioSender_SendsFromPartnerSender(t *testing.T) {
+	var forms []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "AC123" || pass != "twilio-token" {
+			t.Errorf("basic auth = %q/%q, want the account's credentials", user, pass)
+		}
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		forms = append(forms, r.PostForm)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sender, err := sms.NewTwilioSender(sms.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "twilio-token",
+		From:       "+15005550006",
+		BaseURL:    server.URL,
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("NewTwilioSender() error: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := sender.SendSMS(ctx, ports.SMS{To: "+66812345678", Body: "Paid"}); err != nil {
+		t.Fatalf("SendSMS() error: %v", err)
+	}
+	if err := sender.SendSMS(ctx, ports.SMS{From: "MG0123456789abcdef0123456789abcdef", To: "+66812345678", Body: "Paid"}); err != nil {
+		t.Fatalf("SendSMS() error: %v", err)
+	}
+
+	if len(forms) != 2 {
+		t.Fatalf("Twilio got %d messages, want 2", len(forms))
+	}
+	if forms[0].Get("From") != "+15005550006" || forms[0].Get("To") != "+66812345678" {
+		t.Errorf("first message = %v, want it from the default sender", forms[0])
+	}
+	if forms[1].Get("MessagingServiceSid") != "MG0123456789abcdef0123456789abcdef" || forms[1].Get("From") != "" {
+		t.Errorf("second message = %v, want it from the partner's messaging service", forms[1])
+	}
+}
+
+func TestTexts_LimitsMessagesPerRecipient(t *testing.T) {
+	_, partner := newWebhookPartner(t)
+	sender := &smsRecorder{}
+	texts := notification.NewTexts(sender, ratelimit.NewMemoryStore(), notification.SMSPolicy{
+		PerPartner:   100,
+		PerRecipient: 2,
+		Window:       time.Hour,
+	})
+	phone, err := valueobjects.NewPhoneNumber("+66 81-234-5678")
+	if err != nil {
+		t.Fatalf("NewPhoneNumber() error: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := texts.Send(ctx, partner, phone, "Paid"); err != nil {
+			t.Fatalf("Send() #%d error: %v", i+1, err)
+		}
+	}
+	if err := texts.Send(ctx, partner, phone, "Paid"); !stderrors.Is(err, errors.ErrSMSRateLimited) {
+		t.Fatalf("third Send() error = %v, want ErrSMSRateLimited", err)
+	}
+	if len(sender.messages) != 2 || sender.messages[0].To != "+66812345678" {
+		t.Errorf("sent %v, want two messages to the normalized number", sender.messages)
+	}
+}
+
+func TestVerification_CodeIsGoodOnce(t *testing.T) {
+	partners, partner := newWebhookPartner(t)
+	if err := partner.SetFeature(valueobjects.FeatureSMSVerification, true); err != nil {
+		t.Fatalf("SetFeature() error: %v", err)
+	}
+	if err := partners.Update(context.Background(), partner); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	transactions := memory.NewTransactionRepository(memory.NewStore())
+	money, _ := valueobjects.NewMoney(1000, "USD")
+	txn, _ := entities.NewTransaction(partner.ID, "key", money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	_ = txn.SetCustomerInfo("customer@example.com", "Jane", "+66812345678")
+	if err := transactions.Create(context.Background(), txn); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+
+	sender := &smsRecorder{}
+	limits := ratelimit.NewMemoryStore()
+	codes := cache.NewMemoryCodeStore(10)
+	policy := notification.VerificationPolicy{TTL: 10 * time.Minute, MaxAttempts: 3}
+	send := notification.NewSendVerificationCodeUseCase(partners, transactions, codes,
+		notification.NewTexts(sender, limits, notification.SMSPolicy{PerPartner: 10, PerRecipient: 10, Window: time.Hour}), policy)
+	verify := notification.NewVerifyCodeUseCase(transactions, codes, limits, nil, policy)
+
+	ctx := context.Background()
+	if _, err := send.Execute(ctx, partner.ID, txn.ID); err != nil {
+		t.Fatalf("send Execute() error: %v", err)
+	}
+	if len(sender.messages) != 1 {
+		t.Fatalf("sent %d messages, want one code", len(sender.messages))
+	}
+	code := regexp.MustCompile(`\d{6}`).FindString(sender.messages[0].Body)
+	if code == "" {
+		t.Fatalf("message %q has no code", sender.messages[0].Body)
+	}
+
+	input := notification.VerifyCodeInput{PartnerID: partner.ID, TransactionID: txn.ID, Code: "not-it"}
+	if err := verify.Execute(ctx, input); !stderrors.Is(err, errors.ErrInvalidVerificationCode) {
+		t.Fatalf("wrong code error = %v, want ErrInvalidVerificationCode", err)
+	}
+	input.Code = code
+	if err := verify.Execute(ctx, input); err != nil {
+		t.Fatalf("right code error: %v", err)
+	}
+	if err := verify.Execute(ctx, input); !stderrors.Is(err, errors.ErrInvalidVerificationCode) {
+		t.Errorf("reused code error = %v, want ErrInvalidVerificationCode", err)
+	}
+}
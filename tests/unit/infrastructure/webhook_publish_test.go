@@ -0,0 +1,83 @@
+package infrastructure_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/infrastructure/webhook"
+)
+
+// roundTripFunc answers requests without a network
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newWebhookPartner stores a partner with a webhook URL and returns it
+func newWebhookPartner(tb testing.TB) (*memory.PartnerRepository, *entities.Partner) {
+	tb.Helper()
+	repo := memory.NewPartnerRepository(memory.NewStore())
+	partner, err := entities.NewPartner("Acme", "acme@example.com")
+	if err != nil {
+		tb.Fatalf("NewPartner() error: %v", err)
+	}
+	if err := partner.SetWebhook("https://acme.example.com/hooks", "whsec_test"); err != nil {
+		tb.Fatalf("SetWebhook() error: %v", err)
+	}
+	if err := repo.Create(context.Background(), partner); err != nil {
+		tb.Fatalf("Create() error: %v", err)
+	}
+	return repo, partner
+}
+
+func newPaymentEvent(tb testing.TB, partnerID uuid.UUID) *entities.OutboxEvent {
+	tb.Helper()
+	event, err := entities.NewOutboxEvent(partnerID, "transaction", uuid.New(), entities.EventPaymentCompleted, struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{"149.90", "USD"})
+	if err != nil {
+		tb.Fatalf("NewOutboxEvent() error: %v", err)
+	}
+	return event
+}
+
+func TestPublisher_DeliversStoredPayload(t *testing.T) {
+	repo, partner := newWebhookPartner(t)
+	event := newPaymentEvent(t, partner.ID)
+
+	var delivered struct {
+		ID   string          `json:"id"`
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		if err := json.Unmarshal(body, &delivered); err != nil {
+			t.Errorf("delivered body %s: %v", body, err)
+		}
+		if !strings.HasPrefix(req.Header.Get(webhook.HeaderSignature), "t=") {
+			t.Errorf("signature header = %q", req.Header.Get(webhook.HeaderSignature))
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+
+	if err := webhook.NewPublisher(repo, client).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error: %v", err)
+	}
+	if delivered.ID != event.ID.String() || delivered.Type != entities.EventPaymentCompleted {
+		t.Errorf("delivered %s %s, want %s %s", delivered.ID, delivered.Type, event.ID, entities.EventPaymentCompleted)
+	}
+	if string(delivered.Data) != `{"amount":"149.90","currency":"USD"}` {
+		t.Errorf("delivered data = %s, want the event payload as stored", delivered.Data)
+	}
+}
@@ -0,0 +1,23 @@
+package infrastructure_test
+
+import (
+	"testing"
+	"time"
+
+	"Pay2Go/internal/infrastructure/webhook"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"evt"}`)
+	timestamp := time.Unix(1700000000, 0)
+
+	got := webhook.Sign("whsec_test", timestamp, body)
+	want := "t=1700000000,v1=a94cea056df1fbb92eadafcf2c5cd541dbe0c6ef736e4748202dd53f86694a3e"
+	if got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+
+	if other := webhook.Sign("whsec_other", timestamp, body); other == got {
+		t.Error("Sign() with a different secret gave the same signature")
+	}
+}
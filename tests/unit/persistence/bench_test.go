@@ -0,0 +1,79 @@
+package persistence_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/ports"
+)
+
+// BenchmarkRepositoryHotPath times the queries behind every authenticated
+// payment request on each contract backend, so a release that slows one of
+// them shows up in benchstat before it shows up in production.
+func BenchmarkRepositoryHotPath(b *testing.B) {
+	for _, bc := range []struct {
+		name     string
+		newRepos func(t testing.TB) repositories
+	}{
+		{"Memory", newMemoryRepositories},
+		{"SQLite", newSQLiteRepositories},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			repos := bc.newRepos(b)
+			ctx := context.Background()
+			partner := createPartner(b, repos, "bench@example.com")
+			for i := 0; i < 100; i++ {
+				createTransaction(b, repos, partner.ID, fmt.Sprintf("seed-%d", i), 1000, base.Add(time.Duration(i)*time.Minute))
+			}
+			key, _, err := entities.NewAPIKey(partner.ID, "Bench", []valueobjects.APIKeyScope{valueobjects.ScopePayments}, entities.AuthMethodBearer, false)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := repos.apiKeys.Create(ctx, key); err != nil {
+				b.Fatal(err)
+			}
+
+			b.Run("APIKeyGetByPrefix", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := repos.apiKeys.GetByPrefix(ctx, key.KeyPrefix); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("PartnerGetByID", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := repos.partners.GetByID(ctx, partner.ID); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("TransactionList", func(b *testing.B) {
+				query := ports.TransactionQuery{PartnerID: &partner.ID, Limit: 20}
+				for i := 0; i < b.N; i++ {
+					if _, _, err := repos.transactions.List(ctx, query); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			// created numbers idempotency keys across the runs b.Run makes
+			created := 0
+			b.Run("TransactionCreate", func(b *testing.B) {
+				money, _ := valueobjects.NewMoney(1000, "USD")
+				for i := 0; i < b.N; i++ {
+					created++
+					txn, err := entities.NewTransaction(partner.ID, fmt.Sprintf("create-%d", created), money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+					if err != nil {
+						b.Fatal(err)
+					}
+					if err := repos.transactions.Create(ctx, txn); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package persistence_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"Pay2Go/internal/adapters/persistence/postgres"
+	"Pay2Go/internal/adapters/persistence/sqldb"
+	"Pay2Go/internal/infrastructure/encryption"
+	"Pay2Go/internal/infrastructure/migrate"
+	"Pay2Go/migrations"
+)
+
+// newPostgresRepositories are the PostgreSQL repositories the Postgres cases
+// use, on the database PAY2GO_TEST_POSTGRES_DSN names. Its public schema is
+// dropped and migrated again for every case, so it must be a disposable
+// database; without it the cases are skipped.
+func newPostgresRepositories(t testing.TB) repositories {
+	return postgresRepositories(t, newPostgresDB(t))
+}
+
+// postgresRepositories are the PostgreSQL repositories on db
+func postgresRepositories(t testing.TB, db *sql.DB) repositories {
+	cipher, err := encryption.NewEnvelopeCipher(map[string]string{"test": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}, "test")
+	if err != nil {
+		t.Fatalf("NewEnvelopeCipher() error: %v", err)
+	}
+	return repositories{
+		transactions: postgres.NewTransactionRepository(db, nil),
+		partners:     postgres.NewPartnerRepository(db, nil, cipher),
+		auditLogs:    postgres.NewAuditLogRepository(db, nil),
+		unitOfWork:   sqldb.NewUnitOfWork(db),
+	}
+}
+
+// newPostgresDB is the freshly migrated database PAY2GO_TEST_POSTGRES_DSN
+// names, skipping the case without it
+func newPostgresDB(t testing.TB) *sql.DB {
+	dsn := os.Getenv("PAY2GO_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("PAY2GO_TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open() error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "DROP SCHEMA public CASCADE; CREATE SCHEMA public"); err != nil {
+		t.Fatalf("resetting the schema error: %v", err)
+	}
+	migrator, err := migrate.New(db, migrate.Postgres, migrations.FS)
+	if err != nil {
+		t.Fatalf("migrate.New() error: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error: %v", err)
+	}
+	return db
+}
+
+// TestRepositoryContract_Postgres runs the cases that depend on how
+// PostgreSQL handles concurrent transactions
+func TestRepositoryContract_Postgres(t *testing.T) {
+	cases := []struct {
+		name string
+		test func(t *testing.T, repos repositories)
+	}{
+		{"AuditLogChain", testAuditLogChain},
+		{"AuditLogConcurrentAppends", testAuditLogConcurrentAppends},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newPostgresRepositories(t))
+		})
+	}
+}
+
+// TestPostgres_MonthlyPartitions checks the partitions migration 000029 and
+// the partition manager create, and the month each transaction lands in
+func TestPostgres_MonthlyPartitions(t *testing.T) {
+	db := newPostgresDB(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// The migration creates the partitions from this month to three ahead
+	for _, table := range []string{"transactions", "refunds"} {
+		want := []string{table + "_default"}
+		for i := 0; i <= 3; i++ {
+			want = append(want, partitionName(table, month.AddDate(0, i, 0)))
+		}
+		assertPartitions(t, db, table, want)
+	}
+
+	// The manager creates the missing months only, once
+	manager := postgres.NewPartitionManager(db)
+	created, err := manager.EnsurePartitions(ctx, now, 5)
+	if err != nil {
+		t.Fatalf("EnsurePartitions() error: %v", err)
+	}
+	want := []string{
+		partitionName("transactions", month.AddDate(0, 4, 0)),
+		partitionName("refunds", month.AddDate(0, 4, 0)),
+		partitionName("transactions", month.AddDate(0, 5, 0)),
+		partitionName("refunds", month.AddDate(0, 5, 0)),
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("EnsurePartitions() created %v, want %v", created, want)
+	}
+	if created, err := manager.EnsurePartitions(ctx, now, 5); err != nil || len(created) != 0 {
+		t.Errorf("EnsurePartitions() again = %v, %v; want nothing created", created, err)
+	}
+
+	// Transactions land in the partition of their month in UTC, those past
+	// every partition in the default one
+	repos := postgresRepositories(t, db)
+	partner := createPartner(t, repos, "partitions@example.com")
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		partition string
+	}{
+		{"this month", now, partitionName("transactions", month)},
+		{"last instant of the month", month.AddDate(0, 1, 0).Add(-time.Microsecond), partitionName("transactions", month)},
+		{"first instant of the next", month.AddDate(0, 1, 0), partitionName("transactions", month.AddDate(0, 1, 0))},
+		{"next month locally, not in UTC", month.AddDate(0, 1, 0).Add(-time.Hour).In(bangkok), partitionName("transactions", month)},
+		{"created by the manager", month.AddDate(0, 5, 10), partitionName("transactions", month.AddDate(0, 5, 0))},
+		{"past every partition", month.AddDate(1, 0, 0), "transactions_default"},
+	}
+	for i, tt := range tests {
+		txn := createTransaction(t, repos, partner.ID, fmt.Sprintf("partition-%d", i), 1000, tt.createdAt)
+
+		var partition string
+		if err := db.QueryRowContext(ctx, `SELECT tableoid::regclass::text FROM transactions WHERE id = $1`, txn.ID).Scan(&partition); err != nil {
+			t.Fatalf("%s: finding the partition error: %v", tt.name, err)
+		}
+		if partition != tt.partition {
+			t.Errorf("%s: transaction is in %s, want %s", tt.name, partition, tt.partition)
+		}
+		got, err := repos.transactions.GetByID(ctx, txn.ID)
+		if err != nil || !got.CreatedAt.Equal(tt.createdAt.Truncate(time.Microsecond)) {
+			t.Errorf("%s: GetByID() = %v, %v; want it created at %v", tt.name, got, err, tt.createdAt)
+		}
+	}
+}
+
+// partitionName is the name create_monthly_partition gives the partition of
+// table for month
+func partitionName(table string, month time.Time) string {
+	return table + "_p" + month.Format("2006_01")
+}
+
+// assertPartitions checks table has exactly the partitions want
+func assertPartitions(t *testing.T, db *sql.DB, table string, want []string) {
+	t.Helper()
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $1::regclass`, table)
+	if err != nil {
+		t.Fatalf("listing the partitions of %s error: %v", table, err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("listing the partitions of %s error: %v", table, err)
+		}
+		got = append(got, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("listing the partitions of %s error: %v", table, err)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s partitions = %v, want %v", table, got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package persistence_test
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// BenchmarkTransactionHotPath compares the busiest transaction queries run
+// plain and as prepared statements. SQLite parses far faster than a database
+// server a network hop away, so the gain there is a lower bound; run it
+// against Postgres or MySQL for production numbers.
+func BenchmarkTransactionHotPath(b *testing.B) {
+	for _, bc := range []struct {
+		name     string
+		newRepos func(t testing.TB) repositories
+	}{
+		{"Plain", newSQLiteRepositories},
+		{"Prepared", newPreparedSQLiteRepositories},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			repos := bc.newRepos(b)
+			ctx := context.Background()
+			partner := createPartner(b, repos, "bench@example.com")
+			txn := createTransaction(b, repos, partner.ID, "bench", 1000, base)
+
+			b.Run("GetByID", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := repos.transactions.GetByID(ctx, txn.ID); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("GetByIdempotencyKey", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := repos.transactions.GetByIdempotencyKey(ctx, partner.ID, "bench"); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("Update", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					txn.UpdatedAt = time.Now()
+					if err := repos.transactions.Update(ctx, txn); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
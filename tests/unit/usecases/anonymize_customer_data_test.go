@@ -0,0 +1,86 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/objectstore"
+	"Pay2Go/internal/usecases/partner"
+	"Pay2Go/internal/usecases/ports"
+)
+
+func TestAnonymizeCustomerData(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	other := f.addPartner(t, "other@example.com")
+	store, err := objectstore.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() error: %v", err)
+	}
+
+	// The partner's customers show up in a transaction, a refund note, an
+	// event and an export file
+	txn := f.completedTransaction(t, 5000)
+	reason, _ := valueobjects.NewRefundReason("other", "Jane Doe says the parcel never arrived")
+	refund, _ := entities.NewRefund(txn.ID, txn.Amount, reason)
+	if err := f.refunds.Create(ctx, refund); err != nil {
+		t.Fatalf("Create(refund) error: %v", err)
+	}
+	addEvent := func(partnerID uuid.UUID) *entities.OutboxEvent {
+		event, _ := entities.NewOutboxEvent(partnerID, "transaction", txn.ID, entities.EventPaymentCompleted, map[string]string{})
+		if err := f.outbox.Add(ctx, event); err != nil {
+			t.Fatalf("Add(event) error: %v", err)
+		}
+		return event
+	}
+	addEvent(f.partner.ID)
+	otherEvent := addEvent(other.ID)
+	exportKey := "exports/" + f.partner.ID.String() + "/" + uuid.NewString() + ".csv"
+	otherExportKey := "exports/" + other.ID.String() + "/" + uuid.NewString() + ".csv"
+	for _, key := range []string{exportKey, otherExportKey} {
+		if err := store.Put(ctx, key, []byte("id,customer_email\n1,jane@example.com\n")); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+	}
+
+	if err := f.partner.Offboard(0); err != nil {
+		t.Fatalf("Offboard() error: %v", err)
+	}
+	if err := f.partners.Update(ctx, f.partner); err != nil {
+		t.Fatalf("Update(partner) error: %v", err)
+	}
+
+	uc := partner.NewAnonymizeCustomerDataUseCase(f.partners, f.transactions, f.refunds, f.outbox, store, nil)
+	processed, err := uc.Execute(ctx)
+	if err != nil || processed != 1 {
+		t.Fatalf("Execute() = %d, %v; want 1, nil", processed, err)
+	}
+
+	if got := f.transaction(t, txn.ID); got.CustomerEmail != "anonymized@invalid" {
+		t.Errorf("transaction email = %q, want it anonymized", got.CustomerEmail)
+	}
+	if got := f.refund(t, refund.ID); got.Reason.Note != "" || got.Reason.Code != valueobjects.RefundReasonOther {
+		t.Errorf("refund reason = %+v, want the note cleared and the code kept", got.Reason)
+	}
+
+	events, _ := f.outbox.ListByPartner(ctx, f.partner.ID, ports.OutboxCursor{}, otherEvent.CreatedAt.Add(1), 10)
+	if len(events) != 0 {
+		t.Errorf("partner's events = %d, want none", len(events))
+	}
+	if events, _ := f.outbox.ListByPartner(ctx, other.ID, ports.OutboxCursor{}, otherEvent.CreatedAt.Add(1), 10); len(events) != 1 {
+		t.Errorf("other partner's events = %d, want theirs kept", len(events))
+	}
+
+	if keys, _ := store.List(ctx, "exports/"); len(keys) != 1 || keys[0] != otherExportKey {
+		t.Errorf("export files = %v, want only the other partner's", keys)
+	}
+
+	// Nothing is left to do on the next run
+	if processed, err := uc.Execute(ctx); err != nil || processed != 0 {
+		t.Errorf("Execute() again = %d, %v; want 0, nil", processed, err)
+	}
+}
@@ -0,0 +1,156 @@
+package usecases_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/apikey"
+)
+
+// storeAPIKey stores a new key of the fixture's partner, returning it with
+// its plaintext
+func (f *fixture) storeAPIKey(t *testing.T, keys *memory.APIKeyRepository, method entities.APIKeyAuthMethod, hash func(plaintext string) string) (*entities.APIKey, string) {
+	t.Helper()
+	key, plaintext, err := entities.NewAPIKey(f.partner.ID, "ci", []valueobjects.APIKeyScope{valueobjects.ScopePayments}, method, true)
+	if err != nil {
+		t.Fatalf("NewAPIKey() error: %v", err)
+	}
+	if hash != nil {
+		key.KeyHash = hash(plaintext)
+	}
+	if err := keys.Create(context.Background(), key); err != nil {
+		t.Fatalf("Create(API key) error: %v", err)
+	}
+	return key, plaintext
+}
+
+func TestAuthenticator_RehashesLegacyKeys(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	keys := memory.NewAPIKeyRepository(f.store)
+	authenticator := apikey.NewAuthenticator(keys)
+
+	bcryptHash := func(plaintext string) string {
+		hash, err := bcrypt.GenerateFromPassword([]byte(plaintext), bcrypt.MinCost)
+		if err != nil {
+			t.Fatalf("GenerateFromPassword() error: %v", err)
+		}
+		return string(hash)
+	}
+	key, plaintext := f.storeAPIKey(t, keys, entities.AuthMethodBearer, bcryptHash)
+	stored := func() string {
+		t.Helper()
+		saved, err := keys.GetByID(ctx, key.ID)
+		if err != nil || saved == nil {
+			t.Fatalf("GetByID() = %v, %v", saved, err)
+		}
+		return saved.KeyHash
+	}
+
+	// A wrong key leaves the bcrypt hash alone
+	if _, err := authenticator.Authenticate(ctx, plaintext[:8]+"wrong"); !stderrors.Is(err, errors.ErrInvalidAPIKey) {
+		t.Errorf("Authenticate(wrong key) error = %v, want ErrInvalidAPIKey", err)
+	}
+	if hash := stored(); hash != key.KeyHash {
+		t.Errorf("hash after a failed login = %q, want it unchanged", hash)
+	}
+
+	// The first successful login replaces it with argon2id
+	got, err := authenticator.Authenticate(ctx, plaintext)
+	if err != nil || got.ID != key.ID {
+		t.Fatalf("Authenticate() = %v, %v; want the key", got, err)
+	}
+	upgraded := stored()
+	if !strings.HasPrefix(upgraded, "$argon2id$") {
+		t.Fatalf("hash after login = %q, want argon2id", upgraded)
+	}
+
+	// And later logins check against it without hashing again
+	if _, err := authenticator.Authenticate(ctx, plaintext); err != nil {
+		t.Errorf("Authenticate() after the upgrade error: %v", err)
+	}
+	if hash := stored(); hash != upgraded {
+		t.Error("hash changed on a login with a current hash")
+	}
+}
+
+func TestAuthenticator_Rejects(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	keys := memory.NewAPIKeyRepository(f.store)
+	authenticator := apikey.NewAuthenticator(keys)
+
+	_, bearer := f.storeAPIKey(t, keys, entities.AuthMethodBearer, nil)
+	_, signing := f.storeAPIKey(t, keys, entities.AuthMethodHMAC, nil)
+	revokedKey, revoked := f.storeAPIKey(t, keys, entities.AuthMethodBearer, nil)
+	_ = revokedKey.Revoke()
+	if err := keys.Update(ctx, revokedKey); err != nil {
+		t.Fatalf("Update(API key) error: %v", err)
+	}
+
+	tests := map[string]struct {
+		plaintext string
+		want      error
+	}{
+		"unknown prefix":     {"zzzzzzzz" + bearer[8:], errors.ErrInvalidAPIKey},
+		"too short":          {bearer[:5], errors.ErrInvalidAPIKey},
+		"revoked key":        {revoked, errors.ErrAPIKeyRevoked},
+		"HMAC key as bearer": {signing, errors.ErrAuthMethod},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := authenticator.Authenticate(ctx, tc.plaintext); !stderrors.Is(err, tc.want) {
+				t.Errorf("Authenticate() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_AuthenticateSigned(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	keys := memory.NewAPIKeyRepository(f.store)
+	authenticator := apikey.NewAuthenticator(keys)
+
+	key, secret := f.storeAPIKey(t, keys, entities.AuthMethodHMAC, nil)
+	_, bearer := f.storeAPIKey(t, keys, entities.AuthMethodBearer, nil)
+	message := "1700000000\nPOST\n/api/v1/payments\n{}"
+	sign := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(message))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	got, err := authenticator.AuthenticateSigned(ctx, key.KeyPrefix, message, sign(secret))
+	if err != nil || got.ID != key.ID {
+		t.Fatalf("AuthenticateSigned() = %v, %v; want the key", got, err)
+	}
+
+	tests := map[string]struct {
+		prefix, signature string
+		want              error
+	}{
+		"bad signature":      {key.KeyPrefix, sign(secret + "x"), errors.ErrInvalidSignature},
+		"unknown prefix":     {"zzzzzzzz", sign(secret), errors.ErrInvalidAPIKey},
+		"short prefix":       {key.KeyPrefix[:4], sign(secret), errors.ErrInvalidAPIKey},
+		"bearer key signing": {bearer[:8], sign(bearer), errors.ErrAuthMethod},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := authenticator.AuthenticateSigned(ctx, tc.prefix, message, tc.signature); !stderrors.Is(err, tc.want) {
+				t.Errorf("AuthenticateSigned() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package usecases_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestBulkRefund(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	jobs := memory.NewBulkRefundJobRepository(f.store)
+	bulk := transaction.NewBulkRefundUseCase(f.partners, jobs, nil)
+	worker := transaction.NewBulkRefundWorker(f.transactions, f.refunds, jobs, f.refundUseCase(), 5, time.Minute)
+	getJob := transaction.NewGetBulkRefundJobUseCase(jobs)
+
+	paid := f.completedTransaction(t, 10000)
+	partlyRefunded := f.completedTransaction(t, 10000)
+	requestRefund(t, f, partlyRefunded, 4000)
+	fullyRefunded := f.completedTransaction(t, 10000)
+	requestRefund(t, f, fullyRefunded, 10000)
+	other := f.addPartner(t, "other@example.com")
+	foreign := f.paidTransaction(t, other.ID, 10000, time.Now())
+
+	job, err := bulk.Execute(ctx, transaction.BulkRefundInput{
+		PartnerID:      f.partner.ID,
+		TransactionIDs: []uuid.UUID{paid.ID, partlyRefunded.ID, paid.ID, fullyRefunded.ID, foreign.ID, uuid.New()},
+		ReasonCode:     "fraudulent",
+		Livemode:       true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if len(job.TransactionIDs) != 5 {
+		t.Errorf("job has %d transactions, want repeated ones dropped", len(job.TransactionIDs))
+	}
+
+	// The job waits for a worker, which saves its progress as it goes
+	if job.Status != entities.BulkRefundJobStatusPending {
+		t.Errorf("job status = %s, want pending", job.Status)
+	}
+	if finished, err := worker.RunDue(ctx, nil); err != nil || finished != 1 {
+		t.Fatalf("RunDue() = %d, %v; want 1 job finished", finished, err)
+	}
+	if job, err = getJob.Execute(ctx, job.ID, f.partner.ID, true); err != nil {
+		t.Fatalf("GetBulkRefundJob.Execute() error: %v", err)
+	}
+	if !job.IsCompleted() {
+		t.Fatalf("job status = %s, want completed", job.Status)
+	}
+
+	// Each transaction is refunded in full of what was left, and
+	// transactions that cannot be refunded are reported, not leaked
+	want := map[uuid.UUID]string{
+		paid.ID:           string(entities.RefundStatusCompleted),
+		partlyRefunded.ID: string(entities.RefundStatusCompleted),
+		fullyRefunded.ID:  "failed",
+		foreign.ID:        "failed",
+	}
+	for _, item := range job.Results {
+		if status, ok := want[item.TransactionID]; ok && item.Status != status {
+			t.Errorf("transaction %s = %s (%s), want %s", item.TransactionID, item.Status, item.Error, status)
+		}
+		if item.TransactionID == foreign.ID && item.Error != errors.ErrTransactionNotFound.Error() {
+			t.Errorf("foreign transaction error = %q, want transaction not found", item.Error)
+		}
+	}
+	if job.Succeeded != 2 || job.Failed != 3 || job.Processed() != 5 {
+		t.Errorf("job counted %d succeeded and %d failed, want 2 and 3", job.Succeeded, job.Failed)
+	}
+	for _, id := range []uuid.UUID{paid.ID, partlyRefunded.ID} {
+		if got := f.transaction(t, id); got.Status != entities.StatusRefunded {
+			t.Errorf("transaction %s status = %s, want refunded", id, got.Status)
+		}
+	}
+	if got := f.transaction(t, foreign.ID).Status; got != entities.StatusCompleted {
+		t.Errorf("foreign transaction status = %s, want untouched", got)
+	}
+
+	// Jobs are only visible to their partner, in their mode
+	if _, err := getJob.Execute(ctx, job.ID, other.ID, true); !stderrors.Is(err, errors.ErrBulkRefundJobNotFound) {
+		t.Errorf("job from another partner error = %v, want job not found", err)
+	}
+	if _, err := getJob.Execute(ctx, job.ID, f.partner.ID, false); !stderrors.Is(err, errors.ErrBulkRefundJobNotFound) {
+		t.Errorf("live job with a test key error = %v, want job not found", err)
+	}
+
+	// Partners without the feature cannot start jobs
+	_ = f.partner.SetFeature(valueobjects.FeatureBulkRefunds, false)
+	f.savePartner(t)
+	_, err = bulk.Execute(ctx, transaction.BulkRefundInput{PartnerID: f.partner.ID, TransactionIDs: []uuid.UUID{paid.ID}, ReasonCode: "fraudulent", Livemode: true})
+	if !stderrors.Is(err, errors.ErrFeatureDisabled) {
+		t.Errorf("Execute() with the feature off error = %v, want feature disabled", err)
+	}
+}
+
+func TestBulkRefundWorker_ResumesAfterStop(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	jobs := memory.NewBulkRefundJobRepository(f.store)
+	bulk := transaction.NewBulkRefundUseCase(f.partners, jobs, nil)
+	worker := transaction.NewBulkRefundWorker(f.transactions, f.refunds, jobs, f.refundUseCase(), 5, time.Minute)
+
+	first := f.completedTransaction(t, 10000)
+	second := f.completedTransaction(t, 5000)
+	queued, err := bulk.Execute(ctx, transaction.BulkRefundInput{
+		PartnerID:      f.partner.ID,
+		TransactionIDs: []uuid.UUID{first.ID, second.ID},
+		ReasonCode:     "duplicate",
+		Livemode:       true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+
+	// A worker stopping hands the job back untouched, due at once
+	stopping := make(chan struct{})
+	close(stopping)
+	if finished, err := worker.RunDue(ctx, stopping); err != nil || finished != 0 {
+		t.Fatalf("RunDue() while stopping = %d, %v; want none finished", finished, err)
+	}
+	job, _ := jobs.GetByID(ctx, queued.ID)
+	if job.Processed() != 0 || job.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("stopped job processed %d, due %v; want none and due now", job.Processed(), job.NextAttemptAt)
+	}
+
+	// A worker that died after refunding the first transaction, before
+	// saving the outcome, and whose claim has timed out
+	job.StartItem(first.ID)
+	made, err := f.refundUseCase().Execute(ctx, transaction.RefundTransactionInput{
+		TransactionID: first.ID,
+		PartnerID:     f.partner.ID,
+		Amount:        10000,
+		Currency:      "USD",
+		ReasonCode:    "duplicate",
+		Livemode:      true,
+		RefundID:      *job.InFlight().RefundID,
+	})
+	if err != nil {
+		t.Fatalf("RefundTransaction.Execute() error: %v", err)
+	}
+	job.NextAttemptAt = time.Now().Add(-time.Second)
+	if err := jobs.Update(ctx, job); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	if finished, err := worker.RunDue(ctx, nil); err != nil || finished != 1 {
+		t.Fatalf("RunDue() = %d, %v; want the job resumed and finished", finished, err)
+	}
+	job, _ = jobs.GetByID(ctx, queued.ID)
+	if len(job.Results) != 2 || job.Succeeded != 2 || job.Failed != 0 {
+		t.Fatalf("job results = %+v, want both refunded", job.Results)
+	}
+	if item := job.Results[0]; item.RefundID == nil || *item.RefundID != made.ID || item.StartedAt != nil {
+		t.Errorf("first item = %+v, want the refund made before the stop", item)
+	}
+	if got := f.transaction(t, second.ID).Status; got != entities.StatusRefunded {
+		t.Errorf("second transaction status = %s, want refunded", got)
+	}
+
+	// A finished job is not claimed again
+	if finished, err := worker.RunDue(ctx, nil); err != nil || finished != 0 {
+		t.Errorf("RunDue() after finishing = %d, %v; want nothing due", finished, err)
+	}
+}
+
+func TestBulkRefundWorker_ResumesWithItsOwnRefund(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	jobs := memory.NewBulkRefundJobRepository(f.store)
+	bulk := transaction.NewBulkRefundUseCase(f.partners, jobs, nil)
+	worker := transaction.NewBulkRefundWorker(f.transactions, f.refunds, jobs, f.refundUseCase(), 5, time.Minute)
+
+	// startedJob queues a job for txn whose worker died once its refund
+	// started, and whose claim has timed out
+	startedJob := func(txn *entities.Transaction) *entities.BulkRefundJob {
+		t.Helper()
+		queued, err := bulk.Execute(ctx, transaction.BulkRefundInput{
+			PartnerID:      f.partner.ID,
+			TransactionIDs: []uuid.UUID{txn.ID},
+			ReasonCode:     "duplicate",
+			Livemode:       true,
+		})
+		if err != nil {
+			t.Fatalf("Execute() error: %v", err)
+		}
+		job, _ := jobs.GetByID(ctx, queued.ID)
+		job.StartItem(txn.ID)
+		job.NextAttemptAt = time.Now().Add(-time.Second)
+		if err := jobs.Update(ctx, job); err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+		return job
+	}
+
+	// The worker died before making the refund, and meanwhile the partner
+	// refunded part of the transaction themselves; that refund is not the
+	// item's, which refunds the rest
+	first := f.completedTransaction(t, 10000)
+	job := startedJob(first)
+	itemRefundID := *job.InFlight().RefundID
+	partial := requestRefund(t, f, first, 3000)
+	if finished, err := worker.RunDue(ctx, nil); err != nil || finished != 1 {
+		t.Fatalf("RunDue() = %d, %v; want the job resumed and finished", finished, err)
+	}
+	job, _ = jobs.GetByID(ctx, job.ID)
+	item := job.Results[0]
+	if item.RefundID == nil || *item.RefundID != itemRefundID || item.Status != string(entities.RefundStatusCompleted) {
+		t.Fatalf("item = %+v, want the rest refunded with the item's refund, not %s", item, partial.ID)
+	}
+	if refunded, _ := f.refunds.GetTotalRefundedAmount(ctx, first.ID); refunded != 10000 {
+		t.Errorf("transaction refunded %d, want 10000", refunded)
+	}
+
+	// A refund still with the provider keeps the item in flight, and the
+	// job claimed, until its outcome is recorded
+	second := f.completedTransaction(t, 5000)
+	job = startedJob(second)
+	stuck := stuckRefund(t, f, second, 5000, *job.InFlight().RefundID)
+	if finished, err := worker.RunDue(ctx, nil); err != nil || finished != 0 {
+		t.Fatalf("RunDue() with the refund processing = %d, %v; want none finished", finished, err)
+	}
+	job, _ = jobs.GetByID(ctx, job.ID)
+	if job.InFlight() == nil || job.Processed() != 0 || !job.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("job = %+v, want its item in flight and the job claimed", job.Results)
+	}
+
+	if err := stuck.MarkAsCompleted("re_stuck"); err != nil {
+		t.Fatalf("MarkAsCompleted() error: %v", err)
+	}
+	if err := f.refunds.Update(ctx, stuck); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	job.NextAttemptAt = time.Now().Add(-time.Second)
+	if err := jobs.Update(ctx, job); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if finished, err := worker.RunDue(ctx, nil); err != nil || finished != 1 {
+		t.Fatalf("RunDue() after the refund completed = %d, %v; want the job finished", finished, err)
+	}
+	job, _ = jobs.GetByID(ctx, job.ID)
+	if item := job.Results[0]; item.RefundID == nil || *item.RefundID != stuck.ID || item.Status != string(entities.RefundStatusCompleted) || job.Succeeded != 1 {
+		t.Errorf("item = %+v, want the stuck refund's outcome", item)
+	}
+}
+
+// stuckRefund reserves a refund of txn with id that was sent to the
+// provider and has no outcome yet
+func stuckRefund(t *testing.T, f *fixture, txn *entities.Transaction, amount int64, id uuid.UUID) *entities.Refund {
+	t.Helper()
+	reason, err := valueobjects.NewRefundReason("duplicate", "")
+	if err != nil {
+		t.Fatalf("NewRefundReason() error: %v", err)
+	}
+	refund, err := entities.NewRefund(txn.ID, valueobjects.Money{Amount: amount, Currency: txn.Amount.Currency}, reason)
+	if err != nil {
+		t.Fatalf("NewRefund() error: %v", err)
+	}
+	refund.ID = id
+	if err := f.refunds.Reserve(context.Background(), refund); err != nil {
+		t.Fatalf("Reserve() error: %v", err)
+	}
+	if err := refund.MarkAsProcessing(); err != nil {
+		t.Fatalf("MarkAsProcessing() error: %v", err)
+	}
+	if err := f.refunds.Update(context.Background(), refund); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	return refund
+}
@@ -0,0 +1,94 @@
+package usecases_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/partner"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestCreateTransaction_Currencies(t *testing.T) {
+	f := newFixture(t)
+	if err := f.partner.SetAllowedCurrencies([]string{"EUR", "JPY"}); err != nil {
+		t.Fatalf("SetAllowedCurrencies() error: %v", err)
+	}
+	f.savePartner(t)
+	create := transaction.NewCreateTransactionUseCase(f.transactions, f.outbox, f.unitOfWork, f.partners, nil, payment.NewMockPaymentGateway("mock"), nil, nil, nil)
+
+	tests := []struct {
+		name     string
+		currency string
+		wantErr  error
+	}{
+		{"allowed", "EUR", nil},
+		{"allowed without minor units", "jpy", nil},
+		{"not allowed for the partner", "USD", errors.ErrCurrencyNotAllowed},
+		{"unknown", "ABC", errors.ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := create.Execute(context.Background(), transaction.CreateTransactionInput{
+				PartnerID:      f.partner.ID,
+				IdempotencyKey: uuid.NewString(),
+				Amount:         5000,
+				Currency:       tt.currency,
+				PaymentMethod:  "card",
+				Provider:       "stripe",
+				CustomerEmail:  "customer@example.com",
+			})
+			if tt.wantErr != nil {
+				if !stderrors.Is(err, tt.wantErr) {
+					t.Errorf("Execute(%s) error = %v, want %v", tt.currency, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute(%s) error: %v", tt.currency, err)
+			}
+			want, _ := valueobjects.NewCurrency(tt.currency)
+			if got := f.transaction(t, output.TransactionID).Amount.Currency; got != want {
+				t.Errorf("transaction currency = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestUpdatePartnerCurrencies(t *testing.T) {
+	f := newFixture(t)
+	update := partner.NewUpdatePartnerCurrenciesUseCase(f.partners, nil)
+	ctx := context.Background()
+
+	updated, err := update.Execute(ctx, partner.UpdatePartnerCurrenciesInput{PartnerID: f.partner.ID, Currencies: []string{"thb", "USD"}})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if len(updated.AllowedCurrencies) != 2 || updated.AllowedCurrencies[0] != valueobjects.THB {
+		t.Errorf("AllowedCurrencies = %v, want THB and USD", updated.AllowedCurrencies)
+	}
+
+	// An unknown code is refused and nothing is stored
+	_, err = update.Execute(ctx, partner.UpdatePartnerCurrenciesInput{PartnerID: f.partner.ID, Currencies: []string{"EUR", "XYZ"}})
+	var domainErr *errors.DomainError
+	if !stderrors.As(err, &domainErr) || domainErr.Code != errors.CodeValidation {
+		t.Errorf("Execute(unknown) error = %v, want a validation error", err)
+	}
+	saved, err := f.partners.GetByID(ctx, f.partner.ID)
+	if err != nil {
+		t.Fatalf("GetByID(partner) error: %v", err)
+	}
+	if stored := saved.AllowedCurrencies; len(stored) != 2 || stored[0] != valueobjects.THB || stored[1] != valueobjects.USD {
+		t.Errorf("stored currencies = %v after a refused update, want THB and USD", stored)
+	}
+
+	// Partners that do not exist are reported
+	if _, err := update.Execute(ctx, partner.UpdatePartnerCurrenciesInput{PartnerID: uuid.New()}); !stderrors.Is(err, errors.ErrPartnerNotFound) {
+		t.Errorf("Execute(unknown partner) error = %v, want ErrPartnerNotFound", err)
+	}
+}
@@ -0,0 +1,117 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/adapters/persistence/memory"
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// fixture is one partner with in-memory repositories, for running use cases
+// the way the API runs them
+type fixture struct {
+	store        *memory.Store
+	partners     *memory.PartnerRepository
+	transactions *memory.TransactionRepository
+	refunds      *memory.RefundRepository
+	outbox       *memory.OutboxRepository
+	unitOfWork   *memory.UnitOfWork
+	partner      *entities.Partner
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	store := memory.NewStore()
+	f := &fixture{
+		store:        store,
+		partners:     memory.NewPartnerRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		refunds:      memory.NewRefundRepository(store),
+		outbox:       memory.NewOutboxRepository(store),
+		unitOfWork:   memory.NewUnitOfWork(store),
+	}
+
+	f.partner = f.addPartner(t, "acme@example.com")
+	return f
+}
+
+// addPartner stores another partner next to the fixture's
+func (f *fixture) addPartner(t *testing.T, email string) *entities.Partner {
+	t.Helper()
+	partner, err := entities.NewPartner("Acme", email)
+	if err != nil {
+		t.Fatalf("NewPartner() error: %v", err)
+	}
+	if err := f.partners.Create(context.Background(), partner); err != nil {
+		t.Fatalf("Create(partner) error: %v", err)
+	}
+	return partner
+}
+
+// savePartner stores changes made to the fixture's partner
+func (f *fixture) savePartner(t *testing.T) {
+	t.Helper()
+	if err := f.partners.Update(context.Background(), f.partner); err != nil {
+		t.Fatalf("Update(partner) error: %v", err)
+	}
+	saved, err := f.partners.GetByID(context.Background(), f.partner.ID)
+	if err != nil {
+		t.Fatalf("GetByID(partner) error: %v", err)
+	}
+	f.partner = saved
+}
+
+// completedTransaction stores a paid live USD card payment of amount cents
+func (f *fixture) completedTransaction(t *testing.T, amount int64) *entities.Transaction {
+	t.Helper()
+	return f.paidTransaction(t, f.partner.ID, amount, time.Now())
+}
+
+// paidTransaction stores a live USD card payment of partnerID made at createdAt
+func (f *fixture) paidTransaction(t *testing.T, partnerID uuid.UUID, amount int64, createdAt time.Time) *entities.Transaction {
+	t.Helper()
+	money, _ := valueobjects.NewMoney(amount, "USD")
+	txn, err := entities.NewTransaction(partnerID, uuid.NewString(), money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	if err != nil {
+		t.Fatalf("NewTransaction() error: %v", err)
+	}
+	_ = txn.MarkAsProcessing()
+	_ = txn.MarkAsCompleted("ch_" + txn.ID.String()[:8])
+	txn.CreatedAt = createdAt
+	if err := f.transactions.Create(context.Background(), txn); err != nil {
+		t.Fatalf("Create(transaction) error: %v", err)
+	}
+	return f.transaction(t, txn.ID)
+}
+
+// transaction reloads a stored transaction
+func (f *fixture) transaction(t *testing.T, id uuid.UUID) *entities.Transaction {
+	t.Helper()
+	txn, err := f.transactions.GetByID(context.Background(), id)
+	if err != nil || txn == nil {
+		t.Fatalf("GetByID(transaction) = %v, %v", txn, err)
+	}
+	return txn
+}
+
+// refund reloads a stored refund
+func (f *fixture) refund(t *testing.T, id uuid.UUID) *entities.Refund {
+	t.Helper()
+	refund, err := f.refunds.GetByID(context.Background(), id)
+	if err != nil || refund == nil {
+		t.Fatalf("GetByID(refund) = %v, %v", refund, err)
+	}
+	return refund
+}
+
+// refundUseCase refunds through the mock gateway, within a 30 day window
+func (f *fixture) refundUseCase() *transaction.RefundTransactionUseCase {
+	return transaction.NewRefundTransactionUseCase(f.transactions, f.refunds, f.partners, f.outbox, f.unitOfWork, payment.NewMockPaymentGateway("stripe"), nil, nil, 30)
+}
@@ -0,0 +1,105 @@
+package usecases_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// requestRefund asks for a refund of amount cents of txn, which is held
+// when it is above the partner's approval threshold
+func requestRefund(t *testing.T, f *fixture, txn *entities.Transaction, amount int64) *entities.Refund {
+	t.Helper()
+	refund, err := f.refundUseCase().Execute(context.Background(), transaction.RefundTransactionInput{
+		TransactionID: txn.ID,
+		PartnerID:     f.partner.ID,
+		Amount:        amount,
+		Currency:      "USD",
+		ReasonCode:    "requested_by_customer",
+		Livemode:      true,
+	})
+	if err != nil {
+		t.Fatalf("RefundTransaction.Execute() error: %v", err)
+	}
+	return refund
+}
+
+func TestApproveRefund(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	_ = f.partner.SetRefundApprovalThresholds(map[string]string{"USD": "50.00"})
+	f.savePartner(t)
+	approve := transaction.NewApproveRefundUseCase(f.transactions, f.refunds, f.outbox, f.unitOfWork, payment.NewMockPaymentGateway("stripe"), nil)
+
+	// Refunds above the threshold are held, with their amount reserved
+	txn := f.completedTransaction(t, 10000)
+	refund := requestRefund(t, f, txn, 8000)
+	if !refund.RequiresApproval() {
+		t.Fatalf("refund status = %s, want requires_approval", refund.Status)
+	}
+	if got := f.transaction(t, txn.ID).RefundableAmount(); got != 2000 {
+		t.Errorf("refundable amount while held = %d, want 2000", got)
+	}
+
+	// Partner team members only approve their own partner's refunds
+	other := f.addPartner(t, "other@example.com").ID
+	_, err := approve.Execute(ctx, transaction.ApproveRefundInput{RefundID: refund.ID, ApprovedBy: "ops@example.com", PartnerID: &other})
+	if !stderrors.Is(err, errors.ErrRefundNotFound) {
+		t.Errorf("approval by another partner error = %v, want refund not found", err)
+	}
+
+	// Approval sends the refund to the provider
+	approved, err := approve.Execute(ctx, transaction.ApproveRefundInput{RefundID: refund.ID, ApprovedBy: "ops@example.com"})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if !approved.IsCompleted() || approved.ApprovedBy != "ops@example.com" || approved.ApprovedAt == nil {
+		t.Errorf("approved refund = %s by %q, want completed by ops@example.com", approved.Status, approved.ApprovedBy)
+	}
+	if got := f.transaction(t, txn.ID).Status; got != entities.StatusPartiallyRefunded {
+		t.Errorf("transaction status = %s, want partially_refunded", got)
+	}
+
+	// A refund is approved once
+	if _, err := approve.Execute(ctx, transaction.ApproveRefundInput{RefundID: refund.ID, ApprovedBy: "ops@example.com"}); err == nil {
+		t.Error("second approval succeeded, want an invalid state transition")
+	}
+}
+
+func TestApproveRefund_RejectsWhenNoLongerRefundable(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	_ = f.partner.SetRefundApprovalThresholds(map[string]string{"USD": "50.00"})
+	f.savePartner(t)
+	approve := transaction.NewApproveRefundUseCase(f.transactions, f.refunds, f.outbox, f.unitOfWork, payment.NewMockPaymentGateway("stripe"), nil)
+
+	txn := f.completedTransaction(t, 10000)
+	refund := requestRefund(t, f, txn, 8000)
+
+	// The payment is refunded in full elsewhere while the refund waits
+	held := f.transaction(t, txn.ID)
+	_ = held.MarkAsRefunded(false)
+	if err := f.transactions.Update(ctx, held); err != nil {
+		t.Fatalf("Update(transaction) error: %v", err)
+	}
+
+	_, err := approve.Execute(ctx, transaction.ApproveRefundInput{RefundID: refund.ID, ApprovedBy: "ops@example.com"})
+	if !stderrors.Is(err, errors.ErrRefundNotAllowed) {
+		t.Fatalf("Execute() error = %v, want refund not allowed", err)
+	}
+
+	// The refund is rejected rather than left waiting, and its amount is no
+	// longer held against the transaction
+	rejected := f.refund(t, refund.ID)
+	if !rejected.IsFailed() || rejected.ErrorCode != "TRANSACTION_NOT_REFUNDABLE" {
+		t.Errorf("refund = %s (%s), want failed with TRANSACTION_NOT_REFUNDABLE", rejected.Status, rejected.ErrorCode)
+	}
+	if got := f.transaction(t, txn.ID).RefundedAmount.Amount; got != 0 {
+		t.Errorf("refunded amount = %d, want the reservation released", got)
+	}
+}
@@ -0,0 +1,62 @@
+package usecases_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestCancelRefund(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	_ = f.partner.SetRefundApprovalThresholds(map[string]string{"USD": "50.00"})
+	f.savePartner(t)
+	cancel := transaction.NewCancelRefundUseCase(f.transactions, f.refunds, nil)
+
+	txn := f.completedTransaction(t, 10000)
+	refund := requestRefund(t, f, txn, 8000)
+
+	// Refunds are only found for their own partner, in their own mode
+	other := f.addPartner(t, "other@example.com").ID
+	for name, input := range map[string]transaction.CancelRefundInput{
+		"another partner": {RefundID: refund.ID, PartnerID: other, Livemode: true},
+		"test mode":       {RefundID: refund.ID, PartnerID: f.partner.ID, Livemode: false},
+	} {
+		if _, err := cancel.Execute(ctx, input); !stderrors.Is(err, errors.ErrRefundNotFound) {
+			t.Errorf("cancel from %s error = %v, want refund not found", name, err)
+		}
+	}
+
+	// Cancelling a held refund gives its amount back to the transaction
+	cancelled, err := cancel.Execute(ctx, transaction.CancelRefundInput{RefundID: refund.ID, PartnerID: f.partner.ID, Livemode: true})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if !cancelled.IsCancelled() || cancelled.CancelledAt == nil {
+		t.Errorf("refund = %s, want cancelled with a time", cancelled.Status)
+	}
+	if got := f.transaction(t, txn.ID).RefundableAmount(); got != 10000 {
+		t.Errorf("refundable amount = %d, want 10000", got)
+	}
+	if got := f.refund(t, refund.ID).Status; got != entities.RefundStatusCancelled {
+		t.Errorf("stored refund status = %s, want cancelled", got)
+	}
+
+	// Refunds that reached the provider cannot be cancelled
+	completed := requestRefund(t, f, txn, 3000)
+	if !completed.IsCompleted() {
+		t.Fatalf("refund below the threshold = %s, want completed", completed.Status)
+	}
+	for _, done := range []*entities.Refund{cancelled, completed} {
+		if _, err := cancel.Execute(ctx, transaction.CancelRefundInput{RefundID: done.ID, PartnerID: f.partner.ID, Livemode: true}); err == nil {
+			t.Errorf("cancelling a %s refund succeeded, want an invalid state transition", done.Status)
+		}
+	}
+	if got := f.transaction(t, txn.ID).RefundableAmount(); got != 7000 {
+		t.Errorf("refundable amount after a completed refund = %d, want 7000", got)
+	}
+}
@@ -0,0 +1,60 @@
+package usecases_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+func TestRefundTransaction_RefundWindow(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	old := f.paidTransaction(t, f.partner.ID, 10000, time.Now().AddDate(0, 0, -45))
+	refund := func(txn *entities.Transaction) error {
+		_, err := f.refundUseCase().Execute(ctx, transaction.RefundTransactionInput{
+			TransactionID: txn.ID,
+			PartnerID:     f.partner.ID,
+			Amount:        1000,
+			Currency:      "USD",
+			ReasonCode:    "requested_by_customer",
+			Livemode:      true,
+		})
+		return err
+	}
+
+	// Partners without a window of their own get the platform's 30 days
+	if err := refund(old); !stderrors.Is(err, errors.ErrRefundWindowExpired) {
+		t.Errorf("refund after 45 days with the default window error = %v, want window expired", err)
+	}
+
+	// A longer window of the partner's own lets it through
+	if err := f.partner.SetRefundWindowDays(60); err != nil {
+		t.Fatalf("SetRefundWindowDays() error: %v", err)
+	}
+	f.savePartner(t)
+	if err := refund(old); err != nil {
+		t.Errorf("refund after 45 days with a 60 day window error = %v, want none", err)
+	}
+
+	// And a shorter one closes it
+	_ = f.partner.SetRefundWindowDays(7)
+	f.savePartner(t)
+	recent := f.paidTransaction(t, f.partner.ID, 10000, time.Now().AddDate(0, 0, -10))
+	if err := refund(recent); !stderrors.Is(err, errors.ErrRefundWindowExpired) {
+		t.Errorf("refund after 10 days with a 7 day window error = %v, want window expired", err)
+	}
+
+	for _, days := range []int{-1, 3651} {
+		if err := f.partner.SetRefundWindowDays(days); err == nil {
+			t.Errorf("SetRefundWindowDays(%d) succeeded, want a validation error", days)
+		}
+	}
+	if got := f.partner.EffectiveRefundWindowDays(30); got != 7 {
+		t.Errorf("EffectiveRefundWindowDays() = %d, want the partner's 7 days kept", got)
+	}
+}
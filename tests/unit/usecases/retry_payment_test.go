@@ -0,0 +1,70 @@
+package usecases_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Pay2Go/internal/domain/entities"
+	"Pay2Go/internal/domain/errors"
+	"Pay2Go/internal/domain/valueobjects"
+	"Pay2Go/internal/infrastructure/payment"
+	"Pay2Go/internal/usecases/transaction"
+)
+
+// failedTransaction stores a USD card payment that failed after retries
+func (f *fixture) failedTransaction(t *testing.T, retries int) *entities.Transaction {
+	t.Helper()
+	money, _ := valueobjects.NewMoney(5000, "USD")
+	txn, err := entities.NewTransaction(f.partner.ID, uuid.NewString(), money, valueobjects.PaymentMethodCard, valueobjects.ProviderStripe, "customer@example.com")
+	if err != nil {
+		t.Fatalf("NewTransaction() error: %v", err)
+	}
+	_ = txn.MarkAsProcessing()
+	_ = txn.MarkAsFailed("card_declined", "card declined")
+	txn.RetryCount = retries
+	if err := f.transactions.Create(context.Background(), txn); err != nil {
+		t.Fatalf("Create(transaction) error: %v", err)
+	}
+	return f.transaction(t, txn.ID)
+}
+
+func TestRetryFailedPayment(t *testing.T) {
+	f := newFixture(t)
+	retry := transaction.NewRetryFailedPaymentUseCase(f.transactions, f.outbox, f.unitOfWork, payment.NewMockPaymentGateway("mock"), nil, nil, 0)
+	ctx := context.Background()
+
+	// A failed payment with retries left is retried and counted
+	failed := f.failedTransaction(t, 0)
+	if err := retry.Execute(ctx, failed.ID); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if got := f.transaction(t, failed.ID); got.RetryCount != 1 || got.Status == entities.StatusFailed {
+		t.Errorf("retried transaction = retry %d %s, want retry 1 and processed again", got.RetryCount, got.Status)
+	}
+
+	// Payments that did not fail are in the wrong status, not out of retries
+	completed := f.completedTransaction(t, 5000)
+	err := retry.Execute(ctx, completed.ID)
+	if !stderrors.Is(err, errors.ErrInvalidStatus) {
+		t.Errorf("Execute(completed) error = %v, want ErrInvalidStatus", err)
+	}
+	if got := f.transaction(t, completed.ID); got.RetryCount != 0 || got.Status != entities.StatusCompleted {
+		t.Errorf("completed transaction = retry %d %s after a refused retry, want it unchanged", got.RetryCount, got.Status)
+	}
+
+	// Failed payments out of retries break the business rule
+	exhausted := f.failedTransaction(t, entities.MaxRetryAttempts)
+	err = retry.Execute(ctx, exhausted.ID)
+	var domainErr *errors.DomainError
+	if !stderrors.As(err, &domainErr) || domainErr.Code != errors.CodeBusinessRule || stderrors.Is(err, errors.ErrInvalidStatus) {
+		t.Errorf("Execute(exhausted) error = %v, want max_retries_exceeded", err)
+	}
+
+	// Transactions that do not exist are reported
+	if err := retry.Execute(ctx, uuid.New()); !stderrors.Is(err, errors.ErrTransactionNotFound) {
+		t.Errorf("Execute(unknown) error = %v, want ErrTransactionNotFound", err)
+	}
+}